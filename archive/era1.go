@@ -0,0 +1,450 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/golang/snappy"
+)
+
+// Era1 files hold the blocks of an epoch of 8192 blocks in an e2store file, a sequence of typed records.
+// The records of each block are its snappy compressed header, body and receipts followed by its total difficulty,
+// then come the accumulator of the hashes and total difficulties of the blocks, and the index of the blocks.
+// The headers, bodies and receipts are in the encoding of this chain, not the one of Ethereum
+const (
+	// Era1EpochSize is the number of blocks of an era1 file
+	Era1EpochSize = 8192
+
+	// Era1Extension is the extension of the era1 files
+	Era1Extension = ".era1"
+
+	era1TypeVersion            = 0x3265
+	era1TypeCompressedHeader   = 0x03
+	era1TypeCompressedBody     = 0x04
+	era1TypeCompressedReceipts = 0x05
+	era1TypeTotalDifficulty    = 0x06
+	era1TypeAccumulator        = 0x07
+	era1TypeBlockIndex         = 0x3266
+
+	// e2storeHeaderSize is the size of the header of a record: the type, the length of the data and 2 reserved bytes
+	e2storeHeaderSize = 8
+)
+
+var (
+	errInvalidEra1 = errors.New("invalid era1 file")
+)
+
+// Era1Name returns the name of the era1 file of the epoch, suffixed with the beginning of its accumulator root
+func Era1Name(network string, epoch uint64, root types.Hash) string {
+	return fmt.Sprintf("%s-%05d-%x%s", network, epoch, root[:4], Era1Extension)
+}
+
+// e2storeWriter writes the records of an e2store file, keeping track of their offset
+type e2storeWriter struct {
+	w      io.Writer
+	offset int64
+}
+
+func (e *e2storeWriter) write(typ uint16, data []byte) error {
+	header := make([]byte, e2storeHeaderSize)
+	binary.LittleEndian.PutUint16(header[0:2], typ)
+	binary.LittleEndian.PutUint32(header[2:6], uint32(len(data)))
+
+	if _, err := e.w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+
+	e.offset += int64(e2storeHeaderSize + len(data))
+
+	return nil
+}
+
+// era1Writer writes the blocks of an epoch to an era1 file
+type era1Writer struct {
+	e2 *e2storeWriter
+
+	start   uint64
+	offsets []int64
+	records []era1HeaderRecord
+}
+
+// era1HeaderRecord is the accumulated data of a block
+type era1HeaderRecord struct {
+	hash            types.Hash
+	totalDifficulty *big.Int
+}
+
+func newEra1Writer(w io.Writer, start uint64) (*era1Writer, error) {
+	e := &era1Writer{
+		e2:    &e2storeWriter{w: w},
+		start: start,
+	}
+
+	if err := e.e2.write(era1TypeVersion, nil); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// add writes the records of the next block
+func (e *era1Writer) add(hash types.Hash, header, body, receipts []byte, totalDifficulty *big.Int) error {
+	if len(e.records) == Era1EpochSize {
+		return fmt.Errorf("an era1 file holds %d blocks at most", Era1EpochSize)
+	}
+
+	e.offsets = append(e.offsets, e.e2.offset)
+
+	for _, record := range []struct {
+		typ  uint16
+		data []byte
+	}{
+		{era1TypeCompressedHeader, header},
+		{era1TypeCompressedBody, body},
+		{era1TypeCompressedReceipts, receipts},
+	} {
+		compressed, err := snappyFramed(record.data)
+		if err != nil {
+			return err
+		}
+
+		if err := e.e2.write(record.typ, compressed); err != nil {
+			return err
+		}
+	}
+
+	if err := e.e2.write(era1TypeTotalDifficulty, uint256LE(totalDifficulty)); err != nil {
+		return err
+	}
+
+	e.records = append(e.records, era1HeaderRecord{hash: hash, totalDifficulty: totalDifficulty})
+
+	return nil
+}
+
+// finalize writes the accumulator and the index of the blocks, and returns the accumulator root
+func (e *era1Writer) finalize() (types.Hash, error) {
+	root := era1Accumulator(e.records)
+
+	if err := e.e2.write(era1TypeAccumulator, root.Bytes()); err != nil {
+		return types.Hash{}, err
+	}
+
+	// the offsets of the blocks are relative to the beginning of the index record
+	index := make([]byte, 8*(len(e.offsets)+2))
+	binary.LittleEndian.PutUint64(index, e.start)
+
+	for i, offset := range e.offsets {
+		binary.LittleEndian.PutUint64(index[8*(i+1):], uint64(offset-e.e2.offset))
+	}
+
+	binary.LittleEndian.PutUint64(index[len(index)-8:], uint64(len(e.offsets)))
+
+	if err := e.e2.write(era1TypeBlockIndex, index); err != nil {
+		return types.Hash{}, err
+	}
+
+	return root, nil
+}
+
+// era1Accumulator returns the SSZ hash tree root of the list of the header records, of 8192 records at most
+func era1Accumulator(records []era1HeaderRecord) types.Hash {
+	leaves := make([][32]byte, len(records))
+
+	for i, record := range records {
+		var td [32]byte
+
+		copy(td[:], uint256LE(record.totalDifficulty))
+
+		leaves[i] = sha256.Sum256(append(record.hash.Bytes(), td[:]...))
+	}
+
+	// merkleize the leaves padded with zero leaves up to the limit of the list
+	var zero [32]byte
+
+	for depth := 0; 1<<depth < Era1EpochSize; depth++ {
+		next := make([][32]byte, (len(leaves)+1)/2)
+
+		for i := range next {
+			right := zero
+			if 2*i+1 < len(leaves) {
+				right = leaves[2*i+1]
+			}
+
+			next[i] = sha256.Sum256(append(leaves[2*i][:], right[:]...))
+		}
+
+		if len(next) == 0 {
+			next = [][32]byte{sha256.Sum256(append(zero[:], zero[:]...))}
+		}
+
+		leaves = next
+		zero = sha256.Sum256(append(zero[:], zero[:]...))
+	}
+
+	// mix in the length of the list
+	var length [32]byte
+
+	binary.LittleEndian.PutUint64(length[:], uint64(len(records)))
+
+	return sha256.Sum256(append(leaves[0][:], length[:]...))
+}
+
+// uint256LE returns the 32 bytes little endian encoding of the number
+func uint256LE(n *big.Int) []byte {
+	data := make([]byte, 32)
+
+	be := n.Bytes()
+	for i, b := range be {
+		data[len(be)-1-i] = b
+	}
+
+	return data
+}
+
+func snappyFramed(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := snappy.NewBufferedWriter(&buf)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readEra1Count reads the first block and the number of blocks of the era1 file from its index
+func readEra1Count(path string) (uint64, uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tail := make([]byte, 8)
+	if _, err := f.ReadAt(tail, info.Size()-8); err != nil {
+		return 0, 0, fmt.Errorf("%w %s: %v", errInvalidEra1, path, err)
+	}
+
+	count := binary.LittleEndian.Uint64(tail)
+	indexSize := int64(e2storeHeaderSize + 8*(count+2))
+
+	if count > Era1EpochSize || indexSize > info.Size() {
+		return 0, 0, fmt.Errorf("%w %s: %d blocks", errInvalidEra1, path, count)
+	}
+
+	header := make([]byte, e2storeHeaderSize+8)
+	if _, err := f.ReadAt(header, info.Size()-indexSize); err != nil {
+		return 0, 0, err
+	}
+
+	if binary.LittleEndian.Uint16(header) != era1TypeBlockIndex {
+		return 0, 0, fmt.Errorf("%w %s: no block index", errInvalidEra1, path)
+	}
+
+	return binary.LittleEndian.Uint64(header[e2storeHeaderSize:]), count, nil
+}
+
+// era1Files returns the era1 files of the directory, in the order of their epoch
+func era1Files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), Era1Extension) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	// the names are zero padded, so they sort by epoch
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// era1Stream reads the blocks of era1 files one after the other
+type era1Stream struct {
+	files []string
+
+	file *os.File
+}
+
+func newEra1Stream(files []string) *era1Stream {
+	return &era1Stream{files: files}
+}
+
+// getMetadata returns the number of the last block of the files, its hash isn't known until it is read
+func (e *era1Stream) getMetadata() (*Metadata, error) {
+	if len(e.files) == 0 {
+		return nil, nil
+	}
+
+	start, count, err := readEra1Count(e.files[len(e.files)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	return &Metadata{Latest: start + count - 1}, nil
+}
+
+// nextBlock reads the next block, nil once all the files are read
+func (e *era1Stream) nextBlock() (*types.Block, error) {
+	for {
+		if e.file == nil {
+			if len(e.files) == 0 {
+				return nil, nil
+			}
+
+			if err := e.openNext(); err != nil {
+				return nil, err
+			}
+		}
+
+		typ, data, err := e.readRecord()
+		if err != nil {
+			return nil, err
+		}
+
+		switch typ {
+		case era1TypeCompressedHeader:
+			return e.readBlock(data)
+		case era1TypeAccumulator, era1TypeBlockIndex:
+			// the blocks of the file are read
+			if err := e.file.Close(); err != nil {
+				return nil, err
+			}
+
+			e.file = nil
+		default:
+			return nil, fmt.Errorf("%w %s: unexpected record type %#x", errInvalidEra1, e.file.Name(), typ)
+		}
+	}
+}
+
+func (e *era1Stream) openNext() error {
+	file, err := os.Open(e.files[0])
+	if err != nil {
+		return err
+	}
+
+	e.file, e.files = file, e.files[1:]
+
+	typ, _, err := e.readRecord()
+	if err != nil {
+		return err
+	}
+
+	if typ != era1TypeVersion {
+		return fmt.Errorf("%w %s: no version record", errInvalidEra1, file.Name())
+	}
+
+	return nil
+}
+
+// readBlock reads the body, the receipts and the total difficulty records following the header
+func (e *era1Stream) readBlock(compressedHeader []byte) (*types.Block, error) {
+	headerData, err := snappyUnframed(compressedHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &types.Header{}
+	if err := header.UnmarshalRLP(headerData); err != nil {
+		return nil, err
+	}
+
+	typ, compressedBody, err := e.readRecord()
+	if err != nil {
+		return nil, err
+	}
+
+	if typ != era1TypeCompressedBody {
+		return nil, fmt.Errorf("%w %s: no body for block %d", errInvalidEra1, e.file.Name(), header.Number)
+	}
+
+	bodyData, err := snappyUnframed(compressedBody)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &types.Body{}
+	if err := body.UnmarshalRLP(bodyData); err != nil {
+		return nil, err
+	}
+
+	// the receipts and the total difficulty are computed again once the block is written
+	for _, expected := range []uint16{era1TypeCompressedReceipts, era1TypeTotalDifficulty} {
+		if typ, _, err = e.readRecord(); err != nil {
+			return nil, err
+		}
+
+		if typ != expected {
+			return nil, fmt.Errorf("%w %s: unexpected record type %#x", errInvalidEra1, e.file.Name(), typ)
+		}
+	}
+
+	return &types.Block{
+		Header:       header,
+		Transactions: body.Transactions,
+		Uncles:       body.Uncles,
+	}, nil
+}
+
+func (e *era1Stream) readRecord() (uint16, []byte, error) {
+	header := make([]byte, e2storeHeaderSize)
+	if _, err := io.ReadFull(e.file, header); err != nil {
+		return 0, nil, fmt.Errorf("%w %s: %v", errInvalidEra1, e.file.Name(), err)
+	}
+
+	data := make([]byte, binary.LittleEndian.Uint32(header[2:6]))
+	if _, err := io.ReadFull(e.file, data); err != nil {
+		return 0, nil, fmt.Errorf("%w %s: %v", errInvalidEra1, e.file.Name(), err)
+	}
+
+	return binary.LittleEndian.Uint16(header[0:2]), data, nil
+}
+
+func (e *era1Stream) Close() error {
+	if e.file == nil {
+		return nil
+	}
+
+	return e.file.Close()
+}
+
+func snappyUnframed(data []byte) ([]byte, error) {
+	return io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+}
@@ -0,0 +1,330 @@
+package archive
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/fastrlp"
+)
+
+// Formats of the chain exports
+const (
+	FormatRLP  = "rlp"
+	FormatEra1 = "era1"
+)
+
+// ChainReader is the blockchain storage the canonical blocks are exported from
+type ChainReader interface {
+	ReadCanonicalHash(n uint64) (types.Hash, bool)
+	ReadHeader(hash types.Hash) (*types.Header, error)
+	ReadBody(hash types.Hash) (*types.Body, error)
+	ReadReceipts(hash types.Hash) ([]*types.Receipt, error)
+	ReadTotalDifficulty(hash types.Hash) (*big.Int, bool)
+}
+
+// exportedBlock is a canonical block read from the storage
+type exportedBlock struct {
+	hash     types.Hash
+	header   *types.Header
+	body     *types.Body
+	receipts types.Receipts
+}
+
+func readExportedBlock(chain ChainReader, number uint64, withReceipts bool) (*exportedBlock, error) {
+	hash, ok := chain.ReadCanonicalHash(number)
+	if !ok {
+		return nil, fmt.Errorf("no canonical block %d", number)
+	}
+
+	header, err := chain.ReadHeader(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header of block %d: %w", number, err)
+	}
+
+	// the empty bodies, like the one of the genesis, may not be stored
+	body, err := chain.ReadBody(hash)
+	if err != nil && header.HasBody() {
+		return nil, fmt.Errorf("failed to read the body of block %d: %w", number, err)
+	} else if err != nil {
+		body = &types.Body{}
+	}
+
+	block := &exportedBlock{hash: hash, header: header, body: body}
+
+	if withReceipts {
+		receipts, err := chain.ReadReceipts(hash)
+		if err != nil && header.HasReceipts() {
+			return nil, fmt.Errorf("failed to read the receipts of block %d: %w", number, err)
+		}
+
+		block.receipts = receipts
+	}
+
+	return block, nil
+}
+
+// ExportRLP writes the canonical blocks from..to to the writer, RLP encoded one after the other.
+// With their receipts, each block is encoded in a list along with its receipts.
+// The progress is called with the number of each written block
+func ExportRLP(
+	w io.Writer,
+	chain ChainReader,
+	from, to uint64,
+	withReceipts bool,
+	progress func(number uint64),
+) error {
+	arena := &fastrlp.Arena{}
+
+	for number := from; number <= to; number++ {
+		block, err := readExportedBlock(chain, number, withReceipts)
+		if err != nil {
+			return err
+		}
+
+		arena.Reset()
+
+		v := (&types.Block{
+			Header:       block.header,
+			Transactions: block.body.Transactions,
+			Uncles:       block.body.Uncles,
+		}).MarshalRLPWith(arena)
+
+		if withReceipts {
+			entry := arena.NewArray()
+			entry.Set(v)
+			entry.Set(block.receipts.MarshalRLPWith(arena))
+
+			v = entry
+		}
+
+		if _, err := w.Write(v.MarshalTo(nil)); err != nil {
+			return err
+		}
+
+		progress(number)
+	}
+
+	return nil
+}
+
+// ResumeRLP scans the RLP export opened for writing, drops its partially written last block,
+// and returns the number of the block following the last one, 0 if the export is empty.
+// The file is positioned at its end
+func ResumeRLP(f *os.File) (uint64, error) {
+	var (
+		r      = bufio.NewReader(f)
+		offset = int64(0)
+		next   = uint64(0)
+	)
+
+	for {
+		data, err := readRLPArray(r)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+
+		block, err := decodeBlock(data)
+		if err != nil {
+			return 0, fmt.Errorf("invalid block after offset %d of the export: %w", offset, err)
+		}
+
+		offset += int64(len(data))
+		next = block.Number() + 1
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return next, nil
+}
+
+// readRLPArray reads the next RLP encoded array, io.ErrUnexpectedEOF if it is truncated
+func readRLPArray(r *bufio.Reader) ([]byte, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		data        = []byte{prefix}
+		payloadSize uint64
+	)
+
+	switch {
+	case prefix >= 0xc0 && prefix <= 0xf7:
+		payloadSize = uint64(prefix - 0xc0)
+	case prefix >= 0xf8:
+		sizeBytes := make([]byte, prefix-0xf7)
+		if _, err := io.ReadFull(r, sizeBytes); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		data = append(data, sizeBytes...)
+		payloadSize = new(big.Int).SetBytes(sizeBytes).Uint64()
+	default:
+		return nil, errors.New("expected array but got bytes")
+	}
+
+	payload := make([]byte, payloadSize)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return append(data, payload...), nil
+}
+
+// ExportEra1 writes the canonical blocks from..to to era1 files in the directory, one for each epoch.
+// The files cover whole epochs, so the export begins at the first block of the epoch of from.
+// With resume, the epochs whose file holds all their blocks are skipped.
+// The progress is called with the number of each written block
+func ExportEra1(
+	dir, network string,
+	chain ChainReader,
+	from, to uint64,
+	resume bool,
+	progress func(number uint64),
+) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+
+	for epoch := from / Era1EpochSize; epoch <= to/Era1EpochSize; epoch++ {
+		existing, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("%s-%05d-*%s", network, epoch, Era1Extension)))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(existing) > 0 && !resume {
+			return nil, fmt.Errorf("the era1 file of epoch %d already exists: %s", epoch, existing[0])
+		}
+
+		if resume && len(existing) == 1 {
+			if _, count, err := readEra1Count(existing[0]); err == nil && count == Era1EpochSize {
+				files = append(files, existing[0])
+
+				continue
+			}
+		}
+
+		// the file of an epoch which was being written when the head was reached is written again
+		for _, path := range existing {
+			if err := os.Remove(path); err != nil {
+				return nil, err
+			}
+		}
+
+		last := (epoch+1)*Era1EpochSize - 1
+		if last > to {
+			last = to
+		}
+
+		path, err := exportEra1Epoch(dir, network, chain, epoch, last, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+// exportEra1Epoch writes the blocks of the epoch up to last to an era1 file,
+// which is only named once all its blocks are written
+func exportEra1Epoch(
+	dir, network string,
+	chain ChainReader,
+	epoch, last uint64,
+	progress func(number uint64),
+) (string, error) {
+	tmpPath := filepath.Join(dir, fmt.Sprintf("%s-%05d%s.tmp", network, epoch, Era1Extension))
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return "", err
+	}
+
+	closeAndRemove := func() {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+	}
+
+	w := bufio.NewWriter(f)
+
+	writer, err := newEra1Writer(w, epoch*Era1EpochSize)
+	if err != nil {
+		closeAndRemove()
+
+		return "", err
+	}
+
+	for number := epoch * Era1EpochSize; number <= last; number++ {
+		if err := writeEra1Block(writer, chain, number); err != nil {
+			closeAndRemove()
+
+			return "", err
+		}
+
+		progress(number)
+	}
+
+	root, err := writer.finalize()
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if err != nil {
+		closeAndRemove()
+
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return "", err
+	}
+
+	path := filepath.Join(dir, Era1Name(network, epoch, root))
+
+	return path, os.Rename(tmpPath, path)
+}
+
+func writeEra1Block(writer *era1Writer, chain ChainReader, number uint64) error {
+	block, err := readExportedBlock(chain, number, true)
+	if err != nil {
+		return err
+	}
+
+	totalDifficulty, ok := chain.ReadTotalDifficulty(block.hash)
+	if !ok {
+		return fmt.Errorf("no total difficulty of block %d", number)
+	}
+
+	return writer.add(
+		block.hash,
+		block.header.MarshalRLP(),
+		block.body.MarshalRLPTo(nil),
+		block.receipts.MarshalRLPTo(nil),
+		totalDifficulty,
+	)
+}
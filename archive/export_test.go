@@ -0,0 +1,154 @@
+package archive
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockChainReader struct {
+	blocks []*types.Block
+}
+
+func newMockChainReader() *mockChainReader {
+	return &mockChainReader{
+		blocks: append([]*types.Block{genesis}, blocks...),
+	}
+}
+
+func (m *mockChainReader) block(hash types.Hash) *types.Block {
+	for _, b := range m.blocks {
+		if b.Hash() == hash {
+			return b
+		}
+	}
+
+	return nil
+}
+
+func (m *mockChainReader) ReadCanonicalHash(n uint64) (types.Hash, bool) {
+	if n >= uint64(len(m.blocks)) {
+		return types.ZeroHash, false
+	}
+
+	return m.blocks[n].Hash(), true
+}
+
+func (m *mockChainReader) ReadHeader(hash types.Hash) (*types.Header, error) {
+	return m.block(hash).Header, nil
+}
+
+func (m *mockChainReader) ReadBody(hash types.Hash) (*types.Body, error) {
+	return m.block(hash).Body(), nil
+}
+
+func (m *mockChainReader) ReadReceipts(hash types.Hash) ([]*types.Receipt, error) {
+	return []*types.Receipt{}, nil
+}
+
+func (m *mockChainReader) ReadTotalDifficulty(hash types.Hash) (*big.Int, bool) {
+	return new(big.Int).SetUint64(m.block(hash).Number() + 1), true
+}
+
+func assertRestoredBlocks(t *testing.T, chain *mockChain) {
+	t.Helper()
+
+	require.Len(t, chain.blocks, len(blocks))
+
+	for i, b := range chain.blocks {
+		assert.Equal(t, blocks[i].Hash(), b.Hash())
+	}
+}
+
+func TestExportRLP(t *testing.T) {
+	t.Parallel()
+
+	for _, withReceipts := range []bool{false, true} {
+		var buf bytes.Buffer
+
+		exported := make([]uint64, 0)
+
+		require.NoError(t, ExportRLP(&buf, newMockChainReader(), 0, 3, withReceipts, func(number uint64) {
+			exported = append(exported, number)
+		}))
+
+		assert.Equal(t, []uint64{0, 1, 2, 3}, exported)
+
+		chain := &mockChain{genesis: genesis}
+		progression := progress.NewProgressionWrapper(progress.ChainSyncRestore)
+
+		require.NoError(t, importBlocks(chain, newBlockStream(&buf), progression))
+		assertRestoredBlocks(t, chain)
+	}
+}
+
+func TestResumeRLP(t *testing.T) {
+	t.Parallel()
+
+	var full bytes.Buffer
+
+	require.NoError(t, ExportRLP(&full, newMockChainReader(), 0, 3, true, func(uint64) {}))
+
+	var partial bytes.Buffer
+
+	require.NoError(t, ExportRLP(&partial, newMockChainReader(), 0, 2, true, func(uint64) {}))
+
+	path := filepath.Join(t.TempDir(), "export.rlp")
+
+	// the export was interrupted in the middle of the last block
+	require.NoError(t, os.WriteFile(path, full.Bytes()[:partial.Len()+5], 0600))
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	next, err := ResumeRLP(f)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), next)
+
+	require.NoError(t, ExportRLP(f, newMockChainReader(), next, 3, true, func(uint64) {}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, full.Bytes(), data)
+}
+
+func TestExportEra1(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	files, err := ExportEra1(dir, "test", newMockChainReader(), 2, 3, false, func(uint64) {})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	start, count, err := readEra1Count(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), start)
+	assert.Equal(t, uint64(4), count)
+
+	// the file of the epoch exists already
+	_, err = ExportEra1(dir, "test", newMockChainReader(), 0, 3, false, func(uint64) {})
+	assert.Error(t, err)
+
+	// the incomplete epoch is written again
+	files, err = ExportEra1(dir, "test", newMockChainReader(), 0, 3, true, func(uint64) {})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	for _, path := range []string{dir, files[0]} {
+		chain := &mockChain{genesis: genesis}
+		progression := progress.NewProgressionWrapper(progress.ChainSyncRestore)
+
+		require.NoError(t, RestoreChain(chain, path, progression))
+		assertRestoredBlocks(t, chain)
+	}
+}
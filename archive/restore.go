@@ -6,11 +6,13 @@ import (
 	"io"
 	"math/big"
 	"os"
+	"strings"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/fastrlp"
 )
 
 const (
@@ -26,20 +28,50 @@ type blockchainInterface interface {
 	VerifyFinalizedBlock(*types.Block) error
 }
 
-// RestoreChain reads blocks from the archive and write to the chain
+// blockSource is a source of blocks to restore, in ascending order
+type blockSource interface {
+	// getMetadata returns the last block of the source, nil if it isn't known before the blocks are read.
+	// It must be called before the blocks are read
+	getMetadata() (*Metadata, error)
+	// nextBlock returns the next block, nil once all the blocks are read
+	nextBlock() (*types.Block, error)
+}
+
+// RestoreChain reads blocks from the archive and write to the chain.
+// The archive is either a backup, an RLP export, an era1 file or a directory of era1 files
 func RestoreChain(chain blockchainInterface, filePath string, progression *progress.ProgressionWrapper) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() || strings.HasSuffix(filePath, Era1Extension) {
+		files := []string{filePath}
+
+		if info.IsDir() {
+			if files, err = era1Files(filePath); err != nil {
+				return err
+			}
+		}
+
+		stream := newEra1Stream(files)
+		defer stream.Close()
+
+		return importBlocks(chain, stream, progression)
+	}
+
 	fp, err := os.Open(filePath)
 	if err != nil {
 		return err
 	}
 
-	blockStream := newBlockStream(fp)
+	defer fp.Close()
 
-	return importBlocks(chain, blockStream, progression)
+	return importBlocks(chain, newBlockStream(fp), progression)
 }
 
 // import blocks scans all blocks from stream and write them to chain
-func importBlocks(chain blockchainInterface, blockStream *blockStream, progression *progress.ProgressionWrapper) error {
+func importBlocks(chain blockchainInterface, blockStream blockSource, progression *progress.ProgressionWrapper) error {
 	shutdownCh := common.GetTerminationSignalCh()
 
 	metadata, err := blockStream.getMetadata()
@@ -47,14 +79,12 @@ func importBlocks(chain blockchainInterface, blockStream *blockStream, progressi
 		return err
 	}
 
-	if metadata == nil {
-		return errors.New("expected metadata in archive but doesn't exist")
-	}
-
 	// check whether the local chain has the latest block already
-	latestBlock, ok := chain.GetBlockByNumber(metadata.Latest, false)
-	if ok && latestBlock.Hash() == metadata.LatestHash {
-		return nil
+	if metadata != nil && metadata.LatestHash != types.ZeroHash {
+		latestBlock, ok := chain.GetBlockByNumber(metadata.Latest, false)
+		if ok && latestBlock.Hash() == metadata.LatestHash {
+			return nil
+		}
 	}
 
 	// skip existing blocks
@@ -73,7 +103,9 @@ func importBlocks(chain blockchainInterface, blockStream *blockStream, progressi
 	defer progression.StopProgression()
 
 	// Set the goal
-	progression.UpdateHighestProgression(metadata.Latest)
+	if metadata != nil {
+		progression.UpdateHighestProgression(metadata.Latest)
+	}
 
 	nextBlock := firstBlock
 
@@ -111,7 +143,7 @@ func importBlocks(chain blockchainInterface, blockStream *blockStream, progressi
 // returns the first block to be written into chain
 func consumeCommonBlocks(
 	chain blockchainInterface,
-	blockStream *blockStream,
+	blockStream blockSource,
 	shutdownCh <-chan os.Signal,
 ) (*types.Block, error) {
 	for {
@@ -148,10 +180,14 @@ func consumeCommonBlocks(
 	}
 }
 
-// blockStream parse RLP-encoded block from stream and consumed the used bytes
+// blockStream parse RLP-encoded block from stream and consumed the used bytes.
+// The stream of a backup begins with its metadata, the one of an RLP export begins with its first block
 type blockStream struct {
 	input  io.Reader
 	buffer []byte
+
+	// pending is the first block of a stream without metadata, read along with the metadata
+	pending *types.Block
 }
 
 func newBlockStream(input io.Reader) *blockStream {
@@ -161,7 +197,8 @@ func newBlockStream(input io.Reader) *blockStream {
 	}
 }
 
-// getMetadata consumes some bytes from input and returns parsed Metadata
+// getMetadata consumes some bytes from input and returns parsed Metadata,
+// nil if the stream begins with a block
 func (b *blockStream) getMetadata() (*Metadata, error) {
 	size, err := b.loadRLPArray()
 	if err != nil {
@@ -172,11 +209,38 @@ func (b *blockStream) getMetadata() (*Metadata, error) {
 		return nil, nil
 	}
 
+	if !b.isMetadata(size) {
+		b.pending, err = b.parseBlock(size)
+
+		return nil, err
+	}
+
 	return b.parseMetadata(size)
 }
 
+// isMetadata checks whether the RLP encoded array in buffer is Metadata, whose first field isn't a list
+func (b *blockStream) isMetadata(size uint64) bool {
+	parser := fastrlp.DefaultParserPool.Get()
+	defer fastrlp.DefaultParserPool.Put(parser)
+
+	v, err := parser.Parse(b.buffer[:size])
+	if err != nil {
+		return false
+	}
+
+	elems, err := v.GetElems()
+
+	return err == nil && len(elems) == 2 && elems[0].Type() != fastrlp.TypeArray
+}
+
 // nextBlock consumes some bytes from input and returns parsed block
 func (b *blockStream) nextBlock() (*types.Block, error) {
+	if block := b.pending; block != nil {
+		b.pending = nil
+
+		return block, nil
+	}
+
 	size, err := b.loadRLPArray()
 	if err != nil {
 		return nil, err
@@ -284,12 +348,23 @@ func (b *blockStream) parseMetadata(size uint64) (*Metadata, error) {
 	return metadata, nil
 }
 
-// parseBlock parses RLP encoded Block in buffer
+// parseBlock parses RLP encoded Block in buffer,
+// the blocks exported with their receipts are encoded in a list along with the receipts
 func (b *blockStream) parseBlock(size uint64) (*types.Block, error) {
-	data := b.buffer[:size]
+	return decodeBlock(b.buffer[:size])
+}
+
+// decodeBlock decodes the RLP encoded block, or the list of the block and its receipts
+func decodeBlock(data []byte) (*types.Block, error) {
 	block := &types.Block{}
 
-	if err := block.UnmarshalRLP(data); err != nil {
+	if err := types.UnmarshalRlp(func(p *fastrlp.Parser, v *fastrlp.Value) error {
+		if elems, err := v.GetElems(); err == nil && len(elems) == 2 && elems[0].Type() == fastrlp.TypeArray {
+			v = elems[0]
+		}
+
+		return block.UnmarshalRLPFrom(p, v)
+	}, data); err != nil {
 		return nil, err
 	}
 
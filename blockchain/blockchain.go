@@ -4,12 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"path/filepath"
 	"sync"
 	"sync/atomic"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
-	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
 	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/helper/common"
@@ -18,26 +16,32 @@ import (
 	"github.com/0xPolygon/polygon-edge/types/buildroot"
 
 	"github.com/hashicorp/go-hclog"
-	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
 	BlockGasTargetDivisor uint64 = 1024 // The bound divisor of the gas limit, used in update calculations
 	defaultCacheSize      int    = 100  // The default size for Blockchain LRU cache structures
+
+	setHeadSource = "setHead" // The source of the reorg events of the rewinds
 )
 
 var (
-	ErrNoBlock              = errors.New("no block data passed in")
-	ErrParentNotFound       = errors.New("parent block not found")
-	ErrInvalidParentHash    = errors.New("parent block hash is invalid")
-	ErrParentHashMismatch   = errors.New("invalid parent block hash")
-	ErrInvalidBlockSequence = errors.New("invalid block sequence")
-	ErrInvalidSha3Uncles    = errors.New("invalid block sha3 uncles root")
-	ErrInvalidTxRoot        = errors.New("invalid block transactions root")
-	ErrInvalidReceiptsSize  = errors.New("invalid number of receipts")
-	ErrInvalidStateRoot     = errors.New("invalid block state root")
-	ErrInvalidGasUsed       = errors.New("invalid block gas used")
-	ErrInvalidReceiptsRoot  = errors.New("invalid block receipts root")
+	ErrNoBlock               = errors.New("no block data passed in")
+	ErrParentNotFound        = errors.New("parent block not found")
+	ErrInvalidParentHash     = errors.New("parent block hash is invalid")
+	ErrParentHashMismatch    = errors.New("invalid parent block hash")
+	ErrInvalidRewind         = errors.New("rewind target is ahead of the chain head")
+	ErrInvalidBlockSequence  = errors.New("invalid block sequence")
+	ErrInvalidSha3Uncles     = errors.New("invalid block sha3 uncles root")
+	ErrInvalidTxRoot         = errors.New("invalid block transactions root")
+	ErrInvalidReceiptsSize   = errors.New("invalid number of receipts")
+	ErrInvalidStateRoot      = errors.New("invalid block state root")
+	ErrInvalidGasUsed        = errors.New("invalid block gas used")
+	ErrInvalidReceiptsRoot   = errors.New("invalid block receipts root")
+	ErrBlockTooLarge         = errors.New("block body exceeds the maximum block size")
+	ErrReorgBelowFinalized   = errors.New("reorg below the finalized block")
+	ErrFinalizedNotCanonical = errors.New("the finalized block is not canonical")
+	ErrInvalidGasTargetStep  = errors.New("the gas limit doesn't move toward the governed gas target")
 )
 
 // Blockchain is a blockchain reference
@@ -52,8 +56,11 @@ type Blockchain struct {
 	config  *chain.Chain // Config containing chain information
 	genesis types.Hash   // The hash of the genesis block
 
-	headersCache    *lru.Cache // LRU cache for the headers
-	difficultyCache *lru.Cache // LRU cache for the difficulty
+	// LRU caches of the recently accessed blocks, shared by the consensus and the RPC
+	headersCache    *blockCache // LRU cache for the headers
+	bodiesCache     *blockCache // LRU cache for the bodies
+	receiptsCache   *blockCache // LRU cache for the stored block receipts
+	difficultyCache *blockCache // LRU cache for the difficulty
 
 	// We need to keep track of block receipts between the verification phase
 	// and the insertion phase of a new block coming in. To avoid having to
@@ -64,15 +71,24 @@ type Blockchain struct {
 	// that is currently not possible because it would break backwards compatibility due to
 	// insane conditionals in the RLP unmarshal methods for the Block structure, which prevent
 	// any new fields from being added
-	receiptsCache *lru.Cache // LRU cache for the block receipts
+	pendingReceiptsCache *blockCache // LRU cache for the receipts of the executed blocks
 
 	currentHeader     atomic.Value // The current header
 	currentDifficulty atomic.Value // The current difficulty of the chain (total difficulty)
+	finalizedHeader   atomic.Value // The last block finalized by the consensus
 
 	stream *eventStream // Event subscriptions
 
 	gpAverage *gasPriceAverage // A reference to the average gas price
 
+	bloomIndex *bloomIndex // The bloom bits index of the logs
+
+	accumulator *headerAccumulator // The accumulator of the canonical block hashes
+
+	indexer *ChainIndexer // The feed of the canonical blocks to the indexes
+
+	txLookup *txLookupIndex // The lookups of the transactions of the canonical blocks
+
 	writeLock sync.Mutex
 }
 
@@ -93,6 +109,8 @@ type Verifier interface {
 
 type Executor interface {
 	ProcessBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) (*state.Transition, error)
+	// GasTarget returns the block gas target governed on-chain in the state with the given root, 0 if it isn't set
+	GasTarget(root types.Hash) (uint64, error)
 }
 
 type TxSigner interface {
@@ -184,10 +202,10 @@ func (b *Blockchain) GetAvgGasPrice() *big.Int {
 	return b.gpAverage.price
 }
 
-// NewBlockchain creates a new blockchain object
+// NewBlockchain creates a new blockchain object over the storage, kept in memory if it is nil
 func NewBlockchain(
 	logger hclog.Logger,
-	dataDir string,
+	db storage.Storage,
 	config *chain.Chain,
 	consensus Verifier,
 	executor Executor,
@@ -206,25 +224,51 @@ func NewBlockchain(
 		},
 	}
 
-	var (
-		db  storage.Storage
-		err error
-	)
+	if db == nil {
+		var err error
 
-	if dataDir == "" {
 		if db, err = memory.NewMemoryStorage(nil); err != nil {
 			return nil, err
 		}
-	} else {
-		if db, err = leveldb.NewLevelDBStorage(
-			filepath.Join(dataDir, "blockchain"),
-			logger,
-		); err != nil {
-			return nil, err
-		}
 	}
 
 	b.db = db
+	b.bloomIndex = newBloomIndex(
+		b.logger,
+		db,
+		BloomSectionSize,
+		b.GetHeaderByNumber,
+		func() uint64 {
+			return b.Header().Number
+		},
+	)
+
+	b.accumulator = newHeaderAccumulator(
+		b.logger,
+		db,
+		AccumulatorCheckpointSize,
+		b.GetHeaderByNumber,
+		func() uint64 {
+			return b.Header().Number
+		},
+	)
+
+	b.indexer = newChainIndexer(
+		b.logger,
+		db,
+		b.GetHeaderByNumber,
+		func() uint64 {
+			return b.Header().Number
+		},
+	)
+
+	b.txLookup = newTxLookupIndex(b.logger, db, b.GetHeaderByNumber)
+
+	for _, indexer := range []Indexer{b.bloomIndex, b.accumulator, b.txLookup} {
+		if err := b.indexer.Register(indexer); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := b.initCaches(defaultCacheSize); err != nil {
 		return nil, err
@@ -240,24 +284,48 @@ func NewBlockchain(
 func (b *Blockchain) initCaches(size int) error {
 	var err error
 
-	b.headersCache, err = lru.New(size)
-	if err != nil {
-		return fmt.Errorf("unable to create headers cache, %w", err)
+	if b.headersCache, err = newBlockCache("headers", size); err != nil {
+		return err
 	}
 
-	b.difficultyCache, err = lru.New(size)
-	if err != nil {
-		return fmt.Errorf("unable to create difficulty cache, %w", err)
+	if b.bodiesCache, err = newBlockCache("bodies", size); err != nil {
+		return err
 	}
 
-	b.receiptsCache, err = lru.New(size)
-	if err != nil {
-		return fmt.Errorf("unable to create receipts cache, %w", err)
+	if b.receiptsCache, err = newBlockCache("receipts", size); err != nil {
+		return err
+	}
+
+	if b.difficultyCache, err = newBlockCache("difficulty", size); err != nil {
+		return err
+	}
+
+	if b.pendingReceiptsCache, err = newBlockCache("pending_receipts", size); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// SetCacheSize sets the number of recently accessed blocks whose headers, bodies, receipts
+// and total difficulty are cached, evicting the least recently used ones above it
+func (b *Blockchain) SetCacheSize(size int) error {
+	if size <= 0 {
+		return fmt.Errorf("invalid cache size %d", size)
+	}
+
+	for _, cache := range b.caches() {
+		cache.resize(size)
+	}
+
+	return nil
+}
+
+// caches returns the caches of the data of the blocks
+func (b *Blockchain) caches() []*blockCache {
+	return []*blockCache{b.headersCache, b.bodiesCache, b.receiptsCache, b.difficultyCache, b.pendingReceiptsCache}
+}
+
 // ComputeGenesis computes the genesis hash, and updates the blockchain reference
 func (b *Blockchain) ComputeGenesis() error {
 	// try to write the genesis block
@@ -301,8 +369,24 @@ func (b *Blockchain) ComputeGenesis() error {
 		}
 	}
 
+	if err := b.loadFinalizedHeader(); err != nil {
+		return err
+	}
+
 	b.logger.Info("genesis", "hash", b.config.Genesis.Hash())
 
+	if b.bloomIndex != nil {
+		b.bloomIndex.start()
+	}
+
+	if b.accumulator != nil {
+		b.accumulator.start()
+	}
+
+	if b.indexer != nil {
+		b.indexer.start()
+	}
+
 	return nil
 }
 
@@ -336,6 +420,56 @@ func (b *Blockchain) Header() *types.Header {
 	return header
 }
 
+// loadFinalizedHeader loads the last finalized block, the genesis if the consensus finalized none
+func (b *Blockchain) loadFinalizedHeader() error {
+	hash, ok := b.db.ReadFinalizedHash()
+	if !ok {
+		hash = b.genesis
+	}
+
+	header, ok := b.GetHeaderByHash(hash)
+	if !ok {
+		return fmt.Errorf("failed to get the finalized header with hash %s", hash)
+	}
+
+	b.finalizedHeader.Store(header)
+
+	return nil
+}
+
+// FinalizedHeader returns the last block finalized by the consensus (atomic)
+func (b *Blockchain) FinalizedHeader() *types.Header {
+	header, ok := b.finalizedHeader.Load().(*types.Header)
+	if !ok {
+		return nil
+	}
+
+	return header
+}
+
+// SetFinalized marks the canonical block as finalized by the consensus.
+// The chain is never reorganized below the finalized block, which only moves forward
+func (b *Blockchain) SetFinalized(header *types.Header) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if finalized := b.FinalizedHeader(); finalized != nil && header.Number <= finalized.Number {
+		return nil
+	}
+
+	if hash, ok := b.db.ReadCanonicalHash(header.Number); !ok || hash != header.Hash {
+		return fmt.Errorf("%w: block %d (%s)", ErrFinalizedNotCanonical, header.Number, header.Hash)
+	}
+
+	if err := b.db.WriteFinalizedHash(header.Hash); err != nil {
+		return err
+	}
+
+	b.finalizedHeader.Store(header.Copy())
+
+	return nil
+}
+
 // CurrentTD returns the current total difficulty (atomic)
 func (b *Blockchain) CurrentTD() *big.Int {
 	td, ok := b.currentDifficulty.Load().(*big.Int)
@@ -378,14 +512,23 @@ func (b *Blockchain) CalculateGasLimit(number uint64) (uint64, error) {
 		return 0, fmt.Errorf("parent of block %d not found", number)
 	}
 
-	return b.calculateGasLimit(parent.GasLimit), nil
+	blockGasTarget, err := b.executor.GasTarget(parent.StateRoot)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the gas target at block %d: %w", parent.Number, err)
+	}
+
+	// The gas target governed on-chain overrides the one of the node
+	if blockGasTarget == 0 {
+		blockGasTarget = b.Config().BlockGasTarget
+	}
+
+	return calculateGasLimit(parent.GasLimit, blockGasTarget), nil
 }
 
 // calculateGasLimit calculates gas limit in reference to the block gas target
-func (b *Blockchain) calculateGasLimit(parentGasLimit uint64) uint64 {
+func calculateGasLimit(parentGasLimit, blockGasTarget uint64) uint64 {
 	// The gas limit cannot move more than 1/1024 * parentGasLimit
 	// in either direction per block
-	blockGasTarget := b.Config().BlockGasTarget
 
 	// Check if the gas limit target has been set
 	if blockGasTarget == 0 {
@@ -530,7 +673,36 @@ func (b *Blockchain) advanceHead(newHeader *types.Header) (*big.Int, error) {
 
 // GetReceiptsByHash returns the receipts by their hash
 func (b *Blockchain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
-	return b.db.ReadReceipts(hash)
+	if cached, ok := b.receiptsCache.get(hash); ok {
+		if receipts, ok := cached.([]*types.Receipt); ok {
+			return receipts, nil
+		}
+	}
+
+	receipts, err := b.db.ReadReceipts(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	b.receiptsCache.add(hash, receipts)
+
+	return receipts, nil
+}
+
+// GetReceipt returns the receipt of the transaction at the index in the block with the hash.
+// Only the receipt is decoded if the receipts of the block aren't cached
+func (b *Blockchain) GetReceipt(hash types.Hash, index uint64) (*types.Receipt, error) {
+	if cached, ok := b.receiptsCache.get(hash); ok {
+		if receipts, ok := cached.([]*types.Receipt); ok {
+			if index >= uint64(len(receipts)) {
+				return nil, types.ErrReceiptNotFound
+			}
+
+			return receipts[index], nil
+		}
+	}
+
+	return b.db.ReadReceipt(hash, index)
 }
 
 // GetBodyByHash returns the body by their hash
@@ -546,7 +718,7 @@ func (b *Blockchain) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
 // readHeader Returns the header using the hash
 func (b *Blockchain) readHeader(hash types.Hash) (*types.Header, bool) {
 	// Try to find a hit in the headers cache
-	h, ok := b.headersCache.Get(hash)
+	h, ok := b.headersCache.get(hash)
 	if ok {
 		// Hit, return the3 header
 		header, ok := h.(*types.Header)
@@ -565,13 +737,19 @@ func (b *Blockchain) readHeader(hash types.Hash) (*types.Header, bool) {
 
 	// Compute the header hash and update the cache
 	hh.ComputeHash()
-	b.headersCache.Add(hash, hh)
+	b.headersCache.add(hash, hh)
 
 	return hh, true
 }
 
 // readBody reads the block's body, using the block hash
 func (b *Blockchain) readBody(hash types.Hash) (*types.Body, bool) {
+	if cached, ok := b.bodiesCache.get(hash); ok {
+		if body, ok := cached.(*types.Body); ok {
+			return body, true
+		}
+	}
+
 	bb, err := b.db.ReadBody(hash)
 	if err != nil {
 		b.logger.Error("failed to read body", "err", err)
@@ -586,13 +764,15 @@ func (b *Blockchain) readBody(hash types.Hash) (*types.Body, bool) {
 		}
 	}
 
+	b.bodiesCache.add(hash, bb)
+
 	return bb, true
 }
 
 // readTotalDifficulty reads the total difficulty associated with the hash
 func (b *Blockchain) readTotalDifficulty(headerHash types.Hash) (*big.Int, bool) {
 	// Try to find the difficulty in the cache
-	foundDifficulty, ok := b.difficultyCache.Get(headerHash)
+	foundDifficulty, ok := b.difficultyCache.get(headerHash)
 	if ok {
 		// Hit, return the difficulty
 		fd, ok := foundDifficulty.(*big.Int)
@@ -610,7 +790,7 @@ func (b *Blockchain) readTotalDifficulty(headerHash types.Hash) (*big.Int, bool)
 	}
 
 	// Update the difficulty cache
-	b.difficultyCache.Add(headerHash, dbDifficulty)
+	b.difficultyCache.add(headerHash, dbDifficulty)
 
 	return dbDifficulty, true
 }
@@ -706,6 +886,11 @@ func (b *Blockchain) verifyBlock(block *types.Block) error {
 		return ErrNoBlock
 	}
 
+	// Make sure the block isn't larger than allowed
+	if err := b.verifyBlockSize(block); err != nil {
+		return err
+	}
+
 	// Make sure the block is in line with the parent block
 	if err := b.verifyBlockParent(block); err != nil {
 		return err
@@ -719,6 +904,31 @@ func (b *Blockchain) verifyBlock(block *types.Block) error {
 	return nil
 }
 
+// verifyBlockSize makes sure that the encoded transactions and uncles of the block
+// are within the size limit of the chain params, if the block size fork is enabled
+func (b *Blockchain) verifyBlockSize(block *types.Block) error {
+	maxSize := b.config.Params.MaxBlockSizeAt(block.Number())
+	if maxSize == 0 {
+		return nil
+	}
+
+	size := uint64(0)
+
+	for _, tx := range block.Transactions {
+		size += tx.Size()
+	}
+
+	for _, uncle := range block.Uncles {
+		size += uint64(len(uncle.MarshalRLP()))
+	}
+
+	if size > maxSize {
+		return fmt.Errorf("%w, size %d, limit %d", ErrBlockTooLarge, size, maxSize)
+	}
+
+	return nil
+}
+
 // verifyBlockParent makes sure that the child block is in line
 // with the locally saved parent block. This means checking:
 // - The parent exists
@@ -848,6 +1058,11 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 		return nil, ErrParentNotFound
 	}
 
+	// Make sure the gas limit moves toward the governed gas target
+	if err := b.verifyGasTarget(header, parent); err != nil {
+		return nil, err
+	}
+
 	blockCreator, err := b.consensus.GetBlockCreator(header)
 	if err != nil {
 		return nil, err
@@ -865,7 +1080,7 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 	_, root := txn.Commit()
 
 	// Append the receipts to the receipts cache
-	b.receiptsCache.Add(header.Hash, txn.Receipts())
+	b.pendingReceiptsCache.add(header.Hash, txn.Receipts())
 
 	return &BlockResult{
 		Root:     root,
@@ -938,10 +1153,128 @@ func (b *Blockchain) WriteBlock(block *types.Block, source string) error {
 	return nil
 }
 
+// ImportHeader verifies the header with the consensus, and writes it as the new head without its body.
+// It's used to import the headers up to a checkpoint whose state has been fetched from the peers,
+// so the blocks before the checkpoint are neither executed nor stored
+func (b *Blockchain) ImportHeader(header *types.Header, source string) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if header.Number <= b.Header().Number {
+		b.logger.Info("header already imported", "number", header.Number, "source", source)
+
+		return nil
+	}
+
+	// the imported headers only extend the canonical chain
+	if header.ParentHash != b.Header().Hash {
+		return ErrParentHashMismatch
+	}
+
+	if err := b.consensus.VerifyHeader(header); err != nil {
+		return fmt.Errorf("failed to verify the header: %w", err)
+	}
+
+	evnt := &Event{Source: source}
+	if err := b.writeHeaderImpl(evnt, header); err != nil {
+		return err
+	}
+
+	// update snapshot
+	if err := b.consensus.ProcessHeaders([]*types.Header{header}); err != nil {
+		return err
+	}
+
+	b.dispatchEvent(evnt)
+
+	return nil
+}
+
+// SetHead rewinds the canonical chain to the block with the given number.
+// The blocks after it are no longer canonical, and their transactions can't be looked up anymore.
+// The subscribers get a reorg event for the dropped blocks
+func (b *Blockchain) SetHead(number uint64) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	head := b.Header()
+	if number > head.Number {
+		return ErrInvalidRewind
+	}
+
+	target, ok := b.GetHeaderByNumber(number)
+	if !ok {
+		return fmt.Errorf("header %d not found", number)
+	}
+
+	td, ok := b.readTotalDifficulty(target.Hash)
+	if !ok {
+		return fmt.Errorf("total difficulty of %d not found", number)
+	}
+
+	evnt := &Event{Source: setHeadSource}
+
+	// the storage has no deletion, so the dropped entries point to the zero hash
+	for n := head.Number; n > number; n-- {
+		header, ok := b.GetHeaderByNumber(n)
+		if !ok {
+			return fmt.Errorf("header %d not found", n)
+		}
+
+		if header.HasBody() {
+			body, ok := b.readBody(header.Hash)
+			if !ok {
+				return fmt.Errorf("body %d not found", n)
+			}
+
+			for _, tx := range body.Transactions {
+				if err := b.db.WriteTxLookup(tx.Hash, types.ZeroHash, 0); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := b.db.WriteCanonicalHash(n, types.ZeroHash); err != nil {
+			return err
+		}
+
+		evnt.AddOldHeader(header)
+	}
+
+	if err := b.db.WriteHeadHash(target.Hash); err != nil {
+		return err
+	}
+
+	if err := b.db.WriteHeadNumber(target.Number); err != nil {
+		return err
+	}
+
+	// the operator rewinds the finality along with the head
+	if finalized := b.FinalizedHeader(); finalized != nil && finalized.Number > target.Number {
+		if err := b.db.WriteFinalizedHash(target.Hash); err != nil {
+			return err
+		}
+
+		b.finalizedHeader.Store(target)
+	}
+
+	b.setCurrentHeader(target, td)
+
+	evnt.Type = EventReorg
+	evnt.AddNewHeader(target)
+	evnt.SetDifficulty(td)
+
+	b.dispatchEvent(evnt)
+
+	b.logger.Warn("chain head rewound", "from", head.Number, "to", number)
+
+	return nil
+}
+
 // extractBlockReceipts extracts the receipts from the passed in block
 func (b *Blockchain) extractBlockReceipts(block *types.Block) ([]*types.Receipt, error) {
 	// Check the cache for the block receipts
-	receipts, ok := b.receiptsCache.Get(block.Header.Hash)
+	receipts, ok := b.pendingReceiptsCache.get(block.Header.Hash)
 	if !ok {
 		// No receipts found in the cache, execute the transactions from the block
 		// and fetch them
@@ -994,8 +1327,8 @@ func (b *Blockchain) writeBody(block *types.Block) error {
 	}
 
 	// Write txn lookups (txHash -> block)
-	for _, txn := range block.Transactions {
-		if err := b.db.WriteTxLookup(txn.Hash, block.Hash()); err != nil {
+	for i, txn := range block.Transactions {
+		if err := b.db.WriteTxLookup(txn.Hash, block.Hash(), uint64(i)); err != nil {
 			return err
 		}
 	}
@@ -1007,7 +1340,15 @@ func (b *Blockchain) writeBody(block *types.Block) error {
 func (b *Blockchain) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
 	v, ok := b.db.ReadTxLookup(hash)
 
-	return v, ok
+	// the transactions of the rewound blocks point to the zero hash
+	return v, ok && v != types.ZeroHash
+}
+
+// ReadTxLookupIndex returns the block hash and the index of the transaction in the block using the transaction hash
+func (b *Blockchain) ReadTxLookupIndex(hash types.Hash) (types.Hash, uint64, bool) {
+	v, index, ok := b.db.ReadTxLookupIndex(hash)
+
+	return v, index, ok && v != types.ZeroHash
 }
 
 // recoverFromFieldsInBlock recovers 'from' fields in the transactions of the given block
@@ -1087,6 +1428,31 @@ func (b *Blockchain) verifyGasLimit(header *types.Header, parentHeader *types.He
 	return nil
 }
 
+// verifyGasTarget makes sure the gas limit moves toward the gas target once it's governed on-chain.
+// The target is read from the state of the parent, so it's verified along with the execution of the block
+func (b *Blockchain) verifyGasTarget(header *types.Header, parentHeader *types.Header) error {
+	target, err := b.executor.GasTarget(parentHeader.StateRoot)
+	if err != nil {
+		return fmt.Errorf("unable to read the gas target, %w", err)
+	}
+
+	if target == 0 {
+		return nil
+	}
+
+	if expected := calculateGasLimit(parentHeader.GasLimit, target); header.GasLimit != expected {
+		return fmt.Errorf(
+			"%w, limit = %d, want %d toward the target %d",
+			ErrInvalidGasTargetStep,
+			header.GasLimit,
+			expected,
+			target,
+		)
+	}
+
+	return nil
+}
+
 // GetHashHelper is used by the EVM, so that the SC can get the hash of the header number
 func (b *Blockchain) GetHashHelper(header *types.Header) func(i uint64) (res types.Hash) {
 	return func(i uint64) (res types.Hash) {
@@ -1127,6 +1493,10 @@ func (b *Blockchain) GetHashByNumber(blockNumber uint64) types.Hash {
 
 // dispatchEvent pushes a new event to the stream
 func (b *Blockchain) dispatchEvent(evnt *Event) {
+	if b.indexer != nil {
+		b.indexer.notify()
+	}
+
 	b.stream.push(evnt)
 }
 
@@ -1171,7 +1541,7 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 	}
 
 	// Update the headers cache
-	b.headersCache.Add(header.Hash, header)
+	b.headersCache.add(header.Hash, header)
 
 	incomingTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(header.Difficulty))
 	if incomingTD.Cmp(currentTD) > 0 {
@@ -1267,6 +1637,17 @@ func (b *Blockchain) handleReorg(
 		oldChain = append(oldChain, oldHeader)
 	}
 
+	// the blocks finalized by the consensus are never replaced
+	if finalized := b.FinalizedHeader(); finalized != nil && oldHeader.Number < finalized.Number {
+		return fmt.Errorf(
+			"%w: the fork of block %d is at block %d, block %d is finalized",
+			ErrReorgBelowFinalized,
+			newChainHead.Number,
+			oldHeader.Number,
+			finalized.Number,
+		)
+	}
+
 	for _, b := range oldChain[:len(oldChain)-1] {
 		evnt.AddOldHeader(b)
 	}
@@ -1317,7 +1698,8 @@ func (b *Blockchain) GetBlockByHash(hash types.Hash, full bool) (*types.Block, b
 		Header: header,
 	}
 
-	if !full || header.Number == 0 {
+	// the header commits to an empty body, so there is nothing to load
+	if !full || header.Number == 0 || !header.HasBody() {
 		return block, true
 	}
 
@@ -1349,7 +1731,127 @@ func (b *Blockchain) GetBlockByNumber(blockNumber uint64, full bool) (*types.Blo
 	return b.GetBlockByHash(blockHash, full)
 }
 
+// GetTxCountByHash returns the number of transactions in the block with the given hash,
+// without loading the block body
+func (b *Blockchain) GetTxCountByHash(hash types.Hash) (int, bool) {
+	header, ok := b.readHeader(hash)
+	if !ok {
+		return 0, false
+	}
+
+	if !header.HasBody() {
+		return 0, true
+	}
+
+	count, err := b.db.ReadBodyTxCount(hash)
+	if err != nil {
+		b.logger.Error("failed to read body transaction count", "err", err)
+
+		return 0, false
+	}
+
+	return count, true
+}
+
+// IterateHeaders calls the handler for every canonical header in the [from, to] range, in order,
+// without loading the block bodies. The iteration stops early if the handler returns false
+func (b *Blockchain) IterateHeaders(from, to uint64, handler func(*types.Header) bool) error {
+	for i := from; i <= to; i++ {
+		header, ok := b.GetHeaderByNumber(i)
+		if !ok {
+			return fmt.Errorf("header %d not found", i)
+		}
+
+		if !handler(header) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// GetBloomMatches returns the numbers of the blocks in the [from, to] range whose logs bloom
+// possibly contains at least one element of every filter group, using the bloom bits index.
+// Only the indexed part of the range is processed, so the number of the first block
+// which still has to be checked by other means is returned as well
+func (b *Blockchain) GetBloomMatches(from, to uint64, filter [][][]byte) ([]uint64, uint64) {
+	if b.bloomIndex == nil {
+		return nil, from
+	}
+
+	return b.bloomIndex.matches(from, to, filter)
+}
+
+// GetAccumulatorCheckpoint returns the root of the header accumulator at the checkpoint
+func (b *Blockchain) GetAccumulatorCheckpoint(checkpoint uint64) (*AccumulatorCheckpoint, error) {
+	if b.accumulator == nil {
+		return nil, ErrCheckpointNotFound
+	}
+
+	return b.accumulator.checkpoint(checkpoint)
+}
+
+// GetLatestAccumulatorCheckpoint returns the root of the header accumulator at the last committed checkpoint
+func (b *Blockchain) GetLatestAccumulatorCheckpoint() (*AccumulatorCheckpoint, error) {
+	if b.accumulator == nil {
+		return nil, ErrCheckpointNotFound
+	}
+
+	return b.accumulator.latestCheckpoint()
+}
+
+// GetAncestryProof returns the proof that the canonical block is an ancestor of the head of the checkpoint
+func (b *Blockchain) GetAncestryProof(number, checkpoint uint64) (*AncestryProof, error) {
+	if b.accumulator == nil {
+		return nil, ErrCheckpointNotFound
+	}
+
+	return b.accumulator.proof(number, checkpoint)
+}
+
+// SetTxLookupLimit sets the number of recent blocks whose transactions are looked up by hash, 0 for all of them.
+// The lookups of the older blocks are removed, or written back, as the next blocks are indexed
+func (b *Blockchain) SetTxLookupLimit(limit uint64) {
+	b.txLookup.setLimit(limit)
+}
+
+// RegisterIndexer registers the index of the canonical chain, which is fed with the blocks
+// from its checkpoint, or from the genesis for a new one, and then with the blocks of the reorgs
+func (b *Blockchain) RegisterIndexer(indexer Indexer) error {
+	if b.indexer == nil {
+		return ErrNoChainIndexer
+	}
+
+	return b.indexer.Register(indexer)
+}
+
+// GetIndexerProgress returns the number of the last block indexed by the indexer, false if it indexed none
+func (b *Blockchain) GetIndexerProgress(name string) (uint64, bool) {
+	if b.indexer == nil {
+		return 0, false
+	}
+
+	return b.indexer.Progress(name)
+}
+
+// DBStats returns the statistics of the blockchain database
+func (b *Blockchain) DBStats() (string, error) {
+	return b.db.Stats()
+}
+
 // Close closes the DB connection
 func (b *Blockchain) Close() error {
+	if b.bloomIndex != nil {
+		b.bloomIndex.close()
+	}
+
+	if b.accumulator != nil {
+		b.accumulator.close()
+	}
+
+	if b.indexer != nil {
+		b.indexer.close()
+	}
+
 	return b.db.Close()
 }
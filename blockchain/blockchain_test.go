@@ -12,6 +12,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
@@ -566,6 +567,8 @@ func TestBlockchainWriteBody(t *testing.T) {
 			},
 		}
 
+		assert.NoError(t, chain.initCaches(10))
+
 		return chain
 	}
 
@@ -868,6 +871,8 @@ func TestBlockchainReadBody(t *testing.T) {
 		},
 	}
 
+	assert.NoError(t, b.initCaches(10))
+
 	tx := &types.Transaction{
 		Value: big.NewInt(10),
 		V:     big.NewInt(1),
@@ -898,10 +903,281 @@ func TestBlockchainReadBody(t *testing.T) {
 	assert.Equal(t, addr, readBody.Transactions[0].From)
 }
 
+func TestBlockchainCaches(t *testing.T) {
+	t.Parallel()
+
+	storage, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	b := &Blockchain{
+		logger:   hclog.NewNullLogger(),
+		db:       storage,
+		txSigner: &mockSigner{},
+	}
+
+	require.NoError(t, b.initCaches(10))
+
+	hashes := []types.Hash{types.StringToHash("1"), types.StringToHash("2")}
+
+	for _, hash := range hashes {
+		tx := (&types.Transaction{Value: big.NewInt(1), V: big.NewInt(1), From: types.StringToAddress("1")}).ComputeHash()
+		receipt := &types.Receipt{CumulativeGasUsed: 1, GasUsed: 1, TxHash: tx.Hash}
+		receipt.SetStatus(types.ReceiptSuccess)
+
+		require.NoError(t, storage.WriteBody(hash, &types.Body{Transactions: []*types.Transaction{tx}}))
+		require.NoError(t, storage.WriteReceipts(hash, []*types.Receipt{receipt}))
+	}
+
+	// the blocks read once are served from the caches
+	for _, hash := range hashes {
+		body, ok := b.GetBodyByHash(hash)
+		require.True(t, ok)
+
+		cached, ok := b.GetBodyByHash(hash)
+		require.True(t, ok)
+		assert.Same(t, body, cached)
+
+		receipts, err := b.GetReceiptsByHash(hash)
+		require.NoError(t, err)
+
+		receipt, err := b.GetReceipt(hash, 0)
+		require.NoError(t, err)
+		assert.Same(t, receipts[0], receipt)
+
+		_, err = b.GetReceipt(hash, 1)
+		assert.ErrorIs(t, err, types.ErrReceiptNotFound)
+	}
+
+	// the least recently used blocks are evicted from all the caches once they are shrunk
+	assert.Error(t, b.SetCacheSize(0))
+	require.NoError(t, b.SetCacheSize(1))
+
+	assert.False(t, b.bodiesCache.cache.Contains(hashes[0]))
+	assert.False(t, b.receiptsCache.cache.Contains(hashes[0]))
+	assert.True(t, b.bodiesCache.cache.Contains(hashes[1]))
+	assert.True(t, b.receiptsCache.cache.Contains(hashes[1]))
+}
+
+func TestBlockchainGetTxCountByHash(t *testing.T) {
+	t.Parallel()
+
+	storage, err := memory.NewMemoryStorage(nil)
+	assert.NoError(t, err)
+
+	b := &Blockchain{
+		logger:   hclog.NewNullLogger(),
+		db:       storage,
+		txSigner: &mockSigner{},
+	}
+
+	assert.NoError(t, b.initCaches(10))
+
+	emptyHeader := &types.Header{
+		Number:     1,
+		TxRoot:     types.EmptyRootHash,
+		Sha3Uncles: types.EmptyUncleHash,
+	}
+	emptyHeader.ComputeHash()
+
+	header := &types.Header{
+		Number: 2,
+	}
+	header.ComputeHash()
+
+	txs := make([]*types.Transaction, 3)
+	for i := range txs {
+		txs[i] = &types.Transaction{
+			Nonce: uint64(i),
+			Value: big.NewInt(10),
+			V:     big.NewInt(1),
+			From:  types.StringToAddress("1"),
+		}
+		txs[i].ComputeHash()
+	}
+
+	for _, h := range []*types.Header{emptyHeader, header} {
+		assert.NoError(t, storage.WriteHeader(h))
+	}
+
+	assert.NoError(t, storage.WriteBody(header.Hash, &types.Body{Transactions: txs}))
+
+	count, ok := b.GetTxCountByHash(emptyHeader.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, 0, count)
+
+	count, ok = b.GetTxCountByHash(header.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, 3, count)
+
+	_, ok = b.GetTxCountByHash(types.StringToHash("1"))
+	assert.False(t, ok)
+
+	// the body of the empty block is never read
+	block, ok := b.GetBlockByHash(emptyHeader.Hash, true)
+	assert.True(t, ok)
+	assert.Len(t, block.Transactions, 0)
+}
+
+func TestBlockchainIterateHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	visited := make([]uint64, 0)
+
+	assert.NoError(t, b.IterateHeaders(2, 5, func(header *types.Header) bool {
+		visited = append(visited, header.Number)
+
+		return true
+	}))
+	assert.Equal(t, []uint64{2, 3, 4, 5}, visited)
+
+	visited = visited[:0]
+
+	assert.NoError(t, b.IterateHeaders(1, 9, func(header *types.Header) bool {
+		visited = append(visited, header.Number)
+
+		return header.Number < 3
+	}))
+	assert.Equal(t, []uint64{1, 2, 3}, visited)
+
+	assert.Error(t, b.IterateHeaders(8, 12, func(header *types.Header) bool {
+		return true
+	}))
+}
+
+func TestBlockchainImportHeader(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(5)
+	b := NewTestBlockchain(t, headers[:2])
+
+	verifier := &MockVerifier{}
+	processed := make([]uint64, 0)
+
+	verifier.HookProcessHeaders(func(headers []*types.Header) error {
+		processed = append(processed, headers[0].Number)
+
+		return nil
+	})
+	b.SetConsensus(verifier)
+
+	// the imported headers extend the head
+	assert.ErrorIs(t, b.ImportHeader(headers[3], "test"), ErrParentHashMismatch)
+
+	for _, header := range headers[2:4] {
+		assert.NoError(t, b.ImportHeader(header, "test"))
+	}
+
+	assert.Equal(t, headers[3].Hash, b.Header().Hash)
+	assert.Equal(t, []uint64{2, 3}, processed)
+
+	// the headers rejected by the consensus are not written
+	verifier.HookVerifyHeader(func(header *types.Header) error {
+		return errors.New("invalid seal")
+	})
+
+	assert.Error(t, b.ImportHeader(headers[4], "test"))
+	assert.Equal(t, headers[3].Hash, b.Header().Hash)
+}
+
+func TestBlockchainSetHead(t *testing.T) {
+	t.Parallel()
+
+	tx := &types.Transaction{Nonce: 1, GasPrice: big.NewInt(1), Value: big.NewInt(1)}
+	tx.ComputeHash()
+
+	// the transaction is in the body of the block 3
+	headers := NewTestHeaders(5)
+	headers[3].TxRoot = types.StringToHash("1")
+	headers[3].ComputeHash()
+	headers[4].ParentHash = headers[3].Hash
+	headers[4].ComputeHash()
+
+	b := NewTestBlockchain(t, headers)
+
+	require.NoError(t, b.db.WriteBody(headers[3].Hash, &types.Body{Transactions: []*types.Transaction{tx}}))
+	require.NoError(t, b.db.WriteTxLookup(tx.Hash, headers[3].Hash, 0))
+
+	sub := b.SubscribeEvents()
+	defer sub.Close()
+
+	assert.ErrorIs(t, b.SetHead(5), ErrInvalidRewind)
+
+	require.NoError(t, b.SetHead(2))
+
+	assert.Equal(t, headers[2].Hash, b.Header().Hash)
+
+	head, ok := b.db.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, headers[2].Hash, head)
+
+	// the dropped blocks are neither canonical nor looked up anymore
+	_, ok = b.GetHeaderByNumber(3)
+	assert.False(t, ok)
+
+	_, ok = b.GetBlockByNumber(4, true)
+	assert.False(t, ok)
+
+	_, ok = b.ReadTxLookup(tx.Hash)
+	assert.False(t, ok)
+
+	evnt := sub.GetEvent()
+	assert.Equal(t, EventReorg, evnt.Type)
+	require.Len(t, evnt.OldChain, 2)
+	assert.Equal(t, headers[4].Hash, evnt.OldChain[0].Hash)
+	assert.Equal(t, headers[3].Hash, evnt.OldChain[1].Hash)
+	assert.Equal(t, headers[2].Hash, evnt.Header().Hash)
+
+	// the chain goes on from the new head
+	newHeaders := AppendNewTestheadersWithSeed(headers[:3], 2, 1)
+	require.NoError(t, b.WriteHeaders(newHeaders[3:]))
+
+	header, ok := b.GetHeaderByNumber(4)
+	assert.True(t, ok)
+	assert.Equal(t, newHeaders[4].Hash, header.Hash)
+}
+
+func TestBlockchainFinalized(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(6)
+	b := NewTestBlockchain(t, headers)
+
+	require.NoError(t, b.SetFinalized(headers[3]))
+	assert.Equal(t, headers[3].Hash, b.FinalizedHeader().Hash)
+
+	// the finalized block only moves forward
+	require.NoError(t, b.SetFinalized(headers[1]))
+	assert.Equal(t, headers[3].Hash, b.FinalizedHeader().Hash)
+
+	finalized, ok := b.db.ReadFinalizedHash()
+	assert.True(t, ok)
+	assert.Equal(t, headers[3].Hash, finalized)
+
+	// a fork from before the finalized block is refused, even with a higher difficulty
+	deepFork := AppendNewTestheadersWithSeed(headers[:3], 5, 1)
+	assert.ErrorIs(t, b.WriteHeaders(deepFork[3:]), ErrReorgBelowFinalized)
+	assert.Equal(t, headers[5].Hash, b.Header().Hash)
+
+	assert.ErrorIs(t, b.SetFinalized(deepFork[4]), ErrFinalizedNotCanonical)
+
+	// a fork from after the finalized block is followed
+	fork := AppendNewTestheadersWithSeed(headers[:5], 3, 1)
+	require.NoError(t, b.WriteHeaders(fork[5:]))
+	assert.Equal(t, fork[7].Hash, b.Header().Hash)
+
+	// rewinding the head below the finalized block rewinds the finality
+	require.NoError(t, b.SetHead(2))
+	assert.Equal(t, headers[2].Hash, b.FinalizedHeader().Hash)
+}
+
 func TestCalculateGasLimit(t *testing.T) {
 	tests := []struct {
 		name             string
 		blockGasTarget   uint64
+		governedTarget   uint64
 		parentGasLimit   uint64
 		expectedGasLimit uint64
 	}{
@@ -935,6 +1211,13 @@ func TestCalculateGasLimit(t *testing.T) {
 			parentGasLimit:   25000000,
 			expectedGasLimit: 25000000 - 25000000/1024 + 100,
 		},
+		{
+			name:             "should move towards the governed target over the one of the node",
+			blockGasTarget:   25000000,
+			governedTarget:   15000000,
+			parentGasLimit:   20000000,
+			expectedGasLimit: 20000000 - 20000000/1024,
+		},
 	}
 
 	for _, tt := range tests {
@@ -948,8 +1231,13 @@ func TestCalculateGasLimit(t *testing.T) {
 				})
 			}
 
+			executorCallback := func(executor *mockExecutor) {
+				executor.gasTarget = tt.governedTarget
+			}
+
 			b, blockchainErr := NewMockBlockchain(map[TestCallbackType]interface{}{
-				StorageCallback: storageCallback,
+				StorageCallback:  storageCallback,
+				ExecutorCallback: executorCallback,
 			})
 			if blockchainErr != nil {
 				t.Fatalf("unable to construct the blockchain, %v", blockchainErr)
@@ -1288,6 +1576,77 @@ func TestBlockchain_VerifyBlockBody(t *testing.T) {
 		assert.ErrorIs(t, blockchain.verifyBlockBody(block), errBlockCreatorNotFound)
 	})
 
+	t.Run("Invalid execution result - gas limit not moving toward the governed target", func(t *testing.T) {
+		t.Parallel()
+
+		parentHeader := emptyHeader.Copy()
+		parentHeader.GasLimit = 20480000
+
+		storageCallback := func(storage *storage.MockStorage) {
+			storage.HookReadHeader(func(hash types.Hash) (*types.Header, error) {
+				return parentHeader, nil
+			})
+		}
+
+		executorCallback := func(executor *mockExecutor) {
+			executor.gasTarget = 30000000
+		}
+
+		blockchain, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+			StorageCallback:  storageCallback,
+			ExecutorCallback: executorCallback,
+		})
+		if err != nil {
+			t.Fatalf("unable to instantiate new blockchain, %v", err)
+		}
+
+		// the gas limit is within the bound, but moves away from the target
+		block := &types.Block{
+			Header: &types.Header{
+				Sha3Uncles: types.EmptyUncleHash,
+				TxRoot:     types.EmptyRootHash,
+				GasLimit:   parentHeader.GasLimit - 1000,
+			},
+		}
+
+		assert.ErrorIs(t, blockchain.verifyBlockBody(block), ErrInvalidGasTargetStep)
+		assert.NoError(t, blockchain.verifyGasTarget(&types.Header{GasLimit: parentHeader.GasLimit + 20000}, parentHeader))
+	})
+
+	t.Run("Invalid execution result - unable to read the gas target", func(t *testing.T) {
+		t.Parallel()
+
+		errStateNotFound := errors.New("state not found")
+
+		storageCallback := func(storage *storage.MockStorage) {
+			storage.HookReadHeader(func(hash types.Hash) (*types.Header, error) {
+				return emptyHeader, nil
+			})
+		}
+
+		// the nodes without the state of the parent don't skip the gas target
+		executorCallback := func(executor *mockExecutor) {
+			executor.gasTargetErr = errStateNotFound
+		}
+
+		blockchain, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+			StorageCallback:  storageCallback,
+			ExecutorCallback: executorCallback,
+		})
+		if err != nil {
+			t.Fatalf("unable to instantiate new blockchain, %v", err)
+		}
+
+		block := &types.Block{
+			Header: &types.Header{
+				Sha3Uncles: types.EmptyUncleHash,
+				TxRoot:     types.EmptyRootHash,
+			},
+		}
+
+		assert.ErrorIs(t, blockchain.verifyBlockBody(block), errStateNotFound)
+	})
+
 	t.Run("Invalid execution result - unable to execute transactions", func(t *testing.T) {
 		t.Parallel()
 
@@ -1330,3 +1689,63 @@ func TestBlockchain_VerifyBlockBody(t *testing.T) {
 		assert.ErrorIs(t, blockchain.verifyBlockBody(block), errUnableToExecute)
 	})
 }
+
+func TestBlockchain_VerifyBlockSize(t *testing.T) {
+	t.Parallel()
+
+	txs := []*types.Transaction{
+		{Nonce: 0, Value: big.NewInt(1), GasPrice: big.NewInt(1), Input: make([]byte, 100)},
+		{Nonce: 1, Value: big.NewInt(1), GasPrice: big.NewInt(1), Input: make([]byte, 100)},
+	}
+
+	size := txs[0].Size() + txs[1].Size()
+
+	testCases := []struct {
+		name    string
+		fork    *chain.Fork
+		maxSize uint64
+		err     error
+	}{
+		{
+			name:    "should accept any size before the fork",
+			fork:    chain.NewFork(2),
+			maxSize: size - 1,
+		},
+		{
+			name:    "should accept the block within the limit",
+			fork:    chain.NewFork(0),
+			maxSize: size,
+		},
+		{
+			name:    "should reject the block exceeding the limit",
+			fork:    chain.NewFork(0),
+			maxSize: size - 1,
+			err:     ErrBlockTooLarge,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			blockchain, err := NewMockBlockchain(nil)
+			if err != nil {
+				t.Fatalf("unable to instantiate new blockchain, %v", err)
+			}
+
+			blockchain.config.Params.Forks.BlockSize = testCase.fork
+			blockchain.config.Params.MaxBlockSize = testCase.maxSize
+
+			block := &types.Block{
+				Header: &types.Header{
+					Number: 1,
+				},
+				Transactions: txs,
+			}
+
+			assert.ErrorIs(t, blockchain.verifyBlockSize(block), testCase.err)
+		})
+	}
+}
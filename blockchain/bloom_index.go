@@ -0,0 +1,322 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// BloomSectionSize is the number of blocks covered by a single section of the bloom bits index
+	BloomSectionSize = 4096
+
+	// bloomBitLength is the number of bits in a logs bloom
+	bloomBitLength = types.BloomByteLength * 8
+)
+
+// bloomIndex builds and queries the bloom bits index of the canonical chain.
+// The logs blooms of the headers are grouped into sections, and for every bit of the bloom
+// a vector with one bit per block of the section is stored, so the blocks which possibly
+// contain the logs of a query are found by reading a few vectors, instead of every header of the range.
+// A section is only indexed once all its blocks are written, and it is invalidated by a reorg
+// which touches any of its blocks
+type bloomIndex struct {
+	logger      hclog.Logger
+	db          storage.Storage
+	sectionSize uint64
+
+	// getHeader returns the canonical header with the given number
+	getHeader func(uint64) (*types.Header, bool)
+
+	// headNumber returns the number of the current head
+	headNumber func() uint64
+
+	lock     sync.RWMutex
+	sections uint64 // number of consecutive indexed sections, starting from genesis
+
+	updateCh  chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newBloomIndex(
+	logger hclog.Logger,
+	db storage.Storage,
+	sectionSize uint64,
+	getHeader func(uint64) (*types.Header, bool),
+	headNumber func() uint64,
+) *bloomIndex {
+	return &bloomIndex{
+		logger:      logger.Named("bloom-index"),
+		db:          db,
+		sectionSize: sectionSize,
+		getHeader:   getHeader,
+		headNumber:  headNumber,
+		updateCh:    make(chan struct{}, 1),
+		closeCh:     make(chan struct{}),
+	}
+}
+
+// start loads the already indexed sections and starts indexing the new ones in the background
+func (i *bloomIndex) start() {
+	sections := uint64(0)
+	for i.isSectionValid(sections) {
+		sections++
+	}
+
+	i.lock.Lock()
+	i.sections = sections
+	i.lock.Unlock()
+
+	go i.run()
+
+	i.notify()
+}
+
+// close stops the background indexing
+func (i *bloomIndex) close() {
+	i.closeOnce.Do(func() {
+		close(i.closeCh)
+	})
+}
+
+// notify signals the background routine that new blocks are available
+func (i *bloomIndex) notify() {
+	select {
+	case i.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// Name returns the name of the bloom bits index for the chain indexer
+func (i *bloomIndex) Name() string {
+	return "bloombits"
+}
+
+// Connect schedules the indexing of the section completed by the block
+func (i *bloomIndex) Connect(header *types.Header) error {
+	if (header.Number+1)%i.sectionSize == 0 {
+		i.notify()
+	}
+
+	return nil
+}
+
+// Disconnect invalidates the section of the block which left the canonical chain
+func (i *bloomIndex) Disconnect(header *types.Header) error {
+	i.rewind(header.Number)
+
+	return nil
+}
+
+// rewind invalidates the sections starting from the one containing the given block
+func (i *bloomIndex) rewind(number uint64) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if section := number / i.sectionSize; section < i.sections {
+		i.sections = section
+	}
+}
+
+func (i *bloomIndex) run() {
+	for {
+		select {
+		case <-i.updateCh:
+			i.indexSections()
+		case <-i.closeCh:
+			return
+		}
+	}
+}
+
+// indexSections indexes all the completed sections which are not indexed yet
+func (i *bloomIndex) indexSections() {
+	for {
+		i.lock.RLock()
+		section := i.sections
+		i.lock.RUnlock()
+
+		if (section+1)*i.sectionSize-1 > i.headNumber() {
+			return
+		}
+
+		select {
+		case <-i.closeCh:
+			return
+		default:
+		}
+
+		if err := i.indexSection(section); err != nil {
+			i.logger.Error("failed to index section", "section", section, "err", err)
+
+			return
+		}
+
+		i.lock.Lock()
+		// the section could have been invalidated by a reorg while being indexed
+		if i.sections == section {
+			i.sections++
+		}
+		i.lock.Unlock()
+
+		i.logger.Debug("section indexed", "section", section)
+	}
+}
+
+// indexSection builds and writes the bloom bits vectors of the section
+func (i *bloomIndex) indexSection(section uint64) error {
+	var (
+		vectors = make([][]byte, bloomBitLength)
+		start   = section * i.sectionSize
+		parent  *types.Header
+	)
+
+	for offset := uint64(0); offset < i.sectionSize; offset++ {
+		header, ok := i.getHeader(start + offset)
+		if !ok {
+			return fmt.Errorf("header %d not found", start+offset)
+		}
+
+		// make sure the section is not built from the headers of different forks
+		if parent != nil && header.ParentHash != parent.Hash {
+			return fmt.Errorf("header %d is not a child of the previous header", header.Number)
+		}
+
+		parent = header
+
+		for bit := uint(0); bit < bloomBitLength; bit++ {
+			if !header.LogsBloom.IsBitSet(bit) {
+				continue
+			}
+
+			if vectors[bit] == nil {
+				vectors[bit] = make([]byte, i.sectionSize/8)
+			}
+
+			vectors[bit][offset/8] |= 1 << (7 - offset%8)
+		}
+	}
+
+	// the vectors without any set bit are not stored
+	for bit, vector := range vectors {
+		if vector == nil {
+			continue
+		}
+
+		if err := i.db.WriteBloomBits(uint(bit), section, vector); err != nil {
+			return err
+		}
+	}
+
+	return i.db.WriteBloomSectionHead(section, parent.Hash)
+}
+
+// isSectionValid checks if the section is indexed on top of the current canonical chain
+func (i *bloomIndex) isSectionValid(section uint64) bool {
+	head, ok := i.db.ReadBloomSectionHead(section)
+	if !ok {
+		return false
+	}
+
+	canonical, ok := i.db.ReadCanonicalHash((section+1)*i.sectionSize - 1)
+
+	return ok && canonical == head
+}
+
+// matches returns the numbers of the blocks in the range whose logs bloom possibly matches the filter.
+// The filter is a list of groups of byte slices, and a block matches if at least one element of
+// every group is present in its bloom. Only the indexed part of the range is processed,
+// so the number of the first block after it is returned as well
+func (i *bloomIndex) matches(from, to uint64, filter [][][]byte) ([]uint64, uint64) {
+	groups := make([][][3]uint, len(filter))
+
+	for idx, group := range filter {
+		groups[idx] = make([][3]uint, len(group))
+
+		for j, data := range group {
+			groups[idx][j] = types.BloomBits(data)
+		}
+	}
+
+	i.lock.RLock()
+	sections := i.sections
+	i.lock.RUnlock()
+
+	var (
+		matches = make([]uint64, 0)
+		next    = from
+	)
+
+	for section := from / i.sectionSize; section < sections; section++ {
+		start := section * i.sectionSize
+		if start > to || !i.isSectionValid(section) {
+			break
+		}
+
+		vector := i.matchSection(section, groups)
+
+		for offset := uint64(0); offset < i.sectionSize; offset++ {
+			num := start + offset
+			if num < from || num > to {
+				continue
+			}
+
+			if vector[offset/8]&(1<<(7-offset%8)) != 0 {
+				matches = append(matches, num)
+			}
+		}
+
+		next = start + i.sectionSize
+		if next > to {
+			next = to + 1
+		}
+	}
+
+	return matches, next
+}
+
+// matchSection returns the vector of the blocks of the section which possibly match the bloom bit groups
+func (i *bloomIndex) matchSection(section uint64, groups [][][3]uint) []byte {
+	cache := make(map[uint][]byte)
+
+	readBits := func(bit uint) []byte {
+		if vector, ok := cache[bit]; ok {
+			return vector
+		}
+
+		vector, ok := i.db.ReadBloomBits(bit, section)
+		if !ok || uint64(len(vector)) != i.sectionSize/8 {
+			// the vectors without any set bit are not stored
+			vector = make([]byte, i.sectionSize/8)
+		}
+
+		cache[bit] = vector
+
+		return vector
+	}
+
+	result := make([]byte, i.sectionSize/8)
+	for idx := range result {
+		result[idx] = 0xff
+	}
+
+	for _, group := range groups {
+		groupVector := make([]byte, i.sectionSize/8)
+
+		for _, bits := range group {
+			for idx := range groupVector {
+				groupVector[idx] |= readBits(bits[0])[idx] & readBits(bits[1])[idx] & readBits(bits[2])[idx]
+			}
+		}
+
+		for idx := range result {
+			result[idx] &= groupVector[idx]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,174 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSectionSize = 16
+
+// newTestBloomChain writes a canonical chain of headers, where the blocks with the numbers
+// found in the logs map contain a log of the given address. The headers starting from forkAt
+// differ from the ones of the chains with another fork point
+func newTestBloomChain(
+	t *testing.T,
+	db storage.Storage,
+	length uint64,
+	logs map[uint64]types.Address,
+	forkAt uint64,
+) []*types.Header {
+	t.Helper()
+
+	headers := make([]*types.Header, length)
+
+	for i := uint64(0); i < length; i++ {
+		header := &types.Header{
+			Number: i,
+		}
+
+		if i >= forkAt {
+			header.ExtraData = []byte("fork")
+		}
+
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash
+		}
+
+		if addr, ok := logs[i]; ok {
+			header.LogsBloom = types.CreateBloom([]*types.Receipt{
+				{Logs: []*types.Log{{Address: addr}}},
+			})
+		}
+
+		header.ComputeHash()
+		headers[i] = header
+
+		assert.NoError(t, db.WriteCanonicalHash(i, header.Hash))
+	}
+
+	return headers
+}
+
+func newTestBloomIndex(t *testing.T, db storage.Storage, headers *[]*types.Header) *bloomIndex {
+	t.Helper()
+
+	return newBloomIndex(
+		hclog.NewNullLogger(),
+		db,
+		testSectionSize,
+		func(n uint64) (*types.Header, bool) {
+			if n >= uint64(len(*headers)) {
+				return nil, false
+			}
+
+			return (*headers)[n], true
+		},
+		func() uint64 {
+			return uint64(len(*headers)) - 1
+		},
+	)
+}
+
+func TestBloomIndex_Matches(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr1 = types.StringToAddress("1")
+		addr2 = types.StringToAddress("2")
+		addr3 = types.StringToAddress("3")
+	)
+
+	db, err := memory.NewMemoryStorage(nil)
+	assert.NoError(t, err)
+
+	// two complete sections and a partial one
+	headers := newTestBloomChain(t, db, 2*testSectionSize+5, map[uint64]types.Address{
+		3:  addr1,
+		17: addr2,
+		20: addr1,
+		33: addr1,
+	}, 2*testSectionSize+5)
+
+	index := newTestBloomIndex(t, db, &headers)
+	index.indexSections()
+
+	assert.Equal(t, uint64(2), index.sections)
+
+	// only the complete sections are covered by the index
+	matches, next := index.matches(0, 36, [][][]byte{{addr1.Bytes()}})
+	assert.Equal(t, []uint64{3, 20}, matches)
+	assert.Equal(t, uint64(2*testSectionSize), next)
+
+	// the range bounds are respected
+	matches, next = index.matches(4, 25, [][][]byte{{addr1.Bytes(), addr2.Bytes()}})
+	assert.Equal(t, []uint64{17, 20}, matches)
+	assert.Equal(t, uint64(26), next)
+
+	// every group has to match
+	matches, _ = index.matches(0, 31, [][][]byte{{addr1.Bytes()}, {addr2.Bytes()}})
+	assert.Empty(t, matches)
+
+	matches, _ = index.matches(0, 31, [][][]byte{{addr3.Bytes()}})
+	assert.Empty(t, matches)
+
+	// the index is loaded from the storage on start
+	restarted := newTestBloomIndex(t, db, &headers)
+	restarted.start()
+	restarted.close()
+
+	assert.Equal(t, uint64(2), restarted.sections)
+}
+
+func TestBloomIndex_Reorg(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr1 = types.StringToAddress("1")
+		addr2 = types.StringToAddress("2")
+	)
+
+	db, err := memory.NewMemoryStorage(nil)
+	assert.NoError(t, err)
+
+	headers := newTestBloomChain(t, db, 2*testSectionSize, map[uint64]types.Address{
+		5:  addr1,
+		20: addr1,
+	}, 2*testSectionSize)
+
+	index := newTestBloomIndex(t, db, &headers)
+	index.indexSections()
+
+	// the second section is replaced by a fork
+	forked := newTestBloomChain(t, db, 2*testSectionSize, map[uint64]types.Address{
+		5:  addr1,
+		22: addr2,
+	}, testSectionSize+2)
+
+	// the stale section is not used even before the reorg is handled
+	matches, next := index.matches(0, 31, [][][]byte{{addr1.Bytes()}})
+	assert.Equal(t, []uint64{5}, matches)
+	assert.Equal(t, uint64(testSectionSize), next)
+
+	// the replaced blocks are disconnected from the newest one
+	for n := 2*testSectionSize - 1; n >= testSectionSize+2; n-- {
+		assert.NoError(t, index.Disconnect(headers[n]))
+	}
+
+	headers = forked
+
+	assert.Equal(t, uint64(1), index.sections)
+
+	index.indexSections()
+
+	matches, next = index.matches(0, 31, [][][]byte{{addr1.Bytes()}, {addr2.Bytes()}})
+	assert.Empty(t, matches)
+	assert.Equal(t, uint64(32), next)
+
+	matches, _ = index.matches(0, 31, [][][]byte{{addr2.Bytes()}})
+	assert.Equal(t, []uint64{22}, matches)
+}
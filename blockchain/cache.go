@@ -0,0 +1,56 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/armon/go-metrics"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// blockCache is a LRU cache of the data of the blocks by their hash, which counts its hits and misses
+type blockCache struct {
+	name   string
+	labels []metrics.Label
+	cache  *lru.Cache
+}
+
+func newBlockCache(name string, size int) (*blockCache, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s cache, %w", name, err)
+	}
+
+	return &blockCache{
+		name:   name,
+		labels: []metrics.Label{{Name: "cache", Value: name}},
+		cache:  cache,
+	}, nil
+}
+
+func (c *blockCache) get(hash types.Hash) (interface{}, bool) {
+	value, ok := c.cache.Get(hash)
+	if !ok {
+		metrics.IncrCounterWithLabels([]string{"blockchain_cache_misses"}, 1, c.labels)
+
+		return nil, false
+	}
+
+	metrics.IncrCounterWithLabels([]string{"blockchain_cache_hits"}, 1, c.labels)
+
+	return value, true
+}
+
+func (c *blockCache) add(hash types.Hash, value interface{}) {
+	c.cache.Add(hash, value)
+
+	metrics.SetGaugeWithLabels([]string{"blockchain_cache_entries"}, float32(c.cache.Len()), c.labels)
+}
+
+// resize changes the number of entries of the cache, evicting the least recently used ones above it
+func (c *blockCache) resize(size int) {
+	c.cache.Resize(size)
+
+	metrics.SetGaugeWithLabels([]string{"blockchain_cache_entries"}, float32(c.cache.Len()), c.labels)
+}
@@ -0,0 +1,268 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+var (
+	ErrIndexerRegistered = errors.New("indexer already registered")
+	ErrIndexerOtherChain = errors.New("the index was built on another chain")
+	ErrNoChainIndexer    = errors.New("the blockchain has no chain indexer")
+
+	errHistoryMissing = errors.New("header not found")
+)
+
+// Indexer is an index of the canonical chain, which the chain indexer feeds with the blocks
+// joining and leaving the canonical chain, in order
+type Indexer interface {
+	// Name returns the unique name the progress of the index is checkpointed under
+	Name() string
+
+	// Connect indexes the block which joined the canonical chain, right after its parent
+	Connect(header *types.Header) error
+
+	// Disconnect removes the last indexed block, which left the canonical chain in a reorg
+	Disconnect(header *types.Header) error
+}
+
+// indexerProgress is a registered indexer, along with the last block it indexed
+type indexerProgress struct {
+	indexer Indexer
+
+	// indexed is false until the first block, the genesis, is indexed
+	indexed bool
+	number  uint64
+	hash    types.Hash
+}
+
+// ChainIndexer feeds the registered indexers with the blocks of the canonical chain.
+// Each indexer is brought from its checkpoint to the current head: the blocks it indexed which left
+// the canonical chain are disconnected from the newest one, then the canonical blocks are connected.
+// Since the canonical chain is compared to the checkpoints rather than to the content of the events,
+// the reorgs happening while the node is stopped or while an event is dropped are handled too
+type ChainIndexer struct {
+	logger hclog.Logger
+	db     storage.Storage
+
+	// getHeader returns the canonical header with the given number
+	getHeader func(uint64) (*types.Header, bool)
+
+	// headNumber returns the number of the current head
+	headNumber func() uint64
+
+	lock      sync.Mutex
+	indexers  []*indexerProgress
+	started   bool
+	startOnce sync.Once
+
+	updateCh  chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newChainIndexer(
+	logger hclog.Logger,
+	db storage.Storage,
+	getHeader func(uint64) (*types.Header, bool),
+	headNumber func() uint64,
+) *ChainIndexer {
+	return &ChainIndexer{
+		logger:     logger.Named("chain-indexer"),
+		db:         db,
+		getHeader:  getHeader,
+		headNumber: headNumber,
+		updateCh:   make(chan struct{}, 1),
+		closeCh:    make(chan struct{}),
+	}
+}
+
+// Register adds the indexer, which resumes from its checkpoint, or from the genesis if it has none
+func (c *ChainIndexer) Register(indexer Indexer) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, p := range c.indexers {
+		if p.indexer.Name() == indexer.Name() {
+			return fmt.Errorf("%w: %s", ErrIndexerRegistered, indexer.Name())
+		}
+	}
+
+	p := &indexerProgress{indexer: indexer}
+	p.number, p.hash, p.indexed = c.db.ReadIndexerCheckpoint(indexer.Name())
+
+	c.indexers = append(c.indexers, p)
+
+	if c.started {
+		c.notify()
+	}
+
+	return nil
+}
+
+// Progress returns the number of the last block indexed by the indexer, false if it indexed none
+func (c *ChainIndexer) Progress(name string) (uint64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, p := range c.indexers {
+		if p.indexer.Name() == name {
+			return p.number, p.indexed
+		}
+	}
+
+	return 0, false
+}
+
+// start starts feeding the indexers in the background
+func (c *ChainIndexer) start() {
+	c.startOnce.Do(func() {
+		c.lock.Lock()
+		c.started = true
+		c.lock.Unlock()
+
+		go c.run()
+
+		c.notify()
+	})
+}
+
+// close stops feeding the indexers
+func (c *ChainIndexer) close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+	})
+}
+
+// notify signals the background routine that the canonical chain changed
+func (c *ChainIndexer) notify() {
+	select {
+	case c.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+func (c *ChainIndexer) run() {
+	for {
+		select {
+		case <-c.updateCh:
+			c.syncIndexers()
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// syncIndexers brings all the indexers to the current head
+func (c *ChainIndexer) syncIndexers() {
+	c.lock.Lock()
+	indexers := make([]*indexerProgress, len(c.indexers))
+	copy(indexers, c.indexers)
+	c.lock.Unlock()
+
+	for _, p := range indexers {
+		if err := c.sync(p); errors.Is(err, errHistoryMissing) {
+			// the chain started from a checkpoint, the blocks before it are not backfilled yet
+			c.logger.Debug("index waiting for the history", "indexer", p.indexer.Name(), "err", err)
+		} else if err != nil {
+			c.logger.Error("failed to update the index", "indexer", p.indexer.Name(), "err", err)
+		}
+	}
+}
+
+// sync disconnects the blocks indexed by the indexer which left the canonical chain,
+// then connects the canonical blocks up to the head
+func (c *ChainIndexer) sync(p *indexerProgress) error {
+	if err := c.rewind(p); err != nil {
+		return err
+	}
+
+	head := c.headNumber()
+
+	for number := c.next(p); number <= head; number++ {
+		select {
+		case <-c.closeCh:
+			return nil
+		default:
+		}
+
+		header, ok := c.getHeader(number)
+		if !ok {
+			return fmt.Errorf("%w: %d", errHistoryMissing, number)
+		}
+
+		// the chain was reorganized since the rewind, the next update resumes from the fork
+		if p.indexed && header.ParentHash != p.hash {
+			c.notify()
+
+			return nil
+		}
+
+		if err := p.indexer.Connect(header); err != nil {
+			return fmt.Errorf("failed to connect block %d: %w", number, err)
+		}
+
+		if err := c.checkpoint(p, header.Number, header.Hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewind disconnects the indexed blocks until the last one is canonical
+func (c *ChainIndexer) rewind(p *indexerProgress) error {
+	for p.indexed {
+		if canonical, ok := c.db.ReadCanonicalHash(p.number); ok && canonical == p.hash {
+			return nil
+		}
+
+		if p.number == 0 {
+			return ErrIndexerOtherChain
+		}
+
+		header, err := c.db.ReadHeader(p.hash)
+		if err != nil {
+			return fmt.Errorf("failed to read the indexed header %d: %w", p.number, err)
+		}
+
+		if err := p.indexer.Disconnect(header); err != nil {
+			return fmt.Errorf("failed to disconnect block %d: %w", header.Number, err)
+		}
+
+		if err := c.checkpoint(p, header.Number-1, header.ParentHash); err != nil {
+			return err
+		}
+
+		c.logger.Debug("block disconnected", "indexer", p.indexer.Name(), "number", header.Number)
+	}
+
+	return nil
+}
+
+// next returns the number of the next block to connect to the indexer
+func (c *ChainIndexer) next(p *indexerProgress) uint64 {
+	if !p.indexed {
+		return 0
+	}
+
+	return p.number + 1
+}
+
+// checkpoint persists the last block indexed by the indexer
+func (c *ChainIndexer) checkpoint(p *indexerProgress, number uint64, hash types.Hash) error {
+	if err := c.db.WriteIndexerCheckpoint(p.indexer.Name(), number, hash); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	p.indexed, p.number, p.hash = true, number, hash
+	c.lock.Unlock()
+
+	return nil
+}
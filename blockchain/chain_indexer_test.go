@@ -0,0 +1,134 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingIndexer records the numbers of the connected blocks, and of the disconnected ones as negatives
+type recordingIndexer struct {
+	name   string
+	events []int64
+}
+
+func (r *recordingIndexer) Name() string {
+	return r.name
+}
+
+func (r *recordingIndexer) Connect(header *types.Header) error {
+	r.events = append(r.events, int64(header.Number))
+
+	return nil
+}
+
+func (r *recordingIndexer) Disconnect(header *types.Header) error {
+	r.events = append(r.events, -int64(header.Number))
+
+	return nil
+}
+
+func newTestIndexerChain(t *testing.T, db storage.Storage, length, forkAt uint64) []*types.Header {
+	t.Helper()
+
+	headers := newTestBloomChain(t, db, length, nil, forkAt)
+
+	for _, header := range headers {
+		require.NoError(t, db.WriteHeader(header))
+	}
+
+	// the blocks after the head of a shorter chain aren't canonical anymore
+	for n := length; ; n++ {
+		if _, ok := db.ReadCanonicalHash(n); !ok {
+			break
+		}
+
+		require.NoError(t, db.WriteCanonicalHash(n, types.ZeroHash))
+	}
+
+	return headers
+}
+
+func newTestChainIndexer(db storage.Storage, headers *[]*types.Header) *ChainIndexer {
+	return newChainIndexer(
+		hclog.NewNullLogger(),
+		db,
+		func(n uint64) (*types.Header, bool) {
+			if n >= uint64(len(*headers)) {
+				return nil, false
+			}
+
+			return (*headers)[n], true
+		},
+		func() uint64 {
+			return uint64(len(*headers)) - 1
+		},
+	)
+}
+
+func TestChainIndexer_Reorg(t *testing.T) {
+	t.Parallel()
+
+	db, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	headers := newTestIndexerChain(t, db, 5, 5)
+
+	indexer := newTestChainIndexer(db, &headers)
+	recorder := &recordingIndexer{name: "test"}
+
+	require.NoError(t, indexer.Register(recorder))
+	require.ErrorIs(t, indexer.Register(&recordingIndexer{name: "test"}), ErrIndexerRegistered)
+
+	indexer.syncIndexers()
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, recorder.events)
+
+	// a longer fork replaces the blocks from 3
+	headers = newTestIndexerChain(t, db, 6, 3)
+	recorder.events = nil
+
+	indexer.syncIndexers()
+	assert.Equal(t, []int64{-4, -3, 3, 4, 5}, recorder.events)
+
+	number, ok := indexer.Progress("test")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5), number)
+
+	// the progress survives a restart, which happens after a reorg to a shorter chain
+	headers = newTestIndexerChain(t, db, 3, 2)
+
+	restarted := newTestChainIndexer(db, &headers)
+	recorder = &recordingIndexer{name: "test"}
+
+	require.NoError(t, restarted.Register(recorder))
+	restarted.syncIndexers()
+
+	assert.Equal(t, []int64{-5, -4, -3, -2, 2}, recorder.events)
+
+	number, ok = restarted.Progress("test")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), number)
+}
+
+func TestChainIndexer_OtherChain(t *testing.T) {
+	t.Parallel()
+
+	db, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	headers := newTestIndexerChain(t, db, 3, 3)
+
+	require.NoError(t, db.WriteIndexerCheckpoint("test", 0, types.StringToHash("genesis")))
+
+	indexer := newTestChainIndexer(db, &headers)
+	recorder := &recordingIndexer{name: "test"}
+
+	require.NoError(t, indexer.Register(recorder))
+	assert.ErrorIs(t, indexer.sync(indexer.indexers[0]), ErrIndexerOtherChain)
+	assert.Empty(t, recorder.events)
+}
@@ -0,0 +1,204 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+)
+
+var (
+	ErrChainNotEmpty     = errors.New("the chain already has blocks after the genesis")
+	ErrBackfillNotLinked = errors.New("the backfilled block is not the parent of the oldest stored block")
+)
+
+// WriteCheckpoint writes the trusted checkpoint block along with its receipts as the head of a fresh chain,
+// without its ancestors. The checkpoint is final, and its state has to be fetched beforehand.
+// The blocks following it are then synced as usual, the ones before it are written by WriteBackfilledBlocks
+func (b *Blockchain) WriteCheckpoint(block *types.Block, receipts []*types.Receipt, source string) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if head := b.Header(); head.Number != 0 {
+		return fmt.Errorf("%w: head %d", ErrChainNotEmpty, head.Number)
+	}
+
+	header := block.Header
+
+	if err := VerifyBlockData(block, receipts); err != nil {
+		return err
+	}
+
+	if err := b.writeBlockData(block, receipts); err != nil {
+		return err
+	}
+
+	// the difficulty of the ancestors is unknown, the chain is only extended from the checkpoint
+	td := new(big.Int).SetUint64(header.Difficulty)
+	if err := b.db.WriteTotalDifficulty(header.Hash, td); err != nil {
+		return err
+	}
+
+	if err := b.db.WriteHeadHash(header.Hash); err != nil {
+		return err
+	}
+
+	if err := b.db.WriteHeadNumber(header.Number); err != nil {
+		return err
+	}
+
+	if err := b.db.WriteFinalizedHash(header.Hash); err != nil {
+		return err
+	}
+
+	b.setCurrentHeader(header, td)
+	b.finalizedHeader.Store(header.Copy())
+
+	evnt := &Event{Source: source, Type: EventHead}
+	evnt.AddNewHeader(header)
+	evnt.SetDifficulty(td)
+
+	b.dispatchEvent(evnt)
+
+	b.logger.Info("chain started from checkpoint", "number", header.Number, "hash", header.Hash)
+
+	return nil
+}
+
+// WriteBackfilledBlocks writes the blocks before the oldest block of a chain started from a checkpoint,
+// from the newest to the oldest, along with their receipts. Each block has to be the parent of the one after it,
+// so the backfilled blocks are as trusted as the checkpoint without being executed. The head doesn't change
+func (b *Blockchain) WriteBackfilledBlocks(blocks []*types.Block, receipts [][]*types.Receipt) error {
+	if len(blocks) != len(receipts) {
+		return ErrInvalidReceiptsSize
+	}
+
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	child, ok := b.GetHeaderByNumber(blocks[0].Number() + 1)
+	if !ok {
+		return fmt.Errorf("%w: block %d not found", ErrBackfillNotLinked, blocks[0].Number()+1)
+	}
+
+	// all the blocks are verified before any is written
+	for i, block := range blocks {
+		if block.Number() == 0 || block.Number()+1 != child.Number || block.Hash() != child.ParentHash {
+			return fmt.Errorf("%w: block %d (%s)", ErrBackfillNotLinked, block.Number(), block.Hash())
+		}
+
+		if err := VerifyBlockData(block, receipts[i]); err != nil {
+			return fmt.Errorf("invalid block %d: %w", block.Number(), err)
+		}
+
+		child = block.Header
+	}
+
+	if child.Number == 1 && child.ParentHash != b.genesis {
+		return fmt.Errorf("%w: the genesis %s is not the parent of block 1", ErrBackfillNotLinked, b.genesis)
+	}
+
+	for i, block := range blocks {
+		if err := b.writeBlockData(block, receipts[i]); err != nil {
+			return err
+		}
+	}
+
+	// the indexes built from the genesis resume once the history is complete
+	if b.indexer != nil {
+		b.indexer.notify()
+	}
+
+	return nil
+}
+
+// MissingHistory returns the number of the newest block missing before the head,
+// which is the next one to backfill for a chain started from a checkpoint. It's 0 if no block is missing
+func (b *Blockchain) MissingHistory() uint64 {
+	head := b.Header().Number
+
+	// the stored blocks are the ones from the oldest backfilled block up to the head
+	oldest := uint64(sort.Search(int(head), func(i int) bool {
+		_, ok := b.GetHeaderByNumber(uint64(i) + 1)
+
+		return ok
+	})) + 1
+
+	return oldest - 1
+}
+
+// writeBlockData writes the verified block and its receipts as canonical, without changing the head
+func (b *Blockchain) writeBlockData(block *types.Block, receipts []*types.Receipt) error {
+	if err := b.writeBody(block); err != nil {
+		return err
+	}
+
+	completeReceipts(block, receipts)
+
+	if err := b.db.WriteHeader(block.Header); err != nil {
+		return err
+	}
+
+	if err := b.db.WriteReceipts(block.Hash(), receipts); err != nil {
+		return err
+	}
+
+	return b.db.WriteCanonicalHash(block.Number(), block.Hash())
+}
+
+// VerifyBlockData checks that the body and the receipts of the block match the roots of its header,
+// which is all that's verified of the blocks trusted by their hash
+func VerifyBlockData(block *types.Block, receipts []*types.Receipt) error {
+	if err := VerifyBodyRoots(block); err != nil {
+		return err
+	}
+
+	if len(receipts) != len(block.Transactions) {
+		return ErrInvalidReceiptsSize
+	}
+
+	if buildroot.CalculateReceiptsRoot(receipts) != block.Header.ReceiptsRoot {
+		return ErrInvalidReceiptsRoot
+	}
+
+	return nil
+}
+
+// VerifyBodyRoots checks that the uncles and the transactions of the block match the roots of its header
+func VerifyBodyRoots(block *types.Block) error {
+	if buildroot.CalculateUncleRoot(block.Uncles) != block.Header.Sha3Uncles {
+		return ErrInvalidSha3Uncles
+	}
+
+	if buildroot.CalculateTransactionsRoot(block.Transactions) != block.Header.TxRoot {
+		return ErrInvalidTxRoot
+	}
+
+	return nil
+}
+
+// completeReceipts sets the fields of the receipts which are left out of their consensus encoding,
+// from the transactions of the block whose senders are recovered
+func completeReceipts(block *types.Block, receipts []*types.Receipt) {
+	cumulativeGasUsed := uint64(0)
+
+	for i, receipt := range receipts {
+		tx := block.Transactions[i]
+
+		receipt.TxHash = tx.Hash
+		receipt.GasUsed = receipt.CumulativeGasUsed - cumulativeGasUsed
+		cumulativeGasUsed = receipt.CumulativeGasUsed
+
+		if tx.To == nil {
+			receipt.ContractAddress = crypto.CreateAddress(tx.From, tx.Nonce).Ptr()
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCheckpointChain returns an empty chain along with the blocks of a chain from its genesis
+func newTestCheckpointChain(t *testing.T, n int) (*Blockchain, []*types.Block) {
+	t.Helper()
+
+	b := NewTestBlockchain(t, nil)
+
+	return b, HeadersToBlocks(NewTestHeadersWithSeed(b.Header(), n, 0))
+}
+
+func TestBlockchain_WriteCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	b, blocks := newTestCheckpointChain(t, 11)
+	checkpoint := blocks[8]
+
+	require.NoError(t, b.WriteCheckpoint(checkpoint, []*types.Receipt{}, "test"))
+
+	assert.Equal(t, checkpoint.Hash(), b.Header().Hash)
+	assert.Equal(t, checkpoint.Hash(), b.FinalizedHeader().Hash)
+	assert.Equal(t, uint64(7), b.MissingHistory())
+
+	_, ok := b.GetHeaderByNumber(7)
+	assert.False(t, ok)
+
+	// the chain is extended from the checkpoint
+	require.NoError(t, b.WriteHeaders([]*types.Header{blocks[9].Header, blocks[10].Header}))
+	assert.Equal(t, blocks[10].Hash(), b.Header().Hash)
+	assert.Equal(t, uint64(7), b.MissingHistory())
+
+	assert.ErrorIs(t, b.WriteCheckpoint(blocks[10], []*types.Receipt{}, "test"), ErrChainNotEmpty)
+}
+
+func TestBlockchain_WriteCheckpoint_InvalidBody(t *testing.T) {
+	t.Parallel()
+
+	b, blocks := newTestCheckpointChain(t, 5)
+
+	receipts := []*types.Receipt{{CumulativeGasUsed: 1}}
+	assert.ErrorIs(t, b.WriteCheckpoint(blocks[4], receipts, "test"), ErrInvalidReceiptsSize)
+	assert.Equal(t, uint64(0), b.Header().Number)
+}
+
+func TestBlockchain_WriteBackfilledBlocks(t *testing.T) {
+	t.Parallel()
+
+	b, blocks := newTestCheckpointChain(t, 9)
+
+	require.NoError(t, b.WriteCheckpoint(blocks[8], []*types.Receipt{}, "test"))
+
+	emptyReceipts := func(n int) [][]*types.Receipt {
+		return make([][]*types.Receipt, n)
+	}
+
+	// the blocks have to be the parents of the oldest stored block, from the newest one
+	assert.ErrorIs(t, b.WriteBackfilledBlocks(blocks[6:7], emptyReceipts(1)), ErrBackfillNotLinked)
+	assert.ErrorIs(t, b.WriteBackfilledBlocks(blocks[6:8], emptyReceipts(2)), ErrBackfillNotLinked)
+	assert.ErrorIs(t, b.WriteBackfilledBlocks(blocks[7:8], emptyReceipts(2)), ErrInvalidReceiptsSize)
+
+	forged := &types.Block{Header: blocks[7].Header.Copy()}
+	forged.Header.GasLimit++
+	forged.Header.ComputeHash()
+
+	assert.ErrorIs(t, b.WriteBackfilledBlocks([]*types.Block{forged}, emptyReceipts(1)), ErrBackfillNotLinked)
+	assert.Equal(t, uint64(7), b.MissingHistory())
+
+	require.NoError(t, b.WriteBackfilledBlocks([]*types.Block{blocks[7], blocks[6], blocks[5]}, emptyReceipts(3)))
+	assert.Equal(t, uint64(4), b.MissingHistory())
+
+	require.NoError(t, b.WriteBackfilledBlocks(
+		[]*types.Block{blocks[4], blocks[3], blocks[2], blocks[1]},
+		emptyReceipts(4),
+	))
+	assert.Equal(t, uint64(0), b.MissingHistory())
+
+	block, ok := b.GetBlockByNumber(1, true)
+	require.True(t, ok)
+	assert.Equal(t, blocks[1].Hash(), block.Hash())
+
+	// the head doesn't change
+	assert.Equal(t, blocks[8].Hash(), b.Header().Hash)
+}
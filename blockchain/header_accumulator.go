@@ -0,0 +1,530 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// AccumulatorCheckpointSize is the number of blocks appended to the header accumulator at every checkpoint
+const AccumulatorCheckpointSize = 1024
+
+var (
+	ErrCheckpointNotFound   = errors.New("accumulator checkpoint not found")
+	ErrBlockNotAccumulated  = errors.New("block is not accumulated by the checkpoint")
+	ErrInvalidAncestryProof = errors.New("invalid ancestry proof")
+)
+
+// AccumulatorCheckpoint is the root of the header accumulator once all the blocks up to its head are appended
+type AccumulatorCheckpoint struct {
+	Number     uint64     // index of the checkpoint
+	HeadNumber uint64     // number of the last block of the checkpoint
+	HeadHash   types.Hash // hash of the last block of the checkpoint
+	Root       types.Hash // root of the accumulator, bagging its peaks
+}
+
+// AncestryProof proves that the block is an ancestor of the head of the checkpoint.
+// The block hash is hashed with the siblings up to the peak of its tree,
+// and the peaks are bagged into the root of the checkpoint
+type AncestryProof struct {
+	Number     uint64
+	Hash       types.Hash
+	Checkpoint *AccumulatorCheckpoint
+	Siblings   []types.Hash // siblings of the nodes from the block up to its peak
+	Peaks      []types.Hash // peaks of the accumulator at the checkpoint, from the highest tree
+}
+
+// Verify checks the proof against the root of the checkpoint, which the verifier has to trust
+func (p *AncestryProof) Verify() error {
+	leaves := p.Checkpoint.HeadNumber + 1
+	if p.Number >= leaves {
+		return fmt.Errorf("%w: block %d after the checkpoint head %d", ErrInvalidAncestryProof, p.Number, p.Checkpoint.HeadNumber)
+	}
+
+	if p.Number == p.Checkpoint.HeadNumber && p.Hash != p.Checkpoint.HeadHash {
+		return fmt.Errorf("%w: head hash mismatch", ErrInvalidAncestryProof)
+	}
+
+	peak, height := mmrPeakOf(leaves, p.Number)
+
+	if len(p.Peaks) != bits.OnesCount64(leaves) || len(p.Siblings) != height {
+		return fmt.Errorf("%w: %d siblings and %d peaks", ErrInvalidAncestryProof, len(p.Siblings), len(p.Peaks))
+	}
+
+	node := p.Hash
+
+	for k, sibling := range p.Siblings {
+		if (p.Number>>k)&1 == 1 {
+			node = mmrHash(sibling, node)
+		} else {
+			node = mmrHash(node, sibling)
+		}
+	}
+
+	if node != p.Peaks[peak] {
+		return fmt.Errorf("%w: peak mismatch", ErrInvalidAncestryProof)
+	}
+
+	if root := mmrBagPeaks(p.Peaks); root != p.Checkpoint.Root {
+		return fmt.Errorf("%w: root mismatch, expected %s, got %s", ErrInvalidAncestryProof, p.Checkpoint.Root, root)
+	}
+
+	return nil
+}
+
+// headerAccumulator maintains a Merkle Mountain Range over the hashes of the canonical blocks,
+// the leaf of every block is at the index of its number. The blocks are appended a checkpoint at a time,
+// once all the blocks of the checkpoint are written, and the root of the accumulator is stored for every checkpoint.
+// The nodes never change as the range grows, so the ancestry of a block is proved against any checkpoint after it.
+// A checkpoint is invalidated by a reorg which touches any of its blocks
+type headerAccumulator struct {
+	logger         hclog.Logger
+	db             storage.Storage
+	checkpointSize uint64
+
+	// getHeader returns the canonical header with the given number
+	getHeader func(uint64) (*types.Header, bool)
+
+	// headNumber returns the number of the current head
+	headNumber func() uint64
+
+	lock        sync.RWMutex
+	checkpoints uint64 // number of consecutive committed checkpoints, starting from genesis
+
+	updateCh  chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newHeaderAccumulator(
+	logger hclog.Logger,
+	db storage.Storage,
+	checkpointSize uint64,
+	getHeader func(uint64) (*types.Header, bool),
+	headNumber func() uint64,
+) *headerAccumulator {
+	return &headerAccumulator{
+		logger:         logger.Named("header-accumulator"),
+		db:             db,
+		checkpointSize: checkpointSize,
+		getHeader:      getHeader,
+		headNumber:     headNumber,
+		updateCh:       make(chan struct{}, 1),
+		closeCh:        make(chan struct{}),
+	}
+}
+
+// start loads the already committed checkpoints and starts committing the new ones in the background
+func (a *headerAccumulator) start() {
+	checkpoints := uint64(0)
+	for a.isCheckpointValid(checkpoints) {
+		checkpoints++
+	}
+
+	a.lock.Lock()
+	a.checkpoints = checkpoints
+	a.lock.Unlock()
+
+	go a.run()
+
+	a.notify()
+}
+
+// close stops the background routine
+func (a *headerAccumulator) close() {
+	a.closeOnce.Do(func() {
+		close(a.closeCh)
+	})
+}
+
+// notify signals the background routine that new blocks are available
+func (a *headerAccumulator) notify() {
+	select {
+	case a.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// Name returns the name of the header accumulator for the chain indexer
+func (a *headerAccumulator) Name() string {
+	return "accumulator"
+}
+
+// Connect schedules the commit of the checkpoint completed by the block
+func (a *headerAccumulator) Connect(header *types.Header) error {
+	if (header.Number+1)%a.checkpointSize == 0 {
+		a.notify()
+	}
+
+	return nil
+}
+
+// Disconnect invalidates the checkpoint of the block which left the canonical chain
+func (a *headerAccumulator) Disconnect(header *types.Header) error {
+	a.rewind(header.Number)
+
+	return nil
+}
+
+// rewind invalidates the checkpoints starting from the one containing the given block
+func (a *headerAccumulator) rewind(number uint64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if checkpoint := number / a.checkpointSize; checkpoint < a.checkpoints {
+		a.checkpoints = checkpoint
+	}
+}
+
+func (a *headerAccumulator) run() {
+	for {
+		select {
+		case <-a.updateCh:
+			a.commitCheckpoints()
+		case <-a.closeCh:
+			return
+		}
+	}
+}
+
+// commitCheckpoints commits all the completed checkpoints which are not committed yet
+func (a *headerAccumulator) commitCheckpoints() {
+	for {
+		a.lock.RLock()
+		checkpoint := a.checkpoints
+		a.lock.RUnlock()
+
+		if (checkpoint+1)*a.checkpointSize-1 > a.headNumber() {
+			return
+		}
+
+		select {
+		case <-a.closeCh:
+			return
+		default:
+		}
+
+		if err := a.commitCheckpoint(checkpoint); err != nil {
+			a.logger.Error("failed to commit checkpoint", "checkpoint", checkpoint, "err", err)
+
+			return
+		}
+
+		a.lock.Lock()
+		// the checkpoint could have been invalidated by a reorg while being committed
+		if a.checkpoints == checkpoint {
+			a.checkpoints++
+		}
+		a.lock.Unlock()
+
+		a.logger.Debug("checkpoint committed", "checkpoint", checkpoint)
+	}
+}
+
+// commitCheckpoint appends the blocks of the checkpoint to the accumulator and writes its root
+func (a *headerAccumulator) commitCheckpoint(checkpoint uint64) error {
+	var (
+		start  = checkpoint * a.checkpointSize
+		nodes  = make(map[uint64]types.Hash)
+		parent types.Hash
+	)
+
+	if checkpoint > 0 {
+		head, _, ok := a.db.ReadAccumulatorCheckpoint(checkpoint - 1)
+		if !ok {
+			return fmt.Errorf("checkpoint %d not found", checkpoint-1)
+		}
+
+		parent = head
+	}
+
+	// readNode returns the node appended by this checkpoint, or the one stored by the previous ones
+	readNode := func(pos uint64) (types.Hash, error) {
+		if node, ok := nodes[pos]; ok {
+			return node, nil
+		}
+
+		node, ok := a.db.ReadMMRNode(pos)
+		if !ok {
+			return types.Hash{}, fmt.Errorf("mmr node %d not found", pos)
+		}
+
+		return node, nil
+	}
+
+	for offset := uint64(0); offset < a.checkpointSize; offset++ {
+		header, ok := a.getHeader(start + offset)
+		if !ok {
+			return fmt.Errorf("header %d not found", start+offset)
+		}
+
+		// make sure the checkpoint is not built from the headers of different forks
+		if header.Number > 0 && header.ParentHash != parent {
+			return fmt.Errorf("header %d is not a child of the previous header", header.Number)
+		}
+
+		parent = header.Hash
+
+		if err := mmrAppend(header.Number, header.Hash, readNode, func(pos uint64, node types.Hash) {
+			nodes[pos] = node
+		}); err != nil {
+			return err
+		}
+	}
+
+	for pos, node := range nodes {
+		if err := a.db.WriteMMRNode(pos, node); err != nil {
+			return err
+		}
+	}
+
+	root, err := a.root(start+a.checkpointSize, readNode)
+	if err != nil {
+		return err
+	}
+
+	return a.db.WriteAccumulatorCheckpoint(checkpoint, parent, root)
+}
+
+// root bags the peaks of the accumulator with the given number of leaves
+func (a *headerAccumulator) root(leaves uint64, readNode func(uint64) (types.Hash, error)) (types.Hash, error) {
+	peaks, err := a.peaks(leaves, readNode)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	return mmrBagPeaks(peaks), nil
+}
+
+// peaks returns the peaks of the accumulator with the given number of leaves
+func (a *headerAccumulator) peaks(leaves uint64, readNode func(uint64) (types.Hash, error)) ([]types.Hash, error) {
+	positions := mmrPeakPositions(leaves)
+	peaks := make([]types.Hash, len(positions))
+
+	for idx, pos := range positions {
+		peak, err := readNode(pos)
+		if err != nil {
+			return nil, err
+		}
+
+		peaks[idx] = peak
+	}
+
+	return peaks, nil
+}
+
+// isCheckpointValid checks if the checkpoint is committed on top of the current canonical chain
+func (a *headerAccumulator) isCheckpointValid(checkpoint uint64) bool {
+	head, _, ok := a.db.ReadAccumulatorCheckpoint(checkpoint)
+	if !ok {
+		return false
+	}
+
+	canonical, ok := a.db.ReadCanonicalHash((checkpoint+1)*a.checkpointSize - 1)
+
+	return ok && canonical == head
+}
+
+// checkpoint returns the committed checkpoint
+func (a *headerAccumulator) checkpoint(checkpoint uint64) (*AccumulatorCheckpoint, error) {
+	a.lock.RLock()
+	checkpoints := a.checkpoints
+	a.lock.RUnlock()
+
+	if checkpoint >= checkpoints || !a.isCheckpointValid(checkpoint) {
+		return nil, fmt.Errorf("%w: %d", ErrCheckpointNotFound, checkpoint)
+	}
+
+	head, root, ok := a.db.ReadAccumulatorCheckpoint(checkpoint)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrCheckpointNotFound, checkpoint)
+	}
+
+	return &AccumulatorCheckpoint{
+		Number:     checkpoint,
+		HeadNumber: (checkpoint+1)*a.checkpointSize - 1,
+		HeadHash:   head,
+		Root:       root,
+	}, nil
+}
+
+// latestCheckpoint returns the last committed checkpoint
+func (a *headerAccumulator) latestCheckpoint() (*AccumulatorCheckpoint, error) {
+	a.lock.RLock()
+	checkpoints := a.checkpoints
+	a.lock.RUnlock()
+
+	if checkpoints == 0 {
+		return nil, ErrCheckpointNotFound
+	}
+
+	return a.checkpoint(checkpoints - 1)
+}
+
+// proof returns the proof that the block is an ancestor of the head of the checkpoint
+func (a *headerAccumulator) proof(number, checkpoint uint64) (*AncestryProof, error) {
+	cp, err := a.checkpoint(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if number > cp.HeadNumber {
+		return nil, fmt.Errorf("%w: block %d, checkpoint head %d", ErrBlockNotAccumulated, number, cp.HeadNumber)
+	}
+
+	readNode := func(pos uint64) (types.Hash, error) {
+		node, ok := a.db.ReadMMRNode(pos)
+		if !ok {
+			return types.Hash{}, fmt.Errorf("mmr node %d not found", pos)
+		}
+
+		return node, nil
+	}
+
+	leaves := cp.HeadNumber + 1
+
+	peaks, err := a.peaks(leaves, readNode)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &AncestryProof{
+		Number:     number,
+		Checkpoint: cp,
+		Peaks:      peaks,
+	}
+
+	pos := mmrLeafPosition(number)
+	if proof.Hash, err = readNode(pos); err != nil {
+		return nil, err
+	}
+
+	_, height := mmrPeakOf(leaves, number)
+	proof.Siblings = make([]types.Hash, height)
+
+	for k := 0; k < height; k++ {
+		offset := uint64(1)<<(k+1) - 1
+
+		// the node is the right child if the bit of its height is set
+		if (number>>k)&1 == 1 {
+			proof.Siblings[k], err = readNode(pos - offset)
+			pos++
+		} else {
+			proof.Siblings[k], err = readNode(pos + offset)
+			pos += offset + 1
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the nodes could have been overwritten by a reorg while being read
+	if !a.isCheckpointValid(checkpoint) {
+		return nil, fmt.Errorf("%w: %d", ErrCheckpointNotFound, checkpoint)
+	}
+
+	return proof, nil
+}
+
+// mmrHash hashes the children into their parent node
+func mmrHash(left, right types.Hash) types.Hash {
+	return types.BytesToHash(crypto.Keccak256(left.Bytes(), right.Bytes()))
+}
+
+// mmrLeafPosition returns the position of the leaf with the given index
+func mmrLeafPosition(index uint64) uint64 {
+	return 2*index - uint64(bits.OnesCount64(index))
+}
+
+// mmrAppend appends the leaf with the given index, writing it and the parents it completes
+func mmrAppend(
+	index uint64,
+	leaf types.Hash,
+	readNode func(uint64) (types.Hash, error),
+	writeNode func(uint64, types.Hash),
+) error {
+	pos := mmrLeafPosition(index)
+	node := leaf
+
+	writeNode(pos, node)
+
+	// every set trailing bit of the index completes a tree with the peak on its left
+	for k := 0; (index>>k)&1 == 1; k++ {
+		left, err := readNode(pos - (uint64(1)<<(k+1) - 1))
+		if err != nil {
+			return err
+		}
+
+		node = mmrHash(left, node)
+		pos++
+
+		writeNode(pos, node)
+	}
+
+	return nil
+}
+
+// mmrPeakPositions returns the positions of the peaks of the range with the given number of leaves,
+// from the highest tree
+func mmrPeakPositions(leaves uint64) []uint64 {
+	var (
+		positions = make([]uint64, 0, bits.OnesCount64(leaves))
+		offset    uint64
+	)
+
+	for k := 63; k >= 0; k-- {
+		if (leaves>>k)&1 == 0 {
+			continue
+		}
+
+		size := uint64(1)<<(k+1) - 1
+		positions = append(positions, offset+size-1)
+		offset += size
+	}
+
+	return positions
+}
+
+// mmrPeakOf returns the index of the peak whose tree contains the leaf, and the height of the tree
+func mmrPeakOf(leaves, index uint64) (int, int) {
+	var (
+		peak  int
+		start uint64
+	)
+
+	for k := 63; k >= 0; k-- {
+		if (leaves>>k)&1 == 0 {
+			continue
+		}
+
+		if index < start+uint64(1)<<k {
+			return peak, k
+		}
+
+		start += uint64(1) << k
+		peak++
+	}
+
+	return peak, 0
+}
+
+// mmrBagPeaks folds the peaks into the root, from the lowest tree
+func mmrBagPeaks(peaks []types.Hash) types.Hash {
+	if len(peaks) == 0 {
+		return types.Hash{}
+	}
+
+	root := peaks[len(peaks)-1]
+	for idx := len(peaks) - 2; idx >= 0; idx-- {
+		root = mmrHash(peaks[idx], root)
+	}
+
+	return root
+}
@@ -0,0 +1,198 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCheckpointSize = 16
+
+func newTestAccumulator(t *testing.T, db storage.Storage, headers *[]*types.Header) *headerAccumulator {
+	t.Helper()
+
+	return newHeaderAccumulator(
+		hclog.NewNullLogger(),
+		db,
+		testCheckpointSize,
+		func(n uint64) (*types.Header, bool) {
+			if n >= uint64(len(*headers)) {
+				return nil, false
+			}
+
+			return (*headers)[n], true
+		},
+		func() uint64 {
+			return uint64(len(*headers)) - 1
+		},
+	)
+}
+
+// naiveMMRRoot computes the root of the range over the hashes of the headers, tree by tree
+func naiveMMRRoot(headers []*types.Header) types.Hash {
+	var (
+		peaks []types.Hash
+		start int
+	)
+
+	for k := 63; k >= 0; k-- {
+		size := 1 << k
+		if len(headers)&size == 0 {
+			continue
+		}
+
+		level := make([]types.Hash, size)
+		for i := range level {
+			level[i] = headers[start+i].Hash
+		}
+
+		for len(level) > 1 {
+			next := make([]types.Hash, len(level)/2)
+			for i := range next {
+				next[i] = mmrHash(level[2*i], level[2*i+1])
+			}
+
+			level = next
+		}
+
+		peaks = append(peaks, level[0])
+		start += size
+	}
+
+	return mmrBagPeaks(peaks)
+}
+
+func TestHeaderAccumulator_Proofs(t *testing.T) {
+	t.Parallel()
+
+	db, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	// three complete checkpoints and a partial one
+	headers := newTestBloomChain(t, db, 3*testCheckpointSize+5, nil, 3*testCheckpointSize+5)
+
+	acc := newTestAccumulator(t, db, &headers)
+	acc.commitCheckpoints()
+
+	require.Equal(t, uint64(3), acc.checkpoints)
+
+	latest, err := acc.latestCheckpoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), latest.Number)
+
+	for checkpoint := uint64(0); checkpoint < 3; checkpoint++ {
+		cp, err := acc.checkpoint(checkpoint)
+		require.NoError(t, err)
+
+		assert.Equal(t, headers[cp.HeadNumber].Hash, cp.HeadHash)
+		assert.Equal(t, naiveMMRRoot(headers[:cp.HeadNumber+1]), cp.Root)
+
+		// every block up to the head is proved against the checkpoint
+		for number := uint64(0); number <= cp.HeadNumber; number++ {
+			proof, err := acc.proof(number, checkpoint)
+			require.NoError(t, err)
+
+			assert.Equal(t, headers[number].Hash, proof.Hash)
+			assert.NoError(t, proof.Verify(), "block %d, checkpoint %d", number, checkpoint)
+		}
+	}
+
+	_, err = acc.proof(2*testCheckpointSize, 1)
+	assert.ErrorIs(t, err, ErrBlockNotAccumulated)
+
+	_, err = acc.proof(1, 3)
+	assert.ErrorIs(t, err, ErrCheckpointNotFound)
+
+	// the checkpoints are loaded from the storage on start
+	restarted := newTestAccumulator(t, db, &headers)
+	restarted.start()
+	restarted.close()
+
+	assert.Equal(t, uint64(3), restarted.checkpoints)
+}
+
+func TestAncestryProof_Verify(t *testing.T) {
+	t.Parallel()
+
+	db, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	headers := newTestBloomChain(t, db, 3*testCheckpointSize, nil, 3*testCheckpointSize)
+
+	acc := newTestAccumulator(t, db, &headers)
+	acc.commitCheckpoints()
+
+	proof, err := acc.proof(20, 2)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify())
+
+	tampered := *proof
+	tampered.Hash = types.StringToHash("1")
+	assert.ErrorIs(t, tampered.Verify(), ErrInvalidAncestryProof)
+
+	// the proof of another block
+	tampered = *proof
+	tampered.Number = 21
+	assert.ErrorIs(t, tampered.Verify(), ErrInvalidAncestryProof)
+
+	tampered = *proof
+	tampered.Siblings = tampered.Siblings[1:]
+	assert.ErrorIs(t, tampered.Verify(), ErrInvalidAncestryProof)
+
+	cp := *proof.Checkpoint
+	cp.Root = types.StringToHash("1")
+	tampered = *proof
+	tampered.Checkpoint = &cp
+	assert.ErrorIs(t, tampered.Verify(), ErrInvalidAncestryProof)
+}
+
+func TestHeaderAccumulator_Reorg(t *testing.T) {
+	t.Parallel()
+
+	db, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	headers := newTestBloomChain(t, db, 3*testCheckpointSize, nil, 3*testCheckpointSize)
+
+	acc := newTestAccumulator(t, db, &headers)
+	acc.commitCheckpoints()
+
+	before, err := acc.checkpoint(0)
+	require.NoError(t, err)
+
+	// the chain is replaced starting from the second checkpoint
+	forkAt := uint64(testCheckpointSize + 3)
+	forked := newTestBloomChain(t, db, 3*testCheckpointSize, nil, forkAt)
+
+	for n := uint64(len(headers)) - 1; n >= forkAt; n-- {
+		require.NoError(t, acc.Disconnect(headers[n]))
+	}
+
+	headers = forked
+	assert.Equal(t, uint64(1), acc.checkpoints)
+
+	_, err = acc.checkpoint(1)
+	assert.ErrorIs(t, err, ErrCheckpointNotFound)
+
+	acc.commitCheckpoints()
+	require.Equal(t, uint64(3), acc.checkpoints)
+
+	// the checkpoint before the fork is unchanged
+	after, err := acc.checkpoint(0)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	cp, err := acc.checkpoint(2)
+	require.NoError(t, err)
+	assert.Equal(t, naiveMMRRoot(headers), cp.Root)
+
+	proof, err := acc.proof(forkAt, 2)
+	require.NoError(t, err)
+	assert.Equal(t, headers[forkAt].Hash, proof.Hash)
+	assert.NoError(t, proof.Verify())
+}
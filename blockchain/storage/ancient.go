@@ -0,0 +1,233 @@
+//nolint:stylecheck
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// freezeInterval is the interval at which the blocks past the threshold are moved to the freezer
+	freezeInterval = time.Minute
+
+	// freezeBatchSize is the number of blocks moved to the freezer at once
+	freezeBatchSize = 10000
+)
+
+var (
+	// ErrAncientMismatch is returned when the freezer doesn't hold the blocks moved out of the kv database
+	ErrAncientMismatch = errors.New("the freezer doesn't match the blockchain database")
+)
+
+// ancientTables are the freezer tables of the kv prefixes of the block data moved to the freezer
+var ancientTables = map[string]string{
+	string(HEADER):   FreezerHeaders,
+	string(BODY):     FreezerBodies,
+	string(RECEIPTS): FreezerReceipts,
+}
+
+// NewAncientStorage creates the storage over the kv database, moving the headers, bodies and receipts of the
+// canonical blocks older than the threshold to the freezer in the background. They are read back transparently
+func NewAncientStorage(logger hclog.Logger, db KV, freezer *Freezer, threshold uint64) (*KeyValueStorage, error) {
+	s := &KeyValueStorage{
+		logger:           logger,
+		db:               db,
+		ancient:          freezer,
+		ancientThreshold: threshold,
+		closeCh:          make(chan struct{}),
+		freezerDoneCh:    make(chan struct{}),
+	}
+
+	moved, err := ReadAncientHead(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if frozen := freezer.Frozen(); frozen < moved {
+		return nil, fmt.Errorf(
+			"%w: %d blocks were moved to the freezer, which holds %d",
+			ErrAncientMismatch,
+			moved,
+			frozen,
+		)
+	}
+
+	go s.runFreezer()
+
+	return s, nil
+}
+
+// ReadAncientHead reads the number of blocks moved out of the kv database to the freezer
+func ReadAncientHead(db KV) (uint64, error) {
+	data, ok, err := db.Get(append(append([]byte{}, HEAD...), ANCIENT...))
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	if len(data) != 8 {
+		return 0, fmt.Errorf("invalid ancient head of %d bytes", len(data))
+	}
+
+	return binary.BigEndian.Uint64(data), nil
+}
+
+func (s *KeyValueStorage) runFreezer() {
+	defer close(s.freezerDoneCh)
+
+	ticker := time.NewTicker(freezeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			moved, err := s.Freeze(freezeBatchSize)
+			if err != nil {
+				s.logger.Error("failed to move the old blocks to the freezer", "err", err)
+
+				break
+			}
+
+			if moved < freezeBatchSize {
+				break
+			}
+
+			select {
+			case <-s.closeCh:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// Freeze moves up to limit canonical blocks older than the threshold to the freezer,
+// and returns the number of blocks moved
+func (s *KeyValueStorage) Freeze(limit uint64) (uint64, error) {
+	if s.ancient == nil {
+		return 0, nil
+	}
+
+	s.freezeLock.Lock()
+	defer s.freezeLock.Unlock()
+
+	head, ok := s.ReadHeadNumber()
+	if !ok || head <= s.ancientThreshold {
+		return 0, nil
+	}
+
+	from, to := s.ancient.Frozen(), head-s.ancientThreshold
+	if to < from {
+		// the head was rewound below the frozen blocks
+		to = from
+	} else if to > from+limit {
+		to = from + limit
+	}
+
+	for number := from; number < to; number++ {
+		if err := s.appendAncient(number); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.ancient.Sync(); err != nil {
+		return 0, err
+	}
+
+	// the blocks of an interrupted run were appended to the freezer, but not removed from the kv database
+	moved, err := ReadAncientHead(s.db)
+	if err != nil {
+		return 0, err
+	}
+
+	if moved == to {
+		return 0, nil
+	}
+
+	for number := moved; number < to; number++ {
+		if err := s.removeAncient(number); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.set(HEAD, ANCIENT, s.encodeUint(to)); err != nil {
+		return 0, err
+	}
+
+	if to > from {
+		s.logger.Debug("moved blocks to the freezer", "from", from, "to", to-1)
+	}
+
+	return to - from, nil
+}
+
+// appendAncient appends the canonical block of the number to the freezer
+func (s *KeyValueStorage) appendAncient(number uint64) error {
+	hash, ok := s.ReadCanonicalHash(number)
+	if !ok {
+		return fmt.Errorf("no canonical block %d", number)
+	}
+
+	items := make([][]byte, 0, len(freezerTables)-1)
+
+	for _, p := range [][]byte{HEADER, BODY, RECEIPTS} {
+		data, _, err := s.db.Get(append(append([]byte{}, p...), hash.Bytes()...))
+		if err != nil {
+			return err
+		}
+
+		items = append(items, data)
+	}
+
+	if items[0] == nil {
+		return fmt.Errorf("no header of the canonical block %d", number)
+	}
+
+	return s.ancient.Append(number, hash, items[0], items[1], items[2])
+}
+
+// removeAncient removes the data of the block appended to the freezer from the kv database,
+// and maps its hash to its number so it is read from the freezer
+func (s *KeyValueStorage) removeAncient(number uint64) error {
+	data, err := s.ancient.Read(FreezerHashes, number)
+	if err != nil {
+		return err
+	}
+
+	hash := types.BytesToHash(data)
+
+	if err := s.set(ANCIENT_NUMBER, hash.Bytes(), s.encodeUint(number)); err != nil {
+		return err
+	}
+
+	for _, p := range [][]byte{HEADER, BODY, RECEIPTS} {
+		if err := s.db.Delete(append(append([]byte{}, p...), hash.Bytes()...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readAncient reads the data of the block moved to the freezer
+func (s *KeyValueStorage) readAncient(table string, hash types.Hash) ([]byte, bool, error) {
+	data, ok, err := s.db.Get(append(append([]byte{}, ANCIENT_NUMBER...), hash.Bytes()...))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	if data, err = s.ancient.Read(table, s.decodeUint(data)); err != nil || data == nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
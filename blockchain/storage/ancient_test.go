@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestFreezer(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	freezer, err := OpenFreezer(path)
+	require.NoError(t, err)
+
+	for i := uint64(0); i < 3; i++ {
+		// the genesis has no body nor receipts
+		var body, receipts []byte
+		if i > 0 {
+			body, receipts = []byte{byte(i), 1}, []byte{byte(i), 2}
+		}
+
+		require.NoError(t, freezer.Append(i, types.Hash{byte(i)}, []byte{byte(i)}, body, receipts))
+	}
+
+	assert.ErrorIs(t, freezer.Append(4, types.Hash{4}, []byte{4}, nil, nil), ErrFreezerGap)
+	assert.Equal(t, uint64(3), freezer.Frozen())
+
+	header, err := freezer.Read(FreezerHeaders, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, header)
+
+	receipts, err := freezer.Read(FreezerReceipts, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2, 2}, receipts)
+
+	body, err := freezer.Read(FreezerBodies, 0)
+	require.NoError(t, err)
+	assert.Nil(t, body)
+
+	_, err = freezer.Read(FreezerHeaders, 3)
+	assert.ErrorIs(t, err, ErrFreezerOutOfBounds)
+
+	require.NoError(t, freezer.Close())
+
+	stats, err := ReadFreezerStats(path)
+	require.NoError(t, err)
+	require.Len(t, stats, 4)
+
+	for _, table := range stats {
+		assert.Equal(t, uint64(3), table.Items)
+		assert.NotZero(t, table.Size)
+	}
+
+	// an append interrupted after the first tables drops the block from all of them
+	index := filepath.Join(path, FreezerReceipts+".idx")
+	require.NoError(t, os.Truncate(index, 2*freezerIndexEntrySize+3))
+
+	freezer, err = OpenFreezer(path)
+	require.NoError(t, err)
+
+	defer freezer.Close()
+
+	assert.Equal(t, uint64(2), freezer.Frozen())
+
+	_, err = freezer.Read(FreezerHashes, 2)
+	assert.ErrorIs(t, err, ErrFreezerOutOfBounds)
+
+	require.NoError(t, freezer.Append(2, types.Hash{2}, []byte{5}, nil, nil))
+
+	header, err = freezer.Read(FreezerHeaders, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{5}, header)
+}
+
+func TestAncientStorage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = kvdb.NewMemoryDatabase()
+		path    = t.TempDir()
+		headers = make([]*types.Header, 5)
+	)
+
+	freezer, err := OpenFreezer(path)
+	require.NoError(t, err)
+
+	s, err := NewAncientStorage(hclog.NewNullLogger(), &databaseKV{db}, freezer, 2)
+	require.NoError(t, err)
+
+	for i := range headers {
+		headers[i] = &types.Header{Number: uint64(i), ExtraData: []byte{}}
+		headers[i].ComputeHash()
+
+		require.NoError(t, s.WriteCanonicalHeader(headers[i], big.NewInt(int64(i))))
+
+		if i == 0 {
+			continue
+		}
+
+		require.NoError(t, s.WriteBody(headers[i].Hash, &types.Body{
+			Transactions: []*types.Transaction{{Nonce: uint64(i), GasPrice: big.NewInt(1), V: big.NewInt(1)}},
+		}))
+		require.NoError(t, s.WriteReceipts(headers[i].Hash, []*types.Receipt{{CumulativeGasUsed: uint64(i)}}))
+	}
+
+	// the blocks older than the threshold from the head are moved
+	moved, err := s.Freeze(freezeBatchSize)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), moved)
+
+	moved, err = s.Freeze(freezeBatchSize)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), moved)
+
+	assertBlocks := func(s Storage) {
+		t.Helper()
+
+		for i, h := range headers {
+			header, err := s.ReadHeader(h.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, h.Number, header.Number)
+
+			if i == 0 {
+				_, err = s.ReadBody(h.Hash)
+				assert.ErrorIs(t, err, ErrNotFound)
+
+				continue
+			}
+
+			body, err := s.ReadBody(h.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(i), body.Transactions[0].Nonce)
+
+			count, err := s.ReadBodyTxCount(h.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+
+			receipts, err := s.ReadReceipts(h.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(i), receipts[0].CumulativeGasUsed)
+		}
+	}
+
+	assertBlocks(s)
+
+	// the moved blocks are out of the kv database
+	_, ok, err := db.Get(append(append([]byte{}, HEADER...), headers[1].Hash.Bytes()...))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = db.Get(append(append([]byte{}, HEADER...), headers[2].Hash.Bytes()...))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// the memory database is closed without dropping the keys
+	require.NoError(t, s.Close())
+
+	// the database can't be opened along with another freezer
+	otherFreezer, err := OpenFreezer(t.TempDir())
+	require.NoError(t, err)
+
+	defer otherFreezer.Close()
+
+	_, err = NewAncientStorage(hclog.NewNullLogger(), &databaseKV{db}, otherFreezer, 2)
+	assert.ErrorIs(t, err, ErrAncientMismatch)
+
+	freezer, err = OpenFreezer(path)
+	require.NoError(t, err)
+
+	s, err = NewAncientStorage(hclog.NewNullLogger(), &databaseKV{db}, freezer, 2)
+	require.NoError(t, err)
+
+	defer s.Close()
+
+	assertBlocks(s)
+
+	moved, err = ReadAncientHead(&databaseKV{db})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), moved)
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/hashicorp/go-hclog"
+)
+
+// NewDatabaseStorage creates the storage over the key-value database, whichever its engine
+func NewDatabaseStorage(logger hclog.Logger, db kvdb.Database) Storage {
+	return NewKeyValueStorage(logger, &databaseKV{db})
+}
+
+// databaseKV is the kv storage of a key-value database
+type databaseKV struct {
+	kvdb.Database
+}
+
+func (d *databaseKV) Set(p []byte, v []byte) error {
+	return d.Put(p, v)
+}
+
+// Stats returns the statistics of the engine of the database
+func (d *databaseKV) Stats() (string, error) {
+	return kvdb.Stats(d.Database)
+}
+
+// NewAncientDatabaseStorage creates the storage over the key-value database,
+// moving the canonical blocks older than the threshold to the freezer
+func NewAncientDatabaseStorage(
+	logger hclog.Logger,
+	db kvdb.Database,
+	freezer *Freezer,
+	threshold uint64,
+) (Storage, error) {
+	return NewAncientStorage(logger, &databaseKV{db}, freezer, threshold)
+}
+
+// ReadDatabaseAncientHead reads the number of blocks moved out of the key-value database to a freezer
+func ReadDatabaseAncientHead(db kvdb.Database) (uint64, error) {
+	return ReadAncientHead(&databaseKV{db})
+}
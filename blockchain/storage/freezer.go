@@ -0,0 +1,366 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/golang/snappy"
+)
+
+// Tables of the freezer
+const (
+	FreezerHashes   = "hashes"
+	FreezerHeaders  = "headers"
+	FreezerBodies   = "bodies"
+	FreezerReceipts = "receipts"
+)
+
+const (
+	// freezerIndexEntrySize is the size of an index entry, the end offset of an item in the data file
+	freezerIndexEntrySize = 8
+)
+
+var (
+	ErrFreezerOutOfBounds = errors.New("item not in the freezer")
+	ErrFreezerGap         = errors.New("items must be appended to the freezer in sequence")
+)
+
+// freezerTables are the tables of the freezer, in the order of the items of Append
+var freezerTables = []string{FreezerHashes, FreezerHeaders, FreezerBodies, FreezerReceipts}
+
+// Freezer is an append-only store of the data of the old blocks, in flat files indexed by block number.
+// Each table is made of a data file holding the snappy compressed items one after the other,
+// and an index file holding the end offset of each item in the data file
+type Freezer struct {
+	lock   sync.RWMutex
+	tables map[string]*freezerTable
+	frozen uint64
+}
+
+// OpenFreezer opens the freezer in the directory, creating it if it doesn't exist.
+// The items written to some tables only, when the node stopped in the middle of an append, are dropped
+func OpenFreezer(path string) (*Freezer, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+
+	f := &Freezer{
+		tables: make(map[string]*freezerTable, len(freezerTables)),
+	}
+
+	for idx, name := range freezerTables {
+		table, err := openFreezerTable(path, name)
+		if err != nil {
+			_ = f.Close()
+
+			return nil, err
+		}
+
+		f.tables[name] = table
+
+		if idx == 0 || table.items < f.frozen {
+			f.frozen = table.items
+		}
+	}
+
+	for _, table := range f.tables {
+		if err := table.truncate(f.frozen); err != nil {
+			_ = f.Close()
+
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// Frozen returns the number of blocks in the freezer, which is the number of the next block to append
+func (f *Freezer) Frozen() uint64 {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.frozen
+}
+
+// Append appends the hash, header, body and receipts of the next block, as encoded in the key-value storage.
+// A missing body or receipts is appended empty
+func (f *Freezer) Append(number uint64, hash types.Hash, header, body, receipts []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if number != f.frozen {
+		return fmt.Errorf("%w: appending block %d, expected %d", ErrFreezerGap, number, f.frozen)
+	}
+
+	for idx, item := range [][]byte{hash.Bytes(), header, body, receipts} {
+		if err := f.tables[freezerTables[idx]].append(item); err != nil {
+			// drop the block from the tables it was appended to, so they stay aligned
+			for _, table := range f.tables {
+				_ = table.truncate(f.frozen)
+			}
+
+			return err
+		}
+	}
+
+	f.frozen++
+
+	return nil
+}
+
+// Read reads the item of the block in the table, nil if it was appended empty
+func (f *Freezer) Read(table string, number uint64) ([]byte, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	t, ok := f.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("unknown freezer table %s", table)
+	}
+
+	if number >= f.frozen {
+		return nil, ErrFreezerOutOfBounds
+	}
+
+	return t.read(number)
+}
+
+// Sync flushes the appended items to the disk
+func (f *Freezer) Sync() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, table := range f.tables {
+		if err := table.sync(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close flushes and closes the tables
+func (f *Freezer) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var errs []error
+
+	for _, table := range f.tables {
+		if err := table.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close the freezer: %v", errs)
+	}
+
+	return nil
+}
+
+// FreezerTableStats are the statistics of a table of the freezer
+type FreezerTableStats struct {
+	Name  string
+	Items uint64
+	// Size is the size of the data and the index files
+	Size uint64
+}
+
+// ReadFreezerStats reads the statistics of the tables of the freezer in the directory without opening it,
+// so the items of an interrupted append are counted until the freezer is opened again
+func ReadFreezerStats(path string) ([]FreezerTableStats, error) {
+	stats := make([]FreezerTableStats, 0, len(freezerTables))
+
+	for _, name := range freezerTables {
+		index, err := os.Stat(filepath.Join(path, name+".idx"))
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := os.Stat(filepath.Join(path, name+".dat"))
+		if err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, FreezerTableStats{
+			Name:  name,
+			Items: uint64(index.Size()) / freezerIndexEntrySize,
+			Size:  uint64(index.Size() + data.Size()),
+		})
+	}
+
+	return stats, nil
+}
+
+// freezerTable is a table of the freezer
+type freezerTable struct {
+	index *os.File
+	data  *os.File
+
+	// items is the number of items in the table
+	items uint64
+	// size is the size of the data file
+	size uint64
+}
+
+func openFreezerTable(path, name string) (*freezerTable, error) {
+	index, err := os.OpenFile(filepath.Join(path, name+".idx"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.OpenFile(filepath.Join(path, name+".dat"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		_ = index.Close()
+
+		return nil, err
+	}
+
+	t := &freezerTable{index: index, data: data}
+
+	if err := t.repair(); err != nil {
+		_ = t.close()
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// repair drops the partially written index entry of an interrupted append,
+// and the index entries of the items whose data didn't reach the disk
+func (t *freezerTable) repair() error {
+	indexStat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+
+	dataStat, err := t.data.Stat()
+	if err != nil {
+		return err
+	}
+
+	items := uint64(indexStat.Size()) / freezerIndexEntrySize
+
+	for ; items > 0; items-- {
+		end, err := t.end(items)
+		if err != nil {
+			return err
+		}
+
+		if end <= uint64(dataStat.Size()) {
+			break
+		}
+	}
+
+	return t.truncate(items)
+}
+
+// truncate drops the items from the given one, and the data of the items which weren't indexed
+func (t *freezerTable) truncate(items uint64) error {
+	size, err := t.end(items)
+	if err != nil {
+		return err
+	}
+
+	if err := t.index.Truncate(int64(items * freezerIndexEntrySize)); err != nil {
+		return err
+	}
+
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+
+	t.items, t.size = items, size
+
+	return nil
+}
+
+// end returns the end offset in the data file of the item before the given one
+func (t *freezerTable) end(item uint64) (uint64, error) {
+	if item == 0 {
+		return 0, nil
+	}
+
+	entry := make([]byte, freezerIndexEntrySize)
+	if _, err := t.index.ReadAt(entry, int64((item-1)*freezerIndexEntrySize)); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(entry), nil
+}
+
+func (t *freezerTable) append(item []byte) error {
+	var data []byte
+	if len(item) > 0 {
+		data = snappy.Encode(nil, item)
+	}
+
+	if _, err := t.data.WriteAt(data, int64(t.size)); err != nil {
+		return err
+	}
+
+	// the index entry is written after the data, so an indexed item is always complete
+	entry := make([]byte, freezerIndexEntrySize)
+	binary.BigEndian.PutUint64(entry, t.size+uint64(len(data)))
+
+	if _, err := t.index.WriteAt(entry, int64(t.items*freezerIndexEntrySize)); err != nil {
+		return err
+	}
+
+	t.items++
+	t.size += uint64(len(data))
+
+	return nil
+}
+
+func (t *freezerTable) read(item uint64) ([]byte, error) {
+	start, err := t.end(item)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := t.end(item + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if start == end {
+		return nil, nil
+	}
+
+	data := make([]byte, end-start)
+	if _, err := t.data.ReadAt(data, int64(start)); err != nil {
+		return nil, err
+	}
+
+	return snappy.Decode(nil, data)
+}
+
+func (t *freezerTable) sync() error {
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+
+	return t.index.Sync()
+}
+
+func (t *freezerTable) close() error {
+	syncErr := t.sync()
+	dataErr := t.data.Close()
+	indexErr := t.index.Close()
+
+	for _, err := range []error{syncErr, dataErr, indexErr} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+//nolint:stylecheck
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Tables of the kv database, reported by the inspection of the database
+const (
+	TableHeaders     = "headers"
+	TableBodies      = "bodies"
+	TableReceipts    = "receipts"
+	TableCanonical   = "canonical"
+	TableDifficulty  = "difficulty"
+	TableTxLookups   = "tx-lookups"
+	TableSnapshots   = "snapshots"
+	TableBloomBits   = "bloom-bits"
+	TableAccumulator = "accumulator"
+	TableAncient     = "ancient-numbers"
+	TableIndexers    = "indexer-checkpoints"
+	TableMetadata    = "metadata"
+)
+
+var (
+	errInvalidKeyLength   = errors.New("invalid key length")
+	errInvalidValueLength = errors.New("invalid value length")
+)
+
+// keyTables are the tables of the prefixes of the kv database
+var keyTables = map[string]string{
+	string(HEADER):                 TableHeaders,
+	string(BODY):                   TableBodies,
+	string(RECEIPTS):               TableReceipts,
+	string(CANONICAL):              TableCanonical,
+	string(DIFFICULTY):             TableDifficulty,
+	string(TX_LOOKUP_PREFIX):       TableTxLookups,
+	string(SNAPSHOTS):              TableSnapshots,
+	string(BLOOM_BITS):             TableBloomBits,
+	string(BLOOM_SECTION):          TableBloomBits,
+	string(MMR_NODE):               TableAccumulator,
+	string(ACCUMULATOR_CHECKPOINT): TableAccumulator,
+	string(ANCIENT_NUMBER):         TableAncient,
+	string(INDEXER_CHECKPOINT):     TableIndexers,
+}
+
+// KeyTable returns the table of the key of the kv database, the heads and the forks are metadata
+func KeyTable(key []byte) string {
+	if len(key) == 0 {
+		return TableMetadata
+	}
+
+	if table, ok := keyTables[string(key[:1])]; ok {
+		return table
+	}
+
+	return TableMetadata
+}
+
+// VerifyEntry checks the entry of the kv database has the key and the value of its table
+func VerifyEntry(key, value []byte) error {
+	var (
+		keyLength   int
+		valueLength int
+		obj         types.RLPUnmarshaler
+	)
+
+	switch KeyTable(key) {
+	case TableHeaders:
+		keyLength, obj = 1+types.HashLength, &types.Header{}
+	case TableBodies:
+		keyLength, obj = 1+types.HashLength, &types.Body{}
+	case TableReceipts:
+		keyLength, obj = 1+types.HashLength, &types.Receipts{}
+	case TableCanonical:
+		keyLength, valueLength = 1+8, types.HashLength
+	case TableDifficulty, TableTxLookups:
+		keyLength = 1 + types.HashLength
+	case TableAncient:
+		keyLength, valueLength = 1+types.HashLength, 8
+	case TableIndexers:
+		valueLength = 8 + types.HashLength
+	}
+
+	if keyLength != 0 && len(key) != keyLength {
+		return fmt.Errorf("%w: %d bytes, expected %d", errInvalidKeyLength, len(key), keyLength)
+	}
+
+	if valueLength != 0 && len(value) != valueLength {
+		return fmt.Errorf("%w: %d bytes, expected %d", errInvalidValueLength, len(value), valueLength)
+	}
+
+	if obj != nil {
+		return decodeRLP(value, obj)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestVerifyEntry(t *testing.T) {
+	t.Parallel()
+
+	header := &types.Header{Number: 1}
+	headerKey := append(append([]byte{}, HEADER...), types.Hash{1}.Bytes()...)
+
+	assert.Equal(t, TableHeaders, KeyTable(headerKey))
+	assert.NoError(t, VerifyEntry(headerKey, header.MarshalRLP()))
+
+	// the header isn't decoded from a body
+	assert.Error(t, VerifyEntry(headerKey, []byte{0x01}))
+
+	canonicalKey := append(append([]byte{}, CANONICAL...), 0, 0, 0, 0, 0, 0, 0, 1)
+
+	assert.Equal(t, TableCanonical, KeyTable(canonicalKey))
+	assert.NoError(t, VerifyEntry(canonicalKey, types.Hash{1}.Bytes()))
+	assert.ErrorIs(t, VerifyEntry(canonicalKey, []byte{1}), errInvalidValueLength)
+	assert.ErrorIs(t, VerifyEntry(canonicalKey[:5], types.Hash{1}.Bytes()), errInvalidKeyLength)
+
+	headKey := append(append([]byte{}, HEAD...), HASH...)
+
+	assert.Equal(t, TableMetadata, KeyTable(headKey))
+	assert.NoError(t, VerifyEntry(headKey, []byte{1}))
+}
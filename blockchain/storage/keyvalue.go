@@ -3,8 +3,10 @@ package storage
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -39,13 +41,35 @@ var (
 
 	// TX_LOOKUP_PREFIX is the prefix for transaction lookups
 	TX_LOOKUP_PREFIX = []byte("l")
+
+	// BLOOM_BITS is the prefix for the bloom bits index vectors
+	BLOOM_BITS = []byte("B")
+
+	// BLOOM_SECTION is the prefix for the heads of the bloom bits index sections
+	BLOOM_SECTION = []byte("i")
+
+	// MMR_NODE is the prefix for the nodes of the header accumulator, by their position
+	MMR_NODE = []byte("m")
+
+	// ACCUMULATOR_CHECKPOINT is the prefix for the heads and roots of the header accumulator checkpoints
+	ACCUMULATOR_CHECKPOINT = []byte("a")
+
+	// ANCIENT_NUMBER is the prefix for the numbers of the blocks moved to the freezer, by hash
+	ANCIENT_NUMBER = []byte("n")
+
+	// INDEXER_CHECKPOINT is the prefix for the last blocks indexed by the chain indexers, by name
+	INDEXER_CHECKPOINT = []byte("x")
 )
 
 // Sub-prefixes
 var (
-	HASH   = []byte("hash")
-	NUMBER = []byte("number")
-	EMPTY  = []byte("empty")
+	HASH    = []byte("hash")
+	NUMBER  = []byte("number")
+	EMPTY   = []byte("empty")
+	ANCIENT = []byte("ancient")
+
+	FINALIZED = []byte("finalized")
+	TX_TAIL   = []byte("txtail")
 )
 
 // KV is a key value storage interface.
@@ -55,13 +79,29 @@ type KV interface {
 	Close() error
 	Set(p []byte, v []byte) error
 	Get(p []byte) ([]byte, bool, error)
+	Delete(p []byte) error
 }
 
+// StatsKV is implemented by the kv databases which report their statistics
+type StatsKV interface {
+	Stats() (string, error)
+}
+
+// ErrStatsNotSupported is returned by the storages whose kv database doesn't report statistics
+var ErrStatsNotSupported = errors.New("database statistics not supported")
+
 // KeyValueStorage is a generic storage for kv databases
 type KeyValueStorage struct {
 	logger hclog.Logger
 	db     KV
 	Db     KV
+
+	// ancient is the freezer the old canonical blocks are moved to, nil if they stay in the kv database
+	ancient          *Freezer
+	ancientThreshold uint64
+	freezeLock       sync.Mutex
+	closeCh          chan struct{}
+	freezerDoneCh    chan struct{}
 }
 
 func NewKeyValueStorage(logger hclog.Logger, db KV) Storage {
@@ -132,6 +172,21 @@ func (s *KeyValueStorage) WriteHeadNumber(n uint64) error {
 	return s.set(HEAD, NUMBER, s.encodeUint(n))
 }
 
+// WriteFinalizedHash writes the hash of the last block finalized by the consensus
+func (s *KeyValueStorage) WriteFinalizedHash(h types.Hash) error {
+	return s.set(HEAD, FINALIZED, h.Bytes())
+}
+
+// ReadFinalizedHash reads the hash of the last block finalized by the consensus
+func (s *KeyValueStorage) ReadFinalizedHash() (types.Hash, bool) {
+	data, ok := s.get(HEAD, FINALIZED)
+	if !ok {
+		return types.Hash{}, false
+	}
+
+	return types.BytesToHash(data), true
+}
+
 // FORK //
 
 // WriteForks writes the current forks
@@ -221,6 +276,33 @@ func (s *KeyValueStorage) ReadBody(hash types.Hash) (*types.Body, error) {
 	return body, err
 }
 
+// ReadBodyTxCount reads the number of transactions in the body,
+// without decoding the transactions themselves
+func (s *KeyValueStorage) ReadBodyTxCount(hash types.Hash) (int, error) {
+	parser := &fastrlp.Parser{}
+
+	v := s.read2(BODY, hash.Bytes(), parser)
+	if v == nil {
+		return 0, ErrNotFound
+	}
+
+	tuple, err := v.GetElems()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(tuple) < 2 {
+		return 0, fmt.Errorf("incorrect number of elements to decode body, expected 2 but found %d", len(tuple))
+	}
+
+	txns, err := tuple[0].GetElems()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(txns), nil
+}
+
 // RECEIPTS //
 
 // WriteReceipts writes the receipts
@@ -238,33 +320,223 @@ func (s *KeyValueStorage) ReadReceipts(hash types.Hash) ([]*types.Receipt, error
 	return *receipts, err
 }
 
+// ReadReceipt reads the receipt of the transaction at the index in the block,
+// without decoding the other receipts of the block
+func (s *KeyValueStorage) ReadReceipt(hash types.Hash, index uint64) (*types.Receipt, error) {
+	data, ok, err := s.read(RECEIPTS, hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return types.UnmarshalStoreReceipt(data, index)
+}
+
 // TX LOOKUP //
 
-// WriteTxLookup maps the transaction hash to the block hash
-func (s *KeyValueStorage) WriteTxLookup(hash types.Hash, blockHash types.Hash) error {
-	ar := &fastrlp.Arena{}
-	vr := ar.NewBytes(blockHash.Bytes())
+// WriteTxLookup maps the transaction hash to the block hash and to the index of the transaction in the block
+func (s *KeyValueStorage) WriteTxLookup(hash types.Hash, blockHash types.Hash, index uint64) error {
+	return s.set(TX_LOOKUP_PREFIX, hash.Bytes(), encodeTxLookup(blockHash, index))
+}
+
+// DeleteTxLookup deletes the lookup of the transaction
+func (s *KeyValueStorage) DeleteTxLookup(hash types.Hash) error {
+	return s.db.Delete(append(append([]byte{}, TX_LOOKUP_PREFIX...), hash.Bytes()...))
+}
+
+// WriteTxLookupTail writes the number of the oldest block whose transactions are looked up
+func (s *KeyValueStorage) WriteTxLookupTail(number uint64) error {
+	return s.set(HEAD, TX_TAIL, s.encodeUint(number))
+}
+
+// ReadTxLookupTail reads the number of the oldest block whose transactions are looked up
+func (s *KeyValueStorage) ReadTxLookupTail() (uint64, bool) {
+	data, ok := s.get(HEAD, TX_TAIL)
+	if !ok || len(data) != 8 {
+		return 0, false
+	}
 
-	return s.write2(TX_LOOKUP_PREFIX, hash.Bytes(), vr)
+	return s.decodeUint(data), true
 }
 
 // ReadTxLookup reads the block hash using the transaction hash
 func (s *KeyValueStorage) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	blockHash, _, _, ok := s.readTxLookup(hash)
+
+	return blockHash, ok
+}
+
+// ReadTxLookupIndex reads the block hash and the index of the transaction in the block using the transaction hash.
+// The lookups written before the index was recorded are resolved with the body of the block
+func (s *KeyValueStorage) ReadTxLookupIndex(hash types.Hash) (types.Hash, uint64, bool) {
+	blockHash, index, indexed, ok := s.readTxLookup(hash)
+	if !ok || indexed || blockHash == types.ZeroHash {
+		return blockHash, index, ok
+	}
+
+	body, err := s.ReadBody(blockHash)
+	if err != nil {
+		return types.Hash{}, 0, false
+	}
+
+	for i, txn := range body.Transactions {
+		if txn.Hash == hash {
+			return blockHash, uint64(i), true
+		}
+	}
+
+	return types.Hash{}, 0, false
+}
+
+// readTxLookup reads the lookup of the transaction, indexed is false if it was written without the index
+func (s *KeyValueStorage) readTxLookup(hash types.Hash) (blockHash types.Hash, index uint64, indexed, ok bool) {
+	data, ok := s.get(TX_LOOKUP_PREFIX, hash.Bytes())
+	if !ok {
+		return types.Hash{}, 0, false, false
+	}
+
+	blockHash, index, indexed, err := decodeTxLookup(data)
+	if err != nil {
+		s.logger.Error("failed to decode the transaction lookup", "hash", hash, "err", err)
+
+		return types.Hash{}, 0, false, false
+	}
+
+	return blockHash, index, indexed, true
+}
+
+// encodeTxLookup encodes the block hash and the index of the transaction in the block
+func encodeTxLookup(blockHash types.Hash, index uint64) []byte {
+	ar := &fastrlp.Arena{}
+
+	vv := ar.NewArray()
+	vv.Set(ar.NewBytes(blockHash.Bytes()))
+	vv.Set(ar.NewUint(index))
+
+	return vv.MarshalTo(nil)
+}
+
+// decodeTxLookup decodes the lookup of a transaction, which only held the block hash before the index was recorded
+func decodeTxLookup(data []byte) (blockHash types.Hash, index uint64, indexed bool, err error) {
 	parser := &fastrlp.Parser{}
 
-	v := s.read2(TX_LOOKUP_PREFIX, hash.Bytes(), parser)
-	if v == nil {
-		return types.Hash{}, false
+	v, err := parser.Parse(data)
+	if err != nil {
+		return types.Hash{}, 0, false, err
 	}
 
-	blockHash := []byte{}
-	blockHash, err := v.GetBytes(blockHash[:0], 32)
+	if v.Type() == fastrlp.TypeBytes {
+		err = v.GetHash(blockHash[:])
 
+		return blockHash, 0, false, err
+	}
+
+	elems, err := v.GetElems()
 	if err != nil {
-		panic(err)
+		return types.Hash{}, 0, false, err
+	}
+
+	if len(elems) != 2 {
+		return types.Hash{}, 0, false, fmt.Errorf("expected 2 elements in the lookup but found %d", len(elems))
+	}
+
+	if err = elems[0].GetHash(blockHash[:]); err != nil {
+		return types.Hash{}, 0, false, err
+	}
+
+	if index, err = elems[1].GetUint64(); err != nil {
+		return types.Hash{}, 0, false, err
+	}
+
+	return blockHash, index, true, nil
+}
+
+// BLOOM BITS //
+
+// WriteBloomBits writes the bloom bits vector of the given bit for the section
+func (s *KeyValueStorage) WriteBloomBits(bit uint, section uint64, bits []byte) error {
+	return s.set(BLOOM_BITS, s.bloomBitsKey(bit, section), bits)
+}
+
+// ReadBloomBits reads the bloom bits vector of the given bit for the section
+func (s *KeyValueStorage) ReadBloomBits(bit uint, section uint64) ([]byte, bool) {
+	return s.get(BLOOM_BITS, s.bloomBitsKey(bit, section))
+}
+
+func (s *KeyValueStorage) bloomBitsKey(bit uint, section uint64) []byte {
+	key := make([]byte, 10)
+	binary.BigEndian.PutUint16(key[:2], uint16(bit))
+	binary.BigEndian.PutUint64(key[2:], section)
+
+	return key
+}
+
+// WriteBloomSectionHead writes the hash of the last block of the indexed section
+func (s *KeyValueStorage) WriteBloomSectionHead(section uint64, hash types.Hash) error {
+	return s.set(BLOOM_SECTION, s.encodeUint(section), hash.Bytes())
+}
+
+// ReadBloomSectionHead reads the hash of the last block of the indexed section
+func (s *KeyValueStorage) ReadBloomSectionHead(section uint64) (types.Hash, bool) {
+	data, ok := s.get(BLOOM_SECTION, s.encodeUint(section))
+	if !ok {
+		return types.Hash{}, false
+	}
+
+	return types.BytesToHash(data), true
+}
+
+// HEADER ACCUMULATOR //
+
+// WriteMMRNode writes the node of the header accumulator at the given position
+func (s *KeyValueStorage) WriteMMRNode(pos uint64, hash types.Hash) error {
+	return s.set(MMR_NODE, s.encodeUint(pos), hash.Bytes())
+}
+
+// ReadMMRNode reads the node of the header accumulator at the given position
+func (s *KeyValueStorage) ReadMMRNode(pos uint64) (types.Hash, bool) {
+	data, ok := s.get(MMR_NODE, s.encodeUint(pos))
+	if !ok {
+		return types.Hash{}, false
 	}
 
-	return types.BytesToHash(blockHash), true
+	return types.BytesToHash(data), true
+}
+
+// WriteAccumulatorCheckpoint writes the hash of the last block of the checkpoint
+// and the root of the header accumulator including it
+func (s *KeyValueStorage) WriteAccumulatorCheckpoint(checkpoint uint64, head, root types.Hash) error {
+	return s.set(ACCUMULATOR_CHECKPOINT, s.encodeUint(checkpoint), append(head.Bytes(), root.Bytes()...))
+}
+
+// ReadAccumulatorCheckpoint reads the hash of the last block of the checkpoint and the accumulator root
+func (s *KeyValueStorage) ReadAccumulatorCheckpoint(checkpoint uint64) (types.Hash, types.Hash, bool) {
+	data, ok := s.get(ACCUMULATOR_CHECKPOINT, s.encodeUint(checkpoint))
+	if !ok || len(data) != 2*types.HashLength {
+		return types.Hash{}, types.Hash{}, false
+	}
+
+	return types.BytesToHash(data[:types.HashLength]), types.BytesToHash(data[types.HashLength:]), true
+}
+
+// CHAIN INDEXERS //
+
+// WriteIndexerCheckpoint writes the number and the hash of the last block indexed by the indexer
+func (s *KeyValueStorage) WriteIndexerCheckpoint(name string, number uint64, hash types.Hash) error {
+	return s.set(INDEXER_CHECKPOINT, []byte(name), append(s.encodeUint(number), hash.Bytes()...))
+}
+
+// ReadIndexerCheckpoint reads the number and the hash of the last block indexed by the indexer
+func (s *KeyValueStorage) ReadIndexerCheckpoint(name string) (uint64, types.Hash, bool) {
+	data, ok := s.get(INDEXER_CHECKPOINT, []byte(name))
+	if !ok || len(data) != 8+types.HashLength {
+		return 0, types.Hash{}, false
+	}
+
+	return s.decodeUint(data[:8]), types.BytesToHash(data[8:]), true
 }
 
 // WRITE OPERATIONS //
@@ -283,9 +555,7 @@ func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
 var ErrNotFound = fmt.Errorf("not found")
 
 func (s *KeyValueStorage) readRLP(p, k []byte, raw types.RLPUnmarshaler) error {
-	p = append(p, k...)
-	data, ok, err := s.db.Get(p)
-
+	data, ok, err := s.read(p, k)
 	if err != nil {
 		return err
 	}
@@ -294,19 +564,17 @@ func (s *KeyValueStorage) readRLP(p, k []byte, raw types.RLPUnmarshaler) error {
 		return ErrNotFound
 	}
 
+	return decodeRLP(data, raw)
+}
+
+func decodeRLP(data []byte, raw types.RLPUnmarshaler) error {
 	if obj, ok := raw.(types.RLPStoreUnmarshaler); ok {
 		// decode in the store format
-		if err := obj.UnmarshalStoreRLP(data); err != nil {
-			return err
-		}
-	} else {
-		// normal rlp decoding
-		if err := raw.UnmarshalRLP(data); err != nil {
-			return err
-		}
+		return obj.UnmarshalStoreRLP(data)
 	}
 
-	return nil
+	// normal rlp decoding
+	return raw.UnmarshalRLP(data)
 }
 
 func (s *KeyValueStorage) read2(p, k []byte, parser *fastrlp.Parser) *fastrlp.Value {
@@ -336,9 +604,7 @@ func (s *KeyValueStorage) set(p []byte, k []byte, v []byte) error {
 }
 
 func (s *KeyValueStorage) get(p []byte, k []byte) ([]byte, bool) {
-	p = append(p, k...)
-	data, ok, err := s.db.Get(p)
-
+	data, ok, err := s.read(p, k)
 	if err != nil {
 		return nil, false
 	}
@@ -346,7 +612,41 @@ func (s *KeyValueStorage) get(p []byte, k []byte) ([]byte, bool) {
 	return data, ok
 }
 
+// read reads the value of the key, from the freezer if it is the data of a block moved to it
+func (s *KeyValueStorage) read(p []byte, k []byte) ([]byte, bool, error) {
+	data, ok, err := s.db.Get(append(append([]byte{}, p...), k...))
+	if err != nil || ok || s.ancient == nil {
+		return data, ok, err
+	}
+
+	table, ok := ancientTables[string(p)]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return s.readAncient(table, types.BytesToHash(k))
+}
+
+// Stats returns the statistics reported by the kv database
+func (s *KeyValueStorage) Stats() (string, error) {
+	db, ok := s.db.(StatsKV)
+	if !ok {
+		return "", ErrStatsNotSupported
+	}
+
+	return db.Stats()
+}
+
 // Close closes the connection with the db
 func (s *KeyValueStorage) Close() error {
+	if s.ancient != nil {
+		close(s.closeCh)
+		<-s.freezerDoneCh
+
+		if err := s.ancient.Close(); err != nil {
+			s.logger.Error("failed to close the freezer", "err", err)
+		}
+	}
+
 	return s.db.Close()
 }
@@ -4,8 +4,8 @@ import (
 	"fmt"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/kvdb"
 	"github.com/hashicorp/go-hclog"
-	"github.com/syndtr/goleveldb/leveldb"
 )
 
 // Factory creates a leveldb storage
@@ -25,41 +25,10 @@ func Factory(config map[string]interface{}, logger hclog.Logger) (storage.Storag
 
 // NewLevelDBStorage creates the new storage reference with leveldb
 func NewLevelDBStorage(path string, logger hclog.Logger) (storage.Storage, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	db, err := kvdb.Open(kvdb.LevelDB, path, kvdb.Options{})
 	if err != nil {
 		return nil, err
 	}
 
-	kv := &levelDBKV{db}
-
-	return storage.NewKeyValueStorage(logger.Named("leveldb"), kv), nil
-}
-
-// levelDBKV is the leveldb implementation of the kv storage
-type levelDBKV struct {
-	db *leveldb.DB
-}
-
-// Set sets the key-value pair in leveldb storage
-func (l *levelDBKV) Set(p []byte, v []byte) error {
-	return l.db.Put(p, v, nil)
-}
-
-// Get retrieves the key-value pair in leveldb storage
-func (l *levelDBKV) Get(p []byte) ([]byte, bool, error) {
-	data, err := l.db.Get(p, nil)
-	if err != nil {
-		if err.Error() == "leveldb: not found" {
-			return nil, false, nil
-		}
-
-		return nil, false, err
-	}
-
-	return data, true, nil
-}
-
-// Close closes the leveldb storage instance
-func (l *levelDBKV) Close() error {
-	return l.db.Close()
+	return storage.NewDatabaseStorage(logger.Named("leveldb"), db), nil
 }
@@ -17,6 +17,9 @@ type Storage interface {
 	WriteHeadHash(h types.Hash) error
 	WriteHeadNumber(uint64) error
 
+	WriteFinalizedHash(h types.Hash) error
+	ReadFinalizedHash() (types.Hash, bool)
+
 	WriteForks(forks []types.Hash) error
 	ReadForks() ([]types.Hash, error)
 
@@ -30,12 +33,36 @@ type Storage interface {
 
 	WriteBody(hash types.Hash, body *types.Body) error
 	ReadBody(hash types.Hash) (*types.Body, error)
+	ReadBodyTxCount(hash types.Hash) (int, error)
 
 	WriteReceipts(hash types.Hash, receipts []*types.Receipt) error
 	ReadReceipts(hash types.Hash) ([]*types.Receipt, error)
+	ReadReceipt(hash types.Hash, index uint64) (*types.Receipt, error)
 
-	WriteTxLookup(hash types.Hash, blockHash types.Hash) error
+	WriteTxLookup(hash types.Hash, blockHash types.Hash, index uint64) error
 	ReadTxLookup(hash types.Hash) (types.Hash, bool)
+	ReadTxLookupIndex(hash types.Hash) (types.Hash, uint64, bool)
+	DeleteTxLookup(hash types.Hash) error
+
+	WriteTxLookupTail(number uint64) error
+	ReadTxLookupTail() (uint64, bool)
+
+	WriteBloomBits(bit uint, section uint64, bits []byte) error
+	ReadBloomBits(bit uint, section uint64) ([]byte, bool)
+
+	WriteBloomSectionHead(section uint64, hash types.Hash) error
+	ReadBloomSectionHead(section uint64) (types.Hash, bool)
+
+	WriteMMRNode(pos uint64, hash types.Hash) error
+	ReadMMRNode(pos uint64) (types.Hash, bool)
+
+	WriteAccumulatorCheckpoint(checkpoint uint64, head, root types.Hash) error
+	ReadAccumulatorCheckpoint(checkpoint uint64) (types.Hash, types.Hash, bool)
+
+	WriteIndexerCheckpoint(name string, number uint64, hash types.Hash) error
+	ReadIndexerCheckpoint(name string) (uint64, types.Hash, bool)
+
+	Stats() (string, error)
 
 	Close() error
 }
@@ -48,6 +48,15 @@ func TestStorage(t *testing.T, m PlaceholderStorage) {
 	t.Run("", func(t *testing.T) {
 		testReceipts(t, m)
 	})
+	t.Run("", func(t *testing.T) {
+		testBloomBits(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testAccumulator(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testIndexerCheckpoint(t, m)
+	})
 }
 
 func testCanonicalChain(t *testing.T, m PlaceholderStorage) {
@@ -193,6 +202,19 @@ func testHead(t *testing.T, m PlaceholderStorage) {
 			t.Fatal("bad")
 		}
 	}
+
+	if _, ok := s.ReadFinalizedHash(); ok {
+		t.Fatal("finalized hash should not be found")
+	}
+
+	if err := s.WriteFinalizedHash(hash1); err != nil {
+		t.Fatal(err)
+	}
+
+	finalized, ok := s.ReadFinalizedHash()
+	if !ok || finalized != hash1 {
+		t.Fatal("finalized hash mismatch")
+	}
 }
 
 func testForks(t *testing.T, m PlaceholderStorage) {
@@ -315,6 +337,13 @@ func testBody(t *testing.T, m PlaceholderStorage) {
 			t.Fatal("tx not correct")
 		}
 	}
+
+	count, err := s.ReadBodyTxCount(header.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, len(tx0), count)
+
+	_, err = s.ReadBodyTxCount(types.StringToHash("1"))
+	assert.Error(t, err)
 }
 
 func testReceipts(t *testing.T, m PlaceholderStorage) {
@@ -349,8 +378,8 @@ func testReceipts(t *testing.T, m PlaceholderStorage) {
 	r0 := &types.Receipt{
 		Root:              types.StringToHash("1"),
 		CumulativeGasUsed: 10,
+		GasUsed:           10,
 		TxHash:            txn.Hash,
-		LogsBloom:         types.Bloom{0x1},
 		Logs: []*types.Log{
 			{
 				Address: addr1,
@@ -364,11 +393,10 @@ func testReceipts(t *testing.T, m PlaceholderStorage) {
 		},
 	}
 	r1 := &types.Receipt{
-		Root:              types.StringToHash("1"),
-		CumulativeGasUsed: 10,
+		CumulativeGasUsed: 25,
+		TransactionType:   types.AccessListTx,
 		TxHash:            txn.Hash,
-		LogsBloom:         types.Bloom{0x1},
-		GasUsed:           10,
+		GasUsed:           15,
 		ContractAddress:   &types.Address{0x1},
 		Logs: []*types.Log{
 			{
@@ -376,9 +404,14 @@ func testReceipts(t *testing.T, m PlaceholderStorage) {
 				Topics:  []types.Hash{hash1},
 			},
 		},
+		LogIndex: 2,
 	}
+	r1.SetStatus(types.ReceiptSuccess)
 
 	receipts := []*types.Receipt{r0, r1}
+	for _, r := range receipts {
+		r.LogsBloom = types.CreateBloom([]*types.Receipt{r})
+	}
 
 	if err := s.WriteReceipts(h.Hash, receipts); err != nil {
 		t.Fatal(err)
@@ -391,6 +424,39 @@ func testReceipts(t *testing.T, m PlaceholderStorage) {
 	}
 
 	assert.True(t, reflect.DeepEqual(receipts, found))
+
+	// a single receipt is read without the others
+	receipt, err := s.ReadReceipt(h.Hash, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, r1, receipt)
+
+	_, err = s.ReadReceipt(h.Hash, 2)
+	assert.ErrorIs(t, err, types.ErrReceiptNotFound)
+
+	// the lookup records the index of the transaction in the block
+	if err := s.WriteTxLookup(txn.Hash, h.Hash, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	blockHash, index, ok := s.ReadTxLookupIndex(txn.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, h.Hash, blockHash)
+	assert.Equal(t, uint64(0), index)
+
+	if err := s.DeleteTxLookup(txn.Hash); err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok = s.ReadTxLookup(txn.Hash)
+	assert.False(t, ok)
+
+	if err := s.WriteTxLookupTail(7); err != nil {
+		t.Fatal(err)
+	}
+
+	tail, ok := s.ReadTxLookupTail()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), tail)
 }
 
 func testWriteCanonicalHeader(t *testing.T, m PlaceholderStorage) {
@@ -446,6 +512,102 @@ func testWriteCanonicalHeader(t *testing.T, m PlaceholderStorage) {
 	}
 }
 
+func testBloomBits(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	if _, ok := s.ReadBloomSectionHead(1); ok {
+		t.Fatal("section head should not be found")
+	}
+
+	bits := []byte{0x1, 0x2, 0x3}
+
+	if err := s.WriteBloomBits(2047, 1, bits); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.WriteBloomSectionHead(1, hash1); err != nil {
+		t.Fatal(err)
+	}
+
+	found, ok := s.ReadBloomBits(2047, 1)
+	if !ok {
+		t.Fatal("bloom bits not found")
+	}
+
+	if !reflect.DeepEqual(bits, found) {
+		t.Fatal("bloom bits mismatch")
+	}
+
+	if _, ok := s.ReadBloomBits(2047, 0); ok {
+		t.Fatal("bloom bits of another section should not be found")
+	}
+
+	head, ok := s.ReadBloomSectionHead(1)
+	if !ok || head != hash1 {
+		t.Fatal("section head mismatch")
+	}
+}
+
+func testAccumulator(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	if _, ok := s.ReadMMRNode(3); ok {
+		t.Fatal("mmr node should not be found")
+	}
+
+	if _, _, ok := s.ReadAccumulatorCheckpoint(1); ok {
+		t.Fatal("checkpoint should not be found")
+	}
+
+	if err := s.WriteMMRNode(3, hash1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.WriteAccumulatorCheckpoint(1, hash1, hash2); err != nil {
+		t.Fatal(err)
+	}
+
+	node, ok := s.ReadMMRNode(3)
+	if !ok || node != hash1 {
+		t.Fatal("mmr node mismatch")
+	}
+
+	head, root, ok := s.ReadAccumulatorCheckpoint(1)
+	if !ok || head != hash1 || root != hash2 {
+		t.Fatal("checkpoint mismatch")
+	}
+}
+
+func testIndexerCheckpoint(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	if _, _, ok := s.ReadIndexerCheckpoint("logs"); ok {
+		t.Fatal("checkpoint should not be found")
+	}
+
+	if err := s.WriteIndexerCheckpoint("logs", 5, hash1); err != nil {
+		t.Fatal(err)
+	}
+
+	number, hash, ok := s.ReadIndexerCheckpoint("logs")
+	if !ok || number != 5 || hash != hash1 {
+		t.Fatal("checkpoint mismatch")
+	}
+
+	if _, _, ok := s.ReadIndexerCheckpoint("log"); ok {
+		t.Fatal("checkpoint of another indexer should not be found")
+	}
+}
+
 // Storage delegators
 
 type readCanonicalHashDelegate func(uint64) (types.Hash, bool)
@@ -454,6 +616,8 @@ type readHeadHashDelegate func() (types.Hash, bool)
 type readHeadNumberDelegate func() (uint64, bool)
 type writeHeadHashDelegate func(types.Hash) error
 type writeHeadNumberDelegate func(uint64) error
+type writeFinalizedHashDelegate func(types.Hash) error
+type readFinalizedHashDelegate func() (types.Hash, bool)
 type writeForksDelegate func([]types.Hash) error
 type readForksDelegate func() ([]types.Hash, error)
 type writeTotalDifficultyDelegate func(types.Hash, *big.Int) error
@@ -463,35 +627,71 @@ type readHeaderDelegate func(types.Hash) (*types.Header, error)
 type writeCanonicalHeaderDelegate func(*types.Header, *big.Int) error
 type writeBodyDelegate func(types.Hash, *types.Body) error
 type readBodyDelegate func(types.Hash) (*types.Body, error)
+type readBodyTxCountDelegate func(types.Hash) (int, error)
 type writeSnapshotDelegate func(types.Hash, []byte) error
 type readSnapshotDelegate func(types.Hash) ([]byte, bool)
 type writeReceiptsDelegate func(types.Hash, []*types.Receipt) error
 type readReceiptsDelegate func(types.Hash) ([]*types.Receipt, error)
-type writeTxLookupDelegate func(types.Hash, types.Hash) error
+type readReceiptDelegate func(types.Hash, uint64) (*types.Receipt, error)
+type writeTxLookupDelegate func(types.Hash, types.Hash, uint64) error
 type readTxLookupDelegate func(types.Hash) (types.Hash, bool)
+type readTxLookupIndexDelegate func(types.Hash) (types.Hash, uint64, bool)
+type deleteTxLookupDelegate func(types.Hash) error
+type writeTxLookupTailDelegate func(uint64) error
+type readTxLookupTailDelegate func() (uint64, bool)
+type writeBloomBitsDelegate func(uint, uint64, []byte) error
+type readBloomBitsDelegate func(uint, uint64) ([]byte, bool)
+type writeBloomSectionHeadDelegate func(uint64, types.Hash) error
+type readBloomSectionHeadDelegate func(uint64) (types.Hash, bool)
+type writeMMRNodeDelegate func(uint64, types.Hash) error
+type readMMRNodeDelegate func(uint64) (types.Hash, bool)
+type writeAccumulatorCheckpointDelegate func(uint64, types.Hash, types.Hash) error
+type readAccumulatorCheckpointDelegate func(uint64) (types.Hash, types.Hash, bool)
+type writeIndexerCheckpointDelegate func(string, uint64, types.Hash) error
+type readIndexerCheckpointDelegate func(string) (uint64, types.Hash, bool)
+type statsDelegate func() (string, error)
 type closeDelegate func() error
 
 type MockStorage struct {
-	readCanonicalHashFn    readCanonicalHashDelegate
-	writeCanonicalHashFn   writeCanonicalHashDelegate
-	readHeadHashFn         readHeadHashDelegate
-	readHeadNumberFn       readHeadNumberDelegate
-	writeHeadHashFn        writeHeadHashDelegate
-	writeHeadNumberFn      writeHeadNumberDelegate
-	writeForksFn           writeForksDelegate
-	readForksFn            readForksDelegate
-	writeTotalDifficultyFn writeTotalDifficultyDelegate
-	readTotalDifficultyFn  readTotalDifficultyDelegate
-	writeHeaderFn          writeHeaderDelegate
-	readHeaderFn           readHeaderDelegate
-	writeCanonicalHeaderFn writeCanonicalHeaderDelegate
-	writeBodyFn            writeBodyDelegate
-	readBodyFn             readBodyDelegate
-	writeReceiptsFn        writeReceiptsDelegate
-	readReceiptsFn         readReceiptsDelegate
-	writeTxLookupFn        writeTxLookupDelegate
-	readTxLookupFn         readTxLookupDelegate
-	closeFn                closeDelegate
+	readCanonicalHashFn     readCanonicalHashDelegate
+	writeCanonicalHashFn    writeCanonicalHashDelegate
+	readHeadHashFn          readHeadHashDelegate
+	readHeadNumberFn        readHeadNumberDelegate
+	writeHeadHashFn         writeHeadHashDelegate
+	writeHeadNumberFn       writeHeadNumberDelegate
+	writeFinalizedHashFn    writeFinalizedHashDelegate
+	readFinalizedHashFn     readFinalizedHashDelegate
+	writeForksFn            writeForksDelegate
+	readForksFn             readForksDelegate
+	writeTotalDifficultyFn  writeTotalDifficultyDelegate
+	readTotalDifficultyFn   readTotalDifficultyDelegate
+	writeHeaderFn           writeHeaderDelegate
+	readHeaderFn            readHeaderDelegate
+	writeCanonicalHeaderFn  writeCanonicalHeaderDelegate
+	writeBodyFn             writeBodyDelegate
+	readBodyFn              readBodyDelegate
+	readBodyTxCountFn       readBodyTxCountDelegate
+	writeReceiptsFn         writeReceiptsDelegate
+	readReceiptsFn          readReceiptsDelegate
+	readReceiptFn           readReceiptDelegate
+	writeTxLookupFn         writeTxLookupDelegate
+	readTxLookupFn          readTxLookupDelegate
+	readTxLookupIndexFn     readTxLookupIndexDelegate
+	deleteTxLookupFn        deleteTxLookupDelegate
+	writeTxLookupTailFn     writeTxLookupTailDelegate
+	readTxLookupTailFn      readTxLookupTailDelegate
+	writeBloomBitsFn        writeBloomBitsDelegate
+	readBloomBitsFn         readBloomBitsDelegate
+	writeBloomSectionHeadFn writeBloomSectionHeadDelegate
+	readBloomSectionHeadFn  readBloomSectionHeadDelegate
+	writeMMRNodeFn          writeMMRNodeDelegate
+	readMMRNodeFn           readMMRNodeDelegate
+	writeAccCheckpointFn    writeAccumulatorCheckpointDelegate
+	readAccCheckpointFn     readAccumulatorCheckpointDelegate
+	writeIdxCheckpointFn    writeIndexerCheckpointDelegate
+	readIdxCheckpointFn     readIndexerCheckpointDelegate
+	statsFn                 statsDelegate
+	closeFn                 closeDelegate
 }
 
 func NewMockStorage() *MockStorage {
@@ -570,6 +770,30 @@ func (m *MockStorage) HookWriteHeadNumber(fn writeHeadNumberDelegate) {
 	m.writeHeadNumberFn = fn
 }
 
+func (m *MockStorage) WriteFinalizedHash(hash types.Hash) error {
+	if m.writeFinalizedHashFn != nil {
+		return m.writeFinalizedHashFn(hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteFinalizedHash(fn writeFinalizedHashDelegate) {
+	m.writeFinalizedHashFn = fn
+}
+
+func (m *MockStorage) ReadFinalizedHash() (types.Hash, bool) {
+	if m.readFinalizedHashFn != nil {
+		return m.readFinalizedHashFn()
+	}
+
+	return types.Hash{}, false
+}
+
+func (m *MockStorage) HookReadFinalizedHash(fn readFinalizedHashDelegate) {
+	m.readFinalizedHashFn = fn
+}
+
 func (m *MockStorage) WriteForks(forks []types.Hash) error {
 	if m.writeForksFn != nil {
 		return m.writeForksFn(forks)
@@ -678,6 +902,18 @@ func (m *MockStorage) HookReadBody(fn readBodyDelegate) {
 	m.readBodyFn = fn
 }
 
+func (m *MockStorage) ReadBodyTxCount(hash types.Hash) (int, error) {
+	if m.readBodyTxCountFn != nil {
+		return m.readBodyTxCountFn(hash)
+	}
+
+	return 0, nil
+}
+
+func (m *MockStorage) HookReadBodyTxCount(fn readBodyTxCountDelegate) {
+	m.readBodyTxCountFn = fn
+}
+
 func (m *MockStorage) WriteReceipts(hash types.Hash, receipts []*types.Receipt) error {
 	if m.writeReceiptsFn != nil {
 		return m.writeReceiptsFn(hash, receipts)
@@ -702,9 +938,21 @@ func (m *MockStorage) HookReadReceipts(fn readReceiptsDelegate) {
 	m.readReceiptsFn = fn
 }
 
-func (m *MockStorage) WriteTxLookup(hash types.Hash, blockHash types.Hash) error {
+func (m *MockStorage) ReadReceipt(hash types.Hash, index uint64) (*types.Receipt, error) {
+	if m.readReceiptFn != nil {
+		return m.readReceiptFn(hash, index)
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *MockStorage) HookReadReceipt(fn readReceiptDelegate) {
+	m.readReceiptFn = fn
+}
+
+func (m *MockStorage) WriteTxLookup(hash types.Hash, blockHash types.Hash, index uint64) error {
 	if m.writeTxLookupFn != nil {
-		return m.writeTxLookupFn(hash, blockHash)
+		return m.writeTxLookupFn(hash, blockHash, index)
 	}
 
 	return nil
@@ -726,6 +974,186 @@ func (m *MockStorage) HookReadTxLookup(fn readTxLookupDelegate) {
 	m.readTxLookupFn = fn
 }
 
+func (m *MockStorage) ReadTxLookupIndex(hash types.Hash) (types.Hash, uint64, bool) {
+	if m.readTxLookupIndexFn != nil {
+		return m.readTxLookupIndexFn(hash)
+	}
+
+	return types.Hash{}, 0, true
+}
+
+func (m *MockStorage) HookReadTxLookupIndex(fn readTxLookupIndexDelegate) {
+	m.readTxLookupIndexFn = fn
+}
+
+func (m *MockStorage) DeleteTxLookup(hash types.Hash) error {
+	if m.deleteTxLookupFn != nil {
+		return m.deleteTxLookupFn(hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookDeleteTxLookup(fn deleteTxLookupDelegate) {
+	m.deleteTxLookupFn = fn
+}
+
+func (m *MockStorage) WriteTxLookupTail(number uint64) error {
+	if m.writeTxLookupTailFn != nil {
+		return m.writeTxLookupTailFn(number)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteTxLookupTail(fn writeTxLookupTailDelegate) {
+	m.writeTxLookupTailFn = fn
+}
+
+func (m *MockStorage) ReadTxLookupTail() (uint64, bool) {
+	if m.readTxLookupTailFn != nil {
+		return m.readTxLookupTailFn()
+	}
+
+	return 0, false
+}
+
+func (m *MockStorage) HookReadTxLookupTail(fn readTxLookupTailDelegate) {
+	m.readTxLookupTailFn = fn
+}
+
+func (m *MockStorage) WriteBloomBits(bit uint, section uint64, bits []byte) error {
+	if m.writeBloomBitsFn != nil {
+		return m.writeBloomBitsFn(bit, section, bits)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBloomBits(fn writeBloomBitsDelegate) {
+	m.writeBloomBitsFn = fn
+}
+
+func (m *MockStorage) ReadBloomBits(bit uint, section uint64) ([]byte, bool) {
+	if m.readBloomBitsFn != nil {
+		return m.readBloomBitsFn(bit, section)
+	}
+
+	return nil, false
+}
+
+func (m *MockStorage) HookReadBloomBits(fn readBloomBitsDelegate) {
+	m.readBloomBitsFn = fn
+}
+
+func (m *MockStorage) WriteBloomSectionHead(section uint64, hash types.Hash) error {
+	if m.writeBloomSectionHeadFn != nil {
+		return m.writeBloomSectionHeadFn(section, hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBloomSectionHead(fn writeBloomSectionHeadDelegate) {
+	m.writeBloomSectionHeadFn = fn
+}
+
+func (m *MockStorage) ReadBloomSectionHead(section uint64) (types.Hash, bool) {
+	if m.readBloomSectionHeadFn != nil {
+		return m.readBloomSectionHeadFn(section)
+	}
+
+	return types.Hash{}, false
+}
+
+func (m *MockStorage) HookReadBloomSectionHead(fn readBloomSectionHeadDelegate) {
+	m.readBloomSectionHeadFn = fn
+}
+
+func (m *MockStorage) WriteMMRNode(pos uint64, hash types.Hash) error {
+	if m.writeMMRNodeFn != nil {
+		return m.writeMMRNodeFn(pos, hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteMMRNode(fn writeMMRNodeDelegate) {
+	m.writeMMRNodeFn = fn
+}
+
+func (m *MockStorage) ReadMMRNode(pos uint64) (types.Hash, bool) {
+	if m.readMMRNodeFn != nil {
+		return m.readMMRNodeFn(pos)
+	}
+
+	return types.Hash{}, false
+}
+
+func (m *MockStorage) HookReadMMRNode(fn readMMRNodeDelegate) {
+	m.readMMRNodeFn = fn
+}
+
+func (m *MockStorage) WriteAccumulatorCheckpoint(checkpoint uint64, head, root types.Hash) error {
+	if m.writeAccCheckpointFn != nil {
+		return m.writeAccCheckpointFn(checkpoint, head, root)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteAccumulatorCheckpoint(fn writeAccumulatorCheckpointDelegate) {
+	m.writeAccCheckpointFn = fn
+}
+
+func (m *MockStorage) ReadAccumulatorCheckpoint(checkpoint uint64) (types.Hash, types.Hash, bool) {
+	if m.readAccCheckpointFn != nil {
+		return m.readAccCheckpointFn(checkpoint)
+	}
+
+	return types.Hash{}, types.Hash{}, false
+}
+
+func (m *MockStorage) HookReadAccumulatorCheckpoint(fn readAccumulatorCheckpointDelegate) {
+	m.readAccCheckpointFn = fn
+}
+
+func (m *MockStorage) WriteIndexerCheckpoint(name string, number uint64, hash types.Hash) error {
+	if m.writeIdxCheckpointFn != nil {
+		return m.writeIdxCheckpointFn(name, number, hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteIndexerCheckpoint(fn writeIndexerCheckpointDelegate) {
+	m.writeIdxCheckpointFn = fn
+}
+
+func (m *MockStorage) ReadIndexerCheckpoint(name string) (uint64, types.Hash, bool) {
+	if m.readIdxCheckpointFn != nil {
+		return m.readIdxCheckpointFn(name)
+	}
+
+	return 0, types.Hash{}, false
+}
+
+func (m *MockStorage) HookReadIndexerCheckpoint(fn readIndexerCheckpointDelegate) {
+	m.readIdxCheckpointFn = fn
+}
+
+func (m *MockStorage) Stats() (string, error) {
+	if m.statsFn != nil {
+		return m.statsFn()
+	}
+
+	return "", nil
+}
+
+func (m *MockStorage) HookStats(fn statsDelegate) {
+	m.statsFn = fn
+}
+
 func (m *MockStorage) Close() error {
 	if m.closeFn != nil {
 		return m.closeFn()
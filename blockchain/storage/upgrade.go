@@ -0,0 +1,156 @@
+//nolint:stylecheck
+package storage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// upgradeBatchSize is the number of entries scanned before the rewritten ones are written at once
+	upgradeBatchSize = 10000
+)
+
+// ReceiptsUpgrade is the number of entries rewritten in the latest encoding by the upgrade of the receipts
+type ReceiptsUpgrade struct {
+	Receipts  uint64
+	TxLookups uint64
+}
+
+// UpgradeDatabaseReceipts rewrites the receipts of the blocks and the transaction lookups stored in the key-value
+// database in their previous encodings, the node using it must be stopped. The bodies of the blocks moved to the
+// freezer, if it is set, are read to index their transactions, while their receipts keep the encoding they were
+// frozen with, which is still read. The number of entries rewritten so far is reported to progress
+func UpgradeDatabaseReceipts(
+	db kvdb.Database,
+	freezer *Freezer,
+	progress func(upgraded uint64),
+) (*ReceiptsUpgrade, error) {
+	s := &KeyValueStorage{logger: hclog.NewNullLogger(), db: &databaseKV{db}, ancient: freezer}
+	upgrade := &ReceiptsUpgrade{}
+
+	var err error
+
+	upgrade.Receipts, err = upgradeEntries(db, RECEIPTS, upgradeReceipts, progress)
+	if err != nil {
+		return upgrade, fmt.Errorf("failed to upgrade the receipts: %w", err)
+	}
+
+	upgrade.TxLookups, err = upgradeEntries(db, TX_LOOKUP_PREFIX, s.upgradeTxLookup, func(upgraded uint64) {
+		progress(upgrade.Receipts + upgraded)
+	})
+	if err != nil {
+		return upgrade, fmt.Errorf("failed to upgrade the transaction lookups: %w", err)
+	}
+
+	return upgrade, nil
+}
+
+// upgradeReceipts returns the receipts of the block in the latest encoding, nil if they are already
+func upgradeReceipts(key, value []byte) ([]byte, error) {
+	if len(key) != len(RECEIPTS)+types.HashLength {
+		return nil, nil
+	}
+
+	receipts := types.Receipts{}
+	if err := receipts.UnmarshalStoreRLP(value); err != nil {
+		return nil, fmt.Errorf("block %s: %w", types.BytesToHash(key[len(RECEIPTS):]), err)
+	}
+
+	if upgraded := receipts.MarshalStoreRLPTo(nil); !bytes.Equal(upgraded, value) {
+		return upgraded, nil
+	}
+
+	return nil, nil
+}
+
+// upgradeTxLookup returns the lookup of the transaction recording its index in the block, nil if it already does.
+// The lookups of the transactions of the rewound blocks, and of the blocks without a body, are left as is
+func (s *KeyValueStorage) upgradeTxLookup(key, value []byte) ([]byte, error) {
+	if len(key) != len(TX_LOOKUP_PREFIX)+types.HashLength {
+		return nil, nil
+	}
+
+	blockHash, _, indexed, err := decodeTxLookup(value)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: %w", types.BytesToHash(key[len(TX_LOOKUP_PREFIX):]), err)
+	}
+
+	if indexed || blockHash == types.ZeroHash {
+		return nil, nil
+	}
+
+	blockHash, index, ok := s.ReadTxLookupIndex(types.BytesToHash(key[len(TX_LOOKUP_PREFIX):]))
+	if !ok {
+		return nil, nil
+	}
+
+	return encodeTxLookup(blockHash, index), nil
+}
+
+// upgradeEntries rewrites the entries with the prefix whose value is upgraded, and returns their number.
+// The entries are scanned in batches, the rewritten ones being written once the iterator of the batch is released
+func upgradeEntries(
+	db kvdb.Database,
+	prefix []byte,
+	upgrade func(key, value []byte) ([]byte, error),
+	progress func(upgraded uint64),
+) (uint64, error) {
+	var (
+		start    []byte
+		upgraded uint64
+	)
+
+	for {
+		batch := db.NewBatch()
+		it := db.NewIterator(prefix, start)
+		scanned, done := 0, true
+
+		for it.Next() {
+			if scanned == upgradeBatchSize {
+				start, done = append([]byte{}, it.Key()[len(prefix):]...), false
+
+				break
+			}
+
+			scanned++
+
+			value, err := upgrade(it.Key(), it.Value())
+			if err != nil {
+				it.Release()
+
+				return upgraded, err
+			}
+
+			if value != nil {
+				batch.Put(append([]byte{}, it.Key()...), value)
+			}
+		}
+
+		err := it.Error()
+		it.Release()
+
+		if err != nil {
+			return upgraded, err
+		}
+
+		if batch.Len() > 0 {
+			count := uint64(batch.Len())
+
+			if err := batch.Write(); err != nil {
+				return upgraded, err
+			}
+
+			upgraded += count
+			progress(upgraded)
+		}
+
+		if done {
+			return upgraded, nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// marshalReceiptsV1 marshals the receipts of a block in the first storage encoding
+func marshalReceiptsV1(receipts []*types.Receipt) []byte {
+	return types.MarshalRLPTo(func(a *fastrlp.Arena) *fastrlp.Value {
+		vv := a.NewArray()
+		for _, r := range receipts {
+			vv.Set(r.MarshalStoreRLPWith(a))
+		}
+
+		return vv
+	}, nil)
+}
+
+func TestUpgradeDatabaseReceipts(t *testing.T) {
+	t.Parallel()
+
+	db := kvdb.NewMemoryDatabase()
+	s := NewDatabaseStorage(hclog.NewNullLogger(), db)
+
+	txns := []*types.Transaction{
+		{Nonce: 1, GasPrice: big.NewInt(1), V: big.NewInt(1)},
+		{Nonce: 2, GasPrice: big.NewInt(1), V: big.NewInt(1)},
+	}
+
+	for _, txn := range txns {
+		txn.ComputeHash()
+	}
+
+	blockHash := types.StringToHash("block")
+	require.NoError(t, s.WriteBody(blockHash, &types.Body{Transactions: txns}))
+
+	receipts := []*types.Receipt{
+		{
+			CumulativeGasUsed: 10,
+			GasUsed:           10,
+			TxHash:            txns[0].Hash,
+			Logs:              []*types.Log{{Address: addr1, Topics: []types.Hash{hash1}}},
+		},
+		{
+			CumulativeGasUsed: 30,
+			GasUsed:           20,
+			TxHash:            txns[1].Hash,
+			Logs:              []*types.Log{{Address: addr2, Topics: []types.Hash{hash2}}},
+		},
+	}
+
+	for _, r := range receipts {
+		r.SetStatus(types.ReceiptSuccess)
+		r.LogsBloom = types.CreateBloom([]*types.Receipt{r})
+	}
+
+	// the receipts and the lookups written in the previous encodings
+	require.NoError(t, db.Put(append(append([]byte{}, RECEIPTS...), blockHash.Bytes()...), marshalReceiptsV1(receipts)))
+
+	for _, txn := range txns {
+		ar := &fastrlp.Arena{}
+		lookup := ar.NewBytes(blockHash.Bytes()).MarshalTo(nil)
+
+		require.NoError(t, db.Put(append(append([]byte{}, TX_LOOKUP_PREFIX...), txn.Hash.Bytes()...), lookup))
+	}
+
+	// the previous encodings are read until they are upgraded
+	receipts[1].LogIndex = 1
+
+	found, err := s.ReadReceipts(blockHash)
+	require.NoError(t, err)
+	assert.Equal(t, receipts, found)
+
+	_, index, ok := s.ReadTxLookupIndex(txns[1].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), index)
+
+	upgrade, err := UpgradeDatabaseReceipts(db, nil, func(uint64) {})
+	require.NoError(t, err)
+	assert.Equal(t, &ReceiptsUpgrade{Receipts: 1, TxLookups: 2}, upgrade)
+
+	receipt, err := s.ReadReceipt(blockHash, 1)
+	require.NoError(t, err)
+	assert.Equal(t, receipts[1], receipt)
+
+	for i, txn := range txns {
+		_, index, indexed, ok := s.(*KeyValueStorage).readTxLookup(txn.Hash)
+		assert.True(t, ok && indexed)
+		assert.Equal(t, uint64(i), index)
+	}
+
+	// the upgraded entries are left as is
+	upgrade, err = UpgradeDatabaseReceipts(db, nil, func(uint64) {})
+	require.NoError(t, err)
+	assert.Equal(t, &ReceiptsUpgrade{}, upgrade)
+}
@@ -288,6 +288,8 @@ type processBlockDelegate func(types.Hash, *types.Block, types.Address) (*state.
 
 type mockExecutor struct {
 	processBlockFn processBlockDelegate
+	gasTarget      uint64
+	gasTargetErr   error
 }
 
 func (m *mockExecutor) ProcessBlock(
@@ -302,6 +304,10 @@ func (m *mockExecutor) ProcessBlock(
 	return nil, nil
 }
 
+func (m *mockExecutor) GasTarget(types.Hash) (uint64, error) {
+	return m.gasTarget, m.gasTargetErr
+}
+
 func (m *mockExecutor) HookProcessBlock(fn processBlockDelegate) {
 	m.processBlockFn = fn
 }
@@ -343,7 +349,7 @@ func newBlockChain(config *chain.Chain, executor Executor) (*Blockchain, error)
 		executor = &mockExecutor{}
 	}
 
-	b, err := NewBlockchain(hclog.NewNullLogger(), "", config, &MockVerifier{}, executor, &mockSigner{})
+	b, err := NewBlockchain(hclog.NewNullLogger(), nil, config, &MockVerifier{}, executor, &mockSigner{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,174 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// txLookupTailBatch is the maximum number of blocks the tail of the lookups is moved by at once,
+	// so that a change of the limit doesn't hold the other indexers back
+	txLookupTailBatch = 1000
+)
+
+// txLookupIndex maintains the lookups of the transactions of the canonical chain, from their hash to their
+// block and their index in it. The lookups of a block are written along with its body, the index removes
+// the ones of the blocks which left the canonical chain and, if the lookups are limited to the recent blocks,
+// the ones of the blocks out of the limit. The oldest block whose transactions are looked up is the tail,
+// which follows the head and is moved back when the limit is raised or lifted
+type txLookupIndex struct {
+	logger hclog.Logger
+	db     storage.Storage
+
+	// getHeader returns the canonical header with the given number
+	getHeader func(uint64) (*types.Header, bool)
+
+	// limit is the number of recent blocks whose transactions are looked up, 0 for all of them
+	limit uint64
+}
+
+func newTxLookupIndex(
+	logger hclog.Logger,
+	db storage.Storage,
+	getHeader func(uint64) (*types.Header, bool),
+) *txLookupIndex {
+	return &txLookupIndex{
+		logger:    logger.Named("tx-lookup-index"),
+		db:        db,
+		getHeader: getHeader,
+	}
+}
+
+// setLimit sets the number of recent blocks whose transactions are looked up, 0 for all of them
+func (i *txLookupIndex) setLimit(limit uint64) {
+	atomic.StoreUint64(&i.limit, limit)
+}
+
+// Name returns the name the progress of the index is checkpointed under
+func (i *txLookupIndex) Name() string {
+	return "txlookup"
+}
+
+// Connect writes the lookups of the transactions of the block, then moves the tail within the limit
+func (i *txLookupIndex) Connect(header *types.Header) error {
+	if err := i.index(header); err != nil {
+		return err
+	}
+
+	return i.moveTail(header.Number)
+}
+
+// Disconnect removes the lookups of the transactions of the block, then moves the tail within the limit
+func (i *txLookupIndex) Disconnect(header *types.Header) error {
+	if err := i.unindex(header); err != nil {
+		return err
+	}
+
+	return i.moveTail(header.Number - 1)
+}
+
+// tailTarget returns the number of the oldest block whose transactions are looked up with the given head
+func (i *txLookupIndex) tailTarget(head uint64) uint64 {
+	if limit := atomic.LoadUint64(&i.limit); limit > 0 && head+1 > limit {
+		return head + 1 - limit
+	}
+
+	return 0
+}
+
+// moveTail removes the lookups of the blocks below the limit, or writes the ones of the blocks back within it
+func (i *txLookupIndex) moveTail(head uint64) error {
+	// the lookups of all the blocks were written before the tail was recorded
+	tail, _ := i.db.ReadTxLookupTail()
+	target := i.tailTarget(head)
+
+	if tail == target {
+		return nil
+	}
+
+	for moved := 0; tail < target && moved < txLookupTailBatch; moved++ {
+		header, ok := i.getHeader(tail)
+		if !ok {
+			return fmt.Errorf("header %d not found", tail)
+		}
+
+		if err := i.unindex(header); err != nil {
+			return err
+		}
+
+		tail++
+	}
+
+	for moved := 0; tail > target && moved < txLookupTailBatch; moved++ {
+		header, ok := i.getHeader(tail - 1)
+		if !ok {
+			return fmt.Errorf("header %d not found", tail-1)
+		}
+
+		if err := i.index(header); err != nil {
+			return err
+		}
+
+		tail--
+	}
+
+	i.logger.Debug("tail moved", "tail", tail, "target", target)
+
+	return i.db.WriteTxLookupTail(tail)
+}
+
+// index writes the lookups of the transactions of the block
+func (i *txLookupIndex) index(header *types.Header) error {
+	body, err := i.readBody(header)
+	if body == nil || err != nil {
+		return err
+	}
+
+	for index, txn := range body.Transactions {
+		if err := i.db.WriteTxLookup(txn.Hash, header.Hash, uint64(index)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unindex removes the lookups of the transactions of the block, but the ones of the transactions
+// which were included again in another block
+func (i *txLookupIndex) unindex(header *types.Header) error {
+	body, err := i.readBody(header)
+	if body == nil || err != nil {
+		return err
+	}
+
+	for _, txn := range body.Transactions {
+		// the lookups of the transactions of the rewound blocks point to the zero hash
+		if blockHash, ok := i.db.ReadTxLookup(txn.Hash); !ok || (blockHash != header.Hash && blockHash != types.ZeroHash) {
+			continue
+		}
+
+		if err := i.db.DeleteTxLookup(txn.Hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readBody reads the body of the block, nil if it has no transactions
+func (i *txLookupIndex) readBody(header *types.Header) (*types.Body, error) {
+	if !header.HasBody() {
+		return nil, nil
+	}
+
+	body, err := i.db.ReadBody(header.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the body of block %d: %w", header.Number, err)
+	}
+
+	return body, nil
+}
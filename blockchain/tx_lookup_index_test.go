@@ -0,0 +1,124 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTxLookupChain writes a canonical chain whose blocks hold a single transaction each
+func newTestTxLookupChain(t *testing.T, db storage.Storage, length uint64) ([]*types.Header, []*types.Transaction) {
+	t.Helper()
+
+	headers := make([]*types.Header, length)
+	txns := make([]*types.Transaction, length)
+
+	for i := uint64(0); i < length; i++ {
+		txns[i] = (&types.Transaction{Nonce: i, GasPrice: big.NewInt(1), V: big.NewInt(1)}).ComputeHash()
+
+		header := &types.Header{
+			Number: i,
+			TxRoot: types.StringToHash("txs"),
+		}
+
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash
+		}
+
+		header.ComputeHash()
+		headers[i] = header
+
+		require.NoError(t, db.WriteCanonicalHash(i, header.Hash))
+		require.NoError(t, db.WriteBody(header.Hash, &types.Body{Transactions: txns[i : i+1]}))
+	}
+
+	return headers, txns
+}
+
+// assertTxLookups checks the transactions of the blocks from the tail are looked up, and the older ones aren't
+func assertTxLookups(t *testing.T, db storage.Storage, txns []*types.Transaction, tail, head uint64) {
+	t.Helper()
+
+	for i, txn := range txns {
+		_, ok := db.ReadTxLookup(txn.Hash)
+		assert.Equal(t, uint64(i) >= tail && uint64(i) <= head, ok, "transaction of block %d", i)
+	}
+
+	stored, _ := db.ReadTxLookupTail()
+	assert.Equal(t, tail, stored)
+}
+
+func TestTxLookupIndex_Limit(t *testing.T) {
+	t.Parallel()
+
+	db, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	headers, txns := newTestTxLookupChain(t, db, 6)
+
+	index := newTxLookupIndex(hclog.NewNullLogger(), db, func(n uint64) (*types.Header, bool) {
+		if n >= uint64(len(headers)) {
+			return nil, false
+		}
+
+		return headers[n], true
+	})
+
+	for _, header := range headers {
+		require.NoError(t, index.Connect(header))
+	}
+
+	assertTxLookups(t, db, txns, 0, 5)
+
+	blockHash, txIndex, ok := db.ReadTxLookupIndex(txns[3].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, headers[3].Hash, blockHash)
+	assert.Equal(t, uint64(0), txIndex)
+
+	// the lookups of the blocks out of the limit are removed
+	index.setLimit(2)
+	require.NoError(t, index.moveTail(5))
+
+	assertTxLookups(t, db, txns, 4, 5)
+
+	// the block out of the limit before the reorg is looked up again
+	require.NoError(t, index.Disconnect(headers[5]))
+
+	assertTxLookups(t, db, txns, 3, 4)
+
+	// the lookups are written back once the limit is lifted
+	index.setLimit(0)
+	require.NoError(t, index.moveTail(4))
+
+	assertTxLookups(t, db, txns, 0, 4)
+}
+
+func TestTxLookupIndex_Reincluded(t *testing.T) {
+	t.Parallel()
+
+	db, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	headers, txns := newTestTxLookupChain(t, db, 2)
+
+	index := newTxLookupIndex(hclog.NewNullLogger(), db, func(uint64) (*types.Header, bool) {
+		return nil, false
+	})
+
+	// the transaction of the disconnected block was included again in the block of the new chain
+	fork := &types.Header{Number: 1, ParentHash: headers[0].Hash, ExtraData: []byte("fork")}
+	fork.ComputeHash()
+
+	require.NoError(t, db.WriteTxLookup(txns[1].Hash, fork.Hash, 0))
+	require.NoError(t, index.Disconnect(headers[1]))
+
+	blockHash, ok := db.ReadTxLookup(txns[1].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, fork.Hash, blockHash)
+}
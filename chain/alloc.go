@@ -0,0 +1,63 @@
+package chain
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// ReadAllocFile streams the accounts of an allocation file, a JSON object of the genesis accounts by address
+// like the alloc of the genesis. The accounts are decoded one at a time, so the file doesn't have to fit in memory
+func ReadAllocFile(path string, fn func(types.Address, *GenesisAccount) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		addr, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("invalid account address %v", token)
+		}
+
+		account := &GenesisAccount{}
+		if err := dec.Decode(account); err != nil {
+			return fmt.Errorf("account %s: %w", addr, err)
+		}
+
+		if err := fn(types.StringToAddress(addr), account); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return fmt.Errorf("expected %s but found %v", delim, token)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package chain
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestReadAllocFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "alloc.json")
+
+	if err := os.WriteFile(path, []byte(`{
+		"0x0000000000000000000000000000000000000001": {
+			"balance": "0x11",
+			"nonce": "0x2"
+		},
+		"0x0000000000000000000000000000000000000002": {
+			"code": "0x6001",
+			"storage": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001":
+					"0x0000000000000000000000000000000000000000000000000000000000000002"
+			}
+		}
+	}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts := map[types.Address]*GenesisAccount{}
+
+	if err := ReadAllocFile(path, func(addr types.Address, account *GenesisAccount) error {
+		accounts[addr] = account
+
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(accounts) != 2 {
+		t.Fatalf("expected 2 accounts but found %d", len(accounts))
+	}
+
+	if account := accounts[addr("1")]; account.Balance.Cmp(big.NewInt(17)) != 0 || account.Nonce != 2 {
+		t.Fatalf("unexpected account %v", account)
+	}
+
+	if account := accounts[addr("2")]; account.Storage[hash("1")] != hash("2") || len(account.Code) != 2 {
+		t.Fatalf("unexpected account %v", account)
+	}
+
+	// the allocation file must be an object of accounts
+	if err := os.WriteFile(path, []byte(`[{"balance": "0x1"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ReadAllocFile(path, func(types.Address, *GenesisAccount) error { return nil }); err == nil {
+		t.Fatal("expected an error for an allocation file which isn't an object")
+	}
+}
+
+func TestGenesisAllocFilePath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "genesis.json")
+
+	if err := os.WriteFile(path, []byte(`{
+		"genesis": {"gasLimit": "0x1", "difficulty": "0x1", "allocFile": "alloc.json"},
+		"params": {"engine": {"ibft": {}}}
+	}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := ImportFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the allocation file is relative to the genesis file
+	if allocFile := chain.Genesis.AllocFilePath(); allocFile != filepath.Join(dir, "alloc.json") {
+		t.Fatalf("unexpected allocation file %s", allocFile)
+	}
+}
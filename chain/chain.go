@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -41,6 +42,10 @@ type Genesis struct {
 	Coinbase   types.Address                     `json:"coinbase"`
 	Alloc      map[types.Address]*GenesisAccount `json:"alloc,omitempty"`
 
+	// AllocFile is the path of a file holding more accounts of the genesis state,
+	// relative to the directory of the genesis file
+	AllocFile string `json:"allocFile,omitempty"`
+
 	// Override
 	StateRoot types.Hash
 
@@ -48,6 +53,18 @@ type Genesis struct {
 	Number     uint64     `json:"number"`
 	GasUsed    uint64     `json:"gasUsed"`
 	ParentHash types.Hash `json:"parentHash"`
+
+	// dir is the directory of the file the genesis is imported from
+	dir string
+}
+
+// AllocFilePath returns the path of the allocation file, empty if there is none
+func (g *Genesis) AllocFilePath() string {
+	if g.AllocFile == "" || filepath.IsAbs(g.AllocFile) {
+		return g.AllocFile
+	}
+
+	return filepath.Join(g.dir, g.AllocFile)
 }
 
 // GenesisHeader converts the initially defined genesis struct to a header
@@ -106,6 +123,7 @@ func (g *Genesis) MarshalJSON() ([]byte, error) {
 		Mixhash    types.Hash                  `json:"mixHash"`
 		Coinbase   types.Address               `json:"coinbase"`
 		Alloc      *map[string]*GenesisAccount `json:"alloc,omitempty"`
+		AllocFile  string                      `json:"allocFile,omitempty"`
 		Number     *string                     `json:"number,omitempty"`
 		GasUsed    *string                     `json:"gasUsed,omitempty"`
 		ParentHash types.Hash                  `json:"parentHash"`
@@ -132,6 +150,7 @@ func (g *Genesis) MarshalJSON() ([]byte, error) {
 		enc.Alloc = &alloc
 	}
 
+	enc.AllocFile = g.AllocFile
 	enc.Number = types.EncodeUint64(g.Number)
 	enc.GasUsed = types.EncodeUint64(g.GasUsed)
 	enc.ParentHash = g.ParentHash
@@ -150,6 +169,7 @@ func (g *Genesis) UnmarshalJSON(data []byte) error {
 		Mixhash    *types.Hash                `json:"mixHash"`
 		Coinbase   *types.Address             `json:"coinbase"`
 		Alloc      map[string]*GenesisAccount `json:"alloc"`
+		AllocFile  string                     `json:"allocFile"`
 		Number     *string                    `json:"number"`
 		GasUsed    *string                    `json:"gasUsed"`
 		ParentHash *types.Hash                `json:"parentHash"`
@@ -214,6 +234,8 @@ func (g *Genesis) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	g.AllocFile = dec.AllocFile
+
 	g.Number, subErr = types.ParseUint64orHex(dec.Number)
 	if subErr != nil {
 		parseError("number", subErr)
@@ -345,7 +367,16 @@ func ImportFromFile(filename string) (*Chain, error) {
 		return nil, err
 	}
 
-	return importChain(data)
+	chain, err := importChain(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if chain.Genesis != nil {
+		chain.Genesis.dir = filepath.Dir(filename)
+	}
+
+	return chain, nil
 }
 
 func importChain(content []byte) (*Chain, error) {
@@ -359,5 +390,11 @@ func importChain(content []byte) (*Chain, error) {
 		return nil, fmt.Errorf("expected one consensus engine but found %d", len(engines))
 	}
 
+	if chain.Params.Forks != nil {
+		if err := chain.Params.Forks.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	return chain, nil
 }
@@ -1,11 +1,22 @@
 package chain
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
+const (
+	// DefaultMaxBlockSize is the maximum size of the block transactions once the block size fork is enabled,
+	// if the chain params don't set it
+	DefaultMaxBlockSize = 2 * 1024 * 1024 // 2 MiB
+)
+
 // Params are all the set of params for the chain
 type Params struct {
 	Forks          *Forks                 `json:"forks"`
@@ -13,6 +24,78 @@ type Params struct {
 	Engine         map[string]interface{} `json:"engine"`
 	Whitelists     *Whitelists            `json:"whitelists,omitempty"`
 	BlockGasTarget uint64                 `json:"blockGasTarget"`
+	MaxBlockSize   uint64                 `json:"maxBlockSize,omitempty"`
+	Precompiles    []*Precompile          `json:"precompiles,omitempty"`
+
+	// GasTargetContract is the contract governing the block gas target, which is the value of its first
+	// storage slot. Once the slot is set, the gas limit of every block moves toward it by the bound,
+	// regardless of the block gas target of the nodes
+	GasTargetContract *types.Address `json:"gasTargetContract,omitempty"`
+
+	// NativeToken configures the economics of the native token from the nativeToken fork
+	NativeToken *NativeToken `json:"nativeToken,omitempty"`
+
+	// Allowlist restricts the accounts deploying contracts or sending transactions from the allowlist fork
+	Allowlist *Allowlist `json:"allowlist,omitempty"`
+}
+
+// Allowlist restricts the deployments and the transactions to the accounts holding the roles,
+// which are granted by the admins through the allowlist precompile
+type Allowlist struct {
+	// Admins grant the roles of the accounts, and hold all of them
+	Admins []types.Address `json:"admins,omitempty"`
+
+	// Deployments restricts the deployment of contracts, including the ones of the contracts,
+	// to the transactions sent by the accounts holding the deployer role
+	Deployments bool `json:"deployments,omitempty"`
+
+	// Transactions restricts the transactions to the accounts holding the sender role
+	Transactions bool `json:"transactions,omitempty"`
+}
+
+// IsAdmin returns whether the account is an admin of the allowlist
+func (a *Allowlist) IsAdmin(addr types.Address) bool {
+	for _, admin := range a.Admins {
+		if admin == addr {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NativeToken configures the supply of the native token and the fees of the transactions
+type NativeToken struct {
+	// Minter is the account allowed to mint the native token through the native token precompile,
+	// the supply can't grow if it isn't set
+	Minter *types.Address `json:"minter,omitempty"`
+
+	// BaseFee is the part of the gas price, in wei, which isn't paid to the block creator.
+	// The transactions must pay at least it
+	BaseFee uint64 `json:"baseFee,omitempty"`
+
+	// Treasury receives the base fee of the transactions, which is burned if the treasury isn't set
+	Treasury *types.Address `json:"treasury,omitempty"`
+}
+
+// NativeTokenAt returns the economics of the native token in the block with the number and the timestamp,
+// nil if the nativeToken fork isn't active in it
+func (p *Params) NativeTokenAt(block, timestamp uint64) *NativeToken {
+	if p.NativeToken == nil || p.Forks == nil || !p.Forks.active(p.Forks.NativeToken, block, timestamp) {
+		return nil
+	}
+
+	return p.NativeToken
+}
+
+// AllowlistAt returns the allowlist of the block with the number and the timestamp,
+// nil if the allowlist fork isn't active in it
+func (p *Params) AllowlistAt(block, timestamp uint64) *Allowlist {
+	if p.Allowlist == nil || p.Forks == nil || !p.Forks.active(p.Forks.Allowlist, block, timestamp) {
+		return nil
+	}
+
+	return p.Allowlist
 }
 
 func (p *Params) GetEngine() string {
@@ -24,9 +107,35 @@ func (p *Params) GetEngine() string {
 	return ""
 }
 
+// MaxBlockSizeAt returns the maximum size in bytes of the encoded transactions and uncles of the block
+// at the given height, 0 if the block size isn't limited. The header isn't accounted,
+// since the committed seals are added to it after the block has been proposed
+func (p *Params) MaxBlockSizeAt(block uint64) uint64 {
+	if p.Forks == nil || !p.Forks.IsBlockSize(block) {
+		return 0
+	}
+
+	if p.MaxBlockSize == 0 {
+		return DefaultMaxBlockSize
+	}
+
+	return p.MaxBlockSize
+}
+
+// Precompile is a chain specific precompiled contract, run at its address from the activation block
+type Precompile struct {
+	// Name is the name the contract was registered with
+	Name       string                 `json:"name"`
+	Address    types.Address          `json:"address"`
+	Activation Fork                   `json:"activation"`
+	Config     map[string]interface{} `json:"config,omitempty"`
+}
+
 // Whitelists specifies supported whitelists
 type Whitelists struct {
 	Deployment []types.Address `json:"deployment,omitempty"`
+	// System are the senders of the system transactions, which the validators include before the other ones
+	System []types.Address `json:"system,omitempty"`
 }
 
 // Forks specifies when each fork is activated
@@ -39,71 +148,271 @@ type Forks struct {
 	EIP150         *Fork `json:"EIP150,omitempty"`
 	EIP158         *Fork `json:"EIP158,omitempty"`
 	EIP155         *Fork `json:"EIP155,omitempty"`
+	EIP2930        *Fork `json:"EIP2930,omitempty"`
+	Multicall      *Fork `json:"multicall,omitempty"`
+	Decompress     *Fork `json:"decompress,omitempty"`
+	Maintenance    *Fork `json:"maintenance,omitempty"`
+	BlockSize      *Fork `json:"blockSize,omitempty"`
+	EIP3855        *Fork `json:"EIP3855,omitempty"`
+	EIP5656        *Fork `json:"EIP5656,omitempty"`
+	EIP1153        *Fork `json:"EIP1153,omitempty"`
+	EIP3198        *Fork `json:"EIP3198,omitempty"`
+	EIP6780        *Fork `json:"EIP6780,omitempty"`
+	NativeToken    *Fork `json:"nativeToken,omitempty"`
+	Allowlist      *Fork `json:"allowlist,omitempty"`
+	Sponsorship    *Fork `json:"sponsorship,omitempty"`
+
+	// BlockAccessList commits the hash of the accounts and slots accessed by the transactions in the IBFT extra
+	BlockAccessList *Fork `json:"blockAccessList,omitempty"`
 }
 
-func (f *Forks) active(ff *Fork, block uint64) bool {
-	if ff == nil {
-		return false
-	}
+// namedFork is a fork of the forks with the name it is configured with
+type namedFork struct {
+	name string
+	fork **Fork
 
-	return ff.Active(block)
+	// blockOnly is set for the forks checked before the header of the block exists,
+	// which can't be activated at a timestamp
+	blockOnly bool
 }
 
-func (f *Forks) IsHomestead(block uint64) bool {
-	return f.active(f.Homestead, block)
+// named returns the forks with their names. The forks of the Ethereum hard forks come first,
+// in the order they must be activated
+func (f *Forks) named() []namedFork {
+	return []namedFork{
+		{name: "homestead", fork: &f.Homestead},
+		{name: "EIP150", fork: &f.EIP150},
+		{name: "EIP155", fork: &f.EIP155},
+		{name: "EIP158", fork: &f.EIP158},
+		{name: "byzantium", fork: &f.Byzantium},
+		{name: "constantinople", fork: &f.Constantinople},
+		{name: "petersburg", fork: &f.Petersburg},
+		{name: "istanbul", fork: &f.Istanbul},
+		{name: "EIP2930", fork: &f.EIP2930},
+		{name: "multicall", fork: &f.Multicall},
+		{name: "decompress", fork: &f.Decompress},
+		{name: "maintenance", fork: &f.Maintenance, blockOnly: true},
+		{name: "blockSize", fork: &f.BlockSize, blockOnly: true},
+		{name: "EIP3855", fork: &f.EIP3855},
+		{name: "EIP5656", fork: &f.EIP5656},
+		{name: "EIP1153", fork: &f.EIP1153},
+		{name: "EIP3198", fork: &f.EIP3198},
+		{name: "EIP6780", fork: &f.EIP6780},
+		{name: "nativeToken", fork: &f.NativeToken},
+		{name: "allowlist", fork: &f.Allowlist},
+		{name: "sponsorship", fork: &f.Sponsorship},
+		{name: "blockAccessList", fork: &f.BlockAccessList},
+	}
 }
 
-func (f *Forks) IsByzantium(block uint64) bool {
-	return f.active(f.Byzantium, block)
+// orderedForks is the number of the first named forks which must be activated in order
+const orderedForks = 9
+
+// ForkNames returns the names of the forks
+func ForkNames() []string {
+	named := (&Forks{}).named()
+	names := make([]string, len(named))
+
+	for i, fork := range named {
+		names[i] = fork.name
+	}
+
+	return names
 }
 
-func (f *Forks) IsConstantinople(block uint64) bool {
-	return f.active(f.Constantinople, block)
+// Set sets the activation of the fork with the name, matched case insensitively
+func (f *Forks) Set(name string, fork *Fork) error {
+	for _, named := range f.named() {
+		if strings.EqualFold(named.name, name) {
+			*named.fork = fork
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown fork %s", name)
 }
 
-func (f *Forks) IsPetersburg(block uint64) bool {
-	return f.active(f.Petersburg, block)
+// Validate checks the Ethereum hard forks are activated in order, a block fork can't follow a timestamp fork,
+// and the forks checked before the header of a block exists are activated at a block
+func (f *Forks) Validate() error {
+	var prev *namedFork
+
+	for i, named := range f.named() {
+		fork := *named.fork
+		if fork == nil {
+			continue
+		}
+
+		if named.blockOnly && fork.Timestamp != nil {
+			return fmt.Errorf("fork %s can only be activated at a block", named.name)
+		}
+
+		if i >= orderedForks {
+			continue
+		}
+
+		if prev != nil && fork.Before(**prev.fork) {
+			return fmt.Errorf("fork %s is activated before fork %s", named.name, prev.name)
+		}
+
+		named := named
+		prev = &named
+	}
+
+	return nil
 }
 
-func (f *Forks) IsEIP150(block uint64) bool {
-	return f.active(f.EIP150, block)
+func (f *Forks) active(ff *Fork, block, timestamp uint64) bool {
+	if ff == nil {
+		return false
+	}
+
+	return ff.Active(block, timestamp)
 }
 
-func (f *Forks) IsEIP158(block uint64) bool {
-	return f.active(f.EIP158, block)
+// IsMaintenance checks the maintenance fork, which is only activated at a block
+func (f *Forks) IsMaintenance(block uint64) bool {
+	return f.active(f.Maintenance, block, 0)
 }
 
-func (f *Forks) IsEIP155(block uint64) bool {
-	return f.active(f.EIP155, block)
+// IsBlockSize checks the block size fork, which is only activated at a block
+func (f *Forks) IsBlockSize(block uint64) bool {
+	return f.active(f.BlockSize, block, 0)
 }
 
-func (f *Forks) At(block uint64) ForksInTime {
+// At returns the forks active in the block with the number and the timestamp
+func (f *Forks) At(block, timestamp uint64) ForksInTime {
 	return ForksInTime{
-		Homestead:      f.active(f.Homestead, block),
-		Byzantium:      f.active(f.Byzantium, block),
-		Constantinople: f.active(f.Constantinople, block),
-		Petersburg:     f.active(f.Petersburg, block),
-		Istanbul:       f.active(f.Istanbul, block),
-		EIP150:         f.active(f.EIP150, block),
-		EIP158:         f.active(f.EIP158, block),
-		EIP155:         f.active(f.EIP155, block),
+		Homestead:      f.active(f.Homestead, block, timestamp),
+		Byzantium:      f.active(f.Byzantium, block, timestamp),
+		Constantinople: f.active(f.Constantinople, block, timestamp),
+		Petersburg:     f.active(f.Petersburg, block, timestamp),
+		Istanbul:       f.active(f.Istanbul, block, timestamp),
+		EIP150:         f.active(f.EIP150, block, timestamp),
+		EIP158:         f.active(f.EIP158, block, timestamp),
+		EIP155:         f.active(f.EIP155, block, timestamp),
+		EIP2930:        f.active(f.EIP2930, block, timestamp),
+		Multicall:      f.active(f.Multicall, block, timestamp),
+		Decompress:     f.active(f.Decompress, block, timestamp),
+		Maintenance:    f.active(f.Maintenance, block, timestamp),
+		BlockSize:      f.active(f.BlockSize, block, timestamp),
+		EIP3855:        f.active(f.EIP3855, block, timestamp),
+		EIP5656:        f.active(f.EIP5656, block, timestamp),
+		EIP1153:        f.active(f.EIP1153, block, timestamp),
+		EIP3198:        f.active(f.EIP3198, block, timestamp),
+		EIP6780:        f.active(f.EIP6780, block, timestamp),
+		NativeToken:    f.active(f.NativeToken, block, timestamp),
+		Allowlist:      f.active(f.Allowlist, block, timestamp),
+		Sponsorship:    f.active(f.Sponsorship, block, timestamp),
+
+		BlockAccessList: f.active(f.BlockAccessList, block, timestamp),
 	}
 }
 
-type Fork uint64
+// Fork is the activation of a fork, at a block number or, if the timestamp is set,
+// at the first block whose timestamp reaches it
+type Fork struct {
+	Block     uint64
+	Timestamp *uint64
+}
 
 func NewFork(n uint64) *Fork {
-	f := Fork(n)
+	return &Fork{Block: n}
+}
+
+// NewTimestampFork returns a fork activated at the timestamp
+func NewTimestampFork(timestamp uint64) *Fork {
+	return &Fork{Timestamp: &timestamp}
+}
+
+const timestampForkPrefix = "timestamp:"
+
+// ParseFork parses the activation of a fork, a block number or timestamp:<unix time>
+func ParseFork(raw string) (*Fork, error) {
+	if strings.HasPrefix(raw, timestampForkPrefix) {
+		timestamp := strings.TrimPrefix(raw, timestampForkPrefix)
+
+		t, err := strconv.ParseUint(timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fork timestamp %s: %w", timestamp, err)
+		}
+
+		return NewTimestampFork(t), nil
+	}
 
-	return &f
+	block, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fork block %s: %w", raw, err)
+	}
+
+	return NewFork(block), nil
 }
 
-func (f Fork) Active(block uint64) bool {
-	return block >= uint64(f)
+func (f Fork) Active(block, timestamp uint64) bool {
+	if f.Timestamp != nil {
+		return timestamp >= *f.Timestamp
+	}
+
+	return block >= f.Block
+}
+
+// Before checks if the fork is activated before the other one. The block forks are activated before
+// the timestamp forks, so the chains switching to timestamps don't go back to blocks
+func (f Fork) Before(other Fork) bool {
+	switch {
+	case f.Timestamp == nil && other.Timestamp == nil:
+		return f.Block < other.Block
+	case f.Timestamp != nil && other.Timestamp != nil:
+		return *f.Timestamp < *other.Timestamp
+	default:
+		return f.Timestamp == nil
+	}
 }
 
 func (f Fork) Int() *big.Int {
-	return big.NewInt(int64(f))
+	return new(big.Int).SetUint64(f.Block)
+}
+
+// MarshalJSON encodes the block forks as their number, for the chain files written before the timestamp forks
+func (f Fork) MarshalJSON() ([]byte, error) {
+	if f.Timestamp != nil {
+		return json.Marshal(map[string]uint64{"timestamp": *f.Timestamp})
+	}
+
+	return json.Marshal(f.Block)
+}
+
+// UnmarshalJSON decodes a block number, or an object with either the block or the timestamp of the fork
+func (f *Fork) UnmarshalJSON(data []byte) error {
+	var block uint64
+	if err := json.Unmarshal(data, &block); err == nil {
+		*f = Fork{Block: block}
+
+		return nil
+	}
+
+	var dec struct {
+		Block     *uint64 `json:"block"`
+		Timestamp *uint64 `json:"timestamp"`
+	}
+
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	switch {
+	case dec.Block != nil && dec.Timestamp != nil:
+		return errors.New("fork activated at both a block and a timestamp")
+	case dec.Timestamp != nil:
+		*f = Fork{Timestamp: dec.Timestamp}
+	case dec.Block != nil:
+		*f = Fork{Block: *dec.Block}
+	default:
+		return errors.New("fork activation expected")
+	}
+
+	return nil
 }
 
 type ForksInTime struct {
@@ -114,7 +423,21 @@ type ForksInTime struct {
 	Istanbul,
 	EIP150,
 	EIP158,
-	EIP155 bool
+	EIP155,
+	EIP2930,
+	Multicall,
+	Decompress,
+	Maintenance,
+	BlockSize,
+	EIP3855,
+	EIP5656,
+	EIP1153,
+	EIP3198,
+	EIP6780,
+	NativeToken,
+	Allowlist,
+	Sponsorship,
+	BlockAccessList bool
 }
 
 var AllForksEnabled = &Forks{
@@ -126,4 +449,15 @@ var AllForksEnabled = &Forks{
 	Constantinople: NewFork(0),
 	Petersburg:     NewFork(0),
 	Istanbul:       NewFork(0),
+	EIP2930:        NewFork(0),
+	Multicall:      NewFork(0),
+	Decompress:     NewFork(0),
+	Maintenance:    NewFork(0),
+	BlockSize:      NewFork(0),
+	EIP3855:        NewFork(0),
+	EIP5656:        NewFork(0),
+	EIP1153:        NewFork(0),
+	EIP3198:        NewFork(0),
+	EIP6780:        NewFork(0),
+	Sponsorship:    NewFork(0),
 }
@@ -41,7 +41,7 @@ func TestParamsForksInTime(t *testing.T) {
 		EIP150:         NewFork(2000),
 	}
 
-	ff := f.At(1000)
+	ff := f.At(1000, 0)
 
 	expect := func(name string, found bool, expect bool) {
 		if expect != found {
@@ -54,3 +54,144 @@ func TestParamsForksInTime(t *testing.T) {
 	expect("constantinople", ff.Constantinople, false)
 	expect("eip150", ff.EIP150, false)
 }
+
+func TestParamsForkJSON(t *testing.T) {
+	cases := []struct {
+		input  string
+		output *Fork
+	}{
+		{`1000`, NewFork(1000)},
+		{`{"block": 1000}`, NewFork(1000)},
+		{`{"timestamp": 1700000000}`, NewTimestampFork(1700000000)},
+		{`{"block": 1, "timestamp": 1700000000}`, nil},
+		{`{}`, nil},
+	}
+
+	for _, c := range cases {
+		var dec *Fork
+		if err := json.Unmarshal([]byte(c.input), &dec); err != nil {
+			if c.output != nil {
+				t.Fatal(err)
+			}
+
+			continue
+		}
+
+		if c.output == nil {
+			t.Fatalf("expected an error for %s", c.input)
+		}
+
+		if !reflect.DeepEqual(dec, c.output) {
+			t.Fatalf("bad fork for %s", c.input)
+		}
+
+		// the block forks are encoded as their number
+		data, err := json.Marshal(dec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dec.Timestamp == nil && string(data) != "1000" {
+			t.Fatalf("bad encoding %s", data)
+		}
+	}
+}
+
+func TestParamsForksTimestamp(t *testing.T) {
+	f := Forks{
+		Homestead: NewFork(0),
+		Istanbul:  NewTimestampFork(1000),
+	}
+
+	if ff := f.At(5, 999); !ff.Homestead || ff.Istanbul {
+		t.Fatal("istanbul should not be active before its timestamp")
+	}
+
+	if ff := f.At(5, 1000); !ff.Istanbul {
+		t.Fatal("istanbul should be active at its timestamp")
+	}
+}
+
+func TestParamsForksValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		forks *Forks
+		valid bool
+	}{
+		{
+			name:  "all forks enabled",
+			forks: AllForksEnabled,
+			valid: true,
+		},
+		{
+			name: "forks in order with gaps",
+			forks: &Forks{
+				Homestead: NewFork(10),
+				Byzantium: NewFork(10),
+				Istanbul:  NewTimestampFork(1000),
+				Multicall: NewFork(0),
+			},
+			valid: true,
+		},
+		{
+			name: "fork activated before the previous one",
+			forks: &Forks{
+				Homestead: NewFork(10),
+				Byzantium: NewFork(5),
+			},
+		},
+		{
+			name: "block fork after a timestamp fork",
+			forks: &Forks{
+				Byzantium: NewTimestampFork(1000),
+				Istanbul:  NewFork(5),
+			},
+		},
+		{
+			name: "maintenance fork at a timestamp",
+			forks: &Forks{
+				Maintenance: NewTimestampFork(1000),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if err := c.forks.Validate(); (err == nil) != c.valid {
+			t.Fatalf("%s: unexpected validation result %v", c.name, err)
+		}
+	}
+}
+
+func TestParamsForksOverride(t *testing.T) {
+	f := Forks{}
+
+	fork, err := ParseFork("timestamp:1700000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Set("istanbul", fork); err != nil {
+		t.Fatal(err)
+	}
+
+	if fork, err = ParseFork("100"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the names are matched case insensitively
+	if err := f.Set("eip150", fork); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(f, Forks{Istanbul: NewTimestampFork(1700000000), EIP150: NewFork(100)}) {
+		t.Fatal("bad overrides")
+	}
+
+	if err := f.Set("unknown", fork); err == nil {
+		t.Fatal("expected an error for an unknown fork")
+	}
+
+	if _, err := ParseFork("timestamp:x"); err == nil {
+		t.Fatal("expected an error for an invalid timestamp")
+	}
+}
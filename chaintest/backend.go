@@ -0,0 +1,667 @@
+package chaintest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// ChainID is the chain id of the backend
+	ChainID = 100
+
+	// DefaultGasLimit is the block gas limit used if none is given
+	DefaultGasLimit = 30000000
+
+	// blockPeriod is the time in seconds between two consecutive blocks
+	blockPeriod = 1
+
+	// source is the source of the blocks written by the backend
+	source = "chaintest"
+)
+
+var (
+	ErrUnknownSnapshot = errors.New("snapshot is ahead of the chain")
+)
+
+// Backend is an in-memory chain implementing the store of the JSON-RPC endpoints,
+// so applications can be tested against the Edge execution without running a node.
+// Every transaction is mined into its own block as soon as it is added, the chain can be
+// reverted to a snapshot, and the timestamps of the next blocks can be moved forward
+type Backend struct {
+	logger hclog.Logger
+	config *chain.Chain
+	signer crypto.TxSigner
+
+	// the trie nodes are content-addressed, so the state is shared by all the chains
+	state   state.State
+	storage itrie.Storage
+
+	lock       sync.RWMutex
+	executor   *state.Executor
+	blockchain *blockchain.Blockchain
+	timeOffset uint64 // seconds added to the timestamp of the next block
+
+	stream eventStream
+}
+
+// NewBackend creates an in-memory chain, whose genesis allocates the accounts.
+// The gas limit of the blocks is DefaultGasLimit if 0
+func NewBackend(alloc map[types.Address]*chain.GenesisAccount, gasLimit uint64) (*Backend, error) {
+	if gasLimit == 0 {
+		gasLimit = DefaultGasLimit
+	}
+
+	b := &Backend{
+		logger: hclog.NewNullLogger(),
+		config: &chain.Chain{
+			Name: source,
+			Genesis: &chain.Genesis{
+				GasLimit:   gasLimit,
+				Difficulty: 1,
+				Timestamp:  uint64(time.Now().Unix()),
+				Alloc:      alloc,
+			},
+			Params: &chain.Params{
+				ChainID: ChainID,
+				Forks:   chain.AllForksEnabled,
+				Engine:  map[string]interface{}{source: nil},
+			},
+		},
+		signer:  crypto.NewEIP155Signer(ChainID),
+		storage: itrie.NewMemoryStorage(),
+	}
+
+	b.state = itrie.NewState(b.storage)
+
+	executor, bc, err := b.newChain()
+	if err != nil {
+		return nil, err
+	}
+
+	b.executor, b.blockchain = executor, bc
+
+	return b, nil
+}
+
+// newChain creates a chain holding only the genesis block
+func (b *Backend) newChain() (*state.Executor, *blockchain.Blockchain, error) {
+	executor := state.NewExecutor(b.config.Params, b.state, b.logger)
+
+	precompiles, err := precompiled.NewStatefulContracts(b.config.Params.Precompiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	executor.Precompiles = precompiles
+	b.config.Genesis.StateRoot = executor.WriteGenesis(b.config.Genesis.Alloc)
+
+	bc, err := blockchain.NewBlockchain(b.logger, nil, b.config, verifier{}, executor, b.signer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	executor.GetHash = bc.GetHashHelper
+
+	if err := bc.ComputeGenesis(); err != nil {
+		return nil, nil, err
+	}
+
+	return executor, bc, nil
+}
+
+// Signer returns the signer of the transactions added to the backend
+func (b *Backend) Signer() crypto.TxSigner {
+	return b.signer
+}
+
+// Mine mines an empty block
+func (b *Backend) Mine() (*types.Block, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.mine(nil)
+}
+
+// AddTx mines the signed transaction into a new block.
+// An error is returned and no block is mined if the transaction can't be included
+func (b *Backend) AddTx(tx *types.Transaction) error {
+	from, err := b.signer.Sender(tx)
+	if err != nil {
+		return err
+	}
+
+	tx.From = from
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, err = b.mine([]*types.Transaction{tx})
+
+	return err
+}
+
+func (b *Backend) mine(txs []*types.Transaction) (*types.Block, error) {
+	parent := b.blockchain.Header()
+
+	header := &types.Header{
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		Miner:      types.ZeroAddress.Bytes(),
+		GasLimit:   parent.GasLimit,
+		Timestamp:  parent.Timestamp + blockPeriod + b.timeOffset,
+		Difficulty: 1,
+	}
+
+	transition, err := b.executor.BeginTxn(parent.StateRoot, header, types.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		if err := transition.Write(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	_, root := transition.Commit()
+
+	header.StateRoot = root
+	header.GasUsed = transition.TotalGas()
+
+	block := consensus.BuildBlock(consensus.BuildBlockParams{
+		Header:   header,
+		Txns:     txs,
+		Receipts: transition.Receipts(),
+	})
+
+	if err := b.blockchain.VerifyFinalizedBlock(block); err != nil {
+		return nil, err
+	}
+
+	if err := b.blockchain.WriteBlock(block, source); err != nil {
+		return nil, err
+	}
+
+	b.timeOffset = 0
+
+	b.stream.push(&blockchain.Event{
+		Type:     blockchain.EventHead,
+		NewChain: []*types.Header{block.Header.Copy()},
+		Source:   source,
+	})
+
+	return block, nil
+}
+
+// AdjustTime moves the timestamp of the next block forward, along with the ones of the blocks after it
+func (b *Backend) AdjustTime(d time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.timeOffset += uint64(d / time.Second)
+}
+
+// Snapshot returns the id of the snapshot of the current chain
+func (b *Backend) Snapshot() uint64 {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.blockchain.Header().Number
+}
+
+// Revert drops the blocks mined after the snapshot, the subscribers get a reorg event for them
+func (b *Backend) Revert(snapshot uint64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	head := b.blockchain.Header()
+	if snapshot > head.Number {
+		return ErrUnknownSnapshot
+	} else if snapshot == head.Number {
+		return nil
+	}
+
+	// blocks can't be removed from a chain, so the remaining ones are written into a new one
+	executor, bc, err := b.newChain()
+	if err != nil {
+		return err
+	}
+
+	evnt := &blockchain.Event{
+		Type:   blockchain.EventReorg,
+		Source: source,
+	}
+
+	for number := uint64(1); number <= head.Number; number++ {
+		block, ok := b.blockchain.GetBlockByNumber(number, true)
+		if !ok {
+			return fmt.Errorf("block %d not found", number)
+		}
+
+		if number > snapshot {
+			evnt.AddOldHeader(block.Header)
+
+			continue
+		}
+
+		if err := bc.WriteBlock(block, source); err != nil {
+			return err
+		}
+	}
+
+	evnt.AddNewHeader(bc.Header())
+
+	if err := b.blockchain.Close(); err != nil {
+		b.logger.Warn("failed to close the reverted chain", "err", err)
+	}
+
+	b.executor, b.blockchain = executor, bc
+	b.timeOffset = 0
+
+	b.stream.push(evnt)
+
+	return nil
+}
+
+// current returns the current chain and its executor
+func (b *Backend) current() (*blockchain.Blockchain, *state.Executor) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.blockchain, b.executor
+}
+
+// BLOCKCHAIN STORE //
+
+// Header returns the header of the latest block
+func (b *Backend) Header() *types.Header {
+	bc, _ := b.current()
+
+	return bc.Header()
+}
+
+// FinalizedHeader returns the header of the latest block, as the mined blocks are only dropped by a revert
+func (b *Backend) FinalizedHeader() *types.Header {
+	return b.Header()
+}
+
+// GetHeaderByNumber returns the header of the block with the given number
+func (b *Backend) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	bc, _ := b.current()
+
+	return bc.GetHeaderByNumber(number)
+}
+
+// GetBlockByHash returns the block with the given hash
+func (b *Backend) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	bc, _ := b.current()
+
+	return bc.GetBlockByHash(hash, full)
+}
+
+// GetBlockByNumber returns the block with the given number
+func (b *Backend) GetBlockByNumber(number uint64, full bool) (*types.Block, bool) {
+	bc, _ := b.current()
+
+	return bc.GetBlockByNumber(number, full)
+}
+
+// GetTxCountByHash returns the number of transactions in the block with the given hash
+func (b *Backend) GetTxCountByHash(hash types.Hash) (int, bool) {
+	bc, _ := b.current()
+
+	return bc.GetTxCountByHash(hash)
+}
+
+// ReadTxLookup returns the hash of the block including the transaction
+func (b *Backend) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	bc, _ := b.current()
+
+	return bc.ReadTxLookup(hash)
+}
+
+// ReadTxLookupIndex returns the hash of the block including the transaction, and its index in the block
+func (b *Backend) ReadTxLookupIndex(hash types.Hash) (types.Hash, uint64, bool) {
+	bc, _ := b.current()
+
+	return bc.ReadTxLookupIndex(hash)
+}
+
+// GetReceiptsByHash returns the receipts of the block with the given hash
+func (b *Backend) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	bc, _ := b.current()
+
+	return bc.GetReceiptsByHash(hash)
+}
+
+// GetReceipt returns the receipt of the transaction at the index in the block with the given hash
+func (b *Backend) GetReceipt(hash types.Hash, index uint64) (*types.Receipt, error) {
+	bc, _ := b.current()
+
+	return bc.GetReceipt(hash, index)
+}
+
+// GetAvgGasPrice returns the average gas price of the mined transactions
+func (b *Backend) GetAvgGasPrice() *big.Int {
+	bc, _ := b.current()
+
+	return bc.GetAvgGasPrice()
+}
+
+// IterateHeaders calls the handler for the headers in the range, until it returns false
+func (b *Backend) IterateHeaders(from, to uint64, handler func(*types.Header) bool) error {
+	bc, _ := b.current()
+
+	return bc.IterateHeaders(from, to, handler)
+}
+
+// GetBloomMatches returns the numbers of the blocks in the range which possibly match the bloom filter
+func (b *Backend) GetBloomMatches(from, to uint64, filter [][][]byte) ([]uint64, uint64) {
+	bc, _ := b.current()
+
+	return bc.GetBloomMatches(from, to, filter)
+}
+
+// GetAccumulatorCheckpoint returns the root of the header accumulator at the checkpoint
+func (b *Backend) GetAccumulatorCheckpoint(checkpoint uint64) (*blockchain.AccumulatorCheckpoint, error) {
+	bc, _ := b.current()
+
+	return bc.GetAccumulatorCheckpoint(checkpoint)
+}
+
+// GetLatestAccumulatorCheckpoint returns the root of the header accumulator at the last committed checkpoint
+func (b *Backend) GetLatestAccumulatorCheckpoint() (*blockchain.AccumulatorCheckpoint, error) {
+	bc, _ := b.current()
+
+	return bc.GetLatestAccumulatorCheckpoint()
+}
+
+// GetAncestryProof returns the proof that the block is an ancestor of the head of the checkpoint
+func (b *Backend) GetAncestryProof(number, checkpoint uint64) (*blockchain.AncestryProof, error) {
+	bc, _ := b.current()
+
+	return bc.GetAncestryProof(number, checkpoint)
+}
+
+// SubscribeEvents subscribes to the blocks mined and reverted by the backend
+func (b *Backend) SubscribeEvents() blockchain.Subscription {
+	return b.stream.subscribe()
+}
+
+// GetSyncProgression returns nil, the backend is never syncing
+func (b *Backend) GetSyncProgression() *progress.Progression {
+	return nil
+}
+
+// STATE STORE //
+
+func (b *Backend) getState(root types.Hash, slot []byte) ([]byte, error) {
+	// the values in the trie are the hashed objects of the keys
+	key := keccak.Keccak256(nil, slot)
+
+	snap, err := b.state.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := snap.Get(key)
+	if !ok {
+		return nil, jsonrpc.ErrStateNotFound
+	}
+
+	return result, nil
+}
+
+// GetAccount returns the account at the state root
+func (b *Backend) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	obj, err := b.getState(root, addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var account state.Account
+	if err := account.UnmarshalRlp(obj); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// GetStorage returns the value of the storage slot at the state root
+func (b *Backend) GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error) {
+	account, err := b.GetAccount(root, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.getState(account.Root, slot.Bytes())
+}
+
+// GetProof returns the trie nodes on the path of the key in the trie with the given root
+func (b *Backend) GetProof(root types.Hash, key []byte) ([][]byte, error) {
+	return b.state.Prove(root, keccak.Keccak256(nil, key))
+}
+
+// GetCode returns the code with the given hash
+func (b *Backend) GetCode(hash types.Hash) ([]byte, error) {
+	code, ok := b.state.GetCode(hash)
+	if !ok {
+		return nil, fmt.Errorf("unable to fetch code")
+	}
+
+	return code, nil
+}
+
+// GetForksInTime returns the active forks in the given block
+func (b *Backend) GetForksInTime(header *types.Header) chain.ForksInTime {
+	_, executor := b.current()
+
+	return executor.GetForksInTime(header)
+}
+
+// EXECUTION //
+
+// ApplyTxn executes the transaction on top of the state of the header, without mining it
+func (b *Backend) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+) (*runtime.ExecutionResult, error) {
+	return b.applyTxn(header, txn, override, nil)
+}
+
+// TraceTxn executes the transaction on top of the state of the header with the tracer, without mining it
+func (b *Backend) TraceTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	return b.applyTxn(header, txn, nil, tracer)
+}
+
+func (b *Backend) applyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	_, executor := b.current()
+
+	transition, err := executor.BeginTxn(header.StateRoot, header, types.BytesToAddress(header.Miner))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := override.Apply(transition.Txn()); err != nil {
+		return nil, err
+	}
+
+	transition.SetTracer(tracer)
+
+	return transition.Apply(txn)
+}
+
+// Simulate executes the calls of the blocks in sequence on top of the state of the parent, without mining them
+func (b *Backend) Simulate(
+	parent *types.Header,
+	blocks []*state.SimulatedBlock,
+) ([][]*state.SimulatedCall, error) {
+	_, executor := b.current()
+
+	return executor.Simulate(parent, blocks)
+}
+
+// GetStorageChanges returns the modifications of the watched storage slots made by the given block
+func (b *Backend) GetStorageChanges(
+	header *types.Header,
+	watches state.StorageWatches,
+) ([]*state.StorageChange, error) {
+	bc, executor := b.current()
+
+	parent, ok := bc.GetHeaderByHash(header.ParentHash)
+	if !ok {
+		return nil, blockchain.ErrParentNotFound
+	}
+
+	block, ok := bc.GetBlockByHash(header.Hash, true)
+	if !ok {
+		return nil, fmt.Errorf("unable to fetch block %s", header.Hash)
+	}
+
+	return executor.GetStorageChanges(parent.StateRoot, block, types.BytesToAddress(header.Miner), watches)
+}
+
+// GetAccountChanges returns the watched accounts whose balance, nonce or code are changed by the given block
+func (b *Backend) GetAccountChanges(
+	header *types.Header,
+	addrs []types.Address,
+) ([]*state.AccountChange, error) {
+	bc, executor := b.current()
+
+	parent, ok := bc.GetHeaderByHash(header.ParentHash)
+	if !ok {
+		return nil, blockchain.ErrParentNotFound
+	}
+
+	return executor.GetAccountChanges(parent.StateRoot, header.StateRoot, addrs)
+}
+
+// TraceBlock re-executes the block on top of its parent state,
+// tracing every transaction with the tracer returned by getTracer
+func (b *Backend) TraceBlock(
+	block *types.Block,
+	getTracer func(idx int, tx *types.Transaction) runtime.Tracer,
+) error {
+	bc, executor := b.current()
+
+	parent, ok := bc.GetHeaderByHash(block.ParentHash())
+	if !ok {
+		return blockchain.ErrParentNotFound
+	}
+
+	return executor.TraceBlock(parent.StateRoot, block, types.BytesToAddress(block.Header.Miner), getTracer)
+}
+
+// GetBlockWitness re-executes the block on top of its parent state, recording the state it reads
+func (b *Backend) GetBlockWitness(block *types.Block) (*state.Witness, error) {
+	bc, executor := b.current()
+
+	parent, ok := bc.GetHeaderByHash(block.ParentHash())
+	if !ok {
+		return nil, blockchain.ErrParentNotFound
+	}
+
+	return executor.Witness(
+		itrie.NewWitnessState(b.storage),
+		parent.StateRoot,
+		block,
+		types.BytesToAddress(block.Header.Miner),
+	)
+}
+
+// GetOpcodeStats returns the opcode stats of the block, if the executor records them
+func (b *Backend) GetOpcodeStats(hash types.Hash) (*tracer.OpcodeStats, bool) {
+	_, executor := b.current()
+	if executor.OpcodeStats == nil {
+		return nil, false
+	}
+
+	return executor.OpcodeStats.Get(hash)
+}
+
+// TXPOOL STORE //
+
+// GetNonce returns the next nonce of the account, there are no pending transactions
+func (b *Backend) GetNonce(addr types.Address) uint64 {
+	account, err := b.GetAccount(b.Header().StateRoot, addr)
+	if err != nil {
+		return 0
+	}
+
+	return account.Nonce
+}
+
+// GetPendingTx returns false, the transactions are mined as soon as they are added
+func (b *Backend) GetPendingTx(types.Hash) (*types.Transaction, bool) {
+	return nil, false
+}
+
+// GetTxs returns no transactions, the transactions are mined as soon as they are added
+func (b *Backend) GetTxs(bool) (map[types.Address][]*types.Transaction, map[types.Address][]*types.Transaction) {
+	return map[types.Address][]*types.Transaction{}, map[types.Address][]*types.Transaction{}
+}
+
+// GetCapacity returns no capacity, the transactions are mined as soon as they are added
+func (b *Backend) GetCapacity() (uint64, uint64) {
+	return 0, 0
+}
+
+// SubscribeTxEvents returns a channel which never receives events, there is no pool
+func (b *Backend) SubscribeTxEvents(...txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	return make(chan *txpoolProto.TxPoolEvent), func() {}
+}
+
+// NETWORK STORE //
+
+// GetPeers returns 0, the backend has no peers
+func (b *Backend) GetPeers() int {
+	return 0
+}
+
+// verifier accepts the blocks mined by the backend
+type verifier struct{}
+
+func (verifier) VerifyHeader(*types.Header) error {
+	return nil
+}
+
+func (verifier) ProcessHeaders([]*types.Header) error {
+	return nil
+}
+
+func (verifier) GetBlockCreator(header *types.Header) (types.Address, error) {
+	return types.BytesToAddress(header.Miner), nil
+}
+
+func (verifier) PreCommitState(*types.Header, *state.Transition) error {
+	return nil
+}
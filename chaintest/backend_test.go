@@ -0,0 +1,188 @@
+package chaintest
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	var (
+		sender    = crypto.PubKeyToAddress(&key.PublicKey)
+		recipient = types.StringToAddress("abcd")
+	)
+
+	backend, err := NewBackend(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1e18)},
+	}, 0)
+	require.NoError(t, err)
+
+	// the backend serves the JSON-RPC endpoints
+	var _ jsonrpc.JSONRPCStore = backend
+
+	sub := backend.SubscribeEvents()
+	defer sub.Close()
+
+	snapshot := backend.Snapshot()
+
+	transfer := func(nonce uint64) error {
+		tx, err := backend.Signer().SignTx(&types.Transaction{
+			Nonce:    nonce,
+			To:       &recipient,
+			Value:    big.NewInt(1000),
+			Gas:      21000,
+			GasPrice: big.NewInt(1),
+		}, key)
+		require.NoError(t, err)
+
+		return backend.AddTx(tx)
+	}
+
+	// the transaction is mined instantly
+	require.NoError(t, transfer(0))
+
+	head := backend.Header()
+	assert.Equal(t, uint64(1), head.Number)
+	assert.Equal(t, uint64(1), backend.GetNonce(sender))
+
+	account, err := backend.GetAccount(head.StateRoot, recipient)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), account.Balance)
+
+	evnt := sub.GetEvent()
+	assert.Equal(t, blockchain.EventHead, evnt.Type)
+	assert.Equal(t, head.Hash, evnt.Header().Hash)
+
+	// a transaction which can't be included doesn't mine a block
+	assert.Error(t, transfer(0))
+	assert.Equal(t, uint64(1), backend.Header().Number)
+
+	// the timestamps move forward
+	backend.AdjustTime(time.Hour)
+
+	block, err := backend.Mine()
+	require.NoError(t, err)
+	assert.Equal(t, head.Timestamp+blockPeriod+3600, block.Header.Timestamp)
+
+	sub.GetEvent()
+
+	// the mined blocks are dropped by the revert
+	require.NoError(t, backend.Revert(snapshot))
+	assert.Equal(t, uint64(0), backend.Header().Number)
+	assert.Equal(t, uint64(0), backend.GetNonce(sender))
+
+	_, ok := backend.ReadTxLookup(block.Hash())
+	assert.False(t, ok)
+
+	evnt = sub.GetEvent()
+	assert.Equal(t, blockchain.EventReorg, evnt.Type)
+	assert.Len(t, evnt.OldChain, 2)
+
+	assert.ErrorIs(t, backend.Revert(5), ErrUnknownSnapshot)
+
+	// the chain goes on from the snapshot, mining the same block again
+	require.NoError(t, transfer(0))
+	assert.Equal(t, head.Hash, backend.Header().Hash)
+}
+
+func TestBackend_RevertKeepsBlocks(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewBackend(nil, 0)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := backend.Mine()
+		require.NoError(t, err)
+	}
+
+	kept, ok := backend.GetHeaderByNumber(2)
+	require.True(t, ok)
+
+	require.NoError(t, backend.Revert(2))
+
+	header := backend.Header()
+	assert.Equal(t, kept.Hash, header.Hash)
+
+	_, ok = backend.GetHeaderByNumber(3)
+	assert.False(t, ok)
+}
+
+func TestBackend_Simulate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sender    = types.StringToAddress("abc1")
+		recipient = types.StringToAddress("abc2")
+		poor      = types.StringToAddress("abc3")
+	)
+
+	backend, err := NewBackend(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1e18)},
+	}, 0)
+	require.NoError(t, err)
+
+	transfer := func(from types.Address) *types.Transaction {
+		return &types.Transaction{
+			From:     from,
+			To:       &recipient,
+			Value:    big.NewInt(1000),
+			GasPrice: big.NewInt(0),
+			Input:    []byte{},
+		}
+	}
+
+	parent := backend.Header()
+	blocks := []*state.SimulatedBlock{
+		{
+			Header: &types.Header{Number: 1, GasLimit: DefaultGasLimit},
+			Calls:  []*types.Transaction{transfer(sender), transfer(sender)},
+		},
+		{
+			Header: &types.Header{Number: 5, GasLimit: DefaultGasLimit},
+			Calls:  []*types.Transaction{transfer(sender), transfer(poor)},
+		},
+	}
+
+	res, err := backend.Simulate(parent, blocks)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	// the nonces of the sender follow the previous calls
+	for _, call := range append(res[0], res[1][0]) {
+		require.NoError(t, call.Err)
+		assert.False(t, call.Result.Failed())
+		assert.Equal(t, uint64(21000), call.Result.GasUsed)
+	}
+
+	require.Error(t, res[1][1].Err)
+	assert.Contains(t, res[1][1].Err.Error(), state.ErrNotEnoughFunds.Error())
+
+	// the simulated blocks are chained
+	assert.Equal(t, parent.Hash, blocks[0].Header.ParentHash)
+	assert.Equal(t, blocks[0].Header.Hash, blocks[1].Header.ParentHash)
+
+	// nothing is committed
+	assert.Equal(t, uint64(0), backend.Header().Number)
+	assert.Equal(t, uint64(0), backend.GetNonce(sender))
+
+	// the blocks must move forward
+	_, err = backend.Simulate(parent, []*state.SimulatedBlock{
+		{Header: &types.Header{Number: 0}},
+	})
+	assert.ErrorIs(t, err, state.ErrSimulatedBlockNotAhead)
+}
@@ -0,0 +1,84 @@
+package chaintest
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+)
+
+// eventBufferSize is the number of events a subscriber can lag behind before they are dropped
+const eventBufferSize = 64
+
+// subscription is a blockchain event subscription of the backend,
+// it outlives the chains replaced by the reverts
+type subscription struct {
+	eventCh   chan *blockchain.Event
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+// GetEventCh returns the event channel
+func (s *subscription) GetEventCh() chan *blockchain.Event {
+	return s.eventCh
+}
+
+// GetEvent returns the next event, or nil once the subscription is closed (BLOCKING)
+func (s *subscription) GetEvent() *blockchain.Event {
+	select {
+	case evnt := <-s.eventCh:
+		return evnt
+	case <-s.closeCh:
+		return nil
+	}
+}
+
+// Close closes the subscription
+func (s *subscription) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+	})
+}
+
+// eventStream dispatches the events of the backend to the subscriptions
+type eventStream struct {
+	lock          sync.Mutex
+	subscriptions []*subscription
+}
+
+func (e *eventStream) subscribe() *subscription {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	sub := &subscription{
+		eventCh: make(chan *blockchain.Event, eventBufferSize),
+		closeCh: make(chan struct{}),
+	}
+
+	e.subscriptions = append(e.subscriptions, sub)
+
+	return sub
+}
+
+func (e *eventStream) push(evnt *blockchain.Event) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	active := e.subscriptions[:0]
+
+	for _, sub := range e.subscriptions {
+		select {
+		case <-sub.closeCh:
+			continue
+		default:
+		}
+
+		select {
+		case sub.eventCh <- evnt:
+		default:
+		}
+
+		active = append(active, sub)
+	}
+
+	e.subscriptions = active
+}
@@ -0,0 +1,25 @@
+package chain
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/chain/export"
+	"github.com/0xPolygon/polygon-edge/command/server"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	chainCmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Top level command for exporting and importing the blocks of the chain. Only accepts subcommands.",
+	}
+
+	registerSubcommands(chainCmd)
+
+	return chainCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		export.GetCommand(),
+		server.GetImportCommand(),
+	)
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"github.com/0xPolygon/polygon-edge/archive"
+	"github.com/0xPolygon/polygon-edge/command"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use: "export",
+		Short: "Exports the canonical blocks of a stopped node to an RLP file, or to era1 files of 8192 blocks, " +
+			"which the chain import command restores without syncing from the peers",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(exportCmd)
+	helper.SetRequiredFlags(exportCmd, params.getRequiredFlags())
+
+	return exportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dbHelper.DataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.ancientDir,
+		ancientDirFlag,
+		"",
+		"the directory of the freezer the old blocks are moved to, if the node is started with one",
+	)
+
+	cmd.Flags().StringVar(
+		&params.format,
+		formatFlag,
+		archive.FormatRLP,
+		"the format of the export, rlp for a single file or era1 for a directory of files",
+	)
+
+	cmd.Flags().StringVar(
+		&params.out,
+		outFlag,
+		"",
+		"the file of the rlp export, or the directory of the era1 files",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.from,
+		fromFlag,
+		0,
+		"the first block to export, era1 files begin at the first block of its epoch",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.to,
+		toFlag,
+		0,
+		"the last block to export, the head if not set",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.receipts,
+		receiptsFlag,
+		false,
+		"export the receipts along with the blocks of an rlp export, era1 files always hold them",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.resume,
+		resumeFlag,
+		false,
+		"continue an interrupted export after the blocks it already holds",
+	)
+
+	cmd.Flags().StringVar(
+		&params.name,
+		nameFlag,
+		"edge",
+		"the network name prefixing the era1 files",
+	)
+
+	_ = cmd.MarkFlagDirname(dbHelper.DataDirFlag)
+	_ = cmd.MarkFlagDirname(ancientDirFlag)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.export(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
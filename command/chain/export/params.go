@@ -0,0 +1,234 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/archive"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	ancientDirFlag = "datadir.ancient"
+	formatFlag     = "format"
+	outFlag        = "out"
+	fromFlag       = "from"
+	toFlag         = "to"
+	receiptsFlag   = "receipts"
+	resumeFlag     = "resume"
+	nameFlag       = "name"
+)
+
+const (
+	// progressInterval is the interval at which the progress of the export is reported
+	progressInterval = 5 * time.Second
+)
+
+var (
+	params = &exportParams{}
+
+	errUnknownFormat   = errors.New("unknown export format")
+	errInvalidRange    = errors.New("the first block is after the last one")
+	errReceiptsInEra1  = errors.New("the era1 files always hold the receipts")
+	errNoBlockchain    = errors.New("no blockchain database in the data directory")
+	errBeyondHead      = errors.New("the last block is after the head")
+	errResumeFinished  = errors.New("the export already holds the last block")
+	errEmptyBlockchain = errors.New("the blockchain has no head")
+	errNoAncientDir    = errors.New("the ancient directory of the freezer must be set")
+)
+
+type exportParams struct {
+	dataDir    string
+	ancientDir string
+	format     string
+	out        string
+	from       uint64
+	to         uint64
+	receipts   bool
+	resume     bool
+	name       string
+
+	exported uint64
+	files    []string
+}
+
+func (p *exportParams) getRequiredFlags() []string {
+	return []string{
+		dbHelper.DataDirFlag,
+		outFlag,
+	}
+}
+
+func (p *exportParams) validateFlags() error {
+	switch p.format {
+	case archive.FormatRLP:
+	case archive.FormatEra1:
+		if p.receipts {
+			return errReceiptsInEra1
+		}
+	default:
+		return fmt.Errorf("%w %s, expected %s or %s", errUnknownFormat, p.format, archive.FormatRLP, archive.FormatEra1)
+	}
+
+	if p.to != 0 && p.from > p.to {
+		return errInvalidRange
+	}
+
+	return nil
+}
+
+// openBlockchain opens the blockchain storage of the stopped node for reading
+func (p *exportParams) openBlockchain() (storage.Storage, error) {
+	path := filepath.Join(p.dataDir, "blockchain")
+
+	engine, err := kvdb.DetectEngine(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if engine == "" {
+		return nil, fmt.Errorf("%w %s", errNoBlockchain, p.dataDir)
+	}
+
+	db, err := kvdb.Open(engine, path, kvdb.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.ancientDir == "" {
+		moved, err := storage.ReadDatabaseAncientHead(db)
+		if err == nil && moved > 0 {
+			err = fmt.Errorf("%w, the first %d blocks were moved to it", errNoAncientDir, moved)
+		}
+
+		if err != nil {
+			_ = db.Close()
+
+			return nil, err
+		}
+
+		return storage.NewDatabaseStorage(hclog.NewNullLogger(), db), nil
+	}
+
+	freezer, err := storage.OpenFreezer(p.ancientDir)
+	if err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to open the freezer: %w", err)
+	}
+
+	// no block is written by the export, so none is moved to the freezer
+	chain, err := storage.NewAncientDatabaseStorage(hclog.NewNullLogger(), db, freezer, math.MaxUint64)
+	if err != nil {
+		_ = freezer.Close()
+		_ = db.Close()
+
+		return nil, err
+	}
+
+	return chain, nil
+}
+
+func (p *exportParams) export() error {
+	chain, err := p.openBlockchain()
+	if err != nil {
+		return err
+	}
+
+	defer chain.Close()
+
+	head, ok := chain.ReadHeadNumber()
+	if !ok {
+		return errEmptyBlockchain
+	}
+
+	if p.to == 0 {
+		p.to = head
+	} else if p.to > head {
+		return fmt.Errorf("%w %d", errBeyondHead, head)
+	}
+
+	if p.from > p.to {
+		return errInvalidRange
+	}
+
+	if p.format == archive.FormatEra1 {
+		p.from -= p.from % archive.Era1EpochSize
+		p.files, err = archive.ExportEra1(p.out, p.name, chain, p.from, p.to, p.resume, p.newProgress())
+
+		return err
+	}
+
+	return p.exportRLP(chain)
+}
+
+func (p *exportParams) exportRLP(chain archive.ChainReader) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if p.resume {
+		flags = os.O_RDWR | os.O_CREATE
+	}
+
+	f, err := os.OpenFile(p.out, flags, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if p.resume {
+		next, err := archive.ResumeRLP(f)
+		if err != nil {
+			return err
+		}
+
+		if next > p.to {
+			return errResumeFinished
+		}
+
+		if next > p.from {
+			p.from = next
+		}
+	}
+
+	if err := archive.ExportRLP(f, chain, p.from, p.to, p.receipts, p.newProgress()); err != nil {
+		return err
+	}
+
+	p.files = []string{p.out}
+
+	return f.Sync()
+}
+
+// newProgress returns the progress of the export, reported on the standard error periodically
+func (p *exportParams) newProgress() func(number uint64) {
+	lastReport := time.Now()
+
+	return func(number uint64) {
+		p.exported++
+
+		if time.Since(lastReport) < progressInterval {
+			return
+		}
+
+		lastReport = time.Now()
+
+		_, _ = fmt.Fprintf(os.Stderr, "exported block %d of %d\n", number, p.to)
+	}
+}
+
+func (p *exportParams) getResult() *ExportResult {
+	return &ExportResult{
+		Format:   p.format,
+		From:     p.from,
+		To:       p.to,
+		Exported: p.exported,
+		Files:    p.files,
+	}
+}
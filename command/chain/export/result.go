@@ -0,0 +1,37 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ExportResult struct {
+	Format   string   `json:"format"`
+	From     uint64   `json:"from"`
+	To       uint64   `json:"to"`
+	Exported uint64   `json:"exported"`
+	Files    []string `json:"files"`
+}
+
+func (r *ExportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN EXPORT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Format|%s", r.Format),
+		fmt.Sprintf("From|%d", r.From),
+		fmt.Sprintf("To|%d", r.To),
+		fmt.Sprintf("Exported blocks|%d", r.Exported),
+	}))
+	buffer.WriteString("\n")
+
+	if len(r.Files) > 0 {
+		buffer.WriteString("\n[FILES]\n")
+		buffer.WriteString(helper.FormatList(r.Files))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
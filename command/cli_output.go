@@ -17,7 +17,8 @@ func (cli *CLIOutput) WriteOutput() {
 	if cli.errorOutput != nil {
 		_, _ = fmt.Fprintln(os.Stderr, cli.getErrorOutput())
 
-		return
+		// exit with a non-zero code, so the failure can be detected by scripts
+		os.Exit(1)
 	}
 
 	_, _ = fmt.Fprintln(os.Stdout, cli.getCommandOutput())
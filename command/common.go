@@ -122,6 +122,15 @@ func getValidatorAddressFromSecretManager(manager secrets.SecretsManager) (types
 		return types.ZeroAddress, ErrECDSAKeyNotFound
 	}
 
+	if device, ok := manager.(secrets.ECDSASigner); ok {
+		pub, err := device.ECDSAPublicKey(secrets.ValidatorKey)
+		if err != nil {
+			return types.ZeroAddress, err
+		}
+
+		return crypto.PubKeyToAddress(pub), nil
+	}
+
 	keyBytes, err := manager.GetSecret(secrets.ValidatorKey)
 	if err != nil {
 		return types.ZeroAddress, err
@@ -0,0 +1,52 @@
+package compact
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	compactCmd := &cobra.Command{
+		Use: "compact",
+		Short: "Compacts the databases of the data directory of a stopped node, " +
+			"dropping the deleted and overwritten entries from the disk",
+		Run: runCommand,
+	}
+
+	setFlags(compactCmd)
+	helper.SetRequiredFlags(compactCmd, params.getRequiredFlags())
+
+	return compactCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dbHelper.DataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	_ = cmd.MarkFlagDirname(dbHelper.DataDirFlag)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	progress := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), format, args...)
+	}
+
+	if err := params.compact(progress); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
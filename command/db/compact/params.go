@@ -0,0 +1,93 @@
+package compact
+
+import (
+	"fmt"
+	"time"
+
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+)
+
+var (
+	params = &compactParams{}
+)
+
+type compactParams struct {
+	dataDir string
+
+	compacted []*CompactedDatabase
+}
+
+func (p *compactParams) getRequiredFlags() []string {
+	return []string{
+		dbHelper.DataDirFlag,
+	}
+}
+
+// compact compacts the databases of the data directory one after the other,
+// the start of each compaction is written to out
+func (p *compactParams) compact(out func(format string, args ...interface{})) error {
+	databases, err := dbHelper.FindDatabases(p.dataDir)
+	if err != nil {
+		return err
+	}
+
+	for _, db := range databases {
+		out("%s: compacting\n", db.Name)
+
+		compacted, err := compactDatabase(db)
+		if err != nil {
+			return fmt.Errorf("failed to compact the %s database: %w", db.Name, err)
+		}
+
+		p.compacted = append(p.compacted, compacted)
+	}
+
+	return nil
+}
+
+func compactDatabase(db *dbHelper.Database) (*CompactedDatabase, error) {
+	sizeBefore, err := dbHelper.DirSize(db.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := db.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	if err := kvdb.Compact(kv); err != nil {
+		_ = kv.Close()
+
+		return nil, err
+	}
+
+	duration := time.Since(start)
+
+	// the obsolete files are removed once the database is closed
+	if err := kv.Close(); err != nil {
+		return nil, err
+	}
+
+	sizeAfter, err := dbHelper.DirSize(db.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompactedDatabase{
+		Name:       db.Name,
+		Engine:     db.Engine,
+		SizeBefore: sizeBefore,
+		SizeAfter:  sizeAfter,
+		Duration:   duration.Round(time.Millisecond).String(),
+	}, nil
+}
+
+func (p *compactParams) getResult() *CompactResult {
+	return &CompactResult{
+		Databases: p.compacted,
+	}
+}
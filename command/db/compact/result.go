@@ -0,0 +1,40 @@
+package compact
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type CompactedDatabase struct {
+	Name       string `json:"name"`
+	Engine     string `json:"engine"`
+	SizeBefore uint64 `json:"size_before"`
+	SizeAfter  uint64 `json:"size_after"`
+	Duration   string `json:"duration"`
+}
+
+type CompactResult struct {
+	Databases []*CompactedDatabase `json:"databases"`
+}
+
+func (r *CompactResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DB COMPACT]\n")
+
+	for _, db := range r.Databases {
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Database|%s", db.Name),
+			fmt.Sprintf("Engine|%s", db.Engine),
+			fmt.Sprintf("Size before|%d", db.SizeBefore),
+			fmt.Sprintf("Size after|%d", db.SizeAfter),
+			fmt.Sprintf("Duration|%s", db.Duration),
+		}))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
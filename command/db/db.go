@@ -0,0 +1,31 @@
+package db
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/db/compact"
+	"github.com/0xPolygon/polygon-edge/command/db/inspect"
+	"github.com/0xPolygon/polygon-edge/command/db/migrate"
+	"github.com/0xPolygon/polygon-edge/command/db/stat"
+	"github.com/0xPolygon/polygon-edge/command/db/upgrade"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Top level command for managing the databases of the data directory. Only accepts subcommands.",
+	}
+
+	registerSubcommands(dbCmd)
+
+	return dbCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		inspect.GetCommand(),
+		compact.GetCommand(),
+		stat.GetCommand(),
+		migrate.GetCommand(),
+		upgrade.GetCommand(),
+	)
+}
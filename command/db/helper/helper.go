@@ -0,0 +1,81 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/kvdb"
+)
+
+const (
+	DataDirFlag = "data-dir"
+)
+
+var (
+	// Databases are the databases of the data directory, the flat state one only exists if it is enabled
+	Databases = []string{"blockchain", "trie", "flat"}
+
+	ErrNoDatabases = errors.New("no database in the data directory")
+)
+
+// Database is a database found in the data directory
+type Database struct {
+	Name   string
+	Path   string
+	Engine string
+}
+
+// FindDatabases returns the databases of the data directory, along with the engine they were created with
+func FindDatabases(dataDir string) ([]*Database, error) {
+	found := make([]*Database, 0, len(Databases))
+
+	for _, name := range Databases {
+		path := filepath.Join(dataDir, name)
+
+		engine, err := kvdb.DetectEngine(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if engine == "" {
+			continue
+		}
+
+		found = append(found, &Database{Name: name, Path: path, Engine: engine})
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrNoDatabases, dataDir)
+	}
+
+	return found, nil
+}
+
+// Open opens the database with its engine, the node using it must be stopped
+func (d *Database) Open() (kvdb.Database, error) {
+	return kvdb.Open(d.Engine, d.Path, kvdb.Options{})
+}
+
+// DirSize returns the size of the files in the directory and its subdirectories
+func DirSize(path string) (uint64, error) {
+	size := uint64(0)
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		size += uint64(info.Size())
+
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,60 @@
+package inspect
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	inspectCmd := &cobra.Command{
+		Use: "inspect",
+		Short: "Reports the number of keys and the size of each table of the databases of the data directory " +
+			"of a stopped node, and optionally verifies the entries are keyed and encoded as their table expects",
+		Run: runCommand,
+	}
+
+	setFlags(inspectCmd)
+	helper.SetRequiredFlags(inspectCmd, params.getRequiredFlags())
+
+	return inspectCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dbHelper.DataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.verify,
+		verifyFlag,
+		false,
+		"verify the trie nodes and the contract code are keyed by their hash, "+
+			"and the blocks and the accounts decode",
+	)
+
+	_ = cmd.MarkFlagDirname(dbHelper.DataDirFlag)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	progress := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), format, args...)
+	}
+
+	if err := params.inspect(progress); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
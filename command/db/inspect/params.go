@@ -0,0 +1,140 @@
+package inspect
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/state/flat"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+)
+
+const (
+	verifyFlag = "verify"
+
+	// progressInterval is the number of keys inspected between the progress reports
+	progressInterval = 1_000_000
+
+	// maxReportedInvalid is the number of invalid entries reported by database
+	maxReportedInvalid = 10
+)
+
+var (
+	params = &inspectParams{}
+
+	// inspectors are the layouts of the keys of the databases
+	inspectors = map[string]inspector{
+		"blockchain": {keyTable: storage.KeyTable, verify: storage.VerifyEntry},
+		"trie":       {keyTable: itrie.KeyTable, verify: itrie.VerifyEntry},
+		"flat":       {keyTable: flat.KeyTable, verify: flat.VerifyEntry},
+	}
+)
+
+type inspector struct {
+	keyTable func(key []byte) string
+	verify   func(key, value []byte) error
+}
+
+type inspectParams struct {
+	dataDir string
+	verify  bool
+
+	inspected []*InspectedDatabase
+}
+
+func (p *inspectParams) getRequiredFlags() []string {
+	return []string{
+		dbHelper.DataDirFlag,
+	}
+}
+
+// inspect iterates over all the keys of the databases of the data directory,
+// the progress of the iterations is written to out
+func (p *inspectParams) inspect(out func(format string, args ...interface{})) error {
+	databases, err := dbHelper.FindDatabases(p.dataDir)
+	if err != nil {
+		return err
+	}
+
+	for _, db := range databases {
+		inspected, err := p.inspectDatabase(db, func(keys uint64) {
+			out("%s: %d keys inspected\n", db.Name, keys)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to inspect the %s database: %w", db.Name, err)
+		}
+
+		p.inspected = append(p.inspected, inspected)
+	}
+
+	return nil
+}
+
+func (p *inspectParams) inspectDatabase(db *dbHelper.Database, progress func(uint64)) (*InspectedDatabase, error) {
+	kv, err := db.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer kv.Close()
+
+	var (
+		insp     = inspectors[db.Name]
+		tables   = map[string]*InspectedTable{}
+		result   = &InspectedDatabase{Name: db.Name, Engine: db.Engine}
+		iterator = kv.NewIterator(nil, nil)
+	)
+
+	defer iterator.Release()
+
+	for iterator.Next() {
+		key, value := iterator.Key(), iterator.Value()
+
+		name := insp.keyTable(key)
+
+		table, ok := tables[name]
+		if !ok {
+			table = &InspectedTable{Name: name}
+			tables[name] = table
+			result.Tables = append(result.Tables, table)
+		}
+
+		table.Keys++
+		table.Size += uint64(len(key) + len(value))
+
+		result.Keys++
+		result.Size += uint64(len(key) + len(value))
+
+		if p.verify {
+			if err := insp.verify(key, value); err != nil {
+				table.Invalid++
+
+				if len(result.InvalidEntries) < maxReportedInvalid {
+					result.InvalidEntries = append(result.InvalidEntries, fmt.Sprintf("%s %x: %v", name, key, err))
+				}
+			}
+		}
+
+		if result.Keys%progressInterval == 0 {
+			progress(result.Keys)
+		}
+	}
+
+	if err := iterator.Error(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.Tables, func(i, j int) bool {
+		return result.Tables[i].Name < result.Tables[j].Name
+	})
+
+	return result, nil
+}
+
+func (p *inspectParams) getResult() *InspectResult {
+	return &InspectResult{
+		Verified:  p.verify,
+		Databases: p.inspected,
+	}
+}
@@ -0,0 +1,78 @@
+package inspect
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type InspectedTable struct {
+	Name    string `json:"name"`
+	Keys    uint64 `json:"keys"`
+	Size    uint64 `json:"size"`
+	Invalid uint64 `json:"invalid"`
+}
+
+type InspectedDatabase struct {
+	Name   string            `json:"name"`
+	Engine string            `json:"engine"`
+	Keys   uint64            `json:"keys"`
+	Size   uint64            `json:"size"`
+	Tables []*InspectedTable `json:"tables"`
+
+	// InvalidEntries are the first invalid entries found, along with the reason
+	InvalidEntries []string `json:"invalid_entries,omitempty"`
+}
+
+type InspectResult struct {
+	Verified  bool                 `json:"verified"`
+	Databases []*InspectedDatabase `json:"databases"`
+}
+
+func (r *InspectResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DB INSPECT]\n")
+
+	for _, db := range r.Databases {
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Database|%s", db.Name),
+			fmt.Sprintf("Engine|%s", db.Engine),
+			fmt.Sprintf("Keys|%d", db.Keys),
+			fmt.Sprintf("Size|%d", db.Size),
+		}))
+		buffer.WriteString("\n")
+
+		if len(db.Tables) == 0 {
+			continue
+		}
+
+		rows := []string{"Table|Keys|Size"}
+		if r.Verified {
+			rows[0] += "|Invalid"
+		}
+
+		for _, table := range db.Tables {
+			row := fmt.Sprintf("%s|%d|%d", table.Name, table.Keys, table.Size)
+			if r.Verified {
+				row += fmt.Sprintf("|%d", table.Invalid)
+			}
+
+			rows = append(rows, row)
+		}
+
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatList(rows))
+		buffer.WriteString("\n")
+
+		if len(db.InvalidEntries) > 0 {
+			buffer.WriteString("\nInvalid entries:\n")
+			buffer.WriteString(helper.FormatList(db.InvalidEntries))
+			buffer.WriteString("\n")
+		}
+	}
+
+	return buffer.String()
+}
@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use: "migrate",
+		Short: "Moves the databases of the data directory of a stopped node to another engine. " +
+			"The current databases are kept next to the new ones, suffixed with their engine",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(migrateCmd)
+	helper.SetRequiredFlags(migrateCmd, params.getRequiredFlags())
+
+	return migrateCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dbHelper.DataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.engine,
+		dbEngineFlag,
+		"",
+		fmt.Sprintf(
+			"the engine the databases are moved to: %s",
+			strings.Join([]string{kvdb.LevelDB, kvdb.Pebble, kvdb.Badger}, ", "),
+		),
+	)
+
+	_ = cmd.MarkFlagDirname(dbHelper.DataDirFlag)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	progress := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), format, args...)
+	}
+
+	if err := params.migrate(progress); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
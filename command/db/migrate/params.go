@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"fmt"
+
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+)
+
+const (
+	dbEngineFlag = "db.engine"
+
+	// progressInterval is the number of keys copied between the progress reports
+	progressInterval = 1_000_000
+)
+
+var (
+	params = &migrateParams{}
+)
+
+type migrateParams struct {
+	dataDir string
+	engine  string
+
+	migrated []*MigratedDatabase
+}
+
+func (p *migrateParams) getRequiredFlags() []string {
+	return []string{
+		dbHelper.DataDirFlag,
+		dbEngineFlag,
+	}
+}
+
+func (p *migrateParams) validateFlags() error {
+	for _, engine := range kvdb.Engines() {
+		if p.engine == engine && engine != kvdb.Memory {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", kvdb.ErrUnknownEngine, p.engine)
+}
+
+// migrate moves the databases of the data directory which aren't of the engine yet,
+// the progress of the copies is written to out
+func (p *migrateParams) migrate(out func(format string, args ...interface{})) error {
+	databases, err := dbHelper.FindDatabases(p.dataDir)
+	if err != nil {
+		return err
+	}
+
+	for _, db := range databases {
+		if db.Engine == p.engine {
+			continue
+		}
+
+		reported := uint64(0)
+
+		migration, err := kvdb.Migrate(db.Path, p.engine, func(copied uint64) {
+			if copied-reported >= progressInterval {
+				reported = copied
+				out("%s: %d keys copied\n", db.Name, copied)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate the %s database: %w", db.Name, err)
+		}
+
+		p.migrated = append(p.migrated, &MigratedDatabase{
+			Name:   db.Name,
+			From:   migration.From,
+			Keys:   migration.Keys,
+			Backup: migration.Backup,
+		})
+	}
+
+	return nil
+}
+
+func (p *migrateParams) getResult() *MigrateResult {
+	return &MigrateResult{
+		Engine:    p.engine,
+		Databases: p.migrated,
+	}
+}
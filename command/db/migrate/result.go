@@ -0,0 +1,47 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type MigratedDatabase struct {
+	Name   string `json:"name"`
+	From   string `json:"from"`
+	Keys   uint64 `json:"keys"`
+	Backup string `json:"backup"`
+}
+
+type MigrateResult struct {
+	Engine    string              `json:"engine"`
+	Databases []*MigratedDatabase `json:"databases"`
+}
+
+func (r *MigrateResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DB MIGRATE]\n")
+
+	if len(r.Databases) == 0 {
+		buffer.WriteString(fmt.Sprintf("The databases already use the %s engine\n", r.Engine))
+
+		return buffer.String()
+	}
+
+	buffer.WriteString(fmt.Sprintf("Migrated the databases to the %s engine:\n", r.Engine))
+
+	for _, db := range r.Databases {
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Database|%s", db.Name),
+			fmt.Sprintf("From|%s", db.From),
+			fmt.Sprintf("Keys|%d", db.Keys),
+			fmt.Sprintf("Backup|%s", db.Backup),
+		}))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
@@ -0,0 +1,104 @@
+package stat
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+)
+
+const (
+	ancientDirFlag = "datadir.ancient"
+)
+
+var (
+	params = &statParams{}
+)
+
+type statParams struct {
+	dataDir    string
+	ancientDir string
+
+	databases []*DatabaseStats
+	freezer   []*FreezerTableStats
+}
+
+func (p *statParams) getRequiredFlags() []string {
+	return []string{
+		dbHelper.DataDirFlag,
+	}
+}
+
+func (p *statParams) stat() error {
+	databases, err := dbHelper.FindDatabases(p.dataDir)
+	if err != nil {
+		return err
+	}
+
+	for _, db := range databases {
+		stats, err := statDatabase(db)
+		if err != nil {
+			return fmt.Errorf("failed to read the statistics of the %s database: %w", db.Name, err)
+		}
+
+		p.databases = append(p.databases, stats)
+	}
+
+	if p.ancientDir == "" {
+		return nil
+	}
+
+	tables, err := storage.ReadFreezerStats(p.ancientDir)
+	if err != nil {
+		return fmt.Errorf("failed to read the statistics of the freezer: %w", err)
+	}
+
+	for _, table := range tables {
+		p.freezer = append(p.freezer, &FreezerTableStats{
+			Name:  table.Name,
+			Items: table.Items,
+			Size:  table.Size,
+		})
+	}
+
+	return nil
+}
+
+func statDatabase(db *dbHelper.Database) (*DatabaseStats, error) {
+	size, err := dbHelper.DirSize(db.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := db.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	engineStats, err := kvdb.Stats(kv)
+	if err != nil && !errors.Is(err, kvdb.ErrStatsNotSupported) {
+		_ = kv.Close()
+
+		return nil, err
+	}
+
+	if err := kv.Close(); err != nil {
+		return nil, err
+	}
+
+	return &DatabaseStats{
+		Name:        db.Name,
+		Engine:      db.Engine,
+		Size:        size,
+		EngineStats: engineStats,
+	}, nil
+}
+
+func (p *statParams) getResult() *StatResult {
+	return &StatResult{
+		Databases: p.databases,
+		Freezer:   p.freezer,
+	}
+}
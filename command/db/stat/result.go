@@ -0,0 +1,62 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type DatabaseStats struct {
+	Name        string `json:"name"`
+	Engine      string `json:"engine"`
+	Size        uint64 `json:"size"`
+	EngineStats string `json:"engine_stats,omitempty"`
+}
+
+type FreezerTableStats struct {
+	Name  string `json:"name"`
+	Items uint64 `json:"items"`
+	Size  uint64 `json:"size"`
+}
+
+type StatResult struct {
+	Databases []*DatabaseStats     `json:"databases"`
+	Freezer   []*FreezerTableStats `json:"freezer,omitempty"`
+}
+
+func (r *StatResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DB STAT]\n")
+
+	for _, db := range r.Databases {
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Database|%s", db.Name),
+			fmt.Sprintf("Engine|%s", db.Engine),
+			fmt.Sprintf("Size|%d", db.Size),
+		}))
+		buffer.WriteString("\n")
+
+		if db.EngineStats != "" {
+			buffer.WriteString("\n")
+			buffer.WriteString(db.EngineStats)
+			buffer.WriteString("\n")
+		}
+	}
+
+	if len(r.Freezer) > 0 {
+		rows := []string{"Table|Items|Size"}
+
+		for _, table := range r.Freezer {
+			rows = append(rows, fmt.Sprintf("%s|%d|%d", table.Name, table.Items, table.Size))
+		}
+
+		buffer.WriteString("\n[FREEZER]\n")
+		buffer.WriteString(helper.FormatList(rows))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
@@ -0,0 +1,54 @@
+package stat
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	statCmd := &cobra.Command{
+		Use: "stat",
+		Short: "Reports the size on the disk and the engine statistics of the databases of the data directory " +
+			"of a stopped node, and the size of the tables of the freezer",
+		Run: runCommand,
+	}
+
+	setFlags(statCmd)
+	helper.SetRequiredFlags(statCmd, params.getRequiredFlags())
+
+	return statCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dbHelper.DataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.ancientDir,
+		ancientDirFlag,
+		"",
+		"the directory of the freezer the old blocks are moved to, if the node is started with one",
+	)
+
+	_ = cmd.MarkFlagDirname(dbHelper.DataDirFlag)
+	_ = cmd.MarkFlagDirname(ancientDirFlag)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.stat(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
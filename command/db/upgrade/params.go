@@ -0,0 +1,98 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+)
+
+const (
+	ancientDirFlag = "datadir.ancient"
+
+	// progressInterval is the number of entries rewritten between the progress reports
+	progressInterval = 100_000
+)
+
+var (
+	params = &upgradeParams{}
+
+	errNoBlockchain = errors.New("no blockchain database in the data directory")
+	errNoAncientDir = errors.New("the ancient directory of the freezer must be set")
+)
+
+type upgradeParams struct {
+	dataDir    string
+	ancientDir string
+
+	upgraded *storage.ReceiptsUpgrade
+}
+
+func (p *upgradeParams) getRequiredFlags() []string {
+	return []string{
+		dbHelper.DataDirFlag,
+	}
+}
+
+// upgrade rewrites the receipts and the transaction lookups of the blockchain database,
+// the progress of the rewrite is written to out
+func (p *upgradeParams) upgrade(out func(format string, args ...interface{})) error {
+	path := filepath.Join(p.dataDir, "blockchain")
+
+	engine, err := kvdb.DetectEngine(path)
+	if err != nil {
+		return err
+	}
+
+	if engine == "" {
+		return fmt.Errorf("%w %s", errNoBlockchain, p.dataDir)
+	}
+
+	db, err := kvdb.Open(engine, path, kvdb.Options{})
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	var freezer *storage.Freezer
+
+	if p.ancientDir == "" {
+		// the bodies of the blocks moved to the freezer are needed to index their transactions
+		moved, err := storage.ReadDatabaseAncientHead(db)
+		if err != nil {
+			return err
+		}
+
+		if moved > 0 {
+			return fmt.Errorf("%w, the first %d blocks were moved to it", errNoAncientDir, moved)
+		}
+	} else {
+		if freezer, err = storage.OpenFreezer(p.ancientDir); err != nil {
+			return fmt.Errorf("failed to open the freezer: %w", err)
+		}
+
+		defer freezer.Close()
+	}
+
+	reported := uint64(0)
+
+	p.upgraded, err = storage.UpgradeDatabaseReceipts(db, freezer, func(upgraded uint64) {
+		if upgraded-reported >= progressInterval {
+			reported = upgraded
+			out("%d entries upgraded\n", upgraded)
+		}
+	})
+
+	return err
+}
+
+func (p *upgradeParams) getResult() *UpgradeResult {
+	return &UpgradeResult{
+		Receipts:  p.upgraded.Receipts,
+		TxLookups: p.upgraded.TxLookups,
+	}
+}
@@ -0,0 +1,26 @@
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type UpgradeResult struct {
+	Receipts  uint64 `json:"receipts"`
+	TxLookups uint64 `json:"tx_lookups"`
+}
+
+func (r *UpgradeResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DB UPGRADE RECEIPTS]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Receipts upgraded|%d", r.Receipts),
+		fmt.Sprintf("Transaction lookups upgraded|%d", r.TxLookups),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
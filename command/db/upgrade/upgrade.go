@@ -0,0 +1,60 @@
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	dbHelper "github.com/0xPolygon/polygon-edge/command/db/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	upgradeCmd := &cobra.Command{
+		Use: "upgrade-receipts",
+		Short: "Rewrites the receipts and the transaction lookups of the blockchain database of a stopped node " +
+			"in their latest encoding, which serves the receipt of a transaction without the others of its block",
+		Run: runCommand,
+	}
+
+	setFlags(upgradeCmd)
+	helper.SetRequiredFlags(upgradeCmd, params.getRequiredFlags())
+
+	return upgradeCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dbHelper.DataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.ancientDir,
+		ancientDirFlag,
+		"",
+		"the ancient directory of the node, required if blocks were moved to the freezer",
+	)
+
+	_ = cmd.MarkFlagDirname(dbHelper.DataDirFlag)
+	_ = cmd.MarkFlagDirname(ancientDirFlag)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	progress := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), format, args...)
+	}
+
+	if err := params.upgrade(progress); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
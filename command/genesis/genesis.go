@@ -5,9 +5,12 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/command/genesis/predeploy"
+	"github.com/0xPolygon/polygon-edge/command/genesis/validate"
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/consensus/external"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	stakingHelper "github.com/0xPolygon/polygon-edge/helper/staking"
 	"github.com/0xPolygon/polygon-edge/validators"
 	"github.com/spf13/cobra"
 )
@@ -30,6 +33,10 @@ func GetCommand() *cobra.Command {
 	genesisCmd.AddCommand(
 		// genesis predeploy
 		predeploy.GetCommand(),
+		// genesis validate
+		validate.GetCommand(),
+		// genesis wizard
+		getWizardCommand(),
 	)
 
 	return genesisCmd
@@ -67,6 +74,14 @@ func setFlags(cmd *cobra.Command) {
 		),
 	)
 
+	cmd.Flags().StringVar(
+		&params.allocFile,
+		allocFileFlag,
+		"",
+		"the JSON file of the accounts allocated in the genesis state along with the premined ones, "+
+			"streamed by the nodes so it can hold large allocations",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.blockGasLimit,
 		blockGasLimitFlag,
@@ -74,11 +89,62 @@ func setFlags(cmd *cobra.Command) {
 		"the maximum amount of gas used by all transactions in a block",
 	)
 
+	cmd.Flags().StringVar(
+		&params.gasTargetContractRaw,
+		gasTargetFlag,
+		"",
+		"the contract governing the block gas target in its first storage slot, "+
+			"which the gas limit of the blocks moves toward once it's set",
+	)
+
+	cmd.Flags().StringVar(
+		&params.minterRaw,
+		minterFlag,
+		"",
+		"the account allowed to mint the native token through its precompile",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.baseFee,
+		baseFeeFlag,
+		0,
+		"the part of the gas price, in wei, which isn't paid to the block creator",
+	)
+
+	cmd.Flags().StringVar(
+		&params.treasuryRaw,
+		treasuryFlag,
+		"",
+		"the account receiving the base fees, which are burned if it isn't set",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.allowlistAdminsRaw,
+		allowlistAdminFlag,
+		[]string{},
+		"the admins granting the roles of the allowlist through its precompile",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.allowlistDeployments,
+		allowDeployFlag,
+		false,
+		"restrict the deployment of contracts to the accounts with the deployer role of the allowlist",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.allowlistTransactions,
+		allowTxFlag,
+		false,
+		"restrict the transactions to the accounts with the sender role of the allowlist",
+	)
+
 	cmd.Flags().StringArrayVar(
 		&params.bootnodes,
 		command.BootnodeFlag,
 		[]string{},
-		"multiAddr URL for p2p discovery bootstrap. This flag can be used multiple times",
+		"multiAddr URL for p2p discovery bootstrap, or the enrtree:// URL of a DNS tree publishing the bootnodes, "+
+			"which is resolved again periodically. This flag can be used multiple times",
 	)
 
 	cmd.Flags().StringVar(
@@ -95,6 +161,46 @@ func setFlags(cmd *cobra.Command) {
 		"the epoch size for the chain",
 	)
 
+	cmd.Flags().StringVar(
+		&params.externalEngine,
+		externalEngineFlag,
+		external.DefaultEngineAddr,
+		"the gRPC address of the engine driving the external consensus, "+
+			"a loopback address or a unix socket (unix:///path)",
+	)
+
+	cmd.Flags().StringVar(
+		&params.externalHost,
+		externalHostFlag,
+		external.DefaultHostAddr,
+		"the gRPC address the node serves the external consensus engine on, "+
+			"a loopback address or a unix socket (unix:///path)",
+	)
+
+	// IBFT round timeouts
+	{
+		cmd.Flags().Uint64Var(
+			&params.roundTimeout,
+			roundTimeoutFlag,
+			0,
+			"the timeout of the first IBFT round in seconds, extended by the block time. Defaults to 10",
+		)
+
+		cmd.Flags().Float64Var(
+			&params.roundTimeoutFactor,
+			roundFactorFlag,
+			0,
+			"the factor the IBFT round timeout is multiplied by on every round change. Defaults to 2",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.roundTimeoutJitter,
+			roundJitterFlag,
+			0,
+			"the highest share of the IBFT round timeout, in percent, added at random to every round",
+		)
+	}
+
 	// IBFT Validators
 	{
 		cmd.Flags().StringVar(
@@ -147,7 +253,24 @@ func setFlags(cmd *cobra.Command) {
 			common.MaxSafeJSInt,
 			"the maximum number of validators in the validator set for PoS",
 		)
+
+		cmd.Flags().StringVar(
+			&params.stakedAmountRaw,
+			stakedAmountFlag,
+			stakingHelper.DefaultStakedBalance,
+			"the amount pre-staked by each of the initial validators for PoS",
+		)
+
+		cmd.Flags().StringArrayVar(
+			&params.validatorStakesRaw,
+			validatorStakeFlag,
+			[]string{},
+			"the amount pre-staked by an initial validator for PoS, overriding the staked-amount "+
+				"(format: <address>:<amount>). This flag can be used multiple times",
+		)
 	}
+
+	_ = cmd.MarkFlagFilename(allocFileFlag)
 }
 
 // setLegacyFlags sets the legacy flags to preserve backwards compatibility
@@ -0,0 +1,325 @@
+package genesis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/genesis/validate"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	stakingHelper "github.com/0xPolygon/polygon-edge/helper/staking"
+	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+func getWizardCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "wizard",
+		Short: "Walks through the creation of a chain interactively, writing the genesis file " +
+			"and checking it for common mistakes",
+		Run: runWizardCommand,
+	}
+}
+
+func runWizardCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	// the prompts are written to stderr, keeping the stdout for the result
+	w := &wizard{
+		in:  bufio.NewReader(cmd.InOrStdin()),
+		out: cmd.ErrOrStderr(),
+	}
+
+	if err := w.run(params); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	if err := params.generateGenesis(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	validation, err := validate.ValidateFile(params.genesisPath)
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(&GenesisWizardResult{
+		GenesisResult: GenesisResult{
+			Message: fmt.Sprintf("Genesis written to %s\n", params.genesisPath),
+		},
+		Validation: validation,
+	})
+}
+
+var errWizardAborted = errors.New("the wizard input ended before the chain was created")
+
+// wizard prompts for the genesis parameters, asking again until a valid answer is given
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prompts for a value, returning the default value on an empty answer
+func (w *wizard) ask(question, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	line, err := w.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", errWizardAborted
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+
+	return defaultValue, nil
+}
+
+// askValid prompts until the answer passes the check
+func (w *wizard) askValid(question, defaultValue string, check func(string) error) (string, error) {
+	for {
+		answer, err := w.ask(question, defaultValue)
+		if err != nil {
+			return "", err
+		}
+
+		if err := check(answer); err != nil {
+			fmt.Fprintf(w.out, "Invalid answer: %v\n", err)
+
+			continue
+		}
+
+		return answer, nil
+	}
+}
+
+func (w *wizard) askUint64(question string, defaultValue uint64, min uint64) (uint64, error) {
+	var value uint64
+
+	if _, err := w.askValid(question, strconv.FormatUint(defaultValue, 10), func(answer string) error {
+		parsed, err := strconv.ParseUint(answer, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s isn't a number", answer)
+		}
+
+		if parsed < min {
+			return fmt.Errorf("the value must be at least %d", min)
+		}
+
+		value = parsed
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+func (w *wizard) askBool(question string, defaultValue bool) (bool, error) {
+	defaultAnswer := "n"
+	if defaultValue {
+		defaultAnswer = "y"
+	}
+
+	answer, err := w.askValid(question+" (y/n)", defaultAnswer, func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "y", "yes", "n", "no":
+			return nil
+		default:
+			return errors.New("answer y or n")
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
+
+// askList prompts for comma separated values
+func (w *wizard) askList(question string, check func(string) error) ([]string, error) {
+	var values []string
+
+	if _, err := w.askValid(question+" (comma separated)", "", func(answer string) error {
+		values = values[:0]
+
+		for _, value := range strings.Split(answer, ",") {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+
+			if err := check(value); err != nil {
+				return err
+			}
+
+			values = append(values, value)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// run fills the genesis parameters from the answers, and initializes them the way the flags are
+func (w *wizard) run(p *genesisParams) error {
+	var err error
+
+	if p.genesisPath, err = w.askValid("Genesis file", "./"+command.DefaultGenesisFileName, func(path string) error {
+		if genErr := verifyGenesisExistence(path); genErr != nil {
+			return errors.New(genErr.GetMessage())
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if p.name, err = w.ask("Chain name", command.DefaultChainName); err != nil {
+		return err
+	}
+
+	if p.chainID, err = w.askUint64("Chain ID", command.DefaultChainID, 1); err != nil {
+		return err
+	}
+
+	if p.consensusRaw, err = w.askValid("Consensus", string(command.DefaultConsensus), func(answer string) error {
+		if !server.ConsensusSupported(answer) {
+			return errUnsupportedConsensus
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := w.askValidators(p); err != nil {
+		return err
+	}
+
+	if p.premine, err = w.askList("Premined accounts, as <address> or <address>:<balance>", func(prem string) error {
+		return fillPremineMap(make(map[types.Address]*chain.GenesisAccount), []string{prem})
+	}); err != nil {
+		return err
+	}
+
+	if p.blockGasLimit, err = w.askUint64("Block gas limit", command.DefaultGenesisGasLimit, 1); err != nil {
+		return err
+	}
+
+	for len(p.bootnodes) == 0 {
+		if p.bootnodes, err = w.askList("Bootnode multiaddrs or enrtree:// URLs", func(string) error {
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := p.validateFlags(); err != nil {
+		return err
+	}
+
+	return p.initRawParams()
+}
+
+// askValidators prompts for the IBFT validators and, for PoS, the staking parameters
+func (w *wizard) askValidators(p *genesisParams) error {
+	var err error
+
+	p.rawIBFTValidatorType = string(validators.BLSValidatorType)
+	p.epochSize = ibft.DefaultEpochSize
+	p.minNumValidators = 1
+	p.maxNumValidators = common.MaxSafeJSInt
+	p.stakedAmountRaw = stakingHelper.DefaultStakedBalance
+
+	if !p.isIBFTConsensus() {
+		return nil
+	}
+
+	if p.rawIBFTValidatorType, err = w.askValid(
+		"Validator type", string(validators.BLSValidatorType),
+		func(answer string) error {
+			_, err := validators.ParseValidatorType(answer)
+
+			return err
+		},
+	); err != nil {
+		return err
+	}
+
+	validatorType, _ := validators.ParseValidatorType(p.rawIBFTValidatorType)
+
+	if p.validatorPrefixPath, err = w.ask(
+		"Prefix of the validator data directories, leave empty to give the validators", "",
+	); err != nil {
+		return err
+	}
+
+	for p.validatorPrefixPath == "" && len(p.ibftValidatorsRaw) == 0 {
+		if p.ibftValidatorsRaw, err = w.askList("Validators", func(raw string) error {
+			_, err := validators.ParseValidator(validatorType, raw)
+
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	if p.epochSize, err = w.askUint64("Epoch size", ibft.DefaultEpochSize, 2); err != nil {
+		return err
+	}
+
+	if p.isPos, err = w.askBool("Proof of Stake", false); err != nil {
+		return err
+	}
+
+	if !p.isPos {
+		return nil
+	}
+
+	if p.minNumValidators, err = w.askUint64("Minimum validator count", 1, 1); err != nil {
+		return err
+	}
+
+	if p.maxNumValidators, err = w.askUint64(
+		"Maximum validator count", common.MaxSafeJSInt, p.minNumValidators,
+	); err != nil {
+		return err
+	}
+
+	if p.stakedAmountRaw, err = w.askValid(
+		"Amount staked by each validator", stakingHelper.DefaultStakedBalance,
+		func(answer string) error {
+			_, err := types.ParseUint256orHex(&answer)
+
+			return err
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
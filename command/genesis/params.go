@@ -3,10 +3,13 @@ package genesis
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"path/filepath"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/consensus/external"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
@@ -18,15 +21,30 @@ import (
 )
 
 const (
-	dirFlag           = "dir"
-	nameFlag          = "name"
-	premineFlag       = "premine"
-	chainIDFlag       = "chain-id"
-	epochSizeFlag     = "epoch-size"
-	blockGasLimitFlag = "block-gas-limit"
-	posFlag           = "pos"
-	minValidatorCount = "min-validator-count"
-	maxValidatorCount = "max-validator-count"
+	dirFlag            = "dir"
+	nameFlag           = "name"
+	premineFlag        = "premine"
+	allocFileFlag      = "alloc-file"
+	chainIDFlag        = "chain-id"
+	epochSizeFlag      = "epoch-size"
+	blockGasLimitFlag  = "block-gas-limit"
+	posFlag            = "pos"
+	minValidatorCount  = "min-validator-count"
+	maxValidatorCount  = "max-validator-count"
+	stakedAmountFlag   = "staked-amount"
+	validatorStakeFlag = "validator-stake"
+	gasTargetFlag      = "gas-target-contract"
+	minterFlag         = "native-token-minter"
+	baseFeeFlag        = "base-fee"
+	treasuryFlag       = "fee-treasury"
+	allowlistAdminFlag = "allowlist-admin"
+	allowDeployFlag    = "allowlist-deployments"
+	allowTxFlag        = "allowlist-transactions"
+	roundTimeoutFlag   = "round-timeout"
+	roundFactorFlag    = "round-timeout-factor"
+	roundJitterFlag    = "round-timeout-jitter"
+	externalEngineFlag = "external-engine"
+	externalHostFlag   = "external-host"
 )
 
 // Legacy flags that need to be preserved for running clients
@@ -50,6 +68,7 @@ type genesisParams struct {
 	consensusRaw        string
 	validatorPrefixPath string
 	premine             []string
+	allocFile           string
 	bootnodes           []string
 	ibftValidators      validators.Validators
 
@@ -60,9 +79,35 @@ type genesisParams struct {
 	blockGasLimit uint64
 	isPos         bool
 
+	roundTimeout       uint64
+	roundTimeoutFactor float64
+	roundTimeoutJitter uint64
+
+	externalEngine string
+	externalHost   string
+
+	gasTargetContractRaw string
+	gasTargetContract    *types.Address
+
+	minterRaw   string
+	baseFee     uint64
+	treasuryRaw string
+	nativeToken *chain.NativeToken
+
+	allowlistAdminsRaw    []string
+	allowlistDeployments  bool
+	allowlistTransactions bool
+	allowlist             *chain.Allowlist
+
 	minNumValidators uint64
 	maxNumValidators uint64
 
+	stakedAmountRaw    string
+	validatorStakesRaw []string
+
+	stakedAmount    *big.Int
+	validatorStakes map[types.Address]*big.Int
+
 	rawIBFTValidatorType string
 	ibftValidatorType    validators.ValidatorType
 
@@ -100,11 +145,27 @@ func (p *genesisParams) validateFlags() error {
 		return errInvalidEpochSize
 	}
 
+	// Check the external engine is only reached locally
+	if server.ConsensusType(p.consensusRaw) == server.ExternalConsensus {
+		for _, addr := range []string{p.externalEngine, p.externalHost} {
+			if err := external.CheckLocalAddr(addr); err != nil {
+				return fmt.Errorf("invalid external consensus address %q: %w", addr, err)
+			}
+		}
+	}
+
 	// Validate min and max validators number
 	if err := command.ValidateMinMaxValidatorsNumber(p.minNumValidators, p.maxNumValidators); err != nil {
 		return err
 	}
 
+	// Validate the curve of the round timeouts
+	if p.isIBFTConsensus() {
+		if _, err := ibft.RoundTimeoutParams(p.roundTimeoutConfig()); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -137,12 +198,99 @@ func (p *genesisParams) initRawParams() error {
 		return err
 	}
 
+	if err := p.initStakes(); err != nil {
+		return err
+	}
+
+	if err := p.initGasTargetContract(); err != nil {
+		return err
+	}
+
+	if err := p.initNativeToken(); err != nil {
+		return err
+	}
+
+	if err := p.initAllowlist(); err != nil {
+		return err
+	}
+
 	p.initIBFTExtraData()
 	p.initConsensusEngineConfig()
 
 	return nil
 }
 
+// initGasTargetContract parses the contract governing the block gas target, if it's set
+func (p *genesisParams) initGasTargetContract() error {
+	if p.gasTargetContractRaw == "" {
+		return nil
+	}
+
+	contract := types.Address{}
+	if err := contract.UnmarshalText([]byte(p.gasTargetContractRaw)); err != nil {
+		return fmt.Errorf("invalid gas target contract %s: %w", p.gasTargetContractRaw, err)
+	}
+
+	p.gasTargetContract = &contract
+
+	return nil
+}
+
+// initNativeToken parses the economics of the native token, which are enabled from the genesis if any is set
+func (p *genesisParams) initNativeToken() error {
+	if p.minterRaw == "" && p.baseFee == 0 && p.treasuryRaw == "" {
+		return nil
+	}
+
+	p.nativeToken = &chain.NativeToken{BaseFee: p.baseFee}
+
+	for _, account := range []struct {
+		name string
+		raw  string
+		addr **types.Address
+	}{
+		{name: "native token minter", raw: p.minterRaw, addr: &p.nativeToken.Minter},
+		{name: "fee treasury", raw: p.treasuryRaw, addr: &p.nativeToken.Treasury},
+	} {
+		if account.raw == "" {
+			continue
+		}
+
+		addr := types.Address{}
+		if err := addr.UnmarshalText([]byte(account.raw)); err != nil {
+			return fmt.Errorf("invalid %s %s: %w", account.name, account.raw, err)
+		}
+
+		*account.addr = &addr
+	}
+
+	return nil
+}
+
+// initAllowlist parses the allowlist, which is enabled from the genesis if any of its flags is set
+func (p *genesisParams) initAllowlist() error {
+	if len(p.allowlistAdminsRaw) == 0 && !p.allowlistDeployments && !p.allowlistTransactions {
+		return nil
+	}
+
+	p.allowlist = &chain.Allowlist{
+		Admins:       make([]types.Address, 0, len(p.allowlistAdminsRaw)),
+		Deployments:  p.allowlistDeployments,
+		Transactions: p.allowlistTransactions,
+	}
+
+	for _, raw := range p.allowlistAdminsRaw {
+		addr := types.Address{}
+		if err := addr.UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid allowlist admin %s: %w", raw, err)
+		}
+
+		p.allowlist.Admins = append(p.allowlist.Admins, addr)
+	}
+
+	return nil
+}
+
 // setValidatorSetFromCli sets validator set from cli command
 func (p *genesisParams) setValidatorSetFromCli() error {
 	if len(p.ibftValidatorsRaw) == 0 {
@@ -242,6 +390,17 @@ func (p *genesisParams) initIBFTExtraData() {
 }
 
 func (p *genesisParams) initConsensusEngineConfig() {
+	if p.consensus == server.ExternalConsensus {
+		p.consensusEngineConfig = map[string]interface{}{
+			p.consensusRaw: map[string]interface{}{
+				external.KeyEngineAddr: p.externalEngine,
+				external.KeyHostAddr:   p.externalHost,
+			},
+		}
+
+		return
+	}
+
 	if p.consensus != server.IBFTConsensus {
 		p.consensusEngineConfig = map[string]interface{}{
 			p.consensusRaw: map[string]interface{}{},
@@ -260,13 +419,39 @@ func (p *genesisParams) initConsensusEngineConfig() {
 }
 
 func (p *genesisParams) initIBFTEngineMap(ibftType fork.IBFTType) {
+	ibftConfig := map[string]interface{}{
+		fork.KeyType:          ibftType,
+		fork.KeyValidatorType: p.ibftValidatorType,
+		ibft.KeyEpochSize:     p.epochSize,
+	}
+
+	for key, value := range p.roundTimeoutConfig() {
+		ibftConfig[key] = value
+	}
+
 	p.consensusEngineConfig = map[string]interface{}{
-		string(server.IBFTConsensus): map[string]interface{}{
-			fork.KeyType:          ibftType,
-			fork.KeyValidatorType: p.ibftValidatorType,
-			ibft.KeyEpochSize:     p.epochSize,
-		},
+		string(server.IBFTConsensus): ibftConfig,
+	}
+}
+
+// roundTimeoutConfig returns the parameters of the curve of the round timeouts set by the flags,
+// the chain uses the go-ibft curve for the ones not set
+func (p *genesisParams) roundTimeoutConfig() map[string]interface{} {
+	config := map[string]interface{}{}
+
+	if p.roundTimeout != 0 {
+		config[ibft.KeyRoundTimeout] = float64(p.roundTimeout)
+	}
+
+	if p.roundTimeoutFactor != 0 {
+		config[ibft.KeyRoundTimeoutFactor] = p.roundTimeoutFactor
+	}
+
+	if p.roundTimeoutJitter != 0 {
+		config[ibft.KeyRoundTimeoutJitter] = float64(p.roundTimeoutJitter)
 	}
+
+	return config
 }
 
 func (p *genesisParams) generateGenesis() error {
@@ -295,13 +480,29 @@ func (p *genesisParams) initGenesisConfig() error {
 			GasUsed:    command.DefaultGenesisGasUsed,
 		},
 		Params: &chain.Params{
-			ChainID: int(p.chainID),
-			Forks:   chain.AllForksEnabled,
-			Engine:  p.consensusEngineConfig,
+			ChainID:           int(p.chainID),
+			Forks:             chain.AllForksEnabled,
+			Engine:            p.consensusEngineConfig,
+			GasTargetContract: p.gasTargetContract,
 		},
 		Bootnodes: p.bootnodes,
 	}
 
+	if p.nativeToken != nil || p.allowlist != nil {
+		forks := *chain.AllForksEnabled
+		chainConfig.Params.Forks = &forks
+	}
+
+	if p.nativeToken != nil {
+		chainConfig.Params.Forks.NativeToken = chain.NewFork(0)
+		chainConfig.Params.NativeToken = p.nativeToken
+	}
+
+	if p.allowlist != nil {
+		chainConfig.Params.Forks.Allowlist = chain.NewFork(0)
+		chainConfig.Params.Allowlist = p.allowlist
+	}
+
 	// Predeploy staking smart contract if needed
 	if p.shouldPredeployStakingSC() {
 		stakingAccount, err := p.predeployStakingSC()
@@ -316,11 +517,72 @@ func (p *genesisParams) initGenesisConfig() error {
 		return err
 	}
 
+	if p.allocFile != "" {
+		allocFile, err := p.initAllocFile(chainConfig.Genesis.Alloc)
+		if err != nil {
+			return err
+		}
+
+		chainConfig.Genesis.AllocFile = allocFile
+	}
+
 	p.genesisConfig = chainConfig
 
 	return nil
 }
 
+// initAllocFile verifies the accounts of the allocation file don't collide with the genesis allocation,
+// and returns its path relative to the genesis file
+func (p *genesisParams) initAllocFile(alloc map[types.Address]*chain.GenesisAccount) (string, error) {
+	if err := chain.ReadAllocFile(p.allocFile, func(addr types.Address, _ *chain.GenesisAccount) error {
+		if _, ok := alloc[addr]; ok {
+			return fmt.Errorf("account %s is allocated by both the genesis and the allocation file", addr)
+		}
+
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("invalid allocation file %s: %w", p.allocFile, err)
+	}
+
+	allocPath, err := filepath.Abs(p.allocFile)
+	if err != nil {
+		return "", err
+	}
+
+	genesisDir, err := filepath.Abs(filepath.Dir(p.genesisPath))
+	if err != nil {
+		return "", err
+	}
+
+	if relPath, err := filepath.Rel(genesisDir, allocPath); err == nil {
+		return relPath, nil
+	}
+
+	return allocPath, nil
+}
+
+// initStakes parses the amounts pre-staked by the initial validators
+func (p *genesisParams) initStakes() error {
+	if !p.isPos {
+		return nil
+	}
+
+	stakedAmount, err := types.ParseUint256orHex(&p.stakedAmountRaw)
+	if err != nil {
+		return fmt.Errorf("failed to parse staked amount %s: %w", p.stakedAmountRaw, err)
+	}
+
+	validatorStakes, err := parseValidatorStakes(p.validatorStakesRaw)
+	if err != nil {
+		return err
+	}
+
+	p.stakedAmount = stakedAmount
+	p.validatorStakes = validatorStakes
+
+	return nil
+}
+
 func (p *genesisParams) shouldPredeployStakingSC() bool {
 	// If the consensus selected is IBFT / Dev and the mechanism is Proof of Stake,
 	// deploy the Staking SC
@@ -333,6 +595,8 @@ func (p *genesisParams) predeployStakingSC() (*chain.GenesisAccount, error) {
 		stakingHelper.PredeployParams{
 			MinValidatorCount: p.minNumValidators,
 			MaxValidatorCount: p.maxNumValidators,
+			StakedAmount:      p.stakedAmount,
+			ValidatorStakes:   p.validatorStakes,
 		})
 	if predeployErr != nil {
 		return nil, predeployErr
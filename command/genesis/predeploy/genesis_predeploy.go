@@ -50,6 +50,8 @@ func setFlags(cmd *cobra.Command) {
 		[]string{},
 		"the constructor arguments, if any",
 	)
+
+	_ = cmd.MarkFlagFilename(chainFlag)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
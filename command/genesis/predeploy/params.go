@@ -133,6 +133,18 @@ func (p *predeployParams) updateGenesisConfig() error {
 		return errAddressTaken
 	}
 
+	if allocFile := p.genesisConfig.Genesis.AllocFilePath(); allocFile != "" {
+		if err := chain.ReadAllocFile(allocFile, func(addr types.Address, _ *chain.GenesisAccount) error {
+			if addr == p.address {
+				return errAddressTaken
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
 	predeployAccount, err := predeployment.GenerateGenesisAccountFromFile(
 		p.artifactsPath,
 		p.constructorArgs,
@@ -2,6 +2,8 @@ package genesis
 
 import (
 	"bytes"
+
+	"github.com/0xPolygon/polygon-edge/command/genesis/validate"
 )
 
 type GenesisResult struct {
@@ -16,3 +18,17 @@ func (r *GenesisResult) GetOutput() string {
 
 	return buffer.String()
 }
+
+type GenesisWizardResult struct {
+	GenesisResult
+	Validation *validate.GenesisValidateResult `json:"validation"`
+}
+
+func (r *GenesisWizardResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(r.GenesisResult.GetOutput())
+	buffer.WriteString(r.Validation.GetOutput())
+
+	return buffer.String()
+}
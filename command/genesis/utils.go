@@ -2,6 +2,7 @@ package genesis
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 
@@ -57,16 +58,17 @@ func fillPremineMap(
 	premine []string,
 ) error {
 	for _, prem := range premine {
-		var addr types.Address
-
-		val := command.DefaultPremineBalance
+		rawAddr, val := prem, command.DefaultPremineBalance
 
 		if indx := strings.Index(prem, ":"); indx != -1 {
 			// <addr>:<balance>
-			addr, val = types.StringToAddress(prem[:indx]), prem[indx+1:]
-		} else {
-			// <addr>
-			addr = types.StringToAddress(prem)
+			rawAddr, val = prem[:indx], prem[indx+1:]
+		}
+
+		// the address is parsed strictly, as a mistyped address would premine some other account
+		var addr types.Address
+		if err := addr.UnmarshalText([]byte(rawAddr)); err != nil {
+			return fmt.Errorf("invalid premine address %s: %w", rawAddr, err)
 		}
 
 		amount, err := types.ParseUint256orHex(&val)
@@ -81,3 +83,26 @@ func fillPremineMap(
 
 	return nil
 }
+
+// parseValidatorStakes parses the amounts staked by the validators, given in the <address>:<amount> format
+func parseValidatorStakes(rawStakes []string) (map[types.Address]*big.Int, error) {
+	stakes := make(map[types.Address]*big.Int, len(rawStakes))
+
+	for _, rawStake := range rawStakes {
+		indx := strings.Index(rawStake, ":")
+		if indx == -1 {
+			return nil, fmt.Errorf("invalid validator stake %s, expected <address>:<amount>", rawStake)
+		}
+
+		addr, val := types.StringToAddress(rawStake[:indx]), rawStake[indx+1:]
+
+		amount, err := types.ParseUint256orHex(&val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse amount %s: %w", val, err)
+		}
+
+		stakes[addr] = amount
+	}
+
+	return stakes, nil
+}
@@ -0,0 +1,54 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0xPolygon/polygon-edge/command"
+)
+
+func GetCommand() *cobra.Command {
+	genesisValidateCmd := &cobra.Command{
+		Use: "validate",
+		Short: "Checks the genesis file for common mistakes: duplicate validators, stakes overflowing " +
+			"the staking contract, bad premine addresses and inconsistent forks",
+		Run: runCommand,
+	}
+
+	setFlags(genesisValidateCmd)
+
+	return genesisValidateCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		chainFlag,
+		fmt.Sprintf("./%s", command.DefaultGenesisFileName),
+		"the genesis file to validate",
+	)
+
+	_ = cmd.MarkFlagFilename(chainFlag)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+
+	result, err := ValidateFile(params.genesisPath)
+	if err != nil {
+		outputter.SetError(err)
+		outputter.WriteOutput()
+
+		return
+	}
+
+	outputter.SetCommandResult(result)
+	outputter.WriteOutput()
+
+	// exit with a non-zero code, so the invalid genesis can be detected by scripts
+	if !result.Valid {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,419 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
+	stakingHelper "github.com/0xPolygon/polygon-edge/helper/staking"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+const (
+	chainFlag = "chain"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+var (
+	params = &validateParams{}
+)
+
+type validateParams struct {
+	genesisPath string
+}
+
+// genesisValidator collects the mistakes found in a genesis
+type genesisValidator struct {
+	path string
+
+	chain *chain.Chain
+	// allocAddrs are the addresses of the genesis alloc as they are written in the file,
+	// since the chain parses the invalid ones into some address silently
+	allocAddrs []string
+
+	findings []Finding
+}
+
+// ValidateFile checks the genesis file for common mistakes, an error is returned only
+// if the file can't be parsed at all
+func ValidateFile(path string) (*GenesisValidateResult, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis file %s: %w", path, err)
+	}
+
+	// the chain isn't imported, as the import fails on the first inconsistent fork
+	var cc *chain.Chain
+	if err := json.Unmarshal(content, &cc); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis file %s: %w", path, err)
+	}
+
+	if cc == nil || cc.Genesis == nil || cc.Params == nil {
+		return nil, fmt.Errorf("genesis file %s has no genesis or params", path)
+	}
+
+	raw := struct {
+		Genesis struct {
+			Alloc map[string]json.RawMessage `json:"alloc"`
+		} `json:"genesis"`
+	}{}
+
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis alloc of %s: %w", path, err)
+	}
+
+	v := &genesisValidator{
+		path:       path,
+		chain:      cc,
+		allocAddrs: make([]string, 0, len(raw.Genesis.Alloc)),
+		findings:   make([]Finding, 0),
+	}
+
+	for addr := range raw.Genesis.Alloc {
+		v.allocAddrs = append(v.allocAddrs, addr)
+	}
+
+	v.checkChain()
+	v.checkForks()
+	v.checkAlloc()
+
+	if ibftConfig, ok := cc.Params.Engine["ibft"].(map[string]interface{}); ok {
+		v.checkIBFT(ibftConfig)
+	}
+
+	return v.result(), nil
+}
+
+func (v *genesisValidator) errorf(check, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{
+		Check:    check,
+		Severity: severityError,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *genesisValidator) warnf(check, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{
+		Check:    check,
+		Severity: severityWarning,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *genesisValidator) checkChain() {
+	if v.chain.Params.ChainID <= 0 {
+		v.errorf("chain", "the chain ID %d isn't positive", v.chain.Params.ChainID)
+	}
+
+	if engines := len(v.chain.Params.Engine); engines != 1 {
+		v.errorf("chain", "expected one consensus engine but found %d", engines)
+	}
+
+	if len(v.chain.Bootnodes) == 0 {
+		v.warnf("chain", "no bootnodes are set, the nodes can't discover each other")
+	}
+}
+
+// checkForks checks the Ethereum forks are activated in order, and the IBFT forks cover the chain
+// from the genesis without gaps or overlaps
+func (v *genesisValidator) checkForks() {
+	if v.chain.Params.Forks != nil {
+		if err := v.chain.Params.Forks.Validate(); err != nil {
+			v.errorf("forks", "%v", err)
+		}
+	}
+
+	ibftConfig, ok := v.chain.Params.Engine["ibft"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	forks, err := fork.GetIBFTForks(ibftConfig)
+	if err != nil {
+		v.errorf("forks", "invalid IBFT forks: %v", err)
+
+		return
+	}
+
+	if len(forks) == 0 || forks[0].From.Value != 0 {
+		v.errorf("forks", "no IBFT fork begins at the genesis")
+	}
+
+	for idx, f := range forks {
+		if f.To != nil && f.To.Value < f.From.Value {
+			v.errorf("forks", "the IBFT fork from block %d ends before it begins, at block %d", f.From.Value, f.To.Value)
+		}
+
+		if idx == len(forks)-1 {
+			if f.To != nil {
+				v.warnf("forks", "no IBFT fork covers the blocks after block %d, the chain halts there", f.To.Value)
+			}
+
+			break
+		}
+
+		next := forks[idx+1]
+
+		switch {
+		case f.To == nil:
+			v.errorf("forks", "the IBFT fork from block %d has no end, but is followed by the fork from block %d",
+				f.From.Value, next.From.Value)
+		case next.From.Value <= f.To.Value:
+			v.errorf("forks", "the IBFT fork from block %d overlaps the fork from block %d",
+				next.From.Value, f.From.Value)
+		case next.From.Value > f.To.Value+1:
+			v.errorf("forks", "no IBFT fork covers the blocks %d to %d", f.To.Value+1, next.From.Value-1)
+		}
+	}
+}
+
+// checkAlloc checks the addresses and balances of the premined accounts
+func (v *genesisValidator) checkAlloc() {
+	seen := make(map[types.Address]string, len(v.allocAddrs))
+
+	for _, raw := range v.allocAddrs {
+		var addr types.Address
+		if err := addr.UnmarshalText([]byte(raw)); err != nil {
+			v.errorf("premine", "the premined address %q isn't a hex address of %d bytes", raw, types.AddressLength)
+
+			continue
+		}
+
+		if prev, ok := seen[addr]; ok {
+			v.errorf("premine", "the address %s is premined twice, as %q and %q, only one is kept", addr, prev, raw)
+		}
+
+		seen[addr] = raw
+	}
+
+	runtime := precompiled.NewPrecompiled()
+	custom := make(map[types.Address]struct{}, len(v.chain.Params.Precompiles))
+
+	for _, p := range v.chain.Params.Precompiles {
+		custom[p.Address] = struct{}{}
+	}
+
+	supply := big.NewInt(0)
+
+	for addr, account := range v.chain.Genesis.Alloc {
+		if addr == types.ZeroAddress {
+			v.warnf("premine", "the zero address is premined, its balance can't be spent")
+		}
+
+		_, isCustom := custom[addr]
+		if len(account.Code) != 0 && (runtime.IsPrecompiled(addr) || isCustom) {
+			v.errorf("premine", "the code predeployed at %s is never run, it's the address of a precompiled contract", addr)
+		}
+
+		if account.Balance != nil {
+			supply.Add(supply, account.Balance)
+		}
+	}
+
+	if allocFile := v.allocFilePath(); allocFile != "" {
+		if err := chain.ReadAllocFile(allocFile, func(addr types.Address, account *chain.GenesisAccount) error {
+			if _, ok := v.chain.Genesis.Alloc[addr]; ok {
+				v.errorf("premine", "the address %s is allocated by both the genesis and the allocation file", addr)
+			}
+
+			if account.Balance != nil {
+				supply.Add(supply, account.Balance)
+			}
+
+			return nil
+		}); err != nil {
+			v.errorf("premine", "invalid allocation file %s: %v", allocFile, err)
+		}
+	}
+
+	if supply.Cmp(stakingHelper.MaxTotalStakedAmount) > 0 {
+		v.errorf("premine", "the total premined balance %s overflows 256 bits", supply)
+	}
+}
+
+// allocFilePath returns the path of the allocation file, relative to the genesis file
+func (v *genesisValidator) allocFilePath() string {
+	allocFile := v.chain.Genesis.AllocFile
+	if allocFile == "" || filepath.IsAbs(allocFile) {
+		return allocFile
+	}
+
+	return filepath.Join(filepath.Dir(v.path), allocFile)
+}
+
+// checkIBFT checks the validators of the genesis and of the PoA forks, and the stakes of the PoS genesis fork
+func (v *genesisValidator) checkIBFT(ibftConfig map[string]interface{}) {
+	forks, err := fork.GetIBFTForks(ibftConfig)
+	if err != nil || len(forks) == 0 {
+		// reported by the fork checks
+		return
+	}
+
+	for _, f := range forks {
+		if f.Type == fork.PoA && f.Validators != nil {
+			v.checkDuplicateValidators(fmt.Sprintf("the PoA fork from block %d", f.From.Value), f.Validators)
+		}
+	}
+
+	genesisFork := forks[0]
+	if genesisFork.From.Value != 0 {
+		return
+	}
+
+	vals, err := v.genesisValidators(genesisFork.ValidatorType)
+	if err != nil {
+		v.errorf("validators", "%v", err)
+
+		return
+	}
+
+	if vals.Len() == 0 {
+		v.errorf("validators", "there are no validators in the genesis, no block can be sealed")
+
+		return
+	}
+
+	v.checkDuplicateValidators("the genesis", vals)
+
+	if genesisFork.Type == fork.PoS {
+		v.checkStake(vals)
+	}
+}
+
+// genesisValidators decodes the validators of the IBFT extra of the genesis
+func (v *genesisValidator) genesisValidators(validatorType validators.ValidatorType) (validators.Validators, error) {
+	extraData := v.chain.Genesis.ExtraData
+	if len(extraData) < signer.IstanbulExtraVanity {
+		return nil, fmt.Errorf("the genesis extra data of %d bytes has no IBFT extra", len(extraData))
+	}
+
+	var committedSeals signer.Seals
+
+	switch validatorType {
+	case validators.ECDSAValidatorType:
+		committedSeals = new(signer.SerializedSeal)
+	case validators.BLSValidatorType:
+		committedSeals = new(signer.AggregatedSeal)
+	}
+
+	extra := &signer.IstanbulExtra{
+		Validators:     validators.NewValidatorSetFromType(validatorType),
+		ProposerSeal:   []byte{},
+		CommittedSeals: committedSeals,
+	}
+
+	if err := extra.UnmarshalRLP(extraData[signer.IstanbulExtraVanity:]); err != nil {
+		return nil, fmt.Errorf("failed to decode the genesis IBFT extra as %s validators: %w", validatorType, err)
+	}
+
+	return extra.Validators, nil
+}
+
+// checkDuplicateValidators checks no validator address or BLS public key is given twice,
+// as the duplicates count twice in the quorum
+func (v *genesisValidator) checkDuplicateValidators(source string, vals validators.Validators) {
+	addrs := make(map[types.Address]struct{}, vals.Len())
+	keys := make(map[string]types.Address, vals.Len())
+
+	for idx := 0; idx < vals.Len(); idx++ {
+		val := vals.At(uint64(idx))
+
+		if _, ok := addrs[val.Addr()]; ok {
+			v.errorf("validators", "the validator %s is given twice in %s", val.Addr(), source)
+		}
+
+		addrs[val.Addr()] = struct{}{}
+
+		bls, ok := val.(*validators.BLSValidator)
+		if !ok {
+			continue
+		}
+
+		if prev, ok := keys[string(bls.BLSPublicKey)]; ok && prev != bls.Address {
+			v.errorf("validators", "the validators %s and %s share the same BLS public key in %s",
+				prev, bls.Address, source)
+		}
+
+		keys[string(bls.BLSPublicKey)] = bls.Address
+	}
+}
+
+// checkStake checks the staking contract holds the genesis validators, and their voting power
+// fits in the contract
+func (v *genesisValidator) checkStake(vals validators.Validators) {
+	account, ok := v.chain.Genesis.Alloc[staking.AddrStakingContract]
+	if !ok || len(account.Code) == 0 {
+		v.errorf("stake", "the staking contract isn't predeployed at %s for the PoS genesis", staking.AddrStakingContract)
+
+		return
+	}
+
+	stake := stakingHelper.ReadGenesisStake(account)
+
+	count := uint64(vals.Len())
+	if count < stake.MinValidatorCount || count > stake.MaxValidatorCount {
+		v.errorf("stake", "the %d genesis validators are out of the %d to %d validators of the staking contract",
+			count, stake.MinValidatorCount, stake.MaxValidatorCount)
+	}
+
+	if len(stake.Validators) != vals.Len() {
+		v.errorf("stake", "the staking contract holds %d validators, but the genesis has %d",
+			len(stake.Validators), vals.Len())
+	}
+
+	total := big.NewInt(0)
+
+	for _, addr := range stake.Validators {
+		if !vals.Includes(addr) {
+			v.errorf("stake", "the staking contract holds the validator %s, which isn't a genesis validator", addr)
+		}
+
+		amount := stake.Stakes[addr]
+		if amount.Cmp(stakingHelper.MinStakedAmount) < 0 {
+			v.warnf("stake", "the validator %s stakes %s, below the minimum stake of %s",
+				addr, amount, stakingHelper.MinStakedAmount)
+		}
+
+		total.Add(total, amount)
+	}
+
+	if total.Cmp(stakingHelper.MaxTotalStakedAmount) > 0 {
+		v.errorf("stake", "the voting power of the validators %s overflows the maximum of %s",
+			total, stakingHelper.MaxTotalStakedAmount)
+	} else if total.Cmp(stake.TotalStaked) != 0 {
+		v.errorf("stake", "the total stake %s of the staking contract isn't the sum %s of the validator stakes",
+			stake.TotalStaked, total)
+	}
+
+	if account.Balance == nil || account.Balance.Cmp(stake.TotalStaked) != 0 {
+		v.errorf("stake", "the balance of the staking contract isn't its total stake %s", stake.TotalStaked)
+	}
+}
+
+func (v *genesisValidator) result() *GenesisValidateResult {
+	result := &GenesisValidateResult{
+		Source:   v.path,
+		Valid:    true,
+		Findings: v.findings,
+	}
+
+	for _, finding := range v.findings {
+		if finding.Severity == severityError {
+			result.Valid = false
+		}
+	}
+
+	return result
+}
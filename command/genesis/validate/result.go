@@ -0,0 +1,49 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type Finding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type GenesisValidateResult struct {
+	Source   string    `json:"source"`
+	Valid    bool      `json:"valid"`
+	Findings []Finding `json:"findings"`
+}
+
+func (r *GenesisValidateResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[GENESIS VALIDATE]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Source|%s", r.Source),
+		fmt.Sprintf("Valid|%t", r.Valid),
+	}))
+	buffer.WriteString("\n")
+
+	if len(r.Findings) == 0 {
+		buffer.WriteString("\nNo mistake found\n")
+
+		return buffer.String()
+	}
+
+	for _, finding := range r.Findings {
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Severity|%s", finding.Severity),
+			fmt.Sprintf("Check|%s", finding.Check),
+			fmt.Sprintf("Problem|%s", finding.Message),
+		}))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
@@ -7,17 +7,21 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command"
 	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/server"
 	"github.com/0xPolygon/polygon-edge/server/proto"
 	txpoolOp "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/ryanuber/columnize"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -44,8 +48,42 @@ func HandleSignals(
 	outputter command.OutputFormatter,
 ) error {
 	signalCh := common.GetTerminationSignalCh()
-	sig := <-signalCh
 
+	return closeOnSignal(<-signalCh, signalCh, closeFn, outputter)
+}
+
+// HandleSignalsWithReload handles the signals like HandleSignals,
+// except SIGHUP which calls reloadFn instead of shutting down the client
+func HandleSignalsWithReload(
+	closeFn func(),
+	reloadFn func(),
+	outputter command.OutputFormatter,
+) error {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(
+		signalCh,
+		os.Interrupt,
+		syscall.SIGTERM,
+		syscall.SIGHUP,
+	)
+
+	for {
+		sig := <-signalCh
+		if sig != syscall.SIGHUP {
+			return closeOnSignal(sig, signalCh, closeFn, outputter)
+		}
+
+		reloadFn()
+	}
+}
+
+// closeOnSignal shuts down the client gracefully, unless another signal is caught in the meantime
+func closeOnSignal(
+	sig os.Signal,
+	signalCh <-chan os.Signal,
+	closeFn func(),
+	outputter command.OutputFormatter,
+) error {
 	closeMessage := fmt.Sprintf("\n[SIGNAL] Caught signal: %v\n", sig)
 	closeMessage += "Gracefully shutting down client...\n"
 
@@ -251,3 +289,63 @@ func SetRequiredFlags(cmd *cobra.Command, requiredFlags []string) {
 		_ = cmd.MarkFlagRequired(requiredFlag)
 	}
 }
+
+var (
+	errNotTerminal         = errors.New("unable to prompt for passphrase, the input isn't a terminal")
+	errEmptyPassphrase     = errors.New("empty passphrase")
+	errPassphrasesMismatch = errors.New("passphrases don't match")
+)
+
+// ReadPassphrase prompts for a passphrase on the terminal, twice if it needs to be confirmed
+func ReadPassphrase(prompt string, confirm bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errNotTerminal
+	}
+
+	read := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		defer fmt.Fprintln(os.Stderr)
+
+		passphrase, err := term.ReadPassword(fd)
+
+		return string(passphrase), err
+	}
+
+	passphrase, err := read(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase == "" {
+		return "", errEmptyPassphrase
+	}
+
+	if confirm {
+		repeated, err := read("Repeat " + prompt)
+		if err != nil {
+			return "", err
+		}
+
+		if repeated != passphrase {
+			return "", errPassphrasesMismatch
+		}
+	}
+
+	return passphrase, nil
+}
+
+// ReadSecretsPassphrase returns the passphrase of the local secrets from the environment,
+// prompting for it on the terminal if it isn't set there
+func ReadSecretsPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(secrets.PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	passphrase, err := ReadPassphrase("secrets passphrase: ", confirm)
+	if err != nil {
+		return "", fmt.Errorf("%w; set the passphrase by %s", err, secrets.PassphraseEnv)
+	}
+
+	return passphrase, nil
+}
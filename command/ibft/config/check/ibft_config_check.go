@@ -0,0 +1,74 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/server/config"
+)
+
+func GetCommand() *cobra.Command {
+	ibftConfigCheckCmd := &cobra.Command{
+		Use: "check",
+		Short: "Checks the IBFT configuration of the genesis, or the validator set of a running node, " +
+			"for risky parameter combinations and prints how to remediate them",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(ibftConfigCheckCmd)
+
+	return ibftConfigCheckCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		chainFlag,
+		fmt.Sprintf("./%s", command.DefaultGenesisFileName),
+		"the genesis file to check",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.node,
+		nodeFlag,
+		false,
+		"check the latest validator set of the node at the gRPC address instead of the genesis one",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.blockTime,
+		blockTimeFlag,
+		config.DefaultBlockTime,
+		"the block time in seconds the validators are started with",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.faultTolerance,
+		faultToleranceFlag,
+		1,
+		"the number of faulty validators the chain is meant to tolerate",
+	)
+
+	_ = cmd.MarkFlagFilename(chainFlag)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.initValidators(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,188 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+const (
+	chainFlag          = "chain"
+	nodeFlag           = "node"
+	blockTimeFlag      = "block-time"
+	faultToleranceFlag = "fault-tolerance"
+)
+
+var (
+	params = &checkParams{}
+)
+
+var (
+	errIBFTConfigNotFound = errors.New(`"ibft" config doesn't exist in "engine" of genesis.json`)
+	errInvalidBlockTime   = errors.New("block time must be at least 1 second")
+	errNoGenesisFork      = errors.New("no IBFT fork begins at the genesis")
+)
+
+type checkParams struct {
+	genesisPath    string
+	node           bool
+	blockTime      uint64
+	faultTolerance uint64
+
+	genesisConfig *chain.Chain
+	ibftParams    *ibft.CheckParams
+}
+
+func (p *checkParams) initRawParams() error {
+	if p.blockTime < 1 {
+		return errInvalidBlockTime
+	}
+
+	cc, err := chain.Import(p.genesisPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load chain config from %s: %w",
+			p.genesisPath,
+			err,
+		)
+	}
+
+	ibftConfig, ok := cc.Params.Engine["ibft"].(map[string]interface{})
+	if !ok {
+		return errIBFTConfigNotFound
+	}
+
+	ibftParams, err := ibft.NewCheckParams(ibftConfig)
+	if err != nil {
+		return err
+	}
+
+	ibftParams.BlockTime = time.Duration(p.blockTime) * time.Second
+	ibftParams.FaultTolerance = p.faultTolerance
+
+	p.genesisConfig = cc
+	p.ibftParams = ibftParams
+
+	return nil
+}
+
+// initValidators sets the validator set to check, the latest one of the node or the genesis one
+func (p *checkParams) initValidators(grpcAddress string) error {
+	if p.node {
+		return p.initNodeValidators(grpcAddress)
+	}
+
+	return p.initGenesisValidators()
+}
+
+func (p *checkParams) initNodeValidators(grpcAddress string) error {
+	ibftClient, err := helper.GetIBFTOperatorClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := ibftClient.GetSnapshot(context.Background(), &ibftOp.SnapshotReq{Latest: true})
+	if err != nil {
+		return err
+	}
+
+	p.ibftParams.Height = snapshot.Number
+
+	fork := p.ibftParams.Fork()
+	if fork == nil {
+		return fmt.Errorf("no IBFT fork covers the block %d of the node", snapshot.Number)
+	}
+
+	set := validators.NewValidatorSetFromType(fork.ValidatorType)
+
+	for _, v := range snapshot.Validators {
+		validatorType, err := validators.ParseValidatorType(v.Type)
+		if err != nil {
+			return err
+		}
+
+		validator, err := validators.NewValidatorFromType(validatorType)
+		if err != nil {
+			return err
+		}
+
+		if err := validator.SetFromBytes(v.Data); err != nil {
+			return err
+		}
+
+		if err := set.Add(validator); err != nil {
+			return err
+		}
+	}
+
+	p.ibftParams.Validators = set
+
+	return nil
+}
+
+func (p *checkParams) initGenesisValidators() error {
+	fork := p.ibftParams.Fork()
+	if fork == nil {
+		return errNoGenesisFork
+	}
+
+	extraData := p.genesisConfig.Genesis.ExtraData
+	if len(extraData) < signer.IstanbulExtraVanity {
+		return fmt.Errorf("the genesis extra data of %d bytes has no IBFT extra", len(extraData))
+	}
+
+	var committedSeals signer.Seals
+
+	switch fork.ValidatorType {
+	case validators.ECDSAValidatorType:
+		committedSeals = new(signer.SerializedSeal)
+	case validators.BLSValidatorType:
+		committedSeals = new(signer.AggregatedSeal)
+	}
+
+	extra := &signer.IstanbulExtra{
+		Validators:     validators.NewValidatorSetFromType(fork.ValidatorType),
+		ProposerSeal:   []byte{},
+		CommittedSeals: committedSeals,
+	}
+
+	if err := extra.UnmarshalRLP(extraData[signer.IstanbulExtraVanity:]); err != nil {
+		return fmt.Errorf("failed to decode the genesis IBFT extra: %w", err)
+	}
+
+	p.ibftParams.Validators = extra.Validators
+
+	return nil
+}
+
+func (p *checkParams) getResult() *IBFTConfigCheckResult {
+	result := &IBFTConfigCheckResult{
+		Source:     p.genesisPath,
+		Height:     p.ibftParams.Height,
+		Validators: p.ibftParams.Validators.Len(),
+		Findings:   make([]ConfigFinding, 0),
+	}
+
+	if p.node {
+		result.Source = "node"
+	}
+
+	for _, finding := range ibft.CheckConfig(p.ibftParams) {
+		result.Findings = append(result.Findings, ConfigFinding{
+			Check:       finding.Check,
+			Severity:    string(finding.Severity),
+			Message:     finding.Message,
+			Remediation: finding.Remediation,
+		})
+	}
+
+	return result
+}
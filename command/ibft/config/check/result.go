@@ -0,0 +1,53 @@
+package check
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ConfigFinding struct {
+	Check       string `json:"check"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation"`
+}
+
+type IBFTConfigCheckResult struct {
+	Source     string          `json:"source"`
+	Height     uint64          `json:"height"`
+	Validators int             `json:"validators"`
+	Findings   []ConfigFinding `json:"findings"`
+}
+
+func (r *IBFTConfigCheckResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT CONFIG CHECK]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Source|%s", r.Source),
+		fmt.Sprintf("Block|%d", r.Height),
+		fmt.Sprintf("Validators|%d", r.Validators),
+	}))
+	buffer.WriteString("\n")
+
+	if len(r.Findings) == 0 {
+		buffer.WriteString("\nNo risky parameter combination found\n")
+
+		return buffer.String()
+	}
+
+	for _, finding := range r.Findings {
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Severity|%s", finding.Severity),
+			fmt.Sprintf("Check|%s", finding.Check),
+			fmt.Sprintf("Problem|%s", finding.Message),
+			fmt.Sprintf("Remediation|%s", finding.Remediation),
+		}))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/ibft/config/check"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	ibftConfigCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Top level command for inspecting the IBFT configuration. Only accepts subcommands.",
+	}
+
+	registerSubcommands(ibftConfigCmd)
+
+	return ibftConfigCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		// ibft config check
+		check.GetCommand(),
+	)
+}
@@ -3,6 +3,8 @@ package ibft
 import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/command/ibft/candidates"
+	"github.com/0xPolygon/polygon-edge/command/ibft/config"
+	"github.com/0xPolygon/polygon-edge/command/ibft/maintenance"
 	"github.com/0xPolygon/polygon-edge/command/ibft/propose"
 	"github.com/0xPolygon/polygon-edge/command/ibft/quorum"
 	"github.com/0xPolygon/polygon-edge/command/ibft/snapshot"
@@ -38,5 +40,9 @@ func registerSubcommands(baseCmd *cobra.Command) {
 		_switch.GetCommand(),
 		// ibft quorum
 		quorum.GetCommand(),
+		// ibft maintenance
+		maintenance.GetCommand(),
+		// ibft config
+		config.GetCommand(),
 	)
 }
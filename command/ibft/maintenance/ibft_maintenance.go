@@ -0,0 +1,72 @@
+package maintenance
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+func GetCommand() *cobra.Command {
+	ibftMaintenanceCmd := &cobra.Command{
+		Use: "maintenance",
+		Short: "Announces a maintenance window of the validator, " +
+			"during which the other validators deprioritize it in the proposer selection",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(ibftMaintenanceCmd)
+
+	return ibftMaintenanceCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(
+		&params.from,
+		fromFlag,
+		0,
+		"the first block of the maintenance window, which must not be mined yet",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.to,
+		toFlag,
+		0,
+		"the last block of the maintenance window",
+	)
+
+	cmd.Flags().StringVar(
+		&params.gasPrice,
+		gasPriceFlag,
+		"0",
+		"the gas price of the announcement transaction",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.cancel,
+		cancelFlag,
+		false,
+		"cancel the announced maintenance window",
+	)
+
+	cmd.MarkFlagsRequiredTogether(fromFlag, toFlag)
+	cmd.MarkFlagsMutuallyExclusive(fromFlag, cancelFlag)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.announceMaintenance(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
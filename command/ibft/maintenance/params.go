@@ -0,0 +1,83 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+)
+
+const (
+	fromFlag     = "from"
+	toFlag       = "to"
+	gasPriceFlag = "gas-price"
+	cancelFlag   = "cancel"
+)
+
+var (
+	errInvalidWindow = errors.New("invalid maintenance window")
+	errNoWindow      = errors.New("either the maintenance window or --cancel is required")
+)
+
+var (
+	params = &maintenanceParams{}
+)
+
+type maintenanceParams struct {
+	from     uint64
+	to       uint64
+	gasPrice string
+	cancel   bool
+
+	txHash string
+}
+
+func (p *maintenanceParams) validateFlags() error {
+	if p.cancel {
+		return nil
+	}
+
+	if p.from == 0 && p.to == 0 {
+		return errNoWindow
+	}
+
+	if p.from == 0 || p.to < p.from {
+		return errInvalidWindow
+	}
+
+	return nil
+}
+
+func (p *maintenanceParams) announceMaintenance(grpcAddress string) error {
+	ibftClient, err := helper.GetIBFTOperatorClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ibftClient.AnnounceMaintenance(
+		context.Background(),
+		&ibftOp.MaintenanceReq{
+			From:     p.from,
+			To:       p.to,
+			GasPrice: p.gasPrice,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	p.txHash = resp.TxHash
+
+	return nil
+}
+
+func (p *maintenanceParams) getResult() command.CommandResult {
+	return &IBFTMaintenanceResult{
+		From:   p.from,
+		To:     p.to,
+		Cancel: p.cancel,
+		TxHash: p.txHash,
+	}
+}
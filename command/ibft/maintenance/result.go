@@ -0,0 +1,34 @@
+package maintenance
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type IBFTMaintenanceResult struct {
+	From   uint64 `json:"from"`
+	To     uint64 `json:"to"`
+	Cancel bool   `json:"cancel"`
+	TxHash string `json:"tx_hash"`
+}
+
+func (r *IBFTMaintenanceResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT MAINTENANCE]\n")
+
+	if r.Cancel {
+		buffer.WriteString("Cancellation of the maintenance window broadcasted\n")
+	} else {
+		buffer.WriteString(fmt.Sprintf("Maintenance window from block %d to %d broadcasted\n", r.From, r.To))
+	}
+
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Transaction hash|%s", r.TxHash),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -36,6 +36,8 @@ func setFlags(cmd *cobra.Command) {
 		0,
 		"the height to switch the quorum calculation",
 	)
+
+	_ = cmd.MarkFlagFilename(chainFlag)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
@@ -99,7 +99,16 @@ func setFlags(cmd *cobra.Command) {
 			"",
 			"the maximum number of validators in the validator set for PoS",
 		)
+
+		cmd.Flags().StringVar(
+			&params.validatorContractRaw,
+			validatorContract,
+			"",
+			"the contract the validators are read from at every epoch for PoS, the staking contract if omitted",
+		)
 	}
+
+	_ = cmd.MarkFlagFilename(chainFlag)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
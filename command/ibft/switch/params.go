@@ -9,6 +9,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/validators"
@@ -21,6 +22,7 @@ const (
 	fromFlag          = "from"
 	minValidatorCount = "min-validator-count"
 	maxValidatorCount = "max-validator-count"
+	validatorContract = "validator-contract"
 )
 
 var (
@@ -59,6 +61,8 @@ type switchParams struct {
 	maxValidatorCount    *uint64
 	minValidatorCountRaw string
 	minValidatorCount    *uint64
+	validatorContractRaw string
+	validatorContract    *types.Address
 
 	genesisConfig *chain.Chain
 }
@@ -220,9 +224,25 @@ func (p *switchParams) initPoSConfig() error {
 			)
 		}
 
+		if p.validatorContractRaw != "" {
+			return fmt.Errorf(
+				"doesn't support validator contract in %s",
+				string(p.ibftType),
+			)
+		}
+
 		return nil
 	}
 
+	if p.validatorContractRaw != "" {
+		contract := types.StringToAddress(p.validatorContractRaw)
+		if contract == types.ZeroAddress {
+			return fmt.Errorf("invalid validator contract %s", p.validatorContractRaw)
+		}
+
+		p.validatorContract = &contract
+	}
+
 	if p.minValidatorCountRaw != "" {
 		value, err := types.ParseUint64orHex(&p.minValidatorCountRaw)
 		if err != nil {
@@ -312,6 +332,7 @@ func (p *switchParams) updateGenesisConfig() error {
 		p.ibftValidators,
 		p.maxValidatorCount,
 		p.minValidatorCount,
+		p.validatorContract,
 	)
 }
 
@@ -344,6 +365,10 @@ func (p *switchParams) getResult() command.CommandResult {
 		result.Deployment = &common.JSONNumber{Value: *p.deployment}
 	}
 
+	if p.validatorContract != nil {
+		result.ValidatorContract = p.validatorContract
+	}
+
 	if p.minValidatorCount != nil {
 		result.MinValidatorCount = common.JSONNumber{Value: *p.minValidatorCount}
 	} else {
@@ -370,6 +395,7 @@ func appendIBFTForks(
 	// PoS
 	maxValidatorCount *uint64,
 	minValidatorCount *uint64,
+	validatorContract *types.Address,
 ) error {
 	ibftConfig, ok := cc.Params.Engine["ibft"].(map[string]interface{})
 	if !ok {
@@ -383,8 +409,10 @@ func appendIBFTForks(
 
 	lastFork := ibftForks[len(ibftForks)-1]
 
+	// PoS can switch to another validator contract along with the same validator type
 	if (ibftType == lastFork.Type) &&
-		(validatorType == lastFork.ValidatorType) {
+		(validatorType == lastFork.ValidatorType) &&
+		(ibftType != fork.PoS || sameValidatorContract(validatorContract, lastFork.ValidatorContract)) {
 		return ErrSameIBFTAndValidatorType
 	}
 
@@ -420,6 +448,8 @@ func appendIBFTForks(
 		if minValidatorCount != nil {
 			newFork.MinValidatorCount = &common.JSONNumber{Value: *minValidatorCount}
 		}
+
+		newFork.ValidatorContract = validatorContract
 	}
 
 	ibftForks = append(ibftForks, &newFork)
@@ -432,3 +462,17 @@ func appendIBFTForks(
 
 	return nil
 }
+
+// sameValidatorContract returns whether the forks read the validators from the same contract,
+// the staking contract if not set
+func sameValidatorContract(a, b *types.Address) bool {
+	contract := func(addr *types.Address) types.Address {
+		if addr == nil {
+			return staking.AddrStakingContract
+		}
+
+		return *addr
+	}
+
+	return contract(a) == contract(b)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/validators"
 )
 
@@ -18,6 +19,7 @@ type IBFTSwitchResult struct {
 	Deployment        *common.JSONNumber       `json:"deployment,omitempty"`
 	MaxValidatorCount common.JSONNumber        `json:"maxValidatorCount"`
 	MinValidatorCount common.JSONNumber        `json:"minValidatorCount"`
+	ValidatorContract *types.Address           `json:"validatorContract,omitempty"`
 }
 
 func (r *IBFTSwitchResult) GetOutput() string {
@@ -42,6 +44,10 @@ func (r *IBFTSwitchResult) GetOutput() string {
 			fmt.Sprintf("MaxValidatorCount|%d", r.MaxValidatorCount.Value),
 			fmt.Sprintf("MinValidatorCount|%d", r.MinValidatorCount.Value),
 		)
+
+		if r.ValidatorContract != nil {
+			outputs = append(outputs, fmt.Sprintf("ValidatorContract|%s", r.ValidatorContract))
+		}
 	}
 
 	buffer.WriteString(helper.FormatKV(outputs))
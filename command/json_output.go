@@ -14,7 +14,8 @@ func (jo *JSONOutput) WriteOutput() {
 	if jo.errorOutput != nil {
 		_, _ = fmt.Fprintln(os.Stderr, jo.getErrorOutput())
 
-		return
+		// exit with a non-zero code, so the failure can be detected by scripts
+		os.Exit(1)
 	}
 
 	_, _ = fmt.Fprintln(os.Stdout, jo.getCommandOutput())
@@ -0,0 +1,43 @@
+package dashboard
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	dashboardCmd := &cobra.Command{
+		Use: "dashboard",
+		Short: "Writes the Grafana dashboard of the histograms and the labeled counters exported by the node, " +
+			"to be imported along with a Prometheus data source scraping its metrics endpoint",
+		Run: runCommand,
+	}
+
+	setFlags(dashboardCmd)
+
+	return dashboardCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.output,
+		outputFlag,
+		defaultOutput,
+		"the file the dashboard is written to",
+	)
+
+	_ = cmd.MarkFlagFilename(outputFlag, "json")
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.writeDashboard(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
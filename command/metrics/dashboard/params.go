@@ -0,0 +1,49 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
+)
+
+const (
+	outputFlag = "output"
+
+	defaultOutput = "grafana-dashboard.json"
+)
+
+var (
+	params = &dashboardParams{}
+)
+
+type dashboardParams struct {
+	output string
+
+	panels int
+}
+
+// writeDashboard writes the dashboard generated from the definitions of the metrics,
+// replacing the file written by a previous version
+func (p *dashboardParams) writeDashboard() error {
+	data, err := telemetry.Dashboard()
+	if err != nil {
+		return fmt.Errorf("failed to generate the dashboard: %w", err)
+	}
+
+	if err := os.WriteFile(p.output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write the dashboard: %w", err)
+	}
+
+	p.panels = len(telemetry.Definitions())
+
+	return nil
+}
+
+func (p *dashboardParams) getResult() *DashboardResult {
+	return &DashboardResult{
+		Output: p.output,
+		UID:    telemetry.DashboardUID,
+		Panels: p.panels,
+	}
+}
@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type DashboardResult struct {
+	Output string `json:"output"`
+	UID    string `json:"uid"`
+	Panels int    `json:"panels"`
+}
+
+func (r *DashboardResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[METRICS DASHBOARD]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Output|%s", r.Output),
+		fmt.Sprintf("UID|%s", r.UID),
+		fmt.Sprintf("Panels|%d", r.Panels),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/metrics/dashboard"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	metricsCmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Top level command for the metrics exported by the node. Only accepts subcommands.",
+	}
+
+	registerSubcommands(metricsCmd)
+
+	return metricsCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		dashboard.GetCommand(),
+	)
+}
@@ -11,10 +11,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/0xPolygon/polygon-edge/command/helper"
-	"github.com/0xPolygon/polygon-edge/server/proto"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
+const (
+	congestionFlag = "congestion"
+)
+
+var (
+	congestion bool
+)
+
 func GetCommand() *cobra.Command {
 	monitorCmd := &cobra.Command{
 		Use:   "monitor",
@@ -24,6 +31,13 @@ func GetCommand() *cobra.Command {
 
 	helper.RegisterGRPCAddressFlag(monitorCmd)
 
+	monitorCmd.Flags().BoolVar(
+		&congestion,
+		congestionFlag,
+		false,
+		"log the congestion events instead of the block events",
+	)
+
 	return monitorCmd
 }
 
@@ -44,7 +58,7 @@ func subscribeToEvents(
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
 
-	stream, err := getMonitorStream(ctx, grpcAddress)
+	recv, err := getMonitorStream(ctx, grpcAddress)
 	if err != nil {
 		outputter.SetError(err)
 		outputter.WriteOutput()
@@ -53,25 +67,55 @@ func subscribeToEvents(
 	}
 
 	runSubscribeLoop(
-		stream,
+		recv,
 		outputter,
 	)
 }
 
+// getMonitorStream subscribes to the events being monitored,
+// and returns the function receiving the next event
 func getMonitorStream(
 	ctx context.Context,
 	grpcAddress string,
-) (proto.System_SubscribeClient, error) {
+) (func() (command.CommandResult, error), error) {
 	client, err := helper.GetSystemClientConnection(grpcAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	return client.Subscribe(ctx, &empty.Empty{})
+	if congestion {
+		stream, err := client.SubscribeCongestion(ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		return func() (command.CommandResult, error) {
+			streamEvent, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+
+			return NewCongestionEventResult(streamEvent), nil
+		}, nil
+	}
+
+	stream, err := client.Subscribe(ctx, &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (command.CommandResult, error) {
+		streamEvent, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewBlockEventResult(streamEvent), nil
+	}, nil
 }
 
 func runSubscribeLoop(
-	stream proto.System_SubscribeClient,
+	recv func() (command.CommandResult, error),
 	outputter command.OutputFormatter,
 ) {
 	doneCh := make(chan struct{})
@@ -85,7 +129,7 @@ func runSubscribeLoop(
 		defer close(doneCh)
 
 		for {
-			streamEvent, err := stream.Recv()
+			result, err := recv()
 			if errors.Is(err, io.EOF) {
 				break
 			}
@@ -97,7 +141,7 @@ func runSubscribeLoop(
 				break
 			}
 
-			outputter.SetCommandResult(NewBlockEventResult(streamEvent))
+			outputter.SetCommandResult(result)
 			flushOutput()
 		}
 
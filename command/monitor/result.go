@@ -72,3 +72,38 @@ func (r *BlockEventResult) getCombinedEvents() []BlockchainEvent {
 
 	return append(events, r.Events.Removed...)
 }
+
+type CongestionEventResult struct {
+	Type   string  `json:"type"`
+	Active bool    `json:"active"`
+	Number uint64  `json:"number"`
+	Value  float64 `json:"value"`
+}
+
+func NewCongestionEventResult(e *proto.CongestionEvent) *CongestionEventResult {
+	return &CongestionEventResult{
+		Type:   e.Type,
+		Active: e.Active,
+		Number: e.Number,
+		Value:  e.Value,
+	}
+}
+
+func (r *CongestionEventResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	state := "CLEARED"
+	if r.Active {
+		state = "RAISED"
+	}
+
+	buffer.WriteString("\n[CONGESTION EVENT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Type|%s", r.Type),
+		fmt.Sprintf("State|%s", state),
+		fmt.Sprintf("Block Number|%d", r.Number),
+		fmt.Sprintf("Value|%.4f", r.Value),
+	}))
+
+	return buffer.String()
+}
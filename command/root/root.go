@@ -5,11 +5,14 @@ import (
 	"os"
 
 	"github.com/0xPolygon/polygon-edge/command/backup"
+	"github.com/0xPolygon/polygon-edge/command/chain"
+	"github.com/0xPolygon/polygon-edge/command/db"
 	"github.com/0xPolygon/polygon-edge/command/genesis"
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/command/ibft"
 	"github.com/0xPolygon/polygon-edge/command/license"
 	"github.com/0xPolygon/polygon-edge/command/loadbot"
+	"github.com/0xPolygon/polygon-edge/command/metrics"
 	"github.com/0xPolygon/polygon-edge/command/monitor"
 	"github.com/0xPolygon/polygon-edge/command/peers"
 	"github.com/0xPolygon/polygon-edge/command/secrets"
@@ -28,6 +31,8 @@ type RootCommand struct {
 func NewRootCommand() *RootCommand {
 	rootCommand := &RootCommand{
 		baseCmd: &cobra.Command{
+			// the name of the binary, used by the generated shell completion scripts
+			Use:   "polygon-edge",
 			Short: "Polygon Edge is a framework for building Ethereum-compatible Blockchain networks",
 		},
 	}
@@ -50,6 +55,9 @@ func (rc *RootCommand) registerSubCommands() {
 		loadbot.GetCommand(),
 		ibft.GetCommand(),
 		backup.GetCommand(),
+		chain.GetCommand(),
+		db.GetCommand(),
+		metrics.GetCommand(),
 		genesis.GetCommand(),
 		server.GetCommand(),
 		whitelist.GetCommand(),
@@ -0,0 +1,135 @@
+package secretsexport
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	cmdHelper "github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/keystore"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/secrets/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	dataDirFlag  = "data-dir"
+	keystoreFlag = "keystore"
+)
+
+var (
+	params = &exportParams{}
+)
+
+var (
+	errNoValidatorKey = errors.New("no validator key found in the data directory provided")
+	errKeystoreExists = errors.New("keystore file already exists")
+)
+
+type exportParams struct {
+	dataDir      string
+	keystorePath string
+
+	address types.Address
+}
+
+func (ep *exportParams) validateFlags() error {
+	if _, err := os.Stat(ep.keystorePath); err == nil {
+		return fmt.Errorf("%w: %s", errKeystoreExists, ep.keystorePath)
+	}
+
+	return nil
+}
+
+// exportKey writes the validator key of the local secrets to a keystore encrypted by a new passphrase
+func (ep *exportParams) exportKey() error {
+	key, err := ep.readValidatorKey()
+	if err != nil {
+		return err
+	}
+
+	rawKey, err := crypto.MarshalECDSAPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := cmdHelper.ReadPassphrase("keystore passphrase: ", true)
+	if err != nil {
+		return err
+	}
+
+	ep.address = crypto.PubKeyToAddress(&key.PublicKey)
+
+	encrypted, err := keystore.Encrypt(
+		rawKey,
+		hex.EncodeToString(ep.address.Bytes()),
+		passphrase,
+		keystore.StandardScryptN,
+		keystore.StandardScryptP,
+	)
+	if err != nil {
+		return err
+	}
+
+	return writeKeystore(ep.keystorePath, encrypted)
+}
+
+// writeKeystore writes the keystore to a new file only the user can read
+func writeKeystore(path string, keystore []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create keystore (%s), %w", path, err)
+	}
+
+	if _, err := file.Write(keystore); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("unable to write keystore (%s), %w", path, err)
+	}
+
+	return file.Close()
+}
+
+// readValidatorKey reads the validator key of the local secrets,
+// prompting for their passphrase if they're encrypted
+func (ep *exportParams) readValidatorKey() (*ecdsa.PrivateKey, error) {
+	local, err := helper.SetupLocalSecretsManager(ep.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !local.HasSecret(secrets.ValidatorKey) {
+		return nil, errNoValidatorKey
+	}
+
+	encodedKey, err := local.GetSecret(secrets.ValidatorKey)
+	if errors.Is(err, secrets.ErrPassphraseRequired) {
+		passphrase, passErr := cmdHelper.ReadSecretsPassphrase(false)
+		if passErr != nil {
+			return nil, passErr
+		}
+
+		if local, err = helper.SetupEncryptedLocalSecretsManager(ep.dataDir, passphrase); err != nil {
+			return nil, err
+		}
+
+		encodedKey, err = local.GetSecret(secrets.ValidatorKey)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.BytesToECDSAPrivateKey(encodedKey)
+}
+
+func (ep *exportParams) getResult() command.CommandResult {
+	return &SecretsExportResult{
+		Address:  ep.address.String(),
+		Keystore: ep.keystorePath,
+	}
+}
@@ -0,0 +1,26 @@
+package secretsexport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type SecretsExportResult struct {
+	Address  string `json:"address"`
+	Keystore string `json:"keystore"`
+}
+
+func (r *SecretsExportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[SECRETS EXPORT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Public key (address)|%s", r.Address),
+		fmt.Sprintf("Keystore|%s", r.Keystore),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
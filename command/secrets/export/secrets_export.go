@@ -0,0 +1,58 @@
+package secretsexport
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	secretsExportCmd := &cobra.Command{
+		Use: "export",
+		Short: "Exports the validator key from the local FS to an Ethereum keystore (v3) file " +
+			"encrypted by a new passphrase",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(secretsExportCmd)
+	helper.SetRequiredFlags(secretsExportCmd, []string{dataDirFlag, keystoreFlag})
+
+	return secretsExportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the directory for the Polygon Edge data on the local FS",
+	)
+
+	cmd.Flags().StringVar(
+		&params.keystorePath,
+		keystoreFlag,
+		"",
+		"the path of the keystore file to write",
+	)
+
+	_ = cmd.MarkFlagDirname(dataDirFlag)
+	_ = cmd.MarkFlagFilename(keystoreFlag)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.exportKey(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
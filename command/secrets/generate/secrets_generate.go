@@ -35,7 +35,7 @@ func setFlags(cmd *cobra.Command) {
 		&params.token,
 		tokenFlag,
 		"",
-		"the access token for the service",
+		"the access token for the service, not needed when logging in to Vault with the auth-method extra field",
 	)
 
 	cmd.Flags().StringVar(
@@ -50,10 +50,11 @@ func setFlags(cmd *cobra.Command) {
 		typeFlag,
 		string(secrets.HashicorpVault),
 		fmt.Sprintf(
-			"the type of the secrets manager. Available types: %s, %s and %s",
+			"the type of the secrets manager. Available types: %s, %s, %s and %s",
 			secrets.HashicorpVault,
 			secrets.AWSSSM,
 			secrets.GCPSSM,
+			secrets.PKCS11,
 		),
 	)
 
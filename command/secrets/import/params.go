@@ -0,0 +1,112 @@
+package secretsimport
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	cmdHelper "github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/keystore"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/secrets/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	dataDirFlag  = "data-dir"
+	keystoreFlag = "keystore"
+	insecureFlag = "insecure"
+)
+
+var (
+	params = &importParams{}
+)
+
+var (
+	errValidatorKeyExists = fmt.Errorf(`secrets "%s" has been already initialized`, secrets.ValidatorKey)
+	errAddressMismatch    = errors.New("keystore address doesn't match its key")
+)
+
+type importParams struct {
+	dataDir      string
+	keystorePath string
+	insecure     bool
+
+	address types.Address
+}
+
+// importKey decrypts the validator key from the keystore, storing it with the local secrets
+func (ip *importParams) importKey() error {
+	encrypted, err := os.ReadFile(ip.keystorePath)
+	if err != nil {
+		return fmt.Errorf("unable to read keystore (%s), %w", ip.keystorePath, err)
+	}
+
+	passphrase, err := cmdHelper.ReadPassphrase("keystore passphrase: ", false)
+	if err != nil {
+		return err
+	}
+
+	rawKey, err := keystore.Decrypt(encrypted, passphrase)
+	if err != nil {
+		return err
+	}
+
+	key, err := crypto.ParseECDSAPrivateKey(rawKey)
+	if err != nil {
+		return err
+	}
+
+	ip.address = crypto.PubKeyToAddress(&key.PublicKey)
+
+	if err := checkKeystoreAddress(encrypted, ip.address); err != nil {
+		return err
+	}
+
+	secretsPassphrase := ""
+	if !ip.insecure {
+		if secretsPassphrase, err = cmdHelper.ReadSecretsPassphrase(true); err != nil {
+			return err
+		}
+	}
+
+	local, err := helper.SetupEncryptedLocalSecretsManager(ip.dataDir, secretsPassphrase)
+	if err != nil {
+		return err
+	}
+
+	if local.HasSecret(secrets.ValidatorKey) {
+		return errValidatorKeyExists
+	}
+
+	return local.SetSecret(secrets.ValidatorKey, []byte(hex.EncodeToString(rawKey)))
+}
+
+// checkKeystoreAddress checks the address of the keystore matches its key, if it's set
+func checkKeystoreAddress(encrypted []byte, address types.Address) error {
+	var key keystore.EncryptedKey
+	if err := json.Unmarshal(encrypted, &key); err != nil {
+		return err
+	}
+
+	if key.Address == "" {
+		return nil
+	}
+
+	if !strings.EqualFold(strings.TrimPrefix(key.Address, "0x"), hex.EncodeToString(address.Bytes())) {
+		return fmt.Errorf("%w: 0x%s", errAddressMismatch, key.Address)
+	}
+
+	return nil
+}
+
+func (ip *importParams) getResult() command.CommandResult {
+	return &SecretsImportResult{
+		Address: ip.address.String(),
+	}
+}
@@ -0,0 +1,60 @@
+package secretsimport
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	secretsImportCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Imports the validator key from an Ethereum keystore (v3) file to the local FS",
+		Run:   runCommand,
+	}
+
+	setFlags(secretsImportCmd)
+	helper.SetRequiredFlags(secretsImportCmd, []string{dataDirFlag, keystoreFlag})
+
+	return secretsImportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the directory for the Polygon Edge data on the local FS",
+	)
+
+	cmd.Flags().StringVar(
+		&params.keystorePath,
+		keystoreFlag,
+		"",
+		"the path of the keystore file to import",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.insecure,
+		insecureFlag,
+		false,
+		"the flag indicating whether the key is stored in plain on the local FS, "+
+			"instead of encrypted by the secrets passphrase",
+	)
+
+	_ = cmd.MarkFlagDirname(dataDirFlag)
+	_ = cmd.MarkFlagFilename(keystoreFlag)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.importKey(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
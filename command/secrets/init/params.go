@@ -4,17 +4,19 @@ import (
 	"errors"
 
 	"github.com/0xPolygon/polygon-edge/command"
+	cmdHelper "github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/secrets/helper"
 )
 
 const (
-	dataDirFlag = "data-dir"
-	configFlag  = "config"
-	ecdsaFlag   = "ecdsa"
-	blsFlag     = "bls"
-	networkFlag = "network"
-	numFlag     = "num"
+	dataDirFlag  = "data-dir"
+	configFlag   = "config"
+	ecdsaFlag    = "ecdsa"
+	blsFlag      = "bls"
+	networkFlag  = "network"
+	numFlag      = "num"
+	insecureFlag = "insecure"
 )
 
 var (
@@ -29,6 +31,10 @@ type initParams struct {
 	generatesECDSA   bool
 	generatesBLS     bool
 	generatesNetwork bool
+	insecure         bool
+
+	// passphrase encrypting the local secrets
+	passphrase string
 
 	secretsManager secrets.SecretsManager
 	secretsConfig  *secrets.SecretsManagerConfig
@@ -42,6 +48,22 @@ func (ip *initParams) validateFlags() error {
 	return nil
 }
 
+// readPassphrase reads the passphrase encrypting the local secrets, unless they're stored in plain
+func (ip *initParams) readPassphrase() error {
+	if ip.hasConfigPath() || ip.insecure {
+		return nil
+	}
+
+	passphrase, err := cmdHelper.ReadSecretsPassphrase(true)
+	if err != nil {
+		return err
+	}
+
+	ip.passphrase = passphrase
+
+	return nil
+}
+
 func (ip *initParams) initSecrets() error {
 	if err := ip.initSecretsManager(); err != nil {
 		return err
@@ -89,7 +111,7 @@ func (ip *initParams) parseConfig() error {
 }
 
 func (ip *initParams) initLocalSecretsManager() error {
-	local, err := helper.SetupLocalSecretsManager(ip.dataDir)
+	local, err := helper.SetupEncryptedLocalSecretsManager(ip.dataDir, ip.passphrase)
 	if err != nil {
 		return err
 	}
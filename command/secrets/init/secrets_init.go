@@ -84,6 +84,19 @@ func setFlags(cmd *cobra.Command) {
 		true,
 		"the flag indicating whether new BLS key is created",
 	)
+
+	cmd.Flags().BoolVar(
+		&basicParams.insecure,
+		insecureFlag,
+		false,
+		"the flag indicating whether the secrets are stored in plain on the local FS, "+
+			"instead of encrypted by the passphrase",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive(insecureFlag, configFlag)
+
+	_ = cmd.MarkFlagDirname(dataDirFlag)
+	_ = cmd.MarkFlagFilename(configFlag)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
@@ -91,7 +104,11 @@ func runPreRun(_ *cobra.Command, _ []string) error {
 		return errInvalidNum
 	}
 
-	return basicParams.validateFlags()
+	if err := basicParams.validateFlags(); err != nil {
+		return err
+	}
+
+	return basicParams.readPassphrase()
 }
 
 func runCommand(cmd *cobra.Command, _ []string) {
@@ -135,6 +152,8 @@ func newParamsList(params initParams, num int) []initParams {
 			generatesECDSA:   params.generatesECDSA,
 			generatesBLS:     params.generatesBLS,
 			generatesNetwork: params.generatesNetwork,
+			insecure:         params.insecure,
+			passphrase:       params.passphrase,
 		}
 	}
 
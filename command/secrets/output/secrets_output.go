@@ -60,6 +60,9 @@ func setFlags(cmd *cobra.Command) {
 
 	cmd.MarkFlagsMutuallyExclusive(dataDirFlag, configFlag)
 	cmd.MarkFlagsMutuallyExclusive(nodeIDFlag, validatorFlag, blsFlag)
+
+	_ = cmd.MarkFlagDirname(dataDirFlag)
+	_ = cmd.MarkFlagFilename(configFlag)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
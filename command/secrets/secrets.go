@@ -2,7 +2,9 @@ package secrets
 
 import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	secretsexport "github.com/0xPolygon/polygon-edge/command/secrets/export"
 	"github.com/0xPolygon/polygon-edge/command/secrets/generate"
+	secretsimport "github.com/0xPolygon/polygon-edge/command/secrets/import"
 	initCmd "github.com/0xPolygon/polygon-edge/command/secrets/init"
 	"github.com/0xPolygon/polygon-edge/command/secrets/output"
 	"github.com/spf13/cobra"
@@ -29,5 +31,9 @@ func registerSubcommands(baseCmd *cobra.Command) {
 		generate.GetCommand(),
 		// secrets output public data
 		output.GetCommand(),
+		// secrets export validator key
+		secretsexport.GetCommand(),
+		// secrets import validator key
+		secretsimport.GetCommand(),
 	)
 }
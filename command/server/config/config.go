@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/kvdb"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/hashicorp/hcl"
 	"gopkg.in/yaml.v3"
@@ -19,6 +21,7 @@ type Config struct {
 	BlockGasTarget           string     `json:"block_gas_target" yaml:"block_gas_target"`
 	GRPCAddr                 string     `json:"grpc_addr" yaml:"grpc_addr"`
 	JSONRPCAddr              string     `json:"jsonrpc_addr" yaml:"jsonrpc_addr"`
+	IPCPath                  string     `json:"ipc_path" yaml:"ipc_path"`
 	Telemetry                *Telemetry `json:"telemetry" yaml:"telemetry"`
 	Network                  *Network   `json:"network" yaml:"network"`
 	ShouldSeal               bool       `json:"seal" yaml:"seal"`
@@ -29,31 +32,79 @@ type Config struct {
 	Headers                  *Headers   `json:"headers" yaml:"headers"`
 	LogFilePath              string     `json:"log_to" yaml:"log_to"`
 	JSONRPCBatchRequestLimit uint64     `json:"json_rpc_batch_request_limit" yaml:"json_rpc_batch_request_limit"`
+	JSONRPCBatchGasLimit     uint64     `json:"json_rpc_batch_gas_limit" yaml:"json_rpc_batch_gas_limit"`
 	JSONRPCBlockRangeLimit   uint64     `json:"json_rpc_block_range_limit" yaml:"json_rpc_block_range_limit"`
+	JSONRPCStateHistory      uint64     `json:"json_rpc_state_history" yaml:"json_rpc_state_history"`
+	JSONRPCStatePinInterval  uint64     `json:"json_rpc_state_pin_interval" yaml:"json_rpc_state_pin_interval"`
+	JSONRPCFilterTimeout     uint64     `json:"json_rpc_filter_timeout" yaml:"json_rpc_filter_timeout"`
+	JSONRPCMaxClientFilters  uint64     `json:"json_rpc_max_client_filters" yaml:"json_rpc_max_client_filters"`
+	JSONRPCPersistFilters    bool       `json:"json_rpc_persist_filters" yaml:"json_rpc_persist_filters"`
+	JSONRPCTxSenderRateLimit uint64     `json:"json_rpc_tx_sender_rate_limit" yaml:"json_rpc_tx_sender_rate_limit"`
+	JSONRPCTxIPRateLimit     uint64     `json:"json_rpc_tx_ip_rate_limit" yaml:"json_rpc_tx_ip_rate_limit"`
+	JSONRPCTxRateBurst       uint64     `json:"json_rpc_tx_rate_burst" yaml:"json_rpc_tx_rate_burst"`
+	JSONRPCVirtualHosts      []string   `json:"json_rpc_vhosts" yaml:"json_rpc_vhosts"`
+	JSONRPCRateLimit         uint64     `json:"json_rpc_rate_limit" yaml:"json_rpc_rate_limit"`
+	JSONRPCWebhooks          bool       `json:"json_rpc_webhooks" yaml:"json_rpc_webhooks"`
+	GraphQL                  bool       `json:"graphql" yaml:"graphql"`
+	UnsafeDebug              bool       `json:"unsafe_debug" yaml:"unsafe_debug"`
+	JSONRPCAdmin             bool       `json:"json_rpc_admin" yaml:"json_rpc_admin"`
 	JSONLogFormat            bool       `json:"json_log_format" yaml:"json_log_format"`
+	StateSnapshotInterval    uint64     `json:"state_snapshot_interval" yaml:"state_snapshot_interval"`
+	SnapSync                 bool       `json:"snap_sync" yaml:"snap_sync"`
+	Checkpoint               string     `json:"checkpoint" yaml:"checkpoint"`
+	CheckpointBackfill       bool       `json:"checkpoint_backfill" yaml:"checkpoint_backfill"`
+	OpcodeStats              bool       `json:"opcode_stats" yaml:"opcode_stats"`
+	OpcodeStatsTopContracts  uint64     `json:"opcode_stats_top_contracts" yaml:"opcode_stats_top_contracts"`
+	StateRetention           uint64     `json:"state_retention" yaml:"state_retention"`
+	FlatState                bool       `json:"flat_state" yaml:"flat_state"`
+	DBEngine                 string     `json:"db_engine" yaml:"db_engine"`
+	AncientDir               string     `json:"ancient_dir" yaml:"ancient_dir"`
+	AncientThreshold         uint64     `json:"ancient_threshold" yaml:"ancient_threshold"`
+	TxLookupLimit            uint64     `json:"tx_lookup_limit" yaml:"tx_lookup_limit"`
+	StrictSignState          bool       `json:"strict_sign_state" yaml:"strict_sign_state"`
+	ReplicaOf                string     `json:"replica_of" yaml:"replica_of"`
+	ReplicaMaxLag            uint64     `json:"replica_max_lag" yaml:"replica_max_lag"`
+	ReplicaStaleTimeout      uint64     `json:"replica_stale_timeout_s" yaml:"replica_stale_timeout_s"`
+	Light                    bool       `json:"light" yaml:"light"`
+	Cache                    uint64     `json:"cache" yaml:"cache"`
+	CacheTrie                uint64     `json:"cache_trie" yaml:"cache_trie"`
+	CacheCode                uint64     `json:"cache_code" yaml:"cache_code"`
+	CacheSnapshot            uint64     `json:"cache_snapshot" yaml:"cache_snapshot"`
+	CacheTxPool              uint64     `json:"cache_txpool" yaml:"cache_txpool"`
+	CacheBlocks              uint64     `json:"cache_blocks" yaml:"cache_blocks"`
+
+	JSONRPCMethodRateLimits  map[string]uint64 `json:"json_rpc_method_rate_limits" yaml:"json_rpc_method_rate_limits"`
+	JSONRPCConcurrencyLimits map[string]uint64 `json:"json_rpc_concurrency_limits" yaml:"json_rpc_concurrency_limits"`
 }
 
 // Telemetry holds the config details for metric services.
 type Telemetry struct {
 	PrometheusAddr string `json:"prometheus_addr" yaml:"prometheus_addr"`
+	PprofAddr      string `json:"pprof_addr,omitempty" yaml:"pprof_addr,omitempty"`
 }
 
 // Network defines the network configuration params
 type Network struct {
-	NoDiscover       bool   `json:"no_discover" yaml:"no_discover"`
-	Libp2pAddr       string `json:"libp2p_addr" yaml:"libp2p_addr"`
-	NatAddr          string `json:"nat_addr" yaml:"nat_addr"`
-	DNSAddr          string `json:"dns_addr" yaml:"dns_addr"`
-	MaxPeers         int64  `json:"max_peers,omitempty" yaml:"max_peers,omitempty"`
-	MaxOutboundPeers int64  `json:"max_outbound_peers,omitempty" yaml:"max_outbound_peers,omitempty"`
-	MaxInboundPeers  int64  `json:"max_inbound_peers,omitempty" yaml:"max_inbound_peers,omitempty"`
+	NoDiscover       bool     `json:"no_discover" yaml:"no_discover"`
+	Libp2pAddr       string   `json:"libp2p_addr" yaml:"libp2p_addr"`
+	NatAddr          string   `json:"nat_addr" yaml:"nat_addr"`
+	DNSAddr          string   `json:"dns_addr" yaml:"dns_addr"`
+	MaxPeers         int64    `json:"max_peers,omitempty" yaml:"max_peers,omitempty"`
+	MaxOutboundPeers int64    `json:"max_outbound_peers,omitempty" yaml:"max_outbound_peers,omitempty"`
+	MaxInboundPeers  int64    `json:"max_inbound_peers,omitempty" yaml:"max_inbound_peers,omitempty"`
+	BanDuration      uint64   `json:"ban_duration_s" yaml:"ban_duration_s"`
+	StaticPeers      []string `json:"static_peers" yaml:"static_peers"`
+	NoCompression    bool     `json:"no_compression" yaml:"no_compression"`
 }
 
 // TxPool defines the TxPool configuration params
 type TxPool struct {
-	PriceLimit         uint64 `json:"price_limit" yaml:"price_limit"`
-	MaxSlots           uint64 `json:"max_slots" yaml:"max_slots"`
-	MaxAccountEnqueued uint64 `json:"max_account_enqueued" yaml:"max_account_enqueued"`
+	PriceLimit         uint64   `json:"price_limit" yaml:"price_limit"`
+	MaxSlots           uint64   `json:"max_slots" yaml:"max_slots"`
+	MaxAccountEnqueued uint64   `json:"max_account_enqueued" yaml:"max_account_enqueued"`
+	BundlerWhitelist   []string `json:"bundler_whitelist" yaml:"bundler_whitelist"`
+	PrioritySenders    []string `json:"priority_senders" yaml:"priority_senders"`
+	PriorityGasPercent uint64   `json:"priority_gas_percent" yaml:"priority_gas_percent"`
 }
 
 // Headers defines the HTTP response headers required to enable CORS.
@@ -62,6 +113,9 @@ type Headers struct {
 }
 
 const (
+	// DefaultPriorityGasPercent is the share of the block gas limit reserved to the priority senders
+	DefaultPriorityGasPercent uint64 = 25
+
 	// DefaultBlockTime minimum block generation time in seconds
 	DefaultBlockTime uint64 = 2
 
@@ -72,9 +126,48 @@ const (
 	// DefaultJSONRPCBatchRequestLimit maximum length allowed for json_rpc batch requests
 	DefaultJSONRPCBatchRequestLimit uint64 = 20
 
+	// DefaultJSONRPCBatchGasLimit maximum aggregate gas of the call-type requests (eth_call, eth_estimateGas)
+	// allowed in a json_rpc batch request, 0 means no limit
+	DefaultJSONRPCBatchGasLimit uint64 = 0
+
 	// DefaultJSONRPCBlockRangeLimit maximum block range allowed for json_rpc
 	// requests with fromBlock/toBlock values (e.g. eth_getLogs)
 	DefaultJSONRPCBlockRangeLimit uint64 = 1000
+
+	// DefaultJSONRPCFilterTimeout time in seconds after which a filter which isn't polled is removed
+	DefaultJSONRPCFilterTimeout uint64 = 60
+
+	// DefaultOpcodeStatsTopContracts number of contracts which consumed the most gas
+	// reported in the opcode stats of a block
+	DefaultOpcodeStatsTopContracts uint64 = 10
+
+	// DefaultReplicaMaxLag number of blocks a replica can be behind its primary
+	DefaultReplicaMaxLag uint64 = 5
+
+	// DefaultReplicaStaleTimeout time in seconds a replica can go without reaching its primary
+	DefaultReplicaStaleTimeout uint64 = 30
+
+	// DefaultAncientThreshold number of recent blocks kept in the blockchain database
+	// when the older ones are moved to the freezer
+	DefaultAncientThreshold uint64 = 90000
+
+	// DefaultCache memory in megabytes of the state caches and the transaction pool
+	DefaultCache uint64 = 1024
+
+	// DefaultCacheTrie percentage of the cache memory used for the trie nodes
+	DefaultCacheTrie uint64 = 50
+
+	// DefaultCacheCode percentage of the cache memory used for the contract code
+	DefaultCacheCode uint64 = 15
+
+	// DefaultCacheSnapshot percentage of the cache memory used for the flat state
+	DefaultCacheSnapshot uint64 = 15
+
+	// DefaultCacheTxPool percentage of the cache memory used for the transaction pool slots
+	DefaultCacheTxPool uint64 = 20
+
+	// DefaultCacheBlocks number of recently accessed blocks whose data is cached
+	DefaultCacheBlocks uint64 = 256
 )
 
 // DefaultConfig returns the default server configuration
@@ -90,6 +183,7 @@ func DefaultConfig() *Config {
 			MaxPeers:         defaultNetworkConfig.MaxPeers,
 			MaxOutboundPeers: defaultNetworkConfig.MaxOutboundPeers,
 			MaxInboundPeers:  defaultNetworkConfig.MaxInboundPeers,
+			BanDuration:      uint64(defaultNetworkConfig.BanDuration / time.Second),
 			Libp2pAddr: fmt.Sprintf("%s:%d",
 				defaultNetworkConfig.Addr.IP,
 				defaultNetworkConfig.Addr.Port,
@@ -101,6 +195,7 @@ func DefaultConfig() *Config {
 			PriceLimit:         0,
 			MaxSlots:           4096,
 			MaxAccountEnqueued: 128,
+			PriorityGasPercent: DefaultPriorityGasPercent,
 		},
 		LogLevel:    "INFO",
 		RestoreFile: "",
@@ -110,7 +205,21 @@ func DefaultConfig() *Config {
 		},
 		LogFilePath:              "",
 		JSONRPCBatchRequestLimit: DefaultJSONRPCBatchRequestLimit,
+		JSONRPCBatchGasLimit:     DefaultJSONRPCBatchGasLimit,
 		JSONRPCBlockRangeLimit:   DefaultJSONRPCBlockRangeLimit,
+		JSONRPCFilterTimeout:     DefaultJSONRPCFilterTimeout,
+		JSONRPCVirtualHosts:      []string{"*"},
+		OpcodeStatsTopContracts:  DefaultOpcodeStatsTopContracts,
+		ReplicaMaxLag:            DefaultReplicaMaxLag,
+		ReplicaStaleTimeout:      DefaultReplicaStaleTimeout,
+		DBEngine:                 kvdb.LevelDB,
+		AncientThreshold:         DefaultAncientThreshold,
+		Cache:                    DefaultCache,
+		CacheTrie:                DefaultCacheTrie,
+		CacheCode:                DefaultCacheCode,
+		CacheSnapshot:            DefaultCacheSnapshot,
+		CacheTxPool:              DefaultCacheTxPool,
+		CacheBlocks:              DefaultCacheBlocks,
 	}
 }
 
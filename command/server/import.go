@@ -0,0 +1,75 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/spf13/cobra"
+)
+
+// GetImportCommand returns the command importing the blocks of a backup or of a chain export,
+// which takes the flags of the server and closes it once the blocks are written
+func GetImportCommand() *cobra.Command {
+	importCmd := &cobra.Command{
+		Use: "import",
+		Short: "Imports the blocks of a backup, an RLP export, an era1 file or a directory of era1 files " +
+			"into the data directory, executing them like the server does. An interrupted import is resumed " +
+			"after the blocks already written",
+		PreRunE: runPreRun,
+		Run:     runImportCommand,
+	}
+
+	helper.RegisterGRPCAddressFlag(importCmd)
+	helper.RegisterLegacyGRPCAddressFlag(importCmd)
+	helper.RegisterJSONRPCFlag(importCmd)
+
+	setFlags(importCmd)
+	helper.SetRequiredFlags(importCmd, []string{restoreFlag})
+
+	return importCmd
+}
+
+func runImportCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	config := params.generateConfig()
+	config.RestoreOnly = true
+
+	serverInstance, err := server.NewServer(config)
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	head := serverInstance.HeadNumber()
+
+	serverInstance.Close()
+
+	outputter.SetCommandResult(&ImportResult{
+		File: *config.RestoreFile,
+		Head: head,
+	})
+}
+
+type ImportResult struct {
+	File string `json:"file"`
+	Head uint64 `json:"head"`
+}
+
+func (r *ImportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN IMPORT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", r.File),
+		fmt.Sprintf("Head|%d", r.Head),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -4,7 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"net"
+	"math/big"
+	"strings"
 
 	"github.com/0xPolygon/polygon-edge/command/server/config"
 
@@ -12,15 +13,25 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/consensus/dev"
+	"github.com/0xPolygon/polygon-edge/kvdb"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 )
 
 var (
 	errInvalidBlockTime       = errors.New("invalid block time specified")
 	errDataDirectoryUndefined = errors.New("data directory not defined")
+	errInvalidCacheSplit      = errors.New("the cache percentages add up to more than 100")
+	errInvalidBlockCache      = errors.New("the number of cached blocks must be positive")
+	errUnknownDBEngine        = errors.New("unknown database engine")
+	errForkWithoutDevMode     = errors.New("the fork url requires the dev mode with the dev consensus")
+	errInvalidPriorityGas     = errors.New("the priority gas percent must be at most 100")
 )
 
 func (p *serverParams) initConfigFromFile() error {
@@ -30,9 +41,47 @@ func (p *serverParams) initConfigFromFile() error {
 		return parseErr
 	}
 
+	if p.rawConfig.Headers != nil {
+		p.corsAllowedOrigins = p.rawConfig.Headers.AccessControlAllowOrigins
+	}
+
 	return nil
 }
 
+// reloadConfig reads the config file again and returns the settings it holds which can be changed
+// at runtime: the log level, the JSON-RPC settings, the peer limits and the price limit.
+// The settings of the flags are kept
+func (p *serverParams) reloadConfig() (*server.Config, error) {
+	reloaded := &serverParams{
+		configPath:         p.configPath,
+		jsonRPCAddress:     p.jsonRPCAddress,
+		corsAllowedOrigins: p.corsAllowedOrigins,
+		methodRateLimits:   p.methodRateLimits,
+		concurrencyLimits:  p.concurrencyLimits,
+	}
+
+	if err := reloaded.initConfigFromFile(); err != nil {
+		return nil, err
+	}
+
+	if err := reloaded.initJSONRPCLimits(); err != nil {
+		return nil, err
+	}
+
+	reloaded.initPeerLimits()
+
+	return &server.Config{
+		JSONRPC: reloaded.generateJSONRPCConfig(),
+		Network: &network.Config{
+			MaxPeers:         reloaded.rawConfig.Network.MaxPeers,
+			MaxInboundPeers:  reloaded.rawConfig.Network.MaxInboundPeers,
+			MaxOutboundPeers: reloaded.rawConfig.Network.MaxOutboundPeers,
+		},
+		PriceLimit: reloaded.rawConfig.TxPool.PriceLimit,
+		LogLevel:   hclog.LevelFromString(reloaded.rawConfig.LogLevel),
+	}, nil
+}
+
 func (p *serverParams) initRawParams() error {
 	if err := p.initBlockGasTarget(); err != nil {
 		return err
@@ -54,8 +103,42 @@ func (p *serverParams) initRawParams() error {
 		return err
 	}
 
+	if err := p.initCache(); err != nil {
+		return err
+	}
+
+	if err := p.initDBEngine(); err != nil {
+		return err
+	}
+
 	if p.isDevMode {
-		p.initDevMode()
+		if err := p.initDevMode(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.initFork(); err != nil {
+		return err
+	}
+
+	if err := p.initForkOverrides(); err != nil {
+		return err
+	}
+
+	if err := p.initJSONRPCLimits(); err != nil {
+		return err
+	}
+
+	if err := p.initBundlerWhitelist(); err != nil {
+		return err
+	}
+
+	if err := p.initPrioritySenders(); err != nil {
+		return err
+	}
+
+	if err := p.initCheckpoint(); err != nil {
+		return err
 	}
 
 	p.initPeerLimits()
@@ -64,6 +147,45 @@ func (p *serverParams) initRawParams() error {
 	return p.initAddresses()
 }
 
+// initJSONRPCLimits merges the per-method limits set by the flags into the ones of the config file
+func (p *serverParams) initJSONRPCLimits() error {
+	var err error
+
+	if p.rawConfig.JSONRPCMethodRateLimits, err = mergeLimits(
+		p.rawConfig.JSONRPCMethodRateLimits,
+		p.methodRateLimits,
+	); err != nil {
+		return err
+	}
+
+	if p.rawConfig.JSONRPCConcurrencyLimits, err = mergeLimits(
+		p.rawConfig.JSONRPCConcurrencyLimits,
+		p.concurrencyLimits,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func mergeLimits(limits map[string]uint64, overrides map[string]int64) (map[string]uint64, error) {
+	merged := make(map[string]uint64, len(limits)+len(overrides))
+
+	for method, limit := range limits {
+		merged[method] = limit
+	}
+
+	for method, limit := range overrides {
+		if limit < 0 {
+			return nil, fmt.Errorf("%w: %s=%d", errNegativeRPCLimit, method, limit)
+		}
+
+		merged[method] = uint64(limit)
+	}
+
+	return merged, nil
+}
+
 func (p *serverParams) initBlockTime() error {
 	if p.rawConfig.BlockTime < 1 {
 		return errInvalidBlockTime
@@ -72,6 +194,71 @@ func (p *serverParams) initBlockTime() error {
 	return nil
 }
 
+func (p *serverParams) initCache() error {
+	if p.rawConfig.CacheTrie+p.rawConfig.CacheCode+p.rawConfig.CacheSnapshot+p.rawConfig.CacheTxPool > 100 {
+		return errInvalidCacheSplit
+	}
+
+	if p.rawConfig.CacheBlocks == 0 {
+		return errInvalidBlockCache
+	}
+
+	return nil
+}
+
+func (p *serverParams) initDBEngine() error {
+	for _, engine := range kvdb.Engines() {
+		if p.rawConfig.DBEngine == engine {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", errUnknownDBEngine, p.rawConfig.DBEngine)
+}
+
+func (p *serverParams) initBundlerWhitelist() error {
+	p.bundlerWhitelist = make([]types.Address, len(p.rawConfig.TxPool.BundlerWhitelist))
+
+	for i, raw := range p.rawConfig.TxPool.BundlerWhitelist {
+		if err := p.bundlerWhitelist[i].UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid bundler address %s: %w", raw, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *serverParams) initPrioritySenders() error {
+	if p.rawConfig.TxPool.PriorityGasPercent > txpool.MaxPriorityGasPercent {
+		return errInvalidPriorityGas
+	}
+
+	p.prioritySenders = make([]types.Address, len(p.rawConfig.TxPool.PrioritySenders))
+
+	for i, raw := range p.rawConfig.TxPool.PrioritySenders {
+		if err := p.prioritySenders[i].UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid priority sender address %s: %w", raw, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *serverParams) initCheckpoint() error {
+	if p.rawConfig.Checkpoint == "" {
+		return nil
+	}
+
+	checkpoint, err := statesync.ParseCheckpoint(p.rawConfig.Checkpoint)
+	if err != nil {
+		return err
+	}
+
+	p.checkpoint = checkpoint
+
+	return nil
+}
+
 func (p *serverParams) initDataDirLocation() error {
 	if p.rawConfig.DataDir == "" {
 		return errDataDirectoryUndefined
@@ -131,14 +318,108 @@ func (p *serverParams) initGenesisConfig() error {
 	return nil
 }
 
-func (p *serverParams) initDevMode() {
+// initForkOverrides overrides the activation of the forks of the genesis file with the ones of the flags
+func (p *serverParams) initForkOverrides() error {
+	forks := chain.Forks{}
+	if p.genesisConfig.Params.Forks != nil {
+		forks = *p.genesisConfig.Params.Forks
+	}
+
+	overridden := false
+
+	for name, raw := range p.forkOverrides {
+		if *raw == "" {
+			continue
+		}
+
+		fork, err := chain.ParseFork(*raw)
+		if err != nil {
+			return fmt.Errorf("invalid --%s%s: %w", forkOverrideFlagPrefix, strings.ToLower(name), err)
+		}
+
+		if err := forks.Set(name, fork); err != nil {
+			return err
+		}
+
+		overridden = true
+	}
+
+	if !overridden {
+		return nil
+	}
+
+	if err := forks.Validate(); err != nil {
+		return fmt.Errorf("invalid fork overrides: %w", err)
+	}
+
+	// the forks are copied, since the dev mode sets the shared ones enabling all the forks
+	p.genesisConfig.Params.Forks = &forks
+
+	return nil
+}
+
+func (p *serverParams) initDevMode() error {
 	// Dev mode:
 	// - disables peer discovery
 	// - enables all forks
+	// - funds the dev accounts
 	p.rawConfig.Network.NoDiscover = true
-	p.genesisConfig.Params.Forks = chain.AllForksEnabled
+
+	// the native token economics and the allowlist are configured along with their forks, which are kept
+	if forks := p.genesisConfig.Params.Forks; forks != nil && (forks.NativeToken != nil || forks.Allowlist != nil) {
+		devForks := *chain.AllForksEnabled
+		devForks.NativeToken = forks.NativeToken
+		devForks.Allowlist = forks.Allowlist
+
+		p.genesisConfig.Params.Forks = &devForks
+	} else {
+		p.genesisConfig.Params.Forks = chain.AllForksEnabled
+	}
 
 	p.initDevConsensusConfig()
+
+	return p.initDevAccounts()
+}
+
+// initDevAccounts funds the dev accounts in the genesis, unless the genesis allocates them already
+func (p *serverParams) initDevAccounts() error {
+	balance, err := types.ParseUint256orHex(&p.devBalanceRaw)
+	if err != nil {
+		return fmt.Errorf("failed to parse the dev balance %s: %w", p.devBalanceRaw, err)
+	}
+
+	if p.devAccounts, err = dev.GenerateAccounts(p.devAccountCount); err != nil {
+		return err
+	}
+
+	if p.genesisConfig.Genesis.Alloc == nil {
+		p.genesisConfig.Genesis.Alloc = make(map[types.Address]*chain.GenesisAccount, len(p.devAccounts))
+	}
+
+	for _, account := range p.devAccounts {
+		if _, ok := p.genesisConfig.Genesis.Alloc[account.Address]; ok {
+			continue
+		}
+
+		p.genesisConfig.Genesis.Alloc[account.Address] = &chain.GenesisAccount{
+			Balance: new(big.Int).Set(balance),
+		}
+	}
+
+	return nil
+}
+
+// initFork checks that the forked chain is a dev chain, since the local blocks aren't valid on the remote one
+func (p *serverParams) initFork() error {
+	if p.forkURL == "" {
+		return nil
+	}
+
+	if !p.isDevMode || !p.isDevConsensus() {
+		return errForkWithoutDevMode
+	}
+
+	return nil
 }
 
 func (p *serverParams) initDevConsensusConfig() {
@@ -212,6 +493,10 @@ func (p *serverParams) initAddresses() error {
 		return err
 	}
 
+	if err := p.initPprofAddress(); err != nil {
+		return err
+	}
+
 	if err := p.initLibp2pAddress(); err != nil {
 		return err
 	}
@@ -248,6 +533,25 @@ func (p *serverParams) initPrometheusAddress() error {
 	return nil
 }
 
+// initPprofAddress resolves the address of the diagnostics server, which binds to the localhost
+// if only the port is set, as the profiles aren't authenticated
+func (p *serverParams) initPprofAddress() error {
+	if !p.isPprofAddressSet() {
+		return nil
+	}
+
+	var parseErr error
+
+	if p.pprofAddress, parseErr = helper.ResolveAddr(
+		p.rawConfig.Telemetry.PprofAddr,
+		helper.LocalHostBinding,
+	); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
 func (p *serverParams) initLibp2pAddress() error {
 	var parseErr error
 
@@ -266,10 +570,12 @@ func (p *serverParams) initNATAddress() error {
 		return nil
 	}
 
-	if p.natAddress = net.ParseIP(
+	var parseErr error
+
+	if p.natMapping, p.natAddress, parseErr = network.ParseNAT(
 		p.rawConfig.Network.NatAddr,
-	); p.natAddress == nil {
-		return errInvalidNATAddress
+	); parseErr != nil {
+		return parseErr
 	}
 
 	return nil
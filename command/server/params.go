@@ -3,12 +3,18 @@ package server
 import (
 	"errors"
 	"net"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command/server/config"
+	"github.com/0xPolygon/polygon-edge/consensus/dev"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/replica"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/0xPolygon/polygon-edge/state/fork"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/multiformats/go-multiaddr"
 )
@@ -19,6 +25,7 @@ const (
 	dataDirFlag                  = "data-dir"
 	libp2pAddressFlag            = "libp2p"
 	prometheusAddressFlag        = "prometheus"
+	pprofAddressFlag             = "pprof"
 	natFlag                      = "nat"
 	dnsFlag                      = "dns"
 	sealFlag                     = "seal"
@@ -27,17 +34,71 @@ const (
 	maxOutboundPeersFlag         = "max-outbound-peers"
 	priceLimitFlag               = "price-limit"
 	jsonRPCBatchRequestLimitFlag = "json-rpc-batch-request-limit"
+	jsonRPCBatchGasLimitFlag     = "json-rpc-batch-gas-limit"
 	jsonRPCBlockRangeLimitFlag   = "json-rpc-block-range-limit"
+	jsonRPCStateHistoryFlag      = "json-rpc-state-history"
+	jsonRPCStatePinIntervalFlag  = "json-rpc-state-pin-interval"
+	jsonRPCFilterTimeoutFlag     = "json-rpc-filter-timeout"
+	jsonRPCMaxClientFiltersFlag  = "json-rpc-max-client-filters"
+	jsonRPCPersistFiltersFlag    = "json-rpc-persist-filters"
+	jsonRPCTxSenderRateLimitFlag = "json-rpc-tx-sender-rate-limit"
+	jsonRPCTxIPRateLimitFlag     = "json-rpc-tx-ip-rate-limit"
+	jsonRPCTxRateBurstFlag       = "json-rpc-tx-rate-burst"
 	maxSlotsFlag                 = "max-slots"
 	maxEnqueuedFlag              = "max-enqueued"
+	bundlerWhitelistFlag         = "bundler-whitelist"
+	prioritySendersFlag          = "priority-senders"
+	priorityGasPercentFlag       = "priority-gas-percent"
 	blockGasTargetFlag           = "block-gas-target"
 	secretsConfigFlag            = "secrets-config"
 	restoreFlag                  = "restore"
 	blockTimeFlag                = "block-time"
 	devIntervalFlag              = "dev-interval"
 	devFlag                      = "dev"
+	devAccountsFlag              = "dev-accounts"
+	devBalanceFlag               = "dev-balance"
+	forkURLFlag                  = "fork-url"
+	forkBlockFlag                = "fork-block"
 	corsOriginFlag               = "access-control-allow-origins"
 	logFileLocationFlag          = "log-to"
+	stateSnapshotIntervalFlag    = "state-snapshot-interval"
+	snapSyncFlag                 = "snap-sync"
+	checkpointFlag               = "checkpoint"
+	checkpointBackfillFlag       = "checkpoint-backfill"
+	jsonRPCVirtualHostsFlag      = "json-rpc-vhosts"
+	jsonRPCRateLimitFlag         = "json-rpc-rate-limit"
+	jsonRPCMethodRateLimitsFlag  = "json-rpc-method-rate-limits"
+	jsonRPCConcurrencyLimitsFlag = "json-rpc-concurrency-limits"
+	jsonRPCWebhooksFlag          = "json-rpc-webhooks"
+	ipcPathFlag                  = "ipc-path"
+	graphQLFlag                  = "graphql"
+	unsafeDebugFlag              = "unsafe-debug"
+	jsonRPCAdminFlag             = "json-rpc-admin"
+	opcodeStatsFlag              = "opcode-stats"
+	opcodeStatsTopContractsFlag  = "opcode-stats-top-contracts"
+	stateRetentionFlag           = "state-retention"
+	flatStateFlag                = "flat-state"
+	dbEngineFlag                 = "db.engine"
+	ancientDirFlag               = "datadir.ancient"
+	ancientThresholdFlag         = "ancient.threshold"
+	txLookupLimitFlag            = "tx-lookup-limit"
+	banDurationFlag              = "ban-duration"
+	staticPeersFlag              = "static-peers"
+	noCompressionFlag            = "no-compression"
+	strictSignStateFlag          = "strict-sign-state"
+	replicaOfFlag                = "replica-of"
+	replicaMaxLagFlag            = "replica-max-lag"
+	replicaStaleTimeoutFlag      = "replica-stale-timeout"
+	lightFlag                    = "light"
+	cacheFlag                    = "cache"
+	cacheTrieFlag                = "cache-trie"
+	cacheCodeFlag                = "cache-code"
+	cacheSnapshotFlag            = "cache-snapshot"
+	cacheTxPoolFlag              = "cache-txpool"
+	cacheBlocksFlag              = "cache-blocks"
+
+	// forkOverrideFlagPrefix prefixes the flags overriding the activation of the forks, e.g. override.istanbul
+	forkOverrideFlagPrefix = "override."
 )
 
 // Flags that are deprecated, but need to be preserved for
@@ -61,7 +122,7 @@ var (
 )
 
 var (
-	errInvalidNATAddress = errors.New("could not parse NAT IP address")
+	errNegativeRPCLimit = errors.New("json-rpc limits must not be negative")
 )
 
 type serverParams struct {
@@ -70,7 +131,9 @@ type serverParams struct {
 
 	libp2pAddress     *net.TCPAddr
 	prometheusAddress *net.TCPAddr
+	pprofAddress      *net.TCPAddr
 	natAddress        net.IP
+	natMapping        network.NATMapping
 	dnsAddress        multiaddr.Multiaddr
 	grpcAddress       *net.TCPAddr
 	jsonRPCAddress    *net.TCPAddr
@@ -79,14 +142,36 @@ type serverParams struct {
 	devInterval    uint64
 	isDevMode      bool
 
+	// devAccountCount dev accounts are funded with devBalanceRaw in dev mode
+	devAccountCount uint64
+	devBalanceRaw   string
+	devAccounts     []*dev.Account
+
+	// forkURL is the JSON-RPC endpoint of the remote chain the dev chain forks at forkBlock, if it's set
+	forkURL   string
+	forkBlock uint64
+
 	corsAllowedOrigins []string
 
+	// per-method limits set by the flags, they override the ones of the config file
+	methodRateLimits  map[string]int64
+	concurrencyLimits map[string]int64
+
 	ibftBaseTimeoutLegacy uint64
 
+	bundlerWhitelist []types.Address
+	prioritySenders  []types.Address
+
+	// forkOverrides are the activations of the forks set by the override flags, by fork name
+	forkOverrides map[string]*string
+
 	genesisConfig *chain.Chain
 	secretsConfig *secrets.SecretsManagerConfig
 
 	logFileLocation string
+
+	// checkpoint is the trusted block a fresh node starts from, nil if it's not set
+	checkpoint *statesync.Checkpoint
 }
 
 func (p *serverParams) isMaxPeersSet() bool {
@@ -106,6 +191,10 @@ func (p *serverParams) isPrometheusAddressSet() bool {
 	return p.rawConfig.Telemetry.PrometheusAddr != ""
 }
 
+func (p *serverParams) isPprofAddressSet() bool {
+	return p.rawConfig.Telemetry.PprofAddr != ""
+}
+
 func (p *serverParams) isNATAddressSet() bool {
 	return p.rawConfig.Network.NatAddr != ""
 }
@@ -122,6 +211,18 @@ func (p *serverParams) isDevConsensus() bool {
 	return server.ConsensusType(p.genesisConfig.Params.GetEngine()) == server.DevConsensus
 }
 
+// forkConfig returns the remote chain the state of the dev chain is forked from, nil if it isn't forked
+func (p *serverParams) forkConfig() *fork.Config {
+	if p.forkURL == "" {
+		return nil
+	}
+
+	return &fork.Config{
+		URL:   p.forkURL,
+		Block: p.forkBlock,
+	}
+}
+
 func (p *serverParams) getRestoreFilePath() *string {
 	if p.rawConfig.RestoreFile != "" {
 		return &p.rawConfig.RestoreFile
@@ -130,6 +231,18 @@ func (p *serverParams) getRestoreFilePath() *string {
 	return nil
 }
 
+func (p *serverParams) generateReplicaConfig() *replica.Config {
+	if p.rawConfig.ReplicaOf == "" {
+		return nil
+	}
+
+	return &replica.Config{
+		PrimaryAddr:  p.rawConfig.ReplicaOf,
+		MaxLag:       p.rawConfig.ReplicaMaxLag,
+		StaleTimeout: time.Duration(p.rawConfig.ReplicaStaleTimeout) * time.Second,
+	}
+}
+
 func (p *serverParams) setRawGRPCAddress(grpcAddress string) {
 	p.rawConfig.GRPCAddr = grpcAddress
 }
@@ -142,41 +255,107 @@ func (p *serverParams) setJSONLogFormat(jsonLogFormat bool) {
 	p.rawConfig.JSONLogFormat = jsonLogFormat
 }
 
+func (p *serverParams) generateJSONRPCConfig() *server.JSONRPC {
+	return &server.JSONRPC{
+		JSONRPCAddr:              p.jsonRPCAddress,
+		IPCPath:                  p.rawConfig.IPCPath,
+		AccessControlAllowOrigin: p.corsAllowedOrigins,
+		VirtualHosts:             p.rawConfig.JSONRPCVirtualHosts,
+		RateLimit:                p.rawConfig.JSONRPCRateLimit,
+		MethodRateLimits:         p.rawConfig.JSONRPCMethodRateLimits,
+		MaxConcurrentRequests:    p.rawConfig.JSONRPCConcurrencyLimits,
+		BatchLengthLimit:         p.rawConfig.JSONRPCBatchRequestLimit,
+		BatchGasLimit:            p.rawConfig.JSONRPCBatchGasLimit,
+		BlockRangeLimit:          p.rawConfig.JSONRPCBlockRangeLimit,
+		StateHistory:             p.rawConfig.JSONRPCStateHistory,
+		StatePinInterval:         p.rawConfig.JSONRPCStatePinInterval,
+		FilterTimeout:            time.Duration(p.rawConfig.JSONRPCFilterTimeout) * time.Second,
+		MaxFiltersPerClient:      p.rawConfig.JSONRPCMaxClientFilters,
+		PersistFilters:           p.rawConfig.JSONRPCPersistFilters,
+		TxSenderRateLimit:        p.rawConfig.JSONRPCTxSenderRateLimit,
+		TxIPRateLimit:            p.rawConfig.JSONRPCTxIPRateLimit,
+		TxRateBurst:              p.rawConfig.JSONRPCTxRateBurst,
+		Webhooks:                 p.rawConfig.JSONRPCWebhooks,
+		GraphQL:                  p.rawConfig.GraphQL,
+		UnsafeDebug:              p.rawConfig.UnsafeDebug,
+		Admin:                    p.rawConfig.JSONRPCAdmin,
+	}
+}
+
 func (p *serverParams) generateConfig() *server.Config {
 	return &server.Config{
-		Chain: p.genesisConfig,
-		JSONRPC: &server.JSONRPC{
-			JSONRPCAddr:              p.jsonRPCAddress,
-			AccessControlAllowOrigin: p.corsAllowedOrigins,
-			BatchLengthLimit:         p.rawConfig.JSONRPCBatchRequestLimit,
-			BlockRangeLimit:          p.rawConfig.JSONRPCBlockRangeLimit,
-		},
+		Chain:      p.genesisConfig,
+		JSONRPC:    p.generateJSONRPCConfig(),
 		GRPCAddr:   p.grpcAddress,
 		LibP2PAddr: p.libp2pAddress,
 		Telemetry: &server.Telemetry{
 			PrometheusAddr: p.prometheusAddress,
+			PprofAddr:      p.pprofAddress,
 		},
 		Network: &network.Config{
 			NoDiscover:       p.rawConfig.Network.NoDiscover,
 			Addr:             p.libp2pAddress,
 			NatAddr:          p.natAddress,
+			NATMapping:       p.natMapping,
 			DNS:              p.dnsAddress,
 			DataDir:          p.rawConfig.DataDir,
 			MaxPeers:         p.rawConfig.Network.MaxPeers,
 			MaxInboundPeers:  p.rawConfig.Network.MaxInboundPeers,
 			MaxOutboundPeers: p.rawConfig.Network.MaxOutboundPeers,
 			Chain:            p.genesisConfig,
+			BanDuration:      time.Duration(p.rawConfig.Network.BanDuration) * time.Second,
+			StaticPeers:      p.rawConfig.Network.StaticPeers,
+			NoCompression:    p.rawConfig.Network.NoCompression,
 		},
 		DataDir:            p.rawConfig.DataDir,
 		Seal:               p.rawConfig.ShouldSeal,
 		PriceLimit:         p.rawConfig.TxPool.PriceLimit,
 		MaxSlots:           p.rawConfig.TxPool.MaxSlots,
 		MaxAccountEnqueued: p.rawConfig.TxPool.MaxAccountEnqueued,
+		BundlerWhitelist:   p.bundlerWhitelist,
+		PrioritySenders:    p.prioritySenders,
+		PriorityGasPercent: p.rawConfig.TxPool.PriorityGasPercent,
 		SecretsManager:     p.secretsConfig,
 		RestoreFile:        p.getRestoreFilePath(),
 		BlockTime:          p.rawConfig.BlockTime,
 		LogLevel:           hclog.LevelFromString(p.rawConfig.LogLevel),
 		JSONLogFormat:      p.rawConfig.JSONLogFormat,
 		LogFilePath:        p.logFileLocation,
+
+		StateSnapshotInterval: p.rawConfig.StateSnapshotInterval,
+		SnapSync:              p.rawConfig.SnapSync,
+
+		Checkpoint:         p.checkpoint,
+		CheckpointBackfill: p.rawConfig.CheckpointBackfill,
+
+		OpcodeStats:             p.rawConfig.OpcodeStats,
+		OpcodeStatsTopContracts: p.rawConfig.OpcodeStatsTopContracts,
+
+		StateRetention: p.rawConfig.StateRetention,
+		FlatState:      p.rawConfig.FlatState,
+		Fork:           p.forkConfig(),
+		DBEngine:       p.rawConfig.DBEngine,
+
+		AncientDir:       p.rawConfig.AncientDir,
+		AncientThreshold: p.rawConfig.AncientThreshold,
+
+		TxLookupLimit: p.rawConfig.TxLookupLimit,
+
+		TrieCache:     p.cacheSize(p.rawConfig.CacheTrie),
+		CodeCache:     p.cacheSize(p.rawConfig.CacheCode),
+		SnapshotCache: p.cacheSize(p.rawConfig.CacheSnapshot),
+		TxPoolCache:   p.cacheSize(p.rawConfig.CacheTxPool),
+		BlockCache:    int(p.rawConfig.CacheBlocks),
+
+		StrictSignState: p.rawConfig.StrictSignState,
+
+		Replica: p.generateReplicaConfig(),
+
+		Light: p.rawConfig.Light,
 	}
 }
+
+// cacheSize returns the share of the cache memory in bytes, from its percentage
+func (p *serverParams) cacheSize(percentage uint64) int {
+	return int(p.rawConfig.Cache * percentage / 100 * 1024 * 1024)
+}
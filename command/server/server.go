@@ -1,13 +1,22 @@
 package server
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/command/server/config"
 	"github.com/0xPolygon/polygon-edge/command/server/export"
+	"github.com/0xPolygon/polygon-edge/consensus/dev"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/kvdb"
 	"github.com/0xPolygon/polygon-edge/server"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/spf13/cobra"
 )
 
@@ -82,11 +91,20 @@ func setFlags(cmd *cobra.Command) {
 			"If only port is defined (:port) it will bind to 0.0.0.0:port",
 	)
 
+	cmd.Flags().StringVar(
+		&params.rawConfig.Telemetry.PprofAddr,
+		pprofAddressFlag,
+		"",
+		"the address and port for the diagnostics service serving the pprof profiles and execution traces "+
+			"(address:port), disabled if not set. If only port is defined (:port) it will bind to 127.0.0.1:port",
+	)
+
 	cmd.Flags().StringVar(
 		&params.rawConfig.Network.NatAddr,
 		natFlag,
 		"",
-		"the external IP address without port, as can be seen by peers",
+		"the NAT traversal: upnp or pmp maps the libp2p port on the gateway and advertises its external address, "+
+			"extip:<ip> (or the bare IP) is the external IP address without port, as can be seen by peers",
 	)
 
 	cmd.Flags().StringVar(
@@ -161,6 +179,28 @@ func setFlags(cmd *cobra.Command) {
 	cmd.Flag(maxOutboundPeersFlag).DefValue = fmt.Sprintf("%d", defaultConfig.Network.MaxOutboundPeers)
 	cmd.MarkFlagsMutuallyExclusive(maxPeersFlag, maxOutboundPeersFlag)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Network.BanDuration,
+		banDurationFlag,
+		defaultConfig.Network.BanDuration,
+		"the time in seconds a peer is banned for once its reputation falls too low "+
+			"for sending invalid blocks or transactions, timing out or gossiping useless messages",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.Network.StaticPeers,
+		staticPeersFlag,
+		defaultConfig.Network.StaticPeers,
+		"the multiaddrs of the peers the client always stays connected to, even once it reaches its max peers",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.Network.NoCompression,
+		noCompressionFlag,
+		defaultConfig.Network.NoCompression,
+		"prevent the client from snappy compressing the gossip and the sync messages for the peers supporting it",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.TxPool.PriceLimit,
 		priceLimitFlag,
@@ -185,6 +225,27 @@ func setFlags(cmd *cobra.Command) {
 		"maximum number of enqueued transactions per account",
 	)
 
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.TxPool.BundlerWhitelist,
+		bundlerWhitelistFlag,
+		defaultConfig.TxPool.BundlerWhitelist,
+		"the addresses allowed to send conditional transactions, anyone can if empty",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.TxPool.PrioritySenders,
+		prioritySendersFlag,
+		defaultConfig.TxPool.PrioritySenders,
+		"the senders whose transactions are included before the regular ones, in the gas reserved to them",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.PriorityGasPercent,
+		priorityGasPercentFlag,
+		defaultConfig.TxPool.PriorityGasPercent,
+		"the share of the block gas limit reserved to the priority senders, in percent",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.BlockTime,
 		blockTimeFlag,
@@ -192,6 +253,199 @@ func setFlags(cmd *cobra.Command) {
 		"minimum block time in seconds (at least 1s)",
 	)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.StateSnapshotInterval,
+		stateSnapshotIntervalFlag,
+		defaultConfig.StateSnapshotInterval,
+		"the number of blocks between the pivot blocks whose state is served to the snap syncing peers "+
+			"(0 disables serving it). It should not exceed the state retention",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.SnapSync,
+		snapSyncFlag,
+		defaultConfig.SnapSync,
+		"start a fresh node by fetching the state at the latest pivot block served by the peers, "+
+			"in ranges verified against its state root, instead of executing all the blocks. "+
+			"Not supported with the PoS validators",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.Checkpoint,
+		checkpointFlag,
+		defaultConfig.Checkpoint,
+		"the trusted block a fresh node starts from instead of the genesis, as <number>:<hash>. "+
+			"It must be the last block of an epoch whose state is still kept by the peers, "+
+			"the validators of the following blocks are read from its header. "+
+			"Only the 256 blocks before it are fetched, along with its state. Not supported with the PoS validators",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.CheckpointBackfill,
+		checkpointBackfillFlag,
+		defaultConfig.CheckpointBackfill,
+		"fetch all the blocks before the checkpoint in the background. "+
+			"The indexes and logs of the blocks before the checkpoint are available once they're backfilled",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.OpcodeStats,
+		opcodeStatsFlag,
+		defaultConfig.OpcodeStats,
+		"record the opcodes executed in the latest processed blocks and the contracts which consumed the most gas, "+
+			"served by debug_opcodeStats and published as metrics",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.OpcodeStatsTopContracts,
+		opcodeStatsTopContractsFlag,
+		defaultConfig.OpcodeStatsTopContracts,
+		"the number of contracts which consumed the most gas reported in the opcode stats of a block",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.StateRetention,
+		stateRetentionFlag,
+		defaultConfig.StateRetention,
+		fmt.Sprintf(
+			"the number of recent blocks whose state is retained, the state trie nodes which aren't "+
+				"reachable from them are pruned in the background. Value of 0 retains the states of all the blocks, "+
+				"the minimum is %d",
+			itrie.MinStateRetention,
+		),
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.FlatState,
+		flatStateFlag,
+		false,
+		"maintain the flat state of the accounts and storage slots along with the state tries, "+
+			"to read them without traversing the tries",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.DBEngine,
+		dbEngineFlag,
+		defaultConfig.DBEngine,
+		fmt.Sprintf(
+			"the engine of the blockchain and state databases: %s. The databases of the data directory "+
+				"are moved to another engine with the db migrate command",
+			strings.Join(kvdb.Engines(), ", "),
+		),
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.AncientDir,
+		ancientDirFlag,
+		defaultConfig.AncientDir,
+		"the directory of the append-only freezer the headers, bodies and receipts of the old blocks "+
+			"are moved to out of the blockchain database, which must be set once it holds blocks",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.AncientThreshold,
+		ancientThresholdFlag,
+		defaultConfig.AncientThreshold,
+		"the number of recent blocks kept in the blockchain database when the older ones are moved to the freezer",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxLookupLimit,
+		txLookupLimitFlag,
+		defaultConfig.TxLookupLimit,
+		"the number of recent blocks whose transactions are looked up by hash, 0 to look up the transactions "+
+			"of all the blocks. The lookups of the older blocks are removed in the background, "+
+			"and written back once the limit is raised",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Cache,
+		cacheFlag,
+		defaultConfig.Cache,
+		"the memory in megabytes of the caches of the state read from the disk and the transaction pool, "+
+			"split between the trie nodes, the contract code, the flat state and the pool slots. "+
+			"The shares are rebalanced at runtime towards the consumers under pressure",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.CacheTrie,
+		cacheTrieFlag,
+		defaultConfig.CacheTrie,
+		"the percentage of the cache memory used for the trie nodes",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.CacheCode,
+		cacheCodeFlag,
+		defaultConfig.CacheCode,
+		"the percentage of the cache memory used for the contract code",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.CacheSnapshot,
+		cacheSnapshotFlag,
+		defaultConfig.CacheSnapshot,
+		fmt.Sprintf("the percentage of the cache memory used for the flat state, if the %s flag is set", flatStateFlag),
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.CacheTxPool,
+		cacheTxPoolFlag,
+		defaultConfig.CacheTxPool,
+		fmt.Sprintf(
+			"the percentage of the cache memory used for the transaction pool slots, up to the %s flag. "+
+				"0 keeps the pool at %s outside of the memory budget",
+			maxSlotsFlag,
+			maxSlotsFlag,
+		),
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.CacheBlocks,
+		cacheBlocksFlag,
+		defaultConfig.CacheBlocks,
+		"the number of recently accessed blocks whose headers, bodies, receipts and total difficulty "+
+			"are cached, for the consensus and the JSON-RPC alike",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.StrictSignState,
+		strictSignStateFlag,
+		false,
+		"refuse to start the validator if its last sign state file is missing or behind the chain, "+
+			"like after restoring it from a backup, to prevent signing twice for the same block",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.ReplicaOf,
+		replicaOfFlag,
+		"",
+		"the gRPC address of the primary node to replicate. The replica writes the blocks of the primary "+
+			"to its own database to serve the JSON-RPC, instead of running the consensus",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.ReplicaMaxLag,
+		replicaMaxLagFlag,
+		defaultConfig.ReplicaMaxLag,
+		"the number of blocks the replica can be behind its primary before it reports itself syncing",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.ReplicaStaleTimeout,
+		replicaStaleTimeoutFlag,
+		defaultConfig.ReplicaStaleTimeout,
+		"the time in seconds the replica can go without reaching its primary before it reports itself syncing",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.Light,
+		lightFlag,
+		false,
+		"run a light node, following the verified headers and validator set changes from the full nodes "+
+			"and proving the state queries against them, without storing nor executing the blocks",
+	)
+
 	cmd.Flags().StringArrayVar(
 		&params.corsAllowedOrigins,
 		corsOriginFlag,
@@ -206,6 +460,14 @@ func setFlags(cmd *cobra.Command) {
 		"max length to be considered when handling json-rpc batch requests, value of 0 disables it",
 	)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCBatchGasLimit,
+		jsonRPCBatchGasLimitFlag,
+		defaultConfig.JSONRPCBatchGasLimit,
+		"max aggregate gas of the call-type requests (eth_call, eth_estimateGas) "+
+			"to be considered when handling json-rpc batch requests, value of 0 disables it",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.JSONRPCBlockRangeLimit,
 		jsonRPCBlockRangeLimitFlag,
@@ -214,6 +476,132 @@ func setFlags(cmd *cobra.Command) {
 			"that consider fromBlock/toBlock values (e.g. eth_getLogs), value of 0 disables it",
 	)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCStateHistory,
+		jsonRPCStateHistoryFlag,
+		defaultConfig.JSONRPCStateHistory,
+		"number of recent blocks whose state is served to the json-rpc state queries (e.g. eth_call, eth_getBalance), "+
+			"older blocks get a state not available error, value of 0 serves the state of all the blocks",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCStatePinInterval,
+		jsonRPCStatePinIntervalFlag,
+		defaultConfig.JSONRPCStatePinInterval,
+		"the state of every block whose number is a multiple of the interval is served to the json-rpc state queries "+
+			"regardless of the state history, and retained by the state pruning, value of 0 disables it",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCFilterTimeout,
+		jsonRPCFilterTimeoutFlag,
+		defaultConfig.JSONRPCFilterTimeout,
+		"the time in seconds after which a json-rpc filter which isn't polled is removed",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCMaxClientFilters,
+		jsonRPCMaxClientFiltersFlag,
+		defaultConfig.JSONRPCMaxClientFilters,
+		"the maximum number of json-rpc filters installed by a client (identified by its IP), value of 0 disables it",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.JSONRPCPersistFilters,
+		jsonRPCPersistFiltersFlag,
+		defaultConfig.JSONRPCPersistFilters,
+		"keep the json-rpc filters across the restarts of the node, "+
+			"they get the logs and blocks processed since they were last polled",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCTxSenderRateLimit,
+		jsonRPCTxSenderRateLimitFlag,
+		defaultConfig.JSONRPCTxSenderRateLimit,
+		"the number of transactions per second accepted by eth_sendRawTransaction from a single sender, "+
+			"value of 0 disables it",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCTxIPRateLimit,
+		jsonRPCTxIPRateLimitFlag,
+		defaultConfig.JSONRPCTxIPRateLimit,
+		"the number of transactions per second accepted by eth_sendRawTransaction from a single IP, "+
+			"value of 0 disables it",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCTxRateBurst,
+		jsonRPCTxRateBurstFlag,
+		defaultConfig.JSONRPCTxRateBurst,
+		"the number of transactions accepted at once above the sender and IP rates, "+
+			"defaults to the rate if 0",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCVirtualHosts,
+		jsonRPCVirtualHostsFlag,
+		defaultConfig.JSONRPCVirtualHosts,
+		"the host names from which the JSON-RPC server accepts requests (\"*\" accepts any)",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCRateLimit,
+		jsonRPCRateLimitFlag,
+		defaultConfig.JSONRPCRateLimit,
+		"max number of json-rpc requests per second from a single client, value of 0 disables it",
+	)
+
+	cmd.Flags().StringToInt64Var(
+		&params.methodRateLimits,
+		jsonRPCMethodRateLimitsFlag,
+		nil,
+		"max number of calls per second to the json-rpc methods from a single client (e.g. eth_getLogs=5)",
+	)
+
+	cmd.Flags().StringToInt64Var(
+		&params.concurrencyLimits,
+		jsonRPCConcurrencyLimitsFlag,
+		nil,
+		"max number of concurrent calls to the json-rpc methods (e.g. debug_traceTransaction=2)",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.IPCPath,
+		ipcPathFlag,
+		defaultConfig.IPCPath,
+		"the path of the unix socket serving the JSON-RPC to the local tools, it is disabled if empty",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.JSONRPCWebhooks,
+		jsonRPCWebhooksFlag,
+		defaultConfig.JSONRPCWebhooks,
+		"serve the webhook json-rpc namespace, registering the urls notified of the matching logs",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.GraphQL,
+		graphQLFlag,
+		defaultConfig.GraphQL,
+		"serve the GraphQL API (EIP-1767) at the /graphql path of the JSON-RPC server",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.UnsafeDebug,
+		unsafeDebugFlag,
+		defaultConfig.UnsafeDebug,
+		"serve the debug_setHead, debug_dumpBlock and debug_dbStats JSON-RPC methods, "+
+			"which rewind the chain and expose the whole state. Only for test networks and incident recovery",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.JSONRPCAdmin,
+		jsonRPCAdminFlag,
+		defaultConfig.JSONRPCAdmin,
+		"serve the admin json-rpc namespace, adding and removing the static peers at runtime",
+	)
+
 	cmd.Flags().StringVar(
 		&params.rawConfig.LogFilePath,
 		logFileLocationFlag,
@@ -221,9 +609,35 @@ func setFlags(cmd *cobra.Command) {
 		"write all logs to the file at specified location instead of writing them to console",
 	)
 
+	setForkOverrideFlags(cmd)
+
 	setLegacyFlags(cmd)
 
 	setDevFlags(cmd)
+
+	_ = cmd.MarkFlagFilename(configFlag)
+	_ = cmd.MarkFlagDirname(dataDirFlag)
+}
+
+// setForkOverrideFlags sets a flag overriding the activation of each fork of the genesis file
+func setForkOverrideFlags(cmd *cobra.Command) {
+	params.forkOverrides = make(map[string]*string)
+
+	for _, name := range chain.ForkNames() {
+		override := new(string)
+		params.forkOverrides[name] = override
+
+		cmd.Flags().StringVar(
+			override,
+			forkOverrideFlagPrefix+strings.ToLower(name),
+			"",
+			fmt.Sprintf(
+				"override the activation of the %s fork of the genesis file, at a block number or at "+
+					"timestamp:<unix time>. Only for testing, the node forks off the chain of the other nodes",
+				name,
+			),
+		)
+	}
 }
 
 // setLegacyFlags sets the legacy flags to preserve backwards compatibility
@@ -245,19 +659,49 @@ func setDevFlags(cmd *cobra.Command) {
 		&params.isDevMode,
 		devFlag,
 		false,
-		"should the client start in dev mode (default false)",
+		"should the client start in dev mode, as a local development chain with all the forks enabled, "+
+			"funded dev accounts and the evm_mine, evm_increaseTime, evm_snapshot and evm_revert methods "+
+			"if the genesis uses the dev consensus (default false)",
 	)
 
-	_ = cmd.Flags().MarkHidden(devFlag)
-
 	cmd.Flags().Uint64Var(
 		&params.devInterval,
 		devIntervalFlag,
 		0,
-		"the client's dev notification interval in seconds (default 1)",
+		"the interval in seconds the dev consensus seals a block of the pending transactions on, "+
+			"0 seals a block per transaction as soon as it arrives",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.devAccountCount,
+		devAccountsFlag,
+		10,
+		"the number of dev accounts funded in dev mode, their keys are the same on every run",
+	)
+
+	cmd.Flags().StringVar(
+		&params.devBalanceRaw,
+		devBalanceFlag,
+		command.DefaultPremineBalance,
+		"the balance of each dev account in dev mode",
+	)
+
+	cmd.Flags().StringVar(
+		&params.forkURL,
+		forkURLFlag,
+		"",
+		"the JSON-RPC endpoint of a remote chain the dev chain forks, reading the accounts and the storage "+
+			"missing locally from the remote chain at the fork block. The chain continues from the fork block, "+
+			"the remote blocks aren't served",
 	)
 
-	_ = cmd.Flags().MarkHidden(devIntervalFlag)
+	cmd.Flags().Uint64Var(
+		&params.forkBlock,
+		forkBlockFlag,
+		0,
+		"the number of the remote block the dev chain forks, 0 for the latest block. "+
+			"The block is pinned in the data directory on the first run",
+	)
 }
 
 func runPreRun(cmd *cobra.Command, _ []string) error {
@@ -289,6 +733,12 @@ func isConfigFileSpecified(cmd *cobra.Command) bool {
 func runCommand(cmd *cobra.Command, _ []string) {
 	outputter := command.InitializeOutputter(cmd)
 
+	// the keys of the dev accounts are only printed for the console, they would break the JSON output
+	if params.isDevMode && len(params.devAccounts) > 0 && !cmd.Flag(command.JSONOutputFlag).Changed {
+		outputter.SetCommandResult(newDevAccountsResult(params.devAccounts))
+		outputter.WriteOutput()
+	}
+
 	if err := runServerLoop(params.generateConfig(), outputter); err != nil {
 		outputter.SetError(err)
 		outputter.WriteOutput()
@@ -297,14 +747,83 @@ func runCommand(cmd *cobra.Command, _ []string) {
 	}
 }
 
+// DevAccountsResult lists the keys of the funded dev accounts, so they can be imported into the wallets and tools
+type DevAccountsResult struct {
+	Accounts []DevAccount `json:"accounts"`
+}
+
+type DevAccount struct {
+	Address    string `json:"address"`
+	PrivateKey string `json:"privateKey"`
+}
+
+func newDevAccountsResult(accounts []*dev.Account) *DevAccountsResult {
+	result := &DevAccountsResult{Accounts: make([]DevAccount, 0, len(accounts))}
+
+	for _, account := range accounts {
+		key, err := crypto.MarshalECDSAPrivateKey(account.PrivateKey)
+		if err != nil {
+			continue
+		}
+
+		result.Accounts = append(result.Accounts, DevAccount{
+			Address:    account.Address.String(),
+			PrivateKey: hex.EncodeToHex(key),
+		})
+	}
+
+	return result
+}
+
+func (r *DevAccountsResult) GetOutput() string {
+	if len(r.Accounts) == 0 {
+		return ""
+	}
+
+	rows := make([]string, 0, len(r.Accounts)+1)
+	rows = append(rows, "Address|Private key")
+
+	for _, account := range r.Accounts {
+		rows = append(rows, fmt.Sprintf("%s|%s", account.Address, account.PrivateKey))
+	}
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DEV ACCOUNTS]\n")
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n\nThe keys of the dev accounts are public, never send real funds to them\n")
+
+	return buffer.String()
+}
+
 func runServerLoop(
 	config *server.Config,
 	outputter command.OutputFormatter,
 ) error {
+	if config.Light {
+		lightNode, err := server.NewLightNode(config)
+		if err != nil {
+			return err
+		}
+
+		return helper.HandleSignals(lightNode.Close, outputter)
+	}
+
 	serverInstance, err := server.NewServer(config)
 	if err != nil {
 		return err
 	}
 
-	return helper.HandleSignals(serverInstance.Close, outputter)
+	if params.configPath == "" {
+		return helper.HandleSignals(serverInstance.Close, outputter)
+	}
+
+	// the config file is reloaded on SIGHUP and by admin_reloadConfig
+	serverInstance.SetConfigReloader(params.reloadConfig)
+
+	return helper.HandleSignalsWithReload(serverInstance.Close, func() {
+		if err := serverInstance.ReloadConfigFile(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "[SIGNAL] %v\n", err)
+		}
+	}, outputter)
 }
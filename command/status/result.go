@@ -12,6 +12,30 @@ type StatusResult struct {
 	CurrentBlockNumber int64  `json:"current_block_number"`
 	CurrentBlockHash   string `json:"current_block_hash"`
 	LibP2PAddress      string `json:"libp2p_address"`
+
+	Healthy      bool     `json:"healthy"`
+	Ready        bool     `json:"ready"`
+	Problems     []string `json:"problems"`
+	Syncing      bool     `json:"syncing"`
+	HighestBlock uint64   `json:"highest_block"`
+	BlockAge     uint64   `json:"block_age"`
+	Peers        int64    `json:"peers"`
+
+	Validators *ValidatorsResult `json:"validators,omitempty"`
+	Disk       *DiskResult       `json:"disk,omitempty"`
+}
+
+// ValidatorsResult is how many of the validators of the head block sealed it
+type ValidatorsResult struct {
+	Validators int64 `json:"validators"`
+	Sealed     int64 `json:"sealed"`
+	Validator  bool  `json:"validator"`
+}
+
+// DiskResult is the usage of the disk of the data directory, in bytes
+type DiskResult struct {
+	Total uint64 `json:"total"`
+	Free  uint64 `json:"free"`
 }
 
 func (r *StatusResult) GetOutput() string {
@@ -25,5 +49,38 @@ func (r *StatusResult) GetOutput() string {
 		fmt.Sprintf("Libp2p Address|%s", r.LibP2PAddress),
 	}))
 
+	health := []string{
+		fmt.Sprintf("Healthy|%t", r.Healthy),
+		fmt.Sprintf("Ready|%t", r.Ready),
+		fmt.Sprintf("Syncing|%t", r.Syncing),
+	}
+
+	if r.Syncing {
+		health = append(health, fmt.Sprintf("Highest Block Number (base 10)|%d", r.HighestBlock))
+	}
+
+	health = append(health,
+		fmt.Sprintf("Latest Block Age|%ds", r.BlockAge),
+		fmt.Sprintf("Peers|%d", r.Peers),
+	)
+
+	if r.Validators != nil {
+		health = append(health,
+			fmt.Sprintf("Validators Sealed|%d of %d", r.Validators.Sealed, r.Validators.Validators),
+			fmt.Sprintf("Validator|%t", r.Validators.Validator),
+		)
+	}
+
+	if r.Disk != nil {
+		health = append(health, fmt.Sprintf("Disk Free|%d of %d bytes", r.Disk.Free, r.Disk.Total))
+	}
+
+	for _, problem := range r.Problems {
+		health = append(health, fmt.Sprintf("Problem|%s", problem))
+	}
+
+	buffer.WriteString("\n\n[HEALTH]\n")
+	buffer.WriteString(helper.FormatKV(health))
+
 	return buffer.String()
 }
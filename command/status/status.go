@@ -35,12 +35,39 @@ func runCommand(cmd *cobra.Command, _ []string) {
 		return
 	}
 
-	outputter.SetCommandResult(&StatusResult{
+	result := &StatusResult{
 		ChainID:            statusResponse.Network,
 		CurrentBlockNumber: statusResponse.Current.Number,
 		CurrentBlockHash:   statusResponse.Current.Hash,
 		LibP2PAddress:      statusResponse.P2PAddr,
-	})
+	}
+
+	if health := statusResponse.Health; health != nil {
+		result.Healthy = health.Healthy
+		result.Ready = health.Ready
+		result.Problems = health.Problems
+		result.Syncing = health.Syncing
+		result.HighestBlock = health.HighestBlock
+		result.BlockAge = health.BlockAge
+		result.Peers = health.Peers
+
+		if health.Validators > 0 {
+			result.Validators = &ValidatorsResult{
+				Validators: health.Validators,
+				Sealed:     health.Sealed,
+				Validator:  health.Validator,
+			}
+		}
+
+		if health.DiskTotal > 0 {
+			result.Disk = &DiskResult{
+				Total: health.DiskTotal,
+				Free:  health.DiskFree,
+			}
+		}
+	}
+
+	outputter.SetCommandResult(result)
 }
 
 func getSystemStatus(grpcAddress string) (*proto.ServerStatus, error) {
@@ -40,6 +40,8 @@ func setFlags(cmd *cobra.Command) {
 		[]string{},
 		"removes a new address from the contract deployment whitelist",
 	)
+
+	_ = cmd.MarkFlagFilename(chainFlag)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
@@ -26,7 +26,8 @@ type showParams struct {
 }
 
 type Whitelists struct {
-	deployment []types.Address
+	Deployment []types.Address `json:"deployment"`
+	System     []types.Address `json:"system"`
 }
 
 func (p *showParams) initRawParams() error {
@@ -55,9 +56,15 @@ func (p *showParams) initWhitelists() error {
 		return err
 	}
 
+	systemWhitelist, err := config.GetSystemWhitelist(genesisConfig)
+	if err != nil {
+		return err
+	}
+
 	// set whitelists
 	p.whitelists = Whitelists{
-		deployment: deploymentWhitelist,
+		Deployment: deploymentWhitelist,
+		System:     systemWhitelist,
 	}
 
 	return nil
@@ -6,7 +6,7 @@ import (
 )
 
 type ShowResult struct {
-	Whitelists Whitelists
+	Whitelists Whitelists `json:"whitelists"`
 }
 
 func (r *ShowResult) GetOutput() string {
@@ -14,7 +14,8 @@ func (r *ShowResult) GetOutput() string {
 
 	buffer.WriteString("\n[WHITELISTS]\n\n")
 
-	buffer.WriteString(fmt.Sprintf("Contract deployment whitelist : %s,\n", r.Whitelists.deployment))
+	buffer.WriteString(fmt.Sprintf("Contract deployment whitelist : %s,\n", r.Whitelists.Deployment))
+	buffer.WriteString(fmt.Sprintf("System senders whitelist : %s,\n", r.Whitelists.System))
 
 	return buffer.String()
 }
@@ -27,6 +27,8 @@ func setFlags(cmd *cobra.Command) {
 		fmt.Sprintf("./%s", command.DefaultGenesisFileName),
 		"the genesis file to update",
 	)
+
+	_ = cmd.MarkFlagFilename(chainFlag)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
@@ -0,0 +1,162 @@
+package system
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/helper/config"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	chainFlag         = "chain"
+	addAddressFlag    = "add"
+	removeAddressFlag = "remove"
+)
+
+var (
+	params = &systemParams{}
+)
+
+type systemParams struct {
+	// raw addresses, entered by CLI commands
+	addAddressRaw    []string
+	removeAddressRaw []string
+
+	// addresses, converted from raw addresses
+	addAddresses    []types.Address
+	removeAddresses []types.Address
+
+	// genesis file
+	genesisPath   string
+	genesisConfig *chain.Chain
+
+	// system senders from genesis configuration
+	whitelist []types.Address
+}
+
+func (p *systemParams) initRawParams() error {
+	// convert raw addresses to appropriate format
+	if err := p.initRawAddresses(); err != nil {
+		return err
+	}
+
+	// init genesis configuration
+	if err := p.initChain(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *systemParams) initRawAddresses() error {
+	// convert addresses to be added from string to type.Address
+	p.addAddresses = unmarshallRawAddresses(p.addAddressRaw)
+
+	// convert addresses to be removed from string to type.Address
+	p.removeAddresses = unmarshallRawAddresses(p.removeAddressRaw)
+
+	return nil
+}
+
+func (p *systemParams) initChain() error {
+	// import genesis configuration
+	cc, err := chain.Import(p.genesisPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load chain config from %s: %w",
+			p.genesisPath,
+			err,
+		)
+	}
+
+	// set genesis configuration
+	p.genesisConfig = cc
+
+	return nil
+}
+
+func (p *systemParams) updateGenesisConfig() error {
+	// Fetch the system senders from genesis config
+	systemWhitelist, err := config.GetSystemWhitelist(p.genesisConfig)
+	if err != nil {
+		return err
+	}
+
+	doesExist := map[types.Address]bool{}
+
+	for _, a := range systemWhitelist {
+		doesExist[a] = true
+	}
+
+	for _, a := range p.addAddresses {
+		doesExist[a] = true
+	}
+
+	for _, a := range p.removeAddresses {
+		doesExist[a] = false
+	}
+
+	newSystemWhitelist := make([]types.Address, 0)
+
+	for addr, exists := range doesExist {
+		if exists {
+			newSystemWhitelist = append(newSystemWhitelist, addr)
+		}
+	}
+
+	// Set whitelist in genesis configuration
+	whitelistConfig := config.GetWhitelist(p.genesisConfig)
+
+	if whitelistConfig == nil {
+		whitelistConfig = &chain.Whitelists{}
+	}
+
+	whitelistConfig.System = newSystemWhitelist
+	p.genesisConfig.Params.Whitelists = whitelistConfig
+
+	// Save whitelist for result
+	p.whitelist = newSystemWhitelist
+
+	return nil
+}
+
+func (p *systemParams) overrideGenesisConfig() error {
+	// Remove the current genesis configuration from the disk
+	if err := os.Remove(p.genesisPath); err != nil {
+		return err
+	}
+
+	// Save the new genesis configuration
+	if err := helper.WriteGenesisConfigToDisk(
+		p.genesisConfig,
+		p.genesisPath,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *systemParams) getResult() command.CommandResult {
+	result := &SystemResult{
+		AddAddresses:    p.addAddresses,
+		RemoveAddresses: p.removeAddresses,
+		Whitelist:       p.whitelist,
+	}
+
+	return result
+}
+
+func unmarshallRawAddresses(addresses []string) []types.Address {
+	marshalledAddresses := make([]types.Address, len(addresses))
+
+	for indx, address := range addresses {
+		marshalledAddresses[indx] = types.StringToAddress(address)
+	}
+
+	return marshalledAddresses
+}
@@ -0,0 +1,68 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	systemCmd := &cobra.Command{
+		Use:     "system",
+		Short:   "Updates the senders of the system transactions, which are included before the other transactions",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(systemCmd)
+
+	return systemCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		chainFlag,
+		fmt.Sprintf("./%s", command.DefaultGenesisFileName),
+		"the genesis file to update",
+	)
+	cmd.Flags().StringArrayVar(
+		&params.addAddressRaw,
+		addAddressFlag,
+		[]string{},
+		"adds a sender of the system transactions",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.removeAddressRaw,
+		removeAddressFlag,
+		[]string{},
+		"removes a sender of the system transactions",
+	)
+
+	_ = cmd.MarkFlagFilename(chainFlag)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.updateGenesisConfig(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	if err := params.overrideGenesisConfig(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
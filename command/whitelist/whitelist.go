@@ -3,6 +3,7 @@ package whitelist
 import (
 	"github.com/0xPolygon/polygon-edge/command/whitelist/deployment"
 	"github.com/0xPolygon/polygon-edge/command/whitelist/show"
+	"github.com/0xPolygon/polygon-edge/command/whitelist/system"
 	"github.com/spf13/cobra"
 )
 
@@ -21,5 +22,6 @@ func registerSubcommands(baseCmd *cobra.Command) {
 	baseCmd.AddCommand(
 		deployment.GetCommand(),
 		show.GetCommand(),
+		system.GetCommand(),
 	)
 }
@@ -0,0 +1,333 @@
+package congestion
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+)
+
+// EventType is the kind of congestion reported by an event
+type EventType string
+
+const (
+	// PoolSaturation is reported while the transaction pool is nearly full
+	PoolSaturation EventType = "pool_saturation"
+
+	// FullBlocks is reported while the blocks keep being full
+	FullBlocks EventType = "full_blocks"
+
+	// FeeSpike is reported while the gas prices are well above their recent average
+	FeeSpike EventType = "fee_spike"
+)
+
+// subscriberBufferSize is the number of events a subscriber can lag behind before they are dropped
+const subscriberBufferSize = 32
+
+// Event is a congestion condition being raised or cleared
+type Event struct {
+	Type   EventType `json:"type"`
+	Active bool      `json:"active"`
+	Number uint64    `json:"number"` // head block when the condition changed
+	Value  float64   `json:"value"`  // measure which changed the condition
+}
+
+// Config holds the marks of the congestion conditions.
+// Every condition is raised at a high mark and cleared at a lower one
+type Config struct {
+	// share of the pool slots in use
+	PoolSaturationHigh float64
+	PoolSaturationLow  float64
+	PoolCheckInterval  time.Duration
+
+	// share of the gas limit a block must use to be full,
+	// and the number of consecutive full (or not full) blocks raising (or clearing) the condition
+	FullBlockRatio  float64
+	FullBlocksRaise uint64
+	FullBlocksClear uint64
+
+	// ratio of the median block gas price to its moving average over the baseline blocks
+	FeeSpikeHigh   float64
+	FeeSpikeLow    float64
+	BaselineBlocks uint64
+}
+
+// DefaultConfig returns the default congestion marks
+func DefaultConfig() *Config {
+	return &Config{
+		PoolSaturationHigh: 0.9,
+		PoolSaturationLow:  0.7,
+		PoolCheckInterval:  5 * time.Second,
+		FullBlockRatio:     0.95,
+		FullBlocksRaise:    5,
+		FullBlocksClear:    5,
+		FeeSpikeHigh:       2,
+		FeeSpikeLow:        1.5,
+		BaselineBlocks:     20,
+	}
+}
+
+type Blockchain interface {
+	Header() *types.Header
+	SubscribeEvents() blockchain.Subscription
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+}
+
+type TxPool interface {
+	GetCapacity() (uint64, uint64)
+}
+
+// Monitor watches the chain and the transaction pool for congestion,
+// and reports the conditions as they are raised and cleared to the subscribers and the metrics
+type Monitor struct {
+	logger     hclog.Logger
+	config     *Config
+	blockchain Blockchain
+	txpool     TxPool
+
+	subscription blockchain.Subscription
+	closeCh      chan struct{}
+
+	// state of the conditions
+	lock       sync.Mutex
+	pool       *threshold
+	fullBlocks *streak
+	fee        *threshold
+	baseline   *baseline
+
+	subsLock    sync.Mutex
+	subscribers map[uint64]chan *Event
+	nextSubID   uint64
+}
+
+// NewMonitor creates a new congestion Monitor
+func NewMonitor(logger hclog.Logger, config *Config, blockchain Blockchain, txpool TxPool) *Monitor {
+	return &Monitor{
+		logger:     logger.Named("congestion"),
+		config:     config,
+		blockchain: blockchain,
+		txpool:     txpool,
+		closeCh:    make(chan struct{}),
+		pool: &threshold{
+			high: config.PoolSaturationHigh,
+			low:  config.PoolSaturationLow,
+		},
+		fullBlocks: &streak{
+			raiseAfter: config.FullBlocksRaise,
+			clearAfter: config.FullBlocksClear,
+		},
+		fee: &threshold{
+			high: config.FeeSpikeHigh,
+			low:  config.FeeSpikeLow,
+		},
+		baseline:    newBaseline(config.BaselineBlocks),
+		subscribers: make(map[uint64]chan *Event),
+	}
+}
+
+// Start starts watching for congestion
+func (m *Monitor) Start() {
+	for _, typ := range []EventType{PoolSaturation, FullBlocks, FeeSpike} {
+		metrics.SetGauge([]string{"congestion", string(typ)}, 0)
+	}
+
+	m.subscription = m.blockchain.SubscribeEvents()
+
+	go m.run()
+}
+
+// Close stops watching for congestion
+func (m *Monitor) Close() {
+	close(m.closeCh)
+
+	if m.subscription != nil {
+		m.subscription.Close()
+	}
+}
+
+// Subscribe returns a channel of the congestion events, along with the function cancelling the subscription.
+// The events are dropped for a subscriber which doesn't keep up
+func (m *Monitor) Subscribe() (<-chan *Event, func()) {
+	m.subsLock.Lock()
+	defer m.subsLock.Unlock()
+
+	id := m.nextSubID
+	m.nextSubID++
+
+	ch := make(chan *Event, subscriberBufferSize)
+	m.subscribers[id] = ch
+
+	return ch, func() {
+		m.subsLock.Lock()
+		defer m.subsLock.Unlock()
+
+		if _, ok := m.subscribers[id]; ok {
+			delete(m.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// Active returns the conditions currently raised
+func (m *Monitor) Active() []EventType {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	active := []EventType{}
+
+	if m.pool.active {
+		active = append(active, PoolSaturation)
+	}
+
+	if m.fullBlocks.active {
+		active = append(active, FullBlocks)
+	}
+
+	if m.fee.active {
+		active = append(active, FeeSpike)
+	}
+
+	return active
+}
+
+func (m *Monitor) run() {
+	ticker := time.NewTicker(m.config.PoolCheckInterval)
+	defer ticker.Stop()
+
+	eventCh := m.subscription.GetEventCh()
+
+	for {
+		select {
+		case evnt := <-eventCh:
+			if evnt == nil || evnt.Type == blockchain.EventFork {
+				continue
+			}
+
+			for _, header := range evnt.NewChain {
+				m.checkBlock(header)
+			}
+		case <-ticker.C:
+			m.checkPool()
+		case <-m.closeCh:
+			return
+		}
+	}
+}
+
+// checkPool updates the pool saturation with the current capacity of the pool
+func (m *Monitor) checkPool() {
+	current, max := m.txpool.GetCapacity()
+	if max == 0 {
+		return
+	}
+
+	ratio := float64(current) / float64(max)
+
+	metrics.SetGauge([]string{"congestion", "pool_saturation_ratio"}, float32(ratio))
+
+	m.lock.Lock()
+	changed := m.pool.update(ratio)
+	active := m.pool.active
+	m.lock.Unlock()
+
+	if changed {
+		m.emit(&Event{
+			Type:   PoolSaturation,
+			Active: active,
+			Number: m.blockchain.Header().Number,
+			Value:  ratio,
+		})
+	}
+}
+
+// checkBlock updates the full blocks and fee spike conditions with the new block
+func (m *Monitor) checkBlock(header *types.Header) {
+	if header.GasLimit == 0 {
+		return
+	}
+
+	usage := float64(header.GasUsed) / float64(header.GasLimit)
+
+	var price *big.Int
+
+	if block, ok := m.blockchain.GetBlockByHash(header.Hash, true); ok {
+		price = medianGasPrice(block.Transactions)
+	}
+
+	metrics.SetGauge([]string{"congestion", "gas_used_ratio"}, float32(usage))
+
+	events := m.updateBlock(header.Number, usage, price)
+
+	for _, evnt := range events {
+		m.emit(evnt)
+	}
+}
+
+// updateBlock feeds the gas usage and the median gas price (if any) of the block to the conditions,
+// and returns the events of the conditions which changed
+func (m *Monitor) updateBlock(number uint64, usage float64, price *big.Int) []*Event {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	events := []*Event{}
+
+	if m.fullBlocks.update(usage >= m.config.FullBlockRatio) {
+		events = append(events, &Event{
+			Type:   FullBlocks,
+			Active: m.fullBlocks.active,
+			Number: number,
+			Value:  usage,
+		})
+	}
+
+	// the blocks without transactions tell nothing about the prices
+	if price == nil {
+		return events
+	}
+
+	value, _ := new(big.Float).SetInt(price).Float64()
+
+	if m.baseline.ready() && m.baseline.value > 0 {
+		ratio := value / m.baseline.value
+
+		metrics.SetGauge([]string{"congestion", "fee_ratio"}, float32(ratio))
+
+		if m.fee.update(ratio) {
+			events = append(events, &Event{
+				Type:   FeeSpike,
+				Active: m.fee.active,
+				Number: number,
+				Value:  ratio,
+			})
+		}
+	}
+
+	m.baseline.add(value)
+
+	return events
+}
+
+// emit reports the event to the metrics and the subscribers
+func (m *Monitor) emit(evnt *Event) {
+	if evnt.Active {
+		m.logger.Warn("congestion raised", "type", evnt.Type, "number", evnt.Number, "value", evnt.Value)
+		metrics.SetGauge([]string{"congestion", string(evnt.Type)}, 1)
+	} else {
+		m.logger.Info("congestion cleared", "type", evnt.Type, "number", evnt.Number, "value", evnt.Value)
+		metrics.SetGauge([]string{"congestion", string(evnt.Type)}, 0)
+	}
+
+	m.subsLock.Lock()
+	defer m.subsLock.Unlock()
+
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- evnt:
+		default:
+		}
+	}
+}
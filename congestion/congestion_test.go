@@ -0,0 +1,171 @@
+package congestion
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBlockchain struct{}
+
+func (m *mockBlockchain) Header() *types.Header {
+	return &types.Header{Number: 10}
+}
+
+func (m *mockBlockchain) SubscribeEvents() blockchain.Subscription {
+	return blockchain.NewMockSubscription()
+}
+
+func (m *mockBlockchain) GetBlockByHash(_ types.Hash, _ bool) (*types.Block, bool) {
+	return nil, false
+}
+
+type mockTxPool struct {
+	current uint64
+}
+
+func (m *mockTxPool) GetCapacity() (uint64, uint64) {
+	return m.current, 100
+}
+
+func newTestMonitor(t *testing.T) (*Monitor, *mockTxPool, <-chan *Event) {
+	t.Helper()
+
+	pool := &mockTxPool{}
+	m := NewMonitor(hclog.NewNullLogger(), DefaultConfig(), &mockBlockchain{}, pool)
+
+	eventCh, cancel := m.Subscribe()
+	t.Cleanup(cancel)
+
+	return m, pool, eventCh
+}
+
+func nextEvent(t *testing.T, eventCh <-chan *Event) *Event {
+	t.Helper()
+
+	select {
+	case evnt := <-eventCh:
+		return evnt
+	default:
+		t.Fatal("no congestion event")
+
+		return nil
+	}
+}
+
+func assertNoEvent(t *testing.T, eventCh <-chan *Event) {
+	t.Helper()
+
+	select {
+	case evnt := <-eventCh:
+		t.Fatalf("unexpected congestion event %v", evnt)
+	default:
+	}
+}
+
+func TestMonitor_PoolSaturation(t *testing.T) {
+	t.Parallel()
+
+	m, pool, eventCh := newTestMonitor(t)
+
+	pool.current = 90
+	m.checkPool()
+
+	evnt := nextEvent(t, eventCh)
+	assert.Equal(t, PoolSaturation, evnt.Type)
+	assert.True(t, evnt.Active)
+	assert.Equal(t, uint64(10), evnt.Number)
+	assert.Equal(t, []EventType{PoolSaturation}, m.Active())
+
+	// the condition holds until the pool drains below the low mark
+	for _, current := range []uint64{80, 95, 71} {
+		pool.current = current
+		m.checkPool()
+	}
+
+	assertNoEvent(t, eventCh)
+
+	pool.current = 70
+	m.checkPool()
+
+	evnt = nextEvent(t, eventCh)
+	assert.False(t, evnt.Active)
+	assert.Empty(t, m.Active())
+}
+
+func TestMonitor_FullBlocks(t *testing.T) {
+	t.Parallel()
+
+	m, _, _ := newTestMonitor(t)
+
+	var events []*Event
+
+	// a non full block in between starts the streak over
+	usages := []float64{1, 1, 1, 1, 0.5, 1, 1, 1, 1}
+	for i, usage := range usages {
+		events = append(events, m.updateBlock(uint64(i), usage, nil)...)
+	}
+
+	assert.Empty(t, events)
+
+	events = m.updateBlock(9, 0.96, nil)
+	require.Len(t, events, 1)
+	assert.Equal(t, FullBlocks, events[0].Type)
+	assert.True(t, events[0].Active)
+
+	for i := uint64(10); i < 14; i++ {
+		assert.Empty(t, m.updateBlock(i, 0, nil))
+	}
+
+	events = m.updateBlock(14, 0, nil)
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Active)
+}
+
+func TestMonitor_FeeSpike(t *testing.T) {
+	t.Parallel()
+
+	m, _, _ := newTestMonitor(t)
+
+	// the prices are not compared during the warm up
+	for i := uint64(0); i < 20; i++ {
+		price := int64(10)
+		if i == 0 {
+			price = 100
+		}
+
+		assert.Empty(t, m.updateBlock(i, 0, big.NewInt(price)))
+	}
+
+	events := m.updateBlock(20, 0, big.NewInt(100))
+	require.Len(t, events, 1)
+	assert.Equal(t, FeeSpike, events[0].Type)
+	assert.True(t, events[0].Active)
+	assert.Greater(t, events[0].Value, 2.0)
+
+	// the blocks without transactions don't clear the condition
+	assert.Empty(t, m.updateBlock(21, 0, nil))
+
+	events = m.updateBlock(22, 0, big.NewInt(10))
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Active)
+}
+
+func TestMedianGasPrice(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, medianGasPrice(nil))
+
+	txs := []*types.Transaction{
+		{GasPrice: big.NewInt(30)},
+		{GasPrice: big.NewInt(10)},
+		{GasPrice: big.NewInt(20)},
+	}
+
+	assert.Equal(t, big.NewInt(20), medianGasPrice(txs))
+}
@@ -0,0 +1,118 @@
+package congestion
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// threshold raises a condition once the value reaches the high mark, and clears it only once the value
+// falls back to the low mark, so a value hovering around a single mark doesn't flap the condition
+type threshold struct {
+	high   float64
+	low    float64
+	active bool
+}
+
+// update feeds the value to the threshold, and returns true if the condition changed
+func (t *threshold) update(value float64) bool {
+	switch {
+	case !t.active && value >= t.high:
+		t.active = true
+	case t.active && value <= t.low:
+		t.active = false
+	default:
+		return false
+	}
+
+	return true
+}
+
+// streak raises a condition after a number of consecutive hits,
+// and clears it after a number of consecutive misses
+type streak struct {
+	raiseAfter uint64
+	clearAfter uint64
+	hits       uint64
+	misses     uint64
+	active     bool
+}
+
+// update feeds the outcome of a block to the streak, and returns true if the condition changed
+func (s *streak) update(hit bool) bool {
+	if hit {
+		s.hits++
+		s.misses = 0
+	} else {
+		s.misses++
+		s.hits = 0
+	}
+
+	switch {
+	case !s.active && s.hits >= s.raiseAfter:
+		s.active = true
+	case s.active && s.misses >= s.clearAfter:
+		s.active = false
+	default:
+		return false
+	}
+
+	return true
+}
+
+// baseline is the exponential moving average of the block gas prices,
+// it is not ready until it has seen enough blocks
+type baseline struct {
+	alpha   float64
+	warmup  uint64
+	samples uint64
+	value   float64
+}
+
+func newBaseline(blocks uint64) *baseline {
+	return &baseline{
+		alpha:  2 / float64(blocks+1),
+		warmup: blocks,
+	}
+}
+
+// ready returns true once the baseline has seen enough blocks to compare the prices with
+func (b *baseline) ready() bool {
+	return b.samples >= b.warmup
+}
+
+func (b *baseline) add(price float64) {
+	if b.samples == 0 {
+		b.value = price
+	} else {
+		b.value += b.alpha * (price - b.value)
+	}
+
+	b.samples++
+}
+
+// medianGasPrice returns the median gas price of the transactions, or nil if there are none
+func medianGasPrice(txs []*types.Transaction) *big.Int {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	prices := make([]*big.Int, 0, len(txs))
+
+	for _, tx := range txs {
+		if tx.GasPrice != nil {
+			prices = append(prices, tx.GasPrice)
+		}
+	}
+
+	if len(prices) == 0 {
+		return nil
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+
+	return prices[len(prices)/2]
+}
@@ -6,10 +6,12 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/clock"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/syncer"
 	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -44,6 +46,22 @@ type Consensus interface {
 	Close() error
 }
 
+// Participation is how many of the validators of a block sealed it
+type Participation struct {
+	// Validators is the number of the validators of the block
+	Validators int
+	// Sealed is the number of the validators which committed the block
+	Sealed int
+	// Validator is whether the node is one of the validators of the block
+	Validator bool
+}
+
+// ParticipationReporter is implemented by the consensus mechanisms sealing the blocks by several validators
+type ParticipationReporter interface {
+	// GetParticipation returns how many of the validators of the header sealed it
+	GetParticipation(header *types.Header) (*Participation, error)
+}
+
 // Config is the configuration for the consensus
 type Config struct {
 	// Logger to be used by the consensus
@@ -70,6 +88,16 @@ type Params struct {
 	Logger         hclog.Logger
 	SecretsManager secrets.SecretsManager
 	BlockTime      uint64
+	SnapSyncer     syncer.SnapSyncer
+
+	// StrictSignState makes the validator refuse to start without the up to date last sign state
+	StrictSignState bool
+
+	// Checkpoint is the trusted checkpoint the chain starts from, nil to start from the genesis
+	Checkpoint *syncer.CheckpointConfig
+
+	// Clock is the clock of the consensus, the system clock if nil
+	Clock clock.Clock
 }
 
 // Factory is the factory function to create a discovery consensus
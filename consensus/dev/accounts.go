@@ -0,0 +1,42 @@
+package dev
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// accountSeed is hashed along with the index of a dev account into its private key
+const accountSeed = "polygon-edge dev account"
+
+// Account is an account funded in the genesis of the dev chain
+type Account struct {
+	Address    types.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// GenerateAccounts derives the keys of the dev accounts, which are the same on every run,
+// so the tools and the tests of the developers can keep using them. They must never hold real funds
+func GenerateAccounts(count uint64) ([]*Account, error) {
+	accounts := make([]*Account, 0, count)
+
+	for i := uint64(0); i < count; i++ {
+		index := make([]byte, 8)
+		binary.BigEndian.PutUint64(index, i)
+
+		key, err := crypto.ParseECDSAPrivateKey(crypto.Keccak256([]byte(accountSeed), index))
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive dev account %d: %w", i, err)
+		}
+
+		accounts = append(accounts, &Account{
+			Address:    crypto.PubKeyToAddress(&key.PublicKey),
+			PrivateKey: key,
+		})
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,33 @@
+package dev
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+)
+
+func TestGenerateAccounts(t *testing.T) {
+	t.Parallel()
+
+	accounts, err := GenerateAccounts(3)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+
+	// the accounts are the same on every run
+	again, err := GenerateAccounts(3)
+	require.NoError(t, err)
+
+	seen := make(map[string]struct{})
+
+	for i, account := range accounts {
+		assert.Equal(t, again[i].Address, account.Address)
+		assert.Equal(t, crypto.PubKeyToAddress(&account.PrivateKey.PublicKey), account.Address)
+
+		seen[account.Address.String()] = struct{}{}
+	}
+
+	assert.Len(t, seen, 3)
+}
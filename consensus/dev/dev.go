@@ -1,14 +1,18 @@
 package dev
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/helper/clock"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/txpool"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 )
@@ -17,7 +21,12 @@ const (
 	devConsensus = "dev-consensus"
 )
 
-// Dev consensus protocol seals any new transaction immediately
+var (
+	errBlockNotSealed = errors.New("no block sealed")
+)
+
+// Dev consensus protocol seals a block per new transaction as soon as it arrives,
+// or a block of all the pending transactions on every interval if one is set
 type Dev struct {
 	logger hclog.Logger
 
@@ -26,9 +35,23 @@ type Dev struct {
 
 	interval uint64
 	txpool   *txpool.TxPool
+	clock    clock.Clock
 
 	blockchain *blockchain.Blockchain
 	executor   *state.Executor
+
+	// lock serializes the sealing of the blocks with the changes of the clock and the snapshots
+	lock sync.Mutex
+	// timeOffset is the number of seconds the timestamps of the blocks are moved from the clock
+	timeOffset int64
+	// snapshots are the saved chain heads, the ID of a snapshot is its index plus one
+	snapshots []snapshot
+}
+
+// snapshot is a chain head the chain can be reverted to, along with the time offset at that head
+type snapshot struct {
+	number     uint64
+	timeOffset int64
 }
 
 // Factory implements the base factory method
@@ -44,6 +67,7 @@ func Factory(
 		blockchain: params.Blockchain,
 		executor:   params.Executor,
 		txpool:     params.TxPool,
+		clock:      clock.OrSystem(params.Clock),
 	}
 
 	rawInterval, ok := params.Config.Config["interval"]
@@ -68,26 +92,27 @@ func (d *Dev) Initialize() error {
 
 // Start starts the consensus mechanism
 func (d *Dev) Start() error {
-	go d.run()
+	if d.interval == 0 {
+		go d.runInstantSeal()
+	} else {
+		go d.run()
+	}
 
 	return nil
 }
 
 func (d *Dev) nextNotify() chan struct{} {
-	if d.interval == 0 {
-		d.interval = 1
-	}
-
 	go func() {
-		<-time.After(time.Duration(d.interval) * time.Second)
+		<-d.clock.After(time.Duration(d.interval) * time.Second)
 		d.notifyCh <- struct{}{}
 	}()
 
 	return d.notifyCh
 }
 
+// run seals a block of the pending transactions on every interval
 func (d *Dev) run() {
-	d.logger.Info("consensus started")
+	d.logger.Info("consensus started", "interval", d.interval)
 
 	for {
 		// wait until there is a new txn
@@ -98,89 +123,106 @@ func (d *Dev) run() {
 		}
 
 		// There are new transactions in the pool, try to seal them
-		header := d.blockchain.Header()
-		if err := d.writeNewBlock(header); err != nil {
+		if _, err := d.sealBlock(0, true, nil); err != nil {
 			d.logger.Error("failed to mine block", "err", err)
 		}
 	}
 }
 
-type transitionInterface interface {
-	Write(txn *types.Transaction) error
-}
-
-func (d *Dev) writeTransactions(gasLimit uint64, transition transitionInterface) []*types.Transaction {
-	var successful []*types.Transaction
+// runInstantSeal seals a block per transaction as soon as it's promoted in the pool
+func (d *Dev) runInstantSeal() {
+	d.logger.Info("consensus started", "interval", "instant")
 
-	d.txpool.Prepare()
+	eventCh, cancel := d.txpool.SubscribeTxEvents(txpoolProto.EventType_PROMOTED)
+	defer cancel()
 
 	for {
-		tx := d.txpool.Peek()
-		if tx == nil {
-			break
+		select {
+		case <-eventCh:
+		case <-d.closeCh:
+			return
 		}
 
-		if tx.ExceedsBlockGasLimit(gasLimit) {
-			d.txpool.Drop(tx)
-
-			continue
-		}
+		for d.txpool.Length() > 0 {
+			if _, err := d.sealBlock(1, false, nil); err != nil {
+				if !errors.Is(err, errBlockNotSealed) {
+					d.logger.Error("failed to mine block", "err", err)
+				}
 
-		if err := transition.Write(tx); err != nil {
-			if _, ok := err.(*state.GasLimitReachedTransitionApplicationError); ok { //nolint:errorlint
 				break
-			} else if appErr, ok := err.(*state.TransitionApplicationError); ok && appErr.IsRecoverable { //nolint:errorlint
-				d.txpool.Demote(tx)
-			} else {
-				d.txpool.Drop(tx)
 			}
-
-			continue
 		}
+	}
+}
+
+// sealBlock writes a block of at most maxTxs transactions from the pool on top of the head,
+// all the pending ones if maxTxs is 0. An empty block is only written if allowEmpty is set.
+// The block is stamped with the clock moved by the time offset, unless a timestamp is given
+func (d *Dev) sealBlock(maxTxs int, allowEmpty bool, timestamp *uint64) (*types.Block, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 
-		// no errors, pop the tx from the pool
-		d.txpool.Pop(tx)
+	parent := d.blockchain.Header()
 
-		successful = append(successful, tx)
+	if timestamp != nil {
+		d.timeOffset = int64(*timestamp) - d.clock.Now().Unix()
 	}
 
-	d.logger.Info("picked out txns from pool", "num", len(successful), "remaining", d.txpool.Length())
+	return d.writeNewBlock(parent, maxTxs, allowEmpty)
+}
+
+// blockTimestamp returns the timestamp of the child of the parent, which never goes back
+// from the parent's one
+func (d *Dev) blockTimestamp(parent *types.Header) uint64 {
+	timestamp := d.clock.Now().Unix() + d.timeOffset
+	if timestamp < 0 || uint64(timestamp) < parent.Timestamp {
+		return parent.Timestamp
+	}
 
-	return successful
+	return uint64(timestamp)
 }
 
 // writeNewBLock generates a new block based on transactions from the pool,
 // and writes them to the blockchain
-func (d *Dev) writeNewBlock(parent *types.Header) error {
+func (d *Dev) writeNewBlock(parent *types.Header, maxTxs int, allowEmpty bool) (*types.Block, error) {
 	// Generate the base block
 	num := parent.Number
 	header := &types.Header{
 		ParentHash: parent.Hash,
 		Number:     num + 1,
 		GasLimit:   parent.GasLimit, // Inherit from parent for now, will need to adjust dynamically later.
-		Timestamp:  uint64(time.Now().Unix()),
+		Timestamp:  d.blockTimestamp(parent),
 	}
 
 	// calculate gas limit based on parent header
 	gasLimit, err := d.blockchain.CalculateGasLimit(header.Number)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	header.GasLimit = gasLimit
 
 	miner, err := d.GetBlockCreator(header)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	transition, err := d.executor.BeginTxn(parent.StateRoot, header, miner)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	txns := d.writeTransactions(gasLimit, transition)
+	txns := consensus.WriteTransactions(d.txpool, transition, consensus.WriteTransactionsParams{
+		Number:   header.Number,
+		GasLimit: gasLimit,
+		MaxSize:  d.blockchain.Config().MaxBlockSizeAt(header.Number),
+		MaxTxs:   maxTxs,
+	})
+
+	if len(txns) == 0 && !allowEmpty {
+		return nil, errBlockNotSealed
+	}
 
 	// Commit the changes
 	_, root := transition.Commit()
@@ -198,19 +240,75 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 	})
 
 	if err := d.blockchain.VerifyFinalizedBlock(block); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Write the block to the blockchain
 	if err := d.blockchain.WriteBlock(block, devConsensus); err != nil {
-		return err
+		return nil, err
 	}
 
 	// after the block has been written we reset the txpool so that
 	// the old transactions are removed
 	d.txpool.ResetWithHeaders(block.Header)
 
-	return nil
+	return block, nil
+}
+
+// Mine seals a block of all the pending transactions right away, even if there are none.
+// If a timestamp is given, the block is stamped with it and the following blocks are stamped from it
+func (d *Dev) Mine(timestamp *uint64) error {
+	_, err := d.sealBlock(0, true, timestamp)
+
+	return err
+}
+
+// IncreaseTime moves the timestamps of the next blocks forward by the seconds,
+// returning the total number of seconds they are moved from the clock
+func (d *Dev) IncreaseTime(seconds uint64) int64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.timeOffset += int64(seconds)
+
+	return d.timeOffset
+}
+
+// Snapshot saves the head of the chain, returning the ID the chain can be reverted with
+func (d *Dev) Snapshot() uint64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.snapshots = append(d.snapshots, snapshot{
+		number:     d.blockchain.Header().Number,
+		timeOffset: d.timeOffset,
+	})
+
+	return uint64(len(d.snapshots))
+}
+
+// Revert rewinds the chain to the head saved by the snapshot, dropping the pending transactions.
+// The snapshot and the ones taken after it are discarded. It returns false if there is no such snapshot
+func (d *Dev) Revert(id uint64) (bool, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if id == 0 || id > uint64(len(d.snapshots)) {
+		return false, nil
+	}
+
+	snap := d.snapshots[id-1]
+
+	if err := d.blockchain.SetHead(snap.number); err != nil {
+		return false, err
+	}
+
+	d.txpool.Rewind(d.blockchain.Header())
+
+	d.timeOffset = snap.timeOffset
+	d.snapshots = d.snapshots[:id-1]
+
+	return true, nil
 }
 
 // REQUIRED BASE INTERFACE METHODS //
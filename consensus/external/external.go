@@ -0,0 +1,272 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/external/proto"
+	"github.com/0xPolygon/polygon-edge/helper/clock"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/txpool"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// KeyEngineAddr is the key of the gRPC address of the external engine in the engine config
+	KeyEngineAddr = "engineAddr"
+	// KeyHostAddr is the key of the gRPC address the node serves the EngineHost service on in the engine config
+	KeyHostAddr = "hostAddr"
+
+	// DefaultEngineAddr is the address of the external engine running along with the node
+	DefaultEngineAddr = "127.0.0.1:9700"
+	// DefaultHostAddr is the address the node serves the EngineHost service on
+	DefaultHostAddr = "127.0.0.1:9701"
+
+	externalConsensus = "external-consensus"
+
+	// engineCallTimeout is the timeout of the calls to the external engine
+	engineCallTimeout = 10 * time.Second
+
+	// unixScheme is the scheme of the addresses of the unix sockets
+	unixScheme = "unix:"
+)
+
+var (
+	errInvalidEngineAddr = errors.New("the external engine address must be a string")
+	errInvalidHostAddr   = errors.New("the engine host address must be a string")
+	errNonLocalAddr      = errors.New("the address must be a loopback address or a unix socket")
+	errInvalidCreator    = errors.New("invalid block creator returned by the external engine")
+)
+
+// External consensus protocol delegates the verification of the blocks to an engine outside of the node,
+// which drives the block production by building and writing the blocks through the EngineHost service.
+// Both the engine and the EngineHost service are only reached locally, the calls aren't authenticated
+type External struct {
+	logger hclog.Logger
+
+	blockchain *blockchain.Blockchain
+	executor   *state.Executor
+	txpool     *txpool.TxPool
+	clock      clock.Clock
+
+	conn   *grpc.ClientConn
+	engine proto.EngineClient
+
+	// hostAddr is the address the EngineHost service is served on,
+	// apart from the gRPC server of the node which can be exposed
+	hostAddr   string
+	hostServer *grpc.Server
+
+	// lock serializes the building and the writing of the blocks
+	lock sync.Mutex
+}
+
+// Factory implements the base factory method
+func Factory(params *consensus.Params) (consensus.Consensus, error) {
+	engineAddr, err := getConfigAddr(params.Config.Config, KeyEngineAddr, DefaultEngineAddr, errInvalidEngineAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	hostAddr, err := getConfigAddr(params.Config.Config, KeyHostAddr, DefaultHostAddr, errInvalidHostAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	// the connection is established on the first call, the engine can start after the node
+	conn, err := grpc.Dial(engineAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the external engine at %s: %w", engineAddr, err)
+	}
+
+	return &External{
+		logger:     params.Logger.Named("external"),
+		blockchain: params.Blockchain,
+		executor:   params.Executor,
+		txpool:     params.TxPool,
+		clock:      clock.OrSystem(params.Clock),
+		conn:       conn,
+		engine:     proto.NewEngineClient(conn),
+		hostAddr:   hostAddr,
+	}, nil
+}
+
+// getConfigAddr returns the local address set in the engine config, the default one if it isn't set
+func getConfigAddr(config map[string]interface{}, key, defaultAddr string, errInvalid error) (string, error) {
+	addr := defaultAddr
+
+	if rawAddr, ok := config[key]; ok {
+		if addr, ok = rawAddr.(string); !ok {
+			return "", errInvalid
+		}
+	}
+
+	if err := CheckLocalAddr(addr); err != nil {
+		return "", fmt.Errorf("invalid %s %q: %w", key, addr, err)
+	}
+
+	return addr, nil
+}
+
+// CheckLocalAddr makes sure the address is a loopback address or a unix socket,
+// the external engine runs along with the node
+func CheckLocalAddr(addr string) error {
+	if strings.HasPrefix(addr, unixScheme) {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return errNonLocalAddr
+	}
+
+	return nil
+}
+
+// listenLocal listens on the loopback address or the unix socket, the socket is only
+// accessible to the user running the node
+func listenLocal(addr string) (net.Listener, error) {
+	if !strings.HasPrefix(addr, unixScheme) {
+		return net.Listen("tcp", addr)
+	}
+
+	path := strings.TrimPrefix(strings.TrimPrefix(addr, unixScheme), "//")
+
+	// remove the socket left by a previous run
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// Initialize sets up the service the external engine drives the block production with
+func (e *External) Initialize() error {
+	e.txpool.SetSealing(true)
+
+	e.hostServer = grpc.NewServer()
+	proto.RegisterEngineHostServer(e.hostServer, &hostService{external: e})
+
+	return nil
+}
+
+// Start serves the EngineHost service, the blocks are produced once the external engine drives it
+func (e *External) Start() error {
+	listener, err := listenLocal(e.hostAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the external engine on %s: %w", e.hostAddr, err)
+	}
+
+	go func() {
+		if err := e.hostServer.Serve(listener); err != nil {
+			e.logger.Error("engine host stopped", "err", err)
+		}
+	}()
+
+	e.logger.Info("consensus started", "engine", e.conn.Target(), "host", listener.Addr())
+
+	return nil
+}
+
+// REQUIRED BASE INTERFACE METHODS //
+
+// VerifyHeader verifies the header with the external engine
+func (e *External) VerifyHeader(header *types.Header) error {
+	ctx, cancel := context.WithTimeout(context.Background(), engineCallTimeout)
+	defer cancel()
+
+	if _, err := e.engine.VerifyHeader(ctx, toProtoHeader(header)); err != nil {
+		return fmt.Errorf("external engine rejected the header %d: %w", header.Number, err)
+	}
+
+	return nil
+}
+
+// ProcessHeaders notifies the external engine of the headers written to the chain
+func (e *External) ProcessHeaders(headers []*types.Header) error {
+	ctx, cancel := context.WithTimeout(context.Background(), engineCallTimeout)
+	defer cancel()
+
+	req := &proto.RawHeaders{Headers: make([]*proto.RawHeader, len(headers))}
+	for idx, header := range headers {
+		req.Headers[idx] = toProtoHeader(header)
+	}
+
+	_, err := e.engine.ProcessHeaders(ctx, req)
+
+	return err
+}
+
+// GetBlockCreator returns the creator of the block returned by the external engine
+func (e *External) GetBlockCreator(header *types.Header) (types.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), engineCallTimeout)
+	defer cancel()
+
+	creator, err := e.engine.BlockCreator(ctx, toProtoHeader(header))
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	if len(creator.Address) != types.AddressLength {
+		return types.ZeroAddress, errInvalidCreator
+	}
+
+	return types.BytesToAddress(creator.Address), nil
+}
+
+// PreCommitState a hook to be called before finalizing state transition on inserting block
+func (e *External) PreCommitState(_ *types.Header, _ *state.Transition) error {
+	return nil
+}
+
+// GetSyncProgression returns nil, the external engine brings the blocks of the other nodes
+func (e *External) GetSyncProgression() *progress.Progression {
+	return nil
+}
+
+// Close stops the EngineHost service and closes the connection to the external engine
+func (e *External) Close() error {
+	if e.hostServer != nil {
+		e.hostServer.Stop()
+	}
+
+	return e.conn.Close()
+}
+
+func toProtoHeader(header *types.Header) *proto.RawHeader {
+	return &proto.RawHeader{Rlp: header.MarshalRLP()}
+}
@@ -0,0 +1,197 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/0xPolygon/polygon-edge/consensus/external/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var errRejected = errors.New("rejected")
+
+// mockEngine is an external engine accepting the headers of the even blocks
+type mockEngine struct {
+	proto.UnimplementedEngineServer
+
+	creator   []byte
+	processed []uint64
+}
+
+func (m *mockEngine) VerifyHeader(_ context.Context, req *proto.RawHeader) (*empty.Empty, error) {
+	header := &types.Header{}
+	if err := header.UnmarshalRLP(req.Rlp); err != nil {
+		return nil, err
+	}
+
+	if header.Number%2 != 0 {
+		return nil, errRejected
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (m *mockEngine) ProcessHeaders(_ context.Context, req *proto.RawHeaders) (*empty.Empty, error) {
+	for _, raw := range req.Headers {
+		header := &types.Header{}
+		if err := header.UnmarshalRLP(raw.Rlp); err != nil {
+			return nil, err
+		}
+
+		m.processed = append(m.processed, header.Number)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (m *mockEngine) BlockCreator(context.Context, *proto.RawHeader) (*proto.Creator, error) {
+	return &proto.Creator{Address: m.creator}, nil
+}
+
+// newTestExternal returns the consensus connected to the engine served in memory
+func newTestExternal(t *testing.T, engine proto.EngineServer) *External {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	proto.RegisterEngineServer(server, engine)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return &External{
+		conn:   conn,
+		engine: proto.NewEngineClient(conn),
+	}
+}
+
+func TestExternal_VerifyHeader(t *testing.T) {
+	t.Parallel()
+
+	external := newTestExternal(t, &mockEngine{})
+
+	assert.NoError(t, external.VerifyHeader(&types.Header{Number: 2}))
+	assert.ErrorContains(t, external.VerifyHeader(&types.Header{Number: 3}), errRejected.Error())
+}
+
+func TestExternal_ProcessHeaders(t *testing.T) {
+	t.Parallel()
+
+	engine := &mockEngine{}
+	external := newTestExternal(t, engine)
+
+	require.NoError(t, external.ProcessHeaders([]*types.Header{{Number: 1}, {Number: 2}}))
+	assert.Equal(t, []uint64{1, 2}, engine.processed)
+}
+
+func TestExternal_GetBlockCreator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		creator  []byte
+		expected types.Address
+		err      error
+	}{
+		{
+			name:     "the creator returned by the engine",
+			creator:  types.StringToAddress("1").Bytes(),
+			expected: types.StringToAddress("1"),
+		},
+		{
+			name:     "an invalid creator",
+			creator:  []byte{0x1},
+			expected: types.ZeroAddress,
+			err:      errInvalidCreator,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			external := newTestExternal(t, &mockEngine{creator: tt.creator})
+
+			creator, err := external.GetBlockCreator(&types.Header{Number: 1})
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, creator)
+		})
+	}
+}
+
+func TestCheckLocalAddr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		addr string
+		err  bool
+	}{
+		{addr: DefaultEngineAddr},
+		{addr: "[::1]:9700"},
+		{addr: "localhost:9700"},
+		{addr: "unix:///tmp/engine.sock"},
+		{addr: "0.0.0.0:9700", err: true},
+		{addr: "10.0.0.1:9700", err: true},
+		{addr: "engine.example.com:9700", err: true},
+		{addr: "127.0.0.1", err: true},
+	}
+
+	for _, tt := range tests {
+		err := CheckLocalAddr(tt.addr)
+		assert.Equal(t, tt.err, err != nil, tt.addr)
+	}
+}
+
+func TestExternal_HostListener(t *testing.T) {
+	t.Parallel()
+
+	hostAddr := "unix://" + filepath.Join(t.TempDir(), "host.sock")
+
+	external := newTestExternal(t, &mockEngine{})
+	external.logger = hclog.NewNullLogger()
+	external.hostAddr = hostAddr
+	external.hostServer = grpc.NewServer()
+	proto.RegisterEngineHostServer(external.hostServer, &hostService{external: external})
+
+	require.NoError(t, external.Start())
+	t.Cleanup(external.hostServer.Stop)
+
+	conn, err := grpc.Dial(hostAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	// the block is decoded before reaching the chain
+	_, err = proto.NewEngineHostClient(conn).WriteBlock(context.Background(), &proto.RawBlock{Rlp: []byte{0x1}})
+	assert.ErrorContains(t, err, "failed to decode the block")
+}
@@ -0,0 +1,146 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/external/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var errInvalidMiner = errors.New("the miner must be an address")
+
+// hostService is the EngineHost service the external engine builds and writes the blocks with
+type hostService struct {
+	proto.UnimplementedEngineHostServer
+
+	external *External
+}
+
+// Head returns the header of the head of the chain
+func (h *hostService) Head(context.Context, *empty.Empty) (*proto.RawHeader, error) {
+	return toProtoHeader(h.external.blockchain.Header()), nil
+}
+
+// BuildBlock builds a block of the pending transactions on top of the head, without writing it.
+// The transactions of the block leave the pool
+func (h *hostService) BuildBlock(_ context.Context, req *proto.BuildBlockReq) (*proto.RawBlock, error) {
+	if len(req.Miner) != 0 && len(req.Miner) != types.AddressLength {
+		return nil, errInvalidMiner
+	}
+
+	block, err := h.external.buildBlock(
+		types.BytesToAddress(req.Miner),
+		req.ExtraData,
+		req.Timestamp,
+		int(req.MaxTxs),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.RawBlock{Rlp: block.MarshalRLP()}, nil
+}
+
+// WriteBlock verifies the block sealed by the external engine and writes it to the chain
+func (h *hostService) WriteBlock(_ context.Context, req *proto.RawBlock) (*empty.Empty, error) {
+	block := &types.Block{}
+	if err := block.UnmarshalRLP(req.Rlp); err != nil {
+		return nil, fmt.Errorf("failed to decode the block: %w", err)
+	}
+
+	if err := h.external.writeBlock(block); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// buildBlock builds a block of at most maxTxs transactions from the pool on top of the head,
+// all the pending ones if maxTxs is 0. The block is stamped with the clock if no timestamp is given
+func (e *External) buildBlock(
+	miner types.Address,
+	extraData []byte,
+	timestamp uint64,
+	maxTxs int,
+) (*types.Block, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	parent := e.blockchain.Header()
+
+	if timestamp == 0 {
+		timestamp = uint64(e.clock.Now().Unix())
+	}
+
+	// the timestamps never go back from the parent's one
+	if timestamp < parent.Timestamp {
+		timestamp = parent.Timestamp
+	}
+
+	header := &types.Header{
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		Miner:      miner.Bytes(),
+		ExtraData:  extraData,
+		Timestamp:  timestamp,
+	}
+
+	gasLimit, err := e.blockchain.CalculateGasLimit(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	header.GasLimit = gasLimit
+
+	transition, err := e.executor.BeginTxn(parent.StateRoot, header, miner)
+	if err != nil {
+		return nil, err
+	}
+
+	txns := consensus.WriteTransactions(e.txpool, transition, consensus.WriteTransactionsParams{
+		Number:   header.Number,
+		GasLimit: gasLimit,
+		MaxSize:  e.blockchain.Config().MaxBlockSizeAt(header.Number),
+		MaxTxs:   maxTxs,
+	})
+
+	// Commit the changes
+	_, root := transition.Commit()
+
+	header.StateRoot = root
+	header.GasUsed = transition.TotalGas()
+
+	return consensus.BuildBlock(consensus.BuildBlockParams{
+		Header:   header,
+		Txns:     txns,
+		Receipts: transition.Receipts(),
+	}), nil
+}
+
+// writeBlock verifies the block, along with the seal of the external engine, and writes it to the chain
+func (e *External) writeBlock(block *types.Block) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	// the header hash covers the seal the engine set
+	block.Header.ComputeHash()
+
+	if err := e.blockchain.VerifyFinalizedBlock(block); err != nil {
+		return err
+	}
+
+	if err := e.blockchain.WriteBlock(block, externalConsensus); err != nil {
+		return err
+	}
+
+	// after the block has been written we reset the txpool so that
+	// the old transactions are removed
+	e.txpool.ResetWithHeaders(block.Header)
+
+	return nil
+}
@@ -0,0 +1,467 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: consensus/external/proto/external_engine.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RawHeader is an RLP encoded header
+type RawHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rlp []byte `protobuf:"bytes,1,opt,name=rlp,proto3" json:"rlp,omitempty"`
+}
+
+func (x *RawHeader) Reset() {
+	*x = RawHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_consensus_external_proto_external_engine_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RawHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawHeader) ProtoMessage() {}
+
+func (x *RawHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_consensus_external_proto_external_engine_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawHeader.ProtoReflect.Descriptor instead.
+func (*RawHeader) Descriptor() ([]byte, []int) {
+	return file_consensus_external_proto_external_engine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RawHeader) GetRlp() []byte {
+	if x != nil {
+		return x.Rlp
+	}
+	return nil
+}
+
+type RawHeaders struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Headers []*RawHeader `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
+}
+
+func (x *RawHeaders) Reset() {
+	*x = RawHeaders{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_consensus_external_proto_external_engine_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RawHeaders) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawHeaders) ProtoMessage() {}
+
+func (x *RawHeaders) ProtoReflect() protoreflect.Message {
+	mi := &file_consensus_external_proto_external_engine_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawHeaders.ProtoReflect.Descriptor instead.
+func (*RawHeaders) Descriptor() ([]byte, []int) {
+	return file_consensus_external_proto_external_engine_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RawHeaders) GetHeaders() []*RawHeader {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+// RawBlock is an RLP encoded block
+type RawBlock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rlp []byte `protobuf:"bytes,1,opt,name=rlp,proto3" json:"rlp,omitempty"`
+}
+
+func (x *RawBlock) Reset() {
+	*x = RawBlock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_consensus_external_proto_external_engine_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RawBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawBlock) ProtoMessage() {}
+
+func (x *RawBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_consensus_external_proto_external_engine_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawBlock.ProtoReflect.Descriptor instead.
+func (*RawBlock) Descriptor() ([]byte, []int) {
+	return file_consensus_external_proto_external_engine_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RawBlock) GetRlp() []byte {
+	if x != nil {
+		return x.Rlp
+	}
+	return nil
+}
+
+type Creator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *Creator) Reset() {
+	*x = Creator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_consensus_external_proto_external_engine_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Creator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Creator) ProtoMessage() {}
+
+func (x *Creator) ProtoReflect() protoreflect.Message {
+	mi := &file_consensus_external_proto_external_engine_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Creator.ProtoReflect.Descriptor instead.
+func (*Creator) Descriptor() ([]byte, []int) {
+	return file_consensus_external_proto_external_engine_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Creator) GetAddress() []byte {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+type BuildBlockReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// miner is the address set as the miner of the block
+	Miner []byte `protobuf:"bytes,1,opt,name=miner,proto3" json:"miner,omitempty"`
+	// extra_data is the extra data of the header, which the engine seals the block in
+	ExtraData []byte `protobuf:"bytes,2,opt,name=extra_data,json=extraData,proto3" json:"extra_data,omitempty"`
+	// timestamp is the timestamp of the block, the current time if zero
+	Timestamp uint64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// max_txs is the highest number of transactions of the block, all the pending ones if zero
+	MaxTxs uint64 `protobuf:"varint,4,opt,name=max_txs,json=maxTxs,proto3" json:"max_txs,omitempty"`
+}
+
+func (x *BuildBlockReq) Reset() {
+	*x = BuildBlockReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_consensus_external_proto_external_engine_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildBlockReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildBlockReq) ProtoMessage() {}
+
+func (x *BuildBlockReq) ProtoReflect() protoreflect.Message {
+	mi := &file_consensus_external_proto_external_engine_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildBlockReq.ProtoReflect.Descriptor instead.
+func (*BuildBlockReq) Descriptor() ([]byte, []int) {
+	return file_consensus_external_proto_external_engine_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BuildBlockReq) GetMiner() []byte {
+	if x != nil {
+		return x.Miner
+	}
+	return nil
+}
+
+func (x *BuildBlockReq) GetExtraData() []byte {
+	if x != nil {
+		return x.ExtraData
+	}
+	return nil
+}
+
+func (x *BuildBlockReq) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BuildBlockReq) GetMaxTxs() uint64 {
+	if x != nil {
+		return x.MaxTxs
+	}
+	return 0
+}
+
+var File_consensus_external_proto_external_engine_proto protoreflect.FileDescriptor
+
+var file_consensus_external_proto_external_engine_proto_rawDesc = []byte{
+	0x0a, 0x2e, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x2f, 0x65, 0x78, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x5f, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x02, 0x76, 0x31, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x1d, 0x0a, 0x09, 0x52, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x10,
+	0x0a, 0x03, 0x72, 0x6c, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x72, 0x6c, 0x70,
+	0x22, 0x35, 0x0a, 0x0a, 0x52, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x27,
+	0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x07,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x22, 0x1c, 0x0a, 0x08, 0x52, 0x61, 0x77, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x6c, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x03, 0x72, 0x6c, 0x70, 0x22, 0x23, 0x0a, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x7b, 0x0a, 0x0d, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x6d,
+	0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6d, 0x69, 0x6e, 0x65,
+	0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x17,
+	0x0a, 0x07, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x78, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x6d, 0x61, 0x78, 0x54, 0x78, 0x73, 0x32, 0xa5, 0x01, 0x0a, 0x06, 0x45, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x12, 0x35, 0x0a, 0x0c, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x12, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x38, 0x0a, 0x0e, 0x50, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x0e, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x12, 0x2a, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x1a, 0x0b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72, 0x32,
+	0x9e, 0x01, 0x0a, 0x0a, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x2d,
+	0x0a, 0x04, 0x48, 0x65, 0x61, 0x64, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0d,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x77, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x2d, 0x0a,
+	0x0a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x11, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x0c,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x77, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x32, 0x0a, 0x0a,
+	0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x0c, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x61, 0x77, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x42, 0x1b, 0x5a, 0x19, 0x2f, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x2f, 0x65,
+	0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_consensus_external_proto_external_engine_proto_rawDescOnce sync.Once
+	file_consensus_external_proto_external_engine_proto_rawDescData = file_consensus_external_proto_external_engine_proto_rawDesc
+)
+
+func file_consensus_external_proto_external_engine_proto_rawDescGZIP() []byte {
+	file_consensus_external_proto_external_engine_proto_rawDescOnce.Do(func() {
+		file_consensus_external_proto_external_engine_proto_rawDescData = protoimpl.X.CompressGZIP(file_consensus_external_proto_external_engine_proto_rawDescData)
+	})
+	return file_consensus_external_proto_external_engine_proto_rawDescData
+}
+
+var file_consensus_external_proto_external_engine_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_consensus_external_proto_external_engine_proto_goTypes = []interface{}{
+	(*RawHeader)(nil),     // 0: v1.RawHeader
+	(*RawHeaders)(nil),    // 1: v1.RawHeaders
+	(*RawBlock)(nil),      // 2: v1.RawBlock
+	(*Creator)(nil),       // 3: v1.Creator
+	(*BuildBlockReq)(nil), // 4: v1.BuildBlockReq
+	(*emptypb.Empty)(nil), // 5: google.protobuf.Empty
+}
+var file_consensus_external_proto_external_engine_proto_depIdxs = []int32{
+	0, // 0: v1.RawHeaders.headers:type_name -> v1.RawHeader
+	0, // 1: v1.Engine.VerifyHeader:input_type -> v1.RawHeader
+	1, // 2: v1.Engine.ProcessHeaders:input_type -> v1.RawHeaders
+	0, // 3: v1.Engine.BlockCreator:input_type -> v1.RawHeader
+	5, // 4: v1.EngineHost.Head:input_type -> google.protobuf.Empty
+	4, // 5: v1.EngineHost.BuildBlock:input_type -> v1.BuildBlockReq
+	2, // 6: v1.EngineHost.WriteBlock:input_type -> v1.RawBlock
+	5, // 7: v1.Engine.VerifyHeader:output_type -> google.protobuf.Empty
+	5, // 8: v1.Engine.ProcessHeaders:output_type -> google.protobuf.Empty
+	3, // 9: v1.Engine.BlockCreator:output_type -> v1.Creator
+	0, // 10: v1.EngineHost.Head:output_type -> v1.RawHeader
+	2, // 11: v1.EngineHost.BuildBlock:output_type -> v1.RawBlock
+	5, // 12: v1.EngineHost.WriteBlock:output_type -> google.protobuf.Empty
+	7, // [7:13] is the sub-list for method output_type
+	1, // [1:7] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_consensus_external_proto_external_engine_proto_init() }
+func file_consensus_external_proto_external_engine_proto_init() {
+	if File_consensus_external_proto_external_engine_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_consensus_external_proto_external_engine_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RawHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_consensus_external_proto_external_engine_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RawHeaders); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_consensus_external_proto_external_engine_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RawBlock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_consensus_external_proto_external_engine_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Creator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_consensus_external_proto_external_engine_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildBlockReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_consensus_external_proto_external_engine_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_consensus_external_proto_external_engine_proto_goTypes,
+		DependencyIndexes: file_consensus_external_proto_external_engine_proto_depIdxs,
+		MessageInfos:      file_consensus_external_proto_external_engine_proto_msgTypes,
+	}.Build()
+	File_consensus_external_proto_external_engine_proto = out.File
+	file_consensus_external_proto_external_engine_proto_rawDesc = nil
+	file_consensus_external_proto_external_engine_proto_goTypes = nil
+	file_consensus_external_proto_external_engine_proto_depIdxs = nil
+}
@@ -0,0 +1,348 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: consensus/external/proto/external_engine.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// EngineClient is the client API for Engine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EngineClient interface {
+	// VerifyHeader verifies the header is sealed according to the engine
+	VerifyHeader(ctx context.Context, in *RawHeader, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ProcessHeaders notifies the engine of the verified headers written to the chain
+	ProcessHeaders(ctx context.Context, in *RawHeaders, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BlockCreator returns the address of the creator of the block
+	BlockCreator(ctx context.Context, in *RawHeader, opts ...grpc.CallOption) (*Creator, error)
+}
+
+type engineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEngineClient(cc grpc.ClientConnInterface) EngineClient {
+	return &engineClient{cc}
+}
+
+func (c *engineClient) VerifyHeader(ctx context.Context, in *RawHeader, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/v1.Engine/VerifyHeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) ProcessHeaders(ctx context.Context, in *RawHeaders, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/v1.Engine/ProcessHeaders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) BlockCreator(ctx context.Context, in *RawHeader, opts ...grpc.CallOption) (*Creator, error) {
+	out := new(Creator)
+	err := c.cc.Invoke(ctx, "/v1.Engine/BlockCreator", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EngineServer is the server API for Engine service.
+// All implementations must embed UnimplementedEngineServer
+// for forward compatibility
+type EngineServer interface {
+	// VerifyHeader verifies the header is sealed according to the engine
+	VerifyHeader(context.Context, *RawHeader) (*emptypb.Empty, error)
+	// ProcessHeaders notifies the engine of the verified headers written to the chain
+	ProcessHeaders(context.Context, *RawHeaders) (*emptypb.Empty, error)
+	// BlockCreator returns the address of the creator of the block
+	BlockCreator(context.Context, *RawHeader) (*Creator, error)
+	mustEmbedUnimplementedEngineServer()
+}
+
+// UnimplementedEngineServer must be embedded to have forward compatible implementations.
+type UnimplementedEngineServer struct {
+}
+
+func (UnimplementedEngineServer) VerifyHeader(context.Context, *RawHeader) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyHeader not implemented")
+}
+func (UnimplementedEngineServer) ProcessHeaders(context.Context, *RawHeaders) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessHeaders not implemented")
+}
+func (UnimplementedEngineServer) BlockCreator(context.Context, *RawHeader) (*Creator, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockCreator not implemented")
+}
+func (UnimplementedEngineServer) mustEmbedUnimplementedEngineServer() {}
+
+// UnsafeEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EngineServer will
+// result in compilation errors.
+type UnsafeEngineServer interface {
+	mustEmbedUnimplementedEngineServer()
+}
+
+func RegisterEngineServer(s grpc.ServiceRegistrar, srv EngineServer) {
+	s.RegisterService(&Engine_ServiceDesc, srv)
+}
+
+func _Engine_VerifyHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawHeader)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).VerifyHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Engine/VerifyHeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).VerifyHeader(ctx, req.(*RawHeader))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_ProcessHeaders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawHeaders)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).ProcessHeaders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Engine/ProcessHeaders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).ProcessHeaders(ctx, req.(*RawHeaders))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_BlockCreator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawHeader)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).BlockCreator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Engine/BlockCreator",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).BlockCreator(ctx, req.(*RawHeader))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Engine_ServiceDesc is the grpc.ServiceDesc for Engine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Engine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.Engine",
+	HandlerType: (*EngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VerifyHeader",
+			Handler:    _Engine_VerifyHeader_Handler,
+		},
+		{
+			MethodName: "ProcessHeaders",
+			Handler:    _Engine_ProcessHeaders_Handler,
+		},
+		{
+			MethodName: "BlockCreator",
+			Handler:    _Engine_BlockCreator_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "consensus/external/proto/external_engine.proto",
+}
+
+// EngineHostClient is the client API for EngineHost service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EngineHostClient interface {
+	// Head returns the header of the head of the chain
+	Head(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RawHeader, error)
+	// BuildBlock builds a block of the pending transactions on top of the head, without writing it
+	BuildBlock(ctx context.Context, in *BuildBlockReq, opts ...grpc.CallOption) (*RawBlock, error)
+	// WriteBlock verifies the block sealed by the engine and writes it to the chain
+	WriteBlock(ctx context.Context, in *RawBlock, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type engineHostClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEngineHostClient(cc grpc.ClientConnInterface) EngineHostClient {
+	return &engineHostClient{cc}
+}
+
+func (c *engineHostClient) Head(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RawHeader, error) {
+	out := new(RawHeader)
+	err := c.cc.Invoke(ctx, "/v1.EngineHost/Head", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineHostClient) BuildBlock(ctx context.Context, in *BuildBlockReq, opts ...grpc.CallOption) (*RawBlock, error) {
+	out := new(RawBlock)
+	err := c.cc.Invoke(ctx, "/v1.EngineHost/BuildBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineHostClient) WriteBlock(ctx context.Context, in *RawBlock, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/v1.EngineHost/WriteBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EngineHostServer is the server API for EngineHost service.
+// All implementations must embed UnimplementedEngineHostServer
+// for forward compatibility
+type EngineHostServer interface {
+	// Head returns the header of the head of the chain
+	Head(context.Context, *emptypb.Empty) (*RawHeader, error)
+	// BuildBlock builds a block of the pending transactions on top of the head, without writing it
+	BuildBlock(context.Context, *BuildBlockReq) (*RawBlock, error)
+	// WriteBlock verifies the block sealed by the engine and writes it to the chain
+	WriteBlock(context.Context, *RawBlock) (*emptypb.Empty, error)
+	mustEmbedUnimplementedEngineHostServer()
+}
+
+// UnimplementedEngineHostServer must be embedded to have forward compatible implementations.
+type UnimplementedEngineHostServer struct {
+}
+
+func (UnimplementedEngineHostServer) Head(context.Context, *emptypb.Empty) (*RawHeader, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Head not implemented")
+}
+func (UnimplementedEngineHostServer) BuildBlock(context.Context, *BuildBlockReq) (*RawBlock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildBlock not implemented")
+}
+func (UnimplementedEngineHostServer) WriteBlock(context.Context, *RawBlock) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteBlock not implemented")
+}
+func (UnimplementedEngineHostServer) mustEmbedUnimplementedEngineHostServer() {}
+
+// UnsafeEngineHostServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EngineHostServer will
+// result in compilation errors.
+type UnsafeEngineHostServer interface {
+	mustEmbedUnimplementedEngineHostServer()
+}
+
+func RegisterEngineHostServer(s grpc.ServiceRegistrar, srv EngineHostServer) {
+	s.RegisterService(&EngineHost_ServiceDesc, srv)
+}
+
+func _EngineHost_Head_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineHostServer).Head(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.EngineHost/Head",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineHostServer).Head(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EngineHost_BuildBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildBlockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineHostServer).BuildBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.EngineHost/BuildBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineHostServer).BuildBlock(ctx, req.(*BuildBlockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EngineHost_WriteBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawBlock)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineHostServer).WriteBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.EngineHost/WriteBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineHostServer).WriteBlock(ctx, req.(*RawBlock))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EngineHost_ServiceDesc is the grpc.ServiceDesc for EngineHost service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EngineHost_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.EngineHost",
+	HandlerType: (*EngineHostServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Head",
+			Handler:    _EngineHost_Head_Handler,
+		},
+		{
+			MethodName: "BuildBlock",
+			Handler:    _EngineHost_BuildBlock_Handler,
+		},
+		{
+			MethodName: "WriteBlock",
+			Handler:    _EngineHost_WriteBlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "consensus/external/proto/external_engine.proto",
+}
@@ -0,0 +1,338 @@
+package ibft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+const (
+	// baseRoundTimeout is the timeout of the first round of go-ibft, which the block time extends
+	baseRoundTimeout = 10 * time.Second
+
+	// maxRoundChangeBlocks is the number of block times a round change can last
+	// before an offline proposer noticeably slows down the chain
+	maxRoundChangeBlocks = 10
+
+	// minEpochDuration is the duration under which the validator set and the votes change too often
+	minEpochDuration = 10 * time.Minute
+)
+
+// CheckSeverity is the severity of a risky consensus configuration
+type CheckSeverity string
+
+const (
+	// CheckWarning is a configuration which works, but degrades the chain under some conditions
+	CheckWarning CheckSeverity = "warning"
+	// CheckError is a configuration which can halt the chain or break its safety
+	CheckError CheckSeverity = "error"
+)
+
+// CheckFinding is a risky combination of consensus parameters, along with the steps to remediate it
+type CheckFinding struct {
+	Check       string
+	Severity    CheckSeverity
+	Message     string
+	Remediation string
+}
+
+// CheckParams are the consensus parameters checked by CheckConfig
+type CheckParams struct {
+	EpochSize          uint64
+	QuorumSizeBlockNum uint64
+	Forks              fork.IBFTForks
+
+	// Height is the height of the validator set, 0 for the genesis one
+	Height     uint64
+	Validators validators.Validators
+
+	// BlockTime is the block time the validators are started with
+	BlockTime time.Duration
+	// RoundTimeout is the timeout of the first round, the go-ibft one if zero
+	RoundTimeout time.Duration
+	// FaultTolerance is the number of faulty validators the chain is meant to tolerate
+	FaultTolerance uint64
+}
+
+// NewCheckParams reads the consensus parameters of the IBFT engine config in the genesis
+func NewCheckParams(engineConfig map[string]interface{}) (*CheckParams, error) {
+	epochSize, err := getConfigUint64(engineConfig, KeyEpochSize, DefaultEpochSize)
+	if err != nil {
+		return nil, err
+	}
+
+	quorumSizeBlockNum, err := getConfigUint64(engineConfig, KeyQuorumSizeBlockNum, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	forks, err := fork.GetIBFTForks(engineConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	roundTimeout, err := RoundTimeoutParams(engineConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckParams{
+		EpochSize:          epochSize,
+		QuorumSizeBlockNum: quorumSizeBlockNum,
+		Forks:              forks,
+		RoundTimeout:       roundTimeout.Base,
+		FaultTolerance:     1,
+	}, nil
+}
+
+// CheckConfig analyzes the consensus parameters for the combinations which put the chain at risk
+func CheckConfig(p *CheckParams) []CheckFinding {
+	findings := make([]CheckFinding, 0)
+
+	for _, check := range []func(*CheckParams) []CheckFinding{
+		checkValidatorSetSize,
+		checkQuorumSize,
+		checkEpochSize,
+		checkBlockTime,
+		checkForks,
+	} {
+		findings = append(findings, check(p)...)
+	}
+
+	return findings
+}
+
+// requiredValidators returns the number of validators tolerating the configured faulty validators
+func (p *CheckParams) requiredValidators() uint64 {
+	return 3*p.FaultTolerance + 1
+}
+
+// Fork returns the fork of the checked validator set, nil if no fork covers its height
+func (p *CheckParams) Fork() *fork.IBFTFork {
+	for idx := len(p.Forks) - 1; idx >= 0; idx-- {
+		if f := p.Forks[idx]; f.From.Value <= p.Height && (f.To == nil || p.Height <= f.To.Value) {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func checkValidatorSetSize(p *CheckParams) []CheckFinding {
+	if p.Validators == nil || p.Validators.Len() == 0 {
+		return []CheckFinding{{
+			Check:       "validator-set",
+			Severity:    CheckError,
+			Message:     fmt.Sprintf("there are no validators at block %d, no block can be sealed", p.Height),
+			Remediation: "regenerate the genesis with --ibft-validator or --ibft-validators-prefix-path",
+		}}
+	}
+
+	size, required := uint64(p.Validators.Len()), p.requiredValidators()
+	if size >= required {
+		return nil
+	}
+
+	remediation := fmt.Sprintf(
+		"propose %d more validators with `ibft propose --vote auth`, each needs a quorum of votes",
+		required-size,
+	)
+
+	if f := p.Fork(); f != nil && f.Type == fork.PoS {
+		remediation = fmt.Sprintf("stake %d more validators in the staking contract", required-size)
+	}
+
+	return []CheckFinding{{
+		Check:    "validator-set",
+		Severity: CheckError,
+		Message: fmt.Sprintf(
+			"%d validators tolerate %d faulty validators, %d are required to tolerate %d",
+			size,
+			CalcMaxFaultyNodes(p.Validators),
+			required,
+			p.FaultTolerance,
+		),
+		Remediation: remediation,
+	}}
+}
+
+func checkQuorumSize(p *CheckParams) []CheckFinding {
+	if p.Validators == nil || p.Height >= p.QuorumSizeBlockNum {
+		return nil
+	}
+
+	legacy, optimal := LegacyQuorumSize(p.Validators), OptimalQuorumSize(p.Validators)
+	if legacy >= optimal {
+		return nil
+	}
+
+	return []CheckFinding{{
+		Check:    "quorum-size",
+		Severity: CheckError,
+		Message: fmt.Sprintf(
+			"the legacy quorum of %d out of %d validators is used until block %d, "+
+				"two quorums can be reached for conflicting blocks",
+			legacy,
+			p.Validators.Len(),
+			p.QuorumSizeBlockNum,
+		),
+		Remediation: fmt.Sprintf(
+			"switch to the optimal quorum of %d validators at an upcoming block with `ibft quorum --from`",
+			optimal,
+		),
+	}}
+}
+
+func checkEpochSize(p *CheckParams) []CheckFinding {
+	if p.BlockTime == 0 {
+		return nil
+	}
+
+	duration := time.Duration(p.EpochSize) * p.BlockTime
+	if duration >= minEpochDuration {
+		return nil
+	}
+
+	return []CheckFinding{{
+		Check:    "epoch-size",
+		Severity: CheckWarning,
+		Message: fmt.Sprintf(
+			"epochs of %d blocks last %s with a block time of %s, "+
+				"the pending votes are dropped and the staked validators are updated every epoch",
+			p.EpochSize,
+			duration,
+			p.BlockTime,
+		),
+		Remediation: fmt.Sprintf(
+			"regenerate the genesis with --epoch-size %d or more",
+			uint64(minEpochDuration/p.BlockTime),
+		),
+	}}
+}
+
+func checkBlockTime(p *CheckParams) []CheckFinding {
+	if p.BlockTime == 0 {
+		return nil
+	}
+
+	base := p.RoundTimeout
+	if base == 0 {
+		base = baseRoundTimeout
+	}
+
+	roundTimeout := base + p.BlockTime
+	if roundTimeout <= maxRoundChangeBlocks*p.BlockTime {
+		return nil
+	}
+
+	// the shortest block time in seconds whose round changes last at most maxRoundChangeBlocks block times
+	minBlockTime := (base/(maxRoundChangeBlocks-1) + time.Second - 1) / time.Second
+
+	return []CheckFinding{{
+		Check:    "block-time",
+		Severity: CheckWarning,
+		Message: fmt.Sprintf(
+			"a block time of %s is short against the round timeout of %s, "+
+				"every block proposed by an offline validator takes %d block times",
+			p.BlockTime,
+			roundTimeout,
+			roundTimeout/p.BlockTime,
+		),
+		Remediation: fmt.Sprintf(
+			"start the validators with --block-time %d or more, or shorten the round timeout, "+
+				"and announce the validators going offline with `ibft maintenance` so they aren't picked as proposers",
+			minBlockTime,
+		),
+	}}
+}
+
+func checkForks(p *CheckParams) []CheckFinding {
+	findings := make([]CheckFinding, 0)
+
+	for _, f := range p.Forks {
+		if f.Type != fork.PoS {
+			continue
+		}
+
+		from := f.From.Value
+
+		if f.Deployment != nil && f.Deployment.Value >= from && from > 0 {
+			findings = append(findings, CheckFinding{
+				Check:    "fork",
+				Severity: CheckError,
+				Message: fmt.Sprintf(
+					"the staking contract of the PoS fork from block %d is deployed at block %d, "+
+						"after the fork begins",
+					from,
+					f.Deployment.Value,
+				),
+				Remediation: "deploy the staking contract before the fork begins, using `ibft switch --deployment`",
+			})
+		}
+
+		if from%p.EpochSize != 0 {
+			findings = append(findings, CheckFinding{
+				Check:    "fork",
+				Severity: CheckWarning,
+				Message: fmt.Sprintf(
+					"the PoS fork from block %d doesn't begin an epoch of %d blocks, "+
+						"its first validator set is only updated at block %d",
+					from,
+					p.EpochSize,
+					(from/p.EpochSize+1)*p.EpochSize,
+				),
+				Remediation: fmt.Sprintf(
+					"begin the fork at block %d, a multiple of the epoch size",
+					(from/p.EpochSize+1)*p.EpochSize,
+				),
+			})
+		}
+
+		findings = append(findings, checkValidatorCountBounds(p, f)...)
+	}
+
+	return findings
+}
+
+func checkValidatorCountBounds(p *CheckParams, f *fork.IBFTFork) []CheckFinding {
+	required := p.requiredValidators()
+
+	if f.MaxValidatorCount != nil && f.MaxValidatorCount.Value < required {
+		return []CheckFinding{{
+			Check:    "fork",
+			Severity: CheckError,
+			Message: fmt.Sprintf(
+				"the PoS fork from block %d allows at most %d validators, %d are required to tolerate %d faulty ones",
+				f.From.Value,
+				f.MaxValidatorCount.Value,
+				required,
+				p.FaultTolerance,
+			),
+			Remediation: fmt.Sprintf("raise the maxValidatorCount of the fork to %d or more", required),
+		}}
+	}
+
+	minCount := uint64(1)
+	if f.MinValidatorCount != nil {
+		minCount = f.MinValidatorCount.Value
+	}
+
+	if minCount >= required {
+		return nil
+	}
+
+	return []CheckFinding{{
+		Check:    "fork",
+		Severity: CheckWarning,
+		Message: fmt.Sprintf(
+			"the PoS fork from block %d lets the validators unstake down to %d validators, "+
+				"which tolerate fewer than %d faulty ones",
+			f.From.Value,
+			minCount,
+			p.FaultTolerance,
+		),
+		Remediation: fmt.Sprintf("raise the minValidatorCount of the fork to %d or more", required),
+	}}
+}
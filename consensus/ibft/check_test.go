@@ -0,0 +1,175 @@
+package ibft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+func newCheckValidators(count int) validators.Validators {
+	set := validators.NewECDSAValidatorSet()
+
+	for i := 0; i < count; i++ {
+		_ = set.Add(validators.NewECDSAValidator(types.BytesToAddress([]byte{byte(i + 1)})))
+	}
+
+	return set
+}
+
+func TestNewCheckParams(t *testing.T) {
+	t.Parallel()
+
+	params, err := NewCheckParams(map[string]interface{}{
+		"type":                "PoA",
+		KeyEpochSize:          float64(10),
+		KeyQuorumSizeBlockNum: float64(20),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(10), params.EpochSize)
+	assert.Equal(t, uint64(20), params.QuorumSizeBlockNum)
+	assert.Len(t, params.Forks, 1)
+	assert.Equal(t, uint64(1), params.FaultTolerance)
+
+	_, err = NewCheckParams(map[string]interface{}{"type": "PoA", KeyEpochSize: "10"})
+	assert.Error(t, err)
+}
+
+func TestCheckConfig(t *testing.T) {
+	t.Parallel()
+
+	poaForks := fork.IBFTForks{{Type: fork.PoA, ValidatorType: validators.ECDSAValidatorType}}
+
+	tests := []struct {
+		name     string
+		params   *CheckParams
+		expected map[string]CheckSeverity
+	}{
+		{
+			name: "safe config",
+			params: &CheckParams{
+				EpochSize:      DefaultEpochSize,
+				Forks:          poaForks,
+				Validators:     newCheckValidators(4),
+				BlockTime:      2 * time.Second,
+				FaultTolerance: 1,
+			},
+			expected: map[string]CheckSeverity{},
+		},
+		{
+			name: "too few validators for the fault tolerance",
+			params: &CheckParams{
+				EpochSize:      DefaultEpochSize,
+				Forks:          poaForks,
+				Validators:     newCheckValidators(5),
+				BlockTime:      2 * time.Second,
+				FaultTolerance: 2,
+			},
+			expected: map[string]CheckSeverity{"validator-set": CheckError},
+		},
+		{
+			name: "legacy quorum",
+			params: &CheckParams{
+				EpochSize:          DefaultEpochSize,
+				QuorumSizeBlockNum: 100,
+				Forks:              poaForks,
+				Validators:         newCheckValidators(5),
+				BlockTime:          2 * time.Second,
+				FaultTolerance:     1,
+			},
+			expected: map[string]CheckSeverity{"quorum-size": CheckError},
+		},
+		{
+			name: "short epoch and block time",
+			params: &CheckParams{
+				EpochSize:      10,
+				Forks:          poaForks,
+				Validators:     newCheckValidators(4),
+				BlockTime:      time.Second,
+				FaultTolerance: 1,
+			},
+			expected: map[string]CheckSeverity{"epoch-size": CheckWarning, "block-time": CheckWarning},
+		},
+		{
+			name: "misaligned PoS fork",
+			params: &CheckParams{
+				EpochSize: 100,
+				Forks: fork.IBFTForks{
+					{Type: fork.PoA, ValidatorType: validators.ECDSAValidatorType, To: &common.JSONNumber{Value: 149}},
+					{
+						Type:              fork.PoS,
+						ValidatorType:     validators.ECDSAValidatorType,
+						Deployment:        &common.JSONNumber{Value: 160},
+						From:              common.JSONNumber{Value: 150},
+						MinValidatorCount: &common.JSONNumber{Value: 4},
+					},
+				},
+				Validators:     newCheckValidators(4),
+				BlockTime:      10 * time.Second,
+				FaultTolerance: 1,
+			},
+			expected: map[string]CheckSeverity{"fork": CheckError},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			severities := map[string]CheckSeverity{}
+
+			for _, finding := range CheckConfig(test.params) {
+				assert.NotEmpty(t, finding.Message)
+				assert.NotEmpty(t, finding.Remediation)
+
+				// an error prevails over the warnings of the same check
+				if severities[finding.Check] != CheckError {
+					severities[finding.Check] = finding.Severity
+				}
+			}
+
+			assert.Equal(t, test.expected, severities)
+		})
+	}
+}
+
+func TestCheckConfig_PoSValidatorCountBounds(t *testing.T) {
+	t.Parallel()
+
+	params := &CheckParams{
+		EpochSize: 100,
+		Forks: fork.IBFTForks{{
+			Type:          fork.PoS,
+			ValidatorType: validators.ECDSAValidatorType,
+		}},
+		Validators:     newCheckValidators(3),
+		BlockTime:      10 * time.Second,
+		FaultTolerance: 1,
+	}
+
+	findings := CheckConfig(params)
+	require.Len(t, findings, 2)
+
+	// the missing validators are staked in PoS
+	assert.Equal(t, "validator-set", findings[0].Check)
+	assert.Contains(t, findings[0].Remediation, "stake 1 more")
+
+	// the default min validator count lets the set shrink to a single validator
+	assert.Equal(t, "fork", findings[1].Check)
+	assert.Equal(t, CheckWarning, findings[1].Severity)
+
+	params.Forks[0].MaxValidatorCount = &common.JSONNumber{Value: 3}
+
+	findings = CheckConfig(params)
+	require.Len(t, findings, 2)
+	assert.Equal(t, CheckError, findings[1].Severity)
+}
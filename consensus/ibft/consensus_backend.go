@@ -3,11 +3,11 @@ package ibft
 import (
 	"fmt"
 	"math"
-	"time"
 
 	"github.com/0xPolygon/go-ibft/messages"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
 )
@@ -72,6 +72,11 @@ func (i *backendIBFT) InsertBlock(
 		return
 	}
 
+	// the committed seals of a quorum of validators finalize the block
+	if err := i.blockchain.SetFinalized(newBlock.Header); err != nil {
+		i.logger.Error("cannot finalize block", "err", err)
+	}
+
 	i.updateMetrics(newBlock)
 
 	i.logger.Info(
@@ -153,7 +158,7 @@ func (i *backendIBFT) buildBlock(parent *types.Header) (*types.Block, error) {
 	}
 
 	// set the timestamp
-	header.Timestamp = uint64(time.Now().Unix())
+	header.Timestamp = uint64(i.clock.Now().Unix())
 
 	parentCommittedSeals, err := i.extractParentCommittedSeals(parent)
 	if err != nil {
@@ -169,10 +174,14 @@ func (i *backendIBFT) buildBlock(parent *types.Header) (*types.Block, error) {
 
 	txs := i.writeTransactions(gasLimit, header.Number, transition)
 
-	if err := i.PreCommitState(header, transition); err != nil {
+	if err := i.forkManager.GetHooks(header.Number).PreCommitState(header, transition); err != nil {
 		return nil, err
 	}
 
+	if accessList := transition.AccessList(); accessList != nil {
+		header.ExtraData = signer.PackAccessListHashIntoExtra(header.ExtraData, accessList.Hash())
+	}
+
 	_, root := transition.Commit()
 	header.StateRoot = root
 	header.GasUsed = transition.TotalGas()
@@ -207,6 +216,8 @@ const (
 	success status = iota
 	fail
 	skip
+	// deferred is the status of the transactions which don't fit the gas left, left for the next blocks
+	deferred
 )
 
 type txExeResult struct {
@@ -217,6 +228,8 @@ type txExeResult struct {
 type transitionInterface interface {
 	Write(txn *types.Transaction) error
 	WriteFailedReceipt(txn *types.Transaction) error
+	MatchKnownAccounts(txn *types.Transaction) bool
+	TotalGas() uint64
 }
 
 func (i *backendIBFT) writeTransactions(
@@ -231,60 +244,47 @@ func (i *backendIBFT) writeTransactions(
 	}
 
 	var (
-		blockTimer = time.NewTimer(i.blockTime)
-
-		successful = 0
-		failed     = 0
-		skipped    = 0
+		// the block is proposed once the block time elapses, the packing stops before it
+		blockTimer    = i.clock.NewTimer(i.blockTime)
+		deadlineTimer = i.clock.NewTimer(packingDeadline(i.blockTime))
 	)
 
-	defer func() {
-		i.logger.Info(
-			"executed txs",
-			"successful", successful,
-			"failed", failed,
-			"skipped", skipped,
-			"remaining", i.txpool.Length(),
-		)
-	}()
-
-	i.txpool.Prepare()
-
-write:
-	for {
-		select {
-		case <-blockTimer.C:
-			return
-		default:
-			// execute transactions one by one
-			result, ok := i.writeTransaction(
-				i.txpool.Peek(),
-				transition,
-				gasLimit,
-			)
+	packer := &packer{
+		txpool:      i.txpool,
+		transition:  transition,
+		deadline:    deadlineTimer.C(),
+		blockNumber: blockNumber,
+		gasLimit:    gasLimit,
+		// the encoded transactions are limited in size, 0 if they aren't
+		maxSize: i.config.Params.MaxBlockSizeAt(blockNumber),
+		write: func(tx *types.Transaction) (*txExeResult, bool) {
+			return i.writeTransaction(tx, transition, gasLimit)
+		},
+	}
 
-			if !ok {
-				break write
-			}
-
-			tx := result.tx
-
-			switch result.status {
-			case success:
-				executed = append(executed, tx)
-				successful++
-			case fail:
-				failed++
-			case skip:
-				skipped++
-			}
-		}
+	stop := packer.pack()
+	deadlineTimer.Stop()
+
+	gasLeft := packer.gasLeft()
+	if gasLimit != 0 {
+		telemetry.ConsensusBlockGasLeft.WithLabelValues(stop).Observe(float64(gasLeft) / float64(gasLimit))
 	}
 
+	i.logger.Info(
+		"executed txs",
+		"successful", packer.successful,
+		"failed", packer.failed,
+		"skipped", packer.skipped,
+		"deferred", packer.deferred,
+		"gas_left", gasLeft,
+		"stop", stop,
+		"remaining", i.txpool.Length(),
+	)
+
 	//	wait for the timer to expire
-	<-blockTimer.C
+	<-blockTimer.C()
 
-	return
+	return packer.executed
 }
 
 func (i *backendIBFT) writeTransaction(
@@ -292,10 +292,6 @@ func (i *backendIBFT) writeTransaction(
 	transition transitionInterface,
 	gasLimit uint64,
 ) (*txExeResult, bool) {
-	if tx == nil {
-		return nil, false
-	}
-
 	if tx.ExceedsBlockGasLimit(gasLimit) {
 		i.txpool.Drop(tx)
 
@@ -314,8 +310,8 @@ func (i *backendIBFT) writeTransaction(
 
 	if err := transition.Write(tx); err != nil {
 		if _, ok := err.(*state.GasLimitReachedTransitionApplicationError); ok { //nolint:errorlint
-			// stop processing
-			return nil, false
+			// the account is skipped until the next block
+			return &txExeResult{tx, deferred}, true
 		} else if appErr, ok := err.(*state.TransitionApplicationError); ok && appErr.IsRecoverable { //nolint:errorlint
 			i.txpool.Demote(tx)
 
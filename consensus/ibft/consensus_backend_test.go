@@ -0,0 +1,154 @@
+package ibft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/hook"
+	"github.com/0xPolygon/polygon-edge/helper/clock"
+	"github.com/0xPolygon/polygon-edge/txpool"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// mockTxPool serves the given number of transactions, and advances the clock
+// by the given time on every transaction
+type mockTxPool struct {
+	txPoolInterface
+
+	clock   *clock.Simulated
+	perTx   time.Duration
+	txs     uint64
+	nonce   uint64
+	written []uint64
+}
+
+func (m *mockTxPool) Prepare() {}
+
+func (m *mockTxPool) Length() uint64 {
+	return 0
+}
+
+func (m *mockTxPool) Peek() *types.Transaction {
+	if m.nonce == m.txs {
+		return nil
+	}
+
+	m.clock.Advance(m.perTx)
+
+	return &types.Transaction{Nonce: m.nonce, Gas: 21000}
+}
+
+func (m *mockTxPool) Lane(*types.Transaction) txpool.Lane {
+	return txpool.LaneRegular
+}
+
+func (m *mockTxPool) LaneGas(_ txpool.Lane, gasLimit uint64) uint64 {
+	return gasLimit
+}
+
+func (m *mockTxPool) Pop(tx *types.Transaction) {
+	m.written = append(m.written, tx.Nonce)
+	m.nonce++
+}
+
+type mockTransition struct{}
+
+func (mockTransition) Write(*types.Transaction) error {
+	return nil
+}
+
+func (mockTransition) WriteFailedReceipt(*types.Transaction) error {
+	return nil
+}
+
+func (mockTransition) MatchKnownAccounts(*types.Transaction) bool {
+	return true
+}
+
+func (mockTransition) TotalGas() uint64 {
+	return 0
+}
+
+func TestWriteTransactions_BlockTime(t *testing.T) {
+	t.Parallel()
+
+	newBackend := func(pool *mockTxPool) *backendIBFT {
+		return &backendIBFT{
+			logger:       hclog.NewNullLogger(),
+			config:       &consensus.Config{Params: &chain.Params{}},
+			txpool:       pool,
+			clock:        pool.clock,
+			blockTime:    time.Second,
+			currentHooks: &hook.Hooks{},
+		}
+	}
+
+	t.Run("the transactions are written until the packing deadline", func(t *testing.T) {
+		t.Parallel()
+
+		simulated := clock.NewSimulated(time.Unix(0, 0))
+		pool := &mockTxPool{clock: simulated, perTx: 300 * time.Millisecond, txs: 10}
+
+		var executed []*types.Transaction
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			executed = newBackend(pool).writeTransactions(1_000_000, 1, mockTransition{})
+		}()
+
+		// the packing stops at 3/4 of the block time, the builder then waits for the block timer
+		assert.Eventually(t, func() bool {
+			return simulated.Pending() == 1
+		}, 5*time.Second, time.Millisecond)
+
+		simulated.Advance(100 * time.Millisecond)
+		<-done
+
+		// the transaction peeked as the deadline elapses is still written
+		assert.Len(t, executed, 3)
+		assert.Equal(t, []uint64{0, 1, 2}, pool.written)
+		assert.Equal(t, time.Unix(1, 0), simulated.Now())
+	})
+
+	t.Run("the block time elapses once the pool is empty", func(t *testing.T) {
+		t.Parallel()
+
+		simulated := clock.NewSimulated(time.Unix(0, 0))
+		pool := &mockTxPool{clock: simulated, perTx: 300 * time.Millisecond, txs: 2}
+
+		var executed []*types.Transaction
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			executed = newBackend(pool).writeTransactions(1_000_000, 1, mockTransition{})
+		}()
+
+		// the builder waits for the block timer
+		assert.Eventually(t, func() bool {
+			return simulated.Pending() == 1
+		}, 5*time.Second, time.Millisecond)
+
+		select {
+		case <-done:
+			t.Fatal("the block was built before the block time")
+		default:
+		}
+
+		simulated.Advance(400 * time.Millisecond)
+		<-done
+
+		assert.Len(t, executed, 2)
+		assert.Equal(t, time.Unix(1, 0), simulated.Now())
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/validators"
 )
 
@@ -33,6 +35,8 @@ type IBFTFork struct {
 	// PoS
 	MaxValidatorCount *common.JSONNumber `json:"maxValidatorCount,omitempty"`
 	MinValidatorCount *common.JSONNumber `json:"minValidatorCount,omitempty"`
+	// ValidatorContract is the contract the validators are read from, the staking contract if not set
+	ValidatorContract *types.Address `json:"validatorContract,omitempty"`
 }
 
 func (f *IBFTFork) UnmarshalJSON(data []byte) error {
@@ -45,6 +49,7 @@ func (f *IBFTFork) UnmarshalJSON(data []byte) error {
 		Validators        interface{}               `json:"validators,omitempty"`
 		MaxValidatorCount *common.JSONNumber        `json:"maxValidatorCount,omitempty"`
 		MinValidatorCount *common.JSONNumber        `json:"minValidatorCount,omitempty"`
+		ValidatorContract *types.Address            `json:"validatorContract,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -57,6 +62,7 @@ func (f *IBFTFork) UnmarshalJSON(data []byte) error {
 	f.To = raw.To
 	f.MaxValidatorCount = raw.MaxValidatorCount
 	f.MinValidatorCount = raw.MinValidatorCount
+	f.ValidatorContract = raw.ValidatorContract
 
 	f.ValidatorType = validators.ECDSAValidatorType
 	if raw.ValidatorType != nil {
@@ -143,6 +149,26 @@ func (fs *IBFTForks) getFork(height uint64) *IBFTFork {
 	return nil
 }
 
+// ValidatorTypeAt returns the type of the validators of the fork in which the given height is,
+// ECDSA if no fork covers it
+func (fs *IBFTForks) ValidatorTypeAt(height uint64) validators.ValidatorType {
+	if fork := fs.getFork(height); fork != nil {
+		return fork.ValidatorType
+	}
+
+	return validators.ECDSAValidatorType
+}
+
+// ValidatorContractAt returns the contract the validators are read from in the fork in which the given height is,
+// the staking contract if the fork doesn't set one
+func (fs *IBFTForks) ValidatorContractAt(height uint64) types.Address {
+	if fork := fs.getFork(height); fork != nil && fork.ValidatorContract != nil {
+		return *fork.ValidatorContract
+	}
+
+	return staking.AddrStakingContract
+}
+
 // filterByType returns new list of IBFTFork whose type matches with the given type
 func (fs *IBFTForks) filterByType(ibftType IBFTType) IBFTForks {
 	filteredForks := make(IBFTForks, 0)
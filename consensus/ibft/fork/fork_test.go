@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	testHelper "github.com/0xPolygon/polygon-edge/helper/tests"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -16,6 +17,8 @@ import (
 func TestIBFTForkUnmarshalJSON(t *testing.T) {
 	t.Parallel()
 
+	validatorContract := types.StringToAddress("2000")
+
 	tests := []struct {
 		name     string
 		data     string
@@ -90,6 +93,20 @@ func TestIBFTForkUnmarshalJSON(t *testing.T) {
 				MinValidatorCount: nil,
 			},
 		},
+		{
+			name: "should parse the validator contract",
+			data: fmt.Sprintf(`{
+				"type": "%s",
+				"from": %d,
+				"validatorContract": "%s"
+			}`, PoS, 10, types.StringToAddress("2000")),
+			expected: &IBFTFork{
+				Type:              PoS,
+				ValidatorType:     validators.ECDSAValidatorType,
+				From:              common.JSONNumber{Value: 10},
+				ValidatorContract: &validatorContract,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -288,6 +305,34 @@ func TestIBFTForks_getFork(t *testing.T) {
 	}
 }
 
+func TestIBFTForks_ValidatorContractAt(t *testing.T) {
+	t.Parallel()
+
+	validatorContract := types.StringToAddress("2000")
+
+	forks := IBFTForks{
+		{
+			Type: PoA,
+			From: common.JSONNumber{Value: 0},
+			To:   &common.JSONNumber{Value: 10},
+		},
+		{
+			Type: PoS,
+			From: common.JSONNumber{Value: 11},
+			To:   &common.JSONNumber{Value: 20},
+		},
+		{
+			Type:              PoS,
+			From:              common.JSONNumber{Value: 21},
+			ValidatorContract: &validatorContract,
+		},
+	}
+
+	assert.Equal(t, staking.AddrStakingContract, forks.ValidatorContractAt(0))
+	assert.Equal(t, staking.AddrStakingContract, forks.ValidatorContractAt(20))
+	assert.Equal(t, validatorContract, forks.ValidatorContractAt(21))
+}
+
 func TestIBFTForks_filterByType(t *testing.T) {
 	t.Parallel()
 
@@ -286,6 +286,7 @@ func (m *ForkManager) initializeValidatorStore(setType store.SourceType) error {
 			m.blockchain,
 			m.executor,
 			m.GetSigner,
+			m.forks.ValidatorContractAt,
 		)
 	}
 
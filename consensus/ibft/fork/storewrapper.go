@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/validators"
 	"github.com/0xPolygon/polygon-edge/validators/store"
 	"github.com/0xPolygon/polygon-edge/validators/store/contract"
@@ -91,7 +92,8 @@ func NewSnapshotValidatorStoreWrapper(
 // in order to add Close and GetValidators
 type ContractValidatorStoreWrapper struct {
 	*contract.ContractValidatorStore
-	getSigner func(uint64) (signer.Signer, error)
+	getSigner   func(uint64) (signer.Signer, error)
+	getContract func(uint64) types.Address
 }
 
 // NewContractValidatorStoreWrapper creates *ContractValidatorStoreWrapper
@@ -100,6 +102,7 @@ func NewContractValidatorStoreWrapper(
 	blockchain store.HeaderGetter,
 	executor contract.Executor,
 	getSigner func(uint64) (signer.Signer, error),
+	getContract func(uint64) types.Address,
 ) (*ContractValidatorStoreWrapper, error) {
 	contractStore, err := contract.NewContractValidatorStore(
 		logger,
@@ -115,6 +118,7 @@ func NewContractValidatorStoreWrapper(
 	return &ContractValidatorStoreWrapper{
 		ContractValidatorStore: contractStore,
 		getSigner:              getSigner,
+		getContract:            getContract,
 	}, nil
 }
 
@@ -134,6 +138,7 @@ func (w *ContractValidatorStoreWrapper) GetValidators(
 
 	return w.GetValidatorsByHeight(
 		signer.Type(),
+		w.getContract(height),
 		calculateContractStoreFetchingHeight(
 			height,
 			epochSize,
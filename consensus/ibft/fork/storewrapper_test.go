@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
 	testHelper "github.com/0xPolygon/polygon-edge/helper/tests"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -264,6 +265,11 @@ func (m *MockExecutor) BeginTxn(hash types.Hash, header *types.Header, addr type
 	return m.BeginTxnFunc(hash, header, addr)
 }
 
+// stakingContract returns the staking contract as the contract the validators are read from
+func stakingContract(uint64) types.Address {
+	return staking.AddrStakingContract
+}
+
 func TestNewContractValidatorStoreWrapper(t *testing.T) {
 	t.Parallel()
 
@@ -274,6 +280,7 @@ func TestNewContractValidatorStoreWrapper(t *testing.T) {
 		func(u uint64) (signer.Signer, error) {
 			return nil, nil
 		},
+		stakingContract,
 	)
 
 	assert.NoError(t, err)
@@ -289,6 +296,7 @@ func TestNewContractValidatorStoreWrapperClose(t *testing.T) {
 		func(u uint64) (signer.Signer, error) {
 			return nil, nil
 		},
+		stakingContract,
 	)
 
 	assert.NoError(t, err)
@@ -308,6 +316,7 @@ func TestNewContractValidatorStoreWrapperGetValidators(t *testing.T) {
 			func(u uint64) (signer.Signer, error) {
 				return nil, errTest
 			},
+			stakingContract,
 		)
 
 		assert.NoError(t, err)
@@ -334,6 +343,7 @@ func TestNewContractValidatorStoreWrapperGetValidators(t *testing.T) {
 					nil,
 				), nil
 			},
+			stakingContract,
 		)
 
 		assert.NoError(t, err)
@@ -3,6 +3,8 @@ package ibft
 import (
 	"errors"
 	"fmt"
+	"math/rand"
+	"path/filepath"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
@@ -10,11 +12,13 @@ import (
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/helper/clock"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/syncer"
+	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/validators"
 	"github.com/armon/go-metrics"
@@ -27,6 +31,9 @@ const (
 	IbftKeyName      = "validator.key"
 	KeyEpochSize     = "epochSize"
 
+	// KeyQuorumSizeBlockNum is the block from which the optimal quorum size is used
+	KeyQuorumSizeBlockNum = "quorumSizeBlockNum"
+
 	ibftProto = "/ibft/0.2"
 )
 
@@ -37,15 +44,22 @@ var (
 	ErrInvalidSha3Uncles            = errors.New("invalid sha3 uncles")
 	ErrWrongDifficulty              = errors.New("wrong difficulty")
 	ErrParentCommittedSealsNotFound = errors.New("parent committed seals not found")
+	ErrInvalidAccessListHash        = errors.New("invalid access list hash")
+	ErrCheckpointNotEpochEnd        = errors.New("checkpoint must be the last block of an epoch")
+	ErrCheckpointNotSupported       = errors.New("checkpoint is not supported with the PoS validators")
 )
 
 type txPoolInterface interface {
+	AddTx(tx *types.Transaction) error
+	GetNonce(addr types.Address) uint64
 	Prepare()
 	Length() uint64
 	Peek() *types.Transaction
 	Pop(tx *types.Transaction)
 	Drop(tx *types.Transaction)
 	Demote(tx *types.Transaction)
+	Lane(tx *types.Transaction) txpool.Lane
+	LaneGas(lane txpool.Lane, gasLimit uint64) uint64
 	ResetWithHeaders(headers ...*types.Header)
 	SetSealing(bool)
 }
@@ -69,6 +83,7 @@ type backendIBFT struct {
 	network        *network.Server        // Reference to the networking layer
 	executor       *state.Executor        // Reference to the state executor
 	txpool         txPoolInterface        // Reference to the transaction pool
+	clock          clock.Clock            // Reference to the clock
 	syncer         syncer.Syncer          // Reference to the sync protocol
 	secretsManager secrets.SecretsManager // Reference to the secret manager
 	Grpc           *grpc.Server           // Reference to the gRPC manager
@@ -76,16 +91,23 @@ type backendIBFT struct {
 	transport      transport              // Reference to the transport protocol
 
 	// Dynamic References
-	forkManager       forkManagerInterface  // Manager to hold IBFT Forks
-	currentSigner     signer.Signer         // Signer at current sequence
-	currentValidators validators.Validators // signer at current sequence
-	currentHooks      fork.HooksInterface   // Hooks at current sequence
+	forkManager        forkManagerInterface   // Manager to hold IBFT Forks
+	currentSigner      signer.Signer          // Signer at current sequence
+	currentValidators  validators.Validators  // signer at current sequence
+	currentHooks       fork.HooksInterface    // Hooks at current sequence
+	currentMaintenance map[types.Address]bool // Validators in maintenance at current sequence
+	validatorPeers     *validatorPeers        // Network peers of the current validators
+	signGuard          *signGuard             // Guard against signing twice for the same state
 
 	// Configurations
 	config             *consensus.Config // Consensus configuration
 	epochSize          uint64
 	quorumSizeBlockNum uint64
 	blockTime          time.Duration // Minimum block generation time in seconds
+	roundTimeout       *RoundTimeout // Curve of the round timeouts
+	strictSignState    bool          // Refuse to start without the up to date last sign state
+
+	checkpoint *syncer.CheckpointConfig // Trusted checkpoint the chain starts from, nil to start from the genesis
 
 	// Channels
 	closeCh chan struct{} // Channel for closing
@@ -93,30 +115,14 @@ type backendIBFT struct {
 
 // Factory implements the base consensus Factory method
 func Factory(params *consensus.Params) (consensus.Consensus, error) {
-	// defaults for user set fields in genesis
-	var (
-		epochSize          = uint64(DefaultEpochSize)
-		quorumSizeBlockNum = uint64(0)
-	)
-
-	if definedEpochSize, ok := params.Config.Config[KeyEpochSize]; ok {
-		// Epoch size is defined, use the passed in one
-		readSize, ok := definedEpochSize.(float64)
-		if !ok {
-			return nil, errors.New("invalid type assertion")
-		}
-
-		epochSize = uint64(readSize)
+	epochSize, quorumSizeBlockNum, err := EpochParams(params.Config.Config)
+	if err != nil {
+		return nil, err
 	}
 
-	if rawBlockNum, ok := params.Config.Config["quorumSizeBlockNum"]; ok {
-		// Block number specified for quorum size switch
-		readBlockNum, ok := rawBlockNum.(float64)
-		if !ok {
-			return nil, errors.New("invalid type assertion")
-		}
-
-		quorumSizeBlockNum = uint64(readBlockNum)
+	roundTimeout, err := RoundTimeoutParams(params.Config.Config)
+	if err != nil {
+		return nil, err
 	}
 
 	logger := params.Logger.Named("ibft")
@@ -137,37 +143,83 @@ func Factory(params *consensus.Params) (consensus.Consensus, error) {
 
 	p := &backendIBFT{
 		// References
-		logger:     logger,
-		blockchain: params.Blockchain,
-		network:    params.Network,
-		executor:   params.Executor,
-		txpool:     params.TxPool,
-		syncer: syncer.NewSyncer(
-			params.Logger,
-			params.Network,
-			params.Blockchain,
-			time.Duration(params.BlockTime)*3*time.Second,
-		),
+		logger:         logger,
+		blockchain:     params.Blockchain,
+		network:        params.Network,
+		executor:       params.Executor,
+		txpool:         params.TxPool,
+		clock:          clock.OrSystem(params.Clock),
 		secretsManager: params.SecretsManager,
 		Grpc:           params.Grpc,
 		forkManager:    forkManager,
+		validatorPeers: newValidatorPeers(),
 
 		// Configurations
 		config:             params.Config,
 		epochSize:          epochSize,
 		quorumSizeBlockNum: quorumSizeBlockNum,
 		blockTime:          time.Duration(params.BlockTime) * time.Second,
+		roundTimeout:       roundTimeout,
+		strictSignState:    params.StrictSignState,
+		checkpoint:         params.Checkpoint,
 
 		// Channels
 		closeCh: make(chan struct{}),
 	}
 
+	// the validators of a checkpoint header are only the ones of the following blocks at the end of an epoch,
+	// since the votes are reset then
+	if params.Checkpoint != nil && !p.IsLastOfEpoch(params.Checkpoint.Number) {
+		return nil, fmt.Errorf("%w: %d, epoch size %d", ErrCheckpointNotEpochEnd, params.Checkpoint.Number, epochSize)
+	}
+
+	p.syncer = syncer.NewSyncer(
+		params.Logger,
+		params.Network,
+		params.Blockchain,
+		time.Duration(params.BlockTime)*3*time.Second,
+		params.SnapSyncer,
+		params.Checkpoint,
+		p.initCheckpoint,
+	)
+
 	// Istanbul requires a different header hash function
 	p.SetHeaderHash()
 
 	return p, nil
 }
 
+// getConfigUint64 returns the number set in the IBFT config of the genesis, the default value if it isn't set
+// EpochParams returns the epoch size and the block number of the quorum size switch of the IBFT config
+func EpochParams(config map[string]interface{}) (uint64, uint64, error) {
+	epochSize, err := getConfigUint64(config, KeyEpochSize, DefaultEpochSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Block number specified for quorum size switch
+	quorumSizeBlockNum, err := getConfigUint64(config, KeyQuorumSizeBlockNum, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return epochSize, quorumSizeBlockNum, nil
+}
+
+func getConfigUint64(config map[string]interface{}, key string, defaultValue uint64) (uint64, error) {
+	raw, ok := config[key]
+	if !ok {
+		return defaultValue, nil
+	}
+
+	value, ok := raw.(float64)
+	if !ok {
+		return 0, errors.New("invalid type assertion")
+	}
+
+	return uint64(value), nil
+}
+
 func (i *backendIBFT) Initialize() error {
 	// register the grpc operator
 	if i.Grpc != nil {
@@ -185,19 +237,45 @@ func (i *backendIBFT) Initialize() error {
 		return err
 	}
 
+	if i.checkpoint != nil {
+		// the validators of the contract are read from the states before the checkpoint
+		validatorStore, err := i.forkManager.GetValidatorStore(i.checkpoint.Number)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := validatorStore.(fork.Updatable); !ok {
+			return ErrCheckpointNotSupported
+		}
+	}
+
 	if err := i.updateCurrentModules(i.blockchain.Header().Number + 1); err != nil {
 		return err
 	}
 
 	i.logger.Info("validator key", "addr", i.currentSigner.Address().String())
 
+	// load the last signed state, so the validator doesn't sign twice for the same state
+	signGuard, err := newSignGuard(
+		filepath.Join(i.config.Path, signStateFile),
+		i.blockchain.Header().Number,
+		i.strictSignState,
+	)
+	if err != nil {
+		return err
+	}
+
+	i.signGuard = signGuard
+
 	i.consensus = newIBFT(
-		i.logger.Named("consensus"),
+		newRoundMetricsLogger(i.logger.Named("consensus")),
 		i,
 		i,
 	)
 
-	// Ensure consensus takes into account user configured block production time
+	// Ensure consensus takes into account the curve of the round timeouts,
+	// along with the user configured block production time
+	i.consensus.SetRoundTimeout(i.roundTimeout.roundTimeouts(rand.Int63n)) //nolint:gosec
 	i.consensus.ExtendRoundTimeout(i.blockTime)
 
 	return nil
@@ -206,6 +284,11 @@ func (i *backendIBFT) Initialize() error {
 // sync runs the syncer in the background to receive blocks from advanced peers
 func (i *backendIBFT) startSyncing() {
 	callInsertBlockHook := func(block *types.Block) bool {
+		// the synced blocks are verified along with their committed seals
+		if err := i.blockchain.SetFinalized(block.Header); err != nil {
+			i.logger.Error("failed to finalize block", "height", block.Header.Number, "err", err)
+		}
+
 		if err := i.currentHooks.PostInsertBlock(block); err != nil {
 			i.logger.Error("failed to call PostInsertBlock", "height", block.Header.Number, "error", err)
 		}
@@ -476,7 +559,41 @@ func (i *backendIBFT) GetBlockCreator(header *types.Header) (types.Address, erro
 func (i *backendIBFT) PreCommitState(header *types.Header, txn *state.Transition) error {
 	hooks := i.forkManager.GetHooks(header.Number)
 
-	return hooks.PreCommitState(header, txn)
+	if err := hooks.PreCommitState(header, txn); err != nil {
+		return err
+	}
+
+	return i.verifyAccessListHash(header, txn)
+}
+
+// verifyAccessListHash checks the access list hash in the IBFT Extra matches the accesses of the executed block,
+// the header must not have one before the block access list fork
+func (i *backendIBFT) verifyAccessListHash(header *types.Header, txn *state.Transition) error {
+	signer, err := i.forkManager.GetSigner(header.Number)
+	if err != nil {
+		return err
+	}
+
+	extra, err := signer.GetIBFTExtra(header)
+	if err != nil {
+		return err
+	}
+
+	accessList := txn.AccessList()
+
+	if accessList == nil {
+		if extra.AccessListHash != nil {
+			return ErrInvalidAccessListHash
+		}
+
+		return nil
+	}
+
+	if extra.AccessListHash == nil || *extra.AccessListHash != accessList.Hash() {
+		return ErrInvalidAccessListHash
+	}
+
+	return nil
 }
 
 // GetEpoch returns the current epoch
@@ -493,6 +610,50 @@ func (i *backendIBFT) IsLastOfEpoch(number uint64) bool {
 	return number > 0 && number%i.epochSize == 0
 }
 
+// GetEpochSize returns the number of the blocks of an epoch
+func (i *backendIBFT) GetEpochSize() uint64 {
+	return i.epochSize
+}
+
+// GetValidators returns the validators sealing the header, listed in its extra
+func (i *backendIBFT) GetValidators(header *types.Header) (validators.Validators, error) {
+	signer, err := i.forkManager.GetSigner(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.GetValidators(header)
+}
+
+// GetParticipation returns how many of the validators of the header committed it
+func (i *backendIBFT) GetParticipation(header *types.Header) (*consensus.Participation, error) {
+	signer, err := i.forkManager.GetSigner(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	validators, err := signer.GetValidators(header)
+	if err != nil {
+		return nil, err
+	}
+
+	extra, err := signer.GetIBFTExtra(header)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := 0
+	if extra.CommittedSeals != nil {
+		sealed = extra.CommittedSeals.Num()
+	}
+
+	return &consensus.Participation{
+		Validators: validators.Len(),
+		Sealed:     sealed,
+		Validator:  validators.Includes(signer.Address()),
+	}, nil
+}
+
 // Close closes the IBFT consensus mechanism, and does write back to disk
 func (i *backendIBFT) Close() error {
 	close(i.closeCh)
@@ -529,7 +690,7 @@ func (i *backendIBFT) SetHeaderHash() {
 	}
 }
 
-// updateCurrentModules updates Signer, Hooks, and Validators
+// updateCurrentModules updates Signer, Hooks, Validators and the validators in maintenance
 // that are used at specified height
 // by fetching from ForkManager
 func (i *backendIBFT) updateCurrentModules(height uint64) error {
@@ -545,10 +706,52 @@ func (i *backendIBFT) updateCurrentModules(height uint64) error {
 	i.currentHooks = hooks
 
 	i.logFork(lastSigner, signer)
+	i.updateValidatorPeers()
+
+	maintenance, err := i.getMaintenanceValidators(height, validators)
+	if err != nil {
+		i.currentMaintenance = nil
+
+		return err
+	}
+
+	i.currentMaintenance = maintenance
 
 	return nil
 }
 
+// initCheckpoint sets up the validators of the blocks following the checkpoint the chain started from,
+// from the validators in its header. The ancestors of the checkpoint aren't processed,
+// so the snapshot is set at the parent of the checkpoint in order to verify its committed seals too
+func (i *backendIBFT) initCheckpoint(header *types.Header) error {
+	signer, err := i.forkManager.GetSigner(header.Number)
+	if err != nil {
+		return err
+	}
+
+	vals, err := signer.GetValidators(header)
+	if err != nil {
+		return err
+	}
+
+	validatorStore, err := i.forkManager.GetValidatorStore(header.Number)
+	if err != nil {
+		return err
+	}
+
+	if us, ok := validatorStore.(fork.Updatable); ok {
+		if err := us.UpdateValidatorSet(vals, header.Number); err != nil {
+			return err
+		}
+	}
+
+	i.logger.Info("validators set up from the checkpoint", "number", header.Number, "validators", vals.Len())
+
+	i.txpool.ResetWithHeaders(header)
+
+	return i.updateCurrentModules(header.Number + 1)
+}
+
 // logFork logs validation type switch
 func (i *backendIBFT) logFork(
 	lastSigner, signer signer.Signer,
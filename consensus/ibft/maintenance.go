@@ -0,0 +1,117 @@
+package ibft
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+// maintenanceTxGas is the gas limit of the maintenance announcement transaction
+const maintenanceTxGas = 60000
+
+var (
+	ErrMaintenanceNotEnabled = errors.New("maintenance announcements are not enabled on the chain")
+	ErrInvalidMaintenance    = errors.New("maintenance window must start after the next block and not exceed the maximum length")
+)
+
+// getMaintenanceValidators returns the validators whose maintenance window covers the height,
+// as registered in the state of the parent block, so all the validators agree on them
+func (i *backendIBFT) getMaintenanceValidators(
+	height uint64,
+	vals validators.Validators,
+) (map[types.Address]bool, error) {
+	if height == 0 || !i.config.Params.Forks.IsMaintenance(height) {
+		return nil, nil
+	}
+
+	parent, ok := i.blockchain.GetHeaderByNumber(height - 1)
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+
+	snap, err := i.executor.StateAt(parent.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		txn         = state.NewTxn(i.executor.State(), snap)
+		maintenance = make(map[types.Address]bool)
+	)
+
+	for idx := 0; idx < vals.Len(); idx++ {
+		addr := vals.At(uint64(idx)).Addr()
+
+		if from, to := precompiled.DecodeMaintenanceWindow(
+			txn.GetState(addr, precompiled.MaintenanceSlot),
+		); from <= height && height <= to {
+			maintenance[addr] = true
+		}
+	}
+
+	if len(maintenance) > 0 {
+		i.logger.Debug("validators in maintenance", "height", height, "count", len(maintenance))
+	}
+
+	return maintenance, nil
+}
+
+// announceMaintenance signs the transaction announcing the maintenance window of the validator
+// with its key, and adds it to the pool which broadcasts it to the network
+func (i *backendIBFT) announceMaintenance(fromBlock, toBlock uint64, gasPrice *big.Int) (*types.Transaction, error) {
+	height := i.blockchain.Header().Number + 1
+	if !i.config.Params.Forks.IsMaintenance(height) {
+		return nil, ErrMaintenanceNotEnabled
+	}
+
+	// (0, 0) cancels the window
+	if (fromBlock != 0 || toBlock != 0) &&
+		(fromBlock <= height || toBlock < fromBlock || toBlock-fromBlock >= precompiled.MaxMaintenanceWindow) {
+		return nil, ErrInvalidMaintenance
+	}
+
+	key, err := crypto.ReadConsensusKey(i.secretsManager)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := precompiled.MaintenanceMethod.Encode(map[string]interface{}{
+		"fromBlock": fromBlock,
+		"toBlock":   toBlock,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sender = crypto.PubKeyToAddress(&key.PublicKey)
+		to     = precompiled.MaintenanceAddr
+	)
+
+	tx, err := crypto.NewSigner(
+		i.config.Params.Forks.At(height, uint64(i.clock.Now().Unix())),
+		uint64(i.config.Params.ChainID),
+	).SignTx(&types.Transaction{
+		Nonce:    i.txpool.GetNonce(sender),
+		GasPrice: gasPrice,
+		Gas:      maintenanceTxGas,
+		To:       &to,
+		Value:    big.NewInt(0),
+		Input:    input,
+		From:     sender,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.txpool.AddTx(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
@@ -8,6 +8,12 @@ import (
 )
 
 func (i *backendIBFT) signMessage(msg *protoIBFT.Message) *protoIBFT.Message {
+	if err := i.guardSign(msg); err != nil {
+		i.logger.Error("refused to sign the message", "type", msg.Type, "err", err)
+
+		return nil
+	}
+
 	raw, err := proto.Marshal(msg)
 	if err != nil {
 		return nil
@@ -64,11 +70,8 @@ func (i *backendIBFT) BuildPrepareMessage(proposalHash []byte, view *protoIBFT.V
 }
 
 func (i *backendIBFT) BuildCommitMessage(proposalHash []byte, view *protoIBFT.View) *protoIBFT.Message {
-	committedSeal, err := i.currentSigner.CreateCommittedSeal(proposalHash)
-	if err != nil {
-		i.logger.Error("Unable to build commit message, %v", err)
-
-		return nil
+	commitData := &protoIBFT.CommitMessage{
+		ProposalHash: proposalHash,
 	}
 
 	msg := &protoIBFT.Message{
@@ -76,13 +79,26 @@ func (i *backendIBFT) BuildCommitMessage(proposalHash []byte, view *protoIBFT.Vi
 		From: i.ID(),
 		Type: protoIBFT.MessageType_COMMIT,
 		Payload: &protoIBFT.Message_CommitData{
-			CommitData: &protoIBFT.CommitMessage{
-				ProposalHash:  proposalHash,
-				CommittedSeal: committedSeal,
-			},
+			CommitData: commitData,
 		},
 	}
 
+	// the committed seal signs the proposal as well
+	if err := i.guardSign(msg); err != nil {
+		i.logger.Error("refused to sign the message", "type", msg.Type, "err", err)
+
+		return nil
+	}
+
+	committedSeal, err := i.currentSigner.CreateCommittedSeal(proposalHash)
+	if err != nil {
+		i.logger.Error("Unable to build commit message, %v", err)
+
+		return nil
+	}
+
+	commitData.CommittedSeal = committedSeal
+
 	return i.signMessage(msg)
 }
 
@@ -103,3 +119,28 @@ func (i *backendIBFT) BuildRoundChangeMessage(
 
 	return i.signMessage(msg)
 }
+
+// guardSign checks the message against the last signed state, and records its state if it votes for a proposal
+func (i *backendIBFT) guardSign(msg *protoIBFT.Message) error {
+	if i.signGuard == nil {
+		return nil
+	}
+
+	step, ok := signSteps[msg.Type]
+	if !ok {
+		return i.signGuard.checkHeight(msg.View.Height)
+	}
+
+	var proposalHash []byte
+
+	switch payload := msg.Payload.(type) {
+	case *protoIBFT.Message_PreprepareData:
+		proposalHash = payload.PreprepareData.ProposalHash
+	case *protoIBFT.Message_PrepareData:
+		proposalHash = payload.PrepareData.ProposalHash
+	case *protoIBFT.Message_CommitData:
+		proposalHash = payload.CommitData.ProposalHash
+	}
+
+	return i.signGuard.sign(msg.View.Height, msg.View.Round, step, types.BytesToHash(proposalHash))
+}
@@ -120,6 +120,26 @@ func (o *operator) Candidates(ctx context.Context, req *empty.Empty) (*proto.Can
 	}, nil
 }
 
+// AnnounceMaintenance broadcasts the transaction registering the maintenance window of the validator
+func (o *operator) AnnounceMaintenance(
+	ctx context.Context,
+	req *proto.MaintenanceReq,
+) (*proto.MaintenanceResp, error) {
+	gasPrice, err := types.ParseUint256orHex(&req.GasPrice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas price: %w", err)
+	}
+
+	tx, err := o.ibft.announceMaintenance(req.From, req.To, gasPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.MaintenanceResp{
+		TxHash: tx.Hash.String(),
+	}, nil
+}
+
 // parseCandidate parses proto.Candidate and maps to validator
 func (o *operator) parseCandidate(req *proto.Candidate) (validators.Validator, error) {
 	signer, err := o.getLatestSigner()
@@ -0,0 +1,309 @@
+package ibft
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/txpool"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// packingDeadlineRatio is the share of the block time spent packing the transactions,
+	// the rest of it is left to commit the state and seal the block before the block time elapses
+	packingDeadlineRatio = 0.75
+
+	// maxFillCandidates is the number of transactions the fill pass chooses among, in the order of their tips
+	maxFillCandidates = 256
+	// fillCapacityUnits is the number of units the gas left is divided in by the fill pass
+	fillCapacityUnits = 1024
+)
+
+// The reasons the packing of a block stopped
+const (
+	stopPoolEmpty = "pool_empty"
+	stopGasLimit  = "gas_limit"
+	stopSizeLimit = "size_limit"
+	stopDeadline  = "deadline"
+)
+
+// The passes of the packer
+const (
+	passPriority = "priority"
+	passFill     = "fill"
+)
+
+// packingDeadline returns the time spent packing the transactions of a block
+func packingDeadline(blockTime time.Duration) time.Duration {
+	return time.Duration(float64(blockTime) * packingDeadlineRatio)
+}
+
+// packer packs the transactions of the pool into a block until the deadline.
+//
+// The whole gas price is paid to the proposer, so the effective tip of a transaction is its gas price,
+// which the pool orders the first executable transaction of every account by. Popping the transaction of
+// an account makes its next one executable, so the transactions of an account are packed in the order of
+// their nonces, and an account whose transaction doesn't fit the gas left is skipped until the next block.
+//
+// The pool peeks the transactions of the system and priority lanes first, and each lane is packed in the gas
+// reserved to it, the transactions of an account exceeding it are left for the next block.
+//
+// The priority pass packs the transactions in the order of their lanes and tips until a regular one doesn't
+// fit the gas left. The block is then near its gas limit, and the fill pass packs the set of the remaining transactions
+// which pays the most in the gas left, rather than only the ones following the order of the tips
+type packer struct {
+	txpool     txPoolInterface
+	transition transitionInterface
+	deadline   <-chan time.Time
+
+	// write executes the transaction, the packing stops if it returns false
+	write func(tx *types.Transaction) (*txExeResult, bool)
+
+	blockNumber uint64
+	gasLimit    uint64
+	maxSize     uint64
+
+	size     uint64
+	executed []*types.Transaction
+
+	// laneGas is the gas used by the transactions of every lane
+	laneGas map[txpool.Lane]uint64
+
+	successful int
+	failed     int
+	skipped    int
+	deferred   int
+}
+
+// pack packs the transactions, and returns the reason it stopped
+func (p *packer) pack() string {
+	p.txpool.Prepare()
+
+	// the priority pass
+	for {
+		if p.expired() {
+			return stopDeadline
+		}
+
+		tx := p.txpool.Peek()
+		if tx == nil {
+			return stopPoolEmpty
+		}
+
+		if !p.check(tx) {
+			continue
+		}
+
+		if p.maxSize != 0 && p.size+tx.Size() > p.maxSize {
+			return stopSizeLimit
+		}
+
+		if lane := p.txpool.Lane(tx); lane != txpool.LaneRegular {
+			if tx.Gas > p.gasLeft() || p.laneGas[lane]+tx.Gas > p.txpool.LaneGas(lane, p.gasLimit) {
+				// the account is skipped until the next block, the lane goes on
+				p.deferred++
+
+				continue
+			}
+		} else if tx.Gas > p.gasLeft() {
+			// the account is skipped until the next block
+			p.deferred++
+
+			break
+		}
+
+		if stop := p.apply(tx, passPriority); stop != "" {
+			return stop
+		}
+	}
+
+	return p.fill()
+}
+
+// fill packs the remaining transactions chosen to pay the most in the gas left,
+// until none fits in it. Each round chooses among the executable transactions,
+// which are followed by the next transactions of their accounts in the next round
+func (p *packer) fill() string {
+	for {
+		if p.gasLeft() < state.TxGas {
+			return stopGasLimit
+		}
+
+		var candidates []*types.Transaction
+
+		for len(candidates) < maxFillCandidates {
+			if p.expired() {
+				return stopDeadline
+			}
+
+			tx := p.txpool.Peek()
+			if tx == nil {
+				break
+			}
+
+			if !p.check(tx) {
+				continue
+			}
+
+			if tx.Gas > p.gasLeft() || (p.maxSize != 0 && p.size+tx.Size() > p.maxSize) {
+				p.deferred++
+
+				continue
+			}
+
+			candidates = append(candidates, tx)
+		}
+
+		if len(candidates) == 0 {
+			return stopGasLimit
+		}
+
+		chosen := choose(candidates, p.gasLeft())
+		packed := false
+
+		for _, tx := range candidates {
+			if !chosen[tx] || tx.Gas > p.gasLeft() || (p.maxSize != 0 && p.size+tx.Size() > p.maxSize) {
+				// the accounts not chosen are skipped until the next block
+				p.deferred++
+
+				continue
+			}
+
+			if stop := p.apply(tx, passFill); stop != "" {
+				return stop
+			}
+
+			packed = true
+		}
+
+		if !packed {
+			return stopGasLimit
+		}
+	}
+}
+
+// check drops the transaction if it can't be included anymore, and returns whether it can be packed
+func (p *packer) check(tx *types.Transaction) bool {
+	if p.maxSize != 0 && tx.Size() > p.maxSize {
+		// the transaction can't fit in any block
+		p.txpool.Drop(tx)
+		p.failed++
+
+		return false
+	}
+
+	if tx.IsExpired(p.blockNumber) || !p.transition.MatchKnownAccounts(tx) {
+		// the conditions of the transaction don't hold anymore
+		p.txpool.Drop(tx)
+		p.failed++
+
+		return false
+	}
+
+	return true
+}
+
+// apply executes the transaction, and returns the reason the packing stops if it does
+func (p *packer) apply(tx *types.Transaction, pass string) string {
+	used := p.transition.TotalGas()
+
+	result, ok := p.write(tx)
+	if !ok {
+		return stopGasLimit
+	}
+
+	if p.laneGas == nil {
+		p.laneGas = make(map[txpool.Lane]uint64)
+	}
+
+	p.laneGas[p.txpool.Lane(tx)] += p.transition.TotalGas() - used
+
+	switch result.status {
+	case success:
+		p.executed = append(p.executed, tx)
+		p.size += tx.Size()
+		p.successful++
+
+		telemetry.ConsensusPackedTransactions.WithLabelValues(pass).Inc()
+	case fail:
+		p.failed++
+	case skip:
+		p.skipped++
+	case deferred:
+		p.deferred++
+	}
+
+	return ""
+}
+
+func (p *packer) gasLeft() uint64 {
+	if used := p.transition.TotalGas(); used < p.gasLimit {
+		return p.gasLimit - used
+	}
+
+	return 0
+}
+
+func (p *packer) expired() bool {
+	select {
+	case <-p.deadline:
+		return true
+	default:
+		return false
+	}
+}
+
+// choose returns the transactions paying the most fees in the gas, solving the knapsack
+// over the gas divided in units. The gas of the transactions is rounded down to the units,
+// so the ones filling the gas exactly are chosen together, and the chosen transactions which
+// don't fit the gas left once applied are left for the next block. The fees are counted at the gas price plus one wei,
+// so the most gas is packed among the free transactions
+func choose(txs []*types.Transaction, gas uint64) map[*types.Transaction]bool {
+	unit := gas / fillCapacityUnits
+	if unit == 0 {
+		unit = 1
+	}
+
+	capacity := int(gas / unit)
+
+	// best[c] is the highest fee paid in c units, by the transactions chosen in taken[i][c]
+	best := make([]float64, capacity+1)
+	taken := make([][]bool, len(txs))
+
+	for i, tx := range txs {
+		weight := units(tx.Gas, unit)
+		price, _ := new(big.Float).SetInt(tx.GasPrice).Float64()
+		fee := (price + 1) * float64(tx.Gas)
+
+		taken[i] = make([]bool, capacity+1)
+
+		for c := capacity; c >= weight; c-- {
+			if value := best[c-weight] + fee; value > best[c] {
+				best[c] = value
+				taken[i][c] = true
+			}
+		}
+	}
+
+	chosen := make(map[*types.Transaction]bool)
+
+	for i, c := len(txs)-1, capacity; i >= 0; i-- {
+		if taken[i][c] {
+			chosen[txs[i]] = true
+			c -= units(txs[i].Gas, unit)
+		}
+	}
+
+	return chosen
+}
+
+// units returns the gas in units, at least one
+func units(gas, unit uint64) int {
+	if gas < unit {
+		return 1
+	}
+
+	return int(gas / unit)
+}
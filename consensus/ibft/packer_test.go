@@ -0,0 +1,250 @@
+package ibft
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/txpool"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// mockAccountsPool serves the transactions of the accounts in the order of their nonces,
+// peeking the first one of the accounts in the order of their gas prices like the pool
+type mockAccountsPool struct {
+	txPoolInterface
+
+	accounts    map[types.Address][]*types.Transaction
+	executables []*types.Transaction
+	dropped     []types.Address
+
+	// priority are the senders of the priority lane, reserved half of the gas limit
+	priority map[types.Address]bool
+}
+
+func newMockAccountsPool(txs ...*types.Transaction) *mockAccountsPool {
+	pool := &mockAccountsPool{accounts: make(map[types.Address][]*types.Transaction)}
+
+	for _, tx := range txs {
+		pool.accounts[tx.From] = append(pool.accounts[tx.From], tx)
+	}
+
+	return pool
+}
+
+func (m *mockAccountsPool) push(tx *types.Transaction) {
+	m.executables = append(m.executables, tx)
+
+	sort.SliceStable(m.executables, func(i, j int) bool {
+		if li, lj := m.Lane(m.executables[i]), m.Lane(m.executables[j]); li != lj {
+			return li < lj
+		}
+
+		return m.executables[i].GasPrice.Cmp(m.executables[j].GasPrice) > 0
+	})
+}
+
+func (m *mockAccountsPool) Lane(tx *types.Transaction) txpool.Lane {
+	if m.priority[tx.From] {
+		return txpool.LanePriority
+	}
+
+	return txpool.LaneRegular
+}
+
+func (m *mockAccountsPool) LaneGas(lane txpool.Lane, gasLimit uint64) uint64 {
+	if lane == txpool.LanePriority {
+		return gasLimit / 2
+	}
+
+	return gasLimit
+}
+
+func (m *mockAccountsPool) Prepare() {
+	m.executables = nil
+
+	for _, txs := range m.accounts {
+		m.push(txs[0])
+	}
+}
+
+func (m *mockAccountsPool) Peek() *types.Transaction {
+	if len(m.executables) == 0 {
+		return nil
+	}
+
+	tx := m.executables[0]
+	m.executables = m.executables[1:]
+
+	return tx
+}
+
+func (m *mockAccountsPool) Pop(tx *types.Transaction) {
+	txs := m.accounts[tx.From][1:]
+	if len(txs) == 0 {
+		delete(m.accounts, tx.From)
+
+		return
+	}
+
+	m.accounts[tx.From] = txs
+	m.push(txs[0])
+}
+
+func (m *mockAccountsPool) Drop(tx *types.Transaction) {
+	delete(m.accounts, tx.From)
+	m.dropped = append(m.dropped, tx.From)
+}
+
+// mockGasTransition counts the gas of the written transactions
+type mockGasTransition struct {
+	mockTransition
+
+	gas uint64
+}
+
+func (m *mockGasTransition) Write(tx *types.Transaction) error {
+	m.gas += tx.Gas
+
+	return nil
+}
+
+func (m *mockGasTransition) TotalGas() uint64 {
+	return m.gas
+}
+
+func newPacker(pool *mockAccountsPool, gasLimit uint64, deadline <-chan time.Time) *packer {
+	transition := &mockGasTransition{}
+
+	return &packer{
+		txpool:     pool,
+		transition: transition,
+		deadline:   deadline,
+		gasLimit:   gasLimit,
+		write: func(tx *types.Transaction) (*txExeResult, bool) {
+			_ = transition.Write(tx)
+			pool.Pop(tx)
+
+			return &txExeResult{tx, success}, true
+		},
+	}
+}
+
+func packerTx(from byte, nonce, price, gas uint64) *types.Transaction {
+	return &types.Transaction{
+		From:     types.Address{from},
+		Nonce:    nonce,
+		GasPrice: new(big.Int).SetUint64(price),
+		Gas:      gas,
+	}
+}
+
+func TestPacker_Pack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the transactions are packed in the order of their tips and nonces", func(t *testing.T) {
+		t.Parallel()
+
+		a0, a1 := packerTx(1, 0, 10, 21000), packerTx(1, 1, 1, 21000)
+		b0 := packerTx(2, 0, 5, 21000)
+
+		p := newPacker(newMockAccountsPool(a0, a1, b0), 1_000_000, nil)
+
+		assert.Equal(t, stopPoolEmpty, p.pack())
+		assert.Equal(t, []*types.Transaction{a0, b0, a1}, p.executed)
+	})
+
+	t.Run("the account not fitting the gas left is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		large := packerTx(1, 0, 10, 80_000)
+		next := packerTx(1, 1, 10, 10_000)
+		small := packerTx(2, 0, 1, 21_000)
+
+		p := newPacker(newMockAccountsPool(packerTx(3, 0, 20, 40_000), large, next, small), 100_000, nil)
+
+		assert.Equal(t, stopGasLimit, p.pack())
+		assert.Len(t, p.executed, 2)
+		assert.Equal(t, small, p.executed[1])
+		assert.Equal(t, 1, p.deferred)
+	})
+
+	t.Run("the fill pass packs the transactions paying the most in the gas left", func(t *testing.T) {
+		t.Parallel()
+
+		first := packerTx(1, 0, 30, 100_000)
+		a := packerTx(2, 0, 10, 60_000)
+		b := packerTx(3, 0, 9, 50_000)
+		c := packerTx(4, 0, 8, 50_000)
+
+		p := newPacker(newMockAccountsPool(first, a, b, c, packerTx(5, 0, 20, 150_000)), 200_000, nil)
+
+		assert.Equal(t, stopGasLimit, p.pack())
+		assert.Equal(t, []*types.Transaction{first, b, c}, p.executed)
+		assert.Zero(t, p.gasLeft())
+	})
+
+	t.Run("the priority lane is packed first in the gas reserved to it", func(t *testing.T) {
+		t.Parallel()
+
+		p0, p1, p2 := packerTx(1, 0, 1, 30_000), packerTx(1, 1, 1, 30_000), packerTx(1, 2, 1, 30_000)
+		other := packerTx(2, 0, 2, 50_000)
+		regular := packerTx(3, 0, 100, 30_000)
+
+		pool := newMockAccountsPool(p0, p1, p2, other, regular)
+		pool.priority = map[types.Address]bool{{1}: true, {2}: true}
+
+		p := newPacker(pool, 200_000, nil)
+
+		// the second transaction of the first sender exceeds the reserved gas, and the regular lane goes on
+		assert.Equal(t, stopPoolEmpty, p.pack())
+		assert.Equal(t, []*types.Transaction{other, p0, regular}, p.executed)
+		assert.Equal(t, 1, p.deferred)
+	})
+
+	t.Run("the packing stops at the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		deadline := make(chan time.Time, 1)
+		deadline <- time.Now()
+
+		p := newPacker(newMockAccountsPool(packerTx(1, 0, 1, 21000)), 1_000_000, deadline)
+
+		assert.Equal(t, stopDeadline, p.pack())
+		assert.Empty(t, p.executed)
+	})
+
+	t.Run("the expired transactions are dropped", func(t *testing.T) {
+		t.Parallel()
+
+		expired := packerTx(1, 0, 1, 21000)
+		expired.ValidUntil = 1
+
+		pool := newMockAccountsPool(expired, packerTx(2, 0, 1, 21000))
+
+		p := newPacker(pool, 1_000_000, nil)
+		p.blockNumber = 2
+
+		assert.Equal(t, stopPoolEmpty, p.pack())
+		assert.Len(t, p.executed, 1)
+		assert.Equal(t, []types.Address{{1}}, pool.dropped)
+	})
+}
+
+func TestChoose(t *testing.T) {
+	t.Parallel()
+
+	a := packerTx(1, 0, 10, 60_000)
+	b := packerTx(2, 0, 9, 50_000)
+	c := packerTx(3, 0, 9, 50_000)
+
+	assert.Equal(t, map[*types.Transaction]bool{b: true, c: true}, choose([]*types.Transaction{a, b, c}, 100_000))
+	assert.Equal(t, map[*types.Transaction]bool{a: true}, choose([]*types.Transaction{a, b, c}, 60_000))
+
+	// the free transactions are chosen to pack the most gas
+	free := []*types.Transaction{packerTx(1, 0, 0, 30_000), packerTx(2, 0, 0, 40_000), packerTx(3, 0, 0, 60_000)}
+	assert.Equal(t, map[*types.Transaction]bool{free[1]: true, free[2]: true}, choose(free, 100_000))
+}
@@ -0,0 +1,88 @@
+package ibft
+
+import (
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/go-ibft/core"
+
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
+)
+
+// The outcomes of the consensus rounds
+const (
+	roundCommitted = "committed"
+	roundTimeout   = "timeout"
+	roundChange    = "round_change"
+	roundCancelled = "cancelled"
+)
+
+// roundEvents maps the messages logged by go-ibft at the end of a round to its outcome.
+// A sequence done without any of them is done by the commit of the round in process
+var roundEvents = map[string]string{
+	"round timeout expired":    roundTimeout,
+	"received future proposal": roundChange,
+	"received future RCC":      roundChange,
+	"sequence cancelled":       roundCancelled,
+}
+
+// roundMetricsLogger is the logger of go-ibft measuring the duration of the rounds from the messages it logs,
+// as the sequence doesn't expose the starts and the ends of the rounds
+type roundMetricsLogger struct {
+	core.Logger
+
+	lock    sync.Mutex
+	started time.Time
+	running bool
+
+	now     func() time.Time
+	observe func(outcome string, duration time.Duration)
+}
+
+func newRoundMetricsLogger(logger core.Logger) *roundMetricsLogger {
+	return &roundMetricsLogger{
+		Logger: logger,
+		now:    time.Now,
+		observe: func(outcome string, duration time.Duration) {
+			telemetry.ConsensusRoundDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+		},
+	}
+}
+
+func (l *roundMetricsLogger) Info(msg string, args ...interface{}) {
+	l.track(msg)
+	l.Logger.Info(msg, args...)
+}
+
+func (l *roundMetricsLogger) Debug(msg string, args ...interface{}) {
+	l.track(msg)
+	l.Logger.Debug(msg, args...)
+}
+
+// track starts or ends the round in process on the message
+func (l *roundMetricsLogger) track(msg string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	switch msg {
+	case "round started":
+		l.started = l.now()
+		l.running = true
+	case "sequence done":
+		l.end(roundCommitted)
+	default:
+		if outcome, ok := roundEvents[msg]; ok {
+			l.end(outcome)
+		}
+	}
+}
+
+// end observes the duration of the round in process with the outcome, if any
+func (l *roundMetricsLogger) end(outcome string) {
+	if !l.running {
+		return
+	}
+
+	l.running = false
+	l.observe(outcome, l.now().Sub(l.started))
+}
@@ -0,0 +1,57 @@
+package ibft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundMetricsLogger(t *testing.T) {
+	t.Parallel()
+
+	type round struct {
+		outcome  string
+		duration time.Duration
+	}
+
+	var (
+		now    = time.Unix(0, 0)
+		rounds []round
+		logger = newRoundMetricsLogger(hclog.NewNullLogger())
+	)
+
+	logger.now = func() time.Time {
+		return now
+	}
+	logger.observe = func(outcome string, duration time.Duration) {
+		rounds = append(rounds, round{outcome, duration})
+	}
+
+	// a round timing out, then a round committed
+	logger.Info("sequence started", "height", 1)
+	logger.Info("round started", "round", 0)
+	now = now.Add(10 * time.Second)
+	logger.Info("round timeout expired", "round", 0)
+	logger.Info("round started", "round", 1)
+	now = now.Add(2 * time.Second)
+	logger.Info("sequence done", "height", 1)
+
+	// a round jumping to a future round, then a round cancelled
+	logger.Info("sequence started", "height", 2)
+	logger.Info("round started", "round", 0)
+	now = now.Add(3 * time.Second)
+	logger.Info("received future RCC", "round", 2)
+	logger.Info("round started", "round", 2)
+	now = now.Add(time.Second)
+	logger.Debug("sequence cancelled")
+	logger.Info("sequence done", "height", 2)
+
+	assert.Equal(t, []round{
+		{roundTimeout, 10 * time.Second},
+		{roundCommitted, 2 * time.Second},
+		{roundChange, 3 * time.Second},
+		{roundCancelled, time.Second},
+	}, rounds)
+}
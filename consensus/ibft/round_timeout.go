@@ -0,0 +1,130 @@
+package ibft
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// KeyRoundTimeout is the timeout of the first round in seconds
+	KeyRoundTimeout = "roundTimeout"
+	// KeyRoundTimeoutFactor is the factor the round timeout is multiplied by on every round change
+	KeyRoundTimeoutFactor = "roundTimeoutFactor"
+	// KeyRoundTimeoutJitter is the highest share of the round timeout, in percent, added at random to every round
+	KeyRoundTimeoutJitter = "roundTimeoutJitter"
+
+	// DefaultRoundTimeoutFactor is the factor of go-ibft, which doubles the round timeout on every round change
+	DefaultRoundTimeoutFactor = 2
+
+	// maxRoundTimeoutJitter is the highest jitter, which can double the round timeout
+	maxRoundTimeoutJitter = 100
+
+	// maxRoundTimeout bounds the curve, the rounds reaching it are the ones of a halted network
+	maxRoundTimeout = 24 * time.Hour
+)
+
+var (
+	ErrInvalidRoundTimeout       = errors.New("the round timeout must be positive")
+	ErrInvalidRoundTimeoutFactor = errors.New("the round timeout factor must be at least 1")
+	ErrInvalidRoundTimeoutJitter = fmt.Errorf("the round timeout jitter must be at most %d%%", maxRoundTimeoutJitter)
+)
+
+// RoundTimeout is the curve of the timeouts of the rounds, which grow exponentially
+// so the validators eventually agree on a round when several proposers are down
+type RoundTimeout struct {
+	// Base is the timeout of the first round
+	Base time.Duration
+	// Factor is the factor the timeout is multiplied by on every round change
+	Factor float64
+	// Jitter is the highest share of the timeout, in percent, added at random to every round,
+	// so the validators don't change rounds all at once
+	Jitter uint64
+}
+
+// RoundTimeoutParams reads the curve of the round timeouts of the IBFT config, the go-ibft one if it isn't set
+func RoundTimeoutParams(config map[string]interface{}) (*RoundTimeout, error) {
+	base, err := getConfigUint64(config, KeyRoundTimeout, uint64(baseRoundTimeout/time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	factor, err := getConfigFloat64(config, KeyRoundTimeoutFactor, DefaultRoundTimeoutFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	jitter, err := getConfigUint64(config, KeyRoundTimeoutJitter, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := &RoundTimeout{
+		Base:   time.Duration(base) * time.Second,
+		Factor: factor,
+		Jitter: jitter,
+	}
+
+	if err := curve.Validate(); err != nil {
+		return nil, err
+	}
+
+	return curve, nil
+}
+
+// Validate checks the curve grows from a positive timeout, with a bounded jitter
+func (r *RoundTimeout) Validate() error {
+	if r.Base <= 0 {
+		return ErrInvalidRoundTimeout
+	}
+
+	if r.Factor < 1 {
+		return ErrInvalidRoundTimeoutFactor
+	}
+
+	if r.Jitter > maxRoundTimeoutJitter {
+		return ErrInvalidRoundTimeoutJitter
+	}
+
+	return nil
+}
+
+// Timeout returns the timeout of the round without the jitter
+func (r *RoundTimeout) Timeout(round uint64) time.Duration {
+	timeout := float64(r.Base) * math.Pow(r.Factor, float64(round))
+	if timeout >= float64(maxRoundTimeout) {
+		return maxRoundTimeout
+	}
+
+	return time.Duration(timeout)
+}
+
+// getConfigFloat64 returns the number set in the IBFT config of the genesis, the default value if it isn't set
+func getConfigFloat64(config map[string]interface{}, key string, defaultValue float64) (float64, error) {
+	raw, ok := config[key]
+	if !ok {
+		return defaultValue, nil
+	}
+
+	value, ok := raw.(float64)
+	if !ok {
+		return 0, errors.New("invalid type assertion")
+	}
+
+	return value, nil
+}
+
+// roundTimeouts returns the timeouts of the rounds set in go-ibft, on the curve along with the jitter.
+// jitter returns a random duration in [0, n)
+func (r *RoundTimeout) roundTimeouts(jitter func(n int64) int64) func(round uint64) time.Duration {
+	return func(round uint64) time.Duration {
+		timeout := r.Timeout(round)
+
+		if r.Jitter > 0 {
+			timeout += time.Duration(jitter(int64(timeout)/100*int64(r.Jitter) + 1))
+		}
+
+		return timeout
+	}
+}
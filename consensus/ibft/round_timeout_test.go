@@ -0,0 +1,102 @@
+package ibft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTimeoutParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected *RoundTimeout
+		err      error
+	}{
+		{
+			name:     "the go-ibft curve by default",
+			config:   map[string]interface{}{},
+			expected: &RoundTimeout{Base: 10 * time.Second, Factor: 2},
+		},
+		{
+			name: "the configured curve",
+			config: map[string]interface{}{
+				KeyRoundTimeout:       float64(4),
+				KeyRoundTimeoutFactor: 1.5,
+				KeyRoundTimeoutJitter: float64(20),
+			},
+			expected: &RoundTimeout{Base: 4 * time.Second, Factor: 1.5, Jitter: 20},
+		},
+		{
+			name:   "a zero timeout",
+			config: map[string]interface{}{KeyRoundTimeout: float64(0)},
+			err:    ErrInvalidRoundTimeout,
+		},
+		{
+			name:   "a shrinking curve",
+			config: map[string]interface{}{KeyRoundTimeoutFactor: 0.5},
+			err:    ErrInvalidRoundTimeoutFactor,
+		},
+		{
+			name:   "a jitter above the timeout",
+			config: map[string]interface{}{KeyRoundTimeoutJitter: float64(101)},
+			err:    ErrInvalidRoundTimeoutJitter,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			curve, err := RoundTimeoutParams(tt.config)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, curve)
+		})
+	}
+}
+
+func TestRoundTimeouts(t *testing.T) {
+	t.Parallel()
+
+	// the highest jitter
+	jitter := func(n int64) int64 {
+		return n - 1
+	}
+
+	// timeouts returns the timeouts of the first rounds set in go-ibft
+	timeouts := func(curve *RoundTimeout) []time.Duration {
+		roundTimeout := curve.roundTimeouts(jitter)
+
+		return []time.Duration{roundTimeout(0), roundTimeout(1), roundTimeout(2)}
+	}
+
+	t.Run("the go-ibft curve", func(t *testing.T) {
+		t.Parallel()
+
+		curve := &RoundTimeout{Base: baseRoundTimeout, Factor: DefaultRoundTimeoutFactor}
+
+		assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second}, timeouts(curve))
+	})
+
+	t.Run("the configured curve along with the jitter", func(t *testing.T) {
+		t.Parallel()
+
+		curve := &RoundTimeout{Base: 4 * time.Second, Factor: 1.5, Jitter: 50}
+
+		// the highest jitter is half the timeout of the curve
+		assert.Equal(t, []time.Duration{6 * time.Second, 9 * time.Second, 13500 * time.Millisecond}, timeouts(curve))
+	})
+
+	t.Run("the curve is bounded", func(t *testing.T) {
+		t.Parallel()
+
+		curve := &RoundTimeout{Base: time.Second, Factor: 10}
+		require.Equal(t, maxRoundTimeout, curve.Timeout(100))
+	})
+}
@@ -0,0 +1,195 @@
+package ibft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	protoIBFT "github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// signStateFile is the file of the last state signed by the validator, in the consensus directory
+	signStateFile = "last_sign_state.json"
+)
+
+var (
+	ErrSignStateMissing = errors.New("last sign state file is missing")
+	ErrSignStateBehind  = errors.New("last sign state is behind the chain")
+	ErrSignRegression   = errors.New("signing a state preceding the last signed one")
+	ErrDoubleSign       = errors.New("signing a different proposal for the last signed state")
+)
+
+// signStep is the step of the round the validator signs for, in the order they are signed
+type signStep uint8
+
+const (
+	signStepPrePrepare signStep = iota + 1
+	signStepPrepare
+	signStepCommit
+)
+
+// signSteps maps the messages voting for a proposal to their step
+var signSteps = map[protoIBFT.MessageType]signStep{
+	protoIBFT.MessageType_PREPREPARE: signStepPrePrepare,
+	protoIBFT.MessageType_PREPARE:    signStepPrepare,
+	protoIBFT.MessageType_COMMIT:     signStepCommit,
+}
+
+// signState is the last height, round and step the validator signed for, along with the signed proposal
+type signState struct {
+	Height       uint64     `json:"height"`
+	Round        uint64     `json:"round"`
+	Step         signStep   `json:"step"`
+	ProposalHash types.Hash `json:"proposalHash"`
+}
+
+// precedes checks whether the state comes before the given height, round and step
+func (s *signState) precedes(height, round uint64, step signStep) bool {
+	if s.Height != height {
+		return s.Height < height
+	}
+
+	if s.Round != round {
+		return s.Round < round
+	}
+
+	return s.Step < step
+}
+
+// signGuard refuses to sign for a state preceding the last signed one, or for a different proposal
+// at the last signed state. The state is persisted before the signature is released,
+// so the validator doesn't sign twice after a restart
+type signGuard struct {
+	path string
+
+	lock sync.Mutex
+	// last signed state, nil if none
+	last *signState
+}
+
+// newSignGuard loads the last sign state from the file. In strict mode the file has to exist
+// and it must not be behind the chain head, like after restoring the validator from a backup
+func newSignGuard(path string, head uint64, strict bool) (*signGuard, error) {
+	g := &signGuard{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(data, &g.last); err != nil {
+			return nil, fmt.Errorf("failed to read the last sign state: %w", err)
+		}
+	}
+
+	if !strict {
+		return g, nil
+	}
+
+	if g.last == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSignStateMissing, path)
+	}
+
+	if g.last.Height < head {
+		return nil, fmt.Errorf("%w: last signed height %d, chain height %d", ErrSignStateBehind, g.last.Height, head)
+	}
+
+	return g, nil
+}
+
+// checkHeight checks that the height isn't below the last signed one, for the messages not voting for a proposal
+func (g *signGuard) checkHeight(height uint64) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.last != nil && height < g.last.Height {
+		return fmt.Errorf("%w: height %d, last signed height %d", ErrSignRegression, height, g.last.Height)
+	}
+
+	return nil
+}
+
+// sign records the state to be signed, if it is safe to sign for it
+func (g *signGuard) sign(height, round uint64, step signStep, proposalHash types.Hash) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if last := g.last; last != nil {
+		if last.Height == height && last.Round == round && last.Step == step {
+			if last.ProposalHash != proposalHash {
+				return fmt.Errorf(
+					"%w: height %d, round %d, signed %s, signing %s",
+					ErrDoubleSign, height, round, last.ProposalHash, proposalHash,
+				)
+			}
+
+			// the same signature again
+			return nil
+		}
+
+		if !last.precedes(height, round, step) {
+			return fmt.Errorf(
+				"%w: height %d, round %d, last signed height %d, round %d",
+				ErrSignRegression, height, round, last.Height, last.Round,
+			)
+		}
+	}
+
+	state := &signState{
+		Height:       height,
+		Round:        round,
+		Step:         step,
+		ProposalHash: proposalHash,
+	}
+
+	if err := g.write(state); err != nil {
+		return fmt.Errorf("failed to write the last sign state: %w", err)
+	}
+
+	g.last = state
+
+	return nil
+}
+
+// write replaces the file with the state, synced to the disk before it is renamed
+func (g *signGuard) write(state *signState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(g.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := g.path + ".tmp"
+
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, g.path)
+}
@@ -0,0 +1,69 @@
+package ibft
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestSignGuard_Sign(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hash1 = types.StringToHash("1")
+		hash2 = types.StringToHash("2")
+		path  = filepath.Join(t.TempDir(), signStateFile)
+	)
+
+	guard, err := newSignGuard(path, 0, false)
+	require.NoError(t, err)
+
+	require.NoError(t, guard.sign(10, 0, signStepPrepare, hash1))
+
+	// the same signature again
+	assert.NoError(t, guard.sign(10, 0, signStepPrepare, hash1))
+
+	// a different proposal for the same state
+	assert.ErrorIs(t, guard.sign(10, 0, signStepPrepare, hash2), ErrDoubleSign)
+
+	// the states preceding the last signed one
+	assert.ErrorIs(t, guard.sign(10, 0, signStepPrePrepare, hash1), ErrSignRegression)
+	assert.ErrorIs(t, guard.sign(9, 5, signStepCommit, hash1), ErrSignRegression)
+	assert.ErrorIs(t, guard.checkHeight(9), ErrSignRegression)
+
+	// the next step and a new round
+	assert.NoError(t, guard.sign(10, 0, signStepCommit, hash1))
+	assert.NoError(t, guard.sign(10, 1, signStepPrepare, hash2))
+	assert.NoError(t, guard.checkHeight(10))
+
+	// the last signed state is kept after a restart
+	guard, err = newSignGuard(path, 10, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, &signState{Height: 10, Round: 1, Step: signStepPrepare, ProposalHash: hash2}, guard.last)
+	assert.ErrorIs(t, guard.sign(10, 1, signStepPrepare, hash1), ErrDoubleSign)
+}
+
+func TestNewSignGuard_Strict(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), signStateFile)
+
+	_, err := newSignGuard(path, 0, true)
+	assert.ErrorIs(t, err, ErrSignStateMissing)
+
+	guard, err := newSignGuard(path, 0, false)
+	require.NoError(t, err)
+	require.NoError(t, guard.sign(5, 0, signStepCommit, types.StringToHash("1")))
+
+	_, err = newSignGuard(path, 6, true)
+	assert.ErrorIs(t, err, ErrSignStateBehind)
+
+	// the chain being behind isn't checked without the strict mode
+	_, err = newSignGuard(path, 6, false)
+	assert.NoError(t, err)
+}
@@ -15,11 +15,25 @@ import (
 // and implements methods of signing by this key
 type ECDSAKeyManager struct {
 	key     *ecdsa.PrivateKey
+	device  secrets.ECDSASigner // signs instead of the key when the key is kept on a device
 	address types.Address
 }
 
-// NewECDSAKeyManager initializes ECDSAKeyManager by the ECDSA key loaded from SecretsManager
+// NewECDSAKeyManager initializes ECDSAKeyManager by the ECDSA key loaded from SecretsManager,
+// or by the device of SecretsManager signing with the key it keeps
 func NewECDSAKeyManager(manager secrets.SecretsManager) (KeyManager, error) {
+	if device, ok := manager.(secrets.ECDSASigner); ok {
+		pub, err := getOrCreateDeviceECDSAKey(manager, device)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ECDSAKeyManager{
+			device:  device,
+			address: crypto.PubKeyToAddress(pub),
+		}, nil
+	}
+
 	key, err := getOrCreateECDSAKey(manager)
 	if err != nil {
 		return nil, err
@@ -58,12 +72,21 @@ func (s *ECDSAKeyManager) NewEmptyCommittedSeals() Seals {
 
 // SignProposerSeal signs the given message by ECDSA key the ECDSAKeyManager holds for ProposerSeal
 func (s *ECDSAKeyManager) SignProposerSeal(message []byte) ([]byte, error) {
-	return crypto.Sign(s.key, message)
+	return s.sign(message)
 }
 
 // SignProposerSeal signs the given message by ECDSA key the ECDSAKeyManager holds for committed seal
 func (s *ECDSAKeyManager) SignCommittedSeal(message []byte) ([]byte, error) {
-	return crypto.Sign(s.key, message)
+	return s.sign(message)
+}
+
+// sign signs the given hash by the key, or by the device keeping the key
+func (s *ECDSAKeyManager) sign(hash []byte) ([]byte, error) {
+	if s.device != nil {
+		return s.device.SignECDSA(secrets.ValidatorKey, hash)
+	}
+
+	return crypto.Sign(s.key, hash)
 }
 
 // VerifyCommittedSeal verifies a committed seal
@@ -130,7 +153,7 @@ func (s *ECDSAKeyManager) VerifyCommittedSeals(
 }
 
 func (s *ECDSAKeyManager) SignIBFTMessage(msg []byte) ([]byte, error) {
-	return crypto.Sign(s.key, msg)
+	return s.sign(msg)
 }
 
 func (s *ECDSAKeyManager) Ecrecover(sig, digest []byte) (types.Address, error) {
@@ -93,6 +93,60 @@ func TestNewECDSAKeyManager(t *testing.T) {
 		})
 	}
 }
+
+func TestNewECDSAKeyManager_Device(t *testing.T) {
+	t.Parallel()
+
+	testKey, _ := newTestECDSAKey(t)
+
+	generated := false
+	device := &MockDeviceSecretManager{
+		MockSecretManager: MockSecretManager{
+			HasSecretFn: func(name string) bool {
+				return generated
+			},
+			GetSecretFn: func(name string) ([]byte, error) {
+				return nil, secrets.ErrSecretNotExportable
+			},
+		},
+		GenerateECDSAKeyFn: func(name string) (*ecdsa.PublicKey, error) {
+			assert.Equal(t, secrets.ValidatorKey, name)
+
+			generated = true
+
+			return &testKey.PublicKey, nil
+		},
+		ECDSAPublicKeyFn: func(name string) (*ecdsa.PublicKey, error) {
+			return &testKey.PublicKey, nil
+		},
+		SignECDSAFn: func(name string, hash []byte) ([]byte, error) {
+			assert.Equal(t, secrets.ValidatorKey, name)
+
+			return crypto.Sign(testKey, hash)
+		},
+	}
+
+	// the key is generated on the device, and the seals are signed by it
+	ecdsaKeyManager, err := NewECDSAKeyManager(device)
+	assert.NoError(t, err)
+	assert.True(t, generated)
+	assert.Equal(t, crypto.PubKeyToAddress(&testKey.PublicKey), ecdsaKeyManager.Address())
+
+	msg := crypto.Keccak256([]byte("message"))
+
+	seal, err := ecdsaKeyManager.SignCommittedSeal(msg)
+	assert.NoError(t, err)
+
+	signer, err := ecdsaKeyManager.Ecrecover(seal, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, ecdsaKeyManager.Address(), signer)
+
+	// the key already on the device is loaded
+	ecdsaKeyManager, err = NewECDSAKeyManager(device)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubKeyToAddress(&testKey.PublicKey), ecdsaKeyManager.Address())
+}
+
 func TestNewECDSAKeyManagerFromKey(t *testing.T) {
 	t.Parallel()
 
@@ -28,6 +28,9 @@ type IstanbulExtra struct {
 	ProposerSeal         []byte
 	CommittedSeals       Seals
 	ParentCommittedSeals Seals
+	// AccessListHash is the hash of the accounts and slots accessed by the transactions,
+	// only set once the block access list fork is active
+	AccessListHash *types.Hash
 }
 
 type Seals interface {
@@ -62,6 +65,14 @@ func (i *IstanbulExtra) MarshalRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
 	// ParentCommittedSeal
 	if i.ParentCommittedSeals != nil {
 		vv.Set(i.ParentCommittedSeals.MarshalRLPWith(ar))
+	} else if i.AccessListHash != nil {
+		// keep the position of the access list hash in the first block, which has no parent committed seals
+		vv.Set(ar.NewNullArray())
+	}
+
+	// AccessListHash
+	if i.AccessListHash != nil {
+		vv.Set(ar.NewCopyBytes(i.AccessListHash.Bytes()))
 	}
 
 	return vv
@@ -105,6 +116,16 @@ func (i *IstanbulExtra) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) er
 		}
 	}
 
+	// AccessListHash
+	if len(elems) >= 5 {
+		hash := types.Hash{}
+		if err := elems[4].GetHash(hash[:]); err != nil {
+			return fmt.Errorf("failed to decode AccessListHash: %w", err)
+		}
+
+		i.AccessListHash = &hash
+	}
+
 	return nil
 }
 
@@ -162,24 +183,28 @@ func packFieldsIntoExtra(
 	extraHeader := extraBytes[:IstanbulExtraVanity]
 	extraBody := extraBytes[IstanbulExtraVanity:]
 
-	newExtraBody := types.MarshalRLPTo(func(ar *fastrlp.Arena) *fastrlp.Value {
-		vv := ar.NewArray()
+	var newExtraBody []byte
 
-		_ = types.UnmarshalRlp(func(p *fastrlp.Parser, v *fastrlp.Value) error {
-			elems, err := v.GetElems()
-			if err != nil {
-				return err
-			}
+	// the old values belong to the pooled parser, so the new extra is marshaled before the parser is released
+	_ = types.UnmarshalRlp(func(p *fastrlp.Parser, v *fastrlp.Value) error {
+		elems, err := v.GetElems()
+		if err != nil {
+			return err
+		}
 
-			if len(elems) < 3 {
-				return fmt.Errorf("incorrect number of elements to decode istambul extra, expected 3 but found %d", len(elems))
-			}
+		if len(elems) < 3 {
+			return fmt.Errorf("incorrect number of elements to decode istambul extra, expected 3 but found %d", len(elems))
+		}
+
+		newExtraBody = types.MarshalRLPTo(func(ar *fastrlp.Arena) *fastrlp.Value {
+			vv := ar.NewArray()
+			_ = packFn(ar, elems, vv)
 
-			return packFn(ar, elems, vv)
-		}, extraBody)
+			return vv
+		}, nil)
 
-		return vv
-	}, nil)
+		return nil
+	}, extraBody)
 
 	return append(
 		extraHeader,
@@ -202,15 +227,15 @@ func packProposerSealIntoExtra(
 			// Validators
 			newArrayValue.Set(oldValues[0])
 
-			// Seal
-			newArrayValue.Set(ar.NewBytes(proposerSeal))
+			// Seal, copied as the pooled arena may reuse the value and overwrite the seal of the caller
+			newArrayValue.Set(ar.NewCopyBytes(proposerSeal))
 
 			// CommittedSeal
 			newArrayValue.Set(oldValues[2])
 
-			// ParentCommittedSeal
-			if len(oldValues) >= 4 {
-				newArrayValue.Set(oldValues[3])
+			// ParentCommittedSeal and AccessListHash
+			for _, value := range oldValues[3:] {
+				newArrayValue.Set(value)
 			}
 
 			return nil
@@ -239,11 +264,43 @@ func packCommittedSealsIntoExtra(
 			// CommittedSeal
 			newArrayValue.Set(committedSeal.MarshalRLPWith(ar))
 
+			// ParentCommittedSeal and AccessListHash
+			for _, value := range oldValues[3:] {
+				newArrayValue.Set(value)
+			}
+
+			return nil
+		},
+	)
+}
+
+// PackAccessListHashIntoExtra updates only AccessListHash field in Extra
+func PackAccessListHashIntoExtra(
+	extraBytes []byte,
+	accessListHash types.Hash,
+) []byte {
+	return packFieldsIntoExtra(
+		extraBytes,
+		func(
+			ar *fastrlp.Arena,
+			oldValues []*fastrlp.Value,
+			newArrayValue *fastrlp.Value,
+		) error {
+			// Validators, Seal and CommittedSeal
+			for _, value := range oldValues[:3] {
+				newArrayValue.Set(value)
+			}
+
 			// ParentCommittedSeal
 			if len(oldValues) >= 4 {
 				newArrayValue.Set(oldValues[3])
+			} else {
+				newArrayValue.Set(ar.NewNullArray())
 			}
 
+			// AccessListHash
+			newArrayValue.Set(ar.NewCopyBytes(accessListHash.Bytes()))
+
 			return nil
 		},
 	)
@@ -503,3 +503,90 @@ func Test_putIbftExtra(t *testing.T) {
 		})
 	}
 }
+
+func TestPackAccessListHashIntoExtra(t *testing.T) {
+	t.Parallel()
+
+	accessListHash := types.StringToHash("1")
+
+	tests := []struct {
+		name  string
+		extra *IstanbulExtra
+	}{
+		{
+			name: "ECDSAExtra",
+			extra: &IstanbulExtra{
+				Validators: validators.NewECDSAValidatorSet(
+					ecdsaValidator1,
+				),
+				ProposerSeal: testProposerSeal,
+				CommittedSeals: &SerializedSeal{
+					[]byte{0x1},
+				},
+				ParentCommittedSeals: &SerializedSeal{
+					[]byte{0x3},
+				},
+			},
+		},
+		{
+			name: "ECDSAExtra without ParentCommittedSeals",
+			extra: &IstanbulExtra{
+				Validators: validators.NewECDSAValidatorSet(
+					ecdsaValidator1,
+				),
+				ProposerSeal: testProposerSeal,
+				CommittedSeals: &SerializedSeal{
+					[]byte{0x1},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// create expected data
+			test.extra.AccessListHash = &accessListHash
+			expectedJSON := JSONMarshalHelper(t, test.extra)
+			test.extra.AccessListHash = nil
+
+			extraBytes := PackAccessListHashIntoExtra(
+				// prepend IstanbulExtraHeader to parse
+				append(
+					make([]byte, IstanbulExtraVanity),
+					test.extra.MarshalRLPTo(nil)...,
+				),
+				accessListHash,
+			)
+
+			// the hash is kept once the seals are packed
+			extraBytes = packProposerSealIntoExtra(extraBytes, testProposerSeal)
+			extraBytes = packCommittedSealsIntoExtra(extraBytes, test.extra.CommittedSeals)
+
+			// decode into a new extra, the seals of the test extra are shared by the tests
+			extra := &IstanbulExtra{
+				Validators:     validators.NewECDSAValidatorSet(),
+				ProposerSeal:   []byte{},
+				CommittedSeals: &SerializedSeal{},
+			}
+
+			if test.extra.ParentCommittedSeals != nil {
+				extra.ParentCommittedSeals = &SerializedSeal{}
+			}
+
+			assert.NoError(
+				t,
+				extra.UnmarshalRLP(extraBytes[IstanbulExtraVanity:]),
+			)
+
+			assert.Equal(
+				t,
+				expectedJSON,
+				JSONMarshalHelper(t, extra),
+			)
+		})
+	}
+}
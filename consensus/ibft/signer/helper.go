@@ -43,6 +43,16 @@ func getOrCreateECDSAKey(manager secrets.SecretsManager) (*ecdsa.PrivateKey, err
 	return crypto.BytesToECDSAPrivateKey(keyBytes)
 }
 
+// getOrCreateDeviceECDSAKey loads the public key of the ECDSA key kept on the device,
+// or generates the key on the device
+func getOrCreateDeviceECDSAKey(manager secrets.SecretsManager, device secrets.ECDSASigner) (*ecdsa.PublicKey, error) {
+	if !manager.HasSecret(secrets.ValidatorKey) {
+		return device.GenerateECDSAKey(secrets.ValidatorKey)
+	}
+
+	return device.ECDSAPublicKey(secrets.ValidatorKey)
+}
+
 // getOrCreateECDSAKey loads BLS key or creates a new key
 func getOrCreateBLSKey(manager secrets.SecretsManager) (*bls_sig.SecretKey, error) {
 	if !manager.HasSecret(secrets.ValidatorBLSKey) {
@@ -109,6 +119,19 @@ func NewKeyManagerFromType(
 	}
 }
 
+// NewVerifierKeyManager creates KeyManager based on the given type without any key,
+// which only verifies the seals of the other validators
+func NewVerifierKeyManager(validatorType validators.ValidatorType) (KeyManager, error) {
+	switch validatorType {
+	case validators.ECDSAValidatorType:
+		return &ECDSAKeyManager{}, nil
+	case validators.BLSValidatorType:
+		return &BLSKeyManager{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported validator type: %s", validatorType)
+	}
+}
+
 // verifyIBFTExtraSize checks whether header.ExtraData has enough size for IBFT Extra
 func verifyIBFTExtraSize(header *types.Header) error {
 	if len(header.ExtraData) < IstanbulExtraVanity {
@@ -1,6 +1,8 @@
 package signer
 
 import (
+	"crypto/ecdsa"
+
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/validators"
@@ -27,6 +29,27 @@ func (m *MockSecretManager) SetSecret(name string, key []byte) error {
 	return m.SetSecretFn(name, key)
 }
 
+// MockDeviceSecretManager is a MockSecretManager keeping the ECDSA keys on a device
+type MockDeviceSecretManager struct {
+	MockSecretManager
+
+	GenerateECDSAKeyFn func(string) (*ecdsa.PublicKey, error)
+	ECDSAPublicKeyFn   func(string) (*ecdsa.PublicKey, error)
+	SignECDSAFn        func(string, []byte) ([]byte, error)
+}
+
+func (m *MockDeviceSecretManager) GenerateECDSAKey(name string) (*ecdsa.PublicKey, error) {
+	return m.GenerateECDSAKeyFn(name)
+}
+
+func (m *MockDeviceSecretManager) ECDSAPublicKey(name string) (*ecdsa.PublicKey, error) {
+	return m.ECDSAPublicKeyFn(name)
+}
+
+func (m *MockDeviceSecretManager) SignECDSA(name string, hash []byte) ([]byte, error) {
+	return m.SignECDSAFn(name, hash)
+}
+
 type MockKeyManager struct {
 	TypeFunc                   func() validators.ValidatorType
 	AddressFunc                func() types.Address
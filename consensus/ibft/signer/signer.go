@@ -254,6 +254,53 @@ func (s *SignerImpl) VerifyCommittedSeals(
 	return nil
 }
 
+// GetCommittedSealSigners returns the addresses of the validators whose CommittedSeals are in IBFT Extra
+// of the header. The seals are expected to be verified against the validators beforehand
+func (s *SignerImpl) GetCommittedSealSigners(
+	header *types.Header,
+	validators validators.Validators,
+) ([]types.Address, error) {
+	extra, err := s.GetIBFTExtra(header)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := make([]types.Address, 0, validators.Len())
+
+	switch seals := extra.CommittedSeals.(type) {
+	case *SerializedSeal:
+		hash, err := s.CalculateHeaderHash(header)
+		if err != nil {
+			return nil, err
+		}
+
+		msg := crypto.Keccak256(wrapCommitHash(hash[:]))
+
+		for _, seal := range *seals {
+			addr, err := ecrecover(seal, msg)
+			if err != nil {
+				return nil, err
+			}
+
+			signers = append(signers, addr)
+		}
+	case *AggregatedSeal:
+		if seals.Bitmap == nil {
+			return signers, nil
+		}
+
+		for idx := 0; idx < validators.Len(); idx++ {
+			if seals.Bitmap.Bit(idx) == 1 {
+				signers = append(signers, validators.At(uint64(idx)).Addr())
+			}
+		}
+	default:
+		return nil, ErrInvalidCommittedSealType
+	}
+
+	return signers, nil
+}
+
 // VerifyParentCommittedSeals verifies ParentCommittedSeals in IBFT Extra of the header
 func (s *SignerImpl) VerifyParentCommittedSeals(
 	parent, header *types.Header,
@@ -376,8 +423,14 @@ func (s *SignerImpl) filterHeaderForHash(header *types.Header) (*types.Header, e
 	}
 
 	// This will effectively remove the Seal and CommittedSeals from the IBFT Extra of header,
-	// while keeping proposer vanity, validator set, and ParentCommittedSeals
-	s.initIbftExtra(clone, extra.Validators, parentCommittedSeals)
+	// while keeping proposer vanity, validator set, ParentCommittedSeals and AccessListHash
+	putIbftExtra(clone, &IstanbulExtra{
+		Validators:           extra.Validators,
+		ProposerSeal:         []byte{},
+		CommittedSeals:       s.keyManager.NewEmptyCommittedSeals(),
+		ParentCommittedSeals: parentCommittedSeals,
+		AccessListHash:       extra.AccessListHash,
+	})
 
 	return clone, nil
 }
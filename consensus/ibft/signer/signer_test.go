@@ -1246,3 +1246,81 @@ func TestSignerSignIBFTMessageAndEcrecoverFromIBFTMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestSignerGetCommittedSealSigners(t *testing.T) {
+	t.Parallel()
+
+	for _, validatorType := range []validators.ValidatorType{
+		validators.ECDSAValidatorType,
+		validators.BLSValidatorType,
+	} {
+		validatorType := validatorType
+
+		t.Run(string(validatorType), func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				signers = make([]*SignerImpl, 4)
+				vals    = validators.NewValidatorSetFromType(validatorType)
+			)
+
+			for i := range signers {
+				ecdsaKey, err := crypto.GenerateECDSAKey()
+				assert.NoError(t, err)
+
+				addr := crypto.PubKeyToAddress(&ecdsaKey.PublicKey)
+
+				if validatorType == validators.ECDSAValidatorType {
+					signers[i] = NewSigner(NewECDSAKeyManagerFromKey(ecdsaKey), nil)
+
+					assert.NoError(t, vals.Add(validators.NewECDSAValidator(addr)))
+
+					continue
+				}
+
+				blsKey, err := crypto.GenerateBLSKey()
+				assert.NoError(t, err)
+
+				blsPubKey, err := crypto.BLSSecretKeyToPubkeyBytes(blsKey)
+				assert.NoError(t, err)
+
+				signers[i] = NewSigner(NewBLSKeyManagerFromKeys(ecdsaKey, blsKey), nil)
+
+				assert.NoError(t, vals.Add(validators.NewBLSValidator(addr, blsPubKey)))
+			}
+
+			header := &types.Header{Number: 1}
+			signers[0].InitIBFTExtra(header, vals, nil)
+
+			hash, err := signers[0].CalculateHeaderHash(header)
+			assert.NoError(t, err)
+
+			// the seals of the second and the last validators
+			sealMap := make(map[types.Address][]byte)
+
+			for _, idx := range []int{1, 3} {
+				seal, err := signers[idx].CreateCommittedSeal(hash.Bytes())
+				assert.NoError(t, err)
+
+				sealMap[signers[idx].Address()] = seal
+			}
+
+			header, err = signers[0].WriteCommittedSeals(header, sealMap)
+			assert.NoError(t, err)
+
+			// the seals are verified and their signers are returned without any key
+			keyManager, err := NewVerifierKeyManager(validatorType)
+			assert.NoError(t, err)
+
+			verifier := NewSigner(keyManager, nil)
+			assert.NoError(t, verifier.VerifyCommittedSeals(header, vals, 2))
+
+			addrs, err := verifier.GetCommittedSealSigners(header, vals)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []types.Address{signers[1].Address(), signers[3].Address()}, addrs)
+		})
+	}
+
+	_, err := NewVerifierKeyManager("unknown")
+	assert.Error(t, err)
+}
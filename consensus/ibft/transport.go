@@ -1,10 +1,20 @@
 package ibft
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/0xPolygon/go-ibft/messages/proto"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/libp2p/go-libp2p/core/peer"
+	rawProto "google.golang.org/protobuf/proto"
+)
+
+var (
+	errMalformedMessage = errors.New("malformed IBFT message")
+	errPayloadMismatch  = errors.New("message payload doesn't match its type")
+	errInvalidProposal  = errors.New("invalid proposal")
 )
 
 type transport interface {
@@ -20,6 +30,11 @@ func (g *gossipTransport) Multicast(msg *proto.Message) error {
 }
 
 func (i *backendIBFT) Multicast(msg *proto.Message) {
+	// the message isn't built if it can't be signed
+	if msg == nil {
+		return
+	}
+
 	if err := i.transport.Multicast(msg); err != nil {
 		i.logger.Error("fail to gossip", "err", err)
 	}
@@ -33,9 +48,12 @@ func (i *backendIBFT) setupTransport() error {
 		return err
 	}
 
+	// the malformed messages are rejected before they're relayed
+	topic.SetValidator(network.PenaltyInvalidBlock, validateMessage)
+
 	// Subscribe to the newly created topic
 	if err := topic.Subscribe(
-		func(obj interface{}, _ peer.ID) {
+		func(obj interface{}, from peer.ID) {
 			if !i.isActiveValidator() {
 				return
 			}
@@ -47,6 +65,7 @@ func (i *backendIBFT) setupTransport() error {
 				return
 			}
 
+			i.observeValidatorPeer(msg, from)
 			i.consensus.AddMessage(msg)
 
 			i.logger.Debug(
@@ -65,3 +84,77 @@ func (i *backendIBFT) setupTransport() error {
 
 	return nil
 }
+
+// validateMessage runs the checks of a gossiped message which don't depend on the state of the consensus,
+// so the malformed messages and the proposals which can never be valid aren't relayed to the other peers
+func validateMessage(obj rawProto.Message) error {
+	msg, ok := obj.(*proto.Message)
+	if !ok {
+		return errMalformedMessage
+	}
+
+	if msg.View == nil || len(msg.From) != types.AddressLength || len(msg.Signature) == 0 {
+		return errMalformedMessage
+	}
+
+	switch payload := msg.Payload.(type) {
+	case *proto.Message_PreprepareData:
+		if msg.Type != proto.MessageType_PREPREPARE || payload.PreprepareData == nil {
+			return errPayloadMismatch
+		}
+
+		if len(payload.PreprepareData.ProposalHash) != types.HashLength {
+			return errMalformedMessage
+		}
+
+		return validateProposal(payload.PreprepareData.Proposal, msg.View.Height)
+	case *proto.Message_PrepareData:
+		if msg.Type != proto.MessageType_PREPARE || payload.PrepareData == nil {
+			return errPayloadMismatch
+		}
+
+		if len(payload.PrepareData.ProposalHash) != types.HashLength {
+			return errMalformedMessage
+		}
+	case *proto.Message_CommitData:
+		if msg.Type != proto.MessageType_COMMIT || payload.CommitData == nil {
+			return errPayloadMismatch
+		}
+
+		if len(payload.CommitData.ProposalHash) != types.HashLength || len(payload.CommitData.CommittedSeal) == 0 {
+			return errMalformedMessage
+		}
+	case *proto.Message_RoundChangeData:
+		if msg.Type != proto.MessageType_ROUND_CHANGE || payload.RoundChangeData == nil {
+			return errPayloadMismatch
+		}
+
+		// the round change carries the proposal only if the sender has prepared one
+		if proposal := payload.RoundChangeData.LastPreparedProposedBlock; len(proposal) != 0 {
+			return validateProposal(proposal, msg.View.Height)
+		}
+	default:
+		return errPayloadMismatch
+	}
+
+	return nil
+}
+
+// validateProposal checks the proposal is a block at the given height which doesn't use more gas than its limit
+func validateProposal(proposal []byte, height uint64) error {
+	block := &types.Block{}
+	if err := block.UnmarshalRLP(proposal); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidProposal, err)
+	}
+
+	if block.Number() != height {
+		return fmt.Errorf("%w: block %d at height %d", errInvalidProposal, block.Number(), height)
+	}
+
+	if block.Header.GasUsed > block.Header.GasLimit {
+		return fmt.Errorf("%w: gas used %d above the limit %d",
+			errInvalidProposal, block.Header.GasUsed, block.Header.GasLimit)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMessage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from = types.StringToAddress("1").Bytes()
+		hash = types.StringToHash("1").Bytes()
+		view = &proto.View{Height: 10, Round: 1}
+	)
+
+	newProposal := func(number, gasLimit, gasUsed uint64) []byte {
+		return (&types.Block{
+			Header: &types.Header{Number: number, GasLimit: gasLimit, GasUsed: gasUsed},
+		}).MarshalRLP()
+	}
+
+	newPrePrepare := func(proposal []byte) *proto.Message {
+		return &proto.Message{
+			View:      view,
+			From:      from,
+			Signature: []byte{0x1},
+			Type:      proto.MessageType_PREPREPARE,
+			Payload: &proto.Message_PreprepareData{
+				PreprepareData: &proto.PrePrepareMessage{Proposal: proposal, ProposalHash: hash},
+			},
+		}
+	}
+
+	newCommit := func(seal []byte) *proto.Message {
+		return &proto.Message{
+			View:      view,
+			From:      from,
+			Signature: []byte{0x1},
+			Type:      proto.MessageType_COMMIT,
+			Payload: &proto.Message_CommitData{
+				CommitData: &proto.CommitMessage{ProposalHash: hash, CommittedSeal: seal},
+			},
+		}
+	}
+
+	newRoundChange := func(proposal []byte) *proto.Message {
+		return &proto.Message{
+			View:      view,
+			From:      from,
+			Signature: []byte{0x1},
+			Type:      proto.MessageType_ROUND_CHANGE,
+			Payload: &proto.Message_RoundChangeData{
+				RoundChangeData: &proto.RoundChangeMessage{LastPreparedProposedBlock: proposal},
+			},
+		}
+	}
+
+	mismatched := newCommit([]byte{0x1})
+	mismatched.Type = proto.MessageType_PREPARE
+
+	noView := newCommit([]byte{0x1})
+	noView.View = nil
+
+	tests := []struct {
+		name string
+		msg  *proto.Message
+		err  error
+	}{
+		{
+			name: "valid proposal",
+			msg:  newPrePrepare(newProposal(10, 100, 50)),
+		},
+		{
+			name: "valid commit",
+			msg:  newCommit([]byte{0x1}),
+		},
+		{
+			name: "round change without a prepared proposal",
+			msg:  newRoundChange(nil),
+		},
+		{
+			name: "undecodable proposal",
+			msg:  newPrePrepare([]byte{0x1, 0x2}),
+			err:  errInvalidProposal,
+		},
+		{
+			name: "proposal at another height",
+			msg:  newPrePrepare(newProposal(11, 100, 50)),
+			err:  errInvalidProposal,
+		},
+		{
+			name: "proposal using more gas than its limit",
+			msg:  newPrePrepare(newProposal(10, 100, 101)),
+			err:  errInvalidProposal,
+		},
+		{
+			name: "round change with an invalid proposal",
+			msg:  newRoundChange(newProposal(10, 100, 101)),
+			err:  errInvalidProposal,
+		},
+		{
+			name: "commit without a seal",
+			msg:  newCommit(nil),
+			err:  errMalformedMessage,
+		},
+		{
+			name: "message without a view",
+			msg:  noView,
+			err:  errMalformedMessage,
+		},
+		{
+			name: "payload not matching the type",
+			msg:  mismatched,
+			err:  errPayloadMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ErrorIs(t, validateMessage(test.msg), test.err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package ibft
+
+import (
+	"sync"
+
+	protoIBFT "github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// validatorPeers maps the active validators to the network peers publishing their messages.
+// The mapping is learned from the signed IBFT messages, as the validators don't announce their peer IDs
+type validatorPeers struct {
+	sync.RWMutex
+
+	peers map[types.Address]peer.ID
+}
+
+func newValidatorPeers() *validatorPeers {
+	return &validatorPeers{
+		peers: make(map[types.Address]peer.ID),
+	}
+}
+
+// get returns the peer of the validator, if known
+func (v *validatorPeers) get(addr types.Address) (peer.ID, bool) {
+	v.RLock()
+	defer v.RUnlock()
+
+	id, ok := v.peers[addr]
+
+	return id, ok
+}
+
+// set records the peer of the validator
+func (v *validatorPeers) set(addr types.Address, id peer.ID) {
+	v.Lock()
+	defer v.Unlock()
+
+	v.peers[addr] = id
+}
+
+// prune removes the peers of the addresses which are not in the validator set,
+// and returns the peers of the remaining validators
+func (v *validatorPeers) prune(vals validators.Validators) []peer.ID {
+	v.Lock()
+	defer v.Unlock()
+
+	ids := make([]peer.ID, 0, len(v.peers))
+
+	for addr, id := range v.peers {
+		if vals == nil || !vals.Includes(addr) {
+			delete(v.peers, addr)
+
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// observeValidatorPeer records the peer which published the validator message,
+// so the gossip mesh can prefer direct links to the other validators
+func (i *backendIBFT) observeValidatorPeer(msg *protoIBFT.Message, from peer.ID) {
+	if i.network == nil || i.validatorPeers == nil || from == "" || msg.View == nil {
+		return
+	}
+
+	addr := types.BytesToAddress(msg.From)
+
+	if id, ok := i.validatorPeers.get(addr); ok && id == from {
+		return
+	}
+
+	// only a message signed by the validator proves the peer belongs to it
+	if !i.IsValidSender(msg) {
+		return
+	}
+
+	i.validatorPeers.set(addr, from)
+	i.updateValidatorPeers()
+}
+
+// updateValidatorPeers passes the peers of the current validators to the networking layer
+func (i *backendIBFT) updateValidatorPeers() {
+	if i.network == nil || i.validatorPeers == nil {
+		return
+	}
+
+	i.network.SetValidatorPeers(i.validatorPeers.prune(i.currentValidators))
+}
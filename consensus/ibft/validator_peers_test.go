@@ -0,0 +1,35 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatorPeers_Prune(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr1 = types.StringToAddress("1")
+		addr2 = types.StringToAddress("2")
+		peers = newValidatorPeers()
+	)
+
+	peers.set(addr1, peer.ID("peer1"))
+	peers.set(addr2, peer.ID("peer2"))
+
+	ids := peers.prune(validators.NewECDSAValidatorSet(
+		validators.NewECDSAValidator(addr1),
+	))
+
+	assert.Equal(t, []peer.ID{"peer1"}, ids)
+
+	_, ok := peers.get(addr2)
+	assert.False(t, ok)
+
+	// all the peers are removed if there is no validator set
+	assert.Empty(t, peers.prune(nil))
+}
@@ -48,10 +48,13 @@ func OptimalQuorumSize(set validators.Validators) int {
 	return int(math.Ceil(2 * float64(set.Len()) / 3))
 }
 
+// CalcProposer returns the proposer of the round in the round robin order following the last proposer.
+// The validators in maintenance are skipped, unless all of them are
 func CalcProposer(
 	validators validators.Validators,
 	round uint64,
 	lastProposer types.Address,
+	maintenance map[types.Address]bool,
 ) validators.Validator {
 	var seed uint64
 
@@ -67,7 +70,14 @@ func CalcProposer(
 		seed = uint64(offset) + round + 1
 	}
 
-	pick := seed % uint64(validators.Len())
+	size := uint64(validators.Len())
+	pick := seed % size
+
+	for i := uint64(0); i < size; i++ {
+		if validator := validators.At((pick + i) % size); !maintenance[validator.Addr()] {
+			return validator
+		}
+	}
 
 	return validators.At(pick)
 }
@@ -0,0 +1,85 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+func TestCalcProposer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr1 = types.StringToAddress("1")
+		addr2 = types.StringToAddress("2")
+		addr3 = types.StringToAddress("3")
+		addr4 = types.StringToAddress("4")
+	)
+
+	vals := validators.NewECDSAValidatorSet(
+		validators.NewECDSAValidator(addr1),
+		validators.NewECDSAValidator(addr2),
+		validators.NewECDSAValidator(addr3),
+		validators.NewECDSAValidator(addr4),
+	)
+
+	tests := []struct {
+		name         string
+		round        uint64
+		lastProposer types.Address
+		maintenance  map[types.Address]bool
+		expected     types.Address
+	}{
+		{
+			name:     "first block",
+			round:    1,
+			expected: addr2,
+		},
+		{
+			name:         "next of the last proposer",
+			round:        0,
+			lastProposer: addr2,
+			expected:     addr3,
+		},
+		{
+			name:         "round change",
+			round:        2,
+			lastProposer: addr2,
+			expected:     addr1,
+		},
+		{
+			name:         "next in maintenance",
+			round:        0,
+			lastProposer: addr2,
+			maintenance:  map[types.Address]bool{addr3: true},
+			expected:     addr4,
+		},
+		{
+			name:         "wrapping around the validators in maintenance",
+			round:        1,
+			lastProposer: addr2,
+			maintenance:  map[types.Address]bool{addr4: true, addr1: true},
+			expected:     addr2,
+		},
+		{
+			name:         "all in maintenance",
+			round:        0,
+			lastProposer: addr2,
+			maintenance:  map[types.Address]bool{addr1: true, addr2: true, addr3: true, addr4: true},
+			expected:     addr3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, CalcProposer(vals, tt.round, tt.lastProposer, tt.maintenance).Addr())
+		})
+	}
+}
@@ -129,6 +129,7 @@ func (i *backendIBFT) IsProposer(id []byte, height, round uint64) bool {
 		i.currentValidators,
 		round,
 		previousProposer,
+		i.currentMaintenance,
 	)
 
 	return types.BytesToAddress(id) == nextProposer.Addr()
@@ -1,6 +1,7 @@
 package consensus
 
 import (
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/types/buildroot"
 )
@@ -38,3 +39,77 @@ func BuildBlock(params BuildBlockParams) *types.Block {
 		Transactions: txs,
 	}
 }
+
+// TxPool is the pool the transactions of the blocks are packed from
+type TxPool interface {
+	Prepare()
+	Peek() *types.Transaction
+	Pop(tx *types.Transaction)
+	Drop(tx *types.Transaction)
+	Demote(tx *types.Transaction)
+}
+
+// Transition is the state transition the transactions of the blocks are written to
+type Transition interface {
+	Write(txn *types.Transaction) error
+	MatchKnownAccounts(txn *types.Transaction) bool
+}
+
+// WriteTransactionsParams are the limits of the block passed into the WriteTransactions helper method
+type WriteTransactionsParams struct {
+	Number   uint64
+	GasLimit uint64
+	// MaxSize is the highest size of the transactions of the block, unlimited if 0
+	MaxSize uint64
+	// MaxTxs is the highest number of transactions of the block, unlimited if 0
+	MaxTxs int
+}
+
+// WriteTransactions is a utility function that writes the transactions of the pool to the transition
+// until the block is full. The transactions written are popped from the pool, the ones which
+// can't be included anymore are dropped. It returns the transactions written
+func WriteTransactions(pool TxPool, transition Transition, params WriteTransactionsParams) []*types.Transaction {
+	var (
+		successful []*types.Transaction
+		blockSize  uint64
+	)
+
+	pool.Prepare()
+
+	for params.MaxTxs == 0 || len(successful) < params.MaxTxs {
+		tx := pool.Peek()
+		if tx == nil {
+			break
+		}
+
+		if tx.ExceedsBlockGasLimit(params.GasLimit) || (params.MaxSize != 0 && tx.Size() > params.MaxSize) ||
+			tx.IsExpired(params.Number) || !transition.MatchKnownAccounts(tx) {
+			pool.Drop(tx)
+
+			continue
+		}
+
+		if params.MaxSize != 0 && blockSize+tx.Size() > params.MaxSize {
+			break
+		}
+
+		if err := transition.Write(tx); err != nil {
+			if _, ok := err.(*state.GasLimitReachedTransitionApplicationError); ok { //nolint:errorlint
+				break
+			} else if appErr, ok := err.(*state.TransitionApplicationError); ok && appErr.IsRecoverable { //nolint:errorlint
+				pool.Demote(tx)
+			} else {
+				pool.Drop(tx)
+			}
+
+			continue
+		}
+
+		pool.Pop(tx)
+
+		successful = append(successful, tx)
+		blockSize += tx.Size()
+	}
+
+	return successful
+}
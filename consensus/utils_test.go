@@ -0,0 +1,112 @@
+package consensus
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// mockTxPool serves the pending transactions in order, and records what happens to them
+type mockTxPool struct {
+	pending []*types.Transaction
+
+	popped, dropped, demoted []uint64
+}
+
+func (m *mockTxPool) Prepare() {}
+
+func (m *mockTxPool) Peek() *types.Transaction {
+	if len(m.pending) == 0 {
+		return nil
+	}
+
+	return m.pending[0]
+}
+
+func (m *mockTxPool) Pop(tx *types.Transaction) {
+	m.pending = m.pending[1:]
+	m.popped = append(m.popped, tx.Nonce)
+}
+
+func (m *mockTxPool) Drop(tx *types.Transaction) {
+	m.pending = m.pending[1:]
+	m.dropped = append(m.dropped, tx.Nonce)
+}
+
+func (m *mockTxPool) Demote(tx *types.Transaction) {
+	m.pending = m.pending[1:]
+	m.demoted = append(m.demoted, tx.Nonce)
+}
+
+// mockTransition fails to write the transactions with the errors set for their nonces
+type mockTransition struct {
+	errs map[uint64]error
+}
+
+func (m *mockTransition) Write(tx *types.Transaction) error {
+	return m.errs[tx.Nonce]
+}
+
+func (m *mockTransition) MatchKnownAccounts(*types.Transaction) bool {
+	return true
+}
+
+func TestWriteTransactions(t *testing.T) {
+	t.Parallel()
+
+	newTxs := func(count int) []*types.Transaction {
+		txs := make([]*types.Transaction, count)
+		for i := range txs {
+			txs[i] = &types.Transaction{Nonce: uint64(i), Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(0)}
+		}
+
+		return txs
+	}
+
+	t.Run("the transactions are written up to the limit", func(t *testing.T) {
+		t.Parallel()
+
+		pool := &mockTxPool{pending: newTxs(3)}
+
+		txs := WriteTransactions(pool, &mockTransition{}, WriteTransactionsParams{GasLimit: 100000, MaxTxs: 2})
+		assert.Len(t, txs, 2)
+		assert.Equal(t, []uint64{0, 1}, pool.popped)
+		assert.Len(t, pool.pending, 1)
+	})
+
+	t.Run("the failed transactions leave the pool", func(t *testing.T) {
+		t.Parallel()
+
+		pool := &mockTxPool{pending: newTxs(4)}
+		pool.pending[0].Gas = 200000
+
+		transition := &mockTransition{errs: map[uint64]error{
+			1: state.NewTransitionApplicationError(errors.New("nonce too low"), true),
+			2: state.NewTransitionApplicationError(errors.New("invalid"), false),
+		}}
+
+		txs := WriteTransactions(pool, transition, WriteTransactionsParams{GasLimit: 100000})
+		assert.Len(t, txs, 1)
+		assert.Equal(t, []uint64{3}, pool.popped)
+		assert.Equal(t, []uint64{1}, pool.demoted)
+		assert.Equal(t, []uint64{0, 2}, pool.dropped)
+	})
+
+	t.Run("the block is full", func(t *testing.T) {
+		t.Parallel()
+
+		pool := &mockTxPool{pending: newTxs(2)}
+		transition := &mockTransition{errs: map[uint64]error{
+			1: state.NewGasLimitReachedTransitionApplicationError(errors.New("gas limit reached")),
+		}}
+
+		txs := WriteTransactions(pool, transition, WriteTransactionsParams{GasLimit: 100000})
+		assert.Len(t, txs, 1)
+		assert.Len(t, pool.pending, 1)
+	})
+}
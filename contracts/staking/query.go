@@ -97,7 +97,7 @@ func DecodeValidators(method *abi.Method, returnValue []byte) ([]types.Address,
 }
 
 // QueryValidators is a helper function to get validator addresses from contract
-func QueryValidators(t TxQueryHandler, from types.Address) ([]types.Address, error) {
+func QueryValidators(t TxQueryHandler, contract, from types.Address) ([]types.Address, error) {
 	method, ok := abis.StakingABI.Methods[methodValidators]
 	if !ok {
 		return nil, ErrMethodNotFoundInABI
@@ -105,7 +105,7 @@ func QueryValidators(t TxQueryHandler, from types.Address) ([]types.Address, err
 
 	res, err := t.Apply(createCallViewTx(
 		from,
-		AddrStakingContract,
+		contract,
 		method.ID(),
 		t.GetNonce(from),
 	))
@@ -140,7 +140,7 @@ func decodeBLSPublicKeys(
 }
 
 // QueryBLSPublicKeys is a helper function to get BLS Public Keys from contract
-func QueryBLSPublicKeys(t TxQueryHandler, from types.Address) ([][]byte, error) {
+func QueryBLSPublicKeys(t TxQueryHandler, contract, from types.Address) ([][]byte, error) {
 	method, ok := abis.StakingABI.Methods[methodValidatorBLSPublicKeys]
 	if !ok {
 		return nil, ErrMethodNotFoundInABI
@@ -148,7 +148,7 @@ func QueryBLSPublicKeys(t TxQueryHandler, from types.Address) ([][]byte, error)
 
 	res, err := t.Apply(createCallViewTx(
 		from,
-		AddrStakingContract,
+		contract,
 		method.ID(),
 		t.GetNonce(from),
 	))
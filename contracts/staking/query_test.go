@@ -210,7 +210,7 @@ func TestQueryValidators(t *testing.T) {
 				},
 			}
 
-			res, err := QueryValidators(mock, tt.from)
+			res, err := QueryValidators(mock, AddrStakingContract, tt.from)
 			if tt.succeed {
 				assert.NoError(t, err)
 			} else {
@@ -139,6 +139,25 @@ func MarshalPublicKey(pub *ecdsa.PublicKey) []byte {
 	return elliptic.Marshal(S256, pub.X, pub.Y)
 }
 
+// ParseCompressedPublicKey parses the 33 bytes compressed form of a public key on the secp256k1 elliptic curve.
+func ParseCompressedPublicKey(buf []byte) (*ecdsa.PublicKey, error) {
+	if len(buf) != btcec.PubKeyBytesLenCompressed {
+		return nil, fmt.Errorf("invalid compressed public key length %d", len(buf))
+	}
+
+	pub, err := btcec.ParsePubKey(buf, S256)
+	if err != nil {
+		return nil, err
+	}
+
+	return pub.ToECDSA(), nil
+}
+
+// MarshalCompressedPublicKey marshals a public key on the secp256k1 elliptic curve into its 33 bytes compressed form.
+func MarshalCompressedPublicKey(pub *ecdsa.PublicKey) []byte {
+	return (*btcec.PublicKey)(pub).SerializeCompressed()
+}
+
 func Ecrecover(hash, sig []byte) ([]byte, error) {
 	pub, err := RecoverPubkey(sig, hash)
 	if err != nil {
@@ -33,6 +33,15 @@ func TestKeyEncoding(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, priv.PublicKey, *pub0)
+
+		// marshall compressed public key
+		buf = MarshalCompressedPublicKey(&priv.PublicKey)
+		assert.Len(t, buf, 33)
+
+		pub1, err := ParseCompressedPublicKey(buf)
+		assert.NoError(t, err)
+
+		assert.Equal(t, priv.PublicKey, *pub1)
 	}
 }
 
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"math/bits"
@@ -12,6 +13,10 @@ import (
 	"github.com/umbracle/fastrlp"
 )
 
+var (
+	ErrInvalidChainID = errors.New("invalid chain id for signer")
+)
+
 // TxSigner is a utility interface used to recover data from a transaction
 type TxSigner interface {
 	// Hash returns the hash of the transaction
@@ -47,6 +52,10 @@ var signerPool fastrlp.ArenaPool
 
 // calcTxHash calculates the transaction hash (keccak256 hash of the RLP value)
 func calcTxHash(tx *types.Transaction, chainID uint64) types.Hash {
+	if tx.IsTyped() {
+		return calcTypedTxHash(tx)
+	}
+
 	a := signerPool.Get()
 
 	v := a.NewArray()
@@ -77,6 +86,42 @@ func calcTxHash(tx *types.Transaction, chainID uint64) types.Hash {
 	return types.BytesToHash(hash)
 }
 
+// calcTypedTxHash calculates the hash signed by the sender of an access list transaction (EIP-2930)
+// or a sponsored transaction, which commits to the type and the chain ID of the transaction
+func calcTypedTxHash(tx *types.Transaction) types.Hash {
+	a := signerPool.Get()
+
+	v := a.NewArray()
+	v.Set(a.NewBigInt(tx.ChainID))
+	v.Set(a.NewUint(tx.Nonce))
+	v.Set(a.NewBigInt(tx.GasPrice))
+	v.Set(a.NewUint(tx.Gas))
+
+	if tx.To == nil {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewCopyBytes((*tx.To).Bytes()))
+	}
+
+	v.Set(a.NewBigInt(tx.Value))
+	v.Set(a.NewCopyBytes(tx.Input))
+	v.Set(tx.AccessList.MarshalRLPWith(a))
+
+	if tx.Type == types.SponsoredTx {
+		if tx.Sponsor != nil {
+			v.Set(a.NewCopyBytes(tx.Sponsor.Bytes()))
+		} else {
+			v.Set(a.NewNull())
+		}
+	}
+
+	hash := keccak.Keccak256(nil, v.MarshalTo([]byte{byte(tx.Type)}))
+
+	signerPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
 // Hash is a wrapper function for the calcTxHash, with chainID 0
 func (f *FrontierSigner) Hash(tx *types.Transaction) types.Hash {
 	return calcTxHash(tx, 0)
@@ -90,6 +135,10 @@ var (
 
 // Sender decodes the signature and returns the sender of the transaction
 func (f *FrontierSigner) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.IsTyped() {
+		return types.Address{}, types.ErrTxTypeNotSupported
+	}
+
 	refV := big.NewInt(0)
 	if tx.V != nil {
 		refV.SetBytes(tx.V.Bytes())
@@ -117,6 +166,10 @@ func (f *FrontierSigner) SignTx(
 	tx *types.Transaction,
 	privateKey *ecdsa.PrivateKey,
 ) (*types.Transaction, error) {
+	if tx.IsTyped() {
+		return nil, types.ErrTxTypeNotSupported
+	}
+
 	tx = tx.Copy()
 
 	h := f.Hash(tx)
@@ -157,6 +210,10 @@ func (e *EIP155Signer) Hash(tx *types.Transaction) types.Hash {
 
 // Sender returns the transaction sender
 func (e *EIP155Signer) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.IsTyped() {
+		return e.typedSender(tx)
+	}
+
 	protected := true
 
 	// Check if v value conforms to an earlier standard (before EIP155)
@@ -194,6 +251,35 @@ func (e *EIP155Signer) Sender(tx *types.Transaction) (types.Address, error) {
 	return types.BytesToAddress(buf), nil
 }
 
+// typedSender returns the sender of a typed transaction, whose V value is the signature parity
+func (e *EIP155Signer) typedSender(tx *types.Transaction) (types.Address, error) {
+	if tx.Type != types.AccessListTx && tx.Type != types.SponsoredTx {
+		return types.Address{}, types.ErrTxTypeNotSupported
+	}
+
+	if tx.ChainID == nil || !tx.ChainID.IsUint64() || tx.ChainID.Uint64() != e.chainID {
+		return types.Address{}, ErrInvalidChainID
+	}
+
+	if tx.V == nil || !tx.V.IsUint64() || tx.V.Uint64() > 1 {
+		return types.Address{}, fmt.Errorf("invalid txn signature")
+	}
+
+	sig, err := encodeSignature(tx.R, tx.S, byte(tx.V.Uint64()))
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	pub, err := Ecrecover(e.Hash(tx).Bytes(), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	buf := Keccak256(pub[1:])[12:]
+
+	return types.BytesToAddress(buf), nil
+}
+
 // SignTx signs the transaction using the passed in private key
 func (e *EIP155Signer) SignTx(
 	tx *types.Transaction,
@@ -201,6 +287,10 @@ func (e *EIP155Signer) SignTx(
 ) (*types.Transaction, error) {
 	tx = tx.Copy()
 
+	if tx.IsTyped() {
+		tx.ChainID = new(big.Int).SetUint64(e.chainID)
+	}
+
 	h := e.Hash(tx)
 
 	sig, err := Sign(privateKey, h[:])
@@ -210,7 +300,13 @@ func (e *EIP155Signer) SignTx(
 
 	tx.R = new(big.Int).SetBytes(sig[:32])
 	tx.S = new(big.Int).SetBytes(sig[32:64])
-	tx.V = new(big.Int).SetBytes(e.CalculateV(sig[64]))
+
+	if tx.IsTyped() {
+		// typed transactions carry the chain ID on their own, V is only the parity
+		tx.V = new(big.Int).SetBytes([]byte{sig[64]})
+	} else {
+		tx.V = new(big.Int).SetBytes(e.CalculateV(sig[64]))
+	}
 
 	return tx, nil
 }
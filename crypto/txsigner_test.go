@@ -143,3 +143,94 @@ func TestEIP155Signer_ChainIDMismatch(t *testing.T) {
 		}
 	}
 }
+
+func TestEIP155Signer_AccessListTx(t *testing.T) {
+	t.Parallel()
+
+	toAddress := types.StringToAddress("1")
+	key, err := GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	txn := &types.Transaction{
+		Type:     types.AccessListTx,
+		To:       &toAddress,
+		Value:    big.NewInt(10),
+		GasPrice: big.NewInt(1),
+		Gas:      30000,
+		AccessList: types.TxAccessList{
+			{
+				Address:     toAddress,
+				StorageKeys: []types.Hash{types.StringToHash("1")},
+			},
+		},
+	}
+
+	signer := NewEIP155Signer(100)
+
+	signedTx, err := signer.SignTx(txn, key)
+	assert.NoError(t, err)
+
+	// the chain ID is set on the transaction, and V is the signature parity
+	assert.Equal(t, big.NewInt(100), signedTx.ChainID)
+	assert.True(t, signedTx.V.Uint64() <= 1)
+
+	// the sender is recovered from the transaction decoded from its binary encoding
+	decodedTx := &types.Transaction{}
+	assert.NoError(t, decodedTx.UnmarshalRLP(signedTx.MarshalRLP()))
+
+	from, err := signer.Sender(decodedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the access list is covered by the signature
+	decodedTx.AccessList[0].StorageKeys[0] = types.StringToHash("2")
+
+	from, err = signer.Sender(decodedTx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the transaction can't be replayed on another chain
+	_, err = NewEIP155Signer(101).Sender(signedTx)
+	assert.ErrorIs(t, err, ErrInvalidChainID)
+
+	_, err = (&FrontierSigner{}).Sender(signedTx)
+	assert.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+}
+
+func TestEIP155Signer_SponsoredTx(t *testing.T) {
+	t.Parallel()
+
+	toAddress := types.StringToAddress("1")
+	sponsor := types.StringToAddress("2")
+	key, err := GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	txn := &types.Transaction{
+		Type:     types.SponsoredTx,
+		To:       &toAddress,
+		Value:    big.NewInt(10),
+		GasPrice: big.NewInt(1),
+		Gas:      30000,
+		Sponsor:  &sponsor,
+	}
+
+	signer := NewEIP155Signer(100)
+
+	signedTx, err := signer.SignTx(txn, key)
+	assert.NoError(t, err)
+
+	decodedTx := &types.Transaction{}
+	assert.NoError(t, decodedTx.UnmarshalRLP(signedTx.MarshalRLP()))
+
+	from, err := signer.Sender(decodedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the sponsor is covered by the signature
+	otherSponsor := types.StringToAddress("3")
+	decodedTx.Sponsor = &otherSponsor
+
+	from, err = signer.Sender(decodedTx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, PubKeyToAddress(&key.PublicKey), from)
+}
@@ -8,7 +8,7 @@ case "$1" in
 
    "init")
       echo "Generating secrets..."
-      secrets=$("$POLYGON_EDGE_BIN" secrets init --num 4 --data-dir data- --json)
+      secrets=$("$POLYGON_EDGE_BIN" secrets init --num 4 --data-dir data- --insecure --json)
       echo "Secrets have been successfully generated"
 
       echo "Generating genesis file..."
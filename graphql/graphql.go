@@ -0,0 +1,156 @@
+package graphql
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/hashicorp/go-hclog"
+)
+
+// Backend defines the methods required by the GraphQL resolvers,
+// they are a subset of the JSON-RPC store so that both APIs are served by the same backend
+type Backend interface {
+	// Header returns the current header of the chain (genesis if empty)
+	Header() *types.Header
+
+	// GetHeaderByNumber returns the header by number
+	GetHeaderByNumber(block uint64) (*types.Header, bool)
+
+	// GetBlockByHash gets a block using the provided hash
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+
+	// GetBlockByNumber returns a block using the provided number
+	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// IterateHeaders calls the handler for every canonical header in the range, without loading the block bodies
+	IterateHeaders(from, to uint64, handler func(*types.Header) bool) error
+
+	// ReadTxLookup returns a block hash in which a given txn was mined
+	ReadTxLookup(txnHash types.Hash) (types.Hash, bool)
+
+	// GetReceiptsByHash returns the receipts for a block hash
+	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
+
+	// GetAvgGasPrice returns the average gas price
+	GetAvgGasPrice() *big.Int
+
+	// ApplyTxn applies a transaction object to the blockchain, on top of the replaced state of the accounts
+	ApplyTxn(
+		header *types.Header,
+		txn *types.Transaction,
+		override state.StateOverride,
+	) (*runtime.ExecutionResult, error)
+
+	// GetSyncProgression retrieves the current sync progression, if any
+	GetSyncProgression() *progress.Progression
+
+	GetAccount(root types.Hash, addr types.Address) (*state.Account, error)
+	GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error)
+	GetCode(hash types.Hash) ([]byte, error)
+
+	// AddTx adds a new transaction to the tx pool
+	AddTx(tx *types.Transaction) error
+
+	// GetPendingTx gets the pending transaction from the transaction pool, if it's present
+	GetPendingTx(txHash types.Hash) (*types.Transaction, bool)
+
+	// GetTxs gets tx pool transactions currently pending for inclusion and currently queued for validation
+	GetTxs(inclQueued bool) (map[types.Address][]*types.Transaction, map[types.Address][]*types.Transaction)
+}
+
+type Config struct {
+	Backend         Backend
+	ChainID         uint64
+	PriceLimit      uint64
+	BlockRangeLimit uint64
+}
+
+// Handler serves the GraphQL queries over HTTP
+type Handler struct {
+	logger hclog.Logger
+	schema *graphql.Schema
+}
+
+// request is the body of a GraphQL query
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewHandler returns the handler serving the GraphQL queries
+func NewHandler(logger hclog.Logger, config *Config) (*Handler, error) {
+	s, err := graphql.ParseSchema(
+		schema,
+		&Resolver{
+			backend:         config.Backend,
+			chainID:         config.ChainID,
+			priceLimit:      config.PriceLimit,
+			blockRangeLimit: config.BlockRangeLimit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		logger: logger.Named("graphql"),
+		schema: s,
+	}, nil
+}
+
+// ServeHTTP handles the queries sent in the body of a POST request,
+// or in the query parameters of a GET request
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := &request{}
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	case http.MethodOptions:
+		return
+	default:
+		http.Error(w, "method "+r.Method+" not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	resp := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		h.logger.Error("failed to encode the response", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if len(resp.Errors) > 0 && resp.Data == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	_, _ = w.Write(body)
+}
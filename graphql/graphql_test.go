@@ -0,0 +1,202 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/chaintest"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func newTestHandler(t *testing.T) (*Handler, *chaintest.Backend, types.Address, types.Hash) {
+	t.Helper()
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	sender := crypto.PubKeyToAddress(&key.PublicKey)
+
+	backend, err := chaintest.NewBackend(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1e18)},
+	}, 0)
+	require.NoError(t, err)
+
+	recipient := types.StringToAddress("abcd")
+
+	tx, err := backend.Signer().SignTx(&types.Transaction{
+		To:       &recipient,
+		Value:    big.NewInt(1000),
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	}, key)
+	require.NoError(t, err)
+
+	tx.ComputeHash()
+	require.NoError(t, backend.AddTx(tx))
+
+	handler, err := NewHandler(hclog.NewNullLogger(), &Config{
+		Backend: backend,
+		ChainID: chaintest.ChainID,
+	})
+	require.NoError(t, err)
+
+	return handler, backend, sender, tx.Hash
+}
+
+func query(t *testing.T, handler http.Handler, q string, variables map[string]interface{}) *response {
+	t.Helper()
+
+	body, err := json.Marshal(&request{Query: q, Variables: variables})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+
+	resp := &response{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), resp))
+
+	return resp
+}
+
+func TestGraphQL_Block(t *testing.T) {
+	t.Parallel()
+
+	handler, backend, sender, txHash := newTestHandler(t)
+
+	resp := query(t, handler, `{
+		block {
+			number
+			hash
+			gasUsed
+			parent { number }
+			transactions { hash status gasUsed from { address balance transactionCount } }
+			account(address: "0x000000000000000000000000000000000000abcd") { balance }
+		}
+		chainID
+	}`, nil)
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		Block struct {
+			Number       int64
+			Hash         string
+			GasUsed      int64
+			Parent       struct{ Number int64 }
+			Transactions []struct {
+				Hash    string
+				Status  int64
+				GasUsed int64
+				From    struct {
+					Address          string
+					Balance          string
+					TransactionCount int64
+				}
+			}
+			Account struct{ Balance string }
+		}
+		ChainID string
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+
+	head := backend.Header()
+
+	assert.Equal(t, int64(1), data.Block.Number)
+	assert.Equal(t, head.Hash.String(), data.Block.Hash)
+	assert.Equal(t, int64(21000), data.Block.GasUsed)
+	assert.Equal(t, int64(0), data.Block.Parent.Number)
+	require.Len(t, data.Block.Transactions, 1)
+
+	tx := data.Block.Transactions[0]
+	assert.Equal(t, txHash.String(), tx.Hash)
+	assert.Equal(t, int64(1), tx.Status)
+	assert.Equal(t, int64(21000), tx.GasUsed)
+	assert.Equal(t, sender.String(), tx.From.Address)
+	assert.Equal(t, int64(1), tx.From.TransactionCount)
+	assert.Equal(t, hex.EncodeBig(big.NewInt(1e18-1000-21000)), tx.From.Balance)
+	assert.Equal(t, "0x3e8", data.Block.Account.Balance)
+	assert.Equal(t, hex.EncodeUint64(chaintest.ChainID), data.ChainID)
+}
+
+func TestGraphQL_TransactionAndVariables(t *testing.T) {
+	t.Parallel()
+
+	handler, _, _, txHash := newTestHandler(t)
+
+	q := `query($hash: Bytes32!) { transaction(hash: $hash) { index block { number } to { address } } }`
+
+	resp := query(t, handler, q, map[string]interface{}{"hash": txHash.String()})
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		Transaction struct {
+			Index int32
+			Block struct{ Number int64 }
+			To    struct{ Address string }
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+
+	assert.Equal(t, int32(0), data.Transaction.Index)
+	assert.Equal(t, int64(1), data.Transaction.Block.Number)
+	assert.Equal(t, types.StringToAddress("abcd").String(), data.Transaction.To.Address)
+
+	// the unknown transactions are null
+	resp = query(t, handler, q, map[string]interface{}{"hash": types.StringToHash("1").String()})
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"transaction": null}`, string(resp.Data))
+
+	// the queries are also served over GET
+	rec := httptest.NewRecorder()
+	target := "/graphql?query=" + url.QueryEscape("{ block { number } }")
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	assert.JSONEq(t, `{"data": {"block": {"number": 1}}}`, rec.Body.String())
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	t.Parallel()
+
+	handler, _, _, _ := newTestHandler(t)
+
+	resp := query(t, handler, `query($hash: Bytes32) { block(number: 1, hash: $hash) { number } }`, map[string]interface{}{
+		"hash": types.ZeroHash.String(),
+	})
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, ErrAmbiguousBlock.Error())
+
+	resp = query(t, handler, `{ block { unknownField } }`, nil)
+	assert.NotEmpty(t, resp.Errors)
+
+	// the raw transactions are decoded before they are sent to the pool
+	resp = query(t, handler, `mutation { sendRawTransaction(data: "0x01") }`, nil)
+	assert.NotEmpty(t, resp.Errors)
+}
+
+func TestGraphQL_EstimateGas(t *testing.T) {
+	t.Parallel()
+
+	handler, _, sender, _ := newTestHandler(t)
+
+	resp := query(t, handler, `query($from: Address) {
+		pending { estimateGas(data: { from: $from, to: "0x000000000000000000000000000000000000abcd", value: "1" }) }
+	}`, map[string]interface{}{"from": sender.String()})
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"pending": {"estimateGas": 21000}}`, string(resp.Data))
+}
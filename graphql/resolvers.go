@@ -0,0 +1,993 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrBlockNotFound         = errors.New("block not found")
+	ErrAmbiguousBlock        = errors.New("only one of the block number and hash can be given")
+	ErrReceiptNotFound       = errors.New("receipt not found")
+	ErrGasEstimationFailed   = errors.New("transaction fails at the highest gas limit")
+	ErrNegativeBlockNumber   = errors.New("block number must not be negative")
+	ErrTransactionIndexRange = errors.New("transaction index out of range")
+)
+
+// Resolver is the root resolver of the queries and mutations
+type Resolver struct {
+	backend         Backend
+	chainID         uint64
+	priceLimit      uint64
+	blockRangeLimit uint64
+}
+
+// headerAt returns the header at the number, or at the head of the chain if no number is given
+func (r *Resolver) headerAt(number *Long) (*types.Header, error) {
+	if number == nil {
+		return r.backend.Header(), nil
+	}
+
+	if *number < 0 {
+		return nil, ErrNegativeBlockNumber
+	}
+
+	header, ok := r.backend.GetHeaderByNumber(uint64(*number))
+	if !ok {
+		return nil, ErrBlockNotFound
+	}
+
+	return header, nil
+}
+
+func (r *Resolver) blockByNumber(number uint64) *Block {
+	block, ok := r.backend.GetBlockByNumber(number, true)
+	if !ok {
+		return nil
+	}
+
+	return &Block{r: r, block: block}
+}
+
+func (r *Resolver) Block(args struct {
+	Number *Long
+	Hash   *Bytes32
+}) (*Block, error) {
+	if args.Number != nil && args.Hash != nil {
+		return nil, ErrAmbiguousBlock
+	}
+
+	if args.Hash != nil {
+		block, ok := r.backend.GetBlockByHash(types.Hash(*args.Hash), true)
+		if !ok {
+			return nil, nil
+		}
+
+		return &Block{r: r, block: block}, nil
+	}
+
+	number := r.backend.Header().Number
+
+	if args.Number != nil {
+		if *args.Number < 0 {
+			return nil, ErrNegativeBlockNumber
+		}
+
+		number = uint64(*args.Number)
+	}
+
+	return r.blockByNumber(number), nil
+}
+
+func (r *Resolver) Blocks(args struct {
+	From *Long
+	To   *Long
+}) ([]*Block, error) {
+	var from, to uint64
+
+	if args.From != nil {
+		if *args.From < 0 {
+			return nil, ErrNegativeBlockNumber
+		}
+
+		from = uint64(*args.From)
+	}
+
+	head := r.backend.Header().Number
+
+	to = head
+	if args.To != nil {
+		if *args.To < 0 {
+			return nil, ErrNegativeBlockNumber
+		}
+
+		to = common.Min(uint64(*args.To), head)
+	}
+
+	if to < from {
+		return []*Block{}, nil
+	}
+
+	if r.blockRangeLimit != 0 && to-from > r.blockRangeLimit {
+		return nil, jsonrpc.ErrBlockRangeTooHigh
+	}
+
+	blocks := make([]*Block, 0, to-from+1)
+
+	for number := from; number <= to; number++ {
+		block := r.blockByNumber(number)
+		if block == nil {
+			break
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+func (r *Resolver) Pending() *Pending {
+	return &Pending{r: r}
+}
+
+func (r *Resolver) Transaction(args struct{ Hash Bytes32 }) *Transaction {
+	hash := types.Hash(args.Hash)
+
+	if blockHash, ok := r.backend.ReadTxLookup(hash); ok {
+		if block, ok := r.backend.GetBlockByHash(blockHash, true); ok {
+			for i, tx := range block.Transactions {
+				if tx.Hash == hash {
+					return &Transaction{r: r, tx: tx, block: &Block{r: r, block: block}, index: i}
+				}
+			}
+		}
+	}
+
+	if tx, ok := r.backend.GetPendingTx(hash); ok {
+		return &Transaction{r: r, tx: tx}
+	}
+
+	return nil
+}
+
+// FilterCriteria is the filter of the logs in a range of blocks
+type FilterCriteria struct {
+	FromBlock *Long
+	ToBlock   *Long
+	Addresses *[]Address
+	Topics    *[][]Bytes32
+}
+
+func (r *Resolver) Logs(args struct{ Filter FilterCriteria }) ([]*Log, error) {
+	head := r.backend.Header().Number
+
+	from, to := head, head
+
+	if args.Filter.FromBlock != nil {
+		if *args.Filter.FromBlock < 0 {
+			return nil, ErrNegativeBlockNumber
+		}
+
+		from = uint64(*args.Filter.FromBlock)
+	}
+
+	if args.Filter.ToBlock != nil {
+		if *args.Filter.ToBlock < 0 {
+			return nil, ErrNegativeBlockNumber
+		}
+
+		to = uint64(*args.Filter.ToBlock)
+	}
+
+	if to < from {
+		return nil, jsonrpc.ErrIncorrectBlockRange
+	}
+
+	if r.blockRangeLimit != 0 && to-from > r.blockRangeLimit {
+		return nil, jsonrpc.ErrBlockRangeTooHigh
+	}
+
+	query := newLogQuery(args.Filter.Addresses, args.Filter.Topics)
+	logs := []*Log{}
+
+	var blockErr error
+
+	// the iteration error is ignored, as the range can go past the current head
+	_ = r.backend.IterateHeaders(from, to, func(header *types.Header) bool {
+		if !header.HasReceipts() {
+			return true
+		}
+
+		block, ok := r.backend.GetBlockByHash(header.Hash, true)
+		if !ok {
+			return false
+		}
+
+		blockLogs, err := (&Block{r: r, block: block}).filterLogs(query)
+		if err != nil {
+			blockErr = err
+
+			return false
+		}
+
+		logs = append(logs, blockLogs...)
+
+		return true
+	})
+
+	if blockErr != nil {
+		return nil, blockErr
+	}
+
+	return logs, nil
+}
+
+// GasPrice returns the average gas price, at least the price limit of the pool
+func (r *Resolver) GasPrice() BigInt {
+	price := common.Max(r.priceLimit, r.backend.GetAvgGasPrice().Uint64())
+
+	return newBigInt(new(big.Int).SetUint64(price))
+}
+
+func (r *Resolver) ChainID() BigInt {
+	return newBigInt(new(big.Int).SetUint64(r.chainID))
+}
+
+func (r *Resolver) Syncing() *SyncState {
+	progression := r.backend.GetSyncProgression()
+	if progression == nil {
+		return nil
+	}
+
+	return &SyncState{
+		startingBlock: progression.StartingBlock,
+		currentBlock:  progression.CurrentBlock,
+		highestBlock:  progression.HighestBlock,
+	}
+}
+
+// SendRawTransaction adds the RLP encoded transaction to the pool
+func (r *Resolver) SendRawTransaction(args struct{ Data Bytes }) (Bytes32, error) {
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalRLP(args.Data); err != nil {
+		return Bytes32{}, err
+	}
+
+	tx.ComputeHash()
+
+	if err := r.backend.AddTx(tx); err != nil {
+		return Bytes32{}, err
+	}
+
+	return Bytes32(tx.Hash), nil
+}
+
+// newLogQuery returns the query matching the logs of the addresses and topics
+func newLogQuery(addresses *[]Address, topics *[][]Bytes32) *jsonrpc.LogQuery {
+	query := &jsonrpc.LogQuery{}
+
+	if addresses != nil {
+		for _, addr := range *addresses {
+			query.Addresses = append(query.Addresses, types.Address(addr))
+		}
+	}
+
+	if topics != nil {
+		for _, set := range *topics {
+			hashes := make([]types.Hash, 0, len(set))
+
+			for _, topic := range set {
+				hashes = append(hashes, types.Hash(topic))
+			}
+
+			query.Topics = append(query.Topics, hashes)
+		}
+	}
+
+	return query
+}
+
+// Account is an account at the state of a block
+type Account struct {
+	r       *Resolver
+	address types.Address
+	header  *types.Header
+}
+
+// account returns the account from the state, nil if it doesn't exist
+func (a *Account) account() (*state.Account, error) {
+	account, err := a.r.backend.GetAccount(a.header.StateRoot, a.address)
+	if errors.Is(err, jsonrpc.ErrStateNotFound) {
+		return nil, nil
+	}
+
+	return account, err
+}
+
+func (a *Account) Address() Address {
+	return Address(a.address)
+}
+
+func (a *Account) Balance() (BigInt, error) {
+	account, err := a.account()
+	if err != nil || account == nil {
+		return BigInt{}, err
+	}
+
+	return newBigInt(account.Balance), nil
+}
+
+func (a *Account) TransactionCount() (Long, error) {
+	account, err := a.account()
+	if err != nil || account == nil {
+		return 0, err
+	}
+
+	return Long(account.Nonce), nil
+}
+
+func (a *Account) Code() (Bytes, error) {
+	account, err := a.account()
+	if err != nil || account == nil {
+		return Bytes{}, err
+	}
+
+	code, err := a.r.backend.GetCode(types.BytesToHash(account.CodeHash))
+	if err != nil {
+		// the accounts without code have no code stored
+		return Bytes{}, nil
+	}
+
+	return code, nil
+}
+
+func (a *Account) Storage(args struct{ Slot Bytes32 }) (Bytes32, error) {
+	result, err := a.r.backend.GetStorage(a.header.StateRoot, a.address, types.Hash(args.Slot))
+	if errors.Is(err, jsonrpc.ErrStateNotFound) {
+		return Bytes32{}, nil
+	} else if err != nil {
+		return Bytes32{}, err
+	}
+
+	// the values are RLP encoded in the storage
+	p := &fastrlp.Parser{}
+
+	v, err := p.Parse(result)
+	if err != nil {
+		return Bytes32{}, nil
+	}
+
+	data, err := v.Bytes()
+	if err != nil {
+		return Bytes32{}, nil
+	}
+
+	return Bytes32(types.BytesToHash(data)), nil
+}
+
+// Block is a block of the chain, along with its transactions
+type Block struct {
+	r     *Resolver
+	block *types.Block
+
+	receiptsOnce sync.Once
+	receipts     []*types.Receipt
+	receiptsErr  error
+}
+
+func (b *Block) getReceipts() ([]*types.Receipt, error) {
+	b.receiptsOnce.Do(func() {
+		b.receipts, b.receiptsErr = b.r.backend.GetReceiptsByHash(b.block.Hash())
+	})
+
+	return b.receipts, b.receiptsErr
+}
+
+// filterLogs returns the logs of the block matching the query
+func (b *Block) filterLogs(query *jsonrpc.LogQuery) ([]*Log, error) {
+	receipts, err := b.getReceipts()
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []*Log{}
+	index := 0
+
+	for i, receipt := range receipts {
+		if i >= len(b.block.Transactions) {
+			break
+		}
+
+		tx := &Transaction{r: b.r, tx: b.block.Transactions[i], block: b, index: i}
+
+		for _, log := range receipt.Logs {
+			if query.Match(log) {
+				logs = append(logs, &Log{r: b.r, tx: tx, log: log, index: index})
+			}
+
+			index++
+		}
+	}
+
+	return logs, nil
+}
+
+// account returns the account at the state of the block, or of the block at the number if given
+func (b *Block) account(address types.Address, number *Long) (*Account, error) {
+	header := b.block.Header
+
+	if number != nil {
+		var err error
+
+		if header, err = b.r.headerAt(number); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Account{r: b.r, address: address, header: header}, nil
+}
+
+func (b *Block) Number() Long {
+	return Long(b.block.Number())
+}
+
+func (b *Block) Hash() Bytes32 {
+	return Bytes32(b.block.Hash())
+}
+
+func (b *Block) Parent() *Block {
+	if b.block.Number() == 0 {
+		return nil
+	}
+
+	block, ok := b.r.backend.GetBlockByHash(b.block.ParentHash(), true)
+	if !ok {
+		return nil
+	}
+
+	return &Block{r: b.r, block: block}
+}
+
+func (b *Block) Nonce() Bytes {
+	return b.block.Header.Nonce[:]
+}
+
+func (b *Block) TransactionsRoot() Bytes32 {
+	return Bytes32(b.block.Header.TxRoot)
+}
+
+func (b *Block) TransactionCount() *int32 {
+	count := int32(len(b.block.Transactions))
+
+	return &count
+}
+
+func (b *Block) StateRoot() Bytes32 {
+	return Bytes32(b.block.Header.StateRoot)
+}
+
+func (b *Block) ReceiptsRoot() Bytes32 {
+	return Bytes32(b.block.Header.ReceiptsRoot)
+}
+
+func (b *Block) Miner(args struct{ Block *Long }) (*Account, error) {
+	return b.account(types.BytesToAddress(b.block.Header.Miner), args.Block)
+}
+
+func (b *Block) ExtraData() Bytes {
+	return b.block.Header.ExtraData
+}
+
+func (b *Block) GasLimit() Long {
+	return Long(b.block.Header.GasLimit)
+}
+
+func (b *Block) GasUsed() Long {
+	return Long(b.block.Header.GasUsed)
+}
+
+func (b *Block) Timestamp() Long {
+	return Long(b.block.Header.Timestamp)
+}
+
+func (b *Block) LogsBloom() Bytes {
+	return b.block.Header.LogsBloom[:]
+}
+
+func (b *Block) MixHash() Bytes32 {
+	return Bytes32(b.block.Header.MixHash)
+}
+
+func (b *Block) Difficulty() BigInt {
+	return newBigInt(new(big.Int).SetUint64(b.block.Header.Difficulty))
+}
+
+func (b *Block) OmmerCount() *int32 {
+	count := int32(len(b.block.Uncles))
+
+	return &count
+}
+
+func (b *Block) Ommers() *[]*Block {
+	ommers := make([]*Block, 0, len(b.block.Uncles))
+
+	for _, uncle := range b.block.Uncles {
+		ommers = append(ommers, &Block{r: b.r, block: &types.Block{Header: uncle}})
+	}
+
+	return &ommers
+}
+
+func (b *Block) OmmerHash() Bytes32 {
+	return Bytes32(b.block.Header.Sha3Uncles)
+}
+
+func (b *Block) Transactions() *[]*Transaction {
+	txs := make([]*Transaction, 0, len(b.block.Transactions))
+
+	for i, tx := range b.block.Transactions {
+		txs = append(txs, &Transaction{r: b.r, tx: tx, block: b, index: i})
+	}
+
+	return &txs
+}
+
+func (b *Block) TransactionAt(args struct{ Index int32 }) (*Transaction, error) {
+	if args.Index < 0 || int(args.Index) >= len(b.block.Transactions) {
+		return nil, ErrTransactionIndexRange
+	}
+
+	return &Transaction{r: b.r, tx: b.block.Transactions[args.Index], block: b, index: int(args.Index)}, nil
+}
+
+// BlockFilterCriteria is the filter of the logs in a block
+type BlockFilterCriteria struct {
+	Addresses *[]Address
+	Topics    *[][]Bytes32
+}
+
+func (b *Block) Logs(args struct{ Filter BlockFilterCriteria }) ([]*Log, error) {
+	return b.filterLogs(newLogQuery(args.Filter.Addresses, args.Filter.Topics))
+}
+
+func (b *Block) Account(args struct{ Address Address }) *Account {
+	return &Account{r: b.r, address: types.Address(args.Address), header: b.block.Header}
+}
+
+func (b *Block) Call(args struct{ Data CallData }) (*CallResult, error) {
+	return b.r.call(b.block.Header, &args.Data)
+}
+
+func (b *Block) EstimateGas(args struct{ Data CallData }) (Long, error) {
+	return b.r.estimateGas(b.block.Header, &args.Data)
+}
+
+// Transaction is a transaction, either mined in a block or pending in the pool
+type Transaction struct {
+	r     *Resolver
+	tx    *types.Transaction
+	block *Block // nil while the transaction is pending
+	index int
+}
+
+// receipt returns the receipt of the transaction, nil while it is pending
+func (t *Transaction) receipt() (*types.Receipt, error) {
+	if t.block == nil {
+		return nil, nil
+	}
+
+	receipts, err := t.block.getReceipts()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.index >= len(receipts) {
+		return nil, ErrReceiptNotFound
+	}
+
+	return receipts[t.index], nil
+}
+
+// account returns the account at the state of the block including the transaction,
+// or of the block at the number if given. The pending transactions use the head of the chain
+func (t *Transaction) account(address types.Address, number *Long) (*Account, error) {
+	if t.block != nil {
+		return t.block.account(address, number)
+	}
+
+	header, err := t.r.headerAt(number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Account{r: t.r, address: address, header: header}, nil
+}
+
+func (t *Transaction) Hash() Bytes32 {
+	return Bytes32(t.tx.Hash)
+}
+
+func (t *Transaction) Nonce() Long {
+	return Long(t.tx.Nonce)
+}
+
+func (t *Transaction) Index() *int32 {
+	if t.block == nil {
+		return nil
+	}
+
+	index := int32(t.index)
+
+	return &index
+}
+
+func (t *Transaction) From(args struct{ Block *Long }) (*Account, error) {
+	return t.account(t.tx.From, args.Block)
+}
+
+func (t *Transaction) To(args struct{ Block *Long }) (*Account, error) {
+	if t.tx.To == nil {
+		return nil, nil
+	}
+
+	return t.account(*t.tx.To, args.Block)
+}
+
+func (t *Transaction) Value() BigInt {
+	return newBigInt(t.tx.Value)
+}
+
+func (t *Transaction) GasPrice() BigInt {
+	return newBigInt(t.tx.GasPrice)
+}
+
+func (t *Transaction) Gas() Long {
+	return Long(t.tx.Gas)
+}
+
+func (t *Transaction) InputData() Bytes {
+	return t.tx.Input
+}
+
+func (t *Transaction) Block() *Block {
+	return t.block
+}
+
+func (t *Transaction) Status() (*Long, error) {
+	receipt, err := t.receipt()
+	if err != nil || receipt == nil || receipt.Status == nil {
+		return nil, err
+	}
+
+	status := Long(*receipt.Status)
+
+	return &status, nil
+}
+
+func (t *Transaction) GasUsed() (*Long, error) {
+	receipt, err := t.receipt()
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+
+	gasUsed := Long(receipt.GasUsed)
+
+	return &gasUsed, nil
+}
+
+func (t *Transaction) CumulativeGasUsed() (*Long, error) {
+	receipt, err := t.receipt()
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+
+	gasUsed := Long(receipt.CumulativeGasUsed)
+
+	return &gasUsed, nil
+}
+
+func (t *Transaction) CreatedContract(args struct{ Block *Long }) (*Account, error) {
+	receipt, err := t.receipt()
+	if err != nil || receipt == nil || receipt.ContractAddress == nil {
+		return nil, err
+	}
+
+	return t.account(*receipt.ContractAddress, args.Block)
+}
+
+func (t *Transaction) Logs() (*[]*Log, error) {
+	if t.block == nil {
+		return nil, nil
+	}
+
+	receipts, err := t.block.getReceipts()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.index >= len(receipts) {
+		return nil, ErrReceiptNotFound
+	}
+
+	// the index of the logs is their position in the block
+	index := 0
+	for _, receipt := range receipts[:t.index] {
+		index += len(receipt.Logs)
+	}
+
+	logs := make([]*Log, 0, len(receipts[t.index].Logs))
+
+	for i, log := range receipts[t.index].Logs {
+		logs = append(logs, &Log{r: t.r, tx: t, log: log, index: index + i})
+	}
+
+	return &logs, nil
+}
+
+func (t *Transaction) R() BigInt {
+	return newBigInt(t.tx.R)
+}
+
+func (t *Transaction) S() BigInt {
+	return newBigInt(t.tx.S)
+}
+
+func (t *Transaction) V() BigInt {
+	return newBigInt(t.tx.V)
+}
+
+// Log is a log emitted by a mined transaction
+type Log struct {
+	r     *Resolver
+	tx    *Transaction
+	log   *types.Log
+	index int
+}
+
+func (l *Log) Index() int32 {
+	return int32(l.index)
+}
+
+func (l *Log) Account(args struct{ Block *Long }) (*Account, error) {
+	return l.tx.account(l.log.Address, args.Block)
+}
+
+func (l *Log) Topics() []Bytes32 {
+	topics := make([]Bytes32, 0, len(l.log.Topics))
+
+	for _, topic := range l.log.Topics {
+		topics = append(topics, Bytes32(topic))
+	}
+
+	return topics
+}
+
+func (l *Log) Data() Bytes {
+	return l.log.Data
+}
+
+func (l *Log) Transaction() *Transaction {
+	return l.tx
+}
+
+// Pending is the state of the pool. The chain has no pending block,
+// so the pending state is the state at the head of the chain
+type Pending struct {
+	r *Resolver
+}
+
+// pendingTxs returns the transactions pending for inclusion, ordered by sender and nonce
+func (p *Pending) pendingTxs() []*types.Transaction {
+	pending, _ := p.r.backend.GetTxs(false)
+
+	senders := make([]types.Address, 0, len(pending))
+	for sender := range pending {
+		senders = append(senders, sender)
+	}
+
+	sort.Slice(senders, func(i, j int) bool {
+		return senders[i].String() < senders[j].String()
+	})
+
+	txs := []*types.Transaction{}
+
+	for _, sender := range senders {
+		txs = append(txs, pending[sender]...)
+	}
+
+	return txs
+}
+
+func (p *Pending) TransactionCount() int32 {
+	return int32(len(p.pendingTxs()))
+}
+
+func (p *Pending) Transactions() *[]*Transaction {
+	pending := p.pendingTxs()
+
+	txs := make([]*Transaction, 0, len(pending))
+	for _, tx := range pending {
+		txs = append(txs, &Transaction{r: p.r, tx: tx})
+	}
+
+	return &txs
+}
+
+func (p *Pending) Account(args struct{ Address Address }) *Account {
+	return &Account{r: p.r, address: types.Address(args.Address), header: p.r.backend.Header()}
+}
+
+func (p *Pending) Call(args struct{ Data CallData }) (*CallResult, error) {
+	return p.r.call(p.r.backend.Header(), &args.Data)
+}
+
+func (p *Pending) EstimateGas(args struct{ Data CallData }) (Long, error) {
+	return p.r.estimateGas(p.r.backend.Header(), &args.Data)
+}
+
+// SyncState is the progression of the chain sync
+type SyncState struct {
+	startingBlock uint64
+	currentBlock  uint64
+	highestBlock  uint64
+}
+
+func (s *SyncState) StartingBlock() Long {
+	return Long(s.startingBlock)
+}
+
+func (s *SyncState) CurrentBlock() Long {
+	return Long(s.currentBlock)
+}
+
+func (s *SyncState) HighestBlock() Long {
+	return Long(s.highestBlock)
+}
+
+// CallData is a message executed on top of the state of a block
+type CallData struct {
+	From     *Address
+	To       *Address
+	Gas      *Long
+	GasPrice *BigInt
+	Value    *BigInt
+	Data     *Bytes
+}
+
+// toTransaction returns the message as a transaction sent with the next nonce of the sender at the block,
+// using the gas limit of the block if no gas is given
+func (r *Resolver) toTransaction(header *types.Header, c *CallData) (*types.Transaction, error) {
+	tx := &types.Transaction{
+		Gas:      header.GasLimit,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	}
+
+	if c.From != nil {
+		tx.From = types.Address(*c.From)
+
+		account, err := (&Account{r: r, address: tx.From, header: header}).account()
+		if err != nil {
+			return nil, err
+		}
+
+		if account != nil {
+			tx.Nonce = account.Nonce
+		}
+	}
+
+	if c.To != nil {
+		to := types.Address(*c.To)
+		tx.To = &to
+	}
+
+	if c.Gas != nil && *c.Gas > 0 {
+		tx.Gas = uint64(*c.Gas)
+	}
+
+	if price := c.GasPrice.toBig(); price != nil {
+		tx.GasPrice = price
+	}
+
+	if value := c.Value.toBig(); value != nil {
+		tx.Value = value
+	}
+
+	if c.Data != nil {
+		tx.Input = *c.Data
+	}
+
+	tx.ComputeHash()
+
+	return tx, nil
+}
+
+// CallResult is the outcome of a call
+type CallResult struct {
+	data    Bytes
+	gasUsed Long
+	status  Long
+}
+
+func (c *CallResult) Data() Bytes {
+	return c.data
+}
+
+func (c *CallResult) GasUsed() Long {
+	return c.gasUsed
+}
+
+func (c *CallResult) Status() Long {
+	return c.status
+}
+
+func (r *Resolver) call(header *types.Header, data *CallData) (*CallResult, error) {
+	tx, err := r.toTransaction(header, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.backend.ApplyTxn(header, tx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	status := Long(1)
+	if result.Failed() {
+		status = 0
+	}
+
+	return &CallResult{
+		data:    result.ReturnValue,
+		gasUsed: Long(result.GasUsed),
+		status:  status,
+	}, nil
+}
+
+// estimateGas searches the lowest gas limit the message executes with
+func (r *Resolver) estimateGas(header *types.Header, data *CallData) (Long, error) {
+	tx, err := r.toTransaction(header, data)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeds := func(gas uint64) bool {
+		txn := tx.Copy()
+		txn.Gas = gas
+
+		result, err := r.backend.ApplyTxn(header, txn, nil)
+
+		return err == nil && !result.Failed()
+	}
+
+	low, high := state.TxGas, tx.Gas
+	if tx.IsContractCreation() {
+		low = state.TxGasContractCreation
+	}
+
+	if !succeeds(high) {
+		return 0, fmt.Errorf("%w %d", ErrGasEstimationFailed, high)
+	}
+
+	for low < high {
+		mid := (low + high) / 2
+
+		if succeeds(mid) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return Long(high), nil
+}
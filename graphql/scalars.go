@@ -0,0 +1,200 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Bytes32 is the 32 bytes scalar, encoded in hex
+type Bytes32 types.Hash
+
+func (Bytes32) ImplementsGraphQLType(name string) bool {
+	return name == "Bytes32"
+}
+
+func (b *Bytes32) UnmarshalGraphQL(input interface{}) error {
+	str, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for Bytes32", input)
+	}
+
+	buf, err := hex.DecodeHex(str)
+	if err != nil {
+		return err
+	}
+
+	if len(buf) != types.HashLength {
+		return fmt.Errorf("invalid Bytes32 length %d", len(buf))
+	}
+
+	*b = Bytes32(types.BytesToHash(buf))
+
+	return nil
+}
+
+func (b Bytes32) MarshalJSON() ([]byte, error) {
+	return json.Marshal(types.Hash(b).String())
+}
+
+// Address is the 20 bytes account address scalar, encoded in hex
+type Address types.Address
+
+func (Address) ImplementsGraphQLType(name string) bool {
+	return name == "Address"
+}
+
+func (a *Address) UnmarshalGraphQL(input interface{}) error {
+	str, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for Address", input)
+	}
+
+	buf, err := hex.DecodeHex(str)
+	if err != nil {
+		return err
+	}
+
+	if len(buf) != types.AddressLength {
+		return fmt.Errorf("invalid Address length %d", len(buf))
+	}
+
+	*a = Address(types.BytesToAddress(buf))
+
+	return nil
+}
+
+func (a Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(types.Address(a).String())
+}
+
+// Bytes is the arbitrary length binary scalar, encoded in hex
+type Bytes []byte
+
+func (Bytes) ImplementsGraphQLType(name string) bool {
+	return name == "Bytes"
+}
+
+func (b *Bytes) UnmarshalGraphQL(input interface{}) error {
+	str, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for Bytes", input)
+	}
+
+	buf, err := hex.DecodeHex(str)
+	if err != nil {
+		return err
+	}
+
+	*b = buf
+
+	return nil
+}
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToHex(b))
+}
+
+// BigInt is the arbitrary precision integer scalar, encoded as a hex quantity.
+// It is decoded from either a hex or a decimal string
+type BigInt big.Int
+
+func newBigInt(i *big.Int) BigInt {
+	if i == nil {
+		return BigInt{}
+	}
+
+	return BigInt(*i)
+}
+
+func (BigInt) ImplementsGraphQLType(name string) bool {
+	return name == "BigInt"
+}
+
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	var (
+		i  *big.Int
+		ok bool
+	)
+
+	switch v := input.(type) {
+	case string:
+		if strings.HasPrefix(v, "0x") {
+			i, ok = new(big.Int).SetString(v[2:], 16)
+		} else {
+			i, ok = new(big.Int).SetString(v, 10)
+		}
+	case int32:
+		i, ok = big.NewInt(int64(v)), true
+	default:
+		return fmt.Errorf("unexpected type %T for BigInt", input)
+	}
+
+	if !ok {
+		return fmt.Errorf("invalid BigInt %v", input)
+	}
+
+	*b = BigInt(*i)
+
+	return nil
+}
+
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	i := big.Int(b)
+
+	return json.Marshal(hex.EncodeBig(&i))
+}
+
+// toBig returns the integer as a big.Int, nil if the integer is not set
+func (b *BigInt) toBig() *big.Int {
+	if b == nil {
+		return nil
+	}
+
+	i := big.Int(*b)
+
+	return &i
+}
+
+// Long is the 64 bits integer scalar.
+// It is decoded from either a number, or a hex or decimal string
+type Long int64
+
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case string:
+		var (
+			value int64
+			err   error
+		)
+
+		if strings.HasPrefix(v, "0x") {
+			value, err = strconv.ParseInt(v[2:], 16, 64)
+		} else {
+			value, err = strconv.ParseInt(v, 10, 64)
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid Long %s: %w", v, err)
+		}
+
+		*l = Long(value)
+	case int32:
+		*l = Long(v)
+	case float64:
+		*l = Long(v)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package health
+
+import (
+	"errors"
+)
+
+// diskUsage isn't reported on windows
+func diskUsage(_ string) (*DiskUsage, error) {
+	return nil, errors.New("disk usage is not supported on windows")
+}
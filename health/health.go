@@ -0,0 +1,227 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// HealthPath is the path of the liveness check, failing when the node can't keep running
+	HealthPath = "/health"
+
+	// ReadyPath is the path of the readiness check, failing when the node can't serve the latest state
+	ReadyPath = "/readyz"
+)
+
+// Config holds the marks of the checks
+type Config struct {
+	// MinPeers is the number of peers the node needs to be ready
+	MinPeers uint64
+
+	// MaxBlockAge is the age of the head block past which the node isn't ready, 0 disables the check
+	MaxBlockAge time.Duration
+
+	// MinFreeDisk is the free space of the data directory below which the node isn't healthy
+	MinFreeDisk uint64
+}
+
+// DefaultConfig returns the default marks of the checks for the given block time
+func DefaultConfig(blockTime time.Duration) *Config {
+	return &Config{
+		MinPeers:    1,
+		MaxBlockAge: 10 * blockTime,
+		MinFreeDisk: 1 << 30,
+	}
+}
+
+type Blockchain interface {
+	Header() *types.Header
+}
+
+type Network interface {
+	GetPeers() int
+}
+
+type Syncer interface {
+	GetSyncProgression() *progress.Progression
+}
+
+// Report is the health of the node
+type Report struct {
+	// Healthy is whether the node can keep running
+	Healthy bool `json:"healthy"`
+	// Ready is whether the node is synced and connected, so it can serve the latest state
+	Ready bool `json:"ready"`
+	// Problems are the failed checks
+	Problems []string `json:"problems,omitempty"`
+
+	Syncing     bool           `json:"syncing"`
+	Sync        *SyncStatus    `json:"sync,omitempty"`
+	LatestBlock *BlockStatus   `json:"latest_block"`
+	Peers       int            `json:"peers"`
+	Validators  *Participation `json:"validators,omitempty"`
+	Disk        *DiskUsage     `json:"disk,omitempty"`
+}
+
+// SyncStatus is the progression of the sync in progress
+type SyncStatus struct {
+	StartingBlock uint64 `json:"starting_block"`
+	CurrentBlock  uint64 `json:"current_block"`
+	HighestBlock  uint64 `json:"highest_block"`
+}
+
+// BlockStatus is the head block of the node
+type BlockStatus struct {
+	Number    uint64 `json:"number"`
+	Hash      string `json:"hash"`
+	Timestamp uint64 `json:"timestamp"`
+	// Age is the number of seconds since the block was sealed
+	Age uint64 `json:"age"`
+}
+
+// Participation is how many of the validators of the head block sealed it
+type Participation struct {
+	Validators int  `json:"validators"`
+	Sealed     int  `json:"sealed"`
+	Validator  bool `json:"validator"`
+}
+
+// DiskUsage is the usage of the disk of the data directory
+type DiskUsage struct {
+	Path  string `json:"path"`
+	Total uint64 `json:"total"`
+	Free  uint64 `json:"free"`
+}
+
+// Checker checks the health of the node
+type Checker struct {
+	config     *Config
+	dataDir    string
+	blockchain Blockchain
+	network    Network
+	syncer     Syncer
+
+	// the participation is reported only if the consensus seals the blocks by several validators
+	consensus consensus.ParticipationReporter
+
+	now func() time.Time
+}
+
+// NewChecker creates a new health Checker, the consensus may be nil
+func NewChecker(
+	config *Config,
+	dataDir string,
+	blockchain Blockchain,
+	network Network,
+	syncer Syncer,
+	consensus consensus.ParticipationReporter,
+) *Checker {
+	return &Checker{
+		config:     config,
+		dataDir:    dataDir,
+		blockchain: blockchain,
+		network:    network,
+		syncer:     syncer,
+		consensus:  consensus,
+		now:        time.Now,
+	}
+}
+
+// Report checks the health of the node
+func (c *Checker) Report() *Report {
+	report := &Report{
+		Healthy: true,
+		Ready:   true,
+		Peers:   c.network.GetPeers(),
+	}
+
+	if prog := c.syncer.GetSyncProgression(); prog != nil {
+		report.Syncing = true
+		report.Sync = &SyncStatus{
+			StartingBlock: prog.StartingBlock,
+			CurrentBlock:  prog.CurrentBlock,
+			HighestBlock:  prog.HighestBlock,
+		}
+
+		report.notReady(fmt.Sprintf("syncing (%d of %d)", prog.CurrentBlock, prog.HighestBlock))
+	}
+
+	header := c.blockchain.Header()
+	report.LatestBlock = &BlockStatus{
+		Number:    header.Number,
+		Hash:      header.Hash.String(),
+		Timestamp: header.Timestamp,
+	}
+
+	if now := uint64(c.now().Unix()); now > header.Timestamp {
+		report.LatestBlock.Age = now - header.Timestamp
+	}
+
+	if maxAge := c.config.MaxBlockAge; maxAge > 0 && header.Number > 0 &&
+		time.Duration(report.LatestBlock.Age)*time.Second > maxAge {
+		report.notReady(fmt.Sprintf("latest block is %ds old", report.LatestBlock.Age))
+	}
+
+	if uint64(report.Peers) < c.config.MinPeers {
+		report.notReady(fmt.Sprintf("%d peers of %d required", report.Peers, c.config.MinPeers))
+	}
+
+	if c.consensus != nil && header.Number > 0 {
+		if participation, err := c.consensus.GetParticipation(header); err == nil {
+			report.Validators = &Participation{
+				Validators: participation.Validators,
+				Sealed:     participation.Sealed,
+				Validator:  participation.Validator,
+			}
+		}
+	}
+
+	if c.dataDir != "" {
+		if disk, err := diskUsage(c.dataDir); err == nil {
+			report.Disk = disk
+
+			if disk.Free < c.config.MinFreeDisk {
+				report.notHealthy(fmt.Sprintf("%d bytes free on disk", disk.Free))
+			}
+		}
+	}
+
+	return report
+}
+
+func (r *Report) notReady(problem string) {
+	r.Ready = false
+	r.Problems = append(r.Problems, problem)
+}
+
+func (r *Report) notHealthy(problem string) {
+	r.Healthy = false
+	r.notReady(problem)
+}
+
+// Handler returns the handler of the health and readiness checks,
+// which respond with the report, and 503 if the check fails
+func (c *Checker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Report()
+
+		passed := report.Healthy
+		if r.URL.Path == ReadyPath {
+			passed = report.Ready
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if !passed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
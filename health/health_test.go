@@ -0,0 +1,165 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBlockchain struct {
+	header *types.Header
+}
+
+func (m *mockBlockchain) Header() *types.Header {
+	return m.header
+}
+
+type mockNetwork struct {
+	peers int
+}
+
+func (m *mockNetwork) GetPeers() int {
+	return m.peers
+}
+
+type mockSyncer struct {
+	progression *progress.Progression
+}
+
+func (m *mockSyncer) GetSyncProgression() *progress.Progression {
+	return m.progression
+}
+
+type mockConsensus struct {
+	participation *consensus.Participation
+	err           error
+}
+
+func (m *mockConsensus) GetParticipation(*types.Header) (*consensus.Participation, error) {
+	return m.participation, m.err
+}
+
+func newTestChecker(header *types.Header, peers int, progression *progress.Progression) *Checker {
+	checker := NewChecker(
+		&Config{MinPeers: 1, MaxBlockAge: 10 * time.Second},
+		"",
+		&mockBlockchain{header: header},
+		&mockNetwork{peers: peers},
+		&mockSyncer{progression: progression},
+		&mockConsensus{participation: &consensus.Participation{Validators: 4, Sealed: 3, Validator: true}},
+	)
+
+	checker.now = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	return checker
+}
+
+func TestChecker_Report(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		header      *types.Header
+		peers       int
+		progression *progress.Progression
+		ready       bool
+		problems    int
+	}{
+		{
+			name:   "ready",
+			header: &types.Header{Number: 10, Timestamp: 995},
+			peers:  2,
+			ready:  true,
+		},
+		{
+			name:   "no peers",
+			header: &types.Header{Number: 10, Timestamp: 995},
+			peers:  0,
+			ready:  false,
+			// not enough peers
+			problems: 1,
+		},
+		{
+			name:   "stale head",
+			header: &types.Header{Number: 10, Timestamp: 900},
+			peers:  2,
+			ready:  false,
+			// the head is older than 10s
+			problems: 1,
+		},
+		{
+			name:        "syncing",
+			header:      &types.Header{Number: 10, Timestamp: 900},
+			peers:       2,
+			progression: &progress.Progression{StartingBlock: 5, CurrentBlock: 10, HighestBlock: 20},
+			ready:       false,
+			// syncing, and the head is stale
+			problems: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := newTestChecker(test.header, test.peers, test.progression).Report()
+
+			assert.True(t, report.Healthy)
+			assert.Equal(t, test.ready, report.Ready)
+			assert.Len(t, report.Problems, test.problems)
+			assert.Equal(t, test.peers, report.Peers)
+			assert.Equal(t, test.header.Number, report.LatestBlock.Number)
+			assert.Equal(t, 1000-test.header.Timestamp, report.LatestBlock.Age)
+			assert.Equal(t, &Participation{Validators: 4, Sealed: 3, Validator: true}, report.Validators)
+			assert.Equal(t, test.progression != nil, report.Syncing)
+		})
+	}
+}
+
+func TestChecker_Report_Genesis(t *testing.T) {
+	t.Parallel()
+
+	checker := newTestChecker(&types.Header{Number: 0, Timestamp: 0}, 1, nil)
+	checker.consensus = &mockConsensus{err: errors.New("no seals")}
+
+	// the genesis isn't sealed, nor stale
+	report := checker.Report()
+	assert.True(t, report.Ready)
+	assert.Nil(t, report.Validators)
+}
+
+func TestChecker_Handler(t *testing.T) {
+	t.Parallel()
+
+	// healthy, but not ready without peers
+	handler := newTestChecker(&types.Header{Number: 10, Timestamp: 995}, 0, nil).Handler()
+
+	for path, code := range map[string]int{
+		HealthPath: http.StatusOK,
+		ReadyPath:  http.StatusServiceUnavailable,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, code, rec.Code, path)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var report Report
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.True(t, report.Healthy)
+		assert.False(t, report.Ready)
+	}
+}
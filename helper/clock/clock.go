@@ -0,0 +1,168 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates the timers, so the components depending on the time
+// can be driven by a simulated clock in the tests
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	After(d time.Duration) <-chan time.Time
+}
+
+// Timer is a timer created by a clock
+type Timer interface {
+	// C returns the channel the time is sent to once the timer expires
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing, it returns false if the timer already expired or was stopped
+	Stop() bool
+}
+
+// New returns the system clock
+func New() Clock {
+	return systemClock{}
+}
+
+// OrSystem returns the clock, or the system clock if it is nil
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return New()
+	}
+
+	return c
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// Simulated is a clock which only advances when told to. The timers expiring as it advances
+// fire in the order of their deadlines, and in their order of creation for the same deadline
+type Simulated struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers []*simulatedTimer
+}
+
+// NewSimulated returns a simulated clock starting at the given time
+func NewSimulated(now time.Time) *Simulated {
+	return &Simulated{now: now}
+}
+
+// Now returns the time of the clock
+func (s *Simulated) Now() time.Time {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.now
+}
+
+// NewTimer creates a timer expiring once the clock advanced by the duration
+func (s *Simulated) NewTimer(d time.Duration) Timer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t := &simulatedTimer{
+		clock:    s,
+		c:        make(chan time.Time, 1),
+		deadline: s.now.Add(d),
+	}
+
+	if d <= 0 {
+		t.c <- s.now
+
+		return t
+	}
+
+	// the stable sort keeps the order of creation of the timers with the same deadline
+	s.timers = append(s.timers, t)
+	sort.SliceStable(s.timers, func(i, j int) bool {
+		return s.timers[i].deadline.Before(s.timers[j].deadline)
+	})
+
+	return t
+}
+
+// After returns the channel of a new timer
+func (s *Simulated) After(d time.Duration) <-chan time.Time {
+	return s.NewTimer(d).C()
+}
+
+// Advance moves the clock forward by the duration, and fires the timers expiring on the way
+func (s *Simulated) Advance(d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	end := s.now.Add(d)
+
+	for len(s.timers) > 0 && !s.timers[0].deadline.After(end) {
+		t := s.timers[0]
+		s.timers = s.timers[1:]
+
+		s.now = t.deadline
+		t.c <- t.deadline
+	}
+
+	s.now = end
+}
+
+// Pending returns the number of timers which didn't expire yet,
+// the tests wait for it to know the code under test is blocked on the clock
+func (s *Simulated) Pending() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.timers)
+}
+
+func (s *Simulated) stop(t *simulatedTimer) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, pending := range s.timers {
+		if pending == t {
+			s.timers = append(s.timers[:i], s.timers[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+type simulatedTimer struct {
+	clock    *Simulated
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (t *simulatedTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *simulatedTimer) Stop() bool {
+	return t.clock.stop(t)
+}
@@ -0,0 +1,61 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulated_Advance(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	c := NewSimulated(start)
+
+	var fired []time.Duration
+
+	timers := make(map[time.Duration]Timer)
+	for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		timers[d] = c.NewTimer(d)
+	}
+
+	stopped := c.NewTimer(time.Second)
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+
+	assert.Equal(t, 3, c.Pending())
+
+	// the timers expiring on the way fire in the order of their deadlines
+	c.Advance(2 * time.Second)
+
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		select {
+		case now := <-timers[d].C():
+			assert.Equal(t, start.Add(d), now)
+
+			fired = append(fired, d)
+		default:
+		}
+	}
+
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, fired)
+	assert.Equal(t, start.Add(2*time.Second), c.Now())
+	assert.Equal(t, 1, c.Pending())
+
+	// the expired timers can't be stopped
+	assert.False(t, timers[time.Second].Stop())
+
+	select {
+	case <-stopped.C():
+		t.Fatal("the stopped timer fired")
+	default:
+	}
+
+	c.Advance(time.Second)
+	assert.Equal(t, start.Add(3*time.Second), <-timers[3*time.Second].C())
+	assert.Zero(t, c.Pending())
+
+	// a timer without duration fires right away
+	assert.Equal(t, c.Now(), <-c.After(0))
+}
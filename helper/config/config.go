@@ -23,3 +23,15 @@ func GetDeploymentWhitelist(genesisConfig *chain.Chain) ([]types.Address, error)
 
 	return whitelistConfig.Deployment, nil
 }
+
+// GetSystemWhitelist fetches the senders of the system transactions from the genesis config
+// if doesn't exist returns empty list
+func GetSystemWhitelist(genesisConfig *chain.Chain) ([]types.Address, error) {
+	whitelistConfig := GetWhitelist(genesisConfig)
+
+	if whitelistConfig == nil {
+		return make([]types.Address, 0), nil
+	}
+
+	return whitelistConfig.System, nil
+}
@@ -0,0 +1,107 @@
+// Package diagnostics serves the runtime profiles of the node, so the performance issues
+// of a running node can be captured without rebuilding it
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxProfileDuration is the longest CPU profile, so a profile can't hold the profiler indefinitely
+	MaxProfileDuration = 5 * time.Minute
+
+	// ProfilesDir is the directory of the data directory the profiles are written to
+	ProfilesDir = "profiles"
+)
+
+var (
+	ErrInvalidProfileDuration = fmt.Errorf("the profile duration must be between 1s and %s", MaxProfileDuration)
+	ErrProfileInProgress      = errors.New("a CPU profile is already in progress")
+)
+
+// Handler returns the handler of the pprof endpoints under /debug/pprof/: the index of the profiles,
+// the named profiles (heap, goroutine, allocs, block, mutex, threadcreate), the CPU profile
+// and the execution trace, which both take the number of seconds to record
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return mux
+}
+
+// EnableContentionProfiles samples the blocking events and the contended mutexes,
+// which the block and mutex profiles are empty without
+func EnableContentionProfiles() {
+	// one blocking event sampled per millisecond spent blocked, and one contention out of 100
+	runtime.SetBlockProfileRate(int(time.Millisecond))
+	runtime.SetMutexProfileFraction(100)
+}
+
+// Profiler writes the CPU profiles requested through the JSON-RPC to a directory
+type Profiler struct {
+	dir string
+
+	// lock is held while profiling, the runtime records one CPU profile at a time
+	lock sync.Mutex
+}
+
+// NewProfiler creates the profiler writing the profiles to the directory, created once the first profile is written
+func NewProfiler(dir string) *Profiler {
+	return &Profiler{
+		dir: dir,
+	}
+}
+
+// CPUProfile records the CPU profile for the duration, and returns the path of the file it is written to
+func (p *Profiler) CPUProfile(duration time.Duration) (string, error) {
+	if duration < time.Second || duration > MaxProfileDuration {
+		return "", ErrInvalidProfileDuration
+	}
+
+	if !p.lock.TryLock() {
+		return "", ErrProfileInProgress
+	}
+	defer p.lock.Unlock()
+
+	if err := os.MkdirAll(p.dir, 0700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(p.dir, fmt.Sprintf("cpu-%s.pprof", time.Now().UTC().Format("20060102-150405")))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	// the profile started through the pprof endpoint is reported as an error too
+	if err := rpprof.StartCPUProfile(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+
+		return "", fmt.Errorf("failed to start the CPU profile: %w", err)
+	}
+
+	time.Sleep(duration)
+	rpprof.StopCPUProfile()
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
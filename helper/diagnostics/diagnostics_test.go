@@ -0,0 +1,51 @@
+package diagnostics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/pprof/cmdline"} {
+		res, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+	}
+}
+
+func TestProfiler_CPUProfile(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), ProfilesDir)
+	profiler := NewProfiler(dir)
+
+	_, err := profiler.CPUProfile(time.Millisecond)
+	assert.ErrorIs(t, err, ErrInvalidProfileDuration)
+
+	_, err = profiler.CPUProfile(MaxProfileDuration + time.Second)
+	assert.ErrorIs(t, err, ErrInvalidProfileDuration)
+
+	path, err := profiler.CPUProfile(time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, dir, filepath.Dir(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Size())
+}
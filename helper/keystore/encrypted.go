@@ -0,0 +1,228 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// version is the version of the Web3 Secret Storage format of the keystores
+	version = 3
+
+	// StandardScryptN and StandardScryptP are the scrypt parameters of the keystores,
+	// the ones Ethereum clients use by default
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+
+	scryptR     = 8
+	scryptDKLen = 32
+
+	cipherAES128CTR = "aes-128-ctr"
+	kdfScrypt       = "scrypt"
+	kdfPBKDF2       = "pbkdf2"
+	prfHMACSHA256   = "hmac-sha256"
+)
+
+var (
+	ErrDecrypt = errors.New("could not decrypt key with given passphrase")
+
+	errUnsupportedVersion = errors.New("unsupported keystore version")
+	errUnsupportedCipher  = errors.New("unsupported keystore cipher")
+	errUnsupportedKDF     = errors.New("unsupported keystore KDF")
+)
+
+// EncryptedKey is the JSON representation of a keystore in the Web3 Secret Storage format (v3)
+type EncryptedKey struct {
+	Address string     `json:"address,omitempty"`
+	Crypto  CryptoJSON `json:"crypto"`
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+// CryptoJSON is the encrypted secret and the parameters needed to decrypt it
+type CryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// IsEncrypted checks if the data is a keystore rather than a plain secret
+func IsEncrypted(data []byte) bool {
+	var key EncryptedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return false
+	}
+
+	return key.Version != 0 && key.Crypto.CipherText != ""
+}
+
+// Encrypt encrypts the secret by the passphrase into a keystore,
+// the address (hex without the 0x prefix) is set only for the ECDSA keys
+func Encrypt(secret []byte, address, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	cipherText, err := aesCTRXOR(derivedKey[:16], secret, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&EncryptedKey{
+		Address: address,
+		Crypto: CryptoJSON{
+			Cipher:       cipherAES128CTR,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          kdfScrypt,
+			KDFParams: map[string]interface{}{
+				"n":     scryptN,
+				"r":     scryptR,
+				"p":     scryptP,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(keccak256(derivedKey[16:32], cipherText)),
+		},
+		ID:      uuid.New().String(),
+		Version: version,
+	})
+}
+
+// Decrypt decrypts the secret of the keystore by the passphrase
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	var key EncryptedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("unable to parse keystore, %w", err)
+	}
+
+	if key.Version != version {
+		return nil, fmt.Errorf("%w: %d", errUnsupportedVersion, key.Version)
+	}
+
+	if key.Crypto.Cipher != cipherAES128CTR {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedCipher, key.Crypto.Cipher)
+	}
+
+	mac, err := hex.DecodeString(key.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := hex.DecodeString(key.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := hex.DecodeString(key.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := deriveKey(&key.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, ErrDecrypt
+	}
+
+	return aesCTRXOR(derivedKey[:16], cipherText, iv)
+}
+
+// deriveKey derives the key of the passphrase by the KDF of the keystore
+func deriveKey(cryptoJSON *CryptoJSON, passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(fmt.Sprintf("%v", cryptoJSON.KDFParams["salt"]))
+	if err != nil {
+		return nil, err
+	}
+
+	dkLen := intParam(cryptoJSON.KDFParams, "dklen")
+	if dkLen < 32 {
+		return nil, fmt.Errorf("%w: dklen %d", errUnsupportedKDF, dkLen)
+	}
+
+	switch cryptoJSON.KDF {
+	case kdfScrypt:
+		return scrypt.Key(
+			[]byte(passphrase),
+			salt,
+			intParam(cryptoJSON.KDFParams, "n"),
+			intParam(cryptoJSON.KDFParams, "r"),
+			intParam(cryptoJSON.KDFParams, "p"),
+			dkLen,
+		)
+	case kdfPBKDF2:
+		if prf := cryptoJSON.KDFParams["prf"]; prf != prfHMACSHA256 {
+			return nil, fmt.Errorf("%w: prf %v", errUnsupportedKDF, prf)
+		}
+
+		return pbkdf2.Key([]byte(passphrase), salt, intParam(cryptoJSON.KDFParams, "c"), dkLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedKDF, cryptoJSON.KDF)
+	}
+}
+
+// intParam returns the numeric KDF param, which is decoded as float64 from JSON
+func intParam(params map[string]interface{}, name string) int {
+	switch value := params[name].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	default:
+		return 0
+	}
+}
+
+func aesCTRXOR(key, input, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+
+	return output, nil
+}
+
+func keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		hash.Write(b)
+	}
+
+	return hash.Sum(nil)
+}
@@ -0,0 +1,222 @@
+package membudget
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// rebalanceInterval is the interval at which the budgets are rebalanced
+	rebalanceInterval = time.Minute
+
+	// rebalanceStep is the percentage of the total budget moved between two consumers at once
+	rebalanceStep = 5
+
+	// highPressure is the pressure above which a consumer receives the budget of the idle ones
+	highPressure = 0.5
+
+	// lowPressure is the pressure under which a consumer gives away its budget
+	lowPressure = 0.1
+)
+
+// Consumer is a memory consumer whose budget can be resized at runtime
+type Consumer interface {
+	// Usage returns the memory in use in bytes
+	Usage() int
+
+	// Pressure returns how much the consumer would benefit from a larger budget since the last call,
+	// from 0 when it doesn't use its whole budget to 1 when it is starved
+	Pressure() float64
+
+	// Resize sets the memory budget in bytes
+	Resize(budget int)
+}
+
+// consumer is a registered consumer, along with its budget
+type consumer struct {
+	name     string
+	consumer Consumer
+
+	// budget is the current budget, kept between min and max
+	budget int
+	min    int
+	max    int
+
+	pressure float64
+}
+
+// Manager apportions a total memory budget between its consumers,
+// and moves the budget of the idle consumers to the ones under pressure
+type Manager struct {
+	logger hclog.Logger
+
+	lock      sync.Mutex
+	consumers []*consumer
+
+	closeCh chan struct{}
+}
+
+func NewManager(logger hclog.Logger) *Manager {
+	return &Manager{
+		logger:  logger.Named("membudget"),
+		closeCh: make(chan struct{}),
+	}
+}
+
+// Register registers the consumer created with the budget,
+// which is rebalanced between half and twice the initial budget
+func (m *Manager) Register(name string, c Consumer, budget int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.consumers = append(m.consumers, &consumer{
+		name:     name,
+		consumer: c,
+		budget:   budget,
+		min:      budget / 2,
+		max:      2 * budget,
+	})
+
+	m.emitMetrics()
+}
+
+// RegisterFixed registers a consumer whose budget is set once, so it counts towards the total but isn't rebalanced
+func (m *Manager) RegisterFixed(name string, budget int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.consumers = append(m.consumers, &consumer{
+		name:   name,
+		budget: budget,
+		min:    budget,
+		max:    budget,
+	})
+
+	m.emitMetrics()
+}
+
+// Budgets returns the current budgets of the consumers by name
+func (m *Manager) Budgets() map[string]int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	budgets := make(map[string]int, len(m.consumers))
+
+	for _, c := range m.consumers {
+		budgets[c.name] = c.budget
+	}
+
+	return budgets
+}
+
+// Start rebalances the budgets periodically until the manager is closed
+func (m *Manager) Start() {
+	go func() {
+		ticker := time.NewTicker(rebalanceInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.closeCh:
+				return
+			case <-ticker.C:
+				m.Rebalance()
+			}
+		}
+	}()
+}
+
+func (m *Manager) Close() {
+	close(m.closeCh)
+}
+
+// Rebalance moves a step of the total budget from the idlest consumer to the one under the highest pressure
+func (m *Manager) Rebalance() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	total := 0
+	resizable := make([]*consumer, 0, len(m.consumers))
+
+	for _, c := range m.consumers {
+		total += c.budget
+
+		if c.consumer != nil {
+			c.pressure = c.consumer.Pressure()
+			resizable = append(resizable, c)
+		}
+	}
+
+	defer m.emitMetrics()
+
+	if len(resizable) < 2 {
+		return
+	}
+
+	sort.SliceStable(resizable, func(i, j int) bool {
+		return resizable[i].pressure < resizable[j].pressure
+	})
+
+	var donor, receiver *consumer
+
+	for _, c := range resizable {
+		if c.pressure < lowPressure && c.budget > c.min {
+			donor = c
+
+			break
+		}
+	}
+
+	for i := len(resizable) - 1; i >= 0; i-- {
+		if c := resizable[i]; c.pressure > highPressure && c.budget < c.max {
+			receiver = c
+
+			break
+		}
+	}
+
+	if donor == nil || receiver == nil {
+		return
+	}
+
+	step := total * rebalanceStep / 100
+	if available := donor.budget - donor.min; step > available {
+		step = available
+	}
+
+	if room := receiver.max - receiver.budget; step > room {
+		step = room
+	}
+
+	// the donor shrinks first, so the total budget is never exceeded
+	donor.budget -= step
+	donor.consumer.Resize(donor.budget)
+
+	receiver.budget += step
+	receiver.consumer.Resize(receiver.budget)
+
+	m.logger.Debug(
+		"moved memory budget",
+		"from", donor.name,
+		"to", receiver.name,
+		"bytes", step,
+		"pressure", receiver.pressure,
+	)
+}
+
+func (m *Manager) emitMetrics() {
+	for _, c := range m.consumers {
+		labels := []metrics.Label{{Name: "consumer", Value: c.name}}
+
+		metrics.SetGaugeWithLabels([]string{"memory_budget"}, float32(c.budget), labels)
+
+		if c.consumer != nil {
+			metrics.SetGaugeWithLabels([]string{"memory_usage"}, float32(c.consumer.Usage()), labels)
+			metrics.SetGaugeWithLabels([]string{"memory_pressure"}, float32(c.pressure), labels)
+		}
+	}
+}
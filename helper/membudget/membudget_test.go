@@ -0,0 +1,79 @@
+package membudget
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockConsumer struct {
+	pressure float64
+	budget   int
+}
+
+func (c *mockConsumer) Usage() int {
+	return c.budget
+}
+
+func (c *mockConsumer) Pressure() float64 {
+	return c.pressure
+}
+
+func (c *mockConsumer) Resize(budget int) {
+	c.budget = budget
+}
+
+func TestManager_Rebalance(t *testing.T) {
+	t.Parallel()
+
+	idle := &mockConsumer{budget: 400}
+	busy := &mockConsumer{budget: 400, pressure: 0.9}
+
+	m := NewManager(hclog.NewNullLogger())
+	m.Register("idle", idle, 400)
+	m.Register("busy", busy, 400)
+	m.RegisterFixed("fixed", 200)
+
+	// a step is 5% of the total budget
+	m.Rebalance()
+
+	assert.Equal(t, 350, idle.budget)
+	assert.Equal(t, 450, busy.budget)
+	assert.Equal(t, map[string]int{"idle": 350, "busy": 450, "fixed": 200}, m.Budgets())
+
+	// the idle consumer keeps half its initial budget
+	for i := 0; i < 10; i++ {
+		m.Rebalance()
+	}
+
+	assert.Equal(t, 200, idle.budget)
+	assert.Equal(t, 600, busy.budget)
+
+	// the budget isn't moved when no consumer is under pressure
+	busy.pressure = 0.3
+
+	m.Rebalance()
+
+	assert.Equal(t, 200, idle.budget)
+	assert.Equal(t, 600, busy.budget)
+}
+
+func TestManager_RebalanceMaxBudget(t *testing.T) {
+	t.Parallel()
+
+	idle := &mockConsumer{budget: 1000}
+	busy := &mockConsumer{budget: 100, pressure: 1}
+
+	m := NewManager(hclog.NewNullLogger())
+	m.Register("idle", idle, 1000)
+	m.Register("busy", busy, 100)
+
+	// the consumer under pressure grows up to twice its initial budget
+	for i := 0; i < 10; i++ {
+		m.Rebalance()
+	}
+
+	assert.Equal(t, 900, idle.budget)
+	assert.Equal(t, 200, busy.budget)
+}
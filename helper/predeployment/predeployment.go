@@ -138,7 +138,7 @@ func getPredeployAccount(address types.Address, input, deployedBytecode []byte)
 	)
 
 	// Enable all forks
-	config := chain.AllForksEnabled.At(0)
+	config := chain.AllForksEnabled.At(0, 0)
 
 	// Create a transition
 	transition := state.NewTransition(config, radix)
@@ -11,6 +11,21 @@ type ChainSyncType string
 const (
 	ChainSyncRestore ChainSyncType = "restore"
 	ChainSyncBulk    ChainSyncType = "bulk-sync"
+	ChainSyncReplica ChainSyncType = "replica"
+)
+
+// SyncMode is the state of the block sync
+type SyncMode string
+
+const (
+	// SyncModeSnap fetches the state at a checkpoint from the peers, and imports the headers up to the checkpoint
+	SyncModeSnap SyncMode = "snap"
+	// SyncModeCheckpoint fetches the block and the state at the trusted checkpoint the chain starts from
+	SyncModeCheckpoint SyncMode = "checkpoint"
+	// SyncModeFull downloads the blocks from all the peers along a skeleton of headers, and executes them in order
+	SyncModeFull SyncMode = "full"
+	// SyncModeFollow follows the new blocks near the tip of the chain
+	SyncModeFollow SyncMode = "follow"
 )
 
 // Progression defines the status of the sync
@@ -19,6 +34,9 @@ type Progression struct {
 	// SyncType is indicating the sync method
 	SyncType ChainSyncType
 
+	// Mode is the current state of the block sync, empty if the sync has no modes
+	Mode SyncMode
+
 	// StartingBlock is the initial block that the node is starting
 	// the sync from. It is reset after every sync batch
 	StartingBlock uint64
@@ -120,6 +138,14 @@ func (pw *ProgressionWrapper) UpdateHighestProgression(highestBlock uint64) {
 	pw.progression.HighestBlock = highestBlock
 }
 
+// UpdateMode sets the current state of the block sync
+func (pw *ProgressionWrapper) UpdateMode(mode SyncMode) {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	pw.progression.Mode = mode
+}
+
 // GetProgression returns the latest sync progression
 func (pw *ProgressionWrapper) GetProgression() *Progression {
 	pw.lock.RLock()
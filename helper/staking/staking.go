@@ -1,6 +1,7 @@
 package staking
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -15,6 +16,19 @@ import (
 var (
 	MinValidatorCount = uint64(1)
 	MaxValidatorCount = common.MaxSafeJSInt
+
+	// MinStakedAmount is the minimum stake the staking contract requires from a validator
+	MinStakedAmount = big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil) // 1 ETH
+
+	// MaxTotalStakedAmount is the total stake the staking contract can account, the voting power
+	// of the validators above it would overflow the uint256 of the contract
+	MaxTotalStakedAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+)
+
+var (
+	ErrStakeBelowMinimum   = errors.New("staked amount is below the minimum of the staking contract")
+	ErrStakeOfNonValidator = errors.New("staked amount given for an address which isn't a validator")
+	ErrTotalStakeOverflow  = errors.New("total staked amount overflows the staking contract")
 )
 
 // getAddressMapping returns the key for the SC storage mapping (address => something)
@@ -132,6 +146,61 @@ func setBytesToStorage(
 type PredeployParams struct {
 	MinValidatorCount uint64
 	MaxValidatorCount uint64
+
+	// StakedAmount is the amount staked by each validator, DefaultStakedBalance if not set
+	StakedAmount *big.Int
+	// ValidatorStakes overrides StakedAmount for the given validators
+	ValidatorStakes map[types.Address]*big.Int
+}
+
+// stakedAmounts returns the amount pre-staked by each of the validators
+func (p *PredeployParams) stakedAmounts(vals validators.Validators) ([]*big.Int, error) {
+	defaultStake := p.StakedAmount
+
+	if defaultStake == nil {
+		// Parse the default staked balance value into *big.Int
+		val := DefaultStakedBalance
+
+		bigDefaultStakedBalance, err := types.ParseUint256orHex(&val)
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate DefaultStakedBalance, %w", err)
+		}
+
+		defaultStake = bigDefaultStakedBalance
+	}
+
+	if vals == nil {
+		if len(p.ValidatorStakes) > 0 {
+			return nil, ErrStakeOfNonValidator
+		}
+
+		return nil, nil
+	}
+
+	amounts := make([]*big.Int, vals.Len())
+
+	for idx := range amounts {
+		addr := vals.At(uint64(idx)).Addr()
+
+		amount, ok := p.ValidatorStakes[addr]
+		if !ok {
+			amount = defaultStake
+		}
+
+		if amount.Cmp(MinStakedAmount) < 0 {
+			return nil, fmt.Errorf("%w, validator %s, amount %s", ErrStakeBelowMinimum, addr, amount)
+		}
+
+		amounts[idx] = amount
+	}
+
+	for addr := range p.ValidatorStakes {
+		if !vals.Includes(addr) {
+			return nil, fmt.Errorf("%w, address %s", ErrStakeOfNonValidator, addr)
+		}
+	}
+
+	return amounts, nil
 }
 
 // StorageIndexes is a wrapper for different storage indexes that
@@ -163,7 +232,7 @@ const (
 )
 
 // PredeployStakingSC is a helper method for setting up the staking smart contract account,
-// using the passed in validators as pre-staked validators
+// using the passed in validators as pre-staked validators, with the amounts given in params
 func PredeployStakingSC(
 	vals validators.Validators,
 	params PredeployParams,
@@ -175,12 +244,10 @@ func PredeployStakingSC(
 		Code: scHex,
 	}
 
-	// Parse the default staked balance value into *big.Int
-	val := DefaultStakedBalance
-	bigDefaultStakedBalance, err := types.ParseUint256orHex(&val)
-
+	// Get the amounts staked by the validators
+	stakedAmounts, err := params.stakedAmounts(vals)
 	if err != nil {
-		return nil, fmt.Errorf("unable to generate DefaultStatkedBalance, %w", err)
+		return nil, err
 	}
 
 	// Generate the empty account storage map
@@ -198,7 +265,7 @@ func PredeployStakingSC(
 			validator := vals.At(uint64(idx))
 
 			// Update the total staked amount
-			stakedAmount = stakedAmount.Add(stakedAmount, bigDefaultStakedBalance)
+			stakedAmount = stakedAmount.Add(stakedAmount, stakedAmounts[idx])
 
 			// Get the storage indexes
 			storageIndexes := getStorageIndexes(validator, idx)
@@ -223,7 +290,7 @@ func PredeployStakingSC(
 
 			// Set the value for the address -> staked amount mapping
 			storageMap[types.BytesToHash(storageIndexes.AddressToStakedAmountIndex)] =
-				types.StringToHash(hex.EncodeBig(bigDefaultStakedBalance))
+				types.StringToHash(hex.EncodeBig(stakedAmounts[idx]))
 
 			// Set the value for the address -> validator index mapping
 			storageMap[types.BytesToHash(storageIndexes.AddressToValidatorIndexIndex)] =
@@ -231,6 +298,10 @@ func PredeployStakingSC(
 		}
 	}
 
+	if stakedAmount.Cmp(MaxTotalStakedAmount) > 0 {
+		return nil, fmt.Errorf("%w, total %s", ErrTotalStakeOverflow, stakedAmount)
+	}
+
 	// Set the value for the total staked amount
 	storageMap[types.BytesToHash(big.NewInt(stakedAmountSlot).Bytes())] =
 		types.BytesToHash(stakedAmount.Bytes())
@@ -250,8 +321,47 @@ func PredeployStakingSC(
 	// Save the storage map
 	stakingAccount.Storage = storageMap
 
-	// Set the Staking SC balance to numValidators * defaultStakedBalance
+	// Set the Staking SC balance to the total staked amount
 	stakingAccount.Balance = stakedAmount
 
 	return stakingAccount, nil
 }
+
+// GenesisStake is the staking state of the staking contract predeployed in the genesis
+type GenesisStake struct {
+	// Stakes are the amounts staked by the validators
+	Stakes map[types.Address]*big.Int
+	// Validators are the addresses in the validators array of the contract
+	Validators []types.Address
+	// TotalStaked is the total staked amount the contract accounts
+	TotalStaked *big.Int
+
+	MinValidatorCount uint64
+	MaxValidatorCount uint64
+}
+
+// ReadGenesisStake reads the staking state from the storage of the predeployed staking contract
+func ReadGenesisStake(account *chain.GenesisAccount) *GenesisStake {
+	slot := func(index []byte) *big.Int {
+		return new(big.Int).SetBytes(account.Storage[types.BytesToHash(index)].Bytes())
+	}
+
+	stake := &GenesisStake{
+		Stakes:            make(map[types.Address]*big.Int),
+		TotalStaked:       slot(big.NewInt(stakedAmountSlot).Bytes()),
+		MinValidatorCount: slot(big.NewInt(minNumValidatorSlot).Bytes()).Uint64(),
+		MaxValidatorCount: slot(big.NewInt(maxNumValidatorSlot).Bytes()).Uint64(),
+	}
+
+	arrayIndex := keccak.Keccak256(nil, common.PadLeftOrTrim(big.NewInt(validatorsSlot).Bytes(), 32))
+	length := slot(big.NewInt(validatorsSlot).Bytes()).Uint64()
+
+	for idx := uint64(0); idx < length; idx++ {
+		addr := types.BytesToAddress(slot(getIndexWithOffset(arrayIndex, idx)).Bytes())
+
+		stake.Validators = append(stake.Validators, addr)
+		stake.Stakes[addr] = slot(getAddressMapping(addr, addressToStakedAmountSlot))
+	}
+
+	return stake
+}
@@ -0,0 +1,157 @@
+package staking
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	addr1 = types.StringToAddress("1")
+	addr2 = types.StringToAddress("2")
+	addr3 = types.StringToAddress("3")
+)
+
+func ethAmount(eth int64) *big.Int {
+	return big.NewInt(0).Mul(big.NewInt(eth), MinStakedAmount)
+}
+
+func getStakedAmount(account map[types.Hash]types.Hash, addr types.Address) *big.Int {
+	value := account[types.BytesToHash(getAddressMapping(addr, addressToStakedAmountSlot))]
+
+	return big.NewInt(0).SetBytes(value.Bytes())
+}
+
+func TestPredeployStakingSC_StakedAmounts(t *testing.T) {
+	t.Parallel()
+
+	val := DefaultStakedBalance
+	defaultStake, err := types.ParseUint256orHex(&val)
+	assert.NoError(t, err)
+
+	vals := validators.NewECDSAValidatorSet(
+		validators.NewECDSAValidator(addr1),
+		validators.NewECDSAValidator(addr2),
+	)
+
+	tests := []struct {
+		name     string
+		params   PredeployParams
+		expected []*big.Int
+		err      error
+	}{
+		{
+			name:     "should use the default staked balance",
+			params:   PredeployParams{},
+			expected: []*big.Int{defaultStake, defaultStake},
+		},
+		{
+			name: "should use the given staked amount",
+			params: PredeployParams{
+				StakedAmount: ethAmount(5),
+			},
+			expected: []*big.Int{ethAmount(5), ethAmount(5)},
+		},
+		{
+			name: "should override the staked amount of the given validator",
+			params: PredeployParams{
+				StakedAmount: ethAmount(5),
+				ValidatorStakes: map[types.Address]*big.Int{
+					addr2: ethAmount(7),
+				},
+			},
+			expected: []*big.Int{ethAmount(5), ethAmount(7)},
+		},
+		{
+			name: "should return error for the amount below the minimum",
+			params: PredeployParams{
+				ValidatorStakes: map[types.Address]*big.Int{
+					addr1: big.NewInt(1),
+				},
+			},
+			err: ErrStakeBelowMinimum,
+		},
+		{
+			name: "should return error for the amount of an address which isn't a validator",
+			params: PredeployParams{
+				ValidatorStakes: map[types.Address]*big.Int{
+					addr3: ethAmount(2),
+				},
+			},
+			err: ErrStakeOfNonValidator,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			account, err := PredeployStakingSC(vals, test.params)
+
+			assert.ErrorIs(t, err, test.err)
+
+			if test.err != nil {
+				return
+			}
+
+			total := big.NewInt(0)
+
+			for idx, addr := range []types.Address{addr1, addr2} {
+				assert.Equal(t, test.expected[idx], getStakedAmount(account.Storage, addr))
+
+				total.Add(total, test.expected[idx])
+			}
+
+			assert.Equal(t, total, account.Balance)
+			assert.Equal(
+				t,
+				total,
+				big.NewInt(0).SetBytes(account.Storage[types.BytesToHash(big.NewInt(stakedAmountSlot).Bytes())].Bytes()),
+			)
+		})
+	}
+}
+
+func TestPredeployStakingSC_TotalStakeOverflow(t *testing.T) {
+	t.Parallel()
+
+	vals := validators.NewECDSAValidatorSet(
+		validators.NewECDSAValidator(addr1),
+		validators.NewECDSAValidator(addr2),
+	)
+
+	_, err := PredeployStakingSC(vals, PredeployParams{StakedAmount: MaxTotalStakedAmount})
+	assert.ErrorIs(t, err, ErrTotalStakeOverflow)
+}
+
+func TestReadGenesisStake(t *testing.T) {
+	t.Parallel()
+
+	vals := validators.NewECDSAValidatorSet(
+		validators.NewECDSAValidator(addr1),
+		validators.NewECDSAValidator(addr2),
+	)
+
+	account, err := PredeployStakingSC(vals, PredeployParams{
+		MinValidatorCount: 1,
+		MaxValidatorCount: 5,
+		StakedAmount:      ethAmount(5),
+		ValidatorStakes: map[types.Address]*big.Int{
+			addr2: ethAmount(7),
+		},
+	})
+	assert.NoError(t, err)
+
+	stake := ReadGenesisStake(account)
+
+	assert.Equal(t, []types.Address{addr1, addr2}, stake.Validators)
+	assert.Equal(t, map[types.Address]*big.Int{addr1: ethAmount(5), addr2: ethAmount(7)}, stake.Stakes)
+	assert.Equal(t, ethAmount(12), stake.TotalStaked)
+	assert.Equal(t, uint64(1), stake.MinValidatorCount)
+	assert.Equal(t, uint64(5), stake.MaxValidatorCount)
+}
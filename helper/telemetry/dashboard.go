@@ -0,0 +1,202 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DashboardUID is the uid of the generated dashboard, so importing it again replaces the previous one
+	DashboardUID = "polygon-edge-node"
+
+	dashboardTitle = "Polygon Edge node"
+
+	panelWidth  = 12
+	panelHeight = 8
+)
+
+// histogramQuantiles are the quantiles shown for the histograms
+var histogramQuantiles = []float64{0.5, 0.99}
+
+// Dashboard returns the Grafana dashboard of the metrics, with a row of panels per module
+func Dashboard() ([]byte, error) {
+	return json.MarshalIndent(dashboard(definitions), "", "  ")
+}
+
+type object = map[string]interface{}
+
+func dashboard(defs []*Definition) object {
+	var (
+		panels []object
+		module string
+		id     = 1
+		y      = 0
+		x      = 0
+	)
+
+	for _, def := range defs {
+		if def.Module != module {
+			if x != 0 {
+				y += panelHeight
+				x = 0
+			}
+
+			module = def.Module
+			panels = append(panels, object{
+				"id":        id,
+				"type":      "row",
+				"title":     module,
+				"collapsed": false,
+				"gridPos":   gridPos(0, y, 24, 1),
+				"panels":    []object{},
+			})
+
+			id++
+			y++
+		}
+
+		panel := panelOf(def)
+		panel["id"] = id
+		panel["gridPos"] = gridPos(x, y, panelWidth, panelHeight)
+		panels = append(panels, panel)
+
+		id++
+
+		if x += panelWidth; x >= 24 {
+			y += panelHeight
+			x = 0
+		}
+	}
+
+	return object{
+		"uid":           DashboardUID,
+		"title":         dashboardTitle,
+		"tags":          []string{Namespace},
+		"timezone":      "browser",
+		"schemaVersion": 36,
+		"refresh":       "30s",
+		"time": object{
+			"from": "now-1h",
+			"to":   "now",
+		},
+		"templating": object{
+			"list": []object{
+				{
+					"name":  "datasource",
+					"label": "Data source",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+				{
+					"name":       "instance",
+					"label":      "Instance",
+					"type":       "query",
+					"datasource": datasource(),
+					"query":      fmt.Sprintf("label_values(%s, instance)", defs[0].FullName()+bucketSuffix(defs[0])),
+					"refresh":    2,
+					"multi":      true,
+					"includeAll": true,
+					"current": object{
+						"text":  "All",
+						"value": "$__all",
+					},
+				},
+			},
+		},
+		"panels": panels,
+	}
+}
+
+// panelOf returns the time series panel of the metric, the quantiles of a histogram or the rate of a counter
+func panelOf(def *Definition) object {
+	var (
+		targets []object
+		legend  = legendOf(def.Labels)
+	)
+
+	switch def.Kind {
+	case KindHistogram:
+		for i, quantile := range histogramQuantiles {
+			targets = append(targets, object{
+				"refId":        string(rune('A' + i)),
+				"datasource":   datasource(),
+				"expr":         histogramQuery(def, quantile),
+				"legendFormat": fmt.Sprintf("p%g %s", quantile*100, legend),
+			})
+		}
+	default:
+		targets = append(targets, object{
+			"refId":        "A",
+			"datasource":   datasource(),
+			"expr":         counterQuery(def),
+			"legendFormat": legend,
+		})
+	}
+
+	return object{
+		"type":        "timeseries",
+		"title":       def.Name,
+		"description": def.Help,
+		"datasource":  datasource(),
+		"targets":     targets,
+		"fieldConfig": object{
+			"defaults": object{
+				"unit": def.Unit,
+			},
+			"overrides": []object{},
+		},
+	}
+}
+
+func histogramQuery(def *Definition, quantile float64) string {
+	return fmt.Sprintf(
+		`histogram_quantile(%g, sum by (%s) (rate(%s_bucket{instance=~"$instance"}[$__rate_interval])))`,
+		quantile,
+		strings.Join(append([]string{"le"}, def.Labels...), ", "),
+		def.FullName(),
+	)
+}
+
+func counterQuery(def *Definition) string {
+	return fmt.Sprintf(
+		`sum by (%s) (rate(%s{instance=~"$instance"}[$__rate_interval]))`,
+		strings.Join(def.Labels, ", "),
+		def.FullName(),
+	)
+}
+
+// bucketSuffix returns the suffix of the series of the metric holding the instance label
+func bucketSuffix(def *Definition) string {
+	if def.Kind == KindHistogram {
+		return "_bucket"
+	}
+
+	return ""
+}
+
+func legendOf(labels []string) string {
+	parts := make([]string, len(labels))
+
+	for i, label := range labels {
+		parts[i] = fmt.Sprintf("{{%s}}", label)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func datasource() object {
+	return object{
+		"type": "prometheus",
+		"uid":  "${datasource}",
+	}
+}
+
+func gridPos(x, y, w, h int) object {
+	return object{
+		"x": x,
+		"y": y,
+		"w": w,
+		"h": h,
+	}
+}
@@ -0,0 +1,245 @@
+// Package telemetry defines the histograms and the labeled counters of the node modules.
+// They are exported on the metrics endpoint next to the metrics emitted through go-metrics,
+// under the same namespace, and the Grafana dashboard of the node is generated from their definitions
+package telemetry
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the prefix of the metric names, shared with the metrics emitted through go-metrics
+const Namespace = "edge"
+
+// Kind is the kind of a metric
+type Kind string
+
+const (
+	KindCounter   Kind = "counter"
+	KindHistogram Kind = "histogram"
+)
+
+// Definition describes a metric of a module
+type Definition struct {
+	Module string
+	Name   string
+	Help   string
+	Kind   Kind
+	Labels []string
+	// Unit is the Grafana unit of the values, the rate of a counter is shown per second
+	Unit string
+}
+
+// FullName returns the name of the metric on the metrics endpoint
+func (d *Definition) FullName() string {
+	return prometheus.BuildFQName(Namespace, d.Module, d.Name)
+}
+
+var (
+	// roundBuckets are the buckets of the consensus rounds, which last from the block time up to the timeouts
+	roundBuckets = []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32, 64, 128}
+	// gasLeftBuckets are the buckets of the shares of the gas limit left unused by the blocks
+	gasLeftBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 0.75, 0.99, 1}
+	// requestBuckets are the buckets of the requests, from 0.5ms up to 16s
+	requestBuckets = prometheus.ExponentialBuckets(0.0005, 2, 16)
+	// dbBuckets are the buckets of the database operations, from 10µs up to 2.6s
+	dbBuckets = prometheus.ExponentialBuckets(0.00001, 4, 10)
+)
+
+var (
+	definitions []*Definition
+	collectors  []prometheus.Collector
+)
+
+var (
+	// ConsensusRoundDuration is the duration of the consensus rounds by how they ended
+	ConsensusRoundDuration = newHistogram(&Definition{
+		Module: "consensus",
+		Name:   "round_duration_seconds",
+		Help:   "The duration of the consensus rounds, by how they ended",
+		Labels: []string{"outcome"},
+		Unit:   "s",
+	}, roundBuckets)
+
+	// ConsensusBlockGasLeft is the share of the gas limit left unused by the blocks built by the node,
+	// by the reason the packing of their transactions stopped
+	ConsensusBlockGasLeft = newHistogram(&Definition{
+		Module: "consensus",
+		Name:   "block_gas_left_ratio",
+		Help:   "The share of the gas limit left unused by the blocks built by the node, by the reason the packing stopped",
+		Labels: []string{"stop"},
+		Unit:   "percentunit",
+	}, gasLeftBuckets)
+
+	// ConsensusPackedTransactions counts the transactions packed in the blocks built by the node, by the pass
+	ConsensusPackedTransactions = newCounter(&Definition{
+		Module: "consensus",
+		Name:   "packed_transactions_total",
+		Help:   "The transactions packed in the blocks built by the node, by the pass of the packer",
+		Labels: []string{"pass"},
+		Unit:   "ops",
+	})
+
+	// TxPoolAdded counts the transactions submitted to the pool, by their origin and result
+	TxPoolAdded = newCounter(&Definition{
+		Module: "txpool",
+		Name:   "added_total",
+		Help:   "The transactions submitted to the pool, by their origin and result: added or the reason they were rejected",
+		Labels: []string{"origin", "result"},
+		Unit:   "ops",
+	})
+
+	// TxPoolDropped counts the transactions removed from the pool without being included, by the reason
+	TxPoolDropped = newCounter(&Definition{
+		Module: "txpool",
+		Name:   "dropped_total",
+		Help:   "The transactions removed from the pool, by the reason",
+		Labels: []string{"reason"},
+		Unit:   "ops",
+	})
+
+	// JSONRPCMethodDuration is the duration of the JSON-RPC method calls
+	JSONRPCMethodDuration = newHistogram(&Definition{
+		Module: "jsonrpc",
+		Name:   "method_duration_seconds",
+		Help:   "The duration of the JSON-RPC method calls, by the method and whether it failed",
+		Labels: []string{"method", "status"},
+		Unit:   "s",
+	}, requestBuckets)
+
+	// DBReadDuration is the duration of the reads of the databases
+	DBReadDuration = newHistogram(&Definition{
+		Module: "db",
+		Name:   "read_duration_seconds",
+		Help:   "The duration of the reads of the databases of the data directory",
+		Labels: []string{"db"},
+		Unit:   "s",
+	}, dbBuckets)
+
+	// DBWriteDuration is the duration of the writes of the databases, by the operation
+	DBWriteDuration = newHistogram(&Definition{
+		Module: "db",
+		Name:   "write_duration_seconds",
+		Help:   "The duration of the writes of the databases of the data directory, by the operation",
+		Labels: []string{"db", "op"},
+		Unit:   "s",
+	}, dbBuckets)
+
+	// NetworkBandwidth is the traffic of the libp2p streams, by the direction and the protocol
+	NetworkBandwidth = newCounterFunc(&Definition{
+		Module: "network",
+		Name:   "bandwidth_bytes_total",
+		Help:   "The bytes sent and received over the libp2p streams, by the direction and the protocol",
+		Labels: []string{"direction", "protocol"},
+		Unit:   "Bps",
+	})
+)
+
+func newHistogram(def *Definition, buckets []float64) *prometheus.HistogramVec {
+	def.Kind = KindHistogram
+
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: def.Module,
+		Name:      def.Name,
+		Help:      def.Help,
+		Buckets:   buckets,
+	}, def.Labels)
+
+	definitions = append(definitions, def)
+	collectors = append(collectors, histogram)
+
+	return histogram
+}
+
+func newCounter(def *Definition) *prometheus.CounterVec {
+	def.Kind = KindCounter
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: def.Module,
+		Name:      def.Name,
+		Help:      def.Help,
+	}, def.Labels)
+
+	definitions = append(definitions, def)
+	collectors = append(collectors, counter)
+
+	return counter
+}
+
+func newCounterFunc(def *Definition) *CounterFunc {
+	def.Kind = KindCounter
+
+	counter := &CounterFunc{
+		desc: prometheus.NewDesc(def.FullName(), def.Help, def.Labels, nil),
+	}
+
+	definitions = append(definitions, def)
+	collectors = append(collectors, counter)
+
+	return counter
+}
+
+// Definitions returns the definitions of the metrics
+func Definitions() []*Definition {
+	return definitions
+}
+
+// Register registers the metrics with the registerer, the metrics already registered with it are kept
+func Register(reg prometheus.Registerer) error {
+	for _, collector := range collectors {
+		if err := reg.Register(collector); err != nil {
+			var registered prometheus.AlreadyRegisteredError
+			if errors.As(err, &registered) && registered.ExistingCollector == collector {
+				continue
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ObserveSince observes the time elapsed since the start in the histogram
+func ObserveSince(histogram *prometheus.HistogramVec, start time.Time, labels ...string) {
+	histogram.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+}
+
+// CounterFunc is a labeled counter whose values are read from their source on every scrape,
+// for the totals counted by the dependencies
+type CounterFunc struct {
+	desc *prometheus.Desc
+
+	lock sync.RWMutex
+	read func(emit func(value float64, labels ...string))
+}
+
+// SetSource sets the function reading the values, which emits the value of every set of labels
+func (c *CounterFunc) SetSource(read func(emit func(value float64, labels ...string))) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.read = read
+}
+
+func (c *CounterFunc) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *CounterFunc) Collect(ch chan<- prometheus.Metric) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.read == nil {
+		return
+	}
+
+	c.read(func(value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, value, labels...)
+	})
+}
@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, Register(reg))
+
+	// registering again keeps the metrics
+	require.NoError(t, Register(reg))
+
+	NetworkBandwidth.SetSource(func(emit func(value float64, labels ...string)) {
+		emit(10, "in", "/ibft/0.2")
+		emit(20, "out", "/ibft/0.2")
+	})
+
+	expected := `
+# HELP edge_network_bandwidth_bytes_total The bytes sent and received over the libp2p streams, by the direction and the protocol
+# TYPE edge_network_bandwidth_bytes_total counter
+edge_network_bandwidth_bytes_total{direction="in",protocol="/ibft/0.2"} 10
+edge_network_bandwidth_bytes_total{direction="out",protocol="/ibft/0.2"} 20
+`
+
+	assert.NoError(t, testutil.GatherAndCompare(
+		reg,
+		strings.NewReader(expected),
+		"edge_network_bandwidth_bytes_total",
+	))
+
+	// the names are unique, and prefixed by the namespace and the module
+	names := make(map[string]bool)
+
+	for _, def := range Definitions() {
+		name := def.FullName()
+
+		assert.False(t, names[name], name)
+		assert.True(t, strings.HasPrefix(name, Namespace+"_"+def.Module+"_"), name)
+
+		names[name] = true
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	t.Parallel()
+
+	data, err := Dashboard()
+	require.NoError(t, err)
+
+	var dashboard struct {
+		UID    string `json:"uid"`
+		Panels []struct {
+			Type    string `json:"type"`
+			Title   string `json:"title"`
+			Targets []struct {
+				Expr string `json:"expr"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+
+	require.NoError(t, json.Unmarshal(data, &dashboard))
+	assert.Equal(t, DashboardUID, dashboard.UID)
+
+	var (
+		rows    int
+		queries = make(map[string]bool)
+	)
+
+	for _, panel := range dashboard.Panels {
+		if panel.Type == "row" {
+			rows++
+
+			continue
+		}
+
+		for _, target := range panel.Targets {
+			queries[target.Expr] = true
+		}
+	}
+
+	// a row per module, and the queries of every metric
+	assert.Equal(t, 5, rows)
+
+	for _, def := range Definitions() {
+		switch def.Kind {
+		case KindHistogram:
+			for _, quantile := range histogramQuantiles {
+				assert.True(t, queries[histogramQuery(def, quantile)], def.FullName())
+			}
+		case KindCounter:
+			assert.True(t, queries[counterQuery(def)], def.FullName())
+		}
+	}
+
+	assert.True(t, queries[`histogram_quantile(0.99, sum by (le, method, status) `+
+		`(rate(edge_jsonrpc_method_duration_seconds_bucket{instance=~"$instance"}[$__rate_interval])))`])
+}
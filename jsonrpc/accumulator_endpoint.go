@@ -0,0 +1,89 @@
+package jsonrpc
+
+import (
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// accumulatorStore provides access to the header accumulator of the canonical chain
+type accumulatorStore interface {
+	// GetAccumulatorCheckpoint returns the root of the header accumulator at the checkpoint
+	GetAccumulatorCheckpoint(checkpoint uint64) (*blockchain.AccumulatorCheckpoint, error)
+
+	// GetLatestAccumulatorCheckpoint returns the root of the header accumulator at the last committed checkpoint
+	GetLatestAccumulatorCheckpoint() (*blockchain.AccumulatorCheckpoint, error)
+
+	// GetAncestryProof returns the proof that the canonical block is an ancestor of the head of the checkpoint
+	GetAncestryProof(number, checkpoint uint64) (*blockchain.AncestryProof, error)
+}
+
+// Accumulator is the header accumulator jsonrpc endpoint, serving the ancestry proofs
+// of the blocks to the light clients and bridges which trust a checkpoint root
+type Accumulator struct {
+	store accumulatorStore
+}
+
+type accumulatorCheckpoint struct {
+	Number     argUint64  `json:"number"`
+	HeadNumber argUint64  `json:"headNumber"`
+	HeadHash   types.Hash `json:"headHash"`
+	Root       types.Hash `json:"root"`
+}
+
+type ancestryProof struct {
+	BlockNumber argUint64              `json:"blockNumber"`
+	BlockHash   types.Hash             `json:"blockHash"`
+	Checkpoint  *accumulatorCheckpoint `json:"checkpoint"`
+	Siblings    []types.Hash           `json:"siblings"`
+	Peaks       []types.Hash           `json:"peaks"`
+}
+
+func toAccumulatorCheckpoint(cp *blockchain.AccumulatorCheckpoint) *accumulatorCheckpoint {
+	return &accumulatorCheckpoint{
+		Number:     argUint64(cp.Number),
+		HeadNumber: argUint64(cp.HeadNumber),
+		HeadHash:   cp.HeadHash,
+		Root:       cp.Root,
+	}
+}
+
+// getCheckpoint returns the checkpoint with the given index, or the latest one if it is omitted
+func (a *Accumulator) getCheckpoint(checkpoint *argUint64) (*blockchain.AccumulatorCheckpoint, error) {
+	if checkpoint == nil {
+		return a.store.GetLatestAccumulatorCheckpoint()
+	}
+
+	return a.store.GetAccumulatorCheckpoint(uint64(*checkpoint))
+}
+
+// GetCheckpoint returns the root of the header accumulator at the checkpoint, the latest one if it is omitted
+func (a *Accumulator) GetCheckpoint(checkpoint *argUint64) (interface{}, error) {
+	cp, err := a.getCheckpoint(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAccumulatorCheckpoint(cp), nil
+}
+
+// GetAncestryProof returns the proof that the block is an ancestor of the head of the checkpoint,
+// the latest one if it is omitted
+func (a *Accumulator) GetAncestryProof(number argUint64, checkpoint *argUint64) (interface{}, error) {
+	cp, err := a.getCheckpoint(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := a.store.GetAncestryProof(uint64(number), cp.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ancestryProof{
+		BlockNumber: argUint64(proof.Number),
+		BlockHash:   proof.Hash,
+		Checkpoint:  toAccumulatorCheckpoint(proof.Checkpoint),
+		Siblings:    proof.Siblings,
+		Peaks:       proof.Peaks,
+	}, nil
+}
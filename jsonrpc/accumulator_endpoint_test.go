@@ -0,0 +1,82 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+type mockAccumulatorStore struct {
+	checkpoints []*blockchain.AccumulatorCheckpoint
+}
+
+func (m *mockAccumulatorStore) GetAccumulatorCheckpoint(checkpoint uint64) (*blockchain.AccumulatorCheckpoint, error) {
+	if checkpoint >= uint64(len(m.checkpoints)) {
+		return nil, blockchain.ErrCheckpointNotFound
+	}
+
+	return m.checkpoints[checkpoint], nil
+}
+
+func (m *mockAccumulatorStore) GetLatestAccumulatorCheckpoint() (*blockchain.AccumulatorCheckpoint, error) {
+	return m.GetAccumulatorCheckpoint(uint64(len(m.checkpoints)) - 1)
+}
+
+func (m *mockAccumulatorStore) GetAncestryProof(number, checkpoint uint64) (*blockchain.AncestryProof, error) {
+	cp, err := m.GetAccumulatorCheckpoint(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blockchain.AncestryProof{
+		Number:     number,
+		Hash:       hash1,
+		Checkpoint: cp,
+		Siblings:   []types.Hash{hash2},
+		Peaks:      []types.Hash{hash3},
+	}, nil
+}
+
+func TestAccumulatorEndpoint(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := newDispatcher(hclog.NewNullLogger(), nil, &dispatcherParams{})
+	dispatcher.endpoints.Accumulator.store = &mockAccumulatorStore{
+		checkpoints: []*blockchain.AccumulatorCheckpoint{
+			{Number: 0, HeadNumber: 1023, HeadHash: hash1, Root: hash2},
+			{Number: 1, HeadNumber: 2047, HeadHash: hash2, Root: hash3},
+		},
+	}
+
+	// the latest checkpoint is used if it is omitted
+	resp, err := dispatcher.Handle([]byte(`{"method": "accumulator_getAncestryProof", "params": ["0x10"]}`))
+	require.NoError(t, err)
+
+	var proof ancestryProof
+
+	require.NoError(t, expectJSONResult(resp, &proof))
+	assert.Equal(t, ancestryProof{
+		BlockNumber: 0x10,
+		BlockHash:   hash1,
+		Checkpoint:  &accumulatorCheckpoint{Number: 1, HeadNumber: 2047, HeadHash: hash2, Root: hash3},
+		Siblings:    []types.Hash{hash2},
+		Peaks:       []types.Hash{hash3},
+	}, proof)
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "accumulator_getCheckpoint", "params": ["0x0"]}`))
+	require.NoError(t, err)
+
+	var cp accumulatorCheckpoint
+
+	require.NoError(t, expectJSONResult(resp, &cp))
+	assert.Equal(t, accumulatorCheckpoint{Number: 0, HeadNumber: 1023, HeadHash: hash1, Root: hash2}, cp)
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "accumulator_getCheckpoint", "params": ["0x2"]}`))
+	require.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &cp))
+}
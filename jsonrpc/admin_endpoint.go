@@ -0,0 +1,68 @@
+package jsonrpc
+
+// AdminStore provides access to the methods needed by the admin endpoint
+type AdminStore interface {
+	// AddStaticPeer adds the peer at the multiaddr to the static peers, which are always kept connected
+	AddStaticPeer(rawPeerMultiaddr string) error
+
+	// RemoveStaticPeer removes the peer at the multiaddr, or with the id, from the static peers
+	// and disconnects from it
+	RemoveStaticPeer(rawPeer string) error
+
+	// GetAdminPeers returns the connected peers
+	GetAdminPeers() []*AdminPeer
+
+	// ReloadConfig reads the config file again and applies the settings which can be changed at runtime
+	ReloadConfig() error
+}
+
+// AdminPeer is a connected peer
+type AdminPeer struct {
+	ID        string   `json:"id"`
+	Addrs     []string `json:"addrs"`
+	Protocols []string `json:"protocols"`
+	Inbound   bool     `json:"inbound"`
+	Outbound  bool     `json:"outbound"`
+	Static    bool     `json:"static"`
+}
+
+// Admin is the admin jsonrpc endpoint, managing the peers of the node at runtime,
+// which is only served if the admin endpoints are enabled
+type Admin struct {
+	store AdminStore
+}
+
+// AddPeer adds the peer at the multiaddr to the static peers, which the node always
+// keeps connected to regardless of its peer limits, and dials it
+func (a *Admin) AddPeer(multiaddr string) (interface{}, error) {
+	if err := a.store.AddStaticPeer(multiaddr); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+// RemovePeer removes the peer at the multiaddr, or with the id, from the static peers
+// and disconnects from it
+func (a *Admin) RemovePeer(peer string) (interface{}, error) {
+	if err := a.store.RemoveStaticPeer(peer); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+// Peers returns the connected peers
+func (a *Admin) Peers() (interface{}, error) {
+	return a.store.GetAdminPeers(), nil
+}
+
+// ReloadConfig reads the config file of the node again, and applies its log level, JSON-RPC policy
+// and rate limits, peer limits and price limit without restarting the node
+func (a *Admin) ReloadConfig() (interface{}, error) {
+	if err := a.store.ReloadConfig(); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
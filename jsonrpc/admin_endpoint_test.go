@@ -0,0 +1,97 @@
+package jsonrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errMockNotStatic = errors.New("not a static peer")
+
+type mockAdminStore struct {
+	static   map[string]bool
+	reloaded int
+}
+
+func (m *mockAdminStore) AddStaticPeer(rawPeerMultiaddr string) error {
+	m.static[rawPeerMultiaddr] = true
+
+	return nil
+}
+
+func (m *mockAdminStore) RemoveStaticPeer(rawPeer string) error {
+	if !m.static[rawPeer] {
+		return errMockNotStatic
+	}
+
+	delete(m.static, rawPeer)
+
+	return nil
+}
+
+func (m *mockAdminStore) GetAdminPeers() []*AdminPeer {
+	peers := make([]*AdminPeer, 0, len(m.static))
+	for addr := range m.static {
+		peers = append(peers, &AdminPeer{ID: addr, Outbound: true, Static: true})
+	}
+
+	return peers
+}
+
+func (m *mockAdminStore) ReloadConfig() error {
+	m.reloaded++
+
+	return nil
+}
+
+func TestAdminEndpoint(t *testing.T) {
+	t.Parallel()
+
+	store := &mockAdminStore{static: make(map[string]bool)}
+	dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), &dispatcherParams{})
+
+	// the admin methods are only served once enabled
+	_, err := dispatcher.Handle([]byte(`{"method": "admin_peers", "params": []}`))
+	require.NoError(t, err)
+	assert.Nil(t, dispatcher.endpoints.Admin)
+
+	dispatcher.registerAdminEndpoint(store)
+
+	res, err := dispatcher.Handle([]byte(`{"method": "admin_addPeer", "params": ["/ip4/127.0.0.1/tcp/1478/p2p/A"]}`))
+	require.NoError(t, err)
+
+	added := false
+	require.NoError(t, expectJSONResult(res, &added))
+	assert.True(t, added)
+
+	res, err = dispatcher.Handle([]byte(`{"method": "admin_peers", "params": []}`))
+	require.NoError(t, err)
+
+	peers := []*AdminPeer{}
+	require.NoError(t, expectJSONResult(res, &peers))
+	require.Len(t, peers, 1)
+	assert.Equal(t, "/ip4/127.0.0.1/tcp/1478/p2p/A", peers[0].ID)
+	assert.True(t, peers[0].Static)
+
+	res, err = dispatcher.Handle([]byte(`{"method": "admin_removePeer", "params": ["/ip4/127.0.0.1/tcp/1478/p2p/A"]}`))
+	require.NoError(t, err)
+
+	removed := false
+	require.NoError(t, expectJSONResult(res, &removed))
+	assert.True(t, removed)
+
+	res, err = dispatcher.Handle([]byte(`{"method": "admin_removePeer", "params": ["/ip4/127.0.0.1/tcp/1478/p2p/A"]}`))
+	require.NoError(t, err)
+	assert.Error(t, expectJSONResult(res, &removed))
+
+	res, err = dispatcher.Handle([]byte(`{"method": "admin_reloadConfig", "params": []}`))
+	require.NoError(t, err)
+
+	reloaded := false
+	require.NoError(t, expectJSONResult(res, &reloaded))
+	assert.True(t, reloaded)
+	assert.Equal(t, 1, store.reloaded)
+}
@@ -0,0 +1,225 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrTraceGenesisBlock = errors.New("genesis is not traceable")
+)
+
+// debugStore provides access to the methods needed by debug endpoint
+type debugStore interface {
+	// Header returns the current header of the chain (genesis if empty)
+	Header() *types.Header
+
+	// GetBlockByHash gets a block using the provided hash
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+
+	// GetBlockByNumber returns a block using the provided number
+	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// ReadTxLookup returns a block hash in which a given txn was mined
+	ReadTxLookup(txnHash types.Hash) (types.Hash, bool)
+
+	// TraceBlock re-executes the block on top of its parent state,
+	// tracing every transaction with the tracer returned by getTracer
+	TraceBlock(block *types.Block, getTracer func(idx int, tx *types.Transaction) runtime.Tracer) error
+
+	// GetOpcodeStats returns the opcode stats recorded while processing the block with the given hash
+	GetOpcodeStats(hash types.Hash) (*tracer.OpcodeStats, bool)
+}
+
+// Debug is the debug jsonrpc endpoint
+type Debug struct {
+	store debugStore
+}
+
+// TraceConfig is the configuration of the tracer used by the debug endpoints
+type TraceConfig struct {
+	Tracer         string `json:"tracer"`
+	DisableStack   bool   `json:"disableStack"`
+	DisableStorage bool   `json:"disableStorage"`
+	EnableMemory   bool   `json:"enableMemory"`
+}
+
+// txTraceResult is the trace of a single transaction of the traced block
+type txTraceResult struct {
+	TxHash types.Hash  `json:"txHash"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// TraceTransaction returns the execution trace of the transaction with the given hash
+func (d *Debug) TraceTransaction(hash types.Hash, config *TraceConfig) (interface{}, error) {
+	blockHash, ok := d.store.ReadTxLookup(hash)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", hash)
+	}
+
+	block, ok := d.store.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", blockHash)
+	}
+
+	txIndex := -1
+
+	for idx, tx := range block.Transactions {
+		if tx.Hash == hash {
+			txIndex = idx
+
+			break
+		}
+	}
+
+	if txIndex < 0 {
+		return nil, fmt.Errorf("transaction %s not found in block %s", hash, blockHash)
+	}
+
+	// the transactions following the traced one don't have to be executed
+	block = &types.Block{
+		Header:       block.Header,
+		Transactions: block.Transactions[:txIndex+1],
+	}
+
+	traces, err := d.traceBlock(block, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return traces[txIndex].Result, nil
+}
+
+// TraceBlockByNumber returns the execution traces of all the transactions in the block
+func (d *Debug) TraceBlockByNumber(number BlockNumber, config *TraceConfig) (interface{}, error) {
+	var num uint64
+
+	switch number {
+	case LatestBlockNumber:
+		num = d.store.Header().Number
+	case EarliestBlockNumber:
+		num = 0
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("tracing the pending block is not supported")
+	default:
+		if number < 0 {
+			return nil, fmt.Errorf("invalid argument 0: block number larger than int64")
+		}
+
+		num = uint64(number)
+	}
+
+	block, ok := d.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	return d.traceBlock(block, config)
+}
+
+// TraceBlockByHash returns the execution traces of all the transactions in the block
+func (d *Debug) TraceBlockByHash(hash types.Hash, config *TraceConfig) (interface{}, error) {
+	block, ok := d.store.GetBlockByHash(hash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", hash)
+	}
+
+	return d.traceBlock(block, config)
+}
+
+// OpcodeStats returns the opcodes executed in the block and the contracts which consumed the most gas.
+// The stats are recorded only if enabled, for the latest processed blocks
+func (d *Debug) OpcodeStats(number BlockNumber) (interface{}, error) {
+	var num uint64
+
+	switch number {
+	case LatestBlockNumber:
+		num = d.store.Header().Number
+	case EarliestBlockNumber:
+		num = 0
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("opcode stats of the pending block are not supported")
+	default:
+		if number < 0 {
+			return nil, fmt.Errorf("invalid argument 0: block number larger than int64")
+		}
+
+		num = uint64(number)
+	}
+
+	block, ok := d.store.GetBlockByNumber(num, false)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	stats, ok := d.store.GetOpcodeStats(block.Hash())
+	if !ok {
+		return nil, fmt.Errorf("opcode stats not recorded for block %d", num)
+	}
+
+	return stats, nil
+}
+
+// traceBlock re-executes the block and collects the results of the per-transaction tracers
+func (d *Debug) traceBlock(block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
+	if block.Number() == 0 {
+		return nil, ErrTraceGenesisBlock
+	}
+
+	if config == nil {
+		config = &TraceConfig{}
+	}
+
+	// validate the tracer name before the block is re-executed
+	if _, err := newTracer(config); err != nil {
+		return nil, err
+	}
+
+	tracers := make([]tracer.Tracer, len(block.Transactions))
+
+	if err := d.store.TraceBlock(block, func(idx int, _ *types.Transaction) runtime.Tracer {
+		tracers[idx], _ = newTracer(config)
+
+		return tracers[idx]
+	}); err != nil {
+		return nil, err
+	}
+
+	results := make([]*txTraceResult, len(block.Transactions))
+
+	for idx, tx := range block.Transactions {
+		results[idx] = &txTraceResult{
+			TxHash: tx.Hash,
+		}
+
+		if tracers[idx] == nil {
+			results[idx].Error = "transaction was not executed"
+
+			continue
+		}
+
+		res, err := tracers[idx].GetResult()
+		if err != nil {
+			results[idx].Error = err.Error()
+
+			continue
+		}
+
+		results[idx].Result = res
+	}
+
+	return results, nil
+}
+
+func newTracer(config *TraceConfig) (tracer.Tracer, error) {
+	return tracer.New(config.Tracer, &tracer.Config{
+		DisableStack:   config.DisableStack,
+		DisableStorage: config.DisableStorage,
+		EnableMemory:   config.EnableMemory,
+	})
+}
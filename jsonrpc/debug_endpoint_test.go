@@ -0,0 +1,176 @@
+package jsonrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var genesisHash = types.StringToHash("genesis")
+
+type debugEndpointMockStore struct {
+	header *types.Header
+	blocks map[types.Hash]*types.Block
+
+	// number of transactions executed by the last TraceBlock call
+	traced int
+
+	opcodeStats map[types.Hash]*tracer.OpcodeStats
+}
+
+func (s *debugEndpointMockStore) Header() *types.Header {
+	return s.header
+}
+
+func (s *debugEndpointMockStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	block, ok := s.blocks[hash]
+
+	return block, ok
+}
+
+func (s *debugEndpointMockStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	for _, block := range s.blocks {
+		if block.Number() == num {
+			return block, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *debugEndpointMockStore) ReadTxLookup(txnHash types.Hash) (types.Hash, bool) {
+	for hash, block := range s.blocks {
+		for _, tx := range block.Transactions {
+			if tx.Hash == txnHash {
+				return hash, true
+			}
+		}
+	}
+
+	return types.ZeroHash, false
+}
+
+func (s *debugEndpointMockStore) TraceBlock(
+	block *types.Block,
+	getTracer func(idx int, tx *types.Transaction) runtime.Tracer,
+) error {
+	s.traced = 0
+
+	for idx, tx := range block.Transactions {
+		if tracer := getTracer(idx, tx); tracer != nil {
+			tracer.TxStart(nil, tx)
+			tracer.CallStart(1, runtime.Call, tx.From, *tx.To, tx.Input, tx.Gas, tx.Value)
+			tracer.CallEnd(1, nil, 0, nil)
+			tracer.TxEnd(&runtime.ExecutionResult{GasUsed: 21000})
+		}
+
+		s.traced++
+	}
+
+	return nil
+}
+
+func (s *debugEndpointMockStore) GetOpcodeStats(hash types.Hash) (*tracer.OpcodeStats, bool) {
+	stats, ok := s.opcodeStats[hash]
+
+	return stats, ok
+}
+
+func newDebugEndpointMockStore() *debugEndpointMockStore {
+	block := &types.Block{
+		Header: &types.Header{
+			Number: 1,
+			Hash:   hash1,
+		},
+		Transactions: []*types.Transaction{
+			{Hash: hash2, To: &addr0, Gas: 21000},
+			{Hash: hash3, To: &addr0, Gas: 21000},
+		},
+	}
+
+	return &debugEndpointMockStore{
+		header: block.Header,
+		blocks: map[types.Hash]*types.Block{
+			hash1:       block,
+			genesisHash: {Header: &types.Header{Number: 0, Hash: genesisHash}},
+		},
+	}
+}
+
+func TestDebug_TraceTransaction(t *testing.T) {
+	t.Parallel()
+
+	store := newDebugEndpointMockStore()
+	debug := &Debug{store}
+
+	res, err := debug.TraceTransaction(hash2, &TraceConfig{Tracer: tracer.CallTracerName})
+	assert.NoError(t, err)
+	assert.IsType(t, &tracer.CallFrame{}, res)
+
+	// the transactions following the traced one are not executed
+	assert.Equal(t, 1, store.traced)
+
+	_, err = debug.TraceTransaction(types.StringToHash("unknown"), nil)
+	assert.Error(t, err)
+}
+
+func TestDebug_TraceBlock(t *testing.T) {
+	t.Parallel()
+
+	store := newDebugEndpointMockStore()
+	debug := &Debug{store}
+
+	res, err := debug.TraceBlockByNumber(LatestBlockNumber, nil)
+	assert.NoError(t, err)
+
+	traces, ok := res.([]*txTraceResult)
+	assert.True(t, ok)
+	assert.Len(t, traces, 2)
+	assert.Equal(t, hash2, traces[0].TxHash)
+	assert.Equal(t, hash3, traces[1].TxHash)
+	assert.IsType(t, &tracer.StructLoggerResult{}, traces[1].Result)
+
+	_, err = debug.TraceBlockByHash(hash1, &TraceConfig{Tracer: "unknown"})
+	assert.Error(t, err)
+
+	_, err = debug.TraceBlockByHash(genesisHash, nil)
+	assert.True(t, errors.Is(err, ErrTraceGenesisBlock))
+}
+
+func TestDebug_OpcodeStats(t *testing.T) {
+	t.Parallel()
+
+	store := newDebugEndpointMockStore()
+	store.opcodeStats = map[types.Hash]*tracer.OpcodeStats{
+		hash1: {
+			Number:   1,
+			Hash:     hash1,
+			TotalOps: 3,
+			Opcodes:  map[string]uint64{"PUSH1": 2, "SSTORE": 1},
+		},
+	}
+
+	debug := &Debug{store}
+
+	res, err := debug.OpcodeStats(LatestBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, store.opcodeStats[hash1], res)
+
+	res, err = debug.OpcodeStats(BlockNumber(1))
+	assert.NoError(t, err)
+	assert.Equal(t, store.opcodeStats[hash1], res)
+
+	// the stats of the genesis aren't recorded
+	_, err = debug.OpcodeStats(EarliestBlockNumber)
+	assert.ErrorContains(t, err, "opcode stats not recorded for block 0")
+
+	_, err = debug.OpcodeStats(BlockNumber(5))
+	assert.ErrorContains(t, err, "block 5 not found")
+
+	_, err = debug.OpcodeStats(PendingBlockNumber)
+	assert.Error(t, err)
+}
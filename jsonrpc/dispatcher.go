@@ -8,9 +8,13 @@ import (
 	"math"
 	"reflect"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/hashicorp/go-hclog"
+	"go.uber.org/atomic"
+
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
 )
 
 type serviceData struct {
@@ -23,17 +27,40 @@ type funcData struct {
 	reqt  []reflect.Type
 	fv    reflect.Value
 	isDyn bool
+
+	// recv is the receiver of the methods added to a service by another value, the service if not set
+	recv reflect.Value
 }
 
 func (f *funcData) numParams() int {
 	return f.inNum - 1
 }
 
+// headPinner is implemented by the endpoints which need all the state reads
+// of a single request to be served from the same chain head
+type headPinner interface {
+	pinHead() interface{}
+}
+
+// clientBinder is implemented by the endpoints whose methods depend on the client of the request
+type clientBinder interface {
+	bindClient(client string) interface{}
+}
+
 type endpoints struct {
-	Eth    *Eth
-	Web3   *Web3
-	Net    *Net
-	TxPool *TxPool
+	Eth         *Eth
+	Web3        *Web3
+	Net         *Net
+	TxPool      *TxPool
+	Debug       *Debug
+	UnsafeDebug *UnsafeDebug
+	Trace       *Trace
+	Webhook     *Webhook
+	Admin       *Admin
+	Evm         *Evm
+	Accumulator *Accumulator
+	Edge        *Edge
+	Profiler    *Profiler
 }
 
 // Dispatcher handles all json rpc requests by delegating
@@ -53,7 +80,16 @@ type dispatcherParams struct {
 
 	priceLimit              uint64
 	jsonRPCBatchLengthLimit uint64
+	jsonRPCBatchGasLimit    uint64
 	blockRangeLimit         uint64
+	stateHistory            uint64
+	statePinInterval        uint64
+
+	filterTimeout       time.Duration
+	maxFiltersPerClient uint64
+	filtersPath         string
+
+	txLimits txLimiterConfig
 }
 
 func newDispatcher(
@@ -68,6 +104,16 @@ func newDispatcher(
 
 	if store != nil {
 		d.filterManager = NewFilterManager(logger, store, params.blockRangeLimit)
+		d.filterManager.maxFiltersPerClient = params.maxFiltersPerClient
+
+		if params.filterTimeout != 0 {
+			d.filterManager.timeout = params.filterTimeout
+		}
+
+		if err := d.filterManager.restoreFilters(params.filtersPath); err != nil {
+			d.logger.Error("failed to restore the persisted filters", "err", err)
+		}
+
 		go d.filterManager.Run()
 	}
 
@@ -82,7 +128,13 @@ func (d *Dispatcher) registerEndpoints(store JSONRPCStore) {
 		store,
 		d.params.chainID,
 		d.filterManager,
-		d.params.priceLimit,
+		atomic.NewUint64(d.params.priceLimit),
+		stateHistory{
+			horizon:     d.params.stateHistory,
+			pinInterval: d.params.statePinInterval,
+		},
+		newTxLimiter(d.params.txLimits),
+		"",
 	}
 	d.endpoints.Net = &Net{
 		store,
@@ -93,11 +145,66 @@ func (d *Dispatcher) registerEndpoints(store JSONRPCStore) {
 		d.params.chainName,
 	}
 	d.endpoints.TxPool = &TxPool{store}
+	d.endpoints.Debug = &Debug{store}
+	d.endpoints.Trace = &Trace{
+		store,
+		d.endpoints.Eth,
+		d.params.blockRangeLimit,
+	}
+	d.endpoints.Accumulator = &Accumulator{store}
+	d.endpoints.Edge = &Edge{store}
 
 	d.registerService("eth", d.endpoints.Eth)
 	d.registerService("net", d.endpoints.Net)
 	d.registerService("web3", d.endpoints.Web3)
 	d.registerService("txpool", d.endpoints.TxPool)
+	d.registerService("debug", d.endpoints.Debug)
+	d.registerService("trace", d.endpoints.Trace)
+	d.registerService("accumulator", d.endpoints.Accumulator)
+	d.registerService("edge", d.endpoints.Edge)
+}
+
+// registerUnsafeDebugEndpoint replaces the debug endpoint with the one serving the chain surgery methods too,
+// which is only done if the unsafe debug endpoints are enabled
+func (d *Dispatcher) registerUnsafeDebugEndpoint(store UnsafeDebugStore) {
+	d.endpoints.UnsafeDebug = &UnsafeDebug{d.endpoints.Debug, store}
+
+	d.registerService("debug", d.endpoints.UnsafeDebug)
+}
+
+// registerWebhookEndpoint registers the webhook endpoint, which is only served if the webhooks are enabled
+func (d *Dispatcher) registerWebhookEndpoint(store WebhookStore) {
+	d.endpoints.Webhook = &Webhook{store}
+
+	d.registerService("webhook", d.endpoints.Webhook)
+}
+
+// setTxLimits replaces the price limit and the transaction rate limits of the eth endpoint
+func (d *Dispatcher) setTxLimits(priceLimit uint64, txLimits txLimiterConfig) {
+	d.endpoints.Eth.priceLimit.Store(priceLimit)
+	d.endpoints.Eth.txLimiter.setConfig(txLimits)
+}
+
+// registerAdminEndpoint registers the admin endpoint, which is only served if the admin endpoints are enabled
+func (d *Dispatcher) registerAdminEndpoint(store AdminStore) {
+	d.endpoints.Admin = &Admin{store}
+
+	d.registerService("admin", d.endpoints.Admin)
+}
+
+// registerProfilerEndpoint adds the profiling methods to the debug endpoint, which is only done
+// if the admin endpoints are enabled. It must be done once the debug endpoint is registered
+func (d *Dispatcher) registerProfilerEndpoint(store ProfilerStore) {
+	d.endpoints.Profiler = &Profiler{store}
+
+	d.extendService("debug", d.endpoints.Profiler)
+}
+
+// registerEvmEndpoint registers the evm endpoint, which is only served by the development chains
+func (d *Dispatcher) registerEvmEndpoint(store EvmStore) {
+	d.endpoints.Evm = &Evm{store}
+
+	d.registerService("evm", d.endpoints.Evm)
 }
 
 func (d *Dispatcher) getFnHandler(req Request) (*serviceData, *funcData, Error) {
@@ -128,6 +235,11 @@ type wsConn interface {
 	SetFilterID(string)
 }
 
+// clientConn is implemented by the connections of a remote client
+type clientConn interface {
+	Client() string
+}
+
 // as per https://www.jsonrpc.org/specification, the `id` in JSON-RPC 2.0
 // can only be a string or a non-decimal integer
 func formatFilterResponse(id interface{}, resp string) (string, Error) {
@@ -161,15 +273,47 @@ func (d *Dispatcher) handleSubscribe(req Request, conn wsConn) (string, Error) {
 		return "", NewSubscriptionNotFoundError(subscribeMethod)
 	}
 
+	// the subscriptions specific to the edge nodes are only served by edge_subscribe
+	if req.Method == "edge_subscribe" {
+		return d.handleEdgeSubscribe(subscribeMethod, params, conn)
+	}
+
 	var filterID string
 	if subscribeMethod == "newHeads" {
 		filterID = d.filterManager.NewBlockFilter(conn)
 	} else if subscribeMethod == "logs" {
-		logQuery, err := decodeLogQueryFromInterface(params[1])
-		if err != nil {
-			return "", NewInternalError(err.Error())
+		// the logs of every contract are streamed if no filter is given
+		logQuery := &LogQuery{}
+
+		if len(params) > 1 {
+			var err error
+			if logQuery, err = decodeLogQueryFromInterface(params[1]); err != nil {
+				return "", NewInvalidParamsError(err.Error())
+			}
 		}
+
 		filterID = d.filterManager.NewLogFilter(logQuery, conn)
+	} else if subscribeMethod == "newPendingTransactions" {
+		// the full transactions are streamed instead of the hashes if the second param is true
+		fullTx := false
+
+		if len(params) > 1 {
+			if fullTx, ok = params[1].(bool); !ok {
+				return "", NewInvalidParamsError("Invalid params")
+			}
+		}
+
+		filterID = d.filterManager.NewPendingTxFilter(fullTx, conn)
+	} else if subscribeMethod == "storageChanges" {
+		if len(params) < 2 {
+			return "", NewInvalidParamsError("Invalid params")
+		}
+
+		storageQuery, err := decodeStorageQueryFromInterface(params[1])
+		if err != nil {
+			return "", NewInvalidParamsError(err.Error())
+		}
+		filterID = d.filterManager.NewStorageFilter(storageQuery, conn)
 	} else {
 		return "", NewSubscriptionNotFoundError(subscribeMethod)
 	}
@@ -177,6 +321,24 @@ func (d *Dispatcher) handleSubscribe(req Request, conn wsConn) (string, Error) {
 	return filterID, nil
 }
 
+func (d *Dispatcher) handleEdgeSubscribe(subscribeMethod string, params []interface{}, conn wsConn) (string, Error) {
+	switch subscribeMethod {
+	case "accountChanges":
+		if len(params) < 2 {
+			return "", NewInvalidParamsError("Invalid params")
+		}
+
+		accountQuery, err := decodeAccountQueryFromInterface(params[1])
+		if err != nil {
+			return "", NewInvalidParamsError(err.Error())
+		}
+
+		return d.filterManager.NewAccountFilter(accountQuery, conn), nil
+	default:
+		return "", NewSubscriptionNotFoundError(subscribeMethod)
+	}
+}
+
 func (d *Dispatcher) handleUnsubscribe(req Request) (bool, Error) {
 	var params []interface{}
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -200,14 +362,25 @@ func (d *Dispatcher) RemoveFilterByWs(conn wsConn) {
 }
 
 func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
+	if isBatchRequest(reqBody) {
+		return d.handleBatch(reqBody, func(req Request) ([]byte, error) {
+			return d.handleWsReq(req, conn)
+		})
+	}
+
 	var req Request
 	if err := json.Unmarshal(reqBody, &req); err != nil {
 		return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 	}
 
+	return d.handleWsReq(req, conn)
+}
+
+// handleWsReq handles a single request received over the websocket connection
+func (d *Dispatcher) handleWsReq(req Request, conn wsConn) ([]byte, error) {
 	// if the request method is eth_subscribe we need to create a
 	// new filter with ws connection
-	if req.Method == "eth_subscribe" {
+	if req.Method == "eth_subscribe" || req.Method == "edge_subscribe" {
 		filterID, err := d.handleSubscribe(req, conn)
 		if err != nil {
 			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
@@ -222,10 +395,10 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 		return []byte(resp), nil
 	}
 
-	if req.Method == "eth_unsubscribe" {
+	if req.Method == "eth_unsubscribe" || req.Method == "edge_unsubscribe" {
 		ok, err := d.handleUnsubscribe(req)
 		if err != nil {
-			return nil, err
+			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
 		}
 
 		res := "false"
@@ -242,15 +415,23 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 	}
 
 	// its a normal query that we handle with the dispatcher
-	resp, err := d.handleReq(req)
-	if err != nil {
-		return nil, err
+	client := ""
+	if c, ok := conn.(clientConn); ok {
+		client = c.Client()
 	}
 
+	resp, err := d.handleReq(req, client)
+
 	return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
 }
 
 func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
+	return d.HandleFrom("", reqBody)
+}
+
+// HandleFrom handles the request body sent by the client, which is
+// used to enforce the per-client limits (e.g. the number of filters)
+func (d *Dispatcher) HandleFrom(client string, reqBody []byte) ([]byte, error) {
 	x := bytes.TrimLeft(reqBody, " \t\r\n")
 	if len(x) == 0 {
 		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
@@ -266,14 +447,31 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 			return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 		}
 
-		resp, err := d.handleReq(req)
+		resp, err := d.handleReq(req, client)
 
 		return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
 	}
 
-	// handle batch requests
-	var requests []Request
-	if err := json.Unmarshal(reqBody, &requests); err != nil {
+	return d.handleBatch(reqBody, func(req Request) ([]byte, error) {
+		resp, err := d.handleReq(req, client)
+
+		return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
+	})
+}
+
+// isBatchRequest checks if the request body is a batch (json array) of requests
+func isBatchRequest(reqBody []byte) bool {
+	x := bytes.TrimLeft(reqBody, " \t\r\n")
+
+	return len(x) > 0 && x[0] == '['
+}
+
+// handleBatch handles a batch of requests, passing every valid entry to handleEntry.
+// Every entry gets its own response, so the invalid entries and the ones exceeding
+// the batch gas limit don't fail the whole batch
+func (d *Dispatcher) handleBatch(reqBody []byte, handleEntry func(Request) ([]byte, error)) ([]byte, error) {
+	var rawRequests []json.RawMessage
+	if err := json.Unmarshal(reqBody, &rawRequests); err != nil {
 		return NewRPCResponse(
 			nil,
 			"2.0",
@@ -282,8 +480,17 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 		).Bytes()
 	}
 
+	if len(rawRequests) == 0 {
+		return NewRPCResponse(
+			nil,
+			"2.0",
+			nil,
+			NewInvalidRequestError("Empty batch request"),
+		).Bytes()
+	}
+
 	// if not disabled, avoid handling long batch requests
-	if d.params.jsonRPCBatchLengthLimit != 0 && len(requests) > int(d.params.jsonRPCBatchLengthLimit) {
+	if d.params.jsonRPCBatchLengthLimit != 0 && len(rawRequests) > int(d.params.jsonRPCBatchLengthLimit) {
 		return NewRPCResponse(
 			nil,
 			"2.0",
@@ -292,18 +499,33 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 		).Bytes()
 	}
 
-	responses := make([]Response, 0)
+	var (
+		responses = make([]json.RawMessage, 0, len(rawRequests))
+		gasUsed   = uint64(0)
+	)
 
-	for _, req := range requests {
-		var response, err = d.handleReq(req)
-		if err != nil {
-			errorResponse := NewRPCResponse(req.ID, "2.0", nil, err)
-			responses = append(responses, errorResponse)
+	for _, rawReq := range rawRequests {
+		var (
+			resp []byte
+			err  error
+		)
 
-			continue
+		var req Request
+		if jsonErr := json.Unmarshal(rawReq, &req); jsonErr != nil || req.Method == "" {
+			resp, err = NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
+		} else if gas, ok := d.callGas(req); ok && d.params.jsonRPCBatchGasLimit != 0 &&
+			gasUsed+gas > d.params.jsonRPCBatchGasLimit {
+			// if not disabled, avoid executing too many calls in a single batch
+			resp, err = NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Batch gas limit exceeded")).Bytes()
+		} else {
+			gasUsed += gas
+			resp, err = handleEntry(req)
+		}
+
+		if err != nil {
+			return NewRPCResponse(nil, "2.0", nil, NewInternalError("Internal error")).Bytes()
 		}
 
-		resp := NewRPCResponse(req.ID, "2.0", response, nil)
 		responses = append(responses, resp)
 	}
 
@@ -315,7 +537,35 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	return respBytes, nil
 }
 
-func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
+// callGas returns the gas limit of the call-type request (eth_call, eth_estimateGas),
+// which is the block gas limit if the gas is not set in the transaction
+func (d *Dispatcher) callGas(req Request) (uint64, bool) {
+	if req.Method != "eth_call" && req.Method != "eth_estimateGas" {
+		return 0, false
+	}
+
+	var (
+		params []json.RawMessage
+		arg    struct {
+			Gas *argUint64 `json:"gas"`
+		}
+	)
+
+	// the params are validated by the endpoint, so only the gas is parsed here
+	if err := json.Unmarshal(req.Params, &params); err == nil && len(params) > 0 {
+		if err := json.Unmarshal(params[0], &arg); err == nil && arg.Gas != nil {
+			return uint64(*arg.Gas), true
+		}
+	}
+
+	if d.endpoints.Eth == nil || d.endpoints.Eth.store == nil {
+		return 0, true
+	}
+
+	return d.endpoints.Eth.store.Header().GasLimit, true
+}
+
+func (d *Dispatcher) handleReq(req Request, client string) ([]byte, Error) {
 	d.logger.Debug("request", "method", req.Method, "id", req.ID)
 
 	service, fd, ferr := d.getFnHandler(req)
@@ -323,9 +573,39 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 		return nil, ferr
 	}
 
+	// only the calls of the resolved methods are timed, so the unknown methods don't add labels
+	start := time.Now()
+	data, err := d.call(service, fd, req, client)
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	telemetry.ObserveSince(telemetry.JSONRPCMethodDuration, start, req.Method, status)
+
+	return data, err
+}
+
+// call calls the function of the method with the params of the request
+func (d *Dispatcher) call(service *serviceData, fd *funcData, req Request, client string) ([]byte, Error) {
 	inArgs := make([]reflect.Value, fd.inNum)
 	inArgs[0] = service.sv
 
+	if fd.recv.IsValid() {
+		inArgs[0] = fd.recv
+	}
+
+	// pin the chain head for the whole request, so new blocks
+	// arriving mid-request can't cause a torn view of the state
+	if pinner, ok := inArgs[0].Interface().(headPinner); ok {
+		inArgs[0] = reflect.ValueOf(pinner.pinHead())
+	}
+
+	if binder, ok := inArgs[0].Interface().(clientBinder); ok && client != "" {
+		inArgs[0] = reflect.ValueOf(binder.bindClient(client))
+	}
+
 	inputs := make([]interface{}, fd.numParams())
 
 	for i := 0; i < fd.inNum-1; i++ {
@@ -344,6 +624,13 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 	if err := getError(output[1]); err != nil {
 		d.logInternalError(req.Method, err)
 
+		// the errors with their own code and data are reported as they are,
+		// like the unavailable state along with the earliest available block
+		var dataErr dataError
+		if errors.As(err, &dataErr) {
+			return nil, dataErr
+		}
+
 		return nil, NewInvalidRequestError(err.Error())
 	}
 
@@ -377,6 +664,29 @@ func (d *Dispatcher) registerService(serviceName string, service interface{}) {
 		panic("jsonrpc: serviceName cannot be empty")
 	}
 
+	d.serviceMap[serviceName] = &serviceData{
+		sv:      reflect.ValueOf(service),
+		funcMap: serviceFuncs(serviceName, service),
+	}
+}
+
+// extendService adds the methods of the value to the registered service, they are called on the value
+func (d *Dispatcher) extendService(serviceName string, methods interface{}) {
+	service, ok := d.serviceMap[serviceName]
+	if !ok {
+		panic(fmt.Sprintf("jsonrpc: service '%s' is not registered", serviceName))
+	}
+
+	recv := reflect.ValueOf(methods)
+
+	for name, fd := range serviceFuncs(serviceName, methods) {
+		fd.recv = recv
+		service.funcMap[name] = fd
+	}
+}
+
+// serviceFuncs returns the functions of the exported methods of the service, by their name
+func serviceFuncs(serviceName string, service interface{}) map[string]*funcData {
 	st := reflect.TypeOf(service)
 	if st.Kind() == reflect.Struct {
 		panic(fmt.Sprintf("jsonrpc: service '%s' must be a pointer to struct", serviceName))
@@ -413,10 +723,7 @@ func (d *Dispatcher) registerService(serviceName string, service interface{}) {
 		funcMap[name] = fd
 	}
 
-	d.serviceMap[serviceName] = &serviceData{
-		sv:      reflect.ValueOf(service),
-		funcMap: funcMap,
-	}
+	return funcMap
 }
 
 func validateFunc(funcName string, fv reflect.Value, _ bool) (inNum int, reqt []reflect.Type, err error) {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -99,6 +100,98 @@ func TestDispatcher_HandleWebsocketConnection_EthSubscribe(t *testing.T) {
 			t.Fatal("\"newHeads\" event not received in 2 seconds")
 		}
 	})
+
+	t.Run("clients should be able to subscribe to \"logs\" and \"newPendingTransactions\"", func(t *testing.T) {
+		t.Parallel()
+
+		dispatcher := newDispatcher(
+			hclog.NewNullLogger(),
+			newMockStore(),
+			&dispatcherParams{
+				jsonRPCBatchLengthLimit: 20,
+				blockRangeLimit:         1000,
+			},
+		)
+
+		cases := []struct {
+			params string
+			valid  bool
+		}{
+			{`["logs"]`, true},
+			{`["logs", {"address": "0x0000000000000000000000000000000000000001"}]`, true},
+			{`["logs", {"address": "0x1"}]`, false},
+			{`["newPendingTransactions"]`, true},
+			{`["newPendingTransactions", true]`, true},
+			{`["newPendingTransactions", "full"]`, false},
+		}
+
+		for _, c := range cases {
+			mockConnection, _ := newMockWsConnWithMsgCh()
+
+			res, err := dispatcher.HandleWs(
+				[]byte(`{"id":1,"jsonrpc":"2.0","method":"eth_subscribe","params":`+c.params+`}`),
+				mockConnection,
+			)
+			assert.NoError(t, err)
+
+			var resp SuccessResponse
+
+			assert.NoError(t, json.Unmarshal(res, &resp))
+
+			if c.valid {
+				assert.Nil(t, resp.Error, c.params)
+				assert.Equal(t, mockConnection.GetFilterID(), strings.Trim(string(resp.Result), `"`))
+			} else {
+				assert.NotNil(t, resp.Error, c.params)
+			}
+		}
+	})
+
+	t.Run("clients should be able to subscribe to \"accountChanges\" thru edge_subscribe", func(t *testing.T) {
+		t.Parallel()
+
+		dispatcher := newDispatcher(
+			hclog.NewNullLogger(),
+			newMockStore(),
+			&dispatcherParams{
+				jsonRPCBatchLengthLimit: 20,
+				blockRangeLimit:         1000,
+			},
+		)
+
+		cases := []struct {
+			method string
+			params string
+			valid  bool
+		}{
+			{"edge_subscribe", `["accountChanges", ["0x0000000000000000000000000000000000000001"]]`, true},
+			{"edge_subscribe", `["accountChanges"]`, false},
+			{"edge_subscribe", `["accountChanges", []]`, false},
+			{"edge_subscribe", `["newHeads"]`, false},
+			{"eth_subscribe", `["accountChanges", ["0x0000000000000000000000000000000000000001"]]`, false},
+		}
+
+		for _, c := range cases {
+			mockConnection, _ := newMockWsConnWithMsgCh()
+
+			res, err := dispatcher.HandleWs(
+				[]byte(`{"id":1,"jsonrpc":"2.0","method":"`+c.method+`","params":`+c.params+`}`),
+				mockConnection,
+			)
+			assert.NoError(t, err)
+
+			var resp SuccessResponse
+
+			assert.NoError(t, json.Unmarshal(res, &resp))
+
+			if c.valid {
+				assert.Nil(t, resp.Error, c.params)
+				assert.Equal(t, mockConnection.GetFilterID(), strings.Trim(string(resp.Result), `"`))
+			} else {
+				assert.NotNil(t, resp.Error, c.params)
+			}
+		}
+	})
 }
 
 func TestDispatcher_WebsocketConnection_RequestFormats(t *testing.T) {
@@ -230,7 +323,7 @@ func TestDispatcherFuncDecode(t *testing.T) {
 		_, err := dispatcher.handleReq(Request{
 			Method: "mock_" + typ,
 			Params: []byte(msg),
-		})
+		}, "")
 		assert.NoError(t, err)
 
 		return <-srv.msgCh
@@ -455,3 +548,99 @@ func TestDispatcherBatchRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestDispatcherBatchRequest_PerEntryErrors(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := newDispatcher(
+		hclog.NewNullLogger(),
+		newMockStore(),
+		&dispatcherParams{
+			jsonRPCBatchLengthLimit: 10,
+			jsonRPCBatchGasLimit:    50000,
+			blockRangeLimit:         1000,
+		},
+	)
+
+	t.Run("empty batch", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := dispatcher.Handle([]byte(`[]`))
+		assert.NoError(t, err)
+
+		var resp ErrorResponse
+
+		assert.NoError(t, expectBatchJSONResult(res, &resp))
+		assert.Equal(t, &ObjectError{Code: -32600, Message: "Empty batch request"}, resp.Error)
+	})
+
+	t.Run("invalid entries and gas limit", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := dispatcher.Handle([]byte(`[
+			{"id":1,"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["latest", true]},
+			1,
+			{"id":3,"jsonrpc":"2.0"},
+			{"id":4,"jsonrpc":"2.0","method":"eth_call","params":[{"to":"0x1","gas":"0x186a0"}, "latest"]}]`))
+		assert.NoError(t, err)
+
+		var batchResp []SuccessResponse
+
+		assert.NoError(t, expectBatchJSONResult(res, &batchResp))
+		assert.Len(t, batchResp, 4)
+
+		assert.Nil(t, batchResp[0].Error)
+		assert.Equal(t, &ObjectError{Code: -32600, Message: "Invalid json request"}, batchResp[1].Error)
+		assert.Equal(t, &ObjectError{Code: -32600, Message: "Invalid json request"}, batchResp[2].Error)
+		assert.Equal(t, &ObjectError{Code: -32600, Message: "Batch gas limit exceeded"}, batchResp[3].Error)
+	})
+
+	t.Run("websocket batch", func(t *testing.T) {
+		t.Parallel()
+
+		mockConnection, _ := newMockWsConnWithMsgCh()
+
+		res, err := dispatcher.HandleWs([]byte(`[
+			{"id":1,"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["latest", true]},
+			{"id":2,"jsonrpc":"2.0","method":"web3_sha3","params":["0x68656c6c6f20776f726c64"]}]`), mockConnection)
+		assert.NoError(t, err)
+
+		var batchResp []SuccessResponse
+
+		assert.NoError(t, expectBatchJSONResult(res, &batchResp))
+		assert.Len(t, batchResp, 2)
+
+		for _, resp := range batchResp {
+			assert.Nil(t, resp.Error)
+		}
+	})
+}
+
+func TestDispatcher_MaxFiltersPerClient(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := newDispatcher(
+		hclog.NewNullLogger(),
+		newMockStore(),
+		&dispatcherParams{
+			maxFiltersPerClient: 1,
+		},
+	)
+
+	newFilter := func(client string) *ObjectError {
+		res, err := dispatcher.HandleFrom(client, []byte(`{"id":1,"jsonrpc":"2.0","method":"eth_newBlockFilter"}`))
+		assert.NoError(t, err)
+
+		var resp SuccessResponse
+
+		assert.NoError(t, json.Unmarshal(res, &resp))
+
+		return resp.Error
+	}
+
+	assert.Nil(t, newFilter("127.0.0.1"))
+	assert.Equal(t, &ObjectError{Code: -32600, Message: ErrTooManyFilters.Error()}, newFilter("127.0.0.1"))
+
+	// the limit is per client
+	assert.Nil(t, newFilter("127.0.0.2"))
+}
@@ -0,0 +1,112 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var ErrWitnessGenesisBlock = errors.New("genesis has no witness")
+
+// edgeStore provides access to the methods needed by the edge endpoint
+type edgeStore interface {
+	// Header returns the current header of the chain (genesis if empty)
+	Header() *types.Header
+
+	// GetBlockByNumber returns a block using the provided number
+	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// GetBlockWitness re-executes the block on top of its parent state, recording the state it reads
+	GetBlockWitness(block *types.Block) (*state.Witness, error)
+}
+
+// Edge is the edge jsonrpc endpoint, serving the data of the chain specific to the edge nodes
+type Edge struct {
+	store edgeStore
+}
+
+type witnessBlockHash struct {
+	Number argUint64  `json:"number"`
+	Hash   types.Hash `json:"hash"`
+}
+
+type blockWitness struct {
+	BlockNumber     argUint64          `json:"blockNumber"`
+	BlockHash       types.Hash         `json:"blockHash"`
+	ParentStateRoot types.Hash         `json:"parentStateRoot"`
+	StateRoot       types.Hash         `json:"stateRoot"`
+	Nodes           []argBytes         `json:"nodes"`
+	Codes           []argBytes         `json:"codes"`
+	BlockHashes     []witnessBlockHash `json:"blockHashes"`
+}
+
+// GetBlockWitness returns the trie nodes, the contract code and the ancestor hashes read while executing the block,
+// which a stateless verifier needs to execute it again from the state root of its parent
+func (e *Edge) GetBlockWitness(number BlockNumber) (interface{}, error) {
+	var num uint64
+
+	switch number {
+	case LatestBlockNumber:
+		num = e.store.Header().Number
+	case EarliestBlockNumber:
+		num = 0
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("the witness of the pending block is not supported")
+	default:
+		if number < 0 {
+			return nil, fmt.Errorf("invalid argument 0: block number larger than int64")
+		}
+
+		num = uint64(number)
+	}
+
+	if num == 0 {
+		return nil, ErrWitnessGenesisBlock
+	}
+
+	block, ok := e.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	parent, ok := e.store.GetBlockByNumber(num-1, false)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num-1)
+	}
+
+	witness, err := e.store.GetBlockWitness(block)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &blockWitness{
+		BlockNumber:     argUint64(block.Number()),
+		BlockHash:       block.Hash(),
+		ParentStateRoot: parent.Header.StateRoot,
+		StateRoot:       block.Header.StateRoot,
+		Nodes:           make([]argBytes, len(witness.Nodes)),
+		Codes:           make([]argBytes, len(witness.Codes)),
+		BlockHashes:     make([]witnessBlockHash, 0, len(witness.BlockHashes)),
+	}
+
+	for i, node := range witness.Nodes {
+		res.Nodes[i] = node
+	}
+
+	for i, code := range witness.Codes {
+		res.Codes[i] = code
+	}
+
+	for number, hash := range witness.BlockHashes {
+		res.BlockHashes = append(res.BlockHashes, witnessBlockHash{Number: argUint64(number), Hash: hash})
+	}
+
+	sort.Slice(res.BlockHashes, func(i, j int) bool {
+		return res.BlockHashes[i].Number < res.BlockHashes[j].Number
+	})
+
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+type mockEdgeStore struct {
+	edgeStore
+
+	blocks []*types.Block
+}
+
+func (m *mockEdgeStore) Header() *types.Header {
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockEdgeStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	if num >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[num], true
+}
+
+func (m *mockEdgeStore) GetBlockWitness(block *types.Block) (*state.Witness, error) {
+	return &state.Witness{
+		Nodes:       [][]byte{{0x1}},
+		Codes:       [][]byte{{0x2}},
+		BlockHashes: map[uint64]types.Hash{1: hash2, 0: hash1},
+	}, nil
+}
+
+func TestEdgeEndpoint_GetBlockWitness(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := newDispatcher(hclog.NewNullLogger(), nil, &dispatcherParams{})
+	dispatcher.endpoints.Edge.store = &mockEdgeStore{
+		blocks: []*types.Block{
+			{Header: &types.Header{Number: 0, StateRoot: hash1}},
+			{Header: &types.Header{Number: 1, StateRoot: hash2}},
+			{Header: &types.Header{Number: 2, StateRoot: hash3}},
+		},
+	}
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "edge_getBlockWitness", "params": ["latest"]}`))
+	require.NoError(t, err)
+
+	var witness blockWitness
+
+	require.NoError(t, expectJSONResult(resp, &witness))
+	assert.Equal(t, argUint64(2), witness.BlockNumber)
+	assert.Equal(t, hash2, witness.ParentStateRoot)
+	assert.Equal(t, hash3, witness.StateRoot)
+	assert.Equal(t, []argBytes{{0x1}}, witness.Nodes)
+	assert.Equal(t, []argBytes{{0x2}}, witness.Codes)
+
+	// the ancestor hashes are sorted by number
+	assert.Equal(t, []witnessBlockHash{{Number: 0, Hash: hash1}, {Number: 1, Hash: hash2}}, witness.BlockHashes)
+
+	// the genesis isn't executed
+	resp, err = dispatcher.Handle([]byte(`{"method": "edge_getBlockWitness", "params": ["earliest"]}`))
+	require.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &witness))
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "edge_getBlockWitness", "params": ["0x3"]}`))
+	require.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &witness))
+}
@@ -9,9 +9,12 @@ import (
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEth_Block_GetBlockByNumber(t *testing.T) {
@@ -30,6 +33,8 @@ func TestEth_Block_GetBlockByNumber(t *testing.T) {
 	}{
 		{"should be able to get the latest block number", LatestBlockNumber, true, false},
 		{"should be able to get the earliest block number", EarliestBlockNumber, true, false},
+		{"should be able to get the finalized block number", FinalizedBlockNumber, true, false},
+		{"should be able to get the safe block number", SafeBlockNumber, true, false},
 		{"should not be able to get block with negative number", BlockNumber(-50), false, true},
 		{"should be able to get block with number 0", BlockNumber(0), true, false},
 		{"should be able to get block with number 2", BlockNumber(2), true, false},
@@ -52,6 +57,27 @@ func TestEth_Block_GetBlockByNumber(t *testing.T) {
 	}
 }
 
+func TestEth_Block_GetBlockByNumber_Finalized(t *testing.T) {
+	store := &mockBlockStore{finalized: 6}
+	for i := 0; i < 10; i++ {
+		store.add(newTestBlock(uint64(i), hash1))
+	}
+
+	eth := newTestEthEndpoint(store)
+
+	for _, tag := range []string{"finalized", "safe"} {
+		number, err := stringToBlockNumber(tag)
+		assert.NoError(t, err)
+
+		res, err := eth.GetBlockByNumber(number, false)
+		assert.NoError(t, err)
+
+		block, ok := res.(*block)
+		assert.True(t, ok)
+		assert.Equal(t, argUint64(6), block.Number)
+	}
+}
+
 func TestEth_Block_GetBlockByHash(t *testing.T) {
 	store := &mockBlockStore{}
 	store.add(newTestBlock(1, hash1))
@@ -100,6 +126,28 @@ func TestEth_Block_GetBlockTransactionCountByNumber(t *testing.T) {
 	assert.Equal(t, res, 10)
 }
 
+func TestEth_Block_GetBlockTransactionCountByHash(t *testing.T) {
+	store := &mockBlockStore{}
+	block := newTestBlock(1, hash1)
+
+	for i := 0; i < 10; i++ {
+		block.Transactions = append(block.Transactions, []*types.Transaction{{Nonce: 0, From: addr0}}...)
+	}
+	store.add(block)
+
+	eth := newTestEthEndpoint(store)
+
+	res, err := eth.GetBlockTransactionCountByHash(block.Hash())
+
+	assert.NoError(t, err)
+	assert.Equal(t, res, 10)
+
+	res, err = eth.GetBlockTransactionCountByHash(hash2)
+
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+}
+
 func TestEth_GetTransactionByHash(t *testing.T) {
 	t.Parallel()
 
@@ -190,8 +238,9 @@ func TestEth_GetTransactionReceipt(t *testing.T) {
 		eth := newTestEthEndpoint(store)
 		block := newTestBlock(1, hash4)
 		store.add(block)
-		txn := newTestTransaction(uint64(0), addr0)
-		block.Transactions = append(block.Transactions, txn)
+		first := newTestTransaction(uint64(0), addr0)
+		txn := newTestTransaction(uint64(1), addr0)
+		block.Transactions = append(block.Transactions, first, txn)
 		rec := &types.Receipt{
 			Logs: []*types.Log{
 				{
@@ -200,9 +249,10 @@ func TestEth_GetTransactionReceipt(t *testing.T) {
 					},
 				},
 			},
+			LogIndex: 2,
 		}
 		rec.SetStatus(types.ReceiptSuccess)
-		store.receipts[hash4] = []*types.Receipt{rec}
+		store.receipts[hash4] = []*types.Receipt{{}, rec}
 
 		res, err := eth.GetTransactionReceipt(txn.Hash)
 
@@ -213,7 +263,10 @@ func TestEth_GetTransactionReceipt(t *testing.T) {
 		response := res.(*receipt)
 		assert.Equal(t, txn.Hash, response.TxHash)
 		assert.Equal(t, block.Hash(), response.BlockHash)
-		assert.NotNil(t, response.Logs)
+		assert.Equal(t, argUint64(1), response.TxIndex)
+		require.Len(t, response.Logs, 1)
+		assert.Equal(t, argUint64(1), response.Logs[0].TxIndex)
+		assert.Equal(t, argUint64(2), response.Logs[0].LogIndex)
 	})
 }
 
@@ -235,6 +288,7 @@ func TestEth_Syncing(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("0x%x", 1), response.StartingBlock)
 		assert.Equal(t, fmt.Sprintf("0x%x", 10), response.CurrentBlock)
 		assert.Equal(t, fmt.Sprintf("0x%x", 100), response.HighestBlock)
+		assert.Equal(t, string(progress.SyncModeFull), response.Mode)
 	})
 
 	t.Run("returns \"false\" if sync is not progress", func(t *testing.T) {
@@ -306,7 +360,7 @@ func TestEth_Call(t *testing.T) {
 			Nonce:    argUintPtr(0),
 		}
 
-		res, err := eth.Call(contractCall, BlockNumberOrHash{})
+		res, err := eth.Call(contractCall, BlockNumberOrHash{}, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), store.ethCallError.Error())
@@ -330,7 +384,7 @@ func TestEth_Call(t *testing.T) {
 			Nonce:    argUintPtr(0),
 		}
 
-		res, err := eth.Call(contractCall, BlockNumberOrHash{})
+		res, err := eth.Call(contractCall, BlockNumberOrHash{}, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
@@ -346,6 +400,7 @@ type mockBlockStore struct {
 	isSyncing       bool
 	averageGasPrice int64
 	ethCallError    error
+	finalized       uint64
 }
 
 func newMockBlockStore() *mockBlockStore {
@@ -452,6 +507,15 @@ func (m *mockBlockStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, e
 	return receipts, nil
 }
 
+func (m *mockBlockStore) GetReceipt(hash types.Hash, index uint64) (*types.Receipt, error) {
+	receipts := m.receipts[hash]
+	if index >= uint64(len(receipts)) {
+		return nil, types.ErrReceiptNotFound
+	}
+
+	return receipts[index], nil
+}
+
 func (m *mockBlockStore) GetHeaderByNumber(blockNumber uint64) (*types.Header, bool) {
 	b, ok := m.GetBlockByNumber(blockNumber, false)
 	if !ok {
@@ -481,10 +545,38 @@ func (m *mockBlockStore) GetBlockByHash(hash types.Hash, full bool) (*types.Bloc
 	return nil, false
 }
 
+func (m *mockBlockStore) GetTxCountByHash(hash types.Hash) (int, bool) {
+	block, ok := m.GetBlockByHash(hash, true)
+	if !ok {
+		return 0, false
+	}
+
+	return len(block.Transactions), true
+}
+
+func (m *mockBlockStore) IterateHeaders(from, to uint64, handler func(*types.Header) bool) error {
+	for i := from; i <= to; i++ {
+		block, ok := m.GetBlockByNumber(i, false)
+		if !ok {
+			return fmt.Errorf("header %d not found", i)
+		}
+
+		if !handler(block.Header) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 func (m *mockBlockStore) Header() *types.Header {
 	return m.blocks[len(m.blocks)-1].Header
 }
 
+func (m *mockBlockStore) FinalizedHeader() *types.Header {
+	return m.blocks[m.finalized].Header
+}
+
 func (m *mockBlockStore) ReadTxLookup(txnHash types.Hash) (types.Hash, bool) {
 	for _, block := range m.blocks {
 		for _, txn := range block.Transactions {
@@ -497,6 +589,18 @@ func (m *mockBlockStore) ReadTxLookup(txnHash types.Hash) (types.Hash, bool) {
 	return types.ZeroHash, false
 }
 
+func (m *mockBlockStore) ReadTxLookupIndex(txnHash types.Hash) (types.Hash, uint64, bool) {
+	for _, block := range m.blocks {
+		for i, txn := range block.Transactions {
+			if txn.Hash == txnHash {
+				return block.Hash(), uint64(i), true
+			}
+		}
+	}
+
+	return types.ZeroHash, 0, false
+}
+
 func (m *mockBlockStore) GetPendingTx(txHash types.Hash) (*types.Transaction, bool) {
 	for _, txn := range m.pendingTxns {
 		if txn.Hash == txHash {
@@ -514,6 +618,7 @@ func (m *mockBlockStore) GetSyncProgression() *progress.Progression {
 			StartingBlock: 1,
 			CurrentBlock:  10,
 			HighestBlock:  100,
+			Mode:          progress.SyncModeFull,
 		}
 	} else {
 		return nil
@@ -524,7 +629,11 @@ func (m *mockBlockStore) GetAvgGasPrice() *big.Int {
 	return big.NewInt(m.averageGasPrice)
 }
 
-func (m *mockBlockStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+func (m *mockBlockStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+) (*runtime.ExecutionResult, error) {
 	return &runtime.ExecutionResult{Err: m.ethCallError}, nil
 }
 
@@ -532,6 +641,30 @@ func (m *mockBlockStore) SubscribeEvents() blockchain.Subscription {
 	return nil
 }
 
+func (m *mockBlockStore) SubscribeTxEvents(
+	eventTypes ...txpoolProto.EventType,
+) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	return nil, func() {}
+}
+
+func (m *mockBlockStore) GetStorageChanges(
+	header *types.Header,
+	watches state.StorageWatches,
+) ([]*state.StorageChange, error) {
+	return nil, nil
+}
+
+func (m *mockBlockStore) GetAccountChanges(
+	header *types.Header,
+	addrs []types.Address,
+) ([]*state.AccountChange, error) {
+	return nil, nil
+}
+
+func (m *mockBlockStore) GetBloomMatches(from, to uint64, filter [][][]byte) ([]uint64, uint64) {
+	return nil, from
+}
+
 func newTestBlock(number uint64, hash types.Hash) *types.Block {
 	return &types.Block{
 		Header: &types.Header{
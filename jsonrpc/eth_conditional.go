@@ -0,0 +1,92 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// conditionalOptions are the conditions of a transaction sent by eth_sendRawTransactionConditional,
+// in the format of the account abstraction bundlers. The known accounts are checked against
+// the storage slots they list, the storage roots and the timestamps aren't supported
+type conditionalOptions struct {
+	BlockNumberMin *argUint64                        `json:"blockNumberMin"`
+	BlockNumberMax *argUint64                        `json:"blockNumberMax"`
+	TimestampMin   *argUint64                        `json:"timestampMin"`
+	TimestampMax   *argUint64                        `json:"timestampMax"`
+	KnownAccounts  map[types.Address]json.RawMessage `json:"knownAccounts"`
+}
+
+// conditionsNotMetError is returned when the conditions of the transaction don't hold in the next block
+type conditionsNotMetError struct {
+	err string
+}
+
+func (e *conditionsNotMetError) Error() string {
+	return e.err
+}
+
+func (e *conditionsNotMetError) ErrorCode() int {
+	return -32003
+}
+
+// SendRawTransactionConditional sends a raw transaction which is only included in a block
+// while its conditions hold, and is dropped by the pool once they don't
+func (e *Eth) SendRawTransactionConditional(input string, options *conditionalOptions) (interface{}, error) {
+	return e.sendRawTransaction(input, options)
+}
+
+// applyConditions checks the conditions hold for the next block, and sets them to the transaction
+func (e *Eth) applyConditions(tx *types.Transaction, options *conditionalOptions) error {
+	if options.TimestampMin != nil || options.TimestampMax != nil {
+		return NewInvalidParamsError("unsupported condition on the timestamp")
+	}
+
+	header := e.store.Header()
+	next := header.Number + 1
+
+	if options.BlockNumberMin != nil && next < uint64(*options.BlockNumberMin) {
+		return &conditionsNotMetError{"out of block range"}
+	}
+
+	if options.BlockNumberMax != nil {
+		if next > uint64(*options.BlockNumberMax) {
+			return &conditionsNotMetError{"out of block range"}
+		}
+
+		tx.ValidUntil = uint64(*options.BlockNumberMax)
+	}
+
+	if len(options.KnownAccounts) == 0 {
+		return nil
+	}
+
+	knownAccounts := make(map[types.Address]map[types.Hash]types.Hash, len(options.KnownAccounts))
+
+	for addr, raw := range options.KnownAccounts {
+		var slots map[types.Hash]types.Hash
+
+		// the accounts are known either by their storage root or by some of their slots
+		if err := json.Unmarshal(raw, &slots); err != nil {
+			return NewInvalidParamsError(fmt.Sprintf("unsupported condition on the storage root of %s", addr))
+		}
+
+		for key, value := range slots {
+			current, err := e.getStorageValue(header.StateRoot, addr, key)
+			if err != nil {
+				return err
+			}
+
+			if types.BytesToHash(current.Bytes()) != value {
+				return &conditionsNotMetError{fmt.Sprintf("storage slot %s of %s doesn't match", key, addr)}
+			}
+		}
+
+		knownAccounts[addr] = slots
+	}
+
+	tx.KnownAccounts = knownAccounts
+
+	return nil
+}
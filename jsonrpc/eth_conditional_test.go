@@ -0,0 +1,112 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestEth_SendRawTransactionConditional(t *testing.T) {
+	t.Parallel()
+
+	var (
+		contract = types.StringToAddress("1234")
+		slot     = types.StringToHash("1")
+		value    = types.StringToHash("2")
+	)
+
+	store := &mockStoreTxn{}
+
+	// the values are stored RLP encoded
+	ar := &fastrlp.Arena{}
+	store.AddAccount(contract).Storage(slot, ar.NewBytes(value.Bytes()[31:]).MarshalTo(nil))
+
+	eth := newTestEthEndpoint(store)
+
+	txn := &types.Transaction{
+		From: addr0,
+		V:    big.NewInt(1),
+	}
+	raw := hex.EncodeToHex(txn.MarshalRLP())
+
+	options := func(t *testing.T, raw string) *conditionalOptions {
+		t.Helper()
+
+		var options conditionalOptions
+		require.NoError(t, json.Unmarshal([]byte(raw), &options))
+
+		return &options
+	}
+
+	// without conditions the transaction is sent as is
+	_, err := eth.SendRawTransactionConditional(raw, nil)
+	require.NoError(t, err)
+	assert.False(t, store.txn.IsConditional())
+
+	_, err = eth.SendRawTransactionConditional(raw, options(t, `{
+		"blockNumberMin": "0x1",
+		"blockNumberMax": "0xa",
+		"knownAccounts": {"`+contract.String()+`": {"`+slot.String()+`": "`+value.String()+`"}}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(10), store.txn.ValidUntil)
+	assert.Equal(t, map[types.Address]map[types.Hash]types.Hash{contract: {slot: value}}, store.txn.KnownAccounts)
+
+	cases := []struct {
+		name    string
+		options string
+		code    int
+	}{
+		{
+			"before the block range",
+			`{"blockNumberMin": "0x2"}`,
+			-32003,
+		},
+		{
+			"after the block range",
+			`{"blockNumberMax": "0x0"}`,
+			-32003,
+		},
+		{
+			"storage slot mismatch",
+			`{"knownAccounts": {"` + contract.String() + `": {"` + slot.String() + `": "0x3"}}}`,
+			-32003,
+		},
+		{
+			"unset storage slot",
+			`{"knownAccounts": {"` + addr0.String() + `": {"` + slot.String() + `": "` + value.String() + `"}}}`,
+			-32003,
+		},
+		{
+			"storage root",
+			`{"knownAccounts": {"` + contract.String() + `": "` + value.String() + `"}}`,
+			-32602,
+		},
+		{
+			"timestamp",
+			`{"timestampMax": "0x10"}`,
+			-32602,
+		},
+	}
+
+	for _, c := range cases {
+		store.txn = nil
+
+		_, err := eth.SendRawTransactionConditional(raw, options(t, c.options))
+		require.Error(t, err, c.name)
+
+		rpcErr, ok := err.(Error) //nolint:errorlint
+		require.True(t, ok, c.name)
+
+		assert.Equal(t, c.code, rpcErr.ErrorCode(), c.name)
+		assert.Nil(t, store.txn, c.name)
+	}
+}
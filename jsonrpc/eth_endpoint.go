@@ -4,16 +4,21 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
+	"sync"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/umbracle/fastrlp"
+	"go.uber.org/atomic"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -31,14 +36,20 @@ type ethTxPoolStore interface {
 type ethStateStore interface {
 	GetAccount(root types.Hash, addr types.Address) (*state.Account, error)
 	GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error)
-	GetForksInTime(blockNumber uint64) chain.ForksInTime
+	GetForksInTime(header *types.Header) chain.ForksInTime
 	GetCode(hash types.Hash) ([]byte, error)
+
+	// GetProof returns the encoded trie nodes proving the value of the key in the trie with the given root
+	GetProof(root types.Hash, key []byte) ([][]byte, error)
 }
 
 type ethBlockchainStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the last block finalized by the consensus (genesis if none)
+	FinalizedHeader() *types.Header
+
 	// GetHeaderByNumber returns the header by number
 	GetHeaderByNumber(block uint64) (*types.Header, bool)
 
@@ -48,17 +59,33 @@ type ethBlockchainStore interface {
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
 
-	// ReadTxLookup returns a block hash in which a given txn was mined
-	ReadTxLookup(txnHash types.Hash) (types.Hash, bool)
+	// GetTxCountByHash returns the number of transactions in the block, without loading its body
+	GetTxCountByHash(hash types.Hash) (int, bool)
+
+	// ReadTxLookupIndex returns the hash of the block in which a given txn was mined, and its index in the block
+	ReadTxLookupIndex(txnHash types.Hash) (types.Hash, uint64, bool)
 
 	// GetReceiptsByHash returns the receipts for a block hash
 	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
 
+	// GetReceipt returns the receipt of the txn at the index in the block, without loading the other receipts
+	GetReceipt(hash types.Hash, index uint64) (*types.Receipt, error)
+
 	// GetAvgGasPrice returns the average gas price
 	GetAvgGasPrice() *big.Int
 
-	// ApplyTxn applies a transaction object to the blockchain
-	ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
+	// ApplyTxn applies a transaction object to the blockchain, on top of the replaced state of the accounts
+	ApplyTxn(
+		header *types.Header,
+		txn *types.Transaction,
+		override state.StateOverride,
+	) (*runtime.ExecutionResult, error)
+
+	// TraceTxn applies a transaction object to the blockchain, tracing its execution with the tracer
+	TraceTxn(header *types.Header, txn *types.Transaction, tracer runtime.Tracer) (*runtime.ExecutionResult, error)
+
+	// Simulate executes the calls of the blocks in sequence on top of the state of the parent, without committing them
+	Simulate(parent *types.Header, blocks []*state.SimulatedBlock) ([][]*state.SimulatedCall, error)
 
 	// GetSyncProgression retrieves the current sync progression, if any
 	GetSyncProgression() *progress.Progression
@@ -77,13 +104,58 @@ type Eth struct {
 	store         ethStore
 	chainID       uint64
 	filterManager *FilterManager
-	priceLimit    uint64
+	priceLimit    *atomic.Uint64 // shared by the bound endpoints, as it can be changed at runtime
+	stateHistory  stateHistory
+	txLimiter     *txLimiter
+	client        string // client of the request, set by bindClient
 }
 
 var (
 	ErrInsufficientFunds = errors.New("insufficient funds for execution")
 )
 
+// pinnedHeadStore wraps the eth store and serves the same chain head for its whole lifetime,
+// so that all the state reads of a single request are served from one consistent state root,
+// even if new blocks are written while the request is being handled
+type pinnedHeadStore struct {
+	ethStore
+
+	once sync.Once
+	head *types.Header
+}
+
+// Header returns the chain head at the moment it was first requested
+func (p *pinnedHeadStore) Header() *types.Header {
+	p.once.Do(func() {
+		p.head = p.ethStore.Header()
+	})
+
+	return p.head
+}
+
+// HasState returns whether the wrapped store has the state with the given root
+func (p *pinnedHeadStore) HasState(root types.Hash) bool {
+	store, ok := p.ethStore.(stateAvailabilityStore)
+
+	return !ok || store.HasState(root)
+}
+
+// pinHead returns a copy of the endpoint whose latest block is pinned for the duration of a single request
+func (e *Eth) pinHead() interface{} {
+	pinned := *e
+	pinned.store = &pinnedHeadStore{ethStore: e.store}
+
+	return &pinned
+}
+
+// bindClient returns a copy of the endpoint serving a single request of the client
+func (e *Eth) bindClient(client string) interface{} {
+	bound := *e
+	bound.client = client
+
+	return &bound
+}
+
 // ChainId returns the chain id of the client
 //
 //nolint:stylecheck
@@ -122,6 +194,7 @@ func (e *Eth) Syncing() (interface{}, error) {
 			StartingBlock: hex.EncodeUint64(syncProgression.StartingBlock),
 			CurrentBlock:  hex.EncodeUint64(syncProgression.CurrentBlock),
 			HighestBlock:  hex.EncodeUint64(syncProgression.HighestBlock),
+			Mode:          string(syncProgression.Mode),
 		}, nil
 	}
 
@@ -137,6 +210,9 @@ func GetNumericBlockNumber(number BlockNumber, e *Eth) (uint64, error) {
 	case EarliestBlockNumber:
 		return 0, nil
 
+	case FinalizedBlockNumber, SafeBlockNumber:
+		return e.store.FinalizedHeader().Number, nil
+
 	case PendingBlockNumber:
 		return 0, fmt.Errorf("fetching the pending header is not supported")
 
@@ -181,13 +257,27 @@ func (e *Eth) GetBlockTransactionCountByNumber(number BlockNumber) (interface{},
 		return nil, err
 	}
 
-	block, ok := e.store.GetBlockByNumber(num, true)
+	header, ok := e.store.GetHeaderByNumber(num)
+	if !ok {
+		return nil, nil
+	}
+
+	count, ok := e.store.GetTxCountByHash(header.Hash)
+	if !ok {
+		return nil, nil
+	}
 
+	return count, nil
+}
+
+// GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash
+func (e *Eth) GetBlockTransactionCountByHash(hash types.Hash) (interface{}, error) {
+	count, ok := e.store.GetTxCountByHash(hash)
 	if !ok {
 		return nil, nil
 	}
 
-	return len(block.Transactions), nil
+	return count, nil
 }
 
 // BlockNumber returns current block number
@@ -202,6 +292,14 @@ func (e *Eth) BlockNumber() (interface{}, error) {
 
 // SendRawTransaction sends a raw transaction
 func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
+	return e.sendRawTransaction(input, nil)
+}
+
+func (e *Eth) sendRawTransaction(input string, options *conditionalOptions) (interface{}, error) {
+	if err := e.txLimiter.allowIP(e.client); err != nil {
+		return nil, err
+	}
+
 	buf, decodeErr := hex.DecodeHex(input)
 	if decodeErr != nil {
 		return nil, fmt.Errorf("unable to decode input, %w", decodeErr)
@@ -214,6 +312,23 @@ func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
 
 	tx.ComputeHash()
 
+	if options != nil {
+		if err := e.applyConditions(tx, options); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.txLimiter.limitsSenders() {
+		sender, err := crypto.NewEIP155Signer(e.chainID).Sender(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := e.txLimiter.allowSender(sender); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := e.store.AddTx(tx); err != nil {
 		return nil, err
 	}
@@ -235,7 +350,7 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 	// for the transaction with the provided hash
 	findSealedTx := func() *transaction {
 		// Check the chain state for the transaction
-		blockHash, ok := e.store.ReadTxLookup(hash)
+		blockHash, index, ok := e.store.ReadTxLookupIndex(hash)
 		if !ok {
 			// Block not found in storage
 			return nil
@@ -248,19 +363,19 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 			return nil
 		}
 
-		// Find the transaction within the block
-		for idx, txn := range block.Transactions {
-			if txn.Hash == hash {
-				return toTransaction(
-					txn,
-					argUintPtr(block.Number()),
-					argHashPtr(block.Hash()),
-					&idx,
-				)
-			}
+		// The transaction is at its index within the block
+		if index >= uint64(len(block.Transactions)) || block.Transactions[index].Hash != hash {
+			return nil
 		}
 
-		return nil
+		idx := int(index)
+
+		return toTransaction(
+			block.Transactions[idx],
+			argUintPtr(block.Number()),
+			argHashPtr(block.Hash()),
+			&idx,
+		)
 	}
 
 	// findPendingTx is a helper method for checking the TxPool
@@ -295,7 +410,7 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 
 // GetTransactionReceipt returns a transaction receipt by his hash
 func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
-	blockHash, ok := e.store.ReadTxLookup(hash)
+	blockHash, indx, ok := e.store.ReadTxLookupIndex(hash)
 	if !ok {
 		// txn not found
 		return nil, nil
@@ -311,46 +426,26 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 		return nil, nil
 	}
 
-	receipts, err := e.store.GetReceiptsByHash(blockHash)
-	if err != nil {
-		// block receipts not found
-		e.logger.Warn(
-			fmt.Sprintf("Receipts for block with hash [%s] not found", blockHash.String()),
-		)
-
+	if indx >= uint64(len(block.Transactions)) || block.Transactions[indx].Hash != hash {
+		// txn not found in the body
 		return nil, nil
 	}
 
-	if len(receipts) == 0 {
+	raw, err := e.store.GetReceipt(blockHash, indx)
+	if err != nil {
 		// Receipts not written yet on the db
 		e.logger.Warn(
-			fmt.Sprintf("No receipts found for block with hash [%s]", blockHash.String()),
+			fmt.Sprintf("Receipt for transaction [%s] not found in block [%s]", hash.String(), blockHash.String()),
 		)
 
 		return nil, nil
 	}
-	// find the transaction in the body
-	indx := -1
-
-	for i, txn := range block.Transactions {
-		if txn.Hash == hash {
-			indx = i
-
-			break
-		}
-	}
-
-	if indx == -1 {
-		// txn not found
-		return nil, nil
-	}
 
 	txn := block.Transactions[indx]
-	raw := receipts[indx]
 
 	logs := make([]*Log, len(raw.Logs))
-	for indx, elem := range raw.Logs {
-		logs[indx] = &Log{
+	for i, elem := range raw.Logs {
+		logs[i] = &Log{
 			Address:     elem.Address,
 			Topics:      elem.Topics,
 			Data:        argBytes(elem.Data),
@@ -358,12 +453,13 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 			BlockNumber: argUint64(block.Number()),
 			TxHash:      txn.Hash,
 			TxIndex:     argUint64(indx),
-			LogIndex:    argUint64(indx),
+			LogIndex:    argUint64(raw.LogIndex + uint64(i)),
 			Removed:     false,
 		}
 	}
 
 	res := &receipt{
+		Type:              argUint64(raw.TransactionType),
 		Root:              raw.Root,
 		CumulativeGasUsed: argUint64(raw.CumulativeGasUsed),
 		LogsBloom:         raw.LogsBloom,
@@ -376,6 +472,7 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 		ContractAddress:   raw.ContractAddress,
 		FromAddr:          txn.From,
 		ToAddr:            txn.To,
+		Sponsor:           raw.Sponsor,
 		Logs:              logs,
 	}
 
@@ -398,9 +495,9 @@ func (e *Eth) GetStorageAt(
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = e.getStateHeader(&filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get header from block hash or block number")
+		return nil, err
 	}
 
 	// Get the storage for the passed in location
@@ -431,6 +528,125 @@ func (e *Eth) GetStorageAt(
 	return argBytesPtr(types.BytesToHash(data).Bytes()), nil
 }
 
+// emptyCodeHash is the code hash of the accounts without code
+var emptyCodeHash = types.BytesToHash(crypto.Keccak256(nil))
+
+// accountProof is the Merkle proof of an account and of its storage slots (EIP-1186)
+type accountProof struct {
+	Address      types.Address   `json:"address"`
+	AccountProof []argBytes      `json:"accountProof"`
+	Balance      argBig          `json:"balance"`
+	CodeHash     types.Hash      `json:"codeHash"`
+	Nonce        argUint64       `json:"nonce"`
+	StorageHash  types.Hash      `json:"storageHash"`
+	StorageProof []*storageProof `json:"storageProof"`
+}
+
+// storageProof is the Merkle proof of a storage slot against the storage root of its account
+type storageProof struct {
+	Key   types.Hash `json:"key"`
+	Value argBig     `json:"value"`
+	Proof []argBytes `json:"proof"`
+}
+
+func toArgBytesList(nodes [][]byte) []argBytes {
+	res := make([]argBytes, len(nodes))
+	for i, node := range nodes {
+		res[i] = argBytes(node)
+	}
+
+	return res
+}
+
+// GetProof returns the Merkle proofs of the account and of its storage slots at the referenced block.
+// The proofs of the missing account and slots prove their absence
+func (e *Eth) GetProof(
+	address types.Address,
+	storageKeys []types.Hash,
+	filter BlockNumberOrHash,
+) (interface{}, error) {
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	header, err := e.getStateHeader(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := e.store.GetProof(header.StateRoot, address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := e.store.GetAccount(header.StateRoot, address)
+	if errors.Is(err, ErrStateNotFound) {
+		acc = &state.Account{
+			Balance:  big.NewInt(0),
+			Root:     types.EmptyRootHash,
+			CodeHash: emptyCodeHash.Bytes(),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	res := &accountProof{
+		Address:      address,
+		AccountProof: toArgBytesList(proof),
+		Balance:      argBig(*acc.Balance),
+		CodeHash:     types.BytesToHash(acc.CodeHash),
+		Nonce:        argUint64(acc.Nonce),
+		StorageHash:  acc.Root,
+		StorageProof: make([]*storageProof, len(storageKeys)),
+	}
+
+	for i, key := range storageKeys {
+		proof, err := e.store.GetProof(acc.Root, key.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := e.getStorageValue(header.StateRoot, address, key)
+		if err != nil {
+			return nil, err
+		}
+
+		res.StorageProof[i] = &storageProof{
+			Key:   key,
+			Value: argBig(*value),
+			Proof: toArgBytesList(proof),
+		}
+	}
+
+	return res, nil
+}
+
+// getStorageValue returns the value of the storage slot, zero if it is not set
+func (e *Eth) getStorageValue(root types.Hash, address types.Address, slot types.Hash) (*big.Int, error) {
+	result, err := e.store.GetStorage(root, address, slot)
+	if errors.Is(err, ErrStateNotFound) {
+		return big.NewInt(0), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the values are stored RLP encoded
+	p := &fastrlp.Parser{}
+
+	v, err := p.Parse(result)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := v.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
 // GasPrice returns the average gas price based on the last x blocks
 // taking into consideration operator defined price limit
 func (e *Eth) GasPrice() (string, error) {
@@ -438,11 +654,21 @@ func (e *Eth) GasPrice() (string, error) {
 	avgGasPrice := e.store.GetAvgGasPrice().Uint64()
 
 	// Return --price-limit flag defined value if it is greater than avgGasPrice
-	return hex.EncodeUint64(common.Max(e.priceLimit, avgGasPrice)), nil
+	return hex.EncodeUint64(common.Max(e.getPriceLimit(), avgGasPrice)), nil
+}
+
+// getPriceLimit returns the operator defined price limit, 0 if it isn't set
+func (e *Eth) getPriceLimit() uint64 {
+	if e.priceLimit == nil {
+		return 0
+	}
+
+	return e.priceLimit.Load()
 }
 
-// Call executes a smart contract call using the transaction object data
-func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error) {
+// Call executes a smart contract call using the transaction object data,
+// on top of the replaced state of the accounts if any
+func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash, override *stateOverride) (interface{}, error) {
 	var (
 		header *types.Header
 		err    error
@@ -453,9 +679,9 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = e.getStateHeader(&filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get header from block hash or block number")
+		return nil, err
 	}
 
 	transaction, err := e.decodeTxn(arg)
@@ -469,7 +695,7 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 	}
 
 	// The return value of the execution is saved in the transition (returnValue field)
-	result, err := e.store.ApplyTxn(header, transaction)
+	result, err := e.store.ApplyTxn(header, transaction, override.toState())
 	if err != nil {
 		return nil, err
 	}
@@ -486,8 +712,9 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 	return argBytesPtr(result.ReturnValue), nil
 }
 
-// EstimateGas estimates the gas needed to execute a transaction
-func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error) {
+// EstimateGas estimates the gas needed to execute a transaction,
+// on top of the replaced state of the accounts if any
+func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber, override *stateOverride) (interface{}, error) {
 	transaction, err := e.decodeTxn(arg)
 	if err != nil {
 		return nil, err
@@ -504,7 +731,12 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 		return nil, err
 	}
 
-	forksInTime := e.store.GetForksInTime(uint64(number))
+	if err := e.checkStateAvailable(header); err != nil {
+		return nil, err
+	}
+
+	forksInTime := e.store.GetForksInTime(header)
+	stateOverride := override.toState()
 
 	var standardGas uint64
 	if transaction.IsContractCreation() && forksInTime.Homestead {
@@ -550,6 +782,10 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 			accountBalance = acc.Balance
 		}
 
+		if account, ok := stateOverride[transaction.From]; ok && account.Balance != nil {
+			accountBalance = account.Balance
+		}
+
 		availableBalance = new(big.Int).Set(accountBalance)
 
 		if transaction.Value != nil {
@@ -606,7 +842,7 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 		txn := transaction.Copy()
 		txn.Gas = gas
 
-		result, applyErr := e.store.ApplyTxn(header, txn)
+		result, applyErr := e.store.ApplyTxn(header, txn, stateOverride)
 
 		if applyErr != nil {
 			// Check the application error.
@@ -677,6 +913,310 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 	return hex.EncodeUint64(highEnd), nil
 }
 
+// maxAccessListRuns is the maximum number of executions used to find the access list of a transaction
+const maxAccessListRuns = 8
+
+// accessListResult is the access list of a transaction, along with the gas it uses with that access list
+type accessListResult struct {
+	AccessList types.TxAccessList `json:"accessList"`
+	GasUsed    argUint64          `json:"gasUsed"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// CreateAccessList returns the accounts and storage slots accessed by the transaction, as an access list.
+// The access list changes the gas available to the execution, and so possibly the accessed state,
+// so the transaction is executed with the found access list until it doesn't change anymore
+func (e *Eth) CreateAccessList(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error) {
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	header, err := e.getStateHeader(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := e.decodeTxn(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Gas == 0 {
+		transaction.Gas = header.GasLimit
+	}
+
+	accessList := transaction.AccessList
+
+	for run := 0; ; run++ {
+		txn := transaction.Copy()
+		txn.Type = types.AccessListTx
+		txn.ChainID = new(big.Int).SetUint64(e.chainID)
+		txn.AccessList = accessList
+
+		accessTracer := tracer.NewAccessListTracer()
+
+		result, err := e.store.TraceTxn(header, txn, accessTracer)
+		if err != nil {
+			return nil, err
+		}
+
+		found := accessTracer.AccessList()
+
+		if result.Failed() || reflect.DeepEqual(found, accessList) || run == maxAccessListRuns-1 {
+			res := &accessListResult{
+				AccessList: found,
+				GasUsed:    argUint64(result.GasUsed),
+			}
+
+			if result.Failed() {
+				res.Error = result.Err.Error()
+			}
+
+			return res, nil
+		}
+
+		accessList = found
+	}
+}
+
+const (
+	// maxSimulatedBlocks is the maximum number of blocks simulated by a single eth_simulateV1 request
+	maxSimulatedBlocks = 256
+
+	// the error codes of the failed calls, as defined by the eth_simulateV1 spec
+	simulateRevertedCode    = 3
+	simulateVMExecutionCode = -32015
+)
+
+var (
+	ErrTooManySimulatedBlocks = fmt.Errorf("too many blocks to simulate, the maximum is %d", maxSimulatedBlocks)
+)
+
+// simulateOpts is the request of eth_simulateV1
+type simulateOpts struct {
+	BlockStateCalls []*simulateBlock `json:"blockStateCalls"`
+}
+
+// simulateBlock is a block of calls executed on top of the state left by the previous ones,
+// with the replaced fields of the block and state of the accounts
+type simulateBlock struct {
+	BlockOverrides *blockOverrides `json:"blockOverrides"`
+	StateOverrides *stateOverride  `json:"stateOverrides"`
+	Calls          []*txnArgs      `json:"calls"`
+}
+
+// blockOverrides are the replaced fields of a simulated block,
+// by default it follows the previous block by one number and one second
+type blockOverrides struct {
+	Number       *argUint64     `json:"number"`
+	Time         *argUint64     `json:"time"`
+	GasLimit     *argUint64     `json:"gasLimit"`
+	FeeRecipient *types.Address `json:"feeRecipient"`
+}
+
+// simulatedBlockResult is a simulated block along with the results of its calls
+type simulatedBlockResult struct {
+	*block
+
+	Calls []*simulatedCallResult `json:"calls"`
+}
+
+// simulatedCallResult is the result of a simulated call
+type simulatedCallResult struct {
+	ReturnData argBytes            `json:"returnData"`
+	Logs       []*Log              `json:"logs"`
+	GasUsed    argUint64           `json:"gasUsed"`
+	Status     argUint64           `json:"status"`
+	Error      *simulatedCallError `json:"error,omitempty"`
+}
+
+// simulatedCallError is the reason a simulated call failed,
+// with the revert data for the reverted ones
+type simulatedCallError struct {
+	Code    int       `json:"code"`
+	Message string    `json:"message"`
+	Data    *argBytes `json:"data,omitempty"`
+}
+
+// SimulateV1 executes the calls of a sequence of simulated blocks on top of the state of the given block,
+// each block on top of the state left by the previous ones, and returns the logs and gas used by every call.
+// Nothing is committed, the nonces of the calls are taken from the simulated state
+func (e *Eth) SimulateV1(opts *simulateOpts, filter BlockNumberOrHash) (interface{}, error) {
+	if opts == nil || len(opts.BlockStateCalls) == 0 {
+		return nil, state.ErrNoSimulatedBlocks
+	}
+
+	if len(opts.BlockStateCalls) > maxSimulatedBlocks {
+		return nil, ErrTooManySimulatedBlocks
+	}
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	parent, err := e.getStateHeader(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		blocks = make([]*state.SimulatedBlock, len(opts.BlockStateCalls))
+		last   = parent
+	)
+
+	for idx, simBlock := range opts.BlockStateCalls {
+		header := simBlock.BlockOverrides.toHeader(last)
+
+		calls := make([]*types.Transaction, len(simBlock.Calls))
+
+		for callIdx, arg := range simBlock.Calls {
+			// the nonce is taken from the simulated state
+			if arg.Nonce == nil {
+				arg.Nonce = argUintPtr(0)
+			}
+
+			if calls[callIdx], err = e.decodeTxn(arg); err != nil {
+				return nil, err
+			}
+		}
+
+		blocks[idx] = &state.SimulatedBlock{
+			Header:   header,
+			Override: simBlock.StateOverrides.toState(),
+			Calls:    calls,
+		}
+
+		last = header
+	}
+
+	results, err := e.store.Simulate(parent, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*simulatedBlockResult, len(blocks))
+
+	for idx, simBlock := range blocks {
+		if res[idx], err = toSimulatedBlockResult(simBlock, results[idx]); err != nil {
+			return nil, fmt.Errorf("block %d: %w", simBlock.Header.Number, err)
+		}
+	}
+
+	return res, nil
+}
+
+// toHeader returns the header of the simulated block following the previous one
+func (o *blockOverrides) toHeader(prev *types.Header) *types.Header {
+	header := &types.Header{
+		Number:     prev.Number + 1,
+		Timestamp:  prev.Timestamp + 1,
+		GasLimit:   prev.GasLimit,
+		Difficulty: prev.Difficulty,
+		Sha3Uncles: types.EmptyUncleHash,
+		// the consensus hashes the headers from their extra data
+		ExtraData: prev.ExtraData,
+	}
+
+	if o == nil {
+		return header
+	}
+
+	if o.Number != nil {
+		header.Number = uint64(*o.Number)
+	}
+
+	if o.Time != nil {
+		header.Timestamp = uint64(*o.Time)
+	}
+
+	if o.GasLimit != nil {
+		header.GasLimit = uint64(*o.GasLimit)
+	}
+
+	if o.FeeRecipient != nil {
+		header.Miner = o.FeeRecipient.Bytes()
+	}
+
+	return header
+}
+
+// toSimulatedBlockResult converts the simulated block and the results of its calls
+func toSimulatedBlockResult(
+	simBlock *state.SimulatedBlock,
+	calls []*state.SimulatedCall,
+) (*simulatedBlockResult, error) {
+	var (
+		header   = simBlock.Header
+		gasUsed  uint64
+		logIndex uint64
+		res      = &simulatedBlockResult{
+			Calls: make([]*simulatedCallResult, len(calls)),
+		}
+	)
+
+	for idx, call := range calls {
+		// the calls which can't be applied, because of the balance of the sender or
+		// the gas left in the block, would make an invalid block
+		if call.Err != nil {
+			return nil, fmt.Errorf("call %d: %w", idx, call.Err)
+		}
+
+		callRes := &simulatedCallResult{
+			ReturnData: call.Result.ReturnValue,
+			Logs:       make([]*Log, len(call.Logs)),
+			GasUsed:    argUint64(call.Result.GasUsed),
+			Status:     argUint64(types.ReceiptSuccess),
+		}
+
+		for logIdx, log := range call.Logs {
+			callRes.Logs[logIdx] = &Log{
+				Address:     log.Address,
+				Topics:      log.Topics,
+				Data:        log.Data,
+				BlockNumber: argUint64(header.Number),
+				BlockHash:   header.Hash,
+				TxHash:      simBlock.Calls[idx].Hash,
+				TxIndex:     argUint64(idx),
+				LogIndex:    argUint64(logIndex),
+			}
+
+			logIndex++
+		}
+
+		if call.Result.Failed() {
+			callRes.Status = argUint64(types.ReceiptFailed)
+			callRes.Error = toSimulatedCallError(call.Result)
+		}
+
+		gasUsed += call.Result.GasUsed
+		res.Calls[idx] = callRes
+	}
+
+	res.block = toBlock(&types.Block{Header: header}, false)
+	res.GasUsed = argUint64(gasUsed)
+
+	return res, nil
+}
+
+// toSimulatedCallError returns the error of the failed call, the reverted calls use
+// the error code of eth_call and return the revert data
+func toSimulatedCallError(result *runtime.ExecutionResult) *simulatedCallError {
+	if result.Reverted() {
+		return &simulatedCallError{
+			Code:    simulateRevertedCode,
+			Message: constructErrorFromRevert(result).Error(),
+			Data:    argBytesPtr(result.ReturnValue),
+		}
+	}
+
+	return &simulatedCallError{
+		Code:    simulateVMExecutionCode,
+		Message: result.Err.Error(),
+	}
+}
+
 // GetFilterLogs returns an array of logs for the specified filter
 func (e *Eth) GetFilterLogs(id string) (interface{}, error) {
 	logFilter, err := e.filterManager.GetLogFilterFromID(id)
@@ -704,9 +1244,9 @@ func (e *Eth) GetBalance(address types.Address, filter BlockNumberOrHash) (inter
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = e.getStateHeader(&filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get header from block hash or block number")
+		return nil, err
 	}
 
 	// Extract the account balance
@@ -736,9 +1276,9 @@ func (e *Eth) GetTransactionCount(address types.Address, filter BlockNumberOrHas
 	}
 
 	if filter.BlockNumber == nil {
-		header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+		header, err = e.getStateHeader(&filter)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get header from block hash or block number")
+			return nil, err
 		}
 
 		blockNumber = BlockNumber(header.Number)
@@ -770,9 +1310,9 @@ func (e *Eth) GetCode(address types.Address, filter BlockNumberOrHash) (interfac
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = e.getStateHeader(&filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get header from block hash or block number")
+		return nil, err
 	}
 
 	emptySlice := []byte{}
@@ -798,12 +1338,17 @@ func (e *Eth) GetCode(address types.Address, filter BlockNumberOrHash) (interfac
 
 // NewFilter creates a filter object, based on filter options, to notify when the state changes (logs).
 func (e *Eth) NewFilter(filter *LogQuery) (interface{}, error) {
-	return e.filterManager.NewLogFilter(filter, nil), nil
+	return e.filterManager.NewPollingLogFilter(e.client, filter)
 }
 
 // NewBlockFilter creates a filter in the node, to notify when a new block arrives
 func (e *Eth) NewBlockFilter() (interface{}, error) {
-	return e.filterManager.NewBlockFilter(nil), nil
+	return e.filterManager.NewPollingBlockFilter(e.client)
+}
+
+// NewPendingTransactionFilter creates a filter in the node, to notify when new transactions become pending
+func (e *Eth) NewPendingTransactionFilter() (interface{}, error) {
+	return e.filterManager.NewPollingPendingTxFilter(e.client)
 }
 
 // GetFilterChanges is a polling method for a filter, which returns an array of logs which occurred since last poll.
@@ -834,6 +1379,9 @@ func (e *Eth) getBlockHeader(number BlockNumber) (*types.Header, error) {
 
 		return header, nil
 
+	case FinalizedBlockNumber, SafeBlockNumber:
+		return e.store.FinalizedHeader(), nil
+
 	case PendingBlockNumber:
 		return nil, fmt.Errorf("fetching the pending header is not supported")
 
@@ -865,6 +1413,10 @@ func (e *Eth) getNextNonce(address types.Address, number BlockNumber) (uint64, e
 		return 0, err
 	}
 
+	if err := e.checkStateAvailable(header); err != nil {
+		return 0, err
+	}
+
 	acc, err := e.store.GetAccount(header.StateRoot, address)
 
 	//nolint:govet
@@ -934,6 +1486,12 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 		txn.To = arg.To
 	}
 
+	if arg.AccessList != nil {
+		txn.Type = types.AccessListTx
+		txn.ChainID = new(big.Int).SetUint64(e.chainID)
+		txn.AccessList = *arg.AccessList
+	}
+
 	txn.ComputeHash()
 
 	return txn, nil
@@ -9,6 +9,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
 )
 
 func TestEth_DecodeTxn(t *testing.T) {
@@ -233,9 +234,46 @@ func TestEth_GetNextNonce(t *testing.T) {
 }
 
 func newTestEthEndpoint(store ethStore) *Eth {
-	return &Eth{hclog.NewNullLogger(), store, 100, nil, 0}
+	return &Eth{hclog.NewNullLogger(), store, 100, nil, atomic.NewUint64(0), stateHistory{}, nil, ""}
 }
 
 func newTestEthEndpointWithPriceLimit(store ethStore, priceLimit uint64) *Eth {
-	return &Eth{hclog.NewNullLogger(), store, 100, nil, priceLimit}
+	return &Eth{hclog.NewNullLogger(), store, 100, nil, atomic.NewUint64(priceLimit), stateHistory{}, nil, ""}
+}
+
+type advancingHeadStore struct {
+	ethStore
+
+	number uint64
+}
+
+func (s *advancingHeadStore) Header() *types.Header {
+	s.number++
+
+	return &types.Header{Number: s.number}
+}
+
+func TestEth_PinHead(t *testing.T) {
+	t.Parallel()
+
+	store := &advancingHeadStore{}
+	eth := &Eth{store: store}
+
+	pinned, ok := eth.pinHead().(*Eth)
+	assert.True(t, ok)
+
+	// the head is resolved once and served for the whole request
+	first, err := pinned.BlockNumber()
+	assert.NoError(t, err)
+
+	second, err := pinned.BlockNumber()
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+
+	// the original endpoint keeps following the chain head
+	third, err := eth.BlockNumber()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first, third)
 }
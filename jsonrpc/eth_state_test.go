@@ -11,8 +11,10 @@ import (
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -582,6 +584,87 @@ func TestEth_State_GetStorageAt(t *testing.T) {
 	}
 }
 
+func TestEth_State_GetProof(t *testing.T) {
+	t.Parallel()
+
+	storageRoot := types.StringToHash("1234")
+
+	store := &mockSpecialStore{
+		account: &mockAccount{
+			address: addr0,
+			account: &state.Account{
+				Balance:  big.NewInt(100),
+				Nonce:    5,
+				Root:     storageRoot,
+				CodeHash: hash2.Bytes(),
+			},
+			storage: make(map[types.Hash][]byte),
+		},
+		block: &types.Block{
+			Header: &types.Header{
+				Hash:      hash1,
+				Number:    0,
+				StateRoot: hash3,
+			},
+		},
+	}
+
+	a := &fastrlp.Arena{}
+	store.account.Storage(hash1, a.NewBytes([]byte{0x2a}).MarshalTo(nil))
+
+	eth := newTestEthEndpoint(store)
+
+	t.Run("existing account", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := eth.GetProof(addr0, []types.Hash{hash1, hash2}, BlockNumberOrHash{})
+		require.NoError(t, err)
+
+		proof, ok := res.(*accountProof)
+		require.True(t, ok)
+
+		assert.Equal(t, addr0, proof.Address)
+		assert.Equal(t, []argBytes{hash3.Bytes(), addr0.Bytes()}, proof.AccountProof)
+		assert.Equal(t, argBig(*big.NewInt(100)), proof.Balance)
+		assert.Equal(t, argUint64(5), proof.Nonce)
+		assert.Equal(t, hash2, proof.CodeHash)
+		assert.Equal(t, storageRoot, proof.StorageHash)
+
+		require.Len(t, proof.StorageProof, 2)
+		assert.Equal(t, hash1, proof.StorageProof[0].Key)
+		assert.Equal(t, argBig(*big.NewInt(0x2a)), proof.StorageProof[0].Value)
+		assert.Equal(t, []argBytes{storageRoot.Bytes(), hash1.Bytes()}, proof.StorageProof[0].Proof)
+
+		// the unset slots are proved to be empty
+		assert.Equal(t, argBig(*big.NewInt(0)), proof.StorageProof[1].Value)
+	})
+
+	t.Run("missing account", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := eth.GetProof(addr1, []types.Hash{hash1}, BlockNumberOrHash{})
+		require.NoError(t, err)
+
+		proof, ok := res.(*accountProof)
+		require.True(t, ok)
+
+		assert.Equal(t, argBig(*big.NewInt(0)), proof.Balance)
+		assert.Equal(t, argUint64(0), proof.Nonce)
+		assert.Equal(t, emptyCodeHash, proof.CodeHash)
+		assert.Equal(t, types.EmptyRootHash, proof.StorageHash)
+		assert.Equal(t, argBig(*big.NewInt(0)), proof.StorageProof[0].Value)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		t.Parallel()
+
+		blockNumber := BlockNumber(1)
+
+		_, err := eth.GetProof(addr0, nil, BlockNumberOrHash{BlockNumber: &blockNumber})
+		assert.Error(t, err)
+	})
+}
+
 func constructMockTx(gasLimit *argUint64, data *argBytes) *txnArgs {
 	return &txnArgs{
 		From:     &addr0,
@@ -683,7 +766,7 @@ func TestEth_EstimateGas_GasLimit(t *testing.T) {
 			}
 
 			// Run the estimation
-			estimate, estimateErr := ethEndpoint.EstimateGas(testCase.transaction, nil)
+			estimate, estimateErr := ethEndpoint.EstimateGas(testCase.transaction, nil, nil)
 
 			if testCase.expectedError != nil {
 				if estimateErr == nil {
@@ -734,6 +817,7 @@ func TestEth_EstimateGas_Reverts(t *testing.T) {
 	estimate, estimateErr := ethEndpoint.EstimateGas(
 		constructMockTx(nil, nil),
 		nil,
+		nil,
 	)
 
 	assert.Equal(t, 0, estimate)
@@ -760,6 +844,7 @@ func TestEth_EstimateGas_Errors(t *testing.T) {
 	estimate, estimateErr := ethEndpoint.EstimateGas(
 		mockTx,
 		nil,
+		nil,
 	)
 
 	assert.Equal(t, 0, estimate)
@@ -768,12 +853,201 @@ func TestEth_EstimateGas_Errors(t *testing.T) {
 	assert.ErrorIs(t, estimateErr, ErrInsufficientFunds)
 }
 
+func TestEth_EstimateGas_StateOverride(t *testing.T) {
+	store := getExampleStore()
+	ethEndpoint := newTestEthEndpoint(store)
+
+	// Account doesn't have any balance, unless it's overridden
+	store.account.account.Balance = big.NewInt(0)
+
+	mockTx := constructMockTx(nil, nil)
+	mockTx.Value = argBytesPtr([]byte{0x1})
+
+	override := &stateOverride{
+		addr0: {
+			Balance: argBigPtr(big.NewInt(100)),
+		},
+	}
+
+	store.applyTxnHook = func(
+		header *types.Header,
+		txn *types.Transaction,
+	) (*runtime.ExecutionResult, error) {
+		return &runtime.ExecutionResult{}, nil
+	}
+
+	estimate, estimateErr := ethEndpoint.EstimateGas(mockTx, nil, override)
+
+	assert.NoError(t, estimateErr)
+	assert.Equal(t, fmt.Sprintf("0x%x", state.TxGas), estimate)
+}
+
+func TestEth_CreateAccessList(t *testing.T) {
+	t.Parallel()
+
+	var (
+		contract = types.StringToAddress("abcd")
+		slot     = types.StringToHash("1")
+		runs     = 0
+	)
+
+	store := getExampleStore()
+	ethEndpoint := newTestEthEndpoint(store)
+
+	// the transaction reads a storage slot of the contract, and uses less gas once it's in the access list
+	store.traceTxnHook = func(
+		header *types.Header,
+		txn *types.Transaction,
+		tracer runtime.Tracer,
+	) (*runtime.ExecutionResult, error) {
+		runs++
+
+		assert.Equal(t, types.AccessListTx, txn.Type)
+		assert.Equal(t, header.GasLimit, txn.Gas)
+
+		tracer.ExecuteState(&runtime.ExecutionStep{
+			Contract: &runtime.Contract{Address: contract},
+			Op:       int(evm.SLOAD),
+			Stack:    []*big.Int{new(big.Int).SetBytes(slot.Bytes())},
+		})
+
+		return &runtime.ExecutionResult{GasUsed: 30000 - uint64(txn.AccessList.StorageKeys())}, nil
+	}
+
+	res, err := ethEndpoint.CreateAccessList(constructMockTx(nil, nil), BlockNumberOrHash{})
+	require.NoError(t, err)
+
+	assert.Equal(t, &accessListResult{
+		AccessList: types.TxAccessList{
+			{Address: contract, StorageKeys: []types.Hash{slot}},
+		},
+		GasUsed: 29999,
+	}, res)
+	assert.Equal(t, 2, runs)
+
+	// the failure of the transaction is returned along with the access list
+	store.traceTxnHook = func(
+		header *types.Header,
+		txn *types.Transaction,
+		tracer runtime.Tracer,
+	) (*runtime.ExecutionResult, error) {
+		return &runtime.ExecutionResult{Err: runtime.ErrOutOfGas}, nil
+	}
+
+	res, err = ethEndpoint.CreateAccessList(constructMockTx(nil, nil), BlockNumberOrHash{})
+	require.NoError(t, err)
+
+	assert.Equal(t, &accessListResult{
+		AccessList: types.TxAccessList{},
+		Error:      runtime.ErrOutOfGas.Error(),
+	}, res)
+}
+
+func TestEth_SimulateV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store       = getExampleStore()
+		ethEndpoint = newTestEthEndpoint(store)
+		number      = argUint64(10)
+		recipient   = types.StringToAddress("abcd")
+		topic       = types.StringToHash("topic")
+	)
+
+	opts := &simulateOpts{
+		BlockStateCalls: []*simulateBlock{
+			{
+				Calls: []*txnArgs{{From: &addr0, To: &recipient}},
+			},
+			{
+				BlockOverrides: &blockOverrides{Number: &number, FeeRecipient: &recipient},
+				Calls:          []*txnArgs{{From: &addr0, To: &recipient}},
+			},
+		},
+	}
+
+	store.simulateHook = func(parent *types.Header, blocks []*state.SimulatedBlock) ([][]*state.SimulatedCall, error) {
+		assert.Equal(t, hash1, parent.Hash)
+		require.Len(t, blocks, 2)
+
+		// the first block follows the parent and the second one is overridden
+		assert.Equal(t, uint64(1), blocks[0].Header.Number)
+		assert.Equal(t, uint64(1), blocks[0].Header.Timestamp)
+		assert.Equal(t, uint64(500000), blocks[0].Header.GasLimit)
+		assert.Equal(t, uint64(10), blocks[1].Header.Number)
+		assert.Equal(t, recipient.Bytes(), blocks[1].Header.Miner)
+
+		for _, block := range blocks {
+			block.Header.ComputeHash()
+		}
+
+		return [][]*state.SimulatedCall{
+			{
+				{
+					Result: &runtime.ExecutionResult{GasUsed: 25000},
+					Logs:   []*types.Log{{Address: recipient, Topics: []types.Hash{topic}}},
+				},
+			},
+			{
+				{
+					Result: &runtime.ExecutionResult{GasUsed: 22000, Err: runtime.ErrExecutionReverted},
+				},
+			},
+		}, nil
+	}
+
+	res, err := ethEndpoint.SimulateV1(opts, BlockNumberOrHash{})
+	require.NoError(t, err)
+
+	blocks, ok := res.([]*simulatedBlockResult)
+	require.True(t, ok)
+	require.Len(t, blocks, 2)
+
+	assert.Equal(t, argUint64(1), blocks[0].Number)
+	assert.Equal(t, argUint64(25000), blocks[0].GasUsed)
+	require.Len(t, blocks[0].Calls, 1)
+	assert.Equal(t, argUint64(types.ReceiptSuccess), blocks[0].Calls[0].Status)
+	assert.Nil(t, blocks[0].Calls[0].Error)
+	require.Len(t, blocks[0].Calls[0].Logs, 1)
+	assert.Equal(t, blocks[0].Hash, blocks[0].Calls[0].Logs[0].BlockHash)
+	assert.Equal(t, []types.Hash{topic}, blocks[0].Calls[0].Logs[0].Topics)
+
+	assert.Equal(t, argUint64(10), blocks[1].Number)
+	assert.Equal(t, argUint64(types.ReceiptFailed), blocks[1].Calls[0].Status)
+	assert.Equal(t, simulateRevertedCode, blocks[1].Calls[0].Error.Code)
+
+	// a call which can't be applied fails the simulation
+	store.simulateHook = func(parent *types.Header, blocks []*state.SimulatedBlock) ([][]*state.SimulatedCall, error) {
+		return [][]*state.SimulatedCall{
+			{{Err: state.ErrNotEnoughFunds}},
+			{{Result: &runtime.ExecutionResult{}}},
+		}, nil
+	}
+
+	_, err = ethEndpoint.SimulateV1(opts, BlockNumberOrHash{})
+	assert.ErrorIs(t, err, state.ErrNotEnoughFunds)
+
+	_, err = ethEndpoint.SimulateV1(&simulateOpts{}, BlockNumberOrHash{})
+	assert.ErrorIs(t, err, state.ErrNoSimulatedBlocks)
+
+	_, err = ethEndpoint.SimulateV1(&simulateOpts{
+		BlockStateCalls: make([]*simulateBlock, maxSimulatedBlocks+1),
+	}, BlockNumberOrHash{})
+	assert.ErrorIs(t, err, ErrTooManySimulatedBlocks)
+}
+
 type mockSpecialStore struct {
 	ethStore
 	account *mockAccount
 	block   *types.Block
 
 	applyTxnHook func(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
+	traceTxnHook func(
+		header *types.Header,
+		txn *types.Transaction,
+		tracer runtime.Tracer,
+	) (*runtime.ExecutionResult, error)
+	simulateHook func(parent *types.Header, blocks []*state.SimulatedBlock) ([][]*state.SimulatedCall, error)
 }
 
 func (m *mockSpecialStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
@@ -831,14 +1105,41 @@ func (m *mockSpecialStore) GetCode(hash types.Hash) ([]byte, error) {
 	return nil, fmt.Errorf("code not found")
 }
 
-func (m *mockSpecialStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+func (m *mockSpecialStore) GetProof(root types.Hash, key []byte) ([][]byte, error) {
+	return [][]byte{root.Bytes(), key}, nil
+}
+
+func (m *mockSpecialStore) GetForksInTime(header *types.Header) chain.ForksInTime {
 	return chain.ForksInTime{}
 }
 
-func (m *mockSpecialStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+func (m *mockSpecialStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+) (*runtime.ExecutionResult, error) {
 	if m.applyTxnHook != nil {
 		return m.applyTxnHook(header, txn)
 	}
 
 	return &runtime.ExecutionResult{}, nil
 }
+
+func (m *mockSpecialStore) TraceTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	if m.traceTxnHook != nil {
+		return m.traceTxnHook(header, txn, tracer)
+	}
+
+	return &runtime.ExecutionResult{}, nil
+}
+
+func (m *mockSpecialStore) Simulate(
+	parent *types.Header,
+	blocks []*state.SimulatedBlock,
+) ([][]*state.SimulatedCall, error) {
+	return m.simulateHook(parent, blocks)
+}
@@ -82,6 +82,20 @@ func (m *mockStoreTxn) Header() *types.Header {
 	return &types.Header{}
 }
 
+func (m *mockStoreTxn) GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error) {
+	acct, ok := m.accounts[addr]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	val, ok := acct.storage[slot]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	return val, nil
+}
+
 func (m *mockStoreTxn) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
 	acct, ok := m.accounts[addr]
 	if !ok {
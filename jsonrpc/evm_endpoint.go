@@ -0,0 +1,84 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EvmStore provides access to the methods needed by the evm endpoint
+type EvmStore interface {
+	// Mine seals a block of the pending transactions right away, stamped with the timestamp if it's given
+	Mine(timestamp *uint64) error
+
+	// IncreaseTime moves the timestamps of the next blocks forward by the seconds,
+	// returning the total number of seconds they are moved
+	IncreaseTime(seconds uint64) int64
+
+	// Snapshot saves the head of the chain, returning the ID of the snapshot
+	Snapshot() uint64
+
+	// Revert rewinds the chain to the head saved by the snapshot, returning false if there is no such snapshot
+	Revert(id uint64) (bool, error)
+}
+
+// Evm is the evm jsonrpc endpoint of the development chains, controlling the sealing and the clock
+// of the chain the way the Ethereum development tools do, which is only served by the dev consensus
+type Evm struct {
+	store EvmStore
+}
+
+// argQuantity is a quantity sent either as a JSON number or as a hex string,
+// like the development tools send the quantities of the evm methods
+type argQuantity uint64
+
+func (q *argQuantity) UnmarshalJSON(data []byte) error {
+	var num uint64
+	if err := json.Unmarshal(data, &num); err == nil {
+		*q = argQuantity(num)
+
+		return nil
+	}
+
+	var str argUint64
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("invalid quantity %s", string(data))
+	}
+
+	*q = argQuantity(str)
+
+	return nil
+}
+
+// Mine seals a block of all the pending transactions, even if there are none.
+// If a timestamp is given, the block is stamped with it and the next blocks follow it
+func (e *Evm) Mine(timestamp *argQuantity) (interface{}, error) {
+	var ts *uint64
+
+	if timestamp != nil {
+		value := uint64(*timestamp)
+		ts = &value
+	}
+
+	if err := e.store.Mine(ts); err != nil {
+		return nil, err
+	}
+
+	return "0x0", nil
+}
+
+// IncreaseTime moves the timestamps of the next blocks forward by the seconds,
+// returning the total number of seconds they are moved from the clock
+func (e *Evm) IncreaseTime(seconds argQuantity) (interface{}, error) {
+	return e.store.IncreaseTime(uint64(seconds)), nil
+}
+
+// Snapshot saves the head of the chain, returning the ID it can be reverted with
+func (e *Evm) Snapshot() (interface{}, error) {
+	return argUint64(e.store.Snapshot()), nil
+}
+
+// Revert rewinds the chain to the snapshot, dropping the pending transactions.
+// The snapshot and the ones taken after it are discarded
+func (e *Evm) Revert(id argQuantity) (interface{}, error) {
+	return e.store.Revert(uint64(id))
+}
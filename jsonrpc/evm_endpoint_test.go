@@ -0,0 +1,103 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockEvmStore struct {
+	mined      []*uint64
+	timeOffset int64
+	snapshots  uint64
+	reverted   []uint64
+}
+
+func (m *mockEvmStore) Mine(timestamp *uint64) error {
+	m.mined = append(m.mined, timestamp)
+
+	return nil
+}
+
+func (m *mockEvmStore) IncreaseTime(seconds uint64) int64 {
+	m.timeOffset += int64(seconds)
+
+	return m.timeOffset
+}
+
+func (m *mockEvmStore) Snapshot() uint64 {
+	m.snapshots++
+
+	return m.snapshots
+}
+
+func (m *mockEvmStore) Revert(id uint64) (bool, error) {
+	if id == 0 || id > m.snapshots {
+		return false, nil
+	}
+
+	m.reverted = append(m.reverted, id)
+	m.snapshots = id - 1
+
+	return true, nil
+}
+
+func TestEvmEndpoint(t *testing.T) {
+	t.Parallel()
+
+	store := &mockEvmStore{}
+	dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), &dispatcherParams{})
+
+	// the evm methods are only served by the dev chains
+	_, err := dispatcher.Handle([]byte(`{"method": "evm_mine", "params": []}`))
+	require.NoError(t, err)
+	assert.Nil(t, dispatcher.endpoints.Evm)
+
+	dispatcher.registerEvmEndpoint(store)
+
+	_, err = dispatcher.Handle([]byte(`{"method": "evm_mine", "params": []}`))
+	require.NoError(t, err)
+
+	// the timestamp is accepted as a number and as a hex string
+	_, err = dispatcher.Handle([]byte(`{"method": "evm_mine", "params": [1700000000]}`))
+	require.NoError(t, err)
+
+	_, err = dispatcher.Handle([]byte(`{"method": "evm_mine", "params": ["0x10"]}`))
+	require.NoError(t, err)
+
+	require.Len(t, store.mined, 3)
+	assert.Nil(t, store.mined[0])
+	assert.Equal(t, uint64(1700000000), *store.mined[1])
+	assert.Equal(t, uint64(16), *store.mined[2])
+
+	res, err := dispatcher.Handle([]byte(`{"method": "evm_increaseTime", "params": [3600]}`))
+	require.NoError(t, err)
+
+	offset := int64(0)
+	require.NoError(t, expectJSONResult(res, &offset))
+	assert.Equal(t, int64(3600), offset)
+
+	res, err = dispatcher.Handle([]byte(`{"method": "evm_snapshot", "params": []}`))
+	require.NoError(t, err)
+
+	id := ""
+	require.NoError(t, expectJSONResult(res, &id))
+	assert.Equal(t, "0x1", id)
+
+	res, err = dispatcher.Handle([]byte(`{"method": "evm_revert", "params": ["0x1"]}`))
+	require.NoError(t, err)
+
+	reverted := false
+	require.NoError(t, expectJSONResult(res, &reverted))
+	assert.True(t, reverted)
+
+	// a snapshot can't be reverted to twice
+	res, err = dispatcher.Handle([]byte(`{"method": "evm_revert", "params": ["0x1"]}`))
+	require.NoError(t, err)
+
+	require.NoError(t, expectJSONResult(res, &reverted))
+	assert.False(t, reverted)
+	assert.Equal(t, []uint64{1}, store.reverted)
+}
@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/state"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -25,6 +29,7 @@ var (
 	ErrBlockRangeTooHigh                = errors.New("block range too high")
 	ErrPendingBlockNumber               = errors.New("pending block number is not supported")
 	ErrNoWSConnection                   = errors.New("no websocket connection")
+	ErrTooManyFilters                   = errors.New("too many filters installed by the client")
 )
 
 // defaultTimeout is the timeout to remove the filters that don't have a web socket stream
@@ -33,6 +38,14 @@ var defaultTimeout = 1 * time.Minute
 const (
 	// The index in heap which is indicating the element is not in the heap
 	NoIndexInHeap = -1
+
+	// maxFilterUpdates is the number of updates a filter buffers until they are taken.
+	// The oldest updates are dropped once it is reached, so a subscriber which
+	// doesn't keep up with the chain can't grow the memory of the node
+	maxFilterUpdates = 10000
+
+	// maxWatchedAccounts is the number of accounts a subscription to the account changes can watch
+	maxWatchedAccounts = 10000
 )
 
 // filter is an interface that BlockFilter and LogFilter implement
@@ -63,6 +76,13 @@ type filterBase struct {
 
 	// websocket connection
 	ws wsConn
+
+	// client which installed the polling filter, empty if it isn't counted against a client limit
+	client string
+
+	// number of the last block whose updates have been taken by the polling filter,
+	// a persisted filter gets the updates of the following blocks once restored
+	lastBlock uint64
 }
 
 // newFilterBase initializes filterBase with unique ID
@@ -84,9 +104,14 @@ func (f *filterBase) hasWSConn() bool {
 	return f.ws != nil
 }
 
-const ethSubscriptionTemplate = `{
+const (
+	ethSubscription  = "eth_subscription"
+	edgeSubscription = "edge_subscription"
+)
+
+const subscriptionTemplate = `{
 	"jsonrpc": "2.0",
-	"method": "eth_subscription",
+	"method": "%s",
 	"params": {
 		"subscription":"%s",
 		"result": %s
@@ -95,13 +120,18 @@ const ethSubscriptionTemplate = `{
 
 // writeMessageToWs sends given message to websocket stream
 func (f *filterBase) writeMessageToWs(msg string) error {
+	return f.writeNotificationToWs(ethSubscription, msg)
+}
+
+// writeNotificationToWs sends given message to websocket stream as a notification of the given method
+func (f *filterBase) writeNotificationToWs(method, msg string) error {
 	if !f.hasWSConn() {
 		return ErrNoWSConnection
 	}
 
 	return f.ws.WriteMessage(
 		websocket.TextMessage,
-		[]byte(fmt.Sprintf(ethSubscriptionTemplate, f.id, msg)),
+		[]byte(fmt.Sprintf(subscriptionTemplate, method, f.id, msg)),
 	)
 }
 
@@ -168,11 +198,15 @@ type logFilter struct {
 	logs  []*Log
 }
 
-// appendLog appends new log to logs
+// appendLog appends new log to logs, dropping the oldest one if the buffer is full
 func (f *logFilter) appendLog(log *Log) {
 	f.Lock()
 	defer f.Unlock()
 
+	if len(f.logs) >= maxFilterUpdates {
+		f.logs = f.logs[1:]
+	}
+
 	f.logs = append(f.logs, log)
 }
 
@@ -212,14 +246,210 @@ func (f *logFilter) sendUpdates() error {
 	return nil
 }
 
+// storageFilter is a filter to store the changes of watched storage slots
+type storageFilter struct {
+	filterBase
+	sync.Mutex
+
+	query   *StorageQuery
+	changes []*StorageChange
+}
+
+// appendChange appends new storage change to changes
+func (f *storageFilter) appendChange(change *StorageChange) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.changes = append(f.changes, change)
+}
+
+// takeChangeUpdates returns all saved storage changes in filter and set new change slice
+func (f *storageFilter) takeChangeUpdates() []*StorageChange {
+	f.Lock()
+	defer f.Unlock()
+
+	changes := f.changes
+	f.changes = []*StorageChange{}
+
+	return changes
+}
+
+// getUpdates returns stored storage changes
+func (f *storageFilter) getUpdates() (interface{}, error) {
+	return f.takeChangeUpdates(), nil
+}
+
+// sendUpdates writes stored storage changes to web socket stream
+func (f *storageFilter) sendUpdates() error {
+	changes := f.takeChangeUpdates()
+
+	for _, change := range changes {
+		res, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeMessageToWs(string(res)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// accountFilter is a filter to store the changes of watched accounts
+type accountFilter struct {
+	filterBase
+	sync.Mutex
+
+	query   *AccountQuery
+	changes []*AccountChange
+}
+
+// appendChange appends new account change to changes
+func (f *accountFilter) appendChange(change *AccountChange) {
+	f.Lock()
+	defer f.Unlock()
+
+	if len(f.changes) >= maxFilterUpdates {
+		f.changes = f.changes[1:]
+	}
+
+	f.changes = append(f.changes, change)
+}
+
+// takeChangeUpdates returns all saved account changes in filter and set new change slice
+func (f *accountFilter) takeChangeUpdates() []*AccountChange {
+	f.Lock()
+	defer f.Unlock()
+
+	changes := f.changes
+	f.changes = []*AccountChange{}
+
+	return changes
+}
+
+// getUpdates returns stored account changes
+func (f *accountFilter) getUpdates() (interface{}, error) {
+	return f.takeChangeUpdates(), nil
+}
+
+// sendUpdates writes stored account changes to web socket stream
+func (f *accountFilter) sendUpdates() error {
+	changes := f.takeChangeUpdates()
+
+	for _, change := range changes {
+		res, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeNotificationToWs(edgeSubscription, string(res)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pendingTxFilter is a filter to store the transactions which become pending in the txpool
+type pendingTxFilter struct {
+	filterBase
+	sync.Mutex
+
+	fullTx bool
+	txs    []*types.Transaction
+}
+
+// appendTx appends new transaction to txs, dropping the oldest one if the buffer is full
+func (f *pendingTxFilter) appendTx(tx *types.Transaction) {
+	f.Lock()
+	defer f.Unlock()
+
+	if len(f.txs) >= maxFilterUpdates {
+		f.txs = f.txs[1:]
+	}
+
+	f.txs = append(f.txs, tx)
+}
+
+// takeTxUpdates returns all saved transactions in filter and set new transaction slice
+func (f *pendingTxFilter) takeTxUpdates() []*types.Transaction {
+	f.Lock()
+	defer f.Unlock()
+
+	txs := f.txs
+	f.txs = []*types.Transaction{}
+
+	return txs
+}
+
+// getUpdates returns the stored transactions, or their hashes if full transactions are not requested
+func (f *pendingTxFilter) getUpdates() (interface{}, error) {
+	txs := f.takeTxUpdates()
+
+	if f.fullTx {
+		res := make([]*transaction, len(txs))
+		for i, tx := range txs {
+			res[i] = toPendingTransaction(tx)
+		}
+
+		return res, nil
+	}
+
+	res := make([]types.Hash, len(txs))
+	for i, tx := range txs {
+		res[i] = tx.Hash
+	}
+
+	return res, nil
+}
+
+// sendUpdates writes stored transactions to web socket stream
+func (f *pendingTxFilter) sendUpdates() error {
+	txs := f.takeTxUpdates()
+
+	for _, tx := range txs {
+		var (
+			res []byte
+			err error
+		)
+
+		if f.fullTx {
+			res, err = json.Marshal(toPendingTransaction(tx))
+		} else {
+			res, err = json.Marshal(tx.Hash)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeMessageToWs(string(res)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // filterManagerStore provides methods required by FilterManager
 type filterManagerStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the last block finalized by the consensus (genesis if none)
+	FinalizedHeader() *types.Header
+
 	// SubscribeEvents subscribes for chain head events
 	SubscribeEvents() blockchain.Subscription
 
+	// SubscribeTxEvents subscribes for the txpool events of the given types
+	SubscribeTxEvents(eventTypes ...txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func())
+
+	// GetPendingTx returns the transaction from the txpool, if it's present
+	GetPendingTx(txHash types.Hash) (*types.Transaction, bool)
+
 	// GetReceiptsByHash returns the receipts for a block hash
 	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
 
@@ -228,6 +458,19 @@ type filterManagerStore interface {
 
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// IterateHeaders calls the handler for every canonical header in the range, without loading the block bodies
+	IterateHeaders(from, to uint64, handler func(*types.Header) bool) error
+
+	// GetStorageChanges returns the modifications of the watched storage slots made by the block
+	GetStorageChanges(header *types.Header, watches state.StorageWatches) ([]*state.StorageChange, error)
+
+	// GetAccountChanges returns the watched accounts whose balance, nonce or code are changed by the block
+	GetAccountChanges(header *types.Header, addrs []types.Address) ([]*state.AccountChange, error)
+
+	// GetBloomMatches returns the numbers of the blocks in the range which possibly match the bloom filter,
+	// along with the number of the first block which is not covered by the bloom bits index
+	GetBloomMatches(from, to uint64, filter [][][]byte) ([]uint64, uint64)
 }
 
 // FilterManager manages all running filters
@@ -238,6 +481,17 @@ type FilterManager struct {
 
 	timeout time.Duration
 
+	// maximum number of polling filters a client can install, 0 means no limit
+	maxFiltersPerClient uint64
+	clientFilters       map[string]uint64
+
+	// file storing the polling filters, they are not persisted if empty
+	path  string
+	dirty bool
+
+	// number of the last block processed by the filters
+	processed uint64
+
 	store           filterManagerStore
 	subscription    blockchain.Subscription
 	blockStream     *blockStream
@@ -258,6 +512,7 @@ func NewFilterManager(logger hclog.Logger, store filterManagerStore, blockRangeL
 		blockStream:     &blockStream{},
 		blockRangeLimit: blockRangeLimit,
 		filters:         make(map[string]filter),
+		clientFilters:   make(map[string]uint64),
 		timeouts:        timeHeapImpl{},
 		updateCh:        make(chan struct{}),
 		closeCh:         make(chan struct{}),
@@ -266,6 +521,7 @@ func NewFilterManager(logger hclog.Logger, store filterManagerStore, blockRangeL
 	// start blockstream with the current header
 	header := store.Header()
 	m.blockStream.push(header)
+	m.processed = header.Number
 
 	// start the head watcher
 	m.subscription = store.SubscribeEvents()
@@ -288,6 +544,10 @@ func (f *FilterManager) Run() {
 		}
 	}()
 
+	// watch for the transactions which become pending in the txpool
+	txEventCh, cancelTxEvents := f.store.SubscribeTxEvents(txpoolProto.EventType_PROMOTED)
+	defer cancelTxEvents()
+
 	var timeoutCh <-chan time.Time
 
 	for {
@@ -306,6 +566,23 @@ func (f *FilterManager) Run() {
 				f.logger.Error("failed to dispatch event", "err", err)
 			}
 
+			// the polling filters are persisted at most once per block
+			if err := f.persistFilters(); err != nil {
+				f.logger.Error("failed to persist filters", "err", err)
+			}
+
+		case txEvent, more := <-txEventCh:
+			if !more {
+				// the txpool subscription is closed
+				txEventCh = nil
+
+				break
+			}
+
+			if err := f.dispatchPendingTx(types.StringToHash(txEvent.TxHash)); err != nil {
+				f.logger.Error("failed to dispatch pending transaction", "err", err)
+			}
+
 		case <-timeoutCh:
 			// timeout for filter
 			// if filter still exists
@@ -318,6 +595,10 @@ func (f *FilterManager) Run() {
 
 		case <-f.closeCh:
 			// stop the filter manager
+			if err := f.persistFilters(); err != nil {
+				f.logger.Error("failed to persist filters", "err", err)
+			}
+
 			return
 		}
 	}
@@ -356,6 +637,71 @@ func (f *FilterManager) NewLogFilter(logQuery *LogQuery, ws wsConn) string {
 	return f.addFilter(filter)
 }
 
+// NewStorageFilter adds new StorageFilter
+func (f *FilterManager) NewStorageFilter(storageQuery *StorageQuery, ws wsConn) string {
+	filter := &storageFilter{
+		filterBase: newFilterBase(ws),
+		query:      storageQuery,
+	}
+
+	if filter.hasWSConn() {
+		ws.SetFilterID(filter.id)
+	}
+
+	return f.addFilter(filter)
+}
+
+// NewAccountFilter adds new AccountFilter
+func (f *FilterManager) NewAccountFilter(accountQuery *AccountQuery, ws wsConn) string {
+	filter := &accountFilter{
+		filterBase: newFilterBase(ws),
+		query:      accountQuery,
+	}
+
+	if filter.hasWSConn() {
+		ws.SetFilterID(filter.id)
+	}
+
+	return f.addFilter(filter)
+}
+
+// NewPendingTxFilter adds new PendingTxFilter
+func (f *FilterManager) NewPendingTxFilter(fullTx bool, ws wsConn) string {
+	filter := &pendingTxFilter{
+		filterBase: newFilterBase(ws),
+		fullTx:     fullTx,
+	}
+
+	if filter.hasWSConn() {
+		ws.SetFilterID(filter.id)
+	}
+
+	return f.addFilter(filter)
+}
+
+// NewPollingLogFilter adds new LogFilter polled by the client
+func (f *FilterManager) NewPollingLogFilter(client string, logQuery *LogQuery) (string, error) {
+	return f.addPollingFilter(client, &logFilter{
+		filterBase: newFilterBase(nil),
+		query:      logQuery,
+	})
+}
+
+// NewPollingBlockFilter adds new BlockFilter polled by the client
+func (f *FilterManager) NewPollingBlockFilter(client string) (string, error) {
+	return f.addPollingFilter(client, &blockFilter{
+		filterBase: newFilterBase(nil),
+		block:      f.blockStream.Head(),
+	})
+}
+
+// NewPollingPendingTxFilter adds new PendingTxFilter polled by the client
+func (f *FilterManager) NewPollingPendingTxFilter(client string) (string, error) {
+	return f.addPollingFilter(client, &pendingTxFilter{
+		filterBase: newFilterBase(nil),
+	})
+}
+
 // Exists checks the filter with given ID exists
 func (f *FilterManager) Exists(id string) bool {
 	f.RLock()
@@ -405,6 +751,8 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 			num = 0
 		case LatestBlockNumber:
 			return latestBlockNumber, nil
+		case FinalizedBlockNumber, SafeBlockNumber:
+			return f.store.FinalizedHeader().Number, nil
 		}
 
 		return uint64(num), nil
@@ -437,23 +785,61 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 
 	logs := make([]*Log, 0)
 
-	for i := from; i <= to; i++ {
-		block, ok := f.store.GetBlockByNumber(i, true)
-		if !ok {
-			break
+	// use the bloom bits index to only check the blocks which possibly contain
+	// matching logs, the part of the range which is not indexed yet is scanned
+	if filter := query.bloomFilter(); filter != nil {
+		matches, next := f.store.GetBloomMatches(from, to, filter)
+
+		for _, num := range matches {
+			block, ok := f.store.GetBlockByNumber(num, true)
+			if !ok {
+				return logs, nil
+			}
+
+			if len(block.Transactions) == 0 {
+				continue
+			}
+
+			blockLogs, err := f.getLogsFromBlock(query, block)
+			if err != nil {
+				return nil, err
+			}
+
+			logs = append(logs, blockLogs...)
 		}
 
-		if len(block.Transactions) == 0 {
+		from = next
+	}
+
+	var blockErr error
+
+	// only the headers are scanned, the bodies are loaded for the blocks with receipts.
+	// The iteration error is ignored, as the range can go past the current head
+	_ = f.store.IterateHeaders(from, to, func(header *types.Header) bool {
+		if !header.HasReceipts() {
 			// do not check logs if no txs
-			continue
+			return true
+		}
+
+		block, ok := f.store.GetBlockByHash(header.Hash, true)
+		if !ok {
+			return false
 		}
 
 		blockLogs, err := f.getLogsFromBlock(query, block)
 		if err != nil {
-			return nil, err
+			blockErr = err
+
+			return false
 		}
 
 		logs = append(logs, blockLogs...)
+
+		return true
+	})
+
+	if blockErr != nil {
+		return nil, blockErr
 	}
 
 	return logs, nil
@@ -506,12 +892,17 @@ func (f *FilterManager) GetLogFilterFromID(filterID string) (*logFilter, error)
 
 // GetFilterChanges returns the updates of the filter with given ID in string, and refreshes the timeout on the filter
 func (f *FilterManager) GetFilterChanges(id string) (interface{}, error) {
+	// the updates taken are at least the ones of the blocks processed so far
+	processed := f.processedNumber()
+
 	filter, res, err := f.getFilterAndChanges(id)
 
 	if err == nil && !filter.hasWSConn() {
 		// Refresh the timeout on this filter
 		f.Lock()
 		f.refreshFilterTimeout(filter.getFilterBase())
+		filter.getFilterBase().lastBlock = processed
+		f.dirty = true
 		f.Unlock()
 	}
 
@@ -560,6 +951,17 @@ func (f *FilterManager) removeFilterByID(id string) bool {
 
 	delete(f.filters, id)
 
+	base := filter.getFilterBase()
+	if base.client != "" {
+		if f.clientFilters[base.client]--; f.clientFilters[base.client] == 0 {
+			delete(f.clientFilters, base.client)
+		}
+	}
+
+	if !filter.hasWSConn() {
+		f.dirty = true
+	}
+
 	if removed := f.timeouts.removeFilter(filter.getFilterBase()); removed {
 		f.emitSignalToUpdateCh()
 	}
@@ -593,16 +995,53 @@ func (f *FilterManager) addFilter(filter filter) string {
 	f.Lock()
 	defer f.Unlock()
 
+	base := filter.getFilterBase()
+	base.lastBlock = f.processedNumber()
+
+	f.insertFilter(filter)
+
+	return base.id
+}
+
+// addPollingFilter adds the filter installed by the client, if the client is within its filter limit
+func (f *FilterManager) addPollingFilter(client string, filter filter) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if client != "" && f.maxFiltersPerClient != 0 && f.clientFilters[client] >= f.maxFiltersPerClient {
+		return "", ErrTooManyFilters
+	}
+
+	base := filter.getFilterBase()
+	base.client = client
+	base.lastBlock = f.processedNumber()
+
+	f.insertFilter(filter)
+
+	return base.id, nil
+}
+
+// insertFilter adds the filter to list and heap [NOT Thread Safe]
+func (f *FilterManager) insertFilter(filter filter) {
 	base := filter.getFilterBase()
 
 	f.filters[base.id] = filter
 
+	if base.client != "" {
+		f.clientFilters[base.client]++
+	}
+
 	// Set timeout and add to heap if filter doesn't have web socket connection
 	if !filter.hasWSConn() {
 		f.addFilterTimeout(base)
+
+		f.dirty = true
 	}
+}
 
-	return base.id
+// processedNumber returns the number of the last block processed by the filters
+func (f *FilterManager) processedNumber() uint64 {
+	return atomic.LoadUint64(&f.processed)
 }
 
 func (f *FilterManager) emitSignalToUpdateCh() {
@@ -642,6 +1081,26 @@ func (f *FilterManager) dispatchEvent(evnt *blockchain.Event) error {
 	return nil
 }
 
+// dispatchPendingTx is an event handler for the transactions which become pending in the txpool
+func (f *FilterManager) dispatchPendingTx(txHash types.Hash) error {
+	pendingTxFilters := f.getPendingTxFilters()
+	if len(pendingTxFilters) == 0 {
+		return nil
+	}
+
+	// the transaction could have been already removed from the pool
+	tx, ok := f.store.GetPendingTx(txHash)
+	if !ok {
+		return nil
+	}
+
+	for _, filter := range pendingTxFilters {
+		filter.appendTx(tx)
+	}
+
+	return f.flushWsFilters()
+}
+
 // processEvent makes each filter append the new data that interests them
 func (f *FilterManager) processEvent(evnt *blockchain.Event) {
 	f.RLock()
@@ -655,6 +1114,18 @@ func (f *FilterManager) processEvent(evnt *blockchain.Event) {
 		if processErr := f.appendLogsToFilters(header); processErr != nil {
 			f.logger.Error(fmt.Sprintf("Unable to process block, %v", processErr))
 		}
+
+		// process new chain to include the changes of watched slots for StorageFilter
+		if processErr := f.appendStorageChangesToFilters(header); processErr != nil {
+			f.logger.Error(fmt.Sprintf("Unable to process storage changes, %v", processErr))
+		}
+
+		// process new chain to include the changes of watched accounts for AccountFilter
+		if processErr := f.appendAccountChangesToFilters(header); processErr != nil {
+			f.logger.Error(fmt.Sprintf("Unable to process account changes, %v", processErr))
+		}
+
+		atomic.StoreUint64(&f.processed, header.Number)
 	}
 }
 
@@ -705,6 +1176,101 @@ func (f *FilterManager) appendLogsToFilters(header *types.Header) error {
 	return nil
 }
 
+// appendStorageChangesToFilters makes each StorageFilter append the changes of its watched slots in the header
+func (f *FilterManager) appendStorageChangesToFilters(header *types.Header) error {
+	storageFilters := f.getStorageFilters()
+	if len(storageFilters) == 0 {
+		return nil
+	}
+
+	// merge the watched slots of all filters so the block is traced only once
+	watches := state.StorageWatches{}
+	watched := map[types.Address]map[types.Hash]struct{}{}
+
+	for _, filter := range storageFilters {
+		addr := filter.query.Address
+		if _, ok := watched[addr]; !ok {
+			watched[addr] = map[types.Hash]struct{}{}
+		}
+
+		for _, slot := range filter.query.Slots {
+			if _, ok := watched[addr][slot]; ok {
+				continue
+			}
+
+			watched[addr][slot] = struct{}{}
+			watches[addr] = append(watches[addr], slot)
+		}
+	}
+
+	changes, err := f.store.GetStorageChanges(header, watches)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		for _, filter := range storageFilters {
+			if filter.query.Match(change) {
+				filter.appendChange(&StorageChange{
+					Address:     change.Address,
+					Slot:        change.Slot,
+					OldValue:    change.OldValue,
+					NewValue:    change.NewValue,
+					BlockNumber: argUint64(header.Number),
+					BlockHash:   header.Hash,
+					TxHash:      change.TxHash,
+					TxIndex:     argUint64(change.TxIndex),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendAccountChangesToFilters makes each AccountFilter append the changes of its watched accounts in the header
+func (f *FilterManager) appendAccountChangesToFilters(header *types.Header) error {
+	accountFilters := f.getAccountFilters()
+	if len(accountFilters) == 0 {
+		return nil
+	}
+
+	// merge the watched accounts of all filters so they are compared only once
+	addrs := make([]types.Address, 0)
+	watched := map[types.Address]struct{}{}
+
+	for _, filter := range accountFilters {
+		for _, addr := range filter.query.Addresses {
+			if _, ok := watched[addr]; !ok {
+				watched[addr] = struct{}{}
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	changes, err := f.store.GetAccountChanges(header, addrs)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		for _, filter := range accountFilters {
+			if filter.query.Match(change) {
+				filter.appendChange(&AccountChange{
+					Address:     change.Address,
+					Balance:     argBig(*change.Balance),
+					Nonce:       argUint64(change.Nonce),
+					CodeHash:    change.CodeHash,
+					BlockNumber: argUint64(header.Number),
+					BlockHash:   header.Hash,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
 // flushWsFilters make each filters with web socket connection write the updates to web socket stream
 // flushWsFilters also removes the filters if flushWsFilters notices the connection is closed
 func (f *FilterManager) flushWsFilters() error {
@@ -718,8 +1284,9 @@ func (f *FilterManager) flushWsFilters() error {
 		}
 
 		if flushErr := filter.sendUpdates(); flushErr != nil {
-			// mark as closed if the connection is closed
-			if errors.Is(flushErr, websocket.ErrCloseSent) || errors.Is(flushErr, net.ErrClosed) {
+			// mark as closed if the connection is closed, or the peer doesn't read the messages in time
+			if errors.Is(flushErr, websocket.ErrCloseSent) || errors.Is(flushErr, net.ErrClosed) ||
+				errors.Is(flushErr, os.ErrDeadlineExceeded) {
 				closedFilterIDs = append(closedFilterIDs, id)
 
 				f.logger.Warn(fmt.Sprintf("Subscription %s has been closed", id))
@@ -763,6 +1330,54 @@ func (f *FilterManager) getLogFilters() []*logFilter {
 	return logFilters
 }
 
+// getAccountFilters returns accountFilters
+func (f *FilterManager) getAccountFilters() []*accountFilter {
+	f.RLock()
+	defer f.RUnlock()
+
+	accountFilters := make([]*accountFilter, 0)
+
+	for _, f := range f.filters {
+		if accountFilter, ok := f.(*accountFilter); ok {
+			accountFilters = append(accountFilters, accountFilter)
+		}
+	}
+
+	return accountFilters
+}
+
+// getStorageFilters returns storageFilters
+func (f *FilterManager) getStorageFilters() []*storageFilter {
+	f.RLock()
+	defer f.RUnlock()
+
+	storageFilters := make([]*storageFilter, 0)
+
+	for _, f := range f.filters {
+		if storageFilter, ok := f.(*storageFilter); ok {
+			storageFilters = append(storageFilters, storageFilter)
+		}
+	}
+
+	return storageFilters
+}
+
+// getPendingTxFilters returns pendingTxFilters
+func (f *FilterManager) getPendingTxFilters() []*pendingTxFilter {
+	f.RLock()
+	defer f.RUnlock()
+
+	pendingTxFilters := make([]*pendingTxFilter, 0)
+
+	for _, f := range f.filters {
+		if pendingTxFilter, ok := f.(*pendingTxFilter); ok {
+			pendingTxFilters = append(pendingTxFilters, pendingTxFilter)
+		}
+	}
+
+	return pendingTxFilters
+}
+
 type timeHeapImpl []*filterBase
 
 func (t *timeHeapImpl) addFilter(filter *filterBase) {
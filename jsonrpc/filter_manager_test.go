@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-hclog"
@@ -239,6 +240,140 @@ func TestFilterLog(t *testing.T) {
 	}
 }
 
+func TestFilterStorage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		contract     = types.StringToAddress("1")
+		watchedSlot  = types.StringToHash("1")
+		ignoredSlot  = types.StringToHash("2")
+		header       = &types.Header{Number: 1, Hash: hash1}
+		store        = newMockStore()
+		expectedDiff = &state.StorageChange{
+			Address:  contract,
+			Slot:     watchedSlot,
+			OldValue: types.ZeroHash,
+			NewValue: types.StringToHash("10"),
+			TxIndex:  1,
+			TxHash:   hash3,
+		}
+	)
+
+	store.storageChanges = map[types.Hash][]*state.StorageChange{
+		hash1: {
+			expectedDiff,
+			{
+				Address:  contract,
+				Slot:     ignoredSlot,
+				NewValue: types.StringToHash("20"),
+				TxHash:   hash3,
+			},
+		},
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	go m.Run()
+
+	id := m.NewStorageFilter(&StorageQuery{
+		Address: contract,
+		Slots:   []types.Hash{watchedSlot},
+	}, nil)
+
+	store.emitEvent(&mockEvent{
+		NewChain: []*mockHeader{
+			{
+				header: header,
+			},
+		},
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	res, err := m.GetFilterChanges(id)
+	assert.NoError(t, err)
+
+	changes, ok := res.([]*StorageChange)
+	assert.True(t, ok)
+	assert.Equal(t, []*StorageChange{
+		{
+			Address:     contract,
+			Slot:        watchedSlot,
+			OldValue:    types.ZeroHash,
+			NewValue:    expectedDiff.NewValue,
+			BlockNumber: argUint64(header.Number),
+			BlockHash:   header.Hash,
+			TxHash:      hash3,
+			TxIndex:     argUint64(1),
+		},
+	}, changes)
+}
+
+func TestFilterAccountChanges(t *testing.T) {
+	t.Parallel()
+
+	var (
+		watched  = types.StringToAddress("1")
+		ignored  = types.StringToAddress("2")
+		codeHash = types.StringToHash("3")
+		header   = &types.Header{Number: 1, Hash: hash1}
+		store    = newMockStore()
+	)
+
+	store.accountChanges = map[types.Hash][]*state.AccountChange{
+		hash1: {
+			{
+				Address:  watched,
+				Balance:  big.NewInt(10),
+				Nonce:    2,
+				CodeHash: codeHash,
+			},
+			{
+				Address:  ignored,
+				Balance:  big.NewInt(20),
+				Nonce:    1,
+				CodeHash: codeHash,
+			},
+		},
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	go m.Run()
+
+	id := m.NewAccountFilter(&AccountQuery{
+		Addresses: []types.Address{watched},
+	}, nil)
+
+	store.emitEvent(&mockEvent{
+		NewChain: []*mockHeader{
+			{
+				header: header,
+			},
+		},
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	res, err := m.GetFilterChanges(id)
+	assert.NoError(t, err)
+
+	changes, ok := res.([]*AccountChange)
+	assert.True(t, ok)
+	assert.Equal(t, []*AccountChange{
+		{
+			Address:     watched,
+			Balance:     argBig(*big.NewInt(10)),
+			Nonce:       argUint64(2),
+			CodeHash:    codeHash,
+			BlockNumber: argUint64(header.Number),
+			BlockHash:   header.Hash,
+		},
+	}, changes)
+}
+
 func TestFilterBlock(t *testing.T) {
 	t.Parallel()
 
@@ -304,6 +439,68 @@ func TestFilterBlock(t *testing.T) {
 	}
 }
 
+func TestFilterPendingTx(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	go m.Run()
+
+	hashID := m.NewPendingTxFilter(false, nil)
+	fullID := m.NewPendingTxFilter(true, nil)
+
+	tx := &types.Transaction{
+		Nonce:    1,
+		GasPrice: big.NewInt(10),
+		Value:    big.NewInt(0),
+		V:        big.NewInt(1),
+		R:        big.NewInt(1),
+		S:        big.NewInt(1),
+	}
+	tx.ComputeHash()
+
+	store.emitPendingTx(tx)
+
+	// we need to wait for the manager to process the data
+	time.Sleep(500 * time.Millisecond)
+
+	hashes, err := m.GetFilterChanges(hashID)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Hash{tx.Hash}, hashes)
+
+	txs, err := m.GetFilterChanges(fullID)
+	assert.NoError(t, err)
+
+	//nolint:forcetypeassert
+	fullTxs := txs.([]*transaction)
+	assert.Len(t, fullTxs, 1)
+	assert.Equal(t, tx.Hash, fullTxs[0].Hash)
+	assert.Nil(t, fullTxs[0].BlockHash)
+
+	// the updates are taken once
+	hashes, err = m.GetFilterChanges(hashID)
+	assert.NoError(t, err)
+	assert.Len(t, hashes, 0)
+}
+
+func TestFilterPendingTx_DropOldest(t *testing.T) {
+	t.Parallel()
+
+	filter := &pendingTxFilter{}
+
+	for i := 0; i < maxFilterUpdates+2; i++ {
+		filter.appendTx(&types.Transaction{Nonce: uint64(i)})
+	}
+
+	txs := filter.takeTxUpdates()
+
+	assert.Len(t, txs, maxFilterUpdates)
+	assert.Equal(t, uint64(2), txs[0].Nonce)
+}
+
 func TestFilterTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -324,6 +521,44 @@ func TestFilterTimeout(t *testing.T) {
 	assert.False(t, m.Exists(id))
 }
 
+func TestFilterManager_MaxFiltersPerClient(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	m.maxFiltersPerClient = 2
+
+	go m.Run()
+
+	id, err := m.NewPollingBlockFilter("client1")
+	assert.NoError(t, err)
+
+	_, err = m.NewPollingLogFilter("client1", &LogQuery{})
+	assert.NoError(t, err)
+
+	_, err = m.NewPollingPendingTxFilter("client1")
+	assert.ErrorIs(t, err, ErrTooManyFilters)
+
+	// the limit is per client
+	_, err = m.NewPollingBlockFilter("client2")
+	assert.NoError(t, err)
+
+	// the uninstalled filters don't count
+	assert.True(t, m.Uninstall(id))
+
+	_, err = m.NewPollingPendingTxFilter("client1")
+	assert.NoError(t, err)
+
+	// the filters of unknown clients are not limited
+	for i := 0; i < 3; i++ {
+		_, err = m.NewPollingBlockFilter("")
+		assert.NoError(t, err)
+	}
+}
+
 func TestRemoveFilterByWebsocket(t *testing.T) {
 	t.Parallel()
 
@@ -467,6 +702,33 @@ func TestFilterWebsocket(t *testing.T) {
 	}
 }
 
+func TestFilterPendingTxWebsocket(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	mock, msgCh := newMockWsConnWithMsgCh()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	go m.Run()
+
+	m.NewPendingTxFilter(false, mock)
+
+	tx := &types.Transaction{Nonce: 1}
+	tx.ComputeHash()
+
+	store.emitPendingTx(tx)
+
+	select {
+	case msg := <-msgCh:
+		assert.Contains(t, string(msg), tx.Hash.String())
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending transaction not received in 2 seconds")
+	}
+}
+
 type mockWsConn struct {
 	SetFilterIDFn  func(string)
 	GetFilterIDFn  func() string
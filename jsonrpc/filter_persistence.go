@@ -0,0 +1,271 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	persistedLogFilter       = "logs"
+	persistedBlockFilter     = "blocks"
+	persistedPendingTxFilter = "pendingTransactions"
+)
+
+// persistedFilter is a polling filter stored across the restarts of the node
+type persistedFilter struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Client    string `json:"client,omitempty"`
+	LastBlock uint64 `json:"lastBlock"`
+
+	// query of the log filter
+	BlockHash *types.Hash     `json:"blockHash,omitempty"`
+	FromBlock int64           `json:"fromBlock,omitempty"`
+	ToBlock   int64           `json:"toBlock,omitempty"`
+	Addresses []types.Address `json:"addresses,omitempty"`
+	Topics    [][]types.Hash  `json:"topics,omitempty"`
+}
+
+// toPersistedFilter returns the filter in its stored form, nil if the filter isn't persisted
+func toPersistedFilter(filter filter) *persistedFilter {
+	if filter.hasWSConn() {
+		return nil
+	}
+
+	base := filter.getFilterBase()
+	res := &persistedFilter{
+		ID:        base.id,
+		Client:    base.client,
+		LastBlock: base.lastBlock,
+	}
+
+	switch typedFilter := filter.(type) {
+	case *logFilter:
+		res.Kind = persistedLogFilter
+		res.BlockHash = typedFilter.query.BlockHash
+		res.FromBlock = int64(typedFilter.query.fromBlock)
+		res.ToBlock = int64(typedFilter.query.toBlock)
+		res.Addresses = typedFilter.query.Addresses
+		res.Topics = typedFilter.query.Topics
+	case *blockFilter:
+		res.Kind = persistedBlockFilter
+	case *pendingTxFilter:
+		res.Kind = persistedPendingTxFilter
+	default:
+		return nil
+	}
+
+	return res
+}
+
+// persistFilters writes the polling filters into the file, if they changed since the last write
+func (f *FilterManager) persistFilters() error {
+	f.Lock()
+
+	if f.path == "" || !f.dirty {
+		f.Unlock()
+
+		return nil
+	}
+
+	filters := make([]*persistedFilter, 0, len(f.filters))
+
+	for _, filter := range f.filters {
+		if persisted := toPersistedFilter(filter); persisted != nil {
+			filters = append(filters, persisted)
+		}
+	}
+
+	f.dirty = false
+
+	f.Unlock()
+
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].ID < filters[j].ID
+	})
+
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.path)
+}
+
+// restoreFilters installs again the polling filters persisted in the file, with the same IDs,
+// and persists the following changes of the filters into it. The log and block filters get the
+// updates of the blocks processed since they were last polled, the oldest ones are dropped
+// if they exceed the block range limit or the updates buffered by a filter
+func (f *FilterManager) restoreFilters(path string) error {
+	f.path = path
+
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var persisted []*persistedFilter
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return err
+	}
+
+	head := f.store.Header()
+	blocks, earliest := f.rebuildBlockStream(persisted, head)
+	restored := 0
+
+	for _, p := range persisted {
+		filter, err := f.restoreFilter(p, head, blocks, earliest)
+		if err != nil {
+			f.logger.Warn("failed to restore filter", "id", p.ID, "err", err)
+
+			continue
+		}
+
+		base := filter.getFilterBase()
+		base.id = p.ID
+		base.client = p.Client
+		base.lastBlock = p.LastBlock
+
+		f.Lock()
+		f.insertFilter(filter)
+		f.Unlock()
+
+		restored++
+	}
+
+	f.logger.Info("restored filters", "count", restored)
+
+	return nil
+}
+
+// rebuildBlockStream pushes into the block stream the headers from the earliest block
+// the block filters resume from, and returns the stream elements by block number along with the earliest one
+func (f *FilterManager) rebuildBlockStream(
+	persisted []*persistedFilter,
+	head *types.Header,
+) (map[uint64]*headElem, *headElem) {
+	from := head.Number
+
+	for _, p := range persisted {
+		if p.Kind == persistedBlockFilter && p.LastBlock < from {
+			from = p.LastBlock
+		}
+	}
+
+	if head.Number-from > maxFilterUpdates {
+		from = head.Number - maxFilterUpdates
+	}
+
+	var (
+		stream   = &blockStream{}
+		elems    = make(map[uint64]*headElem)
+		earliest *headElem
+	)
+
+	_ = f.store.IterateHeaders(from, head.Number, func(header *types.Header) bool {
+		stream.push(header)
+		elems[header.Number] = stream.Head()
+
+		if earliest == nil {
+			earliest = stream.Head()
+		}
+
+		return true
+	})
+
+	if earliest == nil {
+		return map[uint64]*headElem{}, f.blockStream.Head()
+	}
+
+	f.blockStream = stream
+
+	return elems, earliest
+}
+
+// restoreFilter creates the persisted filter, along with the updates since it was last polled
+func (f *FilterManager) restoreFilter(
+	p *persistedFilter,
+	head *types.Header,
+	blocks map[uint64]*headElem,
+	earliest *headElem,
+) (filter, error) {
+	switch p.Kind {
+	case persistedLogFilter:
+		filter := &logFilter{
+			filterBase: newFilterBase(nil),
+			query: &LogQuery{
+				BlockHash: p.BlockHash,
+				fromBlock: BlockNumber(p.FromBlock),
+				toBlock:   BlockNumber(p.ToBlock),
+				Addresses: p.Addresses,
+				Topics:    p.Topics,
+			},
+		}
+
+		if p.LastBlock >= head.Number {
+			return filter, nil
+		}
+
+		from := p.LastBlock + 1
+		if f.blockRangeLimit != 0 && head.Number-from > f.blockRangeLimit {
+			from = head.Number - f.blockRangeLimit
+		}
+
+		logs, err := f.getLogsFromBlocks(&LogQuery{
+			fromBlock: BlockNumber(from),
+			toBlock:   BlockNumber(head.Number),
+			Addresses: p.Addresses,
+			Topics:    p.Topics,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, log := range logs {
+			filter.appendLog(log)
+		}
+
+		return filter, nil
+
+	case persistedBlockFilter:
+		elem, ok := blocks[p.LastBlock]
+		if !ok {
+			// the filter resumes from the earliest block of the stream if it's
+			// too far behind, or from the head if the chain has been rewound
+			elem = f.blockStream.Head()
+
+			if p.LastBlock < head.Number {
+				elem = earliest
+			}
+		}
+
+		return &blockFilter{
+			filterBase: newFilterBase(nil),
+			block:      elem,
+		}, nil
+
+	case persistedPendingTxFilter:
+		return &pendingTxFilter{
+			filterBase: newFilterBase(nil),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown filter kind %s", p.Kind)
+	}
+}
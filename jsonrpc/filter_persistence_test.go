@@ -0,0 +1,124 @@
+package jsonrpc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// persistedFiltersMockStore is a chain whose head can be moved forward between the restarts
+type persistedFiltersMockStore struct {
+	*mockStore
+
+	headers []*types.Header
+	blocks  map[types.Hash]*types.Block
+}
+
+func (s *persistedFiltersMockStore) Header() *types.Header {
+	return s.headers[len(s.headers)-1]
+}
+
+func (s *persistedFiltersMockStore) IterateHeaders(from, to uint64, handler func(*types.Header) bool) error {
+	for num := from; num <= to && num < uint64(len(s.headers)); num++ {
+		if !handler(s.headers[num]) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *persistedFiltersMockStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	block, ok := s.blocks[hash]
+
+	return block, ok
+}
+
+// addBlock appends a block to the chain, with a transaction emitting the log if it isn't nil
+func (s *persistedFiltersMockStore) addBlock(log *types.Log) *types.Header {
+	header := &types.Header{
+		Number:       uint64(len(s.headers)),
+		Hash:         types.BytesToHash([]byte{byte(len(s.headers) + 1)}),
+		ReceiptsRoot: types.EmptyRootHash,
+	}
+
+	block := &types.Block{Header: header}
+
+	if log != nil {
+		header.ReceiptsRoot = types.StringToHash("receipts")
+		block.Transactions = []*types.Transaction{{Hash: hash3}}
+
+		s.receipts[header.Hash] = []*types.Receipt{{Logs: []*types.Log{log}, TxHash: hash3}}
+	}
+
+	s.headers = append(s.headers, header)
+	s.blocks[header.Hash] = block
+
+	return header
+}
+
+func TestFilterManager_PersistFilters(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "filters.json")
+
+	store := &persistedFiltersMockStore{
+		mockStore: newMockStore(),
+		blocks:    map[types.Hash]*types.Block{},
+	}
+	store.receipts = map[types.Hash][]*types.Receipt{}
+
+	for i := 0; i < 3; i++ {
+		store.addBlock(nil)
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	require.NoError(t, m.restoreFilters(path))
+
+	logID, err := m.NewPollingLogFilter("client1", &LogQuery{Topics: [][]types.Hash{{hash1}}})
+	require.NoError(t, err)
+
+	blockID, err := m.NewPollingBlockFilter("client1")
+	require.NoError(t, err)
+
+	txID, err := m.NewPollingPendingTxFilter("client2")
+	require.NoError(t, err)
+
+	// the web socket subscriptions are not persisted
+	ws, _ := newMockWsConnWithMsgCh()
+	wsID := m.NewBlockFilter(ws)
+
+	require.NoError(t, m.persistFilters())
+
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+
+	// the node goes on while the filters aren't polled
+	header3 := store.addBlock(nil)
+	header4 := store.addBlock(&types.Log{Topics: []types.Hash{hash1}})
+	store.addBlock(&types.Log{Topics: []types.Hash{hash2}})
+
+	restarted := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	require.NoError(t, restarted.restoreFilters(path))
+
+	assert.True(t, restarted.Exists(txID))
+	assert.False(t, restarted.Exists(wsID))
+	assert.Equal(t, uint64(2), restarted.clientFilters["client1"])
+
+	// the restored filters get the updates since they were last polled
+	logs, err := restarted.GetFilterChanges(logID)
+	require.NoError(t, err)
+
+	//nolint:forcetypeassert
+	require.Len(t, logs.([]*Log), 1)
+	assert.Equal(t, header4.Hash, logs.([]*Log)[0].BlockHash) //nolint:forcetypeassert
+
+	blocks, err := restarted.GetFilterChanges(blockID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{header3.Hash.String(), header4.Hash.String(), store.Header().Hash.String()}, blocks)
+}
@@ -0,0 +1,156 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ipcConn is a connection to the IPC endpoint, it serves the subscriptions like a web socket connection
+type ipcConn struct {
+	sync.Mutex
+
+	conn     net.Conn
+	filterID string
+}
+
+func (c *ipcConn) SetFilterID(filterID string) {
+	c.filterID = filterID
+}
+
+func (c *ipcConn) GetFilterID() string {
+	return c.filterID
+}
+
+// WriteMessage writes out the message to the IPC peer, the messages are delimited by new lines
+func (c *ipcConn) WriteMessage(_ int, data []byte) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+
+	msg := make([]byte, 0, len(data)+1)
+	msg = append(msg, data...)
+	msg = append(msg, '\n')
+
+	_, err := c.conn.Write(msg)
+
+	return err
+}
+
+// setupIPC starts serving the JSON-RPC over the unix socket at the IPC path.
+// The socket is only accessible to the user running the client
+func (j *JSONRPC) setupIPC() error {
+	path := j.config.IPCPath
+
+	if err := removeStaleSocket(path); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = lis.Close()
+
+		return err
+	}
+
+	j.ipcListener = lis
+
+	j.logger.Info("ipc server started", "path", path)
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					j.logger.Error("closed ipc listener", "err", err)
+				}
+
+				return
+			}
+
+			go j.handleIPC(conn)
+		}
+	}()
+
+	return nil
+}
+
+// removeStaleSocket removes the socket left over at the path by a client which didn't shut down,
+// it fails if another client is listening on it
+func removeStaleSocket(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("ipc path %s is already in use", path)
+	}
+
+	return os.Remove(path)
+}
+
+// handleIPC serves the requests read from the IPC connection, which is a stream of JSON values.
+// The IPC peers are local, so their requests are not subject to the rate limits
+func (j *JSONRPC) handleIPC(conn net.Conn) {
+	wrapConn := &ipcConn{conn: conn}
+
+	defer func() {
+		j.dispatcher.RemoveFilterByWs(wrapConn)
+
+		_ = conn.Close()
+	}()
+
+	decoder := json.NewDecoder(conn)
+
+	for {
+		var message json.RawMessage
+		if err := decoder.Decode(&message); err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				// the stream can't be read past malformed JSON
+				resp, _ := NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
+				_ = wrapConn.WriteMessage(0, resp)
+			}
+
+			return
+		}
+
+		go func() {
+			resp, err := j.dispatcher.HandleWs(message, wrapConn)
+			if err != nil {
+				j.logger.Error("unable to handle IPC request", "err", err)
+
+				return
+			}
+
+			_ = wrapConn.WriteMessage(0, resp)
+		}()
+	}
+}
+
+// Close stops serving the IPC endpoint, and removes its socket
+func (j *JSONRPC) Close() error {
+	if j.ipcListener == nil {
+		return nil
+	}
+
+	return j.ipcListener.Close()
+}
@@ -0,0 +1,74 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIPCServer(t *testing.T, path string) *JSONRPC {
+	t.Helper()
+
+	srv := &JSONRPC{
+		logger:     hclog.NewNullLogger(),
+		config:     &Config{IPCPath: path},
+		dispatcher: newDispatcher(hclog.NewNullLogger(), newMockStore(), &dispatcherParams{chainID: 100}),
+	}
+
+	require.NoError(t, srv.setupIPC())
+
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+
+	return srv
+}
+
+func TestIPCServer(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ipc", "edge.ipc")
+
+	// a socket left over by a previous run is replaced
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	newTestIPCServer(t, path)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the socket is not taken over while it is served
+	assert.ErrorContains(t, removeStaleSocket(path), "already in use")
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	// the requests are a stream of JSON values, the responses are delimited by new lines
+	_, err = conn.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "net_version", "params": []}` +
+		`[{"jsonrpc": "2.0", "id": 2, "method": "net_version", "params": []}]`))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+
+	responses := make([]string, 0, 2)
+
+	for i := 0; i < 2; i++ {
+		line, err := reader.ReadBytes('\n')
+		require.NoError(t, err)
+
+		responses = append(responses, string(line))
+	}
+
+	assert.Contains(t, responses, `{"jsonrpc":"2.0","id":1,"result":"100"}`+"\n")
+	assert.Contains(t, responses, `[{"jsonrpc":"2.0","id":2,"result":"100"}]`+"\n")
+}
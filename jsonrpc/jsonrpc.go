@@ -40,12 +40,20 @@ type JSONRPC struct {
 	logger     hclog.Logger
 	config     *Config
 	dispatcher dispatcher
+
+	policyLock sync.RWMutex
+	policy     *Policy
+	limiter    *requestLimiter
+
+	ipcListener net.Listener
 }
 
 type dispatcher interface {
 	RemoveFilterByWs(conn wsConn)
 	HandleWs(reqBody []byte, conn wsConn) ([]byte, error)
 	Handle(reqBody []byte) ([]byte, error)
+	HandleFrom(client string, reqBody []byte) ([]byte, error)
+	setTxLimits(priceLimit uint64, txLimits txLimiterConfig)
 }
 
 // JSONRPCStore defines all the methods required
@@ -55,35 +63,98 @@ type JSONRPCStore interface {
 	networkStore
 	txPoolStore
 	filterManagerStore
+	debugStore
+	accumulatorStore
+	edgeStore
 }
 
 type Config struct {
-	Store                    JSONRPCStore
-	Addr                     *net.TCPAddr
-	ChainID                  uint64
-	ChainName                string
-	AccessControlAllowOrigin []string
-	PriceLimit               uint64
-	BatchLengthLimit         uint64
-	BlockRangeLimit          uint64
+	Store            JSONRPCStore
+	Addr             *net.TCPAddr
+	IPCPath          string
+	ChainID          uint64
+	ChainName        string
+	Policy           *Policy
+	Webhooks         WebhookStore
+	UnsafeDebug      UnsafeDebugStore
+	Admin            AdminStore
+	Profiler         ProfilerStore
+	Evm              EvmStore
+	GraphQL          http.Handler
+	Health           http.Handler
+	PriceLimit       uint64
+	BatchLengthLimit uint64
+	BatchGasLimit    uint64
+	BlockRangeLimit  uint64
+	StateHistory     uint64
+	StatePinInterval uint64
+
+	FilterTimeout       time.Duration
+	MaxFiltersPerClient uint64
+	FiltersPath         string
+
+	// TxSenderRateLimit and TxIPRateLimit are the transactions per second accepted
+	// by eth_sendRawTransaction from a single sender and IP, 0 disables the limit
+	TxSenderRateLimit uint64
+	TxIPRateLimit     uint64
+	TxRateBurst       uint64
 }
 
 // NewJSONRPC returns the JSONRPC http server
 func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
-	srv := &JSONRPC{
-		logger: logger.Named("jsonrpc"),
-		config: config,
-		dispatcher: newDispatcher(
-			logger,
-			config.Store,
-			&dispatcherParams{
-				chainID:                 config.ChainID,
-				chainName:               config.ChainName,
-				priceLimit:              config.PriceLimit,
-				jsonRPCBatchLengthLimit: config.BatchLengthLimit,
-				blockRangeLimit:         config.BlockRangeLimit,
+	d := newDispatcher(
+		logger,
+		config.Store,
+		&dispatcherParams{
+			chainID:                 config.ChainID,
+			chainName:               config.ChainName,
+			priceLimit:              config.PriceLimit,
+			jsonRPCBatchLengthLimit: config.BatchLengthLimit,
+			jsonRPCBatchGasLimit:    config.BatchGasLimit,
+			blockRangeLimit:         config.BlockRangeLimit,
+			stateHistory:            config.StateHistory,
+			statePinInterval:        config.StatePinInterval,
+			filterTimeout:           config.FilterTimeout,
+			maxFiltersPerClient:     config.MaxFiltersPerClient,
+			filtersPath:             config.FiltersPath,
+			txLimits: txLimiterConfig{
+				senderRate: config.TxSenderRateLimit,
+				ipRate:     config.TxIPRateLimit,
+				burst:      config.TxRateBurst,
 			},
-		),
+		},
+	)
+
+	if config.Webhooks != nil {
+		d.registerWebhookEndpoint(config.Webhooks)
+	}
+
+	if config.UnsafeDebug != nil {
+		d.registerUnsafeDebugEndpoint(config.UnsafeDebug)
+	}
+
+	if config.Admin != nil {
+		d.registerAdminEndpoint(config.Admin)
+	}
+
+	if config.Profiler != nil {
+		d.registerProfilerEndpoint(config.Profiler)
+	}
+
+	if config.Evm != nil {
+		d.registerEvmEndpoint(config.Evm)
+	}
+
+	srv := &JSONRPC{
+		logger:     logger.Named("jsonrpc"),
+		config:     config,
+		dispatcher: d,
+	}
+
+	if config.Policy == nil {
+		srv.SetPolicy(DefaultPolicy())
+	} else {
+		srv.SetPolicy(config.Policy)
 	}
 
 	// start http server
@@ -91,6 +162,12 @@ func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
 		return nil, err
 	}
 
+	if config.IPCPath != "" {
+		if err := srv.setupIPC(); err != nil {
+			return nil, err
+		}
+	}
+
 	return srv, nil
 }
 
@@ -107,11 +184,18 @@ func (j *JSONRPC) setupHTTP() error {
 	// If pprof need to be enabled, this should be DefaultServeMux
 	mux := http.NewServeMux()
 
-	// The middleware factory returns a handler, so we need to wrap the handler function properly.
-	jsonRPCHandler := http.HandlerFunc(j.handle)
-	mux.Handle("/", middlewareFactory(j.config)(jsonRPCHandler))
+	mux.Handle("/", j.policyMiddleware(http.HandlerFunc(j.handle)))
+	mux.Handle("/ws", j.policyMiddleware(http.HandlerFunc(j.handleWs)))
 
-	mux.HandleFunc("/ws", j.handleWs)
+	if j.config.GraphQL != nil {
+		mux.Handle("/graphql", j.policyMiddleware(j.config.GraphQL))
+	}
+
+	// the checks are probed by the load balancers and orchestrators, regardless of the policy
+	if j.config.Health != nil {
+		mux.Handle("/health", j.config.Health)
+		mux.Handle("/readyz", j.config.Health)
+	}
 
 	srv := http.Server{
 		Handler:           mux,
@@ -127,28 +211,94 @@ func (j *JSONRPC) setupHTTP() error {
 	return nil
 }
 
-// The middlewareFactory builds a middleware which enables CORS using the provided config.
-func middlewareFactory(config *Config) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			origin := r.Header.Get("Origin")
+// SetPolicy replaces the access policy of the server, the rate limits of the clients start over
+func (j *JSONRPC) SetPolicy(policy *Policy) {
+	j.policyLock.Lock()
+	defer j.policyLock.Unlock()
 
-			for _, allowedOrigin := range config.AccessControlAllowOrigin {
-				if allowedOrigin == "*" {
-					w.Header().Set("Access-Control-Allow-Origin", "*")
+	j.policy = policy
+	j.limiter = newRequestLimiter(policy)
+}
 
-					break
-				}
+// SetTxLimits replaces the price limit and the transaction rate limits of the server by the ones of the config,
+// the rate limits of the senders and IPs start over
+func (j *JSONRPC) SetTxLimits(config *Config) {
+	j.dispatcher.setTxLimits(config.PriceLimit, txLimiterConfig{
+		senderRate: config.TxSenderRateLimit,
+		ipRate:     config.TxIPRateLimit,
+		burst:      config.TxRateBurst,
+	})
+}
 
-				if allowedOrigin == origin {
-					w.Header().Set("Access-Control-Allow-Origin", origin)
+func (j *JSONRPC) getPolicy() (*Policy, *requestLimiter) {
+	j.policyLock.RLock()
+	defer j.policyLock.RUnlock()
 
-					break
-				}
-			}
-			next.ServeHTTP(w, r)
-		})
+	return j.policy, j.limiter
+}
+
+// policyMiddleware rejects the requests to a host which isn't one of the virtual hosts,
+// and enables CORS for the allowed origins
+func (j *JSONRPC) policyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy, _ := j.getPolicy()
+
+		if !policy.allowsHost(r.Host) {
+			http.Error(w, "invalid host specified", http.StatusForbidden)
+
+			return
+		}
+
+		if allowed, ok := policy.allowsOrigin(r.Header.Get("Origin")); ok {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acquireLimits checks the requests in the body are within the limits of the client,
+// and returns the function releasing them once the requests are handled
+func (j *JSONRPC) acquireLimits(remoteAddr string, reqBody []byte) (func(), Error) {
+	_, limiter := j.getPolicy()
+
+	return limiter.acquire(clientFromAddr(remoteAddr), requestMethods(reqBody))
+}
+
+// clientFromAddr returns the client identified by the remote address of the request
+func clientFromAddr(remoteAddr string) string {
+	client, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+
+	return client
+}
+
+// requestMethods returns the methods of the single or batch request,
+// the invalid requests are left to the dispatcher
+func requestMethods(reqBody []byte) []string {
+	var reqs []Request
+
+	if isBatchRequest(reqBody) {
+		if err := json.Unmarshal(reqBody, &reqs); err != nil {
+			return nil
+		}
+	} else {
+		var req Request
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil
+		}
+
+		reqs = append(reqs, req)
+	}
+
+	methods := make([]string, len(reqs))
+	for i, req := range reqs {
+		methods[i] = req.Method
 	}
+
+	return methods
 }
 
 // wsUpgrader defines upgrade parameters for the WS connection
@@ -161,6 +311,10 @@ var wsUpgrader = websocket.Upgrader{
 	WriteBufferSize: 1024,
 }
 
+// wsWriteTimeout is the time the peer has to read a message written to the web socket connection.
+// The subscriptions of a peer which doesn't keep up are removed once it expires
+const wsWriteTimeout = 10 * time.Second
+
 // wsWrapper is a wrapping object for the web socket connection and logger
 type wsWrapper struct {
 	sync.Mutex
@@ -168,6 +322,12 @@ type wsWrapper struct {
 	ws       *websocket.Conn // the actual WS connection
 	logger   hclog.Logger    // module logger
 	filterID string          // filter ID
+	client   string          // client of the connection, identified by its IP
+}
+
+// Client returns the client of the connection
+func (w *wsWrapper) Client() string {
+	return w.client
 }
 
 func (w *wsWrapper) SetFilterID(filterID string) {
@@ -182,6 +342,11 @@ func (w *wsWrapper) GetFilterID() string {
 func (w *wsWrapper) WriteMessage(messageType int, data []byte) error {
 	w.Lock()
 	defer w.Unlock()
+
+	if err := w.ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+
 	writeErr := w.ws.WriteMessage(messageType, data)
 
 	if writeErr != nil {
@@ -200,11 +365,23 @@ func isSupportedWSType(messageType int) bool {
 }
 
 func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
-	// CORS rule - Allow requests from anywhere
-	wsUpgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	upgrader := wsUpgrader
+
+	// CORS rule - Allow requests from the allowed origins, and from the clients which aren't browsers
+	upgrader.CheckOrigin = func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		policy, _ := j.getPolicy()
+		_, ok := policy.allowsOrigin(origin)
+
+		return ok
+	}
 
 	// Upgrade the connection to a WS one
-	ws, err := wsUpgrader.Upgrade(w, req, nil)
+	ws, err := upgrader.Upgrade(w, req, nil)
 	if err != nil {
 		j.logger.Error(fmt.Sprintf("Unable to upgrade to a WS connection, %s", err.Error()))
 
@@ -221,7 +398,7 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 		}
 	}(ws)
 
-	wrapConn := &wsWrapper{ws: ws, logger: j.logger}
+	wrapConn := &wsWrapper{ws: ws, logger: j.logger, client: clientFromAddr(req.RemoteAddr)}
 
 	j.logger.Info("Websocket connection established")
 	// Run the listen loop
@@ -248,6 +425,16 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 
 		if isSupportedWSType(msgType) {
 			go func() {
+				release, limitErr := j.acquireLimits(req.RemoteAddr, message)
+				if limitErr != nil {
+					resp, _ := NewRPCResponse(nil, "2.0", nil, limitErr).Bytes()
+					_ = wrapConn.WriteMessage(msgType, resp)
+
+					return
+				}
+
+				defer release()
+
 				resp, handleErr := j.dispatcher.HandleWs(message, wrapConn)
 				if handleErr != nil {
 					j.logger.Error(fmt.Sprintf("Unable to handle WS request, %s", handleErr.Error()))
@@ -295,7 +482,19 @@ func (j *JSONRPC) handleJSONRPCRequest(w http.ResponseWriter, req *http.Request)
 	// log request
 	j.logger.Debug("handle", "request", string(data))
 
-	resp, err := j.dispatcher.Handle(data)
+	release, limitErr := j.acquireLimits(req.RemoteAddr, data)
+	if limitErr != nil {
+		resp, _ := NewRPCResponse(nil, "2.0", nil, limitErr).Bytes()
+
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write(resp)
+
+		return
+	}
+
+	defer release()
+
+	resp, err := j.dispatcher.HandleFrom(clientFromAddr(req.RemoteAddr), data)
 
 	if err != nil {
 		_, _ = w.Write([]byte(err.Error()))
@@ -7,6 +7,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/state"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -53,6 +54,13 @@ type mockStore struct {
 	receiptsLock sync.Mutex
 	receipts     map[types.Hash][]*types.Receipt
 	accounts     map[types.Address]*state.Account
+
+	storageChanges map[types.Hash][]*state.StorageChange
+	accountChanges map[types.Hash][]*state.AccountChange
+
+	pendingTxsLock sync.Mutex
+	pendingTxs     map[types.Hash]*types.Transaction
+	txEventCh      chan *txpoolProto.TxPoolEvent
 }
 
 func newMockStore() *mockStore {
@@ -60,9 +68,35 @@ func newMockStore() *mockStore {
 		header:       &types.Header{Number: 0},
 		subscription: blockchain.NewMockSubscription(),
 		accounts:     map[types.Address]*state.Account{},
+		pendingTxs:   map[types.Hash]*types.Transaction{},
+		txEventCh:    make(chan *txpoolProto.TxPoolEvent),
 	}
 }
 
+func (m *mockStore) emitPendingTx(tx *types.Transaction) {
+	m.pendingTxsLock.Lock()
+	m.pendingTxs[tx.Hash] = tx
+	m.pendingTxsLock.Unlock()
+
+	m.txEventCh <- &txpoolProto.TxPoolEvent{
+		Type:   txpoolProto.EventType_PROMOTED,
+		TxHash: tx.Hash.String(),
+	}
+}
+
+func (m *mockStore) SubscribeTxEvents(eventTypes ...txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	return m.txEventCh, func() {}
+}
+
+func (m *mockStore) GetPendingTx(txHash types.Hash) (*types.Transaction, bool) {
+	m.pendingTxsLock.Lock()
+	defer m.pendingTxsLock.Unlock()
+
+	tx, ok := m.pendingTxs[txHash]
+
+	return tx, ok
+}
+
 func (m *mockStore) emitEvent(evnt *mockEvent) {
 	if m.receipts == nil {
 		m.receipts = map[types.Hash][]*types.Receipt{}
@@ -115,6 +149,50 @@ func (m *mockStore) SubscribeEvents() blockchain.Subscription {
 	return m.subscription
 }
 
+func (m *mockStore) GetBloomMatches(from, to uint64, filter [][][]byte) ([]uint64, uint64) {
+	return nil, from
+}
+
+func (m *mockStore) GetStorageChanges(
+	header *types.Header,
+	watches state.StorageWatches,
+) ([]*state.StorageChange, error) {
+	m.receiptsLock.Lock()
+	defer m.receiptsLock.Unlock()
+
+	changes := make([]*state.StorageChange, 0)
+
+	for _, change := range m.storageChanges[header.Hash] {
+		for _, slot := range watches[change.Address] {
+			if slot == change.Slot {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func (m *mockStore) GetAccountChanges(
+	header *types.Header,
+	addrs []types.Address,
+) ([]*state.AccountChange, error) {
+	m.receiptsLock.Lock()
+	defer m.receiptsLock.Unlock()
+
+	changes := make([]*state.AccountChange, 0)
+
+	for _, change := range m.accountChanges[header.Hash] {
+		for _, addr := range addrs {
+			if addr == change.Address {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	return changes, nil
+}
+
 func (m *mockStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
 	return nil, false
 }
@@ -123,6 +201,14 @@ func (m *mockStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
 	return nil, false
 }
 
+func (m *mockStore) GetTxCountByHash(hash types.Hash) (int, bool) {
+	return 0, false
+}
+
+func (m *mockStore) IterateHeaders(from, to uint64, handler func(*types.Header) bool) error {
+	return nil
+}
+
 func (m *mockStore) GetTxs(inclQueued bool) (
 	map[types.Address][]*types.Transaction,
 	map[types.Address][]*types.Transaction,
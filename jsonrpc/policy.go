@@ -0,0 +1,220 @@
+package jsonrpc
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTimeout is the time after which the rate limits of an inactive client are dropped
+const limiterIdleTimeout = 10 * time.Minute
+
+// Policy limits the access to the JSON-RPC server, it can be replaced while the server is running
+type Policy struct {
+	// AccessControlAllowOrigin lists the origins the responses can be shared with (CORS), "*" allows any origin
+	AccessControlAllowOrigin []string
+
+	// VirtualHosts lists the accepted values of the Host header, "*" accepts any host
+	VirtualHosts []string
+
+	// RateLimit is the number of requests per second accepted from a single IP, 0 disables the limit
+	RateLimit uint64
+
+	// MethodRateLimits is the number of requests of the method per second accepted from a single IP
+	MethodRateLimits map[string]uint64
+
+	// MaxConcurrentRequests is the number of requests of the method handled at the same time, from all the clients
+	MaxConcurrentRequests map[string]uint64
+}
+
+// DefaultPolicy returns the policy accepting any request
+func DefaultPolicy() *Policy {
+	return &Policy{
+		AccessControlAllowOrigin: []string{"*"},
+		VirtualHosts:             []string{"*"},
+	}
+}
+
+// allowsOrigin checks if the responses can be shared with the origin, and returns the matching CORS header value
+func (p *Policy) allowsOrigin(origin string) (string, bool) {
+	for _, allowedOrigin := range p.AccessControlAllowOrigin {
+		if allowedOrigin == "*" {
+			return "*", true
+		}
+
+		if allowedOrigin == origin {
+			return origin, true
+		}
+	}
+
+	return "", false
+}
+
+// allowsHost checks if the Host header is one of the virtual hosts
+func (p *Policy) allowsHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	for _, vhost := range p.VirtualHosts {
+		if vhost == "*" || strings.EqualFold(vhost, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientLimiter holds the rate limits of a single client
+type clientLimiter struct {
+	all      *rate.Limiter
+	methods  map[string]*rate.Limiter
+	lastSeen time.Time
+}
+
+// requestLimiter enforces the rate and concurrency limits of a policy
+type requestLimiter struct {
+	policy *Policy
+
+	lock      sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+
+	// concurrent holds a semaphore for every method with a concurrency limit
+	concurrent map[string]chan struct{}
+}
+
+func newRequestLimiter(policy *Policy) *requestLimiter {
+	l := &requestLimiter{
+		policy:     policy,
+		clients:    make(map[string]*clientLimiter),
+		lastSweep:  time.Now(),
+		concurrent: make(map[string]chan struct{}, len(policy.MaxConcurrentRequests)),
+	}
+
+	for method, limit := range policy.MaxConcurrentRequests {
+		if limit > 0 {
+			l.concurrent[method] = make(chan struct{}, limit)
+		}
+	}
+
+	return l
+}
+
+// acquire checks the requests of the client with the given methods are within the limits.
+// The returned release function must be called once the requests are handled
+func (l *requestLimiter) acquire(client string, methods []string) (func(), Error) {
+	if err := l.allowRate(client, methods); err != nil {
+		return nil, err
+	}
+
+	acquired := make([]chan struct{}, 0, len(methods))
+	release := func() {
+		for _, sem := range acquired {
+			<-sem
+		}
+	}
+
+	for _, method := range methods {
+		sem, ok := l.concurrent[method]
+		if !ok {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+			acquired = append(acquired, sem)
+		default:
+			release()
+			limitedRequest(method, "concurrency")
+
+			return nil, NewLimitExceededError("too many concurrent " + method + " requests")
+		}
+	}
+
+	return release, nil
+}
+
+// allowRate checks the requests of the client are within its rate limits
+func (l *requestLimiter) allowRate(client string, methods []string) Error {
+	if l.policy.RateLimit == 0 && len(l.policy.MethodRateLimits) == 0 {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	c, ok := l.clients[client]
+	if !ok {
+		c = &clientLimiter{methods: make(map[string]*rate.Limiter)}
+
+		if l.policy.RateLimit > 0 {
+			c.all = rate.NewLimiter(rate.Limit(l.policy.RateLimit), int(l.policy.RateLimit))
+		}
+
+		l.clients[client] = c
+	}
+
+	c.lastSeen = now
+
+	if c.all != nil && !c.all.AllowN(now, len(methods)) {
+		limitedRequest("all", "rate")
+
+		return NewLimitExceededError("request rate limit exceeded")
+	}
+
+	for _, method := range methods {
+		limit, ok := l.policy.MethodRateLimits[method]
+		if !ok || limit == 0 {
+			continue
+		}
+
+		limiter, ok := c.methods[method]
+		if !ok {
+			limiter = rate.NewLimiter(rate.Limit(limit), int(limit))
+			c.methods[method] = limiter
+		}
+
+		if !limiter.AllowN(now, 1) {
+			limitedRequest(method, "rate")
+
+			return NewLimitExceededError(method + " rate limit exceeded")
+		}
+	}
+
+	return nil
+}
+
+// sweep drops the rate limits of the inactive clients, at most once per idle timeout
+func (l *requestLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterIdleTimeout {
+		return
+	}
+
+	for client, c := range l.clients {
+		if now.Sub(c.lastSeen) >= limiterIdleTimeout {
+			delete(l.clients, client)
+		}
+	}
+
+	l.lastSweep = now
+}
+
+// limitedRequest counts the requests rejected because of the limits
+func limitedRequest(method, limit string) {
+	metrics.IncrCounterWithLabels(
+		[]string{"jsonrpc", "limited_requests"},
+		1,
+		[]metrics.Label{
+			{Name: "method", Value: method},
+			{Name: "limit", Value: limit},
+		},
+	)
+}
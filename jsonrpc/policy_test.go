@@ -0,0 +1,121 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPolicyServer(policy *Policy) *JSONRPC {
+	j := &JSONRPC{
+		logger:     hclog.NewNullLogger(),
+		config:     &Config{},
+		dispatcher: newDispatcher(hclog.NewNullLogger(), nil, &dispatcherParams{}),
+	}
+	j.SetPolicy(policy)
+
+	return j
+}
+
+func postRequest(j *JSONRPC, host, remoteAddr, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Host = host
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("Origin", "http://dapp.example")
+
+	rec := httptest.NewRecorder()
+	j.policyMiddleware(http.HandlerFunc(j.handle)).ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestPolicy_VirtualHostsAndCORS(t *testing.T) {
+	t.Parallel()
+
+	j := newTestPolicyServer(&Policy{
+		AccessControlAllowOrigin: []string{"http://dapp.example"},
+		VirtualHosts:             []string{"localhost"},
+	})
+
+	rec := postRequest(j, "localhost:8545", "1.1.1.1:1", `{"id":1,"method":"web3_clientVersion"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "http://dapp.example", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	rec = postRequest(j, "node.example:8545", "1.1.1.1:1", `{"id":1,"method":"web3_clientVersion"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// the policy is replaced while the server is running
+	j.SetPolicy(DefaultPolicy())
+
+	rec = postRequest(j, "node.example:8545", "1.1.1.1:1", `{"id":1,"method":"web3_clientVersion"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestPolicy_RateLimits(t *testing.T) {
+	t.Parallel()
+
+	j := newTestPolicyServer(&Policy{
+		VirtualHosts:     []string{"*"},
+		RateLimit:        3,
+		MethodRateLimits: map[string]uint64{"web3_sha3": 1},
+	})
+
+	limited := func(remoteAddr, body string) bool {
+		rec := postRequest(j, "localhost", remoteAddr, body)
+		if rec.Code != http.StatusTooManyRequests {
+			return false
+		}
+
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, -32005, resp.Error.Code)
+
+		return true
+	}
+
+	sha3 := `{"id":1,"method":"web3_sha3","params":["0x00"]}`
+	version := `{"id":1,"method":"web3_clientVersion"}`
+
+	// the method limit is reached before the limit of the client
+	assert.False(t, limited("1.1.1.1:1", sha3))
+	assert.True(t, limited("1.1.1.1:2", sha3))
+	assert.False(t, limited("1.1.1.1:3", version))
+
+	// the batch entries count towards the limit of the client
+	assert.True(t, limited("1.1.1.1:4", `[`+version+`,`+version+`]`))
+
+	// the limits are per IP
+	assert.False(t, limited("2.2.2.2:1", sha3))
+}
+
+func TestPolicy_MaxConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRequestLimiter(&Policy{
+		MaxConcurrentRequests: map[string]uint64{"eth_getLogs": 1},
+	})
+
+	release, err := limiter.acquire("1.1.1.1", []string{"eth_getLogs"})
+	require.Nil(t, err)
+
+	// another client is limited as well, the other methods are not
+	_, err = limiter.acquire("2.2.2.2", []string{"eth_blockNumber", "eth_getLogs"})
+	assert.NotNil(t, err)
+
+	other, err := limiter.acquire("2.2.2.2", []string{"eth_blockNumber"})
+	require.Nil(t, err)
+	other()
+
+	release()
+
+	release, err = limiter.acquire("2.2.2.2", []string{"eth_getLogs"})
+	require.Nil(t, err)
+	release()
+}
@@ -0,0 +1,25 @@
+package jsonrpc
+
+import (
+	"time"
+)
+
+// ProfilerStore provides access to the methods needed by the profiling methods of the debug endpoint
+type ProfilerStore interface {
+	// CPUProfile records the CPU profile for the duration, and returns the path of the file it is written to
+	CPUProfile(duration time.Duration) (string, error)
+}
+
+// Profiler serves the profiling methods of the debug endpoint, next to its other methods,
+// which are only served if the admin endpoints are enabled
+type Profiler struct {
+	store ProfilerStore
+}
+
+// CpuProfile records the CPU profile of the node for the number of seconds, and returns the path
+// of the file it is written to in the data directory. The call returns once the profile is recorded
+//
+//nolint:stylecheck
+func (p *Profiler) CpuProfile(seconds argQuantity) (interface{}, error) {
+	return p.store.CPUProfile(time.Duration(seconds) * time.Second)
+}
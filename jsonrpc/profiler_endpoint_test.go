@@ -0,0 +1,46 @@
+package jsonrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProfilerStore struct {
+	duration time.Duration
+}
+
+func (m *mockProfilerStore) CPUProfile(duration time.Duration) (string, error) {
+	m.duration = duration
+
+	return "/data/profiles/cpu.pprof", nil
+}
+
+func TestProfilerEndpoint(t *testing.T) {
+	t.Parallel()
+
+	store := &mockProfilerStore{}
+	dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), &dispatcherParams{})
+
+	// the profiling methods are only served once enabled
+	_, _, ferr := dispatcher.getFnHandler(Request{Method: "debug_cpuProfile"})
+	require.Error(t, ferr)
+
+	dispatcher.registerProfilerEndpoint(store)
+
+	res, err := dispatcher.Handle([]byte(`{"method": "debug_cpuProfile", "params": [30]}`))
+	require.NoError(t, err)
+
+	path := ""
+	require.NoError(t, expectJSONResult(res, &path))
+	assert.Equal(t, "/data/profiles/cpu.pprof", path)
+	assert.Equal(t, 30*time.Second, store.duration)
+
+	// the other methods of the debug endpoint are kept
+	service, _, ferr := dispatcher.getFnHandler(Request{Method: "debug_traceTransaction"})
+	require.Nil(t, ferr)
+	assert.Equal(t, dispatcher.endpoints.Debug, service.sv.Interface())
+}
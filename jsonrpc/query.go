@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -213,3 +214,115 @@ func (q *LogQuery) Match(log *types.Log) bool {
 
 	return true
 }
+
+// bloomFilter returns the groups of values which have to be present in the logs bloom of a block
+// for it to possibly contain matching logs. Nil is returned if the query matches any log
+func (q *LogQuery) bloomFilter() [][][]byte {
+	filter := make([][][]byte, 0, len(q.Topics)+1)
+
+	if len(q.Addresses) > 0 {
+		group := make([][]byte, len(q.Addresses))
+		for i, addr := range q.Addresses {
+			group[i] = addr.Bytes()
+		}
+
+		filter = append(filter, group)
+	}
+
+	for _, sub := range q.Topics {
+		if len(sub) == 0 {
+			// any topic matches at this position
+			continue
+		}
+
+		group := make([][]byte, len(sub))
+		for i, topic := range sub {
+			group[i] = topic.Bytes()
+		}
+
+		filter = append(filter, group)
+	}
+
+	if len(filter) == 0 {
+		return nil
+	}
+
+	return filter
+}
+
+// StorageQuery is a query to watch the storage slots of a contract
+type StorageQuery struct {
+	Address types.Address `json:"address"`
+	Slots   []types.Hash  `json:"slots"`
+}
+
+func decodeStorageQueryFromInterface(i interface{}) (*StorageQuery, error) {
+	raw, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &StorageQuery{}
+	if err := json.Unmarshal(raw, &query); err != nil {
+		return nil, err
+	}
+
+	if len(query.Slots) == 0 {
+		return nil, fmt.Errorf("at least one storage slot expected")
+	}
+
+	return query, nil
+}
+
+// Match returns whether the storage change modifies one of the watched slots
+func (q *StorageQuery) Match(change *state.StorageChange) bool {
+	if q.Address != change.Address {
+		return false
+	}
+
+	for _, slot := range q.Slots {
+		if slot == change.Slot {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AccountQuery is a query to watch the balance, nonce and code of accounts
+type AccountQuery struct {
+	Addresses []types.Address
+}
+
+func decodeAccountQueryFromInterface(i interface{}) (*AccountQuery, error) {
+	raw, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &AccountQuery{}
+	if err := json.Unmarshal(raw, &query.Addresses); err != nil {
+		return nil, err
+	}
+
+	if len(query.Addresses) == 0 {
+		return nil, fmt.Errorf("at least one address expected")
+	}
+
+	if len(query.Addresses) > maxWatchedAccounts {
+		return nil, fmt.Errorf("at most %d addresses expected", maxWatchedAccounts)
+	}
+
+	return query, nil
+}
+
+// Match returns whether the account change is of one of the watched accounts
+func (q *AccountQuery) Match(change *state.AccountChange) bool {
+	for _, addr := range q.Addresses {
+		if addr == change.Address {
+			return true
+		}
+	}
+
+	return false
+}
@@ -229,3 +229,38 @@ func TestFilterMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterBloomFilter(t *testing.T) {
+	cases := []struct {
+		query  *LogQuery
+		filter [][][]byte
+	}{
+		{
+			&LogQuery{},
+			nil,
+		},
+		{
+			// wildcard topics are not part of the filter
+			&LogQuery{
+				Topics: [][]types.Hash{{}, {}},
+			},
+			nil,
+		},
+		{
+			&LogQuery{
+				Addresses: []types.Address{addr1, addr2},
+				Topics:    [][]types.Hash{{}, {hash1, hash2}},
+			},
+			[][][]byte{
+				{addr1.Bytes(), addr2.Bytes()},
+				{hash1.Bytes(), hash2.Bytes()},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if filter := c.query.bloomFilter(); !reflect.DeepEqual(filter, c.filter) {
+			t.Fatal("bad")
+		}
+	}
+}
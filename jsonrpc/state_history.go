@@ -0,0 +1,131 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// stateHistory decides which historical states are served by the state queries.
+// Only the states of the last blocks within the horizon are served, along with the
+// pinned ones, the states of the blocks whose number is a multiple of the pin interval
+type stateHistory struct {
+	horizon     uint64 // 0 serves the states of all the blocks
+	pinInterval uint64 // 0 pins no block
+}
+
+// stateAvailabilityStore is implemented by the stores which may miss the state of some blocks,
+// like the blocks preceding the checkpoint of a snap synced node
+type stateAvailabilityStore interface {
+	// HasState returns whether the state with the given root is stored
+	HasState(root types.Hash) bool
+}
+
+// stateNotAvailableError is returned by the state queries at a block whose state isn't served,
+// along with the earliest block from which the states are served
+type stateNotAvailableError struct {
+	number   uint64
+	earliest uint64
+}
+
+func (e *stateNotAvailableError) Error() string {
+	return fmt.Sprintf("state not available for block %d, the earliest available block is %d", e.number, e.earliest)
+}
+
+func (e *stateNotAvailableError) ErrorCode() int {
+	return -32000
+}
+
+func (e *stateNotAvailableError) ErrorData() interface{} {
+	return map[string]argUint64{
+		"requestedBlock": argUint64(e.number),
+		"earliestBlock":  argUint64(e.earliest),
+	}
+}
+
+// isPinned checks whether the state of the block is served regardless of the horizon
+func (h *stateHistory) isPinned(number uint64) bool {
+	return h.pinInterval != 0 && number%h.pinInterval == 0
+}
+
+// earliest returns the earliest block within the horizon
+func (h *stateHistory) earliest(head uint64) uint64 {
+	if h.horizon == 0 || head < h.horizon {
+		return 0
+	}
+
+	return head - h.horizon
+}
+
+// getStateHeader returns the header of the block whose state is queried,
+// if the state of the block is served
+func (e *Eth) getStateHeader(bnh *BlockNumberOrHash) (*types.Header, error) {
+	header, err := e.getHeaderFromBlockNumberOrHash(bnh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number: %w", err)
+	}
+
+	if err := e.checkStateAvailable(header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// checkStateAvailable returns a stateNotAvailableError if the state of the block is out of
+// the history horizon (and not pinned), or if it is missing in the store.
+// The pinned states are served below the horizon, they are retained by the state pruning
+func (e *Eth) checkStateAvailable(header *types.Header) error {
+	head := e.store.Header().Number
+	earliest := e.stateHistory.earliest(head)
+
+	if header.Number < earliest && !e.stateHistory.isPinned(header.Number) {
+		return &stateNotAvailableError{number: header.Number, earliest: earliest}
+	}
+
+	// the pinned states preceding the snap sync checkpoint are missing all the same
+	store, ok := e.store.(stateAvailabilityStore)
+	if !ok || store.HasState(header.StateRoot) {
+		return nil
+	}
+
+	return &stateNotAvailableError{
+		number:   header.Number,
+		earliest: e.earliestStoredState(store, earliest, head),
+	}
+}
+
+// earliestStoredState returns the earliest block, from the given one, whose state is stored.
+// The states are stored from a block on (e.g. the snap sync checkpoint, or the retention of the pruning),
+// so it is searched by bisection. The pinned states are stored apart from them, so they are skipped
+func (e *Eth) earliestStoredState(store stateAvailabilityStore, from, head uint64) uint64 {
+	hasState := func(number uint64) bool {
+		// the block following a pinned one isn't pinned, unless all the states are
+		if e.stateHistory.isPinned(number) && number < head {
+			number++
+		}
+
+		header, ok := e.store.GetHeaderByNumber(number)
+
+		return ok && store.HasState(header.StateRoot)
+	}
+
+	// the genesis state is always written, the missing states follow it
+	if from == 0 {
+		from = 1
+	}
+
+	low, high := from, head
+
+	for low < high {
+		mid := low + (high-low)/2
+
+		if hasState(mid) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return low
+}
@@ -0,0 +1,243 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// stateHistoryMockStore is a chain of 100 blocks which has the states from firstState on,
+// the state root of a block is its number
+type stateHistoryMockStore struct {
+	*mockStore
+
+	firstState uint64
+}
+
+func (s *stateHistoryMockStore) Header() *types.Header {
+	header, _ := s.GetHeaderByNumber(100)
+
+	return header
+}
+
+func (s *stateHistoryMockStore) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number > 100 {
+		return nil, false
+	}
+
+	return &types.Header{
+		Number:    number,
+		StateRoot: types.BytesToHash(new(big.Int).SetUint64(number).Bytes()),
+	}, true
+}
+
+func (s *stateHistoryMockStore) HasState(root types.Hash) bool {
+	number := new(big.Int).SetBytes(root.Bytes()).Uint64()
+
+	return number == 0 || number >= s.firstState
+}
+
+func (s *stateHistoryMockStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	return nil, ErrStateNotFound
+}
+
+func TestEth_CheckStateAvailable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		history    stateHistory
+		firstState uint64
+		number     uint64
+		earliest   uint64 // 0 if the state is available
+	}{
+		{
+			name:   "all the states",
+			number: 1,
+		},
+		{
+			name:    "within the horizon",
+			history: stateHistory{horizon: 10},
+			number:  90,
+		},
+		{
+			name:     "out of the horizon",
+			history:  stateHistory{horizon: 10},
+			number:   89,
+			earliest: 90,
+		},
+		{
+			name:    "pinned out of the horizon",
+			history: stateHistory{horizon: 10, pinInterval: 8},
+			number:  88,
+		},
+		{
+			name:       "missing state",
+			firstState: 42,
+			number:     41,
+			earliest:   42,
+		},
+		{
+			name:       "stored genesis state",
+			firstState: 42,
+			number:     0,
+		},
+		{
+			name:       "missing state within the horizon",
+			history:    stateHistory{horizon: 80},
+			firstState: 42,
+			number:     30,
+			earliest:   42,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &stateHistoryMockStore{mockStore: newMockStore(), firstState: tt.firstState}
+			eth := &Eth{store: store, stateHistory: tt.history}
+
+			header, _ := store.GetHeaderByNumber(tt.number)
+
+			err := eth.checkStateAvailable(header)
+			if tt.earliest == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var stateErr *stateNotAvailableError
+
+			require.True(t, errors.As(err, &stateErr))
+			assert.Equal(t, tt.number, stateErr.number)
+			assert.Equal(t, tt.earliest, stateErr.earliest)
+		})
+	}
+}
+
+// prunedStateStore is a chain whose states are written to the trie storage, and pruned
+type prunedStateStore struct {
+	*mockStore
+
+	headers []*types.Header
+	state   *itrie.State
+}
+
+func (s *prunedStateStore) Header() *types.Header {
+	return s.headers[len(s.headers)-1]
+}
+
+func (s *prunedStateStore) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number >= uint64(len(s.headers)) {
+		return nil, false
+	}
+
+	return s.headers[number], true
+}
+
+func (s *prunedStateStore) HasState(root types.Hash) bool {
+	_, err := s.state.NewSnapshotAt(root)
+
+	return err == nil
+}
+
+func TestEth_CheckStateAvailable_Pruned(t *testing.T) {
+	t.Parallel()
+
+	storage, err := itrie.NewLevelDBStorage(t.TempDir(), hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = storage.Close()
+	})
+
+	pinInterval := uint64(10)
+
+	pruner, err := itrie.NewPruner(hclog.NewNullLogger(), storage, itrie.MinStateRetention, pinInterval)
+	require.NoError(t, err)
+
+	var (
+		st    = itrie.NewState(pruner.Storage())
+		store = &prunedStateStore{mockStore: newMockStore(), state: itrie.NewState(storage)}
+		root  = types.EmptyRootHash
+		addr  = types.StringToAddress("1")
+	)
+
+	// every block changes the balance of the account
+	for number := uint64(0); number < 2*itrie.MinStateRetention; number++ {
+		snap, err := st.NewSnapshotAt(root)
+		require.NoError(t, err)
+
+		txn := state.NewTxn(st, snap)
+		txn.SetBalance(addr, new(big.Int).SetUint64(number+1))
+
+		_, rootBytes := snap.Commit(txn.Commit(false))
+		root = types.BytesToHash(rootBytes)
+
+		store.headers = append(store.headers, &types.Header{Number: number, StateRoot: root})
+	}
+
+	require.NoError(t, pruner.Prune(store))
+
+	eth := &Eth{store: store, stateHistory: stateHistory{horizon: 10, pinInterval: pinInterval}}
+
+	// the pinned state is served below the horizon
+	assert.NoError(t, eth.checkStateAvailable(store.headers[50]))
+
+	// the earliest state retained by the pruning is found past the pinned ones
+	eth.stateHistory.horizon = 0
+
+	var stateErr *stateNotAvailableError
+
+	require.True(t, errors.As(eth.checkStateAvailable(store.headers[51]), &stateErr))
+	assert.Equal(t, uint64(itrie.MinStateRetention), stateErr.earliest)
+
+	assert.NoError(t, eth.checkStateAvailable(store.headers[itrie.MinStateRetention]))
+}
+
+func TestDispatcher_StateNotAvailable(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := newDispatcher(
+		hclog.NewNullLogger(),
+		&stateHistoryMockStore{mockStore: newMockStore(), firstState: 42},
+		&dispatcherParams{stateHistory: 80},
+	)
+
+	resp, err := dispatcher.Handle([]byte(`{
+		"method": "eth_getBalance",
+		"params": ["0x0000000000000000000000000000000000000001", "0x1e"]
+	}`))
+	require.NoError(t, err)
+
+	var res ErrorResponse
+
+	require.NoError(t, json.Unmarshal(resp, &res))
+	require.NotNil(t, res.Error)
+
+	assert.Equal(t, -32000, res.Error.Code)
+	assert.Equal(t, map[string]interface{}{
+		"requestedBlock": "0x1e",
+		"earliestBlock":  "0x2a",
+	}, res.Error.Data)
+
+	// the state within the horizon is served
+	resp, err = dispatcher.Handle([]byte(`{
+		"method": "eth_getBalance",
+		"params": ["0x0000000000000000000000000000000000000001", "0x2a"]
+	}`))
+	require.NoError(t, err)
+	assert.Contains(t, string(resp), `"result":"0x0"`)
+}
@@ -0,0 +1,305 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// traceCallType is the only trace type of trace_call, the vmTrace and stateDiff ones are not supported
+const traceCallType = "trace"
+
+var (
+	ErrTraceTypeNotSupported = errors.New("only the trace type is supported")
+)
+
+// traceStore provides access to the methods needed by trace endpoint
+type traceStore interface {
+	debugStore
+
+	// TraceTxn applies a transaction object to the blockchain, tracing its execution with the tracer
+	TraceTxn(header *types.Header, txn *types.Transaction, tracer runtime.Tracer) (*runtime.ExecutionResult, error)
+}
+
+// Trace is the trace jsonrpc endpoint, serving the (OpenEthereum/Parity style) flat call traces
+type Trace struct {
+	store traceStore
+
+	// eth decodes the calls and resolves the block they are traced on
+	eth *Eth
+
+	blockRangeLimit uint64
+}
+
+// blockTrace is a flat trace of a mined transaction
+type blockTrace struct {
+	*tracer.FlatTrace
+
+	BlockHash           types.Hash `json:"blockHash"`
+	BlockNumber         uint64     `json:"blockNumber"`
+	TransactionHash     types.Hash `json:"transactionHash"`
+	TransactionPosition int        `json:"transactionPosition"`
+}
+
+// traceFilter selects the traces of trace_filter, by the sender and the recipient of the calls
+type traceFilter struct {
+	FromBlock   *BlockNumber    `json:"fromBlock"`
+	ToBlock     *BlockNumber    `json:"toBlock"`
+	FromAddress []types.Address `json:"fromAddress"`
+	ToAddress   []types.Address `json:"toAddress"`
+	After       *argUint64      `json:"after"`
+	Count       *argUint64      `json:"count"`
+}
+
+// callTraceResult is the result of trace_call
+type callTraceResult struct {
+	Output    argBytes            `json:"output"`
+	StateDiff interface{}         `json:"stateDiff"`
+	Trace     []*tracer.FlatTrace `json:"trace"`
+	VMTrace   interface{}         `json:"vmTrace"`
+}
+
+// Block returns the traces of all the transactions in the block
+func (t *Trace) Block(number BlockNumber) (interface{}, error) {
+	num, err := GetNumericBlockNumber(number, t.eth)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := t.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	return t.traceBlock(block)
+}
+
+// Transaction returns the traces of the transaction with the given hash
+func (t *Trace) Transaction(hash types.Hash) (interface{}, error) {
+	blockHash, ok := t.store.ReadTxLookup(hash)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", hash)
+	}
+
+	block, ok := t.store.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", blockHash)
+	}
+
+	for idx, tx := range block.Transactions {
+		if tx.Hash != hash {
+			continue
+		}
+
+		// the transactions following the traced one don't have to be executed
+		traces, err := t.traceBlock(&types.Block{
+			Header:       block.Header,
+			Transactions: block.Transactions[:idx+1],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		res := make([]*blockTrace, 0)
+
+		for _, trace := range traces {
+			if trace.TransactionPosition == idx {
+				res = append(res, trace)
+			}
+		}
+
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("transaction %s not found in block %s", hash, blockHash)
+}
+
+// Filter returns the traces of the blocks in the range matching the filter
+func (t *Trace) Filter(filter traceFilter) (interface{}, error) {
+	from, to := uint64(0), t.store.Header().Number
+
+	var err error
+
+	if filter.FromBlock != nil {
+		if from, err = GetNumericBlockNumber(*filter.FromBlock, t.eth); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.ToBlock != nil {
+		if to, err = GetNumericBlockNumber(*filter.ToBlock, t.eth); err != nil {
+			return nil, err
+		}
+	}
+
+	if to < from {
+		return nil, ErrIncorrectBlockRange
+	}
+
+	// if not disabled, avoid handling large block ranges
+	if t.blockRangeLimit != 0 && to-from > t.blockRangeLimit {
+		return nil, ErrBlockRangeTooHigh
+	}
+
+	var (
+		res     = make([]*blockTrace, 0)
+		skipped uint64
+	)
+
+	for num := from; num <= to; num++ {
+		block, ok := t.store.GetBlockByNumber(num, true)
+		if !ok {
+			return nil, fmt.Errorf("block %d not found", num)
+		}
+
+		traces, err := t.traceBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, trace := range traces {
+			if !filter.matches(trace.FlatTrace) {
+				continue
+			}
+
+			if filter.After != nil && skipped < uint64(*filter.After) {
+				skipped++
+
+				continue
+			}
+
+			res = append(res, trace)
+
+			if filter.Count != nil && uint64(len(res)) >= uint64(*filter.Count) {
+				return res, nil
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Call returns the traces of the call executed on top of the state of the block
+func (t *Trace) Call(arg *txnArgs, traceTypes []string, filter BlockNumberOrHash) (interface{}, error) {
+	for _, traceType := range traceTypes {
+		if traceType != traceCallType {
+			return nil, fmt.Errorf("%w: %s", ErrTraceTypeNotSupported, traceType)
+		}
+	}
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	header, err := t.eth.getStateHeader(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := t.eth.decodeTxn(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Gas == 0 {
+		transaction.Gas = header.GasLimit
+	}
+
+	callTracer := tracer.NewFlatCallTracer()
+
+	result, err := t.store.TraceTxn(header, transaction, callTracer)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &callTraceResult{
+		Output: result.ReturnValue,
+		Trace:  []*tracer.FlatTrace{},
+	}
+
+	if len(traceTypes) > 0 {
+		if res.Trace, err = callTracer.FlatTraces(); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// traceBlock re-executes the block and returns the traces of all its transactions
+func (t *Trace) traceBlock(block *types.Block) ([]*blockTrace, error) {
+	res := make([]*blockTrace, 0)
+
+	// the genesis and the empty blocks have nothing to trace
+	if len(block.Transactions) == 0 {
+		return res, nil
+	}
+
+	tracers := make([]*tracer.FlatCallTracer, len(block.Transactions))
+
+	if err := t.store.TraceBlock(block, func(idx int, _ *types.Transaction) runtime.Tracer {
+		tracers[idx] = tracer.NewFlatCallTracer()
+
+		return tracers[idx]
+	}); err != nil {
+		return nil, err
+	}
+
+	for idx, tx := range block.Transactions {
+		if tracers[idx] == nil {
+			return nil, fmt.Errorf("transaction %s was not executed", tx.Hash)
+		}
+
+		traces, err := tracers[idx].FlatTraces()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, trace := range traces {
+			res = append(res, &blockTrace{
+				FlatTrace:           trace,
+				BlockHash:           block.Hash(),
+				BlockNumber:         block.Number(),
+				TransactionHash:     tx.Hash,
+				TransactionPosition: idx,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// matches checks whether the sender and the recipient of the traced call are selected by the filter
+func (f *traceFilter) matches(trace *tracer.FlatTrace) bool {
+	if len(f.FromAddress) > 0 && !containsAddress(f.FromAddress, trace.Action.From) {
+		return false
+	}
+
+	if len(f.ToAddress) > 0 {
+		// the recipient of a contract creation is the created contract
+		to := trace.Action.To
+		if to == nil && trace.Result != nil {
+			to = trace.Result.Address
+		}
+
+		if to == nil || !containsAddress(f.ToAddress, *to) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsAddress(addresses []types.Address, addr types.Address) bool {
+	for _, a := range addresses {
+		if a == addr {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,239 @@
+package jsonrpc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+type traceEndpointMockStore struct {
+	*debugEndpointMockStore
+}
+
+func (s *traceEndpointMockStore) TraceTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	tracer.TxStart(nil, txn)
+	tracer.CallStart(1, runtime.Call, txn.From, *txn.To, txn.Input, txn.Gas, txn.Value)
+	tracer.CallStart(2, runtime.StaticCall, *txn.To, addr1, nil, 1000, nil)
+	tracer.CallEnd(2, []byte{0x2}, 100, nil)
+	tracer.CallEnd(1, []byte{0x1}, 500, nil)
+
+	result := &runtime.ExecutionResult{ReturnValue: []byte{0x1}, GasUsed: 21500}
+	tracer.TxEnd(result)
+
+	return result, nil
+}
+
+// traceEthMockStore is the store of the eth endpoint decoding the traced calls
+type traceEthMockStore struct {
+	ethStore
+
+	header *types.Header
+}
+
+func (s *traceEthMockStore) Header() *types.Header {
+	return s.header
+}
+
+func newTraceEndpoint() (*Trace, *traceEndpointMockStore) {
+	store := &traceEndpointMockStore{newDebugEndpointMockStore()}
+	eth := &Eth{store: &traceEthMockStore{header: store.header}}
+
+	return &Trace{store: store, eth: eth}, store
+}
+
+func TestTrace_Block(t *testing.T) {
+	t.Parallel()
+
+	trace, _ := newTraceEndpoint()
+
+	res, err := trace.Block(LatestBlockNumber)
+	require.NoError(t, err)
+
+	traces, ok := res.([]*blockTrace)
+	require.True(t, ok)
+	require.Len(t, traces, 2)
+
+	for idx, tx := range []types.Hash{hash2, hash3} {
+		assert.Equal(t, tx, traces[idx].TransactionHash)
+		assert.Equal(t, idx, traces[idx].TransactionPosition)
+		assert.Equal(t, hash1, traces[idx].BlockHash)
+		assert.Equal(t, uint64(1), traces[idx].BlockNumber)
+		assert.Equal(t, "call", traces[idx].Type)
+		assert.Equal(t, &addr0, traces[idx].Action.To)
+		assert.Equal(t, []int{}, traces[idx].TraceAddress)
+	}
+
+	// the genesis has nothing to trace
+	res, err = trace.Block(EarliestBlockNumber)
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	_, err = trace.Block(BlockNumber(5))
+	assert.Error(t, err)
+}
+
+func TestTrace_Transaction(t *testing.T) {
+	t.Parallel()
+
+	trace, store := newTraceEndpoint()
+
+	res, err := trace.Transaction(hash3)
+	require.NoError(t, err)
+
+	traces, ok := res.([]*blockTrace)
+	require.True(t, ok)
+	require.Len(t, traces, 1)
+	assert.Equal(t, hash3, traces[0].TransactionHash)
+	assert.Equal(t, 1, traces[0].TransactionPosition)
+
+	// the transactions following the traced one are not executed
+	res, err = trace.Transaction(hash2)
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, 1, store.traced)
+
+	_, err = trace.Transaction(types.StringToHash("unknown"))
+	assert.Error(t, err)
+}
+
+func TestTrace_Filter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		latest   = LatestBlockNumber
+		earliest = EarliestBlockNumber
+		one      = argUint64(1)
+	)
+
+	tests := []struct {
+		name     string
+		filter   traceFilter
+		expected []types.Hash
+		err      error
+	}{
+		{
+			name:     "whole chain",
+			filter:   traceFilter{},
+			expected: []types.Hash{hash2, hash3},
+		},
+		{
+			name:     "recipient",
+			filter:   traceFilter{FromBlock: &earliest, ToBlock: &latest, ToAddress: []types.Address{addr0}},
+			expected: []types.Hash{hash2, hash3},
+		},
+		{
+			name:     "other sender",
+			filter:   traceFilter{FromAddress: []types.Address{addr1}},
+			expected: []types.Hash{},
+		},
+		{
+			name:     "after",
+			filter:   traceFilter{After: &one},
+			expected: []types.Hash{hash3},
+		},
+		{
+			name:     "count",
+			filter:   traceFilter{Count: &one},
+			expected: []types.Hash{hash2},
+		},
+		{
+			name:   "reversed range",
+			filter: traceFilter{FromBlock: &latest, ToBlock: &earliest},
+			err:    ErrIncorrectBlockRange,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trace, _ := newTraceEndpoint()
+
+			res, err := trace.Filter(tt.filter)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			traces, ok := res.([]*blockTrace)
+			require.True(t, ok)
+
+			hashes := make([]types.Hash, len(traces))
+			for idx, trace := range traces {
+				hashes[idx] = trace.TransactionHash
+			}
+
+			assert.Equal(t, tt.expected, hashes)
+		})
+	}
+
+	t.Run("block range limit", func(t *testing.T) {
+		t.Parallel()
+
+		trace, _ := newTraceEndpoint()
+		trace.blockRangeLimit = 0
+
+		_, err := trace.Filter(traceFilter{})
+		assert.NoError(t, err)
+
+		trace.blockRangeLimit = 1
+		trace.store.(*traceEndpointMockStore).header = &types.Header{Number: 5}
+
+		_, err = trace.Filter(traceFilter{})
+		assert.True(t, errors.Is(err, ErrBlockRangeTooHigh))
+	})
+}
+
+func TestTrace_Call(t *testing.T) {
+	t.Parallel()
+
+	trace, _ := newTraceEndpoint()
+
+	arg := func() *txnArgs {
+		return &txnArgs{
+			From:  &addr1,
+			To:    &addr0,
+			Nonce: argUintPtr(0),
+			Value: argBytesPtr(big.NewInt(10).Bytes()),
+		}
+	}
+
+	_, err := trace.Call(arg(), []string{"vmTrace"}, BlockNumberOrHash{})
+	assert.True(t, errors.Is(err, ErrTraceTypeNotSupported))
+
+	res, err := trace.Call(arg(), []string{"trace"}, BlockNumberOrHash{})
+	require.NoError(t, err)
+
+	result, ok := res.(*callTraceResult)
+	require.True(t, ok)
+
+	assert.Equal(t, argBytes{0x1}, result.Output)
+	require.Len(t, result.Trace, 2)
+	assert.Equal(t, 1, result.Trace[0].Subtraces)
+	assert.Equal(t, "0xa", result.Trace[0].Action.Value)
+	assert.Equal(t, "staticcall", result.Trace[1].Action.CallType)
+	assert.Equal(t, []int{0}, result.Trace[1].TraceAddress)
+
+	// without trace types only the output is returned
+	res, err = trace.Call(arg(), []string{}, BlockNumberOrHash{})
+	require.NoError(t, err)
+
+	result, ok = res.(*callTraceResult)
+	require.True(t, ok)
+	assert.Empty(t, result.Trace)
+}
@@ -0,0 +1,181 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	sendRawTransactionMethod = "eth_sendRawTransaction"
+
+	// the names of the limits
+	senderLimit = "sender"
+	ipLimit     = "ip"
+)
+
+// txRateLimitError is returned when a transaction is submitted above the rate limit of its sender or IP,
+// along with the time after which a transaction may be accepted again
+type txRateLimitError struct {
+	limit      string // "sender" or "ip"
+	key        string
+	retryAfter time.Duration
+}
+
+func (e *txRateLimitError) Error() string {
+	return fmt.Sprintf("transaction rate limit exceeded for %s %s", e.limit, e.key)
+}
+
+func (e *txRateLimitError) ErrorCode() int {
+	return -32005
+}
+
+func (e *txRateLimitError) ErrorData() interface{} {
+	return map[string]interface{}{
+		"limit":        e.limit,
+		"retryAfterMs": argUint64(e.retryAfter.Milliseconds()),
+	}
+}
+
+// txLimiterConfig holds the rates of the transactions accepted per second from a single sender and IP,
+// 0 disables the limit. Burst is the number of transactions accepted at once, it defaults to the rate
+type txLimiterConfig struct {
+	senderRate uint64
+	ipRate     uint64
+	burst      uint64
+}
+
+// idleLimiter is a rate limiter dropped once it is inactive for the idle timeout
+type idleLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// txLimiter limits the transactions submitted to the pool through the JSON-RPC by every sender and IP,
+// so the scripted floods are rejected before the pool spends any time validating them.
+// Its rates can be changed at runtime
+type txLimiter struct {
+	config txLimiterConfig
+
+	lock      sync.Mutex
+	senders   map[string]*idleLimiter
+	ips       map[string]*idleLimiter
+	lastSweep time.Time
+}
+
+// newTxLimiter returns the transaction limiter, which accepts every transaction if both limits are disabled
+func newTxLimiter(config txLimiterConfig) *txLimiter {
+	return &txLimiter{
+		config:    config,
+		senders:   make(map[string]*idleLimiter),
+		ips:       make(map[string]*idleLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// setConfig replaces the rates of the limiter, the limits of the senders and IPs start over
+func (l *txLimiter) setConfig(config txLimiterConfig) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.config = config
+	l.senders = make(map[string]*idleLimiter)
+	l.ips = make(map[string]*idleLimiter)
+}
+
+// limitsSenders checks if the transactions are limited by their sender,
+// so the sender has to be recovered before the transaction reaches the pool
+func (l *txLimiter) limitsSenders() bool {
+	if l == nil {
+		return false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.config.senderRate != 0
+}
+
+// allowIP checks a transaction of the client IP is within its rate limit, the local clients aren't limited
+func (l *txLimiter) allowIP(client string) Error {
+	if l == nil || client == "" {
+		return nil
+	}
+
+	return l.allow(client, ipLimit)
+}
+
+// allowSender checks a transaction of the sender is within its rate limit
+func (l *txLimiter) allowSender(sender types.Address) Error {
+	if l == nil {
+		return nil
+	}
+
+	return l.allow(sender.String(), senderLimit)
+}
+
+// allow takes a token from the limiter of the key, created with the rate of the limit on its first transaction.
+// The rate is read under the lock, as it is replaced when the config is set
+func (l *txLimiter) allow(key string, name string) Error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	limiters, limit := l.senders, l.config.senderRate
+	if name == ipLimit {
+		limiters, limit = l.ips, l.config.ipRate
+	}
+
+	if limit == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	l.sweep(now)
+
+	c, ok := limiters[key]
+	if !ok {
+		burst := l.config.burst
+		if burst == 0 {
+			burst = limit
+		}
+
+		c = &idleLimiter{limiter: rate.NewLimiter(rate.Limit(limit), int(burst))}
+		limiters[key] = c
+	}
+
+	c.lastSeen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		limitedRequest(sendRawTransactionMethod, name+"_rate")
+
+		return &txRateLimitError{limit: name, key: key, retryAfter: delay}
+	}
+
+	return nil
+}
+
+// sweep drops the limiters of the inactive senders and IPs, at most once per idle timeout
+func (l *txLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterIdleTimeout {
+		return
+	}
+
+	sweepIdle(l.senders, now)
+	sweepIdle(l.ips, now)
+
+	l.lastSweep = now
+}
+
+func sweepIdle(limiters map[string]*idleLimiter, now time.Time) {
+	for key, c := range limiters {
+		if now.Sub(c.lastSeen) >= limiterIdleTimeout {
+			delete(limiters, key)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// signedRawTx returns a raw transaction of a new sender
+func signedRawTx(t *testing.T, nonce uint64) (string, types.Address) {
+	t.Helper()
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	tx, err := crypto.NewEIP155Signer(100).SignTx(&types.Transaction{
+		Nonce:    nonce,
+		To:       &addr0,
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+		Value:    big.NewInt(1),
+	}, key)
+	require.NoError(t, err)
+
+	return hex.EncodeToHex(tx.MarshalRLP()), crypto.PubKeyToAddress(&key.PublicKey)
+}
+
+func TestTxLimiter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	limiter := newTxLimiter(txLimiterConfig{})
+	assert.False(t, limiter.limitsSenders())
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.allowIP("1.1.1.1"))
+		assert.NoError(t, limiter.allowSender(addr0))
+	}
+
+	// the endpoints created without a limiter accept every transaction too
+	var none *txLimiter
+
+	assert.False(t, none.limitsSenders())
+	assert.NoError(t, none.allowIP("1.1.1.1"))
+	assert.NoError(t, none.allowSender(addr0))
+}
+
+func TestTxLimiter_SetConfig(t *testing.T) {
+	t.Parallel()
+
+	limiter := newTxLimiter(txLimiterConfig{})
+	assert.NoError(t, limiter.allowSender(addr0))
+
+	limiter.setConfig(txLimiterConfig{senderRate: 1})
+	assert.True(t, limiter.limitsSenders())
+
+	assert.NoError(t, limiter.allowSender(addr0))
+	assert.IsType(t, &txRateLimitError{}, limiter.allowSender(addr0))
+	assert.NoError(t, limiter.allowIP("1.1.1.1"))
+
+	// the limits start over with the new rates
+	limiter.setConfig(txLimiterConfig{senderRate: 1, burst: 2})
+
+	assert.NoError(t, limiter.allowSender(addr0))
+	assert.NoError(t, limiter.allowSender(addr0))
+	assert.IsType(t, &txRateLimitError{}, limiter.allowSender(addr0))
+}
+
+func TestTxLimiter_Burst(t *testing.T) {
+	t.Parallel()
+
+	limiter := newTxLimiter(txLimiterConfig{senderRate: 1, ipRate: 1, burst: 3})
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.allowSender(addr0))
+		assert.NoError(t, limiter.allowIP("1.1.1.1"))
+	}
+
+	err := limiter.allowSender(addr0)
+	require.IsType(t, &txRateLimitError{}, err)
+
+	limitErr, _ := err.(*txRateLimitError) //nolint:errorlint
+	assert.Equal(t, "sender", limitErr.limit)
+	assert.Positive(t, limitErr.retryAfter)
+	assert.Equal(t, -32005, limitErr.ErrorCode())
+
+	assert.IsType(t, &txRateLimitError{}, limiter.allowIP("1.1.1.1"))
+
+	// the other senders and IPs have their own limits, the local clients aren't limited
+	assert.NoError(t, limiter.allowSender(types.StringToAddress("2")))
+	assert.NoError(t, limiter.allowIP("2.2.2.2"))
+	assert.NoError(t, limiter.allowIP(""))
+}
+
+func TestEth_SendRawTransaction_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	store := &mockStoreTxn{}
+	eth := newTestEthEndpoint(store)
+	eth.txLimiter = newTxLimiter(txLimiterConfig{senderRate: 1, ipRate: 2})
+	eth = eth.bindClient("1.1.1.1").(*Eth) //nolint:forcetypeassert
+
+	raw, sender := signedRawTx(t, 0)
+
+	_, err := eth.SendRawTransaction(raw)
+	require.NoError(t, err)
+
+	// the sender is limited, and the rejected transaction doesn't reach the pool
+	store.txn = nil
+
+	_, err = eth.SendRawTransaction(raw)
+	assert.EqualError(t, err, "transaction rate limit exceeded for sender "+sender.String())
+	assert.Nil(t, store.txn)
+
+	// a new sender from the same IP is limited by the IP
+	raw, _ = signedRawTx(t, 0)
+
+	_, err = eth.SendRawTransaction(raw)
+	assert.EqualError(t, err, "transaction rate limit exceeded for ip 1.1.1.1")
+}
+
+func TestDispatcher_TxRateLimitError(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := newDispatcher(hclog.NewNullLogger(), nil, &dispatcherParams{
+		chainID:  100,
+		txLimits: txLimiterConfig{senderRate: 1},
+	})
+	dispatcher.endpoints.Eth.store = &mockStoreTxn{}
+
+	raw, _ := signedRawTx(t, 0)
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["` + raw + `"]}`)
+
+	_, err := dispatcher.HandleFrom("1.1.1.1", body)
+	require.NoError(t, err)
+
+	resp, err := dispatcher.HandleFrom("1.1.1.1", body)
+	require.NoError(t, err)
+
+	var res SuccessResponse
+	require.NoError(t, json.Unmarshal(resp, &res))
+	require.NotNil(t, res.Error)
+
+	assert.Equal(t, -32005, res.Error.Code)
+	assert.Equal(t, "sender", res.Error.Data.(map[string]interface{})["limit"]) //nolint:forcetypeassert
+}
+
+func TestDispatcher_SetTxLimits(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := newDispatcher(hclog.NewNullLogger(), nil, &dispatcherParams{chainID: 100, priceLimit: 1})
+	dispatcher.endpoints.Eth.store = newMockBlockStore()
+
+	gasPrice := func() string {
+		t.Helper()
+
+		res, err := dispatcher.Handle([]byte(`{"method": "eth_gasPrice", "params": []}`))
+		require.NoError(t, err)
+
+		var price string
+		require.NoError(t, expectJSONResult(res, &price))
+
+		return price
+	}
+
+	assert.Equal(t, "0x1", gasPrice())
+	assert.False(t, dispatcher.endpoints.Eth.txLimiter.limitsSenders())
+
+	dispatcher.setTxLimits(1000, txLimiterConfig{senderRate: 1})
+
+	assert.Equal(t, "0x3e8", gasPrice())
+	assert.True(t, dispatcher.endpoints.Eth.txLimiter.limitsSenders())
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -15,6 +16,7 @@ type transactionOrHash interface {
 }
 
 type transaction struct {
+	Type        argUint64      `json:"type"`
 	Nonce       argUint64      `json:"nonce"`
 	GasPrice    argBig         `json:"gasPrice"`
 	Gas         argUint64      `json:"gas"`
@@ -29,6 +31,11 @@ type transaction struct {
 	BlockHash   *types.Hash    `json:"blockHash"`
 	BlockNumber *argUint64     `json:"blockNumber"`
 	TxIndex     *argUint64     `json:"transactionIndex"`
+
+	// typed transaction fields
+	ChainID    *argBig            `json:"chainId,omitempty"`
+	AccessList types.TxAccessList `json:"accessList,omitempty"`
+	Sponsor    *types.Address     `json:"sponsor,omitempty"`
 }
 
 func (t transaction) getHash() types.Hash { return t.Hash }
@@ -53,6 +60,7 @@ func toTransaction(
 	txIndex *int,
 ) *transaction {
 	res := &transaction{
+		Type:     argUint64(t.Type),
 		Nonce:    argUint64(t.Nonce),
 		GasPrice: argBig(*t.GasPrice),
 		Gas:      argUint64(t.Gas),
@@ -78,6 +86,16 @@ func toTransaction(
 		res.TxIndex = argUintPtr(uint64(*txIndex))
 	}
 
+	if t.IsTyped() {
+		res.AccessList = t.AccessList
+		res.Sponsor = t.Sponsor
+
+		if t.ChainID != nil {
+			chainID := argBig(*t.ChainID)
+			res.ChainID = &chainID
+		}
+	}
+
 	return res
 }
 
@@ -156,6 +174,7 @@ func toBlock(b *types.Block, fullTx bool) *block {
 }
 
 type receipt struct {
+	Type              argUint64      `json:"type"`
 	Root              types.Hash     `json:"root"`
 	CumulativeGasUsed argUint64      `json:"cumulativeGasUsed"`
 	LogsBloom         types.Bloom    `json:"logsBloom"`
@@ -169,6 +188,7 @@ type receipt struct {
 	ContractAddress   *types.Address `json:"contractAddress"`
 	FromAddr          types.Address  `json:"from"`
 	ToAddr            *types.Address `json:"to"`
+	Sponsor           *types.Address `json:"sponsor,omitempty"`
 }
 
 type Log struct {
@@ -183,6 +203,27 @@ type Log struct {
 	Removed     bool          `json:"removed"`
 }
 
+type StorageChange struct {
+	Address     types.Address `json:"address"`
+	Slot        types.Hash    `json:"slot"`
+	OldValue    types.Hash    `json:"oldValue"`
+	NewValue    types.Hash    `json:"newValue"`
+	BlockNumber argUint64     `json:"blockNumber"`
+	BlockHash   types.Hash    `json:"blockHash"`
+	TxHash      types.Hash    `json:"transactionHash"`
+	TxIndex     argUint64     `json:"transactionIndex"`
+}
+
+// AccountChange is the balance, nonce and code hash of a watched account changed by a block
+type AccountChange struct {
+	Address     types.Address `json:"address"`
+	Balance     argBig        `json:"balance"`
+	Nonce       argUint64     `json:"nonce"`
+	CodeHash    types.Hash    `json:"codeHash"`
+	BlockNumber argUint64     `json:"blockNumber"`
+	BlockHash   types.Hash    `json:"blockHash"`
+}
+
 type argBig big.Int
 
 func argBigPtr(b *big.Int) *argBig {
@@ -294,14 +335,61 @@ func encodeToHex(b []byte) []byte {
 
 // txnArgs is the transaction argument for the rpc endpoints
 type txnArgs struct {
-	From     *types.Address
-	To       *types.Address
-	Gas      *argUint64
-	GasPrice *argBytes
-	Value    *argBytes
-	Data     *argBytes
-	Input    *argBytes
-	Nonce    *argUint64
+	From       *types.Address
+	To         *types.Address
+	Gas        *argUint64
+	GasPrice   *argBytes
+	Value      *argBytes
+	Data       *argBytes
+	Input      *argBytes
+	Nonce      *argUint64
+	AccessList *types.TxAccessList
+}
+
+// stateOverride is the state of the accounts replaced for the duration of a call
+type stateOverride map[types.Address]overrideAccount
+
+// overrideAccount is the replaced state of an account, State replaces the whole
+// storage of the account while StateDiff only replaces the given slots
+type overrideAccount struct {
+	Nonce     *argUint64
+	Code      *argBytes
+	Balance   *argBig
+	State     map[types.Hash]types.Hash
+	StateDiff map[types.Hash]types.Hash
+}
+
+// toState converts the replaced state of the accounts for the executor
+func (o *stateOverride) toState() state.StateOverride {
+	if o == nil {
+		return nil
+	}
+
+	override := make(state.StateOverride, len(*o))
+
+	for addr, account := range *o {
+		res := state.OverrideAccount{
+			State:     account.State,
+			StateDiff: account.StateDiff,
+		}
+
+		if account.Nonce != nil {
+			nonce := uint64(*account.Nonce)
+			res.Nonce = &nonce
+		}
+
+		if account.Code != nil {
+			res.Code = *account.Code
+		}
+
+		if account.Balance != nil {
+			res.Balance = new(big.Int).Set((*big.Int)(account.Balance))
+		}
+
+		override[addr] = res
+	}
+
+	return override
 }
 
 type progression struct {
@@ -309,4 +397,5 @@ type progression struct {
 	StartingBlock string `json:"startingBlock"`
 	CurrentBlock  string `json:"currentBlock"`
 	HighestBlock  string `json:"highestBlock"`
+	Mode          string `json:"mode,omitempty"`
 }
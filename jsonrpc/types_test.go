@@ -10,6 +10,7 @@ import (
 	"text/template"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state"
 
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
@@ -95,6 +96,42 @@ func TestDecode_TxnArgs(t *testing.T) {
 	}
 }
 
+func TestDecode_StateOverride(t *testing.T) {
+	var (
+		addr  = types.StringToAddress("1")
+		slot  = types.StringToHash("2")
+		value = types.StringToHash("3")
+	)
+
+	data := `{
+		"` + addr.String() + `": {
+			"nonce": "0x5",
+			"balance": "0x64",
+			"code": "0x6001",
+			"stateDiff": {
+				"` + slot.String() + `": "` + value.String() + `"
+			}
+		}
+	}`
+
+	override := &stateOverride{}
+	assert.NoError(t, json.Unmarshal([]byte(data), override))
+
+	nonce := uint64(5)
+
+	assert.Equal(t, state.StateOverride{
+		addr: {
+			Nonce:     &nonce,
+			Code:      []byte{0x60, 0x01},
+			Balance:   big.NewInt(100),
+			StateDiff: map[types.Hash]types.Hash{slot: value},
+		},
+	}, override.toState())
+
+	// no override at all
+	assert.Nil(t, (*stateOverride)(nil).toState())
+}
+
 func TestToTransaction_Returns_V_R_S_ValuesWithoutLeading0(t *testing.T) {
 	hexWithLeading0 := "0x0ba93811466694b3b3cb8853cb8227b7c9f49db10bf6e7db59d20ac904961565"
 	hexWithoutLeading0 := "0xba93811466694b3b3cb8853cb8227b7c9f49db10bf6e7db59d20ac904961565"
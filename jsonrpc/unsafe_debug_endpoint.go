@@ -0,0 +1,156 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// UnsafeDebugStore provides access to the methods needed by the unsafe debug endpoint
+type UnsafeDebugStore interface {
+	// Header returns the current header of the chain (genesis if empty)
+	Header() *types.Header
+
+	// GetHeaderByNumber returns the header by number
+	GetHeaderByNumber(block uint64) (*types.Header, bool)
+
+	// GetCode returns the code with the given hash
+	GetCode(hash types.Hash) ([]byte, error)
+
+	// SetHead rewinds the canonical chain to the block with the given number
+	SetHead(number uint64) error
+
+	// IterateState calls the handler for every hashed key and value of the trie with the given root
+	IterateState(root types.Hash, handler func(key, value []byte) error) error
+
+	// GetDBStats returns the statistics of the databases reporting them, by database name
+	GetDBStats() map[string]string
+}
+
+// UnsafeDebug is the debug jsonrpc endpoint along with the chain surgery methods,
+// which is only served if the unsafe debug endpoints are enabled
+type UnsafeDebug struct {
+	*Debug
+
+	store UnsafeDebugStore
+}
+
+// dumpAccount is an account of the state dump, its storage is keyed by the hashed slots
+type dumpAccount struct {
+	Balance  argBig                    `json:"balance"`
+	Nonce    argUint64                 `json:"nonce"`
+	Root     types.Hash                `json:"root"`
+	CodeHash types.Hash                `json:"codeHash"`
+	Code     argBytes                  `json:"code,omitempty"`
+	Storage  map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+// stateDump is the state at a block. The preimages of the keys are not stored,
+// so the accounts are keyed by the hashed addresses
+type stateDump struct {
+	Root     types.Hash                  `json:"root"`
+	Accounts map[types.Hash]*dumpAccount `json:"accounts"`
+}
+
+// SetHead rewinds the canonical chain to the block with the given number
+func (d *UnsafeDebug) SetHead(number argUint64) (interface{}, error) {
+	if err := d.store.SetHead(uint64(number)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DumpBlock returns all the accounts of the state at the block, along with their code and storage
+func (d *UnsafeDebug) DumpBlock(number BlockNumber) (interface{}, error) {
+	var num uint64
+
+	switch number {
+	case LatestBlockNumber:
+		num = d.store.Header().Number
+	case EarliestBlockNumber:
+		num = 0
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("dumping the pending state is not supported")
+	default:
+		if number < 0 {
+			return nil, fmt.Errorf("invalid argument 0: block number larger than int64")
+		}
+
+		num = uint64(number)
+	}
+
+	header, ok := d.store.GetHeaderByNumber(num)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	dump := &stateDump{
+		Root:     header.StateRoot,
+		Accounts: make(map[types.Hash]*dumpAccount),
+	}
+
+	err := d.store.IterateState(header.StateRoot, func(key, value []byte) error {
+		var account state.Account
+		if err := account.UnmarshalRlp(value); err != nil {
+			return err
+		}
+
+		res := &dumpAccount{
+			Balance:  argBig(*account.Balance),
+			Nonce:    argUint64(account.Nonce),
+			Root:     account.Root,
+			CodeHash: types.BytesToHash(account.CodeHash),
+		}
+
+		if len(account.CodeHash) != 0 && res.CodeHash != emptyCodeHash {
+			code, err := d.store.GetCode(res.CodeHash)
+			if err != nil {
+				return err
+			}
+
+			res.Code = code
+		}
+
+		if account.Root != types.EmptyRootHash && account.Root != types.ZeroHash {
+			res.Storage = make(map[types.Hash]types.Hash)
+
+			p := &fastrlp.Parser{}
+
+			if err := d.store.IterateState(account.Root, func(slot, value []byte) error {
+				v, err := p.Parse(value)
+				if err != nil {
+					return err
+				}
+
+				data, err := v.Bytes()
+				if err != nil {
+					return err
+				}
+
+				res.Storage[types.BytesToHash(slot)] = types.BytesToHash(data)
+
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		dump.Accounts[types.BytesToHash(key)] = res
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dump, nil
+}
+
+// DbStats returns the statistics of the databases
+func (d *UnsafeDebug) DbStats() (interface{}, error) {
+	return d.store.GetDBStats(), nil
+}
@@ -0,0 +1,143 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	unsafeDebugStateRoot   = types.StringToHash("1")
+	unsafeDebugStorageRoot = types.StringToHash("2")
+	unsafeDebugCodeHash    = types.StringToHash("3")
+)
+
+type mockUnsafeDebugStore struct {
+	head uint64
+}
+
+func (m *mockUnsafeDebugStore) Header() *types.Header {
+	return &types.Header{Number: m.head, StateRoot: unsafeDebugStateRoot}
+}
+
+func (m *mockUnsafeDebugStore) GetHeaderByNumber(block uint64) (*types.Header, bool) {
+	if block > m.head {
+		return nil, false
+	}
+
+	return &types.Header{Number: block, StateRoot: unsafeDebugStateRoot}, true
+}
+
+func (m *mockUnsafeDebugStore) GetCode(hash types.Hash) ([]byte, error) {
+	if hash != unsafeDebugCodeHash {
+		return nil, ErrStateNotFound
+	}
+
+	return []byte{0x60, 0x00}, nil
+}
+
+func (m *mockUnsafeDebugStore) SetHead(number uint64) error {
+	if number > m.head {
+		return ErrStateNotFound
+	}
+
+	m.head = number
+
+	return nil
+}
+
+func (m *mockUnsafeDebugStore) IterateState(root types.Hash, handler func(key, value []byte) error) error {
+	ar := &fastrlp.Arena{}
+
+	switch root {
+	case unsafeDebugStateRoot:
+		account := &state.Account{
+			Nonce:    1,
+			Balance:  big.NewInt(10),
+			Root:     unsafeDebugStorageRoot,
+			CodeHash: unsafeDebugCodeHash.Bytes(),
+		}
+
+		return handler(types.StringToHash("a").Bytes(), account.MarshalWith(ar).MarshalTo(nil))
+
+	case unsafeDebugStorageRoot:
+		return handler(types.StringToHash("b").Bytes(), ar.NewBytes([]byte{0x1}).MarshalTo(nil))
+	}
+
+	return nil
+}
+
+func (m *mockUnsafeDebugStore) GetDBStats() map[string]string {
+	return map[string]string{"blockchain": "stats"}
+}
+
+func TestUnsafeDebugEndpoint(t *testing.T) {
+	t.Parallel()
+
+	store := &mockUnsafeDebugStore{head: 10}
+	dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), &dispatcherParams{})
+
+	// the chain surgery methods are only served once enabled
+	res, err := dispatcher.Handle([]byte(`{"method": "debug_setHead", "params": ["0x5"]}`))
+	require.NoError(t, err)
+	assert.Error(t, expectJSONResult(res, &struct{}{}))
+	assert.Equal(t, uint64(10), store.head)
+
+	dispatcher.registerUnsafeDebugEndpoint(store)
+
+	t.Run("setHead", func(t *testing.T) {
+		res, err := dispatcher.Handle([]byte(`{"method": "debug_setHead", "params": ["0x5"]}`))
+		require.NoError(t, err)
+
+		var result interface{}
+		require.NoError(t, expectJSONResult(res, &result))
+		assert.Equal(t, uint64(5), store.head)
+
+		res, err = dispatcher.Handle([]byte(`{"method": "debug_setHead", "params": ["0x6"]}`))
+		require.NoError(t, err)
+		assert.Error(t, expectJSONResult(res, &result))
+	})
+
+	t.Run("dumpBlock", func(t *testing.T) {
+		res, err := dispatcher.Handle([]byte(`{"method": "debug_dumpBlock", "params": ["latest"]}`))
+		require.NoError(t, err)
+
+		dump := &stateDump{}
+		require.NoError(t, expectJSONResult(res, dump))
+
+		assert.Equal(t, unsafeDebugStateRoot, dump.Root)
+		require.Len(t, dump.Accounts, 1)
+
+		account := dump.Accounts[types.StringToHash("a")]
+		require.NotNil(t, account)
+
+		assert.Equal(t, argUint64(1), account.Nonce)
+		assert.Equal(t, big.NewInt(10), (*big.Int)(&account.Balance))
+		assert.Equal(t, unsafeDebugStorageRoot, account.Root)
+		assert.Equal(t, argBytes{0x60, 0x00}, account.Code)
+		assert.Equal(t, map[types.Hash]types.Hash{types.StringToHash("b"): types.StringToHash("1")}, account.Storage)
+
+		res, err = dispatcher.Handle([]byte(`{"method": "debug_dumpBlock", "params": ["0x64"]}`))
+		require.NoError(t, err)
+		assert.Error(t, expectJSONResult(res, dump))
+	})
+
+	t.Run("dbStats", func(t *testing.T) {
+		res, err := dispatcher.Handle([]byte(`{"method": "debug_dbStats", "params": []}`))
+		require.NoError(t, err)
+
+		stats := map[string]string{}
+		require.NoError(t, expectJSONResult(res, &stats))
+		assert.Equal(t, map[string]string{"blockchain": "stats"}, stats)
+	})
+
+	// the debug methods are still served by the embedded endpoint
+	assert.Equal(t, dispatcher.endpoints.Debug, dispatcher.endpoints.UnsafeDebug.Debug)
+}
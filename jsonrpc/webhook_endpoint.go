@@ -0,0 +1,53 @@
+package jsonrpc
+
+import (
+	"github.com/0xPolygon/polygon-edge/webhook"
+)
+
+// WebhookStore provides access to the methods needed for webhook endpoint
+type WebhookStore interface {
+	// Register registers the url to be notified of the logs matching the filter at the confirmation levels
+	Register(url string, filter webhook.Filter, confirmations []uint64) (*webhook.Webhook, error)
+
+	// Unregister stops notifying the webhook
+	Unregister(id string) error
+
+	// List returns the registered webhooks
+	List() []*webhook.Webhook
+}
+
+// Webhook is the webhook jsonrpc endpoint, registering the urls notified of the matching logs
+type Webhook struct {
+	store WebhookStore
+}
+
+// Register registers the url to be notified of the logs matching the address and topics of the filter,
+// once their block reaches each of the confirmation levels. The returned secret signs the notifications
+func (w *Webhook) Register(url string, filter *LogQuery, confirmations []argUint64) (interface{}, error) {
+	hookFilter := webhook.Filter{}
+	if filter != nil {
+		hookFilter.Addresses = filter.Addresses
+		hookFilter.Topics = filter.Topics
+	}
+
+	levels := make([]uint64, len(confirmations))
+	for i, level := range confirmations {
+		levels[i] = uint64(level)
+	}
+
+	return w.store.Register(url, hookFilter, levels)
+}
+
+// Unregister stops notifying the webhook
+func (w *Webhook) Unregister(id string) (interface{}, error) {
+	if err := w.store.Unregister(id); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+// List returns the registered webhooks, without their secrets
+func (w *Webhook) List() (interface{}, error) {
+	return w.store.List(), nil
+}
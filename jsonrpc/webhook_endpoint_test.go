@@ -0,0 +1,89 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/webhook"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWebhookStore struct {
+	registered *webhook.Webhook
+}
+
+func (m *mockWebhookStore) Register(url string, filter webhook.Filter, confirmations []uint64) (*webhook.Webhook, error) {
+	m.registered = &webhook.Webhook{
+		ID:            "id",
+		URL:           url,
+		Secret:        "secret",
+		Filter:        filter,
+		Confirmations: confirmations,
+	}
+
+	return m.registered, nil
+}
+
+func (m *mockWebhookStore) Unregister(id string) error {
+	if m.registered == nil || m.registered.ID != id {
+		return webhook.ErrWebhookNotFound
+	}
+
+	m.registered = nil
+
+	return nil
+}
+
+func (m *mockWebhookStore) List() []*webhook.Webhook {
+	if m.registered == nil {
+		return []*webhook.Webhook{}
+	}
+
+	return []*webhook.Webhook{m.registered}
+}
+
+func TestWebhookEndpoint(t *testing.T) {
+	t.Parallel()
+
+	store := &mockWebhookStore{}
+	dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), &dispatcherParams{})
+
+	// the webhooks are only served once enabled
+	_, err := dispatcher.Handle([]byte(`{"method": "webhook_list", "params": []}`))
+	require.NoError(t, err)
+	assert.Nil(t, dispatcher.endpoints.Webhook)
+
+	dispatcher.registerWebhookEndpoint(store)
+
+	res, err := dispatcher.Handle([]byte(`{
+		"method": "webhook_register",
+		"params": [
+			"http://localhost:8080",
+			{"address": "0x0000000000000000000000000000000000000001", "topics": [null, "0x` + types.ZeroHash.String()[2:] + `"]},
+			["0x0", "0xc"]
+		]
+	}`))
+	require.NoError(t, err)
+
+	hook := &webhook.Webhook{}
+	require.NoError(t, expectJSONResult(res, hook))
+
+	assert.Equal(t, "http://localhost:8080", hook.URL)
+	assert.Equal(t, "secret", hook.Secret)
+	assert.Equal(t, []types.Address{types.StringToAddress("1")}, hook.Filter.Addresses)
+	assert.Equal(t, [][]types.Hash{{}, {types.ZeroHash}}, hook.Filter.Topics)
+	assert.Equal(t, []uint64{0, 12}, hook.Confirmations)
+
+	res, err = dispatcher.Handle([]byte(`{"method": "webhook_unregister", "params": ["id"]}`))
+	require.NoError(t, err)
+
+	removed := false
+	require.NoError(t, expectJSONResult(res, &removed))
+	assert.True(t, removed)
+
+	res, err = dispatcher.Handle([]byte(`{"method": "webhook_unregister", "params": ["id"]}`))
+	require.NoError(t, err)
+	assert.Error(t, expectJSONResult(res, &removed))
+}
@@ -0,0 +1,255 @@
+package kvdb
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+const (
+	// badgerGCInterval is the interval between the garbage collections of the value log
+	badgerGCInterval = 5 * time.Minute
+
+	// badgerGCDiscardRatio is the ratio of discardable data over which a value log file is rewritten
+	badgerGCDiscardRatio = 0.5
+
+	// badgerCompactDiscardRatio is the ratio of discardable data over which a value log file is rewritten
+	// by a manual compaction
+	badgerCompactDiscardRatio = 0.1
+)
+
+// badgerDB is the badger database, the garbage collection of its value log runs in the background
+type badgerDB struct {
+	db *badger.DB
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+func openBadger(path string, options Options) (Database, error) {
+	opts := badger.DefaultOptions(path).WithLogger(nil)
+
+	if options.CacheSize > 0 {
+		opts = opts.WithBlockCacheSize(int64(options.CacheSize))
+	}
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &badgerDB{
+		db:      db,
+		closeCh: make(chan struct{}),
+	}
+
+	b.wg.Add(1)
+
+	go b.runGC()
+
+	return b, nil
+}
+
+func (b *badgerDB) runGC() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(badgerGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		// a garbage collection rewrites at most one file, so it runs until there is nothing to rewrite
+		for {
+			if err := b.db.RunValueLogGC(badgerGCDiscardRatio); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func (b *badgerDB) Get(key []byte) ([]byte, bool, error) {
+	var value []byte
+
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+
+		value, err = item.ValueCopy(nil)
+
+		return err
+	})
+
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (b *badgerDB) Put(key, value []byte) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, value)
+	})
+}
+
+func (b *badgerDB) Delete(key []byte) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
+func (b *badgerDB) NewBatch() Batch {
+	return &badgerBatch{db: b.db}
+}
+
+func (b *badgerDB) NewIterator(prefix, start []byte) Iterator {
+	txn := b.db.NewTransaction(false)
+
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+
+	return &badgerIterator{
+		txn:   txn,
+		iter:  txn.NewIterator(opts),
+		start: iteratorStart(prefix, start),
+	}
+}
+
+// Stats returns the levels of the badger database
+func (b *badgerDB) Stats() (string, error) {
+	return b.db.LevelsToString(), nil
+}
+
+// Compact merges the levels of the badger database, then rewrites the value log files
+// until no file has enough stale values
+func (b *badgerDB) Compact() error {
+	if err := b.db.Flatten(runtime.NumCPU()); err != nil {
+		return err
+	}
+
+	for {
+		if err := b.db.RunValueLogGC(badgerCompactDiscardRatio); err != nil {
+			if errors.Is(err, badger.ErrNoRewrite) {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+func (b *badgerDB) Close() error {
+	close(b.closeCh)
+	b.wg.Wait()
+
+	return b.db.Close()
+}
+
+// badgerBatch keeps the writes until the batch is written with a write batch of badger,
+// which splits the writes over several transactions once they exceed the size of one
+type badgerBatch struct {
+	db     *badger.DB
+	writes []badgerWrite
+}
+
+type badgerWrite struct {
+	key, value []byte
+	delete     bool
+}
+
+func (b *badgerBatch) Put(key, value []byte) {
+	b.writes = append(b.writes, badgerWrite{
+		key:   append([]byte{}, key...),
+		value: append([]byte{}, value...),
+	})
+}
+
+func (b *badgerBatch) Delete(key []byte) {
+	b.writes = append(b.writes, badgerWrite{key: append([]byte{}, key...), delete: true})
+}
+
+func (b *badgerBatch) Len() int {
+	return len(b.writes)
+}
+
+func (b *badgerBatch) Write() error {
+	wb := b.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, write := range b.writes {
+		var err error
+
+		if write.delete {
+			err = wb.Delete(write.key)
+		} else {
+			err = wb.Set(write.key, write.value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return wb.Flush()
+}
+
+// badgerIterator iterates over the keys in a read-only transaction
+type badgerIterator struct {
+	txn   *badger.Txn
+	iter  *badger.Iterator
+	start []byte
+
+	started bool
+	value   []byte
+	err     error
+}
+
+func (i *badgerIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	if !i.started {
+		i.started = true
+		i.iter.Seek(i.start)
+	} else {
+		i.iter.Next()
+	}
+
+	if !i.iter.Valid() {
+		return false
+	}
+
+	i.value, i.err = i.iter.Item().ValueCopy(i.value[:0])
+
+	return i.err == nil
+}
+
+func (i *badgerIterator) Key() []byte {
+	return i.iter.Item().Key()
+}
+
+func (i *badgerIterator) Value() []byte {
+	return i.value
+}
+
+func (i *badgerIterator) Error() error {
+	return i.err
+}
+
+func (i *badgerIterator) Release() {
+	i.iter.Close()
+	i.txn.Discard()
+}
@@ -0,0 +1,201 @@
+package kvdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// LevelDB is the default engine, the databases created before the engines were selectable are leveldb ones
+	LevelDB = "leveldb"
+	Pebble  = "pebble"
+	Badger  = "badger"
+	// Memory keeps the database in memory, it is lost once closed
+	Memory = "memory"
+
+	// engineFile is the file written in the database directory, recording the engine the database was created with
+	engineFile = "ENGINE"
+)
+
+var (
+	ErrUnknownEngine  = errors.New("unknown database engine")
+	ErrEngineMismatch = errors.New("database engine mismatch")
+
+	// ErrStatsNotSupported is returned by the databases whose engine doesn't report statistics
+	ErrStatsNotSupported = errors.New("database statistics not supported")
+
+	// ErrCompactionNotSupported is returned by the databases whose engine can't be compacted on demand
+	ErrCompactionNotSupported = errors.New("database compaction not supported")
+)
+
+// Engines returns the names of the database engines
+func Engines() []string {
+	return []string{LevelDB, Pebble, Badger, Memory}
+}
+
+// Database is an ordered key-value database
+type Database interface {
+	// Get returns the value of the key, false if it isn't set
+	Get(key []byte) ([]byte, bool, error)
+	Put(key, value []byte) error
+	Delete(key []byte) error
+
+	// NewBatch creates a batch of writes, applied atomically once written
+	NewBatch() Batch
+
+	// NewIterator iterates over the keys with the prefix in ascending order, starting from the key
+	// made of the prefix followed by start. The iterator must be released once done with
+	NewIterator(prefix, start []byte) Iterator
+
+	Close() error
+}
+
+// Batch is a batch of writes to the database
+type Batch interface {
+	Put(key, value []byte)
+	Delete(key []byte)
+
+	// Len returns the number of writes in the batch
+	Len() int
+
+	// Write applies the writes to the database, the batch can't be written again
+	Write() error
+}
+
+// Iterator iterates over the keys of the database. The key and value returned by the iterator
+// are only valid until the next call to Next
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// StatsDatabase is implemented by the databases which report the statistics of their engine
+type StatsDatabase interface {
+	Stats() (string, error)
+}
+
+// CompactDatabase is implemented by the databases which can be compacted on demand
+type CompactDatabase interface {
+	// Compact compacts the whole key range, dropping the deleted and overwritten values from the disk
+	Compact() error
+}
+
+// Options are the options of the database engines
+type Options struct {
+	// CacheSize is the size in bytes of the cache of the blocks read from the disk, the engine default if 0
+	CacheSize int
+}
+
+// Open opens the database of the engine at the path, creating it if it doesn't exist.
+// The engine of an existing database must be the one it was created with
+func Open(engine, path string, options Options) (Database, error) {
+	if engine == Memory {
+		return NewMemoryDatabase(), nil
+	}
+
+	current, err := DetectEngine(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != "" && current != engine {
+		return nil, fmt.Errorf("%w: %s was created with %s, not %s", ErrEngineMismatch, path, current, engine)
+	}
+
+	var db Database
+
+	switch engine {
+	case LevelDB:
+		db, err = openLevelDB(path, options)
+	case Pebble:
+		db, err = openPebble(path, options)
+	case Badger:
+		db, err = openBadger(path, options)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEngine, engine)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if current == "" {
+		if err := os.WriteFile(filepath.Join(path, engineFile), []byte(engine), 0600); err != nil {
+			_ = db.Close()
+
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// DetectEngine returns the engine the database at the path was created with, empty if there is no database.
+// A database without the engine file was created before the engines were selectable, so it is a leveldb one
+func DetectEngine(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(path, engineFile))
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	return LevelDB, nil
+}
+
+// Stats returns the statistics of the database engine
+func Stats(db Database) (string, error) {
+	statsDB, ok := db.(StatsDatabase)
+	if !ok {
+		return "", ErrStatsNotSupported
+	}
+
+	return statsDB.Stats()
+}
+
+// Compact compacts the whole database
+func Compact(db Database) error {
+	compactDB, ok := db.(CompactDatabase)
+	if !ok {
+		return ErrCompactionNotSupported
+	}
+
+	return compactDB.Compact()
+}
+
+// prefixLimit returns the smallest key greater than all the keys with the prefix, nil if there is none
+func prefixLimit(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			limit := make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i]++
+
+			return limit
+		}
+	}
+
+	return nil
+}
+
+// iteratorStart returns the first key of an iterator over the prefix, starting from start
+func iteratorStart(prefix, start []byte) []byte {
+	return append(append([]byte{}, prefix...), start...)
+}
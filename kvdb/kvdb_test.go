@@ -0,0 +1,214 @@
+package kvdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDatabase(t *testing.T, engine string) Database {
+	t.Helper()
+
+	db, err := Open(engine, t.TempDir(), Options{})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
+func iterate(t *testing.T, db Database, prefix, start []byte) []string {
+	t.Helper()
+
+	iter := db.NewIterator(prefix, start)
+	defer iter.Release()
+
+	keys := []string{}
+	for iter.Next() {
+		keys = append(keys, string(iter.Key())+"="+string(iter.Value()))
+	}
+
+	require.NoError(t, iter.Error())
+
+	return keys
+}
+
+func TestDatabase(t *testing.T) {
+	t.Parallel()
+
+	for _, engine := range Engines() {
+		engine := engine
+
+		t.Run(engine, func(t *testing.T) {
+			t.Parallel()
+
+			db := openTestDatabase(t, engine)
+
+			_, ok, err := db.Get([]byte("a"))
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, db.Put([]byte("a"), []byte("1")))
+
+			value, ok, err := db.Get([]byte("a"))
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("1"), value)
+
+			require.NoError(t, db.Delete([]byte("a")))
+
+			_, ok, err = db.Get([]byte("a"))
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			batch := db.NewBatch()
+			batch.Put([]byte("b1"), []byte("1"))
+			batch.Put([]byte("b2"), []byte("2"))
+			batch.Put([]byte("b3"), []byte("3"))
+			batch.Put([]byte("c"), []byte("4"))
+			batch.Put([]byte("\xff"), []byte("5"))
+			batch.Delete([]byte("b3"))
+			assert.Equal(t, 6, batch.Len())
+
+			// the writes of the batch are applied once written
+			_, ok, _ = db.Get([]byte("b1"))
+			assert.False(t, ok)
+
+			require.NoError(t, batch.Write())
+
+			assert.Equal(t, []string{"b1=1", "b2=2", "c=4", "\xff=5"}, iterate(t, db, nil, nil))
+			assert.Equal(t, []string{"b1=1", "b2=2"}, iterate(t, db, []byte("b"), nil))
+			assert.Equal(t, []string{"b2=2"}, iterate(t, db, []byte("b"), []byte("2")))
+			assert.Equal(t, []string{"c=4", "\xff=5"}, iterate(t, db, nil, []byte("c")))
+			assert.Equal(t, []string{"\xff=5"}, iterate(t, db, []byte("\xff"), nil))
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	for _, engine := range []string{LevelDB, Pebble, Badger} {
+		engine := engine
+
+		t.Run(engine, func(t *testing.T) {
+			t.Parallel()
+
+			db := openTestDatabase(t, engine)
+
+			// an empty database is compacted too
+			require.NoError(t, Compact(db))
+
+			for _, key := range []string{"a", "b", "c"} {
+				require.NoError(t, db.Put([]byte(key), []byte(key)))
+			}
+
+			require.NoError(t, db.Delete([]byte("b")))
+			require.NoError(t, Compact(db))
+
+			assert.Equal(t, []string{"a=a", "c=c"}, iterate(t, db, nil, nil))
+		})
+	}
+
+	assert.ErrorIs(t, Compact(NewMemoryDatabase()), ErrCompactionNotSupported)
+}
+
+func TestOpen_EngineMismatch(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	db, err := Open(Pebble, path, Options{})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	engine, err := DetectEngine(path)
+	require.NoError(t, err)
+	assert.Equal(t, Pebble, engine)
+
+	_, err = Open(LevelDB, path, Options{})
+	assert.ErrorIs(t, err, ErrEngineMismatch)
+
+	// the databases without the engine file are leveldb ones
+	legacy := t.TempDir()
+
+	db, err = Open(LevelDB, legacy, Options{})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	require.NoError(t, os.Remove(filepath.Join(legacy, engineFile)))
+
+	_, err = Open(Badger, legacy, Options{})
+	assert.ErrorIs(t, err, ErrEngineMismatch)
+
+	_, err = Open("unknown", t.TempDir(), Options{})
+	assert.ErrorIs(t, err, ErrUnknownEngine)
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "db")
+
+	db, err := Open(LevelDB, path, Options{})
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, db.Put([]byte(key), []byte(key)))
+	}
+
+	require.NoError(t, db.Close())
+
+	_, err = Migrate(path, LevelDB, nil)
+	assert.ErrorIs(t, err, ErrInvalidMigration)
+
+	copied := []uint64{}
+
+	migration, err := Migrate(path, Badger, func(n uint64) {
+		copied = append(copied, n)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Migration{From: LevelDB, Keys: 3, Backup: path + ".leveldb"}, migration)
+	assert.Equal(t, []uint64{3}, copied)
+
+	db, err = Open(Badger, path, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a=a", "b=b", "c=c"}, iterate(t, db, nil, nil))
+	require.NoError(t, db.Close())
+
+	// the migrated database is kept
+	_, err = os.Stat(filepath.Join(path+".leveldb", engineFile))
+	assert.NoError(t, err)
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	db := WithMetrics(openTestDatabase(t, LevelDB), "test")
+
+	batch := db.NewBatch()
+	batch.Put([]byte("a"), []byte("1"))
+	batch.Put([]byte("b"), []byte("2"))
+	require.NoError(t, batch.Write())
+	require.NoError(t, db.Delete([]byte("b")))
+
+	value, ok, err := db.Get([]byte("a"))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	// the optional features of the engine are kept
+	require.NoError(t, Compact(db))
+
+	_, err = Stats(db)
+	assert.NoError(t, err)
+
+	_, err = Stats(WithMetrics(NewMemoryDatabase(), "memory"))
+	assert.ErrorIs(t, err, ErrStatsNotSupported)
+}
@@ -0,0 +1,88 @@
+package kvdb
+
+import (
+	"errors"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// levelDB is the leveldb database
+type levelDB struct {
+	db *leveldb.DB
+}
+
+func openLevelDB(path string, options Options) (Database, error) {
+	db, err := leveldb.OpenFile(path, &opt.Options{BlockCacheCapacity: options.CacheSize})
+	if err != nil {
+		return nil, err
+	}
+
+	return &levelDB{db: db}, nil
+}
+
+func (l *levelDB) Get(key []byte) ([]byte, bool, error) {
+	data, err := l.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (l *levelDB) Put(key, value []byte) error {
+	return l.db.Put(key, value, nil)
+}
+
+func (l *levelDB) Delete(key []byte) error {
+	return l.db.Delete(key, nil)
+}
+
+func (l *levelDB) NewBatch() Batch {
+	return &levelDBBatch{db: l.db, batch: &leveldb.Batch{}}
+}
+
+func (l *levelDB) NewIterator(prefix, start []byte) Iterator {
+	r := util.BytesPrefix(prefix)
+	r.Start = iteratorStart(prefix, start)
+
+	return l.db.NewIterator(r, nil)
+}
+
+// Stats returns the statistics of the levels of the leveldb database
+func (l *levelDB) Stats() (string, error) {
+	return l.db.GetProperty("leveldb.stats")
+}
+
+// Compact compacts all the levels of the leveldb database
+func (l *levelDB) Compact() error {
+	return l.db.CompactRange(util.Range{})
+}
+
+func (l *levelDB) Close() error {
+	return l.db.Close()
+}
+
+type levelDBBatch struct {
+	db    *leveldb.DB
+	batch *leveldb.Batch
+}
+
+func (b *levelDBBatch) Put(key, value []byte) {
+	b.batch.Put(key, value)
+}
+
+func (b *levelDBBatch) Delete(key []byte) {
+	b.batch.Delete(key)
+}
+
+func (b *levelDBBatch) Len() int {
+	return b.batch.Len()
+}
+
+func (b *levelDBBatch) Write() error {
+	return b.db.Write(b.batch, nil)
+}
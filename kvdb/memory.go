@@ -0,0 +1,159 @@
+package kvdb
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// memoryDB is a database kept in memory
+type memoryDB struct {
+	lock sync.RWMutex
+	db   map[string][]byte
+}
+
+// NewMemoryDatabase creates an empty database in memory
+func NewMemoryDatabase() Database {
+	return &memoryDB{db: map[string][]byte{}}
+}
+
+func (m *memoryDB) Get(key []byte) ([]byte, bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	value, ok := m.db[string(key)]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return append([]byte{}, value...), true, nil
+}
+
+func (m *memoryDB) Put(key, value []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.db[string(key)] = append([]byte{}, value...)
+
+	return nil
+}
+
+func (m *memoryDB) Delete(key []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.db, string(key))
+
+	return nil
+}
+
+func (m *memoryDB) NewBatch() Batch {
+	return &memoryBatch{db: m}
+}
+
+// NewIterator iterates over a copy of the keys taken when it is created,
+// so the writes made while iterating aren't seen by the iterator
+func (m *memoryDB) NewIterator(prefix, start []byte) Iterator {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	first := iteratorStart(prefix, start)
+	iter := &memoryIterator{index: -1}
+
+	for key, value := range m.db {
+		if !bytes.HasPrefix([]byte(key), prefix) || key < string(first) {
+			continue
+		}
+
+		iter.keys = append(iter.keys, []byte(key))
+		iter.values = append(iter.values, value)
+	}
+
+	sort.Sort(iter)
+
+	return iter
+}
+
+func (m *memoryDB) Close() error {
+	return nil
+}
+
+type memoryBatch struct {
+	db     *memoryDB
+	writes []memoryWrite
+}
+
+type memoryWrite struct {
+	key, value []byte
+	delete     bool
+}
+
+func (b *memoryBatch) Put(key, value []byte) {
+	b.writes = append(b.writes, memoryWrite{key: append([]byte{}, key...), value: append([]byte{}, value...)})
+}
+
+func (b *memoryBatch) Delete(key []byte) {
+	b.writes = append(b.writes, memoryWrite{key: append([]byte{}, key...), delete: true})
+}
+
+func (b *memoryBatch) Len() int {
+	return len(b.writes)
+}
+
+func (b *memoryBatch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	for _, write := range b.writes {
+		if write.delete {
+			delete(b.db.db, string(write.key))
+		} else {
+			b.db.db[string(write.key)] = write.value
+		}
+	}
+
+	return nil
+}
+
+type memoryIterator struct {
+	keys   [][]byte
+	values [][]byte
+	index  int
+}
+
+func (i *memoryIterator) Len() int {
+	return len(i.keys)
+}
+
+func (i *memoryIterator) Less(a, b int) bool {
+	return bytes.Compare(i.keys[a], i.keys[b]) < 0
+}
+
+func (i *memoryIterator) Swap(a, b int) {
+	i.keys[a], i.keys[b] = i.keys[b], i.keys[a]
+	i.values[a], i.values[b] = i.values[b], i.values[a]
+}
+
+func (i *memoryIterator) Next() bool {
+	if i.index < len(i.keys) {
+		i.index++
+	}
+
+	return i.index < len(i.keys)
+}
+
+func (i *memoryIterator) Key() []byte {
+	return i.keys[i.index]
+}
+
+func (i *memoryIterator) Value() []byte {
+	return i.values[i.index]
+}
+
+func (i *memoryIterator) Error() error {
+	return nil
+}
+
+func (i *memoryIterator) Release() {
+	i.keys, i.values = nil, nil
+}
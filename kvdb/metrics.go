@@ -0,0 +1,74 @@
+package kvdb
+
+import (
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
+)
+
+// The labels of the writes
+const (
+	writePut    = "put"
+	writeDelete = "delete"
+	writeBatch  = "batch"
+)
+
+// WithMetrics returns the database measuring the durations of its reads and writes, labeled with the name.
+// The iterators aren't measured, as they are held open across their whole iteration
+func WithMetrics(db Database, name string) Database {
+	return &metricsDB{
+		Database: db,
+		name:     name,
+	}
+}
+
+type metricsDB struct {
+	Database
+
+	name string
+}
+
+func (m *metricsDB) Get(key []byte) ([]byte, bool, error) {
+	defer telemetry.ObserveSince(telemetry.DBReadDuration, time.Now(), m.name)
+
+	return m.Database.Get(key)
+}
+
+func (m *metricsDB) Put(key, value []byte) error {
+	defer telemetry.ObserveSince(telemetry.DBWriteDuration, time.Now(), m.name, writePut)
+
+	return m.Database.Put(key, value)
+}
+
+func (m *metricsDB) Delete(key []byte) error {
+	defer telemetry.ObserveSince(telemetry.DBWriteDuration, time.Now(), m.name, writeDelete)
+
+	return m.Database.Delete(key)
+}
+
+func (m *metricsDB) NewBatch() Batch {
+	return &metricsBatch{
+		Batch: m.Database.NewBatch(),
+		name:  m.name,
+	}
+}
+
+func (m *metricsDB) Stats() (string, error) {
+	return Stats(m.Database)
+}
+
+func (m *metricsDB) Compact() error {
+	return Compact(m.Database)
+}
+
+type metricsBatch struct {
+	Batch
+
+	name string
+}
+
+func (b *metricsBatch) Write() error {
+	defer telemetry.ObserveSince(telemetry.DBWriteDuration, time.Now(), b.name, writeBatch)
+
+	return b.Batch.Write()
+}
@@ -0,0 +1,147 @@
+package kvdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// migrateBatchSize is the number of keys written at once while migrating a database
+	migrateBatchSize = 10000
+
+	// migratingSuffix is the suffix of the directory a database is copied to while migrating it
+	migratingSuffix = ".migrating"
+)
+
+var (
+	ErrNoDatabase       = errors.New("no database")
+	ErrInvalidMigration = errors.New("invalid migration")
+)
+
+// Migration is the migration of a database to another engine
+type Migration struct {
+	// From is the engine the database was migrated from
+	From string
+	// Keys is the number of keys copied
+	Keys uint64
+	// Backup is the path the database was moved to, once copied
+	Backup string
+}
+
+// Migrate moves the database at the path to the engine. The keys are copied to a new database,
+// which replaces the current one once complete, and the current one is kept at the backup path.
+// The number of keys copied so far is reported to progress, if it is set
+func Migrate(path, engine string, progress func(copied uint64)) (*Migration, error) {
+	if engine == Memory {
+		return nil, fmt.Errorf("%w: a database can't be migrated to the %s engine", ErrInvalidMigration, Memory)
+	}
+
+	from, err := DetectEngine(path)
+	if err != nil {
+		return nil, err
+	} else if from == "" {
+		return nil, fmt.Errorf("%w at %s", ErrNoDatabase, path)
+	} else if from == engine {
+		return nil, fmt.Errorf("%w: %s is already a %s database", ErrInvalidMigration, path, engine)
+	}
+
+	backup := fmt.Sprintf("%s.%s", path, from)
+	if _, err := os.Stat(backup); err == nil {
+		return nil, fmt.Errorf("the backup path %s already exists", backup)
+	}
+
+	// the copy of an interrupted migration is started over
+	migrating := path + migratingSuffix
+	if err := os.RemoveAll(migrating); err != nil {
+		return nil, err
+	}
+
+	keys, err := copyDatabase(from, path, engine, migrating, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(path, backup); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(migrating, path); err != nil {
+		return nil, err
+	}
+
+	return &Migration{From: from, Keys: keys, Backup: backup}, nil
+}
+
+func copyDatabase(
+	srcEngine, srcPath, dstEngine, dstPath string,
+	progress func(copied uint64),
+) (uint64, error) {
+	src, err := Open(srcEngine, srcPath, Options{})
+	if err != nil {
+		return 0, err
+	}
+
+	defer src.Close()
+
+	dst, err := Open(dstEngine, dstPath, Options{})
+	if err != nil {
+		return 0, err
+	}
+
+	keys, err := Copy(dst, src, migrateBatchSize, progress)
+	if err != nil {
+		_ = dst.Close()
+
+		return keys, err
+	}
+
+	return keys, dst.Close()
+}
+
+// Copy copies all the keys of the source database into the destination, written in batches of batchSize keys.
+// The number of keys copied so far is reported to progress after each batch, if it is set
+func Copy(dst, src Database, batchSize int, progress func(copied uint64)) (uint64, error) {
+	iter := src.NewIterator(nil, nil)
+	defer iter.Release()
+
+	var (
+		copied uint64
+		batch  = dst.NewBatch()
+	)
+
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+
+		if batch.Len() < batchSize {
+			continue
+		}
+
+		if err := batch.Write(); err != nil {
+			return copied, err
+		}
+
+		copied += uint64(batch.Len())
+		batch = dst.NewBatch()
+
+		if progress != nil {
+			progress(copied)
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return copied, err
+	}
+
+	if err := batch.Write(); err != nil {
+		return copied, err
+	}
+
+	copied += uint64(batch.Len())
+
+	if progress != nil {
+		progress(copied)
+	}
+
+	return copied, nil
+}
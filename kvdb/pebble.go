@@ -0,0 +1,155 @@
+package kvdb
+
+import (
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// pebbleDB is the pebble database
+type pebbleDB struct {
+	db *pebble.DB
+}
+
+func openPebble(path string, options Options) (Database, error) {
+	opts := &pebble.Options{}
+
+	if options.CacheSize > 0 {
+		cache := pebble.NewCache(int64(options.CacheSize))
+		// the database takes its own reference of the cache
+		defer cache.Unref()
+
+		opts.Cache = cache
+	}
+
+	db, err := pebble.Open(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pebbleDB{db: db}, nil
+}
+
+func (p *pebbleDB) Get(key []byte) ([]byte, bool, error) {
+	data, closer, err := p.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	// the value is only valid until the closer is closed
+	value := append([]byte{}, data...)
+
+	if err := closer.Close(); err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (p *pebbleDB) Put(key, value []byte) error {
+	return p.db.Set(key, value, pebble.NoSync)
+}
+
+func (p *pebbleDB) Delete(key []byte) error {
+	return p.db.Delete(key, pebble.NoSync)
+}
+
+func (p *pebbleDB) NewBatch() Batch {
+	return &pebbleBatch{batch: p.db.NewBatch()}
+}
+
+func (p *pebbleDB) NewIterator(prefix, start []byte) Iterator {
+	iter := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: iteratorStart(prefix, start),
+		UpperBound: prefixLimit(prefix),
+	})
+
+	return &pebbleIterator{iter: iter}
+}
+
+// Stats returns the metrics of the levels of the pebble database
+func (p *pebbleDB) Stats() (string, error) {
+	return p.db.Metrics().String(), nil
+}
+
+// Compact compacts the range between the first and the last key of the pebble database
+func (p *pebbleDB) Compact() error {
+	iter := p.db.NewIter(nil)
+
+	if !iter.First() {
+		return iter.Close()
+	}
+
+	first := append([]byte{}, iter.Key()...)
+
+	iter.Last()
+
+	// the end of the range is exclusive
+	end := append(append([]byte{}, iter.Key()...), 0)
+
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	return p.db.Compact(first, end, true)
+}
+
+func (p *pebbleDB) Close() error {
+	return p.db.Close()
+}
+
+type pebbleBatch struct {
+	batch *pebble.Batch
+}
+
+func (b *pebbleBatch) Put(key, value []byte) {
+	_ = b.batch.Set(key, value, nil)
+}
+
+func (b *pebbleBatch) Delete(key []byte) {
+	_ = b.batch.Delete(key, nil)
+}
+
+func (b *pebbleBatch) Len() int {
+	return int(b.batch.Count())
+}
+
+func (b *pebbleBatch) Write() error {
+	defer b.batch.Close()
+
+	return b.batch.Commit(pebble.NoSync)
+}
+
+// pebbleIterator positions the pebble iterator on the first key with the first call to Next
+type pebbleIterator struct {
+	iter    *pebble.Iterator
+	started bool
+}
+
+func (i *pebbleIterator) Next() bool {
+	if !i.started {
+		i.started = true
+
+		return i.iter.First()
+	}
+
+	return i.iter.Next()
+}
+
+func (i *pebbleIterator) Key() []byte {
+	return i.iter.Key()
+}
+
+func (i *pebbleIterator) Value() []byte {
+	return i.iter.Value()
+}
+
+func (i *pebbleIterator) Error() error {
+	return i.iter.Error()
+}
+
+func (i *pebbleIterator) Release() {
+	_ = i.iter.Close()
+}
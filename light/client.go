@@ -0,0 +1,553 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/light/proto"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/umbracle/fastrlp"
+	rawGrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// defaultSyncInterval is the interval between the syncs of the head from the peers
+	defaultSyncInterval = 2 * time.Second
+
+	// requestTimeout is the timeout of a request to a peer
+	requestTimeout = 10 * time.Second
+
+	// maxCachedHeaders is the number of the verified headers kept in memory
+	maxCachedHeaders = 1024
+)
+
+// emptyCodeHash is the code hash of the accounts without code
+var emptyCodeHash = types.BytesToHash(crypto.Keccak256(nil))
+
+var (
+	ErrNoPeers          = errors.New("no peer served the request")
+	ErrNotSynced        = errors.New("light client not synced yet")
+	ErrHeaderNotTrusted = errors.New("header precedes the trusted header")
+	ErrUnexpectedHeader = errors.New("unexpected header")
+	ErrCheckpointHash   = errors.New("header doesn't match the checkpoint hash")
+)
+
+type ClientNetwork interface {
+	// NewProtoConnection opens up a new stream on the set protocol to the peer,
+	// and returns a reference to the connection
+	NewProtoConnection(protocol string, peerID peer.ID) (*rawGrpc.ClientConn, error)
+	// Peers returns the connected peers
+	Peers() []*network.PeerConnInfo
+}
+
+// ClientConfig is the trusted header and the consensus parameters of the chain followed by the client
+type ClientConfig struct {
+	// Genesis is the genesis header, whose validators are trusted if there's no checkpoint
+	Genesis *types.Header
+	// Checkpoint is the trusted header fetched from the peers instead of starting from the genesis, nil if not set
+	Checkpoint *statesync.Checkpoint
+	// EpochSize is the number of the blocks of an epoch
+	EpochSize uint64
+	// QuorumSizeBlockNum is the block from which the optimal quorum size is used
+	QuorumSizeBlockNum uint64
+	// ValidatorType returns the type of the validators sealing the block at the height
+	ValidatorType func(height uint64) validators.ValidatorType
+	// SyncInterval is the interval between the syncs of the head, the default one if zero
+	SyncInterval time.Duration
+}
+
+// validatorSet is a validator set verified by the client, sealing the blocks from the given one
+type validatorSet struct {
+	from       uint64
+	validators validators.Validators
+}
+
+// Client follows the headers of the chain from the peers serving the light clients, without storing nor
+// executing the blocks. The headers are trusted from the sets of the validators sealing them, starting from the
+// validators of the genesis or of a checkpoint, and following their changes from the validator transitions of
+// the epochs. The state is fetched from the peers along with its proofs against the state roots of the headers
+type Client struct {
+	logger   hclog.Logger
+	network  ClientNetwork
+	config   *ClientConfig
+	verifier *verifier
+
+	lock    sync.RWMutex
+	head    *types.Header   // latest verified header, nil until the client is bootstrapped
+	sets    []*validatorSet // verified validator sets, by the first block they seal
+	headers *lru.Cache      // verified headers, by number
+
+	closeCh chan struct{}
+}
+
+// NewClient creates a new light client, trusting the genesis or the checkpoint of the config
+func NewClient(logger hclog.Logger, network ClientNetwork, config *ClientConfig) (*Client, error) {
+	headers, err := lru.New(maxCachedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.SyncInterval == 0 {
+		config.SyncInterval = defaultSyncInterval
+	}
+
+	c := &Client{
+		logger:   logger.Named("light"),
+		network:  network,
+		config:   config,
+		verifier: newVerifier(config.QuorumSizeBlockNum, config.ValidatorType),
+		headers:  headers,
+		closeCh:  make(chan struct{}),
+	}
+
+	if config.Checkpoint == nil {
+		if err := c.trust(config.Genesis); err != nil {
+			return nil, fmt.Errorf("unable to trust the genesis validators, %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Start starts following the head of the chain
+func (c *Client) Start() {
+	go c.run()
+}
+
+// Close stops following the head of the chain
+func (c *Client) Close() {
+	close(c.closeCh)
+}
+
+func (c *Client) run() {
+	ticker := time.NewTicker(c.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Sync(context.Background()); err != nil {
+			c.logger.Debug("unable to sync the head", "err", err)
+		}
+
+		select {
+		case <-c.closeCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Head returns the latest verified header, nil if the client isn't bootstrapped yet
+func (c *Client) Head() *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.head
+}
+
+// trust sets the trusted header the client starts from, along with the validators listed in its extra
+func (c *Client) trust(header *types.Header) error {
+	if err := c.verifier.hashHeader(header); err != nil {
+		return err
+	}
+
+	vals, err := c.verifier.validators(header)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.head = header
+	c.sets = []*validatorSet{{from: header.Number, validators: vals}}
+	c.headers.Add(header.Number, header)
+
+	c.logger.Info("trusted header", "number", header.Number, "hash", header.Hash, "validators", vals.Len())
+
+	return nil
+}
+
+// validatorsAt returns the verified validator set of the block
+func (c *Client) validatorsAt(number uint64) (validators.Validators, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.head == nil {
+		return nil, ErrNotSynced
+	}
+
+	for i := len(c.sets) - 1; i >= 0; i-- {
+		if c.sets[i].from <= number {
+			return c.sets[i].validators, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d < %d", ErrHeaderNotTrusted, number, c.sets[0].from)
+}
+
+// verify verifies the header against the validator set of its block. The headers past the head are
+// verified against the latest set, and become the head along with their validators
+func (c *Client) verify(header *types.Header) error {
+	trusted, err := c.validatorsAt(header.Number)
+	if err != nil {
+		return err
+	}
+
+	vals, err := c.verifier.verifyHeader(header, trusted)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.headers.Add(header.Number, header)
+
+	if header.Number <= c.head.Number {
+		return nil
+	}
+
+	c.head = header
+
+	if last := c.sets[len(c.sets)-1]; !last.validators.Equal(vals) {
+		c.sets = append(c.sets, &validatorSet{from: header.Number, validators: vals})
+
+		c.logger.Info("validator set changed", "number", header.Number, "validators", vals.Len())
+	}
+
+	return nil
+}
+
+// epoch returns the epoch of the block
+func (c *Client) epoch(number uint64) uint64 {
+	if number == 0 {
+		return 0
+	}
+
+	return (number-1)/c.config.EpochSize + 1
+}
+
+// request calls the peers serving the light clients in random order, until one of them succeeds
+func (c *Client) request(ctx context.Context, call func(ctx context.Context, client proto.LightClient) error) error {
+	peers := c.network.Peers()
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	lastErr := ErrNoPeers
+
+	for _, p := range peers {
+		conn, err := c.network.NewProtoConnection(lightProto, p.Info.ID)
+		if err != nil {
+			continue
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		err = call(reqCtx, proto.NewLightClient(conn))
+
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+
+		c.logger.Debug("light request failed", "peer", p.Info.ID, "err", err)
+
+		lastErr = fmt.Errorf("%w, last error: %v", ErrNoPeers, err)
+	}
+
+	return lastErr
+}
+
+// Sync verifies the head of a peer, following the validator transitions from the head of the client
+func (c *Client) Sync(ctx context.Context) error {
+	if c.Head() == nil {
+		if err := c.bootstrap(ctx); err != nil {
+			return err
+		}
+	}
+
+	return c.request(ctx, func(ctx context.Context, client proto.LightClient) error {
+		res, err := client.GetHead(ctx, &emptypb.Empty{})
+		if err != nil {
+			return err
+		}
+
+		head, err := decodeHeader(res.Header)
+		if err != nil {
+			return err
+		}
+
+		if head.Number <= c.Head().Number {
+			return nil
+		}
+
+		if err := c.followTransitions(ctx, client, head.Number); err != nil {
+			return err
+		}
+
+		return c.verify(head)
+	})
+}
+
+// bootstrap fetches the header of the checkpoint, trusted by its hash
+func (c *Client) bootstrap(ctx context.Context) error {
+	checkpoint := c.config.Checkpoint
+
+	return c.request(ctx, func(ctx context.Context, client proto.LightClient) error {
+		res, err := client.GetHeaders(ctx, &proto.GetHeaderRangeRequest{From: checkpoint.Number, Count: 1})
+		if err != nil {
+			return err
+		}
+
+		if len(res.Headers) != 1 {
+			return fmt.Errorf("%w: checkpoint %d not served", ErrUnexpectedHeader, checkpoint.Number)
+		}
+
+		header, err := decodeHeader(res.Headers[0])
+		if err != nil {
+			return err
+		}
+
+		if err := c.verifier.hashHeader(header); err != nil {
+			return err
+		}
+
+		if header.Number != checkpoint.Number || header.Hash != checkpoint.Hash {
+			return fmt.Errorf("%w: %d %s", ErrCheckpointHash, header.Number, header.Hash)
+		}
+
+		return c.trust(header)
+	})
+}
+
+// followTransitions verifies the headers of the epochs changing the validator set, up to the target block
+func (c *Client) followTransitions(ctx context.Context, client proto.LightClient, target uint64) error {
+	from := c.epoch(c.Head().Number) + 1
+
+	for from <= c.epoch(target) {
+		res, err := client.GetValidatorTransitions(ctx, &proto.GetValidatorTransitionsRequest{FromEpoch: from})
+		if err != nil {
+			return err
+		}
+
+		for _, transition := range res.Transitions {
+			header, err := decodeHeader(transition.Header)
+			if err != nil {
+				return err
+			}
+
+			if transition.Epoch < from || header.Number != (transition.Epoch-1)*c.config.EpochSize+1 {
+				return fmt.Errorf(
+					"%w: block %d as the transition of epoch %d",
+					ErrUnexpectedHeader,
+					header.Number,
+					transition.Epoch,
+				)
+			}
+
+			if err := c.verify(header); err != nil {
+				return err
+			}
+		}
+
+		if res.NextEpoch == 0 {
+			return nil
+		}
+
+		if res.NextEpoch <= from {
+			return fmt.Errorf("%w: transitions from epoch %d continue at %d", ErrUnexpectedHeader, from, res.NextEpoch)
+		}
+
+		from = res.NextEpoch
+	}
+
+	return nil
+}
+
+// GetHeader returns the verified header of the block, fetched from the peers if it isn't cached
+func (c *Client) GetHeader(ctx context.Context, number uint64) (*types.Header, error) {
+	head := c.Head()
+	if head == nil {
+		return nil, ErrNotSynced
+	}
+
+	if number > head.Number {
+		return nil, fmt.Errorf("%w: %d past the head %d", ErrHeaderNotFound, number, head.Number)
+	}
+
+	if header, ok := c.headers.Get(number); ok {
+		header, _ := header.(*types.Header)
+
+		return header, nil
+	}
+
+	var header *types.Header
+
+	err := c.request(ctx, func(ctx context.Context, client proto.LightClient) error {
+		res, err := client.GetHeaders(ctx, &proto.GetHeaderRangeRequest{From: number, Count: 1})
+		if err != nil {
+			return err
+		}
+
+		if len(res.Headers) != 1 {
+			return fmt.Errorf("%w: block %d not served", ErrUnexpectedHeader, number)
+		}
+
+		if header, err = decodeHeader(res.Headers[0]); err != nil {
+			return err
+		}
+
+		if header.Number != number {
+			return fmt.Errorf("%w: block %d instead of %d", ErrUnexpectedHeader, header.Number, number)
+		}
+
+		return c.verify(header)
+	})
+
+	return header, err
+}
+
+// GetAccount returns the account in the state of the block, proved against its state root.
+// A missing account is returned empty
+func (c *Client) GetAccount(ctx context.Context, header *types.Header, addr types.Address) (*state.Account, error) {
+	account, _, err := c.getProvedState(ctx, header, addr, nil)
+
+	return account, err
+}
+
+// GetStorage returns the value of the storage slot of the account in the state of the block,
+// proved against its state root. An unset slot is returned zero
+func (c *Client) GetStorage(
+	ctx context.Context,
+	header *types.Header,
+	addr types.Address,
+	slot types.Hash,
+) (types.Hash, error) {
+	_, values, err := c.getProvedState(ctx, header, addr, []types.Hash{slot})
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	return values[0], nil
+}
+
+// getProvedState fetches the account and the values of its storage slots from the peers, along with their proofs
+func (c *Client) getProvedState(
+	ctx context.Context,
+	header *types.Header,
+	addr types.Address,
+	slots []types.Hash,
+) (*state.Account, []types.Hash, error) {
+	var (
+		account *state.Account
+		values  []types.Hash
+	)
+
+	req := &proto.GetAccountProofRequest{
+		Number:      header.Number,
+		Address:     addr.Bytes(),
+		StorageKeys: make([][]byte, len(slots)),
+	}
+
+	for i, slot := range slots {
+		req.StorageKeys[i] = slot.Bytes()
+	}
+
+	err := c.request(ctx, func(ctx context.Context, client proto.LightClient) error {
+		res, err := client.GetAccountProof(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if account, err = verifyAccount(header.StateRoot, addr, res.AccountProof); err != nil {
+			return err
+		}
+
+		if len(res.StorageProofs) != len(slots) {
+			return fmt.Errorf("%w: %d storage proofs for %d slots", itrie.ErrInvalidProof, len(res.StorageProofs), len(slots))
+		}
+
+		values = make([]types.Hash, len(slots))
+
+		for i, slot := range slots {
+			if values[i], err = verifyStorage(account.Root, slot, res.StorageProofs[i].Nodes); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return account, values, err
+}
+
+// verifyAccount returns the account proved against the state root, empty if its absence is proved
+func verifyAccount(root types.Hash, addr types.Address, proof [][]byte) (*state.Account, error) {
+	value, err := itrie.VerifyProof(root, crypto.Keccak256(addr.Bytes()), proof)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &state.Account{}
+
+	if value == nil {
+		account.Balance = new(big.Int)
+		account.Root = types.EmptyRootHash
+		account.CodeHash = emptyCodeHash.Bytes()
+
+		return account, nil
+	}
+
+	if err := account.UnmarshalRlp(value); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// verifyStorage returns the value of the slot proved against the storage root, zero if its absence is proved
+func verifyStorage(root types.Hash, slot types.Hash, proof [][]byte) (types.Hash, error) {
+	value, err := itrie.VerifyProof(root, crypto.Keccak256(slot.Bytes()), proof)
+	if err != nil || value == nil {
+		return types.ZeroHash, err
+	}
+
+	// the values are stored RLP encoded
+	v, err := (&fastrlp.Parser{}).Parse(value)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	data, err := v.Bytes()
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	return types.BytesToHash(data), nil
+}
+
+// decodeHeader decodes the RLP encoded header
+func decodeHeader(data []byte) (*types.Header, error) {
+	header := &types.Header{}
+	if err := header.UnmarshalRLP(data); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
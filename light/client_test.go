@@ -0,0 +1,313 @@
+package light
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/light/proto"
+	"github.com/0xPolygon/polygon-edge/network"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+// mockNetwork connects the client to a single peer serving the light clients in memory
+type mockNetwork struct {
+	lis *bufconn.Listener
+}
+
+func (m *mockNetwork) NewProtoConnection(_ string, _ peer.ID) (*grpc.ClientConn, error) {
+	return grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return m.lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
+
+func (m *mockNetwork) Peers() []*network.PeerConnInfo {
+	return []*network.PeerConnInfo{{Info: peer.AddrInfo{ID: "peer"}}}
+}
+
+func newMockNetwork(t *testing.T, s *Server) *mockNetwork {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	proto.RegisterLightServer(grpcServer, s)
+
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+
+	t.Cleanup(grpcServer.Stop)
+
+	return &mockNetwork{lis: lis}
+}
+
+func newTestKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	t.Helper()
+
+	keys := make([]*ecdsa.PrivateKey, n)
+
+	for i := range keys {
+		key, err := crypto.GenerateECDSAKey()
+		require.NoError(t, err)
+
+		keys[i] = key
+	}
+
+	return keys
+}
+
+func keysValidators(keys []*ecdsa.PrivateKey) validators.Validators {
+	set := validators.NewECDSAValidatorSet()
+
+	for _, key := range keys {
+		_ = set.Add(validators.NewECDSAValidator(crypto.PubKeyToAddress(&key.PublicKey)))
+	}
+
+	return set
+}
+
+// sealedChain is a chain of headers sealed by the validator keys of their blocks
+type sealedChain struct {
+	*mockBlockchain
+	keys func(number uint64) []*ecdsa.PrivateKey
+}
+
+// newSealedChain creates a chain of the given number of blocks, whose headers list and are sealed by the keys
+// of their block, all sharing the same state
+func newSealedChain(
+	t *testing.T,
+	blocks int,
+	root types.Hash,
+	keys func(number uint64) []*ecdsa.PrivateKey,
+) *sealedChain {
+	t.Helper()
+
+	chain := &sealedChain{
+		mockBlockchain: &mockBlockchain{receipts: make(map[types.Hash][]*types.Receipt)},
+		keys:           keys,
+	}
+
+	for number := 0; number < blocks; number++ {
+		header := &types.Header{Number: uint64(number), StateRoot: root}
+
+		chain.seal(t, header)
+		chain.headers = append(chain.headers, header)
+	}
+
+	return chain
+}
+
+// seal writes the validators of the block into the extra of the header, sealed by all of them
+func (c *sealedChain) seal(t *testing.T, header *types.Header) {
+	t.Helper()
+
+	keys := c.keys(header.Number)
+	s := signer.NewSigner(signer.NewECDSAKeyManagerFromKey(keys[0]), signer.NewECDSAKeyManagerFromKey(keys[0]))
+	s.InitIBFTExtra(header, keysValidators(keys), nil)
+
+	hash, err := s.CalculateHeaderHash(header)
+	require.NoError(t, err)
+
+	header.Hash = hash
+
+	if header.Number == 0 {
+		return
+	}
+
+	seals := make(map[types.Address][]byte)
+
+	for _, key := range keys {
+		seal, err := signer.NewSigner(signer.NewECDSAKeyManagerFromKey(key), nil).CreateCommittedSeal(hash.Bytes())
+		require.NoError(t, err)
+
+		seals[crypto.PubKeyToAddress(&key.PublicKey)] = seal
+	}
+
+	_, err = s.WriteCommittedSeals(header, seals)
+	require.NoError(t, err)
+}
+
+func (c *sealedChain) consensus() *mockConsensus {
+	return &mockConsensus{
+		validators: func(number uint64) validators.Validators {
+			return keysValidators(c.keys(number))
+		},
+	}
+}
+
+func newTestClient(t *testing.T, chain *sealedChain, st *itrie.State, checkpoint *statesync.Checkpoint) *Client {
+	t.Helper()
+
+	server := NewServer(hclog.NewNullLogger(), nil, chain.mockBlockchain, st, chain.consensus())
+
+	client, err := NewClient(hclog.NewNullLogger(), newMockNetwork(t, server), &ClientConfig{
+		Genesis:    chain.headers[0].Copy(),
+		Checkpoint: checkpoint,
+		EpochSize:  testEpochSize,
+		ValidatorType: func(uint64) validators.ValidatorType {
+			return validators.ECDSAValidatorType
+		},
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestClient_Sync(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t, 5)
+	st, root := newTestState()
+
+	// the first validator is replaced in the third epoch
+	chain := newSealedChain(t, 3*testEpochSize+5, root, func(number uint64) []*ecdsa.PrivateKey {
+		if number <= 2*testEpochSize {
+			return keys[:4]
+		}
+
+		return keys[1:]
+	})
+
+	client := newTestClient(t, chain, st, nil)
+	require.NoError(t, client.Sync(context.Background()))
+
+	assert.Equal(t, chain.Header().Hash, client.Head().Hash)
+	require.Len(t, client.sets, 2)
+	assert.Equal(t, uint64(2*testEpochSize+1), client.sets[1].from)
+	assert.True(t, client.sets[1].validators.Equal(keysValidators(keys[1:])))
+
+	// the headers of both validator sets are verified
+	for _, number := range []uint64{5, 2*testEpochSize + 3} {
+		header, err := client.GetHeader(context.Background(), number)
+		require.NoError(t, err)
+		assert.Equal(t, chain.headers[number].Hash, header.Hash)
+	}
+
+	_, err := client.GetHeader(context.Background(), chain.Header().Number+1)
+	assert.ErrorIs(t, err, ErrHeaderNotFound)
+
+	// a header altered after sealing is rejected
+	chain.headers[7].StateRoot = types.StringToHash("1")
+
+	_, err = client.GetHeader(context.Background(), 7)
+	assert.ErrorIs(t, err, ErrNoPeers)
+	assert.ErrorContains(t, err, ErrInvalidSeals.Error())
+}
+
+func TestClient_UntrustedValidators(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t, 8)
+	st, root := newTestState()
+
+	// the validators of the third epoch aren't sealed by any of the previous ones
+	chain := newSealedChain(t, 3*testEpochSize+5, root, func(number uint64) []*ecdsa.PrivateKey {
+		if number <= 2*testEpochSize {
+			return keys[:4]
+		}
+
+		return keys[4:]
+	})
+
+	client := newTestClient(t, chain, st, nil)
+
+	err := client.Sync(context.Background())
+	assert.ErrorContains(t, err, ErrUntrustedValidators.Error())
+
+	// the client stops at the last trusted transition
+	assert.Equal(t, uint64(1), client.Head().Number)
+	assert.Len(t, client.sets, 1)
+}
+
+func TestClient_Checkpoint(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t, 4)
+	st, root := newTestState()
+
+	chain := newSealedChain(t, 3*testEpochSize, root, func(uint64) []*ecdsa.PrivateKey {
+		return keys
+	})
+
+	checkpoint := &statesync.Checkpoint{Number: 2*testEpochSize + 1, Hash: types.StringToHash("1")}
+
+	client := newTestClient(t, chain, st, checkpoint)
+	assert.ErrorContains(t, client.Sync(context.Background()), ErrCheckpointHash.Error())
+	assert.Nil(t, client.Head())
+
+	checkpoint.Hash = chain.headers[checkpoint.Number].Hash
+
+	require.NoError(t, client.Sync(context.Background()))
+	assert.Equal(t, chain.Header().Hash, client.Head().Hash)
+
+	// the headers preceding the checkpoint aren't trusted
+	_, err := client.GetHeader(context.Background(), 5)
+	assert.ErrorIs(t, err, ErrNoPeers)
+	assert.ErrorContains(t, err, ErrHeaderNotTrusted.Error())
+}
+
+func TestClient_GetState(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t, 4)
+	st, root := newTestState()
+
+	chain := newSealedChain(t, 5, root, func(uint64) []*ecdsa.PrivateKey {
+		return keys
+	})
+
+	client := newTestClient(t, chain, st, nil)
+	require.NoError(t, client.Sync(context.Background()))
+
+	var (
+		ctx     = context.Background()
+		head    = client.Head()
+		addr    = types.BytesToAddress([]byte{7})
+		missing = types.StringToAddress("dead")
+		slot    = types.BytesToHash([]byte{1})
+	)
+
+	account, err := client.GetAccount(ctx, head, addr)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(7), account.Balance)
+
+	value, err := client.GetStorage(ctx, head, addr, slot)
+	require.NoError(t, err)
+	assert.Equal(t, types.BytesToHash([]byte{7}), value)
+
+	value, err = client.GetStorage(ctx, head, addr, types.ZeroHash)
+	require.NoError(t, err)
+	assert.Equal(t, types.ZeroHash, value)
+
+	// the absence of the account is proved
+	account, err = client.GetAccount(ctx, head, missing)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(0), account.Balance)
+	assert.Equal(t, types.EmptyRootHash, account.Root)
+
+	// the proofs don't match another state root
+	forged := head.Copy()
+	forged.StateRoot = types.StringToHash("1")
+
+	_, err = client.GetAccount(ctx, forged, addr)
+	assert.ErrorContains(t, err, itrie.ErrInvalidProof.Error())
+}
@@ -0,0 +1,301 @@
+package light
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/light/proto"
+	"github.com/0xPolygon/polygon-edge/network/grpc"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/fastrlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	lightProto = "/light/0.1"
+
+	// maxHeadersPerRequest is the maximum number of the headers served at once
+	maxHeadersPerRequest = 192
+
+	// maxEpochsPerRequest is the maximum number of the epochs searched for the validator transitions at once
+	maxEpochsPerRequest = 1024
+
+	// maxTransitionsPerRequest is the maximum number of the validator transitions served at once
+	maxTransitionsPerRequest = 64
+
+	// maxStorageKeysPerRequest is the maximum number of the storage slots proved at once
+	maxStorageKeysPerRequest = 256
+)
+
+var (
+	ErrNoConsensus     = errors.New("consensus doesn't provide the validator sets")
+	ErrHeaderNotFound  = errors.New("header not found")
+	ErrInvalidEpoch    = errors.New("invalid epoch")
+	ErrInvalidAddress  = errors.New("invalid address")
+	ErrInvalidKey      = errors.New("invalid storage key")
+	ErrInvalidHash     = errors.New("invalid block hash")
+	ErrTooManyItems    = errors.New("too many items requested")
+	ErrReceiptNotFound = errors.New("receipt not found")
+)
+
+// Server serves the light clients, which verify the chain without executing the blocks.
+// The headers are served with the committed seals of the validators in their extra, so a client
+// trusting a validator set verifies the headers it seals, and follows the changes of the set
+// from the validator transitions of the epochs. The accounts and the receipts are served with
+// their Merkle proofs against the state and receipts roots of the verified headers
+type Server struct {
+	proto.UnimplementedLightServer
+
+	logger     hclog.Logger
+	network    Network
+	blockchain Blockchain
+	state      State
+	consensus  Consensus
+
+	stream *grpc.GrpcStream
+}
+
+// NewServer creates a new light client server. The validator transitions aren't served
+// if consensus is nil
+func NewServer(
+	logger hclog.Logger,
+	network Network,
+	blockchain Blockchain,
+	state State,
+	consensus Consensus,
+) *Server {
+	return &Server{
+		logger:     logger.Named("light"),
+		network:    network,
+		blockchain: blockchain,
+		state:      state,
+		consensus:  consensus,
+	}
+}
+
+// Start starts serving the light clients
+func (s *Server) Start() {
+	s.stream = grpc.NewGrpcStream()
+
+	proto.RegisterLightServer(s.stream.GrpcServer(), s)
+	s.stream.Serve()
+	s.network.RegisterProtocol(lightProto, s.stream)
+}
+
+// Close stops serving the light clients
+func (s *Server) Close() error {
+	if s.stream != nil {
+		return s.stream.Close()
+	}
+
+	return nil
+}
+
+// GetHead is a gRPC endpoint to return the latest header and the size of the epochs
+func (s *Server) GetHead(context.Context, *emptypb.Empty) (*proto.Head, error) {
+	res := &proto.Head{Header: s.blockchain.Header().MarshalRLP()}
+
+	if s.consensus != nil {
+		res.EpochSize = s.consensus.GetEpochSize()
+	}
+
+	return res, nil
+}
+
+// GetHeaders is a gRPC endpoint to return the consecutive canonical headers from the given number.
+// The headers past the head of the chain are omitted
+func (s *Server) GetHeaders(_ context.Context, req *proto.GetHeaderRangeRequest) (*proto.HeaderRange, error) {
+	if req.Count > maxHeadersPerRequest {
+		return nil, status.Error(codes.InvalidArgument, ErrTooManyItems.Error())
+	}
+
+	res := &proto.HeaderRange{Headers: make([][]byte, 0, req.Count)}
+
+	for number := req.From; number < req.From+req.Count; number++ {
+		header, ok := s.blockchain.GetHeaderByNumber(number)
+		if !ok {
+			break
+		}
+
+		res.Headers = append(res.Headers, header.MarshalRLP())
+	}
+
+	return res, nil
+}
+
+// GetValidatorTransitions is a gRPC endpoint to return the epochs changing the validator set, from the given one.
+// The first epoch searched is always returned, so a client learns the validator set it starts from
+func (s *Server) GetValidatorTransitions(
+	_ context.Context,
+	req *proto.GetValidatorTransitionsRequest,
+) (*proto.ValidatorTransitions, error) {
+	if s.consensus == nil {
+		return nil, status.Error(codes.Unimplemented, ErrNoConsensus.Error())
+	}
+
+	if req.FromEpoch == 0 {
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidEpoch.Error())
+	}
+
+	var (
+		epochSize = s.consensus.GetEpochSize()
+		head      = s.blockchain.Header().Number
+		res       = &proto.ValidatorTransitions{Transitions: make([]*proto.ValidatorTransition, 0)}
+	)
+
+	var (
+		previous validators.Validators
+		ar       = &fastrlp.Arena{}
+	)
+
+	for epoch := req.FromEpoch; epoch < req.FromEpoch+maxEpochsPerRequest; epoch++ {
+		// the validators of an epoch are the ones of its first block
+		number := (epoch-1)*epochSize + 1
+		if number > head {
+			return res, nil
+		}
+
+		if len(res.Transitions) == maxTransitionsPerRequest {
+			res.NextEpoch = epoch
+
+			return res, nil
+		}
+
+		header, ok := s.blockchain.GetHeaderByNumber(number)
+		if !ok {
+			return nil, status.Error(codes.NotFound, ErrHeaderNotFound.Error())
+		}
+
+		vals, err := s.consensus.GetValidators(header)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		if previous != nil && previous.Equal(vals) {
+			continue
+		}
+
+		previous = vals
+
+		res.Transitions = append(res.Transitions, &proto.ValidatorTransition{
+			Epoch:         epoch,
+			Header:        header.MarshalRLP(),
+			ValidatorType: string(vals.Type()),
+			Validators:    vals.MarshalRLPWith(ar).MarshalTo(nil),
+		})
+
+		ar.Reset()
+	}
+
+	res.NextEpoch = req.FromEpoch + maxEpochsPerRequest
+
+	return res, nil
+}
+
+// GetAccountProof is a gRPC endpoint to return the proof of an account and of its storage slots
+// in the state at the given block. The proofs of the missing ones prove their absence
+func (s *Server) GetAccountProof(_ context.Context, req *proto.GetAccountProofRequest) (*proto.AccountProof, error) {
+	if len(req.Address) != types.AddressLength {
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidAddress.Error())
+	}
+
+	if len(req.StorageKeys) > maxStorageKeysPerRequest {
+		return nil, status.Error(codes.InvalidArgument, ErrTooManyItems.Error())
+	}
+
+	header, ok := s.blockchain.GetHeaderByNumber(req.Number)
+	if !ok {
+		return nil, status.Error(codes.NotFound, ErrHeaderNotFound.Error())
+	}
+
+	addrKey := crypto.Keccak256(req.Address)
+
+	accountProof, err := s.state.Prove(header.StateRoot, addrKey)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	res := &proto.AccountProof{
+		AccountProof:  accountProof,
+		StorageProofs: make([]*proto.Proof, len(req.StorageKeys)),
+	}
+
+	// the storage slots of a missing account are proved absent by the account proof alone
+	storageRoot := types.EmptyRootHash
+
+	snap, err := s.state.NewSnapshotAt(header.StateRoot)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if data, ok := snap.Get(addrKey); ok {
+		var account state.Account
+		if err := account.UnmarshalRlp(data); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		storageRoot = account.Root
+	}
+
+	for i, key := range req.StorageKeys {
+		if len(key) != types.HashLength {
+			return nil, status.Error(codes.InvalidArgument, ErrInvalidKey.Error())
+		}
+
+		proof, err := s.state.Prove(storageRoot, crypto.Keccak256(key))
+		if err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		res.StorageProofs[i] = &proto.Proof{Nodes: proof}
+	}
+
+	return res, nil
+}
+
+// GetReceiptProof is a gRPC endpoint to return the receipt of a transaction of the block with its proof.
+// The receipts trie isn't stored, it's built again from the receipts of the block
+func (s *Server) GetReceiptProof(_ context.Context, req *proto.GetReceiptProofRequest) (*proto.ReceiptProof, error) {
+	if len(req.Hash) != types.HashLength {
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidHash.Error())
+	}
+
+	header, ok := s.blockchain.GetHeaderByHash(types.BytesToHash(req.Hash))
+	if !ok {
+		return nil, status.Error(codes.NotFound, ErrHeaderNotFound.Error())
+	}
+
+	receipts, err := s.blockchain.GetReceiptsByHash(header.Hash)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if req.Index >= uint64(len(receipts)) {
+		return nil, status.Error(codes.NotFound, ErrReceiptNotFound.Error())
+	}
+
+	item := func(i int) []byte {
+		return receipts[i].MarshalRLPTo(nil)
+	}
+
+	root, proof, err := itrie.ProveIndex(len(receipts), item, int(req.Index))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// the receipts of the store don't match the block, they can't be proved
+	if root != header.ReceiptsRoot {
+		s.logger.Error("receipts don't match the receipts root", "block", header.Number, "hash", header.Hash)
+
+		return nil, status.Error(codes.Internal, ErrReceiptNotFound.Error())
+	}
+
+	return &proto.ReceiptProof{Receipt: item(int(req.Index)), Proof: proof}, nil
+}
@@ -0,0 +1,327 @@
+package light
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/fastrlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/light/proto"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+// testEpochSize is the number of the blocks of an epoch of the tests
+const testEpochSize = 10
+
+// mockBlockchain is a chain of headers with the receipts of its blocks
+type mockBlockchain struct {
+	headers  []*types.Header
+	receipts map[types.Hash][]*types.Receipt
+}
+
+func (m *mockBlockchain) Header() *types.Header {
+	return m.headers[len(m.headers)-1]
+}
+
+func (m *mockBlockchain) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number >= uint64(len(m.headers)) {
+		return nil, false
+	}
+
+	return m.headers[number], true
+}
+
+func (m *mockBlockchain) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
+	for _, header := range m.headers {
+		if header.Hash == hash {
+			return header, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m *mockBlockchain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	return m.receipts[hash], nil
+}
+
+// mockConsensus returns the validators of the headers by their number
+type mockConsensus struct {
+	validators func(number uint64) validators.Validators
+}
+
+func (m *mockConsensus) GetEpochSize() uint64 {
+	return testEpochSize
+}
+
+func (m *mockConsensus) GetValidators(header *types.Header) (validators.Validators, error) {
+	return m.validators(header.Number), nil
+}
+
+func testValidators(addrs ...string) validators.Validators {
+	set := validators.NewECDSAValidatorSet()
+
+	for _, addr := range addrs {
+		_ = set.Add(validators.NewECDSAValidator(types.StringToAddress(addr)))
+	}
+
+	return set
+}
+
+// newTestState returns a state of 20 accounts, whose balance and first slot are their index starting from 1
+func newTestState() (*itrie.State, types.Hash) {
+	st := itrie.NewState(itrie.NewMemoryStorage())
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 20; i++ {
+		addr := types.BytesToAddress([]byte{byte(i + 1)})
+		txn.SetBalance(addr, big.NewInt(int64(i+1)))
+		txn.SetState(addr, types.BytesToHash([]byte{1}), types.BytesToHash([]byte{byte(i + 1)}))
+	}
+
+	_, root := st.NewSnapshot().Commit(txn.Commit(false))
+
+	return st, types.BytesToHash(root)
+}
+
+// newTestServer creates a server of a chain of the given number of blocks, whose state and receipts
+// are the same for all the blocks
+func newTestServer(t *testing.T, blocks int, consensus Consensus) *Server {
+	t.Helper()
+
+	st, root := newTestState()
+
+	receipts := make([]*types.Receipt, 30)
+	for i := range receipts {
+		receipts[i] = &types.Receipt{CumulativeGasUsed: uint64(i+1) * 21000, Logs: []*types.Log{}}
+		receipts[i].SetStatus(types.ReceiptSuccess)
+	}
+
+	chain := &mockBlockchain{receipts: make(map[types.Hash][]*types.Receipt)}
+
+	for number := 0; number < blocks; number++ {
+		header := &types.Header{
+			Number:       uint64(number),
+			StateRoot:    root,
+			ReceiptsRoot: buildroot.CalculateReceiptsRoot(receipts),
+		}
+		header.ComputeHash()
+
+		chain.headers = append(chain.headers, header)
+		chain.receipts[header.Hash] = receipts
+	}
+
+	return NewServer(hclog.NewNullLogger(), nil, chain, st, consensus)
+}
+
+// proofState returns a state made only of the proof nodes, so a lookup succeeds only if the proof is complete
+func proofState(proof [][]byte) *itrie.State {
+	storage := itrie.NewMemoryStorage()
+
+	for _, node := range proof {
+		storage.Put(crypto.Keccak256(node), node)
+	}
+
+	return itrie.NewState(storage)
+}
+
+// proveValue returns the value of the key proved against the root
+func proveValue(t *testing.T, root types.Hash, key []byte, proof [][]byte) ([]byte, bool) {
+	t.Helper()
+
+	snap, err := proofState(proof).NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	return snap.Get(key)
+}
+
+func TestServer_GetHeaders(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t, 25, nil)
+
+	res, err := s.GetHeaders(context.Background(), &proto.GetHeaderRangeRequest{From: 5, Count: 10})
+	require.NoError(t, err)
+	require.Len(t, res.Headers, 10)
+
+	header := &types.Header{}
+	require.NoError(t, header.UnmarshalRLP(res.Headers[0]))
+	assert.Equal(t, uint64(5), header.Number)
+
+	// the headers past the head are omitted
+	res, err = s.GetHeaders(context.Background(), &proto.GetHeaderRangeRequest{From: 20, Count: 10})
+	require.NoError(t, err)
+	assert.Len(t, res.Headers, 5)
+
+	_, err = s.GetHeaders(context.Background(), &proto.GetHeaderRangeRequest{Count: maxHeadersPerRequest + 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// the epoch size is unknown without the consensus
+	st, err := s.GetHead(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), st.EpochSize)
+	assert.Equal(t, s.blockchain.Header().MarshalRLP(), st.Header)
+}
+
+func TestServer_GetValidatorTransitions(t *testing.T) {
+	t.Parallel()
+
+	// the validator set changes in the third and fifth epochs
+	consensus := &mockConsensus{
+		validators: func(number uint64) validators.Validators {
+			switch {
+			case number <= 2*testEpochSize:
+				return testValidators("1", "2", "3")
+			case number <= 4*testEpochSize:
+				return testValidators("1", "2", "3", "4")
+			default:
+				return testValidators("2", "3", "4")
+			}
+		},
+	}
+
+	s := newTestServer(t, 5*testEpochSize+3, consensus)
+
+	res, err := s.GetValidatorTransitions(context.Background(), &proto.GetValidatorTransitionsRequest{FromEpoch: 1})
+	require.NoError(t, err)
+	require.Len(t, res.Transitions, 3)
+	assert.Equal(t, uint64(0), res.NextEpoch)
+
+	for i, epoch := range []uint64{1, 3, 5} {
+		transition := res.Transitions[i]
+		assert.Equal(t, epoch, transition.Epoch)
+		assert.Equal(t, string(validators.ECDSAValidatorType), transition.ValidatorType)
+
+		header := &types.Header{}
+		require.NoError(t, header.UnmarshalRLP(transition.Header))
+		assert.Equal(t, (epoch-1)*testEpochSize+1, header.Number)
+
+		vals := validators.NewECDSAValidatorSet()
+		require.NoError(t, vals.UnmarshalRLPFrom(&fastrlp.Parser{}, mustParse(t, transition.Validators)))
+		assert.True(t, vals.Equal(consensus.validators(header.Number)))
+	}
+
+	// the first searched epoch is always returned
+	res, err = s.GetValidatorTransitions(context.Background(), &proto.GetValidatorTransitionsRequest{FromEpoch: 4})
+	require.NoError(t, err)
+	require.Len(t, res.Transitions, 2)
+	assert.Equal(t, uint64(4), res.Transitions[0].Epoch)
+
+	_, err = s.GetValidatorTransitions(context.Background(), &proto.GetValidatorTransitionsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// the transitions aren't served without the consensus
+	_, err = newTestServer(t, 1, nil).GetValidatorTransitions(
+		context.Background(),
+		&proto.GetValidatorTransitionsRequest{FromEpoch: 1},
+	)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func mustParse(t *testing.T, buf []byte) *fastrlp.Value {
+	t.Helper()
+
+	v, err := (&fastrlp.Parser{}).Parse(buf)
+	require.NoError(t, err)
+
+	return v
+}
+
+func TestServer_GetAccountProof(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t, 3, nil)
+	header := s.blockchain.Header()
+
+	var (
+		addr    = types.BytesToAddress([]byte{7})
+		missing = types.StringToAddress("dead")
+		slot    = types.BytesToHash([]byte{1})
+	)
+
+	res, err := s.GetAccountProof(context.Background(), &proto.GetAccountProofRequest{
+		Number:      header.Number,
+		Address:     addr.Bytes(),
+		StorageKeys: [][]byte{slot.Bytes(), types.ZeroHash.Bytes()},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.StorageProofs, 2)
+
+	value, ok := proveValue(t, header.StateRoot, crypto.Keccak256(addr.Bytes()), res.AccountProof)
+	require.True(t, ok)
+
+	var account state.Account
+	require.NoError(t, account.UnmarshalRlp(value))
+	assert.Equal(t, big.NewInt(7), account.Balance)
+
+	// the set slot is proved, and the unset one is proved absent
+	value, ok = proveValue(t, account.Root, crypto.Keccak256(slot.Bytes()), res.StorageProofs[0].Nodes)
+	assert.True(t, ok)
+	assert.NotEmpty(t, value)
+
+	_, ok = proveValue(t, account.Root, crypto.Keccak256(types.ZeroHash.Bytes()), res.StorageProofs[1].Nodes)
+	assert.False(t, ok)
+
+	// the missing account is proved absent
+	res, err = s.GetAccountProof(context.Background(), &proto.GetAccountProofRequest{
+		Number:      header.Number,
+		Address:     missing.Bytes(),
+		StorageKeys: [][]byte{slot.Bytes()},
+	})
+	require.NoError(t, err)
+
+	_, ok = proveValue(t, header.StateRoot, crypto.Keccak256(missing.Bytes()), res.AccountProof)
+	assert.False(t, ok)
+	assert.Empty(t, res.StorageProofs[0].Nodes)
+
+	_, err = s.GetAccountProof(context.Background(), &proto.GetAccountProofRequest{Address: []byte{1}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.GetAccountProof(context.Background(), &proto.GetAccountProofRequest{Number: 10, Address: addr.Bytes()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_GetReceiptProof(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t, 3, nil)
+	header := s.blockchain.Header()
+
+	res, err := s.GetReceiptProof(context.Background(), &proto.GetReceiptProofRequest{
+		Hash:  header.Hash.Bytes(),
+		Index: 17,
+	})
+	require.NoError(t, err)
+
+	// the receipts are keyed by their RLP encoded index
+	key := (&fastrlp.Arena{}).NewUint(17).MarshalTo(nil)
+
+	value, ok := proveValue(t, header.ReceiptsRoot, key, res.Proof)
+	require.True(t, ok)
+	assert.Equal(t, res.Receipt, value)
+
+	receipt := &types.Receipt{}
+	require.NoError(t, receipt.UnmarshalRLP(res.Receipt))
+	assert.Equal(t, uint64(18*21000), receipt.CumulativeGasUsed)
+
+	_, err = s.GetReceiptProof(context.Background(), &proto.GetReceiptProofRequest{Hash: header.Hash.Bytes(), Index: 30})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.GetReceiptProof(context.Background(), &proto.GetReceiptProofRequest{Hash: types.ZeroHash.Bytes()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.GetReceiptProof(context.Background(), &proto.GetReceiptProofRequest{Hash: []byte{1}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// LightClient is the client API for Light service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LightClient interface {
+	// Returns the latest header of the peer and the size of the epochs of its chain
+	GetHead(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Head, error)
+	// Returns the consecutive canonical headers from the given number, along with their committed seals
+	GetHeaders(ctx context.Context, in *GetHeaderRangeRequest, opts ...grpc.CallOption) (*HeaderRange, error)
+	// Returns the epochs changing the validator set from the given epoch
+	GetValidatorTransitions(ctx context.Context, in *GetValidatorTransitionsRequest, opts ...grpc.CallOption) (*ValidatorTransitions, error)
+	// Returns the proof of an account and of its storage slots in the state at the given block
+	GetAccountProof(ctx context.Context, in *GetAccountProofRequest, opts ...grpc.CallOption) (*AccountProof, error)
+	// Returns the proof of the receipt of a transaction of the given block
+	GetReceiptProof(ctx context.Context, in *GetReceiptProofRequest, opts ...grpc.CallOption) (*ReceiptProof, error)
+}
+
+type lightClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLightClient(cc grpc.ClientConnInterface) LightClient {
+	return &lightClient{cc}
+}
+
+func (c *lightClient) GetHead(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Head, error) {
+	out := new(Head)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetHead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightClient) GetHeaders(ctx context.Context, in *GetHeaderRangeRequest, opts ...grpc.CallOption) (*HeaderRange, error) {
+	out := new(HeaderRange)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetHeaders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightClient) GetValidatorTransitions(ctx context.Context, in *GetValidatorTransitionsRequest, opts ...grpc.CallOption) (*ValidatorTransitions, error) {
+	out := new(ValidatorTransitions)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetValidatorTransitions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightClient) GetAccountProof(ctx context.Context, in *GetAccountProofRequest, opts ...grpc.CallOption) (*AccountProof, error) {
+	out := new(AccountProof)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetAccountProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightClient) GetReceiptProof(ctx context.Context, in *GetReceiptProofRequest, opts ...grpc.CallOption) (*ReceiptProof, error) {
+	out := new(ReceiptProof)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetReceiptProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LightServer is the server API for Light service.
+// All implementations must embed UnimplementedLightServer
+// for forward compatibility
+type LightServer interface {
+	// Returns the latest header of the peer and the size of the epochs of its chain
+	GetHead(context.Context, *emptypb.Empty) (*Head, error)
+	// Returns the consecutive canonical headers from the given number, along with their committed seals
+	GetHeaders(context.Context, *GetHeaderRangeRequest) (*HeaderRange, error)
+	// Returns the epochs changing the validator set from the given epoch
+	GetValidatorTransitions(context.Context, *GetValidatorTransitionsRequest) (*ValidatorTransitions, error)
+	// Returns the proof of an account and of its storage slots in the state at the given block
+	GetAccountProof(context.Context, *GetAccountProofRequest) (*AccountProof, error)
+	// Returns the proof of the receipt of a transaction of the given block
+	GetReceiptProof(context.Context, *GetReceiptProofRequest) (*ReceiptProof, error)
+	mustEmbedUnimplementedLightServer()
+}
+
+// UnimplementedLightServer must be embedded to have forward compatible implementations.
+type UnimplementedLightServer struct {
+}
+
+func (UnimplementedLightServer) GetHead(context.Context, *emptypb.Empty) (*Head, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHead not implemented")
+}
+func (UnimplementedLightServer) GetHeaders(context.Context, *GetHeaderRangeRequest) (*HeaderRange, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeaders not implemented")
+}
+func (UnimplementedLightServer) GetValidatorTransitions(context.Context, *GetValidatorTransitionsRequest) (*ValidatorTransitions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetValidatorTransitions not implemented")
+}
+func (UnimplementedLightServer) GetAccountProof(context.Context, *GetAccountProofRequest) (*AccountProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountProof not implemented")
+}
+func (UnimplementedLightServer) GetReceiptProof(context.Context, *GetReceiptProofRequest) (*ReceiptProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceiptProof not implemented")
+}
+func (UnimplementedLightServer) mustEmbedUnimplementedLightServer() {}
+
+// UnsafeLightServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LightServer will
+// result in compilation errors.
+type UnsafeLightServer interface {
+	mustEmbedUnimplementedLightServer()
+}
+
+func RegisterLightServer(s grpc.ServiceRegistrar, srv LightServer) {
+	s.RegisterService(&Light_ServiceDesc, srv)
+}
+
+func _Light_GetHead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetHead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetHead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetHead(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Light_GetHeaders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHeaderRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetHeaders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetHeaders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetHeaders(ctx, req.(*GetHeaderRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Light_GetValidatorTransitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValidatorTransitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetValidatorTransitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetValidatorTransitions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetValidatorTransitions(ctx, req.(*GetValidatorTransitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Light_GetAccountProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetAccountProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetAccountProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetAccountProof(ctx, req.(*GetAccountProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Light_GetReceiptProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReceiptProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetReceiptProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetReceiptProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetReceiptProof(ctx, req.(*GetReceiptProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Light_ServiceDesc is the grpc.ServiceDesc for Light service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Light_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.Light",
+	HandlerType: (*LightServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHead",
+			Handler:    _Light_GetHead_Handler,
+		},
+		{
+			MethodName: "GetHeaders",
+			Handler:    _Light_GetHeaders_Handler,
+		},
+		{
+			MethodName: "GetValidatorTransitions",
+			Handler:    _Light_GetValidatorTransitions_Handler,
+		},
+		{
+			MethodName: "GetAccountProof",
+			Handler:    _Light_GetAccountProof_Handler,
+		},
+		{
+			MethodName: "GetReceiptProof",
+			Handler:    _Light_GetReceiptProof_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "light/proto/light.proto",
+}
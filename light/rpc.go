@@ -0,0 +1,273 @@
+package light
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	jsonRPCVersion = "2.0"
+
+	// maxRequestSize is the maximum size of the body of a JSON-RPC request
+	maxRequestSize = 1 << 20
+)
+
+// header is the JSON-RPC representation of a block, without its transactions as the light client only
+// follows the headers
+type header struct {
+	ParentHash   types.Hash    `json:"parentHash"`
+	Sha3Uncles   types.Hash    `json:"sha3Uncles"`
+	Miner        string        `json:"miner"`
+	StateRoot    types.Hash    `json:"stateRoot"`
+	TxRoot       types.Hash    `json:"transactionsRoot"`
+	ReceiptsRoot types.Hash    `json:"receiptsRoot"`
+	LogsBloom    types.Bloom   `json:"logsBloom"`
+	Difficulty   string        `json:"difficulty"`
+	Number       string        `json:"number"`
+	GasLimit     string        `json:"gasLimit"`
+	GasUsed      string        `json:"gasUsed"`
+	Timestamp    string        `json:"timestamp"`
+	ExtraData    string        `json:"extraData"`
+	MixHash      types.Hash    `json:"mixHash"`
+	Nonce        types.Nonce   `json:"nonce"`
+	Hash         types.Hash    `json:"hash"`
+	Transactions []interface{} `json:"transactions"`
+	Uncles       []types.Hash  `json:"uncles"`
+}
+
+func toHeader(h *types.Header) *header {
+	return &header{
+		ParentHash:   h.ParentHash,
+		Sha3Uncles:   h.Sha3Uncles,
+		Miner:        hex.EncodeToHex(h.Miner),
+		StateRoot:    h.StateRoot,
+		TxRoot:       h.TxRoot,
+		ReceiptsRoot: h.ReceiptsRoot,
+		LogsBloom:    h.LogsBloom,
+		Difficulty:   hex.EncodeUint64(h.Difficulty),
+		Number:       hex.EncodeUint64(h.Number),
+		GasLimit:     hex.EncodeUint64(h.GasLimit),
+		GasUsed:      hex.EncodeUint64(h.GasUsed),
+		Timestamp:    hex.EncodeUint64(h.Timestamp),
+		ExtraData:    hex.EncodeToHex(h.ExtraData),
+		MixHash:      h.MixHash,
+		Nonce:        h.Nonce,
+		Hash:         h.Hash,
+		Transactions: []interface{}{},
+		Uncles:       []types.Hash{},
+	}
+}
+
+// RPC serves the subset of the eth JSON-RPC namespace answerable from the verified headers and the proved state
+type RPC struct {
+	client  *Client
+	chainID uint64
+}
+
+// NewRPC creates the JSON-RPC handler of the light client
+func NewRPC(client *Client, chainID uint64) *RPC {
+	return &RPC{
+		client:  client,
+		chainID: chainID,
+	}
+}
+
+// ServeHTTP handles the single JSON-RPC requests sent by POST
+func (r *RPC) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	var request jsonrpc.Request
+	if err := json.Unmarshal(body, &request); err != nil {
+		r.write(w, jsonrpc.NewRPCResponse(nil, jsonRPCVersion, nil, jsonrpc.NewInvalidRequestError("invalid json request")))
+
+		return
+	}
+
+	result, rpcErr := r.handle(req.Context(), &request)
+	if rpcErr != nil {
+		r.write(w, jsonrpc.NewRPCResponse(request.ID, jsonRPCVersion, nil, rpcErr))
+
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		r.write(w, jsonrpc.NewRPCResponse(request.ID, jsonRPCVersion, nil, jsonrpc.NewInternalError(err.Error())))
+
+		return
+	}
+
+	r.write(w, jsonrpc.NewRPCResponse(request.ID, jsonRPCVersion, data, nil))
+}
+
+func (r *RPC) write(w http.ResponseWriter, response jsonrpc.Response) {
+	data, err := response.Bytes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	_, _ = w.Write(data)
+}
+
+func (r *RPC) handle(ctx context.Context, req *jsonrpc.Request) (interface{}, jsonrpc.Error) {
+	switch req.Method {
+	case "eth_chainId":
+		return hex.EncodeUint64(r.chainID), nil
+	case "net_version":
+		return fmt.Sprintf("%d", r.chainID), nil
+	case "eth_blockNumber":
+		head := r.client.Head()
+		if head == nil {
+			return nil, jsonrpc.NewInternalError(ErrNotSynced.Error())
+		}
+
+		return hex.EncodeUint64(head.Number), nil
+	case "eth_getBlockByNumber":
+		var (
+			number jsonrpc.BlockNumber
+			fullTx bool
+		)
+
+		if err := parseParams(req.Params, &number, &fullTx); err != nil {
+			return nil, err
+		}
+
+		h, err := r.header(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+
+		return toHeader(h), nil
+	case "eth_getBalance", "eth_getTransactionCount":
+		var (
+			addr   types.Address
+			number jsonrpc.BlockNumber
+		)
+
+		if err := parseParams(req.Params, &addr, &number); err != nil {
+			return nil, err
+		}
+
+		h, rpcErr := r.header(ctx, number)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		account, err := r.client.GetAccount(ctx, h, addr)
+		if err != nil {
+			return nil, jsonrpc.NewInternalError(err.Error())
+		}
+
+		if req.Method == "eth_getBalance" {
+			return hex.EncodeBig(account.Balance), nil
+		}
+
+		return hex.EncodeUint64(account.Nonce), nil
+	case "eth_getStorageAt":
+		var (
+			addr   types.Address
+			slot   types.Hash
+			number jsonrpc.BlockNumber
+		)
+
+		if err := parseParams(req.Params, &addr, &slot, &number); err != nil {
+			return nil, err
+		}
+
+		h, rpcErr := r.header(ctx, number)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		value, err := r.client.GetStorage(ctx, h, addr, slot)
+		if err != nil {
+			return nil, jsonrpc.NewInternalError(err.Error())
+		}
+
+		return value, nil
+	default:
+		return nil, jsonrpc.NewMethodNotFoundError(req.Method)
+	}
+}
+
+// header returns the verified header of the block, the tags resolving to the head
+func (r *RPC) header(ctx context.Context, number jsonrpc.BlockNumber) (*types.Header, jsonrpc.Error) {
+	var (
+		h   *types.Header
+		err error
+	)
+
+	switch number {
+	case jsonrpc.LatestBlockNumber, jsonrpc.PendingBlockNumber,
+		jsonrpc.SafeBlockNumber, jsonrpc.FinalizedBlockNumber:
+		if h = r.client.Head(); h == nil {
+			err = ErrNotSynced
+		}
+	case jsonrpc.EarliestBlockNumber:
+		h, err = r.client.GetHeader(ctx, 0)
+	default:
+		h, err = r.client.GetHeader(ctx, uint64(number))
+	}
+
+	if err != nil {
+		return nil, jsonrpc.NewInternalError(err.Error())
+	}
+
+	return h, nil
+}
+
+// parseParams decodes the positional params, the missing trailing block number defaulting to the latest one
+func parseParams(raw json.RawMessage, params ...interface{}) jsonrpc.Error {
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return jsonrpc.NewInvalidParamsError("invalid params")
+	}
+
+	if len(values) > len(params) {
+		return jsonrpc.NewInvalidParamsError(fmt.Sprintf("too many params, expected %d", len(params)))
+	}
+
+	for i, param := range params {
+		if i >= len(values) {
+			if number, ok := param.(*jsonrpc.BlockNumber); ok {
+				*number = jsonrpc.LatestBlockNumber
+
+				continue
+			}
+
+			if _, ok := param.(*bool); ok {
+				continue
+			}
+
+			return jsonrpc.NewInvalidParamsError(fmt.Sprintf("missing param %d", i))
+		}
+
+		if err := json.Unmarshal(values[i], param); err != nil {
+			return jsonrpc.NewInvalidParamsError(fmt.Sprintf("invalid param %d: %v", i, err))
+		}
+	}
+
+	return nil
+}
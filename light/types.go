@@ -0,0 +1,40 @@
+package light
+
+import (
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+type Blockchain interface {
+	// Header returns get latest header
+	Header() *types.Header
+	// GetHeaderByNumber returns the header by number
+	GetHeaderByNumber(uint64) (*types.Header, bool)
+	// GetHeaderByHash returns the header by hash
+	GetHeaderByHash(types.Hash) (*types.Header, bool)
+	// GetReceiptsByHash returns the receipts of the block by its hash
+	GetReceiptsByHash(types.Hash) ([]*types.Receipt, error)
+}
+
+type Network interface {
+	// RegisterProtocol registers gRPC service
+	RegisterProtocol(string, network.Protocol)
+}
+
+type State interface {
+	// NewSnapshotAt returns the snapshot of the state with the given root
+	NewSnapshotAt(types.Hash) (state.Snapshot, error)
+	// Prove returns the trie nodes on the path of the key in the trie with the given root
+	Prove(root types.Hash, key []byte) ([][]byte, error)
+}
+
+// Consensus provides the validator sets of the chain, it's implemented by the consensus
+// whose headers are sealed by the validators
+type Consensus interface {
+	// GetEpochSize returns the number of the blocks of an epoch
+	GetEpochSize() uint64
+	// GetValidators returns the validators sealing the header
+	GetValidators(header *types.Header) (validators.Validators, error)
+}
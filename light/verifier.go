@@ -0,0 +1,138 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/signer"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+var (
+	ErrInvalidSeals        = errors.New("invalid committed seals")
+	ErrUntrustedValidators = errors.New("validators not vouched for by the trusted validators")
+)
+
+// verifier verifies the committed seals of the headers, without any validator key
+type verifier struct {
+	quorumSizeBlockNum uint64
+	validatorType      func(height uint64) validators.ValidatorType
+	keyManagers        map[validators.ValidatorType]signer.KeyManager
+}
+
+func newVerifier(quorumSizeBlockNum uint64, validatorType func(height uint64) validators.ValidatorType) *verifier {
+	v := &verifier{
+		quorumSizeBlockNum: quorumSizeBlockNum,
+		validatorType:      validatorType,
+		keyManagers:        make(map[validators.ValidatorType]signer.KeyManager),
+	}
+
+	for _, validatorType := range []validators.ValidatorType{
+		validators.ECDSAValidatorType,
+		validators.BLSValidatorType,
+	} {
+		v.keyManagers[validatorType], _ = signer.NewVerifierKeyManager(validatorType)
+	}
+
+	return v
+}
+
+// signer returns the signer of the header, whose parent committed seals are the ones of the parent validators
+func (v *verifier) signer(number uint64) (*signer.SignerImpl, error) {
+	parentNumber := number
+	if number > 0 {
+		parentNumber--
+	}
+
+	keyManager, ok := v.keyManagers[v.validatorType(number)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", validators.ErrInvalidValidatorType, v.validatorType(number))
+	}
+
+	parentKeyManager, ok := v.keyManagers[v.validatorType(parentNumber)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", validators.ErrInvalidValidatorType, v.validatorType(parentNumber))
+	}
+
+	return signer.NewSigner(keyManager, parentKeyManager), nil
+}
+
+// hashHeader sets the hash of the header, which covers its validators but not its committed seals
+func (v *verifier) hashHeader(header *types.Header) error {
+	s, err := v.signer(header.Number)
+	if err != nil {
+		return err
+	}
+
+	if header.Hash, err = s.CalculateHeaderHash(header); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validators returns the validators listed in the extra of the header
+func (v *verifier) validators(header *types.Header) (validators.Validators, error) {
+	s, err := v.signer(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetValidators(header)
+}
+
+// verifyHeader verifies the header is sealed by a quorum of the validators listed in its extra, and returns them.
+// The validators differing from the trusted ones are accepted only if the seals of more trusted validators
+// than the trusted set tolerates faulty are among the seals of the header, so an honest one vouches for them
+func (v *verifier) verifyHeader(
+	header *types.Header,
+	trusted validators.Validators,
+) (validators.Validators, error) {
+	s, err := v.signer(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.hashHeader(header); err != nil {
+		return nil, err
+	}
+
+	vals, err := s.GetValidators(header)
+	if err != nil {
+		return nil, err
+	}
+
+	quorumFn := ibft.OptimalQuorumSize
+	if header.Number < v.quorumSizeBlockNum {
+		quorumFn = ibft.LegacyQuorumSize
+	}
+
+	if err := s.VerifyCommittedSeals(header, vals, quorumFn(vals)); err != nil {
+		return nil, fmt.Errorf("%w: block %d, %v", ErrInvalidSeals, header.Number, err)
+	}
+
+	if vals.Equal(trusted) {
+		return vals, nil
+	}
+
+	signers, err := s.GetCommittedSealSigners(header, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	vouched := 0
+
+	for _, addr := range signers {
+		if trusted.Includes(addr) {
+			vouched++
+		}
+	}
+
+	if vouched <= ibft.CalcMaxFaultyNodes(trusted) {
+		return nil, fmt.Errorf("%w: block %d, %d trusted seals", ErrUntrustedValidators, header.Number, vouched)
+	}
+
+	return vals, nil
+}
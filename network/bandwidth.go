@@ -0,0 +1,22 @@
+package network
+
+import (
+	p2pMetrics "github.com/libp2p/go-libp2p/core/metrics"
+
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
+)
+
+// newBandwidthCounter creates the counter of the traffic of the libp2p streams,
+// whose totals by protocol are exported on the metrics endpoint
+func newBandwidthCounter() *p2pMetrics.BandwidthCounter {
+	counter := p2pMetrics.NewBandwidthCounter()
+
+	telemetry.NetworkBandwidth.SetSource(func(emit func(value float64, labels ...string)) {
+		for protocol, stats := range counter.GetBandwidthByProtocol() {
+			emit(float64(stats.TotalIn), "in", string(protocol))
+			emit(float64(stats.TotalOut), "out", string(protocol))
+		}
+	})
+
+	return counter
+}
@@ -0,0 +1,129 @@
+package network
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// bootnodeTreesRefreshInterval is the interval at which the bootnode trees are resolved again,
+	// so the rotated bootnodes are picked up without restarting the node
+	bootnodeTreesRefreshInterval = 30 * time.Minute
+
+	// bootnodeTreesResolveTimeout is the time the resolution of all the bootnode trees can take
+	bootnodeTreesResolveTimeout = time.Minute
+)
+
+// resolveBootnodeTrees resolves the bootnodes published in the DNS trees. The bootnodes of the trees
+// failing to resolve are the ones last resolved, so a DNS outage doesn't take them down
+func (s *Server) resolveBootnodeTrees() []*peer.AddrInfo {
+	ctx, cancel := context.WithTimeout(context.Background(), bootnodeTreesResolveTimeout)
+	defer cancel()
+
+	bootnodes := make([]*peer.AddrInfo, 0)
+
+	for _, tree := range s.bootnodeTrees {
+		addrs, err := s.treeClient.Resolve(ctx, tree)
+		if err != nil {
+			s.logger.Error("Unable to resolve bootnode tree", "url", tree, "err", err)
+
+			bootnodes = append(bootnodes, s.treeBootnodes[tree]...)
+
+			continue
+		}
+
+		treeBootnodes := make([]*peer.AddrInfo, 0, len(addrs))
+
+		for _, rawAddr := range addrs {
+			bootnode, err := common.StringToAddrInfo(rawAddr)
+			if err != nil {
+				s.logger.Warn("Skipping invalid bootnode from tree", "url", tree, "addr", rawAddr, "err", err)
+
+				continue
+			}
+
+			treeBootnodes = append(treeBootnodes, bootnode)
+		}
+
+		s.treeBootnodes[tree] = treeBootnodes
+		bootnodes = append(bootnodes, treeBootnodes...)
+	}
+
+	return bootnodes
+}
+
+// refreshBootnodes resolves the bootnode trees again, and replaces the bootnodes with
+// the static ones and the resolved ones. The new bootnodes are added to the discovery
+func (s *Server) refreshBootnodes() {
+	if len(s.bootnodeTrees) == 0 {
+		return
+	}
+
+	added := s.setBootnodes(append(s.resolveBootnodeTrees(), s.staticBootnodes...))
+
+	if len(added) == 0 {
+		return
+	}
+
+	s.logger.Info("Bootnodes updated from the trees", "new", len(added), "total", s.bootnodes.getBootnodeCount())
+
+	if s.discovery != nil {
+		s.discovery.ConnectToBootnodes(added)
+	}
+}
+
+// setBootnodes replaces the bootnodes, and returns the ones that weren't bootnodes before
+func (s *Server) setBootnodes(bootnodes []*peer.AddrInfo) []*peer.AddrInfo {
+	var (
+		bootnodesArr = make([]*peer.AddrInfo, 0, len(bootnodes))
+		bootnodesMap = make(map[peer.ID]*peer.AddrInfo, len(bootnodes))
+		added        = make([]*peer.AddrInfo, 0)
+		connCount    int64
+	)
+
+	for _, bootnode := range bootnodes {
+		if bootnode.ID == s.host.ID() {
+			s.logger.Info("Omitting bootnode with same ID as host", "id", bootnode.ID)
+
+			continue
+		}
+
+		if _, ok := bootnodesMap[bootnode.ID]; ok {
+			continue
+		}
+
+		bootnodesArr = append(bootnodesArr, bootnode)
+		bootnodesMap[bootnode.ID] = bootnode
+
+		if !s.bootnodes.isBootnode(bootnode.ID) {
+			added = append(added, bootnode)
+		}
+
+		// the connections to the removed bootnodes aren't counted anymore
+		if s.hasPeer(bootnode.ID) {
+			connCount++
+		}
+	}
+
+	s.bootnodes.setBootnodes(bootnodesArr, bootnodesMap, connCount)
+
+	return added
+}
+
+// keepBootnodeTreesResolved resolves the bootnode trees periodically
+func (s *Server) keepBootnodeTreesResolved() {
+	ticker := time.NewTicker(bootnodeTreesRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.refreshBootnodes()
+		case <-s.closeCh:
+			return
+		}
+	}
+}
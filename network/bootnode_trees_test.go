@@ -0,0 +1,161 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/0xPolygon/polygon-edge/network/dnsdisc"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// treeResolver serves the records of the published bootnode trees
+type treeResolver struct {
+	lock    sync.Mutex
+	records map[string]string
+}
+
+func (r *treeResolver) LookupTXT(_ context.Context, domain string) ([]string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	txt, ok := r.records[domain]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	return []string{txt}, nil
+}
+
+// publish replaces the records of the tree at the domain with the bootnodes
+func (r *treeResolver) publish(t *testing.T, domain string, seq uint64, bootnodes ...*Server) {
+	t.Helper()
+
+	addrs := make([]string, len(bootnodes))
+	for i, bootnode := range bootnodes {
+		addrs[i] = common.AddrInfoToString(bootnode.AddrInfo())
+	}
+
+	records, err := dnsdisc.MakeTree(addrs, nil, seq, treeKey)
+	require.NoError(t, err)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.records = make(map[string]string, len(records))
+
+	for subdomain, txt := range records {
+		if subdomain == "" {
+			r.records[domain] = txt
+		} else {
+			r.records[subdomain+"."+domain] = txt
+		}
+	}
+}
+
+var treeKey, _ = crypto.GenerateECDSAKey()
+
+func bootnodeIDs(srv *Server) []peer.ID {
+	ids := make([]peer.ID, 0)
+	for _, bootnode := range srv.bootnodes.getBootnodes() {
+		ids = append(ids, bootnode.ID)
+	}
+
+	return ids
+}
+
+func TestBootnodeTrees(t *testing.T) {
+	bootnodes, createErr := createServers(2, nil)
+	require.NoError(t, createErr)
+
+	var (
+		domain   = "bootnodes.example.org"
+		treeURL  = (&dnsdisc.URL{Domain: domain, PublicKey: &treeKey.PublicKey}).String()
+		resolver = &treeResolver{}
+	)
+
+	resolver.publish(t, domain, 1, bootnodes[0])
+
+	server, createErr := CreateServer(&CreateServerParams{
+		ServerCallback: func(server *Server) {
+			server.treeClient = dnsdisc.NewClient(hclog.NewNullLogger(), resolver)
+			server.config.Chain.Bootnodes = []string{treeURL}
+		},
+	})
+	require.NoError(t, createErr)
+
+	t.Cleanup(func() {
+		closeTestServers(t, append(bootnodes, server))
+	})
+
+	// the bootnodes of the tree are resolved on start, and connected to
+	assert.Equal(t, []peer.ID{bootnodes[0].host.ID()}, bootnodeIDs(server))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := WaitUntilPeerConnectsTo(ctx, server, bootnodes[0].host.ID())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), server.GetBootnodeConnCount())
+
+	// the rotated bootnodes are picked up when the tree is resolved again
+	resolver.publish(t, domain, 2, bootnodes[1])
+	server.refreshBootnodes()
+
+	assert.Equal(t, []peer.ID{bootnodes[1].host.ID()}, bootnodeIDs(server))
+	assert.False(t, server.bootnodes.isBootnode(bootnodes[0].host.ID()))
+
+	// the connection to the removed bootnode isn't counted anymore
+	assert.Equal(t, int64(0), server.GetBootnodeConnCount())
+
+	// the new bootnode is added to the discovery
+	assert.Contains(t, server.discovery.RoutingTablePeers(), bootnodes[1].host.ID())
+
+	// the last resolved bootnodes are kept while the tree doesn't resolve
+	resolver.lock.Lock()
+	resolver.records = make(map[string]string)
+	resolver.lock.Unlock()
+
+	server.refreshBootnodes()
+
+	assert.Equal(t, []peer.ID{bootnodes[1].host.ID()}, bootnodeIDs(server))
+}
+
+func TestBootnodeTrees_Invalid(t *testing.T) {
+	testTable := []struct {
+		name      string
+		bootnodes []string
+	}{
+		{
+			name:      "tree URL without a domain",
+			bootnodes: []string{"enrtree://AKA3AM6LPBYEUDMVNU3BSVQJ5AD45Y7YPOHJLEF6W26QOE4VTUDPE"},
+		},
+		{
+			name:      "tree URL with an invalid public key",
+			bootnodes: []string{"enrtree://AAAA@bootnodes.example.org"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			_, createErr := CreateServer(&CreateServerParams{
+				ServerCallback: func(server *Server) {
+					server.config.Chain.Bootnodes = testCase.bootnodes
+				},
+			})
+
+			assert.ErrorIs(t, createErr, dnsdisc.ErrInvalidURL, fmt.Sprint(testCase.bootnodes))
+		})
+	}
+}
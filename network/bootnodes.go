@@ -1,12 +1,16 @@
 package network
 
 import (
+	"sync"
 	"sync/atomic"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
 type bootnodesWrapper struct {
+	// lock protects the bootnodes, which are replaced when the bootnode trees are resolved again
+	lock sync.RWMutex
+
 	// bootnodeArr is the array that contains all the bootnode addresses
 	bootnodeArr []*peer.AddrInfo
 
@@ -18,8 +22,11 @@ type bootnodesWrapper struct {
 	bootnodeConnCount int64
 }
 
-// isBootnode checks if the node ID belongs to a set bootnode
+// isBootnode checks if the node ID belongs to a set bootnode [Thread safe]
 func (bw *bootnodesWrapper) isBootnode(nodeID peer.ID) bool {
+	bw.lock.RLock()
+	defer bw.lock.RUnlock()
+
 	_, ok := bw.bootnodesMap[nodeID]
 
 	return ok
@@ -35,13 +42,19 @@ func (bw *bootnodesWrapper) increaseBootnodeConnCount(delta int64) {
 	atomic.AddInt64(&bw.bootnodeConnCount, delta)
 }
 
-// getBootnodes gets all the bootnodes
+// getBootnodes gets all the bootnodes [Thread safe]
 func (bw *bootnodesWrapper) getBootnodes() []*peer.AddrInfo {
+	bw.lock.RLock()
+	defer bw.lock.RUnlock()
+
 	return bw.bootnodeArr
 }
 
-// getBootnodeCount returns the number of set bootnodes
+// getBootnodeCount returns the number of set bootnodes [Thread safe]
 func (bw *bootnodesWrapper) getBootnodeCount() int {
+	bw.lock.RLock()
+	defer bw.lock.RUnlock()
+
 	return len(bw.bootnodeArr)
 }
 
@@ -49,3 +62,18 @@ func (bw *bootnodesWrapper) getBootnodeCount() int {
 func (bw *bootnodesWrapper) hasBootnodes() bool {
 	return bw.getBootnodeCount() > 0
 }
+
+// setBootnodes replaces the bootnodes, along with the number of the connected ones [Thread safe]
+func (bw *bootnodesWrapper) setBootnodes(
+	bootnodeArr []*peer.AddrInfo,
+	bootnodesMap map[peer.ID]*peer.AddrInfo,
+	connCount int64,
+) {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	bw.bootnodeArr = bootnodeArr
+	bw.bootnodesMap = bootnodesMap
+
+	atomic.StoreInt64(&bw.bootnodeConnCount, connCount)
+}
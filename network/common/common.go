@@ -22,6 +22,21 @@ const (
 	IdentityProto = "/id/0.1"
 )
 
+const (
+	// ProtocolVersion is the version of the node's protocols, exchanged on the handshake.
+	// The peers which don't send their version run the version 0
+	ProtocolVersion uint64 = 1
+
+	// MinProtocolVersion is the lowest version of the peers the node stays compatible with
+	MinProtocolVersion uint64 = 0
+
+	// CapabilitySnappy is the capability of the peers accepting the snappy compressed messages
+	CapabilitySnappy = "snappy"
+
+	// CapabilityRequestID is the capability of the peers echoing the ids of the sync requests in their responses
+	CapabilityRequestID = "request-id"
+)
+
 // DNSRegex is a regex string to match against a valid dns/dns4/dns6 addr
 const DNSRegex = `^/?(dns)(4|6)?/[^-|^/][A-Za-z0-9-]([^-|^/]?)+([\\-\\.]{1}[a-z0-9]+)*\\.[A-Za-z]{2,}(/?)$`
 
@@ -0,0 +1,176 @@
+package network
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/golang/snappy"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// gossipSubSnappyID is the gossipsub protocol whose streams are snappy compressed. It's preferred over
+// the plain gossipsub protocols, which are still negotiated with the peers not supporting it
+const gossipSubSnappyID = protocol.ID("/meshsub/1.1.0/snappy")
+
+// peerProtocol is the protocol version and the capabilities the peer sent on the handshake
+type peerProtocol struct {
+	version      uint64
+	capabilities map[string]bool
+}
+
+// SetPeerProtocol saves the protocol version and the capabilities the peer sent on the handshake [Thread safe]
+func (s *Server) SetPeerProtocol(peerID peer.ID, version uint64, capabilities []string) {
+	p := &peerProtocol{
+		version:      version,
+		capabilities: make(map[string]bool, len(capabilities)),
+	}
+
+	for _, capability := range capabilities {
+		p.capabilities[capability] = true
+	}
+
+	s.logger.Debug("peer protocol negotiated", "id", peerID, "version", version, "capabilities", capabilities)
+
+	s.peerProtocols.Store(peerID, p)
+}
+
+// PeerProtocolVersion returns the protocol version of the peer, false if the peer didn't complete the handshake
+func (s *Server) PeerProtocolVersion(peerID peer.ID) (uint64, bool) {
+	p, ok := s.getPeerProtocol(peerID)
+	if !ok {
+		return 0, false
+	}
+
+	return p.version, true
+}
+
+// SupportsCapability checks if the peer sent the capability on the handshake [Thread safe]
+func (s *Server) SupportsCapability(peerID peer.ID, capability string) bool {
+	p, ok := s.getPeerProtocol(peerID)
+
+	return ok && p.capabilities[capability]
+}
+
+func (s *Server) getPeerProtocol(peerID peer.ID) (*peerProtocol, bool) {
+	value, ok := s.peerProtocols.Load(peerID)
+	if !ok {
+		return nil, false
+	}
+
+	p, ok := value.(*peerProtocol)
+
+	return p, ok
+}
+
+// capabilities returns the capabilities of the node, sent to the peers on the handshake
+func (s *Server) capabilities() []string {
+	if s.config.NoCompression {
+		return []string{common.CapabilityRequestID}
+	}
+
+	return []string{common.CapabilitySnappy, common.CapabilityRequestID}
+}
+
+// gossipSubProtocolsOption prepends the snappy gossipsub protocol to the default ones,
+// with the features of the gossipsub protocol it compresses
+func gossipSubProtocolsOption() pubsub.Option {
+	protocols := append([]protocol.ID{gossipSubSnappyID}, pubsub.GossipSubDefaultProtocols...)
+
+	return pubsub.WithGossipSubProtocols(protocols, func(feature pubsub.GossipSubFeature, id protocol.ID) bool {
+		if id == gossipSubSnappyID {
+			id = pubsub.GossipSubID_v11
+		}
+
+		return pubsub.GossipSubDefaultFeatures(feature, id)
+	})
+}
+
+// compressionHost is a host whose streams of the compressed protocols are snappy compressed
+type compressionHost struct {
+	host.Host
+
+	compressed map[protocol.ID]bool
+}
+
+func newCompressionHost(h host.Host, compressed ...protocol.ID) *compressionHost {
+	ch := &compressionHost{
+		Host:       h,
+		compressed: make(map[protocol.ID]bool, len(compressed)),
+	}
+
+	for _, id := range compressed {
+		ch.compressed[id] = true
+	}
+
+	return ch
+}
+
+func (h *compressionHost) SetStreamHandler(id protocol.ID, handler network.StreamHandler) {
+	h.Host.SetStreamHandler(id, h.wrapHandler(handler))
+}
+
+func (h *compressionHost) SetStreamHandlerMatch(
+	id protocol.ID,
+	match func(string) bool,
+	handler network.StreamHandler,
+) {
+	h.Host.SetStreamHandlerMatch(id, match, h.wrapHandler(handler))
+}
+
+// NewStream opens a stream of the first protocol the peer supports, compressed if the protocol is
+func (h *compressionHost) NewStream(
+	ctx context.Context,
+	peerID peer.ID,
+	ids ...protocol.ID,
+) (network.Stream, error) {
+	stream, err := h.Host.NewStream(ctx, peerID, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.wrapStream(stream), nil
+}
+
+func (h *compressionHost) wrapHandler(handler network.StreamHandler) network.StreamHandler {
+	return func(stream network.Stream) {
+		handler(h.wrapStream(stream))
+	}
+}
+
+func (h *compressionHost) wrapStream(stream network.Stream) network.Stream {
+	if !h.compressed[stream.Protocol()] {
+		return stream
+	}
+
+	return &snappyStream{
+		Stream: stream,
+		reader: snappy.NewReader(stream),
+		writer: snappy.NewBufferedWriter(stream),
+	}
+}
+
+// snappyStream is a stream whose data is snappy framed
+type snappyStream struct {
+	network.Stream
+
+	reader *snappy.Reader
+	writer *snappy.Writer
+}
+
+func (s *snappyStream) Read(b []byte) (int, error) {
+	return s.reader.Read(b)
+}
+
+// Write compresses the data and flushes it, the writes are already buffered by the protocols
+func (s *snappyStream) Write(b []byte) (int, error) {
+	n, err := s.writer.Write(b)
+	if err != nil {
+		return n, err
+	}
+
+	return n, s.writer.Flush()
+}
@@ -0,0 +1,165 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/0xPolygon/polygon-edge/network/grpc"
+	testproto "github.com/0xPolygon/polygon-edge/network/proto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rawGrpc "google.golang.org/grpc"
+)
+
+const compressionTestProto = "/compression-test/0.1"
+
+// compressionTestService replies with the compressor of the request
+type compressionTestService struct {
+	testproto.UnimplementedTestServiceServer
+}
+
+func (s *compressionTestService) SayHello(
+	ctx context.Context,
+	_ *testproto.GenericMessage,
+) (*testproto.GenericMessage, error) {
+	stream, _ := rawGrpc.ServerTransportStreamFromContext(ctx).(interface{ RecvCompress() string })
+	if stream == nil {
+		return &testproto.GenericMessage{}, nil
+	}
+
+	return &testproto.GenericMessage{Message: stream.RecvCompress()}, nil
+}
+
+// gossipProtocol returns the gossipsub protocol of the streams from the server to the peer
+func gossipProtocol(srv *Server, peerID peer.ID) protocol.ID {
+	for _, conn := range srv.host.Network().ConnsToPeer(peerID) {
+		for _, stream := range conn.GetStreams() {
+			if id := stream.Protocol(); strings.HasPrefix(string(id), "/meshsub/") {
+				return id
+			}
+		}
+	}
+
+	return ""
+}
+
+func TestCompressionNegotiation(t *testing.T) {
+	servers, createErr := createServers(3, map[int]*CreateServerParams{
+		2: {
+			ConfigCallback: func(c *Config) {
+				c.NoCompression = true
+			},
+		},
+	})
+	require.NoError(t, createErr)
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	for _, srv := range servers {
+		grpcStream := grpc.NewGrpcStream()
+		testproto.RegisterTestServiceServer(grpcStream.GrpcServer(), &compressionTestService{})
+		grpcStream.Serve()
+
+		srv.RegisterProtocol(compressionTestProto, grpcStream)
+	}
+
+	require.Empty(t, MeshJoin(servers...))
+
+	var (
+		compressed = servers[1].host.ID()
+		legacy     = servers[2].host.ID()
+	)
+
+	// the capabilities are exchanged on the handshake
+	assert.True(t, servers[0].SupportsCapability(compressed, common.CapabilitySnappy))
+	assert.False(t, servers[0].SupportsCapability(legacy, common.CapabilitySnappy))
+	assert.True(t, servers[0].SupportsCapability(legacy, common.CapabilityRequestID))
+
+	version, ok := servers[0].PeerProtocolVersion(legacy)
+	assert.True(t, ok)
+	assert.Equal(t, common.ProtocolVersion, version)
+
+	// the requests are compressed for the peers supporting it
+	for peerID, compressor := range map[peer.ID]string{compressed: grpc.SnappyCompressor, legacy: ""} {
+		conn, err := servers[0].NewProtoConnection(compressionTestProto, peerID)
+		require.NoError(t, err)
+
+		res, err := testproto.NewTestServiceClient(conn).SayHello(context.Background(), &testproto.GenericMessage{})
+		require.NoError(t, err)
+
+		assert.Equal(t, compressor, res.Message)
+	}
+
+	// the gossip reaches all the peers, compressed for the peers supporting it
+	type delivery struct {
+		server  int
+		message string
+	}
+
+	topicName := "compression-test"
+	deliveryCh := make(chan delivery, 64)
+	topics := make([]*Topic, len(servers))
+
+	for i, srv := range servers {
+		i := i
+
+		topic, err := srv.NewTopic(topicName, &testproto.GenericMessage{})
+		require.NoError(t, err)
+
+		require.NoError(t, topic.Subscribe(func(obj interface{}, _ peer.ID) {
+			if msg, ok := obj.(*testproto.GenericMessage); ok {
+				select {
+				case deliveryCh <- delivery{server: i, message: msg.Message}:
+				default:
+				}
+			}
+		}))
+
+		topics[i] = topic
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, srv := range servers {
+		require.NoError(t, WaitForSubscribers(ctx, srv, topicName, len(servers)-1))
+	}
+
+	for _, publisher := range []int{0, 2} {
+		var (
+			prefix   = fmt.Sprintf("%d:", publisher)
+			received = make(map[int]bool)
+			timeout  = time.After(15 * time.Second)
+		)
+
+		// the message is published again until the peers joined the mesh and all of them got it
+		for len(received) < len(servers) {
+			message := prefix + strings.Repeat("a", 1024)
+			require.NoError(t, topics[publisher].Publish(&testproto.GenericMessage{Message: message}))
+
+			select {
+			case d := <-deliveryCh:
+				if strings.HasPrefix(d.message, prefix) {
+					assert.Equal(t, message, d.message)
+
+					received[d.server] = true
+				}
+			case <-time.After(500 * time.Millisecond):
+			case <-timeout:
+				t.Fatalf("gossip from server %d not received before timeout, %d/%d", publisher, len(received), len(servers))
+			}
+		}
+	}
+
+	assert.Equal(t, gossipSubSnappyID, gossipProtocol(servers[0], compressed))
+	assert.Equal(t, protocol.ID(pubsub.GossipSubID_v11), gossipProtocol(servers[0], legacy))
+}
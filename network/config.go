@@ -2,6 +2,7 @@ package network
 
 import (
 	"net"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/secrets"
@@ -13,6 +14,7 @@ type Config struct {
 	NoDiscover       bool                   // flag indicating if the discovery mechanism should be turned on
 	Addr             *net.TCPAddr           // the base address
 	NatAddr          net.IP                 // the NAT address
+	NATMapping       NATMapping             // the protocol mapping the port on the NAT gateway
 	DNS              multiaddr.Multiaddr    // the DNS address
 	DataDir          string                 // the base data directory for the client
 	MaxPeers         int64                  // the maximum number of peer connections
@@ -20,6 +22,9 @@ type Config struct {
 	MaxOutboundPeers int64                  // the maximum number of outbound peer connections
 	Chain            *chain.Chain           // the reference to the chain configuration
 	SecretsManager   secrets.SecretsManager // the secrets manager used for key storage
+	BanDuration      time.Duration          // the time a peer is banned for once its reputation is too low
+	StaticPeers      []string               // the multiaddrs of the peers always kept connected
+	NoCompression    bool                   // flag indicating if the messages shouldn't be compressed for the peers
 }
 
 func DefaultConfig() *Config {
@@ -36,5 +41,6 @@ func DefaultConfig() *Config {
 		// The default ratio for outbound / inbound connections is 0.25
 		MaxInboundPeers:  32,
 		MaxOutboundPeers: 8,
+		BanDuration:      DefaultBanDuration,
 	}
 }
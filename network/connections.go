@@ -97,16 +97,21 @@ func (ci *ConnectionInfo) HasFreeInboundConn() bool {
 	return ci.GetInboundConnCount()+ci.GetPendingInboundConnCount() < ci.maxInboundConnCount()
 }
 
-// maxOutboundConnCount returns the maximum number of outbound connections.
-// [Thread safe] since this value is unchanged during runtime
+// maxOutboundConnCount returns the maximum number of outbound connections [Thread safe]
 func (ci *ConnectionInfo) maxOutboundConnCount() int64 {
-	return ci.maxOutboundConnectionCount
+	return atomic.LoadInt64(&ci.maxOutboundConnectionCount)
 }
 
-// maxInboundConnCount returns the minimum number of outbound connections
-// [Thread safe] since this value is unchanged during runtime
+// maxInboundConnCount returns the maximum number of inbound connections [Thread safe]
 func (ci *ConnectionInfo) maxInboundConnCount() int64 {
-	return ci.maxInboundConnectionCount
+	return atomic.LoadInt64(&ci.maxInboundConnectionCount)
+}
+
+// SetMaxConnCounts replaces the connection limits. The connections above the new limits
+// are kept, only the new connections are refused until the counts drop [Thread safe]
+func (ci *ConnectionInfo) SetMaxConnCounts(maxInboundConnCount, maxOutboundConnCount int64) {
+	atomic.StoreInt64(&ci.maxInboundConnectionCount, maxInboundConnCount)
+	atomic.StoreInt64(&ci.maxOutboundConnectionCount, maxOutboundConnCount)
 }
 
 // UpdateConnCountByDirection updates the connection count by delta
@@ -0,0 +1,234 @@
+package dnsdisc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// maxEntries is the maximum number of the records resolved for a tree and its linked trees,
+	// so a misconfigured tree can't make the node query the DNS endlessly
+	maxEntries = 4096
+
+	// maxLinkDepth is the maximum depth of the linked trees followed from the root tree
+	maxLinkDepth = 4
+)
+
+var (
+	ErrMissingRoot       = errors.New("tree root not found")
+	ErrUnexpectedEntry   = errors.New("unexpected tree entry")
+	ErrTooManyEntries    = errors.New("too many tree entries")
+	ErrMissingEntry      = errors.New("tree entry not found")
+	ErrStaleRootSequence = errors.New("tree root sequence decreased")
+)
+
+// Resolver looks up the TXT records of the domains, *net.Resolver by default
+type Resolver interface {
+	LookupTXT(ctx context.Context, domain string) ([]string, error)
+}
+
+// Client resolves the multiaddrs of the trees. The records are addressed by their hashes,
+// so they are cached between the resolutions and only the changed ones are queried again
+type Client struct {
+	logger   hclog.Logger
+	resolver Resolver
+
+	lock    sync.Mutex
+	entries map[string]map[string]entry // cached records by their subdomain, by the resolved URL
+	seqs    map[string]uint64           // last root sequence, by the domain of the tree
+}
+
+// NewClient creates a client resolving the trees with the resolver, the system resolver if nil
+func NewClient(logger hclog.Logger, resolver Resolver) *Client {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return &Client{
+		logger:   logger.Named("dnsdisc"),
+		resolver: resolver,
+		entries:  make(map[string]map[string]entry),
+		seqs:     make(map[string]uint64),
+	}
+}
+
+// resolution is the state of a single resolution of a tree and its linked trees
+type resolution struct {
+	visitedTrees map[string]bool
+	cache        map[string]entry
+	entries      map[string]entry
+	addrs        []string
+}
+
+// Resolve returns the multiaddrs of the nodes of the tree and of the trees it links to.
+// The linked trees failing to resolve are skipped
+func (c *Client) Resolve(ctx context.Context, rawURL string) ([]string, error) {
+	url, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	res := &resolution{
+		visitedTrees: make(map[string]bool),
+		cache:        c.entries[rawURL],
+		entries:      make(map[string]entry),
+		addrs:        make([]string, 0),
+	}
+
+	if err := c.resolveTree(ctx, url, res, 0); err != nil {
+		return nil, err
+	}
+
+	// only the records of the latest trees are kept
+	c.entries[rawURL] = res.entries
+
+	return res.addrs, nil
+}
+
+// resolveTree verifies the root of the tree and walks its subtrees
+func (c *Client) resolveTree(ctx context.Context, url *URL, res *resolution, depth int) error {
+	domain := strings.ToLower(url.Domain)
+	if res.visitedTrees[domain] {
+		return nil
+	}
+
+	res.visitedTrees[domain] = true
+
+	root, err := c.resolveRoot(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	if err := c.walk(ctx, domain, root.nodesRoot, res, depth, false); err != nil {
+		return err
+	}
+
+	if depth >= maxLinkDepth {
+		return nil
+	}
+
+	return c.walk(ctx, domain, root.linksRoot, res, depth, true)
+}
+
+// resolveRoot looks up and verifies the root of the tree
+func (c *Client) resolveRoot(ctx context.Context, url *URL) (*rootEntry, error) {
+	txts, err := c.resolver.LookupTXT(ctx, url.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up the root of %s, %w", url.Domain, err)
+	}
+
+	for _, txt := range txts {
+		if !strings.HasPrefix(txt, rootPrefix) {
+			continue
+		}
+
+		root, err := parseRoot(txt)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := root.verify(url.PublicKey); err != nil {
+			return nil, err
+		}
+
+		// a lower sequence is a replayed root, which could bring back removed nodes
+		domain := strings.ToLower(url.Domain)
+		if root.seq < c.seqs[domain] {
+			return nil, fmt.Errorf("%w: %d < %d", ErrStaleRootSequence, root.seq, c.seqs[domain])
+		}
+
+		c.seqs[domain] = root.seq
+
+		return root, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrMissingRoot, url.Domain)
+}
+
+// walk collects the leaves of the subtree, the linked trees if links is set and the nodes otherwise
+func (c *Client) walk(ctx context.Context, domain, hash string, res *resolution, depth int, links bool) error {
+	if _, ok := res.entries[hash]; ok {
+		return nil
+	}
+
+	if len(res.entries) >= maxEntries {
+		return ErrTooManyEntries
+	}
+
+	e, ok := res.cache[hash]
+	if !ok {
+		var err error
+
+		if e, err = c.resolveEntry(ctx, domain, hash); err != nil {
+			return err
+		}
+	}
+
+	res.entries[hash] = e
+
+	switch e := e.(type) {
+	case *branchEntry:
+		for _, child := range e.children {
+			if err := c.walk(ctx, domain, child, res, depth, links); err != nil {
+				return err
+			}
+		}
+	case *leafEntry:
+		if links {
+			return fmt.Errorf("%w: node in the links of %s", ErrUnexpectedEntry, domain)
+		}
+
+		res.addrs = append(res.addrs, e.addr)
+	case *linkEntry:
+		if !links {
+			return fmt.Errorf("%w: link in the nodes of %s", ErrUnexpectedEntry, domain)
+		}
+
+		url, err := ParseURL(e.url)
+		if err != nil {
+			return err
+		}
+
+		if err := c.resolveTree(ctx, url, res, depth+1); err != nil {
+			c.logger.Warn("unable to resolve linked tree", "url", e.url, "err", err)
+		}
+	}
+
+	return nil
+}
+
+// resolveEntry looks up the record of the subdomain, checked against its hash
+func (c *Client) resolveEntry(ctx context.Context, domain, hash string) (entry, error) {
+	name := hash + "." + domain
+
+	txts, err := c.resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up %s, %w", name, err)
+	}
+
+	for _, txt := range txts {
+		e, err := parseEntry(txt)
+		if errors.Is(err, ErrUnknownEntry) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if hashEntry(e) != hash {
+			return nil, fmt.Errorf("%w: %s", ErrHashMismatch, name)
+		}
+
+		return e, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrMissingEntry, name)
+}
@@ -0,0 +1,258 @@
+package dnsdisc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResolver serves the TXT records of the domains, counting the lookups
+type mockResolver struct {
+	records map[string]string
+	lookups int
+}
+
+func newMockResolver() *mockResolver {
+	return &mockResolver{records: make(map[string]string)}
+}
+
+func (r *mockResolver) LookupTXT(_ context.Context, domain string) ([]string, error) {
+	r.lookups++
+
+	txt, ok := r.records[domain]
+	if !ok {
+		return nil, fmt.Errorf("no such host %s", domain)
+	}
+
+	return []string{txt}, nil
+}
+
+// publish adds the records of the tree at the domain, returning its URL
+func (r *mockResolver) publish(t *testing.T, domain string, addrs, links []string, seq uint64) string {
+	t.Helper()
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	return r.publishWithKey(t, domain, addrs, links, seq, key)
+}
+
+func (r *mockResolver) publishWithKey(
+	t *testing.T,
+	domain string,
+	addrs, links []string,
+	seq uint64,
+	key *ecdsa.PrivateKey,
+) string {
+	t.Helper()
+
+	records, err := MakeTree(addrs, links, seq, key)
+	require.NoError(t, err)
+
+	for subdomain, txt := range records {
+		if subdomain == "" {
+			r.records[domain] = txt
+		} else {
+			r.records[subdomain+"."+domain] = txt
+		}
+	}
+
+	return (&URL{Domain: domain, PublicKey: &key.PublicKey}).String()
+}
+
+func testAddrs(n int) []string {
+	addrs := make([]string, n)
+
+	for i := range addrs {
+		addrs[i] = fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/p2p/16Uiu2HAmJxxH1tScDX2rLGSU9exnuvZKNM9SoK3v315azp68DLPW", 10000+i)
+	}
+
+	sort.Strings(addrs)
+
+	return addrs
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	raw := (&URL{Domain: "nodes.example.org", PublicKey: &key.PublicKey}).String()
+	assert.True(t, IsURL(raw))
+
+	url, err := ParseURL(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, "nodes.example.org", url.Domain)
+	assert.Equal(t, key.PublicKey.X, url.PublicKey.X)
+	assert.Equal(t, key.PublicKey.Y, url.PublicKey.Y)
+
+	for _, invalid := range []string{
+		"/ip4/127.0.0.1/tcp/10001",
+		"enrtree://nodes.example.org",
+		"enrtree://AAAA@nodes.example.org",
+		"enrtree://" + raw[len(linkPrefix):len(raw)-len("@nodes.example.org")] + "@",
+	} {
+		_, err := ParseURL(invalid)
+		assert.ErrorIs(t, err, ErrInvalidURL, invalid)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		addrs []string
+	}{
+		{"single node", testAddrs(1)},
+		{"single branch", testAddrs(maxChildren)},
+		{"nested branches", testAddrs(3*maxChildren*maxChildren + 1)},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := newMockResolver()
+			url := resolver.publish(t, "nodes.example.org", testCase.addrs, nil, 1)
+
+			addrs, err := NewClient(hclog.NewNullLogger(), resolver).Resolve(context.Background(), url)
+			require.NoError(t, err)
+
+			sort.Strings(addrs)
+			assert.Equal(t, testCase.addrs, addrs)
+		})
+	}
+}
+
+func TestResolve_Links(t *testing.T) {
+	t.Parallel()
+
+	var (
+		resolver = newMockResolver()
+		addrs    = testAddrs(4)
+	)
+
+	// the trees link each other, and to a tree not resolving
+	linkedURL := resolver.publish(t, "linked.example.org", addrs[2:], nil, 1)
+	brokenURL := resolver.publish(t, "broken.example.org", addrs[:1], nil, 1)
+	delete(resolver.records, "broken.example.org")
+
+	rootURL := resolver.publish(t, "nodes.example.org", addrs[:2], []string{linkedURL, brokenURL}, 1)
+
+	resolved, err := NewClient(hclog.NewNullLogger(), resolver).Resolve(context.Background(), rootURL)
+	require.NoError(t, err)
+
+	sort.Strings(resolved)
+	assert.Equal(t, addrs, resolved)
+}
+
+func TestResolve_Refresh(t *testing.T) {
+	t.Parallel()
+
+	var (
+		resolver = newMockResolver()
+		client   = NewClient(hclog.NewNullLogger(), resolver)
+		addrs    = testAddrs(2 * maxChildren)
+	)
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	url := resolver.publishWithKey(t, "nodes.example.org", addrs, nil, 1, key)
+
+	_, err = client.Resolve(context.Background(), url)
+	require.NoError(t, err)
+
+	// the unchanged records are served from the cache, only the root is looked up again
+	resolver.lookups = 0
+
+	resolved, err := client.Resolve(context.Background(), url)
+	require.NoError(t, err)
+	assert.Len(t, resolved, len(addrs))
+	assert.Equal(t, 1, resolver.lookups)
+
+	// the rotated nodes are picked up by the next resolution
+	resolver.records = make(map[string]string)
+	resolver.publishWithKey(t, "nodes.example.org", addrs[:3], nil, 2, key)
+
+	resolved, err = client.Resolve(context.Background(), url)
+	require.NoError(t, err)
+
+	sort.Strings(resolved)
+	assert.Equal(t, addrs[:3], resolved)
+
+	// a replayed older root is rejected
+	resolver.records = make(map[string]string)
+	resolver.publishWithKey(t, "nodes.example.org", addrs, nil, 1, key)
+
+	_, err = client.Resolve(context.Background(), url)
+	assert.ErrorIs(t, err, ErrStaleRootSequence)
+}
+
+func TestResolve_Invalid(t *testing.T) {
+	t.Parallel()
+
+	addrs := testAddrs(2)
+
+	testTable := []struct {
+		name   string
+		tamper func(t *testing.T, resolver *mockResolver)
+		err    error
+	}{
+		{
+			name: "root signed by another key",
+			tamper: func(t *testing.T, resolver *mockResolver) {
+				// the root of another tree is served at the domain of the URL
+				resolver.publish(t, "other.example.org", addrs, nil, 1)
+				resolver.records["nodes.example.org"] = resolver.records["other.example.org"]
+			},
+			err: ErrInvalidSignature,
+		},
+		{
+			name: "entry not matching its hash",
+			tamper: func(t *testing.T, resolver *mockResolver) {
+				for domain, txt := range resolver.records {
+					if strings.HasPrefix(txt, leafPrefix) {
+						resolver.records[domain] = leafPrefix + "/ip4/10.0.0.1/tcp/1"
+					}
+				}
+			},
+			err: ErrHashMismatch,
+		},
+		{
+			name: "missing root",
+			tamper: func(t *testing.T, resolver *mockResolver) {
+				resolver.records["nodes.example.org"] = "v=spf1 -all"
+			},
+			err: ErrMissingRoot,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := newMockResolver()
+			url := resolver.publish(t, "nodes.example.org", addrs, nil, 1)
+			testCase.tamper(t, resolver)
+
+			_, err := NewClient(hclog.NewNullLogger(), resolver).Resolve(context.Background(), url)
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package dnsdisc
+
+import (
+	"crypto/ecdsa"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+)
+
+const (
+	rootPrefix   = "enrtree-root:v1"
+	branchPrefix = "enrtree-branch:"
+	linkPrefix   = "enrtree://"
+	leafPrefix   = "multiaddr:"
+
+	// maxChildren is the maximum number of the hashes of a branch, so the record fits a TXT string
+	maxChildren = 13
+
+	// hashLength is the length of the truncated hashes of the records
+	hashLength = 16
+)
+
+var (
+	ErrInvalidURL       = errors.New("invalid tree URL")
+	ErrUnknownEntry     = errors.New("unknown tree entry")
+	ErrInvalidRoot      = errors.New("invalid tree root")
+	ErrInvalidSignature = errors.New("invalid tree root signature")
+	ErrHashMismatch     = errors.New("tree entry doesn't match its hash")
+	ErrInvalidBranch    = errors.New("invalid tree branch")
+)
+
+var (
+	b32format = base32.StdEncoding.WithPadding(base32.NoPadding)
+	b64format = base64.RawURLEncoding
+)
+
+// URL is the location of a tree, made of the domain of its root and the public key signing it.
+// It's formatted as enrtree://<base32 compressed public key>@<domain>
+type URL struct {
+	Domain    string
+	PublicKey *ecdsa.PublicKey
+}
+
+// IsURL checks if the string is the URL of a tree
+func IsURL(s string) bool {
+	return strings.HasPrefix(s, linkPrefix)
+}
+
+// ParseURL parses the URL of a tree
+func ParseURL(s string) (*URL, error) {
+	if !IsURL(s) {
+		return nil, fmt.Errorf("%w: missing %s prefix", ErrInvalidURL, linkPrefix)
+	}
+
+	rawKey, domain, ok := strings.Cut(strings.TrimPrefix(s, linkPrefix), "@")
+	if !ok || domain == "" {
+		return nil, fmt.Errorf("%w: missing domain", ErrInvalidURL)
+	}
+
+	keyBytes, err := b32format.DecodeString(rawKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key encoding, %v", ErrInvalidURL, err)
+	}
+
+	key, err := crypto.ParseCompressedPublicKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key, %v", ErrInvalidURL, err)
+	}
+
+	return &URL{Domain: domain, PublicKey: key}, nil
+}
+
+func (u *URL) String() string {
+	return linkPrefix + b32format.EncodeToString(crypto.MarshalCompressedPublicKey(u.PublicKey)) + "@" + u.Domain
+}
+
+// entry is a record of the tree
+type entry interface {
+	fmt.Stringer
+}
+
+// rootEntry is the signed record at the domain of the tree, referencing the roots of
+// the subtree of the nodes and of the subtree of the links to other trees
+type rootEntry struct {
+	nodesRoot string
+	linksRoot string
+	seq       uint64
+	sig       []byte
+}
+
+func (e *rootEntry) signedText() string {
+	return fmt.Sprintf("%s e=%s l=%s seq=%d", rootPrefix, e.nodesRoot, e.linksRoot, e.seq)
+}
+
+func (e *rootEntry) String() string {
+	return e.signedText() + " sig=" + b64format.EncodeToString(e.sig)
+}
+
+// verify checks the root is signed by the key of the tree
+func (e *rootEntry) verify(key *ecdsa.PublicKey) error {
+	if len(e.sig) != 65 {
+		return ErrInvalidSignature
+	}
+
+	pub, err := crypto.RecoverPubkey(e.sig, crypto.Keccak256([]byte(e.signedText())))
+	if err != nil || pub.X.Cmp(key.X) != 0 || pub.Y.Cmp(key.Y) != 0 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// branchEntry is a record referencing the hashes of its children
+type branchEntry struct {
+	children []string
+}
+
+func (e *branchEntry) String() string {
+	return branchPrefix + strings.Join(e.children, ",")
+}
+
+// leafEntry is a record holding the multiaddr of a node
+type leafEntry struct {
+	addr string
+}
+
+func (e *leafEntry) String() string {
+	return leafPrefix + e.addr
+}
+
+// linkEntry is a record holding the URL of another tree
+type linkEntry struct {
+	url string
+}
+
+func (e *linkEntry) String() string {
+	return e.url
+}
+
+// hashEntry returns the subdomain of the record, the base32 encoded truncated keccak256 hash of its text
+func hashEntry(e entry) string {
+	return b32format.EncodeToString(crypto.Keccak256([]byte(e.String()))[:hashLength])
+}
+
+// parseRoot parses the root record of a tree
+func parseRoot(txt string) (*rootEntry, error) {
+	fields := strings.Fields(txt)
+	if len(fields) != 5 || fields[0] != rootPrefix {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRoot, txt)
+	}
+
+	values := make(map[string]string, 4)
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRoot, txt)
+		}
+
+		values[key] = value
+	}
+
+	seq, err := strconv.ParseUint(values["seq"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid seq, %v", ErrInvalidRoot, err)
+	}
+
+	sig, err := b64format.DecodeString(values["sig"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid sig, %v", ErrInvalidRoot, err)
+	}
+
+	root := &rootEntry{nodesRoot: values["e"], linksRoot: values["l"], seq: seq, sig: sig}
+	if !isHash(root.nodesRoot) || !isHash(root.linksRoot) {
+		return nil, fmt.Errorf("%w: invalid subtree roots", ErrInvalidRoot)
+	}
+
+	return root, nil
+}
+
+// parseEntry parses a record of the subtrees
+func parseEntry(txt string) (entry, error) {
+	switch {
+	case strings.HasPrefix(txt, branchPrefix):
+		children := strings.Split(strings.TrimPrefix(txt, branchPrefix), ",")
+		if len(children) == 1 && children[0] == "" {
+			return &branchEntry{children: []string{}}, nil
+		}
+
+		for _, child := range children {
+			if !isHash(child) {
+				return nil, fmt.Errorf("%w: invalid child %s", ErrInvalidBranch, child)
+			}
+		}
+
+		return &branchEntry{children: children}, nil
+	case strings.HasPrefix(txt, leafPrefix):
+		return &leafEntry{addr: strings.TrimPrefix(txt, leafPrefix)}, nil
+	case IsURL(txt):
+		if _, err := ParseURL(txt); err != nil {
+			return nil, err
+		}
+
+		return &linkEntry{url: txt}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEntry, txt)
+	}
+}
+
+// isHash checks if the string is the base32 encoding of a truncated hash
+func isHash(s string) bool {
+	raw, err := b32format.DecodeString(s)
+
+	return err == nil && len(raw) == hashLength
+}
+
+// MakeTree builds the records of the tree of the given multiaddrs and links to other trees,
+// signed by the key. The records are keyed by their subdomain, the root by the empty one
+func MakeTree(addrs, links []string, seq uint64, key *ecdsa.PrivateKey) (map[string]string, error) {
+	var (
+		records   = make(map[string]string)
+		nodes     = make([]entry, 0, len(addrs))
+		linkNodes = make([]entry, 0, len(links))
+	)
+
+	// the records are sorted, so the same nodes always make the same tree
+	addrs = append([]string{}, addrs...)
+	sort.Strings(addrs)
+
+	for _, addr := range addrs {
+		nodes = append(nodes, &leafEntry{addr: addr})
+	}
+
+	for _, link := range links {
+		if _, err := ParseURL(link); err != nil {
+			return nil, err
+		}
+
+		linkNodes = append(linkNodes, &linkEntry{url: link})
+	}
+
+	root := &rootEntry{
+		nodesRoot: buildSubtree(nodes, records),
+		linksRoot: buildSubtree(linkNodes, records),
+		seq:       seq,
+	}
+
+	sig, err := crypto.Sign(key, crypto.Keccak256([]byte(root.signedText())))
+	if err != nil {
+		return nil, err
+	}
+
+	root.sig = sig
+	records[""] = root.String()
+
+	return records, nil
+}
+
+// buildSubtree adds the records of the subtree of the entries, and returns the hash of its root
+func buildSubtree(entries []entry, records map[string]string) string {
+	if len(entries) == 1 {
+		hash := hashEntry(entries[0])
+		records[hash] = entries[0].String()
+
+		return hash
+	}
+
+	if len(entries) <= maxChildren {
+		branch := &branchEntry{children: make([]string, len(entries))}
+
+		for i, e := range entries {
+			branch.children[i] = hashEntry(e)
+			records[branch.children[i]] = e.String()
+		}
+
+		hash := hashEntry(branch)
+		records[hash] = branch.String()
+
+		return hash
+	}
+
+	// the entries are split into subtrees of the children of the branch
+	branches := make([]entry, 0, maxChildren)
+	size := (len(entries) + maxChildren - 1) / maxChildren
+
+	for i := 0; i < len(entries); i += size {
+		end := i + size
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		hash := buildSubtree(entries[i:end], records)
+		branches = append(branches, rawEntry(records[hash]))
+	}
+
+	return buildSubtree(branches, records)
+}
+
+// rawEntry is an entry already added to the records
+type rawEntry string
+
+func (e rawEntry) String() string {
+	return string(e)
+}
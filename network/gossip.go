@@ -3,6 +3,7 @@ package network
 import (
 	"context"
 	"reflect"
+	"sync"
 
 	"github.com/hashicorp/go-hclog"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -17,12 +18,29 @@ const (
 	subscribeOutputBufferSize = 1024
 )
 
+// MessageValidator checks a decoded gossip message before it's handled and relayed
+type MessageValidator func(obj proto.Message) error
+
 type Topic struct {
 	logger hclog.Logger
 
 	topic   *pubsub.Topic
 	typ     reflect.Type
 	closeCh chan struct{}
+
+	validatorLock sync.RWMutex
+	validator     MessageValidator
+	// penalty is reported for the peer which relayed a message rejected by the validator
+	penalty PeerPenalty
+}
+
+// SetValidator sets the validator of the decoded messages. The messages it rejects are neither handled
+// nor relayed, and the peer which relayed them is reported with the given penalty
+func (t *Topic) SetValidator(penalty PeerPenalty, validator MessageValidator) {
+	t.validatorLock.Lock()
+	defer t.validatorLock.Unlock()
+
+	t.validator, t.penalty = validator, penalty
 }
 
 func (t *Topic) createObj() proto.Message {
@@ -70,16 +88,47 @@ func (t *Topic) readLoop(sub *pubsub.Subscription, handler func(obj interface{},
 			continue
 		}
 
-		go func() {
-			obj := t.createObj()
-			if err := proto.Unmarshal(msg.Data, obj); err != nil {
-				t.logger.Error("failed to unmarshal topic", "err", err)
+		// the message was decoded by the topic validator
+		obj, ok := msg.ValidatorData.(proto.Message)
+		if !ok {
+			continue
+		}
 
-				return
+		go handler(obj, msg.GetFrom())
+	}
+}
+
+// validate decodes the gossiped messages for the handlers. The messages which can't be decoded or are rejected
+// by the validator of the topic aren't relayed, and gossipsub penalizes the peer which relayed them,
+// which is also reported
+func (t *Topic) validate(report func(peer.ID, PeerPenalty)) pubsub.ValidatorEx {
+	return func(_ context.Context, from peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+		obj := t.createObj()
+		if err := proto.Unmarshal(msg.Data, obj); err != nil {
+			t.logger.Debug("rejecting undecodable gossip", "from", from, "err", err)
+
+			report(from, PenaltyUselessGossip)
+
+			return pubsub.ValidationReject
+		}
+
+		t.validatorLock.RLock()
+		validator, penalty := t.validator, t.penalty
+		t.validatorLock.RUnlock()
+
+		if validator != nil {
+			if err := validator(obj); err != nil {
+				t.logger.Debug("rejecting invalid gossip", "from", from, "err", err)
+
+				report(from, penalty)
+
+				return pubsub.ValidationReject
 			}
+		}
+
+		msg.ValidatorData = obj
 
-			handler(obj, msg.GetFrom())
-		}()
+		return pubsub.ValidationAccept
 	}
 }
 
@@ -95,5 +144,13 @@ func (s *Server) NewTopic(protoID string, obj proto.Message) (*Topic, error) {
 		typ:    reflect.TypeOf(obj).Elem(),
 	}
 
+	if err := s.ps.RegisterTopicValidator(protoID, tt.validate(s.ReportPeer)); err != nil {
+		return nil, err
+	}
+
+	if err := topic.SetScoreParams(gossipTopicScoreParams()); err != nil {
+		return nil, err
+	}
+
 	return tt, nil
 }
@@ -0,0 +1,96 @@
+package network
+
+import (
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// validatorPeerScore is the application specific score of the peers of the active validators.
+	// Gossipsub keeps the best scored peers when pruning a topic mesh, and opportunistically grafts
+	// them when the mesh median score is low, so validators keep direct mesh links with each other
+	validatorPeerScore = 100
+
+	// peerScoreDecayInterval is the interval at which the peer scores are refreshed
+	peerScoreDecayInterval = time.Second
+
+	// invalidGossipWeight is the weight of the square of the number of the messages rejected
+	// by a topic validator a peer relayed, so a few of them put it under the gossip threshold
+	invalidGossipWeight = -10
+
+	// invalidGossipDecay is the decay of the number of the rejected messages at each decay interval
+	invalidGossipDecay = 0.99
+)
+
+// gossipPeerScoreOption returns the gossipsub option that biases the mesh construction
+// towards the validator peers, and away from the peers with a low reputation. No other
+// score components are enabled, so the peers are only penalized for the misbehaviors
+// reported to the reputation: their gossip is ignored under the gossip threshold,
+// and dropped altogether under the graylist one
+func (s *Server) gossipPeerScoreOption() pubsub.Option {
+	return pubsub.WithPeerScore(
+		&pubsub.PeerScoreParams{
+			AppSpecificScore:  s.appSpecificPeerScore,
+			AppSpecificWeight: 1,
+			DecayInterval:     peerScoreDecayInterval,
+			DecayToZero:       0.01,
+			Topics:            make(map[string]*pubsub.TopicScoreParams),
+		},
+		&pubsub.PeerScoreThresholds{
+			GossipThreshold:             -validatorPeerScore,
+			PublishThreshold:            -2 * validatorPeerScore,
+			GraylistThreshold:           -4 * validatorPeerScore,
+			AcceptPXThreshold:           validatorPeerScore,
+			OpportunisticGraftThreshold: validatorPeerScore / 2,
+		},
+	)
+}
+
+// gossipTopicScoreParams returns the score parameters of the topics, which only penalize
+// the peers relaying the messages rejected by the topic validators
+func gossipTopicScoreParams() *pubsub.TopicScoreParams {
+	return &pubsub.TopicScoreParams{
+		SkipAtomicValidation: true,
+		TopicWeight:          1,
+		// gossipsub divides the time in mesh by its quantum even though its weight is zero
+		TimeInMeshQuantum:              time.Second,
+		InvalidMessageDeliveriesWeight: invalidGossipWeight,
+		InvalidMessageDeliveriesDecay:  invalidGossipDecay,
+	}
+}
+
+// appSpecificPeerScore returns the score of the peer, its reputation raised for the validator peers
+func (s *Server) appSpecificPeerScore(id peer.ID) float64 {
+	score := s.reputation.score(id)
+
+	if s.IsValidatorPeer(id) {
+		score += validatorPeerScore
+	}
+
+	return score
+}
+
+// SetValidatorPeers replaces the set of peers which belong to the active validators
+func (s *Server) SetValidatorPeers(ids []peer.ID) {
+	validatorPeers := make(map[peer.ID]struct{}, len(ids))
+	for _, id := range ids {
+		validatorPeers[id] = struct{}{}
+	}
+
+	s.validatorPeersLock.Lock()
+	defer s.validatorPeersLock.Unlock()
+
+	s.validatorPeers = validatorPeers
+}
+
+// IsValidatorPeer checks if the peer belongs to an active validator
+func (s *Server) IsValidatorPeer(id peer.ID) bool {
+	s.validatorPeersLock.RLock()
+	defer s.validatorPeersLock.RUnlock()
+
+	_, ok := s.validatorPeers[id]
+
+	return ok
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	testproto "github.com/0xPolygon/polygon-edge/network/proto"
+	"github.com/hashicorp/go-hclog"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/protobuf/proto"
 )
 
 func NumSubscribers(srv *Server, topic string) int {
@@ -107,3 +112,96 @@ func TestSimpleGossip(t *testing.T) {
 		}
 	}
 }
+
+func TestValidatorPeerScore(t *testing.T) {
+	servers, createErr := createServers(1, nil)
+	if createErr != nil {
+		t.Fatalf("Unable to create servers, %v", createErr)
+	}
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	var (
+		srv       = servers[0]
+		validator = peer.ID("validator")
+		other     = peer.ID("other")
+	)
+
+	srv.SetValidatorPeers([]peer.ID{validator})
+
+	if score := srv.appSpecificPeerScore(validator); score != validatorPeerScore {
+		t.Fatalf("invalid validator peer score %f", score)
+	}
+
+	if score := srv.appSpecificPeerScore(other); score != 0 {
+		t.Fatalf("invalid peer score %f", score)
+	}
+
+	// the validator set is replaced
+	srv.SetValidatorPeers([]peer.ID{other})
+
+	if srv.IsValidatorPeer(validator) || !srv.IsValidatorPeer(other) {
+		t.Fatal("validator peers not replaced")
+	}
+}
+
+func TestTopicValidate(t *testing.T) {
+	topic := &Topic{
+		logger: hclog.NewNullLogger(),
+		typ:    reflect.TypeOf(testproto.GenericMessage{}),
+	}
+
+	topic.SetValidator(PenaltyInvalidTx, func(obj proto.Message) error {
+		if msg, _ := obj.(*testproto.GenericMessage); msg.Message == "invalid" {
+			return errors.New("invalid message")
+		}
+
+		return nil
+	})
+
+	reported := make(map[peer.ID]PeerPenalty)
+	validate := topic.validate(func(id peer.ID, penalty PeerPenalty) {
+		reported[id] = penalty
+	})
+
+	newMessage := func(text string) *pubsub.Message {
+		data, err := proto.Marshal(&testproto.GenericMessage{Message: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return &pubsub.Message{Message: &pubsubpb.Message{Data: data}}
+	}
+
+	// the valid message is decoded for the handler
+	valid := newMessage("valid")
+	if res := validate(context.Background(), peer.ID("A"), valid); res != pubsub.ValidationAccept {
+		t.Fatalf("valid message not accepted, %v", res)
+	}
+
+	if msg, _ := valid.ValidatorData.(*testproto.GenericMessage); msg == nil || msg.Message != "valid" {
+		t.Fatal("valid message not decoded")
+	}
+
+	// the message rejected by the validator is reported with its penalty
+	if res := validate(context.Background(), peer.ID("B"), newMessage("invalid")); res != pubsub.ValidationReject {
+		t.Fatalf("invalid message not rejected, %v", res)
+	}
+
+	// the undecodable message is reported as useless
+	undecodable := &pubsub.Message{Message: &pubsubpb.Message{Data: []byte{0xff}}}
+	if res := validate(context.Background(), peer.ID("C"), undecodable); res != pubsub.ValidationReject {
+		t.Fatalf("undecodable message not rejected, %v", res)
+	}
+
+	expected := map[peer.ID]PeerPenalty{
+		peer.ID("B"): PenaltyInvalidTx,
+		peer.ID("C"): PenaltyUselessGossip,
+	}
+
+	if !reflect.DeepEqual(expected, reported) {
+		t.Fatalf("invalid reported peers %v", reported)
+	}
+}
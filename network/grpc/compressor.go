@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+)
+
+// SnappyCompressor is the name of the gRPC compressor of the snappy framed messages
+const SnappyCompressor = "snappy"
+
+func init() {
+	// the servers decompress the requests of the peers using the compressor,
+	// and compress their responses the same way
+	encoding.RegisterCompressor(&snappyCompressor{})
+}
+
+// snappyCompressor is the gRPC compressor of the messages exchanged with the peers supporting snappy
+type snappyCompressor struct{}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+func (c *snappyCompressor) Name() string {
+	return SnappyCompressor
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
+	"github.com/0xPolygon/polygon-edge/network/common"
 	"github.com/0xPolygon/polygon-edge/network/event"
 	"github.com/hashicorp/go-hclog"
 
@@ -14,11 +17,21 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
-const PeerID = "peerID"
+const (
+	PeerID = "peerID"
+
+	// ProtocolVersion is the metadata key of the protocol version of the node
+	ProtocolVersion = "protocolVersion"
+
+	// Capabilities is the metadata key of the comma separated capabilities of the node
+	Capabilities = "capabilities"
+)
 
 var (
-	ErrInvalidChainID   = errors.New("invalid chain ID")
-	ErrNoAvailableSlots = errors.New("no available Slots")
+	ErrInvalidChainID         = errors.New("invalid chain ID")
+	ErrNoAvailableSlots       = errors.New("no available Slots")
+	ErrIncompatibleVersion    = errors.New("incompatible protocol version")
+	ErrInvalidProtocolVersion = errors.New("invalid protocol version")
 )
 
 // networkingServer defines the base communication interface between
@@ -52,6 +65,14 @@ type networkingServer interface {
 
 	// HasFreeConnectionSlot checks if there are available outbound connection slots [Thread safe]
 	HasFreeConnectionSlot(direction network.Direction) bool
+
+	// IsStaticPeer checks if the peer is a static peer, which is connected regardless of the free slots [Thread safe]
+	IsStaticPeer(peerID peer.ID) bool
+
+	// PROTOCOL NEGOTIATION //
+
+	// SetPeerProtocol saves the protocol version and the capabilities the peer sent on the handshake [Thread safe]
+	SetPeerProtocol(peerID peer.ID, version uint64, capabilities []string)
 }
 
 // IdentityService is a networking service used to handle peer handshaking.
@@ -63,8 +84,9 @@ type IdentityService struct {
 	logger                 hclog.Logger     // The IdentityService logger
 	baseServer             networkingServer // The interface towards the base networking server
 
-	chainID int64   // The chain ID of the network
-	hostID  peer.ID // The base networking server's host peer ID
+	chainID      int64    // The chain ID of the network
+	hostID       peer.ID  // The base networking server's host peer ID
+	capabilities []string // The capabilities of the node, sent on the handshake
 }
 
 // NewIdentityService returns a new instance of the IdentityService
@@ -73,12 +95,14 @@ func NewIdentityService(
 	logger hclog.Logger,
 	chainID int64,
 	hostID peer.ID,
+	capabilities []string,
 ) *IdentityService {
 	return &IdentityService{
-		logger:     logger.Named("identity"),
-		baseServer: server,
-		chainID:    chainID,
-		hostID:     hostID,
+		logger:       logger.Named("identity"),
+		baseServer:   server,
+		chainID:      chainID,
+		hostID:       hostID,
+		capabilities: capabilities,
 	}
 }
 
@@ -93,7 +117,7 @@ func (i *IdentityService) GetNotifyBundle() *network.NotifyBundle {
 				return
 			}
 
-			if !i.baseServer.HasFreeConnectionSlot(conn.Stat().Direction) {
+			if !i.baseServer.IsStaticPeer(peerID) && !i.baseServer.HasFreeConnectionSlot(conn.Stat().Direction) {
 				i.disconnectFromPeer(peerID, ErrNoAvailableSlots.Error())
 
 				return
@@ -182,6 +206,19 @@ func (i *IdentityService) handleConnected(peerID peer.ID, direction network.Dire
 		return ErrInvalidChainID
 	}
 
+	version, capabilities, err := parseProtocol(resp.Metadata)
+	if err != nil {
+		return err
+	}
+
+	// The peers running an older version than the node is compatible with are refused
+	if version < common.MinProtocolVersion {
+		return fmt.Errorf("%w: %d, minimum %d", ErrIncompatibleVersion, version, common.MinProtocolVersion)
+	}
+
+	// The protocol is saved before the peer is added, so the connections to the peer are opened with its capabilities
+	i.baseServer.SetPeerProtocol(peerID, version, capabilities)
+
 	// If this is a NOT temporary connection, save it
 	if !resp.TemporaryDial && !status.TemporaryDial {
 		i.baseServer.AddPeer(peerID, direction)
@@ -207,9 +244,35 @@ func (i *IdentityService) Hello(_ context.Context, req *proto.Status) (*proto.St
 func (i *IdentityService) constructStatus(peerID peer.ID) *proto.Status {
 	return &proto.Status{
 		Metadata: map[string]string{
-			PeerID: i.hostID.Pretty(),
+			PeerID:          i.hostID.Pretty(),
+			ProtocolVersion: strconv.FormatUint(common.ProtocolVersion, 10),
+			Capabilities:    strings.Join(i.capabilities, ","),
 		},
 		Chain:         i.chainID,
 		TemporaryDial: i.baseServer.IsTemporaryDial(peerID),
 	}
 }
+
+// parseProtocol parses the protocol version and the capabilities from the handshake metadata.
+// The nodes which don't send them run the version 0, without any capability
+func parseProtocol(metadata map[string]string) (uint64, []string, error) {
+	rawVersion, ok := metadata[ProtocolVersion]
+	if !ok {
+		return 0, []string{}, nil
+	}
+
+	version, err := strconv.ParseUint(rawVersion, 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %s", ErrInvalidProtocolVersion, rawVersion)
+	}
+
+	capabilities := []string{}
+
+	for _, capability := range strings.Split(metadata[Capabilities], ",") {
+		if capability != "" {
+			capabilities = append(capabilities, capability)
+		}
+	}
+
+	return version, capabilities, nil
+}
@@ -122,3 +122,81 @@ func TestHandshake_Errors(t *testing.T) {
 	// Make sure no peers have been  added to the base networking server
 	assert.Len(t, peersArray, 0)
 }
+
+// TestHandshake_Protocol tests the protocol versions and capabilities exchanged on the handshake
+func TestHandshake_Protocol(t *testing.T) {
+	testTable := []struct {
+		name         string
+		metadata     map[string]string
+		version      uint64
+		capabilities []string
+		err          error
+	}{
+		{
+			"Peer sending its version and capabilities",
+			map[string]string{ProtocolVersion: "1", Capabilities: "snappy,other"},
+			1,
+			[]string{"snappy", "other"},
+			nil,
+		},
+		{
+			"Legacy peer without a version",
+			map[string]string{},
+			0,
+			[]string{},
+			nil,
+		},
+		{
+			"Peer sending an invalid version",
+			map[string]string{ProtocolVersion: "v1"},
+			0,
+			nil,
+			ErrInvalidProtocolVersion,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			var (
+				peersArray   = make([]peer.ID, 0)
+				version      uint64
+				capabilities []string
+			)
+
+			identityService := newIdentityService(
+				func(server *networkTesting.MockNetworkingServer) {
+					server.HookAddPeer(func(id peer.ID, _ network.Direction) {
+						peersArray = append(peersArray, id)
+					})
+
+					server.HookSetPeerProtocol(func(_ peer.ID, v uint64, c []string) {
+						version, capabilities = v, c
+					})
+
+					server.GetMockIdentityClient().HookHello(func(
+						ctx context.Context,
+						in *proto.Status,
+						opts ...grpc.CallOption,
+					) (*proto.Status, error) {
+						// the node sends its own version
+						assert.Equal(t, "1", in.Metadata[ProtocolVersion])
+
+						return &proto.Status{Metadata: testCase.metadata}, nil
+					})
+				},
+			)
+
+			assert.ErrorIs(t, identityService.handleConnected("TestPeer", network.DirInbound), testCase.err)
+			assert.Equal(t, testCase.version, version)
+			assert.Equal(t, testCase.capabilities, capabilities)
+
+			if testCase.err != nil {
+				assert.Len(t, peersArray, 0)
+			} else {
+				assert.Len(t, peersArray, 1)
+			}
+		})
+	}
+}
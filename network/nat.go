@@ -0,0 +1,220 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-nat"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// NATMapping is the protocol mapping the libp2p port on the NAT gateway
+type NATMapping string
+
+const (
+	// NATMappingNone doesn't map the port, the node is only dialable at its listen
+	// or external address
+	NATMappingNone NATMapping = ""
+	// NATMappingUPnP maps the port with UPnP
+	NATMappingUPnP NATMapping = "upnp"
+	// NATMappingPMP maps the port with NAT-PMP
+	NATMappingPMP NATMapping = "pmp"
+)
+
+const (
+	// natExtIPPrefix prefixes the external address set instead of a mapping protocol
+	natExtIPPrefix = "extip:"
+
+	// natDiscoveryTimeout is the time the NAT gateway is looked for
+	natDiscoveryTimeout = 10 * time.Second
+
+	// natMappingLifetime is the time the gateway keeps the port mapped for,
+	// it is renewed at half of it so it never lapses
+	natMappingLifetime = 20 * time.Minute
+
+	// natMappingDescription is the description of the port mapping on the gateway
+	natMappingDescription = "polygon-edge"
+)
+
+var ErrInvalidNAT = errors.New("invalid NAT, expected upnp, pmp or extip:<ip>")
+
+// ParseNAT parses the NAT traversal setting, which is either the protocol mapping the port
+// (upnp or pmp) or the external address (extip:<ip>). A bare IP is the external address too
+func ParseNAT(raw string) (NATMapping, net.IP, error) {
+	switch mapping := NATMapping(strings.ToLower(raw)); mapping {
+	case NATMappingNone, NATMappingUPnP, NATMappingPMP:
+		return mapping, nil, nil
+	}
+
+	if ip := net.ParseIP(strings.TrimPrefix(raw, natExtIPPrefix)); ip != nil {
+		return NATMappingNone, ip, nil
+	}
+
+	return NATMappingNone, nil, fmt.Errorf("%w: %s", ErrInvalidNAT, raw)
+}
+
+// portMapper keeps the libp2p port mapped on the NAT gateway, and the external address
+// the node is reachable at through the mapping, which is advertised instead of the listen addresses
+type portMapper struct {
+	logger   hclog.Logger
+	mapping  NATMapping
+	port     int
+	discover func(ctx context.Context, mapping NATMapping) (nat.NAT, error)
+
+	// gateway is only accessed by the mapping routine
+	gateway nat.NAT
+
+	lock     sync.RWMutex
+	external multiaddr.Multiaddr
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+func newPortMapper(logger hclog.Logger, mapping NATMapping, port int) *portMapper {
+	return &portMapper{
+		logger:   logger.Named("nat"),
+		mapping:  mapping,
+		port:     port,
+		discover: discoverGateway,
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// start maps the port in the background, renewing the mapping until closed
+func (m *portMapper) start() {
+	m.doneCh = make(chan struct{})
+
+	go m.run()
+}
+
+func (m *portMapper) run() {
+	defer close(m.doneCh)
+
+	for {
+		m.refresh()
+
+		select {
+		case <-time.After(natMappingLifetime / 2):
+		case <-m.closeCh:
+			m.unmap()
+
+			return
+		}
+	}
+}
+
+// refresh maps the port on the gateway, looking for it first if it isn't known yet.
+// The external address is cleared if the port can't be mapped, until the next refresh
+func (m *portMapper) refresh() {
+	if m.gateway == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), natDiscoveryTimeout)
+		defer cancel()
+
+		gateway, err := m.discover(ctx, m.mapping)
+		if err != nil {
+			m.logger.Warn("no NAT gateway found", "mapping", m.mapping, "err", err)
+			m.setExternalAddr(nil)
+
+			return
+		}
+
+		m.gateway = gateway
+	}
+
+	externalPort, err := m.gateway.AddPortMapping("tcp", m.port, natMappingDescription, natMappingLifetime)
+	if err != nil {
+		m.logger.Warn("failed to map the port", "gateway", m.gateway.Type(), "port", m.port, "err", err)
+
+		// the gateway is looked for again, it may have been replaced
+		m.gateway = nil
+		m.setExternalAddr(nil)
+
+		return
+	}
+
+	externalIP, err := m.gateway.GetExternalAddress()
+	if err != nil {
+		m.logger.Warn("failed to get the external address", "gateway", m.gateway.Type(), "err", err)
+		m.setExternalAddr(nil)
+
+		return
+	}
+
+	addr, err := ipTCPMultiaddr(externalIP, externalPort)
+	if err != nil {
+		m.logger.Error("invalid external address", "ip", externalIP, "port", externalPort, "err", err)
+		m.setExternalAddr(nil)
+
+		return
+	}
+
+	m.setExternalAddr(addr)
+}
+
+// unmap removes the port mapping from the gateway
+func (m *portMapper) unmap() {
+	if m.gateway == nil {
+		return
+	}
+
+	if err := m.gateway.DeletePortMapping("tcp", m.port); err != nil {
+		m.logger.Warn("failed to remove the port mapping", "gateway", m.gateway.Type(), "err", err)
+	}
+}
+
+func (m *portMapper) setExternalAddr(addr multiaddr.Multiaddr) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if addr != nil && (m.external == nil || !m.external.Equal(addr)) {
+		m.logger.Info("port mapped", "external", addr, "port", m.port)
+	}
+
+	m.external = addr
+}
+
+// externalAddr returns the address the node is reachable at through the mapping, nil if it isn't mapped
+func (m *portMapper) externalAddr() multiaddr.Multiaddr {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.external
+}
+
+// close stops renewing the mapping, and removes it from the gateway
+func (m *portMapper) close() {
+	close(m.closeCh)
+
+	if m.doneCh != nil {
+		<-m.doneCh
+	}
+}
+
+// discoverGateway looks for a NAT gateway supporting the mapping protocol
+func discoverGateway(ctx context.Context, mapping NATMapping) (nat.NAT, error) {
+	for gateway := range nat.DiscoverNATs(ctx) {
+		isUPnP := strings.HasPrefix(gateway.Type(), "UPNP")
+
+		if (mapping == NATMappingUPnP) == isUPnP {
+			return gateway, nil
+		}
+	}
+
+	return nil, nat.ErrNoNATFound
+}
+
+// ipTCPMultiaddr returns the multiaddr of the TCP port at the IP
+func ipTCPMultiaddr(ip net.IP, port int) (multiaddr.Multiaddr, error) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", ip4, port))
+	}
+
+	return multiaddr.NewMultiaddr(fmt.Sprintf("/ip6/%s/tcp/%d", ip, port))
+}
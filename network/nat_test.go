@@ -0,0 +1,119 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNAT(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		raw     string
+		mapping NATMapping
+		ip      net.IP
+		err     error
+	}{
+		{"", NATMappingNone, nil, nil},
+		{"upnp", NATMappingUPnP, nil, nil},
+		{"PMP", NATMappingPMP, nil, nil},
+		{"extip:192.0.2.1", NATMappingNone, net.ParseIP("192.0.2.1"), nil},
+		{"192.0.2.1", NATMappingNone, net.ParseIP("192.0.2.1"), nil},
+		{"extip:", NATMappingNone, nil, ErrInvalidNAT},
+		{"stun", NATMappingNone, nil, ErrInvalidNAT},
+	}
+
+	for _, testCase := range testTable {
+		mapping, ip, err := ParseNAT(testCase.raw)
+
+		assert.ErrorIs(t, err, testCase.err, testCase.raw)
+		assert.Equal(t, testCase.mapping, mapping, testCase.raw)
+		assert.True(t, testCase.ip.Equal(ip), testCase.raw)
+	}
+}
+
+// mockGateway is a NAT gateway mapping the ports to the ports 10000 above them
+type mockGateway struct {
+	nat.NAT
+
+	mapped  map[int]bool
+	failing bool
+}
+
+func (g *mockGateway) Type() string {
+	return "NAT-PMP"
+}
+
+func (g *mockGateway) GetExternalAddress() (net.IP, error) {
+	return net.ParseIP("192.0.2.1"), nil
+}
+
+func (g *mockGateway) AddPortMapping(_ string, port int, _ string, _ time.Duration) (int, error) {
+	if g.failing {
+		return 0, errors.New("mapping refused")
+	}
+
+	g.mapped[port] = true
+
+	return port + 10000, nil
+}
+
+func (g *mockGateway) DeletePortMapping(_ string, port int) error {
+	delete(g.mapped, port)
+
+	return nil
+}
+
+func TestPortMapper(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gateway     = &mockGateway{mapped: make(map[int]bool)}
+		discoveries = 0
+	)
+
+	mapper := newPortMapper(hclog.NewNullLogger(), NATMappingPMP, 1478)
+	mapper.discover = func(_ context.Context, mapping NATMapping) (nat.NAT, error) {
+		assert.Equal(t, NATMappingPMP, mapping)
+
+		discoveries++
+
+		return gateway, nil
+	}
+
+	mapper.refresh()
+
+	require.NotNil(t, mapper.externalAddr())
+	assert.Equal(t, "/ip4/192.0.2.1/tcp/11478", mapper.externalAddr().String())
+	assert.True(t, gateway.mapped[1478])
+
+	// the known gateway is kept while the port is mapped
+	mapper.refresh()
+	assert.Equal(t, 1, discoveries)
+
+	// the external address isn't advertised once the mapping fails
+	gateway.failing = true
+
+	mapper.refresh()
+	assert.Nil(t, mapper.externalAddr())
+
+	gateway.failing = false
+
+	mapper.refresh()
+	assert.Equal(t, 2, discoveries)
+	assert.NotNil(t, mapper.externalAddr())
+
+	// the mapping is removed once closed
+	mapper.start()
+	mapper.close()
+
+	assert.False(t, gateway.mapped[1478])
+}
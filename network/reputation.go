@@ -0,0 +1,314 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// PeerPenalty is a misbehavior of a peer which lowers its reputation
+type PeerPenalty int
+
+const (
+	// PenaltyInvalidBlock is a block from the peer which failed the verification
+	PenaltyInvalidBlock PeerPenalty = iota
+	// PenaltyInvalidTx is a transaction from the peer which can never be included
+	PenaltyInvalidTx
+	// PenaltyTimeout is a request the peer didn't answer in time
+	PenaltyTimeout
+	// PenaltyUselessGossip is a gossiped message from the peer which couldn't be decoded
+	PenaltyUselessGossip
+	// PenaltyInvalidResponse is a response from the peer which doesn't answer its request or exceeds its limits
+	PenaltyInvalidResponse
+)
+
+// String returns the name of the penalty
+func (p PeerPenalty) String() string {
+	switch p {
+	case PenaltyInvalidBlock:
+		return "invalid-block"
+	case PenaltyInvalidTx:
+		return "invalid-tx"
+	case PenaltyTimeout:
+		return "timeout"
+	case PenaltyUselessGossip:
+		return "useless-gossip"
+	case PenaltyInvalidResponse:
+		return "invalid-response"
+	default:
+		return fmt.Sprintf("penalty(%d)", int(p))
+	}
+}
+
+const (
+	// demotePeerScore is the reputation under which a peer is disconnected. It can connect again,
+	// but its gossip is ignored until its reputation recovers above the gossip threshold
+	demotePeerScore = -validatorPeerScore
+
+	// banPeerScore is the reputation under which a peer is banned for the ban duration
+	banPeerScore = -2 * validatorPeerScore
+
+	// reputationHalfLife is the time after which the reputation of a peer is halved back towards zero
+	reputationHalfLife = 10 * time.Minute
+
+	// banlistFile is the name of the file the banned peers are persisted to, in the data directory
+	banlistFile = "banlist.json"
+
+	// DefaultBanDuration is the time a peer is banned for once its reputation falls under the ban score
+	DefaultBanDuration = time.Hour
+)
+
+// penaltyScores are the reputation lost by a peer for each of its misbehaviors
+var penaltyScores = map[PeerPenalty]float64{
+	PenaltyInvalidBlock:    -80,
+	PenaltyInvalidResponse: -40,
+	PenaltyInvalidTx:       -20,
+	PenaltyTimeout:         -10,
+	PenaltyUselessGossip:   -5,
+}
+
+// peerReputation is the reputation of a peer at the time it was last penalized
+type peerReputation struct {
+	score   float64
+	updated time.Time
+}
+
+// reputation keeps the reputation of the peers, decaying back towards zero over time, and the peers
+// banned for having fallen under the ban score. It gates the connections, so the banned peers
+// can neither be dialed nor accepted until their ban expires. The banned peers are persisted
+// in the data directory, if it is set, so they stay banned across restarts
+type reputation struct {
+	logger      hclog.Logger
+	path        string
+	banDuration time.Duration
+
+	lock    sync.Mutex
+	scores  map[peer.ID]*peerReputation
+	banlist map[peer.ID]time.Time
+
+	// now returns the current time, replaced in the tests
+	now func() time.Time
+}
+
+func newReputation(logger hclog.Logger, dataDir string, banDuration time.Duration) (*reputation, error) {
+	if banDuration <= 0 {
+		banDuration = DefaultBanDuration
+	}
+
+	r := &reputation{
+		logger:      logger.Named("reputation"),
+		banDuration: banDuration,
+		scores:      make(map[peer.ID]*peerReputation),
+		banlist:     make(map[peer.ID]time.Time),
+		now:         time.Now,
+	}
+
+	if dataDir != "" {
+		r.path = filepath.Join(dataDir, banlistFile)
+
+		if err := r.loadBanlist(); err != nil {
+			return nil, fmt.Errorf("failed to load the banlist: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// score returns the current reputation of the peer
+func (r *reputation) score(id peer.ID) float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.decayedScore(id)
+}
+
+// decayedScore returns the reputation of the peer decayed since it was last penalized
+func (r *reputation) decayedScore(id peer.ID) float64 {
+	rep, ok := r.scores[id]
+	if !ok {
+		return 0
+	}
+
+	elapsed := r.now().Sub(rep.updated)
+
+	return rep.score * math.Pow(0.5, elapsed.Seconds()/reputationHalfLife.Seconds())
+}
+
+// penalize lowers the reputation of the peer, and returns it. The peer is banned
+// once its reputation falls under the ban score, in which case banned is true
+func (r *reputation) penalize(id peer.ID, penalty PeerPenalty) (score float64, banned bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	score = r.decayedScore(id) + penaltyScores[penalty]
+
+	metrics.IncrCounterWithLabels(
+		[]string{"peer_penalties"},
+		1,
+		[]metrics.Label{{Name: "penalty", Value: penalty.String()}},
+	)
+
+	if score >= banPeerScore {
+		r.scores[id] = &peerReputation{score: score, updated: r.now()}
+
+		return score, false
+	}
+
+	// the peer starts over once its ban expires
+	delete(r.scores, id)
+
+	r.banlist[id] = r.now().Add(r.banDuration)
+	r.saveBanlist()
+
+	metrics.SetGauge([]string{"banned_peers"}, float32(len(r.banlist)))
+
+	return score, true
+}
+
+// isBanned checks if the peer is banned, and removes its ban once expired
+func (r *reputation) isBanned(id peer.ID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	until, ok := r.banlist[id]
+	if !ok {
+		return false
+	}
+
+	if r.now().Before(until) {
+		return true
+	}
+
+	delete(r.banlist, id)
+	r.saveBanlist()
+
+	metrics.SetGauge([]string{"banned_peers"}, float32(len(r.banlist)))
+
+	return false
+}
+
+// loadBanlist reads the banned peers from the data directory, dropping the expired bans
+func (r *reputation) loadBanlist() error {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	banlist := make(map[string]time.Time)
+	if err := json.Unmarshal(data, &banlist); err != nil {
+		return err
+	}
+
+	for rawID, until := range banlist {
+		id, err := peer.Decode(rawID)
+		if err != nil {
+			return fmt.Errorf("invalid peer %s: %w", rawID, err)
+		}
+
+		if r.now().Before(until) {
+			r.banlist[id] = until
+		}
+	}
+
+	return nil
+}
+
+// saveBanlist writes the banned peers to the data directory, replacing the previous file at once.
+// A failure is only logged, the peers stay banned until the node is restarted
+func (r *reputation) saveBanlist() {
+	if r.path == "" {
+		return
+	}
+
+	banlist := make(map[string]time.Time, len(r.banlist))
+	for id, until := range r.banlist {
+		banlist[id.String()] = until
+	}
+
+	data, err := json.MarshalIndent(banlist, "", "  ")
+	if err != nil {
+		r.logger.Error("failed to encode the banlist", "err", err)
+
+		return
+	}
+
+	tmp := r.path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		r.logger.Error("failed to write the banlist", "err", err)
+
+		return
+	}
+
+	if err := os.Rename(tmp, r.path); err != nil {
+		r.logger.Error("failed to write the banlist", "err", err)
+	}
+}
+
+// InterceptPeerDial rejects the dials to the banned peers
+func (r *reputation) InterceptPeerDial(id peer.ID) bool {
+	return !r.isBanned(id)
+}
+
+// InterceptAddrDial allows the dials to any address of the peers which aren't banned
+func (r *reputation) InterceptAddrDial(peer.ID, multiaddr.Multiaddr) bool {
+	return true
+}
+
+// InterceptAccept allows the inbound connections until the peer is known
+func (r *reputation) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptSecured rejects the connections to and from the banned peers once they are identified
+func (r *reputation) InterceptSecured(_ network.Direction, id peer.ID, _ network.ConnMultiaddrs) bool {
+	return !r.isBanned(id)
+}
+
+// InterceptUpgraded allows the connections which passed the previous checks
+func (r *reputation) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// ReportPeer lowers the reputation of the peer for its misbehavior. The peer is disconnected once its
+// reputation falls under the demote score, and banned once it falls under the ban score.
+// The static peers are trusted, so they are never penalized
+func (s *Server) ReportPeer(id peer.ID, penalty PeerPenalty) {
+	if s.IsStaticPeer(id) {
+		s.logger.Debug("static peer misbehaved", "id", id, "penalty", penalty)
+
+		return
+	}
+
+	score, banned := s.reputation.penalize(id, penalty)
+
+	s.logger.Debug("peer penalized", "id", id, "penalty", penalty, "score", score)
+
+	switch {
+	case banned:
+		s.logger.Warn("peer banned", "id", id, "penalty", penalty, "duration", s.reputation.banDuration)
+
+		s.DisconnectFromPeer(id, "banned")
+	case score < demotePeerScore:
+		s.DisconnectFromPeer(id, "low reputation")
+	}
+}
+
+// IsBannedPeer checks if the peer is banned
+func (s *Server) IsBannedPeer(id peer.ID) bool {
+	return s.reputation.isBanned(id)
+}
@@ -0,0 +1,82 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReputation_Decay(t *testing.T) {
+	t.Parallel()
+
+	r, err := newReputation(hclog.NewNullLogger(), "", 0)
+	require.NoError(t, err)
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	score, banned := r.penalize("A", PenaltyInvalidBlock)
+	assert.False(t, banned)
+	assert.Equal(t, penaltyScores[PenaltyInvalidBlock], score)
+	assert.Equal(t, float64(0), r.score("B"))
+
+	// the reputation is halved back towards zero after each half-life
+	now = now.Add(reputationHalfLife)
+	assert.InDelta(t, penaltyScores[PenaltyInvalidBlock]/2, r.score("A"), 0.001)
+
+	score, _ = r.penalize("A", PenaltyTimeout)
+	assert.InDelta(t, penaltyScores[PenaltyInvalidBlock]/2+penaltyScores[PenaltyTimeout], score, 0.001)
+}
+
+func TestReputation_Ban(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dataDir = t.TempDir()
+		a       = test.RandPeerIDFatal(t)
+		b       = test.RandPeerIDFatal(t)
+	)
+
+	r, err := newReputation(hclog.NewNullLogger(), dataDir, time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	var banned bool
+
+	for i := 0; !banned; i++ {
+		require.Less(t, i, 3, "peer not banned")
+
+		_, banned = r.penalize(a, PenaltyInvalidBlock)
+	}
+
+	assert.True(t, r.isBanned(a))
+	assert.False(t, r.InterceptPeerDial(a))
+	assert.True(t, r.InterceptPeerDial(b))
+
+	// the peer starts over once banned
+	assert.Equal(t, float64(0), r.score(a))
+
+	// the ban is kept across restarts until it expires
+	restarted, err := newReputation(hclog.NewNullLogger(), dataDir, time.Minute)
+	require.NoError(t, err)
+
+	restarted.now = r.now
+
+	assert.True(t, restarted.isBanned(a))
+
+	now = now.Add(time.Minute)
+
+	assert.False(t, restarted.isBanned(a))
+
+	restarted, err = newReputation(hclog.NewNullLogger(), dataDir, time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[peer.ID]time.Time{}, restarted.banlist)
+}
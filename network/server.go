@@ -10,6 +10,8 @@ import (
 	"github.com/0xPolygon/polygon-edge/network/common"
 	"github.com/0xPolygon/polygon-edge/network/dial"
 	"github.com/0xPolygon/polygon-edge/network/discovery"
+	"github.com/0xPolygon/polygon-edge/network/dnsdisc"
+	"github.com/0xPolygon/polygon-edge/network/grpc"
 	"github.com/armon/go-metrics"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
@@ -78,13 +80,29 @@ type Server struct {
 
 	ps *pubsub.PubSub // reference to the networking PubSub service
 
+	validatorPeers     map[peer.ID]struct{} // peers of the active validators, preferred in the gossip mesh
+	validatorPeersLock sync.RWMutex         // lock for the validator peers map
+
+	reputation *reputation // reputation of the peers, gating the connections of the banned ones
+
+	staticPeers *staticPeers // peers always kept connected, regardless of the free connection slots
+
+	portMapper *portMapper // keeps the port mapped on the NAT gateway, nil if no mapping is set
+
 	emitterPeerEvent event.Emitter // event emitter for listeners
 
 	connectionCounts *ConnectionInfo
 
 	temporaryDials sync.Map // map of temporary connections; peerID -> bool
 
+	peerProtocols sync.Map // map of the protocols the peers sent on the handshake; peerID -> *peerProtocol
+
 	bootnodes *bootnodesWrapper // reference of all bootnodes for the node
+
+	staticBootnodes []*peer.AddrInfo            // bootnodes set by their multiaddrs in the chain config
+	bootnodeTrees   []string                    // URLs of the DNS trees publishing bootnodes, resolved periodically
+	treeBootnodes   map[string][]*peer.AddrInfo // last resolved bootnodes, by the URL of their tree
+	treeClient      *dnsdisc.Client             // resolves the bootnode trees
 }
 
 // NewServer returns a new instance of the networking server
@@ -101,6 +119,11 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	var mapper *portMapper
+	if config.NATMapping != NATMappingNone && config.NatAddr == nil {
+		mapper = newPortMapper(logger, config.NATMapping, config.Addr.Port)
+	}
+
 	addrsFactory := func(addrs []multiaddr.Multiaddr) []multiaddr.Multiaddr {
 		if config.NatAddr != nil {
 			addr, _ := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", config.NatAddr.String(), config.Addr.Port))
@@ -110,17 +133,29 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 			}
 		} else if config.DNS != nil {
 			addrs = []multiaddr.Multiaddr{config.DNS}
+		} else if mapper != nil {
+			// the mapped address is advertised once the port is mapped on the gateway
+			if addr := mapper.externalAddr(); addr != nil {
+				addrs = []multiaddr.Multiaddr{addr}
+			}
 		}
 
 		return addrs
 	}
 
+	reputation, err := newReputation(logger, config.DataDir, config.BanDuration)
+	if err != nil {
+		return nil, err
+	}
+
 	host, err := libp2p.New(
 		// Use noise as the encryption protocol
 		libp2p.Security(noise.ID, noise.New),
 		libp2p.ListenAddrs(listenAddr),
 		libp2p.AddrsFactory(addrsFactory),
 		libp2p.Identity(key),
+		libp2p.ConnectionGater(reputation),
+		libp2p.BandwidthReporter(newBandwidthCounter()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create libp2p stack: %w", err)
@@ -137,6 +172,10 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		host:             host,
 		addrs:            host.Addrs(),
 		peers:            make(map[peer.ID]*PeerConnInfo),
+		validatorPeers:   make(map[peer.ID]struct{}),
+		reputation:       reputation,
+		staticPeers:      newStaticPeers(),
+		portMapper:       mapper,
 		dialQueue:        dial.NewDialQueue(),
 		closeCh:          make(chan struct{}),
 		emitterPeerEvent: emitter,
@@ -147,18 +186,30 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 			bootnodesMap:      make(map[peer.ID]*peer.AddrInfo),
 			bootnodeConnCount: 0,
 		},
+		treeBootnodes: make(map[string][]*peer.AddrInfo),
+		treeClient:    dnsdisc.NewClient(logger, nil),
 		connectionCounts: NewBlankConnectionInfo(
 			config.MaxInboundPeers,
 			config.MaxOutboundPeers,
 		),
 	}
 
-	// start gossip protocol
-	ps, err := pubsub.NewGossipSub(
-		context.Background(),
-		host, pubsub.WithPeerOutboundQueueSize(peerOutboundBufferSize),
+	gossipOpts := []pubsub.Option{
+		pubsub.WithPeerOutboundQueueSize(peerOutboundBufferSize),
 		pubsub.WithValidateQueueSize(validateBufferSize),
-	)
+		srv.gossipPeerScoreOption(),
+	}
+
+	// the gossip is compressed for the peers negotiating the snappy gossipsub protocol
+	gossipHost := host
+	if !config.NoCompression {
+		gossipHost = newCompressionHost(host, gossipSubSnappyID)
+
+		gossipOpts = append(gossipOpts, gossipSubProtocolsOption())
+	}
+
+	// start gossip protocol
+	ps, err := pubsub.NewGossipSub(context.Background(), gossipHost, gossipOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -179,6 +230,10 @@ type PeerConnInfo struct {
 
 	connDirections  map[network.Direction]bool
 	protocolStreams map[string]*rawGrpc.ClientConn
+
+	// static is set if the peer was a static peer once connected,
+	// in which case its connections aren't counted against the limits
+	static bool
 }
 
 // addProtocolStream adds a protocol stream
@@ -245,6 +300,10 @@ func (s *Server) Start() error {
 		return fmt.Errorf("unable to setup identity, %w", setupErr)
 	}
 
+	if s.portMapper != nil {
+		s.portMapper.start()
+	}
+
 	// Set up the peer discovery mechanism if needed
 	if !s.config.NoDiscover {
 		// Parse the bootnode data
@@ -258,8 +317,17 @@ func (s *Server) Start() error {
 		}
 	}
 
+	if setupErr := s.setupStaticPeers(); setupErr != nil {
+		return fmt.Errorf("unable to setup static peers, %w", setupErr)
+	}
+
 	go s.runDial()
 	go s.keepAliveMinimumPeerConnections()
+	go s.keepStaticPeerConnections()
+
+	if len(s.bootnodeTrees) > 0 {
+		go s.keepBootnodeTreesResolved()
+	}
 
 	// watch for disconnected peers
 	s.host.Network().Notify(&network.NotifyBundle{
@@ -272,7 +340,8 @@ func (s *Server) Start() error {
 	return nil
 }
 
-// setupBootnodes sets up the node's bootnode connections
+// setupBootnodes sets up the node's bootnode connections. The bootnodes are either
+// multiaddrs, or URLs of the DNS trees publishing them
 func (s *Server) setupBootnodes() error {
 	// Check the bootnode config is present
 	if s.config.Chain.Bootnodes == nil {
@@ -284,34 +353,35 @@ func (s *Server) setupBootnodes() error {
 		return ErrMinBootnodes
 	}
 
-	bootnodesArr := make([]*peer.AddrInfo, 0)
-	bootnodesMap := make(map[peer.ID]*peer.AddrInfo)
+	staticBootnodes := make([]*peer.AddrInfo, 0)
+	bootnodeTrees := make([]string, 0)
 
 	for _, rawAddr := range s.config.Chain.Bootnodes {
-		bootnode, err := common.StringToAddrInfo(rawAddr)
-		if err != nil {
-			return fmt.Errorf("failed to parse bootnode %s: %w", rawAddr, err)
-		}
+		if dnsdisc.IsURL(rawAddr) {
+			if _, err := dnsdisc.ParseURL(rawAddr); err != nil {
+				return fmt.Errorf("failed to parse bootnode tree %s: %w", rawAddr, err)
+			}
 
-		if bootnode.ID == s.host.ID() {
-			s.logger.Info("Omitting bootnode with same ID as host", "id", bootnode.ID)
+			bootnodeTrees = append(bootnodeTrees, rawAddr)
 
 			continue
 		}
 
-		bootnodesArr = append(bootnodesArr, bootnode)
-		bootnodesMap[bootnode.ID] = bootnode
-	}
+		bootnode, err := common.StringToAddrInfo(rawAddr)
+		if err != nil {
+			return fmt.Errorf("failed to parse bootnode %s: %w", rawAddr, err)
+		}
 
-	// It's fine for the bootnodes field to be unprotected
-	// at this point because it is initialized once (doesn't change),
-	// and used only after this point
-	s.bootnodes = &bootnodesWrapper{
-		bootnodeArr:       bootnodesArr,
-		bootnodesMap:      bootnodesMap,
-		bootnodeConnCount: 0,
+		staticBootnodes = append(staticBootnodes, bootnode)
 	}
 
+	// The static bootnodes and the trees don't change, and are set before
+	// the bootnode trees are resolved again periodically
+	s.staticBootnodes = staticBootnodes
+	s.bootnodeTrees = bootnodeTrees
+
+	s.setBootnodes(append(s.resolveBootnodeTrees(), staticBootnodes...))
+
 	return nil
 }
 
@@ -447,6 +517,39 @@ func (s *Server) Peers() []*PeerConnInfo {
 	return peers
 }
 
+// PeerStatus is the connection status of a connected peer
+type PeerStatus struct {
+	Info      peer.AddrInfo
+	Protocols []string
+	Inbound   bool
+	Outbound  bool
+	Static    bool
+}
+
+// PeerStatuses returns the connection status of the connected peers [Thread safe]
+func (s *Server) PeerStatuses() []*PeerStatus {
+	s.peersLock.Lock()
+
+	statuses := make([]*PeerStatus, 0, len(s.peers))
+	for _, connectionInfo := range s.peers {
+		statuses = append(statuses, &PeerStatus{
+			Info:     connectionInfo.Info,
+			Inbound:  connectionInfo.connDirections[network.DirInbound],
+			Outbound: connectionInfo.connDirections[network.DirOutbound],
+			Static:   s.IsStaticPeer(connectionInfo.Info.ID),
+		})
+	}
+
+	s.peersLock.Unlock()
+
+	for _, status := range statuses {
+		// the protocols are known once the peer is identified, they are left empty until then
+		status.Protocols, _ = s.GetProtocols(status.Info.ID)
+	}
+
+	return statuses
+}
+
 // hasPeer checks if the peer is present in the peers list [Thread safe]
 func (s *Server) hasPeer(peerID peer.ID) bool {
 	s.peersLock.Lock()
@@ -473,6 +576,8 @@ func (s *Server) GetProtocols(peerID peer.ID) ([]string, error) {
 func (s *Server) removePeer(peerID peer.ID) {
 	s.logger.Info("Peer disconnected", "id", peerID.String())
 
+	s.peerProtocols.Delete(peerID)
+
 	// Remove the peer from the peers map
 	connectionInfo := s.removePeerInfo(peerID)
 	if connectionInfo == nil {
@@ -508,8 +613,11 @@ func (s *Server) removePeerInfo(peerID peer.ID) *PeerConnInfo {
 	// Update connection counters
 	for connDirection, active := range connectionInfo.connDirections {
 		if active {
-			s.connectionCounts.UpdateConnCountByDirection(-1, connDirection)
-			s.updateConnCountMetrics(connDirection)
+			if !connectionInfo.static {
+				s.connectionCounts.UpdateConnCountByDirection(-1, connDirection)
+				s.updateConnCountMetrics(connDirection)
+			}
+
 			s.updateBootnodeConnCount(peerID, -1)
 		}
 	}
@@ -549,6 +657,14 @@ var (
 	DefaultBufferTimeout = DefaultJoinTimeout + time.Second*5
 )
 
+// SetPeerLimits replaces the limits of the inbound and outbound peers at runtime,
+// the peers above the new limits stay connected
+func (s *Server) SetPeerLimits(maxInboundPeers, maxOutboundPeers int64) {
+	s.connectionCounts.SetMaxConnCounts(maxInboundPeers, maxOutboundPeers)
+
+	s.logger.Info("peer limits set", "inbound", maxInboundPeers, "outbound", maxOutboundPeers)
+}
+
 // JoinPeer attempts to add a new peer to the networking server
 func (s *Server) JoinPeer(rawPeerMultiaddr string) error {
 	// Parse the raw string to a MultiAddr format
@@ -590,6 +706,10 @@ func (s *Server) Close() error {
 
 	close(s.closeCh)
 
+	if s.portMapper != nil {
+		s.portMapper.close()
+	}
+
 	return err
 }
 
@@ -609,6 +729,11 @@ func (s *Server) NewProtoConnection(protocol string, peerID peer.ID) (*rawGrpc.C
 		return nil, err
 	}
 
+	// the requests to the peers supporting it are compressed, along with their responses
+	if !s.config.NoCompression && s.SupportsCapability(peerID, common.CapabilitySnappy) {
+		return p.Client(stream, rawGrpc.WithDefaultCallOptions(rawGrpc.UseCompressor(grpc.SnappyCompressor))), nil
+	}
+
 	return p.Client(stream), nil
 }
 
@@ -617,7 +742,7 @@ func (s *Server) NewStream(proto string, id peer.ID) (network.Stream, error) {
 }
 
 type Protocol interface {
-	Client(network.Stream) *rawGrpc.ClientConn
+	Client(network.Stream, ...rawGrpc.DialOption) *rawGrpc.ClientConn
 	Handler() func(network.Stream)
 }
 
@@ -68,6 +68,7 @@ func (s *Server) addPeerInfo(id peer.ID, direction network.Direction) bool {
 			Info:            s.host.Peerstore().PeerInfo(id),
 			connDirections:  make(map[network.Direction]bool),
 			protocolStreams: make(map[string]*rawGrpc.ClientConn),
+			static:          s.IsStaticPeer(id),
 		}
 	}
 
@@ -77,8 +78,11 @@ func (s *Server) addPeerInfo(id peer.ID, direction network.Direction) bool {
 	s.peers[id] = connectionInfo
 
 	// Update connection counters
-	s.connectionCounts.UpdateConnCountByDirection(1, direction)
-	s.updateConnCountMetrics(direction)
+	if !connectionInfo.static {
+		s.connectionCounts.UpdateConnCountByDirection(1, direction)
+		s.updateConnCountMetrics(direction)
+	}
+
 	s.updateBootnodeConnCount(id, 1)
 
 	// Update the metric stats
@@ -114,6 +118,7 @@ func (s *Server) setupIdentity() error {
 		s.logger,
 		int64(s.config.Chain.Params.ChainID),
 		s.host.ID(),
+		s.capabilities(),
 	)
 
 	// Register the identity service protocol
@@ -75,6 +75,48 @@ func TestConnLimit_Inbound(t *testing.T) {
 	}
 }
 
+func TestConnLimit_SetPeerLimits(t *testing.T) {
+	// the raised limits are applied to the new connections at runtime
+	defaultConfig := &CreateServerParams{
+		ConfigCallback: func(c *Config) {
+			c.MaxInboundPeers = 1
+			c.MaxOutboundPeers = 1
+			c.NoDiscover = true
+		},
+	}
+
+	servers, createErr := createServers(3, map[int]*CreateServerParams{
+		0: defaultConfig,
+		1: defaultConfig,
+		2: defaultConfig,
+	})
+	if createErr != nil {
+		t.Fatalf("Unable to create servers, %v", createErr)
+	}
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	if joinErr := JoinAndWait(servers[0], servers[1], DefaultBufferTimeout, DefaultJoinTimeout); joinErr != nil {
+		t.Fatalf("Unable to join servers, %v", joinErr)
+	}
+
+	// Server 1 is already connected to max inbound peers
+	smallTimeout := time.Second * 5
+	if joinErr := JoinAndWait(servers[2], servers[1], smallTimeout, smallTimeout); joinErr == nil {
+		t.Fatal("Peer join should've failed", joinErr)
+	}
+
+	servers[1].SetPeerLimits(2, 1)
+
+	if joinErr := JoinAndWait(servers[2], servers[1], DefaultBufferTimeout, DefaultJoinTimeout); joinErr != nil {
+		t.Fatalf("Unable to join servers, %v", joinErr)
+	}
+
+	assert.Equal(t, int64(2), servers[1].connectionCounts.GetInboundConnCount())
+}
+
 func TestConnLimit_Outbound(t *testing.T) {
 	// we should not try to make connections if we are already connected to max peers
 	defaultConfig := &CreateServerParams{
@@ -0,0 +1,190 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+)
+
+const (
+	// staticPeersDialInterval is the interval at which the disconnected static peers are dialed again
+	staticPeersDialInterval = 10 * time.Second
+
+	// staticPeerDialTimeout is the time a single dial of a static peer can take
+	staticPeerDialTimeout = 10 * time.Second
+)
+
+var (
+	ErrStaticPeerSelf = errors.New("static peer is the node itself")
+	ErrNotAStaticPeer = errors.New("peer is not a static peer")
+)
+
+// staticPeers are the peers the node always keeps connected to, regardless of its free connection slots.
+// They are trusted, so their connections aren't counted against the peer limits and they are never penalized
+type staticPeers struct {
+	lock  sync.RWMutex
+	peers map[peer.ID]*peer.AddrInfo
+}
+
+func newStaticPeers() *staticPeers {
+	return &staticPeers{
+		peers: make(map[peer.ID]*peer.AddrInfo),
+	}
+}
+
+// add adds the peer, and returns false if it was already a static peer
+func (sp *staticPeers) add(info *peer.AddrInfo) bool {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	_, exists := sp.peers[info.ID]
+	sp.peers[info.ID] = info
+
+	return !exists
+}
+
+// remove removes the peer, and returns false if it wasn't a static peer
+func (sp *staticPeers) remove(id peer.ID) bool {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	_, exists := sp.peers[id]
+	delete(sp.peers, id)
+
+	return exists
+}
+
+func (sp *staticPeers) has(id peer.ID) bool {
+	sp.lock.RLock()
+	defer sp.lock.RUnlock()
+
+	_, exists := sp.peers[id]
+
+	return exists
+}
+
+func (sp *staticPeers) list() []*peer.AddrInfo {
+	sp.lock.RLock()
+	defer sp.lock.RUnlock()
+
+	list := make([]*peer.AddrInfo, 0, len(sp.peers))
+	for _, info := range sp.peers {
+		list = append(list, info)
+	}
+
+	return list
+}
+
+// setupStaticPeers adds the static peers of the configuration
+func (s *Server) setupStaticPeers() error {
+	for _, rawAddr := range s.config.StaticPeers {
+		if err := s.AddStaticPeer(rawAddr); err != nil {
+			return fmt.Errorf("failed to add static peer %s: %w", rawAddr, err)
+		}
+	}
+
+	return nil
+}
+
+// AddStaticPeer adds the peer at the multiaddr to the static peers, and dials it
+// if it isn't connected yet. Adding a peer twice updates its addresses
+func (s *Server) AddStaticPeer(rawPeerMultiaddr string) error {
+	info, err := common.StringToAddrInfo(rawPeerMultiaddr)
+	if err != nil {
+		return err
+	}
+
+	if info.ID == s.host.ID() {
+		return ErrStaticPeerSelf
+	}
+
+	if s.staticPeers.add(info) {
+		s.logger.Info("Static peer added", "addr", info.String())
+	}
+
+	// the addresses are kept until the node is restarted, even if the peer disconnects
+	s.host.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.PermanentAddrTTL)
+
+	go s.dialStaticPeer(info)
+
+	return nil
+}
+
+// RemoveStaticPeer removes the peer at the multiaddr, or with the id, from the static peers
+// and disconnects from it
+func (s *Server) RemoveStaticPeer(rawPeer string) error {
+	id, err := peer.Decode(rawPeer)
+	if err != nil {
+		info, addrErr := common.StringToAddrInfo(rawPeer)
+		if addrErr != nil {
+			return addrErr
+		}
+
+		id = info.ID
+	}
+
+	if !s.staticPeers.remove(id) {
+		return ErrNotAStaticPeer
+	}
+
+	s.logger.Info("Static peer removed", "id", id)
+
+	s.DisconnectFromPeer(id, "static peer removed")
+
+	return nil
+}
+
+// IsStaticPeer checks if the peer is a static peer [Thread safe]
+func (s *Server) IsStaticPeer(peerID peer.ID) bool {
+	return s.staticPeers.has(peerID)
+}
+
+// StaticPeers returns the static peers [Thread safe]
+func (s *Server) StaticPeers() []*peer.AddrInfo {
+	return s.staticPeers.list()
+}
+
+// keepStaticPeerConnections dials the disconnected static peers at every interval.
+// The static peers are dialed directly instead of through the dial queue, which is only
+// popped while there are free outbound connection slots
+func (s *Server) keepStaticPeerConnections() {
+	for {
+		select {
+		case <-time.After(staticPeersDialInterval):
+		case <-s.closeCh:
+			return
+		}
+
+		for _, info := range s.staticPeers.list() {
+			s.dialStaticPeer(info)
+		}
+	}
+}
+
+// dialStaticPeer connects to the static peer, unless it is already connected
+func (s *Server) dialStaticPeer(info *peer.AddrInfo) {
+	if s.IsConnected(info.ID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), staticPeerDialTimeout)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.closeCh:
+			cancel()
+		}
+	}()
+
+	if err := s.host.Connect(ctx, *info); err != nil {
+		s.logger.Debug("failed to dial static peer", "addr", info.String(), "err", err)
+	}
+}
@@ -0,0 +1,77 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticPeers(t *testing.T) {
+	// the static peers are connected even if there are no free slots left
+	limitedConfig := &CreateServerParams{
+		ConfigCallback: func(c *Config) {
+			c.MaxInboundPeers = 1
+			c.MaxOutboundPeers = 1
+			c.NoDiscover = true
+		},
+	}
+
+	servers, createErr := createServers(3, map[int]*CreateServerParams{
+		0: limitedConfig,
+		1: limitedConfig,
+		2: limitedConfig,
+	})
+	require.NoError(t, createErr)
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	// Server 0 has no outbound slot left once connected to Server 1
+	require.NoError(t, JoinAndWait(servers[0], servers[1], DefaultBufferTimeout, DefaultJoinTimeout))
+	require.False(t, servers[0].HasFreeConnectionSlot(network.DirOutbound))
+
+	var (
+		selfAddr   = fmt.Sprintf("%s/p2p/%s", servers[0].addrs[0], servers[0].host.ID())
+		staticAddr = fmt.Sprintf("%s/p2p/%s", servers[2].addrs[0], servers[2].host.ID())
+	)
+
+	assert.ErrorIs(t, servers[0].AddStaticPeer(selfAddr), ErrStaticPeerSelf)
+	require.NoError(t, servers[0].AddStaticPeer(staticAddr))
+
+	connectCtx, connectFn := context.WithTimeout(context.Background(), DefaultJoinTimeout)
+	defer connectFn()
+
+	_, err := WaitUntilPeerConnectsTo(connectCtx, servers[0], servers[2].host.ID())
+	require.NoError(t, err)
+
+	// the static peer isn't counted against the limits
+	assert.Equal(t, int64(1), servers[0].connectionCounts.GetOutboundConnCount())
+	assert.True(t, servers[0].IsStaticPeer(servers[2].host.ID()))
+
+	statuses := make(map[string]*PeerStatus)
+	for _, status := range servers[0].PeerStatuses() {
+		statuses[status.Info.ID.String()] = status
+	}
+
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[servers[2].host.ID().String()].Static)
+	assert.True(t, statuses[servers[2].host.ID().String()].Outbound)
+	assert.False(t, statuses[servers[1].host.ID().String()].Static)
+
+	// removing the static peer disconnects from it
+	require.NoError(t, servers[0].RemoveStaticPeer(servers[2].host.ID().String()))
+	assert.ErrorIs(t, servers[0].RemoveStaticPeer(staticAddr), ErrNotAStaticPeer)
+
+	disconnectCtx, disconnectFn := context.WithTimeout(context.Background(), DefaultJoinTimeout)
+	defer disconnectFn()
+
+	_, err = WaitUntilPeerDisconnectsFrom(disconnectCtx, servers[0], servers[2].host.ID())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), servers[0].connectionCounts.GetOutboundConnCount())
+}
@@ -30,6 +30,8 @@ type MockNetworkingServer struct {
 	emitEventFn              emitEventDelegate
 	isTemporaryDialFn        isTemporaryDialDelegate
 	hasFreeConnectionSlotFn  hasFreeConnectionSlotDelegate
+	isStaticPeerFn           isStaticPeerDelegate
+	setPeerProtocolFn        setPeerProtocolDelegate
 
 	// Discovery Hooks
 	newDiscoveryClientFn       newDiscoveryClientDelegate
@@ -74,6 +76,8 @@ type updatePendingConnCountDelegate func(int64, network.Direction)
 type emitEventDelegate func(*event.PeerEvent)
 type isTemporaryDialDelegate func(peer.ID) bool
 type hasFreeConnectionSlotDelegate func(network.Direction) bool
+type isStaticPeerDelegate func(peer.ID) bool
+type setPeerProtocolDelegate func(peer.ID, uint64, []string)
 
 // Required for Discovery
 type getRandomBootnodeDelegate func() *peer.AddrInfo
@@ -174,6 +178,28 @@ func (m *MockNetworkingServer) HookHasFreeConnectionSlot(fn hasFreeConnectionSlo
 	m.hasFreeConnectionSlotFn = fn
 }
 
+func (m *MockNetworkingServer) IsStaticPeer(peerID peer.ID) bool {
+	if m.isStaticPeerFn != nil {
+		return m.isStaticPeerFn(peerID)
+	}
+
+	return false
+}
+
+func (m *MockNetworkingServer) HookIsStaticPeer(fn isStaticPeerDelegate) {
+	m.isStaticPeerFn = fn
+}
+
+func (m *MockNetworkingServer) SetPeerProtocol(peerID peer.ID, version uint64, capabilities []string) {
+	if m.setPeerProtocolFn != nil {
+		m.setPeerProtocolFn(peerID, version, capabilities)
+	}
+}
+
+func (m *MockNetworkingServer) HookSetPeerProtocol(fn setPeerProtocolDelegate) {
+	m.setPeerProtocolFn = fn
+}
+
 func (m *MockNetworkingServer) GetRandomBootnode() *peer.AddrInfo {
 	if m.getRandomBootnodeFn != nil {
 		return m.getRandomBootnodeFn()
@@ -0,0 +1,504 @@
+package replica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// replicaSource is the source of the blocks written by the replica
+	replicaSource = "replica"
+
+	// the bounds of the delay before reconnecting to the primary, doubled on every failed attempt
+	minRetryInterval = time.Second
+	maxRetryInterval = 30 * time.Second
+
+	// requestTimeout bounds every request to the primary
+	requestTimeout = 10 * time.Second
+)
+
+var (
+	ErrChainIDMismatch = errors.New("the chain ID of the primary doesn't match the local chain ID")
+	ErrGenesisMismatch = errors.New("the genesis of the primary doesn't match the local genesis")
+	ErrDiverged        = errors.New("the chain of the primary diverged from the local chain")
+)
+
+// Config holds the address of the primary and the marks after which the replica is stale
+type Config struct {
+	// PrimaryAddr is the gRPC address of the primary node
+	PrimaryAddr string
+
+	// MaxLag is the number of blocks the replica can be behind the primary
+	MaxLag uint64
+
+	// StaleTimeout is the time the replica can go without reaching the primary
+	StaleTimeout time.Duration
+}
+
+type Blockchain interface {
+	Config() *chain.Params
+	Header() *types.Header
+	Genesis() types.Hash
+	SubscribeEvents() blockchain.Subscription
+	VerifyFinalizedBlock(*types.Block) error
+	WriteBlock(*types.Block, string) error
+	SetFinalized(*types.Header) error
+}
+
+type TxPool interface {
+	ResetWithHeaders(headers ...*types.Header)
+}
+
+// Replica keeps a copy of the chain of a primary node, so the processes serving the JSON-RPC scale out
+// without running the consensus or syncing from the peers. A database can't be shared by the processes,
+// so the replica follows the blocks of the primary over its gRPC server and writes them to its own database.
+// It catches up on every block announced by the primary, and on reconnecting after losing it
+type Replica struct {
+	logger     hclog.Logger
+	config     *Config
+	blockchain Blockchain
+	txpool     TxPool
+
+	conn   *grpc.ClientConn
+	client proto.SystemClient
+
+	progression *progress.ProgressionWrapper
+
+	// catchUpCh wakes up the replica once the primary is found ahead outside of its announcements
+	catchUpCh chan struct{}
+
+	lock        sync.RWMutex
+	primaryHead uint64
+	lastContact time.Time
+	stale       bool
+
+	// failure is the error which stopped the replica, once the primary was found on another chain
+	failure error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewReplica creates the replica of the primary at the configured address
+func NewReplica(logger hclog.Logger, config *Config, blockchain Blockchain, txpool TxPool) (*Replica, error) {
+	conn, err := grpc.Dial(config.PrimaryAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the primary %s: %w", config.PrimaryAddr, err)
+	}
+
+	r := newReplica(logger, config, blockchain, txpool, proto.NewSystemClient(conn))
+	r.conn = conn
+
+	return r, nil
+}
+
+func newReplica(
+	logger hclog.Logger,
+	config *Config,
+	blockchain Blockchain,
+	txpool TxPool,
+	client proto.SystemClient,
+) *Replica {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Replica{
+		logger:      logger.Named("replica"),
+		config:      config,
+		blockchain:  blockchain,
+		txpool:      txpool,
+		client:      client,
+		progression: progress.NewProgressionWrapper(progress.ChainSyncReplica),
+		catchUpCh:   make(chan struct{}, 1),
+		lastContact: time.Now(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start checks the primary runs the same chain, and starts following it.
+// It fails if the primary is reached on another chain, and retries later if it isn't reached
+func (r *Replica) Start() error {
+	if err := r.verifyPrimary(r.ctx); isChainMismatch(err) {
+		return err
+	} else if err != nil {
+		r.logger.Warn("failed to reach the primary", "err", err)
+	}
+
+	r.logger.Info("following the primary", "addr", r.config.PrimaryAddr)
+
+	r.wg.Add(2)
+
+	go r.run()
+	go r.watch()
+
+	return nil
+}
+
+// Close stops following the primary
+func (r *Replica) Close() {
+	r.cancel()
+	r.wg.Wait()
+
+	if r.conn != nil {
+		if err := r.conn.Close(); err != nil {
+			r.logger.Error("failed to close the connection to the primary", "err", err)
+		}
+	}
+}
+
+// Lag returns the number of blocks the replica is behind the primary, as last seen
+func (r *Replica) Lag() uint64 {
+	r.lock.RLock()
+	primaryHead := r.primaryHead
+	r.lock.RUnlock()
+
+	if head := r.blockchain.Header().Number; primaryHead > head {
+		return primaryHead - head
+	}
+
+	return 0
+}
+
+// IsStale returns true if the replica is too far behind the primary, hasn't reached it for too long,
+// or stopped following it
+func (r *Replica) IsStale() bool {
+	r.lock.RLock()
+	lastContact, failure := r.lastContact, r.failure
+	r.lock.RUnlock()
+
+	return failure != nil || r.Lag() > r.config.MaxLag || time.Since(lastContact) > r.config.StaleTimeout
+}
+
+// GetSyncProgression returns the progression of the catch-up with the primary. A stale replica
+// reports its progression towards the last seen head of the primary, so it is taken out of the rotation
+func (r *Replica) GetSyncProgression() *progress.Progression {
+	if p := r.progression.GetProgression(); p != nil {
+		return p
+	}
+
+	if !r.IsStale() {
+		return nil
+	}
+
+	head := r.blockchain.Header().Number
+
+	return &progress.Progression{
+		SyncType:      progress.ChainSyncReplica,
+		StartingBlock: head,
+		CurrentBlock:  head,
+		HighestBlock:  head + r.Lag(),
+	}
+}
+
+// run follows the primary, and reconnects once it is lost
+func (r *Replica) run() {
+	defer r.wg.Done()
+
+	retryInterval := minRetryInterval
+
+	for {
+		connected, err := r.follow()
+		if r.ctx.Err() != nil {
+			return
+		}
+
+		// the primary was replaced by a node of another chain, following it would write its blocks
+		if isChainMismatch(err) {
+			r.logger.Error("stopped following the primary", "err", err)
+
+			r.lock.Lock()
+			r.failure = err
+			r.lock.Unlock()
+
+			r.updateStale()
+
+			return
+		}
+
+		if connected {
+			retryInterval = minRetryInterval
+		}
+
+		r.logger.Warn("lost the primary", "err", err, "retry", retryInterval)
+
+		select {
+		case <-time.After(retryInterval):
+		case <-r.ctx.Done():
+			return
+		}
+
+		if retryInterval *= 2; retryInterval > maxRetryInterval {
+			retryInterval = maxRetryInterval
+		}
+	}
+}
+
+// follow catches up with the primary and writes the blocks it announces until the stream is lost,
+// returning whether the stream was established
+func (r *Replica) follow() (bool, error) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+
+	if err := r.verifyPrimary(ctx); err != nil {
+		return false, err
+	}
+
+	// subscribe before getting the head of the primary, so no block is missed in between
+	stream, err := r.client.Subscribe(ctx, &empty.Empty{})
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.updatePrimaryHead(ctx); err != nil {
+		return false, err
+	}
+
+	eventCh := make(chan *proto.BlockchainEvent)
+	errCh := make(chan error, 1)
+
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				errCh <- err
+
+				return
+			}
+
+			select {
+			case eventCh <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		if err := r.catchUp(ctx); err != nil {
+			return true, err
+		}
+
+		select {
+		case event := <-eventCh:
+			for _, h := range event.Added {
+				r.contact(uint64(h.Number))
+			}
+		case <-r.catchUpCh:
+		case err := <-errCh:
+			return true, err
+		case <-ctx.Done():
+			return true, nil
+		}
+	}
+}
+
+// verifyPrimary checks the primary runs the same chain, on every connection
+func (r *Replica) verifyPrimary(ctx context.Context) error {
+	statusCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	status, err := r.client.GetStatus(statusCtx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+
+	if chainID := int64(r.blockchain.Config().ChainID); status.Network != chainID {
+		return fmt.Errorf("%w: %d != %d", ErrChainIDMismatch, status.Network, chainID)
+	}
+
+	// the primaries which don't report their genesis are checked against their first block
+	genesis := types.StringToHash(status.Genesis)
+
+	if status.Genesis == "" {
+		block, err := r.getBlock(ctx, 0)
+		if err != nil {
+			return err
+		}
+
+		genesis = block.Hash()
+	}
+
+	if genesis != r.blockchain.Genesis() {
+		return fmt.Errorf("%w: %s != %s", ErrGenesisMismatch, genesis, r.blockchain.Genesis())
+	}
+
+	return nil
+}
+
+func isChainMismatch(err error) bool {
+	return errors.Is(err, ErrChainIDMismatch) || errors.Is(err, ErrGenesisMismatch)
+}
+
+// catchUp writes the blocks of the primary up to its last seen head
+func (r *Replica) catchUp(ctx context.Context) error {
+	head := r.blockchain.Header()
+
+	r.lock.RLock()
+	target := r.primaryHead
+	r.lock.RUnlock()
+
+	if target <= head.Number {
+		return nil
+	}
+
+	// report the catch-ups of more than a block as a sync
+	if target > head.Number+1 {
+		r.logger.Info("catching up with the primary", "from", head.Number+1, "to", target)
+
+		r.progression.StartProgression(head.Number+1, r.blockchain.SubscribeEvents())
+		defer r.progression.StopProgression()
+
+		r.progression.UpdateHighestProgression(target)
+	}
+
+	for number := head.Number + 1; number <= target; number++ {
+		block, err := r.getBlock(ctx, number)
+		if err != nil {
+			return err
+		}
+
+		// the finalized blocks are never replaced, the replica doesn't follow a reorg
+		if block.ParentHash() != head.Hash {
+			return fmt.Errorf("%w at block %d", ErrDiverged, number)
+		}
+
+		if err := r.blockchain.VerifyFinalizedBlock(block); err != nil {
+			return fmt.Errorf("failed to verify block %d: %w", number, err)
+		}
+
+		if err := r.blockchain.WriteBlock(block, replicaSource); err != nil {
+			return fmt.Errorf("failed to write block %d: %w", number, err)
+		}
+
+		if err := r.blockchain.SetFinalized(block.Header); err != nil {
+			return fmt.Errorf("failed to finalize block %d: %w", number, err)
+		}
+
+		r.txpool.ResetWithHeaders(block.Header)
+
+		head = block.Header
+	}
+
+	r.updateStale()
+
+	return nil
+}
+
+func (r *Replica) getBlock(ctx context.Context, number uint64) (*types.Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	resp, err := r.client.BlockByNumber(ctx, &proto.BlockByNumberRequest{Number: number})
+	if err != nil {
+		return nil, err
+	}
+
+	block := &types.Block{}
+	if err := block.UnmarshalRLP(resp.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode block %d: %w", number, err)
+	}
+
+	return block, nil
+}
+
+// updatePrimaryHead asks the primary for its head
+func (r *Replica) updatePrimaryHead(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	status, err := r.client.GetStatus(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+
+	r.contact(uint64(status.Current.Number))
+
+	return nil
+}
+
+// contact records the primary was reached, with its head
+func (r *Replica) contact(primaryHead uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if primaryHead > r.primaryHead {
+		r.primaryHead = primaryHead
+	}
+
+	r.lastContact = time.Now()
+}
+
+// watch polls the head of the primary to detect the staleness of the replica while no block is announced,
+// and wakes up the replica if a block was missed
+func (r *Replica) watch() {
+	defer r.wg.Done()
+
+	interval := r.config.StaleTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.ctx.Done():
+			return
+		}
+
+		if err := r.updatePrimaryHead(r.ctx); err != nil {
+			r.logger.Debug("failed to get the head of the primary", "err", err)
+		}
+
+		if r.Lag() > 0 {
+			select {
+			case r.catchUpCh <- struct{}{}:
+			default:
+			}
+		}
+
+		r.updateStale()
+	}
+}
+
+// updateStale reports the lag and the staleness of the replica
+func (r *Replica) updateStale() {
+	lag, stale := r.Lag(), r.IsStale()
+
+	metrics.SetGauge([]string{"replica", "lag"}, float32(lag))
+
+	r.lock.Lock()
+	changed := stale != r.stale
+	r.stale = stale
+	r.lock.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if stale {
+		metrics.SetGauge([]string{"replica", "stale"}, 1)
+		r.logger.Warn("the replica is stale", "lag", lag)
+	} else {
+		metrics.SetGauge([]string{"replica", "stale"}, 0)
+		r.logger.Info("the replica caught up with the primary")
+	}
+}
@@ -0,0 +1,366 @@
+package replica
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// newTestChain returns a chain of blocks, the ones after the fork have another extra data
+func newTestChain(length int, forkAt int) []*types.Block {
+	blocks := make([]*types.Block, length)
+	parent := types.ZeroHash
+
+	for i := range blocks {
+		header := &types.Header{Number: uint64(i), ParentHash: parent, ExtraData: []byte{}}
+		if forkAt > 0 && i >= forkAt {
+			header.ExtraData = []byte{1}
+		}
+
+		header.ComputeHash()
+		blocks[i] = &types.Block{Header: header}
+		parent = header.Hash
+	}
+
+	return blocks
+}
+
+const testChainID = 100
+
+type mockBlockchain struct {
+	lock      sync.Mutex
+	blocks    []*types.Block
+	finalized uint64
+}
+
+func (m *mockBlockchain) Config() *chain.Params {
+	return &chain.Params{ChainID: testChainID}
+}
+
+func (m *mockBlockchain) Header() *types.Header {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockBlockchain) Genesis() types.Hash {
+	return m.blocks[0].Hash()
+}
+
+func (m *mockBlockchain) SubscribeEvents() blockchain.Subscription {
+	return blockchain.NewMockSubscription()
+}
+
+func (m *mockBlockchain) VerifyFinalizedBlock(*types.Block) error {
+	return nil
+}
+
+func (m *mockBlockchain) WriteBlock(block *types.Block, _ string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.blocks = append(m.blocks, block)
+
+	return nil
+}
+
+func (m *mockBlockchain) SetFinalized(header *types.Header) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.finalized = header.Number
+
+	return nil
+}
+
+type mockTxPool struct {
+	lock  sync.Mutex
+	reset []uint64
+}
+
+func (m *mockTxPool) ResetWithHeaders(headers ...*types.Header) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, h := range headers {
+		m.reset = append(m.reset, h.Number)
+	}
+}
+
+// mockSubscribeClient streams the events sent to it, until it is closed
+type mockSubscribeClient struct {
+	grpc.ClientStream
+
+	ctx    context.Context
+	events chan *proto.BlockchainEvent
+}
+
+func (m *mockSubscribeClient) Recv() (*proto.BlockchainEvent, error) {
+	select {
+	case event, ok := <-m.events:
+		if !ok {
+			return nil, io.EOF
+		}
+
+		return event, nil
+	case <-m.ctx.Done():
+		return nil, m.ctx.Err()
+	}
+}
+
+// mockClient is a primary serving its blocks
+type mockClient struct {
+	proto.SystemClient
+
+	lock    sync.Mutex
+	chainID int64
+	blocks  []*types.Block
+	streams chan *mockSubscribeClient
+
+	// hideGenesis stands for a primary which doesn't report its genesis
+	hideGenesis bool
+}
+
+func newMockClient(blocks []*types.Block) *mockClient {
+	return &mockClient{
+		chainID: testChainID,
+		blocks:  blocks,
+		streams: make(chan *mockSubscribeClient, 8),
+	}
+}
+
+func (m *mockClient) GetStatus(context.Context, *empty.Empty, ...grpc.CallOption) (*proto.ServerStatus, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	head := m.blocks[len(m.blocks)-1]
+
+	status := &proto.ServerStatus{
+		Network: m.chainID,
+		Current: &proto.ServerStatus_Block{Number: int64(head.Number()), Hash: head.Hash().String()},
+	}
+
+	if !m.hideGenesis {
+		status.Genesis = m.blocks[0].Hash().String()
+	}
+
+	return status, nil
+}
+
+func (m *mockClient) BlockByNumber(
+	_ context.Context,
+	req *proto.BlockByNumberRequest,
+	_ ...grpc.CallOption,
+) (*proto.BlockResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return &proto.BlockResponse{Data: m.blocks[req.Number].MarshalRLP()}, nil
+}
+
+func (m *mockClient) Subscribe(
+	ctx context.Context,
+	_ *empty.Empty,
+	_ ...grpc.CallOption,
+) (proto.System_SubscribeClient, error) {
+	stream := &mockSubscribeClient{ctx: ctx, events: make(chan *proto.BlockchainEvent)}
+	m.streams <- stream
+
+	return stream, nil
+}
+
+// produce appends a block to the chain of the primary, and returns its announcement
+func (m *mockClient) produce(block *types.Block) *proto.BlockchainEvent {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.blocks = append(m.blocks, block)
+
+	return &proto.BlockchainEvent{
+		Added: []*proto.BlockchainEvent_Header{{Number: int64(block.Number()), Hash: block.Hash().String()}},
+	}
+}
+
+func newTestReplica(t *testing.T, local, primary []*types.Block) (*Replica, *mockBlockchain, *mockTxPool, *mockClient) {
+	t.Helper()
+
+	chain := &mockBlockchain{blocks: local}
+	pool := &mockTxPool{}
+	client := newMockClient(primary)
+
+	r := newReplica(
+		hclog.NewNullLogger(),
+		&Config{MaxLag: 2, StaleTimeout: time.Minute},
+		chain,
+		pool,
+		client,
+	)
+
+	return r, chain, pool, client
+}
+
+func TestReplica_CatchUp(t *testing.T) {
+	t.Parallel()
+
+	blocks := newTestChain(10, 0)
+	r, chain, pool, client := newTestReplica(t, blocks[:3], blocks)
+
+	require.NoError(t, r.verifyPrimary(context.Background()))
+	require.NoError(t, r.updatePrimaryHead(context.Background()))
+
+	assert.Equal(t, uint64(7), r.Lag())
+	assert.True(t, r.IsStale())
+
+	// a stale replica reports itself syncing towards the primary
+	p := r.GetSyncProgression()
+	require.NotNil(t, p)
+	assert.Equal(t, progress.ChainSyncReplica, p.SyncType)
+	assert.Equal(t, uint64(2), p.CurrentBlock)
+	assert.Equal(t, uint64(9), p.HighestBlock)
+
+	require.NoError(t, r.catchUp(context.Background()))
+
+	assert.Equal(t, blocks, chain.blocks)
+	assert.Equal(t, uint64(9), chain.finalized)
+	assert.Equal(t, []uint64{3, 4, 5, 6, 7, 8, 9}, pool.reset)
+	assert.Zero(t, r.Lag())
+	assert.False(t, r.IsStale())
+	assert.Nil(t, r.GetSyncProgression())
+
+	// nothing more to write
+	client.produce(newTestChain(11, 0)[10])
+	require.NoError(t, r.catchUp(context.Background()))
+	assert.Len(t, chain.blocks, 10)
+}
+
+func TestReplica_Diverged(t *testing.T) {
+	t.Parallel()
+
+	r, chain, _, _ := newTestReplica(t, newTestChain(5, 0), newTestChain(10, 3))
+
+	require.NoError(t, r.updatePrimaryHead(context.Background()))
+	assert.ErrorIs(t, r.catchUp(context.Background()), ErrDiverged)
+	assert.Len(t, chain.blocks, 5)
+}
+
+func TestReplica_GenesisMismatch(t *testing.T) {
+	t.Parallel()
+
+	primary := newTestChain(3, 0)
+	primary[0].Header.ExtraData = []byte{1}
+	primary[0].Header.ComputeHash()
+
+	r, _, _, client := newTestReplica(t, newTestChain(3, 0), primary)
+
+	assert.ErrorIs(t, r.verifyPrimary(context.Background()), ErrGenesisMismatch)
+
+	// the genesis of the primary is fetched if it isn't reported
+	client.hideGenesis = true
+	assert.ErrorIs(t, r.verifyPrimary(context.Background()), ErrGenesisMismatch)
+
+	// the replica doesn't start on another chain
+	assert.ErrorIs(t, r.Start(), ErrGenesisMismatch)
+}
+
+func TestReplica_ChainIDMismatch(t *testing.T) {
+	t.Parallel()
+
+	blocks := newTestChain(5, 0)
+	r, chain, _, client := newTestReplica(t, blocks[:2], blocks)
+
+	require.NoError(t, r.Start())
+	defer r.Close()
+
+	stream := <-client.streams
+
+	assert.Eventually(t, func() bool {
+		return chain.Header().Number == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the primary is replaced by a node of another chain, the replica stops following it
+	client.lock.Lock()
+	client.chainID = testChainID + 1
+	client.lock.Unlock()
+
+	close(stream.events)
+
+	assert.Eventually(t, func() bool {
+		r.lock.RLock()
+		defer r.lock.RUnlock()
+
+		return errors.Is(r.failure, ErrChainIDMismatch)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.True(t, r.IsStale())
+}
+
+func TestReplica_StaleTimeout(t *testing.T) {
+	t.Parallel()
+
+	blocks := newTestChain(3, 0)
+	r, _, _, _ := newTestReplica(t, blocks, blocks)
+
+	assert.False(t, r.IsStale())
+
+	// the primary wasn't reached for too long
+	r.lastContact = time.Now().Add(-2 * time.Minute)
+	assert.True(t, r.IsStale())
+
+	r.contact(2)
+	assert.False(t, r.IsStale())
+}
+
+func TestReplica_Follow(t *testing.T) {
+	t.Parallel()
+
+	blocks := newTestChain(8, 0)
+	r, chain, _, client := newTestReplica(t, blocks[:2], blocks[:4])
+
+	require.NoError(t, r.Start())
+	defer r.Close()
+
+	waitHead := func(number uint64) {
+		t.Helper()
+
+		assert.Eventually(t, func() bool {
+			return chain.Header().Number == number
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// the replica catches up once subscribed
+	stream := <-client.streams
+	waitHead(3)
+
+	// and follows the announced blocks
+	stream.events <- client.produce(blocks[4])
+	waitHead(4)
+
+	// the primary is lost, and it catches up once reconnected
+	close(stream.events)
+	client.produce(blocks[5])
+	client.produce(blocks[6])
+
+	stream = <-client.streams
+	waitHead(6)
+
+	stream.events <- client.produce(blocks[7])
+	waitHead(7)
+
+	assert.Equal(t, blocks, chain.blocks)
+}
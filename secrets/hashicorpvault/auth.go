@@ -0,0 +1,232 @@
+package hashicorpvault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+type (
+	configExtraParamFields string
+	authMethodType         string
+)
+
+const (
+	// authMethod is the method the node authenticates with, the static token by default
+	authMethod configExtraParamFields = "auth-method"
+	// authRole is the Vault role the node logs in as with the kubernetes and jwt methods
+	authRole configExtraParamFields = "auth-role"
+	// authMount is the path the auth method is mounted on, the name of the method by default
+	authMount configExtraParamFields = "auth-mount"
+	// jwtPath is the file the JWT is read from on each login
+	jwtPath configExtraParamFields = "jwt-path"
+)
+
+const (
+	// tokenAuth uses the token of the config as is
+	tokenAuth authMethodType = "token"
+	// kubernetesAuth logs in with the service account token of the pod
+	kubernetesAuth authMethodType = "kubernetes"
+	// jwtAuth logs in with a JWT signed by a trusted issuer
+	jwtAuth authMethodType = "jwt"
+)
+
+const (
+	// defaultServiceAccountTokenPath is where the service account token is mounted in the pods
+	//nolint:gosec
+	defaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// reloginInterval is the time waited before retrying a failed login
+	reloginInterval = 10 * time.Second
+)
+
+var (
+	errUnsupportedAuthMethod = errors.New("unsupported auth method for Vault secrets manager")
+	errNoAuthRole            = fmt.Errorf("no %s specified for Vault secrets manager", authRole)
+	errNoJWTPath             = fmt.Errorf("no %s specified for Vault secrets manager", jwtPath)
+)
+
+// jwtLogin logs in with a JWT read from a file, the kubernetes and jwt auth methods
+// sharing the same login request
+type jwtLogin struct {
+	// mount is the path the auth method is mounted on
+	mount string
+
+	// role is the Vault role to log in as
+	role string
+
+	// path is the file the JWT is read from, re-read on each login as it may be rotated
+	path string
+}
+
+// newAuthMethod returns the login of the auth method set in the extra fields of the config,
+// nil if the token of the config is used as is
+func newAuthMethod(extra map[string]interface{}) (vault.AuthMethod, error) {
+	method := authMethodType(extraString(extra, authMethod))
+
+	switch method {
+	case "", tokenAuth:
+		return nil, nil
+	case kubernetesAuth, jwtAuth:
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedAuthMethod, method)
+	}
+
+	login := &jwtLogin{
+		mount: extraString(extra, authMount),
+		role:  extraString(extra, authRole),
+		path:  extraString(extra, jwtPath),
+	}
+
+	if login.mount == "" {
+		login.mount = string(method)
+	}
+
+	if login.role == "" {
+		return nil, errNoAuthRole
+	}
+
+	if login.path == "" {
+		if method != kubernetesAuth {
+			return nil, errNoJWTPath
+		}
+
+		login.path = defaultServiceAccountTokenPath
+	}
+
+	return login, nil
+}
+
+// Login implements vault.AuthMethod
+func (l *jwtLogin) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	jwt, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read JWT, %w", err)
+	}
+
+	return client.Logical().WriteWithContext(
+		ctx,
+		fmt.Sprintf("auth/%s/login", strings.Trim(l.mount, "/")),
+		map[string]interface{}{
+			"role": l.role,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		},
+	)
+}
+
+// extraString returns the string value of the extra field, empty if not set
+func extraString(extra map[string]interface{}, field configExtraParamFields) string {
+	value, ok := extra[string(field)]
+	if !ok || value == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// authenticate sets the token of the client, logging in with the auth method if any.
+// It returns the secret of the token to renew, nil if the token can't be renewed
+func (v *VaultSecretsManager) authenticate(ctx context.Context) (*vault.Secret, error) {
+	if v.authMethod != nil {
+		secret, err := v.client.Auth().Login(ctx, v.authMethod)
+		if err != nil {
+			return nil, fmt.Errorf("unable to log in to Vault, %w", err)
+		}
+
+		if !secret.Auth.Renewable {
+			return nil, nil
+		}
+
+		return secret, nil
+	}
+
+	v.client.SetToken(v.token)
+
+	// the static token is renewed only if it's a renewable one with a TTL, unlike the root tokens
+	self, err := v.client.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		v.logger.Warn("unable to look up Vault token, it won't be renewed", "err", err)
+
+		return nil, nil
+	}
+
+	if renewable, _ := self.TokenIsRenewable(); !renewable {
+		return nil, nil
+	}
+
+	if ttl, _ := self.TokenTTL(); ttl > 0 {
+		return &vault.Secret{
+			Auth: &vault.SecretAuth{
+				ClientToken:   v.token,
+				Renewable:     true,
+				LeaseDuration: int(ttl.Seconds()),
+			},
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// keepTokenAlive renews the token until it reaches its maximum TTL, logging in again afterwards
+// if the auth method allows it
+func (v *VaultSecretsManager) keepTokenAlive(secret *vault.Secret) {
+	for secret != nil {
+		watcher, err := v.client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{
+			Secret: secret,
+		})
+		if err != nil {
+			v.logger.Error("unable to watch Vault token", "err", err)
+
+			return
+		}
+
+		go watcher.Start()
+
+		v.watchToken(watcher)
+		watcher.Stop()
+
+		if v.authMethod == nil {
+			v.logger.Warn("Vault token can no longer be renewed")
+
+			return
+		}
+
+		secret = v.relogin()
+	}
+}
+
+// watchToken logs the renewals of the token until the watcher is done
+func (v *VaultSecretsManager) watchToken(watcher *vault.LifetimeWatcher) {
+	for {
+		select {
+		case err := <-watcher.DoneCh():
+			if err != nil {
+				v.logger.Warn("failed to renew Vault token", "err", err)
+			}
+
+			return
+		case renewal := <-watcher.RenewCh():
+			v.logger.Debug("renewed Vault token", "at", renewal.RenewedAt)
+		}
+	}
+}
+
+// relogin logs in until it succeeds, returning the secret of the new token
+func (v *VaultSecretsManager) relogin() *vault.Secret {
+	for {
+		secret, err := v.authenticate(context.Background())
+		if err == nil {
+			v.logger.Info("logged in to Vault again")
+
+			return secret
+		}
+
+		v.logger.Error("failed to log in to Vault, retrying", "err", err, "in", reloginInterval)
+		time.Sleep(reloginInterval)
+	}
+}
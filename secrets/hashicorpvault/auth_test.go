@@ -0,0 +1,148 @@
+package hashicorpvault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/secrets"
+)
+
+func TestNewAuthMethod(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		extra map[string]interface{}
+		login *jwtLogin
+		err   error
+	}{
+		{
+			name:  "token by default",
+			extra: map[string]interface{}{},
+		},
+		{
+			name: "kubernetes with the service account token",
+			extra: map[string]interface{}{
+				"auth-method": "kubernetes",
+				"auth-role":   "validator",
+			},
+			login: &jwtLogin{mount: "kubernetes", role: "validator", path: defaultServiceAccountTokenPath},
+		},
+		{
+			name: "jwt on a custom mount",
+			extra: map[string]interface{}{
+				"auth-method": "jwt",
+				"auth-role":   "validator",
+				"auth-mount":  "oidc",
+				"jwt-path":    "/tmp/jwt",
+			},
+			login: &jwtLogin{mount: "oidc", role: "validator", path: "/tmp/jwt"},
+		},
+		{
+			name: "jwt without path",
+			extra: map[string]interface{}{
+				"auth-method": "jwt",
+				"auth-role":   "validator",
+			},
+			err: errNoJWTPath,
+		},
+		{
+			name: "no role",
+			extra: map[string]interface{}{
+				"auth-method": "kubernetes",
+			},
+			err: errNoAuthRole,
+		},
+		{
+			name: "unsupported method",
+			extra: map[string]interface{}{
+				"auth-method": "userpass",
+			},
+			err: errUnsupportedAuthMethod,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			method, err := newAuthMethod(test.extra)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if test.login == nil {
+				assert.Nil(t, method)
+			} else {
+				assert.Equal(t, test.login, method)
+			}
+		})
+	}
+}
+
+func TestVaultSecretsManager_KubernetesLogin(t *testing.T) {
+	t.Parallel()
+
+	jwtFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(jwtFile, []byte("service-account-jwt\n"), 0600))
+
+	var (
+		login map[string]interface{}
+		token string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/k8s/login":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&login))
+
+			_, _ = w.Write([]byte(`{"auth": {"client_token": "issued", "renewable": false, "lease_duration": 60}}`))
+		case "/v1/secret/data/node/validator-key":
+			token = r.Header.Get("X-Vault-Token")
+
+			_, _ = w.Write([]byte(`{"data": {"data": {"validator-key": "key"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	manager, err := SecretsManagerFactory(
+		&secrets.SecretsManagerConfig{
+			ServerURL: server.URL,
+			Type:      secrets.HashicorpVault,
+			Name:      "node",
+			Extra: map[string]interface{}{
+				"auth-method": "kubernetes",
+				"auth-role":   "validator",
+				"auth-mount":  "k8s",
+				"jwt-path":    jwtFile,
+			},
+		},
+		&secrets.SecretsManagerParams{Logger: hclog.NewNullLogger()},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"role": "validator", "jwt": "service-account-jwt"}, login)
+
+	value, err := manager.GetSecret(secrets.ValidatorKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key"), value)
+
+	// the secrets are read with the token issued on login
+	assert.Equal(t, "issued", token)
+}
@@ -1,6 +1,7 @@
 package hashicorpvault
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -32,6 +33,9 @@ type VaultSecretsManager struct {
 
 	// The namespace under which the secrets are stored
 	namespace string
+
+	// The auth method logging in to get the token, nil if the token is used as is
+	authMethod vault.AuthMethod
 }
 
 // SecretsManagerFactory implements the factory method
@@ -44,8 +48,16 @@ func SecretsManagerFactory(
 		logger: params.Logger.Named(string(secrets.HashicorpVault)),
 	}
 
-	// Check if the token is present
-	if config.Token == "" {
+	// Grab the auth method from the config
+	authMethod, err := newAuthMethod(config.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	vaultManager.authMethod = authMethod
+
+	// Check if the token is present, unless the node logs in to get one
+	if authMethod == nil && config.Token == "" {
 		return nil, errors.New("no token specified for Vault secrets manager")
 	}
 
@@ -75,7 +87,9 @@ func SecretsManagerFactory(
 	vaultManager.basePath = fmt.Sprintf("secret/data/%s", vaultManager.name)
 
 	// Run the initial setup
-	_ = vaultManager.Setup()
+	if err := vaultManager.Setup(); err != nil {
+		return nil, err
+	}
 
 	return vaultManager, nil
 }
@@ -92,14 +106,22 @@ func (v *VaultSecretsManager) Setup() error {
 		return fmt.Errorf("unable to initialize Vault client: %w", err)
 	}
 
-	// Set the access token
-	client.SetToken(v.token)
-
 	// Set the namespace
 	client.SetNamespace(v.namespace)
 
 	v.client = client
 
+	// Set the access token, logging in if needed
+	secret, err := v.authenticate(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// Keep the token renewed in the background, so no long-lived token is needed
+	if secret != nil {
+		go v.keepTokenAlive(secret)
+	}
+
 	return nil
 }
 
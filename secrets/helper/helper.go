@@ -12,6 +12,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/secrets/gcpssm"
 	"github.com/0xPolygon/polygon-edge/secrets/hashicorpvault"
 	"github.com/0xPolygon/polygon-edge/secrets/local"
+	"github.com/0xPolygon/polygon-edge/secrets/pkcs11"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
@@ -20,12 +21,19 @@ import (
 
 // SetupLocalSecretsManager is a helper method for boilerplate local secrets manager setup
 func SetupLocalSecretsManager(dataDir string) (secrets.SecretsManager, error) {
+	return SetupEncryptedLocalSecretsManager(dataDir, "")
+}
+
+// SetupEncryptedLocalSecretsManager is a helper method for boilerplate setup of
+// the local secrets manager encrypting the secrets by the passphrase
+func SetupEncryptedLocalSecretsManager(dataDir, passphrase string) (secrets.SecretsManager, error) {
 	return local.SecretsManagerFactory(
 		nil, // Local secrets manager doesn't require a config
 		&secrets.SecretsManagerParams{
 			Logger: hclog.NewNullLogger(),
 			Extra: map[string]interface{}{
-				secrets.Path: dataDir,
+				secrets.Path:       dataDir,
+				secrets.Passphrase: passphrase,
 			},
 		},
 	)
@@ -73,6 +81,16 @@ func InitECDSAValidatorKey(secretsManager secrets.SecretsManager) (types.Address
 		return types.ZeroAddress, fmt.Errorf(`secrets "%s" has been already initialized`, secrets.ValidatorKey)
 	}
 
+	// the key is generated on the device keeping it
+	if device, ok := secretsManager.(secrets.ECDSASigner); ok {
+		pub, err := device.GenerateECDSAKey(secrets.ValidatorKey)
+		if err != nil {
+			return types.ZeroAddress, err
+		}
+
+		return crypto.PubKeyToAddress(pub), nil
+	}
+
 	validatorKey, validatorKeyEncoded, err := crypto.GenerateAndEncodeECDSAPrivateKey()
 	if err != nil {
 		return types.ZeroAddress, err
@@ -145,6 +163,15 @@ func LoadValidatorAddress(secretsManager secrets.SecretsManager) (types.Address,
 		return types.ZeroAddress, nil
 	}
 
+	if device, ok := secretsManager.(secrets.ECDSASigner); ok {
+		pub, err := device.ECDSAPublicKey(secrets.ValidatorKey)
+		if err != nil {
+			return types.ZeroAddress, err
+		}
+
+		return crypto.PubKeyToAddress(pub), nil
+	}
+
 	encodedKey, err := secretsManager.GetSecret(secrets.ValidatorKey)
 	if err != nil {
 		return types.ZeroAddress, err
@@ -206,6 +233,18 @@ func LoadNodeID(secretsManager secrets.SecretsManager) (string, error) {
 	return nodeID.String(), nil
 }
 
+// setupPKCS11 is a helper method for boilerplate PKCS#11 HSM secrets manager setup
+func setupPKCS11(
+	secretsConfig *secrets.SecretsManagerConfig,
+) (secrets.SecretsManager, error) {
+	return pkcs11.SecretsManagerFactory(
+		secretsConfig,
+		&secrets.SecretsManagerParams{
+			Logger: hclog.NewNullLogger(),
+		},
+	)
+}
+
 // GetCloudSecretsManager returns the cloud secrets manager from the provided config
 func InitCloudSecretsManager(secretsConfig *secrets.SecretsManagerConfig) (secrets.SecretsManager, error) {
 	var secretsManager secrets.SecretsManager
@@ -232,6 +271,13 @@ func InitCloudSecretsManager(secretsConfig *secrets.SecretsManagerConfig) (secre
 		}
 
 		secretsManager = GCPSSM
+	case secrets.PKCS11:
+		HSM, err := setupPKCS11(secretsConfig)
+		if err != nil {
+			return secretsManager, err
+		}
+
+		secretsManager = HSM
 	default:
 		return secretsManager, errors.New("unsupported secrets manager")
 	}
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/helper/keystore"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/hashicorp/go-hclog"
 )
@@ -26,6 +27,13 @@ type LocalSecretsManager struct {
 
 	// Mux for the secretPathMap
 	secretPathMapLock sync.RWMutex
+
+	// Passphrase encrypting the secrets written to disk, they're written in plain if it's empty
+	passphrase string
+
+	// The scrypt parameters of the encrypted secrets
+	scryptN int
+	scryptP int
 }
 
 // SecretsManagerFactory implements the factory method
@@ -37,6 +45,8 @@ func SecretsManagerFactory(
 	localManager := &LocalSecretsManager{
 		logger:        params.Logger.Named(string(secrets.Local)),
 		secretPathMap: make(map[string]string),
+		scryptN:       keystore.StandardScryptN,
+		scryptP:       keystore.StandardScryptP,
 	}
 
 	// Grab the path to the working directory
@@ -50,6 +60,12 @@ func SecretsManagerFactory(
 		return nil, errors.New("invalid type assertion")
 	}
 
+	// Grab the passphrase, falling back to the environment
+	localManager.passphrase, _ = params.Extra[secrets.Passphrase].(string)
+	if localManager.passphrase == "" {
+		localManager.passphrase = os.Getenv(secrets.PassphraseEnv)
+	}
+
 	// Run the initial setup
 	_ = localManager.Setup()
 
@@ -94,7 +110,7 @@ func (l *LocalSecretsManager) Setup() error {
 	return nil
 }
 
-// GetSecret gets the local SecretsManager's secret from disk
+// GetSecret gets the local SecretsManager's secret from disk, decrypting it if it's encrypted
 func (l *LocalSecretsManager) GetSecret(name string) ([]byte, error) {
 	l.secretPathMapLock.RLock()
 	secretPath, ok := l.secretPathMap[name]
@@ -114,10 +130,27 @@ func (l *LocalSecretsManager) GetSecret(name string) ([]byte, error) {
 		)
 	}
 
+	if !keystore.IsEncrypted(secret) {
+		return secret, nil
+	}
+
+	if l.passphrase == "" {
+		return nil, fmt.Errorf("%w (%s)", secrets.ErrPassphraseRequired, secretPath)
+	}
+
+	if secret, err = keystore.Decrypt(secret, l.passphrase); err != nil {
+		return nil, fmt.Errorf(
+			"unable to decrypt secret (%s), %w",
+			secretPath,
+			err,
+		)
+	}
+
 	return secret, nil
 }
 
-// SetSecret saves the local SecretsManager's secret to disk
+// SetSecret saves the local SecretsManager's secret to disk,
+// encrypted by the passphrase if it's set
 func (l *LocalSecretsManager) SetSecret(name string, value []byte) error {
 	// If the data directory is not specified, skip write
 	if l.path == "" {
@@ -139,6 +172,16 @@ func (l *LocalSecretsManager) SetSecret(name string, value []byte) error {
 			secretPath,
 		)
 	}
+
+	if l.passphrase != "" {
+		encrypted, err := keystore.Encrypt(value, "", l.passphrase, l.scryptN, l.scryptP)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt secret (%s), %w", secretPath, err)
+		}
+
+		value = encrypted
+	}
+
 	// Write the secret to disk
 	if err := os.WriteFile(secretPath, value, os.ModePerm); err != nil {
 		return fmt.Errorf(
@@ -151,11 +194,20 @@ func (l *LocalSecretsManager) SetSecret(name string, value []byte) error {
 	return nil
 }
 
-// HasSecret checks if the secret is present on disk
+// HasSecret checks if the secret is present on disk,
+// which doesn't require the passphrase if the secret is encrypted
 func (l *LocalSecretsManager) HasSecret(name string) bool {
-	_, err := l.GetSecret(name)
+	l.secretPathMapLock.RLock()
+	secretPath, ok := l.secretPathMap[name]
+	l.secretPathMapLock.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	info, err := os.Stat(secretPath)
 
-	return err == nil
+	return err == nil && !info.IsDir()
 }
 
 // RemoveSecret removes the local SecretsManager's secret from disk
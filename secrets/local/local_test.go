@@ -7,6 +7,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/helper/keystore"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/hashicorp/go-hclog"
 	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
@@ -272,3 +273,40 @@ func TestLocalSecretsManager_RemoveSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalSecretsManager_EncryptedSecret(t *testing.T) {
+	_, validatorKeyEncoded, genErr := crypto.GenerateAndEncodeECDSAPrivateKey()
+	if genErr != nil {
+		t.Fatalf("Unable to generate validator private key, %v", genErr)
+	}
+
+	manager, ok := getLocalSecretsManager(t).(*LocalSecretsManager)
+	assert.True(t, ok)
+
+	// Encrypt the secret with light scrypt parameters
+	manager.passphrase = "passphrase"
+	manager.scryptN, manager.scryptP = 1<<12, 6
+
+	assert.NoError(t, manager.SetSecret(secrets.ValidatorKey, validatorKeyEncoded))
+
+	// The secret is encrypted on disk
+	raw, err := os.ReadFile(manager.secretPathMap[secrets.ValidatorKey])
+	assert.NoError(t, err)
+	assert.True(t, keystore.IsEncrypted(raw))
+
+	secret, err := manager.GetSecret(secrets.ValidatorKey)
+	assert.NoError(t, err)
+	assert.Equal(t, validatorKeyEncoded, secret)
+
+	// The secret can't be read by a wrong passphrase, or without one
+	manager.passphrase = "wrong"
+
+	_, err = manager.GetSecret(secrets.ValidatorKey)
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	manager.passphrase = ""
+
+	_, err = manager.GetSecret(secrets.ValidatorKey)
+	assert.ErrorIs(t, err, secrets.ErrPassphraseRequired)
+	assert.True(t, manager.HasSecret(secrets.ValidatorKey))
+}
@@ -0,0 +1,478 @@
+//go:build cgo
+// +build cgo
+
+package pkcs11
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/miekg/pkcs11"
+
+	"github.com/0xPolygon/polygon-edge/secrets"
+)
+
+const (
+	// wrappingKeyName is the name of the AES key wrapping the secrets which aren't ECDSA keys
+	wrappingKeyName = "wrapping-key"
+
+	// gcmNonceSize and gcmTagBits are the sizes of the nonce and the tag of the wrapped secrets
+	gcmNonceSize = 12
+	gcmTagBits   = 128
+)
+
+var (
+	errTokenNotFound       = errors.New("token not found")
+	errWrappedSecretLength = errors.New("wrapped secret too short")
+	errECDSAKeyImport      = errors.New("ECDSA keys are generated on the device and can't be imported")
+)
+
+// PKCS11SecretsManager is a SecretsManager that keeps the ECDSA keys on an HSM, signing with them
+// through the device, and stores the other secrets on it wrapped by an AES key of the device
+type PKCS11SecretsManager struct {
+	// Logger object
+	logger hclog.Logger
+
+	// The settings read from the config
+	params *params
+
+	// The loaded PKCS#11 module
+	ctx *pkcs11.Ctx
+
+	// The session logged in to the token, which is used by one operation at a time
+	session pkcs11.SessionHandle
+	lock    sync.Mutex
+
+	// The AES key wrapping the secrets
+	wrappingKey pkcs11.ObjectHandle
+
+	// The public keys of the ECDSA keys read so far, so they aren't read on each signing
+	publicKeys map[string]*ecdsa.PublicKey
+}
+
+// SecretsManagerFactory implements the factory method
+func SecretsManagerFactory(
+	config *secrets.SecretsManagerConfig,
+	params *secrets.SecretsManagerParams,
+) (secrets.SecretsManager, error) {
+	p, err := readParams(config)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := &PKCS11SecretsManager{
+		logger:     params.Logger.Named(string(secrets.PKCS11)),
+		params:     p,
+		publicKeys: make(map[string]*ecdsa.PublicKey),
+	}
+
+	if err := manager.Setup(); err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// Setup loads the PKCS#11 module and logs in to the token
+func (m *PKCS11SecretsManager) Setup() error {
+	ctx := pkcs11.New(m.params.module)
+	if ctx == nil {
+		return fmt.Errorf("unable to load PKCS#11 module %s", m.params.module)
+	}
+
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+
+		return fmt.Errorf("unable to initialize PKCS#11 module, %w", err)
+	}
+
+	m.ctx = ctx
+
+	if err := m.openSession(); err != nil {
+		m.close()
+
+		return err
+	}
+
+	wrappingKey, err := m.getOrCreateWrappingKey()
+	if err != nil {
+		m.close()
+
+		return err
+	}
+
+	m.wrappingKey = wrappingKey
+
+	return nil
+}
+
+// openSession opens a session on the token with the configured label, and logs in as the user
+func (m *PKCS11SecretsManager) openSession() error {
+	slots, err := m.ctx.GetSlotList(true)
+	if err != nil {
+		return fmt.Errorf("unable to list PKCS#11 slots, %w", err)
+	}
+
+	for _, slot := range slots {
+		info, err := m.ctx.GetTokenInfo(slot)
+		if err != nil || info.Label != m.params.token {
+			continue
+		}
+
+		session, err := m.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+		if err != nil {
+			return fmt.Errorf("unable to open PKCS#11 session, %w", err)
+		}
+
+		m.session = session
+
+		if err := m.ctx.Login(session, pkcs11.CKU_USER, m.params.pin); err != nil &&
+			!errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+			return fmt.Errorf("unable to log in to token, %w", err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", errTokenNotFound, m.params.token)
+}
+
+// close releases the session and the module
+func (m *PKCS11SecretsManager) close() {
+	if m.session != 0 {
+		_ = m.ctx.Logout(m.session)
+		_ = m.ctx.CloseSession(m.session)
+	}
+
+	_ = m.ctx.Finalize()
+	m.ctx.Destroy()
+}
+
+// findObject returns the object of the class with the label, false if there is none
+func (m *PKCS11SecretsManager) findObject(class uint, label string) (pkcs11.ObjectHandle, bool, error) {
+	if err := m.ctx.FindObjectsInit(m.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}); err != nil {
+		return 0, false, err
+	}
+
+	objects, _, err := m.ctx.FindObjects(m.session, 1)
+
+	if finalErr := m.ctx.FindObjectsFinal(m.session); err == nil {
+		err = finalErr
+	}
+
+	if err != nil || len(objects) == 0 {
+		return 0, false, err
+	}
+
+	return objects[0], true, nil
+}
+
+// getOrCreateWrappingKey returns the AES key wrapping the secrets of the node, generating it if needed
+func (m *PKCS11SecretsManager) getOrCreateWrappingKey() (pkcs11.ObjectHandle, error) {
+	label := m.params.objectLabel(wrappingKeyName)
+
+	key, ok, err := m.findObject(pkcs11.CKO_SECRET_KEY, label)
+	if err != nil || ok {
+		return key, err
+	}
+
+	m.logger.Info("generating the wrapping key on the device", "label", label)
+
+	key, err = m.ctx.GenerateKey(
+		m.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+			pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to generate wrapping key, %w", err)
+	}
+
+	return key, nil
+}
+
+// GetSecret fetches a wrapped secret from the device, the ECDSA keys can't be exported
+func (m *PKCS11SecretsManager) GetSecret(name string) ([]byte, error) {
+	if ecdsaSecrets[name] {
+		return nil, secrets.ErrSecretNotExportable
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	object, ok, err := m.findObject(pkcs11.CKO_DATA, m.params.objectLabel(name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find secret on device, %w", err)
+	}
+
+	if !ok {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	attrs, err := m.ctx.GetAttributeValue(m.session, object, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret from device, %w", err)
+	}
+
+	wrapped := attrs[0].Value
+	if len(wrapped) < gcmNonceSize {
+		return nil, errWrappedSecretLength
+	}
+
+	gcm := pkcs11.NewGCMParams(wrapped[:gcmNonceSize], nil, gcmTagBits)
+	defer gcm.Free()
+
+	if err := m.ctx.DecryptInit(
+		m.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, gcm)},
+		m.wrappingKey,
+	); err != nil {
+		return nil, fmt.Errorf("unable to unwrap secret, %w", err)
+	}
+
+	value, err := m.ctx.Decrypt(m.session, wrapped[gcmNonceSize:])
+	if err != nil {
+		return nil, fmt.Errorf("unable to unwrap secret, %w", err)
+	}
+
+	return value, nil
+}
+
+// SetSecret wraps the secret by the AES key of the device and stores it on the device,
+// the ECDSA keys are generated on the device instead
+func (m *PKCS11SecretsManager) SetSecret(name string, value []byte) error {
+	if ecdsaSecrets[name] {
+		return errECDSAKeyImport
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	nonce := make([]byte, gcmNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	gcm := pkcs11.NewGCMParams(nonce, nil, gcmTagBits)
+	defer gcm.Free()
+
+	if err := m.ctx.EncryptInit(
+		m.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, gcm)},
+		m.wrappingKey,
+	); err != nil {
+		return fmt.Errorf("unable to wrap secret (%s), %w", name, err)
+	}
+
+	ciphertext, err := m.ctx.Encrypt(m.session, value)
+	if err != nil {
+		return fmt.Errorf("unable to wrap secret (%s), %w", name, err)
+	}
+
+	// the device may use its own nonce
+	if iv := gcm.IV(); len(iv) == gcmNonceSize {
+		nonce = iv
+	}
+
+	label := m.params.objectLabel(name)
+
+	if err := m.destroyObjects(label, pkcs11.CKO_DATA); err != nil {
+		return err
+	}
+
+	if _, err := m.ctx.CreateObject(m.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_DATA),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, append(nonce, ciphertext...)),
+	}); err != nil {
+		return fmt.Errorf("unable to store secret (%s), %w", name, err)
+	}
+
+	return nil
+}
+
+// HasSecret checks if the secret is present on the device
+func (m *PKCS11SecretsManager) HasSecret(name string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	class := uint(pkcs11.CKO_DATA)
+	if ecdsaSecrets[name] {
+		class = pkcs11.CKO_PRIVATE_KEY
+	}
+
+	_, ok, err := m.findObject(class, m.params.objectLabel(name))
+
+	return err == nil && ok
+}
+
+// RemoveSecret removes a secret from the device
+func (m *PKCS11SecretsManager) RemoveSecret(name string) error {
+	if !m.HasSecret(name) {
+		return secrets.ErrSecretNotFound
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	classes := []uint{pkcs11.CKO_DATA}
+	if ecdsaSecrets[name] {
+		classes = []uint{pkcs11.CKO_PRIVATE_KEY, pkcs11.CKO_PUBLIC_KEY}
+
+		delete(m.publicKeys, name)
+	}
+
+	return m.destroyObjects(m.params.objectLabel(name), classes...)
+}
+
+// destroyObjects destroys the objects of the classes with the label
+func (m *PKCS11SecretsManager) destroyObjects(label string, classes ...uint) error {
+	for _, class := range classes {
+		object, ok, err := m.findObject(class, label)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := m.ctx.DestroyObject(m.session, object); err != nil {
+			return fmt.Errorf("unable to delete secret (%s), %w", label, err)
+		}
+	}
+
+	return nil
+}
+
+// GenerateECDSAKey generates a secp256k1 key pair on the device, whose private key can't be exported
+func (m *PKCS11SecretsManager) GenerateECDSAKey(name string) (*ecdsa.PublicKey, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	label := m.params.objectLabel(name)
+
+	if _, ok, err := m.findObject(pkcs11.CKO_PRIVATE_KEY, label); err != nil || ok {
+		return nil, fmt.Errorf(`secrets "%s" has been already initialized`, name)
+	}
+
+	pub, _, err := m.ctx.GenerateKeyPair(
+		m.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, secp256k1OID),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate ECDSA key on device, %w", err)
+	}
+
+	return m.publicKey(pub)
+}
+
+// ECDSAPublicKey returns the public key of the ECDSA key on the device
+func (m *PKCS11SecretsManager) ECDSAPublicKey(name string) (*ecdsa.PublicKey, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if pub, ok := m.publicKeys[name]; ok {
+		return pub, nil
+	}
+
+	object, ok, err := m.findObject(pkcs11.CKO_PUBLIC_KEY, m.params.objectLabel(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	pub, err := m.publicKey(object)
+	if err != nil {
+		return nil, err
+	}
+
+	m.publicKeys[name] = pub
+
+	return pub, nil
+}
+
+// publicKey reads the public key object
+func (m *PKCS11SecretsManager) publicKey(object pkcs11.ObjectHandle) (*ecdsa.PublicKey, error) {
+	attrs, err := m.ctx.GetAttributeValue(m.session, object, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read public key from device, %w", err)
+	}
+
+	return parseECPoint(attrs[0].Value)
+}
+
+// SignECDSA signs the hash by the ECDSA key on the device
+func (m *PKCS11SecretsManager) SignECDSA(name string, hash []byte) ([]byte, error) {
+	pub, err := m.ECDSAPublicKey(name)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key, ok, err := m.findObject(pkcs11.CKO_PRIVATE_KEY, m.params.objectLabel(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	if err := m.ctx.SignInit(
+		m.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)},
+		key,
+	); err != nil {
+		return nil, fmt.Errorf("unable to sign on device, %w", err)
+	}
+
+	signature, err := m.ctx.Sign(m.session, hash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign on device, %w", err)
+	}
+
+	return toRecoverableSignature(hash, signature, pub)
+}
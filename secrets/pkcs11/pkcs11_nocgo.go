@@ -0,0 +1,18 @@
+//go:build !cgo
+// +build !cgo
+
+package pkcs11
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/secrets"
+)
+
+// SecretsManagerFactory implements the factory method, the PKCS#11 module being loaded through cgo
+func SecretsManagerFactory(
+	_ *secrets.SecretsManagerConfig,
+	_ *secrets.SecretsManagerParams,
+) (secrets.SecretsManager, error) {
+	return nil, errors.New("PKCS#11 secrets manager requires a build with cgo enabled")
+}
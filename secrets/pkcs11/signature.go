@@ -0,0 +1,131 @@
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+)
+
+type configExtraParamFields string
+
+const (
+	// modulePath is the path to the PKCS#11 module of the HSM
+	modulePath configExtraParamFields = "module"
+	// tokenLabel is the label of the token the secrets are kept on
+	tokenLabel configExtraParamFields = "token-label"
+)
+
+var (
+	errNoModulePath       = fmt.Errorf("no %s specified for PKCS#11 secrets manager", modulePath)
+	errNoTokenLabel       = fmt.Errorf("no %s specified for PKCS#11 secrets manager", tokenLabel)
+	errNoPIN              = errors.New("no user PIN specified as the token of PKCS#11 secrets manager")
+	errNoNodeName         = errors.New("no node name specified for PKCS#11 secrets manager")
+	errInvalidECPoint     = errors.New("invalid EC point of the public key")
+	errInvalidSignature   = errors.New("invalid signature returned by the device")
+	errSignerNotRecovered = errors.New("signature of the device doesn't recover its public key")
+)
+
+var (
+	// secp256k1OID is the DER encoded OID of the secp256k1 curve, the CKA_EC_PARAMS of the keys
+	secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+	secp256k1N     = crypto.S256.Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// ecdsaSecrets are the secrets kept as ECDSA keys on the device, the others are stored wrapped by it
+var ecdsaSecrets = map[string]bool{
+	secrets.ValidatorKey: true,
+}
+
+// params are the settings of the secrets manager read from its config
+type params struct {
+	module string
+	token  string
+	pin    string
+	name   string
+}
+
+// readParams reads the settings from the config
+func readParams(config *secrets.SecretsManagerConfig) (*params, error) {
+	p := &params{
+		module: fmt.Sprintf("%v", config.Extra[string(modulePath)]),
+		token:  fmt.Sprintf("%v", config.Extra[string(tokenLabel)]),
+		pin:    config.Token,
+		name:   config.Name,
+	}
+
+	switch {
+	case config.Extra[string(modulePath)] == nil || p.module == "":
+		return nil, errNoModulePath
+	case config.Extra[string(tokenLabel)] == nil || p.token == "":
+		return nil, errNoTokenLabel
+	case p.pin == "":
+		return nil, errNoPIN
+	case p.name == "":
+		return nil, errNoNodeName
+	}
+
+	return p, nil
+}
+
+// objectLabel returns the label of the object holding the secret of the node
+func (p *params) objectLabel(name string) string {
+	return fmt.Sprintf("%s-%s", p.name, name)
+}
+
+// parseECPoint parses the uncompressed public key from the CKA_EC_POINT of the key,
+// which is DER encoded as an octet string, though some devices return it raw
+func parseECPoint(ecPoint []byte) (*ecdsa.PublicKey, error) {
+	point := ecPoint
+
+	var raw []byte
+	if rest, err := asn1.Unmarshal(ecPoint, &raw); err == nil && len(rest) == 0 {
+		point = raw
+	}
+
+	pub, err := crypto.ParsePublicKey(point)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidECPoint, err)
+	}
+
+	return pub, nil
+}
+
+// toRecoverableSignature converts the [R || S] signature of the device to the [R || S || V] one,
+// normalizing S to the lower half of the order and finding the recovery id of the public key
+func toRecoverableSignature(hash, signature []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	if len(signature) != 64 {
+		return nil, fmt.Errorf("%w: %d bytes", errInvalidSignature, len(signature))
+	}
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+
+	sig := make([]byte, 65)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+
+	expected := crypto.MarshalPublicKey(pub)
+
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+
+		recovered, err := crypto.Ecrecover(hash, sig)
+		if err == nil && bytes.Equal(recovered, expected) {
+			return sig, nil
+		}
+	}
+
+	return nil, errSignerNotRecovered
+}
@@ -0,0 +1,102 @@
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+)
+
+func TestReadParams(t *testing.T) {
+	t.Parallel()
+
+	config := func(token, name string, extra map[string]interface{}) *secrets.SecretsManagerConfig {
+		return &secrets.SecretsManagerConfig{Token: token, Name: name, Extra: extra}
+	}
+
+	validExtra := map[string]interface{}{
+		"module":      "/usr/lib/softhsm/libsofthsm2.so",
+		"token-label": "validators",
+	}
+
+	p, err := readParams(config("1234", "node-1", validExtra))
+	require.NoError(t, err)
+	assert.Equal(t, &params{
+		module: "/usr/lib/softhsm/libsofthsm2.so",
+		token:  "validators",
+		pin:    "1234",
+		name:   "node-1",
+	}, p)
+	assert.Equal(t, "node-1-validator-key", p.objectLabel(secrets.ValidatorKey))
+
+	_, err = readParams(config("1234", "node-1", map[string]interface{}{"token-label": "validators"}))
+	assert.ErrorIs(t, err, errNoModulePath)
+
+	_, err = readParams(config("1234", "node-1", map[string]interface{}{"module": "/lib.so"}))
+	assert.ErrorIs(t, err, errNoTokenLabel)
+
+	_, err = readParams(config("", "node-1", validExtra))
+	assert.ErrorIs(t, err, errNoPIN)
+
+	_, err = readParams(config("1234", "", validExtra))
+	assert.ErrorIs(t, err, errNoNodeName)
+}
+
+func TestParseECPoint(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	raw := crypto.MarshalPublicKey(&key.PublicKey)
+
+	encoded, err := asn1.Marshal(raw)
+	require.NoError(t, err)
+
+	// the point is DER encoded by the standard, raw on some devices
+	for _, point := range [][]byte{encoded, raw} {
+		pub, err := parseECPoint(point)
+		require.NoError(t, err)
+		assert.Equal(t, raw, crypto.MarshalPublicKey(pub))
+	}
+
+	_, err = parseECPoint([]byte{0x04, 0x01})
+	assert.ErrorIs(t, err, errInvalidECPoint)
+}
+
+func TestToRecoverableSignature(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	other, err := crypto.GenerateECDSAKey()
+	require.NoError(t, err)
+
+	hash := crypto.Keccak256([]byte("message"))
+
+	expected, err := crypto.Sign(key, hash)
+	require.NoError(t, err)
+
+	// the device may return S of either half of the order
+	highS := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(expected[32:64]))
+
+	for _, s := range [][]byte{expected[32:64], highS.FillBytes(make([]byte, 32))} {
+		signature := append(append([]byte{}, expected[:32]...), s...)
+
+		sig, err := toRecoverableSignature(hash, signature, &key.PublicKey)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sig)
+	}
+
+	_, err = toRecoverableSignature(hash, expected[:64], &other.PublicKey)
+	assert.ErrorIs(t, err, errSignerNotRecovered)
+
+	_, err = toRecoverableSignature(hash, expected, &key.PublicKey)
+	assert.ErrorIs(t, err, errInvalidSignature)
+}
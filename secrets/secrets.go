@@ -1,6 +1,7 @@
 package secrets
 
 import (
+	"crypto/ecdsa"
 	"errors"
 
 	"github.com/hashicorp/go-hclog"
@@ -19,8 +20,15 @@ const (
 
 	// Name is the name of the current node
 	Name = "name"
+
+	// Passphrase is the passphrase encrypting the secrets of the local secrets manager
+	Passphrase = "passphrase"
 )
 
+// PassphraseEnv is the environment variable the passphrase of the local secrets is read from,
+// if it isn't passed in
+const PassphraseEnv = "POLYGON_EDGE_SECRETS_PASSPHRASE"
+
 // Define constant names for available secrets
 const (
 	// ValidatorKey is the private key secret of the validator node
@@ -47,7 +55,9 @@ const (
 )
 
 var (
-	ErrSecretNotFound = errors.New("secret not found")
+	ErrSecretNotFound      = errors.New("secret not found")
+	ErrSecretNotExportable = errors.New("secret is kept on the device and can't be exported")
+	ErrPassphraseRequired  = errors.New("secret is encrypted, but no passphrase is set")
 )
 
 type SecretsManagerType string
@@ -65,6 +75,9 @@ const (
 
 	// GCPSSM pertains to the Google Cloud Computing secret store manager
 	GCPSSM SecretsManagerType = "gcp-ssm"
+
+	// PKCS11 pertains to an HSM accessed through its PKCS#11 module
+	PKCS11 SecretsManagerType = "pkcs11"
 )
 
 // SecretsManager defines the base public interface that all
@@ -86,6 +99,19 @@ type SecretsManager interface {
 	RemoveSecret(name string) error
 }
 
+// ECDSASigner is implemented by the secrets managers keeping the ECDSA secrets on a device,
+// which sign with them instead of returning them from GetSecret
+type ECDSASigner interface {
+	// GenerateECDSAKey generates the ECDSA secret on the device and returns its public key
+	GenerateECDSAKey(name string) (*ecdsa.PublicKey, error)
+
+	// ECDSAPublicKey returns the public key of the ECDSA secret
+	ECDSAPublicKey(name string) (*ecdsa.PublicKey, error)
+
+	// SignECDSA signs the hash with the ECDSA secret, returning the [R || S || V] signature
+	SignECDSA(name string, hash []byte) ([]byte, error)
+}
+
 // SecretsManagerParams defines the configuration params for the
 // secrets manager
 type SecretsManagerParams struct {
@@ -110,5 +136,5 @@ type SecretsManagerFactory func(
 // SupportedServiceManager checks if the passed in service manager type is supported
 func SupportedServiceManager(service SecretsManagerType) bool {
 	return service == HashicorpVault || service == AWSSSM ||
-		service == Local || service == GCPSSM
+		service == Local || service == GCPSSM || service == PKCS11
 }
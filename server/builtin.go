@@ -4,26 +4,30 @@ import (
 	"github.com/0xPolygon/polygon-edge/consensus"
 	consensusDev "github.com/0xPolygon/polygon-edge/consensus/dev"
 	consensusDummy "github.com/0xPolygon/polygon-edge/consensus/dummy"
+	consensusExternal "github.com/0xPolygon/polygon-edge/consensus/external"
 	consensusIBFT "github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/secrets/awsssm"
 	"github.com/0xPolygon/polygon-edge/secrets/gcpssm"
 	"github.com/0xPolygon/polygon-edge/secrets/hashicorpvault"
 	"github.com/0xPolygon/polygon-edge/secrets/local"
+	"github.com/0xPolygon/polygon-edge/secrets/pkcs11"
 )
 
 type ConsensusType string
 
 const (
-	DevConsensus   ConsensusType = "dev"
-	IBFTConsensus  ConsensusType = "ibft"
-	DummyConsensus ConsensusType = "dummy"
+	DevConsensus      ConsensusType = "dev"
+	IBFTConsensus     ConsensusType = "ibft"
+	DummyConsensus    ConsensusType = "dummy"
+	ExternalConsensus ConsensusType = "external"
 )
 
 var consensusBackends = map[ConsensusType]consensus.Factory{
-	DevConsensus:   consensusDev.Factory,
-	IBFTConsensus:  consensusIBFT.Factory,
-	DummyConsensus: consensusDummy.Factory,
+	DevConsensus:      consensusDev.Factory,
+	IBFTConsensus:     consensusIBFT.Factory,
+	DummyConsensus:    consensusDummy.Factory,
+	ExternalConsensus: consensusExternal.Factory,
 }
 
 // secretsManagerBackends defines the SecretManager factories for different
@@ -33,6 +37,7 @@ var secretsManagerBackends = map[secrets.SecretsManagerType]secrets.SecretsManag
 	secrets.HashicorpVault: hashicorpvault.SecretsManagerFactory,
 	secrets.AWSSSM:         awsssm.SecretsManagerFactory,
 	secrets.GCPSSM:         gcpssm.SecretsManagerFactory,
+	secrets.PKCS11:         pkcs11.SecretsManagerFactory,
 }
 
 func ConsensusSupported(value string) bool {
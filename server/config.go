@@ -2,12 +2,18 @@ package server
 
 import (
 	"net"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/replica"
 	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/state/fork"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/types"
 )
 
 const DefaultGRPCPort int = 9632
@@ -24,13 +30,21 @@ type Config struct {
 	PriceLimit         uint64
 	MaxAccountEnqueued uint64
 	MaxSlots           uint64
+	BundlerWhitelist   []types.Address
 	BlockTime          uint64
 
+	// PrioritySenders are the senders whose transactions are packed before the regular ones,
+	// in the share of the block gas limit set by PriorityGasPercent
+	PrioritySenders    []types.Address
+	PriorityGasPercent uint64
+
 	Telemetry *Telemetry
 	Network   *network.Config
 
 	DataDir     string
 	RestoreFile *string
+	// RestoreOnly stops the server once the restore file is imported, before the consensus starts
+	RestoreOnly bool
 
 	Seal bool
 
@@ -41,17 +55,91 @@ type Config struct {
 	JSONLogFormat bool
 
 	LogFilePath string
+
+	StateSnapshotInterval uint64
+	SnapSync              bool
+
+	// Checkpoint is the trusted block a fresh node starts from instead of the genesis, nil if it's not set
+	Checkpoint         *statesync.Checkpoint
+	CheckpointBackfill bool
+
+	OpcodeStats             bool
+	OpcodeStatsTopContracts uint64
+
+	StateRetention uint64
+	FlatState      bool
+
+	// Fork is the remote chain the state of the dev chain is forked from, nil if it isn't forked
+	Fork *fork.Config
+
+	// DBEngine is the engine of the databases of the data directory
+	DBEngine string
+
+	// AncientDir is the directory of the freezer the old blocks are moved to, empty to keep them in the database
+	AncientDir       string
+	AncientThreshold uint64
+
+	// TxLookupLimit is the number of recent blocks whose transactions are looked up by hash, 0 for all of them
+	TxLookupLimit uint64
+
+	// the memory of the state caches in bytes, 0 disables them
+	TrieCache     int
+	CodeCache     int
+	SnapshotCache int
+	// TxPoolCache is the memory of the pool slots in bytes, 0 keeps the pool at MaxSlots
+	TxPoolCache int
+	// BlockCache is the number of recently accessed blocks whose data is cached, 0 for the default
+	BlockCache int
+
+	StrictSignState bool
+
+	// Replica is the config of the replica of a primary node, nil if the node runs the consensus
+	Replica *replica.Config
+
+	// Light runs a light node following the verified headers instead of the full node
+	Light bool
 }
 
 // Telemetry holds the config details for metric services
 type Telemetry struct {
 	PrometheusAddr *net.TCPAddr
+	// PprofAddr is the address of the diagnostics server serving the runtime profiles, disabled if nil
+	PprofAddr *net.TCPAddr
 }
 
 // JSONRPC holds the config details for the JSON-RPC server
 type JSONRPC struct {
 	JSONRPCAddr              *net.TCPAddr
+	IPCPath                  string
 	AccessControlAllowOrigin []string
+	VirtualHosts             []string
+	RateLimit                uint64
+	MethodRateLimits         map[string]uint64
+	MaxConcurrentRequests    map[string]uint64
 	BatchLengthLimit         uint64
+	BatchGasLimit            uint64
 	BlockRangeLimit          uint64
+	StateHistory             uint64
+	StatePinInterval         uint64
+	FilterTimeout            time.Duration
+	MaxFiltersPerClient      uint64
+	PersistFilters           bool
+	TxSenderRateLimit        uint64
+	TxIPRateLimit            uint64
+	TxRateBurst              uint64
+	Webhooks                 bool
+	GraphQL                  bool
+	UnsafeDebug              bool
+	Admin                    bool
+}
+
+// Policy returns the access policy of the JSON-RPC server
+func (j *JSONRPC) Policy() *jsonrpc.Policy {
+	return &jsonrpc.Policy{
+		AccessControlAllowOrigin: j.AccessControlAllowOrigin,
+		VirtualHosts:             j.VirtualHosts,
+		RateLimit:                j.RateLimit,
+		MethodRateLimits:         j.MethodRateLimits,
+		MaxConcurrentRequests:    j.MaxConcurrentRequests,
+	}
 }
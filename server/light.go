@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/fork"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/light"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/validators"
+)
+
+// LightNode is a node following the verified headers of the chain from the full nodes, without storing nor
+// executing the blocks. It serves the JSON-RPC queries answerable from the headers and the proved state
+type LightNode struct {
+	logger hclog.Logger
+	config *Config
+
+	network *network.Server
+	client  *light.Client
+	rpc     *http.Server
+}
+
+// NewLightNode creates and starts a new light node
+func NewLightNode(config *Config) (*LightNode, error) {
+	logger, err := newLoggerFromConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not setup new logger instance, %w", err)
+	}
+
+	n := &LightNode{
+		logger: logger.Named("light-node"),
+		config: config,
+	}
+
+	if err := common.SetupDataDir(config.DataDir, []string{"libp2p"}); err != nil {
+		return nil, fmt.Errorf("failed to create data directories: %w", err)
+	}
+
+	clientConfig, err := lightClientConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	secretsManager, err := newSecretsManager(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the secrets manager: %w", err)
+	}
+
+	netConfig := config.Network
+	netConfig.Chain = config.Chain
+	netConfig.DataDir = filepath.Join(config.DataDir, "libp2p")
+	netConfig.SecretsManager = secretsManager
+
+	if n.network, err = network.NewServer(logger, netConfig); err != nil {
+		return nil, err
+	}
+
+	if n.client, err = light.NewClient(logger, n.network, clientConfig); err != nil {
+		return nil, err
+	}
+
+	if err := n.network.Start(); err != nil {
+		return nil, err
+	}
+
+	n.client.Start()
+
+	if err := n.startRPC(); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// lightClientConfig returns the trusted header and the IBFT parameters of the chain followed by the light client
+func lightClientConfig(config *Config) (*light.ClientConfig, error) {
+	engine, ok := config.Chain.Params.Engine["ibft"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the light node only follows IBFT chains")
+	}
+
+	epochSize, quorumSizeBlockNum, err := ibft.EpochParams(engine)
+	if err != nil {
+		return nil, err
+	}
+
+	forks, err := fork.GetIBFTForks(engine)
+	if err != nil {
+		return nil, err
+	}
+
+	return &light.ClientConfig{
+		Genesis:            config.Chain.Genesis.GenesisHeader(),
+		Checkpoint:         config.Checkpoint,
+		EpochSize:          epochSize,
+		QuorumSizeBlockNum: quorumSizeBlockNum,
+		ValidatorType: func(height uint64) validators.ValidatorType {
+			return forks.ValidatorTypeAt(height)
+		},
+	}, nil
+}
+
+// startRPC serves the JSON-RPC queries of the light client
+func (n *LightNode) startRPC() error {
+	addr := n.config.JSONRPC.JSONRPCAddr
+
+	lis, err := net.Listen("tcp", addr.String())
+	if err != nil {
+		return err
+	}
+
+	n.rpc = &http.Server{
+		Handler:           light.NewRPC(n.client, uint64(n.config.Chain.Params.ChainID)),
+		ReadHeaderTimeout: 60 * time.Second,
+	}
+
+	go func() {
+		if err := n.rpc.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			n.logger.Error("closed http connection", "err", err)
+		}
+	}()
+
+	n.logger.Info("JSON-RPC server started", "addr", addr.String())
+
+	return nil
+}
+
+// Close stops the light node
+func (n *LightNode) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := n.rpc.Shutdown(ctx); err != nil {
+		n.logger.Error("failed to close JSON-RPC server", "err", err)
+	}
+
+	n.client.Close()
+
+	if err := n.network.Close(); err != nil {
+		n.logger.Error("failed to close networking", "err", err)
+	}
+}
@@ -33,6 +33,8 @@ type SystemClient interface {
 	BlockByNumber(ctx context.Context, in *BlockByNumberRequest, opts ...grpc.CallOption) (*BlockResponse, error)
 	// Export returns blockchain data
 	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (System_ExportClient, error)
+	// SubscribeCongestion subscribes to congestion events
+	SubscribeCongestion(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (System_SubscribeCongestionClient, error)
 }
 
 type systemClient struct {
@@ -152,6 +154,38 @@ func (x *systemExportClient) Recv() (*ExportEvent, error) {
 	return m, nil
 }
 
+func (c *systemClient) SubscribeCongestion(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (System_SubscribeCongestionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[2], "/v1.System/SubscribeCongestion", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &systemSubscribeCongestionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type System_SubscribeCongestionClient interface {
+	Recv() (*CongestionEvent, error)
+	grpc.ClientStream
+}
+
+type systemSubscribeCongestionClient struct {
+	grpc.ClientStream
+}
+
+func (x *systemSubscribeCongestionClient) Recv() (*CongestionEvent, error) {
+	m := new(CongestionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SystemServer is the server API for System service.
 // All implementations must embed UnimplementedSystemServer
 // for forward compatibility
@@ -170,6 +204,8 @@ type SystemServer interface {
 	BlockByNumber(context.Context, *BlockByNumberRequest) (*BlockResponse, error)
 	// Export returns blockchain data
 	Export(*ExportRequest, System_ExportServer) error
+	// SubscribeCongestion subscribes to congestion events
+	SubscribeCongestion(*emptypb.Empty, System_SubscribeCongestionServer) error
 	mustEmbedUnimplementedSystemServer()
 }
 
@@ -198,6 +234,9 @@ func (UnimplementedSystemServer) BlockByNumber(context.Context, *BlockByNumberRe
 func (UnimplementedSystemServer) Export(*ExportRequest, System_ExportServer) error {
 	return status.Errorf(codes.Unimplemented, "method Export not implemented")
 }
+func (UnimplementedSystemServer) SubscribeCongestion(*emptypb.Empty, System_SubscribeCongestionServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCongestion not implemented")
+}
 func (UnimplementedSystemServer) mustEmbedUnimplementedSystemServer() {}
 
 // UnsafeSystemServer may be embedded to opt out of forward compatibility for this service.
@@ -343,6 +382,27 @@ func (x *systemExportServer) Send(m *ExportEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _System_SubscribeCongestion_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SystemServer).SubscribeCongestion(m, &systemSubscribeCongestionServer{stream})
+}
+
+type System_SubscribeCongestionServer interface {
+	Send(*CongestionEvent) error
+	grpc.ServerStream
+}
+
+type systemSubscribeCongestionServer struct {
+	grpc.ServerStream
+}
+
+func (x *systemSubscribeCongestionServer) Send(m *CongestionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // System_ServiceDesc is the grpc.ServiceDesc for System service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -382,6 +442,11 @@ var System_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _System_Export_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeCongestion",
+			Handler:       _System_SubscribeCongestion_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "system.proto",
 }
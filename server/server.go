@@ -9,26 +9,43 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/archive"
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/congestion"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/graphql"
+	"github.com/0xPolygon/polygon-edge/health"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	configHelper "github.com/0xPolygon/polygon-edge/helper/config"
+	"github.com/0xPolygon/polygon-edge/helper/diagnostics"
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/helper/membudget"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/jsonrpc"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/light"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/replica"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/server/proto"
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/state/flat"
+	"github.com/0xPolygon/polygon-edge/state/fork"
 	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/syncer"
 	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/webhook"
 	"github.com/hashicorp/go-hclog"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -65,13 +82,54 @@ type Server struct {
 
 	prometheusServer *http.Server
 
+	// diagnostics server
+	diagnosticsServer *http.Server
+
 	// secrets manager
 	secretsManager secrets.SecretsManager
 
+	// state sync
+	stateSync *statesync.StateSync
+
+	// light client server
+	lightServer *light.Server
+
+	// webhooks notified of the logs, nil if they are disabled
+	webhooks *webhook.Manager
+
+	// congestion events
+	congestion *congestion.Monitor
+
+	// health and readiness checks
+	health *health.Checker
+
+	// state pruner, nil if the states of all the blocks are retained
+	pruner *itrie.Pruner
+
+	// flat state of the recent blocks, nil if the state is read from the tries only
+	flatState *flat.Tree
+
+	// forkDB caches the state of the remote chain the dev chain is forked from,
+	// nil if it isn't forked. The chain starts from the forkBlock
+	forkDB    kvdb.Database
+	forkBlock *fork.PinnedBlock
+
+	// memory budget of the caches and the pool slots
+	memBudget *membudget.Manager
+
+	// replica of a primary node, nil if the node runs the consensus
+	replica *replica.Replica
+
 	// restore
 	restoreProgression *progress.ProgressionWrapper
+
+	// configReloader reads the config file again, nil if the node isn't started from a config file
+	configReloader func() (*Config, error)
+	reloadLock     sync.Mutex
 }
 
+var errNoConfigFile = errors.New("the node isn't started from a config file")
+
 var dirPaths = []string{
 	"blockchain",
 	"trie",
@@ -148,6 +206,10 @@ func NewServer(config *Config) (*Server, error) {
 		m.prometheusServer = m.startPrometheusServer(config.Telemetry.PrometheusAddr)
 	}
 
+	if config.Telemetry.PprofAddr != nil {
+		m.diagnosticsServer = m.startDiagnosticsServer(config.Telemetry.PprofAddr)
+	}
+
 	// Set up datadog profiler
 	if ddErr := m.enableDataDogProfiler(); err != nil {
 		m.logger.Error("DataDog profiler setup failed", "err", ddErr.Error())
@@ -173,31 +235,115 @@ func NewServer(config *Config) (*Server, error) {
 	}
 
 	// start blockchain object
-	stateStorage, err := itrie.NewLevelDBStorage(filepath.Join(m.config.DataDir, "trie"), logger)
+	stateDB, err := m.openDatabase("trie", 0)
 	if err != nil {
 		return nil, err
 	}
 
+	stateStorage := itrie.NewKVStorage(stateDB)
 	m.stateStorage = stateStorage
 
-	st := itrie.NewState(stateStorage)
+	if config.StateRetention != 0 {
+		// the states pinned on the JSON-RPC are retained as well
+		m.pruner, err = itrie.NewPruner(logger, stateStorage, config.StateRetention, config.JSONRPC.StatePinInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		// the nodes written while pruning are kept
+		m.stateStorage = m.pruner.Storage()
+	}
+
+	cachedStorage := itrie.NewCachedStorage(m.stateStorage, config.TrieCache, config.CodeCache)
+
+	m.memBudget = membudget.NewManager(logger)
+	m.memBudget.Register("trie", cachedStorage.NodesCache(), config.TrieCache)
+	m.memBudget.Register("code", cachedStorage.CodeCache(), config.CodeCache)
+
+	if m.pruner != nil {
+		m.pruner.SetCache(cachedStorage)
+	}
+
+	st := itrie.NewState(cachedStorage)
 	m.state = st
 
-	m.executor = state.NewExecutor(config.Chain.Params, st, logger)
+	if config.FlatState {
+		flatDB, err := m.openDatabase("flat", config.SnapshotCache)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.flatState, err = flat.NewTree(logger, flatDB, st); err != nil {
+			return nil, err
+		}
+
+		// the cache of the database engine can't be resized
+		m.memBudget.RegisterFixed("snapshot", config.SnapshotCache)
+
+		m.state = flat.NewState(st, m.flatState)
+	}
+
+	if config.Fork != nil {
+		if err := m.setupFork(); err != nil {
+			return nil, err
+		}
+	}
+
+	m.executor = state.NewExecutor(config.Chain.Params, m.state, logger)
+
+	if config.OpcodeStats {
+		m.executor.OpcodeStats = state.NewOpcodeStatsRecorder(int(config.OpcodeStatsTopContracts))
+	}
+
+	if m.executor.Precompiles, err = precompiled.NewStatefulContracts(config.Chain.Params.Precompiles); err != nil {
+		return nil, err
+	}
 
 	// compute the genesis root state
-	genesisRoot := m.executor.WriteGenesis(config.Chain.Genesis.Alloc)
+	genesisRoot, err := m.executor.WriteGenesisWithFile(
+		config.Chain.Genesis.Alloc,
+		config.Chain.Genesis.AllocFilePath(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	config.Chain.Genesis.StateRoot = genesisRoot
 
 	// use the eip155 signer
 	signer := crypto.NewEIP155Signer(uint64(m.config.Chain.Params.ChainID))
 
 	// blockchain object
-	m.blockchain, err = blockchain.NewBlockchain(logger, m.config.DataDir, config.Chain, nil, m.executor, signer)
+	blockchainDB, err := m.openDatabase("blockchain", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	blockchainStorage, err := m.openBlockchainStorage(logger.Named(config.DBEngine), blockchainDB)
+	if err != nil {
+		return nil, err
+	}
+
+	m.blockchain, err = blockchain.NewBlockchain(
+		logger,
+		blockchainStorage,
+		config.Chain,
+		nil,
+		m.executor,
+		signer,
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	m.blockchain.SetTxLookupLimit(config.TxLookupLimit)
+
+	if config.BlockCache > 0 {
+		if err := m.blockchain.SetCacheSize(config.BlockCache); err != nil {
+			return nil, err
+		}
+	}
+
 	m.executor.GetHash = m.blockchain.GetHashHelper
 
 	{
@@ -211,18 +357,42 @@ func NewServer(config *Config) (*Server, error) {
 			return nil, err
 		}
 
+		systemWhitelist, err := configHelper.GetSystemWhitelist(config.Chain)
+		if err != nil {
+			return nil, err
+		}
+
+		// the transactions must pay the base fee once the nativeToken fork activates,
+		// and the senders must hold the roles of the allowlist once the allowlist fork activates.
+		// The pool checks the activation for every transaction
+		var (
+			baseFee uint64
+			params  = config.Chain.Params
+		)
+
+		if params.NativeToken != nil {
+			baseFee = params.NativeToken.BaseFee
+		}
+
 		// start transaction pool
 		m.txpool, err = txpool.NewTxPool(
 			logger,
-			m.chain.Params.Forks.At(0),
+			m.chain.Params.Forks,
 			hub,
 			m.grpcServer,
 			m.network,
 			&txpool.Config{
+				ChainID:             uint64(m.config.Chain.Params.ChainID),
 				MaxSlots:            m.config.MaxSlots,
 				PriceLimit:          m.config.PriceLimit,
 				MaxAccountEnqueued:  m.config.MaxAccountEnqueued,
 				DeploymentWhitelist: deploymentWhitelist,
+				BundlerWhitelist:    m.config.BundlerWhitelist,
+				SystemSenders:       systemWhitelist,
+				PrioritySenders:     m.config.PrioritySenders,
+				PriorityGasPercent:  m.config.PriorityGasPercent,
+				BaseFee:             baseFee,
+				Allowlist:           params.Allowlist,
 			},
 		)
 		if err != nil {
@@ -230,8 +400,25 @@ func NewServer(config *Config) (*Server, error) {
 		}
 
 		m.txpool.SetSigner(signer)
+
+		if config.TxPoolCache > 0 {
+			txPoolMemory := m.txpool.MemoryConsumer()
+			txPoolMemory.Resize(config.TxPoolCache)
+
+			m.memBudget.Register("txpool", txPoolMemory, config.TxPoolCache)
+		}
 	}
 
+	// the state at the pivot blocks is fetched by the consensus syncer
+	m.stateSync = statesync.NewStateSync(
+		logger,
+		m.network,
+		m.blockchain,
+		st,
+		filepath.Join(m.config.DataDir, "statesync"),
+		m.config.StateSnapshotInterval,
+	)
+
 	{
 		// Setup consensus
 		if err := m.setupConsensus(); err != nil {
@@ -247,11 +434,39 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	// the forked chain starts from the pinned block, on top of the genesis state
+	if m.forkBlock != nil && m.blockchain.Header().Number == 0 {
+		block := &types.Block{
+			Header: m.forkBlock.Header(m.blockchain.Header().StateRoot),
+		}
+
+		if err := m.blockchain.WriteCheckpoint(block, nil, "fork"); err != nil {
+			return nil, fmt.Errorf("failed to start the chain from the forked block: %w", err)
+		}
+	}
+
 	// initialize data in consensus layer
 	if err := m.consensus.Initialize(); err != nil {
 		return nil, err
 	}
 
+	// the validator sets are served only if the consensus seals the headers with them
+	lightConsensus, _ := m.consensus.(light.Consensus)
+	m.lightServer = light.NewServer(logger, m.network, m.blockchain, st, lightConsensus)
+
+	m.congestion = congestion.NewMonitor(logger, congestion.DefaultConfig(), m.blockchain, m.txpool)
+
+	// the participation is reported only if the consensus seals the blocks by several validators
+	participation, _ := m.consensus.(consensus.ParticipationReporter)
+	m.health = health.NewChecker(
+		health.DefaultConfig(time.Duration(config.BlockTime)*time.Second),
+		config.DataDir,
+		m.blockchain,
+		&healthHub{m},
+		&healthHub{m},
+		participation,
+	)
+
 	// setup and start grpc server
 	if err := m.setupGRPC(); err != nil {
 		return nil, err
@@ -261,23 +476,66 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
-	// setup and start jsonrpc server
-	if err := m.setupJSONRPC(); err != nil {
+	// serve the state ranges to the peers
+	if err := m.stateSync.Start(); err != nil {
 		return nil, err
 	}
 
+	// serve the headers and proofs to the light clients
+	m.lightServer.Start()
+
+	// the replica follows the blocks of its primary instead of running the consensus
+	if config.Replica != nil {
+		if m.replica, err = replica.NewReplica(logger, config.Replica, m.blockchain, m.txpool); err != nil {
+			return nil, err
+		}
+	}
+
+	// setup and start jsonrpc server, an import doesn't serve it
+	if !config.RestoreOnly {
+		if err := m.setupJSONRPC(); err != nil {
+			return nil, err
+		}
+	}
+
 	// restore archive data before starting
 	if err := m.restoreChain(); err != nil {
 		return nil, err
 	}
 
-	// start consensus
-	if err := m.consensus.Start(); err != nil {
+	// prune the states as the chain advances
+	if m.pruner != nil {
+		m.pruner.Start(m.blockchain)
+	}
+
+	// move the memory to the caches and the pool under pressure
+	m.memBudget.Start()
+
+	// follow the head with the flat state
+	if m.flatState != nil {
+		if err := m.flatState.Start(m.blockchain); err != nil {
+			return nil, err
+		}
+	}
+
+	// an import closes the server once the chain is restored
+	if config.RestoreOnly {
+		return m, nil
+	}
+
+	// start consensus, or follow the primary
+	if m.replica != nil {
+		if err := m.replica.Start(); err != nil {
+			return nil, err
+		}
+	} else if err := m.consensus.Start(); err != nil {
 		return nil, err
 	}
 
 	m.txpool.Start()
 
+	m.congestion.Start()
+
 	return m, nil
 }
 
@@ -304,14 +562,8 @@ func (t *txpoolHub) GetNonce(root types.Hash, addr types.Address) uint64 {
 		return 0
 	}
 
-	result, ok := snap.Get(keccak.Keccak256(nil, addr.Bytes()))
-	if !ok {
-		return 0
-	}
-
-	var account state.Account
-
-	if err := account.UnmarshalRlp(result); err != nil {
+	account, ok, err := state.ReadAccount(snap, addr)
+	if err != nil || !ok {
 		return 0
 	}
 
@@ -324,22 +576,109 @@ func (t *txpoolHub) GetBalance(root types.Hash, addr types.Address) (*big.Int, e
 		return nil, fmt.Errorf("unable to get snapshot for root, %w", err)
 	}
 
-	result, ok := snap.Get(keccak.Keccak256(nil, addr.Bytes()))
+	account, ok, err := state.ReadAccount(snap, addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal account from snapshot, %w", err)
+	}
+
 	if !ok {
 		return big.NewInt(0), nil
 	}
 
-	var account state.Account
-	if err = account.UnmarshalRlp(result); err != nil {
-		return nil, fmt.Errorf("unable to unmarshal account from snapshot, %w", err)
+	return account.Balance, nil
+}
+
+func (t *txpoolHub) GetStorage(root types.Hash, addr types.Address, slot types.Hash) types.Hash {
+	snap, err := t.state.NewSnapshotAt(root)
+	if err != nil {
+		return types.ZeroHash
 	}
 
-	return account.Balance, nil
+	return state.NewTxn(t.state, snap).GetState(addr, slot)
+}
+
+// openBlockchainStorage creates the blockchain storage over its database,
+// moving the old blocks to the freezer of the ancient directory if it is set
+func (s *Server) openBlockchainStorage(logger hclog.Logger, db kvdb.Database) (storage.Storage, error) {
+	if s.config.AncientDir == "" {
+		moved, err := storage.ReadDatabaseAncientHead(db)
+		if err != nil {
+			return nil, err
+		}
+
+		if moved > 0 {
+			return nil, fmt.Errorf("the first %d blocks of the blockchain database were moved to a freezer, "+
+				"its ancient directory must be set", moved)
+		}
+
+		return storage.NewDatabaseStorage(logger, db), nil
+	}
+
+	freezer, err := storage.OpenFreezer(s.config.AncientDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the freezer: %w", err)
+	}
+
+	blockchainStorage, err := storage.NewAncientDatabaseStorage(logger, db, freezer, s.config.AncientThreshold)
+	if err != nil {
+		_ = freezer.Close()
+
+		return nil, err
+	}
+
+	return blockchainStorage, nil
+}
+
+// setupFork layers the state over the state of the remote chain at the pinned block,
+// which is pinned on the first run
+func (s *Server) setupFork() error {
+	client, err := fork.NewClient(s.config.Fork.URL)
+	if err != nil {
+		return err
+	}
+
+	if s.forkDB, err = s.openDatabase("fork", 0); err != nil {
+		return err
+	}
+
+	if s.forkBlock, err = fork.Pin(client, s.forkDB, s.config.Fork.Block); err != nil {
+		return err
+	}
+
+	s.logger.Info("forking the remote chain", "url", s.config.Fork.URL,
+		"number", s.forkBlock.Number, "hash", s.forkBlock.Hash)
+
+	s.state = fork.NewState(s.logger, s.state, client.At(s.forkBlock.Number), s.forkDB)
+
+	return nil
+}
+
+// openDatabase opens the database of the data directory with the configured engine,
+// caching up to cacheSize bytes of the blocks read from the disk, the engine default if 0
+func (s *Server) openDatabase(name string, cacheSize int) (kvdb.Database, error) {
+	db, err := kvdb.Open(s.config.DBEngine, filepath.Join(s.config.DataDir, name), kvdb.Options{CacheSize: cacheSize})
+	if err != nil {
+		return nil, err
+	}
+
+	return kvdb.WithMetrics(db, name), nil
 }
 
 // setupSecretsManager sets up the secrets manager
 func (s *Server) setupSecretsManager() error {
-	secretsManagerConfig := s.config.SecretsManager
+	secretsManager, err := newSecretsManager(s.config, s.logger)
+	if err != nil {
+		return err
+	}
+
+	s.secretsManager = secretsManager
+
+	return nil
+}
+
+// newSecretsManager instantiates the secrets manager of the config, the local one if it's not set
+func newSecretsManager(config *Config, logger hclog.Logger) (secrets.SecretsManager, error) {
+	secretsManagerConfig := config.SecretsManager
 	if secretsManagerConfig == nil {
 		// No config provided, use default
 		secretsManagerConfig = &secrets.SecretsManagerConfig{
@@ -349,21 +688,21 @@ func (s *Server) setupSecretsManager() error {
 
 	secretsManagerType := secretsManagerConfig.Type
 	secretsManagerParams := &secrets.SecretsManagerParams{
-		Logger: s.logger,
+		Logger: logger,
 	}
 
 	if secretsManagerType == secrets.Local {
 		// Only the base directory is required for
 		// the local secrets manager
 		secretsManagerParams.Extra = map[string]interface{}{
-			secrets.Path: s.config.DataDir,
+			secrets.Path: config.DataDir,
 		}
 	}
 
 	// Grab the factory method
 	secretsManagerFactory, ok := secretsManagerBackends[secretsManagerType]
 	if !ok {
-		return fmt.Errorf("secrets manager type '%s' not found", secretsManagerType)
+		return nil, fmt.Errorf("secrets manager type '%s' not found", secretsManagerType)
 	}
 
 	// Instantiate the secrets manager
@@ -373,12 +712,10 @@ func (s *Server) setupSecretsManager() error {
 	)
 
 	if factoryErr != nil {
-		return fmt.Errorf("unable to instantiate secrets manager, %w", factoryErr)
+		return nil, fmt.Errorf("unable to instantiate secrets manager, %w", factoryErr)
 	}
 
-	s.secretsManager = secretsManager
-
-	return nil
+	return secretsManager, nil
 }
 
 // setupConsensus sets up the consensus mechanism
@@ -401,20 +738,35 @@ func (s *Server) setupConsensus() error {
 		Path:   filepath.Join(s.config.DataDir, "consensus"),
 	}
 
-	consensus, err := engine(
-		&consensus.Params{
-			Context:        context.Background(),
-			Config:         config,
-			TxPool:         s.txpool,
-			Network:        s.network,
-			Blockchain:     s.blockchain,
-			Executor:       s.executor,
-			Grpc:           s.grpcServer,
-			Logger:         s.logger,
-			SecretsManager: s.secretsManager,
-			BlockTime:      s.config.BlockTime,
-		},
-	)
+	params := &consensus.Params{
+		Context:        context.Background(),
+		Config:         config,
+		TxPool:         s.txpool,
+		Network:        s.network,
+		Blockchain:     s.blockchain,
+		Executor:       s.executor,
+		Grpc:           s.grpcServer,
+		Logger:         s.logger,
+		SecretsManager: s.secretsManager,
+		BlockTime:      s.config.BlockTime,
+
+		StrictSignState: s.config.StrictSignState,
+	}
+
+	if s.config.SnapSync {
+		params.SnapSyncer = s.stateSync
+	}
+
+	// the state at the checkpoint is fetched by the snap syncer, even if the snap sync is disabled
+	if s.config.Checkpoint != nil {
+		params.SnapSyncer = s.stateSync
+		params.Checkpoint = &syncer.CheckpointConfig{
+			Checkpoint: *s.config.Checkpoint,
+			Backfill:   s.config.CheckpointBackfill,
+		}
+	}
+
+	consensus, err := engine(params)
 
 	if err != nil {
 		return err
@@ -427,13 +779,17 @@ func (s *Server) setupConsensus() error {
 
 type jsonRPCHub struct {
 	state              state.State
+	stateStorage       itrie.Storage
 	restoreProgression *progress.ProgressionWrapper
+	replica            *replica.Replica
 
 	*blockchain.Blockchain
 	*txpool.TxPool
 	*state.Executor
 	*network.Server
 	consensus.Consensus
+
+	reloadConfig func() error
 }
 
 // HELPER + WRAPPER METHODS //
@@ -442,6 +798,35 @@ func (j *jsonRPCHub) GetPeers() int {
 	return len(j.Server.Peers())
 }
 
+// GetAdminPeers returns the connected peers, along with their connection status
+func (j *jsonRPCHub) GetAdminPeers() []*jsonrpc.AdminPeer {
+	statuses := j.Server.PeerStatuses()
+
+	peers := make([]*jsonrpc.AdminPeer, len(statuses))
+	for i, status := range statuses {
+		addrs := make([]string, len(status.Info.Addrs))
+		for k, addr := range status.Info.Addrs {
+			addrs[k] = addr.String()
+		}
+
+		peers[i] = &jsonrpc.AdminPeer{
+			ID:        status.Info.ID.String(),
+			Addrs:     addrs,
+			Protocols: status.Protocols,
+			Inbound:   status.Inbound,
+			Outbound:  status.Outbound,
+			Static:    status.Static,
+		}
+	}
+
+	return peers
+}
+
+// ReloadConfig reads the config file again and applies the settings which can be changed at runtime
+func (j *jsonRPCHub) ReloadConfig() error {
+	return j.reloadConfig()
+}
+
 func (j *jsonRPCHub) getState(root types.Hash, slot []byte) ([]byte, error) {
 	// the values in the trie are the hashed objects of the keys
 	key := keccak.Keccak256(nil, slot)
@@ -461,22 +846,34 @@ func (j *jsonRPCHub) getState(root types.Hash, slot []byte) ([]byte, error) {
 }
 
 func (j *jsonRPCHub) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
-	obj, err := j.getState(root, addr.Bytes())
+	snap, err := j.state.NewSnapshotAt(root)
 	if err != nil {
 		return nil, err
 	}
 
-	var account state.Account
-	if err := account.UnmarshalRlp(obj); err != nil {
+	account, ok, err := state.ReadAccount(snap, addr)
+	if err != nil {
 		return nil, err
 	}
 
-	return &account, nil
+	if !ok {
+		return nil, jsonrpc.ErrStateNotFound
+	}
+
+	return account, nil
 }
 
-// GetForksInTime returns the active forks at the given block height
-func (j *jsonRPCHub) GetForksInTime(blockNumber uint64) chain.ForksInTime {
-	return j.Executor.GetForksInTime(blockNumber)
+// HasState returns whether the state with the given root is stored,
+// the states preceding the snap sync checkpoint are missing
+func (j *jsonRPCHub) HasState(root types.Hash) bool {
+	_, err := j.state.NewSnapshotAt(root)
+
+	return err == nil
+}
+
+// GetForksInTime returns the active forks in the given block
+func (j *jsonRPCHub) GetForksInTime(header *types.Header) chain.ForksInTime {
+	return j.Executor.GetForksInTime(header)
 }
 
 func (j *jsonRPCHub) GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error) {
@@ -486,6 +883,16 @@ func (j *jsonRPCHub) GetStorage(root types.Hash, addr types.Address, slot types.
 		return nil, err
 	}
 
+	// the storage of the accounts of a remote chain is read by the plain slots
+	if reader, ok := account.Trie.(state.SlotReader); ok {
+		obj, ok := reader.GetSlot(slot)
+		if !ok {
+			return nil, jsonrpc.ErrStateNotFound
+		}
+
+		return obj, nil
+	}
+
 	obj, err := j.getState(account.Root, slot.Bytes())
 
 	if err != nil {
@@ -495,6 +902,33 @@ func (j *jsonRPCHub) GetStorage(root types.Hash, addr types.Address, slot types.
 	return obj, nil
 }
 
+// GetProof returns the trie nodes on the path of the key in the trie with the given root
+func (j *jsonRPCHub) GetProof(root types.Hash, key []byte) ([][]byte, error) {
+	return j.state.Prove(root, keccak.Keccak256(nil, key))
+}
+
+// IterateState calls the handler for every hashed key and value of the trie with the given root
+func (j *jsonRPCHub) IterateState(root types.Hash, handler func(key, value []byte) error) error {
+	return j.state.Iterate(root, handler)
+}
+
+// GetDBStats returns the statistics of the blockchain and state databases, if they report them
+func (j *jsonRPCHub) GetDBStats() map[string]string {
+	stats := make(map[string]string)
+
+	if blockchainStats, err := j.Blockchain.DBStats(); err == nil {
+		stats["blockchain"] = blockchainStats
+	}
+
+	if db, ok := j.stateStorage.(storage.StatsKV); ok {
+		if stateStats, err := db.Stats(); err == nil {
+			stats["state"] = stateStats
+		}
+	}
+
+	return stats
+}
+
 func (j *jsonRPCHub) GetCode(hash types.Hash) ([]byte, error) {
 	res, ok := j.state.GetCode(hash)
 
@@ -508,6 +942,24 @@ func (j *jsonRPCHub) GetCode(hash types.Hash) ([]byte, error) {
 func (j *jsonRPCHub) ApplyTxn(
 	header *types.Header,
 	txn *types.Transaction,
+	override state.StateOverride,
+) (*runtime.ExecutionResult, error) {
+	return j.applyTxn(header, txn, override, nil)
+}
+
+func (j *jsonRPCHub) TraceTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	return j.applyTxn(header, txn, nil, tracer)
+}
+
+func (j *jsonRPCHub) applyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	tracer runtime.Tracer,
 ) (result *runtime.ExecutionResult, err error) {
 	blockCreator, err := j.GetConsensus().GetBlockCreator(header)
 	if err != nil {
@@ -520,50 +972,226 @@ func (j *jsonRPCHub) ApplyTxn(
 		return
 	}
 
+	if err = override.Apply(transition.Txn()); err != nil {
+		return
+	}
+
+	transition.SetCall()
+	transition.SetTracer(tracer)
+
 	result, err = transition.Apply(txn)
 
 	return
 }
 
+// GetStorageChanges returns the modifications of the watched storage slots made by the given block
+func (j *jsonRPCHub) GetStorageChanges(
+	header *types.Header,
+	watches state.StorageWatches,
+) ([]*state.StorageChange, error) {
+	parent, ok := j.GetHeaderByHash(header.ParentHash)
+	if !ok {
+		return nil, blockchain.ErrParentNotFound
+	}
+
+	block, ok := j.GetBlockByHash(header.Hash, true)
+	if !ok {
+		return nil, fmt.Errorf("unable to fetch block %s", header.Hash)
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Executor.GetStorageChanges(parent.StateRoot, block, blockCreator, watches)
+}
+
+// GetAccountChanges returns the watched accounts whose balance, nonce or code are changed by the given block
+func (j *jsonRPCHub) GetAccountChanges(
+	header *types.Header,
+	addrs []types.Address,
+) ([]*state.AccountChange, error) {
+	parent, ok := j.GetHeaderByHash(header.ParentHash)
+	if !ok {
+		return nil, blockchain.ErrParentNotFound
+	}
+
+	return j.Executor.GetAccountChanges(parent.StateRoot, header.StateRoot, addrs)
+}
+
+// TraceBlock re-executes the block on top of its parent state,
+// tracing every transaction with the tracer returned by getTracer
+func (j *jsonRPCHub) TraceBlock(
+	block *types.Block,
+	getTracer func(idx int, tx *types.Transaction) runtime.Tracer,
+) error {
+	parent, ok := j.GetHeaderByHash(block.ParentHash())
+	if !ok {
+		return blockchain.ErrParentNotFound
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(block.Header)
+	if err != nil {
+		return err
+	}
+
+	return j.Executor.TraceBlock(parent.StateRoot, block, blockCreator, getTracer)
+}
+
+// GetBlockWitness re-executes the block on top of its parent state, recording the state it reads
+func (j *jsonRPCHub) GetBlockWitness(block *types.Block) (*state.Witness, error) {
+	parent, ok := j.GetHeaderByHash(block.ParentHash())
+	if !ok {
+		return nil, blockchain.ErrParentNotFound
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Executor.Witness(itrie.NewWitnessState(j.stateStorage), parent.StateRoot, block, blockCreator)
+}
+
+func (j *jsonRPCHub) GetOpcodeStats(hash types.Hash) (*tracer.OpcodeStats, bool) {
+	if j.Executor.OpcodeStats == nil {
+		return nil, false
+	}
+
+	return j.Executor.OpcodeStats.Get(hash)
+}
+
 func (j *jsonRPCHub) GetSyncProgression() *progress.Progression {
+	return syncProgression(j.restoreProgression, j.replica, j.Consensus)
+}
+
+// syncProgression returns the progression of the restore, the catch-up with the primary
+// or the consensus sync, whichever is in progress
+func syncProgression(
+	restoreProgression *progress.ProgressionWrapper,
+	replica *replica.Replica,
+	consensus consensus.Consensus,
+) *progress.Progression {
 	// restore progression
-	if restoreProg := j.restoreProgression.GetProgression(); restoreProg != nil {
+	if restoreProg := restoreProgression.GetProgression(); restoreProg != nil {
 		return restoreProg
 	}
 
+	// catch-up with the primary
+	if replica != nil {
+		return replica.GetSyncProgression()
+	}
+
 	// consensus sync progression
-	if consensusSyncProg := j.Consensus.GetSyncProgression(); consensusSyncProg != nil {
+	if consensusSyncProg := consensus.GetSyncProgression(); consensusSyncProg != nil {
 		return consensusSyncProg
 	}
 
 	return nil
 }
 
+// healthHub is the view of the server the health checks read,
+// the replica being set after the checks start
+type healthHub struct {
+	server *Server
+}
+
+func (h *healthHub) GetPeers() int {
+	return len(h.server.network.Peers())
+}
+
+func (h *healthHub) GetSyncProgression() *progress.Progression {
+	return syncProgression(h.server.restoreProgression, h.server.replica, h.server.consensus)
+}
+
 // SETUP //
 
 // setupJSONRCP sets up the JSONRPC server, using the set configuration
 func (s *Server) setupJSONRPC() error {
 	hub := &jsonRPCHub{
 		state:              s.state,
+		stateStorage:       s.stateStorage,
 		restoreProgression: s.restoreProgression,
+		replica:            s.replica,
 		Blockchain:         s.blockchain,
 		TxPool:             s.txpool,
 		Executor:           s.executor,
 		Consensus:          s.consensus,
 		Server:             s.network,
+		reloadConfig:       s.ReloadConfigFile,
 	}
 
 	conf := &jsonrpc.Config{
-		Store:                    hub,
-		Addr:                     s.config.JSONRPC.JSONRPCAddr,
-		ChainID:                  uint64(s.config.Chain.Params.ChainID),
-		ChainName:                s.chain.Name,
-		AccessControlAllowOrigin: s.config.JSONRPC.AccessControlAllowOrigin,
-		PriceLimit:               s.config.PriceLimit,
-		BatchLengthLimit:         s.config.JSONRPC.BatchLengthLimit,
-		BlockRangeLimit:          s.config.JSONRPC.BlockRangeLimit,
+		Store:            hub,
+		Addr:             s.config.JSONRPC.JSONRPCAddr,
+		IPCPath:          s.config.JSONRPC.IPCPath,
+		ChainID:          uint64(s.config.Chain.Params.ChainID),
+		ChainName:        s.chain.Name,
+		Policy:           s.config.JSONRPC.Policy(),
+		PriceLimit:       s.config.PriceLimit,
+		BatchLengthLimit: s.config.JSONRPC.BatchLengthLimit,
+		BatchGasLimit:    s.config.JSONRPC.BatchGasLimit,
+		BlockRangeLimit:  s.config.JSONRPC.BlockRangeLimit,
+		StateHistory:     s.config.JSONRPC.StateHistory,
+		StatePinInterval: s.config.JSONRPC.StatePinInterval,
+
+		FilterTimeout:       s.config.JSONRPC.FilterTimeout,
+		MaxFiltersPerClient: s.config.JSONRPC.MaxFiltersPerClient,
+
+		TxSenderRateLimit: s.config.JSONRPC.TxSenderRateLimit,
+		TxIPRateLimit:     s.config.JSONRPC.TxIPRateLimit,
+		TxRateBurst:       s.config.JSONRPC.TxRateBurst,
+	}
+
+	if s.config.JSONRPC.PersistFilters {
+		conf.FiltersPath = filepath.Join(s.config.DataDir, "filters.json")
+	}
+
+	if s.config.JSONRPC.Webhooks {
+		s.webhooks = webhook.NewManager(s.logger, s.blockchain, filepath.Join(s.config.DataDir, "webhooks.json"))
+
+		if err := s.webhooks.Start(); err != nil {
+			return err
+		}
+
+		conf.Webhooks = s.webhooks
+	}
+
+	if s.config.JSONRPC.UnsafeDebug {
+		s.logger.Warn("unsafe debug JSON-RPC methods are enabled, the chain can be rewound by any client")
+
+		conf.UnsafeDebug = hub
+	}
+
+	if s.config.JSONRPC.Admin {
+		s.logger.Warn("admin JSON-RPC methods are enabled, the peers can be managed by any client")
+
+		conf.Admin = hub
+		conf.Profiler = diagnostics.NewProfiler(filepath.Join(s.config.DataDir, diagnostics.ProfilesDir))
+	}
+
+	// the dev consensus controls its sealing and clock through the evm methods of the development tools
+	if evm, ok := s.consensus.(jsonrpc.EvmStore); ok {
+		conf.Evm = evm
 	}
 
+	if s.config.JSONRPC.GraphQL {
+		handler, err := graphql.NewHandler(s.logger, &graphql.Config{
+			Backend:         hub,
+			ChainID:         uint64(s.config.Chain.Params.ChainID),
+			PriceLimit:      s.config.PriceLimit,
+			BlockRangeLimit: s.config.JSONRPC.BlockRangeLimit,
+		})
+		if err != nil {
+			return err
+		}
+
+		conf.GraphQL = handler
+	}
+
+	conf.Health = s.health.Handler()
+
 	srv, err := jsonrpc.NewJSONRPC(s.logger, conf)
 	if err != nil {
 		return err
@@ -599,26 +1227,130 @@ func (s *Server) Chain() *chain.Chain {
 	return s.chain
 }
 
+// HeadNumber returns the number of the head block of the client
+func (s *Server) HeadNumber() uint64 {
+	return s.blockchain.Header().Number
+}
+
 // JoinPeer attempts to add a new peer to the networking server
 func (s *Server) JoinPeer(rawPeerMultiaddr string) error {
 	return s.network.JoinPeer(rawPeerMultiaddr)
 }
 
+// SetConfigReloader sets the function reading the config file again,
+// which is called on SIGHUP and by admin_reloadConfig
+func (s *Server) SetConfigReloader(reload func() (*Config, error)) {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	s.configReloader = reload
+}
+
+// ReloadConfigFile reads the config file again and applies the settings which can be changed at runtime
+func (s *Server) ReloadConfigFile() error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	if s.configReloader == nil {
+		return errNoConfigFile
+	}
+
+	config, err := s.configReloader()
+	if err != nil {
+		return fmt.Errorf("unable to reload config, %w", err)
+	}
+
+	s.applyConfig(config)
+
+	return nil
+}
+
+// applyConfig applies the log level, the JSON-RPC policy and limits, the peer limits
+// and the price limit of the config to the running node, the other settings need a restart
+func (s *Server) applyConfig(config *Config) {
+	s.logger.SetLevel(config.LogLevel)
+
+	if s.jsonrpcServer != nil && config.JSONRPC != nil {
+		s.jsonrpcServer.SetPolicy(config.JSONRPC.Policy())
+		s.jsonrpcServer.SetTxLimits(&jsonrpc.Config{
+			PriceLimit:        config.PriceLimit,
+			TxSenderRateLimit: config.JSONRPC.TxSenderRateLimit,
+			TxIPRateLimit:     config.JSONRPC.TxIPRateLimit,
+			TxRateBurst:       config.JSONRPC.TxRateBurst,
+		})
+	}
+
+	if config.Network != nil {
+		s.network.SetPeerLimits(config.Network.MaxInboundPeers, config.Network.MaxOutboundPeers)
+	}
+
+	s.txpool.SetPriceLimit(config.PriceLimit)
+
+	s.logger.Info("config reloaded", "log-level", config.LogLevel, "price-limit", config.PriceLimit)
+}
+
 // Close closes the Minimal server (blockchain, networking, consensus)
 func (s *Server) Close() {
+	// Stop following the primary before the blockchain is closed
+	if s.replica != nil {
+		s.replica.Close()
+	}
+
+	// Stop pruning the states
+	if s.pruner != nil {
+		s.pruner.Close()
+	}
+
+	// Stop rebalancing the memory budget
+	s.memBudget.Close()
+
+	// Stop following the head with the flat state
+	if s.flatState != nil {
+		if err := s.flatState.Close(); err != nil {
+			s.logger.Error("failed to close the flat state", "err", err.Error())
+		}
+	}
+
 	// Close the blockchain layer
 	if err := s.blockchain.Close(); err != nil {
 		s.logger.Error("failed to close blockchain", "err", err.Error())
 	}
 
+	// Stop serving the state ranges
+	if err := s.stateSync.Close(); err != nil {
+		s.logger.Error("failed to close state sync", "err", err.Error())
+	}
+
+	// Stop serving the light clients
+	if err := s.lightServer.Close(); err != nil {
+		s.logger.Error("failed to close light server", "err", err.Error())
+	}
+
+	// Stop serving the IPC endpoint
+	if s.jsonrpcServer != nil {
+		if err := s.jsonrpcServer.Close(); err != nil {
+			s.logger.Error("failed to close the IPC endpoint", "err", err.Error())
+		}
+	}
+
+	// Stop notifying the webhooks
+	if s.webhooks != nil {
+		s.webhooks.Close()
+	}
+
+	// Stop watching for congestion
+	s.congestion.Close()
+
 	// Close the networking layer
 	if err := s.network.Close(); err != nil {
 		s.logger.Error("failed to close networking", "err", err.Error())
 	}
 
-	// Close the consensus layer
-	if err := s.consensus.Close(); err != nil {
-		s.logger.Error("failed to close consensus", "err", err.Error())
+	// Close the consensus layer, which isn't started by a replica or an import
+	if s.replica == nil && !s.config.RestoreOnly {
+		if err := s.consensus.Close(); err != nil {
+			s.logger.Error("failed to close consensus", "err", err.Error())
+		}
 	}
 
 	// Close the state storage
@@ -626,14 +1358,29 @@ func (s *Server) Close() {
 		s.logger.Error("failed to close storage for trie", "err", err.Error())
 	}
 
+	// Close the cache of the forked state
+	if s.forkDB != nil {
+		if err := s.forkDB.Close(); err != nil {
+			s.logger.Error("failed to close the fork cache", "err", err.Error())
+		}
+	}
+
 	if s.prometheusServer != nil {
 		if err := s.prometheusServer.Shutdown(context.Background()); err != nil {
 			s.logger.Error("Prometheus server shutdown error", err)
 		}
 	}
 
+	if s.diagnosticsServer != nil {
+		if err := s.diagnosticsServer.Shutdown(context.Background()); err != nil {
+			s.logger.Error("diagnostics server shutdown error", "err", err)
+		}
+	}
+
 	// close the txpool's main loop
-	s.txpool.Close()
+	if !s.config.RestoreOnly {
+		s.txpool.Close()
+	}
 
 	// close DataDog profiler
 	s.closeDataDogProfiler()
@@ -1,14 +1,21 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/armon/go-metrics/prometheus"
+	prom "github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 	"gopkg.in/DataDog/dd-trace-go.v1/profiler"
+
+	"github.com/0xPolygon/polygon-edge/helper/diagnostics"
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
 )
 
 func (s *Server) setupTelemetry() error {
@@ -25,7 +32,30 @@ func (s *Server) setupTelemetry() error {
 		inm, promSink,
 	})
 
-	return nil
+	// the histograms and the labeled counters of the modules are exported next to the go-metrics ones
+	return telemetry.Register(prom.DefaultRegisterer)
+}
+
+// startDiagnosticsServer starts the server of the pprof profiles and execution traces of the node.
+// It isn't authenticated, so it should only listen on a private interface
+func (s *Server) startDiagnosticsServer(listenAddr *net.TCPAddr) *http.Server {
+	diagnostics.EnableContentionProfiles()
+
+	srv := &http.Server{
+		Addr:              listenAddr.String(),
+		Handler:           diagnostics.Handler(),
+		ReadHeaderTimeout: 60 * time.Second,
+	}
+
+	go func() {
+		s.logger.Info("diagnostics server started", "addr", listenAddr.String())
+
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("diagnostics server failed", "err", err)
+		}
+	}()
+
+	return srv
 }
 
 // enableDataDogProfiler enables DataDog profiler. Enable it by setting DD_ENABLE env var.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/health"
 	"github.com/0xPolygon/polygon-edge/network/common"
 	"github.com/0xPolygon/polygon-edge/server/proto"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -27,21 +28,54 @@ type systemService struct {
 // Current: { Number: <blockNumber>; Hash: <headerHash> }
 //
 // P2PAddr: <libp2pAddress>
+//
+// Health: { Healthy; Ready; Problems; Syncing; Peers; Validators; Disk... }
 func (s *systemService) GetStatus(ctx context.Context, req *empty.Empty) (*proto.ServerStatus, error) {
 	header := s.server.blockchain.Header()
 
 	status := &proto.ServerStatus{
 		Network: int64(s.server.chain.Params.ChainID),
+		Genesis: s.server.blockchain.Genesis().String(),
 		Current: &proto.ServerStatus_Block{
 			Number: int64(header.Number),
 			Hash:   header.Hash.String(),
 		},
 		P2PAddr: common.AddrInfoToString(s.server.network.AddrInfo()),
+		Health:  healthToProto(s.server.health.Report()),
 	}
 
 	return status, nil
 }
 
+// healthToProto converts the health report to its proto representation
+func healthToProto(report *health.Report) *proto.ServerStatus_Health {
+	status := &proto.ServerStatus_Health{
+		Healthy:  report.Healthy,
+		Ready:    report.Ready,
+		Problems: report.Problems,
+		Syncing:  report.Syncing,
+		BlockAge: report.LatestBlock.Age,
+		Peers:    int64(report.Peers),
+	}
+
+	if report.Sync != nil {
+		status.HighestBlock = report.Sync.HighestBlock
+	}
+
+	if report.Validators != nil {
+		status.Validators = int64(report.Validators.Validators)
+		status.Sealed = int64(report.Validators.Sealed)
+		status.Validator = report.Validators.Validator
+	}
+
+	if report.Disk != nil {
+		status.DiskTotal = report.Disk.Total
+		status.DiskFree = report.Disk.Free
+	}
+
+	return status
+}
+
 // Subscribe implements the blockchain event subscription service
 func (s *systemService) Subscribe(req *empty.Empty, stream proto.System_SubscribeServer) error {
 	sub := s.server.blockchain.SubscribeEvents()
@@ -83,6 +117,30 @@ func (s *systemService) Subscribe(req *empty.Empty, stream proto.System_Subscrib
 	return nil
 }
 
+// SubscribeCongestion implements the congestion event subscription service
+func (s *systemService) SubscribeCongestion(req *empty.Empty, stream proto.System_SubscribeCongestionServer) error {
+	eventCh, cancel := s.server.congestion.Subscribe()
+	defer cancel()
+
+	for {
+		select {
+		case evnt := <-eventCh:
+			err := stream.Send(&proto.CongestionEvent{
+				Type:   string(evnt.Type),
+				Active: evnt.Active,
+				Number: evnt.Number,
+				Value:  evnt.Value,
+			})
+
+			if err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
 // PeersAdd implements the 'peers add' operator service
 func (s *systemService) PeersAdd(_ context.Context, req *proto.PeersAddRequest) (*proto.PeersAddResponse, error) {
 	if joinErr := s.server.JoinPeer(req.Id); joinErr != nil {
@@ -0,0 +1,142 @@
+package state
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// BlockAccessList is the accounts and storage slots accessed by the transactions of a block.
+// The accesses of a transaction are only kept once it is applied, so the transactions the proposer
+// couldn't include don't change the list the other nodes record when executing the block
+type BlockAccessList struct {
+	accounts map[types.Address]map[types.Hash]struct{}
+
+	// pending are the accesses of the transaction being applied, nil outside of a transaction
+	pending map[types.Address]map[types.Hash]struct{}
+}
+
+// NewBlockAccessList creates an empty access list
+func NewBlockAccessList() *BlockAccessList {
+	return &BlockAccessList{
+		accounts: map[types.Address]map[types.Hash]struct{}{},
+	}
+}
+
+// begin starts recording the accesses of a transaction
+func (l *BlockAccessList) begin() {
+	if l == nil {
+		return
+	}
+
+	l.pending = map[types.Address]map[types.Hash]struct{}{}
+}
+
+// end stops recording the accesses of the transaction, and adds them to the list if it was applied
+func (l *BlockAccessList) end(applied bool) {
+	if l == nil {
+		return
+	}
+
+	if applied {
+		for addr, slots := range l.pending {
+			accountSlots := l.account(l.accounts, addr)
+
+			for slot := range slots {
+				accountSlots[slot] = struct{}{}
+			}
+		}
+	}
+
+	l.pending = nil
+}
+
+func (l *BlockAccessList) account(
+	accounts map[types.Address]map[types.Hash]struct{},
+	addr types.Address,
+) map[types.Hash]struct{} {
+	slots, ok := accounts[addr]
+	if !ok {
+		slots = map[types.Hash]struct{}{}
+		accounts[addr] = slots
+	}
+
+	return slots
+}
+
+func (l *BlockAccessList) touchAccount(addr types.Address) {
+	if l == nil || l.pending == nil {
+		return
+	}
+
+	l.account(l.pending, addr)
+}
+
+func (l *BlockAccessList) touchSlot(addr types.Address, slot types.Hash) {
+	if l == nil || l.pending == nil {
+		return
+	}
+
+	l.account(l.pending, addr)[slot] = struct{}{}
+}
+
+// AccessedAccount is an account of the access list with its accessed storage slots
+type AccessedAccount struct {
+	Address types.Address
+	Slots   []types.Hash
+}
+
+// Accounts returns the accessed accounts sorted by address, with their slots sorted
+func (l *BlockAccessList) Accounts() []AccessedAccount {
+	accounts := make([]AccessedAccount, 0, len(l.accounts))
+
+	for addr, slots := range l.accounts {
+		account := AccessedAccount{
+			Address: addr,
+			Slots:   make([]types.Hash, 0, len(slots)),
+		}
+
+		for slot := range slots {
+			account.Slots = append(account.Slots, slot)
+		}
+
+		sort.Slice(account.Slots, func(i, j int) bool {
+			return bytes.Compare(account.Slots[i].Bytes(), account.Slots[j].Bytes()) < 0
+		})
+
+		accounts = append(accounts, account)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address.Bytes(), accounts[j].Address.Bytes()) < 0
+	})
+
+	return accounts
+}
+
+// Hash returns the hash of the RLP encoded accounts, a list of [address, [slots]]
+func (l *BlockAccessList) Hash() types.Hash {
+	ar := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(ar)
+
+	v := ar.NewArray()
+
+	for _, account := range l.Accounts() {
+		vv := ar.NewArray()
+		vv.Set(ar.NewCopyBytes(account.Address.Bytes()))
+
+		slots := ar.NewArray()
+		for _, slot := range account.Slots {
+			slots.Set(ar.NewCopyBytes(slot.Bytes()))
+		}
+
+		vv.Set(slots)
+		v.Set(vv)
+	}
+
+	return types.BytesToHash(keccak.Keccak256Rlp(nil, v))
+}
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestBlockAccessList(t *testing.T) {
+	t.Parallel()
+
+	txn := newTestTxn(defaultPreState)
+	txn.accessList = NewBlockAccessList()
+
+	// the accesses outside of a transaction aren't recorded
+	txn.GetNonce(addr1)
+	assert.Empty(t, txn.accessList.Accounts())
+
+	emptyHash := txn.accessList.Hash()
+
+	txn.accessList.begin()
+	txn.SetState(addr2, hash2, hash1)
+	txn.GetState(addr2, hash1)
+	txn.GetBalance(addr1)
+	txn.accessList.end(true)
+
+	// the accesses of a transaction which isn't applied are discarded
+	txn.accessList.begin()
+	txn.GetState(addr1, hash1)
+	txn.accessList.end(false)
+
+	assert.Equal(t, []AccessedAccount{
+		{Address: addr1, Slots: []types.Hash{}},
+		{Address: addr2, Slots: []types.Hash{hash1, hash2}},
+	}, txn.accessList.Accounts())
+
+	hash := txn.accessList.Hash()
+	assert.NotEqual(t, emptyHash, hash)
+
+	// the list of the same accesses recorded in another order has the same hash
+	other := NewBlockAccessList()
+	other.begin()
+	other.touchAccount(addr1)
+	other.touchSlot(addr2, hash1)
+	other.touchSlot(addr2, hash2)
+	other.end(true)
+
+	assert.Equal(t, hash, other.Hash())
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// AccountChange is the balance, nonce and code hash of an account changed by a block
+type AccountChange struct {
+	Address  types.Address
+	Balance  *big.Int
+	Nonce    uint64
+	CodeHash types.Hash
+}
+
+// ChangedAccountsState is implemented by the states which know the accounts changed by a block
+type ChangedAccountsState interface {
+	// ChangedAccounts returns the hashed addresses of the accounts committed along with the state at the root,
+	// false if they aren't known
+	ChangedAccounts(root types.Hash) (map[types.Hash]struct{}, bool)
+}
+
+// GetAccountChanges returns the watched accounts whose balance, nonce or code differ between the parent state
+// and the state of the block. Only the accounts committed by the block are compared if the state knows them
+func (e *Executor) GetAccountChanges(
+	parentRoot types.Hash,
+	root types.Hash,
+	addrs []types.Address,
+) ([]*AccountChange, error) {
+	var committed map[types.Hash]struct{}
+
+	if st, ok := e.state.(ChangedAccountsState); ok {
+		committed, _ = st.ChangedAccounts(root)
+	}
+
+	parent, err := e.state.NewSnapshotAt(parentRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := e.state.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*AccountChange, 0)
+
+	for _, addr := range addrs {
+		key := crypto.Keccak256(addr.Bytes())
+
+		if committed != nil {
+			if _, ok := committed[types.BytesToHash(key)]; !ok {
+				continue
+			}
+		}
+
+		before, err := readAccount(parent, key)
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := readAccount(snap, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if after.Nonce == before.Nonce && after.Balance.Cmp(before.Balance) == 0 &&
+			bytes.Equal(after.CodeHash, before.CodeHash) {
+			continue
+		}
+
+		changes = append(changes, &AccountChange{
+			Address:  addr,
+			Balance:  after.Balance,
+			Nonce:    after.Nonce,
+			CodeHash: types.BytesToHash(after.CodeHash),
+		})
+	}
+
+	return changes, nil
+}
+
+// readAccount reads the account with the hashed address, an empty one if it doesn't exist
+func readAccount(snap Snapshot, key []byte) (*Account, error) {
+	data, ok := snap.Get(key)
+	if !ok {
+		return &Account{Balance: big.NewInt(0), CodeHash: emptyCodeHashTwo.Bytes()}, nil
+	}
+
+	account := &Account{}
+	if err := account.UnmarshalRlp(data); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
@@ -0,0 +1,61 @@
+package state
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	// ErrSenderNotAllowed is returned by the transactions of the senders without the sender role of the allowlist
+	ErrSenderNotAllowed = errors.New("sender not allowed by the allowlist")
+	// ErrDeployerNotAllowed is returned by the deployments of the senders without the deployer role of the allowlist
+	ErrDeployerNotAllowed = errors.New("deployer not allowed by the allowlist")
+)
+
+// AllowlistRoles returns the roles of the account in the allowlist, all of them for the admins of the chain
+func (t *Transition) AllowlistRoles(addr types.Address) uint64 {
+	if t.allowlist != nil && t.allowlist.IsAdmin(addr) {
+		return precompiled.AllowlistAllRoles
+	}
+
+	roles := new(big.Int).SetBytes(t.state.GetState(precompiled.AllowlistAddr, precompiled.AllowlistSlot(addr)).Bytes())
+
+	return roles.Uint64()
+}
+
+// SetAllowlistRoles replaces the roles of the account in the allowlist
+func (t *Transition) SetAllowlistRoles(addr types.Address, roles uint64) {
+	value := types.BytesToHash(new(big.Int).SetUint64(roles).Bytes())
+
+	t.setPrecompileState(precompiled.AllowlistAddr, precompiled.AllowlistSlot(addr), value)
+}
+
+// checkAllowlist checks that the sender holds the roles the allowlist requires for the transaction
+func (t *Transition) checkAllowlist(msg *types.Transaction) error {
+	if t.allowlist == nil {
+		return nil
+	}
+
+	if t.allowlist.Transactions && !t.call && t.AllowlistRoles(msg.From)&precompiled.AllowlistSender == 0 {
+		return ErrSenderNotAllowed
+	}
+
+	if msg.IsContractCreation() && !t.allowedDeployer(msg.From) {
+		return ErrDeployerNotAllowed
+	}
+
+	return nil
+}
+
+// allowedDeployer returns whether the account may deploy contracts, the roles are only read
+// when the allowlist restricts the deployments
+func (t *Transition) allowedDeployer(addr types.Address) bool {
+	if t.allowlist == nil || !t.allowlist.Deployments {
+		return true
+	}
+
+	return t.AllowlistRoles(addr)&precompiled.AllowlistDeployer != 0
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
 	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -21,6 +22,9 @@ const (
 
 	TxGas                 uint64 = 21000 // Per transaction not creating a contract
 	TxGasContractCreation uint64 = 53000 // Per transaction that creates a contract
+
+	TxAccessListAddressGas    uint64 = 2400 // Per address in the access list (EIP-2930)
+	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key in the access list (EIP-2930)
 )
 
 var emptyCodeHashTwo = types.BytesToHash(crypto.Keccak256(nil))
@@ -38,6 +42,12 @@ type Executor struct {
 	GetHash GetHashByNumberHelper
 
 	PostHook func(txn *Transition)
+
+	// OpcodeStats records the opcode stats of the processed blocks, if set
+	OpcodeStats *OpcodeStatsRecorder
+
+	// Precompiles are the chain specific precompiled contracts
+	Precompiles []*precompiled.Stateful
 }
 
 // NewExecutor creates a new executor
@@ -54,27 +64,77 @@ func (e *Executor) WriteGenesis(alloc map[types.Address]*chain.GenesisAccount) t
 	txn := NewTxn(e.state, snap)
 
 	for addr, account := range alloc {
-		if account.Balance != nil {
-			txn.AddBalance(addr, account.Balance)
-		}
+		allocateGenesisAccount(txn, addr, account)
+	}
 
-		if account.Nonce != 0 {
-			txn.SetNonce(addr, account.Nonce)
-		}
+	objs := txn.Commit(false)
+	_, root := snap.Commit(objs)
+
+	return types.BytesToHash(root)
+}
+
+// genesisChunkSize is the number of accounts of the allocation file committed at once
+const genesisChunkSize = 1000
+
+// WriteGenesisWithFile writes the genesis allocation along with the accounts streamed from the allocation file,
+// if any. The accounts of the file are committed by chunks, so the memory used doesn't grow with its size
+func (e *Executor) WriteGenesisWithFile(
+	alloc map[types.Address]*chain.GenesisAccount,
+	path string,
+) (types.Hash, error) {
+	root := e.WriteGenesis(alloc)
+	if path == "" {
+		return root, nil
+	}
+
+	snap, err := e.state.NewSnapshotAt(root)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	txn := NewTxn(e.state, snap)
+	count := 0
 
-		if len(account.Code) != 0 {
-			txn.SetCode(addr, account.Code)
+	if err := chain.ReadAllocFile(path, func(addr types.Address, account *chain.GenesisAccount) error {
+		if _, ok := alloc[addr]; ok {
+			return fmt.Errorf("account %s is allocated by both the genesis and the allocation file", addr)
 		}
 
-		for key, value := range account.Storage {
-			txn.SetState(addr, key, value)
+		allocateGenesisAccount(txn, addr, account)
+
+		if count++; count%genesisChunkSize == 0 {
+			snap, _ = snap.Commit(txn.Commit(false))
+			txn = NewTxn(e.state, snap)
 		}
+
+		return nil
+	}); err != nil {
+		return types.ZeroHash, fmt.Errorf("failed to read the allocation file %s: %w", path, err)
 	}
 
-	objs := txn.Commit(false)
-	_, root := snap.Commit(objs)
+	_, committed := snap.Commit(txn.Commit(false))
 
-	return types.BytesToHash(root)
+	return types.BytesToHash(committed), nil
+}
+
+// allocateGenesisAccount sets the allocated fields of the account, the other ones are kept
+// if the account already exists in the state of a forked chain
+func allocateGenesisAccount(txn *Txn, addr types.Address, account *chain.GenesisAccount) {
+	if account.Balance != nil {
+		txn.SetBalance(addr, account.Balance)
+	}
+
+	if account.Nonce != 0 {
+		txn.SetNonce(addr, account.Nonce)
+	}
+
+	if len(account.Code) != 0 {
+		txn.SetCode(addr, account.Code)
+	}
+
+	for key, value := range account.Storage {
+		txn.SetState(addr, key, value)
+	}
 }
 
 type BlockResult struct {
@@ -94,6 +154,12 @@ func (e *Executor) ProcessBlock(
 		return nil, err
 	}
 
+	var statsTracer *tracer.OpcodeStatsTracer
+	if e.OpcodeStats != nil {
+		statsTracer = tracer.NewOpcodeStatsTracer()
+		txn.SetTracer(statsTracer)
+	}
+
 	for _, t := range block.Transactions {
 		if t.ExceedsBlockGasLimit(block.Header.GasLimit) {
 			if err := txn.WriteFailedReceipt(t); err != nil {
@@ -108,9 +174,46 @@ func (e *Executor) ProcessBlock(
 		}
 	}
 
+	if statsTracer != nil {
+		txn.SetTracer(nil)
+		e.OpcodeStats.record(block.Header, statsTracer)
+	}
+
 	return txn, nil
 }
 
+// TraceBlock re-executes the block on top of the parent state. Every transaction is traced
+// with the tracer returned by getTracer, or executed without tracing if it returns nil
+func (e *Executor) TraceBlock(
+	parentRoot types.Hash,
+	block *types.Block,
+	blockCreator types.Address,
+	getTracer func(idx int, tx *types.Transaction) runtime.Tracer,
+) error {
+	txn, err := e.BeginTxn(parentRoot, block.Header, blockCreator)
+	if err != nil {
+		return err
+	}
+
+	for idx, t := range block.Transactions {
+		txn.SetTracer(getTracer(idx, t))
+
+		if t.ExceedsBlockGasLimit(block.Header.GasLimit) {
+			if err := txn.WriteFailedReceipt(t); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := txn.Write(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // StateAt returns snapshot at given root
 func (e *Executor) State() State {
 	return e.state
@@ -121,9 +224,29 @@ func (e *Executor) StateAt(root types.Hash) (Snapshot, error) {
 	return e.state.NewSnapshotAt(root)
 }
 
-// GetForksInTime returns the active forks at the given block height
-func (e *Executor) GetForksInTime(blockNumber uint64) chain.ForksInTime {
-	return e.config.Forks.At(blockNumber)
+// GasTarget returns the block gas target governed by the gas target contract of the chain in the state
+// with the given root, 0 if the chain has no such contract or its first storage slot isn't set
+func (e *Executor) GasTarget(root types.Hash) (uint64, error) {
+	if e.config.GasTargetContract == nil {
+		return 0, nil
+	}
+
+	snap, err := e.state.NewSnapshotAt(root)
+	if err != nil {
+		return 0, err
+	}
+
+	target := new(big.Int).SetBytes(NewTxn(e.state, snap).GetState(*e.config.GasTargetContract, types.ZeroHash).Bytes())
+	if !target.IsUint64() {
+		return math.MaxUint64, nil
+	}
+
+	return target.Uint64(), nil
+}
+
+// GetForksInTime returns the active forks in the given block
+func (e *Executor) GetForksInTime(header *types.Header) chain.ForksInTime {
+	return e.config.Forks.At(header.Number, header.Timestamp)
 }
 
 func (e *Executor) BeginTxn(
@@ -131,7 +254,7 @@ func (e *Executor) BeginTxn(
 	header *types.Header,
 	coinbaseReceiver types.Address,
 ) (*Transition, error) {
-	config := e.config.Forks.At(header.Number)
+	config := e.config.Forks.At(header.Number, header.Timestamp)
 
 	auxSnap2, err := e.state.NewSnapshotAt(parentRoot)
 	if err != nil {
@@ -140,6 +263,10 @@ func (e *Executor) BeginTxn(
 
 	newTxn := NewTxn(e.state, auxSnap2)
 
+	if config.BlockAccessList {
+		newTxn.accessList = NewBlockAccessList()
+	}
+
 	env2 := runtime.TxContext{
 		Coinbase:   coinbaseReceiver,
 		Timestamp:  int64(header.Timestamp),
@@ -162,10 +289,13 @@ func (e *Executor) BeginTxn(
 		totalGas: 0,
 
 		evm:         evm.NewEVM(),
-		precompiles: precompiled.NewPrecompiled(),
+		precompiles: precompiled.NewPrecompiled(e.Precompiles...),
 		PostHook:    e.PostHook,
 	}
 
+	txn.setNativeToken(e.config.NativeTokenAt(header.Number, header.Timestamp))
+	txn.allowlist = e.config.AllowlistAt(header.Number, header.Timestamp)
+
 	return txn, nil
 }
 
@@ -190,6 +320,17 @@ type Transition struct {
 	// runtimes
 	evm         *evm.EVM
 	precompiles *precompiled.Precompiled
+
+	// tracer collecting the execution traces, if any
+	tracer runtime.Tracer
+
+	// nativeToken is the economics of the native token in the block, nil before the nativeToken fork
+	nativeToken *chain.NativeToken
+	// call is set for the calls, which aren't included in blocks
+	call bool
+
+	// allowlist restricts the senders and the deployers of the block, nil before the allowlist fork
+	allowlist *chain.Allowlist
 }
 
 func NewTransition(config chain.ForksInTime, radix *Txn) *Transition {
@@ -201,6 +342,12 @@ func NewTransition(config chain.ForksInTime, radix *Txn) *Transition {
 	}
 }
 
+// AccessList returns the accounts and slots accessed by the applied transactions,
+// nil if the block access list fork isn't active
+func (t *Transition) AccessList() *BlockAccessList {
+	return t.state.accessList
+}
+
 func (t *Transition) TotalGas() uint64 {
 	return t.totalGas
 }
@@ -226,6 +373,7 @@ func (t *Transition) WriteFailedReceipt(txn *types.Transaction) error {
 
 	receipt := &types.Receipt{
 		CumulativeGasUsed: t.totalGas,
+		TransactionType:   txn.Type,
 		TxHash:            txn.Hash,
 		Logs:              t.state.Logs(),
 	}
@@ -272,8 +420,10 @@ func (t *Transition) Write(txn *types.Transaction) error {
 
 	receipt := &types.Receipt{
 		CumulativeGasUsed: t.totalGas,
+		TransactionType:   txn.Type,
 		TxHash:            txn.Hash,
 		GasUsed:           result.GasUsed,
+		Sponsor:           txn.Sponsor,
 	}
 
 	if t.config.Byzantium {
@@ -339,12 +489,20 @@ func (t *Transition) Txn() *Txn {
 // Apply applies a new transaction
 func (t *Transition) Apply(msg *types.Transaction) (*runtime.ExecutionResult, error) {
 	s := t.state.Snapshot()
+
+	t.state.accessList.begin()
 	result, err := t.apply(msg)
 
 	if err != nil {
 		t.state.RevertToSnapshot(s)
 	}
 
+	t.state.accessList.end(err == nil)
+
+	// the transient storage and the created accounts only live for the transaction
+	t.state.ClearTransientStorage()
+	t.state.ClearCreatedAccounts()
+
 	if t.PostHook != nil {
 		t.PostHook(t)
 	}
@@ -363,7 +521,7 @@ func (t *Transition) subGasLimitPrice(msg *types.Transaction) error {
 	upfrontGasCost := new(big.Int).Set(msg.GasPrice)
 	upfrontGasCost.Mul(upfrontGasCost, new(big.Int).SetUint64(msg.Gas))
 
-	if err := t.state.SubBalance(msg.From, upfrontGasCost); err != nil {
+	if err := t.state.SubBalance(feePayer(msg), upfrontGasCost); err != nil {
 		if errors.Is(err, runtime.ErrNotEnoughFunds) {
 			return ErrNotEnoughFundsForGas
 		}
@@ -426,14 +584,27 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	// First check this message satisfies all consensus rules before
 	// applying the message. The rules include these clauses
 	//
+	// 0. the transaction type is enabled
 	// 1. the nonce of the message caller is correct
 	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
 	// 3. the amount of gas required is available in the block
 	// 4. there is no overflow when calculating intrinsic gas
 	// 5. the purchased gas is enough to cover intrinsic usage
 	// 6. caller has enough balance to cover asset transfer for **topmost** call
+	// 7. the gas price covers the base fee of the chain
+	// 8. the sender holds the roles the allowlist requires for the transaction
+	// 9. the sponsor of a sponsored transaction approves paying its fee
 	txn := t.state
 
+	// 0. the transaction type is enabled
+	if msg.Type == types.AccessListTx && !t.config.EIP2930 {
+		return nil, NewTransitionApplicationError(types.ErrTxTypeNotSupported, false)
+	}
+
+	if msg.Type == types.SponsoredTx && (!t.config.Sponsorship || msg.Sponsor == nil) {
+		return nil, NewTransitionApplicationError(types.ErrTxTypeNotSupported, false)
+	}
+
 	// 1. the nonce of the message caller is correct
 	if err := t.nonceCheck(msg); err != nil {
 		return nil, NewTransitionApplicationError(err, true)
@@ -467,6 +638,17 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		return nil, NewTransitionApplicationError(ErrNotEnoughFunds, true)
 	}
 
+	// 7. the gas price covers the base fee of the chain
+	baseFee := t.baseFee()
+	if msg.GasPrice.Cmp(baseFee) < 0 {
+		return nil, NewTransitionApplicationError(ErrBelowBaseFee, false)
+	}
+
+	// 8. the sender holds the roles the allowlist requires for the transaction
+	if err := t.checkAllowlist(msg); err != nil {
+		return nil, NewTransitionApplicationError(err, false)
+	}
+
 	gasPrice := new(big.Int).Set(msg.GasPrice)
 	value := new(big.Int).Set(msg.Value)
 
@@ -474,6 +656,20 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	t.ctx.GasPrice = types.BytesToHash(gasPrice.Bytes())
 	t.ctx.Origin = msg.From
 
+	// 9. the sponsor of a sponsored transaction approves paying its fee
+	if msg.Sponsor != nil {
+		approvalGas, err := t.approveSponsor(msg, gasLeft)
+		if err != nil {
+			return nil, NewTransitionApplicationError(err, false)
+		}
+
+		gasLeft -= approvalGas
+	}
+
+	if t.tracer != nil {
+		t.tracer.TxStart(t, msg)
+	}
+
 	var result *runtime.ExecutionResult
 	if msg.IsContractCreation() {
 		result = t.Create2(msg.From, msg.Input, value, gasLeft)
@@ -485,12 +681,21 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	refund := txn.GetRefund()
 	result.UpdateGasUsed(msg.Gas, refund)
 
-	// refund the sender
+	if t.tracer != nil {
+		t.tracer.TxEnd(result)
+	}
+
+	// refund the sender, or the sponsor which paid the fee
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), gasPrice)
-	txn.AddBalance(msg.From, remaining)
+	txn.AddBalance(feePayer(msg), remaining)
 
-	// pay the coinbase
-	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), gasPrice)
+	// pay the base fee, and the rest of the fee to the coinbase
+	gasUsed := new(big.Int).SetUint64(result.GasUsed)
+	if baseFee.Sign() > 0 {
+		t.payBaseFee(new(big.Int).Mul(gasUsed, baseFee))
+	}
+
+	coinbaseFee := new(big.Int).Mul(gasUsed, new(big.Int).Sub(gasPrice, baseFee))
 	txn.AddBalance(t.ctx.Coinbase, coinbaseFee)
 
 	// return gas to the pool
@@ -560,7 +765,15 @@ func (t *Transition) applyCall(
 	c *runtime.Contract,
 	callType runtime.CallType,
 	host runtime.Host,
-) *runtime.ExecutionResult {
+) (result *runtime.ExecutionResult) {
+	if t.tracer != nil {
+		t.captureCallStart(c, callType)
+
+		defer func() {
+			t.captureCallEnd(c, result)
+		}()
+	}
+
 	if c.Depth > int(1024)+1 {
 		return &runtime.ExecutionResult{
 			GasLeft: c.Gas,
@@ -581,7 +794,7 @@ func (t *Transition) applyCall(
 		}
 	}
 
-	result := t.run(c, host)
+	result = t.run(c, host)
 	if result.Failed() {
 		t.state.RevertToSnapshot(snapshot)
 	}
@@ -606,7 +819,20 @@ func (t *Transition) hasCodeOrNonce(addr types.Address) bool {
 	return false
 }
 
-func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtime.ExecutionResult {
+func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) (result *runtime.ExecutionResult) {
+	if t.tracer != nil {
+		callType := runtime.Create
+		if c.Type == runtime.Create2 {
+			callType = runtime.Create2
+		}
+
+		t.captureCallStart(c, callType)
+
+		defer func() {
+			t.captureCallEnd(c, result)
+		}()
+	}
+
 	gasLimit := c.Gas
 
 	if c.Depth > int(1024)+1 {
@@ -616,6 +842,14 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		}
 	}
 
+	// The contracts deploy on behalf of the sender of the transaction
+	if !t.allowedDeployer(t.ctx.Origin) {
+		return &runtime.ExecutionResult{
+			GasLeft: gasLimit,
+			Err:     ErrDeployerNotAllowed,
+		}
+	}
+
 	// Increment the nonce of the caller
 	t.state.IncrNonce(c.Caller)
 
@@ -644,7 +878,7 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		}
 	}
 
-	result := t.run(c, host)
+	result = t.run(c, host)
 
 	if result.Failed() {
 		t.state.RevertToSnapshot(snapshot)
@@ -684,6 +918,41 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 	return result
 }
 
+// SetCall marks the following transactions as calls, which aren't included in blocks and are usually
+// priced at zero. They don't pay the base fee, nor need the sender role of the allowlist
+func (t *Transition) SetCall() {
+	t.call = true
+	t.ctx.BaseFee = 0
+}
+
+// SetTracer sets the tracer collecting the execution traces of the following transactions
+func (t *Transition) SetTracer(tracer runtime.Tracer) {
+	t.tracer = tracer
+}
+
+// GetTracer returns the tracer of the transition, if any
+func (t *Transition) GetTracer() runtime.Tracer {
+	return t.tracer
+}
+
+func (t *Transition) captureCallStart(c *runtime.Contract, callType runtime.CallType) {
+	input := c.Input
+	if callType == runtime.Create || callType == runtime.Create2 {
+		input = c.Code
+	}
+
+	t.tracer.CallStart(c.Depth, callType, c.Caller, c.Address, input, c.Gas, c.Value)
+}
+
+func (t *Transition) captureCallEnd(c *runtime.Contract, result *runtime.ExecutionResult) {
+	var gasUsed uint64
+	if c.Gas > result.GasLeft {
+		gasUsed = c.Gas - result.GasLeft
+	}
+
+	t.tracer.CallEnd(c.Depth, result.ReturnValue, gasUsed, result.Err)
+}
+
 func (t *Transition) SetStorage(
 	addr types.Address,
 	key types.Hash,
@@ -693,6 +962,14 @@ func (t *Transition) SetStorage(
 	return t.state.SetStorage(addr, key, value, config)
 }
 
+func (t *Transition) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	return t.state.GetTransientState(addr, key)
+}
+
+func (t *Transition) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	t.state.SetTransientState(addr, key, value)
+}
+
 func (t *Transition) GetTxContext() runtime.TxContext {
 	return t.ctx
 }
@@ -725,6 +1002,19 @@ func (t *Transition) GetStorage(addr types.Address, key types.Hash) types.Hash {
 	return t.state.GetState(addr, key)
 }
 
+// MatchKnownAccounts checks if the accounts hold the storage slots expected by the transaction
+func (t *Transition) MatchKnownAccounts(txn *types.Transaction) bool {
+	for addr, slots := range txn.KnownAccounts {
+		for key, value := range slots {
+			if t.state.GetState(addr, key) != value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 func (t *Transition) AccountExists(addr types.Address) bool {
 	return t.state.Exist(addr)
 }
@@ -738,6 +1028,18 @@ func (t *Transition) GetNonce(addr types.Address) uint64 {
 }
 
 func (t *Transition) Selfdestruct(addr types.Address, beneficiary types.Address) {
+	// eip-6780, the accounts created in the previous transactions only send away their balance
+	if t.config.EIP6780 && !t.state.IsCreated(addr) {
+		if addr != beneficiary {
+			balance := new(big.Int).Set(t.state.GetBalance(addr))
+
+			t.state.SetBalance(addr, big.NewInt(0))
+			t.state.AddBalance(beneficiary, balance)
+		}
+
+		return
+	}
+
 	if !t.state.HasSuicided(addr) {
 		t.state.AddRefund(24000)
 	}
@@ -825,5 +1127,22 @@ func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul bool) (u
 		cost += zeros * 4
 	}
 
+	// the access list is only present in typed transactions, which are enabled by the EIP2930 fork
+	if len(msg.AccessList) > 0 {
+		addresses, storageKeys := uint64(len(msg.AccessList)), uint64(msg.AccessList.StorageKeys())
+
+		if (math.MaxUint64-cost)/TxAccessListAddressGas < addresses {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += addresses * TxAccessListAddressGas
+
+		if (math.MaxUint64-cost)/TxAccessListStorageKeyGas < storageKeys {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += storageKeys * TxAccessListStorageKeyGas
+	}
+
 	return cost, nil
 }
@@ -0,0 +1,145 @@
+package flat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// generateChunkSize is the number of accounts generated at once, along with the progress
+	generateChunkSize = 1000
+)
+
+var errChunkFull = errors.New("chunk full")
+
+// wakeGenerator resumes the generation of the disk layer
+func (t *Tree) wakeGenerator() {
+	select {
+	case t.genCh <- struct{}{}:
+	default:
+	}
+}
+
+// generate generates the disk layer from its state trie, in chunks of accounts.
+// The flattening of the diff layers is held off while a chunk is generated
+func (t *Tree) generate() {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case <-t.closeCh:
+			return
+		case <-t.genCh:
+		}
+
+		start := time.Now()
+
+		for {
+			done, err := t.generateChunk()
+			if err != nil {
+				t.logger.Error("failed to generate the flat state", "err", err)
+
+				break
+			}
+
+			if done {
+				t.logger.Info("generated the flat state", "duration", time.Since(start))
+
+				break
+			}
+
+			select {
+			case <-t.closeCh:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// generateChunk generates the next chunk of accounts of the disk layer, along with their storage.
+// It returns true if the disk layer is generated
+func (t *Tree) generateChunk() (bool, error) {
+	t.genLock.Lock()
+	defer t.genLock.Unlock()
+
+	t.lock.RLock()
+	root, progress := t.disk.hash, t.progress
+	t.lock.RUnlock()
+
+	if !progress.Generating {
+		return true, nil
+	}
+
+	var (
+		batch = t.db.NewBatch()
+		count = 0
+		last  = progress.Marker
+	)
+
+	err := t.state.IterateFrom(root, progress.Marker, func(key, value []byte) error {
+		// the marker has already been generated
+		if progress.Marker != nil && bytes.Equal(key, progress.Marker) {
+			return nil
+		}
+
+		if count == generateChunkSize {
+			return errChunkFull
+		}
+
+		batch.Put(accountKey(key), value)
+
+		var account state.Account
+		if err := account.UnmarshalRlp(value); err != nil {
+			return err
+		}
+
+		if account.Root != types.EmptyRootHash {
+			if err := t.state.Iterate(account.Root, func(slot, data []byte) error {
+				batch.Put(storageKey(key, slot), data)
+
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		last = append([]byte{}, key...)
+		count++
+
+		return nil
+	})
+
+	done := err == nil
+	if err != nil && !errors.Is(err, errChunkFull) {
+		return false, err
+	}
+
+	progress = generatorProgress{Generating: !done, Marker: last}
+	if done {
+		progress.Marker = nil
+	}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return false, err
+	}
+
+	batch.Put(generatorKey, data)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := batch.Write(); err != nil {
+		return false, err
+	}
+
+	t.progress = progress
+
+	return done, nil
+}
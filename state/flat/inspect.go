@@ -0,0 +1,41 @@
+package flat
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Tables of the flat state database, reported by the inspection of the database
+const (
+	TableAccounts = "accounts"
+	TableStorage  = "storage"
+	TableMetadata = "metadata"
+)
+
+// KeyTable returns the table of the key of the flat state database, the root and the generation progress
+// of the disk layer are metadata
+func KeyTable(key []byte) string {
+	switch {
+	case len(key) == len(accountPrefix)+types.HashLength && key[0] == accountPrefix[0]:
+		return TableAccounts
+	case len(key) == len(storagePrefix)+2*types.HashLength && key[0] == storagePrefix[0]:
+		return TableStorage
+	default:
+		return TableMetadata
+	}
+}
+
+// VerifyEntry checks the account of the flat state decodes as the one of the state trie
+func VerifyEntry(key, value []byte) error {
+	if KeyTable(key) != TableAccounts {
+		return nil
+	}
+
+	if err := new(state.Account).UnmarshalRlp(value); err != nil {
+		return fmt.Errorf("invalid account: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package flat
+
+import (
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	// accountPrefix is the prefix of the accounts keyed by their hashed address
+	accountPrefix = []byte("a")
+	// storagePrefix is the prefix of the storage slots keyed by the hashed address and the hashed slot
+	storagePrefix = []byte("s")
+)
+
+// layer is the flat view of the state at a root. The values are the ones stored in the tries,
+// nil if the account or the slot doesn't exist. The layer doesn't cover the value if it can't tell it,
+// so it is read from the trie
+type layer interface {
+	root() types.Hash
+	account(hash types.Hash) ([]byte, bool)
+	storage(addrHash, slotHash types.Hash) ([]byte, bool)
+}
+
+func accountKey(hash []byte) []byte {
+	return append(append([]byte{}, accountPrefix...), hash...)
+}
+
+func storageKey(addrHash, slotHash []byte) []byte {
+	return append(append(append([]byte{}, storagePrefix...), addrHash...), slotHash...)
+}
+
+// diskLayer is the flat state persisted in the database, the bottom layer of the tree.
+// Only the accounts which are already generated are covered while the layer is generated
+type diskLayer struct {
+	tree  *Tree
+	hash  types.Hash
+	stale bool
+}
+
+func (d *diskLayer) root() types.Hash {
+	return d.hash
+}
+
+func (d *diskLayer) account(hash types.Hash) ([]byte, bool) {
+	if d.stale || !d.tree.generated(hash) {
+		return nil, false
+	}
+
+	return d.get(accountKey(hash.Bytes()))
+}
+
+func (d *diskLayer) storage(addrHash, slotHash types.Hash) ([]byte, bool) {
+	if d.stale || !d.tree.generated(addrHash) {
+		return nil, false
+	}
+
+	return d.get(storageKey(addrHash.Bytes(), slotHash.Bytes()))
+}
+
+func (d *diskLayer) get(key []byte) ([]byte, bool) {
+	data, _, err := d.tree.db.Get(key)
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// diffLayer holds the accounts and storage slots changed by a block on top of its parent layer
+type diffLayer struct {
+	parent layer
+	hash   types.Hash
+	stale  bool
+
+	// accounts whose storage is wiped, since they are deleted or created again
+	destructs map[types.Hash]struct{}
+	// hashed address -> account, nil if it is deleted
+	accounts map[types.Hash][]byte
+	// hashed address -> hashed slot -> value, nil if it is deleted
+	slots map[types.Hash]map[types.Hash][]byte
+}
+
+func (d *diffLayer) root() types.Hash {
+	return d.hash
+}
+
+func (d *diffLayer) account(hash types.Hash) ([]byte, bool) {
+	if d.stale {
+		return nil, false
+	}
+
+	if data, ok := d.accounts[hash]; ok {
+		return data, true
+	}
+
+	if _, ok := d.destructs[hash]; ok {
+		return nil, true
+	}
+
+	return d.parent.account(hash)
+}
+
+func (d *diffLayer) storage(addrHash, slotHash types.Hash) ([]byte, bool) {
+	if d.stale {
+		return nil, false
+	}
+
+	if data, ok := d.slots[addrHash][slotHash]; ok {
+		return data, true
+	}
+
+	if _, ok := d.destructs[addrHash]; ok {
+		return nil, true
+	}
+
+	return d.parent.storage(addrHash, slotHash)
+}
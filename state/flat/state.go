@@ -0,0 +1,111 @@
+package flat
+
+import (
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// State is the state whose accounts and storage slots are read from the flat state,
+// and from the tries if the flat state doesn't cover them
+type State struct {
+	*itrie.State
+	tree *Tree
+}
+
+// NewState creates the state reading through the flat state tree
+func NewState(st *itrie.State, tree *Tree) *State {
+	return &State{
+		State: st,
+		tree:  tree,
+	}
+}
+
+func (s *State) NewSnapshot() state.Snapshot {
+	return s.wrap(types.EmptyRootHash, s.State.NewSnapshot())
+}
+
+func (s *State) NewSnapshotAt(root types.Hash) (state.Snapshot, error) {
+	snap, err := s.State.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wrap(root, snap), nil
+}
+
+func (s *State) wrap(root types.Hash, snap state.Snapshot) *snapshot {
+	return &snapshot{
+		state: s,
+		root:  root,
+		snap:  snap,
+	}
+}
+
+// snapshot is the state trie at a root read through the flat state
+type snapshot struct {
+	state *State
+	root  types.Hash
+	snap  state.Snapshot
+}
+
+func (s *snapshot) Get(k []byte) ([]byte, bool) {
+	if data, ok := s.state.tree.account(s.root, types.BytesToHash(k)); ok {
+		return data, data != nil
+	}
+
+	return s.snap.Get(k)
+}
+
+func (s *snapshot) Commit(objs []*state.Object) (state.Snapshot, []byte) {
+	snap, root := s.snap.Commit(objs)
+	hash := types.BytesToHash(root)
+
+	s.state.tree.update(s.root, hash, objs, snap)
+
+	return s.state.wrap(hash, snap), root
+}
+
+func (s *snapshot) Storage(addrHash types.Hash, root types.Hash) state.StorageReader {
+	return &storageReader{
+		snapshot:    s,
+		addrHash:    addrHash,
+		storageRoot: root,
+	}
+}
+
+// storageReader reads the storage of an account from the flat state,
+// its storage trie is loaded if the flat state doesn't cover the slot
+type storageReader struct {
+	snapshot    *snapshot
+	addrHash    types.Hash
+	storageRoot types.Hash
+	trie        state.Snapshot
+}
+
+func (r *storageReader) Get(k []byte) ([]byte, bool) {
+	if data, ok := r.snapshot.state.tree.storage(r.snapshot.root, r.addrHash, types.BytesToHash(k)); ok {
+		return data, data != nil
+	}
+
+	if r.storageRoot == types.EmptyRootHash {
+		return nil, false
+	}
+
+	if r.trie == nil {
+		trie, err := r.snapshot.state.State.NewSnapshotAt(r.storageRoot)
+		if err != nil {
+			return nil, false
+		}
+
+		r.trie = trie
+	}
+
+	return r.trie.Get(k)
+}
+
+// ChangedAccounts returns the hashed addresses of the accounts committed along with the state at the root,
+// false if the diff layer of the state isn't in the tree
+func (s *State) ChangedAccounts(root types.Hash) (map[types.Hash]struct{}, bool) {
+	return s.tree.changedAccounts(root)
+}
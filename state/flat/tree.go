@@ -0,0 +1,529 @@
+package flat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// maxDiffLayers is the number of diff layers kept in memory below the head, the older ones are
+	// flattened into the disk layer. The disk layer is generated from its state trie, so it is kept
+	// within the states retained by the pruner
+	maxDiffLayers = 64
+)
+
+var (
+	// rootKey is the key of the root of the disk layer
+	rootKey = []byte("root")
+	// generatorKey is the key of the generation progress of the disk layer
+	generatorKey = []byte("generator")
+)
+
+// Blockchain is the chain followed by the tree
+type Blockchain interface {
+	Header() *types.Header
+	SubscribeEvents() blockchain.Subscription
+}
+
+// generatorProgress is the generation progress of the disk layer, written along with the generated accounts
+type generatorProgress struct {
+	Generating bool `json:"generating"`
+	// Marker is the last generated account, nil if none
+	Marker []byte `json:"marker,omitempty"`
+}
+
+// Tree is the flat state of the recent blocks, a diff layer for each block on top of the disk layer.
+// It is updated along with the state tries, and it follows the head of the chain: the diff layers
+// below the head are flattened into the disk layer, and it is generated again from the state trie
+// if the head isn't in the tree, like after a reorg deeper than the diff layers or a crash
+type Tree struct {
+	logger hclog.Logger
+	db     kvdb.Database
+	state  *itrie.State
+
+	lock   sync.RWMutex
+	layers map[types.Hash]layer
+	disk   *diskLayer
+	head   types.Hash
+
+	// generation of the disk layer
+	progress generatorProgress
+	// genLock serializes the generation of the accounts and the flattening of the diff layers
+	genLock sync.Mutex
+	genCh   chan struct{}
+
+	subscription blockchain.Subscription
+	closeCh      chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewTree opens the flat state stored in the database, over the state tries.
+// The database is closed along with the tree
+func NewTree(logger hclog.Logger, db kvdb.Database, st *itrie.State) (*Tree, error) {
+	t := &Tree{
+		logger:  logger.Named("flat"),
+		db:      db,
+		state:   st,
+		layers:  make(map[types.Hash]layer),
+		genCh:   make(chan struct{}, 1),
+		closeCh: make(chan struct{}),
+	}
+
+	root, ok, err := db.Get(rootKey)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return t, nil
+	}
+
+	data, ok, err := db.Get(generatorKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the generation progress: %w", err)
+	} else if !ok {
+		return nil, errors.New("failed to read the generation progress: not found")
+	}
+
+	if err := json.Unmarshal(data, &t.progress); err != nil {
+		return nil, fmt.Errorf("failed to read the generation progress: %w", err)
+	}
+
+	t.disk = &diskLayer{tree: t, hash: types.BytesToHash(root)}
+	t.layers[t.disk.hash] = t.disk
+
+	return t, nil
+}
+
+// Start follows the head of the chain, the disk layer is generated again if it isn't at the head
+func (t *Tree) Start(chain Blockchain) error {
+	head := chain.Header().StateRoot
+
+	t.lock.Lock()
+
+	if t.disk == nil || t.disk.hash != head {
+		if err := t.reset(head); err != nil {
+			t.lock.Unlock()
+
+			return err
+		}
+	}
+
+	t.head = head
+
+	t.lock.Unlock()
+
+	t.subscription = chain.SubscribeEvents()
+
+	t.wg.Add(2)
+
+	go t.run()
+	go t.generate()
+
+	t.wakeGenerator()
+
+	return nil
+}
+
+// Close stops following the chain, and flattens the diff layers up to the head into the disk layer,
+// so the flat state is at the head when the tree is opened again
+func (t *Tree) Close() error {
+	if t.subscription != nil {
+		t.subscription.Close()
+	}
+
+	close(t.closeCh)
+	t.wg.Wait()
+
+	t.genLock.Lock()
+	defer t.genLock.Unlock()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if head, ok := t.layers[t.head].(*diffLayer); ok {
+		if err := t.flatten(head, 0); err != nil {
+			t.logger.Error("failed to flatten the diff layers", "err", err)
+		}
+	}
+
+	return t.db.Close()
+}
+
+func (t *Tree) run() {
+	defer t.wg.Done()
+
+	for {
+		evnt := t.subscription.GetEvent()
+		if evnt == nil {
+			return
+		}
+
+		if evnt.Type == blockchain.EventFork || len(evnt.NewChain) == 0 {
+			continue
+		}
+
+		if err := t.setHead(evnt.NewChain[len(evnt.NewChain)-1].StateRoot); err != nil {
+			t.logger.Error("failed to follow the head", "err", err)
+		}
+	}
+}
+
+// setHead flattens the diff layers exceeding the ones kept below the head into the disk layer,
+// or generates the disk layer at the head if it isn't in the tree
+func (t *Tree) setHead(root types.Hash) error {
+	t.genLock.Lock()
+	defer t.genLock.Unlock()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.head = root
+
+	head, ok := t.layers[root]
+	if !ok {
+		t.logger.Info("head not in the flat state, generating it again", "root", root)
+
+		if err := t.reset(root); err != nil {
+			return err
+		}
+
+		t.wakeGenerator()
+
+		return nil
+	}
+
+	diff, ok := head.(*diffLayer)
+	if !ok {
+		return nil
+	}
+
+	return t.flatten(diff, maxDiffLayers)
+}
+
+// flatten merges the diff layers below the given one, exceeding the number of layers to keep,
+// into the disk layer. The layers which don't descend from the new disk layer are dropped
+func (t *Tree) flatten(diff *diffLayer, keep int) error {
+	// the diff layers from the bottom one to the given one
+	diffs := []*diffLayer{diff}
+
+	for {
+		parent, ok := diffs[0].parent.(*diffLayer)
+		if !ok {
+			break
+		}
+
+		diffs = append([]*diffLayer{parent}, diffs...)
+	}
+
+	if len(diffs) <= keep {
+		return nil
+	}
+
+	for _, bottom := range diffs[:len(diffs)-keep] {
+		if err := t.flattenBottom(bottom); err != nil {
+			return err
+		}
+	}
+
+	t.dropStaleLayers()
+
+	return nil
+}
+
+// flattenBottom writes the diff layer on top of the disk layer into the database,
+// the children of the diff layer get the new disk layer as parent
+func (t *Tree) flattenBottom(diff *diffLayer) error {
+	batch := t.db.NewBatch()
+
+	for hash := range diff.destructs {
+		if !t.generated(hash) {
+			continue
+		}
+
+		batch.Delete(accountKey(hash.Bytes()))
+
+		iter := t.db.NewIterator(storageKey(hash.Bytes(), nil), nil)
+		for iter.Next() {
+			batch.Delete(append([]byte{}, iter.Key()...))
+		}
+
+		iter.Release()
+	}
+
+	for hash, data := range diff.accounts {
+		if !t.generated(hash) {
+			continue
+		}
+
+		if data == nil {
+			batch.Delete(accountKey(hash.Bytes()))
+		} else {
+			batch.Put(accountKey(hash.Bytes()), data)
+		}
+	}
+
+	for addrHash, slots := range diff.slots {
+		if !t.generated(addrHash) {
+			continue
+		}
+
+		for slotHash, data := range slots {
+			if data == nil {
+				batch.Delete(storageKey(addrHash.Bytes(), slotHash.Bytes()))
+			} else {
+				batch.Put(storageKey(addrHash.Bytes(), slotHash.Bytes()), data)
+			}
+		}
+	}
+
+	batch.Put(rootKey, diff.hash.Bytes())
+
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	disk := &diskLayer{tree: t, hash: diff.hash}
+
+	t.disk.stale = true
+	diff.stale = true
+
+	delete(t.layers, t.disk.hash)
+	t.layers[diff.hash] = disk
+	t.disk = disk
+
+	for _, l := range t.layers {
+		if child, ok := l.(*diffLayer); ok && child.parent == diff {
+			child.parent = disk
+		}
+	}
+
+	return nil
+}
+
+// dropStaleLayers removes the diff layers which don't descend from the disk layer
+func (t *Tree) dropStaleLayers() {
+	for hash, l := range t.layers {
+		diff, ok := l.(*diffLayer)
+		if !ok {
+			continue
+		}
+
+		var ancestor layer = diff
+
+		for {
+			parent, ok := ancestor.(*diffLayer)
+			if !ok || parent.stale {
+				break
+			}
+
+			ancestor = parent.parent
+		}
+
+		if ancestor != layer(t.disk) {
+			diff.stale = true
+
+			delete(t.layers, hash)
+		}
+	}
+}
+
+// reset wipes the flat state, and starts generating the disk layer at the given root
+func (t *Tree) reset(root types.Hash) error {
+	batch := t.db.NewBatch()
+
+	iter := t.db.NewIterator(nil, nil)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+
+	iter.Release()
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	progress := generatorProgress{Generating: true}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	batch.Put(rootKey, root.Bytes())
+	batch.Put(generatorKey, data)
+
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	for _, l := range t.layers {
+		switch typed := l.(type) {
+		case *diffLayer:
+			typed.stale = true
+		case *diskLayer:
+			typed.stale = true
+		}
+	}
+
+	t.progress = progress
+	t.disk = &diskLayer{tree: t, hash: root}
+	t.layers = map[types.Hash]layer{root: t.disk}
+
+	return nil
+}
+
+// generated checks whether the account has been generated in the disk layer
+func (t *Tree) generated(hash types.Hash) bool {
+	if !t.progress.Generating {
+		return true
+	}
+
+	return t.progress.Marker != nil && bytes.Compare(hash.Bytes(), t.progress.Marker) <= 0
+}
+
+var storageArenaPool fastrlp.ArenaPool
+
+// update adds the diff layer of the state committed on top of the parent one,
+// the accounts are read from the committed state trie
+func (t *Tree) update(parent, root types.Hash, objs []*state.Object, snap state.Snapshot) {
+	if parent == root {
+		return
+	}
+
+	t.lock.RLock()
+	parentLayer, ok := t.layers[parent]
+	_, exists := t.layers[root]
+	t.lock.RUnlock()
+
+	// the parent state isn't followed, or the state has already been committed
+	if !ok || exists {
+		return
+	}
+
+	diff := &diffLayer{
+		parent:    parentLayer,
+		hash:      root,
+		destructs: make(map[types.Hash]struct{}),
+		accounts:  make(map[types.Hash][]byte, len(objs)),
+		slots:     make(map[types.Hash]map[types.Hash][]byte),
+	}
+
+	arena := storageArenaPool.Get()
+	defer storageArenaPool.Put(arena)
+
+	for _, obj := range objs {
+		hash := types.BytesToHash(crypto.Keccak256(obj.Address.Bytes()))
+
+		if obj.Deleted {
+			diff.destructs[hash] = struct{}{}
+			diff.accounts[hash] = nil
+
+			continue
+		}
+
+		data, ok := snap.Get(hash.Bytes())
+		if !ok {
+			diff.accounts[hash] = nil
+
+			continue
+		}
+
+		diff.accounts[hash] = data
+
+		// the storage of an account created again starts from the empty one
+		if obj.Root == types.EmptyRootHash {
+			diff.destructs[hash] = struct{}{}
+		}
+
+		if len(obj.Storage) == 0 {
+			continue
+		}
+
+		slots := make(map[types.Hash][]byte, len(obj.Storage))
+
+		for _, entry := range obj.Storage {
+			slotHash := types.BytesToHash(crypto.Keccak256(entry.Key))
+
+			if entry.Deleted {
+				slots[slotHash] = nil
+			} else {
+				slots[slotHash] = arena.NewBytes(bytes.TrimLeft(entry.Val, "\x00")).MarshalTo(nil)
+			}
+		}
+
+		arena.Reset()
+
+		diff.slots[hash] = slots
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// the parent layer may have been flattened into the disk layer, or dropped meanwhile
+	if current := t.layers[parent]; current != parentLayer {
+		if current == nil || current != layer(t.disk) {
+			return
+		}
+
+		diff.parent = current
+	}
+
+	t.layers[root] = diff
+}
+
+// account returns the account with the hashed address in the state at the root, if covered by the tree
+func (t *Tree) account(root, hash types.Hash) ([]byte, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	l, ok := t.layers[root]
+	if !ok {
+		return nil, false
+	}
+
+	return l.account(hash)
+}
+
+// changedAccounts returns the hashed addresses of the accounts of the diff layer of the state at the root
+func (t *Tree) changedAccounts(root types.Hash) (map[types.Hash]struct{}, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	diff, ok := t.layers[root].(*diffLayer)
+	if !ok || diff.stale {
+		return nil, false
+	}
+
+	accounts := make(map[types.Hash]struct{}, len(diff.accounts))
+
+	for hash := range diff.accounts {
+		accounts[hash] = struct{}{}
+	}
+
+	return accounts, true
+}
+
+// storage returns the storage slot of the account in the state at the root, if covered by the tree
+func (t *Tree) storage(root, addrHash, slotHash types.Hash) ([]byte, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	l, ok := t.layers[root]
+	if !ok {
+		return nil, false
+	}
+
+	return l.storage(addrHash, slotHash)
+}
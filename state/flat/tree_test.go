@@ -0,0 +1,322 @@
+package flat
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	addr1 = types.StringToAddress("1")
+	addr2 = types.StringToAddress("2")
+
+	slot1 = types.StringToHash("1")
+	slot2 = types.StringToHash("2")
+)
+
+func newTestTree(t *testing.T) (*Tree, *State) {
+	t.Helper()
+
+	st := itrie.NewState(itrie.NewMemoryStorage())
+
+	tree, err := NewTree(hclog.NewNullLogger(), kvdb.NewMemoryDatabase(), st)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = tree.db.Close()
+	})
+
+	return tree, NewState(st, tree)
+}
+
+// commit applies the changes to the state at the root, and returns the new root
+func commit(t *testing.T, st state.State, root types.Hash, change func(txn *state.Txn)) types.Hash {
+	t.Helper()
+
+	snap, err := st.NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	txn := state.NewTxn(st, snap)
+	change(txn)
+
+	_, newRoot := snap.Commit(txn.Commit(false))
+
+	return types.BytesToHash(newRoot)
+}
+
+// generate generates the disk layer of the tree at the root
+func generate(t *testing.T, tree *Tree, root types.Hash) {
+	t.Helper()
+
+	require.NoError(t, tree.reset(root))
+
+	for {
+		done, err := tree.generateChunk()
+		require.NoError(t, err)
+
+		if done {
+			return
+		}
+	}
+}
+
+// assertAccount checks the account and its storage read from the flat state
+func assertAccount(t *testing.T, tree *Tree, root types.Hash, addr types.Address, slots map[types.Hash]types.Hash) {
+	t.Helper()
+
+	addrHash := types.BytesToHash(crypto.Keccak256(addr.Bytes()))
+
+	expected, err := tree.state.NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	expectedData, _ := expected.Get(addrHash.Bytes())
+
+	data, ok := tree.account(root, addrHash)
+	require.True(t, ok)
+	assert.Equal(t, expectedData, data)
+
+	for slot, value := range slots {
+		data, ok := tree.storage(root, addrHash, types.BytesToHash(crypto.Keccak256(slot.Bytes())))
+		require.True(t, ok)
+
+		if value == types.ZeroHash {
+			assert.Nil(t, data)
+		} else {
+			assert.Equal(t, value, decodeSlot(t, data))
+		}
+	}
+}
+
+// decodeSlot decodes the value of a storage slot
+func decodeSlot(t *testing.T, data []byte) types.Hash {
+	t.Helper()
+
+	var p fastrlp.Parser
+
+	v, err := p.Parse(data)
+	require.NoError(t, err)
+
+	value, err := v.GetBytes(nil)
+	require.NoError(t, err)
+
+	return types.BytesToHash(value)
+}
+
+func TestTree_Generate(t *testing.T) {
+	t.Parallel()
+
+	tree, st := newTestTree(t)
+
+	root := commit(t, st.State, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.SetBalance(addr1, big.NewInt(1))
+		txn.SetState(addr1, slot1, types.StringToHash("10"))
+		txn.SetState(addr1, slot2, types.StringToHash("20"))
+
+		for i := 0; i < 2*generateChunkSize; i++ {
+			txn.SetNonce(types.BytesToAddress(big.NewInt(int64(i+100)).Bytes()), 1)
+		}
+	})
+
+	generate(t, tree, root)
+
+	assert.False(t, tree.progress.Generating)
+	assertAccount(t, tree, root, addr1, map[types.Hash]types.Hash{
+		slot1: types.StringToHash("10"),
+		slot2: types.StringToHash("20"),
+	})
+
+	// an account which doesn't exist is covered by the flat state
+	data, ok := tree.account(root, types.BytesToHash(crypto.Keccak256(addr2.Bytes())))
+	assert.True(t, ok)
+	assert.Nil(t, data)
+}
+
+func TestTree_DiffLayers(t *testing.T) {
+	t.Parallel()
+
+	tree, st := newTestTree(t)
+
+	generate(t, tree, types.EmptyRootHash)
+
+	root1 := commit(t, st, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.SetBalance(addr1, big.NewInt(1))
+		txn.SetState(addr1, slot1, types.StringToHash("10"))
+		txn.SetState(addr2, slot1, types.StringToHash("30"))
+	})
+
+	root2 := commit(t, st, root1, func(txn *state.Txn) {
+		txn.SetState(addr1, slot1, types.ZeroHash)
+		txn.SetState(addr1, slot2, types.StringToHash("20"))
+		txn.Suicide(addr2)
+	})
+
+	require.IsType(t, &diffLayer{}, tree.layers[root1])
+	require.IsType(t, &diffLayer{}, tree.layers[root2])
+
+	assertAccount(t, tree, root1, addr1, map[types.Hash]types.Hash{
+		slot1: types.StringToHash("10"),
+	})
+	assertAccount(t, tree, root2, addr1, map[types.Hash]types.Hash{
+		slot1: types.ZeroHash,
+		slot2: types.StringToHash("20"),
+	})
+	assertAccount(t, tree, root2, addr2, map[types.Hash]types.Hash{
+		slot1: types.ZeroHash,
+	})
+
+	// the reads through the state match the tries
+	snap, err := st.NewSnapshotAt(root2)
+	require.NoError(t, err)
+
+	txn := state.NewTxn(st, snap)
+	assert.Equal(t, big.NewInt(1), txn.GetBalance(addr1))
+	assert.Equal(t, types.StringToHash("20"), txn.GetState(addr1, slot2))
+	assert.False(t, txn.Exist(addr2))
+
+	// the bottom layer is flattened into the disk
+	tree.head = root2
+	require.NoError(t, tree.flatten(tree.layers[root2].(*diffLayer), 1))
+
+	require.IsType(t, &diskLayer{}, tree.layers[root1])
+	assertAccount(t, tree, root2, addr1, map[types.Hash]types.Hash{
+		slot1: types.ZeroHash,
+		slot2: types.StringToHash("20"),
+	})
+
+	require.NoError(t, tree.flatten(tree.layers[root2].(*diffLayer), 0))
+
+	require.IsType(t, &diskLayer{}, tree.layers[root2])
+	assert.NotContains(t, tree.layers, root1)
+	assertAccount(t, tree, root2, addr2, map[types.Hash]types.Hash{
+		slot1: types.ZeroHash,
+	})
+}
+
+func TestState_AccountChanges(t *testing.T) {
+	t.Parallel()
+
+	tree, st := newTestTree(t)
+
+	generate(t, tree, types.EmptyRootHash)
+
+	root1 := commit(t, st, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.SetBalance(addr1, big.NewInt(1))
+		txn.SetBalance(addr2, big.NewInt(2))
+	})
+
+	root2 := commit(t, st, root1, func(txn *state.Txn) {
+		txn.SetNonce(addr1, 1)
+		txn.SetState(addr2, slot1, types.StringToHash("10"))
+	})
+
+	// only the accounts committed by the block are compared
+	changed, ok := st.ChangedAccounts(root2)
+	require.True(t, ok)
+	assert.Len(t, changed, 2)
+
+	executor := state.NewExecutor(&chain.Params{}, st, hclog.NewNullLogger())
+
+	changes, err := executor.GetAccountChanges(root1, root2, []types.Address{addr1, addr2})
+	require.NoError(t, err)
+
+	// the storage of addr2 changed, but not its balance, nonce or code
+	require.Len(t, changes, 1)
+	assert.Equal(t, addr1, changes[0].Address)
+	assert.Equal(t, big.NewInt(1), changes[0].Balance)
+	assert.Equal(t, uint64(1), changes[0].Nonce)
+
+	changes, err = executor.GetAccountChanges(types.EmptyRootHash, root1, []types.Address{addr2})
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, big.NewInt(2), changes[0].Balance)
+}
+
+func TestTree_Reorg(t *testing.T) {
+	t.Parallel()
+
+	tree, st := newTestTree(t)
+
+	generate(t, tree, types.EmptyRootHash)
+
+	root1 := commit(t, st, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.SetBalance(addr1, big.NewInt(1))
+	})
+
+	// the fork of the first block
+	fork := commit(t, st, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.SetBalance(addr2, big.NewInt(2))
+	})
+
+	root2 := commit(t, st, root1, func(txn *state.Txn) {
+		txn.SetBalance(addr1, big.NewInt(3))
+	})
+
+	require.NoError(t, tree.setHead(root2))
+
+	// the layers not descending from the disk layer are dropped once flattened
+	require.NoError(t, tree.flatten(tree.layers[root2].(*diffLayer), 1))
+
+	assert.NotContains(t, tree.layers, fork)
+	assert.Contains(t, tree.layers, root1)
+
+	// the flat state is generated again at the head which isn't in the tree
+	require.NoError(t, tree.setHead(fork))
+
+	assert.Len(t, tree.layers, 1)
+	assert.Equal(t, fork, tree.disk.hash)
+	assert.True(t, tree.progress.Generating)
+
+	_, ok := tree.account(fork, types.BytesToHash(crypto.Keccak256(addr2.Bytes())))
+	assert.False(t, ok)
+}
+
+func TestTree_Reopen(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path = t.TempDir()
+		st   = itrie.NewState(itrie.NewMemoryStorage())
+	)
+
+	db, err := kvdb.Open(kvdb.LevelDB, path, kvdb.Options{})
+	require.NoError(t, err)
+
+	tree, err := NewTree(hclog.NewNullLogger(), db, st)
+	require.NoError(t, err)
+
+	generate(t, tree, types.EmptyRootHash)
+
+	root := commit(t, NewState(st, tree), types.EmptyRootHash, func(txn *state.Txn) {
+		txn.SetBalance(addr1, big.NewInt(1))
+	})
+
+	// the diff layers up to the head are flattened on close
+	tree.head = root
+	require.NoError(t, tree.Close())
+
+	db, err = kvdb.Open(kvdb.LevelDB, path, kvdb.Options{})
+	require.NoError(t, err)
+
+	tree, err = NewTree(hclog.NewNullLogger(), db, st)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = tree.db.Close()
+	})
+
+	assert.Equal(t, root, tree.disk.hash)
+	assert.False(t, tree.progress.Generating)
+	assertAccount(t, tree, root, addr1, nil)
+}
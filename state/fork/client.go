@@ -0,0 +1,145 @@
+package fork
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Config is the remote chain the state of a dev chain is forked from
+type Config struct {
+	// URL is the JSON-RPC endpoint of the remote chain
+	URL string
+	// Block is the number of the remote block the state is forked at, 0 for the latest block
+	Block uint64
+}
+
+// Account is an account of the remote chain
+type Account struct {
+	Nonce   uint64
+	Balance *big.Int
+	Code    []byte
+}
+
+// CodeHash returns the hash of the code of the account
+func (a *Account) CodeHash() types.Hash {
+	return types.BytesToHash(crypto.Keccak256(a.Code))
+}
+
+// Empty returns whether the account is empty, the remote chain doesn't tell it apart from a missing one
+func (a *Account) Empty() bool {
+	return a.Nonce == 0 && a.Balance.Sign() == 0 && len(a.Code) == 0
+}
+
+// Remote is the state of the remote chain at the pinned block
+type Remote interface {
+	// Account returns the account at the address, which is empty if it doesn't exist
+	Account(addr types.Address) (*Account, error)
+	// Storage returns the value of the slot of the account
+	Storage(addr types.Address, key types.Hash) (types.Hash, error)
+}
+
+// Client reads the remote chain through its JSON-RPC endpoint
+type Client struct {
+	client *jsonrpc.Client
+}
+
+// NewClient creates the client of the JSON-RPC endpoint of the remote chain
+func NewClient(url string) (*Client, error) {
+	client, err := jsonrpc.NewClient(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the fork url %s: %w", url, err)
+	}
+
+	return &Client{
+		client: client,
+	}, nil
+}
+
+// Block returns the remote block with the number, the latest block if it's 0
+func (c *Client) Block(number uint64) (*PinnedBlock, error) {
+	num := ethgo.Latest
+	if number != 0 {
+		num = ethgo.BlockNumber(number)
+	}
+
+	block, err := c.client.Eth().GetBlockByNumber(num, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the remote block %s: %w", num, err)
+	}
+
+	if block == nil {
+		return nil, fmt.Errorf("the remote block %s doesn't exist", num)
+	}
+
+	pinned := &PinnedBlock{
+		Number:    block.Number,
+		Hash:      types.Hash(block.Hash),
+		Miner:     types.Address(block.Miner),
+		GasLimit:  block.GasLimit,
+		Timestamp: block.Timestamp,
+	}
+
+	if block.Difficulty != nil {
+		pinned.Difficulty = block.Difficulty.Uint64()
+	}
+
+	return pinned, nil
+}
+
+// At returns the state of the remote chain at the block with the number
+func (c *Client) At(number uint64) Remote {
+	return &remoteAt{
+		eth:   c.client.Eth(),
+		block: ethgo.BlockNumber(number),
+	}
+}
+
+// remoteAt reads the state of the remote chain at a block
+type remoteAt struct {
+	eth   *jsonrpc.Eth
+	block ethgo.BlockNumber
+}
+
+func (r *remoteAt) Account(addr types.Address) (*Account, error) {
+	balance, err := r.eth.GetBalance(ethgo.Address(addr), r.block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the remote balance of %s: %w", addr, err)
+	}
+
+	nonce, err := r.eth.GetNonce(ethgo.Address(addr), r.block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the remote nonce of %s: %w", addr, err)
+	}
+
+	rawCode, err := r.eth.GetCode(ethgo.Address(addr), r.block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the remote code of %s: %w", addr, err)
+	}
+
+	code, err := hex.DecodeHex(rawCode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote code of %s: %w", addr, err)
+	}
+
+	return &Account{
+		Nonce:   nonce,
+		Balance: balance,
+		Code:    code,
+	}, nil
+}
+
+func (r *remoteAt) Storage(addr types.Address, key types.Hash) (types.Hash, error) {
+	value, err := r.eth.GetStorageAt(ethgo.Address(addr), ethgo.Hash(key), r.block)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("failed to get the remote slot %s of %s: %w", key, addr, err)
+	}
+
+	return types.Hash(value), nil
+}
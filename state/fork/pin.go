@@ -0,0 +1,84 @@
+package fork
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	// ErrPinMismatch is returned if the data directory forks another block than the requested one
+	ErrPinMismatch = errors.New("the data directory forks another remote block")
+
+	pinKey = []byte("pin")
+)
+
+// PinnedBlock is the remote block the state is forked at
+type PinnedBlock struct {
+	Number     uint64        `json:"number"`
+	Hash       types.Hash    `json:"hash"`
+	Miner      types.Address `json:"miner"`
+	Difficulty uint64        `json:"difficulty"`
+	GasLimit   uint64        `json:"gasLimit"`
+	Timestamp  uint64        `json:"timestamp"`
+}
+
+// Header returns the header of the block the local chain starts from, in place of the remote block.
+// It has the number, the time and the gas limit of the remote block, but the state root of the local state,
+// so its hash differs from the remote one
+func (b *PinnedBlock) Header(stateRoot types.Hash) *types.Header {
+	header := &types.Header{
+		ParentHash:   types.ZeroHash,
+		Sha3Uncles:   types.EmptyUncleHash,
+		Miner:        b.Miner.Bytes(),
+		StateRoot:    stateRoot,
+		TxRoot:       types.EmptyRootHash,
+		ReceiptsRoot: types.EmptyRootHash,
+		Difficulty:   b.Difficulty,
+		Number:       b.Number,
+		GasLimit:     b.GasLimit,
+		Timestamp:    b.Timestamp,
+	}
+
+	return header.ComputeHash()
+}
+
+// Pin returns the remote block the state is forked at. The block is pinned in the database on the first run,
+// so the later runs fork the same block. The number is the one of the block to pin, 0 for the latest block
+func Pin(client *Client, db kvdb.Database, number uint64) (*PinnedBlock, error) {
+	data, ok, err := db.Get(pinKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		pinned := &PinnedBlock{}
+		if err := json.Unmarshal(data, pinned); err != nil {
+			return nil, fmt.Errorf("invalid pinned block: %w", err)
+		}
+
+		if number != 0 && number != pinned.Number {
+			return nil, fmt.Errorf("%w: %d instead of %d", ErrPinMismatch, pinned.Number, number)
+		}
+
+		return pinned, nil
+	}
+
+	pinned, err := client.Block(number)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = json.Marshal(pinned); err != nil {
+		return nil, err
+	}
+
+	if err := db.Put(pinKey, data); err != nil {
+		return nil, err
+	}
+
+	return pinned, nil
+}
@@ -0,0 +1,381 @@
+package fork
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	// the prefixes of the remote accounts, slots and code cached in the database
+	accountPrefix = []byte("a")
+	storagePrefix = []byte("s")
+	codePrefix    = []byte("c")
+
+	// localStorageKey is the key of the slot marking the storage of an account as local only,
+	// once the remote account is deleted and created again. The keys of the slots are 32 bytes,
+	// so it doesn't clash with them
+	localStorageKey = []byte("local-storage")
+
+	emptyCodeHash = types.BytesToHash(crypto.Keccak256(nil))
+)
+
+// State is the local state layered over the state of a remote chain. The accounts and the slots
+// the local state doesn't hold are fetched from the remote chain once, and cached in the database.
+//
+// The accounts of the remote chain deleted locally are committed as empty accounts,
+// which read as missing, and the zeroed slots of the remote contracts are committed as zero values,
+// so they hide the remote ones. The storage of an account is only read from the remote chain
+// while the account has its remote code, and it wasn't deleted locally
+type State struct {
+	state.State
+
+	logger hclog.Logger
+	remote Remote
+	db     kvdb.Database
+}
+
+// NewState creates the state layered over the remote chain, caching it in the database
+func NewState(logger hclog.Logger, st state.State, remote Remote, db kvdb.Database) *State {
+	return &State{
+		State:  st,
+		logger: logger.Named("fork"),
+		remote: remote,
+		db:     db,
+	}
+}
+
+func (s *State) NewSnapshot() state.Snapshot {
+	return s.wrap(s.State.NewSnapshot())
+}
+
+func (s *State) NewSnapshotAt(root types.Hash) (state.Snapshot, error) {
+	snap, err := s.State.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wrap(snap), nil
+}
+
+// GetCode returns the code with the hash, from the local state or from the code of the remote accounts
+func (s *State) GetCode(hash types.Hash) ([]byte, bool) {
+	if code, ok := s.State.GetCode(hash); ok {
+		return code, true
+	}
+
+	code, ok, err := s.db.Get(dbKey(codePrefix, hash.Bytes()))
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	return code, true
+}
+
+// dbKey returns the key of the cached entry, made of the prefix followed by the parts
+func dbKey(prefix []byte, parts ...[]byte) []byte {
+	key := append([]byte{}, prefix...)
+
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+
+	return key
+}
+
+func (s *State) wrap(snap state.Snapshot) *snapshot {
+	return &snapshot{
+		state: s,
+		snap:  snap,
+	}
+}
+
+// cachedAccount is the remote account cached in the database, along with the hash of its code
+type cachedAccount struct {
+	Nonce    uint64     `json:"nonce"`
+	Balance  *big.Int   `json:"balance"`
+	CodeHash types.Hash `json:"codeHash"`
+}
+
+// remoteAccount returns the account of the remote chain at the address, which is cached once fetched.
+// The errors of the remote chain are logged, and the account reads as missing
+func (s *State) remoteAccount(addr types.Address) (*cachedAccount, bool) {
+	key := dbKey(accountPrefix, addr.Bytes())
+
+	if data, ok, err := s.db.Get(key); err == nil && ok {
+		account := &cachedAccount{}
+		if err := json.Unmarshal(data, account); err == nil {
+			return account, !account.empty()
+		}
+	}
+
+	remote, err := s.remote.Account(addr)
+	if err != nil {
+		s.logger.Error("failed to fetch the remote account", "address", addr, "err", err)
+
+		return nil, false
+	}
+
+	account := &cachedAccount{
+		Nonce:    remote.Nonce,
+		Balance:  remote.Balance,
+		CodeHash: remote.CodeHash(),
+	}
+
+	batch := s.db.NewBatch()
+
+	if len(remote.Code) != 0 {
+		batch.Put(dbKey(codePrefix, account.CodeHash.Bytes()), remote.Code)
+	}
+
+	if data, err := json.Marshal(account); err == nil {
+		batch.Put(key, data)
+	}
+
+	if err := batch.Write(); err != nil {
+		s.logger.Error("failed to cache the remote account", "address", addr, "err", err)
+	}
+
+	return account, !account.empty()
+}
+
+func (a *cachedAccount) empty() bool {
+	return a.Nonce == 0 && a.Balance.Sign() == 0 && a.CodeHash == emptyCodeHash
+}
+
+// remoteStorage returns the value of the slot of the remote account, which is cached once fetched
+func (s *State) remoteStorage(addr types.Address, slot types.Hash) (types.Hash, bool) {
+	key := dbKey(storagePrefix, addr.Bytes(), slot.Bytes())
+
+	if data, ok, err := s.db.Get(key); err == nil && ok {
+		return types.BytesToHash(data), true
+	}
+
+	value, err := s.remote.Storage(addr, slot)
+	if err != nil {
+		s.logger.Error("failed to fetch the remote slot", "address", addr, "slot", slot, "err", err)
+
+		return types.Hash{}, false
+	}
+
+	if err := s.db.Put(key, value.Bytes()); err != nil {
+		s.logger.Error("failed to cache the remote slot", "address", addr, "slot", slot, "err", err)
+	}
+
+	return value, true
+}
+
+// forked returns whether the storage of the account with the code hash is the one of the remote account
+func (s *State) forked(addr types.Address, codeHash types.Hash) bool {
+	if codeHash == emptyCodeHash {
+		return false
+	}
+
+	remote, ok := s.remoteAccount(addr)
+
+	return ok && remote.CodeHash == codeHash
+}
+
+// snapshot is the local state at a root layered over the remote chain
+type snapshot struct {
+	state *State
+	snap  state.Snapshot
+}
+
+func (s *snapshot) Get(k []byte) ([]byte, bool) {
+	return s.snap.Get(k)
+}
+
+// GetAccount returns the local account at the address, or the remote one if there is none locally
+func (s *snapshot) GetAccount(addr types.Address) (*state.Account, bool) {
+	addrHash := types.BytesToHash(crypto.Keccak256(addr.Bytes()))
+
+	if data, ok := s.snap.Get(addrHash.Bytes()); ok {
+		var account state.Account
+		if err := account.UnmarshalRlp(data); err != nil || deleted(&account) {
+			return nil, false
+		}
+
+		local := s.localStorage(addrHash, account.Root)
+		forked := s.state.forked(addr, types.BytesToHash(account.CodeHash))
+
+		if forked && local != nil {
+			_, marked := local.Get(crypto.Keccak256(localStorageKey))
+			forked = !marked
+		}
+
+		account.Trie = &storage{
+			state:  s.state,
+			addr:   addr,
+			local:  local,
+			forked: forked,
+		}
+
+		return &account, true
+	}
+
+	remote, ok := s.state.remoteAccount(addr)
+	if !ok {
+		return nil, false
+	}
+
+	return &state.Account{
+		Nonce:    remote.Nonce,
+		Balance:  new(big.Int).Set(remote.Balance),
+		Root:     types.EmptyRootHash,
+		CodeHash: remote.CodeHash.Bytes(),
+		Trie: &storage{
+			state:  s.state,
+			addr:   addr,
+			forked: remote.CodeHash != emptyCodeHash,
+		},
+	}, true
+}
+
+// deleted returns whether the local account is a remote account deleted locally, which is empty
+func deleted(account *state.Account) bool {
+	return account.Nonce == 0 && account.Balance.Sign() == 0 &&
+		types.BytesToHash(account.CodeHash) == emptyCodeHash
+}
+
+// deletedLocally returns whether the account at the address is a remote account deleted locally
+func (s *snapshot) deletedLocally(addr types.Address) bool {
+	data, ok := s.snap.Get(crypto.Keccak256(addr.Bytes()))
+	if !ok {
+		return false
+	}
+
+	var account state.Account
+	if err := account.UnmarshalRlp(data); err != nil {
+		return false
+	}
+
+	return deleted(&account)
+}
+
+// localStorage returns the local storage of the account, nil if it has none
+func (s *snapshot) localStorage(addrHash types.Hash, root types.Hash) state.StorageReader {
+	if flat, ok := s.snap.(state.FlatSnapshot); ok {
+		return flat.Storage(addrHash, root)
+	}
+
+	if root == types.EmptyRootHash {
+		return nil
+	}
+
+	trie, err := s.state.State.NewSnapshotAt(root)
+	if err != nil {
+		return nil
+	}
+
+	return trie
+}
+
+// Commit commits the objects to the local state, keeping the deleted remote accounts and slots
+// from reading through to the remote chain
+func (s *snapshot) Commit(objs []*state.Object) (state.Snapshot, []byte) {
+	committed := make([]*state.Object, 0, len(objs))
+
+	for _, obj := range objs {
+		if obj.Deleted {
+			if _, ok := s.state.remoteAccount(obj.Address); ok {
+				obj = &state.Object{
+					Address:  obj.Address,
+					Balance:  big.NewInt(0),
+					Root:     types.EmptyRootHash,
+					CodeHash: emptyCodeHash,
+				}
+			}
+		} else if s.state.forked(obj.Address, obj.CodeHash) {
+			if s.deletedLocally(obj.Address) {
+				// the account created again doesn't read the storage of the deleted one
+				obj = markLocalStorage(obj)
+			} else if len(obj.Storage) != 0 {
+				obj = zeroDeletedSlots(obj)
+			}
+		}
+
+		committed = append(committed, obj)
+	}
+
+	snap, root := s.snap.Commit(committed)
+
+	return s.state.wrap(snap), root
+}
+
+// zeroDeletedSlots returns a copy of the object whose deleted slots are zero values instead
+func zeroDeletedSlots(obj *state.Object) *state.Object {
+	copied := *obj
+	copied.Storage = make([]*state.StorageObject, len(obj.Storage))
+
+	for i, entry := range obj.Storage {
+		if entry.Deleted {
+			entry = &state.StorageObject{
+				Key: entry.Key,
+				Val: types.ZeroHash.Bytes(),
+			}
+		}
+
+		copied.Storage[i] = entry
+	}
+
+	return &copied
+}
+
+// markLocalStorage returns a copy of the object whose storage is marked as local only
+func markLocalStorage(obj *state.Object) *state.Object {
+	copied := *obj
+	copied.Storage = append([]*state.StorageObject{{
+		Key: localStorageKey,
+		Val: []byte{0x1},
+	}}, obj.Storage...)
+
+	return &copied
+}
+
+var slotArenaPool fastrlp.ArenaPool
+
+// storage is the storage of an account, whose slots missing locally are read from the remote account
+// if the storage is forked
+type storage struct {
+	state  *State
+	addr   types.Address
+	local  state.StorageReader
+	forked bool
+}
+
+func (s *storage) Get(k []byte) ([]byte, bool) {
+	if s.local == nil {
+		return nil, false
+	}
+
+	return s.local.Get(k)
+}
+
+func (s *storage) GetSlot(key types.Hash) ([]byte, bool) {
+	if val, ok := s.Get(crypto.Keccak256(key.Bytes())); ok {
+		return val, true
+	}
+
+	if !s.forked {
+		return nil, false
+	}
+
+	value, ok := s.state.remoteStorage(s.addr, key)
+	if !ok || value == types.ZeroHash {
+		return nil, false
+	}
+
+	arena := slotArenaPool.Get()
+	defer slotArenaPool.Put(arena)
+
+	return arena.NewBytes(bytes.TrimLeft(value.Bytes(), "\x00")).MarshalTo(nil), true
+}
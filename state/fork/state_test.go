@@ -0,0 +1,195 @@
+package fork
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	addr1 = types.StringToAddress("1")
+	addr2 = types.StringToAddress("2")
+	addr3 = types.StringToAddress("3")
+
+	slot1 = types.StringToHash("1")
+	slot2 = types.StringToHash("2")
+
+	code = []byte{0x60, 0x00}
+)
+
+// mockRemote is a remote chain holding the accounts and the slots, counting the fetches
+type mockRemote struct {
+	accounts map[types.Address]*Account
+	storage  map[types.Address]map[types.Hash]types.Hash
+	fetches  int
+}
+
+func (m *mockRemote) Account(addr types.Address) (*Account, error) {
+	m.fetches++
+
+	if account, ok := m.accounts[addr]; ok {
+		return account, nil
+	}
+
+	return &Account{Balance: big.NewInt(0)}, nil
+}
+
+func (m *mockRemote) Storage(addr types.Address, key types.Hash) (types.Hash, error) {
+	m.fetches++
+
+	return m.storage[addr][key], nil
+}
+
+func newTestState(t *testing.T) (*State, *mockRemote) {
+	t.Helper()
+
+	remote := &mockRemote{
+		accounts: map[types.Address]*Account{
+			addr1: {Nonce: 1, Balance: big.NewInt(100)},
+			addr2: {Nonce: 1, Balance: big.NewInt(0), Code: code},
+		},
+		storage: map[types.Address]map[types.Hash]types.Hash{
+			addr2: {
+				slot1: types.StringToHash("11"),
+				slot2: types.StringToHash("22"),
+			},
+		},
+	}
+
+	db := kvdb.NewMemoryDatabase()
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewState(hclog.NewNullLogger(), itrie.NewState(itrie.NewMemoryStorage()), remote, db), remote
+}
+
+// commit applies the changes to the state at the root, and returns the new root
+func commit(t *testing.T, st state.State, root types.Hash, change func(txn *state.Txn)) types.Hash {
+	t.Helper()
+
+	snap, err := st.NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	txn := state.NewTxn(st, snap)
+	change(txn)
+
+	_, newRoot := snap.Commit(txn.Commit(true))
+
+	return types.BytesToHash(newRoot)
+}
+
+// read returns a transaction reading the state at the root
+func read(t *testing.T, st state.State, root types.Hash) *state.Txn {
+	t.Helper()
+
+	snap, err := st.NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	return state.NewTxn(st, snap)
+}
+
+func TestState_ReadRemote(t *testing.T) {
+	t.Parallel()
+
+	st, remote := newTestState(t)
+
+	// the local accounts hide the remote ones
+	root := commit(t, st, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.SetBalance(addr3, big.NewInt(5))
+	})
+
+	txn := read(t, st, root)
+
+	assert.Equal(t, big.NewInt(100), txn.GetBalance(addr1))
+	assert.Equal(t, uint64(1), txn.GetNonce(addr1))
+	assert.Equal(t, code, txn.GetCode(addr2))
+	assert.Equal(t, types.StringToHash("11"), txn.GetState(addr2, slot1))
+	assert.Equal(t, big.NewInt(5), txn.GetBalance(addr3))
+	assert.False(t, txn.Exist(types.StringToAddress("4")))
+
+	// the remote chain is read once
+	fetches := remote.fetches
+	txn = read(t, st, root)
+
+	assert.Equal(t, big.NewInt(100), txn.GetBalance(addr1))
+	assert.Equal(t, types.StringToHash("11"), txn.GetState(addr2, slot1))
+	assert.Equal(t, fetches, remote.fetches)
+
+	// the accounts are read by the other readers of the snapshots as well
+	snap, err := st.NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	account, ok, err := state.ReadAccount(snap, addr1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, big.NewInt(100), account.Balance)
+}
+
+func TestState_LocalChanges(t *testing.T) {
+	t.Parallel()
+
+	st, _ := newTestState(t)
+
+	root := commit(t, st, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.AddBalance(addr1, big.NewInt(1))
+		txn.SetState(addr2, slot1, types.StringToHash("33"))
+	})
+
+	txn := read(t, st, root)
+
+	assert.Equal(t, big.NewInt(101), txn.GetBalance(addr1))
+	assert.Equal(t, uint64(1), txn.GetNonce(addr1))
+	assert.Equal(t, types.StringToHash("33"), txn.GetState(addr2, slot1))
+	assert.Equal(t, types.StringToHash("22"), txn.GetState(addr2, slot2))
+	assert.Equal(t, code, txn.GetCode(addr2))
+
+	// the zeroed slots hide the remote ones
+	root = commit(t, st, root, func(txn *state.Txn) {
+		txn.SetState(addr2, slot2, types.ZeroHash)
+	})
+
+	txn = read(t, st, root)
+
+	assert.Equal(t, types.StringToHash("33"), txn.GetState(addr2, slot1))
+	assert.Equal(t, types.ZeroHash, txn.GetState(addr2, slot2))
+}
+
+func TestState_DeleteRemote(t *testing.T) {
+	t.Parallel()
+
+	st, _ := newTestState(t)
+
+	root := commit(t, st, types.EmptyRootHash, func(txn *state.Txn) {
+		txn.Suicide(addr2)
+		require.NoError(t, txn.SubBalance(addr1, big.NewInt(100)))
+		txn.SetNonce(addr1, 0)
+	})
+
+	txn := read(t, st, root)
+
+	// the deleted accounts don't read through to the remote chain
+	assert.False(t, txn.Exist(addr1))
+	assert.False(t, txn.Exist(addr2))
+	assert.Equal(t, types.ZeroHash, txn.GetState(addr2, slot1))
+
+	// a deleted account created again starts from the empty storage
+	root = commit(t, st, root, func(txn *state.Txn) {
+		txn.SetCode(addr2, code)
+		txn.SetNonce(addr2, 1)
+	})
+
+	txn = read(t, st, root)
+
+	assert.True(t, txn.Exist(addr2))
+	assert.Equal(t, types.ZeroHash, txn.GetState(addr2, slot1))
+}
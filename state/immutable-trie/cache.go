@@ -0,0 +1,220 @@
+package itrie
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/armon/go-metrics"
+
+	"github.com/0xPolygon/polygon-edge/helper/membudget"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// cacheFullMark is the percentage of its max size from which a cache is considered full
+	cacheFullMark = 90
+)
+
+// sizedCache is a LRU cache bounded by the size of its keys and values
+type sizedCache struct {
+	name string
+
+	lock    sync.Mutex
+	maxSize int
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+
+	// hits and misses are counted since the last pressure reading
+	hits   uint64
+	misses uint64
+}
+
+type sizedCacheEntry struct {
+	key   string
+	value []byte
+}
+
+func newSizedCache(name string, maxSize int) *sizedCache {
+	return &sizedCache{
+		name:    name,
+		maxSize: maxSize,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (c *sizedCache) get(key []byte) ([]byte, bool) {
+	c.lock.Lock()
+	elem, ok := c.entries[string(key)]
+
+	if ok {
+		c.order.MoveToFront(elem)
+	}
+	c.lock.Unlock()
+
+	labels := []metrics.Label{{Name: "cache", Value: c.name}}
+
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		metrics.IncrCounterWithLabels([]string{"state_cache_misses"}, 1, labels)
+
+		return nil, false
+	}
+
+	atomic.AddUint64(&c.hits, 1)
+	metrics.IncrCounterWithLabels([]string{"state_cache_hits"}, 1, labels)
+
+	entry, _ := elem.Value.(*sizedCacheEntry)
+
+	return entry.value, true
+}
+
+func (c *sizedCache) add(key, value []byte) {
+	size := len(key) + len(value)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if size > c.maxSize {
+		return
+	}
+
+	if elem, ok := c.entries[string(key)]; ok {
+		c.removeElement(elem)
+	}
+
+	c.entries[string(key)] = c.order.PushFront(&sizedCacheEntry{key: string(key), value: value})
+	c.size += size
+
+	c.evict()
+}
+
+// evict removes the least recently used entries until the cache fits its max size
+func (c *sizedCache) evict() {
+	for c.size > c.maxSize {
+		c.removeElement(c.order.Back())
+	}
+
+	metrics.SetGaugeWithLabels(
+		[]string{"state_cache_size"},
+		float32(c.size),
+		[]metrics.Label{{Name: "cache", Value: c.name}},
+	)
+}
+
+// Usage returns the size of the cached keys and values
+func (c *sizedCache) Usage() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.size
+}
+
+// Pressure returns the miss ratio since the last call once the cache is full,
+// as a cache which still has room wouldn't hit more with a larger size
+func (c *sizedCache) Pressure() float64 {
+	hits, misses := atomic.SwapUint64(&c.hits, 0), atomic.SwapUint64(&c.misses, 0)
+
+	c.lock.Lock()
+	full := c.maxSize > 0 && c.size >= c.maxSize*cacheFullMark/100
+	c.lock.Unlock()
+
+	if !full || hits+misses == 0 {
+		return 0
+	}
+
+	return float64(misses) / float64(hits+misses)
+}
+
+// Resize changes the max size of the cache, evicting the entries past it
+func (c *sizedCache) Resize(maxSize int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.maxSize = maxSize
+	c.evict()
+}
+
+func (c *sizedCache) remove(keys ...[]byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[string(key)]; ok {
+			c.removeElement(elem)
+		}
+	}
+}
+
+func (c *sizedCache) removeElement(elem *list.Element) {
+	entry, _ := c.order.Remove(elem).(*sizedCacheEntry)
+
+	delete(c.entries, entry.key)
+	c.size -= len(entry.key) + len(entry.value)
+}
+
+// CachedStorage is a storage whose trie nodes and contract code are cached in memory, up to the given sizes.
+// The trie nodes are keyed by their hash, so the cached ones can't get stale unless they are removed
+type CachedStorage struct {
+	Storage
+
+	nodes *sizedCache
+	code  *sizedCache
+}
+
+// NewCachedStorage caches the reads of the storage, the caches are disabled with a size of 0
+func NewCachedStorage(storage Storage, nodesSize, codeSize int) *CachedStorage {
+	return &CachedStorage{
+		Storage: storage,
+		nodes:   newSizedCache("trie", nodesSize),
+		code:    newSizedCache("code", codeSize),
+	}
+}
+
+func (s *CachedStorage) Get(k []byte) ([]byte, bool) {
+	if v, ok := s.nodes.get(k); ok {
+		return v, true
+	}
+
+	v, ok := s.Storage.Get(k)
+	if ok {
+		s.nodes.add(k, v)
+	}
+
+	return v, ok
+}
+
+func (s *CachedStorage) Put(k, v []byte) {
+	s.nodes.remove(k)
+	s.Storage.Put(k, v)
+}
+
+func (s *CachedStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	if code, ok := s.code.get(hash.Bytes()); ok {
+		return code, true
+	}
+
+	code, ok := s.Storage.GetCode(hash)
+	if ok {
+		s.code.add(hash.Bytes(), code)
+	}
+
+	return code, ok
+}
+
+// NodesCache returns the cache of the trie nodes, whose size is managed by a memory budget
+func (s *CachedStorage) NodesCache() membudget.Consumer {
+	return s.nodes
+}
+
+// CodeCache returns the cache of the contract code, whose size is managed by a memory budget
+func (s *CachedStorage) CodeCache() membudget.Consumer {
+	return s.code
+}
+
+// Evict removes the nodes from the cache, once they are removed from the storage
+func (s *CachedStorage) Evict(keys ...[]byte) {
+	s.nodes.remove(keys...)
+}
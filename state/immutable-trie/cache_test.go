@@ -0,0 +1,120 @@
+package itrie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestSizedCache_Eviction(t *testing.T) {
+	t.Parallel()
+
+	// room for two entries of 4 bytes
+	c := newSizedCache("test", 8)
+
+	c.add([]byte{1}, []byte{1, 1, 1})
+	c.add([]byte{2}, []byte{2, 2, 2})
+
+	// the first entry is the most recently used one
+	_, ok := c.get([]byte{1})
+	assert.True(t, ok)
+
+	c.add([]byte{3}, []byte{3, 3, 3})
+
+	_, ok = c.get([]byte{2})
+	assert.False(t, ok)
+
+	v, ok := c.get([]byte{1})
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1, 1, 1}, v)
+
+	assert.Equal(t, 8, c.size)
+
+	// the entries larger than the cache aren't cached
+	c.add([]byte{4}, make([]byte, 8))
+
+	_, ok = c.get([]byte{4})
+	assert.False(t, ok)
+	assert.Len(t, c.entries, 2)
+
+	c.remove([]byte{1}, []byte{3})
+	assert.Equal(t, 0, c.size)
+	assert.Equal(t, 0, c.order.Len())
+}
+
+func TestSizedCache_Resize(t *testing.T) {
+	t.Parallel()
+
+	c := newSizedCache("test", 8)
+
+	c.add([]byte{1}, []byte{1, 1, 1})
+
+	// a cache with room left isn't under pressure
+	_, _ = c.get([]byte{2})
+	assert.Equal(t, float64(0), c.Pressure())
+
+	c.add([]byte{2}, []byte{2, 2, 2})
+
+	_, _ = c.get([]byte{1})
+	_, _ = c.get([]byte{3})
+	assert.Equal(t, 0.5, c.Pressure())
+
+	// the least recently used entries are evicted when the cache shrinks
+	c.Resize(4)
+
+	assert.Equal(t, 4, c.Usage())
+
+	_, ok := c.get([]byte{1})
+	assert.True(t, ok)
+
+	_, ok = c.get([]byte{2})
+	assert.False(t, ok)
+}
+
+func TestCachedStorage(t *testing.T) {
+	t.Parallel()
+
+	storage := NewMemoryStorage()
+	cached := NewCachedStorage(storage, 1024, 1024)
+
+	key := types.StringToHash("1").Bytes()
+	storage.Put(key, []byte{1})
+
+	v, ok := cached.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1}, v)
+
+	// the cached node is served once changed in the storage, until it is evicted
+	storage.Put(key, []byte{2})
+
+	v, _ = cached.Get(key)
+	assert.Equal(t, []byte{1}, v)
+
+	cached.Evict(key)
+
+	v, _ = cached.Get(key)
+	assert.Equal(t, []byte{2}, v)
+
+	// the writes through the cached storage evict the node
+	cached.Put(key, []byte{3})
+
+	v, _ = cached.Get(key)
+	assert.Equal(t, []byte{3}, v)
+
+	codeHash := types.StringToHash("2")
+	cached.SetCode(codeHash, []byte{4})
+
+	code, ok := cached.GetCode(codeHash)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{4}, code)
+	assert.Len(t, cached.code.entries, 1)
+
+	// the caches are disabled with a size of 0
+	disabled := NewCachedStorage(storage, 0, 0)
+
+	_, ok = disabled.Get(key)
+	assert.True(t, ok)
+	assert.Len(t, disabled.nodes.entries, 0)
+}
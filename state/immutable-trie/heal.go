@@ -0,0 +1,245 @@
+package itrie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// healBatchSize is the maximum number of the trie nodes or codes fetched at once while healing
+const healBatchSize = 128
+
+// HealFetcher fetches the trie nodes, or the contract codes, with the given hashes.
+// The values are returned in the order of the hashes
+type HealFetcher func(hashes []types.Hash, code bool) ([][]byte, error)
+
+// healTask is a trie node or a code missing from the storage
+type healTask struct {
+	hash        types.Hash
+	code        bool
+	accountTrie bool
+
+	value   []byte
+	missing int // number of the children not stored yet
+	parent  *healTask
+}
+
+// TrieNode returns the stored trie node with the given hash
+func (s *State) TrieNode(hash types.Hash) ([]byte, bool) {
+	return s.storage.Get(hash.Bytes())
+}
+
+// Heal fetches the trie nodes and the codes of the state with the given root which are missing from the storage.
+// A node is only stored once all of its descendants are, so a stored node is always the root of a complete subtree
+// and isn't descended into. It makes the healing resumable, and keeps the state consistent if it's interrupted
+func (s *State) Heal(root types.Hash, fetch HealFetcher) error {
+	return s.heal(root, true, fetch)
+}
+
+// HealStorage is like Heal, for the storage trie of an account
+func (s *State) HealStorage(root types.Hash, fetch HealFetcher) error {
+	return s.heal(root, false, fetch)
+}
+
+func (s *State) heal(root types.Hash, accountTrie bool, fetch HealFetcher) error {
+	if root == types.EmptyRootHash {
+		return nil
+	}
+
+	if _, ok := s.storage.Get(root.Bytes()); ok {
+		return nil
+	}
+
+	queue := []*healTask{{hash: root, accountTrie: accountTrie}}
+
+	for len(queue) > 0 {
+		var (
+			nodes = make([]*healTask, 0, healBatchSize)
+			codes = make([]*healTask, 0, healBatchSize)
+			rest  = make([]*healTask, 0, len(queue))
+		)
+
+		for _, task := range queue {
+			switch {
+			case task.code && len(codes) < healBatchSize:
+				codes = append(codes, task)
+			case !task.code && len(nodes) < healBatchSize:
+				nodes = append(nodes, task)
+			default:
+				rest = append(rest, task)
+			}
+		}
+
+		queue = rest
+
+		batch := s.storage.Batch()
+
+		for _, tasks := range [][]*healTask{codes, nodes} {
+			if len(tasks) == 0 {
+				continue
+			}
+
+			if err := fetchHealTasks(tasks, fetch); err != nil {
+				return err
+			}
+
+			for _, task := range tasks {
+				children, err := s.healChildren(task)
+				if err != nil {
+					return err
+				}
+
+				queue = append(queue, children...)
+
+				if task.missing == 0 {
+					s.completeHealTask(task, batch)
+				}
+			}
+		}
+
+		batch.Write()
+	}
+
+	return nil
+}
+
+// fetchHealTasks fetches the values of the tasks, and checks them against their hashes
+func fetchHealTasks(tasks []*healTask, fetch HealFetcher) error {
+	hashes := make([]types.Hash, len(tasks))
+	for i, task := range tasks {
+		hashes[i] = task.hash
+	}
+
+	values, err := fetch(hashes, tasks[0].code)
+	if err != nil {
+		return err
+	}
+
+	if len(values) != len(tasks) {
+		return fmt.Errorf("expected %d values, got %d", len(tasks), len(values))
+	}
+
+	for i, task := range tasks {
+		if !bytes.Equal(crypto.Keccak256(values[i]), task.hash.Bytes()) {
+			return fmt.Errorf("value does not match its hash %s", task.hash)
+		}
+
+		task.value = values[i]
+	}
+
+	return nil
+}
+
+// healChildren returns the tasks of the children of the fetched node which are missing from the storage
+func (s *State) healChildren(task *healTask) ([]*healTask, error) {
+	if task.code {
+		return nil, nil
+	}
+
+	p := parserPool.Get()
+	defer parserPool.Put(p)
+
+	v, err := p.Parse(task.value)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := decodeNode(v, s.storage)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]*healTask, 0)
+
+	add := func(child *healTask) {
+		if child.code {
+			if _, ok := s.storage.GetCode(child.hash); ok {
+				return
+			}
+		} else if _, ok := s.storage.Get(child.hash.Bytes()); ok {
+			return
+		}
+
+		child.parent = task
+		task.missing++
+
+		children = append(children, child)
+	}
+
+	var visit func(node Node) error
+
+	visit = func(node Node) error {
+		switch n := node.(type) {
+		case nil:
+			return nil
+
+		case *ValueNode:
+			if n.hash {
+				add(&healTask{hash: types.BytesToHash(n.buf), accountTrie: task.accountTrie})
+
+				return nil
+			}
+
+			if !task.accountTrie {
+				return nil
+			}
+
+			var account state.Account
+			if err := account.UnmarshalRlp(n.buf); err != nil {
+				return err
+			}
+
+			if account.Root != types.EmptyRootHash && account.Root != types.ZeroHash {
+				add(&healTask{hash: account.Root})
+			}
+
+			if len(account.CodeHash) != 0 && !bytes.Equal(account.CodeHash, emptyCodeHash) {
+				add(&healTask{hash: types.BytesToHash(account.CodeHash), code: true})
+			}
+
+			return nil
+
+		case *ShortNode:
+			return visit(n.child)
+
+		case *FullNode:
+			for _, child := range n.children {
+				if err := visit(child); err != nil {
+					return err
+				}
+			}
+
+			return visit(n.value)
+
+		default:
+			return fmt.Errorf("unknown node type %T", node)
+		}
+	}
+
+	if err := visit(node); err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// completeHealTask stores the value of the task whose descendants are all stored,
+// and then the ones of its ancestors which have no other missing descendant
+func (s *State) completeHealTask(task *healTask, batch Batch) {
+	for task != nil {
+		if task.code {
+			s.storage.SetCode(task.hash, task.value)
+		} else {
+			batch.Put(task.hash.Bytes(), task.value)
+		}
+
+		if task = task.parent; task != nil {
+			if task.missing--; task.missing > 0 {
+				return
+			}
+		}
+	}
+}
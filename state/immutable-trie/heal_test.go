@@ -0,0 +1,149 @@
+package itrie
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healFetcher returns a fetcher reading the values from the source state, and the number of the fetched values
+func healFetcher(src *State, fail func(calls int) bool) (HealFetcher, *int) {
+	var (
+		fetched = 0
+		calls   = 0
+	)
+
+	return func(hashes []types.Hash, code bool) ([][]byte, error) {
+		if calls++; fail != nil && fail(calls) {
+			return nil, errors.New("fetch failed")
+		}
+
+		values := make([][]byte, len(hashes))
+
+		for i, hash := range hashes {
+			var ok bool
+
+			if code {
+				values[i], ok = src.GetCode(hash)
+			} else {
+				values[i], ok = src.TrieNode(hash)
+			}
+
+			if !ok {
+				return nil, fmt.Errorf("%s not found", hash)
+			}
+		}
+
+		fetched += len(hashes)
+
+		return values, nil
+	}, &fetched
+}
+
+func TestState_Heal(t *testing.T) {
+	t.Parallel()
+
+	src := NewState(NewMemoryStorage())
+	txn := state.NewTxn(src, src.NewSnapshot())
+
+	for i := 0; i < 300; i++ {
+		addr := types.BytesToAddress([]byte{byte(i), byte(i >> 8), 0x1})
+		txn.SetBalance(addr, big.NewInt(int64(i+1)))
+
+		if i%30 == 0 {
+			txn.SetCode(addr, []byte{0x60, byte(i)})
+
+			for j := 0; j < 20; j++ {
+				txn.SetState(addr, types.BytesToHash([]byte{byte(j)}), types.BytesToHash([]byte{byte(i + j + 1)}))
+			}
+		}
+	}
+
+	_, rootBytes := src.NewSnapshot().Commit(txn.Commit(false))
+	root := types.BytesToHash(rootBytes)
+
+	total := 0
+	require.NoError(t, src.Walk(root, func(*SnapshotEntry) error {
+		total++
+
+		return nil
+	}))
+
+	dst := NewState(NewMemoryStorage())
+
+	// an interrupted healing leaves no node whose subtree is incomplete
+	fetch, _ := healFetcher(src, func(calls int) bool { return calls == 4 })
+	require.Error(t, dst.Heal(root, fetch))
+
+	_, ok := dst.TrieNode(root)
+	assert.False(t, ok)
+
+	fetch, fetched := healFetcher(src, nil)
+	require.NoError(t, dst.Heal(root, fetch))
+
+	assert.Less(t, *fetched, total)
+	assert.NoError(t, dst.Walk(root, func(*SnapshotEntry) error { return nil }))
+
+	// a complete state is not fetched again
+	fetch, fetched = healFetcher(src, nil)
+	require.NoError(t, dst.Heal(root, fetch))
+	assert.Equal(t, 0, *fetched)
+}
+
+func TestState_HealStorage_AfterRanges(t *testing.T) {
+	t.Parallel()
+
+	src, root := newRangeTestTrie(t, 500)
+
+	dst := NewState(NewMemoryStorage())
+	origin := make([]byte, rangeKeyLength)
+
+	for {
+		keys, values, proof, err := src.ProveRange(root, origin, 4096)
+		require.NoError(t, err)
+
+		entries, more, err := VerifyRangeProof(root, origin, keys, values, proof)
+		require.NoError(t, err)
+
+		dst.WriteSnapshotEntries(entries)
+
+		if !more {
+			break
+		}
+
+		origin = nextRangeKey(keys[len(keys)-1])
+	}
+
+	// only the nodes on the edges of the ranges are fetched
+	fetch, fetched := healFetcher(src, nil)
+	require.NoError(t, dst.HealStorage(root, fetch))
+	assert.Greater(t, *fetched, 0)
+	assert.Less(t, *fetched, 100)
+
+	count := 0
+	require.NoError(t, dst.Iterate(root, func(_, _ []byte) error {
+		count++
+
+		return nil
+	}))
+	assert.Equal(t, 500, count)
+}
+
+func TestState_Heal_HashMismatch(t *testing.T) {
+	t.Parallel()
+
+	_, root := newRangeTestTrie(t, 10)
+
+	dst := NewState(NewMemoryStorage())
+
+	err := dst.HealStorage(root, func(hashes []types.Hash, _ bool) ([][]byte, error) {
+		return [][]byte{{0x1}}, nil
+	})
+	assert.ErrorContains(t, err, "does not match its hash")
+}
@@ -0,0 +1,47 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Tables of the trie database, reported by the inspection of the database
+const (
+	TableNodes    = "trie-nodes"
+	TableCode     = "code"
+	TableMetadata = "metadata"
+)
+
+var errHashMismatch = errors.New("the key isn't the hash of the value")
+
+// KeyTable returns the table of the key of the trie database, the bookkeeping of the pruner is metadata
+func KeyTable(key []byte) string {
+	switch {
+	case len(key) == types.HashLength:
+		return TableNodes
+	case len(key) == len(codePrefix)+types.HashLength && bytes.HasPrefix(key, codePrefix):
+		return TableCode
+	default:
+		return TableMetadata
+	}
+}
+
+// VerifyEntry checks the trie node or the contract code is keyed by its hash
+func VerifyEntry(key, value []byte) error {
+	switch KeyTable(key) {
+	case TableNodes:
+	case TableCode:
+		key = key[len(codePrefix):]
+	default:
+		return nil
+	}
+
+	if !bytes.Equal(key, crypto.Keccak256(value)) {
+		return errHashMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package itrie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+)
+
+func TestVerifyEntry(t *testing.T) {
+	t.Parallel()
+
+	node := []byte{0xc2, 0x01, 0x02}
+	nodeKey := crypto.Keccak256(node)
+
+	assert.Equal(t, TableNodes, KeyTable(nodeKey))
+	assert.NoError(t, VerifyEntry(nodeKey, node))
+	assert.ErrorIs(t, VerifyEntry(nodeKey, []byte{0xc0}), errHashMismatch)
+
+	code := []byte{0x60, 0x00}
+	codeKey := append(append([]byte{}, codePrefix...), crypto.Keccak256(code)...)
+
+	assert.Equal(t, TableCode, KeyTable(codeKey))
+	assert.NoError(t, VerifyEntry(codeKey, code))
+	assert.ErrorIs(t, VerifyEntry(codeKey, node), errHashMismatch)
+
+	assert.Equal(t, TableMetadata, KeyTable(prunerKey))
+	assert.NoError(t, VerifyEntry(prunerKey, []byte{1}))
+}
@@ -0,0 +1,127 @@
+package itrie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Iterate calls the handler for every key and value of the trie with the given root, in the order of the keys.
+// The keys are the hashed ones the values are stored with. The iteration stops at the first error of the handler
+func (s *State) Iterate(root types.Hash, handler func(key, value []byte) error) error {
+	return s.IterateFrom(root, nil, handler)
+}
+
+// IterateFrom is like Iterate, but only for the keys which are not less than the start key
+func (s *State) IterateFrom(root types.Hash, start []byte, handler func(key, value []byte) error) error {
+	if root == types.EmptyRootHash {
+		return nil
+	}
+
+	node, ok, err := GetNode(root.Bytes(), s.storage)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("trie node %s not found", root)
+	}
+
+	it := &trieIterator{
+		state:   s,
+		start:   start,
+		handler: handler,
+	}
+
+	if start != nil {
+		startNibbles := bytesToHexNibbles(start)
+		it.startNibbles = startNibbles[:len(startNibbles)-1]
+	}
+
+	return it.iterateNode(node, nil)
+}
+
+// trieIterator visits the values of a trie from the start key
+type trieIterator struct {
+	state        *State
+	start        []byte
+	startNibbles []byte
+	handler      func(key, value []byte) error
+}
+
+// skip checks whether the keys under the path precede the start key
+func (it *trieIterator) skip(path []byte) bool {
+	n := len(path)
+	if n > len(it.startNibbles) {
+		n = len(it.startNibbles)
+	}
+
+	return bytes.Compare(path[:n], it.startNibbles[:n]) < 0
+}
+
+// iterateNode visits the values under the node, the path holds the nibbles of the key up to the node
+func (it *trieIterator) iterateNode(node Node, path []byte) error {
+	if it.skip(path) {
+		return nil
+	}
+
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case *ValueNode:
+		if n.hash {
+			child, ok, err := GetNode(n.buf, it.state.storage)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return fmt.Errorf("trie node %s not found", types.BytesToHash(n.buf))
+			}
+
+			return it.iterateNode(child, path)
+		}
+
+		key := hexNibblesToBytes(path)
+		if bytes.Compare(key, it.start) < 0 {
+			return nil
+		}
+
+		return it.handler(key, n.buf)
+
+	case *ShortNode:
+		return it.iterateNode(n.child, append(path[:len(path):len(path)], n.key...))
+
+	case *FullNode:
+		if err := it.iterateNode(n.value, path); err != nil {
+			return err
+		}
+
+		for i, child := range n.children {
+			if err := it.iterateNode(child, append(path[:len(path):len(path)], byte(i))); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unknown node type %T", node)
+	}
+}
+
+// hexNibblesToBytes packs the nibbles into bytes, the terminator flag is removed
+func hexNibblesToBytes(nibbles []byte) []byte {
+	if hasTerminator(nibbles) {
+		nibbles = nibbles[:len(nibbles)-1]
+	}
+
+	key := make([]byte, len(nibbles)/2)
+	for i := range key {
+		key[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+
+	return key
+}
@@ -0,0 +1,113 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errStopIteration = errors.New("stop")
+
+func TestState_Iterate(t *testing.T) {
+	t.Parallel()
+
+	st := NewState(NewMemoryStorage())
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 50; i++ {
+		txn.SetBalance(types.BytesToAddress([]byte{byte(i + 1)}), big.NewInt(int64(i+1)))
+	}
+
+	_, rootBytes := st.NewSnapshot().Commit(txn.Commit(false))
+	root := types.BytesToHash(rootBytes)
+
+	snap, err := st.NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	keys := make([][]byte, 0)
+
+	require.NoError(t, st.Iterate(root, func(key, value []byte) error {
+		expected, ok := snap.Get(key)
+		require.True(t, ok)
+		assert.Equal(t, expected, value)
+
+		keys = append(keys, key)
+
+		return nil
+	}))
+
+	require.Len(t, keys, 50)
+
+	for i := 1; i < len(keys); i++ {
+		assert.Equal(t, -1, bytes.Compare(keys[i-1], keys[i]))
+	}
+
+	// the keys are the hashed addresses
+	_, ok := snap.Get(crypto.Keccak256(types.BytesToAddress([]byte{1}).Bytes()))
+	assert.True(t, ok)
+
+	// the iteration stops at the first error
+	count := 0
+
+	assert.ErrorIs(t, st.Iterate(root, func(key, value []byte) error {
+		count++
+
+		return errStopIteration
+	}), errStopIteration)
+	assert.Equal(t, 1, count)
+
+	assert.NoError(t, st.Iterate(types.EmptyRootHash, nil))
+}
+
+func TestState_IterateFrom(t *testing.T) {
+	t.Parallel()
+
+	st := NewState(NewMemoryStorage())
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 50; i++ {
+		txn.SetBalance(types.BytesToAddress([]byte{byte(i + 1)}), big.NewInt(int64(i+1)))
+	}
+
+	_, rootBytes := st.NewSnapshot().Commit(txn.Commit(false))
+	root := types.BytesToHash(rootBytes)
+
+	iterate := func(start []byte) [][]byte {
+		keys := make([][]byte, 0)
+
+		require.NoError(t, st.IterateFrom(root, start, func(key, value []byte) error {
+			keys = append(keys, key)
+
+			return nil
+		}))
+
+		return keys
+	}
+
+	keys := iterate(nil)
+	require.Len(t, keys, 50)
+
+	// the start key is included
+	assert.Equal(t, keys[20:], iterate(keys[20]))
+
+	// the keys following the start key which isn't in the trie
+	start := append([]byte{}, keys[20]...)
+	start[len(start)-1]++
+
+	assert.Equal(t, keys[21:], iterate(start))
+
+	// no key follows the last one
+	last := append([]byte{}, keys[49]...)
+	last[len(last)-1]++
+
+	assert.Empty(t, iterate(last))
+}
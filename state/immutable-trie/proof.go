@@ -0,0 +1,183 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/fastrlp"
+)
+
+// Prove returns the encoded nodes on the path of the key in the trie with the given root, starting from the root.
+// The nodes embedded in their parent are part of its encoding. If the key is not in the trie,
+// the path ends where the key diverges, so the nodes prove its absence (EIP-1186)
+func (s *State) Prove(root types.Hash, key []byte) ([][]byte, error) {
+	proof := make([][]byte, 0)
+
+	if root == types.EmptyRootHash {
+		return proof, nil
+	}
+
+	hash, path := root.Bytes(), bytesToHexNibbles(key)
+
+	for hash != nil {
+		data, ok := s.storage.Get(hash)
+		if !ok {
+			return nil, fmt.Errorf("trie node %s not found", types.BytesToHash(hash))
+		}
+
+		proof = append(proof, data)
+
+		node, _, err := GetNode(hash, s.storage)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, path = nextProofNode(node, path)
+	}
+
+	return proof, nil
+}
+
+// ErrInvalidProof is returned when the proof nodes don't prove the key against the root
+var ErrInvalidProof = errors.New("invalid proof")
+
+// VerifyProof returns the value of the key proved by the nodes against the root, nil if they prove its absence.
+// The proof must hold all the nodes on the path of the key, a missing node doesn't prove the absence
+func VerifyProof(root types.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	if root == types.EmptyRootHash {
+		return nil, nil
+	}
+
+	storage := NewMemoryStorage()
+	for _, node := range proof {
+		storage.Put(crypto.Keccak256(node), node)
+	}
+
+	hash, path := root.Bytes(), bytesToHexNibbles(key)
+
+	for {
+		node, ok, err := GetNode(hash, storage)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		} else if !ok {
+			return nil, fmt.Errorf("%w: node %s missing", ErrInvalidProof, types.BytesToHash(hash))
+		}
+
+		var value []byte
+
+		if value, hash, path = followProofNode(node, path); hash == nil {
+			return value, nil
+		}
+	}
+}
+
+// followProofNode follows the path in the node and its embedded children. It returns the value of the key
+// if the path ends in the node, or the hash of the next stored node on the path and the rest of the path
+func followProofNode(node Node, path []byte) ([]byte, []byte, []byte) {
+	switch n := node.(type) {
+	case *ValueNode:
+		if n.hash {
+			return nil, n.buf, path
+		}
+
+		if len(path) == 0 {
+			return n.buf, nil, nil
+		}
+
+		return nil, nil, nil
+
+	case *ShortNode:
+		plen := len(n.key)
+		if plen > len(path) || !bytes.Equal(path[:plen], n.key) {
+			return nil, nil, nil
+		}
+
+		return followProofNode(n.child, path[plen:])
+
+	case *FullNode:
+		if len(path) == 0 {
+			return followProofNode(n.value, path)
+		}
+
+		return followProofNode(n.getEdge(path[0]), path[1:])
+
+	default:
+		return nil, nil, nil
+	}
+}
+
+// nextProofNode follows the path in the node and its embedded children,
+// it returns the hash of the next stored node on the path and the rest of the path, if any
+func nextProofNode(node Node, path []byte) ([]byte, []byte) {
+	switch n := node.(type) {
+	case *ValueNode:
+		if n.hash {
+			return n.buf, path
+		}
+
+		return nil, nil
+
+	case *ShortNode:
+		plen := len(n.key)
+		if plen > len(path) || !bytes.Equal(path[:plen], n.key) {
+			return nil, nil
+		}
+
+		return nextProofNode(n.child, path[plen:])
+
+	case *FullNode:
+		if len(path) == 0 {
+			return nextProofNode(n.value, path)
+		}
+
+		return nextProofNode(n.getEdge(path[0]), path[1:])
+
+	default:
+		return nil, nil
+	}
+}
+
+// ProveIndex builds the trie of the list keyed by the RLP encoded indexes of its items, as the transactions
+// and receipts tries of the blocks, and returns its root along with the proof of the item at the index
+func ProveIndex(num int, item func(i int) []byte, index int) (types.Hash, [][]byte, error) {
+	if index < 0 || index >= num {
+		return types.Hash{}, nil, fmt.Errorf("index %d out of the %d items", index, num)
+	}
+
+	storage := NewMemoryStorage()
+
+	txn := NewTrie().Txn()
+	txn.batch = storage
+
+	var (
+		ar  = &fastrlp.Arena{}
+		key []byte
+	)
+
+	for i := 0; i < num; i++ {
+		indexKey := ar.NewUint(uint64(i)).MarshalTo(nil)
+		if i == index {
+			key = indexKey
+		}
+
+		txn.Insert(indexKey, item(i))
+		ar.Reset()
+	}
+
+	hash, err := txn.Hash()
+	if err != nil {
+		return types.Hash{}, nil, err
+	}
+
+	root := types.BytesToHash(hash)
+
+	proof, err := NewState(storage).Prove(root, key)
+	if err != nil {
+		return types.Hash{}, nil, err
+	}
+
+	return root, proof, nil
+}
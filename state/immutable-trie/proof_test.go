@@ -0,0 +1,195 @@
+package itrie
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/fastrlp"
+)
+
+// proofState returns a state made only of the proof nodes, so a lookup succeeds only if the proof is complete
+func proofState(proof [][]byte) *State {
+	storage := NewMemoryStorage()
+
+	for _, node := range proof {
+		storage.Put(crypto.Keccak256(node), node)
+	}
+
+	return NewState(storage)
+}
+
+func TestState_Prove(t *testing.T) {
+	t.Parallel()
+
+	st := NewState(NewMemoryStorage())
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 50; i++ {
+		addr := types.BytesToAddress([]byte{byte(i + 1)})
+		txn.SetBalance(addr, big.NewInt(int64(i+1)))
+
+		for j := 0; j < 5; j++ {
+			txn.SetState(addr, types.BytesToHash([]byte{byte(j)}), types.BytesToHash([]byte{byte(i + j + 1)}))
+		}
+	}
+
+	_, rootBytes := st.NewSnapshot().Commit(txn.Commit(false))
+	root := types.BytesToHash(rootBytes)
+
+	snap, err := st.NewSnapshotAt(root)
+	require.NoError(t, err)
+
+	t.Run("existing account", func(t *testing.T) {
+		t.Parallel()
+
+		key := crypto.Keccak256(types.BytesToAddress([]byte{7}).Bytes())
+
+		proof, err := st.Prove(root, key)
+		require.NoError(t, err)
+		assert.NotEmpty(t, proof)
+		assert.Equal(t, root.Bytes(), crypto.Keccak256(proof[0]))
+
+		expected, ok := snap.Get(key)
+		require.True(t, ok)
+
+		proved, err := proofState(proof).NewSnapshotAt(root)
+		require.NoError(t, err)
+
+		value, ok := proved.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, value)
+
+		// the storage slots are proved against the storage root of the account
+		var account state.Account
+		require.NoError(t, account.UnmarshalRlp(value))
+
+		slot := crypto.Keccak256(types.BytesToHash([]byte{3}).Bytes())
+
+		storageProof, err := st.Prove(account.Root, slot)
+		require.NoError(t, err)
+
+		proved, err = proofState(storageProof).NewSnapshotAt(account.Root)
+		require.NoError(t, err)
+
+		value, ok = proved.Get(slot)
+		assert.True(t, ok)
+		assert.NotEmpty(t, value)
+	})
+
+	t.Run("missing account", func(t *testing.T) {
+		t.Parallel()
+
+		key := crypto.Keccak256(types.StringToAddress("dead").Bytes())
+
+		proof, err := st.Prove(root, key)
+		require.NoError(t, err)
+		assert.NotEmpty(t, proof)
+
+		proved, err := proofState(proof).NewSnapshotAt(root)
+		require.NoError(t, err)
+
+		_, ok := proved.Get(key)
+		assert.False(t, ok)
+	})
+
+	t.Run("empty trie", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := st.Prove(types.EmptyRootHash, []byte{0x1})
+		require.NoError(t, err)
+		assert.Empty(t, proof)
+	})
+
+	t.Run("unknown root", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := st.Prove(types.StringToHash("1"), []byte{0x1})
+		assert.Error(t, err)
+	})
+}
+
+func TestProveIndex(t *testing.T) {
+	t.Parallel()
+
+	items := make([][]byte, 200)
+	for i := range items {
+		items[i] = bytes.Repeat([]byte{byte(i)}, 40)
+	}
+
+	item := func(i int) []byte {
+		return items[i]
+	}
+
+	for _, index := range []int{0, 1, 127, 128, 199} {
+		root, proof, err := ProveIndex(len(items), item, index)
+		require.NoError(t, err)
+		assert.Equal(t, root.Bytes(), crypto.Keccak256(proof[0]))
+
+		proved, err := proofState(proof).NewSnapshotAt(root)
+		require.NoError(t, err)
+
+		// the items are keyed by their RLP encoded index
+		key := (&fastrlp.Arena{}).NewUint(uint64(index)).MarshalTo(nil)
+
+		value, ok := proved.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, items[index], value)
+	}
+
+	_, _, err := ProveIndex(len(items), item, len(items))
+	assert.Error(t, err)
+}
+
+func TestVerifyProof(t *testing.T) {
+	t.Parallel()
+
+	st := NewState(NewMemoryStorage())
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 50; i++ {
+		txn.SetBalance(types.BytesToAddress([]byte{byte(i + 1)}), big.NewInt(int64(i+1)))
+	}
+
+	_, rootBytes := st.NewSnapshot().Commit(txn.Commit(false))
+	root := types.BytesToHash(rootBytes)
+
+	key := crypto.Keccak256(types.BytesToAddress([]byte{7}).Bytes())
+
+	proof, err := st.Prove(root, key)
+	require.NoError(t, err)
+
+	value, err := VerifyProof(root, key, proof)
+	require.NoError(t, err)
+
+	var account state.Account
+	require.NoError(t, account.UnmarshalRlp(value))
+	assert.Equal(t, big.NewInt(7), account.Balance)
+
+	// the absence of a key is proved by the nodes on its path
+	missing := crypto.Keccak256(types.StringToAddress("dead").Bytes())
+
+	missingProof, err := st.Prove(root, missing)
+	require.NoError(t, err)
+
+	value, err = VerifyProof(root, missing, missingProof)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// a truncated proof doesn't prove the absence of the key
+	_, err = VerifyProof(root, key, proof[:len(proof)-1])
+	assert.ErrorIs(t, err, ErrInvalidProof)
+
+	_, err = VerifyProof(types.StringToHash("1"), key, proof)
+	assert.ErrorIs(t, err, ErrInvalidProof)
+
+	value, err = VerifyProof(types.EmptyRootHash, key, nil)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
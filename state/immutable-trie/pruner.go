@@ -0,0 +1,557 @@
+package itrie
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-edge/kvdb"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// MinStateRetention is the minimum number of recent blocks whose state is retained,
+	// the states of the recent blocks are cached in memory by the state
+	MinStateRetention = 128
+
+	// pruneCheckInterval is how often the pruner checks if the chain advanced enough to prune
+	pruneCheckInterval = 30 * time.Second
+
+	// pruneBatchSize is the maximum number of nodes removed at once, along with the bookkeeping
+	pruneBatchSize = 10000
+)
+
+var (
+	// prunerKey is the key of the pruner bookkeeping, trie nodes are keyed by their 32 bytes hash
+	prunerKey = []byte("pruner")
+
+	// markPrefix is the prefix of the keys of the nodes marked by the pruning
+	markPrefix = []byte("pruner-mark-")
+
+	errPrunerClosed = errors.New("pruner closed")
+)
+
+// PrunerChain is the chain whose recent states are retained by the pruner
+type PrunerChain interface {
+	Header() *types.Header
+	GetHeaderByNumber(uint64) (*types.Header, bool)
+}
+
+// prunerProgress is the bookkeeping of the pruner. It is written along with each batch of removed nodes,
+// so a pruning interrupted by a crash or a shutdown is resumed on the next start
+type prunerProgress struct {
+	// Number is the head of the chain when the last pruning started
+	Number uint64 `json:"number"`
+	// Cursor is the last key swept by the interrupted pruning
+	Cursor []byte `json:"cursor,omitempty"`
+
+	RemovedNodes   uint64 `json:"removedNodes"`
+	ReclaimedBytes uint64 `json:"reclaimedBytes"`
+}
+
+// Pruner removes the trie nodes which aren't reachable from the states of the last blocks,
+// nor from the pinned states, the states of the blocks whose number is a multiple of the pin interval.
+// The pruning runs along with the block processing, so the nodes written while pruning are kept
+type Pruner struct {
+	logger      hclog.Logger
+	kv          *KVStorage
+	retention   uint64
+	pinInterval uint64 // 0 pins no block
+
+	lock sync.Mutex
+	// keys written while pruning, nil if not pruning
+	written map[string]struct{}
+
+	progress prunerProgress
+
+	// cache the removed nodes are evicted from, nil if none
+	cache *CachedStorage
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewPruner creates the pruner of the key-value state storage, retaining the states of the last retention blocks
+// along with the states of the blocks pinned every pinInterval blocks
+func NewPruner(logger hclog.Logger, storage Storage, retention, pinInterval uint64) (*Pruner, error) {
+	kv, ok := storage.(*KVStorage)
+	if !ok {
+		return nil, fmt.Errorf("state storage %T can't be pruned", storage)
+	}
+
+	if retention < MinStateRetention {
+		return nil, fmt.Errorf("state retention %d is less than the minimum of %d blocks", retention, MinStateRetention)
+	}
+
+	p := &Pruner{
+		logger:      logger.Named("pruner"),
+		kv:          kv,
+		retention:   retention,
+		pinInterval: pinInterval,
+		closeCh:     make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+
+	if data, ok := kv.Get(prunerKey); ok {
+		if err := json.Unmarshal(data, &p.progress); err != nil {
+			return nil, fmt.Errorf("failed to read pruner progress: %w", err)
+		}
+	}
+
+	p.publish()
+
+	return p, nil
+}
+
+// Storage returns the storage the tries are written through, so the nodes written while pruning are kept
+func (p *Pruner) Storage() Storage {
+	return &prunerStorage{KVStorage: p.kv, pruner: p}
+}
+
+// SetCache sets the cache of the storage, the removed nodes are evicted from it
+func (p *Pruner) SetCache(cache *CachedStorage) {
+	p.cache = cache
+}
+
+// Start starts pruning the states as the chain advances
+func (p *Pruner) Start(chain PrunerChain) {
+	go p.run(chain)
+}
+
+// Close stops the pruning, an ongoing one is resumed on the next start
+func (p *Pruner) Close() {
+	close(p.closeCh)
+	<-p.doneCh
+}
+
+func (p *Pruner) run(chain PrunerChain) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(pruneCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if head := chain.Header(); p.shouldPrune(head) {
+			if err := p.Prune(chain); errors.Is(err, errPrunerClosed) {
+				return
+			} else if err != nil {
+				p.logger.Error("failed to prune the state", "err", err)
+			}
+		}
+
+		select {
+		case <-p.closeCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shouldPrune checks if an interrupted pruning has to be resumed,
+// or if the chain advanced by the retention since the last pruning
+func (p *Pruner) shouldPrune(head *types.Header) bool {
+	if head == nil || head.Number < p.retention {
+		return false
+	}
+
+	return p.progress.Cursor != nil || head.Number >= p.progress.Number+p.retention
+}
+
+// Prune removes the trie nodes which aren't reachable from the states of the last retention blocks,
+// nor from the pinned states
+func (p *Pruner) Prune(chain PrunerChain) error {
+	start := time.Now()
+
+	p.lock.Lock()
+	p.written = make(map[string]struct{})
+	p.lock.Unlock()
+
+	defer func() {
+		p.lock.Lock()
+		p.written = nil
+		p.lock.Unlock()
+	}()
+
+	// the marks left by a pruning interrupted while marking
+	marks := newMarkSet(p.kv.db)
+	if err := marks.clear(); err != nil {
+		return err
+	}
+
+	head := chain.Header()
+	from := uint64(0)
+
+	if head.Number >= p.retention {
+		from = head.Number - p.retention + 1
+	}
+
+	marker := &stateWalker{
+		storage: p.kv,
+		handler: func(*SnapshotEntry) error { return nil },
+		visited: marks,
+	}
+
+	to, err := p.mark(chain, marker, from, head.Number)
+	if err != nil {
+		return err
+	}
+
+	// the states of the blocks written while marking were committed before the
+	// nodes written are tracked, so they are marked as well
+	if _, err := p.mark(chain, marker, to+1, chain.Header().Number); err != nil {
+		return err
+	}
+
+	if err := p.markPinned(chain, marker, from); err != nil {
+		return err
+	}
+
+	if err := marks.flush(); err != nil {
+		return err
+	}
+
+	if p.progress.Cursor == nil {
+		p.progress.Number = head.Number
+	}
+
+	removed, reclaimed, err := p.sweep(marks)
+	if err == nil {
+		err = marks.clear()
+	}
+
+	p.logger.Info(
+		"pruned the state",
+		"head", head.Number,
+		"retained", marks.count,
+		"removed", removed,
+		"reclaimed", reclaimed,
+		"interrupted", err != nil,
+		"duration", time.Since(start),
+	)
+
+	return err
+}
+
+// mark visits the states of the blocks in the range, and returns the last visited block
+func (p *Pruner) mark(chain PrunerChain, marker *stateWalker, from, to uint64) (uint64, error) {
+	for number := from; number <= to; number++ {
+		select {
+		case <-p.closeCh:
+			return 0, errPrunerClosed
+		default:
+		}
+
+		header, ok := chain.GetHeaderByNumber(number)
+		if !ok {
+			return 0, fmt.Errorf("header %d not found", number)
+		}
+
+		// the states preceding the snap sync checkpoint are missing
+		if _, ok := p.kv.Get(header.StateRoot.Bytes()); !ok {
+			continue
+		}
+
+		if err := marker.walkHash(header.StateRoot, true); err != nil {
+			return 0, fmt.Errorf("failed to mark the state of block %d: %w", number, err)
+		}
+	}
+
+	return to, nil
+}
+
+// markPinned visits the pinned states of the blocks preceding the given one
+func (p *Pruner) markPinned(chain PrunerChain, marker *stateWalker, to uint64) error {
+	if p.pinInterval == 0 {
+		return nil
+	}
+
+	for number := uint64(0); number < to; number += p.pinInterval {
+		if _, err := p.mark(chain, marker, number, number); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sweep removes the trie nodes which aren't marked nor written while pruning,
+// resuming from the cursor of an interrupted pruning
+func (p *Pruner) sweep(marks *markSet) (uint64, uint64, error) {
+	iter := p.kv.db.NewIterator(nil, p.progress.Cursor)
+	defer iter.Release()
+
+	var (
+		removed, reclaimed uint64
+
+		keys = make([][]byte, 0, pruneBatchSize)
+		size = make([]int, 0, pruneBatchSize)
+	)
+
+	for {
+		keys, size = keys[:0], size[:0]
+
+		for len(keys) < pruneBatchSize && iter.Next() {
+			key := iter.Key()
+			if len(key) != types.HashLength {
+				continue
+			}
+
+			marked, err := marks.contains(types.BytesToHash(key))
+			if err != nil {
+				return removed, reclaimed, err
+			}
+
+			if marked {
+				continue
+			}
+
+			keys = append(keys, append([]byte{}, key...))
+			size = append(size, len(key)+len(iter.Value()))
+		}
+
+		if err := iter.Error(); err != nil {
+			return removed, reclaimed, err
+		}
+
+		done := len(keys) < pruneBatchSize
+
+		batchRemoved, batchReclaimed, err := p.removeNodes(keys, size, done)
+		if err != nil {
+			return removed, reclaimed, err
+		}
+
+		removed += batchRemoved
+		reclaimed += batchReclaimed
+
+		if done {
+			return removed, reclaimed, nil
+		}
+
+		select {
+		case <-p.closeCh:
+			return removed, reclaimed, errPrunerClosed
+		default:
+		}
+	}
+}
+
+// removeNodes removes the nodes which haven't been written while pruning, along with
+// writing the progress, the sweep is completed if done is set
+func (p *Pruner) removeNodes(keys [][]byte, size []int, done bool) (uint64, uint64, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var (
+		batch              = p.kv.db.NewBatch()
+		removedKeys        = make([][]byte, 0, len(keys))
+		removed, reclaimed uint64
+	)
+
+	for i, key := range keys {
+		if _, ok := p.written[string(key)]; ok {
+			continue
+		}
+
+		batch.Delete(key)
+		removedKeys = append(removedKeys, key)
+
+		removed++
+		reclaimed += uint64(size[i])
+	}
+
+	progress := p.progress
+	progress.RemovedNodes += removed
+	progress.ReclaimedBytes += reclaimed
+	progress.Cursor = nil
+
+	if !done && len(keys) > 0 {
+		progress.Cursor = keys[len(keys)-1]
+	}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	batch.Put(prunerKey, data)
+
+	if err := batch.Write(); err != nil {
+		return 0, 0, err
+	}
+
+	if p.cache != nil {
+		p.cache.Evict(removedKeys...)
+	}
+
+	p.progress = progress
+	p.publish()
+
+	return removed, reclaimed, nil
+}
+
+// publish sets the metrics of the pruner
+func (p *Pruner) publish() {
+	metrics.SetGauge([]string{"state_pruned_nodes"}, float32(p.progress.RemovedNodes))
+	metrics.SetGauge([]string{"state_pruned_bytes"}, float32(p.progress.ReclaimedBytes))
+	metrics.SetGauge([]string{"state_pruned_block"}, float32(p.progress.Number))
+}
+
+// track records the keys written while pruning, along with writing them
+func (p *Pruner) track(keys [][]byte, write func()) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.written != nil {
+		for _, key := range keys {
+			p.written[string(key)] = struct{}{}
+		}
+	}
+
+	write()
+}
+
+// prunerStorage is the key-value storage whose writes are tracked by the pruner
+type prunerStorage struct {
+	*KVStorage
+	pruner *Pruner
+}
+
+func (s *prunerStorage) Put(k, v []byte) {
+	s.pruner.track([][]byte{k}, func() {
+		s.KVStorage.Put(k, v)
+	})
+}
+
+func (s *prunerStorage) Batch() Batch {
+	return &prunerBatch{
+		KVBatch: &KVBatch{batch: s.db.NewBatch()},
+		pruner:  s.pruner,
+	}
+}
+
+// prunerBatch is the batch write whose keys are tracked by the pruner
+type prunerBatch struct {
+	*KVBatch
+	pruner *Pruner
+	keys   [][]byte
+}
+
+func (b *prunerBatch) Put(k, v []byte) {
+	b.keys = append(b.keys, append([]byte{}, k...))
+	b.KVBatch.Put(k, v)
+}
+
+func (b *prunerBatch) Write() {
+	b.pruner.track(b.keys, b.KVBatch.Write)
+}
+
+// markSet is the node set of the pruning, written in the database along with the nodes so the memory
+// doesn't grow with the size of the state. Every live node takes a mark of 44 bytes on the disk, the marks
+// are buffered in memory by batches of pruneBatchSize, and removed once the sweep is done
+type markSet struct {
+	db      kvdb.Database
+	pending map[types.Hash]struct{}
+
+	// count is the number of marked nodes
+	count uint64
+	// err is the first error reading or writing the marks, the nodes can't be swept if set
+	err error
+}
+
+func newMarkSet(db kvdb.Database) *markSet {
+	return &markSet{
+		db:      db,
+		pending: make(map[types.Hash]struct{}, pruneBatchSize),
+	}
+}
+
+func markKey(hash types.Hash) []byte {
+	return append(append(make([]byte, 0, len(markPrefix)+types.HashLength), markPrefix...), hash.Bytes()...)
+}
+
+// contains checks if the node is marked
+func (m *markSet) contains(hash types.Hash) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	if _, ok := m.pending[hash]; ok {
+		return true, nil
+	}
+
+	_, ok, err := m.db.Get(markKey(hash))
+
+	return ok, err
+}
+
+func (m *markSet) has(hash types.Hash) bool {
+	ok, err := m.contains(hash)
+	if err != nil && m.err == nil {
+		m.err = err
+	}
+
+	return ok
+}
+
+func (m *markSet) add(hash types.Hash) {
+	m.pending[hash] = struct{}{}
+	m.count++
+
+	if len(m.pending) >= pruneBatchSize {
+		m.err = m.flush()
+	}
+}
+
+// flush writes the pending marks, it returns the first error of the mark set
+func (m *markSet) flush() error {
+	if m.err != nil {
+		return m.err
+	}
+
+	batch := m.db.NewBatch()
+
+	for hash := range m.pending {
+		batch.Put(markKey(hash), []byte{})
+	}
+
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	m.pending = make(map[types.Hash]struct{}, pruneBatchSize)
+
+	return nil
+}
+
+// clear removes the marks written in the database
+func (m *markSet) clear() error {
+	iter := m.db.NewIterator(markPrefix, nil)
+	defer iter.Release()
+
+	batch := m.db.NewBatch()
+
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+
+		if batch.Len() < pruneBatchSize {
+			continue
+		}
+
+		if err := batch.Write(); err != nil {
+			return err
+		}
+
+		batch = m.db.NewBatch()
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	return batch.Write()
+}
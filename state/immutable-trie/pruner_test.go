@@ -0,0 +1,194 @@
+package itrie
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+type mockPrunerChain struct {
+	headers []*types.Header
+}
+
+func (m *mockPrunerChain) Header() *types.Header {
+	return m.headers[len(m.headers)-1]
+}
+
+func (m *mockPrunerChain) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number >= uint64(len(m.headers)) {
+		return nil, false
+	}
+
+	return m.headers[number], true
+}
+
+// writePrunerChain writes the states of the blocks through the pruner,
+// every block changes the balance and the storage of the account
+func writePrunerChain(t *testing.T, pruner *Pruner, blocks uint64) *mockPrunerChain {
+	t.Helper()
+
+	var (
+		st    = NewState(pruner.Storage())
+		chain = &mockPrunerChain{}
+		root  = types.EmptyRootHash
+		addr  = types.StringToAddress("1")
+	)
+
+	for number := uint64(0); number < blocks; number++ {
+		snap, err := st.NewSnapshotAt(root)
+		require.NoError(t, err)
+
+		txn := state.NewTxn(st, snap)
+		txn.SetBalance(addr, big.NewInt(int64(number+1)))
+		txn.SetState(addr, types.BytesToHash([]byte{byte(number % 7)}), types.BytesToHash([]byte{byte(number + 1)}))
+
+		_, rootBytes := snap.Commit(txn.Commit(false))
+		root = types.BytesToHash(rootBytes)
+
+		chain.headers = append(chain.headers, &types.Header{Number: number, StateRoot: root})
+	}
+
+	return chain
+}
+
+func TestPruner_Prune(t *testing.T) {
+	t.Parallel()
+
+	storage, err := NewLevelDBStorage(t.TempDir(), hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = storage.Close()
+	})
+
+	pruner, err := NewPruner(hclog.NewNullLogger(), storage, MinStateRetention, 0)
+	require.NoError(t, err)
+
+	chain := writePrunerChain(t, pruner, 2*MinStateRetention)
+
+	assert.True(t, pruner.shouldPrune(chain.Header()))
+	require.NoError(t, pruner.Prune(chain))
+
+	// read the states without the cached tries
+	stored := NewState(storage)
+
+	for _, header := range chain.headers {
+		err := stored.Walk(header.StateRoot, func(*SnapshotEntry) error { return nil })
+
+		if header.Number > chain.Header().Number-MinStateRetention {
+			assert.NoError(t, err, "state of block %d", header.Number)
+		} else {
+			assert.Error(t, err, "state of block %d", header.Number)
+		}
+	}
+
+	assert.Greater(t, pruner.progress.RemovedNodes, uint64(0))
+	assert.Greater(t, pruner.progress.ReclaimedBytes, uint64(0))
+	assert.False(t, pruner.shouldPrune(chain.Header()))
+
+	// the bookkeeping is persisted along with the removed nodes
+	data, ok := storage.Get(prunerKey)
+	require.True(t, ok)
+
+	var progress prunerProgress
+	require.NoError(t, json.Unmarshal(data, &progress))
+	assert.Equal(t, pruner.progress, progress)
+	assert.Nil(t, progress.Cursor)
+}
+
+func TestPruner_PinnedStates(t *testing.T) {
+	t.Parallel()
+
+	storage, err := NewLevelDBStorage(t.TempDir(), hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = storage.Close()
+	})
+
+	pinInterval := uint64(10)
+
+	pruner, err := NewPruner(hclog.NewNullLogger(), storage, MinStateRetention, pinInterval)
+	require.NoError(t, err)
+
+	chain := writePrunerChain(t, pruner, 2*MinStateRetention)
+	require.NoError(t, pruner.Prune(chain))
+
+	// read the states without the cached tries
+	stored := NewState(storage)
+
+	for _, header := range chain.headers {
+		err := stored.Walk(header.StateRoot, func(*SnapshotEntry) error { return nil })
+
+		if header.Number > chain.Header().Number-MinStateRetention || header.Number%pinInterval == 0 {
+			assert.NoError(t, err, "state of block %d", header.Number)
+		} else {
+			assert.Error(t, err, "state of block %d", header.Number)
+		}
+	}
+
+	// the marks are removed along with the pruned nodes
+	iter := pruner.kv.db.NewIterator(markPrefix, nil)
+	defer iter.Release()
+
+	assert.False(t, iter.Next())
+}
+
+func TestPruner_KeepWrittenNodes(t *testing.T) {
+	t.Parallel()
+
+	storage, err := NewLevelDBStorage(t.TempDir(), hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = storage.Close()
+	})
+
+	pruner, err := NewPruner(hclog.NewNullLogger(), storage, MinStateRetention, 0)
+	require.NoError(t, err)
+
+	var (
+		written   = types.StringToHash("1")
+		unwritten = types.StringToHash("2")
+	)
+
+	storage.Put(unwritten.Bytes(), []byte{0x1})
+
+	// the node is written while pruning
+	pruner.written = map[string]struct{}{}
+	pruner.Storage().Put(written.Bytes(), []byte{0x2})
+
+	removed, _, err := pruner.sweep(newMarkSet(pruner.kv.db))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), removed)
+
+	_, ok := storage.Get(written.Bytes())
+	assert.True(t, ok)
+
+	_, ok = storage.Get(unwritten.Bytes())
+	assert.False(t, ok)
+}
+
+func TestNewPruner_MinRetention(t *testing.T) {
+	t.Parallel()
+
+	storage, err := NewLevelDBStorage(t.TempDir(), hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = storage.Close()
+	})
+
+	_, err = NewPruner(hclog.NewNullLogger(), storage, MinStateRetention-1, 0)
+	assert.Error(t, err)
+
+	_, err = NewPruner(hclog.NewNullLogger(), NewMemoryStorage(), MinStateRetention, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// rangeKeyLength is the length of the keys of the state tries, the hashes of the addresses and the storage slots
+const rangeKeyLength = types.HashLength
+
+var (
+	ErrInvalidRange      = errors.New("invalid range")
+	ErrInvalidRangeProof = errors.New("invalid range proof")
+
+	errRangeFull = errors.New("range full")
+)
+
+// ProveRange returns the consecutive keys and values of the trie with the given root from the origin key,
+// until their size reaches the max bytes, along with the proof of the range. The proof is made of the nodes
+// on the paths of the origin and of the last returned key, so the range is verified against the root alone
+func (s *State) ProveRange(root types.Hash, origin []byte, maxBytes int) ([][]byte, [][]byte, [][]byte, error) {
+	var (
+		keys   = make([][]byte, 0)
+		values = make([][]byte, 0)
+		size   = 0
+	)
+
+	if err := s.IterateFrom(root, origin, func(key, value []byte) error {
+		keys = append(keys, key)
+		values = append(values, value)
+
+		if size += len(key) + len(value); size >= maxBytes {
+			return errRangeFull
+		}
+
+		return nil
+	}); err != nil && !errors.Is(err, errRangeFull) {
+		return nil, nil, nil, err
+	}
+
+	proof, err := s.Prove(root, origin)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(keys) > 0 {
+		lastProof, err := s.Prove(root, keys[len(keys)-1])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		// the paths share their nodes from the root down to where they diverge
+		seen := make(map[string]struct{}, len(proof))
+		for _, node := range proof {
+			seen[string(node)] = struct{}{}
+		}
+
+		for _, node := range lastProof {
+			if _, ok := seen[string(node)]; !ok {
+				proof = append(proof, node)
+			}
+		}
+	}
+
+	return keys, values, proof, nil
+}
+
+// VerifyRangeProof checks that the keys and values are all the ones of the trie with the given root
+// from the origin key up to the last key. It rebuilds the trie nodes of the range, and returns the ones
+// whose subtree is complete, so they can be stored as they are. It also returns whether the trie
+// has more keys after the range
+func VerifyRangeProof(
+	root types.Hash,
+	origin []byte,
+	keys, values, proof [][]byte,
+) ([]*SnapshotEntry, bool, error) {
+	if len(origin) != rangeKeyLength || len(keys) != len(values) {
+		return nil, false, ErrInvalidRange
+	}
+
+	for i, key := range keys {
+		if len(key) != rangeKeyLength || len(values[i]) == 0 {
+			return nil, false, ErrInvalidRange
+		}
+
+		prev := origin
+		if i > 0 {
+			prev = keys[i-1]
+		}
+
+		if cmp := bytes.Compare(key, prev); cmp < 0 || (cmp == 0 && i > 0) {
+			return nil, false, fmt.Errorf("%w: keys out of order", ErrInvalidRange)
+		}
+	}
+
+	proofStorage := NewMemoryStorage()
+	for _, node := range proof {
+		proofStorage.Put(crypto.Keccak256(node), node)
+	}
+
+	if len(keys) == 0 {
+		// nothing is left from the origin, so the proof must not lead to any key after it
+		err := NewState(proofStorage).IterateFrom(root, origin, func(key, value []byte) error {
+			return fmt.Errorf("%w: missing key %x", ErrInvalidRange, key)
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %v", ErrInvalidRangeProof, err)
+		}
+
+		return nil, false, nil
+	}
+
+	var (
+		left  = bytesToHexNibbles(origin)
+		right = bytesToHexNibbles(keys[len(keys)-1])
+	)
+
+	// the nodes between the paths of the range are rebuilt from its keys, the ones outside of it are kept
+	trimmed, err := trimRange(&ValueNode{hash: true, buf: root.Bytes()}, proofStorage, 0, left, right, true, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	more := hasRightSibling(trimmed, right)
+
+	collected := make(map[types.Hash][]byte)
+	txn := &Txn{root: trimmed, epoch: 1, storage: proofStorage, batch: rangeCollector(collected)}
+
+	for i, key := range keys {
+		txn.Insert(key, values[i])
+	}
+
+	hash, err := txn.Hash()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !bytes.Equal(hash, root.Bytes()) {
+		return nil, false, fmt.Errorf(
+			"%w: root mismatch, expected %s, got %s",
+			ErrInvalidRangeProof,
+			root,
+			types.BytesToHash(hash),
+		)
+	}
+
+	// the nodes on the paths of the range also cover keys outside of it, unless there are none
+	var (
+		leftOpen  = !bytes.Equal(origin, make([]byte, rangeKeyLength))
+		edges     = make(map[types.Hash]struct{})
+		entries   = make([]*SnapshotEntry, 0, len(collected))
+		txnRoot   = txn.root
+		edgePaths = make([][]byte, 0, 2)
+	)
+
+	if leftOpen {
+		edgePaths = append(edgePaths, left)
+	}
+
+	if more {
+		edgePaths = append(edgePaths, right)
+	}
+
+	for _, path := range edgePaths {
+		collectPathHashes(txnRoot, path, edges)
+	}
+
+	for hash, value := range collected {
+		if _, ok := edges[hash]; !ok {
+			entries = append(entries, &SnapshotEntry{Hash: hash, Value: value})
+		}
+	}
+
+	return entries, more, nil
+}
+
+// rangeCollector collects the nodes hashed by a transaction
+type rangeCollector map[types.Hash][]byte
+
+func (c rangeCollector) Put(k, v []byte) {
+	c[types.BytesToHash(k)] = append([]byte(nil), v...)
+}
+
+// trimRange removes the subtrees of the node which are strictly between the left and right paths,
+// the node being on the left path, the right path or both. The nodes on the paths are resolved from the proof
+// and copied, the subtrees outside of the paths are kept as they are
+func trimRange(node Node, proof Storage, pos int, left, right []byte, onLeft, onRight bool) (Node, error) {
+	switch n := node.(type) {
+	case nil:
+		return nil, nil
+
+	case *ValueNode:
+		if !n.hash {
+			// the leaf of the origin or of the last key, the value is set again by the range
+			return n, nil
+		}
+
+		resolved, ok, err := GetNode(n.buf, proof)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRangeProof, err)
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("%w: missing node %s", ErrInvalidRangeProof, types.BytesToHash(n.buf))
+		}
+
+		trimmed, err := trimRange(resolved, proof, pos, left, right, onLeft, onRight)
+		if err != nil {
+			return nil, err
+		}
+
+		if trimmed == resolved {
+			// the whole subtree is outside of the range, it's still referenced by its hash
+			return n, nil
+		}
+
+		return trimmed, nil
+
+	case *ShortNode:
+		key := n.key
+		if hasTerminator(key) {
+			key = key[:len(key)-1]
+		}
+
+		if pos+len(key) >= len(left) {
+			return nil, fmt.Errorf("%w: key too long", ErrInvalidRangeProof)
+		}
+
+		var (
+			cmpLeft  = bytes.Compare(key, left[pos:pos+len(key)])
+			cmpRight = bytes.Compare(key, right[pos:pos+len(key)])
+		)
+
+		if (onLeft && cmpLeft < 0) || (onRight && cmpRight > 0) {
+			return n, nil
+		}
+
+		onLeft, onRight = onLeft && cmpLeft == 0, onRight && cmpRight == 0
+		if !onLeft && !onRight {
+			return nil, nil
+		}
+
+		child, err := trimRange(n.child, proof, pos+len(key), left, right, onLeft, onRight)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ShortNode{key: n.key, child: child}, nil
+
+	case *FullNode:
+		if pos >= len(left)-1 {
+			return nil, fmt.Errorf("%w: branch too deep", ErrInvalidRangeProof)
+		}
+
+		nc := &FullNode{value: n.value}
+
+		for i, child := range n.children {
+			var (
+				idx        = byte(i)
+				childLeft  = onLeft && idx == left[pos]
+				childRight = onRight && idx == right[pos]
+			)
+
+			switch {
+			case (onLeft && idx < left[pos]) || (onRight && idx > right[pos]):
+				nc.children[i] = child
+
+			case childLeft || childRight:
+				trimmed, err := trimRange(child, proof, pos+1, left, right, childLeft, childRight)
+				if err != nil {
+					return nil, err
+				}
+
+				nc.children[i] = trimmed
+			}
+		}
+
+		return nc, nil
+
+	default:
+		return nil, fmt.Errorf("unknown node type %T", node)
+	}
+}
+
+// hasRightSibling checks if the trie has any key after the path, which leads to a key of the trie
+func hasRightSibling(node Node, path []byte) bool {
+	switch n := node.(type) {
+	case *ShortNode:
+		key := n.key
+		if hasTerminator(key) {
+			return false
+		}
+
+		return hasRightSibling(n.child, path[len(key):])
+
+	case *FullNode:
+		for i := int(path[0]) + 1; i < len(n.children); i++ {
+			if n.children[i] != nil {
+				return true
+			}
+		}
+
+		return hasRightSibling(n.children[path[0]], path[1:])
+
+	default:
+		return false
+	}
+}
+
+// collectPathHashes collects the hashes of the stored nodes on the path,
+// the nodes embedded in their parent have none
+func collectPathHashes(node Node, path []byte, hashes map[types.Hash]struct{}) {
+	if hash, ok := node.Hash(); ok {
+		hashes[types.BytesToHash(hash)] = struct{}{}
+	}
+
+	switch n := node.(type) {
+	case *ShortNode:
+		if plen := len(n.key); plen <= len(path) && bytes.Equal(n.key, path[:plen]) {
+			collectPathHashes(n.child, path[plen:], hashes)
+		}
+
+	case *FullNode:
+		if len(path) > 0 {
+			if child := n.getEdge(path[0]); child != nil {
+				collectPathHashes(child, path[1:], hashes)
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package itrie
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRangeTestTrie creates a trie with the given number of hashed keys, and returns its root
+func newRangeTestTrie(t *testing.T, n int) (*State, types.Hash) {
+	t.Helper()
+
+	storage := NewMemoryStorage()
+	batch := storage.Batch()
+	txn := &Txn{storage: storage, batch: batch}
+
+	for i := 0; i < n; i++ {
+		key := crypto.Keccak256(big.NewInt(int64(i)).Bytes())
+		txn.Insert(key, append([]byte{0x1}, key[:i%8]...))
+	}
+
+	root, err := txn.Hash()
+	require.NoError(t, err)
+
+	batch.Write()
+
+	return NewState(storage), types.BytesToHash(root)
+}
+
+func TestState_ProveRange(t *testing.T) {
+	t.Parallel()
+
+	st, root := newRangeTestTrie(t, 500)
+
+	var (
+		dst    = NewState(NewMemoryStorage())
+		origin = make([]byte, rangeKeyLength)
+		all    = make([][]byte, 0)
+		ranges = 0
+	)
+
+	for {
+		keys, values, proof, err := st.ProveRange(root, origin, 1024)
+		require.NoError(t, err)
+
+		entries, more, err := VerifyRangeProof(root, origin, keys, values, proof)
+		require.NoError(t, err)
+
+		dst.WriteSnapshotEntries(entries)
+
+		all = append(all, keys...)
+		ranges++
+
+		if !more {
+			break
+		}
+
+		origin = nextRangeKey(keys[len(keys)-1])
+	}
+
+	assert.Greater(t, ranges, 1)
+	assert.Len(t, all, 500)
+
+	// the nodes on the edges of the ranges are missing, the stored ones are the roots of complete subtrees
+	_, ok := dst.TrieNode(root)
+	assert.False(t, ok)
+
+	for _, key := range [][]byte{all[0], all[250], all[499]} {
+		proof, err := st.Prove(root, key)
+		require.NoError(t, err)
+
+		for _, node := range proof {
+			hash := types.BytesToHash(crypto.Keccak256(node))
+			if _, ok := dst.TrieNode(hash); ok {
+				assert.NoError(t, dst.Iterate(hash, func(_, _ []byte) error { return nil }))
+			}
+		}
+	}
+}
+
+func TestState_ProveRange_Complete(t *testing.T) {
+	t.Parallel()
+
+	st, root := newRangeTestTrie(t, 100)
+
+	keys, values, proof, err := st.ProveRange(root, make([]byte, rangeKeyLength), 1024*1024)
+	require.NoError(t, err)
+	assert.Len(t, keys, 100)
+
+	entries, more, err := VerifyRangeProof(root, make([]byte, rangeKeyLength), keys, values, proof)
+	require.NoError(t, err)
+	assert.False(t, more)
+
+	// the whole trie is in the range, so all of its nodes are stored
+	dst := NewState(NewMemoryStorage())
+	dst.WriteSnapshotEntries(entries)
+
+	count := 0
+	require.NoError(t, dst.Iterate(root, func(_, _ []byte) error {
+		count++
+
+		return nil
+	}))
+	assert.Equal(t, 100, count)
+}
+
+func TestVerifyRangeProof_Invalid(t *testing.T) {
+	t.Parallel()
+
+	st, root := newRangeTestTrie(t, 200)
+	origin := bytes.Repeat([]byte{0x40}, rangeKeyLength)
+
+	keys, values, proof, err := st.ProveRange(root, origin, 2048)
+	require.NoError(t, err)
+	require.Greater(t, len(keys), 3)
+
+	_, more, err := VerifyRangeProof(root, origin, keys, values, proof)
+	require.NoError(t, err)
+	assert.True(t, more)
+
+	t.Run("tampered value", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := append([][]byte{}, values...)
+		tampered[1] = []byte{0x2}
+
+		_, _, err := VerifyRangeProof(root, origin, keys, tampered, proof)
+		assert.ErrorIs(t, err, ErrInvalidRangeProof)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := VerifyRangeProof(
+			root,
+			origin,
+			append([][]byte{keys[0]}, keys[2:]...),
+			append([][]byte{values[0]}, values[2:]...),
+			proof,
+		)
+		assert.ErrorIs(t, err, ErrInvalidRangeProof)
+	})
+
+	t.Run("unordered keys", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := VerifyRangeProof(
+			root,
+			origin,
+			[][]byte{keys[1], keys[0]},
+			[][]byte{values[1], values[0]},
+			proof,
+		)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+
+	t.Run("missing proof", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := VerifyRangeProof(root, origin, keys, values, proof[1:])
+		assert.ErrorIs(t, err, ErrInvalidRangeProof)
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		t.Parallel()
+
+		// no key is left after the last one
+		last := bytes.Repeat([]byte{0xff}, rangeKeyLength)
+
+		keys, values, proof, err := st.ProveRange(root, last, 1024)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+
+		_, more, err := VerifyRangeProof(root, last, keys, values, proof)
+		require.NoError(t, err)
+		assert.False(t, more)
+
+		// but there are after the origin
+		_, _, err = VerifyRangeProof(root, origin, nil, nil, proof)
+		assert.ErrorIs(t, err, ErrInvalidRangeProof)
+	})
+}
+
+// nextRangeKey returns the key following the given one
+func nextRangeKey(key []byte) []byte {
+	next := new(big.Int).Add(new(big.Int).SetBytes(key), big.NewInt(1))
+
+	return next.FillBytes(make([]byte, rangeKeyLength))
+}
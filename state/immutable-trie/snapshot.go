@@ -0,0 +1,173 @@
+package itrie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var emptyCodeHash = crypto.Keccak256(nil)
+
+// SnapshotEntry is a stored node of the state tries or a contract code, keyed by its hash
+type SnapshotEntry struct {
+	Hash  types.Hash
+	Value []byte
+	Code  bool
+}
+
+// Walk calls the handler for every stored node of the state trie with the given root,
+// the storage tries of its accounts and their contract codes, each one exactly once.
+// An error is returned if any of them is missing, so it also checks that the state is complete
+func (s *State) Walk(root types.Hash, handler func(*SnapshotEntry) error) error {
+	w := &stateWalker{
+		storage: s.storage,
+		handler: handler,
+		visited: hashSet{},
+	}
+
+	if root == types.EmptyRootHash {
+		return nil
+	}
+
+	return w.walkHash(root, true)
+}
+
+// WriteSnapshotEntries writes the entries into the state storage
+func (s *State) WriteSnapshotEntries(entries []*SnapshotEntry) {
+	batch := s.storage.Batch()
+
+	for _, entry := range entries {
+		if entry.Code {
+			s.storage.SetCode(entry.Hash, entry.Value)
+
+			continue
+		}
+
+		batch.Put(entry.Hash.Bytes(), entry.Value)
+	}
+
+	batch.Write()
+}
+
+// nodeSet is the set of the nodes and codes visited by the state walker
+type nodeSet interface {
+	has(hash types.Hash) bool
+	add(hash types.Hash)
+}
+
+// hashSet is the node set held in memory
+type hashSet map[types.Hash]struct{}
+
+func (s hashSet) has(hash types.Hash) bool {
+	_, ok := s[hash]
+
+	return ok
+}
+
+func (s hashSet) add(hash types.Hash) {
+	s[hash] = struct{}{}
+}
+
+type stateWalker struct {
+	storage Storage
+	handler func(*SnapshotEntry) error
+	visited nodeSet
+}
+
+// walkHash visits the stored node with the given hash and its descendants
+func (w *stateWalker) walkHash(hash types.Hash, accountTrie bool) error {
+	if w.visited.has(hash) {
+		return nil
+	}
+
+	data, ok := w.storage.Get(hash.Bytes())
+	if !ok {
+		return fmt.Errorf("trie node %s not found", hash)
+	}
+
+	w.visited.add(hash)
+
+	if err := w.handler(&SnapshotEntry{Hash: hash, Value: data}); err != nil {
+		return err
+	}
+
+	node, ok, err := GetNode(hash.Bytes(), w.storage)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("trie node %s not found", hash)
+	}
+
+	return w.walkNode(node, accountTrie)
+}
+
+// walkNode visits the children of the node, the nodes embedded in their parent are not stored on their own
+func (w *stateWalker) walkNode(node Node, accountTrie bool) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case *ValueNode:
+		if n.hash {
+			return w.walkHash(types.BytesToHash(n.buf), accountTrie)
+		}
+
+		if accountTrie {
+			return w.walkAccount(n.buf)
+		}
+
+		return nil
+
+	case *ShortNode:
+		return w.walkNode(n.child, accountTrie)
+
+	case *FullNode:
+		for _, child := range n.children {
+			if err := w.walkNode(child, accountTrie); err != nil {
+				return err
+			}
+		}
+
+		return w.walkNode(n.value, accountTrie)
+
+	default:
+		return fmt.Errorf("unknown node type %T", node)
+	}
+}
+
+// walkAccount visits the storage trie and the code of the account
+func (w *stateWalker) walkAccount(data []byte) error {
+	var account state.Account
+	if err := account.UnmarshalRlp(data); err != nil {
+		return err
+	}
+
+	if account.Root != types.EmptyRootHash && account.Root != types.ZeroHash {
+		if err := w.walkHash(account.Root, false); err != nil {
+			return err
+		}
+	}
+
+	if len(account.CodeHash) == 0 || bytes.Equal(account.CodeHash, emptyCodeHash) {
+		return nil
+	}
+
+	codeHash := types.BytesToHash(account.CodeHash)
+	if w.visited.has(codeHash) {
+		return nil
+	}
+
+	code, ok := w.storage.GetCode(codeHash)
+	if !ok {
+		return fmt.Errorf("code %s not found", codeHash)
+	}
+
+	w.visited.add(codeHash)
+
+	return w.handler(&SnapshotEntry{Hash: codeHash, Value: code, Code: true})
+}
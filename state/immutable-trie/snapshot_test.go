@@ -0,0 +1,65 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState_WalkAndWriteSnapshotEntries(t *testing.T) {
+	t.Parallel()
+
+	st := NewState(NewMemoryStorage())
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 50; i++ {
+		addr := types.BytesToAddress([]byte{byte(i + 1)})
+		txn.SetBalance(addr, big.NewInt(int64(i+1)))
+
+		if i%10 == 0 {
+			txn.SetCode(addr, []byte{0x60, byte(i)})
+
+			for j := 0; j < 5; j++ {
+				txn.SetState(addr, types.BytesToHash([]byte{byte(j)}), types.BytesToHash([]byte{byte(i + j + 1)}))
+			}
+		}
+	}
+
+	_, rootBytes := st.NewSnapshot().Commit(txn.Commit(false))
+	root := types.BytesToHash(rootBytes)
+
+	entries := make([]*SnapshotEntry, 0)
+	codes := 0
+
+	assert.NoError(t, st.Walk(root, func(entry *SnapshotEntry) error {
+		entries = append(entries, entry)
+
+		if entry.Code {
+			codes++
+		}
+
+		return nil
+	}))
+	assert.Equal(t, 5, codes)
+
+	// the state is complete once all the entries are written
+	dst := NewState(NewMemoryStorage())
+	assert.Error(t, dst.Walk(root, func(*SnapshotEntry) error { return nil }))
+
+	dst.WriteSnapshotEntries(entries)
+	assert.NoError(t, dst.Walk(root, func(*SnapshotEntry) error { return nil }))
+
+	snap, err := dst.NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	dstTxn := state.NewTxn(dst, snap)
+	addr := types.BytesToAddress([]byte{11})
+
+	assert.Equal(t, big.NewInt(11), dstTxn.GetBalance(addr))
+	assert.Equal(t, []byte{0x60, 10}, dstTxn.GetCode(addr))
+	assert.Equal(t, types.BytesToHash([]byte{13}), dstTxn.GetState(addr, types.BytesToHash([]byte{2})))
+}
@@ -1,9 +1,19 @@
 package itrie
 
 import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
 )
 
 func TestState(t *testing.T) {
@@ -17,3 +27,52 @@ func buildPreState(pre state.PreStates) (state.State, state.Snapshot) {
 
 	return st, snap
 }
+
+func TestWriteGenesisWithFile(t *testing.T) {
+	t.Parallel()
+
+	premined := types.StringToAddress("1")
+	alloc := map[types.Address]*chain.GenesisAccount{
+		premined: {Balance: big.NewInt(1)},
+	}
+
+	// enough accounts to be committed in several chunks
+	fileAlloc := map[types.Address]*chain.GenesisAccount{}
+
+	for i := 0; i < 2500; i++ {
+		fileAlloc[types.BytesToAddress(big.NewInt(int64(i+2)).Bytes())] = &chain.GenesisAccount{
+			Balance: big.NewInt(int64(i)),
+			Nonce:   uint64(i),
+			Code:    []byte{0x1, byte(i)},
+			Storage: map[types.Hash]types.Hash{
+				types.StringToHash("1"): types.StringToHash("2"),
+			},
+		}
+	}
+
+	allocFile := filepath.Join(t.TempDir(), "alloc.json")
+
+	data, err := json.Marshal(fileAlloc)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(allocFile, data, 0600))
+
+	newExecutor := func() *state.Executor {
+		return state.NewExecutor(&chain.Params{}, NewState(NewMemoryStorage()), hclog.NewNullLogger())
+	}
+
+	root, err := newExecutor().WriteGenesisWithFile(alloc, allocFile)
+	require.NoError(t, err)
+
+	// the root is the one of the accounts allocated at once
+	for addr, account := range alloc {
+		fileAlloc[addr] = account
+	}
+
+	assert.Equal(t, newExecutor().WriteGenesis(fileAlloc), root)
+
+	// the accounts can't be allocated by both the genesis and the file
+	_, err = newExecutor().WriteGenesisWithFile(map[types.Address]*chain.GenesisAccount{
+		types.StringToAddress("2"): {Balance: big.NewInt(1)},
+	}, allocFile)
+	assert.Error(t, err)
+}
@@ -2,11 +2,12 @@ package itrie
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/kvdb"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -33,15 +34,14 @@ type Storage interface {
 	Close() error
 }
 
-// KVStorage is a k/v storage on memory using leveldb
+// KVStorage is a k/v storage over a key-value database
 type KVStorage struct {
-	db *leveldb.DB
+	db kvdb.Database
 }
 
-// KVBatch is a batch write for leveldb
+// KVBatch is a batch write for the key-value database
 type KVBatch struct {
-	db    *leveldb.DB
-	batch *leveldb.Batch
+	batch kvdb.Batch
 }
 
 func (b *KVBatch) Put(k, v []byte) {
@@ -49,7 +49,7 @@ func (b *KVBatch) Put(k, v []byte) {
 }
 
 func (b *KVBatch) Write() {
-	_ = b.db.Write(b.batch, nil)
+	_ = b.batch.Write()
 }
 
 func (kv *KVStorage) SetCode(hash types.Hash, code []byte) {
@@ -61,32 +61,38 @@ func (kv *KVStorage) GetCode(hash types.Hash) ([]byte, bool) {
 }
 
 func (kv *KVStorage) Batch() Batch {
-	return &KVBatch{db: kv.db, batch: &leveldb.Batch{}}
+	return &KVBatch{batch: kv.db.NewBatch()}
 }
 
 func (kv *KVStorage) Put(k, v []byte) {
-	_ = kv.db.Put(k, v, nil)
+	_ = kv.db.Put(k, v)
 }
 
 func (kv *KVStorage) Get(k []byte) ([]byte, bool) {
-	data, err := kv.db.Get(k, nil)
+	data, ok, err := kv.db.Get(k)
 	if err != nil {
-		if err.Error() == "leveldb: not found" {
-			return nil, false
-		} else {
-			panic(err)
-		}
+		panic(err)
 	}
 
-	return data, true
+	return data, ok
+}
+
+// Stats returns the statistics of the engine of the database
+func (kv *KVStorage) Stats() (string, error) {
+	return kvdb.Stats(kv.db)
 }
 
 func (kv *KVStorage) Close() error {
 	return kv.db.Close()
 }
 
+// NewKVStorage creates the trie storage over the key-value database
+func NewKVStorage(db kvdb.Database) Storage {
+	return &KVStorage{db}
+}
+
 func NewLevelDBStorage(path string, logger hclog.Logger) (Storage, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	db, err := kvdb.Open(kvdb.LevelDB, path, kvdb.Options{})
 	if err != nil {
 		return nil, err
 	}
@@ -95,12 +101,13 @@ func NewLevelDBStorage(path string, logger hclog.Logger) (Storage, error) {
 }
 
 type memStorage struct {
+	lock sync.RWMutex
 	db   map[string][]byte
 	code map[string][]byte
 }
 
 type memBatch struct {
-	db *map[string][]byte
+	storage *memStorage
 }
 
 // NewMemoryStorage creates an inmemory trie storage
@@ -111,10 +118,17 @@ func NewMemoryStorage() Storage {
 func (m *memStorage) Put(p []byte, v []byte) {
 	buf := make([]byte, len(v))
 	copy(buf[:], v[:])
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	m.db[hex.EncodeToHex(p)] = buf
 }
 
 func (m *memStorage) Get(p []byte) ([]byte, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	v, ok := m.db[hex.EncodeToHex(p)]
 	if !ok {
 		return []byte{}, false
@@ -124,17 +138,23 @@ func (m *memStorage) Get(p []byte) ([]byte, bool) {
 }
 
 func (m *memStorage) SetCode(hash types.Hash, code []byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	m.code[hash.String()] = code
 }
 
 func (m *memStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	code, ok := m.code[hash.String()]
 
 	return code, ok
 }
 
 func (m *memStorage) Batch() Batch {
-	return &memBatch{db: &m.db}
+	return &memBatch{storage: m}
 }
 
 func (m *memStorage) Close() error {
@@ -142,9 +162,7 @@ func (m *memStorage) Close() error {
 }
 
 func (m *memBatch) Put(p, v []byte) {
-	buf := make([]byte, len(v))
-	copy(buf[:], v[:])
-	(*m.db)[hex.EncodeToHex(p)] = buf
+	m.storage.Put(p, v)
 }
 
 func (m *memBatch) Write() {
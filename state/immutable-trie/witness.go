@@ -0,0 +1,156 @@
+package itrie
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// WitnessState is a state whose trie nodes and contract code read from the storage are recorded
+type WitnessState struct {
+	*State
+
+	recorder *WitnessRecorder
+}
+
+// NewWitnessState creates a state over the storage recording the reads. The recent tries cached in memory
+// by the other states aren't shared, so every node of the executions is read from the storage
+func NewWitnessState(storage Storage) *WitnessState {
+	recorder := NewWitnessRecorder(storage)
+
+	return &WitnessState{
+		State:    NewState(recorder),
+		recorder: recorder,
+	}
+}
+
+// Witness returns the trie nodes and the contract code read so far
+func (s *WitnessState) Witness() *state.Witness {
+	return s.recorder.Witness()
+}
+
+// WitnessRecorder is a storage recording the trie nodes and the contract code read from the underlying storage.
+// The writes are kept in memory, so the recorded execution doesn't change the storage, and the nodes written
+// by the execution aren't part of the witness
+type WitnessRecorder struct {
+	storage Storage
+
+	lock    sync.Mutex
+	nodes   map[string][]byte
+	codes   map[types.Hash][]byte
+	written *memStorage
+}
+
+// NewWitnessRecorder records the reads of the storage
+func NewWitnessRecorder(storage Storage) *WitnessRecorder {
+	return &WitnessRecorder{
+		storage: storage,
+		nodes:   map[string][]byte{},
+		codes:   map[types.Hash][]byte{},
+		written: &memStorage{db: map[string][]byte{}, code: map[string][]byte{}},
+	}
+}
+
+func (r *WitnessRecorder) Get(k []byte) ([]byte, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if v, ok := r.written.Get(k); ok {
+		return v, true
+	}
+
+	v, ok := r.storage.Get(k)
+	if ok {
+		r.nodes[string(k)] = v
+	}
+
+	return v, ok
+}
+
+func (r *WitnessRecorder) Put(k, v []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.written.Put(k, v)
+}
+
+func (r *WitnessRecorder) Batch() Batch {
+	return &witnessBatch{recorder: r}
+}
+
+func (r *WitnessRecorder) SetCode(hash types.Hash, code []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.written.SetCode(hash, code)
+}
+
+func (r *WitnessRecorder) GetCode(hash types.Hash) ([]byte, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if code, ok := r.written.GetCode(hash); ok {
+		return code, true
+	}
+
+	code, ok := r.storage.GetCode(hash)
+	if ok {
+		r.codes[hash] = code
+	}
+
+	return code, ok
+}
+
+// Close doesn't close the underlying storage, which is still in use
+func (r *WitnessRecorder) Close() error {
+	return nil
+}
+
+// Witness returns the recorded nodes and code, sorted so the witness of a block is always the same
+func (r *WitnessRecorder) Witness() *state.Witness {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	w := &state.Witness{
+		Nodes: make([][]byte, 0, len(r.nodes)),
+		Codes: make([][]byte, 0, len(r.codes)),
+	}
+
+	for _, node := range r.nodes {
+		w.Nodes = append(w.Nodes, node)
+	}
+
+	for _, code := range r.codes {
+		w.Codes = append(w.Codes, code)
+	}
+
+	sortBytes(w.Nodes)
+	sortBytes(w.Codes)
+
+	return w
+}
+
+func sortBytes(list [][]byte) {
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i], list[j]) < 0
+	})
+}
+
+// witnessBatch writes to the memory of the recorder
+type witnessBatch struct {
+	recorder *WitnessRecorder
+	entries  [][2][]byte
+}
+
+func (b *witnessBatch) Put(k, v []byte) {
+	b.entries = append(b.entries, [2][]byte{append([]byte{}, k...), append([]byte{}, v...)})
+}
+
+func (b *witnessBatch) Write() {
+	for _, entry := range b.entries {
+		b.recorder.Put(entry[0], entry[1])
+	}
+}
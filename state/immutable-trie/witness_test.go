@@ -0,0 +1,103 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestExecutor_Witness(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sender   = types.StringToAddress("1000")
+		contract = types.StringToAddress("2000")
+		ancestor = types.StringToHash("3")
+
+		// stores the hash of the block 0 in the slot 0
+		code = []byte{0x60, 0x00, 0x40, 0x60, 0x00, 0x55, 0x00}
+	)
+
+	newExecutor := func(st state.State) *state.Executor {
+		ex := state.NewExecutor(&chain.Params{Forks: chain.AllForksEnabled}, st, hclog.NewNullLogger())
+		ex.GetHash = func(*types.Header) state.GetHashByNumber {
+			return func(uint64) types.Hash {
+				return ancestor
+			}
+		}
+
+		return ex
+	}
+
+	storage := NewMemoryStorage()
+	ex := newExecutor(NewState(storage))
+
+	parentRoot := ex.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1000)},
+		contract: {
+			Code:    code,
+			Storage: map[types.Hash]types.Hash{types.StringToHash("1"): types.StringToHash("1")},
+		},
+	})
+
+	block := &types.Block{
+		Header: &types.Header{Number: 1, GasLimit: 1_000_000},
+		Transactions: []*types.Transaction{
+			{
+				From:     sender,
+				To:       &contract,
+				Value:    big.NewInt(1),
+				Gas:      100_000,
+				GasPrice: big.NewInt(0),
+			},
+		},
+	}
+
+	transition, err := ex.ProcessBlock(parentRoot, block, types.ZeroAddress)
+	require.NoError(t, err)
+
+	_, block.Header.StateRoot = transition.Commit()
+
+	written := len(storage.(*memStorage).db)
+
+	witness, err := ex.Witness(NewWitnessState(storage), parentRoot, block, types.ZeroAddress)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, witness.Nodes)
+	assert.Equal(t, [][]byte{code}, witness.Codes)
+	assert.Equal(t, map[uint64]types.Hash{0: ancestor}, witness.BlockHashes)
+
+	// the nodes written by the execution are discarded
+	assert.Len(t, storage.(*memStorage).db, written)
+
+	// the block is executed again from the witness alone
+	stateless := NewMemoryStorage()
+
+	for _, node := range witness.Nodes {
+		stateless.Put(crypto.Keccak256(node), node)
+	}
+
+	for _, code := range witness.Codes {
+		stateless.SetCode(types.BytesToHash(crypto.Keccak256(code)), code)
+	}
+
+	transition, err = newExecutor(NewState(stateless)).ProcessBlock(parentRoot, block, types.ZeroAddress)
+	require.NoError(t, err)
+
+	_, root := transition.Commit()
+	assert.Equal(t, block.Header.StateRoot, root)
+
+	// the witness isn't returned if the execution doesn't match the block
+	block.Header.StateRoot = types.StringToHash("4")
+
+	_, err = ex.Witness(NewWitnessState(storage), parentRoot, block, types.ZeroAddress)
+	assert.ErrorIs(t, err, state.ErrWitnessRootMismatch)
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// ErrBelowBaseFee is returned by the transactions whose gas price doesn't cover the base fee of the chain
+var ErrBelowBaseFee = errors.New("gas price below the base fee")
+
+// setNativeToken sets the economics of the native token of the block, nil before the nativeToken fork
+func (t *Transition) setNativeToken(nativeToken *chain.NativeToken) {
+	t.nativeToken = nativeToken
+	t.ctx.BaseFee = 0
+
+	if nativeToken != nil && !t.call {
+		t.ctx.BaseFee = nativeToken.BaseFee
+	}
+}
+
+// baseFee returns the base fee paid by the transactions, zero if there is none
+func (t *Transition) baseFee() *big.Int {
+	return new(big.Int).SetUint64(t.ctx.BaseFee)
+}
+
+// payBaseFee pays the base fee of the gas used to the treasury, or burns it if there is no treasury
+func (t *Transition) payBaseFee(fee *big.Int) {
+	if fee.Sign() == 0 {
+		return
+	}
+
+	if t.nativeToken.Treasury != nil {
+		t.state.AddBalance(*t.nativeToken.Treasury, fee)
+
+		return
+	}
+
+	t.addNativeTokenSupply(precompiled.NativeTokenBurnedSlot, fee)
+}
+
+// addNativeTokenSupply adds the amount to the counter of the native token precompile in the slot
+func (t *Transition) addNativeTokenSupply(slot types.Hash, amount *big.Int) {
+	total := new(big.Int).SetBytes(t.state.GetState(precompiled.NativeTokenAddr, slot).Bytes())
+	total.Add(total, amount)
+
+	t.setPrecompileState(precompiled.NativeTokenAddr, slot, types.BytesToHash(total.Bytes()))
+}
+
+// setPrecompileState sets the slot of the precompile account, which holds a nonce
+// so it isn't removed as an empty account along with its storage
+func (t *Transition) setPrecompileState(addr types.Address, slot, value types.Hash) {
+	if t.state.GetNonce(addr) == 0 {
+		t.state.SetNonce(addr, 1)
+	}
+
+	t.state.SetState(addr, slot, value)
+}
+
+// NativeTokenMinter returns the minter of the native token, false if it can't be minted
+func (t *Transition) NativeTokenMinter() (types.Address, bool) {
+	if t.nativeToken == nil || t.nativeToken.Minter == nil {
+		return types.ZeroAddress, false
+	}
+
+	return *t.nativeToken.Minter, true
+}
+
+// MintNativeToken adds the amount to the balance of the account, and to the minted supply
+func (t *Transition) MintNativeToken(to types.Address, amount *big.Int) {
+	t.state.AddBalance(to, amount)
+	t.addNativeTokenSupply(precompiled.NativeTokenMintedSlot, amount)
+}
+
+// BurnNativeToken removes the amount from the balance of the account, and adds it to the burned supply
+func (t *Transition) BurnNativeToken(from types.Address, amount *big.Int) error {
+	if err := t.state.SubBalance(from, amount); err != nil {
+		return err
+	}
+
+	t.addNativeTokenSupply(precompiled.NativeTokenBurnedSlot, amount)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/armon/go-metrics"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// opcodeStatsBlocks is the number of the latest executed blocks whose opcode stats are kept
+	opcodeStatsBlocks = 256
+)
+
+// OpcodeStatsRecorder records the opcode stats of the blocks processed by the executor.
+// The stats of the last processed block are also published as metrics
+type OpcodeStatsRecorder struct {
+	topK int
+
+	// block hash -> *tracer.OpcodeStats
+	stats *lru.Cache
+
+	lock sync.Mutex
+	// opcodes published by the last block, reset when the next one doesn't execute them
+	published map[string]struct{}
+}
+
+// NewOpcodeStatsRecorder creates a new recorder reporting the topK contracts which consumed the most gas
+func NewOpcodeStatsRecorder(topK int) *OpcodeStatsRecorder {
+	stats, _ := lru.New(opcodeStatsBlocks)
+
+	return &OpcodeStatsRecorder{
+		topK:      topK,
+		stats:     stats,
+		published: make(map[string]struct{}),
+	}
+}
+
+// Get returns the opcode stats of the block with the given hash, if it has been processed recently
+func (r *OpcodeStatsRecorder) Get(hash types.Hash) (*tracer.OpcodeStats, bool) {
+	stats, ok := r.stats.Get(hash)
+	if !ok {
+		return nil, false
+	}
+
+	res, ok := stats.(*tracer.OpcodeStats)
+
+	return res, ok
+}
+
+// record stores the stats collected while processing the block.
+// A block processed more than once (verified, then written) just overwrites its stats
+func (r *OpcodeStatsRecorder) record(header *types.Header, statsTracer *tracer.OpcodeStatsTracer) {
+	stats := statsTracer.Stats(r.topK)
+	stats.Number = header.Number
+	stats.Hash = header.Hash
+
+	r.stats.Add(header.Hash, stats)
+
+	r.publish(stats)
+}
+
+// publish sets the metrics of the opcodes executed in the block
+func (r *OpcodeStatsRecorder) publish(stats *tracer.OpcodeStats) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for name := range r.published {
+		if _, ok := stats.Opcodes[name]; !ok {
+			metrics.SetGaugeWithLabels([]string{"evm_opcodes"}, 0, []metrics.Label{{Name: "opcode", Value: name}})
+		}
+	}
+
+	r.published = make(map[string]struct{}, len(stats.Opcodes))
+
+	for name, count := range stats.Opcodes {
+		metrics.SetGaugeWithLabels([]string{"evm_opcodes"}, float32(count), []metrics.Label{{Name: "opcode", Value: name}})
+
+		r.published[name] = struct{}{}
+	}
+
+	metrics.SetGauge([]string{"evm_total_opcodes"}, float32(stats.TotalOps))
+
+	if len(stats.HotContracts) > 0 {
+		metrics.SetGauge([]string{"evm_hottest_contract_gas"}, float32(stats.HotContracts[0].GasUsed))
+	} else {
+		metrics.SetGauge([]string{"evm_hottest_contract_gas"}, 0)
+	}
+}
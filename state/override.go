@@ -0,0 +1,61 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var ErrOverrideStateAndDiff = errors.New("state and stateDiff can't be overridden at the same time")
+
+// OverrideAccount is the replaced state of an account.
+// A nil field keeps the original value, State replaces the whole storage
+// of the account while StateDiff only replaces the given slots
+type OverrideAccount struct {
+	Nonce     *uint64
+	Code      []byte
+	Balance   *big.Int
+	State     map[types.Hash]types.Hash
+	StateDiff map[types.Hash]types.Hash
+}
+
+// StateOverride maps the accounts to their replaced state
+type StateOverride map[types.Address]OverrideAccount
+
+// Apply replaces the state of the accounts in the transaction state.
+// The replaced state is never committed, it's only seen by the transactions applied afterwards
+func (o StateOverride) Apply(txn *Txn) error {
+	for addr, account := range o {
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s: %w", addr, ErrOverrideStateAndDiff)
+		}
+
+		if account.Nonce != nil {
+			txn.SetNonce(addr, *account.Nonce)
+		}
+
+		if account.Code != nil {
+			txn.SetCode(addr, account.Code)
+		}
+
+		if account.Balance != nil {
+			txn.SetBalance(addr, account.Balance)
+		}
+
+		if account.State != nil {
+			txn.ClearStorage(addr)
+
+			for slot, value := range account.State {
+				txn.SetState(addr, slot, value)
+			}
+		}
+
+		for slot, value := range account.StateDiff {
+			txn.SetState(addr, slot, value)
+		}
+	}
+
+	return nil
+}
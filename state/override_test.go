@@ -0,0 +1,74 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateOverride_Apply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("state diff keeps the other slots", func(t *testing.T) {
+		t.Parallel()
+
+		txn := newTestTxn(map[types.Address]*PreState{
+			addr1: {
+				Nonce:   1,
+				Balance: 10,
+			},
+		})
+		nonce := uint64(5)
+
+		txn.SetState(addr1, hash1, hash1)
+		txn.SetState(addr1, hash2, hash2)
+
+		assert.NoError(t, StateOverride{
+			addr1: {
+				Nonce:     &nonce,
+				Code:      []byte{0x1},
+				Balance:   big.NewInt(100),
+				StateDiff: map[types.Hash]types.Hash{hash1: hash2},
+			},
+		}.Apply(txn))
+
+		assert.Equal(t, nonce, txn.GetNonce(addr1))
+		assert.Equal(t, []byte{0x1}, txn.GetCode(addr1))
+		assert.Equal(t, big.NewInt(100), txn.GetBalance(addr1))
+		assert.Equal(t, hash2, txn.GetState(addr1, hash1))
+		assert.Equal(t, hash2, txn.GetState(addr1, hash2))
+	})
+
+	t.Run("state replaces the whole storage", func(t *testing.T) {
+		t.Parallel()
+
+		txn := newTestTxn(defaultPreState)
+
+		txn.SetState(addr1, hash1, hash1)
+		txn.SetState(addr1, hash2, hash2)
+
+		assert.NoError(t, StateOverride{
+			addr1: {
+				State: map[types.Hash]types.Hash{hash1: hash2},
+			},
+		}.Apply(txn))
+
+		assert.Equal(t, hash2, txn.GetState(addr1, hash1))
+		assert.Equal(t, types.Hash{}, txn.GetState(addr1, hash2))
+	})
+
+	t.Run("state and state diff", func(t *testing.T) {
+		t.Parallel()
+
+		txn := newTestTxn(defaultPreState)
+
+		assert.ErrorIs(t, StateOverride{
+			addr1: {
+				State:     map[types.Hash]types.Hash{},
+				StateDiff: map[types.Hash]types.Hash{},
+			},
+		}.Apply(txn), ErrOverrideStateAndDiff)
+	})
+}
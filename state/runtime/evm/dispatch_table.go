@@ -1,6 +1,11 @@
 package evm
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+)
 
 type handler struct {
 	inst  instruction
@@ -8,7 +13,86 @@ type handler struct {
 	gas   uint64
 }
 
-var dispatchTable [256]handler
+// jumpTable holds the handlers of the instructions, indexed by their opcode
+type jumpTable [256]handler
+
+// dispatchTable is the table of all the instructions, with the gas of the ones repriced by the forks
+// set to their frontier gas. The tables of the forks are built from it
+var dispatchTable jumpTable
+
+// jumpTables caches the jump table of each fork, keyed by the forks in time
+var jumpTables sync.Map
+
+// getJumpTable returns the jump table of the fork, built once
+func getJumpTable(config *chain.ForksInTime) *jumpTable {
+	if table, ok := jumpTables.Load(*config); ok {
+		return table.(*jumpTable) //nolint:forcetypeassert
+	}
+
+	table, _ := jumpTables.LoadOrStore(*config, newJumpTable(config))
+
+	return table.(*jumpTable) //nolint:forcetypeassert
+}
+
+// newJumpTable builds the jump table of the fork, without the instructions which aren't enabled yet
+// and with the base gas of the repriced instructions
+func newJumpTable(config *chain.ForksInTime) *jumpTable {
+	table := dispatchTable
+
+	disable := func(ops ...OpCode) {
+		for _, op := range ops {
+			table[op] = handler{}
+		}
+	}
+
+	if !config.Homestead {
+		disable(DELEGATECALL)
+	}
+
+	if !config.Byzantium {
+		disable(STATICCALL, REVERT, RETURNDATASIZE, RETURNDATACOPY)
+	}
+
+	if !config.Constantinople {
+		disable(SHL, SHR, SAR, EXTCODEHASH, CREATE2)
+	}
+
+	if !config.Istanbul {
+		disable(SELFBALANCE, CHAINID)
+	}
+
+	if !config.EIP3198 {
+		disable(BASEFEE)
+	}
+
+	if !config.EIP3855 {
+		disable(PUSH0)
+	}
+
+	if !config.EIP1153 {
+		disable(TLOAD, TSTORE)
+	}
+
+	if !config.EIP5656 {
+		disable(MCOPY)
+	}
+
+	// eip-150
+	if config.EIP150 {
+		table[SLOAD].gas = 200
+		table[BALANCE].gas = 400
+		table[EXTCODESIZE].gas = 700
+	}
+
+	// eip-1884
+	if config.Istanbul {
+		table[SLOAD].gas = 800
+		table[BALANCE].gas = 700
+		table[EXTCODEHASH].gas = 700
+	}
+
+	return &table
+}
 
 func register(op OpCode, h handler) {
 	if dispatchTable[op].inst != nil {
@@ -38,6 +122,7 @@ func init() {
 	register(SMOD, handler{opSMod, 2, 5})
 	register(EXP, handler{opExp, 2, 10})
 
+	register(PUSH0, handler{opPush0, 0, 2})
 	registerRange(PUSH1, PUSH32, opPush, 3)
 	registerRange(DUP1, DUP16, opDup, 3)
 	registerRange(SWAP1, SWAP16, opSwap, 3)
@@ -83,18 +168,20 @@ func init() {
 	register(MSTORE8, handler{opMStore8, 2, 3})
 
 	// store
-	register(SLOAD, handler{opSload, 1, 0})
+	register(SLOAD, handler{opSload, 1, 50})
 	register(SSTORE, handler{opSStore, 2, 0})
+	register(TLOAD, handler{opTload, 1, 100})
+	register(TSTORE, handler{opTstore, 2, 100})
 
 	register(SHA3, handler{opSha3, 2, 30})
 
 	register(POP, handler{opPop, 1, 2})
 
-	register(EXTCODEHASH, handler{opExtCodeHash, 1, 0})
+	register(EXTCODEHASH, handler{opExtCodeHash, 1, 400})
 
 	// context operations
 	register(ADDRESS, handler{opAddress, 0, 2})
-	register(BALANCE, handler{opBalance, 1, 0})
+	register(BALANCE, handler{opBalance, 1, 20})
 	register(SELFBALANCE, handler{opSelfBalance, 0, 5})
 	register(ORIGIN, handler{opOrigin, 0, 2})
 	register(CALLER, handler{opCaller, 0, 2})
@@ -102,10 +189,11 @@ func init() {
 	register(CALLDATALOAD, handler{opCallDataLoad, 1, 3})
 	register(CALLDATASIZE, handler{opCallDataSize, 0, 2})
 	register(CODESIZE, handler{opCodeSize, 0, 2})
-	register(EXTCODESIZE, handler{opExtCodeSize, 1, 0})
+	register(EXTCODESIZE, handler{opExtCodeSize, 1, 20})
 	register(GASPRICE, handler{opGasPrice, 0, 2})
 	register(RETURNDATASIZE, handler{opReturnDataSize, 0, 2})
 	register(CHAINID, handler{opChainID, 0, 2})
+	register(BASEFEE, handler{opBaseFee, 0, 2})
 	register(PC, handler{opPC, 0, 2})
 	register(MSIZE, handler{opMSize, 0, 2})
 	register(GAS, handler{opGas, 0, 2})
@@ -114,6 +202,7 @@ func init() {
 
 	register(CALLDATACOPY, handler{opCallDataCopy, 3, 3})
 	register(RETURNDATACOPY, handler{opReturnDataCopy, 3, 3})
+	register(MCOPY, handler{opMCopy, 3, 3})
 	register(CODECOPY, handler{opCodeCopy, 3, 3})
 
 	// block information
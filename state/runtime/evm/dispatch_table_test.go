@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/chain"
 )
 
 func TestPushOpcodes(t *testing.T) {
@@ -32,3 +34,36 @@ func TestPushOpcodes(t *testing.T) {
 		c++
 	}
 }
+
+func TestJumpTable_Forks(t *testing.T) {
+	t.Parallel()
+
+	frontier := getJumpTable(&chain.ForksInTime{})
+
+	// the instructions of the later forks aren't available
+	for _, op := range []OpCode{
+		DELEGATECALL, STATICCALL, REVERT, RETURNDATACOPY, SHL, CREATE2, CHAINID,
+		BASEFEE, PUSH0, TLOAD, TSTORE, MCOPY,
+	} {
+		assert.Nil(t, frontier[op].inst, op.String())
+		assert.NotNil(t, getJumpTable(&allEnabledForks)[op].inst, op.String())
+	}
+
+	// the repriced instructions
+	assert.Equal(t, uint64(50), frontier[SLOAD].gas)
+	assert.Equal(t, uint64(200), getJumpTable(&chain.ForksInTime{EIP150: true})[SLOAD].gas)
+	assert.Equal(t, uint64(800), getJumpTable(&allEnabledForks)[SLOAD].gas)
+
+	// the table is built once for each fork
+	assert.Same(t, frontier, getJumpTable(&chain.ForksInTime{}))
+
+	s, closeFn := getState()
+	defer closeFn()
+
+	s.code = []byte{PUSH1, 0x00, PUSH1, 0x00, CREATE2}
+	s.gas = 1000
+	s.table = frontier
+
+	_, err := s.Run()
+	assert.Equal(t, errOpCodeNotFound, err)
+}
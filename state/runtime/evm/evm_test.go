@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
@@ -43,6 +44,14 @@ func (m *mockHost) SetStorage(
 	panic("Not implemented in tests")
 }
 
+func (m *mockHost) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	panic("Not implemented in tests")
+}
+
+func (m *mockHost) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	panic("Not implemented in tests")
+}
+
 func (m *mockHost) GetBalance(addr types.Address) *big.Int {
 	panic("Not implemented in tests")
 }
@@ -87,6 +96,10 @@ func (m *mockHost) GetNonce(addr types.Address) uint64 {
 	panic("Not implemented in tests")
 }
 
+func (m *mockHost) GetTracer() runtime.Tracer {
+	return nil
+}
+
 func TestRun(t *testing.T) {
 	t.Parallel()
 
@@ -169,3 +182,100 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+// erc20Host serves the storage of the ERC-20 token, and drops its logs
+type erc20Host struct {
+	mockHost
+
+	storage map[types.Hash]types.Hash
+}
+
+func (m *erc20Host) GetStorage(addr types.Address, key types.Hash) types.Hash {
+	return m.storage[key]
+}
+
+func (m *erc20Host) SetStorage(
+	addr types.Address,
+	key types.Hash,
+	value types.Hash,
+	config *chain.ForksInTime,
+) runtime.StorageStatus {
+	m.storage[key] = value
+
+	return runtime.StorageModified
+}
+
+func (m *erc20Host) EmitLog(addr types.Address, topics []types.Hash, data []byte) {}
+
+// erc20TransferCode is the runtime code of an ERC-20 token which only implements transfer(address,uint256),
+// the balances are held in the mapping at slot 0 and a Transfer event is emitted the way solc does
+var erc20TransferCode = append(append([]byte{
+	// the selector is transfer, reverting otherwise
+	PUSH1, 0x00, CALLDATALOAD, PUSH1, 0xe0, SHR, PUSH1 + 3, 0xa9, 0x05, 0x9c, 0xbb, EQ, PUSH1, 19, JUMPI,
+	PUSH1, 0x00, DUP1, REVERT,
+	// 19: amount, to
+	JUMPDEST, PUSH1, 0x24, CALLDATALOAD, PUSH1, 0x04, CALLDATALOAD,
+	// the slot of the balance of the caller, which has to cover the amount
+	CALLER, PUSH1, 0x00, MSTORE, PUSH1, 0x00, PUSH1, 0x20, MSTORE, PUSH1, 0x40, PUSH1, 0x00, SHA3,
+	DUP1, SLOAD, DUP1 + 3, DUP1 + 1, LT, PUSH1, 122, JUMPI,
+	DUP1 + 3, SWAP1, SUB, SWAP1, SSTORE,
+	// the balance of the recipient
+	DUP1, PUSH1, 0x00, MSTORE, PUSH1, 0x40, PUSH1, 0x00, SHA3,
+	DUP1, SLOAD, DUP1 + 3, ADD, SWAP1, SSTORE,
+	// Transfer(caller, to, amount)
+	DUP1 + 1, PUSH1, 0x00, MSTORE, CALLER, PUSH32,
+}, crypto.Keccak256([]byte("Transfer(address,address,uint256)"))...), []byte{
+	PUSH1, 0x20, PUSH1, 0x00, LOG3,
+	// return true
+	POP, PUSH1, 0x01, PUSH1, 0x00, MSTORE, PUSH1, 0x20, PUSH1, 0x00, RETURN,
+	// 122: revert
+	JUMPDEST, PUSH1, 0x00, DUP1, REVERT,
+}...)
+
+func BenchmarkRun_ERC20Transfer(b *testing.B) {
+	var (
+		sender    = types.StringToAddress("1")
+		recipient = types.StringToAddress("2")
+		token     = types.StringToAddress("3")
+
+		// the slot of the balance of the account in the mapping at slot 0
+		balanceSlot = func(addr types.Address) types.Hash {
+			return types.BytesToHash(crypto.Keccak256(types.BytesToHash(addr.Bytes()).Bytes(), types.ZeroHash.Bytes()))
+		}
+
+		config = &chain.ForksInTime{
+			Homestead: true, EIP150: true, EIP155: true, EIP158: true,
+			Byzantium: true, Constantinople: true, Petersburg: true, Istanbul: true,
+		}
+	)
+
+	input := append(
+		types.BytesToHash([]byte{0xa9, 0x05, 0x9c, 0xbb}).Bytes()[28:],
+		append(types.BytesToHash(recipient.Bytes()).Bytes(), types.BytesToHash([]byte{0x01}).Bytes()...)...,
+	)
+
+	host := &erc20Host{storage: map[types.Hash]types.Hash{
+		balanceSlot(sender): types.BytesToHash(big.NewInt(1_000_000_000_000).Bytes()),
+	}}
+
+	evm := NewEVM()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		contract := runtime.NewContract(1, sender, sender, token, big.NewInt(0), 100_000, erc20TransferCode)
+		contract.Input = input
+
+		if res := evm.Run(contract, host, config); res.Err != nil || len(res.ReturnValue) != 32 || res.ReturnValue[31] != 1 {
+			b.Fatalf("transfer failed: %v", res.Err)
+		}
+	}
+
+	b.StopTimer()
+
+	received := new(big.Int).SetBytes(host.storage[balanceSlot(recipient)].Bytes())
+	if received.Cmp(big.NewInt(int64(b.N))) != 0 {
+		b.Fatalf("the recipient received %s, expected %d", received, b.N)
+	}
+}
@@ -4,10 +4,12 @@ package evm
 import (
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
-	"math/bits"
 	"sync"
 
+	"github.com/holiman/uint256"
+
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
@@ -17,9 +19,9 @@ import (
 type instruction func(c *state)
 
 var (
-	zero     = big.NewInt(0)
-	one      = big.NewInt(1)
-	wordSize = big.NewInt(32)
+	zero     = uint256.NewInt(0)
+	one      = uint256.NewInt(1)
+	wordSize = uint256.NewInt(32)
 )
 
 func opAdd(c *state) {
@@ -27,7 +29,6 @@ func opAdd(c *state) {
 	b := c.top()
 
 	b.Add(a, b)
-	toU256(b)
 }
 
 func opMul(c *state) {
@@ -35,7 +36,6 @@ func opMul(c *state) {
 	b := c.top()
 
 	b.Mul(a, b)
-	toU256(b)
 }
 
 func opSub(c *state) {
@@ -43,81 +43,36 @@ func opSub(c *state) {
 	b := c.top()
 
 	b.Sub(a, b)
-	toU256(b)
 }
 
+// the divisions by zero result in zero
+
 func opDiv(c *state) {
 	a := c.pop()
 	b := c.top()
 
-	if b.Sign() == 0 {
-		// division by zero
-		b.Set(zero)
-	} else {
-		b.Div(a, b)
-		toU256(b)
-	}
+	b.Div(a, b)
 }
 
 func opSDiv(c *state) {
-	a := to256(c.pop())
-	b := to256(c.top())
+	a := c.pop()
+	b := c.top()
 
-	if b.Sign() == 0 {
-		// division by zero
-		b.Set(zero)
-	} else {
-		neg := a.Sign() != b.Sign()
-		b.Div(a.Abs(a), b.Abs(b))
-		if neg {
-			b.Neg(b)
-		}
-		toU256(b)
-	}
+	b.SDiv(a, b)
 }
 
 func opMod(c *state) {
 	a := c.pop()
 	b := c.top()
 
-	if b.Sign() == 0 {
-		// division by zero
-		b.Set(zero)
-	} else {
-		b.Mod(a, b)
-		toU256(b)
-	}
+	b.Mod(a, b)
 }
 
 func opSMod(c *state) {
-	a := to256(c.pop())
-	b := to256(c.top())
-
-	if b.Sign() == 0 {
-		// division by zero
-		b.Set(zero)
-	} else {
-		neg := a.Sign() < 0
-		b.Mod(a.Abs(a), b.Abs(b))
-		if neg {
-			b.Neg(b)
-		}
-		toU256(b)
-	}
-}
-
-var bigPool = sync.Pool{
-	New: func() interface{} {
-		return new(big.Int)
-	},
-}
-
-func acquireBig() *big.Int {
-	return bigPool.Get().(*big.Int)
-}
+	a := c.pop()
+	b := c.top()
 
-func releaseBig(b *big.Int) {
-	bigPool.Put(b)
+	b.SMod(a, b)
 }
 
 func opExp(c *state) {
@@ -136,23 +91,7 @@ func opExp(c *state) {
 		return
 	}
 
-	z := acquireBig().Set(one)
-
-	// https://www.programminglogic.com/fast-exponentiation-algorithms/
-	for _, d := range y.Bits() {
-		for i := 0; i < _W; i++ {
-			if d&1 == 1 {
-				toU256(z.Mul(z, x))
-			}
-
-			d >>= 1
-
-			toU256(x.Mul(x, x))
-		}
-	}
-
-	y.Set(z)
-	releaseBig(z)
+	y.Exp(x, y)
 }
 
 func opAddMod(c *state) {
@@ -160,14 +99,7 @@ func opAddMod(c *state) {
 	b := c.pop()
 	z := c.top()
 
-	if z.Sign() == 0 {
-		// division by zero
-		z.Set(zero)
-	} else {
-		a = a.Add(a, b)
-		z = z.Mod(a, z)
-		toU256(z)
-	}
+	z.AddMod(a, b, z)
 }
 
 func opMulMod(c *state) {
@@ -175,14 +107,7 @@ func opMulMod(c *state) {
 	b := c.pop()
 	z := c.top()
 
-	if z.Sign() == 0 {
-		// division by zero
-		z.Set(zero)
-	} else {
-		a = a.Mul(a, b)
-		z = z.Mod(a, z)
-		toU256(z)
-	}
+	z.MulMod(a, b, z)
 }
 
 func opAnd(c *state) {
@@ -206,183 +131,117 @@ func opXor(c *state) {
 	b.Xor(a, b)
 }
 
-var opByteMask = big.NewInt(255)
-
 func opByte(c *state) {
 	x := c.pop()
 	y := c.top()
 
-	indx := x.Int64()
-	if indx > 31 {
-		y.Set(zero)
-	} else {
-		sh := (31 - indx) * 8
-		y.Rsh(y, uint(sh))
-		y.And(y, opByteMask)
-	}
+	y.Byte(x)
 }
 
 func opNot(c *state) {
 	a := c.top()
 
 	a.Not(a)
-	toU256(a)
+}
+
+// setBool sets the item to one if the condition holds, to zero otherwise
+func setBool(v *uint256.Int, cond bool) {
+	if cond {
+		v.SetOne()
+	} else {
+		v.Clear()
+	}
 }
 
 func opIsZero(c *state) {
 	a := c.top()
 
-	if a.Sign() == 0 {
-		a.Set(one)
-	} else {
-		a.Set(zero)
-	}
+	setBool(a, a.IsZero())
 }
 
 func opEq(c *state) {
 	a := c.pop()
 	b := c.top()
 
-	if a.Cmp(b) == 0 {
-		b.Set(one)
-	} else {
-		b.Set(zero)
-	}
+	setBool(b, a.Eq(b))
 }
 
 func opLt(c *state) {
 	a := c.pop()
 	b := c.top()
 
-	if a.Cmp(b) < 0 {
-		b.Set(one)
-	} else {
-		b.Set(zero)
-	}
+	setBool(b, a.Lt(b))
 }
 
 func opGt(c *state) {
 	a := c.pop()
 	b := c.top()
 
-	if a.Cmp(b) > 0 {
-		b.Set(one)
-	} else {
-		b.Set(zero)
-	}
+	setBool(b, a.Gt(b))
 }
 
 func opSlt(c *state) {
-	a := to256(c.pop())
-	b := to256(c.top())
+	a := c.pop()
+	b := c.top()
 
-	if a.Cmp(b) < 0 {
-		b.Set(one)
-	} else {
-		b.Set(zero)
-	}
+	setBool(b, a.Slt(b))
 }
 
 func opSgt(c *state) {
-	a := to256(c.pop())
-	b := to256(c.top())
+	a := c.pop()
+	b := c.top()
 
-	if a.Cmp(b) > 0 {
-		b.Set(one)
-	} else {
-		b.Set(zero)
-	}
+	setBool(b, a.Sgt(b))
 }
 
 func opSignExtension(c *state) {
 	ext := c.pop()
 	x := c.top()
 
-	if ext.Cmp(wordSize) > 0 {
-		return
-	}
-
 	if x == nil {
 		return
 	}
 
-	bit := uint(ext.Uint64()*8 + 7)
-
-	mask := acquireBig().Set(one)
-	mask.Lsh(mask, bit)
-	mask.Sub(mask, one)
-
-	if x.Bit(int(bit)) > 0 {
-		mask.Not(mask)
-		x.Or(x, mask)
-	} else {
-		x.And(x, mask)
-	}
-
-	toU256(x)
-	releaseBig(mask)
-}
-
-func equalOrOverflowsUint256(b *big.Int) bool {
-	return b.BitLen() > 8
+	x.ExtendSign(x, ext)
 }
 
 func opShl(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-
-		return
-	}
-
 	shift := c.pop()
 	value := c.top()
 
-	if equalOrOverflowsUint256(shift) {
-		value.Set(zero)
-	} else {
+	if shift.LtUint64(256) {
 		value.Lsh(value, uint(shift.Uint64()))
-		toU256(value)
+	} else {
+		value.Clear()
 	}
 }
 
 func opShr(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-
-		return
-	}
-
 	shift := c.pop()
 	value := c.top()
 
-	if equalOrOverflowsUint256(shift) {
-		value.Set(zero)
-	} else {
+	if shift.LtUint64(256) {
 		value.Rsh(value, uint(shift.Uint64()))
-		toU256(value)
+	} else {
+		value.Clear()
 	}
 }
 
 func opSar(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-
-		return
-	}
-
 	shift := c.pop()
-	value := to256(c.top())
+	value := c.top()
 
-	if equalOrOverflowsUint256(shift) {
+	if shift.GtUint64(255) {
 		if value.Sign() >= 0 {
-			value.Set(zero)
+			value.Clear()
 		} else {
-			value.Set(tt256m1)
+			value.SetAllOne()
 		}
-	} else {
-		value.Rsh(value, uint(shift.Uint64()))
-		toU256(value)
+
+		return
 	}
+
+	value.SRsh(value, uint(shift.Uint64()))
 }
 
 // memory operations
@@ -410,11 +269,6 @@ func opMload(c *state) {
 	c.push1().SetBytes(c.tmp)
 }
 
-var (
-	_W = bits.UintSize
-	_S = _W / 8
-)
-
 func opMStore(c *state) {
 	offset := c.pop()
 	val := c.pop()
@@ -424,28 +278,8 @@ func opMStore(c *state) {
 	}
 
 	o := offset.Uint64()
-	buf := c.memory[o : o+32]
-
-	i := 32
-
-	// convert big.int to bytes
-	// https://golang.org/src/math/big/nat.go#L1284
-	for _, d := range val.Bits() {
-		for j := 0; j < _S; j++ {
-			i--
-
-			buf[i] = byte(d)
-
-			d >>= 8
-		}
-	}
-
-	// fill the rest of the slot with zeros
-	for i > 0 {
-		i--
-
-		buf[i] = 0
-	}
+	word := val.Bytes32()
+	copy(c.memory[o:o+32], word[:])
 }
 
 func opMStore8(c *state) {
@@ -464,22 +298,28 @@ func opMStore8(c *state) {
 func opSload(c *state) {
 	loc := c.top()
 
-	var gas uint64
-	if c.config.Istanbul {
-		// eip-1884
-		gas = 800
-	} else if c.config.EIP150 {
-		gas = 200
-	} else {
-		gas = 50
-	}
+	val := c.host.GetStorage(c.msg.Address, uint256ToHash(loc))
+	loc.SetBytes32(val.Bytes())
+}
+
+func opTload(c *state) {
+	loc := c.top()
+
+	val := c.host.GetTransientStorage(c.msg.Address, uint256ToHash(loc))
+	loc.SetBytes32(val.Bytes())
+}
+
+func opTstore(c *state) {
+	if c.inStaticCall() {
+		c.exit(errWriteProtection)
 
-	if !c.consumeGas(gas) {
 		return
 	}
 
-	val := c.host.GetStorage(c.msg.Address, bigToHash(loc))
-	loc.SetBytes(val.Bytes())
+	key := c.popHash()
+	val := c.popHash()
+
+	c.host.SetTransientStorage(c.msg.Address, key, val)
 }
 
 func opSStore(c *state) {
@@ -557,8 +397,7 @@ func opSha3(c *state) {
 
 	c.tmp = keccak.Keccak256(c.tmp[:0], c.tmp)
 
-	v := c.push1()
-	v.SetBytes(c.tmp)
+	c.push1().SetBytes32(c.tmp)
 }
 
 func opPop(c *state) {
@@ -574,40 +413,19 @@ func opAddress(c *state) {
 func opBalance(c *state) {
 	addr, _ := c.popAddr()
 
-	var gas uint64
-	if c.config.Istanbul {
-		// eip-1884
-		gas = 700
-	} else if c.config.EIP150 {
-		gas = 400
-	} else {
-		gas = 20
-	}
-
-	if !c.consumeGas(gas) {
-		return
-	}
-
-	c.push1().Set(c.host.GetBalance(addr))
+	c.push1().SetFromBig(c.host.GetBalance(addr))
 }
 
 func opSelfBalance(c *state) {
-	if !c.config.Istanbul {
-		c.exit(errOpCodeNotFound)
-
-		return
-	}
+	c.push1().SetFromBig(c.host.GetBalance(c.msg.Address))
+}
 
-	c.push1().Set(c.host.GetBalance(c.msg.Address))
+func opBaseFee(c *state) {
+	// there is no fee market, the base fee is the one of the chain from the nativeToken fork, zero before
+	c.push1().SetUint64(c.host.GetTxContext().BaseFee)
 }
 
 func opChainID(c *state) {
-	if !c.config.Istanbul {
-		c.exit(errOpCodeNotFound)
-
-		return
-	}
-
 	c.push1().SetUint64(uint64(c.host.GetTxContext().ChainID))
 }
 
@@ -622,9 +440,9 @@ func opCaller(c *state) {
 func opCallValue(c *state) {
 	v := c.push1()
 	if value := c.msg.Value; value != nil {
-		v.Set(value)
+		v.SetFromBig(value)
 	} else {
-		v.Set(zero)
+		v.Clear()
 	}
 }
 
@@ -657,17 +475,6 @@ func opCodeSize(c *state) {
 func opExtCodeSize(c *state) {
 	addr, _ := c.popAddr()
 
-	var gas uint64
-	if c.config.EIP150 {
-		gas = 700
-	} else {
-		gas = 20
-	}
-
-	if !c.consumeGas(gas) {
-		return
-	}
-
 	c.push1().SetUint64(uint64(c.host.GetCodeSize(addr)))
 }
 
@@ -676,38 +483,17 @@ func opGasPrice(c *state) {
 }
 
 func opReturnDataSize(c *state) {
-	if !c.config.Byzantium {
-		c.exit(errOpCodeNotFound)
-	} else {
-		c.push1().SetUint64(uint64(len(c.returnData)))
-	}
+	c.push1().SetUint64(uint64(len(c.returnData)))
 }
 
 func opExtCodeHash(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-
-		return
-	}
-
 	address, _ := c.popAddr()
 
-	var gas uint64
-	if c.config.Istanbul {
-		gas = 700
-	} else {
-		gas = 400
-	}
-
-	if !c.consumeGas(gas) {
-		return
-	}
-
 	v := c.push1()
 	if c.host.Empty(address) {
-		v.Set(zero)
+		v.Clear()
 	} else {
-		v.SetBytes(c.host.GetCodeHash(address).Bytes())
+		v.SetBytes32(c.host.GetCodeHash(address).Bytes())
 	}
 }
 
@@ -723,7 +509,7 @@ func opGas(c *state) {
 	c.push1().SetUint64(c.gas)
 }
 
-func (c *state) setBytes(dst, input []byte, size uint64, dataOffset *big.Int) {
+func (c *state) setBytes(dst, input []byte, size uint64, dataOffset *uint256.Int) {
 	if !dataOffset.IsUint64() {
 		// overflow, copy 'size' 0 bytes to dst
 		for i := uint64(0); i < size; i++ {
@@ -802,13 +588,27 @@ func opCallDataCopy(c *state) {
 	}
 }
 
-func opReturnDataCopy(c *state) {
-	if !c.config.Byzantium {
-		c.exit(errOpCodeNotFound)
+func opMCopy(c *state) {
+	dst := c.pop()
+	src := c.pop()
+	length := c.pop()
+
+	if !c.allocateMemory(dst, length) || !c.allocateMemory(src, length) {
+		return
+	}
 
+	size := length.Uint64()
+	if !c.consumeGas(((size + 31) / 32) * copyGas) {
 		return
 	}
 
+	if size != 0 {
+		from := src.Uint64()
+		copy(c.memory[dst.Uint64():], c.memory[from:from+size])
+	}
+}
+
+func opReturnDataCopy(c *state) {
 	memOffset := c.pop()
 	dataOffset := c.pop()
 	length := c.pop()
@@ -827,8 +627,8 @@ func opReturnDataCopy(c *state) {
 		return
 	}
 
-	dataEnd := length.Add(dataOffset, length)
-	if !dataEnd.IsUint64() {
+	dataEnd, overflow := length.AddOverflow(dataOffset, length)
+	if overflow || !dataEnd.IsUint64() {
 		c.exit(errReturnDataOutOfBounds)
 
 		return
@@ -869,19 +669,20 @@ func opCodeCopy(c *state) {
 func opBlockHash(c *state) {
 	num := c.top()
 
-	if !num.IsInt64() {
-		num.Set(zero)
+	number, overflow := num.Uint64WithOverflow()
+	if overflow || number > math.MaxInt64 {
+		num.Clear()
 
 		return
 	}
 
-	n := num.Int64()
+	n := int64(number)
 	lastBlock := c.host.GetTxContext().Number
 
 	if lastBlock-257 < n && n < lastBlock {
-		num.SetBytes(c.host.GetBlockHash(n).Bytes())
+		num.SetBytes32(c.host.GetBlockHash(n).Bytes())
 	} else {
-		num.Set(zero)
+		num.Clear()
 	}
 }
 
@@ -890,11 +691,11 @@ func opCoinbase(c *state) {
 }
 
 func opTimestamp(c *state) {
-	c.push1().SetInt64(c.host.GetTxContext().Timestamp)
+	c.push1().SetUint64(uint64(c.host.GetTxContext().Timestamp))
 }
 
 func opNumber(c *state) {
-	c.push1().SetInt64(c.host.GetTxContext().Number)
+	c.push1().SetUint64(uint64(c.host.GetTxContext().Number))
 }
 
 func opDifficulty(c *state) {
@@ -902,7 +703,7 @@ func opDifficulty(c *state) {
 }
 
 func opGasLimit(c *state) {
-	c.push1().SetInt64(c.host.GetTxContext().GasLimit)
+	c.push1().SetUint64(uint64(c.host.GetTxContext().GasLimit))
 }
 
 func opSelfDestruct(c *state) {
@@ -951,7 +752,7 @@ func opJumpi(c *state) {
 	dest := c.pop()
 	cond := c.pop()
 
-	if cond.Sign() != 0 {
+	if !cond.IsZero() {
 		if c.validJumpdest(dest) {
 			c.ip = int(dest.Uint64() - 1)
 		} else {
@@ -963,6 +764,10 @@ func opJumpi(c *state) {
 func opJumpDest(c *state) {
 }
 
+func opPush0(c *state) {
+	c.push1().Clear()
+}
+
 func opPush(n int) instruction {
 	return func(c *state) {
 		ins := c.code
@@ -971,6 +776,8 @@ func opPush(n int) instruction {
 		v := c.push1()
 		if ip+1+n > len(ins) {
 			v.SetBytes(append(ins[ip+1:], make([]byte, n)...))
+		} else if n == 1 {
+			v.SetUint64(uint64(ins[ip+1]))
 		} else {
 			v.SetBytes(ins[ip+1 : ip+1+n])
 		}
@@ -1021,7 +828,7 @@ func opLog(size int) instruction {
 
 		topics := make([]types.Hash, size)
 		for i := 0; i < size; i++ {
-			topics[i] = bigToHash(c.pop())
+			topics[i] = c.popHash()
 		}
 
 		var ok bool
@@ -1055,20 +862,12 @@ func opCreate(op OpCode) instruction {
 			return
 		}
 
-		if op == CREATE2 {
-			if !c.config.Constantinople {
-				c.exit(errOpCodeNotFound)
-
-				return
-			}
-		}
-
 		// reset the return data
 		c.resetReturnData()
 
 		contract, err := c.buildCreateContract(op)
 		if err != nil {
-			c.push1().Set(zero)
+			c.push1().Clear()
 
 			if contract != nil {
 				c.gas += contract.Gas
@@ -1088,11 +887,11 @@ func opCreate(op OpCode) instruction {
 
 		v := c.push1()
 		if op == CREATE && c.config.Homestead && errors.Is(result.Err, runtime.ErrCodeStoreOutOfGas) {
-			v.Set(zero)
+			v.Clear()
 		} else if result.Failed() && !errors.Is(result.Err, runtime.ErrCodeStoreOutOfGas) {
-			v.Set(zero)
+			v.Clear()
 		} else {
-			v.SetBytes(contract.Address.Bytes())
+			v.SetBytes20(contract.Address.Bytes())
 		}
 
 		c.gas += result.GasLeft
@@ -1108,25 +907,13 @@ func opCall(op OpCode) instruction {
 		c.resetReturnData()
 
 		if op == CALL && c.inStaticCall() {
-			if val := c.peekAt(3); val != nil && val.BitLen() > 0 {
+			if val := c.peekAt(3); !val.IsZero() {
 				c.exit(errWriteProtection)
 
 				return
 			}
 		}
 
-		if op == DELEGATECALL && !c.config.Homestead {
-			c.exit(errOpCodeNotFound)
-
-			return
-		}
-
-		if op == STATICCALL && !c.config.Byzantium {
-			c.exit(errOpCodeNotFound)
-
-			return
-		}
-
 		var callType runtime.CallType
 
 		switch op {
@@ -1148,7 +935,7 @@ func opCall(op OpCode) instruction {
 
 		contract, offset, size, err := c.buildCallContract(op)
 		if err != nil {
-			c.push1().Set(zero)
+			c.push1().Clear()
 
 			if contract != nil {
 				c.gas += contract.Gas
@@ -1165,12 +952,7 @@ func opCall(op OpCode) instruction {
 
 		result := c.host.Callx(contract, c.host)
 
-		v := c.push1()
-		if result.Succeeded() {
-			v.Set(one)
-		} else {
-			v.Set(zero)
-		}
+		setBool(c.push1(), result.Succeeded())
 
 		if result.Succeeded() || result.Reverted() {
 			if len(result.ReturnValue) != 0 {
@@ -1190,7 +972,7 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 
 	var value *big.Int
 	if op == CALL || op == CALLCODE {
-		value = c.pop()
+		value = c.pop().ToBig()
 	}
 
 	// input range
@@ -1304,13 +1086,13 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 
 func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 	// Pop input arguments
-	value := c.pop()
+	value := c.pop().ToBig()
 	offset := c.pop()
 	length := c.pop()
 
-	var salt *big.Int
+	var salt types.Hash
 	if op == CREATE2 {
-		salt = c.pop()
+		salt = c.popHash()
 	}
 
 	// check if the value can be transferred
@@ -1367,7 +1149,7 @@ func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 	if op == CREATE {
 		address = crypto.CreateAddress(c.msg.Address, c.host.GetNonce(c.msg.Address))
 	} else {
-		address = crypto.CreateAddress2(c.msg.Address, bigToHash(salt), input)
+		address = crypto.CreateAddress2(c.msg.Address, salt, input)
 	}
 
 	contract := runtime.NewContractCreation(c.msg.Depth+1, c.msg.Origin, c.msg.Address, address, value, gas, input)
@@ -1377,12 +1159,6 @@ func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 
 func opHalt(op OpCode) instruction {
 	return func(c *state) {
-		if op == REVERT && !c.config.Byzantium {
-			c.exit(errOpCodeNotFound)
-
-			return
-		}
-
 		offset := c.pop()
 		size := c.pop()
 
@@ -1400,24 +1176,3 @@ func opHalt(op OpCode) instruction {
 		}
 	}
 }
-
-var (
-	tt256   = new(big.Int).Lsh(big.NewInt(1), 256)   // 2 ** 256
-	tt256m1 = new(big.Int).Sub(tt256, big.NewInt(1)) // 2 ** 256 - 1
-)
-
-func toU256(x *big.Int) *big.Int {
-	if x.Sign() < 0 || x.BitLen() > 256 {
-		x.And(x, tt256m1)
-	}
-
-	return x
-}
-
-func to256(x *big.Int) *big.Int {
-	if x.BitLen() > 255 {
-		x.Sub(x, tt256)
-	}
-
-	return x
-}
@@ -1,26 +1,31 @@
 package evm
 
 import (
+	"encoding/hex"
 	"math/big"
 	"testing"
 
+	"github.com/holiman/uint256"
+
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
-	two = big.NewInt(2)
+	two        = uint256.NewInt(2)
+	maxUint256 = new(uint256.Int).SetAllOne()
 
-	allEnabledForks = chain.AllForksEnabled.At(0)
+	allEnabledForks = chain.AllForksEnabled.At(0, 0)
 )
 
 type cases2To1 []struct {
-	a *big.Int
-	b *big.Int
-	c *big.Int
+	a *uint256.Int
+	b *uint256.Int
+	c *uint256.Int
 }
 
 func test2to1(t *testing.T, f instruction, tests cases2To1) {
@@ -40,8 +45,8 @@ func test2to1(t *testing.T, f instruction, tests cases2To1) {
 }
 
 type cases2ToBool []struct {
-	a *big.Int
-	b *big.Int
+	a *uint256.Int
+	b *uint256.Int
 	c bool
 }
 
@@ -92,8 +97,8 @@ func TestMStore(t *testing.T) {
 	s, closeFn := getState()
 	defer closeFn()
 
-	s.push(big.NewInt(10))   // value
-	s.push(big.NewInt(1024)) // offset
+	s.push(uint256.NewInt(10))   // value
+	s.push(uint256.NewInt(1024)) // offset
 
 	s.gas = 1000
 	opMStore(s)
@@ -123,14 +128,14 @@ func TestCreate(t *testing.T) {
 	type state struct {
 		gas    uint64
 		sp     int
-		stack  []*big.Int
+		stack  []uint256.Int
 		memory []byte
 		stop   bool
 		err    error
 	}
 
-	addressToBigInt := func(addr types.Address) *big.Int {
-		return new(big.Int).SetBytes(addr[:])
+	addressToUint256 := func(addr types.Address) uint256.Int {
+		return *new(uint256.Int).SetBytes(addr[:])
 	}
 
 	tests := []struct {
@@ -153,10 +158,10 @@ func TestCreate(t *testing.T) {
 			initState: &state{
 				gas: 1000,
 				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
+				stack: []uint256.Int{
+					*uint256.NewInt(0x01), // length
+					*uint256.NewInt(0x00), // offset
+					*uint256.NewInt(0x00), // value
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -165,10 +170,10 @@ func TestCreate(t *testing.T) {
 			resultState: &state{
 				gas: 500,
 				sp:  1,
-				stack: []*big.Int{
-					addressToBigInt(crypto.CreateAddress(addr1, 0)), // contract address
-					big.NewInt(0x00),
-					big.NewInt(0x00),
+				stack: []uint256.Int{
+					addressToUint256(crypto.CreateAddress(addr1, 0)), // contract address
+					*uint256.NewInt(0x00),
+					*uint256.NewInt(0x00),
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -192,10 +197,10 @@ func TestCreate(t *testing.T) {
 			initState: &state{
 				gas: 1000,
 				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
+				stack: []uint256.Int{
+					*uint256.NewInt(0x01), // length
+					*uint256.NewInt(0x00), // offset
+					*uint256.NewInt(0x00), // value
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -207,10 +212,10 @@ func TestCreate(t *testing.T) {
 			resultState: &state{
 				gas: 1000,
 				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
+				stack: []uint256.Int{
+					*uint256.NewInt(0x01), // length
+					*uint256.NewInt(0x00), // offset
+					*uint256.NewInt(0x00), // value
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -220,44 +225,6 @@ func TestCreate(t *testing.T) {
 			},
 			mockHost: &mockHostForCreate{},
 		},
-		{
-			name:     "should throw errOpCodeNotFound when op is CREATE2 and config.Constantinople is disabled",
-			op:       CREATE2,
-			contract: &runtime.Contract{},
-			config: &chain.ForksInTime{
-				Constantinople: false,
-			},
-			initState: &state{
-				gas: 1000,
-				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
-				},
-				memory: []byte{
-					byte(REVERT),
-				},
-				stop: false,
-				err:  nil,
-			},
-			// shouldn't change any states except for stop and err
-			resultState: &state{
-				gas: 1000,
-				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
-				},
-				memory: []byte{
-					byte(REVERT),
-				},
-				stop: true,
-				err:  errOpCodeNotFound,
-			},
-			mockHost: &mockHostForCreate{},
-		},
 		{
 			name: "should set zero address if op is CREATE and contract call throws ErrCodeStoreOutOfGas",
 			op:   CREATE,
@@ -271,10 +238,10 @@ func TestCreate(t *testing.T) {
 			initState: &state{
 				gas: 1000,
 				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
+				stack: []uint256.Int{
+					*uint256.NewInt(0x01), // length
+					*uint256.NewInt(0x00), // offset
+					*uint256.NewInt(0x00), // value
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -286,11 +253,10 @@ func TestCreate(t *testing.T) {
 			resultState: &state{
 				gas: 1000,
 				sp:  1,
-				stack: []*big.Int{
-					// need to init with 0x01 to add abs field in big.Int
-					big.NewInt(0x01).SetInt64(0x00),
-					big.NewInt(0x00),
-					big.NewInt(0x00),
+				stack: []uint256.Int{
+					*uint256.NewInt(0x00),
+					*uint256.NewInt(0x00),
+					*uint256.NewInt(0x00),
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -319,10 +285,10 @@ func TestCreate(t *testing.T) {
 			initState: &state{
 				gas: 1000,
 				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
+				stack: []uint256.Int{
+					*uint256.NewInt(0x01), // length
+					*uint256.NewInt(0x00), // offset
+					*uint256.NewInt(0x00), // value
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -334,11 +300,10 @@ func TestCreate(t *testing.T) {
 			resultState: &state{
 				gas: 1000,
 				sp:  1,
-				stack: []*big.Int{
-					// need to init with 0x01 to add abs field in big.Int
-					big.NewInt(0x01).SetInt64(0x00),
-					big.NewInt(0x00),
-					big.NewInt(0x00),
+				stack: []uint256.Int{
+					*uint256.NewInt(0x00),
+					*uint256.NewInt(0x00),
+					*uint256.NewInt(0x00),
 				},
 				memory: []byte{
 					byte(REVERT),
@@ -391,36 +356,22 @@ func Test_opReturnDataCopy(t *testing.T) {
 		resultState *state
 	}{
 		{
-			name: "should return error if Byzantium is not applied",
-			config: &chain.ForksInTime{
-				Byzantium: false,
-			},
-			initState: &state{},
-			resultState: &state{
-				config: &chain.ForksInTime{
-					Byzantium: false,
-				},
-				stop: true,
-				err:  errOpCodeNotFound,
-			},
-		},
-		{
-			name:   "should return error if memOffset is negative",
+			name:   "should return error if memOffset overflows uint64",
 			config: &allEnabledForks,
 			initState: &state{
-				stack: []*big.Int{
-					big.NewInt(0),  // length
-					big.NewInt(0),  // dataOffset
-					big.NewInt(-1), // memOffset
+				stack: []uint256.Int{
+					*uint256.NewInt(0), // length
+					*uint256.NewInt(0), // dataOffset
+					*maxUint256,        // memOffset
 				},
 				sp: 3,
 			},
 			resultState: &state{
 				config: &allEnabledForks,
-				stack: []*big.Int{
-					big.NewInt(0),
-					big.NewInt(0),
-					big.NewInt(-1),
+				stack: []uint256.Int{
+					*uint256.NewInt(0),
+					*uint256.NewInt(0),
+					*maxUint256,
 				},
 				sp:   0,
 				stop: true,
@@ -428,23 +379,23 @@ func Test_opReturnDataCopy(t *testing.T) {
 			},
 		},
 		{
-			name:   "should return error if dataOffset is negative",
+			name:   "should return error if dataOffset overflows uint64",
 			config: &allEnabledForks,
 			initState: &state{
-				stack: []*big.Int{
-					big.NewInt(1),  // length
-					big.NewInt(-1), // dataOffset
-					big.NewInt(0),  // memOffset
+				stack: []uint256.Int{
+					*uint256.NewInt(1), // length
+					*maxUint256,        // dataOffset
+					*uint256.NewInt(0), // memOffset
 				},
 				sp:     3,
 				memory: make([]byte, 1),
 			},
 			resultState: &state{
 				config: &allEnabledForks,
-				stack: []*big.Int{
-					big.NewInt(1),
-					big.NewInt(-1),
-					big.NewInt(0),
+				stack: []uint256.Int{
+					*uint256.NewInt(1),
+					*maxUint256,
+					*uint256.NewInt(0),
 				},
 				sp:     0,
 				memory: make([]byte, 1),
@@ -453,22 +404,22 @@ func Test_opReturnDataCopy(t *testing.T) {
 			},
 		},
 		{
-			name:   "should return error if length is negative",
+			name:   "should return error if length overflows uint64",
 			config: &allEnabledForks,
 			initState: &state{
-				stack: []*big.Int{
-					big.NewInt(-1), // length
-					big.NewInt(0),  // dataOffset
-					big.NewInt(0),  // memOffset
+				stack: []uint256.Int{
+					*maxUint256,        // length
+					*uint256.NewInt(0), // dataOffset
+					*uint256.NewInt(0), // memOffset
 				},
 				sp: 3,
 			},
 			resultState: &state{
 				config: &allEnabledForks,
-				stack: []*big.Int{
-					big.NewInt(-1),
-					big.NewInt(0),
-					big.NewInt(0),
+				stack: []uint256.Int{
+					*maxUint256,
+					*uint256.NewInt(0),
+					*uint256.NewInt(0),
 				},
 				sp:   0,
 				stop: true,
@@ -479,10 +430,10 @@ func Test_opReturnDataCopy(t *testing.T) {
 			name:   "should copy data from returnData to memory",
 			config: &allEnabledForks,
 			initState: &state{
-				stack: []*big.Int{
-					big.NewInt(1), // length
-					big.NewInt(0), // dataOffset
-					big.NewInt(0), // memOffset
+				stack: []uint256.Int{
+					*uint256.NewInt(1), // length
+					*uint256.NewInt(0), // dataOffset
+					*uint256.NewInt(0), // memOffset
 				},
 				sp:         3,
 				returnData: []byte{0xff},
@@ -491,10 +442,10 @@ func Test_opReturnDataCopy(t *testing.T) {
 			},
 			resultState: &state{
 				config: &allEnabledForks,
-				stack: []*big.Int{
-					big.NewInt(1),
-					big.NewInt(0),
-					big.NewInt(0),
+				stack: []uint256.Int{
+					*uint256.NewInt(1),
+					*uint256.NewInt(0),
+					*uint256.NewInt(0),
 				},
 				sp:          0,
 				returnData:  []byte{0xff},
@@ -509,10 +460,10 @@ func Test_opReturnDataCopy(t *testing.T) {
 			name:   "should expand memory and copy data returnData",
 			config: &allEnabledForks,
 			initState: &state{
-				stack: []*big.Int{
-					big.NewInt(5), // length
-					big.NewInt(1), // dataOffset
-					big.NewInt(2), // memOffset
+				stack: []uint256.Int{
+					*uint256.NewInt(5), // length
+					*uint256.NewInt(1), // dataOffset
+					*uint256.NewInt(2), // memOffset
 				},
 				sp:         3,
 				returnData: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
@@ -521,10 +472,10 @@ func Test_opReturnDataCopy(t *testing.T) {
 			},
 			resultState: &state{
 				config: &allEnabledForks,
-				stack: []*big.Int{
-					big.NewInt(6), // updated for end index
-					big.NewInt(1),
-					big.NewInt(2),
+				stack: []uint256.Int{
+					*uint256.NewInt(6), // updated for end index
+					*uint256.NewInt(1),
+					*uint256.NewInt(2),
 				},
 				sp:         0,
 				returnData: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
@@ -562,6 +513,7 @@ func Test_opReturnDataCopy(t *testing.T) {
 			state.host = nil
 			state.msg = nil
 			state.evm = nil
+			state.table = nil
 			state.bitmap = bitmap{}
 			state.ret = nil
 
@@ -571,3 +523,342 @@ func Test_opReturnDataCopy(t *testing.T) {
 		})
 	}
 }
+
+func TestPush0(t *testing.T) {
+	s, closeFn := getState()
+	defer closeFn()
+
+	// the slot of the new item holds a stale value
+	s.push(two)
+	s.pop()
+
+	opPush0(s)
+
+	assert.Equal(t, 1, s.stackSize())
+	assert.Equal(t, zero, s.pop())
+}
+
+func TestMCopy(t *testing.T) {
+	t.Parallel()
+
+	// the test vectors of eip-5656
+	tests := []struct {
+		name           string
+		dst, src, size uint64
+		memory         string
+		result         string
+		gas            uint64
+	}{
+		{
+			name:   "copy to the start",
+			dst:    0,
+			src:    32,
+			size:   32,
+			memory: "0000000000000000000000000000000000000000000000000000000000000000000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			result: "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			gas:    3,
+		},
+		{
+			name:   "copy onto itself",
+			dst:    0,
+			src:    0,
+			size:   32,
+			memory: "0101010101010101010101010101010101010101010101010101010101010101",
+			result: "0101010101010101010101010101010101010101010101010101010101010101",
+			gas:    3,
+		},
+		{
+			name:   "overlapping copy backwards",
+			dst:    0,
+			src:    1,
+			size:   8,
+			memory: "0001020304050607080000000000000000000000000000000000000000000000",
+			result: "0102030405060708080000000000000000000000000000000000000000000000",
+			gas:    3,
+		},
+		{
+			name:   "overlapping copy forwards",
+			dst:    1,
+			src:    0,
+			size:   8,
+			memory: "0001020304050607080000000000000000000000000000000000000000000000",
+			result: "0000010203040506070000000000000000000000000000000000000000000000",
+			gas:    3,
+		},
+		{
+			name:   "copy expanding the memory",
+			dst:    32,
+			src:    0,
+			size:   1,
+			memory: "0100000000000000000000000000000000000000000000000000000000000000",
+			result: "01000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000",
+			gas:    3 + 3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, closeFn := getState()
+			defer closeFn()
+
+			memory, _ := hex.DecodeString(tt.memory)
+			s.memory = append([]byte{}, memory...)
+			s.lastGasCost = 3 * uint64(len(s.memory)/32)
+			s.gas = 1000
+
+			s.push(uint256.NewInt(tt.size))
+			s.push(uint256.NewInt(tt.src))
+			s.push(uint256.NewInt(tt.dst))
+
+			opMCopy(s)
+
+			assert.NoError(t, s.err)
+			assert.Equal(t, tt.result, hex.EncodeToString(s.memory))
+			assert.Equal(t, 1000-tt.gas, s.gas)
+		})
+	}
+}
+
+type mockHostForTransient struct {
+	mockHost
+	storage map[types.Hash]types.Hash
+}
+
+func (m *mockHostForTransient) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	return m.storage[key]
+}
+
+func (m *mockHostForTransient) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	m.storage[key] = value
+}
+
+func TestTransientStorage(t *testing.T) {
+	s, closeFn := getState()
+	defer closeFn()
+
+	host := &mockHostForTransient{storage: map[types.Hash]types.Hash{}}
+	s.host = host
+	s.msg = &runtime.Contract{}
+
+	s.push(two) // value
+	s.push(one) // key
+	opTstore(s)
+
+	assert.NoError(t, s.err)
+	assert.Equal(t, uint256ToHash(two), host.storage[uint256ToHash(one)])
+
+	s.push(one)
+	opTload(s)
+	assert.Equal(t, two, s.pop())
+
+	// the unset keys are zero
+	s.push(two)
+	opTload(s)
+	assert.Equal(t, zero, s.pop())
+
+	// the transient storage is read-only in a static call
+	s.msg = &runtime.Contract{Static: true}
+
+	s.push(one)
+	s.push(one)
+	opTstore(s)
+
+	assert.Equal(t, errWriteProtection, s.err)
+	assert.Equal(t, uint256ToHash(two), host.storage[uint256ToHash(one)])
+}
+
+// TestArithmetic_Reference runs the arithmetic opcodes over the edge cases of the words,
+// and compares their results with the ones of the yellow paper computed on big integers
+func TestArithmetic_Reference(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mod     = new(big.Int).Lsh(big.NewInt(1), 256)
+		signBit = new(big.Int).Lsh(big.NewInt(1), 255)
+		bits    = func(n uint) *big.Int { return new(big.Int).Lsh(big.NewInt(1), n) }
+		minus   = func(x *big.Int, n int64) *big.Int { return new(big.Int).Sub(x, big.NewInt(n)) }
+		plus    = func(x *big.Int, n int64) *big.Int { return new(big.Int).Add(x, big.NewInt(n)) }
+	)
+
+	words := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(7), big.NewInt(8),
+		big.NewInt(31), big.NewInt(32), big.NewInt(255), big.NewInt(256),
+		minus(bits(64), 1), bits(64), minus(bits(128), 1), bits(128),
+		minus(signBit, 1), signBit, plus(signBit, 1), minus(mod, 2), minus(mod, 1),
+		new(big.Int).SetBytes(crypto.Keccak256([]byte("a"))), new(big.Int).SetBytes(crypto.Keccak256([]byte("b"))[16:]),
+	}
+
+	// wrap returns the word of the integer, signed ones included
+	wrap := func(x *big.Int) *big.Int {
+		return new(big.Int).Mod(x, mod)
+	}
+
+	signed := func(x *big.Int) *big.Int {
+		if x.Cmp(signBit) >= 0 {
+			return new(big.Int).Sub(x, mod)
+		}
+
+		return x
+	}
+
+	boolean := func(b bool) *big.Int {
+		if b {
+			return big.NewInt(1)
+		}
+
+		return big.NewInt(0)
+	}
+
+	// the operands are in the order they are popped
+	binary := map[OpCode]func(a, b *big.Int) *big.Int{
+		ADD: func(a, b *big.Int) *big.Int { return wrap(new(big.Int).Add(a, b)) },
+		MUL: func(a, b *big.Int) *big.Int { return wrap(new(big.Int).Mul(a, b)) },
+		SUB: func(a, b *big.Int) *big.Int { return wrap(new(big.Int).Sub(a, b)) },
+		DIV: func(a, b *big.Int) *big.Int {
+			if b.Sign() == 0 {
+				return b
+			}
+
+			return new(big.Int).Quo(a, b)
+		},
+		SDIV: func(a, b *big.Int) *big.Int {
+			if b.Sign() == 0 {
+				return b
+			}
+
+			return wrap(new(big.Int).Quo(signed(a), signed(b)))
+		},
+		MOD: func(a, b *big.Int) *big.Int {
+			if b.Sign() == 0 {
+				return b
+			}
+
+			return new(big.Int).Rem(a, b)
+		},
+		SMOD: func(a, b *big.Int) *big.Int {
+			if b.Sign() == 0 {
+				return b
+			}
+
+			return wrap(new(big.Int).Rem(signed(a), signed(b)))
+		},
+		EXP: func(a, b *big.Int) *big.Int { return new(big.Int).Exp(a, b, mod) },
+		SIGNEXTEND: func(a, b *big.Int) *big.Int {
+			if a.Cmp(big.NewInt(31)) >= 0 {
+				return b
+			}
+
+			bit := uint(a.Uint64()*8 + 7)
+			mask := minus(bits(bit+1), 1)
+
+			if b.Bit(int(bit)) == 0 {
+				return new(big.Int).And(b, mask)
+			}
+
+			return new(big.Int).Or(b, new(big.Int).Xor(minus(mod, 1), mask))
+		},
+		LT:  func(a, b *big.Int) *big.Int { return boolean(a.Cmp(b) < 0) },
+		GT:  func(a, b *big.Int) *big.Int { return boolean(a.Cmp(b) > 0) },
+		SLT: func(a, b *big.Int) *big.Int { return boolean(signed(a).Cmp(signed(b)) < 0) },
+		SGT: func(a, b *big.Int) *big.Int { return boolean(signed(a).Cmp(signed(b)) > 0) },
+		EQ:  func(a, b *big.Int) *big.Int { return boolean(a.Cmp(b) == 0) },
+		AND: func(a, b *big.Int) *big.Int { return new(big.Int).And(a, b) },
+		OR:  func(a, b *big.Int) *big.Int { return new(big.Int).Or(a, b) },
+		XOR: func(a, b *big.Int) *big.Int { return new(big.Int).Xor(a, b) },
+		BYTE: func(a, b *big.Int) *big.Int {
+			if a.Cmp(big.NewInt(32)) >= 0 {
+				return big.NewInt(0)
+			}
+
+			return new(big.Int).And(new(big.Int).Rsh(b, uint(8*(31-a.Uint64()))), big.NewInt(0xff))
+		},
+		SHL: func(a, b *big.Int) *big.Int {
+			if a.Cmp(big.NewInt(256)) >= 0 {
+				return big.NewInt(0)
+			}
+
+			return wrap(new(big.Int).Lsh(b, uint(a.Uint64())))
+		},
+		SHR: func(a, b *big.Int) *big.Int {
+			if a.Cmp(big.NewInt(256)) >= 0 {
+				return big.NewInt(0)
+			}
+
+			return new(big.Int).Rsh(b, uint(a.Uint64()))
+		},
+		SAR: func(a, b *big.Int) *big.Int {
+			shift := uint(255)
+			if a.Cmp(big.NewInt(256)) < 0 {
+				shift = uint(a.Uint64())
+			}
+
+			return wrap(new(big.Int).Rsh(signed(b), shift))
+		},
+	}
+
+	ternary := map[OpCode]func(a, b, n *big.Int) *big.Int{
+		ADDMOD: func(a, b, n *big.Int) *big.Int {
+			if n.Sign() == 0 {
+				return n
+			}
+
+			return new(big.Int).Mod(new(big.Int).Add(a, b), n)
+		},
+		MULMOD: func(a, b, n *big.Int) *big.Int {
+			if n.Sign() == 0 {
+				return n
+			}
+
+			return new(big.Int).Mod(new(big.Int).Mul(a, b), n)
+		},
+	}
+
+	unary := map[OpCode]func(a *big.Int) *big.Int{
+		ISZERO: func(a *big.Int) *big.Int { return boolean(a.Sign() == 0) },
+		NOT:    func(a *big.Int) *big.Int { return new(big.Int).Xor(a, minus(mod, 1)) },
+	}
+
+	// run pushes the operands in the reverse order, so the first one is popped first, and returns the result
+	run := func(op OpCode, operands ...*big.Int) *big.Int {
+		code := []byte{}
+
+		for i := len(operands) - 1; i >= 0; i-- {
+			code = append(append(code, PUSH32), types.BytesToHash(operands[i].Bytes()).Bytes()...)
+		}
+
+		code = append(code, byte(op), PUSH1, 0x00, MSTORE, PUSH1, 0x20, PUSH1, 0x00, RETURN)
+
+		res := NewEVM().Run(newMockContract(big.NewInt(0), 10_000_000, code), &mockHost{}, &allEnabledForks)
+		require.NoError(t, res.Err, "%s %v", op, operands)
+
+		return new(big.Int).SetBytes(res.ReturnValue)
+	}
+
+	for op, f := range binary {
+		for _, a := range words {
+			for _, b := range words {
+				assert.Equal(t, f(a, b).String(), run(op, a, b).String(), "%s(%s, %s)", op, a, b)
+			}
+		}
+	}
+
+	for op, f := range ternary {
+		for _, a := range words {
+			for _, b := range words {
+				for _, n := range words {
+					assert.Equal(t, f(a, b, n).String(), run(op, a, b, n).String(), "%s(%s, %s, %s)", op, a, b, n)
+				}
+			}
+		}
+	}
+
+	for op, f := range unary {
+		for _, a := range words {
+			assert.Equal(t, f(a).String(), run(op, a).String(), "%s(%s)", op, a)
+		}
+	}
+}
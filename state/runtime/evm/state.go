@@ -4,18 +4,23 @@ import (
 	"errors"
 	"math/big"
 	"strings"
-
 	"sync"
 
+	"github.com/holiman/uint256"
+
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
+// statePool pools the states along with their stack and memory, so they aren't allocated on every call
 var statePool = sync.Pool{
 	New: func() interface{} {
-		return new(state)
+		return &state{
+			// the stack may exceed the limit by one before the overflow is checked
+			stack: make([]uint256.Int, 0, stackSize+1),
+		}
 	},
 }
 
@@ -57,13 +62,14 @@ type state struct {
 	host   runtime.Host
 	msg    *runtime.Contract // change with msg
 	config *chain.ForksInTime
+	table  *jumpTable
 
 	// memory
 	memory      []byte
 	lastGasCost uint64
 
 	// stack
-	stack []*big.Int
+	stack []uint256.Int
 	sp    int
 
 	// remove later
@@ -104,9 +110,9 @@ func (c *state) reset() {
 	c.memory = c.memory[:0]
 }
 
-func (c *state) validJumpdest(dest *big.Int) bool {
-	udest := dest.Uint64()
-	if dest.BitLen() >= 63 || udest >= uint64(len(c.code)) {
+func (c *state) validJumpdest(dest *uint256.Int) bool {
+	udest, overflow := dest.Uint64WithOverflow()
+	if overflow || udest >= uint64(len(c.code)) {
 		return false
 	}
 
@@ -126,22 +132,19 @@ func (c *state) exit(err error) {
 	c.err = err
 }
 
-func (c *state) push(val *big.Int) {
+func (c *state) push(val *uint256.Int) {
 	c.push1().Set(val)
 }
 
-func (c *state) push1() *big.Int {
-	if len(c.stack) > c.sp {
-		c.sp++
-
-		return c.stack[c.sp-1]
+// push1 pushes a new item to the stack and returns it, the item is the one last popped from its slot
+func (c *state) push1() *uint256.Int {
+	if len(c.stack) == c.sp {
+		c.stack = append(c.stack, uint256.Int{})
 	}
 
-	v := big.NewInt(0)
-	c.stack = append(c.stack, v)
 	c.sp++
 
-	return v
+	return &c.stack[c.sp-1]
 }
 
 func (c *state) stackAtLeast(n int) bool {
@@ -149,7 +152,7 @@ func (c *state) stackAtLeast(n int) bool {
 }
 
 func (c *state) popHash() types.Hash {
-	return types.BytesToHash(c.pop().Bytes())
+	return c.pop().Bytes32()
 }
 
 func (c *state) popAddr() (types.Address, bool) {
@@ -158,34 +161,34 @@ func (c *state) popAddr() (types.Address, bool) {
 		return types.Address{}, false
 	}
 
-	return types.BytesToAddress(b.Bytes()), true
+	return b.Bytes20(), true
 }
 
 func (c *state) stackSize() int {
 	return c.sp
 }
 
-func (c *state) top() *big.Int {
+func (c *state) top() *uint256.Int {
 	if c.sp == 0 {
 		return nil
 	}
 
-	return c.stack[c.sp-1]
+	return &c.stack[c.sp-1]
 }
 
-func (c *state) pop() *big.Int {
+func (c *state) pop() *uint256.Int {
 	if c.sp == 0 {
 		return nil
 	}
 
-	o := c.stack[c.sp-1]
+	o := &c.stack[c.sp-1]
 	c.sp--
 
 	return o
 }
 
-func (c *state) peekAt(n int) *big.Int {
-	return c.stack[c.sp-n]
+func (c *state) peekAt(n int) *uint256.Int {
+	return &c.stack[c.sp-n]
 }
 
 func (c *state) swap(n int) {
@@ -213,6 +216,9 @@ func (c *state) Run() ([]byte, error) {
 	var vmerr error
 
 	codeSize := len(c.code)
+	table := c.table
+	tracer := c.host.GetTracer()
+
 	for !c.stop {
 		if c.ip >= codeSize {
 			c.halt()
@@ -222,12 +228,16 @@ func (c *state) Run() ([]byte, error) {
 
 		op := OpCode(c.code[c.ip])
 
-		inst := dispatchTable[op]
+		inst := &table[op]
 		if inst.inst == nil {
 			c.exit(errOpCodeNotFound)
 
 			break
 		}
+
+		if tracer != nil {
+			c.captureState(tracer, op, inst.gas)
+		}
 		// check if the depth of the stack is enough for the instruction
 		if c.sp < inst.stack {
 			c.exit(errStackUnderflow)
@@ -260,12 +270,37 @@ func (c *state) Run() ([]byte, error) {
 	return c.ret, vmerr
 }
 
+// captureState passes the state of the EVM to the tracer before the instruction is executed
+func (c *state) captureState(tracer runtime.Tracer, op OpCode, cost uint64) {
+	tracer.ExecuteState(&runtime.ExecutionStep{
+		Host:     c.host,
+		Contract: c.msg,
+		PC:       uint64(c.ip),
+		Op:       int(op),
+		Gas:      c.gas,
+		Cost:     cost,
+		Depth:    c.msg.Depth,
+		Stack:    c.bigStack(),
+		Memory:   c.memory,
+	})
+}
+
+// bigStack returns a copy of the stack for the tracer
+func (c *state) bigStack() []*big.Int {
+	stack := make([]*big.Int, c.sp)
+	for i := range stack {
+		stack[i] = c.stack[i].ToBig()
+	}
+
+	return stack
+}
+
 func (c *state) inStaticCall() bool {
 	return c.msg.Static
 }
 
-func bigToHash(b *big.Int) types.Hash {
-	return types.BytesToHash(b.Bytes())
+func uint256ToHash(b *uint256.Int) types.Hash {
+	return b.Bytes32()
 }
 
 func (c *state) Len() int {
@@ -275,14 +310,14 @@ func (c *state) Len() int {
 // allocateMemory allocates memory to enable accessing in the range of [offset, offset+size]
 // throws error if the given offset and size are negative
 // consumes gas if memory needs to be expanded
-func (c *state) allocateMemory(offset, size *big.Int) bool {
+func (c *state) allocateMemory(offset, size *uint256.Int) bool {
 	if !offset.IsUint64() || !size.IsUint64() {
 		c.exit(errGasUintOverflow)
 
 		return false
 	}
 
-	if size.Sign() == 0 {
+	if size.IsZero() {
 		return true
 	}
 
@@ -323,8 +358,8 @@ func extendByteSlice(b []byte, needLen int) []byte {
 	return b[:needLen]
 }
 
-func (c *state) get2(dst []byte, offset, length *big.Int) ([]byte, bool) {
-	if length.Sign() == 0 {
+func (c *state) get2(dst []byte, offset, length *uint256.Int) ([]byte, bool) {
+	if length.IsZero() {
 		return nil, true
 	}
 
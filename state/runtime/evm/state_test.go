@@ -25,6 +25,8 @@ func (c *codeHelper) pop() {
 
 func getState() (*state, func()) {
 	c := statePool.Get().(*state) //nolint:forcetypeassert
+	c.host = &mockHost{}
+	c.table = getJumpTable(&allEnabledForks)
 
 	return c, func() {
 		c.reset()
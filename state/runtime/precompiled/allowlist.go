@@ -0,0 +1,148 @@
+package precompiled
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/umbracle/ethgo/abi"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// AllowlistSender is the role of the accounts allowed to send transactions
+	AllowlistSender uint64 = 1 << iota
+	// AllowlistDeployer is the role of the accounts allowed to deploy contracts
+	AllowlistDeployer
+	// AllowlistAdmin is the role of the accounts granting the roles
+	AllowlistAdmin
+
+	// AllowlistAllRoles are the roles of the admins of the chain
+	AllowlistAllRoles = AllowlistSender | AllowlistDeployer | AllowlistAdmin
+
+	// allowlistWriteGas is the gas of granting the roles, which writes a storage slot and emits a log
+	allowlistWriteGas = 25000
+
+	// allowlistReadGas is the gas of reading the roles, which reads a storage slot
+	allowlistReadGas = 2100
+)
+
+var (
+	// AllowlistAddr is the address of the allowlist precompile
+	AllowlistAddr = types.StringToAddress("2024")
+
+	// AllowlistSetRolesMethod replaces the roles of the account, it can only be called by the admins
+	AllowlistSetRolesMethod, _ = abi.NewMethod("function setRoles(address account, uint256 roles)")
+	// AllowlistRolesMethod returns the roles of the account
+	AllowlistRolesMethod, _ = abi.NewMethod("function roles(address account) returns (uint256)")
+
+	// AllowlistEventID is the topic of the log emitted for every change of roles
+	AllowlistEventID = types.BytesToHash(crypto.Keccak256([]byte("RolesSet(address,uint256)")))
+
+	errAllowlistInput  = errors.New("invalid allowlist input")
+	errAllowlistAdmin  = errors.New("the roles can only be set by the admins")
+	errAllowlistStatic = errors.New("the roles can't be set in a static call")
+	errAllowlistCall   = errors.New("the roles can only be set in a direct call")
+	errAllowlistRoles  = errors.New("unknown allowlist roles")
+	errAllowlistNoHost = errors.New("allowlist requires a host")
+)
+
+// AllowlistSlot returns the storage slot of the allowlist precompile holding the roles of the account
+func AllowlistSlot(addr types.Address) types.Hash {
+	return types.BytesToHash(crypto.Keccak256([]byte("polygon-edge.allowlist"), addr.Bytes()))
+}
+
+// AllowlistHost is the host keeping the roles of the accounts in the storage of the allowlist precompile
+type AllowlistHost interface {
+	// AllowlistRoles returns the roles of the account, all of them for the admins of the chain
+	AllowlistRoles(addr types.Address) uint64
+	// SetAllowlistRoles replaces the roles of the account
+	SetAllowlistRoles(addr types.Address, roles uint64)
+}
+
+// allowlist is the precompile granting the roles of the allowlist from the allowlist fork
+type allowlist struct{}
+
+func (a *allowlist) gas(input []byte, config *chain.ForksInTime) uint64 {
+	if len(input) >= 4 && bytes.Equal(input[:4], AllowlistRolesMethod.ID()) {
+		return allowlistReadGas
+	}
+
+	return allowlistWriteGas
+}
+
+func (a *allowlist) run(input []byte) ([]byte, error) {
+	return nil, errAllowlistNoHost
+}
+
+func (a *allowlist) runWithHost(
+	c *runtime.Contract,
+	host runtime.Host,
+	config *chain.ForksInTime,
+) ([]byte, uint64, error) {
+	allowlistHost, ok := host.(AllowlistHost)
+	if !ok {
+		return nil, 0, errAllowlistNoHost
+	}
+
+	if len(c.Input) < 4 {
+		return nil, 0, errAllowlistInput
+	}
+
+	switch selector := c.Input[:4]; {
+	case bytes.Equal(selector, AllowlistRolesMethod.ID()):
+		var args struct {
+			Account types.Address
+		}
+
+		if err := abi.DecodeStruct(AllowlistRolesMethod.Inputs, c.Input[4:], &args); err != nil {
+			return nil, 0, errAllowlistInput
+		}
+
+		roles := new(big.Int).SetUint64(allowlistHost.AllowlistRoles(args.Account))
+
+		return types.BytesToHash(roles.Bytes()).Bytes(), c.Gas, nil
+	case bytes.Equal(selector, AllowlistSetRolesMethod.ID()):
+		if c.Static {
+			return nil, 0, errAllowlistStatic
+		}
+
+		// a delegate call or a call code keeps the caller of the calling frame,
+		// any contract called by an admin could set the roles on its behalf
+		if c.Type != runtime.Call {
+			return nil, 0, errAllowlistCall
+		}
+
+		if allowlistHost.AllowlistRoles(c.Caller)&AllowlistAdmin == 0 {
+			return nil, 0, errAllowlistAdmin
+		}
+
+		var args struct {
+			Account types.Address
+			Roles   *big.Int
+		}
+
+		if err := abi.DecodeStruct(AllowlistSetRolesMethod.Inputs, c.Input[4:], &args); err != nil {
+			return nil, 0, errAllowlistInput
+		}
+
+		if !args.Roles.IsUint64() || args.Roles.Uint64()&^AllowlistAllRoles != 0 {
+			return nil, 0, errAllowlistRoles
+		}
+
+		allowlistHost.SetAllowlistRoles(args.Account, args.Roles.Uint64())
+		host.EmitLog(
+			AllowlistAddr,
+			[]types.Hash{AllowlistEventID, types.BytesToHash(args.Account.Bytes())},
+			types.BytesToHash(args.Roles.Bytes()).Bytes(),
+		)
+
+		return nil, c.Gas, nil
+	default:
+		return nil, 0, errAllowlistInput
+	}
+}
@@ -0,0 +1,164 @@
+package precompiled
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// allowlistHost keeps the roles set by the precompile, the admin holds all of them
+type allowlistHost struct {
+	runtime.Host
+
+	admin types.Address
+	roles map[types.Address]uint64
+	logs  [][]types.Hash
+}
+
+func (h *allowlistHost) AllowlistRoles(addr types.Address) uint64 {
+	if addr == h.admin {
+		return AllowlistAllRoles
+	}
+
+	return h.roles[addr]
+}
+
+func (h *allowlistHost) SetAllowlistRoles(addr types.Address, roles uint64) {
+	h.roles[addr] = roles
+}
+
+func (h *allowlistHost) EmitLog(_ types.Address, topics []types.Hash, _ []byte) {
+	h.logs = append(h.logs, topics)
+}
+
+func TestAllowlist(t *testing.T) {
+	t.Parallel()
+
+	var (
+		admin   = types.StringToAddress("a1")
+		account = types.StringToAddress("b1")
+		config  = &chain.ForksInTime{Allowlist: true}
+	)
+
+	newContract := func(caller types.Address, input []byte) *runtime.Contract {
+		contract := runtime.NewContractCall(1, caller, caller, AllowlistAddr, nil, 100000, nil, input)
+		contract.CodeAddress = AllowlistAddr
+
+		return contract
+	}
+
+	encodeSetRoles := func(t *testing.T, roles uint64) []byte {
+		t.Helper()
+
+		input, err := AllowlistSetRolesMethod.Encode(map[string]interface{}{
+			"account": account,
+			"roles":   new(big.Int).SetUint64(roles),
+		})
+		require.NoError(t, err)
+
+		return input
+	}
+
+	encodeRoles := func(t *testing.T) []byte {
+		t.Helper()
+
+		input, err := AllowlistRolesMethod.Encode(map[string]interface{}{"account": account})
+		require.NoError(t, err)
+
+		return input
+	}
+
+	newHost := func() *allowlistHost {
+		return &allowlistHost{admin: admin, roles: map[types.Address]uint64{}}
+	}
+
+	t.Run("the admin sets the roles", func(t *testing.T) {
+		t.Parallel()
+
+		host := newHost()
+		p := NewPrecompiled()
+		contract := newContract(admin, encodeSetRoles(t, AllowlistSender|AllowlistDeployer))
+
+		assert.False(t, p.CanRun(contract, host, &chain.ForksInTime{}))
+		require.True(t, p.CanRun(contract, host, config))
+
+		result := p.Run(contract, host, config)
+		require.NoError(t, result.Err)
+		assert.Equal(t, uint64(100000-allowlistWriteGas), result.GasLeft)
+		assert.Equal(t, AllowlistSender|AllowlistDeployer, host.roles[account])
+		assert.Equal(t, [][]types.Hash{{AllowlistEventID, types.BytesToHash(account.Bytes())}}, host.logs)
+
+		result = p.Run(newContract(account, encodeRoles(t)), host, config)
+		require.NoError(t, result.Err)
+		assert.Equal(t, uint64(100000-allowlistReadGas), result.GasLeft)
+		assert.Equal(t, types.BytesToHash(big.NewInt(3).Bytes()).Bytes(), result.ReturnValue)
+	})
+
+	t.Run("the granted admin sets the roles", func(t *testing.T) {
+		t.Parallel()
+
+		host := newHost()
+		host.roles[account] = AllowlistAdmin
+
+		result := NewPrecompiled().Run(newContract(account, encodeSetRoles(t, 0)), host, config)
+		require.NoError(t, result.Err)
+		assert.Zero(t, host.roles[account])
+	})
+
+	t.Run("only the admins set the roles", func(t *testing.T) {
+		t.Parallel()
+
+		host := newHost()
+		host.roles[account] = AllowlistSender | AllowlistDeployer
+
+		result := NewPrecompiled().Run(newContract(account, encodeSetRoles(t, AllowlistAllRoles)), host, config)
+		assert.ErrorIs(t, result.Err, errAllowlistAdmin)
+		assert.Equal(t, AllowlistSender|AllowlistDeployer, host.roles[account])
+	})
+
+	t.Run("unknown roles", func(t *testing.T) {
+		t.Parallel()
+
+		host := newHost()
+
+		result := NewPrecompiled().Run(newContract(admin, encodeSetRoles(t, AllowlistAllRoles+1)), host, config)
+		assert.ErrorIs(t, result.Err, errAllowlistRoles)
+		assert.Empty(t, host.roles)
+	})
+
+	t.Run("delegate call from a contract called by the admin", func(t *testing.T) {
+		t.Parallel()
+
+		for _, callType := range []runtime.CallType{runtime.DelegateCall, runtime.CallCode} {
+			host := newHost()
+
+			// the frame of the contract keeps the admin as the caller
+			contract := runtime.NewContractCall(2, admin, admin, types.StringToAddress("c1"), nil, 100000, nil,
+				encodeSetRoles(t, AllowlistAllRoles))
+			contract.CodeAddress = AllowlistAddr
+			contract.Type = callType
+
+			result := NewPrecompiled().Run(contract, host, config)
+			assert.ErrorIs(t, result.Err, errAllowlistCall)
+			assert.Empty(t, host.roles)
+			assert.Empty(t, host.logs)
+		}
+	})
+
+	t.Run("static call", func(t *testing.T) {
+		t.Parallel()
+
+		contract := newContract(admin, encodeSetRoles(t, AllowlistSender))
+		contract.Type = runtime.StaticCall
+		contract.Static = true
+
+		result := NewPrecompiled().Run(contract, newHost(), config)
+		assert.ErrorIs(t, result.Err, errAllowlistStatic)
+	})
+}
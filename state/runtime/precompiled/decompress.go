@@ -0,0 +1,136 @@
+package precompiled
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// DecompressSnappy is the algorithm byte of the snappy (block format) compressed input
+	DecompressSnappy byte = 0x01
+
+	// DecompressZstd is the algorithm byte of the zstd compressed input,
+	// the frame header must declare the content size
+	DecompressZstd byte = 0x02
+
+	// decompressMaxSize is the maximum size of the decompressed data
+	decompressMaxSize = 4 * 1024 * 1024
+
+	// decompressBaseGas is the gas of the decompress precompile itself
+	decompressBaseGas = 100
+
+	// decompressInputWordGas is the gas of every word of the compressed data
+	decompressInputWordGas = 6
+
+	// decompressOutputWordGas is the gas of every word of the decompressed data
+	decompressOutputWordGas = 3
+)
+
+var (
+	// DecompressAddr is the address of the decompress precompile.
+	// The input is the algorithm byte followed by the compressed data, the output is the decompressed data
+	DecompressAddr = types.StringToAddress("2021")
+
+	errDecompressAlgorithm = errors.New("unknown compression algorithm")
+	errDecompressSize      = errors.New("decompressed size is unknown or too large")
+
+	// zstdDecoder is shared by all the calls, it's safe for concurrent use with DecodeAll
+	zstdDecoder, _ = zstd.NewReader(
+		nil,
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderMaxMemory(decompressMaxSize),
+	)
+)
+
+// decompress is the precompile decompressing the calldata.
+// The gas is metered on the size of the compressed data and on the decompressed size declared by its header
+type decompress struct{}
+
+func (d *decompress) gas(input []byte, config *chain.ForksInTime) uint64 {
+	if len(input) == 0 {
+		return decompressBaseGas
+	}
+
+	size, err := decompressedSize(input[0], input[1:])
+	if err != nil {
+		// the invalid input fails in run, consuming all the gas anyway
+		size = 0
+	} else if size > decompressMaxSize {
+		return math.MaxUint64
+	}
+
+	return decompressBaseGas +
+		decompressInputWordGas*((uint64(len(input))+31)/32) +
+		decompressOutputWordGas*((size+31)/32)
+}
+
+func (d *decompress) run(input []byte) ([]byte, error) {
+	if len(input) == 0 {
+		return nil, errDecompressAlgorithm
+	}
+
+	algorithm, data := input[0], input[1:]
+
+	size, err := decompressedSize(algorithm, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > decompressMaxSize {
+		return nil, errDecompressSize
+	}
+
+	var out []byte
+
+	switch algorithm {
+	case DecompressSnappy:
+		out, err = snappy.Decode(nil, data)
+	case DecompressZstd:
+		out, err = zstdDecoder.DecodeAll(data, make([]byte, 0, size))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the data may hold more frames than the declared one
+	if uint64(len(out)) != size {
+		return nil, errDecompressSize
+	}
+
+	return out, nil
+}
+
+// decompressedSize returns the size of the decompressed data declared by the header of the compressed data
+func decompressedSize(algorithm byte, data []byte) (uint64, error) {
+	switch algorithm {
+	case DecompressSnappy:
+		size, err := snappy.DecodedLen(data)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(size), nil
+
+	case DecompressZstd:
+		var header zstd.Header
+		if err := header.Decode(data); err != nil {
+			return 0, err
+		}
+
+		if !header.HasFCS {
+			return 0, errDecompressSize
+		}
+
+		return header.FrameContentSize, nil
+
+	default:
+		return 0, errDecompressAlgorithm
+	}
+}
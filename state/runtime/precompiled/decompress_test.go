@@ -0,0 +1,143 @@
+package precompiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+)
+
+func compressZstd(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+
+	defer encoder.Close()
+
+	return encoder.EncodeAll(data, nil)
+}
+
+func TestDecompress(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("polygon-edge batch "), 100)
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "snappy",
+			input: append([]byte{DecompressSnappy}, snappy.Encode(nil, data)...),
+		},
+		{
+			name:  "zstd",
+			input: append([]byte{DecompressZstd}, compressZstd(t, data)...),
+		},
+	}
+
+	config := &chain.ForksInTime{Decompress: true}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewPrecompiled()
+			contract := &runtime.Contract{
+				CodeAddress: DecompressAddr,
+				Input:       tt.input,
+				Gas:         100000,
+			}
+
+			assert.False(t, p.CanRun(contract, nil, &chain.ForksInTime{}))
+			require.True(t, p.CanRun(contract, nil, config))
+
+			// the gas is metered on the compressed and on the decompressed sizes
+			gas := (&decompress{}).gas(tt.input, config)
+			assert.Equal(
+				t,
+				uint64(decompressBaseGas+
+					decompressInputWordGas*((len(tt.input)+31)/32)+
+					decompressOutputWordGas*((len(data)+31)/32)),
+				gas,
+			)
+
+			result := p.Run(contract, nil, config)
+			require.NoError(t, result.Err)
+			assert.Equal(t, data, result.ReturnValue)
+			assert.Equal(t, 100000-gas, result.GasLeft)
+		})
+	}
+}
+
+func TestDecompress_Errors(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("data")
+
+	// two zstd frames, the header declares the size of the first one only
+	frames := compressZstd(t, data)
+	frames = append(frames, compressZstd(t, data)...)
+
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{
+			name:  "empty input",
+			input: []byte{},
+			err:   errDecompressAlgorithm,
+		},
+		{
+			name:  "unknown algorithm",
+			input: append([]byte{0x03}, snappy.Encode(nil, data)...),
+			err:   errDecompressAlgorithm,
+		},
+		{
+			name:  "invalid snappy data",
+			input: []byte{DecompressSnappy, 0x10, 0x01},
+		},
+		{
+			name:  "more zstd frames than declared",
+			input: append([]byte{DecompressZstd}, frames...),
+			err:   errDecompressSize,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := (&decompress{}).run(tt.input)
+			assert.Error(t, err)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+
+		// the snappy header declares the decompressed size as a varint
+		input := []byte{DecompressSnappy, 0x80, 0x80, 0x80, 0x04}
+
+		assert.Equal(t, uint64(1<<64-1), (&decompress{}).gas(input, nil))
+
+		_, err := (&decompress{}).run(input)
+		assert.ErrorIs(t, err, errDecompressSize)
+	})
+}
@@ -0,0 +1,116 @@
+package precompiled
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/umbracle/ethgo/abi"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// maintenanceGas is the gas of the maintenance precompile, it writes a storage slot and emits a log
+	maintenanceGas = 25000
+
+	// MaxMaintenanceWindow is the maximum number of blocks of a maintenance window
+	MaxMaintenanceWindow = 10000
+)
+
+var (
+	// MaintenanceAddr is the address of the maintenance precompile
+	MaintenanceAddr = types.StringToAddress("2022")
+
+	// MaintenanceMethod is the interface of the maintenance precompile. The sender of the transaction
+	// announces it will be offline from fromBlock to toBlock (both included), the window must start after
+	// the block of the transaction. A later announcement replaces the previous one, (0, 0) cancels it
+	MaintenanceMethod, _ = abi.NewMethod("function announceMaintenance(uint64 fromBlock, uint64 toBlock)")
+
+	// MaintenanceSlot is the storage slot of the sender account holding its maintenance window
+	MaintenanceSlot = types.BytesToHash(crypto.Keccak256([]byte("polygon-edge.maintenance")))
+
+	// MaintenanceEventID is the topic of the log emitted for every announcement
+	MaintenanceEventID = types.BytesToHash(crypto.Keccak256([]byte("MaintenanceAnnounced(address,uint64,uint64)")))
+
+	errMaintenanceInput  = errors.New("invalid maintenance input")
+	errMaintenanceCaller = errors.New("maintenance must be announced by a direct call of the sender")
+	errMaintenanceWindow = errors.New("invalid maintenance window")
+	errMaintenanceNoHost = errors.New("maintenance requires a host")
+)
+
+// EncodeMaintenanceWindow packs the maintenance window into the value of the storage slot
+func EncodeMaintenanceWindow(from, to uint64) types.Hash {
+	var value types.Hash
+
+	binary.BigEndian.PutUint64(value[16:24], from)
+	binary.BigEndian.PutUint64(value[24:32], to)
+
+	return value
+}
+
+// DecodeMaintenanceWindow unpacks the maintenance window from the value of the storage slot
+func DecodeMaintenanceWindow(value types.Hash) (uint64, uint64) {
+	return binary.BigEndian.Uint64(value[16:24]), binary.BigEndian.Uint64(value[24:32])
+}
+
+// maintenance is the precompile registering the maintenance window of the sender in the state,
+// so that the consensus can deprioritize it in the proposer selection during the window
+type maintenance struct{}
+
+func (m *maintenance) gas(input []byte, config *chain.ForksInTime) uint64 {
+	return maintenanceGas
+}
+
+func (m *maintenance) run(input []byte) ([]byte, error) {
+	return nil, errMaintenanceNoHost
+}
+
+// runWithHost writes the maintenance window to the storage of the sender account
+func (m *maintenance) runWithHost(
+	c *runtime.Contract,
+	host runtime.Host,
+	config *chain.ForksInTime,
+) ([]byte, uint64, error) {
+	if c.Static || c.Type != runtime.Call || c.Caller != c.Origin {
+		return nil, 0, errMaintenanceCaller
+	}
+
+	if len(c.Input) < 4 || !bytes.Equal(c.Input[:4], MaintenanceMethod.ID()) {
+		return nil, 0, errMaintenanceInput
+	}
+
+	var args struct {
+		FromBlock uint64
+		ToBlock   uint64
+	}
+
+	if err := abi.DecodeStruct(MaintenanceMethod.Inputs, c.Input[4:], &args); err != nil {
+		return nil, 0, errMaintenanceInput
+	}
+
+	if args.FromBlock != 0 || args.ToBlock != 0 {
+		number := uint64(host.GetTxContext().Number)
+
+		if args.FromBlock <= number ||
+			args.ToBlock < args.FromBlock ||
+			args.ToBlock-args.FromBlock >= MaxMaintenanceWindow {
+			return nil, 0, errMaintenanceWindow
+		}
+	}
+
+	window := EncodeMaintenanceWindow(args.FromBlock, args.ToBlock)
+	host.SetStorage(c.Caller, MaintenanceSlot, window, config)
+
+	// the log data is the abi encoding of the window
+	data := make([]byte, 64)
+	copy(data[24:32], window[16:24])
+	copy(data[56:64], window[24:32])
+
+	host.EmitLog(MaintenanceAddr, []types.Hash{MaintenanceEventID, types.BytesToHash(c.Caller.Bytes())}, data)
+
+	return nil, c.Gas, nil
+}
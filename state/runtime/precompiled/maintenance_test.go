@@ -0,0 +1,157 @@
+package precompiled
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// maintenanceHost keeps the storage and the logs written by the precompile at the given block
+type maintenanceHost struct {
+	runtime.Host
+
+	number  int64
+	storage map[types.Address]map[types.Hash]types.Hash
+	logs    [][]types.Hash
+}
+
+func (h *maintenanceHost) GetTxContext() runtime.TxContext {
+	return runtime.TxContext{Number: h.number}
+}
+
+func (h *maintenanceHost) SetStorage(
+	addr types.Address,
+	key types.Hash,
+	value types.Hash,
+	_ *chain.ForksInTime,
+) runtime.StorageStatus {
+	if h.storage[addr] == nil {
+		h.storage[addr] = map[types.Hash]types.Hash{}
+	}
+
+	h.storage[addr][key] = value
+
+	return runtime.StorageModified
+}
+
+func (h *maintenanceHost) EmitLog(_ types.Address, topics []types.Hash, _ []byte) {
+	h.logs = append(h.logs, topics)
+}
+
+func encodeMaintenance(t *testing.T, from, to uint64) []byte {
+	t.Helper()
+
+	input, err := MaintenanceMethod.Encode(map[string]interface{}{"fromBlock": from, "toBlock": to})
+	require.NoError(t, err)
+
+	return input
+}
+
+func TestMaintenance(t *testing.T) {
+	t.Parallel()
+
+	sender := types.StringToAddress("a1")
+	config := &chain.ForksInTime{Maintenance: true}
+
+	newContract := func(input []byte) *runtime.Contract {
+		contract := runtime.NewContractCall(1, sender, sender, MaintenanceAddr, nil, 100000, nil, input)
+		contract.CodeAddress = MaintenanceAddr
+
+		return contract
+	}
+
+	tests := []struct {
+		name     string
+		from, to uint64
+		err      error
+	}{
+		{
+			name: "future window",
+			from: 11,
+			to:   20,
+		},
+		{
+			name: "cancellation",
+		},
+		{
+			name: "started window",
+			from: 10,
+			to:   20,
+			err:  errMaintenanceWindow,
+		},
+		{
+			name: "reversed window",
+			from: 20,
+			to:   11,
+			err:  errMaintenanceWindow,
+		},
+		{
+			name: "too long window",
+			from: 11,
+			to:   11 + MaxMaintenanceWindow,
+			err:  errMaintenanceWindow,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			host := &maintenanceHost{number: 10, storage: map[types.Address]map[types.Hash]types.Hash{}}
+			p := NewPrecompiled()
+			contract := newContract(encodeMaintenance(t, tt.from, tt.to))
+
+			assert.False(t, p.CanRun(contract, host, &chain.ForksInTime{}))
+			require.True(t, p.CanRun(contract, host, config))
+
+			result := p.Run(contract, host, config)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, result.Err, tt.err)
+				assert.Empty(t, host.storage)
+
+				return
+			}
+
+			require.NoError(t, result.Err)
+			assert.Equal(t, uint64(100000-maintenanceGas), result.GasLeft)
+
+			from, to := DecodeMaintenanceWindow(host.storage[sender][MaintenanceSlot])
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+
+			assert.Equal(t, [][]types.Hash{{MaintenanceEventID, types.BytesToHash(sender.Bytes())}}, host.logs)
+		})
+	}
+
+	t.Run("call through a contract", func(t *testing.T) {
+		t.Parallel()
+
+		host := &maintenanceHost{number: 10, storage: map[types.Address]map[types.Hash]types.Hash{}}
+		contract := newContract(encodeMaintenance(t, 11, 20))
+		contract.Caller = types.StringToAddress("c1")
+
+		result := NewPrecompiled().Run(contract, host, config)
+		assert.ErrorIs(t, result.Err, errMaintenanceCaller)
+		assert.Empty(t, host.storage)
+	})
+
+	t.Run("static call", func(t *testing.T) {
+		t.Parallel()
+
+		host := &maintenanceHost{number: 10, storage: map[types.Address]map[types.Hash]types.Hash{}}
+		contract := newContract(encodeMaintenance(t, 11, 20))
+		contract.Type = runtime.StaticCall
+		contract.Static = true
+
+		result := NewPrecompiled().Run(contract, host, config)
+		assert.ErrorIs(t, result.Err, errMaintenanceCaller)
+	})
+}
@@ -0,0 +1,123 @@
+package precompiled
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// multicallBaseGas is the gas of the multicall precompile itself
+	multicallBaseGas = 700
+
+	// multicallCallGas is the gas of every call of the batch, on top of the gas used by the call
+	multicallCallGas = 700
+)
+
+var (
+	// MulticallAddr is the address of the multicall precompile
+	MulticallAddr = types.StringToAddress("2020")
+
+	// MulticallMethod is the interface of the multicall precompile. Every call of the batch is a static call
+	// using at most its gas limit, or the whole remaining gas if the limit is 0. A failed call doesn't revert
+	// the batch, its return data (the revert reason) is returned along with the results of the other calls
+	MulticallMethod, _ = abi.NewMethod("function aggregate(" +
+		"tuple(address target, uint64 gasLimit, bytes callData)[] calls) " +
+		"returns (tuple(bool success, uint64 gasUsed, bytes returnData)[] results)")
+
+	errMulticallInput  = errors.New("invalid multicall input")
+	errMulticallNoHost = errors.New("multicall requires a host")
+)
+
+// multicallCall is a call of the batch
+type multicallCall struct {
+	Target   ethgo.Address
+	GasLimit uint64
+	CallData []byte
+}
+
+// multicall is the precompile executing a batch of static calls
+type multicall struct{}
+
+func (m *multicall) gas(input []byte, config *chain.ForksInTime) uint64 {
+	return multicallBaseGas
+}
+
+func (m *multicall) run(input []byte) ([]byte, error) {
+	return nil, errMulticallNoHost
+}
+
+// runWithHost executes the calls of the batch through the host, and returns the results along with the gas left
+func (m *multicall) runWithHost(
+	c *runtime.Contract,
+	host runtime.Host,
+	_ *chain.ForksInTime,
+) ([]byte, uint64, error) {
+	if len(c.Input) < 4 || !bytes.Equal(c.Input[:4], MulticallMethod.ID()) {
+		return nil, 0, errMulticallInput
+	}
+
+	var args struct {
+		Calls []multicallCall
+	}
+
+	if err := abi.DecodeStruct(MulticallMethod.Inputs, c.Input[4:], &args); err != nil {
+		return nil, 0, errMulticallInput
+	}
+
+	var (
+		gas     = c.Gas
+		results = make([]map[string]interface{}, len(args.Calls))
+	)
+
+	for i, call := range args.Calls {
+		if gas < multicallCallGas {
+			return nil, 0, runtime.ErrOutOfGas
+		}
+
+		gas -= multicallCallGas
+
+		callGas := gas
+		if call.GasLimit != 0 && call.GasLimit < callGas {
+			callGas = call.GasLimit
+		}
+
+		target := types.Address(call.Target)
+
+		sub := runtime.NewContractCall(
+			c.Depth+1,
+			c.Origin,
+			c.Address,
+			target,
+			nil,
+			callGas,
+			host.GetCode(target),
+			call.CallData,
+		)
+		sub.Type = runtime.StaticCall
+		sub.Static = true
+
+		result := host.Callx(sub, host)
+		gasUsed := callGas - result.GasLeft
+		gas -= gasUsed
+
+		results[i] = map[string]interface{}{
+			"success":    result.Succeeded(),
+			"gasUsed":    gasUsed,
+			"returnData": result.ReturnValue,
+		}
+	}
+
+	output, err := abi.Encode(map[string]interface{}{"results": results}, MulticallMethod.Outputs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return output, gas, nil
+}
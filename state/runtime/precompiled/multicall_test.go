@@ -0,0 +1,128 @@
+package precompiled
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// multicallHost returns the result of the call for every target, using the given gas
+type multicallHost struct {
+	runtime.Host
+
+	results map[types.Address]*runtime.ExecutionResult
+	gasUsed uint64
+	calls   []*runtime.Contract
+}
+
+func (h *multicallHost) GetCode(types.Address) []byte {
+	return nil
+}
+
+func (h *multicallHost) Callx(c *runtime.Contract, _ runtime.Host) *runtime.ExecutionResult {
+	h.calls = append(h.calls, c)
+
+	result := h.results[c.Address]
+	if c.Gas < h.gasUsed {
+		return &runtime.ExecutionResult{Err: runtime.ErrOutOfGas}
+	}
+
+	return &runtime.ExecutionResult{
+		ReturnValue: result.ReturnValue,
+		GasLeft:     c.Gas - h.gasUsed,
+		Err:         result.Err,
+	}
+}
+
+func encodeMulticall(t *testing.T, calls ...map[string]interface{}) []byte {
+	t.Helper()
+
+	input, err := MulticallMethod.Encode(map[string]interface{}{"calls": calls})
+	require.NoError(t, err)
+
+	return input
+}
+
+func TestMulticall(t *testing.T) {
+	t.Parallel()
+
+	var (
+		target1 = types.StringToAddress("a1")
+		target2 = types.StringToAddress("a2")
+		caller  = types.StringToAddress("c1")
+	)
+
+	host := &multicallHost{
+		results: map[types.Address]*runtime.ExecutionResult{
+			target1: {ReturnValue: []byte{0x1}},
+			target2: {ReturnValue: []byte{0x2}, Err: runtime.ErrExecutionReverted},
+		},
+		gasUsed: 1000,
+	}
+
+	p := NewPrecompiled()
+	config := &chain.ForksInTime{Multicall: true}
+
+	contract := runtime.NewContractCall(1, caller, caller, MulticallAddr, nil, 10000, nil, encodeMulticall(t,
+		map[string]interface{}{"target": ethgo.Address(target1), "gasLimit": uint64(0), "callData": []byte{0x11}},
+		map[string]interface{}{"target": ethgo.Address(target2), "gasLimit": uint64(2000), "callData": []byte{0x22}},
+		// not enough gas for the call, the other calls aren't reverted
+		map[string]interface{}{"target": ethgo.Address(target1), "gasLimit": uint64(500), "callData": []byte{}},
+	))
+
+	assert.False(t, p.CanRun(contract, host, &chain.ForksInTime{}))
+	require.True(t, p.CanRun(contract, host, config))
+
+	result := p.Run(contract, host, config)
+	require.NoError(t, result.Err)
+
+	// the gas of the precompile, of every call, and of the executions
+	assert.Equal(t, uint64(10000-multicallBaseGas-3*multicallCallGas-1000-1000-500), result.GasLeft)
+
+	out, err := abi.Decode(MulticallMethod.Outputs, result.ReturnValue)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"results": []map[string]interface{}{
+			{"success": true, "gasUsed": uint64(1000), "returnData": []byte{0x1}},
+			{"success": false, "gasUsed": uint64(1000), "returnData": []byte{0x2}},
+			{"success": false, "gasUsed": uint64(500), "returnData": []byte{}},
+		},
+	}, out)
+
+	// every call is a static call made by the precompile, with its gas limit
+	require.Len(t, host.calls, 3)
+
+	for _, call := range host.calls {
+		assert.True(t, call.Static)
+		assert.Equal(t, runtime.StaticCall, call.Type)
+		assert.Equal(t, MulticallAddr, call.Caller)
+		assert.Equal(t, 2, call.Depth)
+	}
+
+	assert.Equal(t, uint64(10000-multicallBaseGas-multicallCallGas), host.calls[0].Gas)
+	assert.Equal(t, uint64(2000), host.calls[1].Gas)
+	assert.Equal(t, uint64(500), host.calls[2].Gas)
+}
+
+func TestMulticall_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	p := NewPrecompiled()
+	config := &chain.ForksInTime{Multicall: true}
+
+	for _, input := range [][]byte{nil, {0x1, 0x2, 0x3, 0x4}, MulticallMethod.ID()} {
+		contract := runtime.NewContractCall(1, types.ZeroAddress, types.ZeroAddress, MulticallAddr, nil, 10000, nil, input)
+
+		result := p.Run(contract, &multicallHost{}, config)
+		assert.ErrorIs(t, result.Err, errMulticallInput)
+		assert.Equal(t, uint64(0), result.GasLeft)
+	}
+}
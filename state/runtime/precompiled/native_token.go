@@ -0,0 +1,165 @@
+package precompiled
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/umbracle/ethgo/abi"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// nativeTokenWriteGas is the gas of minting and burning, which change a balance, a storage slot and emit a log
+	nativeTokenWriteGas = 30000
+
+	// nativeTokenReadGas is the gas of reading the supply counters, which read a storage slot
+	nativeTokenReadGas = 2100
+)
+
+var (
+	// NativeTokenAddr is the address of the native token precompile
+	NativeTokenAddr = types.StringToAddress("2023")
+
+	// NativeTokenMintMethod mints the amount to the account, it can only be called by the minter of the chain
+	NativeTokenMintMethod, _ = abi.NewMethod("function mint(address to, uint256 amount)")
+	// NativeTokenBurnMethod burns the value sent along with the call
+	NativeTokenBurnMethod, _ = abi.NewMethod("function burn()")
+	// NativeTokenMintedMethod returns the amount minted since the nativeToken fork
+	NativeTokenMintedMethod, _ = abi.NewMethod("function totalMinted() returns (uint256)")
+	// NativeTokenBurnedMethod returns the amount burned since the nativeToken fork, base fees included
+	NativeTokenBurnedMethod, _ = abi.NewMethod("function totalBurned() returns (uint256)")
+
+	// NativeTokenMintedSlot is the storage slot of the native token precompile holding the minted amount
+	NativeTokenMintedSlot = types.BytesToHash(crypto.Keccak256([]byte("polygon-edge.nativeToken.minted")))
+	// NativeTokenBurnedSlot is the storage slot of the native token precompile holding the burned amount
+	NativeTokenBurnedSlot = types.BytesToHash(crypto.Keccak256([]byte("polygon-edge.nativeToken.burned")))
+
+	// NativeTokenMintedEventID is the topic of the log emitted for every mint
+	NativeTokenMintedEventID = types.BytesToHash(crypto.Keccak256([]byte("Minted(address,uint256)")))
+	// NativeTokenBurnedEventID is the topic of the log emitted for every burn
+	NativeTokenBurnedEventID = types.BytesToHash(crypto.Keccak256([]byte("Burned(address,uint256)")))
+
+	errNativeTokenInput  = errors.New("invalid native token input")
+	errNativeTokenMinter = errors.New("the native token can only be minted by the minter")
+	errNativeTokenStatic = errors.New("the native token supply can't change in a static call")
+	errNativeTokenCall   = errors.New("the native token supply can only change in a direct call")
+	errNativeTokenNoHost = errors.New("native token requires a host")
+)
+
+// NativeTokenHost is the host changing the supply of the native token, which accounts the minted
+// and burned amounts in the storage of the native token precompile
+type NativeTokenHost interface {
+	// NativeTokenMinter returns the minter of the chain, false if the native token can't be minted
+	NativeTokenMinter() (types.Address, bool)
+	// MintNativeToken adds the amount to the balance of the account
+	MintNativeToken(to types.Address, amount *big.Int)
+	// BurnNativeToken removes the amount from the balance of the account
+	BurnNativeToken(from types.Address, amount *big.Int) error
+}
+
+// nativeToken is the precompile managing the supply of the native token from the nativeToken fork
+type nativeToken struct{}
+
+func (n *nativeToken) gas(input []byte, config *chain.ForksInTime) uint64 {
+	if len(input) >= 4 &&
+		(bytes.Equal(input[:4], NativeTokenMintedMethod.ID()) || bytes.Equal(input[:4], NativeTokenBurnedMethod.ID())) {
+		return nativeTokenReadGas
+	}
+
+	return nativeTokenWriteGas
+}
+
+func (n *nativeToken) run(input []byte) ([]byte, error) {
+	return nil, errNativeTokenNoHost
+}
+
+func (n *nativeToken) runWithHost(
+	c *runtime.Contract,
+	host runtime.Host,
+	config *chain.ForksInTime,
+) ([]byte, uint64, error) {
+	tokenHost, ok := host.(NativeTokenHost)
+	if !ok {
+		return nil, 0, errNativeTokenNoHost
+	}
+
+	if len(c.Input) < 4 {
+		return nil, 0, errNativeTokenInput
+	}
+
+	switch selector := c.Input[:4]; {
+	case bytes.Equal(selector, NativeTokenMintedMethod.ID()):
+		value := host.GetStorage(NativeTokenAddr, NativeTokenMintedSlot)
+
+		return value.Bytes(), c.Gas, nil
+	case bytes.Equal(selector, NativeTokenBurnedMethod.ID()):
+		value := host.GetStorage(NativeTokenAddr, NativeTokenBurnedSlot)
+
+		return value.Bytes(), c.Gas, nil
+	case bytes.Equal(selector, NativeTokenMintMethod.ID()):
+		if c.Static {
+			return nil, 0, errNativeTokenStatic
+		}
+
+		// a delegate call or a call code keeps the caller of the calling frame,
+		// any contract called by the minter could mint on its behalf
+		if c.Type != runtime.Call {
+			return nil, 0, errNativeTokenCall
+		}
+
+		if minter, ok := tokenHost.NativeTokenMinter(); !ok || c.Caller != minter {
+			return nil, 0, errNativeTokenMinter
+		}
+
+		var args struct {
+			To     types.Address
+			Amount *big.Int
+		}
+
+		if err := abi.DecodeStruct(NativeTokenMintMethod.Inputs, c.Input[4:], &args); err != nil {
+			return nil, 0, errNativeTokenInput
+		}
+
+		tokenHost.MintNativeToken(args.To, args.Amount)
+		host.EmitLog(
+			NativeTokenAddr,
+			[]types.Hash{NativeTokenMintedEventID, types.BytesToHash(args.To.Bytes())},
+			types.BytesToHash(args.Amount.Bytes()).Bytes(),
+		)
+
+		return nil, c.Gas, nil
+	case bytes.Equal(selector, NativeTokenBurnMethod.ID()):
+		if c.Static {
+			return nil, 0, errNativeTokenStatic
+		}
+
+		// a delegate call or a call code doesn't transfer the value to the precompile
+		if c.Type != runtime.Call {
+			return nil, 0, errNativeTokenCall
+		}
+
+		// the value was transferred to the precompile by the call
+		if c.Value == nil || c.Value.Sign() == 0 {
+			return nil, c.Gas, nil
+		}
+
+		if err := tokenHost.BurnNativeToken(NativeTokenAddr, c.Value); err != nil {
+			return nil, 0, err
+		}
+
+		host.EmitLog(
+			NativeTokenAddr,
+			[]types.Hash{NativeTokenBurnedEventID, types.BytesToHash(c.Caller.Bytes())},
+			types.BytesToHash(c.Value.Bytes()).Bytes(),
+		)
+
+		return nil, c.Gas, nil
+	default:
+		return nil, 0, errNativeTokenInput
+	}
+}
@@ -0,0 +1,188 @@
+package precompiled
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// nativeTokenHost keeps the balances and the supply counters changed by the precompile
+type nativeTokenHost struct {
+	runtime.Host
+
+	minter   *types.Address
+	balances map[types.Address]*big.Int
+	storage  map[types.Hash]*big.Int
+	logs     [][]types.Hash
+}
+
+func newNativeTokenHost(minter *types.Address) *nativeTokenHost {
+	return &nativeTokenHost{
+		minter:   minter,
+		balances: map[types.Address]*big.Int{},
+		storage:  map[types.Hash]*big.Int{},
+	}
+}
+
+func (h *nativeTokenHost) add(m map[types.Hash]*big.Int, key types.Hash, amount *big.Int) {
+	if m[key] == nil {
+		m[key] = new(big.Int)
+	}
+
+	m[key].Add(m[key], amount)
+}
+
+func (h *nativeTokenHost) NativeTokenMinter() (types.Address, bool) {
+	if h.minter == nil {
+		return types.ZeroAddress, false
+	}
+
+	return *h.minter, true
+}
+
+func (h *nativeTokenHost) MintNativeToken(to types.Address, amount *big.Int) {
+	if h.balances[to] == nil {
+		h.balances[to] = new(big.Int)
+	}
+
+	h.balances[to].Add(h.balances[to], amount)
+	h.add(h.storage, NativeTokenMintedSlot, amount)
+}
+
+func (h *nativeTokenHost) BurnNativeToken(from types.Address, amount *big.Int) error {
+	if h.balances[from] == nil || h.balances[from].Cmp(amount) < 0 {
+		return runtime.ErrNotEnoughFunds
+	}
+
+	h.balances[from].Sub(h.balances[from], amount)
+	h.add(h.storage, NativeTokenBurnedSlot, amount)
+
+	return nil
+}
+
+func (h *nativeTokenHost) GetStorage(_ types.Address, key types.Hash) types.Hash {
+	if h.storage[key] == nil {
+		return types.ZeroHash
+	}
+
+	return types.BytesToHash(h.storage[key].Bytes())
+}
+
+func (h *nativeTokenHost) EmitLog(_ types.Address, topics []types.Hash, _ []byte) {
+	h.logs = append(h.logs, topics)
+}
+
+func TestNativeToken(t *testing.T) {
+	t.Parallel()
+
+	var (
+		minter    = types.StringToAddress("a1")
+		recipient = types.StringToAddress("b1")
+		config    = &chain.ForksInTime{NativeToken: true}
+	)
+
+	newContract := func(caller types.Address, value *big.Int, input []byte) *runtime.Contract {
+		contract := runtime.NewContractCall(1, caller, caller, NativeTokenAddr, value, 100000, nil, input)
+		contract.CodeAddress = NativeTokenAddr
+
+		return contract
+	}
+
+	encodeMint := func(t *testing.T, amount int64) []byte {
+		t.Helper()
+
+		input, err := NativeTokenMintMethod.Encode(map[string]interface{}{"to": recipient, "amount": big.NewInt(amount)})
+		require.NoError(t, err)
+
+		return input
+	}
+
+	t.Run("the minter mints", func(t *testing.T) {
+		t.Parallel()
+
+		host := newNativeTokenHost(&minter)
+		p := NewPrecompiled()
+		contract := newContract(minter, nil, encodeMint(t, 100))
+
+		assert.False(t, p.CanRun(contract, host, &chain.ForksInTime{}))
+		require.True(t, p.CanRun(contract, host, config))
+
+		result := p.Run(contract, host, config)
+		require.NoError(t, result.Err)
+		assert.Equal(t, uint64(100000-nativeTokenWriteGas), result.GasLeft)
+		assert.Equal(t, big.NewInt(100), host.balances[recipient])
+		assert.Equal(t, [][]types.Hash{{NativeTokenMintedEventID, types.BytesToHash(recipient.Bytes())}}, host.logs)
+
+		result = p.Run(newContract(recipient, nil, NativeTokenMintedMethod.ID()), host, config)
+		require.NoError(t, result.Err)
+		assert.Equal(t, uint64(100000-nativeTokenReadGas), result.GasLeft)
+		assert.Equal(t, types.BytesToHash(big.NewInt(100).Bytes()).Bytes(), result.ReturnValue)
+	})
+
+	t.Run("only the minter mints", func(t *testing.T) {
+		t.Parallel()
+
+		for _, host := range []*nativeTokenHost{newNativeTokenHost(&minter), newNativeTokenHost(nil)} {
+			result := NewPrecompiled().Run(newContract(recipient, nil, encodeMint(t, 100)), host, config)
+			assert.ErrorIs(t, result.Err, errNativeTokenMinter)
+			assert.Empty(t, host.balances)
+		}
+	})
+
+	t.Run("static call", func(t *testing.T) {
+		t.Parallel()
+
+		host := newNativeTokenHost(&minter)
+		contract := newContract(minter, nil, encodeMint(t, 100))
+		contract.Type = runtime.StaticCall
+		contract.Static = true
+
+		result := NewPrecompiled().Run(contract, host, config)
+		assert.ErrorIs(t, result.Err, errNativeTokenStatic)
+	})
+
+	t.Run("delegate call from a contract called by the minter", func(t *testing.T) {
+		t.Parallel()
+
+		contractAddr := types.StringToAddress("c1")
+
+		for _, callType := range []runtime.CallType{runtime.DelegateCall, runtime.CallCode} {
+			for _, input := range [][]byte{encodeMint(t, 100), NativeTokenBurnMethod.ID()} {
+				host := newNativeTokenHost(&minter)
+				host.balances[contractAddr] = big.NewInt(40)
+
+				// the frame of the contract keeps the minter as the caller
+				contract := runtime.NewContractCall(2, minter, minter, contractAddr, big.NewInt(40), 100000, nil, input)
+				contract.CodeAddress = NativeTokenAddr
+				contract.Type = callType
+
+				result := NewPrecompiled().Run(contract, host, config)
+				assert.ErrorIs(t, result.Err, errNativeTokenCall)
+				assert.Nil(t, host.balances[recipient])
+				assert.Equal(t, big.NewInt(40), host.balances[contractAddr])
+				assert.Empty(t, host.storage)
+				assert.Empty(t, host.logs)
+			}
+		}
+	})
+
+	t.Run("the value sent is burned", func(t *testing.T) {
+		t.Parallel()
+
+		host := newNativeTokenHost(&minter)
+		// the value was transferred to the precompile by the call
+		host.balances[NativeTokenAddr] = big.NewInt(40)
+
+		result := NewPrecompiled().Run(newContract(recipient, big.NewInt(40), NativeTokenBurnMethod.ID()), host, config)
+		require.NoError(t, result.Err)
+		assert.Zero(t, host.balances[NativeTokenAddr].Sign())
+		assert.Equal(t, big.NewInt(40), host.storage[NativeTokenBurnedSlot])
+		assert.Equal(t, [][]types.Hash{{NativeTokenBurnedEventID, types.BytesToHash(recipient.Bytes())}}, host.logs)
+	})
+}
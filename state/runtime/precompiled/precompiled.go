@@ -15,17 +15,32 @@ type contract interface {
 	run(input []byte) ([]byte, error)
 }
 
+// hostContract is a precompiled contract calling other contracts through the host,
+// the gas used by the calls is paid out of the gas left after its own gas
+type hostContract interface {
+	contract
+	runWithHost(c *runtime.Contract, host runtime.Host, config *chain.ForksInTime) ([]byte, uint64, error)
+}
+
 // Precompiled is the runtime for the precompiled contracts
 type Precompiled struct {
 	buf       []byte
 	contracts map[types.Address]contract
 }
 
-// NewPrecompiled creates a new runtime for the precompiled contracts
-func NewPrecompiled() *Precompiled {
+// NewPrecompiled creates a new runtime for the precompiled contracts,
+// along with the chain specific ones
+func NewPrecompiled(custom ...*Stateful) *Precompiled {
 	p := &Precompiled{}
 	p.setupContracts()
 
+	for _, s := range custom {
+		p.contracts[s.Address] = &stateful{
+			activation: s.Activation,
+			contract:   s.Contract,
+		}
+	}
+
 	return p
 }
 
@@ -43,6 +58,21 @@ func (p *Precompiled) setupContracts() {
 
 	// Istanbul fork
 	p.register("9", &blake2f{p})
+
+	// Multicall fork
+	p.contracts[MulticallAddr] = &multicall{}
+
+	// Decompress fork
+	p.contracts[DecompressAddr] = &decompress{}
+
+	// Maintenance fork
+	p.contracts[MaintenanceAddr] = &maintenance{}
+
+	// NativeToken fork
+	p.contracts[NativeTokenAddr] = &nativeToken{}
+
+	// Allowlist fork
+	p.contracts[AllowlistAddr] = &allowlist{}
 }
 
 func (p *Precompiled) register(addrStr string, b contract) {
@@ -53,6 +83,13 @@ func (p *Precompiled) register(addrStr string, b contract) {
 	p.contracts[types.StringToAddress(addrStr)] = b
 }
 
+// IsPrecompiled checks if the address is the address of a precompiled contract
+func (p *Precompiled) IsPrecompiled(addr types.Address) bool {
+	_, ok := p.contracts[addr]
+
+	return ok
+}
+
 var (
 	five  = types.StringToAddress("5")
 	six   = types.StringToAddress("6")
@@ -62,11 +99,17 @@ var (
 )
 
 // CanRun implements the runtime interface
-func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.ForksInTime) bool {
-	if _, ok := p.contracts[c.CodeAddress]; !ok {
+func (p *Precompiled) CanRun(c *runtime.Contract, host runtime.Host, config *chain.ForksInTime) bool {
+	contract, ok := p.contracts[c.CodeAddress]
+	if !ok {
 		return false
 	}
 
+	// chain specific precompiles
+	if s, ok := contract.(*stateful); ok {
+		return s.active(host)
+	}
+
 	// byzantium precompiles
 	switch c.CodeAddress {
 	case five:
@@ -85,6 +128,26 @@ func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.
 		return config.Istanbul
 	}
 
+	if c.CodeAddress == MulticallAddr {
+		return config.Multicall
+	}
+
+	if c.CodeAddress == DecompressAddr {
+		return config.Decompress
+	}
+
+	if c.CodeAddress == MaintenanceAddr {
+		return config.Maintenance
+	}
+
+	if c.CodeAddress == NativeTokenAddr {
+		return config.NativeToken
+	}
+
+	if c.CodeAddress == AllowlistAddr {
+		return config.Allowlist
+	}
+
 	return true
 }
 
@@ -94,7 +157,7 @@ func (p *Precompiled) Name() string {
 }
 
 // Run runs an execution
-func (p *Precompiled) Run(c *runtime.Contract, _ runtime.Host, config *chain.ForksInTime) *runtime.ExecutionResult {
+func (p *Precompiled) Run(c *runtime.Contract, host runtime.Host, config *chain.ForksInTime) *runtime.ExecutionResult {
 	contract := p.contracts[c.CodeAddress]
 	gasCost := contract.gas(c.Input, config)
 
@@ -107,7 +170,17 @@ func (p *Precompiled) Run(c *runtime.Contract, _ runtime.Host, config *chain.For
 	}
 
 	c.Gas = c.Gas - gasCost
-	returnValue, err := contract.run(c.Input)
+
+	var (
+		returnValue []byte
+		err         error
+	)
+
+	if hc, ok := contract.(hostContract); ok {
+		returnValue, c.Gas, err = hc.runWithHost(c, host, config)
+	} else {
+		returnValue, err = contract.run(c.Input)
+	}
 
 	result := &runtime.ExecutionResult{
 		ReturnValue: returnValue,
@@ -0,0 +1,127 @@
+package precompiled
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	errStatefulNoHost = errors.New("stateful precompile requires a host")
+
+	ErrUnknownPrecompile    = errors.New("unknown precompile")
+	ErrPrecompileAddrInUse  = errors.New("precompile address already in use")
+	ErrPrecompileRegistered = errors.New("precompile already registered")
+)
+
+// StatefulContract is a chain specific precompiled contract, configured in the chain params.
+// It accesses the state through the host, and must check by itself whether it can write to it
+type StatefulContract interface {
+	// RequiredGas returns the gas charged before the contract runs
+	RequiredGas(input []byte) uint64
+
+	// Run runs the call, and returns its output along with the gas left
+	Run(c *runtime.Contract, host runtime.Host, config *chain.ForksInTime) ([]byte, uint64, error)
+}
+
+// StatefulFactory creates the contract from its config in the chain params
+type StatefulFactory func(config map[string]interface{}) (StatefulContract, error)
+
+var (
+	statefulFactoriesLock sync.RWMutex
+	statefulFactories     = map[string]StatefulFactory{}
+)
+
+// RegisterStatefulFactory registers the factory of the contracts with the given name,
+// so they can be added to a chain in its params. It is meant to be called on init
+func RegisterStatefulFactory(name string, factory StatefulFactory) error {
+	statefulFactoriesLock.Lock()
+	defer statefulFactoriesLock.Unlock()
+
+	if _, ok := statefulFactories[name]; ok {
+		return fmt.Errorf("%w: %s", ErrPrecompileRegistered, name)
+	}
+
+	statefulFactories[name] = factory
+
+	return nil
+}
+
+// Stateful is a chain specific contract at its address, enabled from the activation block
+type Stateful struct {
+	Address    types.Address
+	Activation chain.Fork
+	Contract   StatefulContract
+}
+
+// NewStatefulContracts creates the chain specific contracts of the chain params
+func NewStatefulContracts(params []*chain.Precompile) ([]*Stateful, error) {
+	builtin := NewPrecompiled()
+	contracts := make([]*Stateful, 0, len(params))
+	addrs := make(map[types.Address]struct{}, len(params))
+
+	statefulFactoriesLock.RLock()
+	defer statefulFactoriesLock.RUnlock()
+
+	for _, param := range params {
+		factory, ok := statefulFactories[param.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPrecompile, param.Name)
+		}
+
+		if _, ok := addrs[param.Address]; ok || builtin.IsPrecompiled(param.Address) {
+			return nil, fmt.Errorf("%w: %s", ErrPrecompileAddrInUse, param.Address)
+		}
+
+		contract, err := factory(param.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create precompile %s: %w", param.Name, err)
+		}
+
+		addrs[param.Address] = struct{}{}
+		contracts = append(contracts, &Stateful{
+			Address:    param.Address,
+			Activation: param.Activation,
+			Contract:   contract,
+		})
+	}
+
+	return contracts, nil
+}
+
+// stateful runs a chain specific contract as a host contract
+type stateful struct {
+	activation chain.Fork
+	contract   StatefulContract
+}
+
+func (s *stateful) gas(input []byte, config *chain.ForksInTime) uint64 {
+	return s.contract.RequiredGas(input)
+}
+
+func (s *stateful) run(input []byte) ([]byte, error) {
+	return nil, errStatefulNoHost
+}
+
+func (s *stateful) runWithHost(
+	c *runtime.Contract,
+	host runtime.Host,
+	config *chain.ForksInTime,
+) ([]byte, uint64, error) {
+	return s.contract.Run(c, host, config)
+}
+
+// active checks if the contract is enabled in the block of the host
+func (s *stateful) active(host runtime.Host) bool {
+	if host == nil {
+		return false
+	}
+
+	ctx := host.GetTxContext()
+
+	return s.activation.Active(uint64(ctx.Number), uint64(ctx.Timestamp))
+}
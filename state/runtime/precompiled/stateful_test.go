@@ -0,0 +1,139 @@
+package precompiled
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var errStaticStore = errors.New("static call to the store contract")
+
+// storeContract writes the input to the storage of the caller, as the configured slot
+type storeContract struct {
+	slot types.Hash
+}
+
+func (s *storeContract) RequiredGas([]byte) uint64 {
+	return 100
+}
+
+func (s *storeContract) Run(c *runtime.Contract, host runtime.Host, config *chain.ForksInTime) ([]byte, uint64, error) {
+	if c.Static {
+		return nil, 0, errStaticStore
+	}
+
+	host.SetStorage(c.Caller, s.slot, types.BytesToHash(c.Input), config)
+
+	return c.Input, c.Gas, nil
+}
+
+func newStoreContract(config map[string]interface{}) (StatefulContract, error) {
+	slot, ok := config["slot"].(string)
+	if !ok {
+		return nil, errors.New("slot not set")
+	}
+
+	return &storeContract{slot: types.StringToHash(slot)}, nil
+}
+
+func TestNewStatefulContracts(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, RegisterStatefulFactory("test-store", newStoreContract))
+	assert.ErrorIs(t, RegisterStatefulFactory("test-store", newStoreContract), ErrPrecompileRegistered)
+
+	addr := types.StringToAddress("3000")
+	config := map[string]interface{}{"slot": "1"}
+
+	contracts, err := NewStatefulContracts([]*chain.Precompile{
+		{Name: "test-store", Address: addr, Activation: *chain.NewFork(10), Config: config},
+	})
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+
+	assert.Equal(t, addr, contracts[0].Address)
+	assert.Equal(t, *chain.NewFork(10), contracts[0].Activation)
+
+	cases := []struct {
+		name   string
+		params []*chain.Precompile
+		err    error
+	}{
+		{
+			"unknown name",
+			[]*chain.Precompile{{Name: "unknown", Address: addr}},
+			ErrUnknownPrecompile,
+		},
+		{
+			"address of a builtin precompile",
+			[]*chain.Precompile{{Name: "test-store", Address: MaintenanceAddr, Config: config}},
+			ErrPrecompileAddrInUse,
+		},
+		{
+			"same address twice",
+			[]*chain.Precompile{
+				{Name: "test-store", Address: addr, Config: config},
+				{Name: "test-store", Address: addr, Config: config},
+			},
+			ErrPrecompileAddrInUse,
+		},
+	}
+
+	for _, c := range cases {
+		_, err := NewStatefulContracts(c.params)
+		assert.ErrorIs(t, err, c.err, c.name)
+	}
+
+	// the errors of the factory
+	_, err = NewStatefulContracts([]*chain.Precompile{{Name: "test-store", Address: addr}})
+	assert.EqualError(t, err, "failed to create precompile test-store: slot not set")
+}
+
+func TestStateful_Run(t *testing.T) {
+	t.Parallel()
+
+	addr := types.StringToAddress("3001")
+	caller := types.StringToAddress("a1")
+	slot := types.StringToHash("1")
+	config := &chain.ForksInTime{}
+
+	p := NewPrecompiled(&Stateful{
+		Address:    addr,
+		Activation: *chain.NewFork(10),
+		Contract:   &storeContract{slot: slot},
+	})
+
+	assert.True(t, p.IsPrecompiled(addr))
+
+	host := &maintenanceHost{number: 9, storage: map[types.Address]map[types.Hash]types.Hash{}}
+	input := types.StringToHash("2").Bytes()
+
+	contract := runtime.NewContractCall(1, caller, caller, addr, nil, 1000, nil, input)
+
+	// not activated yet
+	assert.False(t, p.CanRun(contract, host, config))
+
+	host.number = 10
+	require.True(t, p.CanRun(contract, host, config))
+
+	result := p.Run(contract, host, config)
+	require.NoError(t, result.Err)
+
+	assert.Equal(t, input, result.ReturnValue)
+	assert.Equal(t, uint64(900), result.GasLeft)
+	assert.Equal(t, types.BytesToHash(input), host.storage[caller][slot])
+
+	// the contract rejects the static calls
+	contract = runtime.NewContractCall(1, caller, caller, addr, nil, 1000, nil, input)
+	contract.Static = true
+
+	result = p.Run(contract, host, config)
+	assert.ErrorIs(t, result.Err, errStaticStore)
+	assert.Equal(t, uint64(0), result.GasLeft)
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Tracer collects the execution traces of a transaction
+type Tracer interface {
+	// TxStart is called before the transaction is applied to the state
+	TxStart(host Host, tx *types.Transaction)
+
+	// TxEnd is called once the transaction has been executed
+	TxEnd(result *ExecutionResult)
+
+	// CallStart is called when a new call frame (or contract creation) is entered
+	CallStart(depth int, callType CallType, from, to types.Address, input []byte, gas uint64, value *big.Int)
+
+	// CallEnd is called when a call frame is exited
+	CallEnd(depth int, output []byte, gasUsed uint64, err error)
+
+	// ExecuteState is called before each instruction is executed by the EVM
+	ExecuteState(step *ExecutionStep)
+}
+
+// ExecutionStep is the EVM state right before an instruction is executed.
+// The stack and memory are owned by the EVM and have to be copied if retained
+type ExecutionStep struct {
+	Host     Host
+	Contract *Contract
+
+	PC    uint64
+	Op    int
+	Gas   uint64
+	Cost  uint64
+	Depth int
+
+	Stack  []*big.Int // ordered from the bottom to the top of the stack
+	Memory []byte
+}
+
+// StackBack returns the n-th element counting from the top of the stack
+func (s *ExecutionStep) StackBack(n int) *big.Int {
+	if n >= len(s.Stack) {
+		return nil
+	}
+
+	return s.Stack[len(s.Stack)-n-1]
+}
@@ -0,0 +1,123 @@
+package tracer
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// AccessListTracer collects the accounts and storage slots accessed by the transaction, as an access list.
+// The sender, the recipient and the precompiled contracts are accessed by any transaction,
+// so they are only listed along with their accessed storage slots
+type AccessListTracer struct {
+	precompiles *precompiled.Precompiled
+	excluded    map[types.Address]struct{}
+
+	list    types.TxAccessList
+	indexes map[types.Address]int
+	slots   map[types.Address]map[types.Hash]struct{}
+}
+
+// NewAccessListTracer creates a new access list tracer
+func NewAccessListTracer() *AccessListTracer {
+	return &AccessListTracer{
+		precompiles: precompiled.NewPrecompiled(),
+		excluded:    make(map[types.Address]struct{}),
+		list:        types.TxAccessList{},
+		indexes:     make(map[types.Address]int),
+		slots:       make(map[types.Address]map[types.Hash]struct{}),
+	}
+}
+
+func (a *AccessListTracer) TxStart(_ runtime.Host, tx *types.Transaction) {
+	a.excluded[tx.From] = struct{}{}
+}
+
+func (a *AccessListTracer) TxEnd(*runtime.ExecutionResult) {}
+
+func (a *AccessListTracer) CallStart(
+	depth int,
+	_ runtime.CallType,
+	_, to types.Address,
+	_ []byte,
+	_ uint64,
+	_ *big.Int,
+) {
+	// the recipient of the transaction, which is the created contract for a contract creation
+	if depth == 1 {
+		a.excluded[to] = struct{}{}
+
+		return
+	}
+
+	// the calls made by a precompiled contract aren't seen as opcodes
+	a.addAddress(to)
+}
+
+func (a *AccessListTracer) CallEnd(int, []byte, uint64, error) {}
+
+func (a *AccessListTracer) ExecuteState(step *runtime.ExecutionStep) {
+	switch evm.OpCode(step.Op) {
+	case evm.SLOAD, evm.SSTORE:
+		if key := step.StackBack(0); key != nil {
+			a.addSlot(step.Contract.Address, types.BytesToHash(key.Bytes()))
+		}
+	case evm.BALANCE, evm.EXTCODESIZE, evm.EXTCODECOPY, evm.EXTCODEHASH, evm.SELFDESTRUCT:
+		if addr := step.StackBack(0); addr != nil {
+			a.addAddress(types.BytesToAddress(addr.Bytes()))
+		}
+	case evm.CALL, evm.CALLCODE, evm.DELEGATECALL, evm.STATICCALL:
+		if addr := step.StackBack(1); addr != nil {
+			a.addAddress(types.BytesToAddress(addr.Bytes()))
+		}
+	}
+}
+
+// addAddress adds the account to the access list, if it isn't excluded or listed already
+func (a *AccessListTracer) addAddress(addr types.Address) {
+	if _, ok := a.excluded[addr]; ok || a.precompiles.IsPrecompiled(addr) {
+		return
+	}
+
+	a.listAddress(addr)
+}
+
+// addSlot adds the storage slot of the account to the access list, if it isn't listed already
+func (a *AccessListTracer) addSlot(addr types.Address, slot types.Hash) {
+	idx := a.listAddress(addr)
+
+	if _, ok := a.slots[addr][slot]; ok {
+		return
+	}
+
+	a.slots[addr][slot] = struct{}{}
+	a.list[idx].StorageKeys = append(a.list[idx].StorageKeys, slot)
+}
+
+// listAddress adds the account to the access list if it isn't listed already, and returns its index
+func (a *AccessListTracer) listAddress(addr types.Address) int {
+	if idx, ok := a.indexes[addr]; ok {
+		return idx
+	}
+
+	a.list = append(a.list, types.AccessTuple{
+		Address:     addr,
+		StorageKeys: []types.Hash{},
+	})
+	a.indexes[addr] = len(a.list) - 1
+	a.slots[addr] = make(map[types.Hash]struct{})
+
+	return len(a.list) - 1
+}
+
+// AccessList returns the accounts and storage slots accessed by the transaction, in the access order
+func (a *AccessListTracer) AccessList() types.TxAccessList {
+	return a.list
+}
+
+func (a *AccessListTracer) GetResult() (interface{}, error) {
+	return a.list, nil
+}
@@ -0,0 +1,116 @@
+package tracer
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// CallFrame is a single call (or contract creation) made during the execution
+type CallFrame struct {
+	Type    string        `json:"type"`
+	From    types.Address `json:"from"`
+	To      types.Address `json:"to"`
+	Value   string        `json:"value,omitempty"`
+	Gas     string        `json:"gas"`
+	GasUsed string        `json:"gasUsed"`
+	Input   string        `json:"input"`
+	Output  string        `json:"output,omitempty"`
+	Error   string        `json:"error,omitempty"`
+	Calls   []*CallFrame  `json:"calls,omitempty"`
+}
+
+// CallTracer collects the tree of calls made by the transaction
+type CallTracer struct {
+	// stack of the call frames currently being executed
+	frames []*CallFrame
+	root   *CallFrame
+
+	gasLimit uint64
+}
+
+// NewCallTracer creates a new call tracer
+func NewCallTracer() *CallTracer {
+	return &CallTracer{
+		frames: make([]*CallFrame, 0),
+	}
+}
+
+func (c *CallTracer) TxStart(_ runtime.Host, tx *types.Transaction) {
+	c.gasLimit = tx.Gas
+}
+
+func (c *CallTracer) TxEnd(result *runtime.ExecutionResult) {
+	if c.root == nil {
+		return
+	}
+
+	// the top level frame accounts for the gas of the whole transaction
+	c.root.Gas = hex.EncodeUint64(c.gasLimit)
+	c.root.GasUsed = hex.EncodeUint64(result.GasUsed)
+}
+
+func (c *CallTracer) CallStart(
+	_ int,
+	callType runtime.CallType,
+	from, to types.Address,
+	input []byte,
+	gas uint64,
+	value *big.Int,
+) {
+	frame := &CallFrame{
+		Type:  callTypeToString(callType),
+		From:  from,
+		To:    to,
+		Gas:   hex.EncodeUint64(gas),
+		Input: hex.EncodeToHex(input),
+	}
+
+	if value != nil && callType != runtime.DelegateCall && callType != runtime.StaticCall {
+		frame.Value = hex.EncodeBig(value)
+	}
+
+	c.frames = append(c.frames, frame)
+}
+
+func (c *CallTracer) CallEnd(_ int, output []byte, gasUsed uint64, err error) {
+	if len(c.frames) == 0 {
+		return
+	}
+
+	frame := c.frames[len(c.frames)-1]
+	c.frames = c.frames[:len(c.frames)-1]
+
+	frame.GasUsed = hex.EncodeUint64(gasUsed)
+
+	if err != nil {
+		frame.Error = err.Error()
+	}
+
+	// the output of reverted calls holds the revert reason
+	if err == nil || errors.Is(err, runtime.ErrExecutionReverted) {
+		frame.Output = hex.EncodeToHex(output)
+	}
+
+	if len(c.frames) == 0 {
+		c.root = frame
+
+		return
+	}
+
+	parent := c.frames[len(c.frames)-1]
+	parent.Calls = append(parent.Calls, frame)
+}
+
+func (c *CallTracer) ExecuteState(*runtime.ExecutionStep) {}
+
+func (c *CallTracer) GetResult() (interface{}, error) {
+	if c.root == nil {
+		return nil, errors.New("no call frames captured")
+	}
+
+	return c.root, nil
+}
@@ -0,0 +1,122 @@
+package tracer
+
+import (
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// FlatTraceAction is the call (or contract creation) of a flat trace
+type FlatTraceAction struct {
+	CallType string         `json:"callType,omitempty"`
+	From     types.Address  `json:"from"`
+	To       *types.Address `json:"to,omitempty"`
+	Gas      string         `json:"gas"`
+	Input    string         `json:"input,omitempty"`
+	Init     string         `json:"init,omitempty"`
+	Value    string         `json:"value"`
+}
+
+// FlatTraceResult is the result of the successful call (or contract creation) of a flat trace
+type FlatTraceResult struct {
+	Address *types.Address `json:"address,omitempty"`
+	Code    string         `json:"code,omitempty"`
+	GasUsed string         `json:"gasUsed"`
+	Output  string         `json:"output,omitempty"`
+}
+
+// FlatTrace is a call frame in the (OpenEthereum/Parity) flat trace format,
+// the trace address is the path to the frame in the tree of calls
+type FlatTrace struct {
+	Action       *FlatTraceAction `json:"action"`
+	Result       *FlatTraceResult `json:"result"`
+	Error        string           `json:"error,omitempty"`
+	Subtraces    int              `json:"subtraces"`
+	TraceAddress []int            `json:"traceAddress"`
+	Type         string           `json:"type"`
+}
+
+// FlatCallTracer collects the calls made by the transaction as a list of flat traces
+type FlatCallTracer struct {
+	*CallTracer
+}
+
+// NewFlatCallTracer creates a new flat call tracer
+func NewFlatCallTracer() *FlatCallTracer {
+	return &FlatCallTracer{
+		CallTracer: NewCallTracer(),
+	}
+}
+
+func (f *FlatCallTracer) GetResult() (interface{}, error) {
+	return f.FlatTraces()
+}
+
+// FlatTraces returns the collected calls in the depth first order
+func (f *FlatCallTracer) FlatTraces() ([]*FlatTrace, error) {
+	res, err := f.CallTracer.GetResult()
+	if err != nil {
+		return nil, err
+	}
+
+	root, _ := res.(*CallFrame)
+
+	return flattenCallFrame(root, []int{}, nil), nil
+}
+
+// flattenCallFrame appends the traces of the frame and of its sub calls
+func flattenCallFrame(frame *CallFrame, address []int, traces []*FlatTrace) []*FlatTrace {
+	trace := &FlatTrace{
+		Action: &FlatTraceAction{
+			From:  frame.From,
+			Gas:   frame.Gas,
+			Value: frame.Value,
+		},
+		Subtraces:    len(frame.Calls),
+		TraceAddress: address,
+	}
+
+	if trace.Action.Value == "" {
+		trace.Action.Value = "0x0"
+	}
+
+	to := frame.To
+	isCreate := frame.Type == callTypeToString(runtime.Create) || frame.Type == callTypeToString(runtime.Create2)
+
+	if isCreate {
+		trace.Type = "create"
+		trace.Action.Init = frame.Input
+	} else {
+		trace.Type = "call"
+		trace.Action.CallType = strings.ToLower(frame.Type)
+		trace.Action.To = &to
+		trace.Action.Input = frame.Input
+	}
+
+	switch {
+	case frame.Error == runtime.ErrExecutionReverted.Error():
+		trace.Error = "Reverted"
+	case frame.Error != "":
+		trace.Error = frame.Error
+	case isCreate:
+		trace.Result = &FlatTraceResult{
+			Address: &to,
+			Code:    frame.Output,
+			GasUsed: frame.GasUsed,
+		}
+	default:
+		trace.Result = &FlatTraceResult{
+			GasUsed: frame.GasUsed,
+			Output:  frame.Output,
+		}
+	}
+
+	traces = append(traces, trace)
+
+	for i, call := range frame.Calls {
+		traces = flattenCallFrame(call, append(address[:len(address):len(address)], i), traces)
+	}
+
+	return traces
+}
@@ -0,0 +1,128 @@
+package tracer
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// OpcodeStats is the distribution of the opcodes executed in a block,
+// along with the contracts which consumed the most gas
+type OpcodeStats struct {
+	Number       uint64            `json:"number"`
+	Hash         types.Hash        `json:"hash"`
+	TotalOps     uint64            `json:"totalOps"`
+	Opcodes      map[string]uint64 `json:"opcodes"`
+	HotContracts []*ContractGas    `json:"hotContracts"`
+}
+
+// ContractGas is the gas consumed by the code of a contract,
+// excluding the gas consumed by the contracts it calls
+type ContractGas struct {
+	Address types.Address `json:"address"`
+	GasUsed uint64        `json:"gasUsed"`
+}
+
+// opcodeStatsFrame is a call frame being executed
+type opcodeStatsFrame struct {
+	address types.Address
+	// gas used by the sub calls of the frame
+	childGasUsed uint64
+}
+
+// OpcodeStatsTracer counts the executed opcodes and the gas consumed by each contract,
+// across all the transactions it traces
+type OpcodeStatsTracer struct {
+	counts      [256]uint64
+	contractGas map[types.Address]uint64
+
+	frames []*opcodeStatsFrame
+}
+
+// NewOpcodeStatsTracer creates a new opcode stats tracer
+func NewOpcodeStatsTracer() *OpcodeStatsTracer {
+	return &OpcodeStatsTracer{
+		contractGas: make(map[types.Address]uint64),
+		frames:      make([]*opcodeStatsFrame, 0),
+	}
+}
+
+func (o *OpcodeStatsTracer) TxStart(_ runtime.Host, _ *types.Transaction) {
+	o.frames = o.frames[:0]
+}
+
+func (o *OpcodeStatsTracer) TxEnd(_ *runtime.ExecutionResult) {}
+
+func (o *OpcodeStatsTracer) CallStart(
+	_ int,
+	_ runtime.CallType,
+	_, to types.Address,
+	_ []byte,
+	_ uint64,
+	_ *big.Int,
+) {
+	o.frames = append(o.frames, &opcodeStatsFrame{address: to})
+}
+
+func (o *OpcodeStatsTracer) CallEnd(_ int, _ []byte, gasUsed uint64, _ error) {
+	if len(o.frames) == 0 {
+		return
+	}
+
+	frame := o.frames[len(o.frames)-1]
+	o.frames = o.frames[:len(o.frames)-1]
+
+	if gasUsed > frame.childGasUsed {
+		o.contractGas[frame.address] += gasUsed - frame.childGasUsed
+	}
+
+	if len(o.frames) > 0 {
+		o.frames[len(o.frames)-1].childGasUsed += gasUsed
+	}
+}
+
+func (o *OpcodeStatsTracer) ExecuteState(step *runtime.ExecutionStep) {
+	o.counts[byte(step.Op)]++
+}
+
+// Stats returns the collected stats, with the topK contracts which consumed the most gas
+func (o *OpcodeStatsTracer) Stats(topK int) *OpcodeStats {
+	stats := &OpcodeStats{
+		Opcodes:      make(map[string]uint64),
+		HotContracts: make([]*ContractGas, 0, len(o.contractGas)),
+	}
+
+	for op, count := range o.counts {
+		if count == 0 {
+			continue
+		}
+
+		stats.Opcodes[evm.OpCode(op).String()] += count
+		stats.TotalOps += count
+	}
+
+	for addr, gasUsed := range o.contractGas {
+		stats.HotContracts = append(stats.HotContracts, &ContractGas{
+			Address: addr,
+			GasUsed: gasUsed,
+		})
+	}
+
+	sort.Slice(stats.HotContracts, func(i, j int) bool {
+		a, b := stats.HotContracts[i], stats.HotContracts[j]
+		if a.GasUsed != b.GasUsed {
+			return a.GasUsed > b.GasUsed
+		}
+
+		return a.Address.String() < b.Address.String()
+	})
+
+	if len(stats.HotContracts) > topK {
+		stats.HotContracts = stats.HotContracts[:topK]
+	}
+
+	return stats
+}
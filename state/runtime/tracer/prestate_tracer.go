@@ -0,0 +1,117 @@
+package tracer
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// PrestateAccount is the state of an account before the transaction was executed
+type PrestateAccount struct {
+	Balance string                    `json:"balance"`
+	Nonce   uint64                    `json:"nonce,omitempty"`
+	Code    string                    `json:"code,omitempty"`
+	Storage map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+// PrestateTracer collects the state of every account and storage slot
+// touched by the transaction, as it was before the transaction was executed
+type PrestateTracer struct {
+	host     runtime.Host
+	prestate map[types.Address]*PrestateAccount
+}
+
+// NewPrestateTracer creates a new prestate tracer
+func NewPrestateTracer() *PrestateTracer {
+	return &PrestateTracer{
+		prestate: make(map[types.Address]*PrestateAccount),
+	}
+}
+
+func (p *PrestateTracer) TxStart(host runtime.Host, tx *types.Transaction) {
+	p.host = host
+
+	p.lookupAccount(tx.From)
+	p.lookupAccount(host.GetTxContext().Coinbase)
+
+	if tx.To != nil {
+		p.lookupAccount(*tx.To)
+	}
+}
+
+func (p *PrestateTracer) TxEnd(*runtime.ExecutionResult) {}
+
+func (p *PrestateTracer) CallStart(
+	_ int,
+	_ runtime.CallType,
+	from, to types.Address,
+	_ []byte,
+	_ uint64,
+	_ *big.Int,
+) {
+	p.lookupAccount(from)
+	p.lookupAccount(to)
+}
+
+func (p *PrestateTracer) CallEnd(int, []byte, uint64, error) {}
+
+func (p *PrestateTracer) ExecuteState(step *runtime.ExecutionStep) {
+	// the accounts and slots are captured before the instruction
+	// touching them is executed, so the original values are recorded
+	switch evm.OpCode(step.Op) {
+	case evm.SLOAD, evm.SSTORE:
+		if key := step.StackBack(0); key != nil {
+			p.lookupStorage(step.Contract.Address, types.BytesToHash(key.Bytes()))
+		}
+	case evm.BALANCE, evm.EXTCODESIZE, evm.EXTCODECOPY, evm.EXTCODEHASH, evm.SELFDESTRUCT:
+		if addr := step.StackBack(0); addr != nil {
+			p.lookupAccount(types.BytesToAddress(addr.Bytes()))
+		}
+	case evm.CALL, evm.CALLCODE, evm.DELEGATECALL, evm.STATICCALL:
+		if addr := step.StackBack(1); addr != nil {
+			p.lookupAccount(types.BytesToAddress(addr.Bytes()))
+		}
+	}
+}
+
+// lookupAccount records the current state of the account, if it wasn't recorded already
+func (p *PrestateTracer) lookupAccount(addr types.Address) {
+	if _, ok := p.prestate[addr]; ok || p.host == nil {
+		return
+	}
+
+	account := &PrestateAccount{
+		Balance: hex.EncodeBig(p.host.GetBalance(addr)),
+		Nonce:   p.host.GetNonce(addr),
+		Storage: make(map[types.Hash]types.Hash),
+	}
+
+	if code := p.host.GetCode(addr); len(code) > 0 {
+		account.Code = hex.EncodeToHex(code)
+	}
+
+	p.prestate[addr] = account
+}
+
+// lookupStorage records the current value of the storage slot, if it wasn't recorded already
+func (p *PrestateTracer) lookupStorage(addr types.Address, slot types.Hash) {
+	p.lookupAccount(addr)
+
+	account, ok := p.prestate[addr]
+	if !ok {
+		return
+	}
+
+	if _, ok := account.Storage[slot]; ok {
+		return
+	}
+
+	account.Storage[slot] = p.host.GetStorage(addr, slot)
+}
+
+func (p *PrestateTracer) GetResult() (interface{}, error) {
+	return p.prestate, nil
+}
@@ -0,0 +1,163 @@
+package tracer
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	hexHelper "github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// StructLog is a single opcode-level log of the EVM execution
+type StructLog struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// StructLoggerResult is the result of the struct logger
+type StructLoggerResult struct {
+	Gas         uint64       `json:"gas"`
+	Failed      bool         `json:"failed"`
+	ReturnValue string       `json:"returnValue"`
+	StructLogs  []*StructLog `json:"structLogs"`
+}
+
+// StructLogger collects an opcode-level log of the EVM execution
+type StructLogger struct {
+	config *Config
+
+	logs    []*StructLog
+	storage map[types.Address]map[types.Hash]types.Hash
+
+	// index of the last log for every call depth, used to compute the actual gas cost
+	lastLogs []int
+
+	result *runtime.ExecutionResult
+}
+
+// NewStructLogger creates a new struct logger
+func NewStructLogger(config *Config) *StructLogger {
+	return &StructLogger{
+		config:  config,
+		logs:    make([]*StructLog, 0),
+		storage: make(map[types.Address]map[types.Hash]types.Hash),
+	}
+}
+
+func (s *StructLogger) TxStart(runtime.Host, *types.Transaction) {}
+
+func (s *StructLogger) TxEnd(result *runtime.ExecutionResult) {
+	s.result = result
+}
+
+func (s *StructLogger) CallStart(int, runtime.CallType, types.Address, types.Address, []byte, uint64, *big.Int) {
+	s.lastLogs = append(s.lastLogs, -1)
+}
+
+func (s *StructLogger) CallEnd(_ int, _ []byte, _ uint64, err error) {
+	if len(s.lastLogs) == 0 {
+		return
+	}
+
+	last := s.lastLogs[len(s.lastLogs)-1]
+	s.lastLogs = s.lastLogs[:len(s.lastLogs)-1]
+
+	if last != -1 && err != nil {
+		s.logs[last].Error = err.Error()
+	}
+}
+
+func (s *StructLogger) ExecuteState(step *runtime.ExecutionStep) {
+	op := evm.OpCode(step.Op)
+
+	log := &StructLog{
+		Pc:      step.PC,
+		Op:      op.String(),
+		Gas:     step.Gas,
+		GasCost: step.Cost,
+		Depth:   step.Depth,
+	}
+
+	if !s.config.DisableStack {
+		log.Stack = make([]string, len(step.Stack))
+		for i, item := range step.Stack {
+			log.Stack[i] = hexHelper.EncodeBig(item)
+		}
+	}
+
+	if s.config.EnableMemory {
+		log.Memory = make([]string, 0, len(step.Memory)/32)
+		for i := 0; i+32 <= len(step.Memory); i += 32 {
+			log.Memory = append(log.Memory, hex.EncodeToString(step.Memory[i:i+32]))
+		}
+	}
+
+	if !s.config.DisableStorage && (op == evm.SLOAD || op == evm.SSTORE) {
+		log.Storage = s.captureStorage(step, op)
+	}
+
+	// the actual cost of the previous instruction at the same depth is only known now,
+	// as it includes the dynamic gas (memory expansion, storage access, etc.)
+	if len(s.lastLogs) > 0 {
+		if prev := s.lastLogs[len(s.lastLogs)-1]; prev != -1 && s.logs[prev].Gas >= step.Gas {
+			s.logs[prev].GasCost = s.logs[prev].Gas - step.Gas
+		}
+
+		s.lastLogs[len(s.lastLogs)-1] = len(s.logs)
+	}
+
+	s.logs = append(s.logs, log)
+}
+
+// captureStorage updates the storage view of the current contract and returns a copy of it
+func (s *StructLogger) captureStorage(step *runtime.ExecutionStep, op evm.OpCode) map[string]string {
+	addr := step.Contract.Address
+
+	storage, ok := s.storage[addr]
+	if !ok {
+		storage = make(map[types.Hash]types.Hash)
+		s.storage[addr] = storage
+	}
+
+	switch op {
+	case evm.SLOAD:
+		if key := step.StackBack(0); key != nil {
+			slot := types.BytesToHash(key.Bytes())
+			storage[slot] = step.Host.GetStorage(addr, slot)
+		}
+	case evm.SSTORE:
+		if key, value := step.StackBack(0), step.StackBack(1); key != nil && value != nil {
+			storage[types.BytesToHash(key.Bytes())] = types.BytesToHash(value.Bytes())
+		}
+	}
+
+	res := make(map[string]string, len(storage))
+	for k, v := range storage {
+		res[hex.EncodeToString(k.Bytes())] = hex.EncodeToString(v.Bytes())
+	}
+
+	return res
+}
+
+func (s *StructLogger) GetResult() (interface{}, error) {
+	res := &StructLoggerResult{
+		StructLogs: s.logs,
+	}
+
+	if s.result != nil {
+		res.Gas = s.result.GasUsed
+		res.Failed = s.result.Failed()
+		res.ReturnValue = hex.EncodeToString(s.result.ReturnValue)
+	}
+
+	return res, nil
+}
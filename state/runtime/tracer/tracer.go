@@ -0,0 +1,81 @@
+package tracer
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+)
+
+const (
+	// StructLoggerName is the name of the opcode-level struct logger (the default tracer)
+	StructLoggerName = "structLogger"
+
+	// CallTracerName is the name of the tracer collecting the call frames
+	CallTracerName = "callTracer"
+
+	// FlatCallTracerName is the name of the tracer collecting the call frames as flat (parity style) traces
+	FlatCallTracerName = "flatCallTracer"
+
+	// PrestateTracerName is the name of the tracer collecting the state touched by the transaction
+	PrestateTracerName = "prestateTracer"
+)
+
+// Tracer is a runtime tracer which can report the collected traces
+type Tracer interface {
+	runtime.Tracer
+
+	// GetResult returns the collected traces in a JSON serializable form
+	GetResult() (interface{}, error)
+}
+
+// Config is the configuration of the tracers
+type Config struct {
+	// DisableStack disables the stack capture in the struct logs
+	DisableStack bool
+
+	// DisableStorage disables the storage capture in the struct logs
+	DisableStorage bool
+
+	// EnableMemory enables the memory capture in the struct logs
+	EnableMemory bool
+}
+
+// New creates a new tracer with the given name
+func New(name string, config *Config) (Tracer, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	switch name {
+	case "", StructLoggerName:
+		return NewStructLogger(config), nil
+	case CallTracerName:
+		return NewCallTracer(), nil
+	case FlatCallTracerName:
+		return NewFlatCallTracer(), nil
+	case PrestateTracerName:
+		return NewPrestateTracer(), nil
+	default:
+		return nil, fmt.Errorf("tracer %s not found", name)
+	}
+}
+
+// callTypeToString returns the name of the call type as used in the traces
+func callTypeToString(callType runtime.CallType) string {
+	switch callType {
+	case runtime.Call:
+		return "CALL"
+	case runtime.CallCode:
+		return "CALLCODE"
+	case runtime.DelegateCall:
+		return "DELEGATECALL"
+	case runtime.StaticCall:
+		return "STATICCALL"
+	case runtime.Create:
+		return "CREATE"
+	case runtime.Create2:
+		return "CREATE2"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,272 @@
+package tracer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	addr1 = types.StringToAddress("1")
+	addr2 = types.StringToAddress("2")
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected interface{}
+		err      bool
+	}{
+		{"", &StructLogger{}, false},
+		{StructLoggerName, &StructLogger{}, false},
+		{CallTracerName, &CallTracer{}, false},
+		{FlatCallTracerName, &FlatCallTracer{}, false},
+		{PrestateTracerName, &PrestateTracer{}, false},
+		{"unknown", nil, true},
+	}
+
+	for _, tt := range tests {
+		tracer, err := New(tt.name, nil)
+		if tt.err {
+			assert.Error(t, err)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.IsType(t, tt.expected, tracer)
+	}
+}
+
+func TestStructLogger(t *testing.T) {
+	t.Parallel()
+
+	logger := NewStructLogger(&Config{})
+	contract := &runtime.Contract{Address: addr2}
+
+	logger.TxStart(nil, &types.Transaction{})
+	logger.CallStart(1, runtime.Call, addr1, addr2, nil, 100, big.NewInt(0))
+
+	// PUSH1 0x1, PUSH1 0x0, SSTORE, STOP
+	logger.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, PC: 0, Op: int(evm.PUSH1), Gas: 100, Cost: 3, Depth: 1,
+	})
+	logger.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, PC: 2, Op: int(evm.PUSH1), Gas: 97, Cost: 3, Depth: 1,
+		Stack: []*big.Int{big.NewInt(1)},
+	})
+	logger.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, PC: 4, Op: int(evm.SSTORE), Gas: 94, Cost: 0, Depth: 1,
+		Stack: []*big.Int{big.NewInt(1), big.NewInt(0)},
+	})
+	logger.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, PC: 5, Op: int(evm.STOP), Gas: 74, Cost: 0, Depth: 1,
+	})
+
+	logger.CallEnd(1, nil, 26, nil)
+	logger.TxEnd(&runtime.ExecutionResult{GasUsed: 21026})
+
+	res, err := logger.GetResult()
+	assert.NoError(t, err)
+
+	result, ok := res.(*StructLoggerResult)
+	assert.True(t, ok)
+
+	assert.Equal(t, uint64(21026), result.Gas)
+	assert.False(t, result.Failed)
+	assert.Len(t, result.StructLogs, 4)
+
+	// the cost of SSTORE is computed from the gas left for the next instruction
+	sstore := result.StructLogs[2]
+	assert.Equal(t, "SSTORE", sstore.Op)
+	assert.Equal(t, uint64(20), sstore.GasCost)
+	assert.Equal(t, []string{"0x1", "0x0"}, sstore.Stack)
+	assert.Equal(t, map[string]string{
+		"0000000000000000000000000000000000000000000000000000000000000000": "0000000000000000000000000000000000000000000000000000000000000001",
+	}, sstore.Storage)
+}
+
+func TestCallTracer(t *testing.T) {
+	t.Parallel()
+
+	tracer := NewCallTracer()
+
+	tracer.TxStart(nil, &types.Transaction{Gas: 50000})
+	tracer.CallStart(1, runtime.Call, addr1, addr2, []byte{0x1}, 29000, big.NewInt(10))
+	tracer.CallStart(2, runtime.StaticCall, addr2, addr1, []byte{0x2}, 1000, big.NewInt(0))
+	tracer.CallEnd(2, []byte{0x3}, 400, runtime.ErrExecutionReverted)
+	tracer.CallEnd(1, []byte{0x4}, 2000, nil)
+	tracer.TxEnd(&runtime.ExecutionResult{GasUsed: 23000})
+
+	res, err := tracer.GetResult()
+	assert.NoError(t, err)
+
+	root, ok := res.(*CallFrame)
+	assert.True(t, ok)
+
+	assert.Equal(t, "CALL", root.Type)
+	assert.Equal(t, "0xc350", root.Gas)
+	assert.Equal(t, "0x59d8", root.GasUsed)
+	assert.Equal(t, "0xa", root.Value)
+	assert.Equal(t, "0x04", root.Output)
+	assert.Len(t, root.Calls, 1)
+
+	child := root.Calls[0]
+	assert.Equal(t, "STATICCALL", child.Type)
+	assert.Equal(t, "", child.Value)
+	assert.Equal(t, "0x190", child.GasUsed)
+	assert.Equal(t, "0x03", child.Output)
+	assert.Equal(t, runtime.ErrExecutionReverted.Error(), child.Error)
+}
+
+func TestCallTracer_NoFrames(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCallTracer().GetResult()
+	assert.Error(t, err)
+}
+
+func TestFlatCallTracer(t *testing.T) {
+	t.Parallel()
+
+	addr3 := types.StringToAddress("abcd")
+
+	tracer := NewFlatCallTracer()
+
+	tracer.TxStart(nil, &types.Transaction{Gas: 100000})
+	tracer.CallStart(1, runtime.Call, addr1, addr2, []byte{0x1}, 79000, big.NewInt(10))
+	tracer.CallStart(2, runtime.Create, addr2, addr3, []byte{0x2}, 50000, big.NewInt(0))
+	tracer.CallStart(3, runtime.StaticCall, addr3, addr1, []byte{0x3}, 1000, nil)
+	tracer.CallEnd(3, []byte{0x4}, 400, runtime.ErrExecutionReverted)
+	tracer.CallEnd(2, []byte{0x5}, 30000, nil)
+	tracer.CallStart(2, runtime.DelegateCall, addr2, addr1, nil, 1000, big.NewInt(10))
+	tracer.CallEnd(2, nil, 1000, runtime.ErrOutOfGas)
+	tracer.CallEnd(1, []byte{0x6}, 40000, nil)
+	tracer.TxEnd(&runtime.ExecutionResult{GasUsed: 61000})
+
+	traces, err := tracer.FlatTraces()
+	require.NoError(t, err)
+
+	assert.Equal(t, []*FlatTrace{
+		{
+			Action: &FlatTraceAction{
+				CallType: "call", From: addr1, To: &addr2, Gas: "0x186a0", Input: "0x01", Value: "0xa",
+			},
+			Result:       &FlatTraceResult{GasUsed: "0xee48", Output: "0x06"},
+			Subtraces:    2,
+			TraceAddress: []int{},
+			Type:         "call",
+		},
+		{
+			Action:       &FlatTraceAction{From: addr2, Gas: "0xc350", Init: "0x02", Value: "0x0"},
+			Result:       &FlatTraceResult{Address: &addr3, Code: "0x05", GasUsed: "0x7530"},
+			Subtraces:    1,
+			TraceAddress: []int{0},
+			Type:         "create",
+		},
+		{
+			Action: &FlatTraceAction{
+				CallType: "staticcall", From: addr3, To: &addr1, Gas: "0x3e8", Input: "0x03", Value: "0x0",
+			},
+			Error:        "Reverted",
+			TraceAddress: []int{0, 0},
+			Type:         "call",
+		},
+		{
+			Action: &FlatTraceAction{
+				CallType: "delegatecall", From: addr2, To: &addr1, Gas: "0x3e8", Input: "0x", Value: "0x0",
+			},
+			Error:        runtime.ErrOutOfGas.Error(),
+			TraceAddress: []int{1},
+			Type:         "call",
+		},
+	}, traces)
+}
+
+func TestAccessListTracer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr3     = types.StringToAddress("abcd")
+		ecrecover = types.StringToAddress("1")
+		contract  = &runtime.Contract{Address: addr2}
+		slot      = types.StringToHash("5")
+	)
+
+	tracer := NewAccessListTracer()
+
+	tracer.TxStart(nil, &types.Transaction{From: addr1, To: &addr2})
+	tracer.CallStart(1, runtime.Call, addr1, addr2, nil, 100, big.NewInt(0))
+
+	// the storage of the recipient is listed, unlike the recipient itself
+	tracer.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, Op: int(evm.SLOAD), Stack: []*big.Int{new(big.Int).SetBytes(slot.Bytes())},
+	})
+	tracer.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, Op: int(evm.SSTORE), Stack: []*big.Int{big.NewInt(1), new(big.Int).SetBytes(slot.Bytes())},
+	})
+	tracer.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, Op: int(evm.BALANCE), Stack: []*big.Int{new(big.Int).SetBytes(addr1.Bytes())},
+	})
+	tracer.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, Op: int(evm.CALL), Stack: []*big.Int{big.NewInt(0), new(big.Int).SetBytes(ecrecover.Bytes()), big.NewInt(100)},
+	})
+	tracer.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, Op: int(evm.STATICCALL), Stack: []*big.Int{big.NewInt(0), new(big.Int).SetBytes(addr3.Bytes()), big.NewInt(100)},
+	})
+	tracer.ExecuteState(&runtime.ExecutionStep{
+		Contract: contract, Op: int(evm.EXTCODESIZE), Stack: []*big.Int{new(big.Int).SetBytes(addr3.Bytes())},
+	})
+
+	assert.Equal(t, types.TxAccessList{
+		{Address: addr2, StorageKeys: []types.Hash{slot}},
+		{Address: addr3, StorageKeys: []types.Hash{}},
+	}, tracer.AccessList())
+}
+
+func TestOpcodeStatsTracer(t *testing.T) {
+	t.Parallel()
+
+	addr3 := types.StringToAddress("3")
+
+	tracer := NewOpcodeStatsTracer()
+
+	// addr1 calls addr2, which calls addr3
+	tracer.TxStart(nil, &types.Transaction{Gas: 50000})
+	tracer.CallStart(1, runtime.Call, types.ZeroAddress, addr1, nil, 29000, big.NewInt(0))
+	tracer.ExecuteState(&runtime.ExecutionStep{Op: int(evm.PUSH1)})
+	tracer.CallStart(2, runtime.Call, addr1, addr2, nil, 20000, big.NewInt(0))
+	tracer.ExecuteState(&runtime.ExecutionStep{Op: int(evm.PUSH1)})
+	tracer.ExecuteState(&runtime.ExecutionStep{Op: int(evm.SSTORE)})
+	tracer.CallStart(3, runtime.StaticCall, addr2, addr3, nil, 5000, big.NewInt(0))
+	tracer.ExecuteState(&runtime.ExecutionStep{Op: int(evm.SLOAD)})
+	tracer.CallEnd(3, nil, 2100, nil)
+	tracer.CallEnd(2, nil, 22100, nil)
+	tracer.CallEnd(1, nil, 25000, nil)
+	tracer.TxEnd(&runtime.ExecutionResult{GasUsed: 46000})
+
+	// a second transaction calls addr3 again
+	tracer.TxStart(nil, &types.Transaction{Gas: 30000})
+	tracer.CallStart(1, runtime.Call, types.ZeroAddress, addr3, nil, 9000, big.NewInt(0))
+	tracer.ExecuteState(&runtime.ExecutionStep{Op: int(evm.SLOAD)})
+	tracer.CallEnd(1, nil, 2100, nil)
+	tracer.TxEnd(&runtime.ExecutionResult{GasUsed: 23100})
+
+	stats := tracer.Stats(2)
+
+	assert.Equal(t, uint64(5), stats.TotalOps)
+	assert.Equal(t, map[string]uint64{"PUSH1": 2, "SSTORE": 1, "SLOAD": 2}, stats.Opcodes)
+
+	// the gas used by the sub calls isn't accounted to the caller
+	assert.Equal(t, []*ContractGas{
+		{Address: addr2, GasUsed: 20000},
+		{Address: addr3, GasUsed: 4200},
+	}, stats.HotContracts)
+}
@@ -0,0 +1,131 @@
+package state
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrNoSimulatedBlocks      = errors.New("no blocks to simulate")
+	ErrSimulatedBlockNotAhead = errors.New("simulated block number must be greater than the previous one")
+)
+
+// SimulatedBlock is a block of calls executed on top of the state left by the previous simulated blocks.
+// The header only provides the context of the calls (number, timestamp, gas limit and miner)
+type SimulatedBlock struct {
+	Header   *types.Header
+	Override StateOverride
+	Calls    []*types.Transaction
+}
+
+// SimulatedCall is the result of a simulated call,
+// Err is set instead of the execution result if the call couldn't be applied
+type SimulatedCall struct {
+	Result *runtime.ExecutionResult
+	Logs   []*types.Log
+	Err    error
+}
+
+// Simulate executes the calls of the blocks in sequence on top of the state of the parent,
+// without committing anything. The headers are chained on top of the parent and their hashes computed.
+// The nonces of the calls are set from the simulated state, so consecutive calls of the same sender
+// don't have to provide them, and the calls without gas limit get the gas left in the block
+func (e *Executor) Simulate(parent *types.Header, blocks []*SimulatedBlock) ([][]*SimulatedCall, error) {
+	if len(blocks) == 0 {
+		return nil, ErrNoSimulatedBlocks
+	}
+
+	transition, err := e.BeginTxn(parent.StateRoot, blocks[0].Header, types.BytesToAddress(blocks[0].Header.Miner))
+	if err != nil {
+		return nil, err
+	}
+
+	transition.SetCall()
+
+	var (
+		res    = make([][]*SimulatedCall, len(blocks))
+		hashes = make(map[uint64]types.Hash, len(blocks))
+		last   = parent
+
+		// the hashes of the blocks up to the parent
+		chainHash = e.GetHash(&types.Header{Number: parent.Number + 1, ParentHash: parent.Hash})
+	)
+
+	// the hashes of the simulated blocks are served to BLOCKHASH in the following ones
+	transition.getHash = func(i uint64) types.Hash {
+		if hash, ok := hashes[i]; ok {
+			return hash
+		}
+
+		return chainHash(i)
+	}
+
+	for idx, block := range blocks {
+		header := block.Header
+		if header.Number <= last.Number {
+			return nil, ErrSimulatedBlockNotAhead
+		}
+
+		header.ParentHash = last.Hash
+		header.ComputeHash()
+
+		transition.beginSimulatedBlock(header, e.config.Forks.At(header.Number, header.Timestamp))
+		transition.setNativeToken(e.config.NativeTokenAt(header.Number, header.Timestamp))
+		transition.allowlist = e.config.AllowlistAt(header.Number, header.Timestamp)
+
+		if err := block.Override.Apply(transition.state); err != nil {
+			return nil, err
+		}
+
+		res[idx] = make([]*SimulatedCall, len(block.Calls))
+
+		for callIdx, call := range block.Calls {
+			res[idx][callIdx] = transition.simulateCall(call)
+		}
+
+		hashes[header.Number] = header.Hash
+		last = header
+	}
+
+	return res, nil
+}
+
+// beginSimulatedBlock moves the transition to the context of the simulated block
+func (t *Transition) beginSimulatedBlock(header *types.Header, config chain.ForksInTime) {
+	t.config = config
+	t.ctx.Coinbase = types.BytesToAddress(header.Miner)
+	t.ctx.Timestamp = int64(header.Timestamp)
+	t.ctx.Number = int64(header.Number)
+	t.ctx.Difficulty = types.BytesToHash(new(big.Int).SetUint64(header.Difficulty).Bytes())
+	t.ctx.GasLimit = int64(header.GasLimit)
+	t.gasPool = header.GasLimit
+}
+
+// simulateCall applies the call on top of the state left by the previous ones
+func (t *Transition) simulateCall(call *types.Transaction) *SimulatedCall {
+	msg := call.Copy()
+	msg.Nonce = t.state.GetNonce(msg.From)
+
+	if msg.Gas == 0 {
+		msg.Gas = t.gasPool
+	}
+
+	result, err := t.Apply(msg)
+	if err != nil {
+		return &SimulatedCall{Err: err}
+	}
+
+	logs := t.state.Logs()
+
+	// the suicided accounts (and the empty ones since EIP-158) are gone for the next calls
+	t.state.CleanDeleteObjects(t.config.EIP158)
+
+	return &SimulatedCall{
+		Result: result,
+		Logs:   logs,
+	}
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/umbracle/ethgo/abi"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// SponsorApprovalGas is the highest gas the sponsor can use to approve paying the fee of a transaction.
+// The gas used by the approval is part of the gas of the transaction, paid by the sponsor
+const SponsorApprovalGas = 50000
+
+var (
+	// SponsorApproveMethod is the method of the sponsor contracts which approves paying the fee
+	// of the transaction, it's called statically before the transaction is executed
+	SponsorApproveMethod, _ = abi.NewMethod(
+		"function approveFee(address sender, address to, uint256 gas, uint256 gasPrice, bytes data) returns (bool)",
+	)
+
+	// ErrSponsorNotApproved is returned by the sponsored transactions whose sponsor doesn't approve paying the fee
+	ErrSponsorNotApproved = errors.New("fee not approved by the sponsor")
+)
+
+// feePayer returns the account paying the fee of the transaction, the sponsor of a sponsored transaction
+func feePayer(msg *types.Transaction) types.Address {
+	if msg.Sponsor != nil {
+		return *msg.Sponsor
+	}
+
+	return msg.From
+}
+
+// approveSponsor calls the sponsor of the transaction statically to approve paying its fee,
+// with at most the gas left of the transaction. It returns the gas used by the call
+func (t *Transition) approveSponsor(msg *types.Transaction, gasLeft uint64) (uint64, error) {
+	to := types.ZeroAddress
+	if msg.To != nil {
+		to = *msg.To
+	}
+
+	input, err := SponsorApproveMethod.Encode(map[string]interface{}{
+		"sender":   msg.From,
+		"to":       to,
+		"gas":      new(big.Int).SetUint64(msg.Gas),
+		"gasPrice": msg.GasPrice,
+		"data":     msg.Input,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	gas := uint64(SponsorApprovalGas)
+	if gasLeft < gas {
+		gas = gasLeft
+	}
+
+	sponsor := *msg.Sponsor
+
+	c := runtime.NewContractCall(1, msg.From, msg.From, sponsor, big.NewInt(0), gas, t.state.GetCode(sponsor), input)
+	c.Type = runtime.StaticCall
+	c.Static = true
+
+	// the approval isn't part of the traces of the transaction
+	tracer := t.tracer
+	t.tracer = nil
+	result := t.applyCall(c, runtime.StaticCall, t)
+	t.tracer = tracer
+
+	if result.Failed() || !approved(result.ReturnValue) {
+		return 0, ErrSponsorNotApproved
+	}
+
+	return gas - result.GasLeft, nil
+}
+
+// approved returns whether the value returned by the approval is the ABI encoding of true
+func approved(value []byte) bool {
+	if len(value) != types.HashLength {
+		return false
+	}
+
+	return types.BytesToHash(value) == types.BytesToHash([]byte{1})
+}
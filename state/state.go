@@ -16,6 +16,8 @@ type State interface {
 	NewSnapshotAt(types.Hash) (Snapshot, error)
 	NewSnapshot() Snapshot
 	GetCode(hash types.Hash) ([]byte, bool)
+	Prove(root types.Hash, key []byte) ([][]byte, error)
+	Iterate(root types.Hash, handler func(key, value []byte) error) error
 }
 
 type Snapshot interface {
@@ -23,6 +25,57 @@ type Snapshot interface {
 	Commit(objs []*Object) (Snapshot, []byte)
 }
 
+// FlatSnapshot is a snapshot which reads the storage of the accounts from a flat layer,
+// instead of their storage tries
+type FlatSnapshot interface {
+	Snapshot
+	// Storage returns the storage of the account with the given hashed address and storage root
+	Storage(addrHash types.Hash, root types.Hash) StorageReader
+}
+
+// StorageReader reads the storage slots of an account by their hashed keys
+type StorageReader interface {
+	Get(k []byte) ([]byte, bool)
+}
+
+// RemoteSnapshot is a snapshot layered over the state of a remote chain, which reads the accounts
+// it doesn't hold from the remote chain. The remote chain is read by the plain addresses and keys,
+// so the accounts are read by their addresses instead of their hashed ones
+type RemoteSnapshot interface {
+	Snapshot
+	// GetAccount returns the account at the address, false if it doesn't exist.
+	// The trie of the account is a SlotReader
+	GetAccount(addr types.Address) (*Account, bool)
+}
+
+// SlotReader is the storage of an account which reads the slots by their plain keys,
+// returning the RLP encoded values the way the storage tries hold them
+type SlotReader interface {
+	GetSlot(key types.Hash) ([]byte, bool)
+}
+
+// ReadAccount reads the account at the address from the snapshot, false if it doesn't exist.
+// The trie of the account is only set if the snapshot is a RemoteSnapshot
+func ReadAccount(snap Snapshot, addr types.Address) (*Account, bool, error) {
+	if remote, ok := snap.(RemoteSnapshot); ok {
+		account, ok := remote.GetAccount(addr)
+
+		return account, ok, nil
+	}
+
+	data, ok := snap.Get(crypto.Keccak256(addr.Bytes()))
+	if !ok {
+		return nil, false, nil
+	}
+
+	var account Account
+	if err := account.UnmarshalRlp(data); err != nil {
+		return nil, false, err
+	}
+
+	return &account, true, nil
+}
+
 // account trie
 type accountTrie interface {
 	Get(k []byte) ([]byte, bool)
@@ -132,6 +185,11 @@ func (s *StateObject) GetCommitedState(key types.Hash) types.Hash {
 		return types.Hash{}
 	}
 
+	return decodeSlot(val)
+}
+
+// decodeSlot decodes the RLP encoded value of a storage slot
+func decodeSlot(val []byte) types.Hash {
 	p := stateStateParserPool.Get()
 	defer stateStateParserPool.Put(p)
 
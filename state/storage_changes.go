@@ -0,0 +1,132 @@
+package state
+
+import (
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// StorageChange is a single modification of a storage slot,
+// attributed to the transaction that caused it
+type StorageChange struct {
+	Address  types.Address
+	Slot     types.Hash
+	OldValue types.Hash
+	NewValue types.Hash
+	TxIndex  int
+	TxHash   types.Hash
+}
+
+// StorageWatches maps contract addresses to the storage slots being watched
+type StorageWatches map[types.Address][]types.Hash
+
+// GetStorageChanges returns the modifications of the watched storage slots made by the given block.
+// The values at the parent and the block state root are compared first, and the block is
+// re-executed on top of the parent state only if at least one watched slot differs,
+// so that every change can be attributed to the transaction which caused it.
+// Slots that are modified and restored to their original value within the same block are not reported
+func (e *Executor) GetStorageChanges(
+	parentRoot types.Hash,
+	block *types.Block,
+	blockCreator types.Address,
+	watches StorageWatches,
+) ([]*StorageChange, error) {
+	if len(watches) == 0 {
+		return nil, nil
+	}
+
+	parentValues, err := e.readWatchedSlots(parentRoot, watches)
+	if err != nil {
+		return nil, err
+	}
+
+	finalValues, err := e.readWatchedSlots(block.Header.StateRoot, watches)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasSlotChanges(parentValues, finalValues) {
+		return nil, nil
+	}
+
+	transition, err := e.BeginTxn(parentRoot, block.Header, blockCreator)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		changes    = make([]*StorageChange, 0)
+		lastValues = parentValues
+	)
+
+	for idx, tx := range block.Transactions {
+		if tx.ExceedsBlockGasLimit(block.Header.GasLimit) {
+			if err := transition.WriteFailedReceipt(tx); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if err := transition.Write(tx); err != nil {
+			return nil, err
+		}
+
+		for addr, slots := range watches {
+			for _, slot := range slots {
+				value := transition.Txn().GetState(addr, slot)
+				if value == lastValues[addr][slot] {
+					continue
+				}
+
+				changes = append(changes, &StorageChange{
+					Address:  addr,
+					Slot:     slot,
+					OldValue: lastValues[addr][slot],
+					NewValue: value,
+					TxIndex:  idx,
+					TxHash:   tx.Hash,
+				})
+
+				lastValues[addr][slot] = value
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// readWatchedSlots reads the current values of the watched slots at the given state root
+func (e *Executor) readWatchedSlots(
+	root types.Hash,
+	watches StorageWatches,
+) (map[types.Address]map[types.Hash]types.Hash, error) {
+	snap, err := e.state.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	txn := NewTxn(e.state, snap)
+	values := make(map[types.Address]map[types.Hash]types.Hash, len(watches))
+
+	for addr, slots := range watches {
+		values[addr] = make(map[types.Hash]types.Hash, len(slots))
+
+		for _, slot := range slots {
+			values[addr][slot] = txn.GetState(addr, slot)
+		}
+	}
+
+	return values, nil
+}
+
+// hasSlotChanges checks if any of the slot values differs between the two sets
+func hasSlotChanges(before, after map[types.Address]map[types.Hash]types.Hash) bool {
+	for addr, slots := range before {
+		for slot, value := range slots {
+			if after[addr][slot] != value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
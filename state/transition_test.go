@@ -4,10 +4,14 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestTransition(preState map[types.Address]*PreState) *Transition {
@@ -159,3 +163,506 @@ func TestTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionGasCost_AccessList(t *testing.T) {
+	t.Parallel()
+
+	to := types.StringToAddress("1")
+	msg := &types.Transaction{
+		Type: types.AccessListTx,
+		To:   &to,
+		AccessList: types.TxAccessList{
+			{
+				Address:     addr1,
+				StorageKeys: []types.Hash{types.StringToHash("1"), types.StringToHash("2")},
+			},
+			{
+				Address: addr2,
+			},
+		},
+	}
+
+	cost, err := TransactionGasCost(msg, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGas+2*TxAccessListAddressGas+2*TxAccessListStorageKeyGas, cost)
+}
+
+func TestSelfdestruct_EIP6780(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		eip6780     bool
+		created     bool
+		beneficiary types.Address
+		destroyed   bool
+		balance     uint64 // of the account after the selfdestruct
+		received    uint64 // by the beneficiary
+	}{
+		{
+			name:        "before the fork the existing account is destroyed",
+			beneficiary: addr2,
+			destroyed:   true,
+			received:    100,
+		},
+		{
+			name:        "the existing account only sends its balance",
+			eip6780:     true,
+			beneficiary: addr2,
+			received:    100,
+		},
+		{
+			name:        "the existing account sending to itself keeps its balance",
+			eip6780:     true,
+			beneficiary: addr1,
+			balance:     100,
+			received:    100,
+		},
+		{
+			name:        "the account created in the transaction is destroyed",
+			eip6780:     true,
+			created:     true,
+			beneficiary: addr2,
+			destroyed:   true,
+			received:    100,
+		},
+		{
+			name:        "the account created in the transaction sending to itself burns its balance",
+			eip6780:     true,
+			created:     true,
+			beneficiary: addr1,
+			destroyed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			transition := newTestTransition(map[types.Address]*PreState{
+				addr1: {Balance: 100},
+			})
+			transition.config.EIP6780 = tt.eip6780
+
+			if tt.created {
+				transition.state.CreateAccount(addr1)
+			}
+
+			transition.Selfdestruct(addr1, tt.beneficiary)
+
+			assert.Equal(t, tt.destroyed, transition.state.HasSuicided(addr1))
+			assert.Equal(t, tt.balance, transition.GetBalance(addr1).Uint64())
+			assert.Equal(t, tt.received, transition.GetBalance(tt.beneficiary).Uint64())
+		})
+	}
+}
+
+func TestCreatedAccounts(t *testing.T) {
+	t.Parallel()
+
+	txn := newTestTxn(defaultPreState)
+	assert.False(t, txn.IsCreated(addr1))
+
+	// the failed creation is reverted
+	ss := txn.Snapshot()
+	txn.CreateAccount(addr1)
+	assert.True(t, txn.IsCreated(addr1))
+
+	txn.RevertToSnapshot(ss)
+	assert.False(t, txn.IsCreated(addr1))
+
+	// the account is only created within its transaction
+	txn.CreateAccount(addr1)
+	txn.ClearCreatedAccounts()
+	assert.False(t, txn.IsCreated(addr1))
+}
+
+func TestMatchKnownAccounts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		slot  = types.StringToHash("1")
+		value = types.StringToHash("2")
+	)
+
+	transition := newTestTransition(nil)
+	transition.state.SetState(addr1, slot, value)
+
+	tx := &types.Transaction{}
+	assert.True(t, transition.MatchKnownAccounts(tx))
+
+	tx.KnownAccounts = map[types.Address]map[types.Hash]types.Hash{addr1: {slot: value}}
+	assert.True(t, transition.MatchKnownAccounts(tx))
+
+	// the unset slots are zero
+	tx.KnownAccounts[addr2] = map[types.Hash]types.Hash{slot: types.ZeroHash}
+	assert.True(t, transition.MatchKnownAccounts(tx))
+
+	tx.KnownAccounts[addr1][slot] = types.StringToHash("3")
+	assert.False(t, transition.MatchKnownAccounts(tx))
+}
+
+func TestExecutor_GasTarget(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("0x1234")
+	root := types.StringToHash("0x1")
+
+	// the storage of the mock state is keyed by the hashes of the slots
+	state, snapshot := newStateWithPreState(map[types.Address]*PreState{
+		contract: {
+			State: map[types.Hash]types.Hash{
+				types.BytesToHash(hashit(types.ZeroHash.Bytes())): types.BytesToHash(big.NewInt(30_000_000).Bytes()),
+			},
+		},
+	})
+	state.snapshots[root] = snapshot
+
+	// the chains without the gas target contract have no governed target
+	target, err := NewExecutor(&chain.Params{}, state, hclog.NewNullLogger()).GasTarget(root)
+	assert.NoError(t, err)
+	assert.Zero(t, target)
+
+	target, err = NewExecutor(&chain.Params{GasTargetContract: &contract}, state, hclog.NewNullLogger()).GasTarget(root)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(30_000_000), target)
+}
+
+func TestApply_NativeTokenBaseFee(t *testing.T) {
+	t.Parallel()
+
+	var (
+		coinbase = types.StringToAddress("0xc0")
+		treasury = types.StringToAddress("0xfee")
+	)
+
+	newTransition := func(nativeToken *chain.NativeToken) *Transition {
+		transition := newTestTransition(map[types.Address]*PreState{
+			addr1: {Balance: 1_000_000_000},
+		})
+		transition.evm = evm.NewEVM()
+		transition.precompiles = precompiled.NewPrecompiled()
+		transition.gasPool = 1_000_000
+		transition.ctx.Coinbase = coinbase
+		transition.setNativeToken(nativeToken)
+
+		return transition
+	}
+
+	newTx := func(gasPrice int64) *types.Transaction {
+		return &types.Transaction{
+			From:     addr1,
+			To:       &addr2,
+			Value:    big.NewInt(1),
+			Gas:      TxGas,
+			GasPrice: big.NewInt(gasPrice),
+		}
+	}
+
+	t.Run("the base fee is paid to the treasury", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(&chain.NativeToken{BaseFee: 4, Treasury: &treasury})
+
+		_, err := transition.apply(newTx(10))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(TxGas*4), transition.GetBalance(treasury).Uint64())
+		assert.Equal(t, uint64(TxGas*6), transition.GetBalance(coinbase).Uint64())
+	})
+
+	t.Run("the base fee is burned without a treasury", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(&chain.NativeToken{BaseFee: 4})
+
+		_, err := transition.apply(newTx(10))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(TxGas*6), transition.GetBalance(coinbase).Uint64())
+
+		burned := transition.state.GetState(precompiled.NativeTokenAddr, precompiled.NativeTokenBurnedSlot)
+		assert.Equal(t, types.BytesToHash(new(big.Int).SetUint64(TxGas*4).Bytes()), burned)
+	})
+
+	t.Run("the transactions below the base fee are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(&chain.NativeToken{BaseFee: 4})
+
+		_, err := transition.apply(newTx(3))
+		assert.Equal(t, NewTransitionApplicationError(ErrBelowBaseFee, false), err)
+
+		// the calls don't pay the base fee
+		transition = newTransition(&chain.NativeToken{BaseFee: 4})
+		transition.SetCall()
+
+		_, err = transition.apply(newTx(0))
+		assert.NoError(t, err)
+	})
+}
+
+// delegateCallCode returns the code of a contract delegating the calls to the precompile,
+// it returns whether the delegate call succeeded
+func delegateCallCode(precompile types.Address) []byte {
+	code := []byte{
+		0x36, 0x60, 0x00, 0x60, 0x00, 0x37, // CALLDATACOPY(0, 0, CALLDATASIZE)
+		0x60, 0x00, 0x60, 0x00, 0x36, 0x60, 0x00, // the output, the calldata
+		0x73, // PUSH20 precompile
+	}
+	code = append(code, precompile.Bytes()...)
+
+	return append(code,
+		0x5a, 0xf4, // DELEGATECALL(GAS, precompile, ...)
+		0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3, // RETURN the success
+	)
+}
+
+func TestApply_NativeTokenDelegateCall(t *testing.T) {
+	t.Parallel()
+
+	var (
+		minter    = types.StringToAddress("0xa1")
+		recipient = types.StringToAddress("0xb1")
+		proxy     = types.StringToAddress("0xc1")
+	)
+
+	transition := newTestTransition(map[types.Address]*PreState{
+		minter: {Balance: 1_000_000_000},
+		proxy:  {Balance: 40},
+	})
+	transition.config = chain.ForksInTime{Homestead: true, EIP150: true, NativeToken: true}
+	transition.evm = evm.NewEVM()
+	transition.precompiles = precompiled.NewPrecompiled()
+	transition.gasPool = 1_000_000
+	transition.setNativeToken(&chain.NativeToken{Minter: &minter})
+	transition.state.SetCode(proxy, delegateCallCode(precompiled.NativeTokenAddr))
+
+	mint, err := precompiled.NativeTokenMintMethod.Encode(map[string]interface{}{
+		"to":     recipient,
+		"amount": big.NewInt(100),
+	})
+	require.NoError(t, err)
+
+	// the frame of the proxy keeps the minter as the caller of the precompile
+	for nonce, input := range [][]byte{mint, precompiled.NativeTokenBurnMethod.ID()} {
+		result, err := transition.apply(&types.Transaction{
+			From:     minter,
+			Nonce:    uint64(nonce),
+			To:       &proxy,
+			Value:    big.NewInt(0),
+			Gas:      100_000,
+			GasPrice: big.NewInt(0),
+			Input:    input,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, types.ZeroHash.Bytes(), result.ReturnValue)
+	}
+
+	assert.Zero(t, transition.GetBalance(recipient).Sign())
+	assert.Equal(t, uint64(40), transition.GetBalance(proxy).Uint64())
+	assert.False(t, transition.state.Exist(precompiled.NativeTokenAddr))
+}
+
+func TestApply_Allowlist(t *testing.T) {
+	t.Parallel()
+
+	admin := types.StringToAddress("0xad")
+
+	newTransition := func(allowlist *chain.Allowlist) *Transition {
+		transition := newTestTransition(map[types.Address]*PreState{
+			addr1: {Balance: 1_000_000_000},
+			admin: {Balance: 1_000_000_000},
+		})
+		transition.evm = evm.NewEVM()
+		transition.precompiles = precompiled.NewPrecompiled()
+		transition.gasPool = 10_000_000
+		transition.allowlist = allowlist
+
+		return transition
+	}
+
+	// the transactions are sent with the next nonce of the sender
+	transfer := func(transition *Transition, from types.Address) *types.Transaction {
+		return &types.Transaction{
+			From:     from,
+			Nonce:    transition.state.GetNonce(from),
+			To:       &addr2,
+			Value:    big.NewInt(1),
+			Gas:      TxGas,
+			GasPrice: big.NewInt(0),
+		}
+	}
+
+	// the deployed init code stops, deploying an empty contract
+	deployment := func(transition *Transition, from types.Address) *types.Transaction {
+		return &types.Transaction{
+			From:     from,
+			Nonce:    transition.state.GetNonce(from),
+			Value:    big.NewInt(0),
+			Gas:      100_000,
+			GasPrice: big.NewInt(0),
+			Input:    []byte{0x00},
+		}
+	}
+
+	t.Run("the deployments are restricted to the deployers", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(&chain.Allowlist{Admins: []types.Address{admin}, Deployments: true})
+
+		_, err := transition.apply(deployment(transition, addr1))
+		assert.Equal(t, NewTransitionApplicationError(ErrDeployerNotAllowed, false), err)
+
+		_, err = transition.apply(transfer(transition, addr1))
+		assert.NoError(t, err)
+
+		_, err = transition.apply(deployment(transition, admin))
+		assert.NoError(t, err)
+
+		transition.SetAllowlistRoles(addr1, precompiled.AllowlistDeployer)
+
+		_, err = transition.apply(deployment(transition, addr1))
+		assert.NoError(t, err)
+	})
+
+	t.Run("the transactions are restricted to the senders", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(&chain.Allowlist{Transactions: true})
+
+		_, err := transition.apply(transfer(transition, addr1))
+		assert.Equal(t, NewTransitionApplicationError(ErrSenderNotAllowed, false), err)
+
+		transition.SetAllowlistRoles(addr1, precompiled.AllowlistSender)
+
+		_, err = transition.apply(transfer(transition, addr1))
+		assert.NoError(t, err)
+
+		// the precompile account isn't removed as an empty account
+		assert.Equal(t, uint64(1), transition.state.GetNonce(precompiled.AllowlistAddr))
+	})
+
+	t.Run("the calls don't need the sender role", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(&chain.Allowlist{Transactions: true, Deployments: true})
+		transition.SetCall()
+
+		_, err := transition.apply(transfer(transition, addr1))
+		assert.NoError(t, err)
+
+		_, err = transition.apply(deployment(transition, addr1))
+		assert.Equal(t, NewTransitionApplicationError(ErrDeployerNotAllowed, false), err)
+	})
+
+	t.Run("a contract called by the admin can't set the roles", func(t *testing.T) {
+		t.Parallel()
+
+		proxy := types.StringToAddress("0xc1")
+
+		transition := newTransition(&chain.Allowlist{Admins: []types.Address{admin}, Transactions: true})
+		transition.config = chain.ForksInTime{Homestead: true, EIP150: true, Allowlist: true}
+		transition.state.SetCode(proxy, delegateCallCode(precompiled.AllowlistAddr))
+
+		setRoles, err := precompiled.AllowlistSetRolesMethod.Encode(map[string]interface{}{
+			"account": addr1,
+			"roles":   new(big.Int).SetUint64(precompiled.AllowlistAllRoles),
+		})
+		require.NoError(t, err)
+
+		// the frame of the proxy keeps the admin as the caller of the precompile
+		tx := transfer(transition, admin)
+		tx.To = &proxy
+		tx.Value = big.NewInt(0)
+		tx.Gas = 100_000
+		tx.Input = setRoles
+
+		result, err := transition.apply(tx)
+		require.NoError(t, err)
+		assert.Equal(t, types.ZeroHash.Bytes(), result.ReturnValue)
+		assert.Zero(t, transition.AllowlistRoles(addr1))
+	})
+
+	t.Run("the roles aren't read without an allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(nil)
+
+		_, err := transition.apply(deployment(transition, addr1))
+		assert.NoError(t, err)
+		assert.False(t, transition.state.Exist(precompiled.AllowlistAddr))
+	})
+}
+
+func TestApply_Sponsorship(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sponsor  = types.StringToAddress("0x5b")
+		rejecter = types.StringToAddress("0x5c")
+
+		// the code of the sponsors returns true, or false
+		approveCode = []byte{0x60, 0x01, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+		rejectCode  = []byte{0x60, 0x00, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+	)
+
+	newTransition := func(config chain.ForksInTime) *Transition {
+		transition := newTestTransition(map[types.Address]*PreState{
+			addr1:    {Balance: 10},
+			sponsor:  {Balance: 1_000_000_000},
+			rejecter: {Balance: 1_000_000_000},
+		})
+		transition.config = config
+		transition.evm = evm.NewEVM()
+		transition.precompiles = precompiled.NewPrecompiled()
+		transition.gasPool = 1_000_000
+		transition.state.SetCode(sponsor, approveCode)
+		transition.state.SetCode(rejecter, rejectCode)
+
+		return transition
+	}
+
+	newTx := func(sponsor types.Address) *types.Transaction {
+		return &types.Transaction{
+			Type:     types.SponsoredTx,
+			From:     addr1,
+			To:       &addr2,
+			Value:    big.NewInt(1),
+			Gas:      100_000,
+			GasPrice: big.NewInt(10),
+			Sponsor:  &sponsor,
+		}
+	}
+
+	t.Run("the sponsor pays the fee", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(chain.ForksInTime{Sponsorship: true})
+
+		result, err := transition.apply(newTx(sponsor))
+		assert.NoError(t, err)
+		assert.Greater(t, result.GasUsed, uint64(TxGas))
+
+		// the sender pays only the value
+		assert.Equal(t, uint64(9), transition.GetBalance(addr1).Uint64())
+		assert.Equal(t, uint64(1_000_000_000-result.GasUsed*10), transition.GetBalance(sponsor).Uint64())
+	})
+
+	t.Run("the sponsor doesn't approve", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(chain.ForksInTime{Sponsorship: true})
+
+		_, err := transition.apply(newTx(rejecter))
+		assert.Equal(t, NewTransitionApplicationError(ErrSponsorNotApproved, false), err)
+	})
+
+	t.Run("the sponsored transactions need the sponsorship fork", func(t *testing.T) {
+		t.Parallel()
+
+		transition := newTransition(chain.ForksInTime{})
+
+		_, err := transition.apply(newTx(sponsor))
+		assert.Equal(t, NewTransitionApplicationError(types.ErrTxTypeNotSupported, false), err)
+	})
+}
@@ -21,6 +21,12 @@ var (
 
 	// refundIndex is the index of the refund
 	refundIndex = types.BytesToHash([]byte{3}).Bytes()
+
+	// transientIndex is the index of the transient storage of the transaction (eip-1153)
+	transientIndex = types.BytesToHash([]byte{4}).Bytes()
+
+	// createdIndex is the index of the accounts created in the transaction (eip-6780)
+	createdIndex = types.BytesToHash([]byte{5}).Bytes()
 )
 
 // Txn is a reference of the state
@@ -31,6 +37,9 @@ type Txn struct {
 	txn       *iradix.Txn
 	codeCache *lru.Cache
 	hash      *keccak.Keccak
+
+	// accessList records the accesses of the transactions, nil if they aren't recorded
+	accessList *BlockAccessList
 }
 
 func NewTxn(state State, snapshot Snapshot) *Txn {
@@ -95,6 +104,8 @@ func (txn *Txn) GetAccount(addr types.Address) (*Account, bool) {
 }
 
 func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
+	txn.accessList.touchAccount(addr)
+
 	// Try to get state from radix tree which holds transient states during block processing first
 	val, exists := txn.txn.Get(addr.Bytes())
 	if exists {
@@ -106,7 +117,21 @@ func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
 		return obj.Copy(), true
 	}
 
-	data, ok := txn.snapshot.Get(txn.hashit(addr.Bytes()))
+	// the accounts of a remote chain are read by their addresses
+	if remote, ok := txn.snapshot.(RemoteSnapshot); ok {
+		account, ok := remote.GetAccount(addr)
+		if !ok {
+			return nil, false
+		}
+
+		return &StateObject{
+			Account: account.Copy(),
+		}, true
+	}
+
+	addrHash := types.BytesToHash(txn.hashit(addr.Bytes()))
+
+	data, ok := txn.snapshot.Get(addrHash.Bytes())
 	if !ok {
 		return nil, false
 	}
@@ -119,7 +144,9 @@ func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
 	}
 
 	// Load trie from memory if there is some state
-	if account.Root == emptyStateHash {
+	if flat, ok := txn.snapshot.(FlatSnapshot); ok {
+		account.Trie = flat.Storage(addrHash, account.Root)
+	} else if account.Root == emptyStateHash {
 		account.Trie = txn.state.NewSnapshot()
 	} else {
 		account.Trie, err = txn.state.NewSnapshotAt(account.Root)
@@ -327,6 +354,8 @@ func (txn *Txn) SetState(
 	key,
 	value types.Hash,
 ) {
+	txn.accessList.touchSlot(addr, key)
+
 	txn.upsertAccount(addr, true, func(object *StateObject) {
 		if object.Txn == nil {
 			object.Txn = iradix.New().Txn()
@@ -340,8 +369,19 @@ func (txn *Txn) SetState(
 	})
 }
 
+// ClearStorage empties the storage of the address, along with its modified slots
+func (txn *Txn) ClearStorage(addr types.Address) {
+	txn.upsertAccount(addr, true, func(object *StateObject) {
+		object.Account.Root = emptyStateHash
+		object.Account.Trie = txn.state.NewSnapshot()
+		object.Txn = nil
+	})
+}
+
 // GetState returns the state of the address at a given key
 func (txn *Txn) GetState(addr types.Address, key types.Hash) types.Hash {
+	txn.accessList.touchSlot(addr, key)
+
 	object, exists := txn.getStateObject(addr)
 	if !exists {
 		return types.Hash{}
@@ -361,9 +401,58 @@ func (txn *Txn) GetState(addr types.Address, key types.Hash) types.Hash {
 	}
 
 	// If the object was not found in the radix trie due to no state update, we fetch it from the trie tre
-	k := txn.hashit(key.Bytes())
+	return txn.getCommittedSlot(object, key)
+}
+
+// getCommittedSlot reads the slot of the account from its storage, by the plain key
+// if the storage reads the slots of a remote chain
+func (txn *Txn) getCommittedSlot(object *StateObject, key types.Hash) types.Hash {
+	if reader, ok := object.Account.Trie.(SlotReader); ok {
+		val, ok := reader.GetSlot(key)
+		if !ok {
+			return types.Hash{}
+		}
+
+		return decodeSlot(val)
+	}
+
+	return object.GetCommitedState(types.BytesToHash(txn.hashit(key.Bytes())))
+}
+
+// Transient storage
+
+// indexTree returns the tree stored at the index. The tree is immutable and replaced on every write,
+// so it is reverted along with the snapshots
+func (txn *Txn) indexTree(index []byte) *iradix.Tree {
+	data, exists := txn.txn.Get(index)
+	if !exists {
+		return iradix.New()
+	}
+
+	//nolint:forcetypeassert
+	return data.(*iradix.Tree)
+}
+
+// GetTransientState returns the transient state of the address at the given key
+func (txn *Txn) GetTransientState(addr types.Address, key types.Hash) types.Hash {
+	val, ok := txn.indexTree(transientIndex).Get(append(addr.Bytes(), key.Bytes()...))
+	if !ok {
+		return types.Hash{}
+	}
 
-	return object.GetCommitedState(types.BytesToHash(k))
+	//nolint:forcetypeassert
+	return val.(types.Hash)
+}
+
+// SetTransientState sets the transient state of the address at the given key
+func (txn *Txn) SetTransientState(addr types.Address, key, value types.Hash) {
+	storage, _, _ := txn.indexTree(transientIndex).Insert(append(addr.Bytes(), key.Bytes()...), value)
+	txn.txn.Insert(transientIndex, storage)
+}
+
+// ClearTransientStorage discards the transient storage at the end of the transaction
+func (txn *Txn) ClearTransientStorage() {
+	txn.txn.Delete(transientIndex)
 }
 
 // Nonce
@@ -499,12 +588,14 @@ func (txn *Txn) GetRefund() uint64 {
 
 // GetCommittedState returns the state of the address in the trie
 func (txn *Txn) GetCommittedState(addr types.Address, key types.Hash) types.Hash {
+	txn.accessList.touchSlot(addr, key)
+
 	obj, ok := txn.getStateObject(addr)
 	if !ok {
 		return types.Hash{}
 	}
 
-	return obj.GetCommitedState(types.BytesToHash(txn.hashit(key.Bytes())))
+	return txn.getCommittedSlot(obj, key)
 }
 
 func (txn *Txn) TouchAccount(addr types.Address) {
@@ -557,6 +648,21 @@ func (txn *Txn) CreateAccount(addr types.Address) {
 	}
 
 	txn.txn.Insert(addr.Bytes(), obj)
+
+	created, _, _ := txn.indexTree(createdIndex).Insert(addr.Bytes(), struct{}{})
+	txn.txn.Insert(createdIndex, created)
+}
+
+// IsCreated returns true if the account was created in the current transaction
+func (txn *Txn) IsCreated(addr types.Address) bool {
+	_, ok := txn.indexTree(createdIndex).Get(addr.Bytes())
+
+	return ok
+}
+
+// ClearCreatedAccounts forgets the accounts created in the transaction, at its end
+func (txn *Txn) ClearCreatedAccounts() {
+	txn.txn.Delete(createdIndex)
 }
 
 func (txn *Txn) CleanDeleteObjects(deleteEmptyObjects bool) {
@@ -35,6 +35,14 @@ func (m *mockState) GetCode(hash types.Hash) ([]byte, bool) {
 	panic("Not implemented in tests")
 }
 
+func (m *mockState) Prove(root types.Hash, key []byte) ([][]byte, error) {
+	panic("Not implemented in tests")
+}
+
+func (m *mockState) Iterate(root types.Hash, handler func(key, value []byte) error) error {
+	panic("Not implemented in tests")
+}
+
 type mockSnapshot struct {
 	data map[string][]byte
 }
@@ -145,3 +153,26 @@ func hashit(k []byte) []byte {
 
 	return h.Sum(nil)
 }
+
+func TestTransientStorage(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+	txn.SetState(addr1, hash1, hash2)
+
+	txn.SetTransientState(addr1, hash1, hash1)
+	assert.Equal(t, hash1, txn.GetTransientState(addr1, hash1))
+	assert.Equal(t, types.Hash{}, txn.GetTransientState(addr2, hash1))
+
+	ss := txn.Snapshot()
+	txn.SetTransientState(addr1, hash1, hash2)
+	txn.SetTransientState(addr2, hash1, hash2)
+	assert.Equal(t, hash2, txn.GetTransientState(addr1, hash1))
+
+	txn.RevertToSnapshot(ss)
+	assert.Equal(t, hash1, txn.GetTransientState(addr1, hash1))
+	assert.Equal(t, types.Hash{}, txn.GetTransientState(addr2, hash1))
+
+	// the transient storage isn't persisted
+	txn.ClearTransientStorage()
+	assert.Equal(t, types.Hash{}, txn.GetTransientState(addr1, hash1))
+	assert.Equal(t, hash2, txn.GetState(addr1, hash1))
+}
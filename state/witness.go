@@ -0,0 +1,70 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var ErrWitnessRootMismatch = errors.New("witness execution state root mismatch")
+
+// Witness is the state read while executing a block on top of its parent state: the trie nodes, the contract code
+// and the hashes of the ancestors read by the BLOCKHASH opcode. It is enough to execute the block again without
+// the state, and to verify its state root
+type Witness struct {
+	Nodes       [][]byte
+	Codes       [][]byte
+	BlockHashes map[uint64]types.Hash
+}
+
+// WitnessState is a state recording the trie nodes and the contract code read from it
+type WitnessState interface {
+	State
+
+	// Witness returns the trie nodes and the contract code read so far
+	Witness() *Witness
+}
+
+// Witness re-executes the block on top of its parent state read from the witness state,
+// and returns the witness of the block once its state root is verified
+func (e *Executor) Witness(
+	st WitnessState,
+	parentRoot types.Hash,
+	block *types.Block,
+	blockCreator types.Address,
+) (*Witness, error) {
+	blockHashes := make(map[uint64]types.Hash)
+
+	executor := &Executor{
+		logger: e.logger,
+		config: e.config,
+		state:  st,
+		GetHash: func(header *types.Header) GetHashByNumber {
+			getHash := e.GetHash(header)
+
+			return func(i uint64) types.Hash {
+				hash := getHash(i)
+				blockHashes[i] = hash
+
+				return hash
+			}
+		},
+		PostHook:    e.PostHook,
+		Precompiles: e.Precompiles,
+	}
+
+	transition, err := executor.ProcessBlock(parentRoot, block, blockCreator)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, root := transition.Commit(); root != block.Header.StateRoot {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrWitnessRootMismatch, block.Header.StateRoot, root)
+	}
+
+	witness := st.Witness()
+	witness.BlockHashes = blockHashes
+
+	return witness, nil
+}
@@ -0,0 +1,511 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/statesync/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// requestTimeout is the timeout of a single request to a peer
+var requestTimeout = 30 * time.Second
+
+var emptyCodeHash = types.BytesToHash(crypto.Keccak256(nil))
+
+// statePeer is a peer serving the state at the pivot block
+type statePeer struct {
+	id     peer.ID
+	client proto.StateSyncClient
+	close  func() error
+}
+
+// Fetch downloads the state at the checkpoint from the peers, and writes it into the state.
+// The account trie is split into segments fetched from all the peers serving the checkpoint in parallel,
+// range by range along with the storage tries and the codes of their accounts. A peer failing to return
+// a valid range is not used anymore. The trie is then healed from the checkpoint state root.
+// The progress of the segments is kept on disk, so an interrupted fetch is resumed, even at another checkpoint
+// as the healing fetches the nodes which changed since then. It returns the checkpoint header once its state
+// is complete
+func (s *StateSync) Fetch(ctx context.Context, checkpoint Checkpoint, peerIDs []peer.ID) (*types.Header, error) {
+	peers, header := s.findPivotPeers(ctx, checkpoint, peerIDs)
+	if len(peers) == 0 {
+		return nil, ErrNoPivotPeers
+	}
+
+	defer closePeers(peers)
+
+	if err := s.fetchState(ctx, peers, header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// FetchState downloads the state at the given header from the peers, and writes it into the state.
+// Unlike Fetch, the header is trusted by the caller rather than agreed on as a pivot block,
+// so it's fetched from all the peers which still have its state
+func (s *StateSync) FetchState(ctx context.Context, header *types.Header, peerIDs []peer.ID) error {
+	peers := s.connectPeers(peerIDs)
+	if len(peers) == 0 {
+		return ErrNoStatePeers
+	}
+
+	defer closePeers(peers)
+
+	return s.fetchState(ctx, peers, header)
+}
+
+// fetchState fetches the segments of the state at the header from the peers, then heals it
+func (s *StateSync) fetchState(ctx context.Context, peers []*statePeer, header *types.Header) error {
+	progress, err := loadSyncProgress(s.dir)
+	if err != nil {
+		return err
+	}
+
+	pending := progress.pending()
+
+	s.logger.Info(
+		"fetching state",
+		"number", header.Number,
+		"root", header.StateRoot,
+		"segments", len(pending),
+		"peers", len(peers),
+	)
+
+	if err := s.fetchSegments(ctx, peers, header.StateRoot, progress, pending); err != nil {
+		return err
+	}
+
+	s.logger.Info("healing state", "number", header.Number, "root", header.StateRoot)
+
+	if err := s.state.Heal(header.StateRoot, s.healFetcher(ctx, peers)); err != nil {
+		return fmt.Errorf("failed to heal the state: %w", err)
+	}
+
+	if err := progress.remove(); err != nil {
+		s.logger.Warn("failed to remove the sync progress", "err", err)
+	}
+
+	return nil
+}
+
+// LatestCheckpoint returns the latest pivot block served by the peers.
+// The checkpoint is not trusted until the header chain up to it is verified
+func (s *StateSync) LatestCheckpoint(ctx context.Context, peerIDs []peer.ID) (Checkpoint, error) {
+	var latest *types.Header
+
+	for _, id := range peerIDs {
+		conn, err := s.network.NewProtoConnection(stateSyncProto, id)
+		if err != nil {
+			s.logger.Debug("failed to connect to peer", "peer", id, "err", err)
+
+			continue
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		pivot, err := proto.NewStateSyncClient(conn).GetPivot(reqCtx, &emptypb.Empty{})
+
+		cancel()
+
+		_ = conn.Close()
+
+		if err != nil {
+			continue
+		}
+
+		if header, err := pivotHeader(pivot); err == nil && (latest == nil || header.Number > latest.Number) {
+			latest = header
+		}
+	}
+
+	if latest == nil {
+		return Checkpoint{}, ErrNoPivotPeers
+	}
+
+	return Checkpoint{Number: latest.Number, Hash: latest.Hash}, nil
+}
+
+// FetchedChunks returns the number of the ranges, trie nodes and codes responses fetched from the peers so far,
+// it's used to detect a stalled fetch
+func (s *StateSync) FetchedChunks() uint64 {
+	return atomic.LoadUint64(&s.fetchedChunks)
+}
+
+// connectPeers opens the connections to the peers, the ones which can't be reached are skipped
+func (s *StateSync) connectPeers(peerIDs []peer.ID) []*statePeer {
+	peers := make([]*statePeer, 0, len(peerIDs))
+
+	for _, id := range peerIDs {
+		conn, err := s.network.NewProtoConnection(stateSyncProto, id)
+		if err != nil {
+			s.logger.Debug("failed to connect to peer", "peer", id, "err", err)
+
+			continue
+		}
+
+		peers = append(peers, &statePeer{id: id, client: proto.NewStateSyncClient(conn), close: conn.Close})
+	}
+
+	return peers
+}
+
+// closePeers closes the connections to the peers
+func closePeers(peers []*statePeer) {
+	for _, p := range peers {
+		_ = p.close()
+	}
+}
+
+// findPivotPeers returns the peers serving the state at the checkpoint, along with its header
+func (s *StateSync) findPivotPeers(
+	ctx context.Context,
+	checkpoint Checkpoint,
+	peerIDs []peer.ID,
+) ([]*statePeer, *types.Header) {
+	var (
+		peers  = make([]*statePeer, 0, len(peerIDs))
+		header *types.Header
+	)
+
+	for _, p := range s.connectPeers(peerIDs) {
+		reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		pivot, err := p.client.GetPivot(reqCtx, &emptypb.Empty{})
+
+		cancel()
+
+		if err != nil {
+			_ = p.close()
+
+			continue
+		}
+
+		peerHeader, err := pivotHeader(pivot)
+		if err != nil || peerHeader.Number != checkpoint.Number || peerHeader.Hash != checkpoint.Hash {
+			_ = p.close()
+
+			continue
+		}
+
+		header = peerHeader
+		peers = append(peers, p)
+	}
+
+	return peers, header
+}
+
+// fetchSegments fetches the ranges of the segments of the account trie from the peers in parallel
+func (s *StateSync) fetchSegments(
+	ctx context.Context,
+	peers []*statePeer,
+	root types.Hash,
+	progress *syncProgress,
+	segments []*segment,
+) error {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	var (
+		tasks     = make(chan *segment, len(segments))
+		remaining = int64(len(segments))
+		doneCh    = make(chan struct{})
+		doneOnce  sync.Once
+		wg        sync.WaitGroup
+	)
+
+	for _, seg := range segments {
+		tasks <- seg
+	}
+
+	for _, p := range peers {
+		wg.Add(1)
+
+		go func(p *statePeer) {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-doneCh:
+					return
+				case seg := <-tasks:
+					done, err := s.fetchAccountRange(ctx, p, root, progress, seg)
+					if err != nil {
+						s.logger.Warn("failed to fetch range, dropping peer", "peer", p.id, "origin", seg.Next, "err", err)
+					}
+
+					if !done {
+						// hand the segment over to the next peer, or keep fetching it
+						tasks <- seg
+					} else if atomic.AddInt64(&remaining, -1) == 0 {
+						doneOnce.Do(func() { close(doneCh) })
+					}
+
+					if err != nil {
+						return
+					}
+				}
+			}
+		}(p)
+	}
+
+	wg.Wait()
+
+	if left := atomic.LoadInt64(&remaining); left > 0 {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return fmt.Errorf("%d segments of the state could not be fetched from any peer", left)
+	}
+
+	return nil
+}
+
+// fetchAccountRange fetches the next range of the segment from the peer, along with the storage tries and
+// the codes of its accounts, and writes it into the state. The account trie nodes are written last, so the stored
+// nodes are always the roots of complete subtrees. It returns whether the segment is complete
+func (s *StateSync) fetchAccountRange(
+	ctx context.Context,
+	p *statePeer,
+	root types.Hash,
+	progress *syncProgress,
+	seg *segment,
+) (bool, error) {
+	keys, values, entries, more, err := s.fetchRange(ctx, p, root, seg.Next.Bytes())
+	if err != nil {
+		return false, err
+	}
+
+	codes := make([]types.Hash, 0)
+
+	for i, value := range values {
+		var account state.Account
+		if err := account.UnmarshalRlp(value); err != nil {
+			return false, fmt.Errorf("invalid account %x: %w", keys[i], err)
+		}
+
+		if err := s.fetchStorage(ctx, p, account.Root); err != nil {
+			return false, err
+		}
+
+		codeHash := types.BytesToHash(account.CodeHash)
+		if len(account.CodeHash) == 0 || codeHash == emptyCodeHash {
+			continue
+		}
+
+		if _, ok := s.state.GetCode(codeHash); !ok {
+			codes = append(codes, codeHash)
+		}
+	}
+
+	if err := s.fetchCodes(ctx, p, codes); err != nil {
+		return false, err
+	}
+
+	s.state.WriteSnapshotEntries(entries)
+
+	var (
+		done = !more
+		next = seg.Next
+	)
+
+	if len(keys) > 0 {
+		last := types.BytesToHash(keys[len(keys)-1])
+
+		done = done || bytes.Compare(last.Bytes(), seg.Limit.Bytes()) >= 0
+		next = nextKey(last)
+	}
+
+	if err := progress.update(seg, next, done); err != nil {
+		s.logger.Warn("failed to save the sync progress", "err", err)
+	}
+
+	return done, nil
+}
+
+// fetchStorage fetches the storage trie with the given root from the peer, if it isn't stored yet
+func (s *StateSync) fetchStorage(ctx context.Context, p *statePeer, root types.Hash) error {
+	if root == types.EmptyRootHash || root == types.ZeroHash {
+		return nil
+	}
+
+	if _, ok := s.state.TrieNode(root); ok {
+		return nil
+	}
+
+	origin := types.ZeroHash
+
+	for {
+		keys, _, entries, more, err := s.fetchRange(ctx, p, root, origin.Bytes())
+		if err != nil {
+			return err
+		}
+
+		s.state.WriteSnapshotEntries(entries)
+
+		if !more {
+			break
+		}
+
+		origin = nextKey(types.BytesToHash(keys[len(keys)-1]))
+	}
+
+	// the nodes on the edges of the ranges are missing
+	return s.state.HealStorage(root, func(hashes []types.Hash, code bool) ([][]byte, error) {
+		return s.fetchValues(ctx, p, hashes, code)
+	})
+}
+
+// fetchRange fetches the range of the trie from the origin, and verifies it against the root
+func (s *StateSync) fetchRange(
+	ctx context.Context,
+	p *statePeer,
+	root types.Hash,
+	origin []byte,
+) ([][]byte, [][]byte, []*itrie.SnapshotEntry, bool, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	rng, err := p.client.GetRange(reqCtx, &proto.GetRangeRequest{
+		Root:     root.Bytes(),
+		Origin:   origin,
+		MaxBytes: uint64(s.rangeSize),
+	})
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	entries, more, err := itrie.VerifyRangeProof(root, origin, rng.Keys, rng.Values, rng.Proof)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	atomic.AddUint64(&s.fetchedChunks, 1)
+
+	return rng.Keys, rng.Values, entries, more, nil
+}
+
+// fetchCodes fetches the codes with the given hashes from the peer, and writes them into the state
+func (s *StateSync) fetchCodes(ctx context.Context, p *statePeer, hashes []types.Hash) error {
+	for len(hashes) > 0 {
+		batch := hashes
+		if len(batch) > maxValuesPerRequest {
+			batch = batch[:maxValuesPerRequest]
+		}
+
+		hashes = hashes[len(batch):]
+
+		codes, err := s.fetchValues(ctx, p, batch, true)
+		if err != nil {
+			return err
+		}
+
+		entries := make([]*itrie.SnapshotEntry, len(codes))
+		for i, code := range codes {
+			entries[i] = &itrie.SnapshotEntry{Hash: batch[i], Value: code, Code: true}
+		}
+
+		s.state.WriteSnapshotEntries(entries)
+	}
+
+	return nil
+}
+
+// fetchValues fetches the trie nodes, or the codes, with the given hashes from the peer, and verifies them
+func (s *StateSync) fetchValues(ctx context.Context, p *statePeer, hashes []types.Hash, code bool) ([][]byte, error) {
+	req := &proto.GetValuesRequest{Hashes: make([][]byte, len(hashes))}
+	for i, hash := range hashes {
+		req.Hashes[i] = hash.Bytes()
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var (
+		res *proto.Values
+		err error
+	)
+
+	if code {
+		res, err = p.client.GetCodes(reqCtx, req)
+	} else {
+		res, err = p.client.GetTrieNodes(reqCtx, req)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Values) != len(hashes) {
+		return nil, fmt.Errorf("expected %d values, got %d", len(hashes), len(res.Values))
+	}
+
+	for i, value := range res.Values {
+		if !bytes.Equal(crypto.Keccak256(value), hashes[i].Bytes()) {
+			return nil, fmt.Errorf("value does not match its hash %s", hashes[i])
+		}
+	}
+
+	atomic.AddUint64(&s.fetchedChunks, 1)
+
+	return res.Values, nil
+}
+
+// healFetcher returns the fetcher of the trie nodes and codes missing from the state, which are requested
+// from the peers in turn. A peer failing to return them is not used anymore
+func (s *StateSync) healFetcher(ctx context.Context, peers []*statePeer) itrie.HealFetcher {
+	return func(hashes []types.Hash, code bool) ([][]byte, error) {
+		for len(peers) > 0 {
+			values, err := s.fetchValues(ctx, peers[0], hashes, code)
+			if err == nil {
+				return values, nil
+			}
+
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			s.logger.Warn("failed to fetch trie nodes, dropping peer", "peer", peers[0].id, "err", err)
+
+			peers = peers[1:]
+		}
+
+		return nil, errors.New("trie nodes could not be fetched from any peer")
+	}
+}
+
+// pivotHeader decodes the header of the pivot block
+func pivotHeader(pivot *proto.Pivot) (*types.Header, error) {
+	header := &types.Header{}
+	if err := header.UnmarshalRLP(pivot.Header); err != nil {
+		return nil, err
+	}
+
+	header.ComputeHash()
+
+	return header, nil
+}
+
+// nextKey returns the key following the given one, the last key is followed by itself
+func nextKey(key types.Hash) types.Hash {
+	next := new(big.Int).Add(new(big.Int).SetBytes(key.Bytes()), big.NewInt(1))
+	if next.BitLen() > types.HashLength*8 {
+		return key
+	}
+
+	return types.BytesToHash(next.Bytes())
+}
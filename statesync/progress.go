@@ -0,0 +1,117 @@
+package statesync
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// progressFile is the name of the file of the sync progress in the state sync directory
+	progressFile = "progress.json"
+
+	// segmentsCount is the number of the segments of the account trie fetched in parallel
+	segmentsCount = 16
+)
+
+// segment is a part of the account trie key space, fetched range by range
+type segment struct {
+	// Next is the key of the next range to fetch
+	Next types.Hash `json:"next"`
+	// Limit is the last key of the segment
+	Limit types.Hash `json:"limit"`
+	// Done is set once the whole segment is fetched
+	Done bool `json:"done"`
+}
+
+// syncProgress is the progress of the segments of the account trie. The fetched ranges are verified against
+// the state root they were fetched at, so they are kept when the sync is resumed at another pivot block
+type syncProgress struct {
+	path string
+
+	lock     sync.Mutex
+	Segments []*segment `json:"segments"`
+}
+
+// loadSyncProgress reads the sync progress from the directory,
+// a new one made of the segments splitting the key space evenly is returned if there is none
+func loadSyncProgress(dir string) (*syncProgress, error) {
+	progress := &syncProgress{path: filepath.Join(dir, progressFile)}
+
+	data, err := os.ReadFile(progress.path)
+	if err == nil {
+		if err := json.Unmarshal(data, progress); err != nil {
+			return nil, err
+		}
+
+		if len(progress.Segments) == segmentsCount {
+			return progress, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	progress.Segments = make([]*segment, segmentsCount)
+
+	for i := range progress.Segments {
+		var next, limit types.Hash
+
+		next[0] = byte(i * 256 / segmentsCount)
+		limit[0] = byte((i+1)*256/segmentsCount - 1)
+
+		for j := 1; j < types.HashLength; j++ {
+			limit[j] = 0xff
+		}
+
+		progress.Segments[i] = &segment{Next: next, Limit: limit}
+	}
+
+	return progress, nil
+}
+
+// pending returns the segments which are not fetched yet
+func (p *syncProgress) pending() []*segment {
+	pending := make([]*segment, 0, len(p.Segments))
+
+	for _, seg := range p.Segments {
+		if !seg.Done {
+			pending = append(pending, seg)
+		}
+	}
+
+	return pending
+}
+
+// update moves the segment to its next key, or marks it as done, and writes the progress
+func (p *syncProgress) update(seg *segment, next types.Hash, done bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	seg.Next, seg.Done = next, done
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	// written under a temporary name first, so the progress is never partially written
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, p.path)
+}
+
+// remove removes the progress once the state is complete
+func (p *syncProgress) remove() error {
+	if err := os.Remove(p.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// StateSyncClient is the client API for StateSync service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StateSyncClient interface {
+	// Returns the header of the latest pivot block whose state is served by the peer
+	GetPivot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Pivot, error)
+	// Returns the consecutive keys and values of a state trie from the origin key, along with their proof
+	GetRange(ctx context.Context, in *GetRangeRequest, opts ...grpc.CallOption) (*Range, error)
+	// Returns the state trie nodes with the given hashes
+	GetTrieNodes(ctx context.Context, in *GetValuesRequest, opts ...grpc.CallOption) (*Values, error)
+	// Returns the contract codes with the given hashes
+	GetCodes(ctx context.Context, in *GetValuesRequest, opts ...grpc.CallOption) (*Values, error)
+}
+
+type stateSyncClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStateSyncClient(cc grpc.ClientConnInterface) StateSyncClient {
+	return &stateSyncClient{cc}
+}
+
+func (c *stateSyncClient) GetPivot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Pivot, error) {
+	out := new(Pivot)
+	err := c.cc.Invoke(ctx, "/v1.StateSync/GetPivot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateSyncClient) GetRange(ctx context.Context, in *GetRangeRequest, opts ...grpc.CallOption) (*Range, error) {
+	out := new(Range)
+	err := c.cc.Invoke(ctx, "/v1.StateSync/GetRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateSyncClient) GetTrieNodes(ctx context.Context, in *GetValuesRequest, opts ...grpc.CallOption) (*Values, error) {
+	out := new(Values)
+	err := c.cc.Invoke(ctx, "/v1.StateSync/GetTrieNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateSyncClient) GetCodes(ctx context.Context, in *GetValuesRequest, opts ...grpc.CallOption) (*Values, error) {
+	out := new(Values)
+	err := c.cc.Invoke(ctx, "/v1.StateSync/GetCodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StateSyncServer is the server API for StateSync service.
+// All implementations must embed UnimplementedStateSyncServer
+// for forward compatibility
+type StateSyncServer interface {
+	// Returns the header of the latest pivot block whose state is served by the peer
+	GetPivot(context.Context, *emptypb.Empty) (*Pivot, error)
+	// Returns the consecutive keys and values of a state trie from the origin key, along with their proof
+	GetRange(context.Context, *GetRangeRequest) (*Range, error)
+	// Returns the state trie nodes with the given hashes
+	GetTrieNodes(context.Context, *GetValuesRequest) (*Values, error)
+	// Returns the contract codes with the given hashes
+	GetCodes(context.Context, *GetValuesRequest) (*Values, error)
+	mustEmbedUnimplementedStateSyncServer()
+}
+
+// UnimplementedStateSyncServer must be embedded to have forward compatible implementations.
+type UnimplementedStateSyncServer struct {
+}
+
+func (UnimplementedStateSyncServer) GetPivot(context.Context, *emptypb.Empty) (*Pivot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPivot not implemented")
+}
+func (UnimplementedStateSyncServer) GetRange(context.Context, *GetRangeRequest) (*Range, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRange not implemented")
+}
+func (UnimplementedStateSyncServer) GetTrieNodes(context.Context, *GetValuesRequest) (*Values, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrieNodes not implemented")
+}
+func (UnimplementedStateSyncServer) GetCodes(context.Context, *GetValuesRequest) (*Values, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCodes not implemented")
+}
+func (UnimplementedStateSyncServer) mustEmbedUnimplementedStateSyncServer() {}
+
+// UnsafeStateSyncServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StateSyncServer will
+// result in compilation errors.
+type UnsafeStateSyncServer interface {
+	mustEmbedUnimplementedStateSyncServer()
+}
+
+func RegisterStateSyncServer(s grpc.ServiceRegistrar, srv StateSyncServer) {
+	s.RegisterService(&StateSync_ServiceDesc, srv)
+}
+
+func _StateSync_GetPivot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateSyncServer).GetPivot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.StateSync/GetPivot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateSyncServer).GetPivot(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateSync_GetRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateSyncServer).GetRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.StateSync/GetRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateSyncServer).GetRange(ctx, req.(*GetRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateSync_GetTrieNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateSyncServer).GetTrieNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.StateSync/GetTrieNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateSyncServer).GetTrieNodes(ctx, req.(*GetValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateSync_GetCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateSyncServer).GetCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.StateSync/GetCodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateSyncServer).GetCodes(ctx, req.(*GetValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StateSync_ServiceDesc is the grpc.ServiceDesc for StateSync service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StateSync_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.StateSync",
+	HandlerType: (*StateSyncServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPivot",
+			Handler:    _StateSync_GetPivot_Handler,
+		},
+		{
+			MethodName: "GetRange",
+			Handler:    _StateSync_GetRange_Handler,
+		},
+		{
+			MethodName: "GetTrieNodes",
+			Handler:    _StateSync_GetTrieNodes_Handler,
+		},
+		{
+			MethodName: "GetCodes",
+			Handler:    _StateSync_GetCodes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "statesync/proto/statesync.proto",
+}
@@ -0,0 +1,185 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/network/grpc"
+	"github.com/0xPolygon/polygon-edge/statesync/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	stateSyncProto = "/statesync/0.2"
+
+	// defaultRangeSize is the approximate size in bytes of the ranges requested from the peers
+	defaultRangeSize = 512 * 1024
+
+	// maxRangeSize is the maximum size in bytes of the ranges served to the peers
+	maxRangeSize = 2 * 1024 * 1024
+
+	// maxValuesPerRequest is the maximum number of the trie nodes or codes served at once
+	maxValuesPerRequest = 1024
+)
+
+var (
+	ErrNoPivot      = errors.New("no pivot block served")
+	ErrNoPivotPeers = errors.New("no peer serves the state at the pivot block")
+	ErrNoStatePeers = errors.New("no peer to fetch the state from")
+	ErrInvalidKey   = errors.New("invalid root or origin key")
+	ErrTooManyItems = errors.New("too many values requested")
+)
+
+// StateSync serves the state at the recent blocks to the peers and fetches it from the peers.
+// The state is served in ranges of consecutive keys of the account and storage tries, along with the proofs
+// of the ranges, so a range is verified against the state root alone and the ranges are fetched in parallel.
+// The syncing peers agree on the pivot block to fetch the state at, which is the latest block whose number
+// is a multiple of the interval. The nodes on the edges of the ranges are fetched by their hash afterwards,
+// which is the healing of the trie
+type StateSync struct {
+	proto.UnimplementedStateSyncServer
+
+	logger     hclog.Logger
+	network    Network
+	blockchain Blockchain
+	state      State
+
+	dir       string
+	interval  uint64
+	rangeSize int
+
+	stream *grpc.GrpcStream
+
+	fetchedChunks uint64 // number of the ranges, nodes and codes responses fetched from the peers
+}
+
+// NewStateSync creates a new StateSync storing its sync progress in the directory.
+// The state is served at the blocks every interval blocks, 0 disables serving it
+func NewStateSync(
+	logger hclog.Logger,
+	network Network,
+	blockchain Blockchain,
+	state State,
+	dir string,
+	interval uint64,
+) *StateSync {
+	return &StateSync{
+		logger:     logger.Named("statesync"),
+		network:    network,
+		blockchain: blockchain,
+		state:      state,
+		dir:        dir,
+		interval:   interval,
+		rangeSize:  defaultRangeSize,
+	}
+}
+
+// Start starts serving the state to the peers
+func (s *StateSync) Start() error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	s.stream = grpc.NewGrpcStream()
+
+	proto.RegisterStateSyncServer(s.stream.GrpcServer(), s)
+	s.stream.Serve()
+	s.network.RegisterProtocol(stateSyncProto, s.stream)
+
+	return nil
+}
+
+// Close stops serving the state
+func (s *StateSync) Close() error {
+	if s.stream != nil {
+		return s.stream.Close()
+	}
+
+	return nil
+}
+
+// pivot returns the header of the latest pivot block
+func (s *StateSync) pivot() (*types.Header, bool) {
+	if s.interval == 0 || s.blockchain == nil {
+		return nil, false
+	}
+
+	number := s.blockchain.Header().Number
+	number -= number % s.interval
+
+	if number == 0 {
+		return nil, false
+	}
+
+	return s.blockchain.GetHeaderByNumber(number)
+}
+
+// GetPivot is a gRPC endpoint to return the header of the latest pivot block
+func (s *StateSync) GetPivot(context.Context, *emptypb.Empty) (*proto.Pivot, error) {
+	header, ok := s.pivot()
+	if !ok {
+		return nil, status.Error(codes.NotFound, ErrNoPivot.Error())
+	}
+
+	return &proto.Pivot{Header: header.MarshalRLP()}, nil
+}
+
+// GetRange is a gRPC endpoint to return a range of the keys and values of a state trie with its proof.
+// Any trie of the stored states is served, not only the ones at the pivot blocks
+func (s *StateSync) GetRange(_ context.Context, req *proto.GetRangeRequest) (*proto.Range, error) {
+	if len(req.Root) != types.HashLength || len(req.Origin) != types.HashLength {
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidKey.Error())
+	}
+
+	maxBytes := int(req.MaxBytes)
+	if maxBytes <= 0 || maxBytes > maxRangeSize {
+		maxBytes = maxRangeSize
+	}
+
+	keys, values, proof, err := s.state.ProveRange(types.BytesToHash(req.Root), req.Origin, maxBytes)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &proto.Range{Keys: keys, Values: values, Proof: proof}, nil
+}
+
+// GetTrieNodes is a gRPC endpoint to return the state trie nodes with the given hashes
+func (s *StateSync) GetTrieNodes(_ context.Context, req *proto.GetValuesRequest) (*proto.Values, error) {
+	return s.getValues(req, s.state.TrieNode)
+}
+
+// GetCodes is a gRPC endpoint to return the contract codes with the given hashes
+func (s *StateSync) GetCodes(_ context.Context, req *proto.GetValuesRequest) (*proto.Values, error) {
+	return s.getValues(req, s.state.GetCode)
+}
+
+// getValues returns the values with the requested hashes, all of them have to be known
+func (s *StateSync) getValues(
+	req *proto.GetValuesRequest,
+	get func(types.Hash) ([]byte, bool),
+) (*proto.Values, error) {
+	if len(req.Hashes) > maxValuesPerRequest {
+		return nil, status.Error(codes.InvalidArgument, ErrTooManyItems.Error())
+	}
+
+	values := make([][]byte, len(req.Hashes))
+
+	for i, raw := range req.Hashes {
+		hash := types.BytesToHash(raw)
+
+		value, ok := get(hash)
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "%s not found", hash)
+		}
+
+		values[i] = value
+	}
+
+	return &proto.Values{Values: values}, nil
+}
@@ -0,0 +1,252 @@
+package statesync
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/statesync/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// newTestState creates a state with accounts, storage slots and contract codes,
+// and returns the header of its root
+func newTestState(t *testing.T) (*itrie.State, *types.Header) {
+	t.Helper()
+
+	st := itrie.NewState(itrie.NewMemoryStorage())
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 100; i++ {
+		addr := types.BytesToAddress([]byte{byte(i + 1)})
+		txn.SetBalance(addr, big.NewInt(int64(i+1)))
+
+		if i%10 == 0 {
+			txn.SetCode(addr, []byte{0x60, byte(i)})
+
+			for j := 0; j < i+10; j++ {
+				txn.SetState(addr, types.BytesToHash([]byte{byte(j)}), types.BytesToHash([]byte{byte(i + j + 1)}))
+			}
+		}
+	}
+
+	_, root := st.NewSnapshot().Commit(txn.Commit(false))
+
+	header := &types.Header{
+		Number:    testInterval,
+		StateRoot: types.BytesToHash(root),
+	}
+	header.ComputeHash()
+
+	return st, header
+}
+
+// mockBlockchain is a chain whose head is past the pivot block
+type mockBlockchain struct {
+	head  *types.Header
+	pivot *types.Header
+}
+
+func (m *mockBlockchain) Header() *types.Header {
+	return m.head
+}
+
+func (m *mockBlockchain) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number == m.pivot.Number {
+		return m.pivot, true
+	}
+
+	return nil, false
+}
+
+// testInterval is the number of blocks between the pivot blocks of the tests
+const testInterval = 10
+
+func newTestStateSync(t *testing.T, network Network, st State, pivot *types.Header) *StateSync {
+	t.Helper()
+
+	var chain Blockchain
+	if pivot != nil {
+		chain = &mockBlockchain{head: &types.Header{Number: pivot.Number + testInterval/2}, pivot: pivot}
+	}
+
+	s := NewStateSync(hclog.NewNullLogger(), network, chain, st, t.TempDir(), testInterval)
+	s.rangeSize = 512
+
+	return s
+}
+
+func TestStateSync_Serve(t *testing.T) {
+	t.Parallel()
+
+	st, header := newTestState(t)
+	ctx := context.Background()
+
+	// no pivot block is reached yet
+	early := newTestStateSync(t, nil, st, header)
+	early.blockchain = &mockBlockchain{head: &types.Header{Number: testInterval - 1}, pivot: header}
+
+	_, err := early.GetPivot(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	s := newTestStateSync(t, nil, st, header)
+
+	pivot, err := s.GetPivot(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	pHeader, err := pivotHeader(pivot)
+	require.NoError(t, err)
+	assert.Equal(t, header.Hash, pHeader.Hash)
+
+	rng, err := s.GetRange(ctx, &proto.GetRangeRequest{
+		Root:     header.StateRoot.Bytes(),
+		Origin:   types.ZeroHash.Bytes(),
+		MaxBytes: 1024,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, rng.Keys)
+
+	_, more, err := itrie.VerifyRangeProof(header.StateRoot, types.ZeroHash.Bytes(), rng.Keys, rng.Values, rng.Proof)
+	require.NoError(t, err)
+	assert.True(t, more)
+
+	_, err = s.GetRange(ctx, &proto.GetRangeRequest{Root: header.StateRoot.Bytes()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.GetRange(ctx, &proto.GetRangeRequest{Root: types.ZeroHash.Bytes(), Origin: types.ZeroHash.Bytes()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	nodes, err := s.GetTrieNodes(ctx, &proto.GetValuesRequest{Hashes: [][]byte{header.StateRoot.Bytes()}})
+	require.NoError(t, err)
+	assert.Len(t, nodes.Values, 1)
+
+	_, err = s.GetCodes(ctx, &proto.GetValuesRequest{Hashes: [][]byte{types.ZeroHash.Bytes()}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.GetTrieNodes(ctx, &proto.GetValuesRequest{Hashes: make([][]byte, maxValuesPerRequest+1)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func newTestNetwork(t *testing.T) *network.Server {
+	t.Helper()
+
+	srv, err := network.CreateServer(&network.CreateServerParams{
+		ConfigCallback: func(c *network.Config) {
+			c.NoDiscover = true
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+
+	return srv
+}
+
+func TestStateSync_Fetch(t *testing.T) {
+	t.Parallel()
+
+	st, header := newTestState(t)
+
+	peerSrv := newTestNetwork(t)
+	peerSync := newTestStateSync(t, peerSrv, st, header)
+
+	require.NoError(t, peerSync.Start())
+
+	t.Cleanup(func() {
+		_ = peerSync.Close()
+	})
+
+	clientSrv := newTestNetwork(t)
+	dst := itrie.NewState(itrie.NewMemoryStorage())
+	client := newTestStateSync(t, clientSrv, dst, nil)
+
+	// the protocol is registered on both sides of the connection
+	require.NoError(t, client.Start())
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	require.NoError(t, network.JoinAndWait(
+		clientSrv,
+		peerSrv,
+		network.DefaultBufferTimeout,
+		network.DefaultJoinTimeout,
+	))
+
+	peers := []peer.ID{peerSrv.AddrInfo().ID}
+
+	checkpoint, err := client.LatestCheckpoint(context.Background(), peers)
+	require.NoError(t, err)
+	assert.Equal(t, Checkpoint{Number: header.Number, Hash: header.Hash}, checkpoint)
+
+	// the peer doesn't serve the state at another checkpoint
+	_, err = client.Fetch(context.Background(), Checkpoint{Number: header.Number, Hash: types.ZeroHash}, peers)
+	assert.ErrorIs(t, err, ErrNoPivotPeers)
+
+	// the segments fetched before an interruption are not fetched again, the healing fetches
+	// the nodes which are missing
+	progress, err := loadSyncProgress(client.dir)
+	require.NoError(t, err)
+
+	for _, seg := range progress.Segments[:segmentsCount/2] {
+		require.NoError(t, progress.update(seg, seg.Next, true))
+	}
+
+	fetched, err := client.Fetch(context.Background(), checkpoint, peers)
+	require.NoError(t, err)
+	assert.Equal(t, header.Hash, fetched.Hash)
+	assert.Greater(t, client.FetchedChunks(), uint64(0))
+
+	assert.NoError(t, dst.Walk(header.StateRoot, func(*itrie.SnapshotEntry) error { return nil }))
+
+	_, err = os.Stat(filepath.Join(client.dir, progressFile))
+	assert.True(t, os.IsNotExist(err))
+
+	// only the account ranges of the complete state are fetched again
+	fetchedChunks := client.FetchedChunks()
+
+	_, err = client.Fetch(context.Background(), checkpoint, peers)
+	require.NoError(t, err)
+	assert.Less(t, client.FetchedChunks()-fetchedChunks, fetchedChunks)
+
+	// the state at a trusted header is fetched from the given peers, without looking up the pivot block
+	require.NoError(t, client.FetchState(context.Background(), header, peers))
+	assert.ErrorIs(t, client.FetchState(context.Background(), header, nil), ErrNoStatePeers)
+}
+
+func TestParseCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	hash := types.StringToHash("checkpoint")
+
+	checkpoint, err := ParseCheckpoint("128:" + hash.String())
+	require.NoError(t, err)
+	assert.Equal(t, &Checkpoint{Number: 128, Hash: hash}, checkpoint)
+
+	for _, raw := range []string{
+		"128",
+		"0:" + hash.String(),
+		"-1:" + hash.String(),
+		"128:0x1234",
+		"128:" + hash.String() + "00",
+	} {
+		_, err := ParseCheckpoint(raw)
+		assert.ErrorIs(t, err, ErrInvalidCheckpoint, raw)
+	}
+}
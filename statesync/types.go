@@ -0,0 +1,76 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	rawGrpc "google.golang.org/grpc"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/network"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+type Blockchain interface {
+	// Header returns get latest header
+	Header() *types.Header
+	// GetHeaderByNumber returns the header by number
+	GetHeaderByNumber(uint64) (*types.Header, bool)
+}
+
+type Network interface {
+	// RegisterProtocol registers gRPC service
+	RegisterProtocol(string, network.Protocol)
+	// NewProtoConnection opens up a new stream on the set protocol to the peer,
+	// and returns a reference to the connection
+	NewProtoConnection(protocol string, peerID peer.ID) (*rawGrpc.ClientConn, error)
+}
+
+type State interface {
+	// ProveRange returns the keys and values of the trie from the origin, along with their proof
+	ProveRange(root types.Hash, origin []byte, maxBytes int) ([][]byte, [][]byte, [][]byte, error)
+	// TrieNode returns the stored trie node with the given hash
+	TrieNode(hash types.Hash) ([]byte, bool)
+	// GetCode returns the contract code with the given hash
+	GetCode(hash types.Hash) ([]byte, bool)
+	// WriteSnapshotEntries writes the entries into the state storage
+	WriteSnapshotEntries(entries []*itrie.SnapshotEntry)
+	// Heal fetches the missing nodes and codes of the state with the given root
+	Heal(root types.Hash, fetch itrie.HealFetcher) error
+	// HealStorage fetches the missing nodes of the storage trie with the given root
+	HealStorage(root types.Hash, fetch itrie.HealFetcher) error
+}
+
+// Checkpoint is the trusted pivot block whose state is fetched from the peers.
+// Its hash commits to the header, including the seals of the validators and the state root,
+// so every range of the state can be verified against it
+type Checkpoint struct {
+	Number uint64
+	Hash   types.Hash
+}
+
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint, expected <number>:<hash>")
+
+// ParseCheckpoint parses the checkpoint from its number and hash separated by a colon
+func ParseCheckpoint(raw string) (*Checkpoint, error) {
+	rawNumber, rawHash, ok := strings.Cut(raw, ":")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, raw)
+	}
+
+	number, err := strconv.ParseUint(rawNumber, 10, 64)
+	if err != nil || number == 0 {
+		return nil, fmt.Errorf("%w: invalid number %s", ErrInvalidCheckpoint, rawNumber)
+	}
+
+	hash, err := hex.DecodeHex(rawHash)
+	if err != nil || len(hash) != types.HashLength {
+		return nil, fmt.Errorf("%w: invalid hash %s", ErrInvalidCheckpoint, rawHash)
+	}
+
+	return &Checkpoint{Number: number, Hash: types.BytesToHash(hash)}, nil
+}
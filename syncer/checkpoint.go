@@ -0,0 +1,301 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// blockHashDepth is the number of the blocks before the checkpoint which are always backfilled,
+	// since their hashes are read by the BLOCKHASH opcode in the blocks following the checkpoint
+	blockHashDepth = 256
+
+	// backfillBatchSize is the maximum number of the blocks backfilled from a peer at once
+	backfillBatchSize = 128
+
+	// backfillRetryDelay is the delay before the background backfill retries once no peer could serve the blocks
+	backfillRetryDelay = 30 * time.Second
+)
+
+var (
+	errNoCheckpointPeers    = errors.New("no peer has reached the checkpoint")
+	errLocalChainMismatch   = errors.New("local chain doesn't match the checkpoint")
+	errEmptyBlocksResponse  = errors.New("peer returned no blocks")
+	errCheckpointNotMatched = errors.New("peer returned another block than the checkpoint")
+)
+
+// CheckpointConfig is the trusted checkpoint a fresh node starts from instead of the genesis
+type CheckpointConfig struct {
+	statesync.Checkpoint
+
+	// Backfill enables fetching the blocks before the checkpoint in the background
+	Backfill bool
+}
+
+// initCheckpoint checks the local chain against the checkpoint when the node is restarted.
+// A chain which has blocks before the checkpoint keeps being synced from the genesis
+func (s *syncer) initCheckpoint() error {
+	head := s.blockchain.Header().Number
+
+	switch {
+	case head == 0:
+		return nil
+	case head < s.checkpoint.Number:
+		s.logger.Warn("the chain has blocks before the checkpoint, it's synced from the genesis", "head", head)
+
+		s.checkpoint = nil
+
+		return nil
+	}
+
+	if header, ok := s.blockchain.GetHeaderByNumber(s.checkpoint.Number); !ok || header.Hash != s.checkpoint.Hash {
+		return fmt.Errorf("%w at %d, expected %s", errLocalChainMismatch, s.checkpoint.Number, s.checkpoint.Hash)
+	}
+
+	return nil
+}
+
+// syncCheckpoint starts the chain from the checkpoint if it's still empty, then backfills the blocks whose hashes
+// can be read by the blocks following the checkpoint. The blocks following the checkpoint are verified
+// by the consensus from the validators of its header, which the onCheckpoint callback sets up.
+// The rest of the history is backfilled in the background if it's enabled
+func (s *syncer) syncCheckpoint() error {
+	if s.blockchain.Header().Number == 0 {
+		if err := s.checkpointSync(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.backfill(s.blockHashTarget()); err != nil {
+		return fmt.Errorf("failed to backfill the blocks before the checkpoint: %w", err)
+	}
+
+	// the consensus is set up once, while the checkpoint is still the head
+	if header := s.blockchain.Header(); header.Number == s.checkpoint.Number && s.onCheckpoint != nil {
+		if err := s.onCheckpoint(header); err != nil {
+			return fmt.Errorf("failed to set up the consensus at the checkpoint: %w", err)
+		}
+	}
+
+	if s.checkpoint.Backfill {
+		go s.backfillHistory()
+	}
+
+	return nil
+}
+
+// checkpointSync starts the chain from the trusted checkpoint. The checkpoint block and its receipts
+// are fetched from a peer and verified by the checkpoint hash, then its state is fetched from the peers
+func (s *syncer) checkpointSync() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerIDs := s.checkpointPeers()
+	if len(peerIDs) == 0 {
+		return errNoCheckpointPeers
+	}
+
+	var (
+		block    *types.Block
+		receipts []*types.Receipt
+		err      error
+	)
+
+	for _, id := range peerIDs {
+		if block, receipts, err = s.fetchCheckpointBlock(id); err == nil {
+			break
+		}
+
+		s.logger.Warn("failed to fetch the checkpoint block, try to next peer", "peer ID", id, "err", err)
+	}
+
+	if block == nil {
+		return fmt.Errorf("no peer served the checkpoint block: %w", err)
+	}
+
+	s.logger.Info("fetching the state at the checkpoint", "number", block.Number(), "hash", block.Hash())
+
+	stalledCh := make(chan struct{})
+
+	go s.watchSnapStall(ctx, cancel, stalledCh)
+
+	if err := s.snapSyncer.FetchState(ctx, block.Header, peerIDs); err != nil {
+		select {
+		case <-stalledCh:
+			return errSnapSyncStalled
+		default:
+			return err
+		}
+	}
+
+	return s.blockchain.WriteCheckpoint(block, receipts, syncerName)
+}
+
+// checkpointPeers returns the peers which have reached the checkpoint
+func (s *syncer) checkpointPeers() []peer.ID {
+	ids := make([]peer.ID, 0)
+
+	s.peerMap.Range(func(_, value interface{}) bool {
+		if p, _ := value.(*NoForkPeer); p != nil && p.Number >= s.checkpoint.Number {
+			ids = append(ids, p.ID)
+		}
+
+		return true
+	})
+
+	return ids
+}
+
+// fetchCheckpointBlock fetches the checkpoint block along with its receipts from the peer
+func (s *syncer) fetchCheckpointBlock(peerID peer.ID) (*types.Block, []*types.Receipt, error) {
+	blocks, receipts, err := s.fetchBlocksReverse(peerID, s.checkpoint.Number, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if block := blocks[0]; block.Number() != s.checkpoint.Number || block.Hash() != s.checkpoint.Hash {
+		s.syncPeerClient.ReportPeer(peerID, network.PenaltyInvalidBlock)
+
+		return nil, nil, fmt.Errorf("%w: %d (%s)", errCheckpointNotMatched, block.Number(), block.Hash())
+	}
+
+	return blocks[0], receipts[0], nil
+}
+
+// blockHashTarget returns the oldest block which has to be backfilled before the blocks following
+// the checkpoint are executed
+func (s *syncer) blockHashTarget() uint64 {
+	if s.checkpoint.Number <= blockHashDepth {
+		return 1
+	}
+
+	return s.checkpoint.Number - blockHashDepth
+}
+
+// backfillHistory backfills all the blocks before the checkpoint in the background, until the syncer is closed
+func (s *syncer) backfillHistory() {
+	for {
+		err := s.backfill(1)
+		if err == nil {
+			s.logger.Info("history before the checkpoint backfilled", "checkpoint", s.checkpoint.Number)
+
+			return
+		}
+
+		if errors.Is(err, errSyncerClosed) {
+			return
+		}
+
+		s.logger.Debug("failed to backfill the history, retrying", "err", err)
+
+		select {
+		case <-s.closeCh:
+			return
+		case <-time.After(backfillRetryDelay):
+		}
+	}
+}
+
+// backfill fetches the blocks before the oldest stored block from the peers, down to the given number.
+// The blocks are trusted as the ancestors of the checkpoint, so they're neither verified by the consensus
+// nor executed
+func (s *syncer) backfill(to uint64) error {
+	skipList := make(map[peer.ID]bool)
+
+	for {
+		next := s.blockchain.MissingHistory()
+		if next < to {
+			return nil
+		}
+
+		select {
+		case <-s.closeCh:
+			return errSyncerClosed
+		default:
+		}
+
+		bestPeer := s.peerMap.BestPeer(skipList)
+		if bestPeer == nil || bestPeer.Number < s.checkpoint.Number {
+			return errNoCheckpointPeers
+		}
+
+		amount := next - to + 1
+		if amount > backfillBatchSize {
+			amount = backfillBatchSize
+		}
+
+		blocks, receipts, err := s.fetchBlocksReverse(bestPeer.ID, next, amount)
+		if err == nil {
+			if err = s.blockchain.WriteBackfilledBlocks(blocks, receipts); err != nil {
+				s.syncPeerClient.ReportPeer(bestPeer.ID, network.PenaltyInvalidBlock)
+			}
+		}
+
+		if err != nil {
+			s.logger.Warn("failed to backfill blocks from peer, try to next one", "peer ID", bestPeer.ID, "err", err)
+
+			skipList[bestPeer.ID] = true
+
+			continue
+		}
+
+		s.logger.Debug("blocks backfilled", "from", blocks[len(blocks)-1].Number(), "to", next)
+	}
+}
+
+// fetchBlocksReverse fetches the blocks from the given number downwards, along with their receipts, from the peer.
+// The peer may return less blocks than requested, whose bodies and receipts are verified against their headers
+func (s *syncer) fetchBlocksReverse(
+	peerID peer.ID,
+	from, amount uint64,
+) ([]*types.Block, [][]*types.Receipt, error) {
+	headers, err := s.syncPeerClient.GetHeaders(peerID, from, amount, 0, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]types.Hash, len(headers))
+	for i, header := range headers {
+		hashes[i] = header.Hash
+	}
+
+	bodies, err := s.syncPeerClient.GetBodies(peerID, hashes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	receipts, err := s.syncPeerClient.GetReceipts(peerID, hashes[:len(bodies)])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(receipts) == 0 {
+		return nil, nil, errEmptyBlocksResponse
+	}
+
+	blocks := make([]*types.Block, len(receipts))
+
+	for i := range blocks {
+		blocks[i] = &types.Block{
+			Header:       headers[i],
+			Transactions: bodies[i].Transactions,
+			Uncles:       bodies[i].Uncles,
+		}
+
+		if err := blockchain.VerifyBlockData(blocks[i], receipts[i]); err != nil {
+			s.syncPeerClient.ReportPeer(peerID, network.PenaltyInvalidBlock)
+
+			return nil, nil, fmt.Errorf("invalid block %d: %w", headers[i].Number, err)
+		}
+	}
+
+	return blocks, receipts, nil
+}
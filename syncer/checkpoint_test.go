@@ -0,0 +1,255 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkpointTestChain is a local chain which may start from a checkpoint
+type checkpointTestChain struct {
+	stored     map[uint64]*types.Header
+	head       uint64
+	backfilled []uint64
+}
+
+func newCheckpointTestChain(headers []*types.Header) *checkpointTestChain {
+	c := &checkpointTestChain{stored: make(map[uint64]*types.Header), backfilled: make([]uint64, 0)}
+
+	for _, header := range headers {
+		c.stored[header.Number] = header
+		c.head = header.Number
+	}
+
+	return c
+}
+
+func (c *checkpointTestChain) blockchain() *mockBlockchain {
+	return &mockBlockchain{
+		headerHandler: func() *types.Header {
+			return c.stored[c.head]
+		},
+		getHeaderByNumberHandler: func(n uint64) (*types.Header, bool) {
+			header, ok := c.stored[n]
+
+			return header, ok
+		},
+		writeCheckpointHandler: func(b *types.Block, _ []*types.Receipt) error {
+			c.stored[b.Number()] = b.Header
+			c.head = b.Number()
+
+			return nil
+		},
+		writeBackfilledHandler: func(blocks []*types.Block, _ [][]*types.Receipt) error {
+			for _, b := range blocks {
+				c.stored[b.Number()] = b.Header
+				c.backfilled = append(c.backfilled, b.Number())
+			}
+
+			return nil
+		},
+		missingHistoryHandler: func() uint64 {
+			oldest := c.head
+			for oldest > 1 && c.stored[oldest-1] != nil {
+				oldest--
+			}
+
+			if oldest == 0 {
+				return 0
+			}
+
+			return oldest - 1
+		},
+	}
+}
+
+// newCheckpointTestPeerClient returns a peer client serving the blocks of the given headers
+func newCheckpointTestPeerClient(headers []*types.Header) *mockSyncPeerClient {
+	return &mockSyncPeerClient{
+		getHeadersHandler: func(_ peer.ID, from, amount, _ uint64, _ bool) ([]*types.Header, error) {
+			res := make([]*types.Header, 0, amount)
+			for n := from; n+amount > from && n > 0; n-- {
+				res = append(res, headers[n])
+			}
+
+			return res, nil
+		},
+		getBodiesHandler: func(_ peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+			bodies := make([]*types.Body, len(hashes))
+			for i := range bodies {
+				bodies[i] = &types.Body{}
+			}
+
+			return bodies, nil
+		},
+		getReceiptsHandler: func(_ peer.ID, hashes []types.Hash) ([][]*types.Receipt, error) {
+			return make([][]*types.Receipt, len(hashes)), nil
+		},
+	}
+}
+
+func TestSyncer_initCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	headers := blockchain.NewTestHeaders(12)
+
+	tests := []struct {
+		name       string
+		local      []*types.Header
+		checkpoint statesync.Checkpoint
+		cleared    bool
+		err        error
+	}{
+		{
+			name:       "should keep the checkpoint of an empty chain",
+			local:      headers[:1],
+			checkpoint: statesync.Checkpoint{Number: 10, Hash: headers[10].Hash},
+		},
+		{
+			name:       "should sync from the genesis a chain below the checkpoint",
+			local:      headers[:6],
+			checkpoint: statesync.Checkpoint{Number: 10, Hash: headers[10].Hash},
+			cleared:    true,
+		},
+		{
+			name:       "should keep the checkpoint of a chain started from it",
+			local:      headers[10:],
+			checkpoint: statesync.Checkpoint{Number: 10, Hash: headers[10].Hash},
+		},
+		{
+			name:       "should return the error if the local chain doesn't match the checkpoint",
+			local:      headers[10:],
+			checkpoint: statesync.Checkpoint{Number: 10, Hash: headers[9].Hash},
+			err:        errLocalChainMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			syncer := NewTestSyncer(
+				nil,
+				newCheckpointTestChain(test.local).blockchain(),
+				time.Second,
+				&mockSyncPeerClient{},
+				&mockProgression{},
+			)
+			syncer.checkpoint = &CheckpointConfig{Checkpoint: test.checkpoint}
+
+			assert.ErrorIs(t, syncer.initCheckpoint(), test.err)
+			assert.Equal(t, test.cleared, syncer.checkpoint == nil)
+		})
+	}
+}
+
+func TestSyncer_syncCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	headers := blockchain.NewTestHeaders(12)
+
+	tests := []struct {
+		name       string
+		checkpoint statesync.Checkpoint
+		peerLatest uint64
+
+		head       uint64
+		backfilled []uint64
+		penalties  []network.PeerPenalty
+		err        error
+	}{
+		{
+			name:       "should start from the checkpoint and backfill the blocks before it",
+			checkpoint: statesync.Checkpoint{Number: 10, Hash: headers[10].Hash},
+			peerLatest: 11,
+			head:       10,
+			backfilled: []uint64{9, 8, 7, 6, 5, 4, 3, 2, 1},
+			penalties:  []network.PeerPenalty{},
+		},
+		{
+			name:       "should return the error if no peer has reached the checkpoint",
+			checkpoint: statesync.Checkpoint{Number: 10, Hash: headers[10].Hash},
+			peerLatest: 9,
+			backfilled: []uint64{},
+			penalties:  []network.PeerPenalty{},
+			err:        errNoCheckpointPeers,
+		},
+		{
+			name:       "should report the peer serving another block than the checkpoint",
+			checkpoint: statesync.Checkpoint{Number: 10, Hash: headers[9].Hash},
+			peerLatest: 11,
+			backfilled: []uint64{},
+			penalties:  []network.PeerPenalty{network.PenaltyInvalidBlock},
+			err:        errCheckpointNotMatched,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				chain      = newCheckpointTestChain(headers[:1])
+				peerClient = newCheckpointTestPeerClient(headers)
+				stateAt    *types.Header
+				setUpAt    *types.Header
+
+				syncer = NewTestSyncer(nil, chain.blockchain(), time.Second, peerClient, &mockProgression{})
+			)
+
+			peerClient.reportedPenalties = []network.PeerPenalty{}
+
+			syncer.checkpoint = &CheckpointConfig{Checkpoint: test.checkpoint}
+			syncer.snapSyncer = &mockSnapSyncer{
+				fetchStateHandler: func(_ context.Context, header *types.Header, _ []peer.ID) error {
+					stateAt = header
+
+					return nil
+				},
+			}
+			syncer.snapStallTimeout = time.Second
+			syncer.onCheckpoint = func(header *types.Header) error {
+				setUpAt = header
+
+				return nil
+			}
+
+			syncer.peerMap.Put(&NoForkPeer{
+				ID:       peer.ID("A"),
+				Number:   test.peerLatest,
+				Distance: big.NewInt(0),
+			})
+
+			err := syncer.syncCheckpoint()
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.head, chain.head)
+			assert.Equal(t, test.backfilled, chain.backfilled)
+			assert.Equal(t, test.penalties, peerClient.reportedPenalties)
+
+			if test.err != nil {
+				assert.Nil(t, stateAt)
+				assert.Nil(t, setUpAt)
+
+				return
+			}
+
+			require.NotNil(t, stateAt)
+			assert.Equal(t, test.checkpoint.Hash, stateAt.Hash)
+			assert.Equal(t, stateAt, setUpAt)
+		})
+	}
+}
@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/network/common"
 	"github.com/0xPolygon/polygon-edge/network/event"
 	"github.com/0xPolygon/polygon-edge/syncer/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p/core/peer"
+	rawGrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -22,6 +27,19 @@ const (
 	SyncPeerClientLoggerName = "sync-peer-client"
 	statusTopicName          = "syncer/status/0.1"
 	defaultTimeoutForStatus  = 10 * time.Second
+	defaultTimeoutForRequest = 10 * time.Second
+
+	// timeoutPerItem extends the deadline of a request for each item requested
+	timeoutPerItem = 20 * time.Millisecond
+
+	// blocksPerRequest is the number of blocks requested at once while fetching the blocks from a peer
+	blocksPerRequest = 128
+)
+
+var (
+	ErrTooManyItems      = errors.New("peer returned more items than requested")
+	ErrRequestIDMismatch = errors.New("peer response doesn't answer the request")
+	ErrBlocksNotLinked   = errors.New("peer returned blocks not linked to the previous ones")
 )
 
 type syncPeerClient struct {
@@ -35,7 +53,8 @@ type syncPeerClient struct {
 	peerStatusUpdateCh     chan *NoForkPeer        // peer status update channel
 	peerConnectionUpdateCh chan *event.PeerEvent   // peer connection update channel
 
-	shouldEmitBlocks bool // flag for emitting blocks in the topic
+	shouldEmitBlocks bool   // flag for emitting blocks in the topic
+	lastRequestID    uint64 // id of the last request sent to the peers, accessed atomically
 }
 
 func NewSyncPeerClient(
@@ -234,16 +253,36 @@ func (m *syncPeerClient) startPeerEventProcess() {
 	}
 }
 
+// ReportPeer lowers the reputation of the peer for its misbehavior
+func (m *syncPeerClient) ReportPeer(peerID peer.ID, penalty network.PeerPenalty) {
+	m.network.ReportPeer(peerID, penalty)
+}
+
 // CloseStream closes stream
 func (m *syncPeerClient) CloseStream(peerID peer.ID) error {
 	return m.network.CloseProtocolStream(syncerProto, peerID)
 }
 
-// GetBlocks returns a stream of blocks from given height to peer's latest
+// GetBlocks returns a stream of blocks from given height to peer's latest.
+// The blocks are requested by ranges of headers and their bodies from the peers echoing the request ids,
+// and streamed by the others
 func (m *syncPeerClient) GetBlocks(
 	peerID peer.ID,
 	from uint64,
 	timeoutPerBlock time.Duration,
+) (<-chan *types.Block, error) {
+	if m.network.SupportsCapability(peerID, common.CapabilityRequestID) {
+		return m.requestBlocks(peerID, from, timeoutPerBlock), nil
+	}
+
+	return m.streamBlocks(peerID, from, timeoutPerBlock)
+}
+
+// streamBlocks returns the blocks streamed by the peer from the given height to its latest
+func (m *syncPeerClient) streamBlocks(
+	peerID peer.ID,
+	from uint64,
+	timeoutPerBlock time.Duration,
 ) (<-chan *types.Block, error) {
 	clt, err := m.newSyncPeerClient(peerID)
 	if err != nil {
@@ -294,6 +333,274 @@ func (m *syncPeerClient) GetBlocks(
 	return blockCh, nil
 }
 
+// requestBlocks returns the blocks requested from the peer by ranges, from the given height to its latest.
+// The stream ends once a request fails or a block isn't consumed in time
+func (m *syncPeerClient) requestBlocks(peerID peer.ID, from uint64, timeoutPerBlock time.Duration) <-chan *types.Block {
+	blockCh := make(chan *types.Block, 1)
+
+	go func() {
+		defer close(blockCh)
+
+		var parent *types.Header
+
+		for {
+			blocks, err := m.fetchBlocks(peerID, from, parent)
+			if err != nil {
+				m.logger.Error("failed to get blocks from peer", "peer", peerID, "from", from, "err", err)
+
+				return
+			}
+
+			// the peer has no block past its latest
+			if len(blocks) == 0 {
+				return
+			}
+
+			for _, block := range blocks {
+				select {
+				case blockCh <- block:
+				case <-time.After(timeoutPerBlock):
+					// the blocks aren't consumed anymore
+					return
+				}
+			}
+
+			parent = blocks[len(blocks)-1].Header
+			from = parent.Number + 1
+		}
+	}()
+
+	return blockCh
+}
+
+// fetchBlocks fetches the headers of the range from the peer, then their bodies,
+// verifying the blocks are linked to each other and to the parent if it's known
+func (m *syncPeerClient) fetchBlocks(
+	peerID peer.ID,
+	from uint64,
+	parent *types.Header,
+) ([]*types.Block, error) {
+	headers, err := m.GetHeaders(peerID, from, blocksPerRequest, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]types.Hash, len(headers))
+
+	for i, header := range headers {
+		if header.Number != from+uint64(i) || (parent != nil && header.ParentHash != parent.Hash) {
+			m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+
+			return nil, fmt.Errorf("%w: header %d (%s)", ErrBlocksNotLinked, header.Number, header.Hash)
+		}
+
+		hashes[i], parent = header.Hash, header
+	}
+
+	blocks := make([]*types.Block, 0, len(headers))
+
+	// the peer may return the bodies of only the first blocks
+	for len(blocks) < len(headers) {
+		bodies, err := m.GetBodies(peerID, hashes[len(blocks):])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bodies) == 0 {
+			return nil, fmt.Errorf("%w: no body of block %d", ErrBlockNotFound, headers[len(blocks)].Number)
+		}
+
+		for _, body := range bodies {
+			block := &types.Block{
+				Header:       headers[len(blocks)],
+				Transactions: body.Transactions,
+				Uncles:       body.Uncles,
+			}
+
+			if err := blockchain.VerifyBodyRoots(block); err != nil {
+				m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+
+				return nil, fmt.Errorf("invalid body of block %d: %w", block.Number(), err)
+			}
+
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks, nil
+}
+
+// GetHeaders fetches the headers of the range from the peer,
+// the peer may return less headers than requested
+func (m *syncPeerClient) GetHeaders(
+	peerID peer.ID,
+	from, amount, skip uint64,
+	reverse bool,
+) ([]*types.Header, error) {
+	clt, err := m.newSyncPeerClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync peer client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout(int(amount)))
+	defer cancel()
+
+	requestID := m.nextRequestID()
+
+	resp, err := clt.GetHeaders(ctx, &proto.GetHeadersRequest{
+		From:      from,
+		Amount:    amount,
+		Skip:      skip,
+		Reverse:   reverse,
+		RequestId: requestID,
+	}, rawGrpc.MaxCallRecvMsgSize(maxResponseSize))
+	if err != nil {
+		return nil, m.requestFailed(peerID, err)
+	}
+
+	if err := m.checkResponse(peerID, requestID, resp.RequestId, len(resp.Headers), int(amount)); err != nil {
+		return nil, err
+	}
+
+	headers := make([]*types.Header, len(resp.Headers))
+
+	for idx, raw := range resp.Headers {
+		header := &types.Header{}
+		if err := header.UnmarshalRLP(raw); err != nil {
+			m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+
+			return nil, err
+		}
+
+		headers[idx] = header
+	}
+
+	return headers, nil
+}
+
+// GetBodies fetches the bodies of the blocks with the given hashes from the peer,
+// the peer may return the bodies of only the first blocks
+func (m *syncPeerClient) GetBodies(peerID peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+	clt, err := m.newSyncPeerClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync peer client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout(len(hashes)))
+	defer cancel()
+
+	requestID := m.nextRequestID()
+
+	resp, err := clt.GetBodies(ctx, &proto.GetBodiesRequest{
+		Hashes:    hashesToBytes(hashes),
+		RequestId: requestID,
+	}, rawGrpc.MaxCallRecvMsgSize(maxResponseSize))
+	if err != nil {
+		return nil, m.requestFailed(peerID, err)
+	}
+
+	if err := m.checkResponse(peerID, requestID, resp.RequestId, len(resp.Bodies), len(hashes)); err != nil {
+		return nil, err
+	}
+
+	bodies := make([]*types.Body, len(resp.Bodies))
+
+	for idx, raw := range resp.Bodies {
+		body := &types.Body{}
+		if err := body.UnmarshalRLP(raw); err != nil {
+			m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+
+			return nil, err
+		}
+
+		bodies[idx] = body
+	}
+
+	return bodies, nil
+}
+
+// GetReceipts fetches the receipts of the blocks with the given hashes from the peer,
+// the peer may return the receipts of only the first blocks
+func (m *syncPeerClient) GetReceipts(peerID peer.ID, hashes []types.Hash) ([][]*types.Receipt, error) {
+	clt, err := m.newSyncPeerClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync peer client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout(len(hashes)))
+	defer cancel()
+
+	requestID := m.nextRequestID()
+
+	resp, err := clt.GetReceipts(ctx, &proto.GetReceiptsRequest{
+		Hashes:    hashesToBytes(hashes),
+		RequestId: requestID,
+	}, rawGrpc.MaxCallRecvMsgSize(maxResponseSize))
+	if err != nil {
+		return nil, m.requestFailed(peerID, err)
+	}
+
+	if err := m.checkResponse(peerID, requestID, resp.RequestId, len(resp.Receipts), len(hashes)); err != nil {
+		return nil, err
+	}
+
+	receipts := make([][]*types.Receipt, len(resp.Receipts))
+
+	for idx, raw := range resp.Receipts {
+		var blockReceipts types.Receipts
+		if err := blockReceipts.UnmarshalRLP(raw); err != nil {
+			m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+
+			return nil, err
+		}
+
+		receipts[idx] = blockReceipts
+	}
+
+	return receipts, nil
+}
+
+// nextRequestID returns the id of a new request
+func (m *syncPeerClient) nextRequestID() uint64 {
+	return atomic.AddUint64(&m.lastRequestID, 1)
+}
+
+// requestTimeout returns the deadline of a request of the given number of items
+func requestTimeout(items int) time.Duration {
+	return defaultTimeoutForRequest + time.Duration(items)*timeoutPerItem
+}
+
+// requestFailed penalizes the peer for the requests it didn't answer in time or answered beyond the size limit
+func (m *syncPeerClient) requestFailed(peerID peer.ID, err error) error {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		m.network.ReportPeer(peerID, network.PenaltyTimeout)
+	case codes.ResourceExhausted:
+		m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+	}
+
+	return err
+}
+
+// checkResponse verifies the response answers the request and doesn't return more items than requested.
+// The peers which don't echo the request ids answer the requests without one
+func (m *syncPeerClient) checkResponse(peerID peer.ID, requestID, responseID uint64, items, requested int) error {
+	if responseID != requestID &&
+		(responseID != 0 || m.network.SupportsCapability(peerID, common.CapabilityRequestID)) {
+		m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+
+		return fmt.Errorf("%w: response %d to request %d", ErrRequestIDMismatch, responseID, requestID)
+	}
+
+	if items > requested {
+		m.network.ReportPeer(peerID, network.PenaltyInvalidResponse)
+
+		return ErrTooManyItems
+	}
+
+	return nil
+}
+
 // newSyncPeerClient creates gRPC client
 func (m *syncPeerClient) newSyncPeerClient(peerID peer.ID) (proto.SyncPeerClient, error) {
 	conn, err := m.network.NewProtoConnection(syncerProto, peerID)
@@ -306,6 +613,17 @@ func (m *syncPeerClient) newSyncPeerClient(peerID peer.ID) (proto.SyncPeerClient
 	return proto.NewSyncPeerClient(conn), nil
 }
 
+// hashesToBytes converts the hashes to the bytes of the gRPC requests
+func hashesToBytes(hashes []types.Hash) [][]byte {
+	res := make([][]byte, len(hashes))
+
+	for idx, hash := range hashes {
+		res[idx] = hash.Bytes()
+	}
+
+	return res
+}
+
 // fromProto gets block from gRPC response data
 func fromProto(protoBlock *proto.Block) (*types.Block, error) {
 	block := &types.Block{}
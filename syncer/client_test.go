@@ -472,29 +472,48 @@ func Test_shouldEmitBlocks(t *testing.T) {
 	})
 }
 
+// createLinkedHeaders creates the headers of the blocks from 1 to num, linked to each other and without body
+func createLinkedHeaders(num int) []*types.Header {
+	headers := make([]*types.Header, num)
+	parent := types.ZeroHash
+
+	for i := range headers {
+		headers[i] = &types.Header{
+			Number:     uint64(i + 1),
+			ParentHash: parent,
+			TxRoot:     types.EmptyRootHash,
+			Sha3Uncles: types.EmptyUncleHash,
+		}
+		headers[i].ComputeHash()
+
+		parent = headers[i].Hash
+	}
+
+	return headers
+}
+
 func Test_syncPeerClient_GetBlocks(t *testing.T) {
 	t.Parallel()
 
 	clientSrv := newTestNetwork(t)
 	client := newTestSyncPeerClient(clientSrv, nil)
 
-	var (
-		peerLatest = uint64(10)
-		syncFrom   = uint64(1)
-	)
+	// the blocks span several requests
+	headers := createLinkedHeaders(2*blocksPerRequest + 10)
 
 	_, peerSrv := createTestSyncerService(t, &mockBlockchain{
-		headerHandler: newSimpleHeaderHandler(peerLatest),
-		getBlockByNumberHandler: func(u uint64, b bool) (*types.Block, bool) {
-			if u <= 10 {
-				return &types.Block{
-					Header: &types.Header{
-						Number: u,
-					},
-				}, true
+		headerHandler: func() *types.Header {
+			return headers[len(headers)-1]
+		},
+		getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+			if u == 0 || u > uint64(len(headers)) {
+				return nil, false
 			}
 
-			return nil, false
+			return headers[u-1], true
+		},
+		getBodyByHashHandler: func(types.Hash) (*types.Body, bool) {
+			return &types.Body{}, true
 		},
 	})
 
@@ -507,19 +526,115 @@ func Test_syncPeerClient_GetBlocks(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	blockStream, err := client.GetBlocks(peerSrv.AddrInfo().ID, syncFrom, 5*time.Second)
+	blockStream, err := client.GetBlocks(peerSrv.AddrInfo().ID, 1, 5*time.Second)
 	assert.NoError(t, err)
 
-	blocks := make([]*types.Block, 0, peerLatest)
+	hashes := make([]types.Hash, 0, len(headers))
 	for block := range blockStream {
-		blocks = append(blocks, block)
+		hashes = append(hashes, block.Hash())
+	}
+
+	expected := make([]types.Hash, len(headers))
+	for i, header := range headers {
+		expected[i] = header.Hash
 	}
 
-	// hash is calculated on unmarshaling
-	expected := createMockBlocks(10)
-	for _, b := range expected {
-		b.Header.ComputeHash()
+	assert.Equal(t, expected, hashes)
+}
+
+// invalidSyncPeerService answers the requests of headers with another request id or more headers than requested
+type invalidSyncPeerService struct {
+	*syncPeerService
+
+	tooMany bool
+}
+
+func (s *invalidSyncPeerService) GetHeaders(
+	ctx context.Context,
+	req *proto.GetHeadersRequest,
+) (*proto.Headers, error) {
+	if s.tooMany {
+		req.Amount++
+	} else {
+		req.RequestId++
+	}
+
+	return s.syncPeerService.GetHeaders(ctx, req)
+}
+
+func Test_syncPeerClient_InvalidResponse(t *testing.T) {
+	t.Parallel()
+
+	for _, tooMany := range []bool{false, true} {
+		clientSrv := newTestNetwork(t)
+		client := newTestSyncPeerClient(clientSrv, nil)
+		peerSrv := newTestNetwork(t)
+
+		stream := grpc.NewGrpcStream()
+		proto.RegisterSyncPeerServer(stream.GrpcServer(), &invalidSyncPeerService{
+			syncPeerService: &syncPeerService{
+				blockchain: &mockBlockchain{
+					getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+						return &types.Header{Number: u}, u <= 10
+					},
+				},
+			},
+			tooMany: tooMany,
+		})
+		stream.Serve()
+		peerSrv.RegisterProtocol(syncerProto, stream)
+
+		err := network.JoinAndWait(
+			clientSrv,
+			peerSrv,
+			network.DefaultBufferTimeout,
+			network.DefaultJoinTimeout,
+		)
+
+		assert.NoError(t, err)
+
+		_, err = client.GetHeaders(peerSrv.AddrInfo().ID, 1, 4, 0, false)
+
+		if tooMany {
+			assert.ErrorIs(t, err, ErrTooManyItems)
+		} else {
+			assert.ErrorIs(t, err, ErrRequestIDMismatch)
+		}
+	}
+}
+
+func Test_syncPeerClient_GetHeaders(t *testing.T) {
+	t.Parallel()
+
+	clientSrv := newTestNetwork(t)
+	client := newTestSyncPeerClient(clientSrv, nil)
+
+	_, peerSrv := createTestSyncerService(t, &mockBlockchain{
+		getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+			if u <= 10 {
+				return &types.Header{Number: u}, true
+			}
+
+			return nil, false
+		},
+	})
+
+	err := network.JoinAndWait(
+		clientSrv,
+		peerSrv,
+		network.DefaultBufferTimeout,
+		network.DefaultJoinTimeout,
+	)
+
+	assert.NoError(t, err)
+
+	headers, err := client.GetHeaders(peerSrv.AddrInfo().ID, 10, 4, 1, true)
+	assert.NoError(t, err)
+
+	numbers := make([]uint64, len(headers))
+	for idx, header := range headers {
+		numbers[idx] = header.Number
 	}
 
-	assert.Equal(t, expected, blocks)
+	assert.Equal(t, []uint64{10, 8, 6, 4}, numbers)
 }
@@ -0,0 +1,143 @@
+package syncer
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// statsWeight is the weight of a new sample in the moving averages of the peer stats
+	statsWeight = 0.3
+
+	// statsWindow is the number of blocks over which the throughput of a peer is sampled
+	statsWindow = 32
+
+	// degradedRatio is the ratio of the throughput of the fastest peer under which
+	// the peer being synced with is rotated away from
+	degradedRatio = 0.5
+)
+
+var errPeerDegraded = errors.New("peer throughput degraded")
+
+// peerStats is the measured delivery of the blocks of a peer
+type peerStats struct {
+	// Throughput is the moving average of the blocks per second received from the peer, 0 until measured
+	Throughput float64
+	// Latency is the moving average of the time to receive the first block of a range
+	Latency time.Duration
+}
+
+// PeerStats keeps the delivery stats of the peers, so the syncer prefers the fastest ones
+type PeerStats struct {
+	lock  sync.RWMutex
+	stats map[peer.ID]*peerStats
+}
+
+func NewPeerStats() *PeerStats {
+	return &PeerStats{
+		stats: make(map[peer.ID]*peerStats),
+	}
+}
+
+func (s *PeerStats) peer(id peer.ID) *peerStats {
+	stats, ok := s.stats[id]
+	if !ok {
+		stats = &peerStats{}
+		s.stats[id] = stats
+	}
+
+	return stats
+}
+
+// RecordLatency records the time the peer took to send the first block of a range
+func (s *PeerStats) RecordLatency(id peer.ID, latency time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.peer(id)
+
+	if stats.Latency == 0 {
+		stats.Latency = latency
+	} else {
+		stats.Latency = time.Duration(statsWeight*float64(latency) + (1-statsWeight)*float64(stats.Latency))
+	}
+}
+
+// RecordThroughput records the number of blocks received from the peer in the elapsed time,
+// and returns its updated throughput
+func (s *PeerStats) RecordThroughput(id peer.ID, blocks int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		elapsed = time.Nanosecond
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.peer(id)
+	throughput := float64(blocks) / elapsed.Seconds()
+
+	if stats.Throughput == 0 {
+		stats.Throughput = throughput
+	} else {
+		stats.Throughput = statsWeight*throughput + (1-statsWeight)*stats.Throughput
+	}
+
+	return stats.Throughput
+}
+
+// Get returns the stats of the peer, false if it hasn't been measured
+func (s *PeerStats) Get(id peer.ID) (peerStats, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	stats, ok := s.stats[id]
+	if !ok {
+		return peerStats{}, false
+	}
+
+	return *stats, true
+}
+
+// Remove removes the stats of a disconnected peer
+func (s *PeerStats) Remove(id peer.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.stats, id)
+}
+
+// FastestThroughput returns the highest throughput of the peers other than the given one, 0 if none is measured
+func (s *PeerStats) FastestThroughput(exclude peer.ID) float64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var fastest float64
+
+	for id, stats := range s.stats {
+		if id != exclude && stats.Throughput > fastest {
+			fastest = stats.Throughput
+		}
+	}
+
+	return fastest
+}
+
+// IsFaster checks if the peer p is expected to deliver a range of blocks faster than the peer t.
+// The peers which haven't been measured yet come first, so each of them is tried once
+func (s *PeerStats) IsFaster(p, t peer.ID) bool {
+	ps, _ := s.Get(p)
+	ts, _ := s.Get(t)
+
+	if pMeasured, tMeasured := ps.Throughput != 0, ts.Throughput != 0; pMeasured != tMeasured || !pMeasured {
+		return !pMeasured && tMeasured
+	}
+
+	if ps.Throughput != ts.Throughput {
+		return ps.Throughput > ts.Throughput
+	}
+
+	return ps.Latency < ts.Latency
+}
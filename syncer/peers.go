@@ -47,6 +47,20 @@ func (m *PeerMap) Remove(peerID peer.ID) {
 	m.Delete(peerID.String())
 }
 
+// IDs returns the IDs of all the peers
+func (m *PeerMap) IDs() []peer.ID {
+	ids := make([]peer.ID, 0)
+
+	m.Range(func(key, value interface{}) bool {
+		peer, _ := value.(*NoForkPeer)
+		ids = append(ids, peer.ID)
+
+		return true
+	})
+
+	return ids
+}
+
 // BestPeer returns the top of heap
 func (m *PeerMap) BestPeer(skipMap map[peer.ID]bool) *NoForkPeer {
 	var bestPeer *NoForkPeer
@@ -67,3 +81,26 @@ func (m *PeerMap) BestPeer(skipMap map[peer.ID]bool) *NoForkPeer {
 
 	return bestPeer
 }
+
+// FastestPeer returns the fastest peer according to the stats among the ones ahead of the given number,
+// the best one between the peers as fast
+func (m *PeerMap) FastestPeer(skipMap map[peer.ID]bool, number uint64, stats *PeerStats) *NoForkPeer {
+	var fastestPeer *NoForkPeer
+
+	m.Range(func(key, value interface{}) bool {
+		peer, _ := value.(*NoForkPeer)
+
+		if skipMap[peer.ID] || peer.Number <= number {
+			return true
+		}
+
+		if fastestPeer == nil || stats.IsFaster(peer.ID, fastestPeer.ID) ||
+			(!stats.IsFaster(fastestPeer.ID, peer.ID) && peer.IsBetter(fastestPeer)) {
+			fastestPeer = peer
+		}
+
+		return true
+	})
+
+	return fastestPeer
+}
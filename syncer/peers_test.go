@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/assert"
@@ -160,3 +161,44 @@ func TestBestPeer(t *testing.T) {
 		})
 	}
 }
+
+func TestPeerIDs(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(peers)
+
+	assert.ElementsMatch(
+		t,
+		[]peer.ID{peer.ID("A"), peer.ID("B"), peer.ID("C")},
+		peerMap.IDs(),
+	)
+}
+
+func TestFastestPeer(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(peers)
+	stats := NewPeerStats()
+
+	// the best peer is returned between the unmeasured peers
+	assert.Equal(t, peers[2], peerMap.FastestPeer(nil, 0, stats))
+
+	// the unmeasured peers are tried first
+	stats.RecordThroughput(peer.ID("C"), 100, time.Second)
+	assert.Equal(t, peers[1], peerMap.FastestPeer(nil, 0, stats))
+
+	stats.RecordThroughput(peer.ID("B"), 50, time.Second)
+	stats.RecordThroughput(peer.ID("A"), 200, time.Second)
+	assert.Equal(t, peers[0], peerMap.FastestPeer(nil, 0, stats))
+
+	// the peers which aren't ahead of the number are left out
+	assert.Equal(t, peers[2], peerMap.FastestPeer(nil, 10, stats))
+	assert.Equal(t, peers[1], peerMap.FastestPeer(map[peer.ID]bool{peer.ID("C"): true}, 10, stats))
+	assert.Nil(t, peerMap.FastestPeer(nil, 20, stats))
+
+	// the latency breaks the ties
+	stats.RecordThroughput(peer.ID("B"), 350, time.Second)
+	stats.RecordLatency(peer.ID("B"), time.Millisecond)
+	stats.RecordLatency(peer.ID("C"), time.Second)
+	assert.Equal(t, peers[1], peerMap.FastestPeer(nil, 10, stats))
+}
@@ -10,6 +10,21 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 )
 
+const (
+	// maxHeadersServe is the maximum number of headers returned by GetHeaders
+	maxHeadersServe = 1024
+	// maxBodiesServe is the maximum number of bodies returned by GetBodies
+	maxBodiesServe = 256
+	// maxReceiptsServe is the maximum number of block receipts returned by GetReceipts
+	maxReceiptsServe = 256
+	// softResponseLimit is the response size after which no more items are added,
+	// so a response exceeds it by at most one item
+	softResponseLimit = 2 * 1024 * 1024
+	// maxResponseSize is the size of the responses after which the peer is deemed malicious,
+	// leaving room for the one item exceeding the soft limit
+	maxResponseSize = 4 * softResponseLimit
+)
+
 var (
 	ErrBlockNotFound = errors.New("block not found")
 )
@@ -89,6 +104,118 @@ func (s *syncPeerService) GetStatus(
 	}, nil
 }
 
+// GetHeaders is a gRPC endpoint to return the headers of a range,
+// the range ends at the first header the node doesn't have or at the response limits
+func (s *syncPeerService) GetHeaders(
+	ctx context.Context,
+	req *proto.GetHeadersRequest,
+) (*proto.Headers, error) {
+	var (
+		headers = make([][]byte, 0)
+		size    = 0
+		step    = req.Skip + 1
+		amount  = req.Amount
+	)
+
+	if amount > maxHeadersServe {
+		amount = maxHeadersServe
+	}
+
+	for number := req.From; uint64(len(headers)) < amount && size < softResponseLimit; {
+		header, ok := s.blockchain.GetHeaderByNumber(number)
+		if !ok {
+			break
+		}
+
+		raw := header.MarshalRLP()
+		headers = append(headers, raw)
+		size += len(raw)
+
+		if req.Reverse {
+			if number < step {
+				break
+			}
+
+			number -= step
+		} else {
+			if number+step < number {
+				break
+			}
+
+			number += step
+		}
+	}
+
+	return &proto.Headers{
+		Headers:   headers,
+		RequestId: req.RequestId,
+	}, nil
+}
+
+// GetBodies is a gRPC endpoint to return the bodies of the blocks in the order of the hashes,
+// the bodies end at the first block the node doesn't have or at the response limits
+func (s *syncPeerService) GetBodies(
+	ctx context.Context,
+	req *proto.GetBodiesRequest,
+) (*proto.Bodies, error) {
+	var (
+		bodies = make([][]byte, 0)
+		size   = 0
+	)
+
+	for _, raw := range req.Hashes {
+		if len(bodies) >= maxBodiesServe || size >= softResponseLimit {
+			break
+		}
+
+		body, ok := s.blockchain.GetBodyByHash(types.BytesToHash(raw))
+		if !ok {
+			break
+		}
+
+		data := body.MarshalRLPTo(nil)
+		bodies = append(bodies, data)
+		size += len(data)
+	}
+
+	return &proto.Bodies{
+		Bodies:    bodies,
+		RequestId: req.RequestId,
+	}, nil
+}
+
+// GetReceipts is a gRPC endpoint to return the receipts of the blocks in the order of the hashes,
+// the receipts end at the first block the node doesn't have them for or at the response limits
+func (s *syncPeerService) GetReceipts(
+	ctx context.Context,
+	req *proto.GetReceiptsRequest,
+) (*proto.Receipts, error) {
+	var (
+		receipts = make([][]byte, 0)
+		size     = 0
+	)
+
+	for _, raw := range req.Hashes {
+		if len(receipts) >= maxReceiptsServe || size >= softResponseLimit {
+			break
+		}
+
+		blockReceipts, err := s.blockchain.GetReceiptsByHash(types.BytesToHash(raw))
+		if err != nil {
+			break
+		}
+
+		data := types.Receipts(blockReceipts).MarshalRLPTo(nil)
+		receipts = append(receipts, data)
+		size += len(data)
+	}
+
+	return &proto.Receipts{
+		Receipts:  receipts,
+		RequestId: req.RequestId,
+	}, nil
+}
+
 // toProtoBlock converts type.Block -> proto.Block
 func toProtoBlock(block *types.Block) *proto.Block {
 	return &proto.Block{
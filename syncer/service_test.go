@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log"
+	"math/big"
 	"net"
 	"testing"
 
@@ -153,3 +154,204 @@ func TestGetStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, headerNumber, status.Number)
 }
+
+func TestGetHeaders(t *testing.T) {
+	t.Parallel()
+
+	// headers from 0 to 10
+	headers := make(map[uint64]*types.Header)
+	for i := uint64(0); i <= 10; i++ {
+		headers[i] = &types.Header{Number: i}
+	}
+
+	tests := []struct {
+		name     string
+		req      *proto.GetHeadersRequest
+		expected []uint64
+	}{
+		{
+			name:     "range",
+			req:      &proto.GetHeadersRequest{From: 2, Amount: 3},
+			expected: []uint64{2, 3, 4},
+		},
+		{
+			name:     "range with skip",
+			req:      &proto.GetHeadersRequest{From: 1, Amount: 3, Skip: 2},
+			expected: []uint64{1, 4, 7},
+		},
+		{
+			name:     "reverse range with skip",
+			req:      &proto.GetHeadersRequest{From: 8, Amount: 5, Skip: 3, Reverse: true},
+			expected: []uint64{8, 4, 0},
+		},
+		{
+			name:     "should stop at the latest header",
+			req:      &proto.GetHeadersRequest{From: 9, Amount: 5},
+			expected: []uint64{9, 10},
+		},
+		{
+			name:     "unknown header",
+			req:      &proto.GetHeadersRequest{From: 11, Amount: 5},
+			expected: []uint64{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &syncPeerService{
+				blockchain: &mockBlockchain{
+					getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+						header, ok := headers[u]
+
+						return header, ok
+					},
+				},
+			}
+
+			client := newMockGrpcClient(t, service)
+
+			resp, err := client.GetHeaders(context.Background(), test.req)
+			assert.NoError(t, err)
+
+			numbers := make([]uint64, len(resp.Headers))
+
+			for idx, raw := range resp.Headers {
+				header := &types.Header{}
+				assert.NoError(t, header.UnmarshalRLP(raw))
+
+				numbers[idx] = header.Number
+			}
+
+			assert.Equal(t, test.expected, numbers)
+		})
+	}
+}
+
+func TestGetHeaders_Limits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should serve up to maxHeadersServe headers", func(t *testing.T) {
+		t.Parallel()
+
+		service := &syncPeerService{
+			blockchain: &mockBlockchain{
+				getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+					return &types.Header{Number: u}, true
+				},
+			},
+		}
+
+		client := newMockGrpcClient(t, service)
+
+		resp, err := client.GetHeaders(context.Background(), &proto.GetHeadersRequest{
+			From:   1,
+			Amount: maxHeadersServe + 10,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.Headers, maxHeadersServe)
+	})
+
+	t.Run("should stop at the soft response limit", func(t *testing.T) {
+		t.Parallel()
+
+		extra := make([]byte, softResponseLimit/4)
+
+		service := &syncPeerService{
+			blockchain: &mockBlockchain{
+				getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+					return &types.Header{Number: u, ExtraData: extra}, true
+				},
+			},
+		}
+
+		client := newMockGrpcClient(t, service)
+
+		resp, err := client.GetHeaders(context.Background(), &proto.GetHeadersRequest{
+			From:   1,
+			Amount: 10,
+		})
+
+		// the limit is exceeded by the fourth header
+		assert.NoError(t, err)
+		assert.Len(t, resp.Headers, 4)
+	})
+}
+
+func TestGetBodies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hash1 = types.StringToHash("1")
+		hash2 = types.StringToHash("2")
+		hash3 = types.StringToHash("3")
+
+		bodies = map[types.Hash]*types.Body{
+			hash1: {Transactions: []*types.Transaction{{Nonce: 1, Value: big.NewInt(1)}}},
+			hash3: {Transactions: []*types.Transaction{{Nonce: 3, Value: big.NewInt(3)}}},
+		}
+	)
+
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			getBodyByHashHandler: func(hash types.Hash) (*types.Body, bool) {
+				body, ok := bodies[hash]
+
+				return body, ok
+			},
+		},
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	// the bodies end at the unknown block
+	resp, err := client.GetBodies(context.Background(), &proto.GetBodiesRequest{
+		Hashes: [][]byte{hash1.Bytes(), hash2.Bytes(), hash3.Bytes()},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{bodies[hash1].MarshalRLPTo(nil)}, resp.Bodies)
+}
+
+func TestGetReceipts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hash1 = types.StringToHash("1")
+		hash2 = types.StringToHash("2")
+
+		success  = types.ReceiptSuccess
+		receipts = []*types.Receipt{
+			{
+				Status:            &success,
+				CumulativeGasUsed: 21000,
+				Logs:              []*types.Log{},
+			},
+		}
+	)
+
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			getReceiptsByHashHandler: func(hash types.Hash) ([]*types.Receipt, error) {
+				if hash != hash1 {
+					return nil, ErrBlockNotFound
+				}
+
+				return receipts, nil
+			},
+		},
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	resp, err := client.GetReceipts(context.Background(), &proto.GetReceiptsRequest{
+		Hashes: [][]byte{hash1.Bytes(), hash2.Bytes()},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{types.Receipts(receipts).MarshalRLPTo(nil)}, resp.Receipts)
+}
@@ -0,0 +1,388 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// skeletonSpan is the number of blocks between the headers of the skeleton,
+	// the blocks of a span are downloaded from a single peer
+	skeletonSpan = 128
+
+	// skeletonSize is the number of headers of the skeleton fetched for a round of the full sync
+	skeletonSize = 16
+
+	// defaultSegmentStallTimeout is the time a peer has to deliver a segment before it's rotated away from
+	defaultSegmentStallTimeout = 30 * time.Second
+)
+
+var (
+	errEmptySkeleton     = errors.New("peer returned no skeleton headers")
+	errInvalidSkeleton   = errors.New("peer returned skeleton headers at unexpected numbers")
+	errSegmentNotLinked  = errors.New("headers are not linked to the skeleton")
+	errSegmentIncomplete = errors.New("peer doesn't have all the blocks of the segment")
+	errSegmentStalled    = errors.New("peer stalled delivering the segment")
+	errNoSegmentPeers    = errors.New("no peer left to download the segments from")
+)
+
+// segment is a range of blocks between two headers of the skeleton
+type segment struct {
+	index  int
+	from   uint64
+	amount uint64
+	// parent is the hash of the block before the segment
+	parent types.Hash
+	// last is the header of the skeleton ending the segment, nil for the tail of the chain
+	last *types.Header
+}
+
+func (seg *segment) to() uint64 {
+	return seg.from + seg.amount - 1
+}
+
+// segmentResult is the blocks of a segment downloaded from a peer
+type segmentResult struct {
+	segment *segment
+	peerID  peer.ID
+	blocks  []*types.Block
+	elapsed time.Duration
+	err     error
+}
+
+// skeletonSync syncs the blocks up to the follow distance of the given peer. Each round fetches a skeleton
+// of the headers every span blocks from the peer, downloads the spans between them from all the peers
+// concurrently, and writes the blocks in order as the spans before them are delivered.
+// The receipts aren't downloaded, since the blocks are executed
+func (s *syncer) skeletonSync(master *NoForkPeer, callback func(*types.Block) bool) (uint64, bool, error) {
+	// the peers which failed to deliver a segment aren't assigned any other
+	excluded := make(map[peer.ID]bool)
+
+	for {
+		head := s.blockchain.Header()
+		if master.Number <= head.Number+followDistance {
+			return head.Number, false, nil
+		}
+
+		segments, err := s.fetchSkeleton(master, head)
+		if err != nil {
+			return head.Number, false, fmt.Errorf("failed to fetch the skeleton: %w", err)
+		}
+
+		lastNumber, shouldTerminate, err := s.downloadSegments(segments, excluded, callback)
+		if err != nil || shouldTerminate {
+			return lastNumber, shouldTerminate, err
+		}
+	}
+}
+
+// fetchSkeleton fetches the headers every span blocks after the head from the peer, and splits the blocks
+// up to them into segments. The tail of the chain shorter than a span is a single segment
+func (s *syncer) fetchSkeleton(master *NoForkPeer, head *types.Header) ([]*segment, error) {
+	if master.Number-head.Number < skeletonSpan {
+		return []*segment{{from: head.Number + 1, amount: master.Number - head.Number, parent: head.Hash}}, nil
+	}
+
+	headers, err := s.syncPeerClient.GetHeaders(master.ID, head.Number+skeletonSpan, skeletonSize, skeletonSpan-1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(headers) == 0 {
+		return nil, errEmptySkeleton
+	}
+
+	segments := make([]*segment, len(headers))
+	parent := head.Hash
+
+	for i, header := range headers {
+		if expected := head.Number + uint64(i+1)*skeletonSpan; header.Number != expected {
+			s.syncPeerClient.ReportPeer(master.ID, network.PenaltyInvalidBlock)
+
+			return nil, fmt.Errorf("%w: %d, expected %d", errInvalidSkeleton, header.Number, expected)
+		}
+
+		segments[i] = &segment{
+			index:  i,
+			from:   header.Number - skeletonSpan + 1,
+			amount: skeletonSpan,
+			parent: parent,
+			last:   header,
+		}
+
+		parent = header.Hash
+	}
+
+	return segments, nil
+}
+
+// downloadSegments downloads the segments from the peers, each peer downloading one segment at a time
+// with the lowest segments assigned to the fastest peers. A peer failing to deliver its segment or stalling
+// is excluded, a peer degrading under the throughput of the fastest one is rotated away from for the rest
+// of the round. Their segments are assigned to other peers. The blocks are verified and written in order
+func (s *syncer) downloadSegments(
+	segments []*segment,
+	excluded map[peer.ID]bool,
+	callback func(*types.Block) bool,
+) (uint64, bool, error) {
+	var (
+		resultCh = make(chan *segmentResult)
+		doneCh   = make(chan struct{})
+
+		pending     = append([]*segment{}, segments...)
+		assigned    = make(map[peer.ID]*segment)
+		startedAt   = make(map[peer.ID]time.Time)
+		unavailable = make(map[peer.ID]bool, len(excluded))
+		delivered   = make(map[int]*segmentResult)
+
+		next       = 0
+		lastNumber = s.blockchain.Header().Number
+	)
+
+	defer close(doneCh)
+
+	for id := range excluded {
+		unavailable[id] = true
+	}
+
+	for next < len(segments) {
+		for len(pending) > 0 {
+			seg := pending[0]
+
+			p := s.peerMap.FastestPeer(unavailable, seg.to()-1, s.peerStats)
+			if p == nil {
+				break
+			}
+
+			pending = pending[1:]
+			assigned[p.ID], startedAt[p.ID], unavailable[p.ID] = seg, time.Now(), true
+
+			go func(peerID peer.ID) {
+				res := s.fetchSegment(peerID, seg)
+
+				select {
+				case resultCh <- res:
+				case <-doneCh:
+				}
+			}(p.ID)
+		}
+
+		if len(assigned) == 0 {
+			return lastNumber, false, errNoSegmentPeers
+		}
+
+		var res *segmentResult
+
+		// the segments delivered while the blocks were written are handled before the stalled ones
+		select {
+		case res = <-resultCh:
+		default:
+			select {
+			case res = <-resultCh:
+			case <-time.After(s.nextSegmentStall(startedAt)):
+				pending = s.rotateStalledPeers(assigned, startedAt, excluded, pending)
+
+				continue
+			case <-s.closeCh:
+				return lastNumber, false, errSyncerClosed
+			}
+		}
+
+		// the late result of a stalled peer is dropped, its segment is assigned to another one
+		if assigned[res.peerID] != res.segment {
+			continue
+		}
+
+		delete(assigned, res.peerID)
+		delete(startedAt, res.peerID)
+
+		if res.err != nil {
+			s.logger.Warn("failed to download segment from peer, try to next one",
+				"peer ID", res.peerID, "from", res.segment.from, "to", res.segment.to(), "err", res.err)
+
+			if errors.Is(res.err, errSegmentNotLinked) || errors.Is(res.err, blockchain.ErrInvalidTxRoot) ||
+				errors.Is(res.err, blockchain.ErrInvalidSha3Uncles) {
+				s.syncPeerClient.ReportPeer(res.peerID, network.PenaltyInvalidBlock)
+			}
+
+			excluded[res.peerID] = true
+			pending = insertSegment(pending, res.segment)
+
+			continue
+		}
+
+		if err := s.checkThroughput(res.peerID, len(res.blocks), res.elapsed); err != nil {
+			s.logger.Debug("rotating away from peer", "peer ID", res.peerID, "err", err)
+		} else {
+			delete(unavailable, res.peerID)
+		}
+
+		delivered[res.segment.index] = res
+
+		// the segments are written once all the segments before them are
+		for ; delivered[next] != nil; next++ {
+			res := delivered[next]
+			delete(delivered, next)
+
+			for _, block := range res.blocks {
+				if err := s.blockchain.VerifyFinalizedBlock(block); err != nil {
+					s.syncPeerClient.ReportPeer(res.peerID, network.PenaltyInvalidBlock)
+
+					return lastNumber, false, fmt.Errorf("unable to verify block, %w", err)
+				}
+
+				if err := s.blockchain.WriteBlock(block, syncerName); err != nil {
+					return lastNumber, false, fmt.Errorf("failed to write block while skeleton syncing: %w", err)
+				}
+
+				lastNumber = block.Number()
+
+				if callback(block) {
+					return lastNumber, true, nil
+				}
+			}
+		}
+	}
+
+	return lastNumber, false, nil
+}
+
+// nextSegmentStall returns the time until the earliest assigned segment stalls
+func (s *syncer) nextSegmentStall(startedAt map[peer.ID]time.Time) time.Duration {
+	earliest := time.Duration(-1)
+
+	for _, t := range startedAt {
+		if left := s.segmentStallTimeout - time.Since(t); earliest < 0 || left < earliest {
+			earliest = left
+		}
+	}
+
+	return earliest
+}
+
+// rotateStalledPeers reports and excludes the peers which haven't delivered their segments in time,
+// and returns their segments to the pending ones
+func (s *syncer) rotateStalledPeers(
+	assigned map[peer.ID]*segment,
+	startedAt map[peer.ID]time.Time,
+	excluded map[peer.ID]bool,
+	pending []*segment,
+) []*segment {
+	for id, seg := range assigned {
+		if time.Since(startedAt[id]) < s.segmentStallTimeout {
+			continue
+		}
+
+		s.logger.Warn("failed to download segment from peer, try to next one",
+			"peer ID", id, "from", seg.from, "to", seg.to(), "err", errSegmentStalled)
+
+		s.syncPeerClient.ReportPeer(id, network.PenaltyTimeout)
+
+		delete(assigned, id)
+		delete(startedAt, id)
+
+		excluded[id] = true
+		pending = insertSegment(pending, seg)
+	}
+
+	return pending
+}
+
+// insertSegment inserts the segment into the pending ones, which are kept in order
+func insertSegment(pending []*segment, seg *segment) []*segment {
+	i := sort.Search(len(pending), func(i int) bool {
+		return pending[i].index > seg.index
+	})
+
+	pending = append(pending, nil)
+	copy(pending[i+1:], pending[i:])
+	pending[i] = seg
+
+	return pending
+}
+
+// fetchSegment downloads the headers of the segment from the peer, verifies they're linked to the skeleton,
+// then downloads their bodies
+func (s *syncer) fetchSegment(peerID peer.ID, seg *segment) *segmentResult {
+	res := &segmentResult{segment: seg, peerID: peerID}
+	requestedAt := time.Now()
+
+	headers, err := s.syncPeerClient.GetHeaders(peerID, seg.from, seg.amount, 0, false)
+	if err != nil {
+		res.err = err
+
+		return res
+	}
+
+	s.peerStats.RecordLatency(peerID, time.Since(requestedAt))
+
+	if uint64(len(headers)) != seg.amount {
+		res.err = fmt.Errorf("%w: %d headers of %d", errSegmentIncomplete, len(headers), seg.amount)
+
+		return res
+	}
+
+	hashes := make([]types.Hash, len(headers))
+	parent := seg.parent
+
+	for i, header := range headers {
+		if header.Number != seg.from+uint64(i) || header.ParentHash != parent {
+			res.err = fmt.Errorf("%w: header %d (%s)", errSegmentNotLinked, header.Number, header.Hash)
+
+			return res
+		}
+
+		hashes[i], parent = header.Hash, header.Hash
+	}
+
+	if seg.last != nil && parent != seg.last.Hash {
+		res.err = fmt.Errorf("%w: header %d (%s)", errSegmentNotLinked, seg.to(), parent)
+
+		return res
+	}
+
+	res.blocks = make([]*types.Block, 0, len(headers))
+
+	// the peer may return the bodies of only the first blocks
+	for len(res.blocks) < len(headers) {
+		bodies, err := s.syncPeerClient.GetBodies(peerID, hashes[len(res.blocks):])
+		if err != nil {
+			res.err = err
+
+			return res
+		}
+
+		if len(bodies) == 0 {
+			res.err = fmt.Errorf("%w: no body of block %d", errSegmentIncomplete, headers[len(res.blocks)].Number)
+
+			return res
+		}
+
+		for _, body := range bodies {
+			block := &types.Block{
+				Header:       headers[len(res.blocks)],
+				Transactions: body.Transactions,
+				Uncles:       body.Uncles,
+			}
+
+			if err := blockchain.VerifyBodyRoots(block); err != nil {
+				res.err = fmt.Errorf("invalid body of block %d: %w", block.Number(), err)
+
+				return res
+			}
+
+			res.blocks = append(res.blocks, block)
+		}
+	}
+
+	res.elapsed = time.Since(requestedAt)
+
+	return res
+}
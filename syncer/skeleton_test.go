@@ -0,0 +1,211 @@
+package syncer
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// skeletonTestPeer is a peer serving the headers of its chain, which may stall or lack the bodies
+type skeletonTestPeer struct {
+	status   *NoForkPeer
+	headers  []*types.Header
+	stalled  bool
+	noBodies bool
+}
+
+func serveTestHeaders(headers []*types.Header, from, amount, skip uint64) []*types.Header {
+	res := make([]*types.Header, 0, amount)
+
+	for n := from; n < uint64(len(headers)) && uint64(len(res)) < amount; n += skip + 1 {
+		res = append(res, headers[n])
+	}
+
+	return res
+}
+
+func TestSkeletonSync(t *testing.T) {
+	t.Parallel()
+
+	var (
+		headers = blockchain.NewTestHeaders(301)
+		forged  = blockchain.NewTestHeadersWithSeed(nil, 301, 1)
+	)
+
+	newPeer := func(id string, distance int64, headers []*types.Header) *skeletonTestPeer {
+		return &skeletonTestPeer{
+			status:  &NoForkPeer{ID: peer.ID(id), Number: 300, Distance: big.NewInt(distance)},
+			headers: headers,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		peers []*skeletonTestPeer
+
+		synced    uint64
+		penalties []network.PeerPenalty
+		err       error
+	}{
+		{
+			name: "should download the segments from the peers and write the blocks in order",
+			peers: []*skeletonTestPeer{
+				newPeer("A", 1, headers),
+				newPeer("B", 0, headers),
+				newPeer("C", 2, headers),
+			},
+			synced:    300,
+			penalties: []network.PeerPenalty{},
+		},
+		{
+			name: "should rotate away from the peer serving the headers of another chain",
+			peers: []*skeletonTestPeer{
+				newPeer("A", 1, headers),
+				newPeer("B", 0, forged),
+				newPeer("C", 2, headers),
+			},
+			synced:    300,
+			penalties: []network.PeerPenalty{network.PenaltyInvalidBlock},
+		},
+		{
+			name: "should rotate away from the stalled peer",
+			peers: []*skeletonTestPeer{
+				newPeer("A", 1, headers),
+				{status: &NoForkPeer{ID: peer.ID("B"), Number: 300, Distance: big.NewInt(0)}, stalled: true},
+				newPeer("C", 2, headers),
+			},
+			synced:    300,
+			penalties: []network.PeerPenalty{network.PenaltyTimeout},
+		},
+		{
+			name: "should return the error if no peer can deliver the segments",
+			peers: []*skeletonTestPeer{
+				{status: &NoForkPeer{ID: peer.ID("A"), Number: 300, Distance: big.NewInt(0)}, headers: headers, noBodies: true},
+			},
+			synced:    0,
+			penalties: []network.PeerPenalty{},
+			err:       errNoSegmentPeers,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				peers     = make(map[peer.ID]*skeletonTestPeer)
+				releaseCh = make(chan struct{})
+
+				usedLock sync.Mutex
+				used     = make(map[peer.ID]bool)
+
+				written = make([]uint64, 0)
+				head    = headers[0]
+			)
+
+			t.Cleanup(func() {
+				close(releaseCh)
+			})
+
+			for _, p := range test.peers {
+				peers[p.status.ID] = p
+			}
+
+			peerClient := &mockSyncPeerClient{
+				getHeadersHandler: func(id peer.ID, from, amount, skip uint64, _ bool) ([]*types.Header, error) {
+					p := peers[id]
+					if p.stalled {
+						<-releaseCh
+
+						return nil, errTimeout
+					}
+
+					if skip == 0 {
+						usedLock.Lock()
+						used[id] = true
+						usedLock.Unlock()
+					}
+
+					return serveTestHeaders(p.headers, from, amount, skip), nil
+				},
+				getBodiesHandler: func(id peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+					if peers[id].noBodies {
+						return []*types.Body{}, nil
+					}
+
+					bodies := make([]*types.Body, len(hashes))
+					for i := range bodies {
+						bodies[i] = &types.Body{}
+					}
+
+					return bodies, nil
+				},
+				reportedPenalties: []network.PeerPenalty{},
+			}
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return head
+					},
+					verifyFinalizedBlockHandler: func(b *types.Block) error {
+						return nil
+					},
+					writeBlockHandler: func(b *types.Block) error {
+						written = append(written, b.Number())
+						head = b.Header
+
+						return nil
+					},
+				},
+				time.Second,
+				peerClient,
+				&mockProgression{},
+			)
+			syncer.segmentStallTimeout = 500 * time.Millisecond
+
+			for _, p := range test.peers {
+				syncer.peerMap.Put(p.status)
+			}
+
+			synced, shouldTerminate, err := syncer.skeletonSync(test.peers[0].status, func(*types.Block) bool {
+				return false
+			})
+
+			assert.ErrorIs(t, err, test.err)
+			assert.False(t, shouldTerminate)
+			assert.Equal(t, test.synced, synced)
+			assert.Equal(t, test.penalties, peerClient.reportedPenalties)
+
+			for i, number := range written {
+				assert.Equal(t, uint64(i+1), number)
+			}
+
+			if test.err == nil {
+				assert.Len(t, written, int(test.synced))
+				assert.Greater(t, len(used), 1)
+			}
+		})
+	}
+}
+
+func Test_insertSegment(t *testing.T) {
+	t.Parallel()
+
+	segments := []*segment{{index: 0}, {index: 1}, {index: 2}, {index: 3}}
+
+	pending := insertSegment([]*segment{segments[1], segments[3]}, segments[2])
+	pending = insertSegment(pending, segments[0])
+
+	assert.Equal(t, segments, pending)
+}
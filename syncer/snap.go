@@ -0,0 +1,158 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// defaultSnapStallTimeout is the default timeout for the snap sync to fetch a chunk of the state
+const defaultSnapStallTimeout = 2 * time.Minute
+
+var (
+	errNoNewCheckpoint      = errors.New("no checkpoint ahead of the local chain")
+	errSnapSyncStalled      = errors.New("no state chunk fetched before the stall timeout")
+	errCheckpointMismatch   = errors.New("canonical header doesn't match the checkpoint")
+	errSyncerClosed         = errors.New("syncer closed")
+	errCheckpointNotReached = errors.New("peer stream ended before the checkpoint")
+)
+
+// snapSync fetches the state at the latest checkpoint served by the peers, and imports the headers up to it.
+// An error returned before the state is fetched leaves the local chain untouched, so the blocks can be fully synced
+func (s *syncer) snapSync() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerIDs := s.peerMap.IDs()
+
+	checkpoint, err := s.snapSyncer.LatestCheckpoint(ctx, peerIDs)
+	if err != nil {
+		return err
+	}
+
+	if checkpoint.Number <= s.blockchain.Header().Number {
+		return errNoNewCheckpoint
+	}
+
+	s.logger.Info("snap syncing", "checkpoint", checkpoint.Number, "hash", checkpoint.Hash)
+
+	stalledCh := make(chan struct{})
+
+	go s.watchSnapStall(ctx, cancel, stalledCh)
+
+	header, err := s.snapSyncer.Fetch(ctx, checkpoint, peerIDs)
+	if err != nil {
+		select {
+		case <-stalledCh:
+			return errSnapSyncStalled
+		default:
+			return err
+		}
+	}
+
+	// the checkpoint was advertised by the peers, it's trusted
+	// once the headers verified by the consensus lead to it
+	return s.importHeaders(header)
+}
+
+// watchSnapStall cancels the fetch of the state if no chunk is fetched within the stall timeout
+func (s *syncer) watchSnapStall(ctx context.Context, cancel context.CancelFunc, stalledCh chan<- struct{}) {
+	ticker := time.NewTicker(s.snapStallTimeout)
+	defer ticker.Stop()
+
+	fetched := s.snapSyncer.FetchedChunks()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := s.snapSyncer.FetchedChunks()
+			if current == fetched {
+				close(stalledCh)
+				cancel()
+
+				return
+			}
+
+			fetched = current
+		}
+	}
+}
+
+// importHeaders imports the headers from the peers up to the checkpoint header, whose state has been fetched.
+// The local chain has no state until the checkpoint is reached, so the peers are retried until then
+func (s *syncer) importHeaders(checkpoint *types.Header) error {
+	skipList := make(map[peer.ID]bool)
+
+	for {
+		if local := s.blockchain.Header(); local.Number >= checkpoint.Number {
+			return nil
+		}
+
+		bestPeer := s.peerMap.BestPeer(skipList)
+		if bestPeer == nil || bestPeer.Number < checkpoint.Number {
+			// wait for a new status and retry all the peers
+			if _, ok := <-s.newStatusCh; !ok {
+				return errSyncerClosed
+			}
+
+			skipList = make(map[peer.ID]bool)
+
+			continue
+		}
+
+		if err := s.importHeadersFromPeer(bestPeer.ID, checkpoint); err != nil {
+			if errors.Is(err, errCheckpointMismatch) {
+				return err
+			}
+
+			s.logger.Warn("failed to import headers from peer, try to next one", "peer ID", bestPeer.ID, "err", err)
+
+			skipList[bestPeer.ID] = true
+		}
+	}
+}
+
+// importHeadersFromPeer imports the headers of the blocks streamed by the peer up to the checkpoint header
+func (s *syncer) importHeadersFromPeer(peerID peer.ID, checkpoint *types.Header) error {
+	blockCh, err := s.syncPeerClient.GetBlocks(peerID, s.blockchain.Header().Number+1, s.blockTimeout)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := s.syncPeerClient.CloseStream(peerID); err != nil {
+			s.logger.Error("Failed to close stream: ", err)
+		}
+	}()
+
+	for {
+		select {
+		case block, ok := <-blockCh:
+			if !ok {
+				return errCheckpointNotReached
+			}
+
+			header := block.Header
+
+			if header.Number == checkpoint.Number && header.Hash != checkpoint.Hash {
+				return fmt.Errorf("%w at %d, expected %s, got %s", errCheckpointMismatch, header.Number, checkpoint.Hash, header.Hash)
+			}
+
+			if err := s.blockchain.ImportHeader(header, syncerName); err != nil {
+				return fmt.Errorf("failed to import header: %w", err)
+			}
+
+			if header.Number == checkpoint.Number {
+				return nil
+			}
+		case <-time.After(s.blockTimeout):
+			return errTimeout
+		}
+	}
+}
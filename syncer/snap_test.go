@@ -0,0 +1,178 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/statesync"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSnapSyncer struct {
+	latestCheckpointHandler func() (statesync.Checkpoint, error)
+	fetchHandler            func(context.Context, statesync.Checkpoint) (*types.Header, error)
+	fetchStateHandler       func(context.Context, *types.Header, []peer.ID) error
+	fetchedChunksHandler    func() uint64
+}
+
+func (m *mockSnapSyncer) LatestCheckpoint(ctx context.Context, peerIDs []peer.ID) (statesync.Checkpoint, error) {
+	return m.latestCheckpointHandler()
+}
+
+func (m *mockSnapSyncer) Fetch(
+	ctx context.Context,
+	checkpoint statesync.Checkpoint,
+	peerIDs []peer.ID,
+) (*types.Header, error) {
+	return m.fetchHandler(ctx, checkpoint)
+}
+
+func (m *mockSnapSyncer) FetchState(ctx context.Context, header *types.Header, peerIDs []peer.ID) error {
+	return m.fetchStateHandler(ctx, header, peerIDs)
+}
+
+func (m *mockSnapSyncer) FetchedChunks() uint64 {
+	if m.fetchedChunksHandler == nil {
+		return 0
+	}
+
+	return m.fetchedChunksHandler()
+}
+
+func createHashedMockBlocks(num int) []*types.Block {
+	blocks := createMockBlocks(num)
+	for _, b := range blocks {
+		b.Header.Hash = types.StringToHash(strconv.FormatUint(b.Number(), 10))
+	}
+
+	return blocks
+}
+
+func TestSnapSync(t *testing.T) {
+	t.Parallel()
+
+	blocks := createHashedMockBlocks(10)
+	checkpointHeader := blocks[4].Header
+
+	tests := []struct {
+		name string
+
+		latestCheckpointHandler func() (statesync.Checkpoint, error)
+		fetchHandler            func(context.Context, statesync.Checkpoint) (*types.Header, error)
+
+		imported []uint64
+		err      error
+	}{
+		{
+			name: "should return the error if no peer serves the state at a pivot block",
+			latestCheckpointHandler: func() (statesync.Checkpoint, error) {
+				return statesync.Checkpoint{}, statesync.ErrNoPivotPeers
+			},
+			imported: []uint64{},
+			err:      statesync.ErrNoPivotPeers,
+		},
+		{
+			name: "should return the error if the checkpoint is not ahead of the local chain",
+			latestCheckpointHandler: func() (statesync.Checkpoint, error) {
+				return statesync.Checkpoint{Number: 0}, nil
+			},
+			imported: []uint64{},
+			err:      errNoNewCheckpoint,
+		},
+		{
+			name: "should cancel the fetch if no chunk is fetched before the stall timeout",
+			latestCheckpointHandler: func() (statesync.Checkpoint, error) {
+				return statesync.Checkpoint{Number: checkpointHeader.Number, Hash: checkpointHeader.Hash}, nil
+			},
+			fetchHandler: func(ctx context.Context, _ statesync.Checkpoint) (*types.Header, error) {
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			},
+			imported: []uint64{},
+			err:      errSnapSyncStalled,
+		},
+		{
+			name: "should import the headers up to the checkpoint",
+			latestCheckpointHandler: func() (statesync.Checkpoint, error) {
+				return statesync.Checkpoint{Number: checkpointHeader.Number, Hash: checkpointHeader.Hash}, nil
+			},
+			fetchHandler: func(context.Context, statesync.Checkpoint) (*types.Header, error) {
+				return checkpointHeader, nil
+			},
+			imported: []uint64{1, 2, 3, 4, 5},
+			err:      nil,
+		},
+		{
+			name: "should stop if the canonical header doesn't match the checkpoint",
+			latestCheckpointHandler: func() (statesync.Checkpoint, error) {
+				return statesync.Checkpoint{Number: checkpointHeader.Number, Hash: types.StringToHash("1")}, nil
+			},
+			fetchHandler: func(context.Context, statesync.Checkpoint) (*types.Header, error) {
+				header := checkpointHeader.Copy()
+				header.Hash = types.StringToHash("forged")
+
+				return header, nil
+			},
+			imported: []uint64{1, 2, 3, 4},
+			err:      errCheckpointMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				imported    = make([]uint64, 0)
+				localLatest = uint64(0)
+
+				syncer = NewTestSyncer(
+					nil,
+					&mockBlockchain{
+						headerHandler: func() *types.Header {
+							return &types.Header{Number: localLatest}
+						},
+						importHeaderHandler: func(h *types.Header) error {
+							imported = append(imported, h.Number)
+							localLatest = h.Number
+
+							return nil
+						},
+					},
+					time.Second,
+					&mockSyncPeerClient{
+						getBlocksHandler: func(_ peer.ID, from uint64, _ time.Duration) (<-chan *types.Block, error) {
+							return blocksToCh(blocks[from-1:], 0), nil
+						},
+					},
+					&mockProgression{},
+				)
+			)
+
+			syncer.snapSyncer = &mockSnapSyncer{
+				latestCheckpointHandler: test.latestCheckpointHandler,
+				fetchHandler:            test.fetchHandler,
+			}
+			syncer.snapStallTimeout = 10 * time.Millisecond
+
+			syncer.peerMap.Put(&NoForkPeer{
+				ID:       peer.ID("A"),
+				Number:   10,
+				Distance: big.NewInt(0),
+			})
+
+			err := syncer.snapSync()
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.imported, imported)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/network/event"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -15,6 +16,9 @@ import (
 const (
 	syncerName  = "syncer"
 	syncerProto = "/syncer/0.2"
+
+	// followDistance is the distance to the best peer under which the node follows the tip
+	followDistance = 16
 )
 
 var (
@@ -29,6 +33,7 @@ type syncer struct {
 	syncProgression Progression
 
 	peerMap         *PeerMap
+	peerStats       *PeerStats
 	syncPeerService SyncPeerService
 	syncPeerClient  SyncPeerClient
 
@@ -37,6 +42,26 @@ type syncer struct {
 
 	// Channel to notify Sync that a new status arrived
 	newStatusCh chan struct{}
+
+	// snapSyncer fetches the state at a checkpoint, nil if the snap sync is disabled
+	snapSyncer SnapSyncer
+
+	// Timeout for the snap sync to fetch a chunk of the state
+	snapStallTimeout time.Duration
+
+	// Timeout for a peer to deliver a segment of the skeleton before it's rotated away from
+	segmentStallTimeout time.Duration
+
+	// checkpoint is the trusted checkpoint the chain starts from, nil to start from the genesis
+	checkpoint *CheckpointConfig
+
+	// onCheckpoint sets up the consensus to verify the blocks following the checkpoint
+	onCheckpoint func(*types.Header) error
+
+	// mode is the current state of the sync, empty until the sync starts
+	mode progress.SyncMode
+
+	closeCh chan struct{}
 }
 
 func NewSyncer(
@@ -44,16 +69,27 @@ func NewSyncer(
 	network Network,
 	blockchain Blockchain,
 	blockTimeout time.Duration,
+	snapSyncer SnapSyncer,
+	checkpoint *CheckpointConfig,
+	onCheckpoint func(*types.Header) error,
 ) Syncer {
 	return &syncer{
-		logger:          logger.Named(syncerName),
-		blockchain:      blockchain,
-		syncProgression: progress.NewProgressionWrapper(progress.ChainSyncBulk),
-		syncPeerService: NewSyncPeerService(network, blockchain),
-		syncPeerClient:  NewSyncPeerClient(logger, network, blockchain),
-		blockTimeout:    blockTimeout,
-		newStatusCh:     make(chan struct{}),
-		peerMap:         new(PeerMap),
+		logger:           logger.Named(syncerName),
+		blockchain:       blockchain,
+		syncProgression:  progress.NewProgressionWrapper(progress.ChainSyncBulk),
+		syncPeerService:  NewSyncPeerService(network, blockchain),
+		syncPeerClient:   NewSyncPeerClient(logger, network, blockchain),
+		blockTimeout:     blockTimeout,
+		newStatusCh:      make(chan struct{}),
+		peerMap:          new(PeerMap),
+		peerStats:        NewPeerStats(),
+		snapSyncer:       snapSyncer,
+		snapStallTimeout: defaultSnapStallTimeout,
+		checkpoint:       checkpoint,
+		onCheckpoint:     onCheckpoint,
+		closeCh:          make(chan struct{}),
+
+		segmentStallTimeout: defaultSegmentStallTimeout,
 	}
 }
 
@@ -75,6 +111,7 @@ func (s *syncer) Start() error {
 
 // Close terminates goroutine processes
 func (s *syncer) Close() error {
+	close(s.closeCh)
 	close(s.newStatusCh)
 
 	if err := s.syncPeerService.Close(); err != nil {
@@ -134,6 +171,7 @@ func (s *syncer) putToPeerMap(status *NoForkPeer) {
 // removeFromPeerMap removes the peer from peer map
 func (s *syncer) removeFromPeerMap(peerID peer.ID) {
 	s.peerMap.Remove(peerID)
+	s.peerStats.Remove(peerID)
 }
 
 // notifyNewStatusEvent emits signal to newStatusCh
@@ -158,10 +196,24 @@ func (s *syncer) HasSyncPeer() bool {
 	return bestPeer != nil && bestPeer.Number > header.Number
 }
 
-// Sync syncs block with the best peer until callback returns true
+// Sync syncs block with the best peer until callback returns true.
+// A node configured with a checkpoint starts the chain from it, otherwise a fresh node starts in the snap mode
+// if it's enabled, and falls back to the full mode if the state can't be fetched.
+// It switches between the full mode and the follow mode depending on the distance to the best peer
 func (s *syncer) Sync(callback func(*types.Block) bool) error {
 	localLatest := s.blockchain.Header().Number
 	skipList := make(map[peer.ID]bool)
+	snapAttempted := s.snapSyncer == nil || localLatest > 0
+	checkpointSynced := true
+
+	if s.checkpoint != nil {
+		if err := s.initCheckpoint(); err != nil {
+			return err
+		}
+
+		// the checkpoint is cleared if the chain is synced from the genesis
+		checkpointSynced, snapAttempted = s.checkpoint == nil, true
+	}
 
 	for {
 		// Wait for a new event to arrive
@@ -183,13 +235,65 @@ func (s *syncer) Sync(callback func(*types.Block) bool) error {
 
 		// if the bestPeer does not have a new block continue
 		if bestPeer.Number <= localLatest {
+			s.setMode(progress.SyncModeFollow, localLatest, bestPeer.Number)
+
 			continue
 		}
 
+		// a node started from a trusted checkpoint only syncs the blocks following it
+		if !checkpointSynced {
+			s.setMode(progress.SyncModeCheckpoint, localLatest, bestPeer.Number)
+
+			if err := s.syncCheckpoint(); err != nil {
+				s.logger.Warn("failed to sync from the checkpoint, retrying", "checkpoint", s.checkpoint.Number, "err", err)
+
+				continue
+			}
+
+			checkpointSynced = true
+			localLatest = s.blockchain.Header().Number
+		}
+
+		// a fresh node fetches the state at the latest checkpoint instead of executing all the blocks
+		if !snapAttempted {
+			snapAttempted = true
+
+			s.setMode(progress.SyncModeSnap, localLatest, bestPeer.Number)
+
+			if err := s.snapSync(); errors.Is(err, errCheckpointMismatch) {
+				// the headers below the checkpoint are imported, but the state of the head is missing
+				s.logger.Error("snap sync failed, the data directory needs to be removed", "err", err)
+			} else if err != nil {
+				s.logger.Warn("failed to snap sync, falling back to full sync", "err", err)
+			}
+
+			localLatest = s.blockchain.Header().Number
+		}
+
+		// the blocks far behind are downloaded from all the peers along the skeleton of the best one
+		if bestPeer.Number-localLatest > followDistance {
+			s.setMode(progress.SyncModeFull, localLatest, bestPeer.Number)
+
+			_, shouldTerminate, err := s.skeletonSync(bestPeer, callback)
+			if err != nil {
+				s.logger.Warn("failed to complete skeleton sync, try to next peer", "peer ID", bestPeer.ID, "error", err)
+
+				skipList[bestPeer.ID] = true
+			}
+
+			if shouldTerminate {
+				break
+			}
+
+			continue
+		}
+
+		s.setMode(progress.SyncModeFollow, localLatest, bestPeer.Number)
+
 		// fetch block from the peer
 		lastNumber, shouldTerminate, err := s.bulkSyncWithPeer(bestPeer.ID, callback)
 		if err != nil {
-			s.logger.Warn("failed to complete bulk sync with peer, try to next one", "peer ID", "error", bestPeer.ID, err)
+			s.logger.Warn("failed to complete bulk sync with peer, try to next one", "peer ID", bestPeer.ID, "error", err)
 		}
 
 		if lastNumber < bestPeer.Number {
@@ -207,6 +311,33 @@ func (s *syncer) Sync(callback func(*types.Block) bool) error {
 	return nil
 }
 
+// setMode switches the sync to the given mode.
+// The progression is tracked until the node follows the tip
+func (s *syncer) setMode(mode progress.SyncMode, localLatest, highest uint64) {
+	var (
+		wasSyncing = s.mode != "" && s.mode != progress.SyncModeFollow
+		isSyncing  = mode != progress.SyncModeFollow
+	)
+
+	if mode != s.mode {
+		s.logger.Info("sync mode switched", "from", s.mode, "to", mode, "local", localLatest, "highest", highest)
+	}
+
+	s.mode = mode
+
+	switch {
+	case isSyncing && !wasSyncing:
+		s.syncProgression.StartProgression(localLatest, s.blockchain.SubscribeEvents())
+	case !isSyncing && wasSyncing:
+		s.syncProgression.StopProgression()
+	}
+
+	if isSyncing {
+		s.syncProgression.UpdateMode(mode)
+		s.syncProgression.UpdateHighestProgression(highest)
+	}
+}
+
 // bulkSyncWithPeer syncs block with a given peer
 func (s *syncer) bulkSyncWithPeer(peerID peer.ID, newBlockCallback func(*types.Block) bool) (uint64, bool, error) {
 	localLatest := s.blockchain.Header().Number
@@ -224,7 +355,12 @@ func (s *syncer) bulkSyncWithPeer(peerID peer.ID, newBlockCallback func(*types.B
 		}
 	}()
 
-	var lastReceivedNumber uint64
+	var (
+		lastReceivedNumber uint64
+		requestedAt        = time.Now()
+		windowStart        time.Time
+		windowBlocks       int
+	)
 
 	for {
 		select {
@@ -238,7 +374,15 @@ func (s *syncer) bulkSyncWithPeer(peerID peer.ID, newBlockCallback func(*types.B
 				continue
 			}
 
+			if windowStart.IsZero() {
+				s.peerStats.RecordLatency(peerID, time.Since(requestedAt))
+
+				windowStart = time.Now()
+			}
+
 			if err := s.blockchain.VerifyFinalizedBlock(block); err != nil {
+				s.syncPeerClient.ReportPeer(peerID, network.PenaltyInvalidBlock)
+
 				return lastReceivedNumber, false, fmt.Errorf("unable to verify block, %w", err)
 			}
 
@@ -249,8 +393,30 @@ func (s *syncer) bulkSyncWithPeer(peerID peer.ID, newBlockCallback func(*types.B
 			shouldTerminate = newBlockCallback(block)
 
 			lastReceivedNumber = block.Number()
+
+			if windowBlocks++; windowBlocks == statsWindow {
+				if err := s.checkThroughput(peerID, windowBlocks, time.Since(windowStart)); err != nil {
+					return lastReceivedNumber, shouldTerminate, err
+				}
+
+				windowStart, windowBlocks = time.Now(), 0
+			}
 		case <-time.After(s.blockTimeout):
+			s.syncPeerClient.ReportPeer(peerID, network.PenaltyTimeout)
+
 			return lastReceivedNumber, shouldTerminate, errTimeout
 		}
 	}
 }
+
+// checkThroughput records the throughput of the peer over the last window of blocks,
+// and fails if it degraded under the throughput of the fastest other peer
+func (s *syncer) checkThroughput(peerID peer.ID, blocks int, elapsed time.Duration) error {
+	throughput := s.peerStats.RecordThroughput(peerID, blocks, elapsed)
+
+	if fastest := s.peerStats.FastestThroughput(peerID); throughput < degradedRatio*fastest {
+		return fmt.Errorf("%w: %.1f blocks/s, the fastest peer %.1f blocks/s", errPeerDegraded, throughput, fastest)
+	}
+
+	return nil
+}
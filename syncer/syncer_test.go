@@ -10,6 +10,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/network/event"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -20,6 +21,7 @@ import (
 type mockProgression struct {
 	startingBlock uint64
 	highestBlock  uint64
+	modes         []progress.SyncMode
 }
 
 func (m *mockProgression) StartProgression(startingBlock uint64, subscription blockchain.Subscription) {
@@ -30,6 +32,10 @@ func (m *mockProgression) UpdateHighestProgression(highestBlock uint64) {
 	m.highestBlock = highestBlock
 }
 
+func (m *mockProgression) UpdateMode(mode progress.SyncMode) {
+	m.modes = append(m.modes, mode)
+}
+
 func (m *mockProgression) GetProgression() *progress.Progression {
 	// Syncer doesn't use this method. It just exports
 	return nil
@@ -39,8 +45,15 @@ type mockBlockchain struct {
 	subscription                blockchain.Subscription
 	headerHandler               func() *types.Header
 	getBlockByNumberHandler     func(uint64, bool) (*types.Block, bool)
+	getHeaderByNumberHandler    func(uint64) (*types.Header, bool)
+	getBodyByHashHandler        func(types.Hash) (*types.Body, bool)
+	getReceiptsByHashHandler    func(types.Hash) ([]*types.Receipt, error)
 	verifyFinalizedBlockHandler func(*types.Block) error
 	writeBlockHandler           func(*types.Block) error
+	importHeaderHandler         func(*types.Header) error
+	writeCheckpointHandler      func(*types.Block, []*types.Receipt) error
+	writeBackfilledHandler      func([]*types.Block, [][]*types.Receipt) error
+	missingHistoryHandler       func() uint64
 }
 
 func (m *mockBlockchain) SubscribeEvents() blockchain.Subscription {
@@ -55,6 +68,18 @@ func (m *mockBlockchain) GetBlockByNumber(number uint64, full bool) (*types.Bloc
 	return m.getBlockByNumberHandler(number, full)
 }
 
+func (m *mockBlockchain) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	return m.getHeaderByNumberHandler(number)
+}
+
+func (m *mockBlockchain) GetBodyByHash(hash types.Hash) (*types.Body, bool) {
+	return m.getBodyByHashHandler(hash)
+}
+
+func (m *mockBlockchain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	return m.getReceiptsByHashHandler(hash)
+}
+
 func (m *mockBlockchain) VerifyFinalizedBlock(b *types.Block) error {
 	return m.verifyFinalizedBlockHandler(b)
 }
@@ -63,6 +88,22 @@ func (m *mockBlockchain) WriteBlock(b *types.Block, s string) error {
 	return m.writeBlockHandler(b)
 }
 
+func (m *mockBlockchain) ImportHeader(h *types.Header, s string) error {
+	return m.importHeaderHandler(h)
+}
+
+func (m *mockBlockchain) WriteCheckpoint(b *types.Block, receipts []*types.Receipt, s string) error {
+	return m.writeCheckpointHandler(b, receipts)
+}
+
+func (m *mockBlockchain) WriteBackfilledBlocks(blocks []*types.Block, receipts [][]*types.Receipt) error {
+	return m.writeBackfilledHandler(blocks, receipts)
+}
+
+func (m *mockBlockchain) MissingHistory() uint64 {
+	return m.missingHistoryHandler()
+}
+
 func newSimpleHeaderHandler(num uint64) func() *types.Header {
 	return func() *types.Header {
 		return &types.Header{
@@ -85,8 +126,14 @@ type mockSyncPeerClient struct {
 	getPeerStatusHandler                  func(peer.ID) (*NoForkPeer, error)
 	getConnectedPeerStatusesHandler       func() []*NoForkPeer
 	getBlocksHandler                      func(peer.ID, uint64, time.Duration) (<-chan *types.Block, error)
+	getHeadersHandler                     func(peer.ID, uint64, uint64, uint64, bool) ([]*types.Header, error)
+	getBodiesHandler                      func(peer.ID, []types.Hash) ([]*types.Body, error)
+	getReceiptsHandler                    func(peer.ID, []types.Hash) ([][]*types.Receipt, error)
 	getPeerStatusUpdateChHandler          func() <-chan *NoForkPeer
 	getPeerConnectionUpdateEventChHandler func() <-chan *event.PeerEvent
+
+	// reportedPenalties are the penalties of the peers reported by the syncer
+	reportedPenalties []network.PeerPenalty
 }
 
 func (m *mockSyncPeerClient) DisablePublishingPeerStatus() {}
@@ -115,6 +162,22 @@ func (m *mockSyncPeerClient) GetBlocks(
 	return m.getBlocksHandler(id, start, timeoutPerBlock)
 }
 
+func (m *mockSyncPeerClient) GetHeaders(
+	id peer.ID,
+	from, amount, skip uint64,
+	reverse bool,
+) ([]*types.Header, error) {
+	return m.getHeadersHandler(id, from, amount, skip, reverse)
+}
+
+func (m *mockSyncPeerClient) GetBodies(id peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+	return m.getBodiesHandler(id, hashes)
+}
+
+func (m *mockSyncPeerClient) GetReceipts(id peer.ID, hashes []types.Hash) ([][]*types.Receipt, error) {
+	return m.getReceiptsHandler(id, hashes)
+}
+
 func (m *mockSyncPeerClient) GetPeerStatusUpdateCh() <-chan *NoForkPeer {
 	return m.getPeerStatusUpdateChHandler()
 }
@@ -127,6 +190,10 @@ func (m *mockSyncPeerClient) CloseStream(peerID peer.ID) error {
 	return nil
 }
 
+func (m *mockSyncPeerClient) ReportPeer(_ peer.ID, penalty network.PeerPenalty) {
+	m.reportedPenalties = append(m.reportedPenalties, penalty)
+}
+
 func GetAllElementsFromPeerMap(t *testing.T, p *PeerMap) []*NoForkPeer {
 	t.Helper()
 
@@ -168,6 +235,8 @@ func NewTestSyncer(
 		blockTimeout:    blockTimeout,
 		newStatusCh:     make(chan struct{}),
 		peerMap:         new(PeerMap),
+		peerStats:       NewPeerStats(),
+		closeCh:         make(chan struct{}),
 	}
 }
 
@@ -538,8 +607,7 @@ func TestSync(t *testing.T) {
 					return nil
 				}
 			},
-			blocks: blocks[:10],
-			// TODO: need to fix implementation?
+			blocks:             blocks[:10],
 			progressionStart:   0,
 			progressionHighest: 0,
 			err:                nil,
@@ -584,8 +652,7 @@ func TestSync(t *testing.T) {
 					return nil
 				}
 			},
-			blocks: blocks[:10],
-			// TODO: need to fix implementation?
+			blocks:             blocks[:10],
 			progressionStart:   0,
 			progressionHighest: 0,
 			err:                nil,
@@ -694,6 +761,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 		blocks                []*types.Block
 		lastSyncedBlockNumber uint64
 		shouldTerminate       bool
+		penalties             []network.PeerPenalty
 		err                   error
 	}{
 		{
@@ -761,6 +829,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blocks:                blocks[:5],
 			lastSyncedBlockNumber: 5,
 			shouldTerminate:       false,
+			penalties:             []network.PeerPenalty{network.PenaltyInvalidBlock},
 			err:                   errInvalidBlock,
 		},
 		{
@@ -807,6 +876,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blocks:                []*types.Block{},
 			lastSyncedBlockNumber: 0,
 			shouldTerminate:       false,
+			penalties:             []network.PeerPenalty{network.PenaltyTimeout},
 			err:                   errTimeout,
 		},
 	}
@@ -819,6 +889,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 
 			var (
 				syncedBlocks = make([]*types.Block, 0, len(test.blocks))
+				peerClient   = &mockSyncPeerClient{getBlocksHandler: test.getBlocksHandler}
 
 				syncer = NewTestSyncer(
 					nil,
@@ -836,9 +907,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 						},
 					},
 					test.blockTimeout,
-					&mockSyncPeerClient{
-						getBlocksHandler: test.getBlocksHandler,
-					},
+					peerClient,
 					&mockProgression{},
 				)
 			)
@@ -849,6 +918,43 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			assert.Equal(t, test.shouldTerminate, shouldTerminate)
 			assert.ErrorIs(t, err, test.err)
 			assert.Equal(t, test.blocks, syncedBlocks)
+			assert.Equal(t, test.penalties, peerClient.reportedPenalties)
 		})
 	}
 }
+
+func Test_bulkSyncWithPeer_Degraded(t *testing.T) {
+	t.Parallel()
+
+	blocks := createMockBlocks(2 * statsWindow)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler:               newSimpleHeaderHandler(0),
+			verifyFinalizedBlockHandler: func(b *types.Block) error { return nil },
+			writeBlockHandler:           func(b *types.Block) error { return nil },
+		},
+		time.Second,
+		&mockSyncPeerClient{
+			getBlocksHandler: func(id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
+				return blocksToCh(blocks, 5*time.Millisecond), nil
+			},
+		},
+		&mockProgression{},
+	)
+
+	// another peer delivered 1000 blocks per second
+	syncer.peerStats.RecordThroughput(peer.ID("B"), 1000, time.Second)
+
+	lastSynced, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool { return false })
+
+	// the peer is rotated away from after the first window of blocks
+	assert.ErrorIs(t, err, errPeerDegraded)
+	assert.Equal(t, uint64(statsWindow), lastSynced)
+
+	stats, ok := syncer.peerStats.Get(peer.ID("A"))
+	assert.True(t, ok)
+	assert.Less(t, stats.Throughput, 500.0)
+	assert.NotZero(t, stats.Latency)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/network/event"
+	"github.com/0xPolygon/polygon-edge/statesync"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"google.golang.org/protobuf/proto"
@@ -23,10 +24,24 @@ type Blockchain interface {
 	Header() *types.Header
 	// GetBlockByNumber returns block by number
 	GetBlockByNumber(uint64, bool) (*types.Block, bool)
+	// GetHeaderByNumber returns header by number
+	GetHeaderByNumber(uint64) (*types.Header, bool)
+	// GetBodyByHash returns the body of the block with the given hash
+	GetBodyByHash(types.Hash) (*types.Body, bool)
+	// GetReceiptsByHash returns the receipts of the block with the given hash
+	GetReceiptsByHash(types.Hash) ([]*types.Receipt, error)
 	// VerifyFinalizedBlock verifies finalized block
 	VerifyFinalizedBlock(*types.Block) error
 	// WriteBlock writes a given block to chain
 	WriteBlock(*types.Block, string) error
+	// ImportHeader verifies a given header and writes it to chain without the body
+	ImportHeader(*types.Header, string) error
+	// WriteCheckpoint writes the trusted checkpoint block as the head of a fresh chain
+	WriteCheckpoint(*types.Block, []*types.Receipt, string) error
+	// WriteBackfilledBlocks writes the parents of the oldest stored block, from the newest to the oldest
+	WriteBackfilledBlocks([]*types.Block, [][]*types.Receipt) error
+	// MissingHistory returns the number of the newest block missing before the head, 0 if none
+	MissingHistory() uint64
 }
 
 type Network interface {
@@ -51,6 +66,10 @@ type Network interface {
 	SaveProtocolStream(protocol string, stream *rawGrpc.ClientConn, peerID peer.ID)
 	// CloseProtocolStream closes stream
 	CloseProtocolStream(protocol string, peerID peer.ID) error
+	// ReportPeer lowers the reputation of the peer for its misbehavior
+	ReportPeer(peerID peer.ID, penalty network.PeerPenalty)
+	// SupportsCapability checks if the peer sent the capability on the handshake
+	SupportsCapability(peerID peer.ID, capability string) bool
 }
 
 type Syncer interface {
@@ -73,10 +92,23 @@ type Progression interface {
 	UpdateHighestProgression(highestBlock uint64)
 	// GetProgression returns Progression
 	GetProgression() *progress.Progression
+	// UpdateMode updates the state of the sync
+	UpdateMode(mode progress.SyncMode)
 	// StopProgression finishes progression
 	StopProgression()
 }
 
+type SnapSyncer interface {
+	// LatestCheckpoint returns the latest pivot block whose state is served by the peers
+	LatestCheckpoint(ctx context.Context, peerIDs []peer.ID) (statesync.Checkpoint, error)
+	// Fetch fetches the state at the checkpoint from the peers, and returns the checkpoint header
+	Fetch(ctx context.Context, checkpoint statesync.Checkpoint, peerIDs []peer.ID) (*types.Header, error)
+	// FetchState fetches the state at the trusted header from the peers
+	FetchState(ctx context.Context, header *types.Header, peerIDs []peer.ID) error
+	// FetchedChunks returns the number of the state responses fetched so far
+	FetchedChunks() uint64
+}
+
 type SyncPeerService interface {
 	// Start starts server
 	Start()
@@ -95,12 +127,20 @@ type SyncPeerClient interface {
 	GetConnectedPeerStatuses() []*NoForkPeer
 	// GetBlocks returns a stream of blocks from given height to peer's latest
 	GetBlocks(peer.ID, uint64, time.Duration) (<-chan *types.Block, error)
+	// GetHeaders returns the headers of the range from the peer
+	GetHeaders(id peer.ID, from, amount, skip uint64, reverse bool) ([]*types.Header, error)
+	// GetBodies returns the bodies of the blocks with the given hashes from the peer
+	GetBodies(id peer.ID, hashes []types.Hash) ([]*types.Body, error)
+	// GetReceipts returns the receipts of the blocks with the given hashes from the peer
+	GetReceipts(id peer.ID, hashes []types.Hash) ([][]*types.Receipt, error)
 	// GetPeerStatusUpdateCh returns a channel of peer's status update
 	GetPeerStatusUpdateCh() <-chan *NoForkPeer
 	// GetPeerConnectionUpdateEventCh returns peer's connection change event
 	GetPeerConnectionUpdateEventCh() <-chan *event.PeerEvent
 	// CloseStream close a stream
 	CloseStream(peerID peer.ID) error
+	// ReportPeer lowers the reputation of the peer for its misbehavior
+	ReportPeer(peerID peer.ID, penalty network.PeerPenalty)
 	// DisablePublishingPeerStatus disables publishing status in syncer topic
 	DisablePublishingPeerStatus()
 	// EnablePublishingPeerStatus enables publishing status in syncer topic
@@ -52,7 +52,7 @@ func testVMCase(t *testing.T, name string, c *VMCase) {
 
 	s, _, root := buildState(c.Pre)
 
-	config := mainnetChainConfig.Forks.At(uint64(env.Number))
+	config := mainnetChainConfig.Forks.At(uint64(env.Number), uint64(env.Timestamp))
 
 	executor := state.NewExecutor(&mainnetChainConfig, s, hclog.NewNullLogger())
 	executor.GetHash = func(*types.Header) func(i uint64) types.Hash {
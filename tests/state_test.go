@@ -46,7 +46,7 @@ func RunSpecificTest(t *testing.T, file string, c stateCase, name, fork string,
 	}
 
 	s, snapshot, pastRoot := buildState(c.Pre)
-	forks := config.At(uint64(env.Number))
+	forks := config.At(uint64(env.Number), uint64(env.Timestamp))
 
 	xxx := state.NewExecutor(&chain.Params{Forks: config, ChainID: 1}, s, hclog.NewNullLogger())
 
@@ -228,6 +228,31 @@ func (a *account) reset(nonce uint64, promoteCh chan<- promoteRequest) (
 	return
 }
 
+// expire removes the expired transaction with the given nonce, along with
+// the transactions with higher nonce which can't be executed without it.
+// The nonce expected for this account is rolled back to the expired one
+func (a *account) expire(nonce uint64) (
+	prunedPromoted,
+	prunedEnqueued []*types.Transaction,
+) {
+	a.promoted.lock(true)
+	a.enqueued.lock(true)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	prunedPromoted = a.promoted.pruneFrom(nonce)
+	prunedEnqueued = a.enqueued.pruneFrom(nonce)
+
+	if nonce < a.getNonce() {
+		a.setNonce(nonce)
+	}
+
+	return
+}
+
 // enqueue attempts tp push the transaction onto the enqueued queue.
 func (a *account) enqueue(tx *types.Transaction) error {
 	a.enqueued.lock(true)
@@ -0,0 +1,41 @@
+package txpool
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// allowlistRoles returns the roles of the account in the allowlist at the state root,
+// all of them for the admins of the chain
+func (p *TxPool) allowlistRoles(root types.Hash, addr types.Address) uint64 {
+	if p.allowlist.IsAdmin(addr) {
+		return precompiled.AllowlistAllRoles
+	}
+
+	value := p.store.GetStorage(root, precompiled.AllowlistAddr, precompiled.AllowlistSlot(addr))
+
+	return new(big.Int).SetBytes(value.Bytes()).Uint64()
+}
+
+// checkAllowlist rejects the transactions of the senders which don't hold the roles
+// the allowlist requires for them at the state root, once the allowlist fork is active
+func (p *TxPool) checkAllowlist(root types.Hash, forks chain.ForksInTime, tx *types.Transaction) error {
+	if !forks.Allowlist || p.allowlist == nil || (!p.allowlist.Transactions && !p.allowlist.Deployments) {
+		return nil
+	}
+
+	roles := p.allowlistRoles(root, tx.From)
+
+	if p.allowlist.Transactions && roles&precompiled.AllowlistSender == 0 {
+		return ErrSenderNotAllowed
+	}
+
+	if p.allowlist.Deployments && tx.IsContractCreation() && roles&precompiled.AllowlistDeployer == 0 {
+		return ErrDeployerNotAllowed
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// storageMockStore serves the storage of the allowlist precompile
+type storageMockStore struct {
+	defaultMockStore
+
+	storage map[types.Hash]types.Hash
+}
+
+func (m storageMockStore) GetStorage(_ types.Hash, addr types.Address, slot types.Hash) types.Hash {
+	if addr != precompiled.AllowlistAddr {
+		return types.ZeroHash
+	}
+
+	return m.storage[slot]
+}
+
+func TestCheckAllowlist(t *testing.T) {
+	t.Parallel()
+
+	admin := types.StringToAddress("0xad")
+
+	store := storageMockStore{
+		defaultMockStore: NewDefaultMockStore(mockHeader),
+		storage: map[types.Hash]types.Hash{
+			precompiled.AllowlistSlot(addr1): types.BytesToHash(big.NewInt(int64(precompiled.AllowlistSender)).Bytes()),
+			precompiled.AllowlistSlot(addr2): types.BytesToHash(big.NewInt(int64(precompiled.AllowlistDeployer)).Bytes()),
+		},
+	}
+
+	transfer := func(from types.Address) *types.Transaction {
+		return &types.Transaction{From: from, To: &addr3}
+	}
+
+	deployment := func(from types.Address) *types.Transaction {
+		return &types.Transaction{From: from}
+	}
+
+	tests := []struct {
+		name      string
+		allowlist *chain.Allowlist
+		inactive  bool
+		tx        *types.Transaction
+		err       error
+	}{
+		{
+			name: "no allowlist",
+			tx:   deployment(addr3),
+		},
+		{
+			name:      "the allowlist fork isn't active",
+			allowlist: &chain.Allowlist{Deployments: true, Transactions: true},
+			inactive:  true,
+			tx:        deployment(addr3),
+		},
+		{
+			name:      "the admin deploys",
+			allowlist: &chain.Allowlist{Admins: []types.Address{admin}, Deployments: true, Transactions: true},
+			tx:        deployment(admin),
+		},
+		{
+			name:      "the sender without the deployer role transfers",
+			allowlist: &chain.Allowlist{Deployments: true},
+			tx:        transfer(addr1),
+		},
+		{
+			name:      "the sender without the deployer role deploys",
+			allowlist: &chain.Allowlist{Deployments: true},
+			tx:        deployment(addr1),
+			err:       ErrDeployerNotAllowed,
+		},
+		{
+			name:      "the deployer deploys",
+			allowlist: &chain.Allowlist{Deployments: true},
+			tx:        deployment(addr2),
+		},
+		{
+			name:      "the deployer without the sender role transfers",
+			allowlist: &chain.Allowlist{Transactions: true},
+			tx:        transfer(addr2),
+			err:       ErrSenderNotAllowed,
+		},
+		{
+			name:      "the account without roles transfers",
+			allowlist: &chain.Allowlist{Transactions: true},
+			tx:        transfer(addr3),
+			err:       ErrSenderNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool := &TxPool{store: store, allowlist: tt.allowlist}
+			forks := chain.ForksInTime{Allowlist: !tt.inactive}
+
+			assert.ErrorIs(t, pool.checkAllowlist(types.ZeroHash, forks, tt.tx), tt.err)
+		})
+	}
+}
+
+func TestAllowlistForkActivation(t *testing.T) {
+	t.Parallel()
+
+	key, addr := tests.GenerateKeyAndAddr(t)
+	head := &types.Header{GasLimit: mockHeader.GasLimit}
+
+	pool, err := newTestPool(NewDefaultMockStore(head))
+	require.NoError(t, err)
+
+	pool.SetSigner(signerEIP155)
+	pool.allowlist = &chain.Allowlist{Transactions: true}
+	pool.forks = &chain.Forks{Homestead: chain.NewFork(0), Istanbul: chain.NewFork(0), Allowlist: chain.NewFork(2)}
+
+	tx, err := signerEIP155.SignTx(newTx(addr, 0, 1), key)
+	require.NoError(t, err)
+
+	// the senders need the roles from the allowlist fork
+	assert.NoError(t, pool.validateTx(tx))
+
+	head.Number = 1
+
+	assert.ErrorIs(t, pool.validateTx(tx), ErrSenderNotAllowed)
+}
@@ -0,0 +1,315 @@
+package txpool
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// conditionalTxTypeURL marks the gossiped transactions wrapped along with their conditions.
+	// The nodes which can't enforce the conditions fail to decode them, and drop them
+	conditionalTxTypeURL = "conditional"
+
+	// maxKnownSlots bounds the storage slots read to check the conditions of a transaction
+	maxKnownSlots = 1000
+)
+
+var (
+	ErrTxExpired               = errors.New("transaction expired")
+	ErrConditionalTxRestricted = errors.New("conditional transaction restricted to the whitelisted bundlers")
+	ErrTooManyKnownSlots       = errors.New("too many known storage slots")
+
+	errInvalidConditionalTx = errors.New("invalid conditional transaction")
+)
+
+// bundlerWhitelist holds the senders allowed to send conditional transactions,
+// if empty anyone can
+type bundlerWhitelist map[types.Address]struct{}
+
+func newBundlerWhitelist(addrs []types.Address) bundlerWhitelist {
+	w := make(bundlerWhitelist, len(addrs))
+
+	for _, addr := range addrs {
+		w[addr] = struct{}{}
+	}
+
+	return w
+}
+
+// allowed checks if the address can send conditional transactions
+func (w bundlerWhitelist) allowed(addr types.Address) bool {
+	if len(w) == 0 {
+		return true
+	}
+
+	_, ok := w[addr]
+
+	return ok
+}
+
+// validateConditions checks the sender and the cost of the conditions of the transaction
+func (p *TxPool) validateConditions(tx *types.Transaction) error {
+	if !tx.IsConditional() {
+		return nil
+	}
+
+	if !p.bundlerWhitelist.allowed(tx.From) {
+		return ErrConditionalTxRestricted
+	}
+
+	slots := 0
+	for _, known := range tx.KnownAccounts {
+		slots += len(known)
+	}
+
+	if slots > maxKnownSlots {
+		return ErrTooManyKnownSlots
+	}
+
+	return nil
+}
+
+// expirations tracks the transactions of the pool which expire at a block
+type expirations struct {
+	sync.Mutex
+
+	txs map[types.Hash]*types.Transaction
+}
+
+func (e *expirations) add(tx *types.Transaction) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.txs[tx.Hash] = tx
+}
+
+// expired returns the tracked transactions which can't be included in the next block anymore,
+// and stops tracking them along with the ones no longer in the pool
+func (e *expirations) expired(head uint64, inPool func(types.Hash) bool) (expired []*types.Transaction) {
+	e.Lock()
+	defer e.Unlock()
+
+	for hash, tx := range e.txs {
+		switch {
+		case !inPool(hash):
+			delete(e.txs, hash)
+		case tx.IsExpired(head + 1):
+			expired = append(expired, tx)
+
+			delete(e.txs, hash)
+		}
+	}
+
+	return
+}
+
+// pruneExpired removes the transactions which expired with the new head,
+// along with the transactions of their senders which can't be executed without them
+func (p *TxPool) pruneExpired(head uint64) {
+	expired := p.expirations.expired(head, func(hash types.Hash) bool {
+		_, ok := p.index.get(hash)
+
+		return ok
+	})
+
+	for _, tx := range expired {
+		account := p.accounts.get(tx.From)
+		if account == nil {
+			continue
+		}
+
+		prunedPromoted, prunedEnqueued := account.expire(tx.Nonce)
+
+		if len(prunedPromoted) > 0 {
+			p.index.remove(prunedPromoted...)
+			p.gauge.decrease(slotsRequired(prunedPromoted...))
+			p.updatePending(int64(-1 * len(prunedPromoted)))
+
+			p.eventManager.signalEvent(proto.EventType_PRUNED_PROMOTED, toHash(prunedPromoted...)...)
+		}
+
+		if len(prunedEnqueued) > 0 {
+			p.index.remove(prunedEnqueued...)
+			p.gauge.decrease(slotsRequired(prunedEnqueued...))
+
+			p.eventManager.signalEvent(proto.EventType_PRUNED_ENQUEUED, toHash(prunedEnqueued...)...)
+		}
+
+		countDropped(dropExpired, len(prunedPromoted)+len(prunedEnqueued))
+
+		p.logger.Debug("pruned expired tx",
+			"hash", tx.Hash.String(),
+			"valid_until", tx.ValidUntil,
+			"pruned", len(prunedPromoted)+len(prunedEnqueued),
+		)
+	}
+}
+
+// marshalGossipTx returns the gossip message of the transaction,
+// wrapped along with its conditions if it has any
+func marshalGossipTx(tx *types.Transaction) *proto.Txn {
+	if !tx.IsConditional() {
+		return &proto.Txn{
+			Raw: &any.Any{
+				Value: tx.MarshalRLP(),
+			},
+		}
+	}
+
+	ar := &fastrlp.Arena{}
+
+	v := ar.NewArray()
+	v.Set(ar.NewUint(tx.ValidUntil))
+	v.Set(ar.NewBytes(tx.MarshalRLP()))
+
+	accounts := ar.NewArray()
+
+	for addr, known := range tx.KnownAccounts {
+		slots := ar.NewArray()
+
+		for key, value := range known {
+			slot := ar.NewArray()
+			slot.Set(ar.NewBytes(key.Bytes()))
+			slot.Set(ar.NewBytes(value.Bytes()))
+
+			slots.Set(slot)
+		}
+
+		account := ar.NewArray()
+		account.Set(ar.NewBytes(addr.Bytes()))
+		account.Set(slots)
+
+		accounts.Set(account)
+	}
+
+	v.Set(accounts)
+
+	return &proto.Txn{
+		Raw: &any.Any{
+			TypeUrl: conditionalTxTypeURL,
+			Value:   v.MarshalTo(nil),
+		},
+	}
+}
+
+// unmarshalGossipTx decodes the transaction of the gossip message, along with its conditions
+func unmarshalGossipTx(raw *any.Any) (*types.Transaction, error) {
+	tx := new(types.Transaction)
+
+	if raw.TypeUrl != conditionalTxTypeURL {
+		if err := tx.UnmarshalRLP(raw.Value); err != nil {
+			return nil, err
+		}
+
+		return tx, nil
+	}
+
+	p := &fastrlp.Parser{}
+
+	v, err := p.Parse(raw.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	// the known accounts are missing from the messages of the nodes which don't support them
+	if len(elems) != 2 && len(elems) != 3 {
+		return nil, errInvalidConditionalTx
+	}
+
+	validUntil, err := elems[0].GetUint64()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := elems[1].Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.UnmarshalRLP(data); err != nil {
+		return nil, err
+	}
+
+	tx.ValidUntil = validUntil
+
+	if len(elems) == 3 {
+		if tx.KnownAccounts, err = unmarshalKnownAccounts(elems[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+func unmarshalKnownAccounts(v *fastrlp.Value) (map[types.Address]map[types.Hash]types.Hash, error) {
+	accounts, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+
+	knownAccounts := make(map[types.Address]map[types.Hash]types.Hash, len(accounts))
+
+	for _, account := range accounts {
+		elems, err := account.GetElems()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(elems) != 2 {
+			return nil, errInvalidConditionalTx
+		}
+
+		var addr types.Address
+		if err := elems[0].GetAddr(addr[:]); err != nil {
+			return nil, err
+		}
+
+		slots, err := elems[1].GetElems()
+		if err != nil {
+			return nil, err
+		}
+
+		known := make(map[types.Hash]types.Hash, len(slots))
+
+		for _, slot := range slots {
+			kv, err := slot.GetElems()
+			if err != nil {
+				return nil, err
+			}
+
+			if len(kv) != 2 {
+				return nil, errInvalidConditionalTx
+			}
+
+			var key, value types.Hash
+			if err := kv[0].GetHash(key[:]); err != nil {
+				return nil, err
+			}
+
+			if err := kv[1].GetHash(value[:]); err != nil {
+				return nil, err
+			}
+
+			known[key] = value
+		}
+
+		knownAccounts[addr] = known
+	}
+
+	return knownAccounts, nil
+}
@@ -0,0 +1,129 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGossipTx_Conditional(t *testing.T) {
+	t.Parallel()
+
+	tx := newTx(addr1, 3, 1)
+	tx.ComputeHash()
+
+	// the transactions without conditions are gossiped as before
+	msg := marshalGossipTx(tx)
+	assert.Empty(t, msg.Raw.TypeUrl)
+	assert.Equal(t, tx.MarshalRLP(), msg.Raw.Value)
+
+	tx.ValidUntil = 10
+	tx.KnownAccounts = map[types.Address]map[types.Hash]types.Hash{
+		addr2: {types.StringToHash("1"): types.StringToHash("2")},
+	}
+
+	msg = marshalGossipTx(tx)
+	assert.Equal(t, conditionalTxTypeURL, msg.Raw.TypeUrl)
+
+	decoded, err := unmarshalGossipTx(msg.Raw)
+	require.NoError(t, err)
+
+	decoded.ComputeHash()
+	assert.Equal(t, tx.Hash, decoded.Hash)
+	assert.Equal(t, uint64(10), decoded.ValidUntil)
+	assert.Equal(t, tx.KnownAccounts, decoded.KnownAccounts)
+
+	msg.Raw.Value = tx.MarshalRLP()
+
+	_, err = unmarshalGossipTx(msg.Raw)
+	assert.Error(t, err)
+}
+
+func TestAddTx_Expired(t *testing.T) {
+	t.Parallel()
+
+	pool, err := newTestPool(NewDefaultMockStore(&types.Header{
+		Number:   5,
+		GasLimit: mockHeader.GasLimit,
+	}))
+	require.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	// the transaction can't be included in the block after the head
+	tx := newTx(addr1, 0, 1)
+	tx.ValidUntil = 5
+
+	assert.ErrorIs(t, pool.addTx(local, tx), ErrTxExpired)
+}
+
+func TestPruneExpired(t *testing.T) {
+	t.Parallel()
+
+	pool, err := newTestPool()
+	require.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	expiring := newTx(addr1, 0, 1)
+	expiring.ValidUntil = 1
+
+	// the transactions with higher nonce can't be executed without the expired one
+	for _, tx := range []*types.Transaction{expiring, newTx(addr1, 1, 1)} {
+		go func(tx *types.Transaction) {
+			assert.NoError(t, pool.addTx(local, tx))
+		}(tx)
+		go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+		pool.handlePromoteRequest(<-pool.promoteReqCh)
+	}
+
+	require.Equal(t, uint64(2), pool.accounts.get(addr1).promoted.length())
+	require.Equal(t, uint64(2), pool.accounts.get(addr1).getNonce())
+
+	// still valid in block 1
+	pool.pruneExpired(0)
+	assert.Equal(t, uint64(2), pool.accounts.get(addr1).promoted.length())
+
+	pool.pruneExpired(1)
+
+	assert.Equal(t, uint64(0), pool.gauge.read())
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).promoted.length())
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).getNonce())
+	assert.Equal(t, uint64(0), pool.Length())
+
+	_, exists := pool.index.get(expiring.Hash)
+	assert.False(t, exists)
+	assert.Empty(t, pool.expirations.txs)
+}
+
+func TestAddTx_Conditional(t *testing.T) {
+	t.Parallel()
+
+	newConditionalTx := func(addr types.Address, slots int) *types.Transaction {
+		tx := newTx(addr, 0, 1)
+		tx.KnownAccounts = map[types.Address]map[types.Hash]types.Hash{addr3: {}}
+
+		for i := 0; i < slots; i++ {
+			tx.KnownAccounts[addr3][types.BytesToHash([]byte{byte(i >> 8), byte(i)})] = types.ZeroHash
+		}
+
+		return tx
+	}
+
+	pool, err := newTestPool()
+	require.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	pool.bundlerWhitelist = newBundlerWhitelist([]types.Address{addr1})
+
+	// only the whitelisted bundlers send conditional transactions
+	assert.ErrorIs(t, pool.validateTx(newConditionalTx(addr2, 1)), ErrConditionalTxRestricted)
+	assert.NoError(t, pool.validateTx(newTx(addr2, 0, 1)))
+
+	assert.NoError(t, pool.validateTx(newConditionalTx(addr1, maxKnownSlots)))
+	assert.ErrorIs(t, pool.validateTx(newConditionalTx(addr1, maxKnownSlots+1)), ErrTooManyKnownSlots)
+
+	// anyone sends them without a whitelist
+	pool.bundlerWhitelist = newBundlerWhitelist(nil)
+	assert.NoError(t, pool.validateTx(newConditionalTx(addr2, 1)))
+}
@@ -0,0 +1,133 @@
+package txpool
+
+import (
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Lane is the lane of the transactions of a sender. The executable transactions of a lane
+// are peeked before the ones of the next lanes, in the order of their gas prices
+type Lane int
+
+const (
+	// LaneSystem holds the transactions of the system senders of the chain, such as the relayers
+	// of the bridges, which are packed before any other transaction in the whole block
+	LaneSystem Lane = iota
+	// LanePriority holds the transactions of the senders allowlisted by the node,
+	// which are packed before the regular transactions in the gas reserved to them
+	LanePriority
+	// LaneRegular holds the transactions of the other senders
+	LaneRegular
+
+	numLanes
+)
+
+// MaxPriorityGasPercent is the highest share of the block gas limit reserved to the priority lane
+const MaxPriorityGasPercent = 100
+
+func (l Lane) String() string {
+	switch l {
+	case LaneSystem:
+		return "system"
+	case LanePriority:
+		return "priority"
+	default:
+		return "regular"
+	}
+}
+
+// lanes holds the senders of the lanes, and the executable transactions of every lane
+type lanes struct {
+	system   map[types.Address]struct{}
+	priority map[types.Address]struct{}
+
+	// priorityGasPercent is the share of the block gas limit reserved to the priority lane
+	priorityGasPercent uint64
+
+	executables [numLanes]*pricedQueue
+}
+
+func newLanes(config *Config) lanes {
+	l := lanes{
+		system:             make(map[types.Address]struct{}, len(config.SystemSenders)),
+		priority:           make(map[types.Address]struct{}, len(config.PrioritySenders)),
+		priorityGasPercent: config.PriorityGasPercent,
+	}
+
+	for _, addr := range config.SystemSenders {
+		l.system[addr] = struct{}{}
+	}
+
+	for _, addr := range config.PrioritySenders {
+		l.priority[addr] = struct{}{}
+	}
+
+	if l.priorityGasPercent > MaxPriorityGasPercent {
+		l.priorityGasPercent = MaxPriorityGasPercent
+	}
+
+	for i := range l.executables {
+		l.executables[i] = newPricedQueue()
+	}
+
+	return l
+}
+
+// lane returns the lane of the sender
+func (l *lanes) lane(addr types.Address) Lane {
+	if _, ok := l.system[addr]; ok {
+		return LaneSystem
+	}
+
+	if _, ok := l.priority[addr]; ok {
+		return LanePriority
+	}
+
+	return LaneRegular
+}
+
+// clear empties the executables of the lanes
+func (l *lanes) clear() {
+	for _, q := range l.executables {
+		q.clear()
+	}
+}
+
+// push pushes the executable transaction onto the queue of its lane
+func (l *lanes) push(tx *types.Transaction) {
+	l.executables[l.lane(tx.From)].push(tx)
+}
+
+// pop removes the executable transaction of the first lane holding any, nil if they're all empty
+func (l *lanes) pop() *types.Transaction {
+	for _, q := range l.executables {
+		if tx := q.pop(); tx != nil {
+			return tx
+		}
+	}
+
+	return nil
+}
+
+// length returns the number of the executable transactions of the lanes
+func (l *lanes) length() (length uint64) {
+	for _, q := range l.executables {
+		length += q.length()
+	}
+
+	return length
+}
+
+// Lane returns the lane of the transaction
+func (p *TxPool) Lane(tx *types.Transaction) Lane {
+	return p.lanes.lane(tx.From)
+}
+
+// LaneGas returns the gas of the block reserved to the transactions of the lane, which they're packed
+// in before the transactions of the next lanes. The system and regular lanes may use the whole block
+func (p *TxPool) LaneGas(lane Lane, gasLimit uint64) uint64 {
+	if lane != LanePriority {
+		return gasLimit
+	}
+
+	return gasLimit / 100 * p.lanes.priorityGasPercent
+}
@@ -0,0 +1,49 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestLanes(t *testing.T) {
+	t.Parallel()
+
+	system, priority := types.StringToAddress("0x1"), types.StringToAddress("0x2")
+
+	l := newLanes(&Config{
+		SystemSenders:      []types.Address{system},
+		PrioritySenders:    []types.Address{priority},
+		PriorityGasPercent: 25,
+	})
+
+	newPricedTx := func(from types.Address, price int64) *types.Transaction {
+		return &types.Transaction{From: from, GasPrice: big.NewInt(price)}
+	}
+
+	regularHigh, regularLow := newPricedTx(addr1, 100), newPricedTx(addr2, 10)
+	priorityTx, systemTx := newPricedTx(priority, 1), newPricedTx(system, 0)
+
+	for _, tx := range []*types.Transaction{regularLow, priorityTx, regularHigh, systemTx} {
+		l.push(tx)
+	}
+
+	assert.Equal(t, uint64(4), l.length())
+
+	// the lanes come first, then the gas prices
+	for _, expected := range []*types.Transaction{systemTx, priorityTx, regularHigh, regularLow} {
+		assert.Equal(t, expected, l.pop())
+	}
+
+	assert.Nil(t, l.pop())
+
+	pool := &TxPool{lanes: l}
+
+	assert.Equal(t, LanePriority, pool.Lane(priorityTx))
+	assert.Equal(t, LaneRegular, pool.Lane(regularLow))
+	assert.Equal(t, uint64(1_000_000), pool.LaneGas(LaneSystem, 1_000_000))
+	assert.Equal(t, uint64(250_000), pool.LaneGas(LanePriority, 1_000_000))
+}
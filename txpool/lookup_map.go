@@ -49,3 +49,21 @@ func (m *lookupMap) get(hash types.Hash) (*types.Transaction, bool) {
 
 	return tx, true
 }
+
+// hashes returns the hashes of at most limit transactions of the map. [thread-safe]
+func (m *lookupMap) hashes(limit int) []types.Hash {
+	m.RLock()
+	defer m.RUnlock()
+
+	hashes := make([]types.Hash, 0, len(m.all))
+
+	for hash := range m.all {
+		if len(hashes) >= limit {
+			break
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	return hashes
+}
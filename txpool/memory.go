@@ -0,0 +1,40 @@
+package txpool
+
+import (
+	"github.com/0xPolygon/polygon-edge/helper/membudget"
+)
+
+// memoryConsumer sizes the slots of the pool from its memory budget
+type memoryConsumer struct {
+	pool *TxPool
+}
+
+// MemoryConsumer returns the pool as a consumer of the memory budget,
+// which resizes its slots up to the configured max slots
+func (p *TxPool) MemoryConsumer() membudget.Consumer {
+	return &memoryConsumer{pool: p}
+}
+
+func (c *memoryConsumer) Usage() int {
+	return int(c.pool.gauge.read() * txSlotSize)
+}
+
+// Pressure returns the ratio of the occupied slots,
+// 0 once the pool reached its max slots and can't use a larger budget
+func (c *memoryConsumer) Pressure() float64 {
+	limit := c.pool.gauge.limit()
+	if limit == 0 || limit >= c.pool.maxSlots {
+		return 0
+	}
+
+	return float64(c.pool.gauge.read()) / float64(limit)
+}
+
+func (c *memoryConsumer) Resize(budget int) {
+	slots := uint64(budget) / txSlotSize
+	if slots > c.pool.maxSlots {
+		slots = c.pool.maxSlots
+	}
+
+	c.pool.gauge.setLimit(slots)
+}
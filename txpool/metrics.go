@@ -0,0 +1,76 @@
+package txpool
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/helper/telemetry"
+)
+
+// The reasons of the transactions dropped from the pool
+const (
+	// dropFailed is the reason of the accounts dropped after a failed execution
+	dropFailed = "failed"
+	// dropDemoted is the reason of the accounts dropped after too many demotions
+	dropDemoted = "demoted"
+	// dropRewind is the reason of the transactions dropped on the rewind of the chain
+	dropRewind = "rewind"
+	// dropNonceHole is the reason of the enqueued transactions pruned for the gaps in their nonces
+	dropNonceHole = "nonce_hole"
+	// dropExpired is the reason of the expired transactions, and of the transactions of their senders following them
+	dropExpired = "expired"
+	// dropStaleNonce is the reason of the transactions whose nonce was used by the new blocks
+	dropStaleNonce = "stale_nonce"
+	// dropEnqueueFailed is the reason of the transactions the accounts refused to enqueue
+	dropEnqueueFailed = "enqueue_failed"
+)
+
+// addedResult is the result of the transactions added to the pool
+const addedResult = "added"
+
+// addResults are the results of the transactions rejected by the pool, by the error
+var addResults = []struct {
+	err    error
+	result string
+}{
+	{ErrAlreadyKnown, "already_known"},
+	{ErrNonceTooLow, "nonce_too_low"},
+	{ErrUnderpriced, "underpriced"},
+	{ErrInsufficientFunds, "insufficient_funds"},
+	{ErrSponsorUnderfunded, "sponsor_insufficient_funds"},
+	{ErrIntrinsicGas, "intrinsic_gas"},
+	{ErrBlockLimitExceeded, "block_gas_limit"},
+	{ErrTxPoolOverflow, "pool_full"},
+	{ErrRejectFutureTx, "future_rejected"},
+	{ErrMaxEnqueuedLimitReached, "enqueued_limit"},
+	{ErrOversizedData, "oversized"},
+	{ErrTxExpired, "expired"},
+}
+
+// addResult returns the result label of the transaction added to the pool with the error
+func addResult(err error) string {
+	if err == nil {
+		return addedResult
+	}
+
+	for _, entry := range addResults {
+		if errors.Is(err, entry.err) {
+			return entry.result
+		}
+	}
+
+	return "invalid"
+}
+
+// countAdded counts the transaction submitted to the pool from the origin
+func countAdded(origin txOrigin, err error) {
+	telemetry.TxPoolAdded.WithLabelValues(origin.String(), addResult(err)).Inc()
+}
+
+// countDropped counts the transactions dropped from the pool with the reason
+func countDropped(reason string, count int) {
+	if count == 0 {
+		return
+	}
+
+	telemetry.TxPoolDropped.WithLabelValues(reason).Add(float64(count))
+}
@@ -0,0 +1,17 @@
+package txpool
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddResult(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, addedResult, addResult(nil))
+	assert.Equal(t, "nonce_too_low", addResult(ErrNonceTooLow))
+	assert.Equal(t, "underpriced", addResult(fmt.Errorf("%w: 1 wei", ErrUnderpriced)))
+	assert.Equal(t, "invalid", addResult(ErrInvalidChainID))
+}
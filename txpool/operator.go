@@ -78,3 +78,13 @@ func (p *TxPool) Subscribe(
 		}
 	}
 }
+
+// SubscribeTxEvents subscribes to the tx pool events of the given types.
+// It returns the channel of the events and the function that cancels the subscription
+func (p *TxPool) SubscribeTxEvents(eventTypes ...proto.EventType) (<-chan *proto.TxPoolEvent, func()) {
+	subscription := p.eventManager.subscribe(eventTypes)
+
+	return subscription.subscriptionChannel, func() {
+		p.eventManager.cancelSubscription(subscription.subscriptionID)
+	}
+}
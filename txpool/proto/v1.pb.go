@@ -1,16 +1,16 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0
-// 	protoc        v3.12.0
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
 // source: txpool/proto/v1.proto
 
 package proto
 
 import (
-	proto "github.com/golang/protobuf/proto"
-	any "github.com/golang/protobuf/ptypes/any"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -22,16 +22,12 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// This is a compile-time assertion that a sufficiently up-to-date version
-// of the legacy proto package is being used.
-const _ = proto.ProtoPackageIsVersion4
-
 type Txn struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Raw *any.Any `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
+	Raw *anypb.Any `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
 }
 
 func (x *Txn) Reset() {
@@ -66,24 +62,188 @@ func (*Txn) Descriptor() ([]byte, []int) {
 	return file_txpool_proto_v1_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *Txn) GetRaw() *any.Any {
+func (x *Txn) GetRaw() *anypb.Any {
 	if x != nil {
 		return x.Raw
 	}
 	return nil
 }
 
+// PooledHashes contains the hashes of the transactions of a pool
+type PooledHashes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hashes [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+}
+
+func (x *PooledHashes) Reset() {
+	*x = PooledHashes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_v1_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PooledHashes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PooledHashes) ProtoMessage() {}
+
+func (x *PooledHashes) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_v1_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PooledHashes.ProtoReflect.Descriptor instead.
+func (*PooledHashes) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_v1_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PooledHashes) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+// GetPooledTxnsRequest is a request for GetPooledTxns
+type GetPooledTxnsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The hashes of the transactions
+	Hashes [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+}
+
+func (x *GetPooledTxnsRequest) Reset() {
+	*x = GetPooledTxnsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_v1_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPooledTxnsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPooledTxnsRequest) ProtoMessage() {}
+
+func (x *GetPooledTxnsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_v1_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPooledTxnsRequest.ProtoReflect.Descriptor instead.
+func (*GetPooledTxnsRequest) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_v1_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetPooledTxnsRequest) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+// PooledTxns contains the transactions of a pool
+type PooledTxns struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Txns []*Txn `protobuf:"bytes,1,rep,name=txns,proto3" json:"txns,omitempty"`
+}
+
+func (x *PooledTxns) Reset() {
+	*x = PooledTxns{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_v1_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PooledTxns) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PooledTxns) ProtoMessage() {}
+
+func (x *PooledTxns) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_v1_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PooledTxns.ProtoReflect.Descriptor instead.
+func (*PooledTxns) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_v1_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PooledTxns) GetTxns() []*Txn {
+	if x != nil {
+		return x.Txns
+	}
+	return nil
+}
+
 var File_txpool_proto_v1_proto protoreflect.FileDescriptor
 
 var file_txpool_proto_v1_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76,
 	0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76, 0x31, 0x1a, 0x19, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2d, 0x0a, 0x03, 0x54, 0x78, 0x6e, 0x12, 0x26, 0x0a,
-	0x03, 0x72, 0x61, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79,
-	0x52, 0x03, 0x72, 0x61, 0x77, 0x42, 0x0f, 0x5a, 0x0d, 0x2f, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x2d, 0x0a, 0x03, 0x54, 0x78, 0x6e, 0x12, 0x26, 0x0a, 0x03, 0x72, 0x61,
+	0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x03, 0x72,
+	0x61, 0x77, 0x22, 0x26, 0x0a, 0x0c, 0x50, 0x6f, 0x6f, 0x6c, 0x65, 0x64, 0x48, 0x61, 0x73, 0x68,
+	0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0x2e, 0x0a, 0x14, 0x47, 0x65,
+	0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x65, 0x64, 0x54, 0x78, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0x29, 0x0a, 0x0a, 0x50, 0x6f,
+	0x6f, 0x6c, 0x65, 0x64, 0x54, 0x78, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x04, 0x74, 0x78, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x78, 0x6e, 0x52,
+	0x04, 0x74, 0x78, 0x6e, 0x73, 0x32, 0x84, 0x01, 0x0a, 0x0a, 0x54, 0x78, 0x50, 0x6f, 0x6f, 0x6c,
+	0x53, 0x79, 0x6e, 0x63, 0x12, 0x3b, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x65,
+	0x64, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x10, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x65, 0x64, 0x48, 0x61, 0x73, 0x68, 0x65,
+	0x73, 0x12, 0x39, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x65, 0x64, 0x54, 0x78,
+	0x6e, 0x73, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x65,
+	0x64, 0x54, 0x78, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x65, 0x64, 0x54, 0x78, 0x6e, 0x73, 0x42, 0x0f, 0x5a, 0x0d,
+	0x2f, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -98,18 +258,27 @@ func file_txpool_proto_v1_proto_rawDescGZIP() []byte {
 	return file_txpool_proto_v1_proto_rawDescData
 }
 
-var file_txpool_proto_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_txpool_proto_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_txpool_proto_v1_proto_goTypes = []interface{}{
-	(*Txn)(nil),     // 0: v1.Txn
-	(*any.Any)(nil), // 1: google.protobuf.Any
+	(*Txn)(nil),                  // 0: v1.Txn
+	(*PooledHashes)(nil),         // 1: v1.PooledHashes
+	(*GetPooledTxnsRequest)(nil), // 2: v1.GetPooledTxnsRequest
+	(*PooledTxns)(nil),           // 3: v1.PooledTxns
+	(*anypb.Any)(nil),            // 4: google.protobuf.Any
+	(*emptypb.Empty)(nil),        // 5: google.protobuf.Empty
 }
 var file_txpool_proto_v1_proto_depIdxs = []int32{
-	1, // 0: v1.Txn.raw:type_name -> google.protobuf.Any
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: v1.Txn.raw:type_name -> google.protobuf.Any
+	0, // 1: v1.PooledTxns.txns:type_name -> v1.Txn
+	5, // 2: v1.TxPoolSync.GetPooledHashes:input_type -> google.protobuf.Empty
+	2, // 3: v1.TxPoolSync.GetPooledTxns:input_type -> v1.GetPooledTxnsRequest
+	1, // 4: v1.TxPoolSync.GetPooledHashes:output_type -> v1.PooledHashes
+	3, // 5: v1.TxPoolSync.GetPooledTxns:output_type -> v1.PooledTxns
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_txpool_proto_v1_proto_init() }
@@ -130,6 +299,42 @@ func file_txpool_proto_v1_proto_init() {
 				return nil
 			}
 		}
+		file_txpool_proto_v1_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PooledHashes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txpool_proto_v1_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPooledTxnsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txpool_proto_v1_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PooledTxns); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -137,9 +342,9 @@ func file_txpool_proto_v1_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_txpool_proto_v1_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   4,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_txpool_proto_v1_proto_goTypes,
 		DependencyIndexes: file_txpool_proto_v1_proto_depIdxs,
@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// TxPoolSyncClient is the client API for TxPoolSync service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TxPoolSyncClient interface {
+	// Returns the hashes of the transactions of the pool
+	GetPooledHashes(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PooledHashes, error)
+	// Returns the transactions of the pool with the given hashes, skipping the ones not in the pool
+	GetPooledTxns(ctx context.Context, in *GetPooledTxnsRequest, opts ...grpc.CallOption) (*PooledTxns, error)
+}
+
+type txPoolSyncClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTxPoolSyncClient(cc grpc.ClientConnInterface) TxPoolSyncClient {
+	return &txPoolSyncClient{cc}
+}
+
+func (c *txPoolSyncClient) GetPooledHashes(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PooledHashes, error) {
+	out := new(PooledHashes)
+	err := c.cc.Invoke(ctx, "/v1.TxPoolSync/GetPooledHashes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txPoolSyncClient) GetPooledTxns(ctx context.Context, in *GetPooledTxnsRequest, opts ...grpc.CallOption) (*PooledTxns, error) {
+	out := new(PooledTxns)
+	err := c.cc.Invoke(ctx, "/v1.TxPoolSync/GetPooledTxns", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TxPoolSyncServer is the server API for TxPoolSync service.
+// All implementations must embed UnimplementedTxPoolSyncServer
+// for forward compatibility
+type TxPoolSyncServer interface {
+	// Returns the hashes of the transactions of the pool
+	GetPooledHashes(context.Context, *emptypb.Empty) (*PooledHashes, error)
+	// Returns the transactions of the pool with the given hashes, skipping the ones not in the pool
+	GetPooledTxns(context.Context, *GetPooledTxnsRequest) (*PooledTxns, error)
+	mustEmbedUnimplementedTxPoolSyncServer()
+}
+
+// UnimplementedTxPoolSyncServer must be embedded to have forward compatible implementations.
+type UnimplementedTxPoolSyncServer struct {
+}
+
+func (UnimplementedTxPoolSyncServer) GetPooledHashes(context.Context, *emptypb.Empty) (*PooledHashes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPooledHashes not implemented")
+}
+func (UnimplementedTxPoolSyncServer) GetPooledTxns(context.Context, *GetPooledTxnsRequest) (*PooledTxns, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPooledTxns not implemented")
+}
+func (UnimplementedTxPoolSyncServer) mustEmbedUnimplementedTxPoolSyncServer() {}
+
+// UnsafeTxPoolSyncServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TxPoolSyncServer will
+// result in compilation errors.
+type UnsafeTxPoolSyncServer interface {
+	mustEmbedUnimplementedTxPoolSyncServer()
+}
+
+func RegisterTxPoolSyncServer(s grpc.ServiceRegistrar, srv TxPoolSyncServer) {
+	s.RegisterService(&TxPoolSync_ServiceDesc, srv)
+}
+
+func _TxPoolSync_GetPooledHashes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxPoolSyncServer).GetPooledHashes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxPoolSync/GetPooledHashes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxPoolSyncServer).GetPooledHashes(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TxPoolSync_GetPooledTxns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPooledTxnsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxPoolSyncServer).GetPooledTxns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxPoolSync/GetPooledTxns",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxPoolSyncServer).GetPooledTxns(ctx, req.(*GetPooledTxnsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TxPoolSync_ServiceDesc is the grpc.ServiceDesc for TxPoolSync service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TxPoolSync_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.TxPoolSync",
+	HandlerType: (*TxPoolSyncServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPooledHashes",
+			Handler:    _TxPoolSync_GetPooledHashes_Handler,
+		},
+		{
+			MethodName: "GetPooledTxns",
+			Handler:    _TxPoolSync_GetPooledTxns_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "txpool/proto/v1.proto",
+}
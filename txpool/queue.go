@@ -61,6 +61,27 @@ func (q *accountQueue) prune(nonce uint64) (
 	return
 }
 
+// pruneFrom removes all transactions from the queue
+// with nonce higher than or equal to given.
+func (q *accountQueue) pruneFrom(nonce uint64) (
+	pruned []*types.Transaction,
+) {
+	kept := q.queue[:0]
+
+	for _, tx := range q.queue {
+		if tx.Nonce >= nonce {
+			pruned = append(pruned, tx)
+		} else {
+			kept = append(kept, tx)
+		}
+	}
+
+	q.queue = kept
+	heap.Init(&q.queue)
+
+	return
+}
+
 // clear removes all transactions from the queue.
 func (q *accountQueue) clear() (removed []*types.Transaction) {
 	// store txs
@@ -219,7 +240,7 @@ func (q *maxPriceQueue) Swap(i, j int) {
 }
 
 func (q *maxPriceQueue) Less(i, j int) bool {
-	return (*q)[i].GasPrice.Uint64() > (*q)[j].GasPrice.Uint64()
+	return (*q)[i].GasPrice.Cmp((*q)[j].GasPrice) > 0
 }
 
 func (q *maxPriceQueue) Push(x interface{}) {
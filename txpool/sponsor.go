@@ -0,0 +1,40 @@
+package txpool
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// checkFunds checks the sender can pay the transaction, and for a sponsored transaction
+// that the sender pays the value and the sponsor pays the fee
+func (p *TxPool) checkFunds(stateRoot types.Hash, tx *types.Transaction) error {
+	accountBalance, balanceErr := p.store.GetBalance(stateRoot, tx.From)
+	if balanceErr != nil {
+		return ErrInvalidAccountState
+	}
+
+	if tx.Sponsor == nil {
+		if accountBalance.Cmp(tx.Cost()) < 0 {
+			return ErrInsufficientFunds
+		}
+
+		return nil
+	}
+
+	if accountBalance.Cmp(tx.Value) < 0 {
+		return ErrInsufficientFunds
+	}
+
+	sponsorBalance, balanceErr := p.store.GetBalance(stateRoot, *tx.Sponsor)
+	if balanceErr != nil {
+		return ErrInvalidAccountState
+	}
+
+	fee := new(big.Int).Mul(tx.GasPrice, new(big.Int).SetUint64(tx.Gas))
+	if sponsorBalance.Cmp(fee) < 0 {
+		return ErrSponsorUnderfunded
+	}
+
+	return nil
+}
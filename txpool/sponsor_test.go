@@ -0,0 +1,88 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// balanceMockStore serves the balances of the accounts
+type balanceMockStore struct {
+	defaultMockStore
+
+	balances map[types.Address]int64
+}
+
+func (m balanceMockStore) GetBalance(_ types.Hash, addr types.Address) (*big.Int, error) {
+	return big.NewInt(m.balances[addr]), nil
+}
+
+func TestCheckFunds(t *testing.T) {
+	t.Parallel()
+
+	sponsor := types.StringToAddress("0x5b")
+
+	store := balanceMockStore{
+		defaultMockStore: NewDefaultMockStore(mockHeader),
+		balances: map[types.Address]int64{
+			addr1:   10,
+			sponsor: 1000,
+		},
+	}
+
+	newTx := func(value int64, gas uint64, sponsor *types.Address) *types.Transaction {
+		return &types.Transaction{
+			From:     addr1,
+			To:       &addr2,
+			Value:    big.NewInt(value),
+			Gas:      gas,
+			GasPrice: big.NewInt(10),
+			Sponsor:  sponsor,
+		}
+	}
+
+	tests := []struct {
+		name string
+		tx   *types.Transaction
+		err  error
+	}{
+		{
+			name: "the sender pays the fee",
+			tx:   newTx(10, 0, nil),
+		},
+		{
+			name: "the sender can't pay the fee",
+			tx:   newTx(10, 1, nil),
+			err:  ErrInsufficientFunds,
+		},
+		{
+			name: "the sponsor pays the fee",
+			tx:   newTx(10, 100, &sponsor),
+		},
+		{
+			name: "the sponsor can't pay the fee",
+			tx:   newTx(10, 101, &sponsor),
+			err:  ErrSponsorUnderfunded,
+		},
+		{
+			name: "the sender of a sponsored transaction can't pay the value",
+			tx:   newTx(11, 1, &sponsor),
+			err:  ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool := &TxPool{store: store}
+
+			assert.ErrorIs(t, pool.checkFunds(types.ZeroHash, tt.tx), tt.err)
+		})
+	}
+}
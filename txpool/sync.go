@@ -0,0 +1,266 @@
+package txpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/network/event"
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// poolSyncProto is the protocol the pooled transactions are exchanged with the connected peers on
+	poolSyncProto = "/txpool-sync/0.1"
+
+	// maxPooledHashesServe is the maximum number of hashes returned by GetPooledHashes
+	maxPooledHashesServe = 4096
+
+	// maxPooledTxnsRequest is the maximum number of transactions requested or returned at once
+	maxPooledTxnsRequest = 256
+
+	// pooledTxnsResponseLimit is the response size after which no more transactions are added,
+	// so a response exceeds it by at most one transaction
+	pooledTxnsResponseLimit = 2 * 1024 * 1024
+
+	// maxConcurrentPoolSyncs is the number of peers the pool is synced with at once,
+	// the peers connecting while it's reached are skipped
+	maxConcurrentPoolSyncs = 4
+
+	// poolSyncTimeout is the time the pool is synced with a peer for
+	poolSyncTimeout = 30 * time.Second
+)
+
+var (
+	errTooManyPooledItems = errors.New("peer returned more pooled items than allowed")
+	errUnrequestedTx      = errors.New("peer returned a transaction which wasn't requested")
+)
+
+// syncNetwork is the networking the pooled transactions are exchanged through
+type syncNetwork interface {
+	// RegisterProtocol registers gRPC service
+	RegisterProtocol(string, network.Protocol)
+	// NewProtoConnection opens up a new stream on the set protocol to the peer,
+	// and returns a reference to the connection
+	NewProtoConnection(protocol string, peerID peer.ID) (*grpc.ClientConn, error)
+	// SubscribeFn runs the handler on the peer events until the context is done
+	SubscribeFn(ctx context.Context, handler func(*event.PeerEvent)) error
+}
+
+// poolSyncService serves the hashes and the transactions of the pool to the peers syncing their pool
+type poolSyncService struct {
+	proto.UnimplementedTxPoolSyncServer
+
+	pool *TxPool
+}
+
+// GetPooledHashes is a gRPC endpoint to return the hashes of the transactions of the pool
+func (s *poolSyncService) GetPooledHashes(context.Context, *emptypb.Empty) (*proto.PooledHashes, error) {
+	hashes := s.pool.index.hashes(maxPooledHashesServe)
+
+	res := &proto.PooledHashes{
+		Hashes: make([][]byte, len(hashes)),
+	}
+
+	for i, hash := range hashes {
+		res.Hashes[i] = hash.Bytes()
+	}
+
+	return res, nil
+}
+
+// GetPooledTxns is a gRPC endpoint to return the transactions of the pool in the order of the hashes,
+// skipping the ones no longer in the pool. The transactions end at the response limits
+func (s *poolSyncService) GetPooledTxns(
+	_ context.Context,
+	req *proto.GetPooledTxnsRequest,
+) (*proto.PooledTxns, error) {
+	var (
+		txns = make([]*proto.Txn, 0)
+		size = 0
+	)
+
+	for _, raw := range req.Hashes {
+		if len(txns) >= maxPooledTxnsRequest || size >= pooledTxnsResponseLimit {
+			break
+		}
+
+		tx, ok := s.pool.index.get(types.BytesToHash(raw))
+		if !ok {
+			continue
+		}
+
+		txn := marshalGossipTx(tx)
+		txns = append(txns, txn)
+		size += len(txn.Raw.Value)
+	}
+
+	return &proto.PooledTxns{
+		Txns: txns,
+	}, nil
+}
+
+// startPoolSync syncs the pool with the peers as they connect, the peers doing the same with the pool of the node
+func (p *TxPool) startPoolSync(ctx context.Context) {
+	syncing := make(chan struct{}, maxConcurrentPoolSyncs)
+
+	err := p.syncNetwork.SubscribeFn(ctx, func(e *event.PeerEvent) {
+		if e.Type != event.PeerConnected {
+			return
+		}
+
+		select {
+		case syncing <- struct{}{}:
+		default:
+			p.logger.Debug("too many pool syncs in progress, skip peer", "peer", e.PeerID)
+
+			return
+		}
+
+		go func() {
+			defer func() {
+				<-syncing
+			}()
+
+			if err := p.syncPoolWithPeer(ctx, e.PeerID); err != nil {
+				p.logger.Debug("failed to sync pool with peer", "peer", e.PeerID, "err", err)
+			}
+		}()
+	})
+	if err != nil {
+		p.logger.Error("failed to subscribe to peer events", "err", err)
+	}
+}
+
+// syncPoolWithPeer fetches the transactions of the peer's pool missing from the pool
+func (p *TxPool) syncPoolWithPeer(ctx context.Context, peerID peer.ID) error {
+	conn, err := p.syncNetwork.NewProtoConnection(poolSyncProto, peerID)
+	if err != nil {
+		return fmt.Errorf("failed to open a stream, err %w", err)
+	}
+
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, poolSyncTimeout)
+	defer cancel()
+
+	added, err := p.fetchPooledTxns(ctx, proto.NewTxPoolSyncClient(conn), peerID)
+	if added > 0 {
+		p.logger.Debug("synced pool with peer", "peer", peerID, "added", added)
+	}
+
+	return err
+}
+
+// fetchPooledTxns requests the hashes of the peer's pool, then the transactions missing from the pool,
+// which are added as gossiped ones whether the node is sealing or not. It returns the number of transactions added
+func (p *TxPool) fetchPooledTxns(ctx context.Context, clt proto.TxPoolSyncClient, peerID peer.ID) (int, error) {
+	res, err := clt.GetPooledHashes(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(res.Hashes) > maxPooledHashesServe {
+		p.penalizePeer(peerID, network.PenaltyInvalidResponse)
+
+		return 0, fmt.Errorf("%w: %d hashes", errTooManyPooledItems, len(res.Hashes))
+	}
+
+	missing := make([][]byte, 0, len(res.Hashes))
+
+	for _, raw := range res.Hashes {
+		if _, ok := p.index.get(types.BytesToHash(raw)); !ok {
+			missing = append(missing, raw)
+		}
+	}
+
+	added := 0
+
+	for len(missing) > 0 {
+		batch := missing
+		if len(batch) > maxPooledTxnsRequest {
+			batch = batch[:maxPooledTxnsRequest]
+		}
+
+		res, err := clt.GetPooledTxns(ctx, &proto.GetPooledTxnsRequest{Hashes: batch})
+		if err != nil {
+			return added, err
+		}
+
+		if len(res.Txns) > len(batch) {
+			p.penalizePeer(peerID, network.PenaltyInvalidResponse)
+
+			return added, fmt.Errorf("%w: %d transactions of %d", errTooManyPooledItems, len(res.Txns), len(batch))
+		}
+
+		// the peer may return the transactions of only the first hashes
+		if len(res.Txns) == 0 {
+			return added, nil
+		}
+
+		missing = missing[len(batch):]
+
+		n, err := p.addPooledTxns(res.Txns, batch, peerID)
+		added += n
+
+		if err != nil {
+			return added, err
+		}
+	}
+
+	return added, nil
+}
+
+// addPooledTxns adds the transactions returned by the peer for the requested hashes
+func (p *TxPool) addPooledTxns(txns []*proto.Txn, requested [][]byte, peerID peer.ID) (int, error) {
+	hashes := make(map[types.Hash]struct{}, len(requested))
+	for _, raw := range requested {
+		hashes[types.BytesToHash(raw)] = struct{}{}
+	}
+
+	added := 0
+
+	for _, txn := range txns {
+		if err := p.validateGossipTx(txn); err != nil {
+			p.penalizePeer(peerID, network.PenaltyInvalidTx)
+
+			return added, err
+		}
+
+		tx, err := unmarshalGossipTx(txn.Raw)
+		if err != nil {
+			return added, err
+		}
+
+		tx.ComputeHash()
+
+		if _, ok := hashes[tx.Hash]; !ok {
+			p.penalizePeer(peerID, network.PenaltyInvalidResponse)
+
+			return added, fmt.Errorf("%w: %s", errUnrequestedTx, tx.Hash)
+		}
+
+		if err := p.addTx(gossip, tx); err != nil {
+			if isInvalidTx(err) {
+				p.penalizePeer(peerID, network.PenaltyInvalidTx)
+			}
+
+			if !errors.Is(err, ErrAlreadyKnown) {
+				p.logger.Debug("failed to add pooled tx", "err", err, "hash", tx.Hash)
+			}
+
+			continue
+		}
+
+		added++
+	}
+
+	return added, nil
+}
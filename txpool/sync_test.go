@@ -0,0 +1,131 @@
+package txpool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// directSyncClient calls the pool sync service of a pool directly, optionally tampering with its transactions
+type directSyncClient struct {
+	service *poolSyncService
+	tamper  func(txns []*proto.Txn)
+}
+
+func (c *directSyncClient) GetPooledHashes(
+	ctx context.Context,
+	in *emptypb.Empty,
+	_ ...grpc.CallOption,
+) (*proto.PooledHashes, error) {
+	return c.service.GetPooledHashes(ctx, in)
+}
+
+func (c *directSyncClient) GetPooledTxns(
+	ctx context.Context,
+	in *proto.GetPooledTxnsRequest,
+	_ ...grpc.CallOption,
+) (*proto.PooledTxns, error) {
+	res, err := c.service.GetPooledTxns(ctx, in)
+	if err == nil && c.tamper != nil {
+		c.tamper(res.Txns)
+	}
+
+	return res, err
+}
+
+func newStartedTestPool(t *testing.T, signer crypto.TxSigner) *TxPool {
+	t.Helper()
+
+	pool, err := newTestPool()
+	require.NoError(t, err)
+
+	pool.SetSigner(signer)
+	pool.Start()
+
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestFetchPooledTxns(t *testing.T) {
+	t.Parallel()
+
+	key, _ := tests.GenerateKeyAndAddr(t)
+	signer := crypto.NewEIP155Signer(uint64(100))
+
+	txs := make([]*types.Transaction, 3)
+
+	for i := range txs {
+		signedTx, err := signer.SignTx(newTx(types.ZeroAddress, uint64(i), 1), key)
+		require.NoError(t, err)
+
+		signedTx.ComputeHash()
+		txs[i] = signedTx
+	}
+
+	source := newStartedTestPool(t, signer)
+
+	for _, tx := range txs {
+		require.NoError(t, source.addTx(local, tx.Copy()))
+	}
+
+	t.Run("the missing transactions are fetched", func(t *testing.T) {
+		t.Parallel()
+
+		// the pool is synced whether the node is sealing or not
+		pool := newStartedTestPool(t, signer)
+		require.NoError(t, pool.addTx(local, txs[0].Copy()))
+
+		added, err := pool.fetchPooledTxns(
+			context.Background(),
+			&directSyncClient{service: &poolSyncService{pool: source}},
+			"A",
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 2, added)
+
+		for _, tx := range txs {
+			_, ok := pool.index.get(tx.Hash)
+			assert.True(t, ok)
+		}
+	})
+
+	t.Run("the unrequested transactions are reported", func(t *testing.T) {
+		t.Parallel()
+
+		pool := newStartedTestPool(t, signer)
+
+		reported := map[peer.ID]network.PeerPenalty{}
+		pool.reportPeer = func(id peer.ID, penalty network.PeerPenalty) {
+			reported[id] = penalty
+		}
+
+		other, err := signer.SignTx(newTx(types.ZeroAddress, 5, 1), key)
+		require.NoError(t, err)
+
+		_, err = pool.fetchPooledTxns(
+			context.Background(),
+			&directSyncClient{
+				service: &poolSyncService{pool: source},
+				tamper: func(txns []*proto.Txn) {
+					txns[0] = &proto.Txn{Raw: &any.Any{Value: other.MarshalRLP()}}
+				},
+			},
+			"B",
+		)
+		assert.ErrorIs(t, err, errUnrequestedTx)
+		assert.Equal(t, map[peer.ID]network.PeerPenalty{"B": network.PenaltyInvalidResponse}, reported)
+	})
+}
@@ -1,6 +1,7 @@
 package txpool
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -9,15 +10,16 @@ import (
 	goAtomic "sync/atomic"
 
 	"github.com/armon/go-metrics"
-	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"go.uber.org/atomic"
-	"google.golang.org/grpc"
+	rawGrpc "google.golang.org/grpc"
+	rawProto "google.golang.org/protobuf/proto"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/network/grpc"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -47,14 +49,20 @@ var (
 	ErrInvalidSender           = errors.New("invalid sender")
 	ErrTxPoolOverflow          = errors.New("txpool is full")
 	ErrUnderpriced             = errors.New("transaction underpriced")
+	ErrBelowBaseFee            = errors.New("gas price below the base fee")
+	ErrSenderNotAllowed        = errors.New("sender not allowed by the allowlist")
+	ErrDeployerNotAllowed      = errors.New("deployer not allowed by the allowlist")
 	ErrNonceTooLow             = errors.New("nonce too low")
 	ErrInsufficientFunds       = errors.New("insufficient funds for gas * price + value")
+	ErrSponsorUnderfunded      = errors.New("insufficient sponsor funds for gas * price")
 	ErrInvalidAccountState     = errors.New("invalid account state")
 	ErrAlreadyKnown            = errors.New("already known")
 	ErrOversizedData           = errors.New("oversized data")
 	ErrMaxEnqueuedLimitReached = errors.New("maximum number of enqueued transactions reached")
 	ErrRejectFutureTx          = errors.New("rejected future tx due to low slots")
 	ErrSmartContractRestricted = errors.New("smart contract deployment restricted")
+	ErrTxTypeNotSupported      = types.ErrTxTypeNotSupported
+	ErrInvalidChainID          = errors.New("invalid chain id")
 )
 
 // indicates origin of a transaction
@@ -84,6 +92,7 @@ type store interface {
 	Header() *types.Header
 	GetNonce(root types.Hash, addr types.Address) uint64
 	GetBalance(root types.Hash, addr types.Address) (*big.Int, error)
+	GetStorage(root types.Hash, addr types.Address, slot types.Hash) types.Hash
 	GetBlockByHash(types.Hash, bool) (*types.Block, bool)
 }
 
@@ -92,10 +101,23 @@ type signer interface {
 }
 
 type Config struct {
+	ChainID             uint64
 	PriceLimit          uint64
 	MaxSlots            uint64
 	MaxAccountEnqueued  uint64
 	DeploymentWhitelist []types.Address
+	BundlerWhitelist    []types.Address
+
+	// SystemSenders are the senders of the system lane, set by the chain
+	SystemSenders []types.Address
+	// PrioritySenders are the senders of the priority lane, allowlisted by the node
+	PrioritySenders []types.Address
+	// PriorityGasPercent is the share of the block gas limit reserved to the priority lane
+	PriorityGasPercent uint64
+	// BaseFee is the lowest gas price the chain accepts from the nativeToken fork, set by the native token economics
+	BaseFee uint64
+	// Allowlist restricts the senders and the deployers of the chain
+	Allowlist *chain.Allowlist
 }
 
 /* All requests are passed to the main loop
@@ -138,27 +160,54 @@ type promoteRequest struct {
 type TxPool struct {
 	logger hclog.Logger
 	signer signer
-	forks  chain.ForksInTime
 	store  store
 
+	// forks is the schedule of the forks, the transactions are validated
+	// against the forks of the next block
+	forks *chain.Forks
+
 	// map of all accounts registered by the pool
 	accounts accountsMap
 
-	// all the primaries sorted by max gas price
-	executables *pricedQueue
+	// all the primaries sorted by their lanes, then by max gas price
+	lanes lanes
 
 	// lookup map keeping track of all
 	// transactions present in the pool
 	index lookupMap
 
+	// transactions of the pool which expire at a block
+	expirations expirations
+
 	// networking stack
 	topic *network.Topic
+	// reportPeer lowers the reputation of the peer which gossiped an invalid transaction, nil without networking
+	reportPeer func(peer.ID, network.PeerPenalty)
+	// syncNetwork exchanges the pooled transactions with the peers as they connect, nil without networking
+	syncNetwork syncNetwork
+	// syncStream serves the pooled transactions to the peers
+	syncStream *grpc.GrpcStream
+	// cancelPoolSync stops the exchange of the pooled transactions
+	cancelPoolSync context.CancelFunc
 
 	// gauge for measuring pool capacity
 	gauge slotGauge
+	// maxSlots caps the limit of the gauge when it is resized by the memory budget
+	maxSlots uint64
+
+	// priceLimit is a lower threshold for gas price,
+	// which can be changed at runtime
+	priceLimit atomic.Uint64
+
+	// baseFee is the lowest gas price accepted by the chain from the nativeToken fork,
+	// unlike the price limit it can't be changed
+	baseFee uint64
+
+	// allowlist restricts the senders and the deployers, nil if the chain doesn't
+	allowlist *chain.Allowlist
 
-	// priceLimit is a lower threshold for gas price
-	priceLimit uint64
+	// chainID is the chain ID the gossiped transactions have to be signed for
+	chainID uint64
 
 	// channels on which the pool's event loop
 	// does dispatching/handling requests.
@@ -179,6 +228,9 @@ type TxPool struct {
 	// deploymentWhitelist map
 	deploymentWhitelist deploymentWhitelist
 
+	// bundlerWhitelist holds the senders of the conditional transactions
+	bundlerWhitelist bundlerWhitelist
+
 	// indicates which txpool operator commands should be implemented
 	proto.UnimplementedTxnPoolOperatorServer
 
@@ -225,21 +277,25 @@ func newDeploymentWhitelist(deploymentWhitelistRaw []types.Address) deploymentWh
 // NewTxPool returns a new pool for processing incoming transactions.
 func NewTxPool(
 	logger hclog.Logger,
-	forks chain.ForksInTime,
+	forks *chain.Forks,
 	store store,
-	grpcServer *grpc.Server,
-	network *network.Server,
+	grpcServer *rawGrpc.Server,
+	networkServer *network.Server,
 	config *Config,
 ) (*TxPool, error) {
 	pool := &TxPool{
 		logger:      logger.Named("txpool"),
 		forks:       forks,
 		store:       store,
-		executables: newPricedQueue(),
+		lanes:       newLanes(config),
 		accounts:    accountsMap{maxEnqueuedLimit: config.MaxAccountEnqueued},
 		index:       lookupMap{all: make(map[types.Hash]*types.Transaction)},
+		expirations: expirations{txs: make(map[types.Hash]*types.Transaction)},
 		gauge:       slotGauge{height: 0, max: config.MaxSlots},
-		priceLimit:  config.PriceLimit,
+		maxSlots:    config.MaxSlots,
+		chainID:     config.ChainID,
+		baseFee:     config.BaseFee,
+		allowlist:   config.Allowlist,
 
 		//	main loop channels
 		enqueueReqCh: make(chan enqueueRequest),
@@ -248,25 +304,40 @@ func NewTxPool(
 		shutdownCh:   make(chan struct{}),
 	}
 
+	pool.priceLimit.Store(config.PriceLimit)
+
 	// Attach the event manager
 	pool.eventManager = newEventManager(pool.logger)
 
-	if network != nil {
+	if networkServer != nil {
 		// subscribe to the gossip protocol
-		topic, err := network.NewTopic(topicNameV1, &proto.Txn{})
+		topic, err := networkServer.NewTopic(topicNameV1, &proto.Txn{})
 		if err != nil {
 			return nil, err
 		}
 
+		// the invalid transactions are rejected before they're relayed
+		topic.SetValidator(network.PenaltyInvalidTx, pool.validateGossipTx)
+
 		if subscribeErr := topic.Subscribe(pool.addGossipTx); subscribeErr != nil {
 			return nil, fmt.Errorf("unable to subscribe to gossip topic, %w", subscribeErr)
 		}
 
 		pool.topic = topic
+		pool.reportPeer = networkServer.ReportPeer
+
+		// serve the pooled transactions to the connecting peers
+		pool.syncStream = grpc.NewGrpcStream()
+		proto.RegisterTxPoolSyncServer(pool.syncStream.GrpcServer(), &poolSyncService{pool: pool})
+		pool.syncStream.Serve()
+		networkServer.RegisterProtocol(poolSyncProto, pool.syncStream)
+
+		pool.syncNetwork = networkServer
 	}
 
-	// initialize deployment whitelist
+	// initialize the whitelists
 	pool.deploymentWhitelist = newDeploymentWhitelist(config.DeploymentWhitelist)
+	pool.bundlerWhitelist = newBundlerWhitelist(config.BundlerWhitelist)
 
 	if grpcServer != nil {
 		proto.RegisterTxnPoolOperatorServer(grpcServer, pool)
@@ -316,10 +387,29 @@ func (p *TxPool) Start() {
 			}
 		}
 	}()
+
+	// fetch the transactions of the pools of the connecting peers
+	if p.syncNetwork != nil {
+		var ctx context.Context
+
+		ctx, p.cancelPoolSync = context.WithCancel(context.Background())
+
+		p.startPoolSync(ctx)
+	}
 }
 
 // Close shuts down the pool's main loop.
 func (p *TxPool) Close() {
+	if p.cancelPoolSync != nil {
+		p.cancelPoolSync()
+	}
+
+	if p.syncStream != nil {
+		if err := p.syncStream.Close(); err != nil {
+			p.logger.Error("failed to close pool sync stream", "err", err)
+		}
+	}
+
 	p.eventManager.Close()
 	p.shutdownCh <- struct{}{}
 }
@@ -335,6 +425,12 @@ func (p *TxPool) SetSealing(sealing bool) {
 	p.sealing.Store(sealing)
 }
 
+// SetPriceLimit sets the lower threshold for the gas price of the new transactions,
+// the transactions already in the pool are kept
+func (p *TxPool) SetPriceLimit(priceLimit uint64) {
+	p.priceLimit.Store(priceLimit)
+}
+
 // sealing returns the current set sealing flag
 func (p *TxPool) getSealing() bool {
 	return p.sealing.Load()
@@ -352,13 +448,7 @@ func (p *TxPool) AddTx(tx *types.Transaction) error {
 	// broadcast the transaction only if a topic
 	// subscription is present
 	if p.topic != nil {
-		tx := &proto.Txn{
-			Raw: &any.Any{
-				Value: tx.MarshalRLP(),
-			},
-		}
-
-		if err := p.topic.Publish(tx); err != nil {
+		if err := p.topic.Publish(marshalGossipTx(tx)); err != nil {
 			p.logger.Error("failed to topic tx", "err", err)
 		}
 	}
@@ -370,8 +460,8 @@ func (p *TxPool) AddTx(tx *types.Transaction) error {
 // ready for execution. (primaries)
 func (p *TxPool) Prepare() {
 	// clear from previous round
-	if p.executables.length() != 0 {
-		p.executables.clear()
+	if p.lanes.length() != 0 {
+		p.lanes.clear()
 	}
 
 	// fetch primary from each account
@@ -379,12 +469,13 @@ func (p *TxPool) Prepare() {
 
 	// push primaries to the executables queue
 	for _, tx := range primaries {
-		p.executables.push(tx)
+		p.lanes.push(tx)
 	}
 }
 
 // Peek returns the best-price selected
-// transaction ready for execution.
+// transaction ready for execution,
+// of the first lane holding any.
 func (p *TxPool) Peek() *types.Transaction {
 	// Popping the executables queue
 	// does not remove the actual tx
@@ -392,7 +483,7 @@ func (p *TxPool) Peek() *types.Transaction {
 	// The executables queue just provides
 	// insight into which account has the
 	// highest priced tx (head of promoted queue)
-	return p.executables.pop()
+	return p.lanes.pop()
 }
 
 // Pop removes the given transaction from the
@@ -420,13 +511,18 @@ func (p *TxPool) Pop(tx *types.Transaction) {
 
 	// update executables
 	if tx := account.promoted.peek(); tx != nil {
-		p.executables.push(tx)
+		p.lanes.push(tx)
 	}
 }
 
 // Drop clears the entire account associated with the given transaction
 // and reverts its next (expected) nonce.
 func (p *TxPool) Drop(tx *types.Transaction) {
+	p.drop(tx, dropFailed)
+}
+
+// drop clears the account of the transaction, counting the dropped transactions with the reason
+func (p *TxPool) drop(tx *types.Transaction, reason string) {
 	// fetch associated account
 	account := p.accounts.get(tx.From)
 
@@ -465,6 +561,8 @@ func (p *TxPool) Drop(tx *types.Transaction) {
 	dropped = account.enqueued.clear()
 	clearAccountQueue(dropped)
 
+	countDropped(reason, droppedCount)
+
 	p.eventManager.signalEvent(proto.EventType_DROPPED, tx.Hash)
 	p.logger.Debug("dropped account txs",
 		"num", droppedCount,
@@ -484,7 +582,7 @@ func (p *TxPool) Demote(tx *types.Transaction) {
 			"addr", tx.From.String(),
 		)
 
-		p.Drop(tx)
+		p.drop(tx, dropDemoted)
 
 		// reset the demotions counter
 		account.resetDemotions()
@@ -509,6 +607,52 @@ func (p *TxPool) ResetWithHeaders(headers ...*types.Header) {
 	p.processEvent(e)
 }
 
+// Rewind drops the transactions of all the accounts, and aligns their nonces with the state of the header.
+// It's used once the chain has been rewound to the header, as the nonces of the accounts only move forward
+// on the new blocks
+func (p *TxPool) Rewind(header *types.Header) {
+	p.accounts.Range(func(key, value interface{}) bool {
+		addr, ok := key.(types.Address)
+		if !ok {
+			return true
+		}
+
+		account, ok := value.(*account)
+		if !ok {
+			return true
+		}
+
+		account.promoted.lock(true)
+		account.enqueued.lock(true)
+
+		// the cleared queues keep their backing arrays, so the dropped transactions are copied out
+		promoted := account.promoted.clear()
+		dropped := make([]*types.Transaction, 0, len(promoted)+int(account.enqueued.length()))
+		dropped = append(dropped, promoted...)
+		dropped = append(dropped, account.enqueued.clear()...)
+
+		account.setNonce(p.store.GetNonce(header.StateRoot, addr))
+		account.resetDemotions()
+
+		account.enqueued.unlock()
+		account.promoted.unlock()
+
+		if len(dropped) == 0 {
+			return true
+		}
+
+		p.index.remove(dropped...)
+		p.gauge.decrease(slotsRequired(dropped...))
+		p.updatePending(-1 * int64(len(promoted)))
+
+		countDropped(dropRewind, len(dropped))
+
+		p.eventManager.signalEvent(proto.EventType_DROPPED, toHash(dropped...)...)
+
+		return true
+	})
+}
+
 // processEvent collects the latest nonces for each account containted
 // in the received event. Resets all known accounts with the new nonce.
 func (p *TxPool) processEvent(event *blockchain.Event) {
@@ -586,12 +730,28 @@ func (p *TxPool) processEvent(event *blockchain.Event) {
 	// reset accounts with the new state
 	p.resetAccounts(stateNonces)
 
+	// prune the transactions which can't be included anymore
+	p.pruneExpired(p.store.Header().Number)
+
 	if !p.getSealing() {
 		// only non-validator cleanup inactive accounts
 		p.updateAccountSkipsCounts(stateNonces)
 	}
 }
 
+// nextForks returns the forks active in the next block, the one the transactions of the pool are included in.
+// Its timestamp isn't known yet, the block is proposed from now on
+func (p *TxPool) nextForks() chain.ForksInTime {
+	head := p.store.Header()
+
+	timestamp := uint64(time.Now().Unix())
+	if timestamp <= head.Timestamp {
+		timestamp = head.Timestamp + 1
+	}
+
+	return p.forks.At(head.Number+1, timestamp)
+}
+
 // validateTx ensures the transaction conforms to specific
 // constraints before entering the pool.
 func (p *TxPool) validateTx(tx *types.Transaction) error {
@@ -600,6 +760,17 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 		return ErrOversizedData
 	}
 
+	forks := p.nextForks()
+
+	// Check if the transaction type is enabled
+	if tx.Type == types.AccessListTx && !forks.EIP2930 {
+		return ErrTxTypeNotSupported
+	}
+
+	if tx.Type == types.SponsoredTx && !forks.Sponsorship {
+		return ErrTxTypeNotSupported
+	}
+
 	// Check if the transaction has a strictly positive value
 	if tx.Value.Sign() < 0 {
 		return ErrNegativeValue
@@ -631,30 +802,45 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 	}
 
 	// Reject underpriced transactions
-	if tx.IsUnderpriced(p.priceLimit) {
+	if tx.IsUnderpriced(p.priceLimit.Load()) {
 		return ErrUnderpriced
 	}
 
+	// Reject the transactions which can't pay the base fee of the chain
+	if forks.NativeToken && tx.IsUnderpriced(p.baseFee) {
+		return ErrBelowBaseFee
+	}
+
+	// Check if the sender can send the transaction with its conditions
+	if err := p.validateConditions(tx); err != nil {
+		return err
+	}
+
+	// Reject the transactions which can't be included in the next block
+	if tx.IsExpired(p.store.Header().Number + 1) {
+		return ErrTxExpired
+	}
+
 	// Grab the state root for the latest block
 	stateRoot := p.store.Header().StateRoot
 
+	// Check if the sender holds the roles of the allowlist
+	if err := p.checkAllowlist(stateRoot, forks, tx); err != nil {
+		return err
+	}
+
 	// Check nonce ordering
 	if p.store.GetNonce(stateRoot, tx.From) > tx.Nonce {
 		return ErrNonceTooLow
 	}
 
-	accountBalance, balanceErr := p.store.GetBalance(stateRoot, tx.From)
-	if balanceErr != nil {
-		return ErrInvalidAccountState
-	}
-
-	// Check if the sender has enough funds to execute the transaction
-	if accountBalance.Cmp(tx.Cost()) < 0 {
-		return ErrInsufficientFunds
+	// Check if the sender, or the sponsor, has enough funds to execute the transaction
+	if err := p.checkFunds(stateRoot, tx); err != nil {
+		return err
 	}
 
 	// Make sure the transaction has more gas than the basic transaction fee
-	intrinsicGas, err := state.TransactionGasCost(tx, p.forks.Homestead, p.forks.Istanbul)
+	intrinsicGas, err := state.TransactionGasCost(tx, forks.Homestead, forks.Istanbul)
 	if err != nil {
 		return err
 	}
@@ -703,6 +889,8 @@ func (p *TxPool) pruneAccountsWithNonceHoles() {
 			p.index.remove(removed...)
 			p.gauge.decrease(slotsRequired(removed...))
 
+			countDropped(dropNonceHole, len(removed))
+
 			return true
 		},
 	)
@@ -712,7 +900,11 @@ func (p *TxPool) pruneAccountsWithNonceHoles() {
 // for all new transactions. If the call is
 // successful, an account is created for this address
 // (only once) and an enqueueRequest is signaled.
-func (p *TxPool) addTx(origin txOrigin, tx *types.Transaction) error {
+func (p *TxPool) addTx(origin txOrigin, tx *types.Transaction) (err error) {
+	defer func() {
+		countAdded(origin, err)
+	}()
+
 	p.logger.Debug("add tx",
 		"origin", origin.String(),
 		"hash", tx.Hash.String(),
@@ -734,7 +926,7 @@ func (p *TxPool) addTx(origin txOrigin, tx *types.Transaction) error {
 	}
 
 	// check for overflow
-	if p.gauge.read()+slotsRequired(tx) > p.gauge.max {
+	if p.gauge.read()+slotsRequired(tx) > p.gauge.limit() {
 		return ErrTxPoolOverflow
 	}
 
@@ -745,6 +937,10 @@ func (p *TxPool) addTx(origin txOrigin, tx *types.Transaction) error {
 		return ErrAlreadyKnown
 	}
 
+	if tx.ValidUntil != 0 {
+		p.expirations.add(tx)
+	}
+
 	// initialize account for this address once
 	p.createAccountOnce(tx.From)
 
@@ -771,6 +967,7 @@ func (p *TxPool) handleEnqueueRequest(req enqueueRequest) {
 		p.logger.Error("enqueue request", "err", err)
 
 		p.index.remove(tx)
+		countDropped(dropEnqueueFailed, 1)
 
 		return
 	}
@@ -806,13 +1003,14 @@ func (p *TxPool) handlePromoteRequest(req promoteRequest) {
 
 	// update metrics
 	p.updatePending(int64(len(promoted)))
+	countDropped(dropStaleNonce, len(pruned))
 
 	p.eventManager.signalEvent(proto.EventType_PROMOTED, toHash(promoted...)...)
 }
 
 // addGossipTx handles receiving transactions
 // gossiped by the network.
-func (p *TxPool) addGossipTx(obj interface{}, _ peer.ID) {
+func (p *TxPool) addGossipTx(obj interface{}, from peer.ID) {
 	if !p.getSealing() {
 		return
 	}
@@ -831,11 +1029,11 @@ func (p *TxPool) addGossipTx(obj interface{}, _ peer.ID) {
 		return
 	}
 
-	tx := new(types.Transaction)
-
 	// decode tx
-	if err := tx.UnmarshalRLP(raw.Raw.Value); err != nil {
+	tx, err := unmarshalGossipTx(raw.Raw)
+	if err != nil {
 		p.logger.Error("failed to decode broadcast tx", "err", err)
+		p.penalizeGossip(from)
 
 		return
 	}
@@ -849,7 +1047,114 @@ func (p *TxPool) addGossipTx(obj interface{}, _ peer.ID) {
 		}
 
 		p.logger.Error("failed to add broadcast tx", "err", err, "hash", tx.Hash.String())
+
+		if isInvalidTx(err) {
+			p.penalizeGossip(from)
+		}
+	}
+}
+
+// validateGossipTx runs the checks of a gossiped transaction which don't depend on the state,
+// so the transactions which can never be included aren't relayed to the other peers
+func (p *TxPool) validateGossipTx(obj rawProto.Message) error {
+	raw, ok := obj.(*proto.Txn)
+	if !ok || raw.Raw == nil {
+		return errors.New("malformed gossip transaction message")
+	}
+
+	tx, err := unmarshalGossipTx(raw.Raw)
+	if err != nil {
+		return err
+	}
+
+	if uint64(len(tx.MarshalRLP())) > txMaxSize {
+		return ErrOversizedData
+	}
+
+	forks := p.nextForks()
+
+	if tx.Type == types.AccessListTx && !forks.EIP2930 {
+		return ErrTxTypeNotSupported
+	}
+
+	if tx.Type == types.SponsoredTx && !forks.Sponsorship {
+		return ErrTxTypeNotSupported
+	}
+
+	if tx.Value.Sign() < 0 {
+		return ErrNegativeValue
+	}
+
+	if chainID, protected := txChainID(tx); protected && (!chainID.IsUint64() || chainID.Uint64() != p.chainID) {
+		return fmt.Errorf("%w: %s", ErrInvalidChainID, chainID)
+	}
+
+	intrinsicGas, err := state.TransactionGasCost(tx, forks.Homestead, forks.Istanbul)
+	if err != nil {
+		return err
+	}
+
+	if tx.Gas < intrinsicGas {
+		return ErrIntrinsicGas
 	}
+
+	if tx.Gas > p.store.Header().GasLimit {
+		return ErrBlockLimitExceeded
+	}
+
+	return nil
+}
+
+// txChainID returns the chain ID the transaction is signed for,
+// and false for the legacy transactions signed before EIP-155
+func txChainID(tx *types.Transaction) (*big.Int, bool) {
+	if tx.IsTyped() {
+		if tx.ChainID == nil {
+			return big.NewInt(0), true
+		}
+
+		return tx.ChainID, true
+	}
+
+	// v = CHAIN_ID * 2 + 35 + {0, 1} for the protected transactions
+	if tx.V == nil || tx.V.Cmp(big.NewInt(35)) < 0 {
+		return nil, false
+	}
+
+	chainID := new(big.Int).Sub(tx.V, big.NewInt(35))
+
+	return chainID.Rsh(chainID, 1), true
+}
+
+// penalizeGossip reports the peer which gossiped a transaction that can never be included
+func (p *TxPool) penalizeGossip(from peer.ID) {
+	p.penalizePeer(from, network.PenaltyInvalidTx)
+}
+
+// penalizePeer reports the peer for its misbehavior, if the pool is networked
+func (p *TxPool) penalizePeer(from peer.ID, penalty network.PeerPenalty) {
+	if p.reportPeer != nil {
+		p.reportPeer(from, penalty)
+	}
+}
+
+// isInvalidTx checks if the transaction was rejected for a reason which doesn't depend
+// on the state of the pool or of the chain, so it can never be included
+func isInvalidTx(err error) bool {
+	for _, invalidErr := range []error{
+		ErrExtractSignature,
+		ErrInvalidSender,
+		ErrNegativeValue,
+		ErrIntrinsicGas,
+		ErrOversizedData,
+		ErrTxTypeNotSupported,
+	} {
+		if errors.Is(err, invalidErr) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // resetAccounts updates existing accounts with the new nonce and prunes stale transactions.
@@ -886,6 +1191,8 @@ func (p *TxPool) resetAccounts(stateNonces map[types.Address]uint64) {
 	cleanup := func(stale []*types.Transaction) {
 		p.index.remove(stale...)
 		p.gauge.decrease(slotsRequired(stale...))
+
+		countDropped(dropStaleNonce, len(stale))
 	}
 
 	// prune pool state
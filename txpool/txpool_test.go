@@ -11,11 +11,15 @@ import (
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -81,11 +85,12 @@ func newTestPoolWithSlots(maxSlots uint64, mockStore ...store) (*TxPool, error)
 
 	return NewTxPool(
 		hclog.NewNullLogger(),
-		forks.At(0),
+		forks,
 		storeToUse,
 		nil,
 		nil,
 		&Config{
+			ChainID:             100,
 			PriceLimit:          defaultPriceLimit,
 			MaxSlots:            maxSlots,
 			MaxAccountEnqueued:  defaultMaxAccountEnqueued,
@@ -195,7 +200,7 @@ func TestAddTxErrors(t *testing.T) {
 	t.Run("ErrUnderpriced", func(t *testing.T) {
 		t.Parallel()
 		pool := setupPool()
-		pool.priceLimit = 1000000
+		pool.SetPriceLimit(1000000)
 
 		tx := newTx(defaultAddr, 0, 1) // gasPrice == 1
 		tx = signTx(tx)
@@ -252,6 +257,60 @@ func TestAddTxErrors(t *testing.T) {
 		)
 	})
 
+	t.Run("ErrTxTypeNotSupported", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+
+		tx := newTx(defaultAddr, 0, 1)
+		tx.Type = types.AccessListTx
+		tx = signTx(tx)
+
+		assert.ErrorIs(t,
+			pool.addTx(local, tx),
+			ErrTxTypeNotSupported,
+		)
+	})
+
+	t.Run("access list tx", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+		pool.forks = &chain.Forks{
+			Homestead: chain.NewFork(0),
+			Istanbul:  chain.NewFork(0),
+			EIP2930:   chain.NewFork(0),
+		}
+
+		tx := newTx(defaultAddr, 0, 1)
+		tx.Type = types.AccessListTx
+		tx.To = &addr2
+		tx.AccessList = types.TxAccessList{
+			{
+				Address:     addr1,
+				StorageKeys: []types.Hash{types.StringToHash("1")},
+			},
+		}
+
+		// the access list is charged on top of the basic transaction fee
+		tx.Gas = state.TxGas + state.TxAccessListAddressGas
+		tx.Input = nil
+
+		assert.ErrorIs(t,
+			pool.addTx(local, signTx(tx)),
+			ErrIntrinsicGas,
+		)
+
+		tx.Gas += state.TxAccessListStorageKeyGas
+		tx = signTx(tx)
+
+		go func() {
+			assert.NoError(t, pool.addTx(local, tx))
+		}()
+
+		req := <-pool.enqueueReqCh
+		assert.Equal(t, tx.Hash, req.tx.Hash)
+		assert.Equal(t, types.AccessListTx, req.tx.Type)
+	})
+
 	t.Run("ErrAlreadyKnown", func(t *testing.T) {
 		t.Parallel()
 		pool := setupPool()
@@ -564,6 +623,190 @@ func TestAddGossipTx(t *testing.T) {
 
 		assert.Equal(t, uint64(0), pool.accounts.get(sender).enqueued.length())
 	})
+
+	t.Run("invalid tx is reported", func(t *testing.T) {
+		t.Parallel()
+
+		pool, err := newTestPool()
+		assert.NoError(t, err)
+		pool.SetSigner(signer)
+
+		pool.SetSealing(true)
+
+		reported := map[peer.ID]network.PeerPenalty{}
+		pool.reportPeer = func(id peer.ID, penalty network.PeerPenalty) {
+			reported[id] = penalty
+		}
+
+		// the unsigned transaction and the undecodable one can never be included
+		pool.addGossipTx(&proto.Txn{Raw: &any.Any{Value: tx.MarshalRLP()}}, "A")
+		pool.addGossipTx(&proto.Txn{Raw: &any.Any{Value: []byte{0x01}}}, "B")
+
+		assert.Equal(t, map[peer.ID]network.PeerPenalty{
+			"A": network.PenaltyInvalidTx,
+			"B": network.PenaltyInvalidTx,
+		}, reported)
+	})
+}
+
+func TestValidateGossipTx(t *testing.T) {
+	t.Parallel()
+
+	key, _ := tests.GenerateKeyAndAddr(t)
+
+	sign := func(signer crypto.TxSigner, mutate func(tx *types.Transaction)) *proto.Txn {
+		tx := newTx(types.ZeroAddress, 1, 1)
+		mutate(tx)
+
+		signedTx, err := signer.SignTx(tx, key)
+		require.NoError(t, err)
+
+		return &proto.Txn{Raw: &any.Any{Value: signedTx.MarshalRLP()}}
+	}
+
+	testCases := []struct {
+		name string
+		msg  *proto.Txn
+		err  error
+	}{
+		{
+			name: "valid tx",
+			msg:  sign(signerEIP155, func(*types.Transaction) {}),
+		},
+		{
+			name: "tx signed before EIP-155",
+			msg:  sign(&crypto.FrontierSigner{}, func(*types.Transaction) {}),
+		},
+		{
+			name: "tx signed for another chain",
+			msg:  sign(crypto.NewEIP155Signer(200), func(*types.Transaction) {}),
+			err:  ErrInvalidChainID,
+		},
+		{
+			name: "tx type not supported",
+			msg: sign(signerEIP155, func(tx *types.Transaction) {
+				tx.Type = types.AccessListTx
+			}),
+			err: ErrTxTypeNotSupported,
+		},
+		{
+			name: "gas below intrinsic gas",
+			msg: sign(signerEIP155, func(tx *types.Transaction) {
+				tx.Gas = 1
+			}),
+			err: ErrIntrinsicGas,
+		},
+		{
+			name: "gas above block gas limit",
+			msg: sign(signerEIP155, func(tx *types.Transaction) {
+				tx.Gas = mockHeader.GasLimit + 1
+			}),
+			err: ErrBlockLimitExceeded,
+		},
+		{
+			name: "oversized tx",
+			msg: sign(signerEIP155, func(tx *types.Transaction) {
+				tx.Input = make([]byte, txMaxSize)
+			}),
+			err: ErrOversizedData,
+		},
+	}
+
+	pool, err := newTestPool()
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		assert.ErrorIs(t, pool.validateGossipTx(tc.msg), tc.err, tc.name)
+	}
+
+	assert.Error(t, pool.validateGossipTx(&proto.Txn{Raw: &any.Any{Value: []byte{0x01}}}))
+	assert.Error(t, pool.validateGossipTx(&proto.Txn{}))
+}
+
+func TestTxTypeForkActivation(t *testing.T) {
+	t.Parallel()
+
+	key, addr := tests.GenerateKeyAndAddr(t)
+
+	testCases := []struct {
+		name   string
+		txType types.TxType
+		forks  *chain.Forks
+	}{
+		{
+			name:   "access list tx",
+			txType: types.AccessListTx,
+			forks:  &chain.Forks{Homestead: chain.NewFork(0), Istanbul: chain.NewFork(0), EIP2930: chain.NewFork(2)},
+		},
+		{
+			name:   "sponsored tx",
+			txType: types.SponsoredTx,
+			forks:  &chain.Forks{Homestead: chain.NewFork(0), Istanbul: chain.NewFork(0), Sponsorship: chain.NewFork(2)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			head := &types.Header{GasLimit: mockHeader.GasLimit}
+
+			pool, err := newTestPool(NewDefaultMockStore(head))
+			require.NoError(t, err)
+
+			pool.SetSigner(signerEIP155)
+			pool.forks = tc.forks
+
+			tx := newTx(addr, 0, 1)
+			tx.Type = tc.txType
+			tx.To = &addr2
+
+			if tc.txType == types.SponsoredTx {
+				tx.Sponsor = &addr3
+			}
+
+			tx, err = signerEIP155.SignTx(tx, key)
+			require.NoError(t, err)
+
+			msg := &proto.Txn{Raw: &any.Any{Value: tx.MarshalRLP()}}
+
+			// the fork activates in the block after the next one
+			assert.ErrorIs(t, pool.validateTx(tx), ErrTxTypeNotSupported)
+			assert.ErrorIs(t, pool.validateGossipTx(msg), ErrTxTypeNotSupported)
+
+			// the fork activates in the next block
+			head.Number = 1
+
+			assert.NoError(t, pool.validateTx(tx))
+			assert.NoError(t, pool.validateGossipTx(msg))
+		})
+	}
+}
+
+func TestBaseFeeForkActivation(t *testing.T) {
+	t.Parallel()
+
+	key, addr := tests.GenerateKeyAndAddr(t)
+	head := &types.Header{GasLimit: mockHeader.GasLimit}
+
+	pool, err := newTestPool(NewDefaultMockStore(head))
+	require.NoError(t, err)
+
+	pool.SetSigner(signerEIP155)
+	pool.baseFee = defaultPriceLimit + 1
+	pool.forks = &chain.Forks{Homestead: chain.NewFork(0), Istanbul: chain.NewFork(0), NativeToken: chain.NewFork(2)}
+
+	tx, err := signerEIP155.SignTx(newTx(addr, 0, 1), key)
+	require.NoError(t, err)
+
+	// the base fee is paid from the nativeToken fork
+	assert.NoError(t, pool.validateTx(tx))
+
+	head.Number = 1
+
+	assert.ErrorIs(t, pool.validateTx(tx), ErrBelowBaseFee)
 }
 
 func TestDropKnownGossipTx(t *testing.T) {
@@ -1433,6 +1676,43 @@ func TestDrop(t *testing.T) {
 	assert.Equal(t, uint64(0), pool.accounts.get(addr1).promoted.length())
 }
 
+func TestRewind(t *testing.T) {
+	t.Parallel()
+
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	// send 1 tx and promote it
+	go func() {
+		err := pool.addTx(local, newTx(addr1, 0, 1))
+		assert.NoError(t, err)
+	}()
+	go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+	pool.handlePromoteRequest(<-pool.promoteReqCh)
+
+	// send 1 tx with a nonce gap, so it stays enqueued
+	go func() {
+		err := pool.addTx(local, newTx(addr1, 2, 1))
+		assert.NoError(t, err)
+	}()
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	assert.Equal(t, uint64(2), pool.gauge.read())
+	assert.Equal(t, uint64(1), pool.accounts.get(addr1).getNonce())
+	assert.Equal(t, uint64(1), pool.accounts.get(addr1).promoted.length())
+	assert.Equal(t, uint64(1), pool.accounts.get(addr1).enqueued.length())
+
+	// the chain is rewound to the state where the account has sent nothing
+	pool.Rewind(mockHeader)
+
+	assert.Equal(t, uint64(0), pool.gauge.read())
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).getNonce())
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).promoted.length())
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).enqueued.length())
+	assert.Equal(t, uint64(0), pool.Length())
+}
+
 func TestDemote(t *testing.T) {
 	t.Parallel()
 
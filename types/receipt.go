@@ -11,6 +11,11 @@ import (
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
 )
 
+var (
+	ErrReceiptNotFound             = errors.New("receipt not found")
+	ErrReceiptsVersionNotSupported = errors.New("receipts storage version not supported")
+)
+
 type ReceiptStatus uint64
 
 const (
@@ -27,11 +32,18 @@ type Receipt struct {
 	LogsBloom         Bloom
 	Logs              []*Log
 	Status            *ReceiptStatus
+	TransactionType   TxType
 
 	// context fields
 	GasUsed         uint64
 	ContractAddress *Address
 	TxHash          Hash
+
+	// Sponsor is the contract which paid the fee of a sponsored transaction
+	Sponsor *Address
+
+	// LogIndex is the position in the block of the first log of the receipt
+	LogIndex uint64
 }
 
 func (r *Receipt) SetStatus(s ReceiptStatus) {
@@ -125,6 +137,28 @@ func (b *Bloom) setEncode(hasher *keccak.Keccak, h []byte) {
 	}
 }
 
+// BloomBits returns the positions of the bits that the given data sets in a bloom filter
+func BloomBits(data []byte) [3]uint {
+	hasher := keccak.DefaultKeccakPool.Get()
+	defer keccak.DefaultKeccakPool.Put(hasher)
+
+	hasher.Reset()
+	hasher.Write(data)
+	buf := hasher.Read()
+
+	var bits [3]uint
+	for i := 0; i < 6; i += 2 {
+		bits[i/2] = (uint(buf[i+1]) + (uint(buf[i]) << 8)) & 2047
+	}
+
+	return bits
+}
+
+// IsBitSet checks if the bit at the given position (as returned by BloomBits) is set
+func (b *Bloom) IsBitSet(bit uint) bool {
+	return b[BloomByteLength-1-bit/8]&(1<<(bit%8)) != 0
+}
+
 // IsLogInBloom checks if the log has a possible presence in the bloom filter
 func (b *Bloom) IsLogInBloom(log *Log) bool {
 	hasher := keccak.DefaultKeccakPool.Get()
@@ -84,6 +84,16 @@ func TestRLPStorage_Marshall_And_Unmarshall_Receipt(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"Marshal typed receipt",
+			&Receipt{
+				CumulativeGasUsed: 10,
+				GasUsed:           100,
+				TransactionType:   AccessListTx,
+				TxHash:            hash,
+			},
+			true,
+		},
 		{
 			"Marshal receipt without status",
 			&Receipt{
@@ -129,3 +139,130 @@ func TestRLPUnmarshal_Header_ComputeHash(t *testing.T) {
 	assert.NoError(t, h2.UnmarshalRLP(data))
 	assert.Equal(t, h.Hash, h2.Hash)
 }
+
+func TestRLPMarshall_And_Unmarshall_AccessListTransaction(t *testing.T) {
+	addrTo := StringToAddress("11")
+	txn := &Transaction{
+		Type:     AccessListTx,
+		ChainID:  big.NewInt(100),
+		Nonce:    1,
+		GasPrice: big.NewInt(11),
+		Gas:      11,
+		To:       &addrTo,
+		Value:    big.NewInt(1),
+		Input:    []byte{1, 2},
+		AccessList: TxAccessList{
+			{
+				Address:     StringToAddress("12"),
+				StorageKeys: []Hash{StringToHash("1"), StringToHash("2")},
+			},
+			{
+				Address:     StringToAddress("13"),
+				StorageKeys: []Hash{},
+			},
+		},
+		V: big.NewInt(1),
+		S: big.NewInt(26),
+		R: big.NewInt(27),
+	}
+	txn.ComputeHash()
+
+	// the binary encoding is prefixed with the type
+	marshaledRlp := txn.MarshalRLP()
+	assert.Equal(t, byte(AccessListTx), marshaledRlp[0])
+
+	unmarshalledTxn := new(Transaction)
+	assert.NoError(t, unmarshalledTxn.UnmarshalRLP(marshaledRlp))
+	assert.Equal(t, txn, unmarshalledTxn)
+
+	// the typed transaction is embedded in the block body as a byte string
+	legacyTxn := &Transaction{
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+		V:        big.NewInt(27),
+		S:        big.NewInt(1),
+		R:        big.NewInt(1),
+	}
+	legacyTxn.ComputeHash()
+
+	block := &Block{
+		Header:       &Header{},
+		Transactions: []*Transaction{legacyTxn, txn},
+	}
+
+	unmarshalledBlock := new(Block)
+	assert.NoError(t, unmarshalledBlock.UnmarshalRLP(block.MarshalRLP()))
+	assert.Equal(t, block.Transactions, unmarshalledBlock.Transactions)
+
+	// and in the stored body, along with the sender
+	txn.From = StringToAddress("14")
+	body := &Body{Transactions: []*Transaction{txn}}
+
+	unmarshalledBody := new(Body)
+	assert.NoError(t, unmarshalledBody.UnmarshalRLP(body.MarshalRLPTo(nil)))
+	assert.Equal(t, body.Transactions, unmarshalledBody.Transactions)
+}
+
+func TestRLPUnmarshal_UnsupportedTxType(t *testing.T) {
+	txn := new(Transaction)
+	assert.ErrorIs(t, txn.UnmarshalRLP([]byte{0x7f, 0xc0}), ErrTxTypeNotSupported)
+}
+
+func TestRLPMarshall_And_Unmarshall_SponsoredTransaction(t *testing.T) {
+	addrTo, sponsor := StringToAddress("11"), StringToAddress("15")
+	txn := &Transaction{
+		Type:     SponsoredTx,
+		ChainID:  big.NewInt(100),
+		Nonce:    1,
+		GasPrice: big.NewInt(11),
+		Gas:      11,
+		To:       &addrTo,
+		Value:    big.NewInt(1),
+		Input:    []byte{1, 2},
+		Sponsor:  &sponsor,
+		V:        big.NewInt(1),
+		S:        big.NewInt(26),
+		R:        big.NewInt(27),
+	}
+	txn.ComputeHash()
+
+	marshaledRlp := txn.MarshalRLP()
+	assert.Equal(t, byte(SponsoredTx), marshaledRlp[0])
+
+	unmarshalledTxn := new(Transaction)
+	assert.NoError(t, unmarshalledTxn.UnmarshalRLP(marshaledRlp))
+	assert.Equal(t, txn, unmarshalledTxn)
+
+	// the sponsor is required
+	txn.Sponsor = nil
+	assert.Error(t, new(Transaction).UnmarshalRLP(txn.MarshalRLP()))
+}
+
+func TestRLPStorage_Marshall_And_Unmarshall_SponsoredReceipts(t *testing.T) {
+	sponsor := StringToAddress("15")
+
+	receipts := Receipts{
+		{CumulativeGasUsed: 21000, TransactionType: LegacyTx, TxHash: StringToHash("1")},
+		{CumulativeGasUsed: 50000, TransactionType: SponsoredTx, TxHash: StringToHash("2"), Sponsor: &sponsor},
+	}
+
+	for i, receipt := range receipts {
+		receipt.SetStatus(ReceiptSuccess)
+		receipt.LogsBloom = CreateBloom([]*Receipt{receipt})
+		receipt.GasUsed = receipt.CumulativeGasUsed
+
+		if i > 0 {
+			receipt.GasUsed -= receipts[i-1].CumulativeGasUsed
+		}
+	}
+
+	unmarshalled := Receipts{}
+	assert.NoError(t, unmarshalled.UnmarshalStoreRLP(receipts.MarshalStoreRLPTo(nil)))
+	assert.Equal(t, receipts, unmarshalled)
+	assert.Nil(t, unmarshalled[0].Sponsor)
+
+	// the receipt of the block is read by its index
+	receipt, err := UnmarshalStoreReceipt(receipts.MarshalStoreRLPTo(nil), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &sponsor, receipt.Sponsor)
+}
@@ -103,20 +103,29 @@ func (r *Receipt) MarshalRLP() []byte {
 	return r.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo marshals the receipt to its binary encoding,
+// which is prefixed with the transaction type for a typed transaction (EIP-2718)
 func (r *Receipt) MarshalRLPTo(dst []byte) []byte {
+	if r.TransactionType != LegacyTx {
+		return MarshalRLPTo(r.marshalFieldsRLPWith, append(dst, byte(r.TransactionType)))
+	}
+
 	return MarshalRLPTo(r.MarshalRLPWith, dst)
 }
 
-// MarshalRLPWith marshals a receipt with a specific fastrlp.Arena
+// MarshalRLPWith marshals a receipt with a specific fastrlp.Arena.
+// The receipt of a typed transaction is marshaled as a byte string holding its binary encoding
 func (r *Receipt) MarshalRLPWith(a *fastrlp.Arena) *fastrlp.Value {
-	vv := a.NewArray()
-
-	if r.Status != nil {
-		vv.Set(a.NewUint(uint64(*r.Status)))
-	} else {
-		vv.Set(a.NewBytes(r.Root[:]))
+	if r.TransactionType != LegacyTx {
+		return a.NewCopyBytes(r.MarshalRLPTo(nil))
 	}
 
+	return r.marshalFieldsRLPWith(a)
+}
+
+func (r *Receipt) marshalFieldsRLPWith(a *fastrlp.Arena) *fastrlp.Value {
+	vv := a.NewArray()
+	vv.Set(r.marshalRootOrStatusWith(a))
 	vv.Set(a.NewUint(r.CumulativeGasUsed))
 	vv.Set(a.NewCopyBytes(r.LogsBloom[:]))
 	vv.Set(r.MarshalLogsWith(a))
@@ -124,6 +133,15 @@ func (r *Receipt) MarshalRLPWith(a *fastrlp.Arena) *fastrlp.Value {
 	return vv
 }
 
+// marshalRootOrStatusWith marshals the status of the receipt, or its state root before Byzantium
+func (r *Receipt) marshalRootOrStatusWith(a *fastrlp.Arena) *fastrlp.Value {
+	if r.Status != nil {
+		return a.NewUint(uint64(*r.Status))
+	}
+
+	return a.NewBytes(r.Root[:])
+}
+
 // MarshalLogsWith marshals the logs of the receipt to RLP with a specific fastrlp.Arena
 func (r *Receipt) MarshalLogsWith(a *fastrlp.Arena) *fastrlp.Value {
 	if len(r.Logs) == 0 {
@@ -159,14 +177,52 @@ func (t *Transaction) MarshalRLP() []byte {
 	return t.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo marshals the transaction to its binary encoding,
+// which is prefixed with the type for a typed transaction (EIP-2718)
 func (t *Transaction) MarshalRLPTo(dst []byte) []byte {
+	if t.IsTyped() {
+		return MarshalRLPTo(t.marshalTypedRLPWith, append(dst, byte(t.Type)))
+	}
+
 	return MarshalRLPTo(t.MarshalRLPWith, dst)
 }
 
-// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena
+// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena.
+// A typed transaction is marshaled as a byte string holding its binary encoding
 func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if t.IsTyped() {
+		return arena.NewCopyBytes(t.MarshalRLPTo(nil))
+	}
+
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewUint(t.Nonce))
+	vv.Set(arena.NewBigInt(t.GasPrice))
+	vv.Set(arena.NewUint(t.Gas))
+
+	// Address may be empty
+	if t.To != nil {
+		vv.Set(arena.NewBytes((*t.To).Bytes()))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+
+	vv.Set(arena.NewBigInt(t.Value))
+	vv.Set(arena.NewCopyBytes(t.Input))
+
+	// signature values
+	vv.Set(arena.NewBigInt(t.V))
+	vv.Set(arena.NewBigInt(t.R))
+	vv.Set(arena.NewBigInt(t.S))
+
+	return vv
+}
+
+// marshalTypedRLPWith marshals the payload of a typed transaction, without its type
+func (t *Transaction) marshalTypedRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv := arena.NewArray()
 
+	vv.Set(arena.NewBigInt(t.ChainID))
 	vv.Set(arena.NewUint(t.Nonce))
 	vv.Set(arena.NewBigInt(t.GasPrice))
 	vv.Set(arena.NewUint(t.Gas))
@@ -180,6 +236,15 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 
 	vv.Set(arena.NewBigInt(t.Value))
 	vv.Set(arena.NewCopyBytes(t.Input))
+	vv.Set(t.AccessList.MarshalRLPWith(arena))
+
+	if t.Type == SponsoredTx {
+		if t.Sponsor != nil {
+			vv.Set(arena.NewCopyBytes(t.Sponsor.Bytes()))
+		} else {
+			vv.Set(arena.NewNull())
+		}
+	}
 
 	// signature values
 	vv.Set(arena.NewBigInt(t.V))
@@ -188,3 +253,32 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 
 	return vv
 }
+
+// MarshalRLPWith marshals the access list to RLP with a specific fastrlp.Arena
+func (al TxAccessList) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if len(al) == 0 {
+		return arena.NewNullArray()
+	}
+
+	vv := arena.NewArray()
+
+	for _, tuple := range al {
+		v := arena.NewArray()
+		v.Set(arena.NewCopyBytes(tuple.Address.Bytes()))
+
+		if len(tuple.StorageKeys) == 0 {
+			v.Set(arena.NewNullArray())
+		} else {
+			keys := arena.NewArray()
+			for _, key := range tuple.StorageKeys {
+				keys.Set(arena.NewCopyBytes(key.Bytes()))
+			}
+
+			v.Set(keys)
+		}
+
+		vv.Set(v)
+	}
+
+	return vv
+}
@@ -51,14 +51,55 @@ func (t *Transaction) MarshalStoreRLPWith(a *fastrlp.Arena) *fastrlp.Value {
 	return vv
 }
 
+// ReceiptsStoreVersion is the version of the storage encoding of the receipts of a block.
+// The first version is the list of the receipts in their consensus encoding, followed by their context fields.
+// The second one is prefixed with its version, and leaves out the bloom and the gas used of the receipts,
+// which are derived from their logs and cumulative gas used, while recording the position in the block
+// of their first log, so that a single receipt is decoded without the others
+const ReceiptsStoreVersion = 2
+
 func (r Receipts) MarshalStoreRLPTo(dst []byte) []byte {
 	return MarshalRLPTo(r.MarshalStoreRLPWith, dst)
 }
 
+// MarshalStoreRLPWith marshals the receipts of a block in the latest storage encoding
 func (r *Receipts) MarshalStoreRLPWith(a *fastrlp.Arena) *fastrlp.Value {
-	vv := a.NewArray()
+	receipts := a.NewArray()
+	logIndex := uint64(0)
+
 	for _, rr := range *r {
-		vv.Set(rr.MarshalStoreRLPWith(a))
+		receipts.Set(rr.marshalStoreV2RLPWith(a, logIndex))
+		logIndex += uint64(len(rr.Logs))
+	}
+
+	vv := a.NewArray()
+	vv.Set(a.NewUint(ReceiptsStoreVersion))
+	vv.Set(receipts)
+
+	return vv
+}
+
+// marshalStoreV2RLPWith marshals the receipt in the second storage encoding,
+// logIndex being the position in the block of its first log
+func (r *Receipt) marshalStoreV2RLPWith(a *fastrlp.Arena, logIndex uint64) *fastrlp.Value {
+	vv := a.NewArray()
+	vv.Set(a.NewUint(uint64(r.TransactionType)))
+	vv.Set(r.marshalRootOrStatusWith(a))
+	vv.Set(a.NewUint(r.CumulativeGasUsed))
+	vv.Set(a.NewUint(logIndex))
+	vv.Set(r.MarshalLogsWith(a))
+
+	if r.ContractAddress == nil {
+		vv.Set(a.NewNull())
+	} else {
+		vv.Set(a.NewBytes(r.ContractAddress.Bytes()))
+	}
+
+	vv.Set(a.NewBytes(r.TxHash.Bytes()))
+
+	// the sponsor is only stored for the sponsored transactions
+	if r.Sponsor != nil {
+		vv.Set(a.NewBytes(r.Sponsor.Bytes()))
 	}
 
 	return vv
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -193,12 +194,47 @@ func (r *Receipts) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a Receipt from its binary encoding,
+// which is prefixed with the transaction type for a typed transaction (EIP-2718)
 func (r *Receipt) UnmarshalRLP(input []byte) error {
+	if len(input) > 0 && input[0] <= 0x7f {
+		return r.unmarshalTyped(input)
+	}
+
 	return UnmarshalRlp(r.UnmarshalRLPFrom, input)
 }
 
 // UnmarshalRLP unmarshals a Receipt in RLP format
 func (r *Receipt) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	// the receipt of a typed transaction is embedded as a byte string holding its binary encoding
+	if v.Type() == fastrlp.TypeBytes {
+		buf, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+
+		return r.unmarshalTyped(buf)
+	}
+
+	return r.unmarshalFieldsRLPFrom(p, v)
+}
+
+// unmarshalTyped unmarshals the receipt of a typed transaction from its binary encoding
+func (r *Receipt) unmarshalTyped(input []byte) error {
+	if len(input) == 0 {
+		return fmt.Errorf("empty typed receipt")
+	}
+
+	if txType := TxType(input[0]); txType != AccessListTx && txType != SponsoredTx {
+		return fmt.Errorf("%w: %d", ErrTxTypeNotSupported, input[0])
+	}
+
+	r.TransactionType = TxType(input[0])
+
+	return UnmarshalRlp(r.unmarshalFieldsRLPFrom, input[1:])
+}
+
+func (r *Receipt) unmarshalFieldsRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
@@ -208,23 +244,10 @@ func (r *Receipt) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 		return fmt.Errorf("incorrect number of elements to decode receipt, expected 4 but found %d", len(elems))
 	}
 
-	// root or status
-	buf, err := elems[0].Bytes()
-	if err != nil {
+	if err := r.unmarshalRootOrStatus(elems[0]); err != nil {
 		return err
 	}
 
-	switch size := len(buf); size {
-	case 32:
-		// root
-		copy(r.Root[:], buf[:])
-	case 1:
-		// status
-		r.SetStatus(ReceiptStatus(buf[0]))
-	default:
-		r.SetStatus(0)
-	}
-
 	// cumulativeGasUsed
 	if r.CumulativeGasUsed, err = elems[1].GetUint64(); err != nil {
 		return err
@@ -235,7 +258,12 @@ func (r *Receipt) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	}
 
 	// logs
-	logsElems, err := v.Get(3).GetElems()
+	return r.unmarshalLogsFrom(p, elems[3])
+}
+
+// unmarshalLogsFrom unmarshals the logs of the receipt
+func (r *Receipt) unmarshalLogsFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	logsElems, err := v.GetElems()
 	if err != nil {
 		return err
 	}
@@ -252,6 +280,27 @@ func (r *Receipt) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// unmarshalRootOrStatus unmarshals the status of the receipt, or its state root before Byzantium
+func (r *Receipt) unmarshalRootOrStatus(v *fastrlp.Value) error {
+	buf, err := v.Bytes()
+	if err != nil {
+		return err
+	}
+
+	switch size := len(buf); size {
+	case 32:
+		// root
+		copy(r.Root[:], buf[:])
+	case 1:
+		// status
+		r.SetStatus(ReceiptStatus(buf[0]))
+	default:
+		r.SetStatus(0)
+	}
+
+	return nil
+}
+
 func (l *Log) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	elems, err := v.GetElems()
 	if err != nil {
@@ -288,12 +337,28 @@ func (l *Log) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a Transaction from its binary encoding,
+// which is prefixed with the type for a typed transaction (EIP-2718)
 func (t *Transaction) UnmarshalRLP(input []byte) error {
+	if len(input) > 0 && input[0] <= 0x7f {
+		return t.unmarshalTyped(input)
+	}
+
 	return UnmarshalRlp(t.UnmarshalRLPFrom, input)
 }
 
 // UnmarshalRLPFrom unmarshals a Transaction in RLP format
 func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	// a typed transaction is embedded as a byte string holding its binary encoding
+	if v.Type() == fastrlp.TypeBytes {
+		buf, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+
+		return t.unmarshalTyped(buf)
+	}
+
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
@@ -305,6 +370,8 @@ func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) erro
 
 	p.Hash(t.Hash[:0], v)
 
+	t.Type = LegacyTx
+
 	// nonce
 	if t.Nonce, err = elems[0].GetUint64(); err != nil {
 		return err
@@ -337,22 +404,189 @@ func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) erro
 		return err
 	}
 
+	return t.unmarshalSignatureFrom(elems[6:])
+}
+
+// unmarshalTyped unmarshals a typed transaction from its binary encoding
+func (t *Transaction) unmarshalTyped(input []byte) error {
+	if len(input) == 0 {
+		return fmt.Errorf("empty typed transaction")
+	}
+
+	switch TxType(input[0]) {
+	case AccessListTx:
+		if err := UnmarshalRlp(t.unmarshalAccessListTxFrom, input[1:]); err != nil {
+			return err
+		}
+	case SponsoredTx:
+		if err := UnmarshalRlp(t.unmarshalSponsoredTxFrom, input[1:]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %d", ErrTxTypeNotSupported, input[0])
+	}
+
+	t.Type = TxType(input[0])
+	keccak.Keccak256(t.Hash[:0], input)
+
+	return nil
+}
+
+// unmarshalAccessListTxFrom unmarshals the payload of an access list transaction (EIP-2930)
+func (t *Transaction) unmarshalAccessListTxFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) < 11 {
+		return fmt.Errorf("incorrect number of elements to decode access list transaction, expected 11 but found %d", len(elems))
+	}
+
+	if err := t.unmarshalAccessListFieldsFrom(p, elems); err != nil {
+		return err
+	}
+
+	t.Sponsor = nil
+
+	return t.unmarshalSignatureFrom(elems[8:])
+}
+
+// unmarshalSponsoredTxFrom unmarshals the payload of a sponsored transaction,
+// which holds the sponsor after the fields of an access list transaction
+func (t *Transaction) unmarshalSponsoredTxFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) < 12 {
+		return fmt.Errorf(
+			"incorrect number of elements to decode sponsored transaction, expected 12 but found %d",
+			len(elems),
+		)
+	}
+
+	if err := t.unmarshalAccessListFieldsFrom(p, elems); err != nil {
+		return err
+	}
+
+	// sponsor
+	vv, _ := elems[8].Bytes()
+	if len(vv) != AddressLength {
+		return fmt.Errorf("invalid sponsor of sponsored transaction")
+	}
+
+	sponsor := BytesToAddress(vv)
+	t.Sponsor = &sponsor
+
+	return t.unmarshalSignatureFrom(elems[9:])
+}
+
+// unmarshalAccessListFieldsFrom unmarshals the fields of an access list transaction preceding the signature
+func (t *Transaction) unmarshalAccessListFieldsFrom(p *fastrlp.Parser, elems []*fastrlp.Value) error {
+	var err error
+
+	// chainID
+	t.ChainID = new(big.Int)
+	if err := elems[0].GetBigInt(t.ChainID); err != nil {
+		return err
+	}
+	// nonce
+	if t.Nonce, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	// gasPrice
+	t.GasPrice = new(big.Int)
+	if err := elems[2].GetBigInt(t.GasPrice); err != nil {
+		return err
+	}
+	// gas
+	if t.Gas, err = elems[3].GetUint64(); err != nil {
+		return err
+	}
+	// to
+	if vv, _ := elems[4].Bytes(); len(vv) == 20 {
+		// address
+		addr := BytesToAddress(vv)
+		t.To = &addr
+	} else {
+		// reset To
+		t.To = nil
+	}
+	// value
+	t.Value = new(big.Int)
+	if err := elems[5].GetBigInt(t.Value); err != nil {
+		return err
+	}
+	// input
+	if t.Input, err = elems[6].GetBytes(t.Input[:0]); err != nil {
+		return err
+	}
+	// accessList
+	t.AccessList = nil
+
+	return t.AccessList.unmarshalRLPFrom(p, elems[7])
+}
+
+// unmarshalSignatureFrom unmarshals the V, R and S signature values
+func (t *Transaction) unmarshalSignatureFrom(elems []*fastrlp.Value) error {
 	// V
 	t.V = new(big.Int)
-	if err = elems[6].GetBigInt(t.V); err != nil {
+	if err := elems[0].GetBigInt(t.V); err != nil {
 		return err
 	}
-
 	// R
 	t.R = new(big.Int)
-	if err = elems[7].GetBigInt(t.R); err != nil {
+	if err := elems[1].GetBigInt(t.R); err != nil {
 		return err
 	}
 	// S
 	t.S = new(big.Int)
-	if err = elems[8].GetBigInt(t.S); err != nil {
+	if err := elems[2].GetBigInt(t.S); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (al *TxAccessList) unmarshalRLPFrom(_ *fastrlp.Parser, v *fastrlp.Value) error {
+	tuples, err := v.GetElems()
+	if err != nil {
 		return err
 	}
 
+	for _, tuple := range tuples {
+		elems, err := tuple.GetElems()
+		if err != nil {
+			return err
+		}
+
+		if len(elems) < 2 {
+			return fmt.Errorf("incorrect number of elements to decode access tuple, expected 2 but found %d", len(elems))
+		}
+
+		var accessTuple AccessTuple
+
+		if err := elems[0].GetAddr(accessTuple.Address[:]); err != nil {
+			return err
+		}
+
+		keys, err := elems[1].GetElems()
+		if err != nil {
+			return err
+		}
+
+		accessTuple.StorageKeys = make([]Hash, len(keys))
+
+		for i, key := range keys {
+			if err := key.GetHash(accessTuple.StorageKeys[i][:]); err != nil {
+				return err
+			}
+		}
+
+		*al = append(*al, accessTuple)
+	}
+
 	return nil
 }
@@ -87,24 +87,188 @@ func (r *Receipts) UnmarshalStoreRLP(input []byte) error {
 	return UnmarshalRlp(r.UnmarshalStoreRLPFrom, input)
 }
 
+// UnmarshalStoreRLPFrom unmarshals the receipts of a block, in any of their storage encodings
 func (r *Receipts) UnmarshalStoreRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
-	elems, err := v.GetElems()
+	receipts, versioned, err := storedReceiptsElems(v)
 	if err != nil {
 		return err
 	}
 
-	for _, elem := range elems {
+	cumulativeGasUsed, logIndex := uint64(0), uint64(0)
+
+	for _, elem := range receipts {
 		rr := &Receipt{}
-		if err := rr.UnmarshalStoreRLPFrom(p, elem); err != nil {
+
+		if versioned {
+			err = rr.unmarshalStoreV2RLPFrom(p, elem, cumulativeGasUsed)
+		} else {
+			err = rr.UnmarshalStoreRLPFrom(p, elem)
+			rr.LogIndex = logIndex
+		}
+
+		if err != nil {
 			return err
 		}
 
+		cumulativeGasUsed, logIndex = rr.CumulativeGasUsed, logIndex+uint64(len(rr.Logs))
+
 		(*r) = append(*r, rr)
 	}
 
 	return nil
 }
 
+// UnmarshalStoreReceipt unmarshals the receipt at the index out of the stored receipts of a block.
+// The other receipts are only decoded if they are stored in the first encoding
+func UnmarshalStoreReceipt(input []byte, index uint64) (*Receipt, error) {
+	var receipt *Receipt
+
+	err := UnmarshalRlp(func(p *fastrlp.Parser, v *fastrlp.Value) error {
+		receipts, versioned, err := storedReceiptsElems(v)
+		if err != nil {
+			return err
+		}
+
+		if index >= uint64(len(receipts)) {
+			return fmt.Errorf("%w: %d of %d", ErrReceiptNotFound, index, len(receipts))
+		}
+
+		if !versioned {
+			all := Receipts{}
+			if err := all.UnmarshalStoreRLPFrom(p, v); err != nil {
+				return err
+			}
+
+			receipt = all[index]
+
+			return nil
+		}
+
+		// the gas used by the transaction is the difference with the cumulative gas used of the previous one
+		cumulativeGasUsed := uint64(0)
+		if index > 0 {
+			if cumulativeGasUsed, err = receipts[index-1].Get(2).GetUint64(); err != nil {
+				return err
+			}
+		}
+
+		receipt = &Receipt{}
+
+		return receipt.unmarshalStoreV2RLPFrom(p, receipts[index], cumulativeGasUsed)
+	}, input)
+
+	return receipt, err
+}
+
+// storedReceiptsElems returns the elements of the stored receipts of a block,
+// and whether they are in the versioned encoding rather than the first one
+func storedReceiptsElems(v *fastrlp.Value) ([]*fastrlp.Value, bool, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the receipts of the first encoding are lists, the versioned encoding starts with its version
+	if len(elems) == 0 || elems[0].Type() != fastrlp.TypeBytes {
+		return elems, false, nil
+	}
+
+	if len(elems) != 2 {
+		return nil, false, fmt.Errorf("incorrect number of elements to decode receipts, expected 2 but found %d", len(elems))
+	}
+
+	version, err := elems[0].GetUint64()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if version != ReceiptsStoreVersion {
+		return nil, false, fmt.Errorf("%w: %d", ErrReceiptsVersionNotSupported, version)
+	}
+
+	receipts, err := elems[1].GetElems()
+
+	return receipts, true, err
+}
+
+// unmarshalStoreV2RLPFrom unmarshals the receipt from the second storage encoding,
+// given the cumulative gas used of the previous receipt of the block
+func (r *Receipt) unmarshalStoreV2RLPFrom(p *fastrlp.Parser, v *fastrlp.Value, previousGasUsed uint64) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) != 7 && len(elems) != 8 {
+		return fmt.Errorf("incorrect number of elements to decode receipt, expected 7 or 8 but found %d", len(elems))
+	}
+
+	txType, err := elems[0].GetUint64()
+	if err != nil {
+		return err
+	}
+
+	r.TransactionType = TxType(txType)
+
+	if err := r.unmarshalRootOrStatus(elems[1]); err != nil {
+		return err
+	}
+
+	if r.CumulativeGasUsed, err = elems[2].GetUint64(); err != nil {
+		return err
+	}
+
+	if r.CumulativeGasUsed < previousGasUsed {
+		return fmt.Errorf("cumulative gas used %d lower than the previous one %d", r.CumulativeGasUsed, previousGasUsed)
+	}
+
+	r.GasUsed = r.CumulativeGasUsed - previousGasUsed
+
+	if r.LogIndex, err = elems[3].GetUint64(); err != nil {
+		return err
+	}
+
+	if err := r.unmarshalLogsFrom(p, elems[4]); err != nil {
+		return err
+	}
+
+	r.LogsBloom = CreateBloom([]*Receipt{r})
+
+	// contract address
+	vv, err := elems[5].Bytes()
+	if err != nil {
+		return err
+	}
+
+	if len(vv) == AddressLength {
+		r.SetContractAddress(BytesToAddress(vv))
+	}
+
+	if vv, err = elems[6].Bytes(); err != nil {
+		return err
+	}
+
+	r.TxHash = BytesToHash(vv)
+
+	// sponsor
+	r.Sponsor = nil
+
+	if len(elems) == 8 {
+		if vv, err = elems[7].Bytes(); err != nil {
+			return err
+		}
+
+		if len(vv) != AddressLength {
+			return fmt.Errorf("invalid sponsor of receipt")
+		}
+
+		sponsor := BytesToAddress(vv)
+		r.Sponsor = &sponsor
+	}
+
+	return nil
+}
+
 func (r *Receipt) UnmarshalStoreRLP(input []byte) error {
 	return UnmarshalRlp(r.UnmarshalStoreRLPFrom, input)
 }
@@ -1,13 +1,30 @@
 package types
 
 import (
+	"errors"
 	"math/big"
 	"sync/atomic"
 
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
 )
 
+var (
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+)
+
+// TxType is the type of the transaction envelope (EIP-2718)
+type TxType byte
+
+const (
+	LegacyTx     TxType = 0x0
+	AccessListTx TxType = 0x01 // EIP-2930
+	// SponsoredTx is an access list transaction along with the sponsor paying its fee.
+	// Its type is out of the range of the Ethereum transaction types
+	SponsoredTx TxType = 0x40
+)
+
 type Transaction struct {
+	Type     TxType
 	Nonce    uint64
 	GasPrice *big.Int
 	Gas      uint64
@@ -20,6 +37,20 @@ type Transaction struct {
 	Hash     Hash
 	From     Address
 
+	// typed transaction fields
+	ChainID    *big.Int
+	AccessList TxAccessList
+	// Sponsor is the contract paying the fee of a sponsored transaction, once it approves it
+	Sponsor *Address
+
+	// ValidUntil is the last block the transaction can be included in, 0 if it doesn't expire.
+	// It is a condition set by the sender, which isn't part of the encoding
+	ValidUntil uint64
+
+	// KnownAccounts are the storage slots the sender expects the accounts to hold when
+	// the transaction is included, set along with ValidUntil. They aren't part of the encoding
+	KnownAccounts map[Address]map[Hash]Hash
+
 	// Cache
 	size atomic.Value
 }
@@ -29,8 +60,30 @@ func (t *Transaction) IsContractCreation() bool {
 	return t.To == nil
 }
 
+// IsExpired checks if tx can't be included in the block anymore
+func (t *Transaction) IsExpired(number uint64) bool {
+	return t.ValidUntil != 0 && number > t.ValidUntil
+}
+
+// IsConditional checks if tx was sent with conditions on its inclusion
+func (t *Transaction) IsConditional() bool {
+	return t.ValidUntil != 0 || len(t.KnownAccounts) != 0
+}
+
+// IsTyped checks if tx is wrapped in a typed envelope
+func (t *Transaction) IsTyped() bool {
+	return t.Type != LegacyTx
+}
+
 // ComputeHash computes the hash of the transaction
 func (t *Transaction) ComputeHash() *Transaction {
+	// the hash of a typed transaction covers its type as well
+	if t.IsTyped() {
+		keccak.Keccak256(t.Hash[:0], t.MarshalRLP())
+
+		return t
+	}
+
 	ar := marshalArenaPool.Get()
 	hash := keccak.DefaultKeccakPool.Get()
 
@@ -70,6 +123,17 @@ func (t *Transaction) Copy() *Transaction {
 	tt.Input = make([]byte, len(t.Input))
 	copy(tt.Input[:], t.Input[:])
 
+	if t.ChainID != nil {
+		tt.ChainID = new(big.Int).Set(t.ChainID)
+	}
+
+	tt.AccessList = t.AccessList.Copy()
+
+	if t.Sponsor != nil {
+		sponsor := *t.Sponsor
+		tt.Sponsor = &sponsor
+	}
+
 	return tt
 }
 
@@ -104,3 +168,41 @@ func (t *Transaction) ExceedsBlockGasLimit(blockGasLimit uint64) bool {
 func (t *Transaction) IsUnderpriced(priceLimit uint64) bool {
 	return t.GasPrice.Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
 }
+
+// AccessTuple is an account and the storage keys of it accessed by the transaction
+type AccessTuple struct {
+	Address     Address `json:"address"`
+	StorageKeys []Hash  `json:"storageKeys"`
+}
+
+// TxAccessList is the list of the accounts and storage keys
+// the transaction declares to access (EIP-2930)
+type TxAccessList []AccessTuple
+
+// StorageKeys returns the total number of storage keys in the access list
+func (al TxAccessList) StorageKeys() int {
+	count := 0
+
+	for _, tuple := range al {
+		count += len(tuple.StorageKeys)
+	}
+
+	return count
+}
+
+func (al TxAccessList) Copy() TxAccessList {
+	if al == nil {
+		return nil
+	}
+
+	cp := make(TxAccessList, len(al))
+
+	for i, tuple := range al {
+		cp[i] = AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]Hash{}, tuple.StorageKeys...),
+		}
+	}
+
+	return cp
+}
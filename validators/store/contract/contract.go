@@ -67,6 +67,7 @@ func (s *ContractValidatorStore) SourceType() store.SourceType {
 
 func (s *ContractValidatorStore) GetValidatorsByHeight(
 	validatorType validators.ValidatorType,
+	contract types.Address,
 	height uint64,
 ) (validators.Validators, error) {
 	cachedValidators, err := s.loadCachedValidatorSet(height)
@@ -83,7 +84,7 @@ func (s *ContractValidatorStore) GetValidatorsByHeight(
 		return nil, err
 	}
 
-	fetchedValidators, err := FetchValidators(validatorType, transition, types.ZeroAddress)
+	fetchedValidators, err := FetchValidators(validatorType, transition, contract, types.ZeroAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +100,14 @@ func (s *ContractValidatorStore) getTransitionForQuery(height uint64) (*state.Tr
 		return nil, fmt.Errorf("header not found at %d", height)
 	}
 
-	return s.executor.BeginTxn(header.StateRoot, header, types.ZeroAddress)
+	transition, err := s.executor.BeginTxn(header.StateRoot, header, types.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	transition.SetCall()
+
+	return transition, nil
 }
 
 // loadCachedValidatorSet loads validators from validatorSetCache
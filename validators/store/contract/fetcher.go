@@ -14,13 +14,14 @@ import (
 func FetchValidators(
 	validatorType validators.ValidatorType,
 	transition *state.Transition,
+	contract types.Address,
 	from types.Address,
 ) (validators.Validators, error) {
 	switch validatorType {
 	case validators.ECDSAValidatorType:
-		return FetchECDSAValidators(transition, from)
+		return FetchECDSAValidators(transition, contract, from)
 	case validators.BLSValidatorType:
-		return FetchBLSValidators(transition, from)
+		return FetchBLSValidators(transition, contract, from)
 	}
 
 	return nil, fmt.Errorf("unsupported validator type: %s", validatorType)
@@ -29,9 +30,10 @@ func FetchValidators(
 // FetchECDSAValidators queries a contract for validator addresses and returns ECDSAValidators
 func FetchECDSAValidators(
 	transition *state.Transition,
+	contract types.Address,
 	from types.Address,
 ) (validators.Validators, error) {
-	valAddrs, err := staking.QueryValidators(transition, from)
+	valAddrs, err := staking.QueryValidators(transition, contract, from)
 	if err != nil {
 		return nil, err
 	}
@@ -49,14 +51,15 @@ func FetchECDSAValidators(
 // FetchBLSValidators queries a contract for validator addresses & BLS Public Keys and returns ECDSAValidators
 func FetchBLSValidators(
 	transition *state.Transition,
+	contract types.Address,
 	from types.Address,
 ) (validators.Validators, error) {
-	valAddrs, err := staking.QueryValidators(transition, from)
+	valAddrs, err := staking.QueryValidators(transition, contract, from)
 	if err != nil {
 		return nil, err
 	}
 
-	blsPublicKeys, err := staking.QueryBLSPublicKeys(transition, from)
+	blsPublicKeys, err := staking.QueryBLSPublicKeys(transition, contract, from)
 	if err != nil {
 		return nil, err
 	}
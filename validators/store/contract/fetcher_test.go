@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
 	testHelper "github.com/0xPolygon/polygon-edge/helper/tests"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -20,6 +21,7 @@ func TestFetchValidators(t *testing.T) {
 	res, err := FetchValidators(
 		fakeValidatorType,
 		nil,
+		staking.AddrStakingContract,
 		types.ZeroAddress,
 	)
 
@@ -74,6 +76,7 @@ func TestFetchECDSAValidators(t *testing.T) {
 			res, err := FetchValidators(
 				validators.ECDSAValidatorType,
 				test.transition,
+				staking.AddrStakingContract,
 				test.from,
 			)
 
@@ -132,6 +135,7 @@ func TestFetchBLSValidators(t *testing.T) {
 			res, err := FetchValidators(
 				validators.BLSValidatorType,
 				test.transition,
+				staking.AddrStakingContract,
 				test.from,
 			)
 
@@ -41,6 +41,7 @@ var (
 	ErrCandidateNotExistInSet       = errors.New("cannot remove a validator if they're not in the snapshot")
 	ErrAlreadyVoted                 = errors.New("already voted for this address")
 	ErrMultipleVotesBySameValidator = errors.New("more than one proposal per validator per address found")
+	ErrSnapshotChainBroken          = errors.New("validator set hash chain is broken")
 )
 
 type SnapshotValidatorStore struct {
@@ -67,9 +68,16 @@ func NewSnapshotValidatorStore(
 	metadata *SnapshotMetadata,
 	snapshots []*Snapshot,
 ) (*SnapshotValidatorStore, error) {
+	snapshotStore := newSnapshotStore(metadata, snapshots)
+
+	// detect the snapshots tampered or corrupted in the local storage
+	if err := snapshotStore.verifyChain(); err != nil {
+		return nil, err
+	}
+
 	set := &SnapshotValidatorStore{
 		logger:         logger.Named(loggerName),
-		store:          newSnapshotStore(metadata, snapshots),
+		store:          snapshotStore,
 		blockchain:     blockchain,
 		getSigner:      getSigner,
 		candidates:     make([]*store.Candidate, 0),
@@ -244,13 +252,52 @@ func (s *SnapshotValidatorStore) VerifyHeader(header *types.Header) error {
 		return ErrInvalidNonce
 	}
 
+	// Cross-check the snapshot the header is verified against with the hash chain
+	if header.Number > 0 {
+		snap := s.getSnapshot(header.Number - 1)
+		if snap == nil {
+			return ErrSnapshotNotFound
+		}
+
+		if err := s.store.verify(snap.Number); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// headerIterator is implemented by the blockchains which can iterate over a range of headers
+type headerIterator interface {
+	IterateHeaders(from, to uint64, handler func(*types.Header) bool) error
+}
+
 // ProcessHeadersInRange is a helper function process headers in the given range
 func (s *SnapshotValidatorStore) ProcessHeadersInRange(
 	from, to uint64,
 ) error {
+	if iterator, ok := s.blockchain.(headerIterator); ok {
+		if from == 0 {
+			from = 1
+		}
+
+		if from > to {
+			return nil
+		}
+
+		var processErr error
+
+		if err := iterator.IterateHeaders(from, to, func(header *types.Header) bool {
+			processErr = s.ProcessHeader(header)
+
+			return processErr == nil
+		}); err != nil {
+			return err
+		}
+
+		return processErr
+	}
+
 	for i := from; i <= to; i++ {
 		if i == 0 {
 			continue
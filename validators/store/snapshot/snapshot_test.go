@@ -87,7 +87,8 @@ func newTestSnapshotValidatorStore(
 			&SnapshotMetadata{
 				LastBlock: lastBlock,
 			},
-			snapshots,
+			// copy not to link the snapshots shared by the test cases
+			unlinked(snapshots...),
 		),
 		blockchain:     blockchain,
 		getSigner:      getSigner,
@@ -146,6 +147,32 @@ func TestNewSnapshotValidatorStore(t *testing.T) {
 		)
 	})
 
+	t.Run("should return ErrSnapshotChainBroken if the persisted snapshots have been tampered", func(t *testing.T) {
+		t.Parallel()
+
+		snapshots := []*Snapshot{
+			{Number: 0, Set: validators.NewECDSAValidatorSet(ecdsaValidator1)},
+			{Number: 10, Set: validators.NewECDSAValidatorSet(ecdsaValidator1, ecdsaValidator2)},
+		}
+		snapshotSortedList(snapshots).link(0)
+
+		snapshots[1].Set = validators.NewECDSAValidatorSet(ecdsaValidator1, ecdsaValidator3)
+
+		store, err := NewSnapshotValidatorStore(
+			logger,
+			blockchain,
+			func(u uint64) (SignerInterface, error) {
+				return nil, errTest
+			},
+			epochSize,
+			metadata,
+			snapshots,
+		)
+
+		assert.Nil(t, store)
+		assert.ErrorIs(t, err, ErrSnapshotChainBroken)
+	})
+
 	t.Run("should succeed", func(t *testing.T) {
 		t.Parallel()
 
@@ -491,7 +518,7 @@ func TestSnapshotValidatorStore_initialize(t *testing.T) {
 			assert.Equal(
 				t,
 				test.finalSnapshots,
-				snapshotStore.GetSnapshots(),
+				unlinked(snapshotStore.GetSnapshots()...),
 			)
 			assert.Equal(
 				t,
@@ -574,7 +601,7 @@ func TestSnapshotValidatorStoreGetSnapshots(t *testing.T) {
 	assert.Equal(
 		t,
 		snapshots,
-		snapshotStore.GetSnapshots(),
+		unlinked(snapshotStore.GetSnapshots()...),
 	)
 }
 
@@ -858,7 +885,7 @@ func TestSnapshotValidatorStoreUpdateValidatorSet(t *testing.T) {
 			)
 
 			testHelper.AssertErrorMessageContains(t, test.expectedErr, err)
-			assert.Equal(t, test.finalSnapshots, snapshotStore.GetSnapshots())
+			assert.Equal(t, test.finalSnapshots, unlinked(snapshotStore.GetSnapshots()...))
 		})
 	}
 }
@@ -1116,6 +1143,46 @@ func TestSnapshotValidatorStoreVerifyHeader(t *testing.T) {
 	}
 }
 
+func TestSnapshotValidatorStoreVerifyHeaderHashChain(t *testing.T) {
+	t.Parallel()
+
+	newStore := func() *SnapshotValidatorStore {
+		return newTestSnapshotValidatorStore(
+			nil,
+			nil,
+			20,
+			[]*Snapshot{
+				{Number: 0, Set: validators.NewECDSAValidatorSet(ecdsaValidator1)},
+				{Number: 10, Set: validators.NewECDSAValidatorSet(ecdsaValidator1, ecdsaValidator2)},
+				{Number: 20, Set: validators.NewECDSAValidatorSet(ecdsaValidator2)},
+			},
+			nil,
+			10,
+		)
+	}
+
+	t.Run("should return nil if the snapshots are intact", func(t *testing.T) {
+		t.Parallel()
+
+		snapshotStore := newStore()
+
+		for _, number := range []uint64{1, 15, 21} {
+			assert.NoError(t, snapshotStore.VerifyHeader(&types.Header{Number: number}))
+		}
+	})
+
+	t.Run("should return ErrSnapshotChainBroken if the snapshot has been tampered", func(t *testing.T) {
+		t.Parallel()
+
+		snapshotStore := newStore()
+		snapshotStore.getSnapshot(10).Set = validators.NewECDSAValidatorSet(ecdsaValidator3)
+
+		// the earlier snapshots are not affected
+		assert.NoError(t, snapshotStore.VerifyHeader(&types.Header{Number: 5}))
+		assert.ErrorIs(t, snapshotStore.VerifyHeader(&types.Header{Number: 15}), ErrSnapshotChainBroken)
+	})
+}
+
 func TestSnapshotValidatorStoreProcessHeadersInRange(t *testing.T) {
 	t.Parallel()
 
@@ -1338,7 +1405,7 @@ func TestSnapshotValidatorStoreProcessHeadersInRange(t *testing.T) {
 			assert.Equal(
 				t,
 				test.finalSnapshots,
-				snapshotStore.GetSnapshots(),
+				unlinked(snapshotStore.GetSnapshots()...),
 			)
 			assert.Equal(
 				t,
@@ -1637,7 +1704,7 @@ func TestSnapshotValidatorStoreProcessHeader(t *testing.T) {
 			assert.Equal(
 				t,
 				test.finalSnapshots,
-				snapshotStore.GetSnapshots(),
+				unlinked(snapshotStore.GetSnapshots()...),
 			)
 
 			assert.Equal(
@@ -2016,7 +2083,7 @@ func TestSnapshotValidatorStore_addHeaderSnap(t *testing.T) {
 			assert.Equal(
 				t,
 				test.finalSnapshots,
-				snapshotStore.GetSnapshots(),
+				unlinked(snapshotStore.GetSnapshots()...),
 			)
 		})
 	}
@@ -2051,7 +2118,7 @@ func TestSnapshotValidatorStore_getSnapshot(t *testing.T) {
 	assert.Equal(
 		t,
 		expectedSnapshot,
-		snapshotStore.getSnapshot(targetHeight),
+		unlinked(snapshotStore.getSnapshot(targetHeight))[0],
 	)
 }
 
@@ -2084,7 +2151,7 @@ func TestSnapshotValidatorStore_getLatestSnapshot(t *testing.T) {
 	assert.Equal(
 		t,
 		expectedSnapshot,
-		snapshotStore.getLatestSnapshot(),
+		unlinked(snapshotStore.getLatestSnapshot())[0],
 	)
 }
 
@@ -2290,7 +2357,7 @@ func TestSnapshotValidatorStore_saveSnapshotIfChanged(t *testing.T) {
 			assert.Equal(
 				t,
 				test.finalSnapshots,
-				snapshotStore.GetSnapshots(),
+				unlinked(snapshotStore.GetSnapshots()...),
 			)
 		})
 	}
@@ -2377,7 +2444,7 @@ func TestSnapshotValidatorStore_resetSnapshot(t *testing.T) {
 			assert.Equal(
 				t,
 				test.finalSnapshots,
-				snapshotStore.GetSnapshots(),
+				unlinked(snapshotStore.GetSnapshots()...),
 			)
 		})
 	}
@@ -2446,7 +2513,7 @@ func TestSnapshotValidatorStore_removeLowerSnapshots(t *testing.T) {
 			assert.Equal(
 				t,
 				test.finalSnapshots,
-				snapshotStore.GetSnapshots(),
+				unlinked(snapshotStore.GetSnapshots()...),
 			)
 		})
 	}
@@ -2,13 +2,16 @@ package snapshot
 
 import (
 	"encoding/json"
+	"fmt"
 	"sort"
 	"sync"
 	"sync/atomic"
 
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/validators"
 	"github.com/0xPolygon/polygon-edge/validators/store"
+	"github.com/umbracle/fastrlp"
 )
 
 // snapshotMetadata defines the metadata for the snapshot
@@ -30,6 +33,10 @@ type Snapshot struct {
 
 	// current set of validators
 	Set validators.Validators
+
+	// link of the snapshot in the hash chain of the validator set transitions,
+	// it commits to the link of the previous snapshot and to the changes of the set
+	Link types.Hash
 }
 
 func (s *Snapshot) MarshalJSON() ([]byte, error) {
@@ -39,6 +46,7 @@ func (s *Snapshot) MarshalJSON() ([]byte, error) {
 		Votes  []*store.Vote
 		Type   validators.ValidatorType
 		Set    validators.Validators
+		Link   *types.Hash `json:",omitempty"`
 	}{
 		Number: s.Number,
 		Hash:   s.Hash,
@@ -47,6 +55,10 @@ func (s *Snapshot) MarshalJSON() ([]byte, error) {
 		Set:    s.Set,
 	}
 
+	if s.Link != types.ZeroHash {
+		jsonData.Link = &s.Link
+	}
+
 	return json.Marshal(jsonData)
 }
 
@@ -57,6 +69,7 @@ func (s *Snapshot) UnmarshalJSON(data []byte) error {
 		Type   string
 		Votes  []json.RawMessage
 		Set    json.RawMessage
+		Link   types.Hash
 	}{}
 
 	var err error
@@ -67,6 +80,7 @@ func (s *Snapshot) UnmarshalJSON(data []byte) error {
 
 	s.Number = raw.Number
 	s.Hash = raw.Hash
+	s.Link = raw.Link
 
 	isLegacyFormat := raw.Type == ""
 
@@ -162,6 +176,58 @@ func (s *Snapshot) Equal(ss *Snapshot) bool {
 	return s.Set.Equal(ss.Set)
 }
 
+// computeLink returns the link of the snapshot following the given previous snapshot in the hash chain.
+// The link is the hash of the previous link, the block number and the validators added to and removed from the set,
+// the first snapshot of the chain is linked to the zero hash and adds the whole set
+func computeLink(prev, snap *Snapshot) types.Hash {
+	var (
+		ar      = &fastrlp.Arena{}
+		added   = ar.NewArray()
+		removed = ar.NewArray()
+
+		prevLink = types.ZeroHash
+		prevSet  validators.Validators
+	)
+
+	if prev != nil {
+		prevLink, prevSet = prev.Link, prev.Set
+	}
+
+	// includes checks if the exact validator, including its BLS public key, is in the set
+	includes := func(set validators.Validators, val validators.Validator) bool {
+		if set == nil {
+			return false
+		}
+
+		idx := set.Index(val.Addr())
+
+		return idx != -1 && set.At(uint64(idx)).Equal(val)
+	}
+
+	for i := 0; snap.Set != nil && i < snap.Set.Len(); i++ {
+		if val := snap.Set.At(uint64(i)); !includes(prevSet, val) {
+			added.Set(val.MarshalRLPWith(ar))
+		}
+	}
+
+	for i := 0; prevSet != nil && i < prevSet.Len(); i++ {
+		if val := prevSet.At(uint64(i)); !includes(snap.Set, val) {
+			removed.Set(val.MarshalRLPWith(ar))
+		}
+	}
+
+	delta := ar.NewArray()
+	delta.Set(added)
+	delta.Set(removed)
+
+	link := ar.NewArray()
+	link.Set(ar.NewBytes(prevLink.Bytes()))
+	link.Set(ar.NewUint(snap.Number))
+	link.Set(ar.NewBytes(keccak.Keccak256Rlp(nil, delta)))
+
+	return types.BytesToHash(keccak.Keccak256Rlp(nil, link))
+}
+
 // Count returns the vote tally.
 // The count increases if the callback function returns true
 func (s *Snapshot) Count(h func(v *store.Vote) bool) (count int) {
@@ -280,6 +346,8 @@ func newSnapshotStore(
 	return store
 }
 
+// loadData loads the persisted snapshots, the snapshots persisted
+// before the hash chain was introduced have no links and are linked on load
 func (s *snapshotStore) loadData(
 	metadata *SnapshotMetadata,
 	snapshots []*Snapshot,
@@ -288,11 +356,36 @@ func (s *snapshotStore) loadData(
 		s.lastNumber = metadata.LastBlock
 	}
 
+	linked := false
+
 	for _, snap := range snapshots {
-		s.add(snap)
+		linked = linked || snap.Link != types.ZeroHash
+
+		s.list = append(s.list, snap)
+	}
+
+	sort.Sort(&s.list)
+
+	if !linked {
+		s.list.link(0)
 	}
 }
 
+// verifyChain verifies the links of all the snapshots in the store,
+// the oldest snapshot is the anchor of the chain since its predecessors have been pruned
+func (s *snapshotStore) verifyChain() error {
+	s.RLock()
+	defer s.RUnlock()
+
+	for i := 1; i < len(s.list); i++ {
+		if err := s.list.verify(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // getLastBlock returns the latest block number from the snapshot store. [Thread safe]
 func (s *snapshotStore) getLastBlock() uint64 {
 	return atomic.LoadUint64(&s.lastNumber)
@@ -395,6 +488,24 @@ func (s *snapshotStore) add(snap *Snapshot) {
 	// append and sort the list
 	s.list = append(s.list, snap)
 	sort.Sort(&s.list)
+
+	s.list.link(s.list.index(snap))
+}
+
+// verify checks the link of the snapshot at the given number with its predecessor
+func (s *snapshotStore) verify(num uint64) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	i := sort.Search(len(s.list), func(i int) bool {
+		return s.list[i].Number >= num
+	})
+
+	if i == len(s.list) || s.list[i].Number != num {
+		return ErrSnapshotNotFound
+	}
+
+	return s.list.verify(i)
 }
 
 // putByNumber replaces snapshot if the snapshot whose Number matches with the given snapshot's Number
@@ -410,13 +521,13 @@ func (s *snapshotStore) putByNumber(snap *Snapshot) {
 	if i < len(s.list) {
 		// replace if found
 		s.list[i] = snap
-
-		return
+	} else {
+		// append if not found
+		s.list = append(s.list, snap)
+		sort.Sort(&s.list)
 	}
 
-	// append if not found
-	s.list = append(s.list, snap)
-	sort.Sort(&s.list)
+	s.list.link(s.list.index(snap))
 }
 
 // snapshotSortedList defines the sorted snapshot list
@@ -436,3 +547,47 @@ func (s snapshotSortedList) Swap(i, j int) {
 func (s snapshotSortedList) Less(i, j int) bool {
 	return s[i].Number < s[j].Number
 }
+
+// index returns the index of the given snapshot in the list
+func (s snapshotSortedList) index(snap *Snapshot) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == snap {
+			return i
+		}
+	}
+
+	return len(s)
+}
+
+// link recomputes the links of the snapshots from the given index to the end of the list,
+// since the link of every snapshot depends on its predecessor
+func (s snapshotSortedList) link(from int) {
+	for i := from; i < len(s); i++ {
+		var prev *Snapshot
+		if i > 0 {
+			prev = s[i-1]
+		}
+
+		s[i].Link = computeLink(prev, s[i])
+	}
+}
+
+// verify checks the link of the snapshot at the given index with its predecessor,
+// the first snapshot in the list is trusted as the anchor of the chain
+func (s snapshotSortedList) verify(i int) error {
+	if i == 0 {
+		return nil
+	}
+
+	if expected := computeLink(s[i-1], s[i]); s[i].Link != expected {
+		return fmt.Errorf(
+			"%w at %d, expected link %s, got %s",
+			ErrSnapshotChainBroken,
+			s[i].Number,
+			expected,
+			s[i].Link,
+		)
+	}
+
+	return nil
+}
@@ -108,6 +108,27 @@ func newTestVote(
 	}
 }
 
+// unlinked returns copies of the snapshots without their links in the hash chain
+func unlinked(snapshots ...*Snapshot) []*Snapshot {
+	if snapshots == nil {
+		return nil
+	}
+
+	res := make([]*Snapshot, len(snapshots))
+
+	for idx, snap := range snapshots {
+		if snap == nil {
+			continue
+		}
+
+		copied := *snap
+		copied.Link = types.ZeroHash
+		res[idx] = &copied
+	}
+
+	return res
+}
+
 func TestSnapshotMarshalJSON(t *testing.T) {
 	t.Parallel()
 
@@ -1160,14 +1181,9 @@ func Test_snapshotStore_deleteLower(t *testing.T) {
 
 			store.deleteLower(testCase.boundary)
 
-			assert.Equal(
-				t,
-				&snapshotStore{
-					lastNumber: metadata.LastBlock,
-					list:       testCase.expectedSnapshots,
-				},
-				store,
-			)
+			assert.Equal(t, metadata.LastBlock, store.lastNumber)
+			assert.Equal(t, testCase.expectedSnapshots, unlinked(store.list...))
+			assert.NoError(t, store.verifyChain())
 		})
 	}
 }
@@ -1251,7 +1267,7 @@ func Test_snapshotStore_find(t *testing.T) {
 			assert.Equal(
 				t,
 				test.expected,
-				store.find(test.input),
+				unlinked(store.find(test.input))[0],
 			)
 		})
 	}
@@ -1289,15 +1305,86 @@ func Test_snapshotStore_add(t *testing.T) {
 
 	store.add(newSnapshot)
 
-	assert.Equal(
-		t,
-		&snapshotStore{
-			list: snapshotSortedList(
-				expected,
-			),
-		},
-		store,
-	)
+	assert.Equal(t, expected, unlinked(store.list...))
+	assert.NoError(t, store.verifyChain())
+}
+
+func Test_snapshotStore_hashChain(t *testing.T) {
+	t.Parallel()
+
+	newSnapshots := func() []*Snapshot {
+		return []*Snapshot{
+			{Number: 0, Set: validators.NewBLSValidatorSet(blsValidator1)},
+			{Number: 10, Set: validators.NewBLSValidatorSet(blsValidator1, blsValidator2)},
+			{Number: 20, Set: validators.NewBLSValidatorSet(blsValidator2)},
+		}
+	}
+
+	t.Run("should link the legacy snapshots on load", func(t *testing.T) {
+		t.Parallel()
+
+		store := newSnapshotStore(&SnapshotMetadata{}, newSnapshots())
+
+		for _, snap := range store.list {
+			assert.NotEqual(t, types.ZeroHash, snap.Link)
+		}
+
+		assert.NoError(t, store.verifyChain())
+	})
+
+	t.Run("should commit to the changes of the set", func(t *testing.T) {
+		t.Parallel()
+
+		snapshots := newSnapshots()
+		snapshotSortedList(snapshots).link(0)
+
+		// the link doesn't depend on the votes
+		snapshots[1].Votes = []*store.Vote{newTestVote(blsValidator3, addr1, true)}
+		assert.Equal(t, snapshots[1].Link, computeLink(snapshots[0], snapshots[1]))
+
+		// replace the BLS public key of a validator without changing its address
+		snapshots[2].Set = validators.NewBLSValidatorSet(
+			validators.NewBLSValidator(blsValidator2.Addr(), blsValidator1.BLSPublicKey),
+		)
+
+		snapshotStore := newSnapshotStore(&SnapshotMetadata{}, snapshots)
+
+		assert.ErrorIs(t, snapshotStore.verifyChain(), ErrSnapshotChainBroken)
+	})
+
+	t.Run("should relink the following snapshots on put", func(t *testing.T) {
+		t.Parallel()
+
+		store := newSnapshotStore(&SnapshotMetadata{}, newSnapshots())
+		lastLink := store.list[2].Link
+
+		store.putByNumber(&Snapshot{
+			Number: 10,
+			Set:    validators.NewBLSValidatorSet(blsValidator1, blsValidator3),
+		})
+
+		assert.NotEqual(t, lastLink, store.list[2].Link)
+		assert.NoError(t, store.verifyChain())
+	})
+
+	t.Run("should keep the links after marshalling", func(t *testing.T) {
+		t.Parallel()
+
+		snapshots := newSnapshots()
+		snapshotSortedList(snapshots).link(0)
+
+		data, err := json.Marshal(snapshots)
+		assert.NoError(t, err)
+
+		decoded := []*Snapshot{}
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+
+		for idx, snap := range decoded {
+			assert.Equal(t, snapshots[idx].Link, snap.Link)
+		}
+
+		assert.NoError(t, newSnapshotStore(&SnapshotMetadata{}, decoded).verifyChain())
+	})
 }
 
 func Test_snapshotStore_putByNumber(t *testing.T) {
@@ -1359,11 +1446,8 @@ func Test_snapshotStore_putByNumber(t *testing.T) {
 
 			store.putByNumber(test.newSnapshot)
 
-			assert.Equal(
-				t,
-				test.finalSnapshots,
-				[]*Snapshot(store.list),
-			)
+			assert.Equal(t, test.finalSnapshots, unlinked(store.list...))
+			assert.NoError(t, store.verifyChain())
 		})
 	}
 }
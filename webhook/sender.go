@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// SignatureHeader is the header holding the signature of the request body
+	SignatureHeader = "X-Webhook-Signature"
+
+	// queueSize is the number of notifications waiting to be delivered to a webhook
+	queueSize = 256
+
+	// maxAttempts is the number of times the delivery of a notification is attempted
+	maxAttempts = 5
+)
+
+var (
+	// requestTimeout is the timeout of a single request to a webhook
+	requestTimeout = 10 * time.Second
+
+	// retryDelay is the delay before the first retry, it doubles with every retry
+	retryDelay = time.Second
+)
+
+// Sign returns the hex encoded HMAC-SHA256 of the body keyed with the webhook secret,
+// which the application checks against the SignatureHeader of the request
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToHex(mac.Sum(nil))
+}
+
+// sender delivers the notifications of a webhook in order
+type sender struct {
+	logger hclog.Logger
+	hook   *Webhook
+	client *http.Client
+
+	queue   chan *Payload
+	closeCh chan struct{}
+}
+
+func newSender(logger hclog.Logger, hook *Webhook, client *http.Client) *sender {
+	return &sender{
+		logger:  logger.With("webhook", hook.ID),
+		hook:    hook,
+		client:  client,
+		queue:   make(chan *Payload, queueSize),
+		closeCh: make(chan struct{}),
+	}
+}
+
+func (s *sender) run() {
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case payload := <-s.queue:
+			s.deliver(payload)
+		}
+	}
+}
+
+func (s *sender) close() {
+	close(s.closeCh)
+}
+
+// enqueue queues the notification, it is dropped if the webhook is too far behind
+func (s *sender) enqueue(payload *Payload) {
+	select {
+	case s.queue <- payload:
+	default:
+		s.logger.Warn("notification queue is full, dropping notification", "block", payload.BlockNumber)
+	}
+}
+
+// deliver sends the notification, retrying with an exponential backoff until the webhook accepts it
+func (s *sender) deliver(payload *Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		s.logger.Error("failed to encode notification", "err", err)
+
+		return
+	}
+
+	delay := retryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := s.post(body)
+		if err == nil {
+			return
+		}
+
+		if attempt == maxAttempts {
+			s.logger.Warn("failed to deliver notification", "block", payload.BlockNumber, "err", err)
+
+			return
+		}
+
+		select {
+		case <-s.closeCh:
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+func (s *sender) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(s.hook.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
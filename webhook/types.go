@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+type Blockchain interface {
+	// SubscribeEvents subscribes new blockchain event
+	SubscribeEvents() blockchain.Subscription
+	// GetReceiptsByHash returns the receipts of the block with the given hash
+	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
+	// GetBlockByHash returns the block with the given hash
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+}
+
+// Filter selects the logs notified to a webhook, the same way the log filters of the JSON-RPC do
+type Filter struct {
+	Addresses []types.Address `json:"addresses,omitempty"`
+	Topics    [][]types.Hash  `json:"topics,omitempty"`
+}
+
+// Match returns whether the log matches the filter
+func (f *Filter) Match(log *types.Log) bool {
+	if len(f.Addresses) > 0 {
+		match := false
+
+		for _, addr := range f.Addresses {
+			if addr == log.Address {
+				match = true
+
+				break
+			}
+		}
+
+		if !match {
+			return false
+		}
+	}
+
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+
+	for i, sub := range f.Topics {
+		match := len(sub) == 0
+
+		for _, topic := range sub {
+			if log.Topics[i] == topic {
+				match = true
+
+				break
+			}
+		}
+
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Webhook is a URL notified of the logs matching its filter.
+// The matching logs of a block are notified once the block reaches each of the confirmation levels
+type Webhook struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Secret        string   `json:"secret,omitempty"`
+	Filter        Filter   `json:"filter"`
+	Confirmations []uint64 `json:"confirmations"`
+}
+
+// PayloadType is the type of a notification
+type PayloadType string
+
+const (
+	// PayloadLogs notifies the matching logs of a block which reached a confirmation level
+	PayloadLogs PayloadType = "logs"
+
+	// PayloadRevoked notifies the logs previously notified which were dropped by a reorg
+	PayloadRevoked PayloadType = "revoked"
+)
+
+// Payload is the body of the requests sent to the webhooks
+type Payload struct {
+	WebhookID     string      `json:"webhookId"`
+	Type          PayloadType `json:"type"`
+	Confirmations uint64      `json:"confirmations"`
+	BlockNumber   uint64      `json:"blockNumber"`
+	BlockHash     types.Hash  `json:"blockHash"`
+	Logs          []*Log      `json:"logs"`
+}
+
+// Log is a notified log, along with its position in the chain
+type Log struct {
+	Address  types.Address `json:"address"`
+	Topics   []types.Hash  `json:"topics"`
+	Data     string        `json:"data"`
+	TxHash   types.Hash    `json:"transactionHash"`
+	TxIndex  uint64        `json:"transactionIndex"`
+	LogIndex uint64        `json:"logIndex"`
+}
@@ -0,0 +1,474 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// MaxConfirmations is the highest confirmation level of a webhook
+const MaxConfirmations = 1024
+
+var (
+	ErrInvalidURL      = errors.New("webhook url must be an absolute http or https url")
+	ErrTooDeep         = errors.New("confirmation level is too high")
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+// trackedBlock is a block with logs matching some webhooks, which didn't reach all their confirmation levels
+type trackedBlock struct {
+	header   *types.Header
+	logs     map[string][]*Log // matching logs per webhook
+	notified map[string]int    // number of confirmation levels notified per webhook
+}
+
+// Manager notifies the registered webhooks of the logs matching their filters.
+// A block is tracked until it reaches the highest confirmation level of every webhook,
+// the logs of a tracked block dropped by a reorg are revoked from the webhooks they were notified to
+type Manager struct {
+	logger     hclog.Logger
+	blockchain Blockchain
+	path       string // file storing the webhooks, they are not persisted if empty
+	client     *http.Client
+
+	subscription blockchain.Subscription
+
+	lock    sync.Mutex
+	hooks   map[string]*Webhook
+	senders map[string]*sender
+	blocks  map[types.Hash]*trackedBlock
+}
+
+// NewManager creates a new Manager storing the registered webhooks in the file at the path
+func NewManager(logger hclog.Logger, blockchain Blockchain, path string) *Manager {
+	return &Manager{
+		logger:     logger.Named("webhook"),
+		blockchain: blockchain,
+		path:       path,
+		client:     &http.Client{},
+		hooks:      make(map[string]*Webhook),
+		senders:    make(map[string]*sender),
+		blocks:     make(map[types.Hash]*trackedBlock),
+	}
+}
+
+// Start loads the registered webhooks and starts notifying them
+func (m *Manager) Start() error {
+	if err := m.load(); err != nil {
+		return err
+	}
+
+	m.subscription = m.blockchain.SubscribeEvents()
+
+	go m.run()
+
+	return nil
+}
+
+// Close stops notifying the webhooks
+func (m *Manager) Close() {
+	if m.subscription != nil {
+		m.subscription.Close()
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for id, s := range m.senders {
+		s.close()
+		delete(m.senders, id)
+	}
+}
+
+// Register registers the url to be notified of the logs matching the filter at the confirmation levels,
+// and returns the webhook along with the secret signing its notifications
+func (m *Manager) Register(rawURL string, filter Filter, confirmations []uint64) (*Webhook, error) {
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+
+	levels, err := normalizeLevels(confirmations)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	hook := &Webhook{
+		ID:            id,
+		URL:           rawURL,
+		Secret:        secret,
+		Filter:        filter,
+		Confirmations: levels,
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.hooks[id] = hook
+
+	if err := m.persist(); err != nil {
+		delete(m.hooks, id)
+
+		return nil, err
+	}
+
+	m.startSender(hook)
+
+	m.logger.Info("webhook registered", "id", id, "url", rawURL)
+
+	copied := *hook
+
+	return &copied, nil
+}
+
+// Unregister stops notifying the webhook
+func (m *Manager) Unregister(id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hook, ok := m.hooks[id]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+
+	delete(m.hooks, id)
+
+	if err := m.persist(); err != nil {
+		m.hooks[id] = hook
+
+		return err
+	}
+
+	if s, ok := m.senders[id]; ok {
+		s.close()
+		delete(m.senders, id)
+	}
+
+	for _, block := range m.blocks {
+		delete(block.logs, id)
+		delete(block.notified, id)
+	}
+
+	m.logger.Info("webhook unregistered", "id", id)
+
+	return nil
+}
+
+// List returns the registered webhooks, without their secrets
+func (m *Manager) List() []*Webhook {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hooks := make([]*Webhook, 0, len(m.hooks))
+
+	for _, hook := range m.hooks {
+		copied := *hook
+		copied.Secret = ""
+
+		hooks = append(hooks, &copied)
+	}
+
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].ID < hooks[j].ID
+	})
+
+	return hooks
+}
+
+func (m *Manager) run() {
+	for {
+		evnt := m.subscription.GetEvent()
+		if evnt == nil {
+			return
+		}
+
+		m.handleEvent(evnt)
+	}
+}
+
+// handleEvent revokes the logs of the blocks dropped from the chain, tracks the new blocks,
+// and notifies the logs of the blocks reaching a confirmation level with the new head
+func (m *Manager) handleEvent(evnt *blockchain.Event) {
+	if evnt.Type == blockchain.EventFork || len(evnt.NewChain) == 0 {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, header := range evnt.OldChain {
+		m.revoke(header)
+	}
+
+	for _, header := range evnt.NewChain {
+		if err := m.track(header); err != nil {
+			m.logger.Error("failed to get the logs of the block", "number", header.Number, "err", err)
+		}
+	}
+
+	m.notify(evnt.NewChain[len(evnt.NewChain)-1].Number)
+}
+
+// track starts tracking the block if it has logs matching some webhooks
+func (m *Manager) track(header *types.Header) error {
+	if len(m.hooks) == 0 {
+		return nil
+	}
+
+	receipts, err := m.blockchain.GetReceiptsByHash(header.Hash)
+	if err != nil {
+		return err
+	}
+
+	block, ok := m.blockchain.GetBlockByHash(header.Hash, true)
+	if !ok {
+		return fmt.Errorf("block %s not found", header.Hash)
+	}
+
+	tracked := &trackedBlock{
+		header:   header,
+		logs:     make(map[string][]*Log),
+		notified: make(map[string]int),
+	}
+
+	logIndex := uint64(0)
+
+	for txIndex, receipt := range receipts {
+		txHash := receipt.TxHash
+		if txHash == types.ZeroHash && txIndex < len(block.Transactions) {
+			txHash = block.Transactions[txIndex].Hash
+		}
+
+		for _, log := range receipt.Logs {
+			for id, hook := range m.hooks {
+				if !hook.Filter.Match(log) {
+					continue
+				}
+
+				tracked.logs[id] = append(tracked.logs[id], &Log{
+					Address:  log.Address,
+					Topics:   log.Topics,
+					Data:     hex.EncodeToHex(log.Data),
+					TxHash:   txHash,
+					TxIndex:  uint64(txIndex),
+					LogIndex: logIndex,
+				})
+			}
+
+			logIndex++
+		}
+	}
+
+	if len(tracked.logs) > 0 {
+		m.blocks[header.Hash] = tracked
+	}
+
+	return nil
+}
+
+// notify notifies the logs of the tracked blocks which reached a confirmation level,
+// and stops tracking the blocks which reached all of them
+func (m *Manager) notify(head uint64) {
+	blocks := make([]*trackedBlock, 0, len(m.blocks))
+
+	for _, block := range m.blocks {
+		blocks = append(blocks, block)
+	}
+
+	// every webhook is notified in the order of the blocks
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].header.Number < blocks[j].header.Number
+	})
+
+	for _, block := range blocks {
+		if block.header.Number > head {
+			continue
+		}
+
+		depth := head - block.header.Number
+		done := true
+
+		for id, logs := range block.logs {
+			hook, ok := m.hooks[id]
+			if !ok {
+				continue
+			}
+
+			n := block.notified[id]
+
+			for ; n < len(hook.Confirmations) && hook.Confirmations[n] <= depth; n++ {
+				m.send(id, &Payload{
+					WebhookID:     id,
+					Type:          PayloadLogs,
+					Confirmations: hook.Confirmations[n],
+					BlockNumber:   block.header.Number,
+					BlockHash:     block.header.Hash,
+					Logs:          logs,
+				})
+			}
+
+			block.notified[id] = n
+
+			if n < len(hook.Confirmations) {
+				done = false
+			}
+		}
+
+		if done {
+			delete(m.blocks, block.header.Hash)
+		}
+	}
+}
+
+// revoke notifies the webhooks the logs of the block were dropped from the chain
+func (m *Manager) revoke(header *types.Header) {
+	block, ok := m.blocks[header.Hash]
+	if !ok {
+		return
+	}
+
+	delete(m.blocks, header.Hash)
+
+	for id, n := range block.notified {
+		hook, ok := m.hooks[id]
+		if !ok || n == 0 {
+			continue
+		}
+
+		m.send(id, &Payload{
+			WebhookID:     id,
+			Type:          PayloadRevoked,
+			Confirmations: hook.Confirmations[n-1],
+			BlockNumber:   block.header.Number,
+			BlockHash:     block.header.Hash,
+			Logs:          block.logs[id],
+		})
+	}
+}
+
+func (m *Manager) send(id string, payload *Payload) {
+	if s, ok := m.senders[id]; ok {
+		s.enqueue(payload)
+	}
+}
+
+func (m *Manager) startSender(hook *Webhook) {
+	s := newSender(m.logger, hook, m.client)
+	m.senders[hook.ID] = s
+
+	go s.run()
+}
+
+// load reads the registered webhooks from the file
+func (m *Manager) load() error {
+	if m.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var hooks []*Webhook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, hook := range hooks {
+		m.hooks[hook.ID] = hook
+		m.startSender(hook)
+	}
+
+	return nil
+}
+
+// persist writes the registered webhooks into the file
+func (m *Manager) persist() error {
+	if m.path == "" {
+		return nil
+	}
+
+	hooks := make([]*Webhook, 0, len(m.hooks))
+	for _, hook := range m.hooks {
+		hooks = append(hooks, hook)
+	}
+
+	data, err := json.Marshal(hooks)
+	if err != nil {
+		return err
+	}
+
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, m.path)
+}
+
+// normalizeLevels sorts the confirmation levels and removes the duplicates,
+// the logs are notified as soon as they are included if no level is given
+func normalizeLevels(confirmations []uint64) ([]uint64, error) {
+	if len(confirmations) == 0 {
+		return []uint64{0}, nil
+	}
+
+	levels := make([]uint64, 0, len(confirmations))
+
+	for _, level := range confirmations {
+		if level > MaxConfirmations {
+			return nil, ErrTooDeep
+		}
+
+		levels = append(levels, level)
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i] < levels[j]
+	})
+
+	unique := levels[:1]
+
+	for _, level := range levels[1:] {
+		if level != unique[len(unique)-1] {
+			unique = append(unique, level)
+		}
+	}
+
+	return unique, nil
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToHex(buf), nil
+}
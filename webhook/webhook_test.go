@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBlockchain struct {
+	receipts map[types.Hash][]*types.Receipt
+}
+
+func (m *mockBlockchain) SubscribeEvents() blockchain.Subscription {
+	return blockchain.NewMockSubscription()
+}
+
+func (m *mockBlockchain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	return m.receipts[hash], nil
+}
+
+func (m *mockBlockchain) GetBlockByHash(hash types.Hash, _ bool) (*types.Block, bool) {
+	return &types.Block{Header: &types.Header{Hash: hash}}, true
+}
+
+// addBlock adds a block with a single log emitted by the address
+func (m *mockBlockchain) addBlock(number uint64, seed string, addr types.Address) *types.Header {
+	header := &types.Header{
+		Number: number,
+		Hash:   types.StringToHash(seed),
+	}
+
+	m.receipts[header.Hash] = []*types.Receipt{
+		{
+			TxHash: types.StringToHash(seed + "1"),
+			Logs: []*types.Log{
+				{
+					Address: addr,
+					Topics:  []types.Hash{types.StringToHash("topic")},
+					Data:    []byte{0x1},
+				},
+			},
+		},
+	}
+
+	return header
+}
+
+// newTestServer returns a webhook endpoint forwarding the notifications signed with the secret
+func newTestServer(t *testing.T, secret *string) (*httptest.Server, chan *Payload) {
+	t.Helper()
+
+	payloads := make(chan *Payload, 16)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if r.Header.Get(SignatureHeader) != Sign(*secret, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		payload := &Payload{}
+		require.NoError(t, json.Unmarshal(body, payload))
+
+		payloads <- payload
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, payloads
+}
+
+func nextPayload(t *testing.T, payloads chan *Payload) *Payload {
+	t.Helper()
+
+	select {
+	case payload := <-payloads:
+		return payload
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification not delivered")
+
+		return nil
+	}
+}
+
+func TestManager_NotifyAndRevoke(t *testing.T) {
+	t.Parallel()
+
+	var (
+		watched = types.StringToAddress("1")
+		other   = types.StringToAddress("2")
+		chain   = &mockBlockchain{receipts: map[types.Hash][]*types.Receipt{}}
+		secret  string
+	)
+
+	srv, payloads := newTestServer(t, &secret)
+
+	m := NewManager(hclog.NewNullLogger(), chain, "")
+	t.Cleanup(m.Close)
+
+	hook, err := m.Register(srv.URL, Filter{Addresses: []types.Address{watched}}, []uint64{2, 0, 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0, 2}, hook.Confirmations)
+
+	secret = hook.Secret
+
+	block1 := chain.addBlock(1, "block1", watched)
+	m.handleEvent(&blockchain.Event{Type: blockchain.EventHead, NewChain: []*types.Header{block1}})
+
+	payload := nextPayload(t, payloads)
+	assert.Equal(t, PayloadLogs, payload.Type)
+	assert.Equal(t, uint64(0), payload.Confirmations)
+	assert.Equal(t, block1.Hash, payload.BlockHash)
+	require.Len(t, payload.Logs, 1)
+	assert.Equal(t, watched, payload.Logs[0].Address)
+	assert.Equal(t, "0x01", payload.Logs[0].Data)
+
+	// the logs of the other addresses are not notified
+	block2 := chain.addBlock(2, "block2", other)
+	m.handleEvent(&blockchain.Event{Type: blockchain.EventHead, NewChain: []*types.Header{block2}})
+
+	// the reorg drops the notified block before it is confirmed
+	reorged := chain.addBlock(1, "reorged1", other)
+	m.handleEvent(&blockchain.Event{
+		Type:     blockchain.EventReorg,
+		OldChain: []*types.Header{block2, block1},
+		NewChain: []*types.Header{reorged},
+	})
+
+	payload = nextPayload(t, payloads)
+	assert.Equal(t, PayloadRevoked, payload.Type)
+	assert.Equal(t, uint64(0), payload.Confirmations)
+	assert.Equal(t, block1.Hash, payload.BlockHash)
+	require.Len(t, payload.Logs, 1)
+
+	// the block is notified again once it reaches the next level
+	block3 := chain.addBlock(2, "block3", watched)
+	m.handleEvent(&blockchain.Event{Type: blockchain.EventHead, NewChain: []*types.Header{block3}})
+	assert.Equal(t, uint64(0), nextPayload(t, payloads).Confirmations)
+
+	for i, seed := range []string{"block4", "block5"} {
+		header := chain.addBlock(uint64(i+3), seed, other)
+		m.handleEvent(&blockchain.Event{Type: blockchain.EventHead, NewChain: []*types.Header{header}})
+	}
+
+	payload = nextPayload(t, payloads)
+	assert.Equal(t, PayloadLogs, payload.Type)
+	assert.Equal(t, uint64(2), payload.Confirmations)
+	assert.Equal(t, block3.Hash, payload.BlockHash)
+
+	// the block reached all the levels, so it is not tracked anymore
+	assert.Empty(t, m.blocks)
+
+	select {
+	case payload := <-payloads:
+		t.Fatalf("unexpected notification %v", payload)
+	default:
+	}
+}
+
+func TestManager_Register(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	m := NewManager(hclog.NewNullLogger(), &mockBlockchain{}, path)
+
+	t.Cleanup(m.Close)
+
+	_, err := m.Register("ftp://localhost", Filter{}, nil)
+	assert.ErrorIs(t, err, ErrInvalidURL)
+
+	_, err = m.Register("http://localhost", Filter{}, []uint64{MaxConfirmations + 1})
+	assert.ErrorIs(t, err, ErrTooDeep)
+
+	hook, err := m.Register("http://localhost", Filter{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0}, hook.Confirmations)
+	assert.NotEmpty(t, hook.Secret)
+
+	// the webhooks are listed without their secrets
+	listed := m.List()
+	require.Len(t, listed, 1)
+	assert.Equal(t, hook.ID, listed[0].ID)
+	assert.Empty(t, listed[0].Secret)
+
+	// the webhooks are loaded again on restart
+	restarted := NewManager(hclog.NewNullLogger(), &mockBlockchain{}, path)
+
+	t.Cleanup(restarted.Close)
+
+	require.NoError(t, restarted.load())
+	assert.Len(t, restarted.List(), 1)
+	assert.Equal(t, hook.Secret, restarted.hooks[hook.ID].Secret)
+
+	require.NoError(t, restarted.Unregister(hook.ID))
+	assert.ErrorIs(t, restarted.Unregister(hook.ID), ErrWebhookNotFound)
+	assert.Empty(t, restarted.List())
+}